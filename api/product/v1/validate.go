@@ -0,0 +1,76 @@
+package productv1
+
+import (
+	"errors"
+	"fmt"
+
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+// The Validate methods check what the gRPC server cannot leave to the
+// product use case: that messages are present, IDs are set and
+// quantities parse. Field rules are the use case's, as over HTTP.
+
+func (r *CreateProductRequest) Validate() error {
+	return validateInput(r.GetProduct())
+}
+
+func (r *GetProductRequest) Validate() error {
+	return validateID(r.GetId())
+}
+
+func (r *ListProductsRequest) Validate() error {
+	if r.GetLimit() < 0 {
+		return errors.New("limit must not be negative")
+	}
+	if r.GetOffset() < 0 {
+		return errors.New("offset must not be negative")
+	}
+	return nil
+}
+
+func (r *UpdateProductRequest) Validate() error {
+	if err := validateID(r.GetId()); err != nil {
+		return err
+	}
+	return validateInput(r.GetProduct())
+}
+
+func (r *DeleteProductRequest) Validate() error {
+	return validateID(r.GetId())
+}
+
+func validateID(id int64) error {
+	if id <= 0 {
+		return errors.New("id must be positive")
+	}
+	return nil
+}
+
+func validateInput(input *ProductInput) error {
+	if input == nil {
+		return errors.New("product is required")
+	}
+	if input.GetAmount() == "" {
+		return errors.New("amount is required")
+	}
+	quantities := []struct{ field, value string }{
+		{"amount", input.GetAmount()},
+		{"backorder_limit", input.GetBackorderLimit()},
+		{"low_stock_threshold", input.GetLowStockThreshold()},
+	}
+	for _, q := range quantities {
+		if q.value == "" {
+			continue
+		}
+		if _, err := quantity.Parse(q.value); err != nil {
+			return fmt.Errorf("%s: %w", q.field, err)
+		}
+	}
+	if ts := input.GetPreorderReleaseDate(); ts != nil {
+		if err := ts.CheckValid(); err != nil {
+			return fmt.Errorf("preorder_release_date: %w", err)
+		}
+	}
+	return nil
+}
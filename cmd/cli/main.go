@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/internal/backup"
+	"backend-context-engineering-template/internal/catalog"
+	"backend-context-engineering-template/internal/dbhealth"
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/scaffold"
+	"backend-context-engineering-template/pkg/client"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/s3"
+
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `Usage: cli <command> [flags]
+
+Commands:
+  apply     Reconcile the live catalog with a declarative catalog file
+  backup    Upload an encrypted logical backup of the database to S3
+  restore   Restore a backup from S3 into empty tables
+  verify    Restore a backup into a scratch schema and compare row counts
+  dbhealth  Report table bloat, unused indexes and sequential-scan-heavy tables
+  generate  Scaffold a new entity: generate entity <Name>
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "apply":
+		err = runApply(ctx, os.Args[2:])
+	case "backup":
+		err = runBackup(ctx, os.Args[2:])
+	case "restore":
+		err = runRestore(ctx, os.Args[2:])
+	case "verify":
+		err = runVerify(ctx, os.Args[2:])
+	case "dbhealth":
+		err = runDBHealth(ctx, os.Args[2:])
+	case "generate":
+		err = runGenerate(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func runApply(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	file := fs.String("f", "", "path to the catalog YAML file")
+	apiURL := fs.String("api", envOrDefault("API_URL", "http://localhost:8080"), "base URL of the product service")
+	planOnly := fs.Bool("plan", false, "print the plan without applying it")
+	autoApprove := fs.Bool("auto-approve", false, "apply without asking for confirmation")
+	fs.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("-f is required")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	desired, err := catalog.Load(f)
+	if err != nil {
+		return err
+	}
+
+	api := client.New(*apiURL, nil)
+
+	var live []client.Product
+	for product, err := range api.ListAll(ctx, client.ListFilter{}) {
+		if err != nil {
+			return fmt.Errorf("list products: %w", err)
+		}
+		live = append(live, product)
+	}
+
+	plan := catalog.BuildPlan(desired, live)
+	plan.Write(os.Stdout)
+
+	if plan.IsEmpty() || *planOnly {
+		return nil
+	}
+
+	if !*autoApprove && !confirm("Apply these changes?") {
+		fmt.Println("Apply cancelled.")
+		return nil
+	}
+
+	start := time.Now()
+	applied, err := plan.Apply(ctx, api)
+	if err != nil {
+		return fmt.Errorf("applied %d of %d changes: %w", applied, len(plan.Changes), err)
+	}
+
+	fmt.Printf("Apply complete: %d changes in %s.\n", applied, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+func runBackup(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	verify := fs.Bool("verify", false, "restore the new backup into a scratch schema and compare row counts")
+	fs.Parse(args)
+
+	job, closeDB, err := newBackupJob("public")
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	key, counts, err := job.Backup(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Backup uploaded to %s.\n", key)
+	printCounts(counts)
+
+	if *verify {
+		if _, err := job.Verify(ctx, key); err != nil {
+			return err
+		}
+		fmt.Println("Backup verified.")
+	}
+	return nil
+}
+
+func runRestore(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	key := fs.String("key", "", "object key of the backup to restore")
+	schema := fs.String("schema", "public", "schema whose empty, migrated tables receive the rows")
+	autoApprove := fs.Bool("auto-approve", false, "restore without asking for confirmation")
+	fs.Parse(args)
+
+	if *key == "" {
+		return fmt.Errorf("-key is required")
+	}
+
+	job, closeDB, err := newBackupJob(*schema)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	fmt.Printf("Restoring %s into schema %q.\n", *key, *schema)
+	if !*autoApprove && !confirm("Restore this backup?") {
+		fmt.Println("Restore cancelled.")
+		return nil
+	}
+
+	counts, err := job.Restore(ctx, *key)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Restore complete.")
+	printCounts(counts)
+	return nil
+}
+
+func runVerify(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	key := fs.String("key", "", "object key of the backup to verify")
+	fs.Parse(args)
+
+	if *key == "" {
+		return fmt.Errorf("-key is required")
+	}
+
+	job, closeDB, err := newBackupJob("public")
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	counts, err := job.Verify(ctx, *key)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Backup %s verified.\n", *key)
+	printCounts(counts)
+	return nil
+}
+
+func runDBHealth(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("dbhealth", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the report as JSON, as served by GET /admin/db/health")
+	fs.Parse(args)
+
+	logger := logrus.New()
+	db, err := openDB(config.Load(), logger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	report, err := dbhealth.NewInspector(postgres.NewDBHealthRepository(db), dbhealth.DefaultThresholds, clock.Real()).Report(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(dto.ToDBHealthResponse(report))
+	}
+
+	if report.StatsSince.Valid {
+		fmt.Printf("Statistics since %s.\n", report.StatsSince.Time.Format(time.RFC3339))
+	}
+	fmt.Printf("  %-28s %12s %12s %10s %10s\n", "TABLE", "LIVE ROWS", "DEAD ROWS", "SEQ SCANS", "IDX SCANS")
+	for _, table := range report.Tables {
+		fmt.Printf("  %-28s %12d %12d %10d %10d\n", table.Table, table.LiveTuples, table.DeadTuples, table.SeqScans, table.IndexScans)
+	}
+
+	if len(report.Findings) == 0 {
+		fmt.Println("No findings.")
+		return nil
+	}
+	fmt.Printf("%d findings:\n", len(report.Findings))
+	for _, finding := range report.Findings {
+		subject := finding.Table
+		if finding.Index != "" {
+			subject = finding.Index
+		}
+		fmt.Printf("  [%s] %s: %s\n    %s\n", finding.Check, subject, finding.Detail, finding.Recommendation)
+	}
+	return nil
+}
+
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	dir := fs.String("dir", ".", "root of the repository to generate into")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 || fs.Arg(0) != "entity" {
+		return fmt.Errorf("usage: cli generate [-dir root] entity <Name>")
+	}
+
+	entity, err := scaffold.NewEntity(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	paths, err := scaffold.Generate(*dir, entity)
+	for _, path := range paths {
+		fmt.Println("  created", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Next steps:")
+	for i, step := range entity.NextSteps() {
+		fmt.Printf("  %d. %s\n", i+1, step)
+	}
+	return nil
+}
+
+// newBackupJob connects to the database and bucket configured by the
+// service's DB_*, BACKUP_* and S3_* settings.
+func newBackupJob(schema string) (*backup.Job, func(), error) {
+	cfg := config.Load()
+	logger := logrus.New()
+
+	key, err := backup.ParseKey(cfg.Backup.EncryptionKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := s3.New(s3.Config{
+		Endpoint: cfg.S3.Endpoint,
+		Region:   cfg.S3.Region,
+		Bucket:   cfg.S3.Bucket,
+		Credentials: s3.Credentials{
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+		},
+	}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := openDB(cfg, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	job := backup.NewJob(db, store, key, backup.Config{Schema: schema, Prefix: cfg.Backup.Prefix}, clock.Real(), logger)
+	return job, func() { db.Close() }, nil
+}
+
+// openDB connects to the primary database configured by DB_*.
+func openDB(cfg *config.Config, logger *logrus.Logger) (*sql.DB, error) {
+	return database.NewPostgresConnection(database.Config{
+		Host:               cfg.DB.Host,
+		Port:               cfg.DB.Port,
+		User:               cfg.DB.User,
+		Password:           cfg.DB.Password,
+		Name:               cfg.DB.Name,
+		SSLMode:            cfg.DB.SSLMode,
+		TargetSessionAttrs: cfg.DB.TargetSessionAttrs,
+	}, logger)
+}
+
+func printCounts(counts backup.Counts) {
+	for _, table := range backup.Tables {
+		fmt.Printf("  %-24s %d rows\n", table, counts[table])
+	}
+}
+
+func confirm(prompt string) bool {
+	fmt.Print(prompt + " Only 'yes' will be accepted: ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(answer) == "yes"
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
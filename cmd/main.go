@@ -2,75 +2,58 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"net/http"
+	"flag"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"backend-context-engineering-template/config"
-	httpDelivery "backend-context-engineering-template/internal/delivery/http"
-	"backend-context-engineering-template/internal/delivery/http/handlers"
-	"backend-context-engineering-template/internal/repository/postgres"
-	"backend-context-engineering-template/internal/usecase"
-	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/internal/app"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/lifecycle"
 	"backend-context-engineering-template/pkg/logger"
+
+	"go.uber.org/fx"
 )
 
 func main() {
+	loadTest := flag.Bool("loadtest", false, "serve from in-memory repositories with rate limits, audit and outbound calls disabled")
+	flag.Parse()
+
 	cfg := config.Load()
 
 	appLogger := logger.New(cfg.Log.Level)
-	appLogger.Info("Starting application...")
-
-	dbConfig := database.Config{
-		Host:     cfg.DB.Host,
-		Port:     cfg.DB.Port,
-		User:     cfg.DB.User,
-		Password: cfg.DB.Password,
-		Name:     cfg.DB.Name,
-		SSLMode:  cfg.DB.SSLMode,
+	// Everything that reads the time or waits on a ticker gets this clock,
+	// so tests can substitute a fake one.
+	clk := clock.Real()
+
+	// The modules in internal/app build the service; main only starts it
+	// and stops it on a signal or once /admin/drain has finished.
+	var lifecycleManager *lifecycle.Manager
+	application := app.New(cfg, app.Flags{LoadTest: *loadTest}, clk, appLogger, fx.Populate(&lifecycleManager))
+	if err := application.Err(); err != nil {
+		appLogger.WithError(err).Fatal("Failed to build application")
 	}
 
-	db, err := database.NewPostgresConnection(dbConfig, appLogger)
-	if err != nil {
-		appLogger.WithError(err).Fatal("Failed to connect to database")
-	}
-	defer func() {
-		if err := db.Close(); err != nil {
-			appLogger.WithError(err).Error("Failed to close database connection")
-		}
-	}()
-
-	productRepo := postgres.NewProductRepository(db, appLogger)
-	productUseCase := usecase.NewProductUseCase(productRepo, appLogger)
-	productHandler := handlers.NewProductHandler(productUseCase, appLogger)
-
-	router := httpDelivery.SetupRouter(productHandler, appLogger)
-
-	server := &http.Server{
-		Addr:    fmt.Sprintf("%s:%s", cfg.HTTP.Addr, cfg.HTTP.Port),
-		Handler: router,
+	startCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := application.Start(startCtx); err != nil {
+		appLogger.WithError(err).Fatal("Failed to start application")
 	}
 
-	go func() {
-		appLogger.WithField("addr", server.Addr).Info("HTTP server starting")
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			appLogger.WithError(err).Fatal("Failed to start server")
-		}
-	}()
-
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	appLogger.Info("Shutting down server...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	select {
+	case <-quit:
+	case <-lifecycleManager.Drained():
+		appLogger.Info("Drain completed")
+	}
 
-	if err := server.Shutdown(ctx); err != nil {
+	// Stop is not given a deadline: fx would skip the remaining hooks once
+	// it passed, leaving the database open and telemetry unflushed. Hooks
+	// that wait share app.ShutdownTimeout instead.
+	if err := application.Stop(context.Background()); err != nil {
 		appLogger.WithError(err).Fatal("Server forced to shutdown")
 	}
 
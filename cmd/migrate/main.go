@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"backend-context-engineering-template/config"
+	sqlmigrations "backend-context-engineering-template/migrations"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/migrations"
+
+	"github.com/sirupsen/logrus"
+)
+
+const usage = `Usage: migrate <command> [flags]
+
+Commands:
+  up              Apply every pending migration
+  down [-n N]     Roll back the newest N migrations (default 1; -all for every one)
+  version         Print the schema version and the latest known version
+  force VERSION   Record the schema as cleanly at VERSION without running anything
+
+The database is configured with the same DB_* variables as the service.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "up":
+		err = run(ctx, func(m *migrations.Migrator) error {
+			applied, err := m.Up(ctx)
+			fmt.Printf("Applied %d migrations.\n", applied)
+			return err
+		})
+	case "down":
+		fs := flag.NewFlagSet("down", flag.ExitOnError)
+		steps := fs.Int("n", 1, "number of migrations to roll back")
+		all := fs.Bool("all", false, "roll back every migration")
+		fs.Parse(os.Args[2:])
+		err = run(ctx, func(m *migrations.Migrator) error {
+			n := *steps
+			if *all {
+				n = math.MaxInt
+			}
+			rolledBack, err := m.Down(ctx, n)
+			fmt.Printf("Rolled back %d migrations.\n", rolledBack)
+			return err
+		})
+	case "version":
+		err = run(ctx, func(m *migrations.Migrator) error {
+			version, dirty, err := m.Version(ctx)
+			if err != nil {
+				return err
+			}
+			state := ""
+			if dirty {
+				state = " (dirty)"
+			}
+			fmt.Printf("Version %d%s; latest is %d.\n", version, state, m.Latest())
+			return nil
+		})
+	case "force":
+		if len(os.Args) < 3 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		version, parseErr := strconv.ParseInt(os.Args[2], 10, 64)
+		if parseErr != nil {
+			err = fmt.Errorf("invalid version %q", os.Args[2])
+			break
+		}
+		err = run(ctx, func(m *migrations.Migrator) error {
+			return m.Force(ctx, version)
+		})
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, fn func(m *migrations.Migrator) error) error {
+	cfg := config.Load()
+	logger := logrus.New()
+
+	db, err := database.NewPostgresConnection(database.Config{
+		Host:               cfg.DB.Host,
+		Port:               cfg.DB.Port,
+		User:               cfg.DB.User,
+		Password:           cfg.DB.Password,
+		Name:               cfg.DB.Name,
+		SSLMode:            cfg.DB.SSLMode,
+		TargetSessionAttrs: cfg.DB.TargetSessionAttrs,
+	}, logger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrations.New(db, sqlmigrations.Files, logger)
+	if err != nil {
+		return err
+	}
+	return fn(migrator)
+}
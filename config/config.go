@@ -3,31 +3,442 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
 	App struct {
-		Name string
-		Env  string
+		Name    string
+		Env     string
+		Version string
 	}
 	HTTP struct {
 		Addr string
 		Port string
+		// TLS is enabled when a certificate is set. ClientCAFile is the
+		// SPIFFE trust bundle X.509-SVID client certificates are verified
+		// against.
+		TLSCertFile  string
+		TLSKeyFile   string
+		ClientCAFile string
+		// LegacyListResponses keeps answering paged lists with the shapes
+		// v1 clients know rather than the data, meta and links envelope.
+		LegacyListResponses bool
+	}
+	GRPC struct {
+		// Port is empty to serve HTTP only. The server uses the HTTP TLS
+		// certificate and client CA when they are set.
+		Port       string
+		Reflection bool
 	}
 	DB struct {
-		Driver   string
-		Host     string
-		Port     string
-		User     string
+		Driver string
+		// Host is a host or a comma-separated list of them, with Port one
+		// port for all or one per host. Connections go to the first host,
+		// starting with the last one reached, that matches
+		// TargetSessionAttrs.
+		Host               string
+		Port               string
+		User               string
+		Password           string
+		Name               string
+		SSLMode            string
+		TargetSessionAttrs string
+		// FailoverWindow is how long reads are retried, and readiness only
+		// degraded, while no host accepts connections.
+		FailoverWindow time.Duration
+		// IDStrategy is "serial" (database sequence) or "snowflake".
+		// IDNodeID must be set, and unique per instance, with snowflake
+		// IDs; it is -1 (idgen.NodeIDUnset) when ID_NODE_ID is not set.
+		IDStrategy string
+		IDNodeID   int64
+		// ReplicaHost is the region-local read replica. Product reads go
+		// to it while its lag stays under Region.ReplicaMaxLag.
+		ReplicaHost string
+		ReplicaPort string
+		// AutoMigrate applies pending migrations at startup, before
+		// anything uses the database.
+		AutoMigrate bool
+		// QueryBudget is how many statements one HTTP request may run
+		// before it is logged; zero disables the check.
+		QueryBudget int64
+	}
+	Redis struct {
+		// Addr is empty to keep shared state (sessions, rate limit
+		// counters) in process memory, which only suits a single instance.
+		Addr     string
 		Password string
-		Name     string
-		SSLMode  string
+		DB       int64
+		// FailureThreshold failed commands in a row mark Redis down, and
+		// shared state falls back to process memory until a ping every
+		// ProbeInterval succeeds.
+		FailureThreshold int64
+		ProbeInterval    time.Duration
+	}
+	Region struct {
+		// Name is this instance's region. Instances outside Primary are
+		// passive: they serve requests but leave scheduled jobs to the
+		// primary region.
+		Name               string
+		Primary            string
+		ReplicaMaxLag      time.Duration
+		ReplicaLagInterval time.Duration
+	}
+	Lifecycle struct {
+		DrainTimeout time.Duration
+		// HealthCheckTimeout bounds each dependency ping of /readyz.
+		HealthCheckTimeout time.Duration
+	}
+	Warmup struct {
+		Timeout       time.Duration
+		RetryInterval time.Duration
+		PoolConns     int
+		HotKeys       int
+		HotKeysFile   string
+	}
+	Cache struct {
+		Size int
+		TTL  time.Duration
+	}
+	HotKeys struct {
+		TopK         int
+		Threshold    int64
+		TTL          time.Duration
+		SyncInterval time.Duration
+		HalfLife     time.Duration
+	}
+	Telemetry struct {
+		ServiceName          string
+		ResourceAttributes   []string
+		MetricsExporter      string
+		LogsExporter         string
+		OTLPEndpoint         string
+		OTLPHeaders          []string
+		MetricExportInterval time.Duration
+		LogsBatchSize        int
+		LogsExportInterval   time.Duration
+		StoreTopK            int
+	}
+	Synthetics struct {
+		Enabled   bool
+		TargetURL string
+		StoreID   int64
+		Interval  time.Duration
+		Timeout   time.Duration
+	}
+	AdminUI struct {
+		Enabled bool
+		// APIBasePath is the prefix a proxy serves the API under, if
+		// any, so the dashboard calls it there.
+		APIBasePath string
+	}
+	APIDocs struct {
+		Enabled bool
+		// APIBasePath is the prefix a proxy serves the API under, if
+		// any, so the spec's server and the docs' requests point there.
+		APIBasePath string
+	}
+	LiveConfig struct {
+		// RefreshInterval is how often each instance reloads the live
+		// configuration, and so how long a change takes to reach them all.
+		RefreshInterval time.Duration
+	}
+	Profiling struct {
+		Enabled       bool
+		ServerAddress string
+		AuthToken     string
+		Interval      time.Duration
+	}
+	Explain struct {
+		// SlowThreshold enables plan capture for requests slower than it;
+		// zero disables it.
+		SlowThreshold time.Duration
+		SampleRate    float64
+		MaxPerMinute  int64
+		Timeout       time.Duration
+	}
+	IndexAdvisor struct {
+		// MinUses is how often a list query shape must run before a
+		// missing index is suggested for it.
+		MinUses int64
 	}
 	Log struct {
 		Level string
 	}
+	Session struct {
+		TTL            time.Duration
+		IdleTimeout    time.Duration
+		CookieName     string
+		CookieDomain   string
+		CookieSecure   bool
+		CookieSameSite string
+	}
+	Workload struct {
+		Issuers     []string
+		Audience    string
+		Roles       []string
+		AdminRole   string
+		JWKSRefresh time.Duration
+	}
+	TwoFactor struct {
+		Policy string
+		// RolePolicies override Policy per role, as role=policy.
+		RolePolicies []string
+	}
+	Auth struct {
+		JWTSecret       string
+		JWTIssuer       string
+		AccessTokenTTL  time.Duration
+		RefreshTokenTTL time.Duration
+		// ImpersonationTTL caps how long an admin may act as a user.
+		ImpersonationTTL time.Duration
+		ProtectProducts  bool
+	}
+	Lockout struct {
+		MaxFailures   int64
+		MaxIPFailures int64
+		Window        time.Duration
+		Duration      time.Duration
+		BaseDelay     time.Duration
+		MaxDelay      time.Duration
+		WebhookURL    string
+	}
+	Trash struct {
+		Retention     time.Duration
+		PurgeInterval time.Duration
+	}
+	Publishing struct {
+		// SchedulerInterval is how often products entering and leaving
+		// their publishing windows are announced.
+		SchedulerInterval time.Duration
+	}
+	Bulk struct {
+		// Threshold is the most rows a mutation may touch before it needs
+		// an elevated caller and a two-step confirmation.
+		Threshold       int64
+		ConfirmationTTL time.Duration
+		// ConfirmationSecret signs confirmation tokens; without it each
+		// instance signs with a random secret of its own.
+		ConfirmationSecret string
+	}
+	Preview struct {
+		// TokenSecret signs preview links; previews are off without it.
+		TokenSecret string
+		TokenTTL    time.Duration
+		MaxTokenTTL time.Duration
+	}
+	Feed struct {
+		SchedulerInterval time.Duration
+		FetchTimeout      time.Duration
+		MaxBytes          int64
+		MaxShrink         float64
+	}
+	Image struct {
+		// Prefix is prepended to image object keys. Images are kept in the
+		// S3 bucket and feed image import is off when none is set.
+		Prefix        string
+		MaxBytes      int64
+		FetchTimeout  time.Duration
+		ImportTimeout time.Duration
+	}
+	Secrets struct {
+		Key string
+	}
+	Connector struct {
+		RequestTimeout time.Duration
+		SyncTimeout    time.Duration
+	}
+	Reconciliation struct {
+		// Interval between scheduled reconciliations of every enabled
+		// feed and connector; zero turns the schedule off.
+		Interval time.Duration
+		Policy   string
+		Timeout  time.Duration
+	}
+	Moderation struct {
+		Blocklist     []string
+		WebhookURL    string
+		ReviewTimeout time.Duration
+	}
+	Anomaly struct {
+		Window              time.Duration
+		Factor              int64
+		MinEvents           int64
+		RequireConfirmation bool
+		WebhookURL          string
+	}
+	Webhooks struct {
+		BatchWindow    time.Duration
+		MaxBatchSize   int
+		MaxPending     int
+		MaxAttempts    int
+		RetryBackoff   time.Duration
+		Timeout        time.Duration
+		PauseAfter     int
+		PauseNotifyURL string
+		SecretGrace    time.Duration
+	}
+	ProductStream struct {
+		Buffer         int
+		MaxSubscribers int
+		Heartbeat      time.Duration
+	}
+	Digest struct {
+		Interval      time.Duration
+		SendAfter     time.Duration
+		FlushInterval time.Duration
+		MaxPending    int
+		Template      string
+	}
+	Analytics struct {
+		FlushInterval             time.Duration
+		MaxPending                int
+		PopularityRefreshInterval time.Duration
+		PopularityHalfLife        time.Duration
+	}
+	SMTP struct {
+		Addr     string
+		Username string
+		Password string
+		From     string
+	}
+	Events struct {
+		Format          string
+		CloudEventsMode string
+		Source          string
+		// KafkaBrokers turns on publishing product events to KafkaTopic;
+		// without brokers events are not sent to Kafka.
+		KafkaBrokers []string
+		KafkaTopic   string
+		// OutboxPollInterval is how often the relay publishes the events
+		// committed to the outbox, up to OutboxBatchSize at a time.
+		OutboxPollInterval time.Duration
+		OutboxBatchSize    int
+	}
+	Jobs struct {
+		// Queue is where background jobs wait: "redis" shares them between
+		// instances, "memory" keeps them in this process. Empty picks
+		// redis when Redis is configured.
+		Queue        string
+		Concurrency  int
+		PollInterval time.Duration
+		Lease        time.Duration
+		MaxAttempts  int
+		RetryBackoff time.Duration
+		// MaxPending caps the jobs the memory queue holds.
+		MaxPending int
+	}
+	Scheduler struct {
+		// NightlyCleanup is the cron schedule, in UTC, of the purge of
+		// trashed products and expired idempotency keys. "none" leaves
+		// them to the retention interval.
+		NightlyCleanup string
+	}
+	AuditExport struct {
+		Sink          string
+		URL           string
+		Token         string
+		SyslogNetwork string
+		SyslogAddr    string
+		KafkaBrokers  []string
+		KafkaTopic    string
+		BatchSize     int
+		Interval      time.Duration
+		MaxBackoff    time.Duration
+	}
+	Metering struct {
+		// Sink is where hourly usage is emitted: "http", "kafka" or empty
+		// to leave metering off.
+		Sink          string
+		URL           string
+		Token         string
+		KafkaBrokers  []string
+		KafkaTopic    string
+		Interval      time.Duration
+		Grace         time.Duration
+		FlushInterval time.Duration
+		MaxPending    int
+		BatchSize     int
+		ClaimTTL      time.Duration
+	}
+	Backup struct {
+		// Enabled runs scheduled backups in the primary region.
+		Enabled       bool
+		Interval      time.Duration
+		Verify        bool
+		EncryptionKey string
+		Prefix        string
+	}
+	Snapshot struct {
+		// Prefix is prepended to catalog snapshot object keys. Snapshots
+		// are kept in the S3 bucket and are off when none is set.
+		Prefix string
+		// Timeout bounds taking a snapshot and previewing a restore.
+		Timeout        time.Duration
+		RestoreTimeout time.Duration
+	}
+	S3 struct {
+		Endpoint        string
+		Region          string
+		Bucket          string
+		AccessKeyID     string
+		SecretAccessKey string
+	}
+	Retention struct {
+		Interval       time.Duration
+		BatchSize      int
+		BatchDelay     time.Duration
+		AuditLogs      time.Duration
+		JobRecords     time.Duration
+		InboxMessages  time.Duration
+		CatalogChanges time.Duration
+		SentEvents     time.Duration
+	}
+	Idempotency struct {
+		// KeyTTL is how long responses to requests sent with an
+		// Idempotency-Key are replayed to retries; keys are deleted by
+		// the retention worker after it.
+		KeyTTL time.Duration
+		// LockTimeout is how long a request in progress holds its key
+		// before a retry may run it again.
+		LockTimeout time.Duration
+	}
+	RateLimit struct {
+		Units  int64
+		Window time.Duration
+		// RPS caps requests per second per API key or client IP, with
+		// room for Burst at once; 0 turns the cap off.
+		RPS   float64
+		Burst int64
+	}
+	Outbound struct {
+		MaxRetries       int
+		BaseBackoff      time.Duration
+		MaxBackoff       time.Duration
+		BreakerThreshold int
+		BreakerCooldown  time.Duration
+	}
+	CDN struct {
+		// Enabled sets caching headers on the catalog reads and purges
+		// them from Provider, "fastly" or "cloudflare", when products
+		// change.
+		Enabled            bool
+		Provider           string
+		FastlyAPIURL       string
+		FastlyServiceID    string
+		FastlyAPIToken     string
+		CloudflareAPIURL   string
+		CloudflareZoneID   string
+		CloudflareAPIToken string
+		PurgeWindow        time.Duration
+		MaxPendingPurges   int
+		PurgeMaxAttempts   int
+		PurgeRetryBackoff  time.Duration
+	}
 }
 
 func Load() *Config {
@@ -39,9 +450,17 @@ func Load() *Config {
 
 	config.App.Name = getEnv("APP_NAME", "product-service")
 	config.App.Env = getEnv("APP_ENV", "development")
+	config.App.Version = getEnv("APP_VERSION", "dev")
 
 	config.HTTP.Addr = getEnv("HTTP_ADDR", "0.0.0.0")
 	config.HTTP.Port = getEnv("HTTP_PORT", "8080")
+	config.HTTP.TLSCertFile = getEnv("HTTP_TLS_CERT_FILE", "")
+	config.HTTP.TLSKeyFile = getEnv("HTTP_TLS_KEY_FILE", "")
+	config.HTTP.ClientCAFile = getEnv("HTTP_CLIENT_CA_FILE", "")
+	config.HTTP.LegacyListResponses = getEnvBool("HTTP_LEGACY_LIST_RESPONSES", true)
+
+	config.GRPC.Port = getEnv("GRPC_PORT", "")
+	config.GRPC.Reflection = getEnvBool("GRPC_REFLECTION", config.App.Env != "production")
 
 	config.DB.Driver = getEnv("DB_DRIVER", "postgres")
 	config.DB.Host = getEnv("DB_HOST", "localhost")
@@ -50,9 +469,279 @@ func Load() *Config {
 	config.DB.Password = getEnv("DB_PASSWORD", "app_password")
 	config.DB.Name = getEnv("DB_NAME", "product_db")
 	config.DB.SSLMode = getEnv("DB_SSLMODE", "disable")
+	config.DB.TargetSessionAttrs = getEnv("DB_TARGET_SESSION_ATTRS", "read-write")
+	config.DB.FailoverWindow = getEnvDuration("DB_FAILOVER_WINDOW", 30*time.Second)
+	config.DB.IDStrategy = getEnv("ID_STRATEGY", "serial")
+	config.DB.IDNodeID = getEnvInt64("ID_NODE_ID", -1)
+	config.DB.ReplicaHost = getEnv("DB_REPLICA_HOST", "")
+	config.DB.ReplicaPort = getEnv("DB_REPLICA_PORT", config.DB.Port)
+	config.DB.AutoMigrate = getEnvBool("DB_AUTO_MIGRATE", false)
+	config.DB.QueryBudget = getEnvInt64("DB_QUERY_BUDGET", 20)
+
+	config.Redis.Addr = getEnv("REDIS_ADDR", "")
+	config.Redis.Password = getEnv("REDIS_PASSWORD", "")
+	config.Redis.DB = getEnvInt64("REDIS_DB", 0)
+	config.Redis.FailureThreshold = getEnvInt64("REDIS_FAILURE_THRESHOLD", 3)
+	config.Redis.ProbeInterval = getEnvDuration("REDIS_PROBE_INTERVAL", 5*time.Second)
+
+	config.Region.Name = getEnv("REGION", "")
+	config.Region.Primary = getEnv("PRIMARY_REGION", config.Region.Name)
+	config.Region.ReplicaMaxLag = getEnvDuration("REPLICA_MAX_LAG", 5*time.Second)
+	config.Region.ReplicaLagInterval = getEnvDuration("REPLICA_LAG_INTERVAL", 5*time.Second)
 
 	config.Log.Level = getEnv("LOG_LEVEL", "info")
 
+	// Standard OpenTelemetry variables; intervals are in milliseconds as
+	// the specification defines them.
+	config.Telemetry.ServiceName = getEnv("OTEL_SERVICE_NAME", config.App.Name)
+	config.Telemetry.ResourceAttributes = getEnvList("OTEL_RESOURCE_ATTRIBUTES")
+	config.Telemetry.MetricsExporter = getEnv("OTEL_METRICS_EXPORTER", "prometheus")
+	config.Telemetry.LogsExporter = getEnv("OTEL_LOGS_EXPORTER", "none")
+	config.Telemetry.OTLPEndpoint = getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	config.Telemetry.OTLPHeaders = getEnvList("OTEL_EXPORTER_OTLP_HEADERS")
+	config.Telemetry.MetricExportInterval = time.Duration(getEnvInt64("OTEL_METRIC_EXPORT_INTERVAL", 60000)) * time.Millisecond
+	config.Telemetry.LogsBatchSize = int(getEnvInt64("OTEL_BLRP_MAX_EXPORT_BATCH_SIZE", 512))
+	config.Telemetry.LogsExportInterval = time.Duration(getEnvInt64("OTEL_BLRP_SCHEDULE_DELAY", 1000)) * time.Millisecond
+	config.Telemetry.StoreTopK = int(getEnvInt64("METRICS_STORE_TOP_K", 50))
+
+	config.Synthetics.Enabled = getEnvBool("SYNTHETICS_ENABLED", false)
+	config.Synthetics.TargetURL = getEnv("SYNTHETICS_TARGET_URL", "")
+	config.Synthetics.StoreID = getEnvInt64("SYNTHETICS_STORE_ID", 0)
+	config.Synthetics.Interval = getEnvDuration("SYNTHETICS_INTERVAL", time.Minute)
+	config.Synthetics.Timeout = getEnvDuration("SYNTHETICS_TIMEOUT", 10*time.Second)
+
+	config.Profiling.Enabled = getEnvBool("PROFILING_ENABLED", false)
+	config.Profiling.ServerAddress = getEnv("PROFILING_SERVER_ADDRESS", "http://localhost:4040")
+	config.Profiling.AuthToken = getEnv("PROFILING_AUTH_TOKEN", "")
+	config.Profiling.Interval = getEnvDuration("PROFILING_INTERVAL", 10*time.Second)
+
+	config.Explain.SlowThreshold = getEnvDuration("EXPLAIN_SLOW_THRESHOLD", 0)
+	config.Explain.SampleRate = getEnvFloat("EXPLAIN_SAMPLE_RATE", 0.1)
+	config.Explain.MaxPerMinute = getEnvInt64("EXPLAIN_MAX_PER_MINUTE", 6)
+	config.Explain.Timeout = getEnvDuration("EXPLAIN_TIMEOUT", 10*time.Second)
+
+	config.IndexAdvisor.MinUses = getEnvInt64("INDEX_ADVISOR_MIN_USES", 100)
+
+	config.Lifecycle.DrainTimeout = getEnvDuration("DRAIN_TIMEOUT", 30*time.Second)
+	config.Lifecycle.HealthCheckTimeout = getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second)
+
+	config.Warmup.Timeout = getEnvDuration("WARMUP_TIMEOUT", 30*time.Second)
+	config.Warmup.RetryInterval = getEnvDuration("WARMUP_RETRY_INTERVAL", 5*time.Second)
+	config.Warmup.PoolConns = int(getEnvInt64("WARMUP_POOL_CONNS", 10))
+	config.Warmup.HotKeys = int(getEnvInt64("WARMUP_HOT_KEYS", 500))
+	config.Warmup.HotKeysFile = getEnv("WARMUP_HOT_KEYS_FILE", "hot_keys.json")
+
+	config.Cache.Size = int(getEnvInt64("CACHE_SIZE", 10000))
+	config.Cache.TTL = getEnvDuration("CACHE_TTL", 5*time.Minute)
+
+	config.HotKeys.TopK = int(getEnvInt64("HOT_KEYS_TOP_K", 100))
+	config.HotKeys.Threshold = getEnvInt64("HOT_KEYS_THRESHOLD", 1000)
+	config.HotKeys.TTL = getEnvDuration("HOT_KEYS_TTL", 30*time.Minute)
+	config.HotKeys.SyncInterval = getEnvDuration("HOT_KEYS_SYNC_INTERVAL", time.Minute)
+	config.HotKeys.HalfLife = getEnvDuration("HOT_KEYS_HALF_LIFE", 5*time.Minute)
+
+	config.Session.TTL = getEnvDuration("SESSION_TTL", 12*time.Hour)
+	config.Session.IdleTimeout = getEnvDuration("SESSION_IDLE_TIMEOUT", 30*time.Minute)
+	config.Session.CookieName = getEnv("SESSION_COOKIE_NAME", "session")
+	config.Session.CookieDomain = getEnv("SESSION_COOKIE_DOMAIN", "")
+	config.Session.CookieSecure = getEnvBool("SESSION_COOKIE_SECURE", true)
+	config.Session.CookieSameSite = getEnv("SESSION_COOKIE_SAMESITE", "lax")
+
+	config.Workload.Issuers = getEnvList("WORKLOAD_ISSUERS")
+	config.Workload.Audience = getEnv("WORKLOAD_AUDIENCE", config.App.Name)
+	config.Workload.Roles = getEnvList("WORKLOAD_ROLES")
+	config.Workload.AdminRole = getEnv("WORKLOAD_ADMIN_ROLE", "admin")
+	config.Workload.JWKSRefresh = getEnvDuration("WORKLOAD_JWKS_REFRESH", time.Hour)
+
+	config.TwoFactor.Policy = getEnv("TWO_FACTOR_POLICY", "optional")
+	config.TwoFactor.RolePolicies = getEnvList("TWO_FACTOR_ROLE_POLICIES")
+
+	config.Auth.JWTSecret = getEnv("AUTH_JWT_SECRET", "")
+	config.Auth.JWTIssuer = getEnv("AUTH_JWT_ISSUER", config.App.Name)
+	config.Auth.AccessTokenTTL = getEnvDuration("AUTH_ACCESS_TOKEN_TTL", 15*time.Minute)
+	config.Auth.RefreshTokenTTL = getEnvDuration("AUTH_REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	config.Auth.ImpersonationTTL = getEnvDuration("AUTH_IMPERSONATION_TTL", 15*time.Minute)
+	config.Auth.ProtectProducts = getEnvBool("AUTH_PROTECT_PRODUCTS", false)
+
+	config.Lockout.MaxFailures = getEnvInt64("LOGIN_MAX_FAILURES", 5)
+	config.Lockout.MaxIPFailures = getEnvInt64("LOGIN_MAX_IP_FAILURES", 20)
+	config.Lockout.Window = getEnvDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute)
+	config.Lockout.Duration = getEnvDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+	config.Lockout.BaseDelay = getEnvDuration("LOGIN_BASE_DELAY", 250*time.Millisecond)
+	config.Lockout.MaxDelay = getEnvDuration("LOGIN_MAX_DELAY", 5*time.Second)
+	config.Lockout.WebhookURL = getEnv("LOGIN_LOCKOUT_WEBHOOK_URL", "")
+
+	config.Trash.Retention = getEnvDuration("TRASH_RETENTION", 30*24*time.Hour)
+	config.Trash.PurgeInterval = getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour)
+
+	config.Publishing.SchedulerInterval = getEnvDuration("PUBLISHING_SCHEDULER_INTERVAL", time.Minute)
+
+	config.Bulk.Threshold = getEnvInt64("BULK_OPERATION_THRESHOLD", 100)
+	config.Bulk.ConfirmationTTL = getEnvDuration("BULK_CONFIRMATION_TTL", 10*time.Minute)
+	config.Bulk.ConfirmationSecret = getEnv("BULK_CONFIRMATION_SECRET", "")
+
+	config.Preview.TokenSecret = getEnv("PREVIEW_TOKEN_SECRET", "")
+	config.Preview.TokenTTL = getEnvDuration("PREVIEW_TOKEN_TTL", 24*time.Hour)
+	config.Preview.MaxTokenTTL = getEnvDuration("PREVIEW_TOKEN_MAX_TTL", 30*24*time.Hour)
+
+	config.Feed.SchedulerInterval = getEnvDuration("FEED_SCHEDULER_INTERVAL", time.Minute)
+	config.Feed.FetchTimeout = getEnvDuration("FEED_FETCH_TIMEOUT", 2*time.Minute)
+	config.Feed.MaxBytes = getEnvInt64("FEED_MAX_BYTES", 10<<20)
+	config.Feed.MaxShrink = getEnvFloat("FEED_MAX_SHRINK", 0.5)
+
+	config.Image.Prefix = getEnv("IMAGE_PREFIX", "images/")
+	config.Image.MaxBytes = getEnvInt64("IMAGE_MAX_BYTES", 5<<20)
+	config.Image.FetchTimeout = getEnvDuration("IMAGE_FETCH_TIMEOUT", 30*time.Second)
+	config.Image.ImportTimeout = getEnvDuration("IMAGE_IMPORT_TIMEOUT", 15*time.Minute)
+
+	config.Secrets.Key = getEnv("SECRETS_KEY", "")
+
+	config.Connector.RequestTimeout = getEnvDuration("CONNECTOR_REQUEST_TIMEOUT", 30*time.Second)
+	config.Connector.SyncTimeout = getEnvDuration("CONNECTOR_SYNC_TIMEOUT", 10*time.Minute)
+
+	config.Reconciliation.Interval = getEnvDuration("RECONCILIATION_INTERVAL", 0)
+	config.Reconciliation.Policy = getEnv("RECONCILIATION_POLICY", "report")
+	config.Reconciliation.Timeout = getEnvDuration("RECONCILIATION_TIMEOUT", 10*time.Minute)
+
+	config.Moderation.Blocklist = getEnvList("MODERATION_BLOCKLIST")
+	config.Moderation.WebhookURL = getEnv("MODERATION_WEBHOOK_URL", "")
+	config.Moderation.ReviewTimeout = getEnvDuration("MODERATION_REVIEW_TIMEOUT", 30*time.Second)
+
+	config.Anomaly.Window = getEnvDuration("ANOMALY_WINDOW", time.Minute)
+	config.Anomaly.Factor = getEnvInt64("ANOMALY_FACTOR", 10)
+	config.Anomaly.MinEvents = getEnvInt64("ANOMALY_MIN_EVENTS", 50)
+	config.Anomaly.RequireConfirmation = getEnvBool("ANOMALY_REQUIRE_CONFIRMATION", true)
+	config.Anomaly.WebhookURL = getEnv("ANOMALY_WEBHOOK_URL", "")
+
+	config.Webhooks.BatchWindow = getEnvDuration("WEBHOOK_BATCH_WINDOW", 5*time.Second)
+	config.Webhooks.MaxBatchSize = int(getEnvInt64("WEBHOOK_MAX_BATCH_SIZE", 100))
+	config.Webhooks.MaxPending = int(getEnvInt64("WEBHOOK_MAX_PENDING", 10000))
+	config.Webhooks.MaxAttempts = int(getEnvInt64("WEBHOOK_MAX_ATTEMPTS", 3))
+	config.Webhooks.RetryBackoff = getEnvDuration("WEBHOOK_RETRY_BACKOFF", time.Second)
+	config.Webhooks.Timeout = getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second)
+	config.Webhooks.PauseAfter = int(getEnvInt64("WEBHOOK_PAUSE_AFTER", 20))
+	config.Webhooks.PauseNotifyURL = getEnv("WEBHOOK_PAUSE_NOTIFY_URL", "")
+	config.Webhooks.SecretGrace = getEnvDuration("WEBHOOK_SECRET_GRACE", 24*time.Hour)
+
+	config.ProductStream.Buffer = int(getEnvInt64("PRODUCT_STREAM_BUFFER", 64))
+	config.ProductStream.MaxSubscribers = int(getEnvInt64("PRODUCT_STREAM_MAX_SUBSCRIBERS", 1000))
+	config.ProductStream.Heartbeat = getEnvDuration("PRODUCT_STREAM_HEARTBEAT", 15*time.Second)
+
+	config.Digest.Interval = getEnvDuration("DIGEST_INTERVAL", 15*time.Minute)
+	config.Digest.SendAfter = getEnvDuration("DIGEST_SEND_AFTER", time.Hour)
+	config.Digest.FlushInterval = getEnvDuration("DIGEST_FLUSH_INTERVAL", 30*time.Second)
+	config.Digest.MaxPending = int(getEnvInt64("DIGEST_MAX_PENDING", 50000))
+	config.Digest.Template = getEnv("DIGEST_TEMPLATE", "")
+
+	config.Analytics.FlushInterval = getEnvDuration("ANALYTICS_FLUSH_INTERVAL", 10*time.Second)
+	config.Analytics.MaxPending = int(getEnvInt64("ANALYTICS_MAX_PENDING", 100000))
+	config.Analytics.PopularityRefreshInterval = getEnvDuration("POPULARITY_REFRESH_INTERVAL", 15*time.Minute)
+	config.Analytics.PopularityHalfLife = getEnvDuration("POPULARITY_HALF_LIFE", 7*24*time.Hour)
+
+	config.SMTP.Addr = getEnv("SMTP_ADDR", "")
+	config.SMTP.Username = getEnv("SMTP_USERNAME", "")
+	config.SMTP.Password = getEnv("SMTP_PASSWORD", "")
+	config.SMTP.From = getEnv("SMTP_FROM", "")
+
+	config.Events.Format = getEnv("EVENT_FORMAT", "native")
+	config.Events.CloudEventsMode = getEnv("EVENT_CLOUDEVENTS_MODE", "structured")
+	config.Events.Source = getEnv("EVENT_SOURCE", "/product-service")
+	config.Events.KafkaBrokers = getEnvList("EVENT_KAFKA_BROKERS")
+	config.Events.KafkaTopic = getEnv("EVENT_KAFKA_TOPIC", "product-events")
+	config.Events.OutboxPollInterval = getEnvDuration("EVENT_OUTBOX_POLL_INTERVAL", time.Second)
+	config.Events.OutboxBatchSize = int(getEnvInt64("EVENT_OUTBOX_BATCH_SIZE", 100))
+
+	config.Jobs.Queue = getEnv("JOBS_QUEUE", "")
+	config.Jobs.Concurrency = int(getEnvInt64("JOBS_CONCURRENCY", 4))
+	config.Jobs.PollInterval = getEnvDuration("JOBS_POLL_INTERVAL", time.Second)
+	config.Jobs.Lease = getEnvDuration("JOBS_LEASE", 5*time.Minute)
+	config.Jobs.MaxAttempts = int(getEnvInt64("JOBS_MAX_ATTEMPTS", 5))
+	config.Jobs.RetryBackoff = getEnvDuration("JOBS_RETRY_BACKOFF", 10*time.Second)
+	config.Jobs.MaxPending = int(getEnvInt64("JOBS_MAX_PENDING", 10000))
+
+	config.Scheduler.NightlyCleanup = getEnv("SCHEDULE_NIGHTLY_CLEANUP", "0 3 * * *")
+
+	config.AuditExport.Sink = getEnv("AUDIT_EXPORT_SINK", "")
+	config.AuditExport.URL = getEnv("AUDIT_EXPORT_URL", "")
+	config.AuditExport.Token = getEnv("AUDIT_EXPORT_TOKEN", "")
+	config.AuditExport.SyslogNetwork = getEnv("AUDIT_EXPORT_SYSLOG_NETWORK", "tcp")
+	config.AuditExport.SyslogAddr = getEnv("AUDIT_EXPORT_SYSLOG_ADDR", "")
+	config.AuditExport.KafkaBrokers = getEnvList("AUDIT_EXPORT_KAFKA_BROKERS")
+	config.AuditExport.KafkaTopic = getEnv("AUDIT_EXPORT_KAFKA_TOPIC", "audit-logs")
+	config.AuditExport.BatchSize = int(getEnvInt64("AUDIT_EXPORT_BATCH_SIZE", 100))
+	config.AuditExport.Interval = getEnvDuration("AUDIT_EXPORT_INTERVAL", 5*time.Second)
+	config.AuditExport.MaxBackoff = getEnvDuration("AUDIT_EXPORT_MAX_BACKOFF", 5*time.Minute)
+
+	config.Metering.Sink = getEnv("METERING_SINK", "")
+	config.Metering.URL = getEnv("METERING_URL", "")
+	config.Metering.Token = getEnv("METERING_TOKEN", "")
+	config.Metering.KafkaBrokers = getEnvList("METERING_KAFKA_BROKERS")
+	config.Metering.KafkaTopic = getEnv("METERING_KAFKA_TOPIC", "usage-events")
+	config.Metering.Interval = getEnvDuration("METERING_INTERVAL", 5*time.Minute)
+	config.Metering.Grace = getEnvDuration("METERING_GRACE", 10*time.Minute)
+	config.Metering.FlushInterval = getEnvDuration("METERING_FLUSH_INTERVAL", 30*time.Second)
+	config.Metering.MaxPending = int(getEnvInt64("METERING_MAX_PENDING", 100000))
+	config.Metering.BatchSize = int(getEnvInt64("METERING_BATCH_SIZE", 500))
+	config.Metering.ClaimTTL = getEnvDuration("METERING_CLAIM_TTL", 5*time.Minute)
+
+	config.Backup.Enabled = getEnvBool("BACKUP_ENABLED", false)
+	config.Backup.Interval = getEnvDuration("BACKUP_INTERVAL", 24*time.Hour)
+	config.Backup.Verify = getEnvBool("BACKUP_VERIFY", true)
+	config.Backup.EncryptionKey = getEnv("BACKUP_ENCRYPTION_KEY", "")
+	config.Backup.Prefix = getEnv("BACKUP_PREFIX", "backups/")
+
+	config.Snapshot.Prefix = getEnv("SNAPSHOT_PREFIX", "snapshots/")
+	config.Snapshot.Timeout = getEnvDuration("SNAPSHOT_TIMEOUT", 5*time.Minute)
+	config.Snapshot.RestoreTimeout = getEnvDuration("SNAPSHOT_RESTORE_TIMEOUT", 10*time.Minute)
+
+	config.S3.Endpoint = getEnv("S3_ENDPOINT", "https://s3.amazonaws.com")
+	config.S3.Region = getEnv("S3_REGION", "us-east-1")
+	config.S3.Bucket = getEnv("S3_BUCKET", "")
+	config.S3.AccessKeyID = getEnv("S3_ACCESS_KEY_ID", "")
+	config.S3.SecretAccessKey = getEnv("S3_SECRET_ACCESS_KEY", "")
+
+	config.Retention.Interval = getEnvDuration("RETENTION_INTERVAL", time.Hour)
+	config.Retention.BatchSize = int(getEnvInt64("RETENTION_BATCH_SIZE", 1000))
+	config.Retention.BatchDelay = getEnvDuration("RETENTION_BATCH_DELAY", 100*time.Millisecond)
+	config.Retention.AuditLogs = getEnvDuration("RETENTION_AUDIT_LOGS", 365*24*time.Hour)
+	config.Retention.JobRecords = getEnvDuration("RETENTION_JOB_RECORDS", 14*24*time.Hour)
+	config.Retention.InboxMessages = getEnvDuration("RETENTION_INBOX_MESSAGES", 30*24*time.Hour)
+	config.Retention.CatalogChanges = getEnvDuration("RETENTION_CATALOG_CHANGES", 30*24*time.Hour)
+	config.Retention.SentEvents = getEnvDuration("RETENTION_SENT_EVENTS", 7*24*time.Hour)
+
+	config.Idempotency.KeyTTL = getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour)
+	config.Idempotency.LockTimeout = getEnvDuration("IDEMPOTENCY_LOCK_TIMEOUT", time.Minute)
+
+	config.RateLimit.Units = getEnvInt64("RATE_LIMIT_UNITS", 1000)
+	config.RateLimit.Window = getEnvDuration("RATE_LIMIT_WINDOW", time.Minute)
+	config.RateLimit.RPS = getEnvFloat("RATE_LIMIT_RPS", 0)
+	config.RateLimit.Burst = getEnvInt64("RATE_LIMIT_BURST", 20)
+
+	config.Outbound.MaxRetries = int(getEnvInt64("OUTBOUND_MAX_RETRIES", 3))
+	config.Outbound.BaseBackoff = getEnvDuration("OUTBOUND_BASE_BACKOFF", 200*time.Millisecond)
+	config.Outbound.MaxBackoff = getEnvDuration("OUTBOUND_MAX_BACKOFF", 5*time.Second)
+	config.Outbound.BreakerThreshold = int(getEnvInt64("OUTBOUND_BREAKER_THRESHOLD", 5))
+	config.Outbound.BreakerCooldown = getEnvDuration("OUTBOUND_BREAKER_COOLDOWN", 30*time.Second)
+
+	config.AdminUI.Enabled = getEnvBool("ADMIN_UI_ENABLED", false)
+	config.AdminUI.APIBasePath = getEnv("ADMIN_UI_API_BASE_PATH", "")
+	config.APIDocs.Enabled = getEnvBool("API_DOCS_ENABLED", true)
+	config.APIDocs.APIBasePath = getEnv("API_DOCS_API_BASE_PATH", "")
+	config.LiveConfig.RefreshInterval = getEnvDuration("LIVE_CONFIG_REFRESH_INTERVAL", 10*time.Second)
+
+	config.CDN.Enabled = getEnvBool("CDN_CACHE_ENABLED", false)
+	config.CDN.Provider = getEnv("CDN_PROVIDER", "fastly")
+	config.CDN.FastlyAPIURL = getEnv("CDN_FASTLY_API_URL", "https://api.fastly.com")
+	config.CDN.FastlyServiceID = getEnv("CDN_FASTLY_SERVICE_ID", "")
+	config.CDN.FastlyAPIToken = getEnv("CDN_FASTLY_API_TOKEN", "")
+	config.CDN.CloudflareAPIURL = getEnv("CDN_CLOUDFLARE_API_URL", "https://api.cloudflare.com/client/v4")
+	config.CDN.CloudflareZoneID = getEnv("CDN_CLOUDFLARE_ZONE_ID", "")
+	config.CDN.CloudflareAPIToken = getEnv("CDN_CLOUDFLARE_API_TOKEN", "")
+	config.CDN.PurgeWindow = getEnvDuration("CDN_PURGE_WINDOW", 2*time.Second)
+	config.CDN.MaxPendingPurges = int(getEnvInt64("CDN_MAX_PENDING_PURGES", 100000))
+	config.CDN.PurgeMaxAttempts = int(getEnvInt64("CDN_PURGE_MAX_ATTEMPTS", 3))
+	config.CDN.PurgeRetryBackoff = getEnvDuration("CDN_PURGE_RETRY_BACKOFF", 500*time.Millisecond)
+
 	return config
 }
 
@@ -62,3 +751,53 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+		log.Printf("Invalid duration for %s, using default %s", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return i
+		}
+		log.Printf("Invalid integer for %s, using default %d", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+		log.Printf("Invalid number for %s, using default %g", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+		log.Printf("Invalid boolean for %s, using default %t", key, defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,134 @@
+// Package adminui serves the operators' dashboard: a small single page app
+// for browsing the catalog, import jobs and webhooks and for changing the
+// live configuration. It is embedded in the binary, so it ships with the
+// service instead of as its own deployment, and it only calls the
+// service's public API.
+package adminui
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// ContentSecurityPolicy only lets the dashboard load its own scripts and
+// styles and call its own origin, so nothing injected into the catalog data
+// it shows can run or send anything elsewhere.
+const ContentSecurityPolicy = "default-src 'none'; script-src 'self'; style-src 'self'; img-src 'self' data:; " +
+	"connect-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+type asset struct {
+	data    []byte
+	version string
+}
+
+// Handler serves the dashboard. Mount it with the path prefix it is served
+// under stripped: "/" serves the page and "/assets/" its scripts and
+// styles.
+type Handler struct {
+	index     []byte
+	indexETag string
+	assets    map[string]asset
+}
+
+// New renders the dashboard for an API served under apiBasePath, which is
+// empty when the API is at the root of the dashboard's origin, as it is
+// unless a proxy serves the service under a prefix.
+func New(apiBasePath string) (*Handler, error) {
+	if apiBasePath != "" && (!strings.HasPrefix(apiBasePath, "/") || strings.HasPrefix(apiBasePath, "//") || strings.ContainsAny(apiBasePath, "?#")) {
+		return nil, fmt.Errorf("admin UI API base path %q must be a path on the same origin, such as /catalog", apiBasePath)
+	}
+
+	assets := make(map[string]asset)
+	err := fs.WalkDir(staticFiles, "static/assets", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := staticFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		assets[path.Base(name)] = asset{data: data, version: hex.EncodeToString(sum[:6])}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load admin UI assets: %w", err)
+	}
+
+	tmpl, err := template.New("index.html").Funcs(template.FuncMap{
+		// Asset URLs carry their content hash, so they can be cached for
+		// good and still change with each release.
+		"asset": func(name string) (string, error) {
+			a, ok := assets[name]
+			if !ok {
+				return "", fmt.Errorf("unknown asset %q", name)
+			}
+			return "assets/" + name + "?v=" + a.version, nil
+		},
+	}).ParseFS(staticFiles, "static/index.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse admin UI page: %w", err)
+	}
+	var index bytes.Buffer
+	if err := tmpl.Execute(&index, map[string]string{"APIBasePath": strings.TrimRight(apiBasePath, "/")}); err != nil {
+		return nil, fmt.Errorf("failed to render admin UI page: %w", err)
+	}
+	sum := sha256.Sum256(index.Bytes())
+
+	return &Handler{
+		index:     index.Bytes(),
+		indexETag: `"` + hex.EncodeToString(sum[:8]) + `"`,
+		assets:    assets,
+	}, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Security-Policy", ContentSecurityPolicy)
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("X-Frame-Options", "DENY")
+	header.Set("Referrer-Policy", "no-referrer")
+
+	switch {
+	case r.URL.Path == "/" || r.URL.Path == "":
+		// The page names the current assets, so it is revalidated on
+		// every load.
+		header.Set("Cache-Control", "no-cache")
+		header.Set("ETag", h.indexETag)
+		http.ServeContent(w, r, "index.html", time.Time{}, bytes.NewReader(h.index))
+	case strings.HasPrefix(r.URL.Path, "/assets/"):
+		name := strings.TrimPrefix(r.URL.Path, "/assets/")
+		a, ok := h.assets[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("v") == a.version {
+			header.Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			header.Set("Cache-Control", "no-cache")
+		}
+		header.Set("ETag", `"`+a.version+`"`)
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(a.data))
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,100 @@
+package adminui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(h http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler_Index(t *testing.T) {
+	h, err := New("/catalog/")
+	require.NoError(t, err)
+
+	w := serve(h, http.MethodGet, "/", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, ContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Body.String(), `<meta name="api-base-path" content="/catalog">`)
+	assert.Regexp(t, `src="assets/app.js\?v=[0-9a-f]{12}"`, w.Body.String())
+
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	w = serve(h, http.MethodGet, "/", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
+
+func TestHandler_Assets(t *testing.T) {
+	h, err := New("")
+	require.NoError(t, err)
+
+	index := serve(h, http.MethodGet, "/", nil).Body.String()
+	versioned := regexp.MustCompile(`assets/app\.js\?v=[0-9a-f]+`).FindString(index)
+	require.NotEmpty(t, versioned)
+	assert.Contains(t, index, `content=""`)
+
+	tests := []struct {
+		name         string
+		target       string
+		expectedCode int
+		cacheControl string
+	}{
+		{
+			name:         "current version is cached for good",
+			target:       "/" + versioned,
+			expectedCode: http.StatusOK,
+			cacheControl: "public, max-age=31536000, immutable",
+		},
+		{
+			name:         "other versions are revalidated",
+			target:       "/assets/app.js?v=old",
+			expectedCode: http.StatusOK,
+			cacheControl: "no-cache",
+		},
+		{
+			name:         "unknown asset",
+			target:       "/assets/missing.js",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "unknown page",
+			target:       "/settings",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(h, http.MethodGet, tt.target, nil)
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.cacheControl, w.Header().Get("Cache-Control"))
+			assert.Equal(t, ContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+		})
+	}
+
+	w := serve(h, http.MethodPost, "/", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestNew_RejectsOtherOrigins(t *testing.T) {
+	for _, base := range []string{"catalog", "https://api.example.com", "//api.example.com", "/catalog?x=1"} {
+		_, err := New(base)
+		assert.Error(t, err, base)
+	}
+}
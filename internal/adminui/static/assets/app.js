@@ -0,0 +1,258 @@
+'use strict';
+
+// The server injects the path the API is served under when a proxy puts
+// the service behind a prefix.
+const apiBasePath = document.querySelector('meta[name="api-base-path"]').content;
+const pageSize = 25;
+
+function apiKey() {
+  return sessionStorage.getItem('apiKey') || '';
+}
+
+async function api(path, options = {}) {
+  const headers = { Accept: 'application/json' };
+  if (apiKey()) {
+    headers['X-API-Key'] = apiKey();
+  }
+  const init = { method: options.method || 'GET', headers, credentials: 'same-origin' };
+  if (options.body !== undefined) {
+    headers['Content-Type'] = 'application/json';
+    init.body = JSON.stringify(options.body);
+  }
+  const resp = await fetch(apiBasePath + path, init);
+  const body = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    throw new Error(body.message || body.error || resp.status + ' ' + resp.statusText);
+  }
+  return body;
+}
+
+function el(tag, text, className) {
+  const node = document.createElement(tag);
+  if (text !== undefined && text !== null) {
+    node.textContent = String(text);
+  }
+  if (className) {
+    node.className = className;
+  }
+  return node;
+}
+
+function table(columns, rows) {
+  const tbl = el('table');
+  const head = tbl.createTHead().insertRow();
+  for (const column of columns) {
+    head.appendChild(el('th', column.label));
+  }
+  const body = tbl.createTBody();
+  for (const row of rows) {
+    const tr = body.insertRow();
+    for (const column of columns) {
+      const value = column.value(row);
+      const td = el('td', value);
+      if (column.status) {
+        td.className = 'status-' + value;
+      }
+      tr.appendChild(td);
+    }
+  }
+  if (rows.length === 0) {
+    const td = el('td', 'Nothing to show.');
+    td.colSpan = columns.length;
+    body.insertRow().appendChild(td);
+  }
+  return tbl;
+}
+
+function pager(page, onPage) {
+  const nav = el('div', null, 'pager');
+  const prev = el('button', 'Previous');
+  prev.disabled = page.offset === 0;
+  prev.onclick = () => onPage(Math.max(0, page.offset - page.limit));
+  const next = el('button', 'Next');
+  next.disabled = page.offset + page.limit >= page.total;
+  next.onclick = () => onPage(page.offset + page.limit);
+  const shown = Math.min(page.offset + page.limit, page.total);
+  nav.append(prev, ' ', next, ' ', (page.total === 0 ? 0 : page.offset + 1) + '-' + shown + ' of ' + page.total);
+  return nav;
+}
+
+// section renders into its own container, so one failing request, such as
+// an admin endpoint the caller may not use, leaves the rest of the view.
+async function section(view, title, render) {
+  const container = el('section');
+  container.appendChild(el('h2', title));
+  view.appendChild(container);
+  try {
+    await render(container);
+  } catch (err) {
+    container.appendChild(el('div', err.message, 'error'));
+  }
+}
+
+// settingEditor edits one setting of the live configuration as JSON. Saves
+// carry the version the value was read at, so a change someone else made
+// in the meantime is refused instead of overwritten; Reload fetches it.
+function settingEditor(setting, path) {
+  const box = el('div', null, 'setting');
+  const meta = el('div', null, 'meta');
+  const input = el('textarea');
+  input.rows = 6;
+  input.spellcheck = false;
+  const status = el('div');
+  let version = setting.version;
+
+  const show = (s) => {
+    version = s.version;
+    input.value = JSON.stringify(s.value, null, 2);
+    meta.textContent = s.version === 0
+      ? 'Default, never changed'
+      : 'Version ' + s.version + ', changed by ' + (s.updated_by || 'unknown') + ' at ' + s.updated_at;
+  };
+  const run = async (action) => {
+    status.replaceChildren();
+    try {
+      await action();
+    } catch (err) {
+      status.appendChild(el('div', err.message, 'error'));
+    }
+  };
+
+  const save = el('button', 'Save');
+  save.onclick = () => run(async () => {
+    let value;
+    try {
+      value = JSON.parse(input.value);
+    } catch (err) {
+      throw new Error('Not valid JSON: ' + err.message);
+    }
+    show(await api(path, { method: 'PUT', body: { value, version } }));
+    status.appendChild(el('div', 'Saved.', 'notice'));
+  });
+  const reload = el('button', 'Reload');
+  reload.onclick = () => run(async () => show(await api(path)));
+
+  show(setting);
+  box.append(el('h3', setting.key), meta, input, save, ' ', reload, status);
+  return box;
+}
+
+const views = {
+  async catalog(view) {
+    await section(view, 'Products', async (container) => {
+      const show = async (offset) => {
+        const page = await api('/api/v1/products?limit=' + pageSize + '&offset=' + offset);
+        container.replaceChildren(
+          el('h2', 'Products'),
+          table([
+            { label: 'ID', value: (p) => p.id },
+            { label: 'Store', value: (p) => p.store_id },
+            { label: 'Name', value: (p) => p.name },
+            { label: 'Amount', value: (p) => p.amount + (p.unit ? ' ' + p.unit : '') },
+            { label: 'Price', value: (p) => p.price },
+            { label: 'Status', value: (p) => p.status, status: true },
+            { label: 'Updated', value: (p) => p.updated_at },
+          ], page.products || []),
+          pager(page, (next) => show(next).catch((err) => container.appendChild(el('div', err.message, 'error')))),
+        );
+      };
+      await show(0);
+    });
+  },
+
+  async jobs(view) {
+    await section(view, 'Import feeds', async (container) => {
+      const page = await api('/api/v1/feeds?limit=100');
+      container.appendChild(table([
+        { label: 'ID', value: (f) => f.id },
+        { label: 'Store', value: (f) => f.store_id },
+        { label: 'URL', value: (f) => f.url },
+        { label: 'Every', value: (f) => f.interval_minutes + ' min' },
+        { label: 'Enabled', value: (f) => (f.enabled ? 'yes' : 'no') },
+        { label: 'Last run', value: (f) => f.last_run_at || 'never' },
+      ], page.feeds || []));
+    });
+    await section(view, 'Reconciliation runs', async (container) => {
+      const page = await api('/admin/reconciliations?limit=' + pageSize);
+      container.appendChild(table([
+        { label: 'ID', value: (r) => r.id },
+        { label: 'Store', value: (r) => r.store_id },
+        { label: 'Source', value: (r) => r.source },
+        { label: 'Status', value: (r) => r.status, status: true },
+        { label: 'Checked', value: (r) => r.checked },
+        { label: 'Discrepancies', value: (r) => r.discrepancies },
+        { label: 'Started', value: (r) => r.started_at },
+        { label: 'Finished', value: (r) => r.finished_at || '' },
+      ], page.runs || []));
+    });
+  },
+
+  async webhooks(view) {
+    await section(view, 'Webhook subscriptions', async (container) => {
+      const page = await api('/api/v1/webhooks?limit=100');
+      const webhooks = page.webhooks || [];
+      const health = await Promise.all(webhooks.map((w) => api('/api/v1/webhooks/' + w.id + '/health').catch(() => null)));
+      container.appendChild(table([
+        { label: 'ID', value: (w) => w.id },
+        { label: 'URL', value: (w) => w.url },
+        { label: 'Events', value: (w) => (w.event_types || ['all']).join(', ') },
+        { label: 'Status', value: (w) => w.status, status: true },
+        { label: 'Success rate', value: (w) => (w.health ? Math.round(w.health.success_rate * 100) + '%' : '') },
+        { label: 'Failures in a row', value: (w) => (w.health ? w.health.consecutive_failures : '') },
+        { label: 'Last error', value: (w) => (w.health && w.health.last_error) || w.pause_reason || '' },
+      ], webhooks.map((w, i) => Object.assign({}, w, { health: health[i] }))));
+    });
+  },
+
+  async settings(view) {
+    await section(view, 'Service settings', async (container) => {
+      const page = await api('/admin/config');
+      for (const setting of page.settings || []) {
+        if (!setting.key.startsWith('store:')) {
+          container.appendChild(settingEditor(setting, '/admin/config/' + encodeURIComponent(setting.key)));
+        }
+      }
+    });
+    await section(view, 'Store settings', async (container) => {
+      const form = el('form', null, 'inline');
+      const storeID = el('input');
+      storeID.type = 'number';
+      storeID.min = '1';
+      storeID.required = true;
+      const label = el('label', 'Store ID ');
+      label.appendChild(storeID);
+      form.append(label, ' ', el('button', 'Load'));
+      const editor = el('div');
+      form.addEventListener('submit', async (event) => {
+        event.preventDefault();
+        const path = '/admin/stores/' + encodeURIComponent(storeID.value) + '/settings';
+        try {
+          editor.replaceChildren(settingEditor(await api(path), path));
+        } catch (err) {
+          editor.replaceChildren(el('div', err.message, 'error'));
+        }
+      });
+      container.append(form, editor);
+    });
+  },
+};
+
+async function route() {
+  const name = location.hash.slice(1) || 'catalog';
+  const render = Object.hasOwn(views, name) ? views[name] : views.catalog;
+  for (const link of document.querySelectorAll('header nav a')) {
+    link.classList.toggle('active', link.getAttribute('href') === '#' + name);
+  }
+  const view = document.getElementById('view');
+  view.replaceChildren();
+  await render(view);
+}
+
+document.getElementById('api-key').value = apiKey();
+document.getElementById('credentials').addEventListener('submit', (event) => {
+  event.preventDefault();
+  sessionStorage.setItem('apiKey', document.getElementById('api-key').value);
+  route();
+});
+window.addEventListener('hashchange', route);
+route();
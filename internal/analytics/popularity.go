@@ -0,0 +1,80 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+type PopularityConfig struct {
+	// RefreshInterval is how often popularity scores are recomputed.
+	RefreshInterval time.Duration
+	// HalfLife is how long it takes an event's weight in the score to
+	// halve.
+	HalfLife time.Duration
+}
+
+// PopularityStore keeps the products' popularity scores.
+type PopularityStore interface {
+	// RefreshPopularity recomputes the scores from the recorded counts and
+	// returns how many changed.
+	RefreshPopularity(ctx context.Context, scoring domain.PopularityScoring) (int64, error)
+}
+
+// PopularityJob recomputes the popularity scores that sort=popularity
+// listings are ordered by every RefreshInterval. Scores move with counts as
+// the recorder writes them, and decay each UTC day.
+type PopularityJob struct {
+	store  PopularityStore
+	cfg    PopularityConfig
+	logger *logrus.Logger
+	clock  clock.Clock
+}
+
+func NewPopularityJob(store PopularityStore, cfg PopularityConfig, clk clock.Clock, logger *logrus.Logger) *PopularityJob {
+	return &PopularityJob{
+		store:  store,
+		cfg:    cfg,
+		logger: logger,
+		clock:  clk,
+	}
+}
+
+// Run refreshes the scores every RefreshInterval until ctx is cancelled.
+func (j *PopularityJob) Run(ctx context.Context) {
+	j.logger.WithFields(logrus.Fields{"interval": j.cfg.RefreshInterval, "half_life": j.cfg.HalfLife}).Info("Popularity job started")
+
+	ticker := j.clock.NewTicker(j.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Popularity job stopped")
+			return
+		case <-ticker.C():
+			if err := j.Refresh(ctx); err != nil && ctx.Err() == nil {
+				j.logger.WithError(err).Error("Failed to refresh popularity scores")
+			}
+		}
+	}
+}
+
+// Refresh recomputes the scores for the current UTC day.
+func (j *PopularityJob) Refresh(ctx context.Context) error {
+	changed, err := j.store.RefreshPopularity(ctx, domain.PopularityScoring{
+		Day:      startOfDay(j.clock.Now()),
+		HalfLife: j.cfg.HalfLife,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refresh popularity scores: %w", err)
+	}
+
+	j.logger.WithField("changed", changed).Debug("Popularity scores refreshed")
+	return nil
+}
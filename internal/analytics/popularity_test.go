@@ -0,0 +1,44 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePopularityStore struct {
+	refreshed chan domain.PopularityScoring
+}
+
+func (s *fakePopularityStore) RefreshPopularity(ctx context.Context, scoring domain.PopularityScoring) (int64, error) {
+	s.refreshed <- scoring
+	return 3, nil
+}
+
+func TestPopularityJob_RefreshesEveryInterval(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fake := clock.NewFake(day.Add(23*time.Hour + 30*time.Minute))
+	store := &fakePopularityStore{refreshed: make(chan domain.PopularityScoring, 1)}
+	job := NewPopularityJob(store, PopularityConfig{RefreshInterval: time.Hour, HalfLife: 7 * 24 * time.Hour}, fake, logrus.New())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		job.Run(ctx)
+	}()
+
+	fake.BlockUntilTickers(1)
+	fake.Advance(time.Hour)
+	scoring := <-store.refreshed
+	assert.Equal(t, day.AddDate(0, 0, 1), scoring.Day, "scores are computed for the UTC day of the refresh")
+	assert.Equal(t, day.AddDate(0, 0, 1-70), scoring.Since())
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,169 @@
+// Package analytics counts the storefront events clients report for
+// products: views, add-to-carts and purchases.
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Config struct {
+	// FlushInterval is how often recorded events are written.
+	FlushInterval time.Duration
+	// MaxPending caps the counts held in memory between writes.
+	MaxPending int
+}
+
+// CountStore persists event counts.
+type CountStore interface {
+	// RecordCounts adds the counts to those already stored for the same
+	// product, day and type.
+	RecordCounts(ctx context.Context, counts []domain.AnalyticsCount) error
+}
+
+type countKey struct {
+	productID int64
+	day       time.Time
+	eventType string
+}
+
+// Recorder tallies events per product, day and type in memory and writes
+// the counts every FlushInterval, so ingestion never waits on the
+// database. When MaxPending counts are held, events that would add
+// another are dropped.
+type Recorder struct {
+	store  CountStore
+	cfg    Config
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	pending  map[countKey]*domain.AnalyticsCount
+	dropping bool
+
+	events *telemetry.Counter
+}
+
+func NewRecorder(store CountStore, registry *telemetry.Registry, cfg Config, clk clock.Clock, logger *logrus.Logger) *Recorder {
+	return &Recorder{
+		store:   store,
+		cfg:     cfg,
+		logger:  logger,
+		clock:   clk,
+		pending: make(map[countKey]*domain.AnalyticsCount),
+		events:  registry.NewCounter("analytics_events_total", "Analytics events received, by result.", "result"),
+	}
+}
+
+// Record tallies the events and returns how many were kept.
+func (r *Recorder) Record(events []domain.AnalyticsEvent) int {
+	r.mu.Lock()
+	recorded := 0
+	warn := false
+	for _, event := range events {
+		key := countKey{
+			productID: event.ProductID,
+			day:       startOfDay(event.OccurredAt),
+			eventType: event.Type,
+		}
+
+		count, ok := r.pending[key]
+		if !ok {
+			if len(r.pending) >= r.cfg.MaxPending {
+				warn = warn || !r.dropping
+				r.dropping = true
+				continue
+			}
+			count = &domain.AnalyticsCount{ProductID: key.productID, Day: key.day, Type: key.eventType}
+			r.pending[key] = count
+		}
+		count.Events++
+		recorded++
+	}
+	r.mu.Unlock()
+
+	r.events.Add(float64(recorded), "recorded")
+	if dropped := len(events) - recorded; dropped > 0 {
+		r.events.Add(float64(dropped), "dropped")
+		if warn {
+			r.logger.WithField("max_pending", r.cfg.MaxPending).Warn("Analytics count buffer is full, dropping events")
+		}
+	}
+	return recorded
+}
+
+// Run writes recorded counts every FlushInterval until ctx is cancelled,
+// then writes what is left.
+func (r *Recorder) Run(ctx context.Context) {
+	r.logger.WithField("interval", r.cfg.FlushInterval).Info("Analytics recorder started")
+
+	ticker := r.clock.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := r.Flush(context.WithoutCancel(ctx)); err != nil {
+				r.logger.WithError(err).Error("Failed to write analytics counts on shutdown")
+			}
+			r.logger.Info("Analytics recorder stopped")
+			return
+		case <-ticker.C():
+			if err := r.Flush(ctx); err != nil && ctx.Err() == nil {
+				r.logger.WithError(err).Error("Failed to write analytics counts")
+			}
+		}
+	}
+}
+
+// Flush writes the recorded counts. On failure they are kept for the next
+// flush.
+func (r *Recorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	flushed := r.pending
+	r.pending = make(map[countKey]*domain.AnalyticsCount)
+	r.dropping = false
+	r.mu.Unlock()
+
+	if len(flushed) == 0 {
+		return nil
+	}
+
+	counts := make([]domain.AnalyticsCount, 0, len(flushed))
+	for _, count := range flushed {
+		counts = append(counts, *count)
+	}
+	if err := r.store.RecordCounts(ctx, counts); err != nil {
+		r.requeue(flushed)
+		return fmt.Errorf("failed to record analytics counts: %w", err)
+	}
+	return nil
+}
+
+// requeue merges counts that failed to be written back into those
+// recorded since.
+func (r *Recorder) requeue(flushed map[countKey]*domain.AnalyticsCount) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, count := range flushed {
+		if current, ok := r.pending[key]; ok {
+			current.Events += count.Events
+			continue
+		}
+		r.pending[key] = count
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,115 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore adds up counts the way AnalyticsRepository does in Postgres.
+type fakeStore struct {
+	mu        sync.Mutex
+	counts    map[countKey]int64
+	recordErr error
+}
+
+func (s *fakeStore) RecordCounts(ctx context.Context, counts []domain.AnalyticsCount) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.recordErr != nil {
+		return s.recordErr
+	}
+	if s.counts == nil {
+		s.counts = make(map[countKey]int64)
+	}
+	for _, count := range counts {
+		s.counts[countKey{count.ProductID, count.Day, count.Type}] += count.Events
+	}
+	return nil
+}
+
+func (s *fakeStore) get(productID int64, day time.Time, eventType string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counts[countKey{productID, day, eventType}]
+}
+
+var day = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestRecorder(store *fakeStore, maxPending int) (*Recorder, *telemetry.Registry) {
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	cfg := Config{FlushInterval: time.Second, MaxPending: maxPending}
+	return NewRecorder(store, registry, cfg, clock.Real(), logrus.New()), registry
+}
+
+func event(eventType string, productID int64, at time.Time) domain.AnalyticsEvent {
+	return domain.AnalyticsEvent{ProductID: productID, Type: eventType, OccurredAt: at}
+}
+
+func metrics(t *testing.T, registry *telemetry.Registry) string {
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	return buf.String()
+}
+
+func TestRecorder_TalliesPerProductDayAndType(t *testing.T) {
+	store := &fakeStore{}
+	recorder, registry := newTestRecorder(store, 100)
+
+	recorded := recorder.Record([]domain.AnalyticsEvent{
+		event(domain.AnalyticsEventView, 1, day.Add(9*time.Hour)),
+		event(domain.AnalyticsEventView, 1, day.Add(17*time.Hour)),
+		event(domain.AnalyticsEventPurchase, 1, day.Add(17*time.Hour)),
+		event(domain.AnalyticsEventView, 1, day.Add(25*time.Hour)),
+		event(domain.AnalyticsEventView, 2, day.Add(9*time.Hour)),
+	})
+	require.NoError(t, recorder.Flush(context.Background()))
+
+	assert.Equal(t, 5, recorded)
+	assert.Equal(t, int64(2), store.get(1, day, domain.AnalyticsEventView))
+	assert.Equal(t, int64(1), store.get(1, day, domain.AnalyticsEventPurchase))
+	assert.Equal(t, int64(1), store.get(1, day.AddDate(0, 0, 1), domain.AnalyticsEventView))
+	assert.Equal(t, int64(1), store.get(2, day, domain.AnalyticsEventView))
+	assert.Contains(t, metrics(t, registry), `analytics_events_total{result="recorded"} 5`)
+}
+
+func TestRecorder_KeepsCountsWhenWriteFails(t *testing.T) {
+	store := &fakeStore{recordErr: errors.New("db down")}
+	recorder, _ := newTestRecorder(store, 100)
+	ctx := context.Background()
+
+	recorder.Record([]domain.AnalyticsEvent{event(domain.AnalyticsEventView, 1, day)})
+	require.Error(t, recorder.Flush(ctx))
+	recorder.Record([]domain.AnalyticsEvent{event(domain.AnalyticsEventView, 1, day)})
+
+	store.recordErr = nil
+	require.NoError(t, recorder.Flush(ctx))
+	assert.Equal(t, int64(2), store.get(1, day, domain.AnalyticsEventView))
+}
+
+func TestRecorder_DropsWhenFull(t *testing.T) {
+	recorder, registry := newTestRecorder(&fakeStore{}, 1)
+
+	recorded := recorder.Record([]domain.AnalyticsEvent{
+		event(domain.AnalyticsEventView, 1, day),
+		event(domain.AnalyticsEventAddToCart, 1, day),
+		event(domain.AnalyticsEventView, 1, day),
+	})
+
+	assert.Equal(t, 2, recorded)
+	output := metrics(t, registry)
+	assert.Contains(t, output, `analytics_events_total{result="recorded"} 2`)
+	assert.Contains(t, output, `analytics_events_total{result="dropped"} 1`)
+}
@@ -0,0 +1,169 @@
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// baselineWeight is the EWMA weight of the most recent window.
+	baselineWeight = 0.2
+	alertTimeout   = 10 * time.Second
+)
+
+type Config struct {
+	// Window is the bucket mutation counts are compared over.
+	Window time.Duration
+	// Factor is how many times the baseline a window's count must reach to
+	// be an anomaly.
+	Factor float64
+	// MinEvents keeps low-volume stores from tripping the detector on a
+	// handful of mutations.
+	MinEvents int64
+	// RequireConfirmation makes deletes in an anomalous store fail until the
+	// caller confirms the mass operation.
+	RequireConfirmation bool
+}
+
+type Alerter interface {
+	Alert(ctx context.Context, anomaly domain.MutationAnomaly) error
+}
+
+// Detector tracks per-store mutation rates in fixed windows and compares
+// each window against an exponentially weighted baseline of earlier ones.
+type Detector struct {
+	cfg      Config
+	alerters []Alerter
+	logger   *logrus.Logger
+	now      func() time.Time
+
+	mu     sync.Mutex
+	series map[seriesKey]*series
+}
+
+type seriesKey struct {
+	storeID int64
+	kind    string
+}
+
+type series struct {
+	windowStart time.Time
+	count       int64
+	baseline    float64
+	alerted     bool
+}
+
+func NewDetector(cfg Config, alerters []Alerter, logger *logrus.Logger) *Detector {
+	return &Detector{
+		cfg:      cfg,
+		alerters: alerters,
+		logger:   logger,
+		now:      time.Now,
+		series:   make(map[seriesKey]*series),
+	}
+}
+
+// Record counts one mutation and alerts the first time the current window
+// turns anomalous.
+func (d *Detector) Record(ctx context.Context, storeID int64, kind string) {
+	d.mu.Lock()
+	s := d.current(seriesKey{storeID: storeID, kind: kind})
+	s.count++
+
+	var anomaly *domain.MutationAnomaly
+	if !s.alerted && d.anomalous(s) {
+		s.alerted = true
+		anomaly = &domain.MutationAnomaly{
+			StoreID:    storeID,
+			Kind:       kind,
+			Count:      s.count,
+			Baseline:   s.baseline,
+			Window:     d.cfg.Window.String(),
+			DetectedAt: d.now(),
+		}
+	}
+	d.mu.Unlock()
+
+	if anomaly != nil {
+		d.alert(*anomaly)
+	}
+}
+
+// RequiresConfirmation reports whether a mutation of kind in the store must
+// be confirmed before it runs. Only deletes are guarded.
+func (d *Detector) RequiresConfirmation(storeID int64, kind string) bool {
+	if !d.cfg.RequireConfirmation || kind != domain.MutationDelete {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.anomalous(d.current(seriesKey{storeID: storeID, kind: kind}))
+}
+
+// current returns the series for key, rolling its window forward and folding
+// closed windows into the baseline. Callers hold d.mu.
+func (d *Detector) current(key seriesKey) *series {
+	now := d.now()
+	s, ok := d.series[key]
+	if !ok {
+		s = &series{windowStart: now}
+		d.series[key] = s
+		return s
+	}
+
+	elapsed := now.Sub(s.windowStart)
+	if elapsed < d.cfg.Window {
+		return s
+	}
+
+	closed := int(elapsed / d.cfg.Window)
+	s.baseline = (1-baselineWeight)*s.baseline + baselineWeight*float64(s.count)
+	for i := 1; i < closed && s.baseline > 0.01; i++ {
+		s.baseline *= 1 - baselineWeight
+	}
+	s.windowStart = s.windowStart.Add(time.Duration(closed) * d.cfg.Window)
+	s.count = 0
+	s.alerted = false
+	return s
+}
+
+func (d *Detector) anomalous(s *series) bool {
+	baseline := s.baseline
+	if baseline < 1 {
+		baseline = 1
+	}
+	return s.count >= d.cfg.MinEvents && float64(s.count) >= d.cfg.Factor*baseline
+}
+
+func (d *Detector) alert(anomaly domain.MutationAnomaly) {
+	d.logger.WithFields(logrus.Fields{
+		"action":   "mutation_anomaly",
+		"store_id": anomaly.StoreID,
+		"kind":     anomaly.Kind,
+		"count":    anomaly.Count,
+		"baseline": fmt.Sprintf("%.1f", anomaly.Baseline),
+		"window":   anomaly.Window,
+	}).Warn("Mutation rate anomaly detected")
+
+	if len(d.alerters) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+		defer cancel()
+
+		for _, alerter := range d.alerters {
+			if err := alerter.Alert(ctx, anomaly); err != nil {
+				d.logger.WithError(err).Error("Failed to send mutation anomaly alert")
+			}
+		}
+	}()
+}
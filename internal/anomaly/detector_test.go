@@ -0,0 +1,103 @@
+package anomaly
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAlerter struct {
+	mu        sync.Mutex
+	anomalies []domain.MutationAnomaly
+	done      chan struct{}
+}
+
+func (a *recordingAlerter) Alert(_ context.Context, anomaly domain.MutationAnomaly) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.anomalies = append(a.anomalies, anomaly)
+	close(a.done)
+	return nil
+}
+
+func newTestDetector(alerters ...Alerter) (*Detector, *time.Time) {
+	d := NewDetector(Config{
+		Window:              time.Minute,
+		Factor:              10,
+		MinEvents:           5,
+		RequireConfirmation: true,
+	}, alerters, logrus.New())
+
+	now := time.Now()
+	d.now = func() time.Time { return now }
+	return d, &now
+}
+
+func TestDetector_FlagsSpikeAboveBaseline(t *testing.T) {
+	alerter := &recordingAlerter{done: make(chan struct{})}
+	d, now := newTestDetector(alerter)
+	ctx := context.Background()
+
+	// Ten quiet windows of 2 deletes each establish a baseline of about 1.8.
+	for w := 0; w < 10; w++ {
+		d.Record(ctx, 1, domain.MutationDelete)
+		d.Record(ctx, 1, domain.MutationDelete)
+		*now = now.Add(time.Minute)
+	}
+	assert.False(t, d.RequiresConfirmation(1, domain.MutationDelete))
+
+	for i := 0; i < 15; i++ {
+		d.Record(ctx, 1, domain.MutationDelete)
+	}
+	assert.False(t, d.RequiresConfirmation(1, domain.MutationDelete), "below 10x baseline")
+
+	for i := 0; i < 10; i++ {
+		d.Record(ctx, 1, domain.MutationDelete)
+	}
+	assert.True(t, d.RequiresConfirmation(1, domain.MutationDelete))
+	assert.False(t, d.RequiresConfirmation(2, domain.MutationDelete), "other stores are unaffected")
+
+	select {
+	case <-alerter.done:
+	case <-time.After(time.Second):
+		t.Fatal("alert was not sent")
+	}
+	alerter.mu.Lock()
+	require.Len(t, alerter.anomalies, 1, "one alert per anomalous window")
+	assert.Equal(t, int64(1), alerter.anomalies[0].StoreID)
+	assert.Equal(t, domain.MutationDelete, alerter.anomalies[0].Kind)
+	alerter.mu.Unlock()
+
+	*now = now.Add(time.Minute)
+	assert.False(t, d.RequiresConfirmation(1, domain.MutationDelete), "a new window starts clean")
+}
+
+func TestDetector_MinEvents(t *testing.T) {
+	d, _ := newTestDetector()
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		d.Record(ctx, 1, domain.MutationDelete)
+	}
+	assert.False(t, d.RequiresConfirmation(1, domain.MutationDelete))
+
+	d.Record(ctx, 1, domain.MutationDelete)
+	assert.False(t, d.RequiresConfirmation(1, domain.MutationDelete), "cold start baseline of 1 needs 10 events")
+}
+
+func TestDetector_OnlyGuardsDeletes(t *testing.T) {
+	d, _ := newTestDetector()
+	ctx := context.Background()
+
+	for i := 0; i < 50; i++ {
+		d.Record(ctx, 1, domain.MutationUpdate)
+	}
+	assert.False(t, d.RequiresConfirmation(1, domain.MutationUpdate))
+}
@@ -0,0 +1,46 @@
+package anomaly
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// WebhookAlerter posts each anomaly as JSON to an external endpoint, such as
+// a chat or paging integration.
+type WebhookAlerter struct {
+	client *http.Client
+	url    string
+}
+
+func NewWebhookAlerter(client *http.Client, url string) *WebhookAlerter {
+	return &WebhookAlerter{client: client, url: url}
+}
+
+func (a *WebhookAlerter) Alert(ctx context.Context, anomaly domain.MutationAnomaly) error {
+	body, err := json.Marshal(anomaly)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("alert request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alert webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+// Package apidocs serves the OpenAPI description of the service's HTTP API
+// and a page for reading it and trying requests out. The operations are
+// described by hand in static/openapi.yaml, next to the code they describe;
+// the request and response bodies are generated from the DTOs, so the
+// field names documented are the ones the handlers use.
+package apidocs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// ContentSecurityPolicy lets the page load only its own script and styles
+// and call only its own origin, so descriptions in the spec cannot run
+// anything.
+const ContentSecurityPolicy = "default-src 'none'; script-src 'self'; style-src 'self'; img-src 'self' data:; " +
+	"connect-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+type asset struct {
+	data    []byte
+	version string
+}
+
+// Handler serves the docs. Mount it with the path prefix it is served
+// under stripped: "/index.html" serves the page, "/openapi.json" the spec
+// and "/assets/" the page's script and styles.
+type Handler struct {
+	spec      []byte
+	specETag  string
+	index     []byte
+	indexETag string
+	assets    map[string]asset
+}
+
+// New builds the spec for an API served under apiBasePath, which is empty
+// unless a proxy serves the service under a prefix, and renders the page.
+func New(apiBasePath string) (*Handler, error) {
+	if apiBasePath != "" && (!strings.HasPrefix(apiBasePath, "/") || strings.HasPrefix(apiBasePath, "//") || strings.ContainsAny(apiBasePath, "?#")) {
+		return nil, fmt.Errorf("API docs base path %q must be a path on the same origin, such as /catalog", apiBasePath)
+	}
+
+	spec, err := Spec(apiBasePath)
+	if err != nil {
+		return nil, err
+	}
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI spec: %w", err)
+	}
+
+	assets := make(map[string]asset)
+	err = fs.WalkDir(staticFiles, "static/assets", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := staticFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		assets[path.Base(name)] = asset{data: data, version: contentVersion(data, 6)}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API docs assets: %w", err)
+	}
+
+	tmpl, err := template.New("index.html").Funcs(template.FuncMap{
+		"asset": func(name string) (string, error) {
+			a, ok := assets[name]
+			if !ok {
+				return "", fmt.Errorf("unknown asset %q", name)
+			}
+			return "assets/" + name + "?v=" + a.version, nil
+		},
+	}).ParseFS(staticFiles, "static/index.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API docs page: %w", err)
+	}
+	var index bytes.Buffer
+	if err := tmpl.Execute(&index, nil); err != nil {
+		return nil, fmt.Errorf("failed to render API docs page: %w", err)
+	}
+
+	return &Handler{
+		spec:      specJSON,
+		specETag:  `"` + contentVersion(specJSON, 8) + `"`,
+		index:     index.Bytes(),
+		indexETag: `"` + contentVersion(index.Bytes(), 8) + `"`,
+		assets:    assets,
+	}, nil
+}
+
+// Spec returns the OpenAPI document: the operations of openapi.yaml with
+// the schemas of the bodies they refer to.
+func Spec(apiBasePath string) (map[string]any, error) {
+	source, err := staticFiles.ReadFile("static/openapi.yaml")
+	if err != nil {
+		return nil, err
+	}
+	var spec map[string]any
+	if err := yaml.Unmarshal(source, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi.yaml: %w", err)
+	}
+
+	generated, err := generateSchemas()
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe API bodies: %w", err)
+	}
+	components, _ := spec["components"].(map[string]any)
+	if components == nil {
+		components = make(map[string]any)
+		spec["components"] = components
+	}
+	schemas, _ := components["schemas"].(map[string]any)
+	if schemas == nil {
+		schemas = make(map[string]any)
+		components["schemas"] = schemas
+	}
+	for name, schema := range generated {
+		if _, ok := schemas[name]; ok {
+			return nil, fmt.Errorf("schema %s is both in openapi.yaml and generated", name)
+		}
+		schemas[name] = schema
+	}
+
+	server := apiBasePath
+	if server == "" {
+		server = "/"
+	}
+	spec["servers"] = []any{map[string]any{"url": server}}
+	return spec, nil
+}
+
+func contentVersion(data []byte, size int) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:size])
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Security-Policy", ContentSecurityPolicy)
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("X-Frame-Options", "DENY")
+	header.Set("Referrer-Policy", "no-referrer")
+
+	switch {
+	case r.URL.Path == "/" || r.URL.Path == "":
+		// Relative, so it resolves under the prefix the handler is
+		// mounted at; http.Redirect would make it absolute.
+		header.Set("Location", "index.html")
+		w.WriteHeader(http.StatusFound)
+	case r.URL.Path == "/index.html":
+		header.Set("Cache-Control", "no-cache")
+		header.Set("ETag", h.indexETag)
+		http.ServeContent(w, r, "index.html", time.Time{}, bytes.NewReader(h.index))
+	case r.URL.Path == "/openapi.json":
+		header.Set("Cache-Control", "no-cache")
+		header.Set("ETag", h.specETag)
+		// Lets client generators and editors elsewhere load the spec.
+		header.Set("Access-Control-Allow-Origin", "*")
+		http.ServeContent(w, r, "openapi.json", time.Time{}, bytes.NewReader(h.spec))
+	case strings.HasPrefix(r.URL.Path, "/assets/"):
+		name := strings.TrimPrefix(r.URL.Path, "/assets/")
+		a, ok := h.assets[name]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("v") == a.version {
+			header.Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			header.Set("Cache-Control", "no-cache")
+		}
+		header.Set("ETag", `"`+a.version+`"`)
+		http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(a.data))
+	default:
+		http.NotFound(w, r)
+	}
+}
@@ -0,0 +1,148 @@
+package apidocs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(h http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler_Index(t *testing.T) {
+	h, err := New("")
+	require.NoError(t, err)
+
+	w := serve(h, http.MethodGet, "/", nil)
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "index.html", w.Header().Get("Location"))
+
+	w = serve(h, http.MethodGet, "/index.html", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, ContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.Regexp(t, `src="assets/docs.js\?v=[0-9a-f]{12}"`, w.Body.String())
+
+	versioned := regexp.MustCompile(`assets/docs\.js\?v=[0-9a-f]+`).FindString(w.Body.String())
+	w = serve(h, http.MethodGet, "/"+versioned, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=31536000, immutable", w.Header().Get("Cache-Control"))
+
+	w = serve(h, http.MethodGet, "/assets/missing.js", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(h, http.MethodPost, "/openapi.json", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestHandler_Spec(t *testing.T) {
+	h, err := New("/catalog")
+	require.NoError(t, err)
+
+	w := serve(h, http.MethodGet, "/openapi.json", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	assert.Equal(t, "3.0.3", spec.OpenAPI)
+	require.Len(t, spec.Servers, 1)
+	assert.Equal(t, "/catalog", spec.Servers[0].URL)
+
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	w = serve(h, http.MethodGet, "/openapi.json", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+}
+
+func TestNew_RejectsOtherOrigins(t *testing.T) {
+	for _, base := range []string{"catalog", "https://api.example.com", "//api.example.com", "/catalog?x=1"} {
+		_, err := New(base)
+		assert.Error(t, err, base)
+	}
+}
+
+func TestSpec_Consistent(t *testing.T) {
+	spec, err := Spec("")
+	require.NoError(t, err)
+
+	// Every reference must name a component.
+	var walk func(node any)
+	walk = func(node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			if ref, ok := node["$ref"].(string); ok {
+				var target any = spec
+				for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+					target, _ = target.(map[string]any)[key]
+				}
+				assert.NotNil(t, target, "%s does not resolve", ref)
+			}
+			for _, child := range node {
+				walk(child)
+			}
+		case []any:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+	walk(spec)
+
+	parameters := spec["components"].(map[string]any)["parameters"].(map[string]any)
+	param := regexp.MustCompile(`\{([a-z_]+)\}`)
+	operationIDs := make(map[string]bool)
+	for path, item := range spec["paths"].(map[string]any) {
+		item := item.(map[string]any)
+		for method, operation := range item {
+			if method == "parameters" {
+				continue
+			}
+			operation := operation.(map[string]any)
+			id, _ := operation["operationId"].(string)
+			assert.NotEmpty(t, id, "%s %s has no operationId", method, path)
+			assert.False(t, operationIDs[id], "operationId %s is used twice", id)
+			operationIDs[id] = true
+			assert.NotEmpty(t, operation["responses"], "%s %s has no responses", method, path)
+
+			// Every path parameter must be declared.
+			declared := make(map[string]bool)
+			for _, list := range []any{item["parameters"], operation["parameters"]} {
+				list, _ := list.([]any)
+				for _, p := range list {
+					p := p.(map[string]any)
+					if ref, ok := p["$ref"].(string); ok {
+						p, _ = parameters[strings.TrimPrefix(ref, "#/components/parameters/")].(map[string]any)
+					}
+					if p["in"] == "path" {
+						declared[p["name"].(string)] = true
+					}
+				}
+			}
+			for _, match := range param.FindAllStringSubmatch(path, -1) {
+				assert.True(t, declared[match[1]], "%s %s does not declare %s", method, path, match[1])
+			}
+		}
+	}
+}
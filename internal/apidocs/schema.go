@@ -0,0 +1,417 @@
+package apidocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/graphql"
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/lockout"
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+// bodies are the request and response bodies the spec refers to by name.
+// The types they are made of are documented under their Go names. Paged
+// lists are documented in both shapes, the envelope named after the list.
+var bodies = map[string]any{
+	"AnalyticsEventRequest":         dto.AnalyticsEventRequest{},
+	"RecordAnalyticsEventsRequest":  dto.RecordAnalyticsEventsRequest{},
+	"RecordAnalyticsEventsResponse": dto.RecordAnalyticsEventsResponse{},
+	"ProductStatsResponse":          dto.ProductStatsResponse{},
+
+	"RegisterRequest":     dto.RegisterRequest{},
+	"LoginRequest":        dto.LoginRequest{},
+	"RefreshTokenRequest": dto.RefreshTokenRequest{},
+	"UserResponse":        dto.UserResponse{},
+	"TokenResponse":       dto.TokenResponse{},
+
+	"TwoFactorCodeRequest":   dto.TwoFactorCodeRequest{},
+	"TwoFactorSetupResponse": dto.TwoFactorSetupResponse{},
+	"RecoveryCodesResponse":  dto.RecoveryCodesResponse{},
+	"LoginMetrics":           lockout.Metrics{},
+
+	"CreateSessionRequest":  dto.CreateSessionRequest{},
+	"CreateSessionResponse": dto.CreateSessionResponse{},
+	"SessionListResponse":   dto.SessionListResponse{},
+	"CSRFTokenResponse":     dto.CSRFTokenResponse{},
+
+	"ErrorResponse":            dto.ErrorResponse{},
+	"BulkConfirmationResponse": dto.BulkConfirmationResponse{},
+
+	"CreateProductRequest":      dto.CreateProductRequest{},
+	"UpdateProductRequest":      dto.UpdateProductRequest{},
+	"PatchProductRequest":       dto.PatchProductRequest{},
+	"ProductResponse":           dto.ProductResponse{},
+	"ProductListResponse":       dto.ProductListResponse{},
+	"ProductListEnvelope":       dto.ListResponse[dto.ProductResponse]{},
+	"BulkProductsRequest":       dto.BulkProductsRequest{},
+	"BulkProductsResponse":      dto.BulkProductsResponse{},
+	"BulkProductsErrorResponse": dto.BulkProductsErrorResponse{},
+	"ProductImportResponse":     dto.ProductImportResponse{},
+	"CatalogDiffResponse":       dto.CatalogDiffResponse{},
+	"ProductImageListResponse":  dto.ProductImageListResponse{},
+	"CreatePreviewTokenRequest": dto.CreatePreviewTokenRequest{},
+	"PreviewTokenResponse":      dto.PreviewTokenResponse{},
+	"PriceQuoteResponse":        dto.PriceQuoteResponse{},
+
+	"SaveBundleRequest": dto.SaveBundleRequest{},
+	"SellBundleRequest": dto.SellBundleRequest{},
+	"BundleResponse":    dto.BundleResponse{},
+
+	"SaveStoreRequest":     dto.SaveStoreRequest{},
+	"StoreResponse":        dto.StoreResponse{},
+	"StoreListResponse":    dto.StoreListResponse{},
+	"StoreListEnvelope":    dto.ListResponse[dto.StoreResponse]{},
+	"StockSummaryResponse": dto.StockSummaryResponse{},
+
+	"SaveCategoryRequest":         dto.SaveCategoryRequest{},
+	"CategoryResponse":            dto.CategoryResponse{},
+	"CategoryListResponse":        dto.CategoryListResponse{},
+	"CategoryListEnvelope":        dto.ListResponse[dto.CategoryResponse]{},
+	"SetProductCategoriesRequest": dto.SetProductCategoriesRequest{},
+	"ProductCategoryListResponse": dto.ProductCategoryListResponse{},
+
+	"CatalogSnapshotResponse":     dto.CatalogSnapshotResponse{},
+	"CatalogSnapshotListResponse": dto.CatalogSnapshotListResponse{},
+	"CatalogSnapshotListEnvelope": dto.ListResponse[dto.CatalogSnapshotResponse]{},
+	"CatalogRestoreResponse":      dto.CatalogRestoreResponse{},
+
+	"SaveImportMappingRequest":  dto.SaveImportMappingRequest{},
+	"ImportMappingResponse":     dto.ImportMappingResponse{},
+	"ImportMappingListResponse": dto.ImportMappingListResponse{},
+	"ImportPreviewResponse":     dto.ImportPreviewResponse{},
+
+	"CreateOrderRequest": dto.CreateOrderRequest{},
+	"OrderResponse":      dto.OrderResponse{},
+	"OrderListResponse":  dto.OrderListResponse{},
+	"OrderListEnvelope":  dto.ListResponse[dto.OrderResponse]{},
+
+	"SavePricingPolicyRequest":  dto.SavePricingPolicyRequest{},
+	"PricingPolicyResponse":     dto.PricingPolicyResponse{},
+	"PricingPolicyListResponse": dto.PricingPolicyListResponse{},
+
+	"SaveDigestSettingsRequest": dto.SaveDigestSettingsRequest{},
+	"DigestSettingsResponse":    dto.DigestSettingsResponse{},
+
+	"EventSchemaListResponse": dto.EventSchemaListResponse{},
+
+	"CreateFeedRequest":       dto.CreateFeedRequest{},
+	"FeedResponse":            dto.FeedResponse{},
+	"FeedListResponse":        dto.FeedListResponse{},
+	"FeedListEnvelope":        dto.ListResponse[dto.FeedResponse]{},
+	"FeedRunResponse":         dto.FeedRunResponse{},
+	"FeedRunListResponse":     dto.FeedRunListResponse{},
+	"FeedRunListEnvelope":     dto.ListResponse[dto.FeedRunResponse]{},
+	"ImageImportListResponse": dto.ImageImportListResponse{},
+
+	"TrashListResponse":  dto.TrashListResponse{},
+	"TrashListEnvelope":  dto.ListResponse[dto.TrashedProductResponse]{},
+	"EmptyTrashResponse": dto.EmptyTrashResponse{},
+
+	"CreateWebhookRequest":  dto.CreateWebhookRequest{},
+	"WebhookResponse":       dto.WebhookResponse{},
+	"WebhookListResponse":   dto.WebhookListResponse{},
+	"WebhookListEnvelope":   dto.ListResponse[dto.WebhookResponse]{},
+	"WebhookHealthResponse": dto.WebhookHealthResponse{},
+	"WebhookSecretResponse": dto.WebhookSecretResponse{},
+
+	"GraphQLRequest": graphql.Request{},
+
+	"LivenessResponse":            dto.LivenessResponse{},
+	"DependencyReadinessResponse": dto.DependencyReadinessResponse{},
+	"ReadinessResponse":           dto.ReadinessResponse{},
+	"ReplicationResponse":         dto.ReplicationResponse{},
+
+	"HotKeysResponse":    dto.HotKeysResponse{},
+	"CacheStatsResponse": dto.CacheStatsResponse{},
+
+	"ConfigSettingsResponse":   dto.ConfigSettingsResponse{},
+	"ConfigSettingResponse":    dto.ConfigSettingResponse{},
+	"SaveConfigSettingRequest": dto.SaveConfigSettingRequest{},
+
+	"CreateConnectorRequest":    dto.CreateConnectorRequest{},
+	"ConnectorResponse":         dto.ConnectorResponse{},
+	"ConnectorListResponse":     dto.ConnectorListResponse{},
+	"ConnectorListEnvelope":     dto.ListResponse[dto.ConnectorResponse]{},
+	"ConnectorSyncResponse":     dto.ConnectorSyncResponse{},
+	"ConnectorSyncListResponse": dto.ConnectorSyncListResponse{},
+	"ConnectorSyncListEnvelope": dto.ListResponse[dto.ConnectorSyncResponse]{},
+
+	"DBHealthResponse":          dto.DBHealthResponse{},
+	"IndexAdviceReportResponse": dto.IndexAdviceReportResponse{},
+
+	"DrainRequest":  dto.DrainRequest{},
+	"DrainResponse": dto.DrainResponse{},
+
+	"ImpersonateRequest":    dto.ImpersonateRequest{},
+	"ImpersonationResponse": dto.ImpersonationResponse{},
+
+	"ReviewProductRequest": dto.ReviewProductRequest{},
+
+	"ReconcileRequest":              dto.ReconcileRequest{},
+	"ReconciliationRunResponse":     dto.ReconciliationRunResponse{},
+	"ReconciliationRunListResponse": dto.ReconciliationRunListResponse{},
+	"ReconciliationRunListEnvelope": dto.ListResponse[dto.ReconciliationRunResponse]{},
+	"DiscrepancyListResponse":       dto.DiscrepancyListResponse{},
+	"DiscrepancyListEnvelope":       dto.ListResponse[dto.DiscrepancyResponse]{},
+
+	"RetentionReportResponse":     dto.RetentionReportResponse{},
+	"UsageReconciliationResponse": dto.UsageReconciliationResponse{},
+}
+
+// Types whose JSON is not that of their fields.
+var (
+	timeType         = reflect.TypeOf(time.Time{})
+	nullableTimeType = reflect.TypeOf(dto.NullableTime{})
+	quantityType     = reflect.TypeOf(quantity.Quantity{})
+	rawMessageType   = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaGenerator describes Go types as OpenAPI schemas the way
+// encoding/json writes them, and the validation gin applies to requests
+// from their binding tags.
+type schemaGenerator struct {
+	schemas map[string]any
+	names   map[reflect.Type]string
+}
+
+// generateSchemas documents bodies and the types they are made of.
+func generateSchemas() (map[string]any, error) {
+	g := &schemaGenerator{schemas: make(map[string]any), names: make(map[reflect.Type]string)}
+	for name, body := range bodies {
+		g.names[reflect.TypeOf(body)] = name
+	}
+	for name, body := range bodies {
+		if _, err := g.define(name, reflect.TypeOf(body)); err != nil {
+			return nil, err
+		}
+	}
+	return g.schemas, nil
+}
+
+// define documents t under name, once, and returns a reference to it.
+func (g *schemaGenerator) define(name string, t reflect.Type) (map[string]any, error) {
+	ref := map[string]any{"$ref": "#/components/schemas/" + name}
+	if _, ok := g.schemas[name]; ok {
+		return ref, nil
+	}
+	// Taken before the fields are described, so types that refer to
+	// themselves end.
+	g.schemas[name] = nil
+	schema, err := g.object(t, strings.HasSuffix(name, "Request"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	g.schemas[name] = schema
+	return ref, nil
+}
+
+// object describes a struct. A request's required fields are those its
+// binding tags require; a response's are those it always writes.
+func (g *schemaGenerator) object(t reflect.Type, request bool) (map[string]any, error) {
+	properties := make(map[string]any)
+	var required []string
+	if err := g.fields(t, request, properties, &required); err != nil {
+		return nil, err
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+func (g *schemaGenerator) fields(t reflect.Type, request bool, properties map[string]any, required *[]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			// Embedded structs' fields are written as the outer one's.
+			if err := g.fields(field.Type, request, properties, required); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		omitEmpty := strings.Contains(options, "omitempty")
+
+		schema, err := g.schema(field.Type, !omitEmpty)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		// Responses write times as RFC 3339 strings in fields named
+		// after the event.
+		if schema["type"] == "string" && strings.HasSuffix(name, "_at") {
+			schema["format"] = "date-time"
+		}
+		rules := field.Tag.Get("binding")
+		if rules != "" {
+			schema = applyRules(schema, rules)
+		}
+		properties[name] = schema
+
+		if request && hasRule(rules, "required") || !request && !omitEmpty {
+			*required = append(*required, name)
+		}
+	}
+	return nil
+}
+
+// schema describes a value of type t. A nullable pointer is written as
+// null when it is nil.
+func (g *schemaGenerator) schema(t reflect.Type, nullable bool) (map[string]any, error) {
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	case nullableTimeType:
+		return map[string]any{"type": "string", "format": "date-time", "nullable": true}, nil
+	case quantityType:
+		return map[string]any{"type": "number", "description": "A decimal with up to three places."}, nil
+	case rawMessageType:
+		return map[string]any{"description": "Any JSON value."}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema, err := g.schema(t.Elem(), nullable)
+		if err != nil || !nullable {
+			return schema, err
+		}
+		if _, ok := schema["$ref"]; ok {
+			return map[string]any{"allOf": []any{schema}, "nullable": true}, nil
+		}
+		schema["nullable"] = true
+		return schema, nil
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int32:
+		return map[string]any{"type": "integer", "format": "int32"}, nil
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}, nil
+	case reflect.Float64:
+		return map[string]any{"type": "number", "format": "double"}, nil
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Slice:
+		items, err := g.schema(t.Elem(), false)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key %s is not a string", t.Key())
+		}
+		values, err := g.schema(t.Elem(), false)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case reflect.Interface:
+		return map[string]any{"description": "Any JSON value."}, nil
+	case reflect.Struct:
+		if name, ok := g.names[t]; ok {
+			return g.define(name, t)
+		}
+		if t.Name() == "" {
+			return g.object(t, false)
+		}
+		if strings.Contains(t.Name(), "[") {
+			return nil, fmt.Errorf("generic type %s must be named in bodies", t)
+		}
+		g.names[t] = t.Name()
+		return g.define(t.Name(), t)
+	}
+	return nil, fmt.Errorf("type %s cannot be described", t)
+}
+
+// applyRules adds the binding rules of a field to its schema. Rules after
+// dive apply to the elements of a slice.
+func applyRules(schema map[string]any, rules string) map[string]any {
+	own, elements, dive := strings.Cut(rules, ",dive")
+	if dive {
+		if items, ok := schema["items"].(map[string]any); ok {
+			if _, ok := items["$ref"]; !ok {
+				applyRules(items, strings.TrimPrefix(elements, ","))
+			}
+		}
+	}
+
+	target := schema
+	if allOf, ok := schema["allOf"].([]any); ok {
+		target = allOf[0].(map[string]any)
+	}
+	if _, ok := target["$ref"]; ok {
+		return schema
+	}
+	for _, rule := range strings.Split(own, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "oneof":
+			var values []any
+			for _, value := range strings.Fields(param) {
+				values = append(values, value)
+			}
+			target["enum"] = values
+		case "url":
+			target["format"] = "uri"
+		case "email":
+			target["format"] = "email"
+		case "min", "max", "gt", "gte":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			bound(target, name, n)
+		}
+	}
+	return schema
+}
+
+// bound limits a number's value, a string's length or a slice's length.
+func bound(schema map[string]any, rule string, n float64) {
+	var lower, upper string
+	switch schema["type"] {
+	case "string":
+		lower, upper = "minLength", "maxLength"
+	case "array":
+		lower, upper = "minItems", "maxItems"
+	case "integer", "number":
+		lower, upper = "minimum", "maximum"
+	default:
+		return
+	}
+	switch rule {
+	case "min", "gte":
+		schema[lower] = n
+	case "max":
+		schema[upper] = n
+	case "gt":
+		schema[lower] = n
+		if lower == "minimum" {
+			schema["exclusiveMinimum"] = true
+		} else {
+			schema[lower] = n + 1
+		}
+	}
+}
+
+func hasRule(rules, rule string) bool {
+	own, _, _ := strings.Cut(rules, ",dive")
+	for _, r := range strings.Split(own, ",") {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,404 @@
+'use strict';
+
+const methods = ['get', 'post', 'put', 'patch', 'delete'];
+let spec;
+
+function apiKey() {
+  return sessionStorage.getItem('apiKey') || '';
+}
+
+function el(tag, text, className) {
+  const node = document.createElement(tag);
+  if (text !== undefined && text !== null) {
+    node.textContent = String(text);
+  }
+  if (className) {
+    node.className = className;
+  }
+  return node;
+}
+
+// resolve follows a $ref to the component it names.
+function resolve(item) {
+  while (item && item.$ref) {
+    item = item.$ref.replace(/^#\//, '').split('/').reduce((node, key) => node[key], spec);
+  }
+  return item || {};
+}
+
+function refName(schema) {
+  return schema && schema.$ref ? schema.$ref.split('/').pop() : '';
+}
+
+function typeOf(schema) {
+  const name = refName(schema);
+  if (name) {
+    return name;
+  }
+  if (schema.oneOf) {
+    return schema.oneOf.map(typeOf).join(' | ');
+  }
+  if (schema.allOf) {
+    return schema.allOf.map(typeOf).join(' & ') + (schema.nullable ? ' | null' : '');
+  }
+  let type = schema.type || 'any';
+  if (type === 'array') {
+    type = typeOf(schema.items || {}) + '[]';
+  } else if (type === 'object' && schema.additionalProperties) {
+    type = 'map of ' + typeOf(schema.additionalProperties === true ? {} : schema.additionalProperties);
+  }
+  if (schema.format) {
+    type += ' (' + schema.format + ')';
+  }
+  return type + (schema.nullable ? ' | null' : '');
+}
+
+function constraints(schema) {
+  const rules = [];
+  if (schema.enum) {
+    rules.push('one of ' + schema.enum.join(', '));
+  }
+  if (schema.minimum !== undefined) {
+    rules.push((schema.exclusiveMinimum ? '> ' : '≥ ') + schema.minimum);
+  }
+  for (const [key, label] of [['maximum', '≤'], ['minLength', 'length ≥'], ['maxLength', 'length ≤'], ['minItems', 'items ≥'], ['maxItems', 'items ≤']]) {
+    if (schema[key] !== undefined) {
+      rules.push(label + ' ' + schema[key]);
+    }
+  }
+  if (schema.default !== undefined) {
+    rules.push('default ' + schema.default);
+  }
+  return rules.join('; ');
+}
+
+// schemaTable lists an object's fields, opening the objects they hold up
+// to a few levels deep. Named schemas are shown once per table.
+function schemaTable(schema, seen = new Set(), depth = 0) {
+  const name = refName(schema);
+  schema = resolve(schema);
+  if (schema.oneOf) {
+    const wrap = el('div');
+    wrap.appendChild(el('p', 'One of:'));
+    for (const option of schema.oneOf) {
+      wrap.appendChild(el('h4', refName(option) || typeOf(option)));
+      wrap.appendChild(schemaTable(option, new Set(seen), depth));
+    }
+    return wrap;
+  }
+  if (schema.type === 'array') {
+    return schemaTable(schema.items || {}, seen, depth);
+  }
+  if (!schema.properties) {
+    return el('p', typeOf(schema) + (schema.description ? ' — ' + schema.description : ''));
+  }
+  if (name) {
+    seen.add(name);
+  }
+
+  const tbl = el('table', null, 'schema');
+  const head = tbl.createTHead().insertRow();
+  for (const label of ['Field', 'Type', 'Description']) {
+    head.appendChild(el('th', label));
+  }
+  const body = tbl.createTBody();
+  const required = new Set(schema.required || []);
+  for (const [field, property] of Object.entries(schema.properties)) {
+    const tr = body.insertRow();
+    tr.appendChild(el('td')).appendChild(el('code', field + (required.has(field) ? '' : '?')));
+    tr.appendChild(el('td', typeOf(property)));
+    const td = tr.appendChild(el('td'));
+    const resolved = resolve(property);
+    const notes = [property.description || resolved.description, constraints(resolved)].filter(Boolean);
+    td.appendChild(el('div', notes.join('. '), 'description'));
+
+    let nested = property;
+    while (true) {
+      const r = resolve(nested);
+      if (r.type === 'array' && r.items) {
+        nested = r.items;
+      } else if (r.allOf) {
+        nested = r.allOf[0];
+      } else {
+        break;
+      }
+    }
+    const nestedName = refName(nested);
+    if (depth < 4 && resolve(nested).properties && !seen.has(nestedName)) {
+      td.appendChild(schemaTable(nested, new Set(seen), depth + 1));
+    }
+  }
+  return tbl;
+}
+
+// example builds a request body to start a try-it from.
+function example(schema, depth = 0) {
+  schema = resolve(schema);
+  if (schema.allOf) {
+    return example(schema.allOf[0], depth);
+  }
+  if (schema.oneOf) {
+    return example(schema.oneOf[0], depth);
+  }
+  if (schema.default !== undefined) {
+    return schema.default;
+  }
+  if (schema.enum) {
+    return schema.enum[0];
+  }
+  switch (schema.type) {
+    case 'object': {
+      const value = {};
+      const required = new Set(schema.required || []);
+      for (const [field, property] of Object.entries(schema.properties || {})) {
+        if (depth < 4 && (required.has(field) || depth === 0)) {
+          value[field] = example(property, depth + 1);
+        }
+      }
+      return value;
+    }
+    case 'array':
+      return depth < 4 ? [example(schema.items || {}, depth + 1)] : [];
+    case 'integer':
+    case 'number':
+      return schema.minimum || 0;
+    case 'boolean':
+      return false;
+    case 'string':
+      return schema.format === 'date-time' ? new Date().toISOString() : '';
+    default:
+      return null;
+  }
+}
+
+function parameters(pathItem, operation) {
+  const params = new Map();
+  for (const param of [...(pathItem.parameters || []), ...(operation.parameters || [])]) {
+    const resolved = resolve(param);
+    params.set(resolved.in + ':' + resolved.name, resolved);
+  }
+  return [...params.values()];
+}
+
+function renderParameters(params) {
+  const tbl = el('table');
+  const head = tbl.createTHead().insertRow();
+  for (const label of ['Name', 'In', 'Type', 'Description']) {
+    head.appendChild(el('th', label));
+  }
+  const body = tbl.createTBody();
+  for (const param of params) {
+    const tr = body.insertRow();
+    tr.appendChild(el('td')).appendChild(el('code', param.name + (param.required ? '' : '?')));
+    tr.appendChild(el('td', param.in));
+    tr.appendChild(el('td', typeOf(param.schema || {})));
+    const notes = [param.description, constraints(resolve(param.schema))].filter(Boolean);
+    tr.appendChild(el('td', notes.join('. '), 'description'));
+  }
+  return tbl;
+}
+
+function renderResponses(responses) {
+  const tbl = el('table');
+  const head = tbl.createTHead().insertRow();
+  for (const label of ['Status', 'Description', 'Body']) {
+    head.appendChild(el('th', label));
+  }
+  const body = tbl.createTBody();
+  for (const [status, response] of Object.entries(responses)) {
+    const resolved = resolve(response);
+    const tr = body.insertRow();
+    tr.appendChild(el('td', status));
+    tr.appendChild(el('td', resolved.description, 'description'));
+    const td = tr.appendChild(el('td'));
+    for (const [type, media] of Object.entries(resolved.content || {})) {
+      td.appendChild(el('div', type + ': ' + typeOf(media.schema || {})));
+    }
+  }
+  return tbl;
+}
+
+function renderTry(path, method, params, requestBody) {
+  const form = el('form', null, 'try');
+  const inputs = [];
+  for (const param of params) {
+    if (param.in === 'cookie') {
+      continue;
+    }
+    const label = el('label', param.name + ' (' + param.in + ')');
+    const input = el('input');
+    input.placeholder = typeOf(param.schema || {});
+    input.required = !!param.required;
+    label.appendChild(input);
+    form.appendChild(label);
+    inputs.push([param, input]);
+  }
+
+  let bodyInput;
+  const json = requestBody && resolve(requestBody).content && resolve(requestBody).content['application/json'];
+  if (json) {
+    bodyInput = el('textarea');
+    bodyInput.rows = 8;
+    bodyInput.value = JSON.stringify(example(json.schema), null, 2);
+    form.appendChild(bodyInput);
+  } else if (requestBody) {
+    form.appendChild(el('p', 'This operation takes a file; send it with a client such as curl.'));
+  }
+
+  const send = el('button', 'Send');
+  send.type = 'submit';
+  form.appendChild(send);
+  const result = el('div');
+  form.appendChild(result);
+
+  form.addEventListener('submit', async (event) => {
+    event.preventDefault();
+    let url = path;
+    const query = new URLSearchParams();
+    const headers = { Accept: 'application/json' };
+    if (apiKey()) {
+      headers['X-API-Key'] = apiKey();
+    }
+    for (const [param, input] of inputs) {
+      if (input.value === '') {
+        continue;
+      }
+      if (param.in === 'path') {
+        url = url.replace('{' + param.name + '}', encodeURIComponent(input.value));
+      } else if (param.in === 'query') {
+        query.set(param.name, input.value);
+      } else if (param.in === 'header') {
+        headers[param.name] = input.value;
+      }
+    }
+    const base = spec.servers[0].url.replace(/\/$/, '');
+    const init = { method: method.toUpperCase(), headers, credentials: 'same-origin' };
+    if (bodyInput) {
+      headers['Content-Type'] = 'application/json';
+      init.body = bodyInput.value;
+    }
+
+    result.replaceChildren(el('p', 'Sending…'));
+    try {
+      const resp = await fetch(base + url + (query.toString() ? '?' + query : ''), init);
+      let text = await resp.text();
+      try {
+        text = JSON.stringify(JSON.parse(text), null, 2);
+      } catch (err) {
+        // Not JSON; show it as it is.
+      }
+      result.replaceChildren(el('h4', resp.status + ' ' + resp.statusText), el('pre', text));
+    } catch (err) {
+      result.replaceChildren(el('div', err.message, 'error'));
+    }
+  });
+  return form;
+}
+
+function renderOperation(path, method, pathItem, operation) {
+  const details = el('details', null, 'operation');
+  details.id = operation.operationId;
+  const summary = el('summary');
+  summary.appendChild(el('span', method, 'method method-' + method));
+  summary.appendChild(el('code', path));
+  summary.appendChild(el('span', operation.summary, 'summary'));
+  details.appendChild(summary);
+
+  // The body is rendered when the operation is first opened.
+  details.addEventListener('toggle', () => {
+    if (!details.open || details.querySelector('.body')) {
+      return;
+    }
+    const body = el('div', null, 'body');
+    if (operation.description) {
+      body.appendChild(el('p', operation.description, 'description'));
+    }
+    const params = parameters(pathItem, operation);
+    if (params.length > 0) {
+      body.appendChild(el('h4', 'Parameters'));
+      body.appendChild(renderParameters(params));
+    }
+    if (operation.requestBody) {
+      const requestBody = resolve(operation.requestBody);
+      for (const [type, media] of Object.entries(requestBody.content || {})) {
+        body.appendChild(el('h4', 'Request body (' + type + ')'));
+        body.appendChild(schemaTable(media.schema || {}));
+      }
+    }
+    body.appendChild(el('h4', 'Responses'));
+    body.appendChild(renderResponses(operation.responses || {}));
+    for (const [status, response] of Object.entries(operation.responses || {})) {
+      const json = (resolve(response).content || {})['application/json'];
+      if (status.startsWith('2') && json && json.schema) {
+        body.appendChild(el('h4', status + ' body'));
+        body.appendChild(schemaTable(json.schema));
+      }
+    }
+    body.appendChild(el('h4', 'Try it'));
+    body.appendChild(renderTry(path, method, params, operation.requestBody));
+    details.appendChild(body);
+  });
+  return details;
+}
+
+function render() {
+  const view = document.getElementById('view');
+  const toc = document.getElementById('toc');
+  view.replaceChildren();
+  toc.replaceChildren();
+
+  view.appendChild(el('h2', spec.info.title));
+  view.appendChild(el('p', spec.info.description, 'description'));
+
+  const byTag = new Map((spec.tags || []).map((tag) => [tag.name, []]));
+  for (const [path, pathItem] of Object.entries(spec.paths)) {
+    for (const method of methods) {
+      const operation = pathItem[method];
+      if (!operation) {
+        continue;
+      }
+      const tag = (operation.tags || ['Other'])[0];
+      if (!byTag.has(tag)) {
+        byTag.set(tag, []);
+      }
+      byTag.get(tag).push(renderOperation(path, method, pathItem, operation));
+    }
+  }
+
+  for (const [tag, operations] of byTag) {
+    if (operations.length === 0) {
+      continue;
+    }
+    const id = 'tag-' + tag.toLowerCase().replace(/[^a-z0-9]+/g, '-');
+    const heading = el('h2', tag);
+    heading.id = id;
+    view.appendChild(heading);
+    for (const operation of operations) {
+      view.appendChild(operation);
+    }
+    const link = el('a', tag);
+    link.href = '#' + id;
+    toc.appendChild(link);
+  }
+}
+
+document.getElementById('api-key').value = apiKey();
+document.getElementById('credentials').addEventListener('submit', (event) => {
+  event.preventDefault();
+  sessionStorage.setItem('apiKey', document.getElementById('api-key').value);
+});
+
+fetch('openapi.json')
+  .then((resp) => {
+    if (!resp.ok) {
+      throw new Error('Failed to load openapi.json: ' + resp.status);
+    }
+    return resp.json();
+  })
+  .then((loaded) => {
+    spec = loaded;
+    render();
+  })
+  .catch((err) => {
+    document.getElementById('view').replaceChildren(el('div', err.message, 'error'));
+  });
@@ -0,0 +1,242 @@
+package app
+
+import (
+	"database/sql"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/internal/backup"
+	"backend-context-engineering-template/internal/dbhealth"
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/indexadvisor"
+	"backend-context-engineering-template/internal/repository/cached"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/retention"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/explain"
+	"backend-context-engineering-template/pkg/ratelimit"
+	"backend-context-engineering-template/pkg/s3"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// AdminModule provides what operators look after the catalog and its
+// database with: retention, query plans and database health, catalog
+// diffs, stores, reconciliation, snapshots and backups. All of it needs
+// Postgres and is left out in load-test mode.
+var AdminModule = fx.Module("admin",
+	fx.Provide(
+		provideRetention,
+		provideExplainCapturer,
+		provideDBHealth,
+		provideCatalogDiff,
+		provideStores,
+		provideReconciliation,
+		provideSnapshots,
+		provideBackup,
+	),
+	routesFor(httpDelivery.RetentionRoutes),
+	routesFor(httpDelivery.DBHealthRoutes),
+	routesFor(httpDelivery.CatalogDiffRoutes),
+	routesFor(httpDelivery.StoreRoutes),
+	routesFor(httpDelivery.ReconciliationRoutes),
+	routesFor(httpDelivery.CatalogSnapshotRoutes),
+)
+
+// ReconciliationSource is a source the catalog is reconciled with, keyed
+// by its domain.ReconciliationSource kind. Modules contribute them to the
+// "reconciliation_sources" group.
+type ReconciliationSource struct {
+	Kind   string
+	Source usecase.ReferenceSource
+}
+
+type retentionResult struct {
+	fx.Out
+
+	Handler       *handlers.RetentionHandler
+	Workers       []Worker       `group:"workers,flatten"`
+	ScheduledJobs []ScheduledJob `group:"scheduled_jobs,flatten"`
+}
+
+// provideRetention deletes old rows in batches, the trashed products past
+// their retention among them. Those and the expired idempotency keys are
+// left to the nightly cleanup unless its schedule is "none", as purging
+// them can delete a day's worth of rows at once.
+func provideRetention(cfg *config.Config, db *sql.DB, registry *telemetry.Registry, clk clock.Clock, logger *logrus.Logger) retentionResult {
+	if db == nil {
+		return retentionResult{}
+	}
+	worker := retention.NewWorker(postgres.NewRetentionRepository(db),
+		retention.Rules(cfg.Retention.AuditLogs, cfg.Trash.Retention, cfg.Retention.JobRecords, cfg.Retention.InboxMessages, cfg.Retention.CatalogChanges, cfg.Idempotency.KeyTTL, cfg.Retention.SentEvents), registry, retention.Config{
+			Interval:   cfg.Retention.Interval,
+			BatchSize:  cfg.Retention.BatchSize,
+			BatchDelay: cfg.Retention.BatchDelay,
+		}, clk, logger)
+	result := retentionResult{
+		Handler: handlers.NewRetentionHandler(worker),
+		Workers: []Worker{{Run: worker.Run, PrimaryOnly: true}},
+	}
+	if cfg.Scheduler.NightlyCleanup != "none" {
+		result.ScheduledJobs = []ScheduledJob{{
+			Schedule: cfg.Scheduler.NightlyCleanup,
+			Job:      worker.Schedule("nightly_cleanup", "tombstones", "idempotency_keys"),
+		}}
+	}
+	return result
+}
+
+func provideExplainCapturer(cfg *config.Config, db *sql.DB, registry *telemetry.Registry, clk clock.Clock, logger *logrus.Logger) *explain.Capturer {
+	if db == nil || cfg.Explain.SlowThreshold <= 0 {
+		return nil
+	}
+	return explain.NewCapturer(db, ratelimit.NewMemoryStore(clk), registry, explain.Config{
+		Threshold:    cfg.Explain.SlowThreshold,
+		SampleRate:   cfg.Explain.SampleRate,
+		MaxPerMinute: cfg.Explain.MaxPerMinute,
+		Timeout:      cfg.Explain.Timeout,
+	}, logger)
+}
+
+func provideDBHealth(cfg *config.Config, db *sql.DB, accessPatterns *indexadvisor.Tracker, clk clock.Clock, logger *logrus.Logger) *handlers.DBHealthHandler {
+	if db == nil {
+		return nil
+	}
+	repo := postgres.NewDBHealthRepository(db)
+	return handlers.NewDBHealthHandler(dbhealth.NewInspector(repo, dbhealth.DefaultThresholds, clk),
+		indexadvisor.NewAdvisor(accessPatterns, repo, cfg.IndexAdvisor.MinUses, clk))
+}
+
+func provideCatalogDiff(db *sql.DB, clk clock.Clock, logger *logrus.Logger) *handlers.CatalogDiffHandler {
+	if db == nil {
+		return nil
+	}
+	catalogDiffUseCase := usecase.NewCatalogDiffUseCase(postgres.NewProductRevisionRepository(db), clk)
+	return handlers.NewCatalogDiffHandler(catalogDiffUseCase)
+}
+
+type storesResult struct {
+	fx.Out
+
+	UseCase *usecase.StoreUseCase
+	Handler *handlers.StoreHandler
+}
+
+func provideStores(db *sql.DB, logger *logrus.Logger) storesResult {
+	if db == nil {
+		return storesResult{}
+	}
+	stores := usecase.NewStoreUseCase(postgres.NewStoreRepository(db))
+	return storesResult{
+		UseCase: stores,
+		Handler: handlers.NewStoreHandler(stores),
+	}
+}
+
+type reconciliationParams struct {
+	fx.In
+
+	Config      *config.Config
+	DB          *sql.DB
+	Sources     []ReconciliationSource `group:"reconciliation_sources"`
+	ProductRepo *cached.ProductRepository
+	Products    *usecase.ProductUseCase
+	Clock       clock.Clock
+	Logger      *logrus.Logger
+}
+
+type reconciliationResult struct {
+	fx.Out
+
+	Handler *handlers.ReconciliationHandler
+	Workers []Worker `group:"workers,flatten"`
+}
+
+// provideReconciliation compares the catalog with whichever sources are
+// set up, on a schedule when an interval is configured.
+func provideReconciliation(p reconciliationParams) reconciliationResult {
+	cfg := p.Config
+	if p.DB == nil || len(p.Sources) == 0 {
+		return reconciliationResult{}
+	}
+	if !domain.IsReconciliationPolicy(cfg.Reconciliation.Policy) {
+		p.Logger.WithField("policy", cfg.Reconciliation.Policy).Fatal("Unsupported reconciliation policy")
+	}
+	sources := make(map[string]usecase.ReferenceSource, len(p.Sources))
+	for _, source := range p.Sources {
+		sources[source.Kind] = source.Source
+	}
+	reconciliationUseCase := usecase.NewReconciliationUseCase(postgres.NewReconciliationRepository(p.DB), p.ProductRepo, p.Products, sources, p.Clock)
+	result := reconciliationResult{Handler: handlers.NewReconciliationHandler(reconciliationUseCase, cfg.Reconciliation.Timeout)}
+	if cfg.Reconciliation.Interval > 0 {
+		scheduler := usecase.NewReconciliationScheduler(reconciliationUseCase, cfg.Reconciliation.Interval, cfg.Reconciliation.Policy, p.Clock)
+		result.Workers = []Worker{{Run: scheduler.Run, PrimaryOnly: true}}
+	}
+	return result
+}
+
+// provideSnapshots stores catalog snapshots in the S3 bucket backups use,
+// and restores them; they are off without a bucket.
+func provideSnapshots(lc fx.Lifecycle, shutdown *Shutdown, cfg *config.Config, db *sql.DB, productRepo *cached.ProductRepository, events usecase.EventPublisher, clk clock.Clock, logger *logrus.Logger) *handlers.CatalogSnapshotHandler {
+	if db == nil || cfg.S3.Bucket == "" {
+		return nil
+	}
+	storage, err := newS3Client(cfg)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid S3 configuration")
+	}
+	repo := cached.NewCatalogSnapshotRepository(postgres.NewCatalogSnapshotRepository(db), productRepo)
+	snapshotUseCase := usecase.NewCatalogSnapshotUseCase(repo, storage, cfg.Snapshot.Prefix, events, cfg.Snapshot.RestoreTimeout, clk)
+	shutdown.WaitOnStop(lc, snapshotUseCase.Wait, "Running catalog restores did not finish before shutdown deadline", logger)
+	return handlers.NewCatalogSnapshotHandler(snapshotUseCase, cfg.Snapshot.Timeout)
+}
+
+// provideBackup backs the database up to S3 on a schedule from the
+// primary region. Passive regions don't build the job, so its settings
+// only need to be valid in the primary region.
+func provideBackup(cfg *config.Config, db *sql.DB, clk clock.Clock, logger *logrus.Logger) workersResult {
+	if !cfg.Backup.Enabled || db == nil || passive(cfg) {
+		return workersResult{}
+	}
+	job, err := newBackupJob(cfg, db, clk, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid backup configuration")
+	}
+	return workersResult{Workers: []Worker{{Run: job.Run, PrimaryOnly: true}}}
+}
+
+// newS3Client connects to the S3 bucket from the S3_* settings.
+func newS3Client(cfg *config.Config) (*s3.Client, error) {
+	return s3.New(s3.Config{
+		Endpoint: cfg.S3.Endpoint,
+		Region:   cfg.S3.Region,
+		Bucket:   cfg.S3.Bucket,
+		Credentials: s3.Credentials{
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+		},
+	}, nil)
+}
+
+// newBackupJob builds the backup job from the BACKUP_* and S3_* settings.
+// The CLI builds its own from the same settings.
+func newBackupJob(cfg *config.Config, db *sql.DB, clk clock.Clock, logger *logrus.Logger) (*backup.Job, error) {
+	key, err := backup.ParseKey(cfg.Backup.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	store, err := newS3Client(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return backup.NewJob(db, store, key, backup.Config{
+		Schema:   "public",
+		Prefix:   cfg.Backup.Prefix,
+		Interval: cfg.Backup.Interval,
+		Verify:   cfg.Backup.Verify,
+	}, clk, logger), nil
+}
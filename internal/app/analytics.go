@@ -0,0 +1,54 @@
+package app
+
+import (
+	"database/sql"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/internal/analytics"
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/repository/cached"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// AnalyticsModule counts storefront events per product and day and
+// scores products' popularity from them.
+var AnalyticsModule = fx.Module("analytics",
+	fx.Provide(provideAnalytics),
+	routesFor(httpDelivery.AnalyticsRoutes),
+)
+
+type analyticsResult struct {
+	fx.Out
+
+	Handler *handlers.AnalyticsHandler
+	Workers []Worker `group:"workers,flatten"`
+}
+
+func provideAnalytics(cfg *config.Config, flags Flags, db *sql.DB, productRepo *cached.ProductRepository, registry *telemetry.Registry, clk clock.Clock, logger *logrus.Logger) analyticsResult {
+	if flags.LoadTest {
+		return analyticsResult{}
+	}
+	repo := postgres.NewAnalyticsRepository(db)
+	recorder := analytics.NewRecorder(repo, registry, analytics.Config{
+		FlushInterval: cfg.Analytics.FlushInterval,
+		MaxPending:    cfg.Analytics.MaxPending,
+	}, clk, logger)
+	popularity := analytics.NewPopularityJob(repo, analytics.PopularityConfig{
+		RefreshInterval: cfg.Analytics.PopularityRefreshInterval,
+		HalfLife:        cfg.Analytics.PopularityHalfLife,
+	}, clk, logger)
+	return analyticsResult{
+		Handler: handlers.NewAnalyticsHandler(usecase.NewAnalyticsUseCase(recorder, repo, productRepo, clk)),
+		Workers: []Worker{
+			{Run: recorder.Run, Unfinished: "Recorded analytics events were not written before shutdown deadline"},
+			{Run: popularity.Run, PrimaryOnly: true},
+		},
+	}
+}
@@ -0,0 +1,87 @@
+// Package app assembles the service with fx. Each file holds the module of
+// one area: its providers build that area's components from the
+// configuration and what other modules provide, and Modules lists them all.
+// cmd/main.go only loads the configuration and runs the application.
+//
+// A new module goes in a file of its own and is appended to Modules.
+// The routes it serves are contributed as an httpDelivery.Module, its
+// background jobs as Workers and the work it does on a cron schedule as
+// ScheduledJobs, so neither the router nor main change.
+// Components that are turned off by the configuration or in load-test mode
+// are provided as nil, as the modules depending on them already expect.
+package app
+
+import (
+	"backend-context-engineering-template/config"
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// Flags are the command-line flags that change how the service is
+// assembled.
+type Flags struct {
+	// LoadTest serves from in-memory repositories with rate limits,
+	// audit and outbound calls disabled, so results measure the service
+	// itself. Only products and trash are served; everything that needs
+	// Postgres or calls out is left out.
+	LoadTest bool
+}
+
+// Modules are the service's modules. Telemetry comes first so it is shut
+// down last, and Server last so the servers stop before the workers.
+var Modules = []fx.Option{
+	TelemetryModule,
+	DatabaseModule,
+	RedisModule,
+	OutboundModule,
+	ProductsModule,
+	EventsModule,
+	AnalyticsModule,
+	AuditModule,
+	FeedsModule,
+	OrdersModule,
+	AuthModule,
+	LimitsModule,
+	AdminModule,
+	GraphQLModule,
+	MeteringModule,
+	StockSummariesModule,
+	JobsModule,
+	SchedulerModule,
+	WorkersModule,
+	ServerModule,
+}
+
+// moduleRoutes is how a provider contributes the routes it serves; it
+// leaves Routes empty when its module is turned off.
+type moduleRoutes struct {
+	fx.Out
+
+	Routes []httpDelivery.Module `group:"routes,flatten"`
+}
+
+// routesFor contributes the routes newModule serves with a handler another
+// provider builds, unless the handler is nil because its feature is off.
+func routesFor[H any](newModule func(handler *H) httpDelivery.Module) fx.Option {
+	return fx.Provide(func(handler *H) moduleRoutes {
+		if handler == nil {
+			return moduleRoutes{}
+		}
+		return moduleRoutes{Routes: []httpDelivery.Module{newModule(handler)}}
+	})
+}
+
+// New assembles the service. Constructors run here, so a configuration
+// error ends the process before anything is started; options are added to
+// the modules, for example to populate what main needs.
+func New(cfg *config.Config, flags Flags, clk clock.Clock, logger *logrus.Logger, options ...fx.Option) *fx.App {
+	return fx.New(
+		fx.Supply(cfg, flags, logger, fx.Annotate(clk, fx.As(new(clock.Clock)))),
+		fx.NopLogger,
+		fx.Options(Modules...),
+		fx.Options(options...),
+	)
+}
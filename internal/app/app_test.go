@@ -0,0 +1,23 @@
+package app
+
+import (
+	"testing"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+)
+
+func TestModules_Validate(t *testing.T) {
+	// Every dependency a module asks for is provided by another one, and
+	// nothing is provided twice.
+	err := fx.ValidateApp(
+		fx.Supply(&config.Config{}, Flags{}, logrus.New(), fx.Annotate(clock.Real(), fx.As(new(clock.Clock)))),
+		fx.NopLogger,
+		fx.Options(Modules...),
+	)
+	require.NoError(t, err)
+}
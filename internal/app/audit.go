@@ -0,0 +1,83 @@
+package app
+
+import (
+	"database/sql"
+	"time"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/internal/audit"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/usecase"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// AuditModule keeps the audit log and idempotency keys in Postgres and
+// ships audit entries to a SIEM when an export sink is configured.
+// Load-test mode keeps neither.
+var AuditModule = fx.Module("audit",
+	fx.Provide(
+		provideAuditRepository,
+		provideAuditRecorder,
+		provideIdempotencyStore,
+		provideAuditExport,
+	),
+)
+
+func provideAuditRepository(db *sql.DB, logger *logrus.Logger) *postgres.AuditRepository {
+	if db == nil {
+		return nil
+	}
+	return postgres.NewAuditRepository(db)
+}
+
+// provideAuditRecorder records the use cases' audit events. A nil
+// *AuditRepository must not become a non-nil interface.
+func provideAuditRecorder(repo *postgres.AuditRepository) usecase.AuditRecorder {
+	if repo == nil {
+		return nil
+	}
+	return repo
+}
+
+func provideIdempotencyStore(db *sql.DB, logger *logrus.Logger) middleware.IdempotencyStore {
+	if db == nil {
+		return nil
+	}
+	return postgres.NewIdempotencyRepository(db)
+}
+
+// provideAuditExport ships audit log entries to the configured sink from
+// the primary region.
+func provideAuditExport(lc fx.Lifecycle, cfg *config.Config, outbound *Outbound, repo *postgres.AuditRepository, logger *logrus.Logger) workersResult {
+	var sink audit.Sink
+	switch cfg.AuditExport.Sink {
+	case "":
+	case "http":
+		sink = audit.NewHTTPSink(outbound.Client(30*time.Second), cfg.AuditExport.URL, cfg.AuditExport.Token)
+	case "syslog":
+		syslogSink := audit.NewSyslogSink(cfg.AuditExport.SyslogNetwork, cfg.AuditExport.SyslogAddr, cfg.App.Name)
+		lc.Append(fx.StopHook(syslogSink.Close))
+		sink = syslogSink
+	case "kafka":
+		if len(cfg.AuditExport.KafkaBrokers) == 0 {
+			logger.Fatal("AUDIT_EXPORT_KAFKA_BROKERS is required for the kafka audit export sink")
+		}
+		kafkaSink := audit.NewKafkaSink(cfg.AuditExport.KafkaBrokers, cfg.AuditExport.KafkaTopic)
+		lc.Append(fx.StopHook(kafkaSink.Close))
+		sink = kafkaSink
+	default:
+		logger.WithField("sink", cfg.AuditExport.Sink).Fatal("Unsupported audit export sink")
+	}
+	if sink == nil || repo == nil {
+		return workersResult{}
+	}
+	exporter := audit.NewExporter(repo, sink, audit.Config{
+		BatchSize:  cfg.AuditExport.BatchSize,
+		Interval:   cfg.AuditExport.Interval,
+		MaxBackoff: cfg.AuditExport.MaxBackoff,
+	}, logger)
+	return workersResult{Workers: []Worker{{Run: exporter.Run, PrimaryOnly: true}}}
+}
@@ -0,0 +1,206 @@
+package app
+
+import (
+	"database/sql"
+	"time"
+
+	"backend-context-engineering-template/config"
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/lockout"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/apikey"
+	"backend-context-engineering-template/pkg/authtoken"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/ratelimit"
+	"backend-context-engineering-template/pkg/secrets"
+	"backend-context-engineering-template/pkg/session"
+	"backend-context-engineering-template/pkg/workload"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// AuthModule provides what callers authenticate with: API keys, sessions
+// with their second factor, users' access tokens and workload identities,
+// along with the secrets store and the login lockout guard.
+var AuthModule = fx.Module("auth",
+	fx.Provide(
+		provideSecretStore,
+		provideLoginGuard,
+		provideTwoFactor,
+		provideSessions,
+		provideAPIKeys,
+		provideUsers,
+		provideWorkloads,
+	),
+	routesFor(httpDelivery.TwoFactorRoutes),
+	routesFor(httpDelivery.SessionRoutes),
+	routesFor(httpDelivery.AuthRoutes),
+	routesFor(httpDelivery.ImpersonationRoutes),
+)
+
+// provideSecretStore keeps secrets sealed with SECRETS_KEY in Postgres.
+// Connectors, two-factor authentication and webhook signing are disabled
+// without it.
+func provideSecretStore(cfg *config.Config, db *sql.DB, logger *logrus.Logger) *secrets.PostgresStore {
+	if db == nil {
+		return nil
+	}
+	if cfg.Secrets.Key == "" {
+		logger.Warn("SECRETS_KEY is not set, connectors, two-factor authentication and webhook signing are disabled")
+		return nil
+	}
+	store, err := secrets.NewPostgresStore(db, cfg.Secrets.Key)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize secrets store")
+	}
+	return store
+}
+
+// provideLoginGuard delays and locks out repeated failed logins, counted
+// across instances with Redis.
+func provideLoginGuard(cfg *config.Config, flags Flags, redisClient *goredis.Client, outbound *Outbound, clk clock.Clock, logger *logrus.Logger) *lockout.Guard {
+	var notifiers []lockout.Notifier
+	if cfg.Lockout.WebhookURL != "" && !flags.LoadTest {
+		notifiers = append(notifiers, lockout.NewWebhookNotifier(outbound.Client(30*time.Second), cfg.Lockout.WebhookURL))
+	}
+	var store lockout.Store = ratelimit.NewMemoryStore(clk)
+	if redisClient != nil {
+		store = ratelimit.NewFallbackStore(ratelimit.NewRedisStore(redisClient, cfg.App.Name+":login:", clk), ratelimit.NewMemoryStore(clk))
+	}
+	return lockout.NewGuard(store, lockout.Config{
+		MaxFailures:     cfg.Lockout.MaxFailures,
+		MaxIPFailures:   cfg.Lockout.MaxIPFailures,
+		Window:          cfg.Lockout.Window,
+		LockoutDuration: cfg.Lockout.Duration,
+		BaseDelay:       cfg.Lockout.BaseDelay,
+		MaxDelay:        cfg.Lockout.MaxDelay,
+	}, notifiers, clk, logger)
+}
+
+type twoFactorResult struct {
+	fx.Out
+
+	UseCase usecase.TwoFactorUseCaseInterface
+	Handler *handlers.TwoFactorHandler
+}
+
+// provideTwoFactor asks enrolled identities for a TOTP code, whose secrets
+// are kept in the secrets store.
+func provideTwoFactor(cfg *config.Config, db *sql.DB, secretStore *secrets.PostgresStore, guard *lockout.Guard, clk clock.Clock, logger *logrus.Logger) twoFactorResult {
+	if secretStore == nil {
+		return twoFactorResult{}
+	}
+	policies, err := domain.ParseTwoFactorPolicies(cfg.TwoFactor.Policy, cfg.TwoFactor.RolePolicies)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid two-factor policy")
+	}
+	twoFactorUseCase := usecase.NewTwoFactorUseCase(postgres.NewTwoFactorRepository(db), secretStore, cfg.App.Name, policies, clk)
+	return twoFactorResult{
+		UseCase: twoFactorUseCase,
+		Handler: handlers.NewTwoFactorHandler(twoFactorUseCase, guard),
+	}
+}
+
+type sessionsResult struct {
+	fx.Out
+
+	Manager *session.Manager
+	Cookie  session.CookieConfig
+	Handler *handlers.SessionHandler
+}
+
+// provideSessions keeps the cookie sessions of browser admin UIs, shared
+// through Redis when it is configured.
+func provideSessions(cfg *config.Config, redisClient *goredis.Client, twoFactor usecase.TwoFactorUseCaseInterface, guard *lockout.Guard, clk clock.Clock, logger *logrus.Logger) sessionsResult {
+	var store session.Store = session.NewMemoryStore(clk)
+	if redisClient != nil {
+		store = session.NewFallbackStore(session.NewRedisStore(redisClient, cfg.App.Name+":", clk), store)
+	}
+	manager := session.NewManager(store, session.Config{
+		TTL:         cfg.Session.TTL,
+		IdleTimeout: cfg.Session.IdleTimeout,
+	}, clk)
+	cookie := session.CookieConfig{
+		Name:     cfg.Session.CookieName,
+		Domain:   cfg.Session.CookieDomain,
+		Secure:   cfg.Session.CookieSecure,
+		SameSite: session.ParseSameSite(cfg.Session.CookieSameSite),
+	}
+	return sessionsResult{
+		Manager: manager,
+		Cookie:  cookie,
+		Handler: handlers.NewSessionHandler(manager, cookie, twoFactor, guard),
+	}
+}
+
+// provideAPIKeys looks up API keys in Postgres. Load-test mode has no
+// database to issue keys from, so every X-API-Key is rejected there.
+func provideAPIKeys(db *sql.DB) apikey.Store {
+	if db == nil {
+		return apikey.NewMemoryStore()
+	}
+	return apikey.NewPostgresStore(db)
+}
+
+type usersResult struct {
+	fx.Out
+
+	Issuer               *authtoken.Issuer
+	Handler              *handlers.AuthHandler
+	ImpersonationHandler *handlers.ImpersonationHandler
+}
+
+// provideUsers issues users' access tokens. Users need a signing secret,
+// and the database to register them in. Enrolled users log in with a
+// two-factor code.
+func provideUsers(cfg *config.Config, db *sql.DB, twoFactor usecase.TwoFactorUseCaseInterface, guard *lockout.Guard, audit usecase.AuditRecorder, clk clock.Clock, logger *logrus.Logger) usersResult {
+	if cfg.Auth.JWTSecret == "" {
+		return usersResult{}
+	}
+	if len(cfg.Auth.JWTSecret) < 32 {
+		logger.Fatal("AUTH_JWT_SECRET must be at least 32 bytes")
+	}
+	issuer := authtoken.NewIssuer([]byte(cfg.Auth.JWTSecret), cfg.Auth.JWTIssuer, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL, clk)
+	if cfg.Auth.ImpersonationTTL <= 0 {
+		logger.Fatal("AUTH_IMPERSONATION_TTL must be positive")
+	}
+	result := usersResult{Issuer: issuer}
+	if db != nil {
+		userRepo := postgres.NewUserRepository(db)
+		result.Handler = handlers.NewAuthHandler(usecase.NewAuthUseCase(userRepo, issuer, twoFactor), guard)
+		impersonationUseCase := usecase.NewImpersonationUseCase(userRepo, issuer, audit, cfg.Auth.ImpersonationTTL, clk)
+		result.ImpersonationHandler = handlers.NewImpersonationHandler(impersonationUseCase)
+	}
+	return result
+}
+
+type workloadsResult struct {
+	fx.Out
+
+	Verifier *workload.Verifier
+	Roles    workload.Roles
+}
+
+// provideWorkloads verifies the identity tokens of internal services and
+// maps them to service roles.
+func provideWorkloads(cfg *config.Config, outbound *Outbound, logger *logrus.Logger) workloadsResult {
+	var result workloadsResult
+	if len(cfg.Workload.Issuers) > 0 {
+		issuers, err := workload.ParseIssuers(cfg.Workload.Issuers, outbound.Client(30*time.Second), cfg.Workload.JWKSRefresh)
+		if err != nil {
+			logger.WithError(err).Fatal("Invalid WORKLOAD_ISSUERS")
+		}
+		result.Verifier = workload.NewVerifier(issuers, cfg.Workload.Audience)
+	}
+	roles, err := workload.ParseRoles(cfg.Workload.Roles)
+	if err != nil {
+		logger.WithError(err).Fatal("Invalid WORKLOAD_ROLES")
+	}
+	result.Roles = roles
+	return result
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+
+	"backend-context-engineering-template/config"
+	sqlmigrations "backend-context-engineering-template/migrations"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/health"
+	"backend-context-engineering-template/pkg/migrations"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// DatabaseModule connects to Postgres and provides the transaction
+// manager. Load-test mode needs no database, so there *sql.DB is nil.
+var DatabaseModule = fx.Module("database",
+	fx.Provide(
+		provideDatabase,
+		provideTxManager,
+	),
+)
+
+type databaseResult struct {
+	fx.Out
+
+	DB     *sql.DB
+	Checks []health.Check `group:"health_checks,flatten"`
+}
+
+func provideDatabase(lc fx.Lifecycle, cfg *config.Config, flags Flags, clk clock.Clock, logger *logrus.Logger) databaseResult {
+	if flags.LoadTest {
+		logger.Warn("Load-test mode: data is in memory and rate limits, audit, feeds, connectors and two-factor authentication are disabled")
+		return databaseResult{}
+	}
+
+	db, failover, err := database.NewPostgresFailoverConnection(database.Config{
+		Host:               cfg.DB.Host,
+		Port:               cfg.DB.Port,
+		User:               cfg.DB.User,
+		Password:           cfg.DB.Password,
+		Name:               cfg.DB.Name,
+		SSLMode:            cfg.DB.SSLMode,
+		TargetSessionAttrs: cfg.DB.TargetSessionAttrs,
+	}, clk, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to connect to database")
+	}
+	lc.Append(fx.StopHook(func() {
+		if err := db.Close(); err != nil {
+			logger.WithError(err).Error("Failed to close database connection")
+		}
+	}))
+
+	if cfg.DB.AutoMigrate {
+		migrator, err := migrations.New(db, sqlmigrations.Files, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to load migrations")
+		}
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to apply migrations")
+		}
+		logger.WithFields(logrus.Fields{
+			"applied": applied,
+			"version": migrator.Latest(),
+		}).Info("Database schema is up to date")
+	}
+
+	// A failover under way only degrades readiness, as reads are retried
+	// through it.
+	return databaseResult{
+		DB: db,
+		Checks: []health.Check{{Name: "postgres", Ping: func(ctx context.Context) error {
+			err := db.PingContext(ctx)
+			if err != nil && failover.FailingOver(cfg.DB.FailoverWindow) {
+				return health.Degraded(err)
+			}
+			return err
+		}}},
+	}
+}
+
+// provideTxManager runs writes in Postgres transactions. Load-test mode
+// has no database, so its writes are not transactional.
+func provideTxManager(db *sql.DB) database.TxManager {
+	if db == nil {
+		return database.NopTxManager{}
+	}
+	return database.NewTxManager(db)
+}
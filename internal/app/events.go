@@ -0,0 +1,312 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/internal/cdn"
+	"backend-context-engineering-template/internal/changestream"
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/digest"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/events"
+	"backend-context-engineering-template/internal/outbox"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/internal/webhooks"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/mailer"
+	"backend-context-engineering-template/pkg/secrets"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// EventsModule publishes product events: they are written to the outbox
+// with the change that raised them, and the relay hands them to Kafka and
+// to the sinks other modules contribute to the "event_sinks" group.
+// Load-test mode goes without product events, so there the
+// usecase.EventPublisher is nil.
+var EventsModule = fx.Module("events",
+	fx.Provide(
+		provideEventSchemas,
+		handlers.NewEventSchemaHandler,
+		provideEventEncoding,
+		provideWebhooks,
+		provideDigest,
+		provideCDN,
+		provideProductStream,
+		provideOutbox,
+	),
+	routesFor(httpDelivery.EventSchemaRoutes),
+	routesFor(httpDelivery.WebhookRoutes),
+	routesFor(httpDelivery.WebhookSecretRoutes),
+	routesFor(httpDelivery.DigestRoutes),
+	routesFor(httpDelivery.ProductStreamRoutes),
+)
+
+func provideEventSchemas(logger *logrus.Logger) *events.Registry {
+	schemas, err := events.NewRegistry()
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load event schemas")
+	}
+	return schemas
+}
+
+func provideEventEncoding(cfg *config.Config, logger *logrus.Logger) events.Encoding {
+	encoding := events.Encoding{Format: cfg.Events.Format, Mode: cfg.Events.CloudEventsMode, Source: cfg.Events.Source}
+	if err := encoding.Validate(); err != nil {
+		logger.WithError(err).Fatal("Invalid event encoding")
+	}
+	return encoding
+}
+
+type webhooksResult struct {
+	fx.Out
+
+	Handler       *handlers.WebhookHandler
+	SecretHandler *handlers.WebhookSecretHandler
+	Sinks         []events.Sink `group:"event_sinks,flatten"`
+	Workers       []Worker      `group:"workers,flatten"`
+}
+
+// provideWebhooks delivers product events to the stores' webhook
+// subscriptions, signed once a store has rotated in a secret.
+func provideWebhooks(cfg *config.Config, flags Flags, db *sql.DB, secretStore *secrets.PostgresStore, outbound *Outbound, registry *telemetry.Registry, encoding events.Encoding, clk clock.Clock, logger *logrus.Logger) webhooksResult {
+	if flags.LoadTest {
+		return webhooksResult{}
+	}
+	var result webhooksResult
+	repo := postgres.NewWebhookRepository(db)
+	var notifiers []webhooks.Notifier
+	if cfg.Webhooks.PauseNotifyURL != "" {
+		notifiers = append(notifiers, webhooks.NewWebhookNotifier(outbound.Client(30*time.Second), cfg.Webhooks.PauseNotifyURL))
+	}
+	var webhookSecrets webhooks.SecretStore
+	if secretStore != nil {
+		webhookSecrets = secretStore
+		result.SecretHandler = handlers.NewWebhookSecretHandler(usecase.NewWebhookSecretUseCase(secretStore, cfg.Webhooks.SecretGrace, clk))
+	}
+	dispatcher := webhooks.NewDispatcher(repo, outbound.UserURLClient(cfg.Webhooks.Timeout), webhookSecrets, notifiers, registry, webhooks.Config{
+		Window:       cfg.Webhooks.BatchWindow,
+		MaxBatchSize: cfg.Webhooks.MaxBatchSize,
+		MaxPending:   cfg.Webhooks.MaxPending,
+		MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		RetryBackoff: cfg.Webhooks.RetryBackoff,
+		PauseAfter:   cfg.Webhooks.PauseAfter,
+		Encoding:     encoding,
+	}, clk, logger)
+	result.Handler = handlers.NewWebhookHandler(usecase.NewWebhookUseCase(repo, dispatcher, clk))
+	result.Sinks = []events.Sink{dispatcher}
+	result.Workers = []Worker{{Run: dispatcher.Run, Unfinished: "Queued webhook events were not delivered before shutdown deadline"}}
+	return result
+}
+
+type digestResult struct {
+	fx.Out
+
+	Handler *handlers.DigestHandler
+	Sinks   []events.Sink `group:"event_sinks,flatten"`
+	Workers []Worker      `group:"workers,flatten"`
+}
+
+// provideDigest records catalog changes and sends subscribed stores a
+// daily summary of them. Webhook digests are signed like webhook deliveries.
+func provideDigest(cfg *config.Config, flags Flags, db *sql.DB, secretStore *secrets.PostgresStore, outbound *Outbound, registry *telemetry.Registry, clk clock.Clock, logger *logrus.Logger) digestResult {
+	if flags.LoadTest {
+		return digestResult{}
+	}
+	repo := postgres.NewDigestRepository(db)
+	digestConfig := digest.Config{
+		Interval:      cfg.Digest.Interval,
+		SendAfter:     cfg.Digest.SendAfter,
+		FlushInterval: cfg.Digest.FlushInterval,
+		MaxPending:    cfg.Digest.MaxPending,
+	}
+	recorder := digest.NewRecorder(repo, registry, digestConfig, clk, logger)
+	renderer, err := digest.NewRenderer(cfg.Digest.Template)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to load digest template")
+	}
+	var digestSecrets webhooks.SecretStore
+	if secretStore != nil {
+		digestSecrets = secretStore
+	}
+	senders := map[string]digest.Sender{
+		domain.DigestChannelWebhook: digest.NewWebhookSender(outbound.UserURLClient(30*time.Second), digestSecrets, clk),
+	}
+	if cfg.SMTP.Addr != "" {
+		smtpMailer, err := mailer.New(mailer.Config{
+			Addr:     cfg.SMTP.Addr,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		})
+		if err != nil {
+			logger.WithError(err).Fatal("Invalid SMTP configuration")
+		}
+		senders[domain.DigestChannelEmail] = digest.NewEmailSender(smtpMailer)
+	}
+	job := digest.NewJob(repo, recorder, senders, renderer, registry, digestConfig, clk, logger)
+	return digestResult{
+		Handler: handlers.NewDigestHandler(usecase.NewDigestUseCase(repo)),
+		Sinks:   []events.Sink{recorder},
+		Workers: []Worker{
+			{Run: recorder.Run, Unfinished: "Recorded catalog changes were not written before shutdown deadline"},
+			{Run: job.Run, PrimaryOnly: true},
+		},
+	}
+}
+
+type cdnResult struct {
+	fx.Out
+
+	Purger             *cdn.Purger
+	SurrogateKeyHeader middleware.SurrogateKeyHeader
+	Sinks              []events.Sink `group:"event_sinks,flatten"`
+	Workers            []Worker      `group:"workers,flatten"`
+}
+
+// provideCDN purges changed products from the CDN. Cached catalog reads
+// are only safe while changes purge them, which needs the product events
+// load-test mode goes without.
+func provideCDN(cfg *config.Config, flags Flags, outbound *Outbound, registry *telemetry.Registry, clk clock.Clock, logger *logrus.Logger) cdnResult {
+	if !cfg.CDN.Enabled || flags.LoadTest {
+		return cdnResult{}
+	}
+	var result cdnResult
+	var client cdn.Client
+	switch cfg.CDN.Provider {
+	case "fastly":
+		if cfg.CDN.FastlyServiceID == "" || cfg.CDN.FastlyAPIToken == "" {
+			logger.Fatal("The fastly CDN provider requires CDN_FASTLY_SERVICE_ID and CDN_FASTLY_API_TOKEN")
+		}
+		client = cdn.NewFastlyClient(outbound.Client(30*time.Second), cfg.CDN.FastlyAPIURL, cfg.CDN.FastlyServiceID, cfg.CDN.FastlyAPIToken)
+		result.SurrogateKeyHeader = middleware.FastlySurrogateKeys
+	case "cloudflare":
+		if cfg.CDN.CloudflareZoneID == "" || cfg.CDN.CloudflareAPIToken == "" {
+			logger.Fatal("The cloudflare CDN provider requires CDN_CLOUDFLARE_ZONE_ID and CDN_CLOUDFLARE_API_TOKEN")
+		}
+		client = cdn.NewCloudflareClient(outbound.Client(30*time.Second), cfg.CDN.CloudflareAPIURL, cfg.CDN.CloudflareZoneID, cfg.CDN.CloudflareAPIToken)
+		result.SurrogateKeyHeader = middleware.CloudflareCacheTags
+	default:
+		logger.WithField("provider", cfg.CDN.Provider).Fatal("Unsupported CDN provider")
+	}
+	result.Purger = cdn.NewPurger(client, registry, cdn.Config{
+		Window:       cfg.CDN.PurgeWindow,
+		MaxPending:   cfg.CDN.MaxPendingPurges,
+		MaxAttempts:  cfg.CDN.PurgeMaxAttempts,
+		RetryBackoff: cfg.CDN.PurgeRetryBackoff,
+	}, clk, logger)
+	result.Sinks = []events.Sink{result.Purger}
+	result.Workers = []Worker{{Run: result.Purger.Run, Unfinished: "Queued CDN purges were not sent before shutdown deadline"}}
+	return result
+}
+
+type productStreamResult struct {
+	fx.Out
+
+	Handler *handlers.ProductStreamHandler
+	Closers []StreamCloser `group:"stream_closers,flatten"`
+	Sinks   []events.Sink  `group:"event_sinks,flatten"`
+	Workers []Worker       `group:"workers,flatten"`
+}
+
+// provideProductStream streams product changes to the clients of
+// /api/v1/products/stream. With Redis, events are broadcast to every
+// instance's clients; without it, only this instance's clients see the
+// events its relay publishes, so it takes a single instance.
+func provideProductStream(cfg *config.Config, flags Flags, redisClient *goredis.Client, registry *telemetry.Registry, clk clock.Clock, logger *logrus.Logger) productStreamResult {
+	if flags.LoadTest {
+		return productStreamResult{}
+	}
+	if cfg.ProductStream.Buffer < 1 || cfg.ProductStream.MaxSubscribers < 1 {
+		logger.Fatal("PRODUCT_STREAM_BUFFER and PRODUCT_STREAM_MAX_SUBSCRIBERS must be at least 1")
+	}
+	if cfg.ProductStream.Heartbeat <= 0 {
+		logger.Fatal("PRODUCT_STREAM_HEARTBEAT must be positive")
+	}
+	hub := changestream.NewHub(registry, changestream.Config{
+		Buffer:         cfg.ProductStream.Buffer,
+		MaxSubscribers: cfg.ProductStream.MaxSubscribers,
+	})
+	result := productStreamResult{
+		Handler: handlers.NewProductStreamHandler(hub, cfg.ProductStream.Heartbeat, clk),
+		Closers: []StreamCloser{hub.Close},
+		Sinks:   []events.Sink{hub},
+	}
+	if redisClient != nil {
+		broadcaster := changestream.NewRedisBroadcaster(redisClient, cfg.App.Name+":product-stream", logger)
+		result.Sinks = []events.Sink{broadcaster}
+		result.Workers = []Worker{{Run: func(ctx context.Context) {
+			ticker := clk.NewTicker(cfg.Redis.ProbeInterval)
+			defer ticker.Stop()
+			for {
+				err := broadcaster.Subscribe(ctx, hub)
+				if ctx.Err() != nil {
+					return
+				}
+				logger.WithError(err).Warn("Product changes from other instances are not streamed, retrying")
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C():
+				}
+			}
+		}}}
+	}
+	return result
+}
+
+type outboxParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Config    *config.Config
+	Flags     Flags
+	DB        *sql.DB
+	Schemas   *events.Registry
+	Encoding  events.Encoding
+	Sinks     []events.Sink `group:"event_sinks"`
+	Registry  *telemetry.Registry
+	Clock     clock.Clock
+	Logger    *logrus.Logger
+}
+
+type outboxResult struct {
+	fx.Out
+
+	Publisher usecase.EventPublisher
+	Workers   []Worker `group:"workers,flatten"`
+}
+
+// provideOutbox validates product events and writes them to the outbox.
+// The relay publishes them to Kafka, when brokers are configured, and then
+// to the sinks.
+func provideOutbox(p outboxParams) outboxResult {
+	if p.Flags.LoadTest {
+		return outboxResult{}
+	}
+	cfg := p.Config
+	var broker outbox.Broker
+	if len(cfg.Events.KafkaBrokers) > 0 {
+		producer := events.NewKafkaProducer(cfg.Events.KafkaBrokers, cfg.Events.KafkaTopic, p.Registry, p.Encoding, p.Logger)
+		p.Lifecycle.Append(fx.StopHook(producer.Close))
+		broker = producer
+	}
+	store := postgres.NewOutboxRepository(p.DB)
+	relay := outbox.NewRelay(store, broker, events.Sinks(p.Sinks), p.Registry, outbox.Config{
+		PollInterval: cfg.Events.OutboxPollInterval,
+		BatchSize:    cfg.Events.OutboxBatchSize,
+	}, p.Clock, p.Logger)
+	return outboxResult{
+		Publisher: events.NewValidatingPublisher(p.Schemas, outbox.NewWriter(store, p.Registry, p.Logger), p.Registry, p.Logger),
+		Workers:   []Worker{{Run: relay.Run, PrimaryOnly: true, Unfinished: "Outbox relay did not stop before shutdown deadline"}},
+	}
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"database/sql"
+	"time"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/internal/connectors"
+	"backend-context-engineering-template/internal/connectors/shopify"
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/repository/cached"
+	"backend-context-engineering-template/internal/repository/feed"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/secrets"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// FeedsModule imports products from feeds on a schedule, mapping their
+// columns with the stores' import mappings and storing their images, and
+// syncs them from connected platforms. Feeds are left out in load-test
+// mode, which has no database, and connectors without the secrets store.
+var FeedsModule = fx.Module("feeds",
+	fx.Provide(
+		provideFeeds,
+		provideConnectors,
+	),
+	routesFor(httpDelivery.FeedRoutes),
+	routesFor(httpDelivery.ImportMappingRoutes),
+	routesFor(httpDelivery.ImageRoutes),
+	routesFor(httpDelivery.ConnectorRoutes),
+)
+
+type feedsResult struct {
+	fx.Out
+
+	Handler              *handlers.FeedHandler
+	ImportMappingHandler *handlers.ImportMappingHandler
+	ImageHandler         *handlers.ImageHandler
+	Sources              []ReconciliationSource `group:"reconciliation_sources,flatten"`
+	Workers              []Worker               `group:"workers,flatten"`
+}
+
+type feedsParams struct {
+	fx.In
+
+	Lifecycle   fx.Lifecycle
+	Shutdown    *Shutdown
+	Config      *config.Config
+	DB          *sql.DB
+	Outbound    *Outbound
+	ProductRepo *cached.ProductRepository
+	Products    *usecase.ProductUseCase
+	Clock       clock.Clock
+	Logger      *logrus.Logger
+}
+
+func provideFeeds(p feedsParams) feedsResult {
+	if p.DB == nil {
+		return feedsResult{}
+	}
+	cfg, db, clk, logger := p.Config, p.DB, p.Clock, p.Logger
+	var result feedsResult
+
+	importMappingRepo := postgres.NewImportMappingRepository(db)
+	result.ImportMappingHandler = handlers.NewImportMappingHandler(usecase.NewImportMappingUseCase(importMappingRepo))
+
+	// Feed images are kept in the S3 bucket backups use.
+	var images usecase.FeedImageImporter
+	if cfg.S3.Bucket != "" {
+		storage, err := newS3Client(cfg)
+		if err != nil {
+			logger.WithError(err).Fatal("Invalid S3 configuration")
+		}
+		downloader := feed.NewImageDownloader(p.Outbound.UserURLClient(cfg.Image.FetchTimeout), cfg.Image.MaxBytes)
+		imageUseCase := usecase.NewImageUseCase(postgres.NewImageRepository(db), p.ProductRepo, storage, downloader, cfg.Image.Prefix, cfg.Image.ImportTimeout, clk)
+		p.Shutdown.WaitOnStop(p.Lifecycle, imageUseCase.Wait, "Queued image imports did not finish before shutdown deadline", logger)
+		result.ImageHandler = handlers.NewImageHandler(imageUseCase)
+		images = imageUseCase
+	}
+
+	feedRepo := postgres.NewFeedRepository(db)
+	fetcher := feed.NewHTTPFetcher(p.Outbound.UserURLClient(cfg.Feed.FetchTimeout), importMappingRepo, cfg.Feed.MaxBytes)
+	feedUseCase := usecase.NewFeedUseCase(feedRepo, p.ProductRepo, p.Products, fetcher, images, cfg.Feed.MaxShrink, clk)
+	result.Handler = handlers.NewFeedHandler(feedUseCase, cfg.Feed.FetchTimeout+30*time.Second)
+	result.Sources = []ReconciliationSource{{Kind: domain.ReconciliationSourceFeed, Source: usecase.NewFeedReference(feedRepo, fetcher, clk)}}
+
+	scheduler := usecase.NewFeedScheduler(feedUseCase, cfg.Feed.SchedulerInterval, clk)
+	result.Workers = []Worker{{Run: scheduler.Run, PrimaryOnly: true, Unfinished: "Feed scheduler did not stop before shutdown deadline"}}
+	return result
+}
+
+type connectorsResult struct {
+	fx.Out
+
+	Handler *handlers.ConnectorHandler
+	Sources []ReconciliationSource `group:"reconciliation_sources,flatten"`
+}
+
+// provideConnectors syncs products from the platforms stores connect,
+// whose credentials are kept in the secrets store.
+func provideConnectors(cfg *config.Config, db *sql.DB, secretStore *secrets.PostgresStore, outbound *Outbound, productRepo *cached.ProductRepository, products *usecase.ProductUseCase, clk clock.Clock, logger *logrus.Logger) connectorsResult {
+	if secretStore == nil {
+		return connectorsResult{}
+	}
+	registry := connectors.NewRegistry(outbound.UserURLClient(cfg.Connector.RequestTimeout))
+	registry.Register(domain.ConnectorKindShopify, shopify.New)
+
+	repo := postgres.NewConnectorRepository(db)
+	connectorUseCase := usecase.NewConnectorUseCase(repo, productRepo, products, secretStore, registry, clk)
+	return connectorsResult{
+		Handler: handlers.NewConnectorHandler(connectorUseCase, cfg.Connector.SyncTimeout),
+		Sources: []ReconciliationSource{{Kind: domain.ReconciliationSourceConnector, Source: usecase.NewConnectorReference(repo, secretStore, registry, clk)}},
+	}
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/internal/delivery/graphql"
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+
+	"go.uber.org/fx"
+)
+
+// GraphQLModule serves products and stores over GraphQL at /graphql,
+// behind the same middleware as the REST API.
+var GraphQLModule = fx.Module("graphql",
+	fx.Provide(provideGraphQL),
+	routesFor(httpDelivery.GraphQLRoutes),
+)
+
+// provideGraphQL needs stores, so it is off without Postgres.
+func provideGraphQL(cfg *config.Config, products *usecase.ProductUseCase, stores *usecase.StoreUseCase, clk clock.Clock) *handlers.GraphQLHandler {
+	if stores == nil {
+		return nil
+	}
+	return handlers.NewGraphQLHandler(graphql.NewSchema(products, stores, clk), cfg.Auth.ProtectProducts)
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/internal/worker"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// JobsModule runs background jobs. Modules queue them on the
+// worker.Queue and contribute a JobHandler for each type they queue.
+var JobsModule = fx.Module("jobs",
+	fx.Provide(
+		provideJobQueue,
+		provideJobPool,
+	),
+)
+
+// JobHandler runs the jobs of Type. Modules contribute them to the
+// "job_handlers" group.
+type JobHandler struct {
+	Type   string
+	Handle worker.Handler
+}
+
+// provideJobQueue keeps jobs in Redis when it is configured, so every
+// instance takes them from one queue, or in this process otherwise.
+func provideJobQueue(cfg *config.Config, flags Flags, redisClient *goredis.Client, clk clock.Clock, logger *logrus.Logger) worker.Queue {
+	queue := cfg.Jobs.Queue
+	if queue == "" || flags.LoadTest {
+		queue = "memory"
+		if redisClient != nil {
+			queue = "redis"
+		}
+	}
+	switch queue {
+	case "memory":
+		return worker.NewMemoryQueue(cfg.Jobs.MaxPending, clk)
+	case "redis":
+		if redisClient == nil {
+			logger.Fatal("REDIS_ADDR is required for the redis job queue")
+		}
+		return worker.NewRedisQueue(redisClient, cfg.App.Name+":", clk)
+	default:
+		logger.WithField("queue", queue).Fatal("Unsupported job queue")
+		return nil
+	}
+}
+
+type jobPoolParams struct {
+	fx.In
+
+	Config   *config.Config
+	Queue    worker.Queue
+	Handlers []JobHandler `group:"job_handlers"`
+	Registry *telemetry.Registry
+	Clock    clock.Clock
+	Logger   *logrus.Logger
+}
+
+// provideJobPool runs the queued jobs from the primary region, as they
+// write to the database. There is no pool while no module queues jobs.
+func provideJobPool(p jobPoolParams) workersResult {
+	cfg, logger := p.Config, p.Logger
+	if len(p.Handlers) == 0 {
+		return workersResult{}
+	}
+	if cfg.Jobs.Concurrency < 1 {
+		logger.Fatal("JOBS_CONCURRENCY must be at least 1")
+	}
+	if cfg.Jobs.PollInterval <= 0 || cfg.Jobs.Lease <= 0 {
+		logger.Fatal("JOBS_POLL_INTERVAL and JOBS_LEASE must be positive")
+	}
+	handlers := make(map[string]worker.Handler, len(p.Handlers))
+	for _, handler := range p.Handlers {
+		if _, ok := handlers[handler.Type]; ok {
+			logger.WithField("type", handler.Type).Fatal("Job type has more than one handler")
+		}
+		handlers[handler.Type] = handler.Handle
+	}
+	pool := worker.NewPool(p.Queue, handlers, p.Registry, worker.Config{
+		Concurrency:  cfg.Jobs.Concurrency,
+		PollInterval: cfg.Jobs.PollInterval,
+		Lease:        cfg.Jobs.Lease,
+		MaxAttempts:  cfg.Jobs.MaxAttempts,
+		RetryBackoff: cfg.Jobs.RetryBackoff,
+	}, p.Clock, logger)
+	return workersResult{Workers: []Worker{{Run: pool.Run, PrimaryOnly: true, Unfinished: "Running jobs did not finish before shutdown deadline"}}}
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+
+	"backend-context-engineering-template/config"
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/ratelimit"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// LimitsModule provides the rate and cost limits, shared through Redis
+// when it is configured, and the live configuration that adjusts them and
+// switches maintenance mode while the service runs.
+var LimitsModule = fx.Module("limits",
+	fx.Provide(
+		provideRateLimiter,
+		provideCostLimiter,
+		provideLiveConfig,
+	),
+	routesFor(httpDelivery.LiveConfigRoutes),
+)
+
+func provideRateLimiter(cfg *config.Config, flags Flags, redisClient *goredis.Client, clk clock.Clock, logger *logrus.Logger) *middleware.RateLimiter {
+	if cfg.RateLimit.RPS <= 0 || flags.LoadTest {
+		return nil
+	}
+	if cfg.RateLimit.Burst < 1 {
+		logger.Fatal("RATE_LIMIT_BURST must be at least 1")
+	}
+	var limiter ratelimit.Limiter = ratelimit.NewTokenBucket(cfg.RateLimit.RPS, cfg.RateLimit.Burst, clk)
+	if redisClient != nil {
+		limiter = ratelimit.NewFallbackLimiter(ratelimit.NewRedisSlidingWindow(redisClient, cfg.App.Name+":rate:", cfg.RateLimit.RPS, cfg.RateLimit.Burst, clk), limiter)
+	}
+	return middleware.NewRateLimiter(limiter, logger)
+}
+
+func provideCostLimiter(cfg *config.Config, flags Flags, redisClient *goredis.Client, clk clock.Clock, logger *logrus.Logger) *middleware.CostLimiter {
+	if cfg.RateLimit.Units <= 0 || flags.LoadTest {
+		return nil
+	}
+	var store ratelimit.Store = ratelimit.NewMemoryStore(clk)
+	if redisClient != nil {
+		store = ratelimit.NewFallbackStore(ratelimit.NewRedisStore(redisClient, cfg.App.Name+":cost:", clk), ratelimit.NewMemoryStore(clk))
+	}
+	return middleware.NewCostLimiter(store, cfg.RateLimit.Units, cfg.RateLimit.Window, httpDelivery.RouteCosts, logger)
+}
+
+type liveConfigResult struct {
+	fx.Out
+
+	Handler     *handlers.LiveConfigHandler
+	Maintenance middleware.MaintenanceSource
+	Workers     []Worker `group:"workers,flatten"`
+}
+
+// provideLiveConfig reloads the configuration changed through
+// /admin/config. It is kept in Postgres; without it the configured rate
+// limit applies and maintenance mode cannot be used. Every instance
+// applies it, passive or not.
+func provideLiveConfig(cfg *config.Config, db *sql.DB, audit usecase.AuditRecorder, rateLimiter *middleware.RateLimiter, clk clock.Clock, logger *logrus.Logger) liveConfigResult {
+	if db == nil {
+		return liveConfigResult{}
+	}
+	if cfg.LiveConfig.RefreshInterval <= 0 {
+		logger.Fatal("LIVE_CONFIG_REFRESH_INTERVAL must be positive")
+	}
+	// A nil *RateLimiter must not become a non-nil interface.
+	var rateLimitAdjuster usecase.RateLimitAdjuster
+	if rateLimiter != nil {
+		rateLimitAdjuster = rateLimiter
+	}
+	liveConfig := usecase.NewLiveConfigUseCase(postgres.NewLiveConfigRepository(db), postgres.NewStoreRepository(db),
+		audit, rateLimitAdjuster, domain.RateLimitSettings{RPS: cfg.RateLimit.RPS, Burst: cfg.RateLimit.Burst},
+		cfg.LiveConfig.RefreshInterval, clk)
+	if err := liveConfig.Refresh(context.Background()); err != nil {
+		logger.WithError(err).Warn("Failed to load live config; defaults apply until the next refresh")
+	}
+	return liveConfigResult{
+		Handler:     handlers.NewLiveConfigHandler(liveConfig),
+		Maintenance: liveConfig,
+		Workers:     []Worker{{Run: liveConfig.Run}},
+	}
+}
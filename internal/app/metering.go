@@ -0,0 +1,81 @@
+package app
+
+import (
+	"database/sql"
+	"time"
+
+	"backend-context-engineering-template/config"
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/metering"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// MeteringModule bills stores by usage when a metering sink is
+// configured. Every instance counts the calls it serves; the primary
+// region closes the hours and emits them.
+var MeteringModule = fx.Module("metering",
+	fx.Provide(provideMetering),
+	routesFor(httpDelivery.UsageRoutes),
+)
+
+type meteringResult struct {
+	fx.Out
+
+	Handler  *handlers.UsageHandler
+	Recorder middleware.UsageRecorder
+	Workers  []Worker `group:"workers,flatten"`
+}
+
+func provideMetering(lc fx.Lifecycle, cfg *config.Config, db *sql.DB, outbound *Outbound, registry *telemetry.Registry, clk clock.Clock, logger *logrus.Logger) meteringResult {
+	if cfg.Metering.Sink == "" || db == nil {
+		return meteringResult{}
+	}
+	var sink metering.Sink
+	switch cfg.Metering.Sink {
+	case "http":
+		if cfg.Metering.URL == "" {
+			logger.Fatal("METERING_URL is required for the http metering sink")
+		}
+		sink = metering.NewHTTPSink(outbound.Client(30*time.Second), cfg.Metering.URL, cfg.Metering.Token)
+	case "kafka":
+		if len(cfg.Metering.KafkaBrokers) == 0 {
+			logger.Fatal("METERING_KAFKA_BROKERS is required for the kafka metering sink")
+		}
+		kafkaSink := metering.NewKafkaSink(cfg.Metering.KafkaBrokers, cfg.Metering.KafkaTopic)
+		lc.Append(fx.StopHook(kafkaSink.Close))
+		sink = kafkaSink
+	default:
+		logger.WithField("sink", cfg.Metering.Sink).Fatal("Unsupported metering sink")
+	}
+	if cfg.Metering.Grace <= cfg.Metering.FlushInterval {
+		logger.Fatal("METERING_GRACE must exceed METERING_FLUSH_INTERVAL, or calls written late are not billed")
+	}
+
+	repo := postgres.NewUsageRepository(db)
+	recorder := metering.NewRecorder(repo, registry, metering.RecorderConfig{
+		FlushInterval: cfg.Metering.FlushInterval,
+		MaxPending:    cfg.Metering.MaxPending,
+	}, clk, logger)
+	meter := metering.NewMeter(repo, sink, registry, metering.Config{
+		Interval:  cfg.Metering.Interval,
+		Grace:     cfg.Metering.Grace,
+		BatchSize: cfg.Metering.BatchSize,
+		ClaimTTL:  cfg.Metering.ClaimTTL,
+	}, clk, logger)
+	return meteringResult{
+		Handler:  handlers.NewUsageHandler(usecase.NewUsageUseCase(repo, cfg.Metering.Grace, clk)),
+		Recorder: recorder,
+		Workers: []Worker{
+			{Run: recorder.Run, Unfinished: "Metered API calls were not written before shutdown deadline"},
+			{Run: meter.Run, PrimaryOnly: true},
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"database/sql"
+
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/repository/cached"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// OrdersModule serves pricing policies, bundles and orders, which all
+// need Postgres and are left out in load-test mode.
+var OrdersModule = fx.Module("orders",
+	fx.Provide(
+		providePricing,
+		provideOrders,
+	),
+	routesFor(httpDelivery.PricingRoutes),
+	routesFor(httpDelivery.BundleRoutes),
+	routesFor(httpDelivery.OrderRoutes),
+)
+
+func providePricing(db *sql.DB, productRepo *cached.ProductRepository, logger *logrus.Logger) *handlers.PricingHandler {
+	if db == nil {
+		return nil
+	}
+	pricingUseCase := usecase.NewPricingUseCase(postgres.NewPricingRepository(db), productRepo)
+	return handlers.NewPricingHandler(pricingUseCase)
+}
+
+type ordersResult struct {
+	fx.Out
+
+	BundleHandler *handlers.BundleHandler
+	OrderHandler  *handlers.OrderHandler
+}
+
+// provideOrders serves bundles and the orders that sell products and
+// bundles, both taking stock from the product repository.
+func provideOrders(db *sql.DB, tx database.TxManager, productRepo *cached.ProductRepository, events usecase.EventPublisher, clk clock.Clock, logger *logrus.Logger) ordersResult {
+	if db == nil {
+		return ordersResult{}
+	}
+	bundleRepo := cached.NewBundleRepository(postgres.NewBundleRepository(db), productRepo)
+	bundleUseCase := usecase.NewBundleUseCase(bundleRepo, productRepo, events, clk)
+
+	orderRepo := cached.NewOrderRepository(postgres.NewOrderRepository(db), productRepo)
+	orderUseCase := usecase.NewOrderUseCase(tx, orderRepo, productRepo, bundleRepo, events, clk)
+	return ordersResult{
+		BundleHandler: handlers.NewBundleHandler(bundleUseCase),
+		OrderHandler:  handlers.NewOrderHandler(orderUseCase),
+	}
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/httpclient"
+	"backend-context-engineering-template/pkg/requestid"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// OutboundModule provides the HTTP clients for calls to other services.
+var OutboundModule = fx.Module("outbound",
+	fx.Provide(newOutbound),
+)
+
+// Outbound builds HTTP clients that retry and break circuits as
+// configured, with their calls counted per destination in Metrics.
+type Outbound struct {
+	cfg    *config.Config
+	clock  clock.Clock
+	logger *logrus.Logger
+
+	Metrics *httpclient.Metrics
+}
+
+func newOutbound(cfg *config.Config, clk clock.Clock, logger *logrus.Logger) *Outbound {
+	return &Outbound{cfg: cfg, clock: clk, logger: logger, Metrics: httpclient.NewMetrics()}
+}
+
+// Client returns a client for services the operators configure.
+func (o *Outbound) Client(timeout time.Duration, opts ...httpclient.Option) *http.Client {
+	return httpclient.New(httpclient.Config{
+		Timeout:          timeout,
+		MaxRetries:       o.cfg.Outbound.MaxRetries,
+		BaseBackoff:      o.cfg.Outbound.BaseBackoff,
+		MaxBackoff:       o.cfg.Outbound.MaxBackoff,
+		BreakerThreshold: o.cfg.Outbound.BreakerThreshold,
+		BreakerCooldown:  o.cfg.Outbound.BreakerCooldown,
+	}, o.clock, append([]httpclient.Option{
+		httpclient.WithMetrics(o.Metrics),
+		httpclient.WithLogger(o.logger),
+		httpclient.WithPropagator(requestid.Propagate),
+	}, opts...)...)
+}
+
+// UserURLClient returns a client for URLs users register, such as feeds,
+// connectors and webhook subscriptions, which may only reach public
+// addresses.
+func (o *Outbound) UserURLClient(timeout time.Duration) *http.Client {
+	return o.Client(timeout, httpclient.WithBaseTransport(httpclient.PublicTransport()))
+}
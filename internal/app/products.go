@@ -0,0 +1,378 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"time"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/internal/anomaly"
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/indexadvisor"
+	"backend-context-engineering-template/internal/moderation"
+	"backend-context-engineering-template/internal/repository/cached"
+	"backend-context-engineering-template/internal/repository/memory"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/repository/replicated"
+	"backend-context-engineering-template/internal/repository/retrying"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/cache"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/confirmtoken"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/health"
+	"backend-context-engineering-template/pkg/hotkeys"
+	"backend-context-engineering-template/pkg/idgen"
+	"backend-context-engineering-template/pkg/lifecycle"
+	"backend-context-engineering-template/pkg/previewtoken"
+	"backend-context-engineering-template/pkg/ratelimit"
+	"backend-context-engineering-template/pkg/redis"
+	"backend-context-engineering-template/pkg/replication"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// ProductsModule provides the product repository with its caches, the
+// product use case and handlers, moderation, previews, bulk confirmations,
+// the trash and categories.
+var ProductsModule = fx.Module("products",
+	fx.Provide(
+		indexadvisor.NewTracker,
+		cache.NewStats,
+		provideHotKeys,
+		provideProductRepository,
+		provideModeration,
+		provideMutationDetector,
+		provideProductUseCase,
+		providePreviews,
+		provideProductHandler,
+		providePublishingScheduler,
+		provideBulkGuard,
+		provideTrash,
+		provideCacheHandler,
+		provideCategories,
+	),
+	routesFor(httpDelivery.ProductRoutes),
+	routesFor(httpDelivery.ModerationRoutes),
+	routesFor(httpDelivery.PreviewRoutes),
+	routesFor(httpDelivery.TrashRoutes),
+	routesFor(httpDelivery.CacheRoutes),
+	routesFor(httpDelivery.CategoryRoutes),
+)
+
+type hotKeysResult struct {
+	fx.Out
+
+	Tracker *hotkeys.Tracker
+	Workers []Worker `group:"workers,flatten"`
+}
+
+// provideHotKeys counts product reads, shared between instances through
+// Redis when it is configured.
+func provideHotKeys(cfg *config.Config, redisClient *goredis.Client, clk clock.Clock, logger *logrus.Logger) hotKeysResult {
+	var store hotkeys.Store = hotkeys.NewMemoryStore(cfg.HotKeys.HalfLife, clk)
+	if redisClient != nil {
+		store = hotkeys.NewRedisStore(redisClient, cfg.App.Name+":", cfg.HotKeys.HalfLife)
+	}
+	tracker := hotkeys.NewTracker(cfg.HotKeys.TopK, uint64(cfg.HotKeys.Threshold), store, clk)
+	return hotKeysResult{
+		Tracker: tracker,
+		Workers: []Worker{{Run: func(ctx context.Context) {
+			tracker.Run(ctx, cfg.HotKeys.SyncInterval, func(err error) {
+				// The tracker already reports Redis being down.
+				if !errors.Is(err, redis.ErrUnavailable) {
+					logger.WithError(err).Warn("Failed to sync hot key counts")
+				}
+			})
+		}}},
+	}
+}
+
+// warmUp lists the steps run before the service reports ready.
+type warmUp []lifecycle.Step
+
+type productRepositoryResult struct {
+	fx.Out
+
+	Products        *cached.ProductRepository
+	PublishSchedule usecase.PublishScheduleRepository
+	Trash           usecase.TrashRepository
+	ReplicaMonitor  *replication.Monitor
+	WarmUp          warmUp
+	Checks          []health.Check `group:"health_checks,flatten"`
+	Workers         []Worker       `group:"workers,flatten"`
+}
+
+type productRepositoryParams struct {
+	fx.In
+
+	Lifecycle      fx.Lifecycle
+	Config         *config.Config
+	Flags          Flags
+	DB             *sql.DB
+	Redis          *goredis.Client
+	RedisTracker   *redis.Tracker
+	AccessPatterns *indexadvisor.Tracker
+	HotKeys        *hotkeys.Tracker
+	CacheStats     *cache.Stats
+	Clock          clock.Clock
+	Logger         *logrus.Logger
+}
+
+// provideProductRepository layers the product repository: Postgres, or
+// memory in load-test mode, then the read replica, then the cache, which
+// other instances invalidate through Redis. The hottest products are saved
+// at shutdown and primed into the cache during warm-up.
+func provideProductRepository(p productRepositoryParams) productRepositoryResult {
+	cfg, clk, logger := p.Config, p.Clock, p.Logger
+	var result productRepositoryResult
+
+	var base usecase.ProductRepository
+	if p.Flags.LoadTest {
+		memoryStore := memory.NewStore(clk)
+		memoryProductRepo := memory.NewProductRepository(memoryStore)
+		base, result.PublishSchedule = memoryProductRepo, memoryProductRepo
+		result.Trash = memory.NewTrashRepository(memoryStore)
+	} else {
+		productIDs, err := idgen.New(cfg.DB.IDStrategy, cfg.DB.IDNodeID, clk)
+		if err != nil {
+			if errors.Is(err, idgen.ErrNodeIDRequired) {
+				logger.Fatal("ID_NODE_ID must be set to a node ID unique to this instance when ID_STRATEGY=snowflake")
+			}
+			logger.WithError(err).WithField("strategy", cfg.DB.IDStrategy).Fatal("Unsupported ID strategy")
+		}
+		postgresProductRepo := postgres.NewProductRepository(p.DB, productIDs, p.AccessPatterns)
+		p.Lifecycle.Append(fx.StopHook(postgresProductRepo.Close))
+		result.PublishSchedule = postgresProductRepo
+		// Reads from the primary ride out a failover; the replica's fall
+		// back to the primary instead.
+		primaryProductRepo := retrying.NewProductRepository(postgresProductRepo, cfg.DB.FailoverWindow, clk)
+		base = primaryProductRepo
+		if cfg.DB.ReplicaHost != "" {
+			replicaDB, err := database.NewPostgresConnection(database.Config{
+				Host:     cfg.DB.ReplicaHost,
+				Port:     cfg.DB.ReplicaPort,
+				User:     cfg.DB.User,
+				Password: cfg.DB.Password,
+				Name:     cfg.DB.Name,
+				SSLMode:  cfg.DB.SSLMode,
+			}, logger)
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to connect to read replica")
+			}
+			p.Lifecycle.Append(fx.StopHook(replicaDB.Close))
+			// Reads fall back to the primary, so a lost replica only
+			// degrades readiness.
+			result.Checks = append(result.Checks, health.Check{Name: "postgres_replica", Optional: true, Ping: replicaDB.PingContext})
+
+			result.ReplicaMonitor = replication.NewMonitor(func(ctx context.Context) (time.Duration, error) {
+				return database.ReplicationLag(ctx, replicaDB)
+			}, replication.Config{Interval: cfg.Region.ReplicaLagInterval, MaxLag: cfg.Region.ReplicaMaxLag}, clk, logger)
+			result.Workers = append(result.Workers, Worker{Run: result.ReplicaMonitor.Run})
+			base = replicated.NewProductRepository(primaryProductRepo,
+				postgres.NewProductRepository(replicaDB, nil, p.AccessPatterns), result.ReplicaMonitor,
+				cfg.Region.ReplicaMaxLag+3*cfg.Region.ReplicaLagInterval, clk)
+		}
+		result.Trash = postgres.NewTrashRepository(p.DB, p.AccessPatterns)
+		result.WarmUp = append(result.WarmUp,
+			lifecycle.Step{Name: "database pool", Run: func(ctx context.Context) error {
+				return database.Warm(ctx, p.DB, cfg.Warmup.PoolConns)
+			}},
+			lifecycle.Step{Name: "prepared statements", Run: postgresProductRepo.Prepare},
+		)
+	}
+
+	var invalidator *cache.RedisInvalidator
+	var peers cached.Peers
+	if p.Redis != nil {
+		invalidator = cache.NewRedisInvalidator(p.Redis, cfg.App.Name+":product-invalidations", p.RedisTracker)
+		peers = invalidator
+	}
+	productRepo := cached.NewProductRepository(base,
+		cache.New[int64, *domain.Product](cfg.Cache.Size, cfg.Cache.TTL), p.HotKeys, cfg.HotKeys.TTL, p.CacheStats, peers)
+	result.Products = productRepo
+	if invalidator != nil {
+		result.Workers = append(result.Workers, Worker{Run: func(ctx context.Context) {
+			ticker := clk.NewTicker(cfg.Redis.ProbeInterval)
+			defer ticker.Stop()
+			for {
+				err := invalidator.Subscribe(ctx, productRepo.Evict)
+				if ctx.Err() != nil {
+					return
+				}
+				logger.WithError(err).Warn("Product cache invalidations from other instances are not received, retrying")
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C():
+				}
+			}
+		}})
+	}
+
+	hotKeys := cache.NewHotKeyFile(cfg.Warmup.HotKeysFile)
+	result.WarmUp = append(result.WarmUp, lifecycle.Step{Name: "product cache", Run: func(ctx context.Context) error {
+		ids, err := hotKeys.Load()
+		if err != nil {
+			// The file only hints at what to prime; retrying cannot fix it.
+			logger.WithError(err).Warn("Skipping cache priming")
+			return nil
+		}
+		if len(ids) > cfg.Warmup.HotKeys {
+			ids = ids[:cfg.Warmup.HotKeys]
+		}
+		_, err = productRepo.Prime(ctx, ids)
+		return err
+	}})
+	p.Lifecycle.Append(fx.StopHook(func() {
+		if err := hotKeys.Save(productRepo.HotKeys(cfg.Warmup.HotKeys)); err != nil {
+			logger.WithError(err).Warn("Failed to save hot product keys")
+		}
+	}))
+	return result
+}
+
+type moderationResult struct {
+	fx.Out
+
+	UseCase *usecase.ModerationUseCase
+	Handler *handlers.ModerationHandler
+}
+
+// provideModeration screens products against the blocklist, and has the
+// moderation service review them when one is configured.
+func provideModeration(lc fx.Lifecycle, shutdown *Shutdown, cfg *config.Config, flags Flags, outbound *Outbound, productRepo *cached.ProductRepository, clk clock.Clock, logger *logrus.Logger) moderationResult {
+	var reviewer usecase.ContentModerator
+	if cfg.Moderation.WebhookURL != "" && !flags.LoadTest {
+		reviewer = moderation.NewHTTPModerator(outbound.Client(cfg.Moderation.ReviewTimeout), cfg.Moderation.WebhookURL)
+	}
+	moderationUseCase := usecase.NewModerationUseCase(productRepo,
+		[]usecase.ContentModerator{moderation.NewBlocklist(cfg.Moderation.Blocklist)},
+		reviewer, cfg.Moderation.ReviewTimeout)
+	shutdown.WaitOnStop(lc, moderationUseCase.Wait, "Pending moderation reviews did not finish before shutdown deadline", logger)
+	return moderationResult{
+		UseCase: moderationUseCase,
+		Handler: handlers.NewModerationHandler(moderationUseCase, clk),
+	}
+}
+
+func provideMutationDetector(cfg *config.Config, flags Flags, outbound *Outbound, logger *logrus.Logger) *anomaly.Detector {
+	var alerters []anomaly.Alerter
+	if cfg.Anomaly.WebhookURL != "" && !flags.LoadTest {
+		alerters = append(alerters, anomaly.NewWebhookAlerter(outbound.Client(30*time.Second), cfg.Anomaly.WebhookURL))
+	}
+	return anomaly.NewDetector(anomaly.Config{
+		Window:              cfg.Anomaly.Window,
+		Factor:              float64(cfg.Anomaly.Factor),
+		MinEvents:           cfg.Anomaly.MinEvents,
+		RequireConfirmation: cfg.Anomaly.RequireConfirmation && !flags.LoadTest,
+	}, alerters, logger)
+}
+
+func provideProductUseCase(tx database.TxManager, productRepo *cached.ProductRepository, moderationUseCase *usecase.ModerationUseCase, detector *anomaly.Detector, events usecase.EventPublisher, clk clock.Clock, logger *logrus.Logger) *usecase.ProductUseCase {
+	return usecase.NewProductUseCase(tx, productRepo, moderationUseCase, detector, events, clk)
+}
+
+type previewsResult struct {
+	fx.Out
+
+	UseCase usecase.PreviewUseCaseInterface
+	Handler *handlers.PreviewHandler
+}
+
+// providePreviews signs draft preview links. They need a signing secret
+// shared by every instance and are off without one.
+func providePreviews(cfg *config.Config, productRepo *cached.ProductRepository, clk clock.Clock, logger *logrus.Logger) previewsResult {
+	if cfg.Preview.TokenSecret == "" {
+		return previewsResult{}
+	}
+	if len(cfg.Preview.TokenSecret) < 32 {
+		logger.Fatal("PREVIEW_TOKEN_SECRET must be at least 32 bytes")
+	}
+	signer := previewtoken.NewSigner([]byte(cfg.Preview.TokenSecret), clk)
+	previews := usecase.NewPreviewUseCase(productRepo, signer, cfg.Preview.TokenTTL, cfg.Preview.MaxTokenTTL)
+	return previewsResult{
+		UseCase: previews,
+		Handler: handlers.NewPreviewHandler(previews),
+	}
+}
+
+func provideProductHandler(productUseCase *usecase.ProductUseCase, previews usecase.PreviewUseCaseInterface, clk clock.Clock, logger *logrus.Logger) *handlers.ProductHandler {
+	return handlers.NewProductHandler(productUseCase, previews, clk)
+}
+
+type workersResult struct {
+	fx.Out
+
+	Workers []Worker `group:"workers,flatten"`
+}
+
+// providePublishingScheduler sends events for publishing windows that
+// opened or closed.
+func providePublishingScheduler(cfg *config.Config, schedule usecase.PublishScheduleRepository, productRepo *cached.ProductRepository, events usecase.EventPublisher, clk clock.Clock, logger *logrus.Logger) workersResult {
+	scheduler := usecase.NewPublishingScheduler(schedule, productRepo, events, cfg.Publishing.SchedulerInterval, clk)
+	return workersResult{Workers: []Worker{{Run: scheduler.Run, PrimaryOnly: true}}}
+}
+
+// provideBulkGuard holds back mutations touching many rows until they are
+// confirmed. Confirmations are only honored by other instances when they
+// share the secret, and only once across instances with Redis.
+func provideBulkGuard(cfg *config.Config, redisClient *goredis.Client, audit usecase.AuditRecorder, clk clock.Clock, logger *logrus.Logger) *usecase.BulkGuard {
+	secret := []byte(cfg.Bulk.ConfirmationSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			logger.WithError(err).Fatal("Failed to generate bulk confirmation secret")
+		}
+		logger.Warn("BULK_CONFIRMATION_SECRET is not set; bulk operations can only be confirmed on the instance that held them back")
+	} else if len(secret) < 32 {
+		logger.Fatal("BULK_CONFIRMATION_SECRET must be at least 32 bytes")
+	}
+	var used usecase.UsageCounter = ratelimit.NewMemoryStore(clk)
+	if redisClient != nil {
+		used = ratelimit.NewFallbackStore(ratelimit.NewRedisStore(redisClient, cfg.App.Name+":bulk:", clk), ratelimit.NewMemoryStore(clk))
+	}
+	return usecase.NewBulkGuard(confirmtoken.NewSigner(secret, clk), used, audit,
+		cfg.Bulk.Threshold, cfg.Bulk.ConfirmationTTL, clk)
+}
+
+type trashResult struct {
+	fx.Out
+
+	Handler *handlers.TrashHandler
+	Workers []Worker `group:"workers,flatten"`
+}
+
+// provideTrash serves deleted products until they are purged. With
+// Postgres the retention worker purges the trash along with its other
+// rules; the trash purger only covers the in-memory store.
+func provideTrash(cfg *config.Config, db *sql.DB, trashRepo usecase.TrashRepository, guard *usecase.BulkGuard, clk clock.Clock, logger *logrus.Logger) trashResult {
+	trashUseCase := usecase.NewTrashUseCase(trashRepo, guard, cfg.Trash.Retention, clk)
+	result := trashResult{Handler: handlers.NewTrashHandler(trashUseCase, clk)}
+	if db == nil {
+		purger := usecase.NewTrashPurger(trashUseCase, cfg.Trash.PurgeInterval, clk)
+		result.Workers = []Worker{{Run: purger.Run, PrimaryOnly: true}}
+	}
+	return result
+}
+
+func provideCacheHandler(hotKeys *hotkeys.Tracker, stats *cache.Stats, logger *logrus.Logger) *handlers.CacheHandler {
+	return handlers.NewCacheHandler(hotKeys, stats)
+}
+
+// provideCategories serves the category tree, which is only kept in
+// Postgres. Category changes bump the versions of the products they touch
+// and publish their updated events.
+func provideCategories(db *sql.DB, tx database.TxManager, productRepo *cached.ProductRepository, events usecase.EventPublisher, clk clock.Clock, logger *logrus.Logger) *handlers.CategoryHandler {
+	if db == nil {
+		return nil
+	}
+	categoryRepo := cached.NewCategoryRepository(postgres.NewCategoryRepository(db), productRepo)
+	return handlers.NewCategoryHandler(usecase.NewCategoryUseCase(tx, categoryRepo, events, clk))
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/health"
+	"backend-context-engineering-template/pkg/redis"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// RedisModule connects to Redis when it is configured. Sessions, rate
+// limits, lockouts and cache invalidations are shared through it;
+// otherwise they live in this process only, and *goredis.Client is nil.
+var RedisModule = fx.Module("redis",
+	fx.Provide(provideRedis),
+)
+
+type redisResult struct {
+	fx.Out
+
+	Client  *goredis.Client
+	Tracker *redis.Tracker
+	Checks  []health.Check `group:"health_checks,flatten"`
+	Workers []Worker       `group:"workers,flatten"`
+}
+
+// provideRedis connects to Redis. It is not required to serve: while it is
+// down, what it shares falls back to this process, so it only degrades
+// readiness.
+func provideRedis(lc fx.Lifecycle, cfg *config.Config, flags Flags, registry *telemetry.Registry, clk clock.Clock, logger *logrus.Logger) redisResult {
+	if flags.LoadTest {
+		return redisResult{}
+	}
+	if cfg.Redis.Addr == "" {
+		logger.Warn("REDIS_ADDR is not set, sessions are kept in memory and not shared between instances")
+		return redisResult{}
+	}
+	if cfg.Redis.FailureThreshold < 1 {
+		logger.Fatal("REDIS_FAILURE_THRESHOLD must be at least 1")
+	}
+	if cfg.Redis.ProbeInterval <= 0 {
+		logger.Fatal("REDIS_PROBE_INTERVAL must be positive")
+	}
+
+	client := database.NewRedisClient(database.RedisConfig{
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		DB:       int(cfg.Redis.DB),
+	})
+	lc.Append(fx.StopHook(client.Close))
+	tracker := redis.NewTracker(client, registry, redis.Config{
+		FailureThreshold: int(cfg.Redis.FailureThreshold),
+		ProbeInterval:    cfg.Redis.ProbeInterval,
+	}, clk, logger)
+	probeCtx, cancel := context.WithTimeout(context.Background(), cfg.Redis.ProbeInterval)
+	if err := tracker.Probe(probeCtx); err != nil {
+		logger.WithError(err).WithField("addr", cfg.Redis.Addr).Warn("Redis is unavailable, starting without it")
+	} else {
+		logger.WithFields(logrus.Fields{"addr": cfg.Redis.Addr, "db": cfg.Redis.DB}).Info("Successfully connected to Redis")
+	}
+	cancel()
+
+	return redisResult{
+		Client:  client,
+		Tracker: tracker,
+		Checks:  []health.Check{{Name: "redis", Optional: true, Ping: tracker.Probe}},
+		Workers: []Worker{{Run: tracker.Run}},
+	}
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/scheduler"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// SchedulerModule runs the ScheduledJobs other modules contribute on their
+// cron schedules.
+var SchedulerModule = fx.Module("scheduler",
+	fx.Provide(provideScheduler),
+)
+
+// ScheduledJob runs Job on Schedule, a cron spec read in UTC. Modules
+// contribute them to the "scheduled_jobs" group, leaving a job out when
+// the configuration gives it no schedule.
+type ScheduledJob struct {
+	Schedule string
+	Job      scheduler.Job
+}
+
+type schedulerParams struct {
+	fx.In
+
+	Jobs     []ScheduledJob `group:"scheduled_jobs"`
+	Registry *telemetry.Registry
+	Clock    clock.Clock
+	Logger   *logrus.Logger
+}
+
+// provideScheduler runs the scheduled jobs from the primary region, as
+// they write to the database. There is no scheduler while no module
+// schedules a job.
+func provideScheduler(p schedulerParams) workersResult {
+	if len(p.Jobs) == 0 {
+		return workersResult{}
+	}
+	s := scheduler.New(p.Registry, p.Clock, p.Logger)
+	for _, job := range p.Jobs {
+		if err := s.Add(job.Schedule, job.Job); err != nil {
+			p.Logger.WithError(err).Fatal("Failed to schedule job")
+		}
+	}
+	return workersResult{Workers: []Worker{{Run: s.Run, PrimaryOnly: true, Unfinished: "Scheduled jobs did not finish before shutdown deadline"}}}
+}
@@ -0,0 +1,485 @@
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/internal/adminui"
+	"backend-context-engineering-template/internal/apidocs"
+	"backend-context-engineering-template/internal/cdn"
+	grpcDelivery "backend-context-engineering-template/internal/delivery/grpc"
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/lockout"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/synthetics"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/apikey"
+	"backend-context-engineering-template/pkg/authtoken"
+	"backend-context-engineering-template/pkg/cache"
+	"backend-context-engineering-template/pkg/client"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/explain"
+	"backend-context-engineering-template/pkg/health"
+	"backend-context-engineering-template/pkg/httpclient"
+	"backend-context-engineering-template/pkg/lifecycle"
+	"backend-context-engineering-template/pkg/replication"
+	"backend-context-engineering-template/pkg/session"
+	"backend-context-engineering-template/pkg/telemetry"
+	"backend-context-engineering-template/pkg/workload"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// ServerModule serves the HTTP API and gRPC, warms the service up before
+// it reports ready and drains them when it stops. Its hooks are appended
+// last, so the servers stop before the workers do.
+var ServerModule = fx.Module("server",
+	fx.Provide(
+		lifecycle.New,
+		provideHealthHandler,
+		provideRegionHandler,
+		provideLifecycleHandler,
+		provideRouter,
+		provideHTTPServer,
+		provideGRPCServer,
+		provideSynthetics,
+	),
+	fx.Invoke(
+		registerServiceMetrics,
+		serve,
+	),
+	routesFor(httpDelivery.HealthRoutes),
+	routesFor(httpDelivery.RegionRoutes),
+	routesFor(httpDelivery.LifecycleRoutes),
+)
+
+type healthParams struct {
+	fx.In
+
+	Config           *config.Config
+	Checks           []health.Check `group:"health_checks"`
+	LifecycleManager *lifecycle.Manager
+	Clock            clock.Clock
+	Logger           *logrus.Logger
+}
+
+// provideHealthHandler serves the probes; /readyz pings the dependencies
+// other modules contribute to the "health_checks" group.
+func provideHealthHandler(p healthParams) *handlers.HealthHandler {
+	sort.Slice(p.Checks, func(i, j int) bool { return p.Checks[i].Name < p.Checks[j].Name })
+	return handlers.NewHealthHandler(health.NewChecker(p.Config.Lifecycle.HealthCheckTimeout, p.Clock, p.Checks...), p.LifecycleManager)
+}
+
+func provideRegionHandler(cfg *config.Config, replicaMonitor *replication.Monitor) *handlers.RegionHandler {
+	return handlers.NewRegionHandler(cfg.Region.Name, cfg.Region.Primary, replicaMonitor)
+}
+
+func provideLifecycleHandler(cfg *config.Config, manager *lifecycle.Manager, logger *logrus.Logger) *handlers.LifecycleHandler {
+	return handlers.NewLifecycleHandler(manager, cfg.Lifecycle.DrainTimeout)
+}
+
+type routerParams struct {
+	fx.In
+
+	Config *config.Config
+
+	Modules []httpDelivery.Module `group:"routes"`
+
+	APIKeys          apikey.Store
+	Sessions         *session.Manager
+	SessionCookie    session.CookieConfig
+	UserTokens       *authtoken.Issuer
+	WorkloadVerifier *workload.Verifier
+	WorkloadRoles    workload.Roles
+
+	RateLimiter        *middleware.RateLimiter
+	CostLimiter        *middleware.CostLimiter
+	Maintenance        middleware.MaintenanceSource
+	UsageRecorder      middleware.UsageRecorder
+	IdempotencyStore   middleware.IdempotencyStore
+	AuditRepository    *postgres.AuditRepository
+	ExplainCapturer    *explain.Capturer
+	CDNPurger          *cdn.Purger
+	SurrogateKeyHeader middleware.SurrogateKeyHeader
+
+	Clock            clock.Clock
+	LifecycleManager *lifecycle.Manager
+	Registry         *telemetry.Registry
+	Logger           *logrus.Logger
+}
+
+func provideRouter(p routerParams) *gin.Engine {
+	cfg, logger := p.Config, p.Logger
+
+	var storeLabels *telemetry.TopK
+	if cfg.Telemetry.StoreTopK > 0 {
+		storeLabels = telemetry.NewTopK(cfg.Telemetry.StoreTopK)
+	}
+	var metricsHandler http.Handler
+	if cfg.Telemetry.MetricsExporter == "prometheus" {
+		metricsHandler = p.Registry.PrometheusHandler()
+	}
+	var adminUI http.Handler
+	if cfg.AdminUI.Enabled {
+		ui, err := adminui.New(cfg.AdminUI.APIBasePath)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to load admin UI")
+		}
+		adminUI = ui
+	}
+	var apiDocs http.Handler
+	if cfg.APIDocs.Enabled {
+		docs, err := apidocs.New(cfg.APIDocs.APIBasePath)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to load API docs")
+		}
+		apiDocs = docs
+	}
+	// Catalog reads are only cacheable while changes purge them.
+	var cachePolicies map[string]middleware.CachePolicy
+	if p.CDNPurger != nil {
+		cachePolicies = httpDelivery.CachePolicies
+	}
+	var userMiddleware gin.HandlerFunc
+	if p.UserTokens != nil {
+		userMiddleware = middleware.User(p.UserTokens, logger)
+	}
+	// A nil *AuditRepository must not become a non-nil interface.
+	var auditRecorder middleware.AuditRecorder
+	if p.AuditRepository != nil {
+		auditRecorder = p.AuditRepository
+	}
+
+	return httpDelivery.SetupRouter(httpDelivery.RouterDeps{
+		Modules:            p.Modules,
+		APIKeyMiddleware:   middleware.APIKey(p.APIKeys, logger),
+		SessionMiddleware:  middleware.Session(p.Sessions, p.SessionCookie, logger),
+		WorkloadMiddleware: middleware.Workload(p.WorkloadVerifier, p.WorkloadRoles, logger),
+		UserMiddleware:     userMiddleware,
+		ProtectProducts:    cfg.Auth.ProtectProducts,
+		ListEnvelope:       !cfg.HTTP.LegacyListResponses,
+		AdminRole:          cfg.Workload.AdminRole,
+		RateLimiter:        p.RateLimiter,
+		Maintenance:        p.Maintenance,
+		UsageRecorder:      p.UsageRecorder,
+		IdempotencyStore:   p.IdempotencyStore,
+		IdempotencyConfig:  middleware.IdempotencyConfig{TTL: cfg.Idempotency.KeyTTL, LockTimeout: cfg.Idempotency.LockTimeout},
+		CostLimiter:        p.CostLimiter,
+		AuditRecorder:      auditRecorder,
+		ExplainCapturer:    p.ExplainCapturer,
+		QueryBudget:        cfg.DB.QueryBudget,
+		CachePolicies:      cachePolicies,
+		SurrogateKeyHeader: p.SurrogateKeyHeader,
+		Clock:              p.Clock,
+		LifecycleManager:   p.LifecycleManager,
+		Registry:           p.Registry,
+		StoreLabels:        storeLabels,
+		MetricsHandler:     metricsHandler,
+		AdminUI:            adminUI,
+		APIDocs:            apiDocs,
+		Logger:             logger,
+	})
+}
+
+func provideHTTPServer(cfg *config.Config, router *gin.Engine, logger *logrus.Logger) *http.Server {
+	server := &http.Server{
+		Addr:    fmt.Sprintf("%s:%s", cfg.HTTP.Addr, cfg.HTTP.Port),
+		Handler: router,
+	}
+	if cfg.HTTP.ClientCAFile != "" {
+		bundle, err := os.ReadFile(cfg.HTTP.ClientCAFile)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to read HTTP_CLIENT_CA_FILE")
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(bundle) {
+			logger.Fatal("HTTP_CLIENT_CA_FILE contains no certificates")
+		}
+		// Client certificates are optional so API key and browser clients
+		// keep working; X.509-SVIDs that are sent must chain to the bundle.
+		server.TLSConfig = &tls.Config{
+			ClientCAs:  clientCAs,
+			ClientAuth: tls.VerifyClientCertIfGiven,
+		}
+	}
+	return server
+}
+
+type grpcParams struct {
+	fx.In
+
+	Config           *config.Config
+	HTTPServer       *http.Server
+	APIKeys          apikey.Store
+	UserTokens       *authtoken.Issuer
+	WorkloadVerifier *workload.Verifier
+	WorkloadRoles    workload.Roles
+	Products         *usecase.ProductUseCase
+	Clock            clock.Clock
+	LifecycleManager *lifecycle.Manager
+	Registry         *telemetry.Registry
+	Logger           *logrus.Logger
+}
+
+// provideGRPCServer serves gRPC when a port is configured, with the HTTP
+// server's certificate and client CAs; it is nil otherwise.
+func provideGRPCServer(p grpcParams) *grpcDelivery.Server {
+	cfg := p.Config
+	if cfg.GRPC.Port == "" {
+		return nil
+	}
+	var options []grpc.ServerOption
+	if cfg.HTTP.TLSCertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.HTTP.TLSCertFile, cfg.HTTP.TLSKeyFile)
+		if err != nil {
+			p.Logger.WithError(err).Fatal("Failed to load the TLS certificate for gRPC")
+		}
+		tlsConfig := &tls.Config{Certificates: []tls.Certificate{certificate}}
+		if p.HTTPServer.TLSConfig != nil {
+			tlsConfig.ClientCAs = p.HTTPServer.TLSConfig.ClientCAs
+			tlsConfig.ClientAuth = p.HTTPServer.TLSConfig.ClientAuth
+		}
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpcDelivery.NewServer(grpcDelivery.ServerDeps{
+		APIKeys:          p.APIKeys,
+		UserTokens:       p.UserTokens,
+		WorkloadVerifier: p.WorkloadVerifier,
+		WorkloadRoles:    p.WorkloadRoles,
+		Reflection:       cfg.GRPC.Reflection,
+		Products:         p.Products,
+		ProtectProducts:  cfg.Auth.ProtectProducts,
+		Clock:            p.Clock,
+		LifecycleManager: p.LifecycleManager,
+		Registry:         p.Registry,
+		Logger:           p.Logger,
+		Options:          options,
+	})
+	p.LifecycleManager.OnReadyChange(server.SetServing)
+	return server
+}
+
+// provideSynthetics runs the synthetic create, read and delete journey
+// against this instance, or the configured target, when it is enabled.
+func provideSynthetics(cfg *config.Config, registry *telemetry.Registry, clk clock.Clock, logger *logrus.Logger) workersResult {
+	if !cfg.Synthetics.Enabled {
+		return workersResult{}
+	}
+	if cfg.Synthetics.StoreID <= 0 {
+		logger.Fatal("SYNTHETICS_STORE_ID is required when synthetic checks are enabled")
+	}
+	targetURL := cfg.Synthetics.TargetURL
+	if targetURL == "" {
+		scheme := "http"
+		if cfg.HTTP.TLSCertFile != "" {
+			scheme = "https"
+		}
+		targetURL = fmt.Sprintf("%s://127.0.0.1:%s", scheme, cfg.HTTP.Port)
+	}
+	prober := synthetics.NewProber(client.New(targetURL, &http.Client{Timeout: cfg.Synthetics.Timeout}), registry, synthetics.Config{
+		StoreID:  cfg.Synthetics.StoreID,
+		Interval: cfg.Synthetics.Interval,
+		Timeout:  cfg.Synthetics.Timeout,
+	}, clk, logger)
+	return workersResult{Workers: []Worker{{Run: prober.Run}}}
+}
+
+// StreamCloser ends the responses of a stream that stays open until the
+// client leaves, so neither a drain nor the HTTP server's shutdown waits
+// for its clients. Modules contribute them to the "stream_closers" group.
+type StreamCloser func()
+
+type serveParams struct {
+	fx.In
+
+	Lifecycle        fx.Lifecycle
+	Shutdown         *Shutdown
+	Config           *config.Config
+	HTTPServer       *http.Server
+	GRPCServer       *grpcDelivery.Server
+	LifecycleManager *lifecycle.Manager
+	WarmUp           warmUp
+	StreamClosers    []StreamCloser `group:"stream_closers"`
+	Logger           *logrus.Logger
+}
+
+// serve starts the servers and warms the service up in the background,
+// retrying until it is ready. When the service stops it reports not ready
+// and drains both servers at once, so neither waits out the other's calls
+// before it stops accepting new ones.
+func serve(p serveParams) {
+	cfg, logger, manager := p.Config, p.Logger, p.LifecycleManager
+	closeStreams := func() {
+		for _, closeStream := range p.StreamClosers {
+			closeStream()
+		}
+	}
+	// A drain, such as POST /admin/drain before a rollout, ends the
+	// streams too, so it finishes once the other requests have.
+	manager.OnDrain(closeStreams)
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				logger.WithField("addr", p.HTTPServer.Addr).Info("HTTP server starting")
+				var err error
+				if cfg.HTTP.TLSCertFile != "" {
+					err = p.HTTPServer.ListenAndServeTLS(cfg.HTTP.TLSCertFile, cfg.HTTP.TLSKeyFile)
+				} else {
+					err = p.HTTPServer.ListenAndServe()
+				}
+				if err != nil && err != http.ErrServerClosed {
+					logger.WithError(err).Fatal("Failed to start server")
+				}
+			}()
+			if p.GRPCServer != nil {
+				go func() {
+					addr := fmt.Sprintf("%s:%s", cfg.HTTP.Addr, cfg.GRPC.Port)
+					listener, err := net.Listen("tcp", addr)
+					if err != nil {
+						logger.WithError(err).Fatal("Failed to listen for gRPC")
+					}
+					logger.WithField("addr", addr).Info("gRPC server starting")
+					if err := p.GRPCServer.Serve(listener); err != nil {
+						logger.WithError(err).Fatal("Failed to start gRPC server")
+					}
+				}()
+			}
+			go warmUpUntilReady(cfg, manager, p.WarmUp, logger)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			manager.SetReady(false)
+			logger.Info("Shutting down server...")
+
+			ctx := p.Shutdown.Context()
+			grpcDone := make(chan struct{})
+			go func() {
+				defer close(grpcDone)
+				if p.GRPCServer != nil {
+					p.GRPCServer.Shutdown(ctx)
+				}
+			}()
+			closeStreams()
+			err := p.HTTPServer.Shutdown(ctx)
+			<-grpcDone
+			return err
+		},
+	})
+}
+
+// warmUpUntilReady runs the warm-up steps until they succeed, so the
+// service reports ready, or it is drained first.
+func warmUpUntilReady(cfg *config.Config, manager *lifecycle.Manager, steps warmUp, logger *logrus.Logger) {
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Warmup.Timeout)
+		err := manager.WarmUp(ctx, steps...)
+		cancel()
+		if err == nil {
+			break
+		}
+		logger.WithError(err).WithField("attempt", attempt).Warn("Warm-up failed, service stays not ready")
+
+		select {
+		case <-manager.Drained():
+			return
+		case <-time.After(cfg.Warmup.RetryInterval):
+		}
+		if manager.Draining() {
+			return
+		}
+	}
+	if manager.Draining() {
+		return
+	}
+	logger.WithField("duration", time.Since(start)).Info("Warm-up complete, service is ready")
+}
+
+// registerServiceMetrics exports stats the service already keeps through
+// the metrics registry.
+func registerServiceMetrics(registry *telemetry.Registry, outbound *Outbound, guard *lockout.Guard, cacheStats *cache.Stats) {
+	outboundStat := func(stat func(httpclient.DestinationStats) float64) func() []telemetry.Sample {
+		return func() []telemetry.Sample {
+			var samples []telemetry.Sample
+			for host, stats := range outbound.Metrics.Snapshot() {
+				samples = append(samples, telemetry.Sample{
+					Labels: []telemetry.Label{{Name: "server.address", Value: host}},
+					Value:  stat(stats),
+				})
+			}
+			return samples
+		}
+	}
+	registry.RegisterCounterFunc("http_client_requests_total", "Outbound HTTP requests per destination.",
+		outboundStat(func(s httpclient.DestinationStats) float64 { return float64(s.Requests) }))
+	registry.RegisterCounterFunc("http_client_failures_total", "Failed outbound HTTP requests per destination.",
+		outboundStat(func(s httpclient.DestinationStats) float64 { return float64(s.Failures) }))
+	registry.RegisterCounterFunc("http_client_retries_total", "Retried outbound HTTP requests per destination.",
+		outboundStat(func(s httpclient.DestinationStats) float64 { return float64(s.Retries) }))
+	registry.RegisterCounterFunc("http_client_rejected_total", "Outbound requests rejected by an open circuit breaker.",
+		outboundStat(func(s httpclient.DestinationStats) float64 { return float64(s.Rejected) }))
+
+	loginStat := func(stat func(lockout.Metrics) int64) func() []telemetry.Sample {
+		return func() []telemetry.Sample {
+			return []telemetry.Sample{{Value: float64(stat(guard.Metrics()))}}
+		}
+	}
+	registry.RegisterCounterFunc("login_failures_total", "Failed second-factor checks.",
+		loginStat(func(m lockout.Metrics) int64 { return m.Failures }))
+	registry.RegisterCounterFunc("login_rejected_total", "Login attempts refused during a lockout.",
+		loginStat(func(m lockout.Metrics) int64 { return m.Rejected }))
+	registry.RegisterCounterFunc("login_lockouts_total", "Identities and client IPs locked out.", func() []telemetry.Sample {
+		m := guard.Metrics()
+		return []telemetry.Sample{
+			{Labels: []telemetry.Label{{Name: "subject", Value: domain.LockoutSubjectIdentity}}, Value: float64(m.IdentityLockouts)},
+			{Labels: []telemetry.Label{{Name: "subject", Value: domain.LockoutSubjectIP}}, Value: float64(m.IPLockouts)},
+		}
+	})
+
+	cacheStat := func(stat func(cache.EndpointStats) []telemetry.Sample) func() []telemetry.Sample {
+		return func() []telemetry.Sample {
+			var samples []telemetry.Sample
+			for _, e := range cacheStats.Endpoints() {
+				for _, sample := range stat(e) {
+					sample.Labels = append([]telemetry.Label{{Name: "endpoint", Value: e.Endpoint}, {Name: "tier", Value: e.Tier}}, sample.Labels...)
+					samples = append(samples, sample)
+				}
+			}
+			return samples
+		}
+	}
+	registry.RegisterCounterFunc("cache_requests_total", "Cache lookups per endpoint and tier by result.",
+		cacheStat(func(e cache.EndpointStats) []telemetry.Sample {
+			return []telemetry.Sample{
+				{Labels: []telemetry.Label{{Name: "result", Value: "hit"}}, Value: float64(e.Hits)},
+				{Labels: []telemetry.Label{{Name: "result", Value: "miss"}}, Value: float64(e.Misses - e.Expired)},
+				{Labels: []telemetry.Label{{Name: "result", Value: "expired"}}, Value: float64(e.Expired)},
+			}
+		}))
+	registry.RegisterCounterFunc("cache_bytes_saved_total", "Estimated bytes cache hits kept from being read from the tier below.",
+		cacheStat(func(e cache.EndpointStats) []telemetry.Sample {
+			return []telemetry.Sample{{Value: float64(e.BytesSaved)}}
+		}))
+	registry.RegisterCounterFunc("cache_invalidations_total", "Cache entries invalidated by writes per tier.", func() []telemetry.Sample {
+		var samples []telemetry.Sample
+		for tier, n := range cacheStats.Invalidations() {
+			samples = append(samples, telemetry.Sample{Labels: []telemetry.Label{{Name: "tier", Value: tier}}, Value: float64(n)})
+		}
+		return samples
+	})
+}
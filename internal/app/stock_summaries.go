@@ -0,0 +1,45 @@
+package app
+
+import (
+	"database/sql"
+
+	httpDelivery "backend-context-engineering-template/internal/delivery/http"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/events"
+	"backend-context-engineering-template/internal/repository/postgres"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/internal/worker"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// StockSummariesModule serves each store's products counted by stock
+// availability. Product events queue a recalculation of their store as a
+// background job. The summaries are kept in Postgres, so the module is off
+// in load-test mode.
+var StockSummariesModule = fx.Module("stock_summaries",
+	fx.Provide(provideStockSummaries),
+)
+
+type stockSummariesResult struct {
+	fx.Out
+
+	Routes      []httpDelivery.Module `group:"routes,flatten"`
+	Sinks       []events.Sink         `group:"event_sinks,flatten"`
+	JobHandlers []JobHandler          `group:"job_handlers,flatten"`
+}
+
+func provideStockSummaries(db *sql.DB, queue worker.Queue, clk clock.Clock, logger *logrus.Logger) stockSummariesResult {
+	if db == nil {
+		return stockSummariesResult{}
+	}
+	stockSummaryUseCase := usecase.NewStockSummaryUseCase(postgres.NewStockSummaryRepository(db), clk)
+	jobs := worker.NewStockSummaries(queue, stockSummaryUseCase, logger)
+	return stockSummariesResult{
+		Routes:      []httpDelivery.Module{httpDelivery.StockSummaryRoutes(handlers.NewStockSummaryHandler(stockSummaryUseCase))},
+		Sinks:       []events.Sink{jobs},
+		JobHandlers: []JobHandler{{Type: worker.JobRecalculateStockSummary, Handle: jobs.Handle}},
+	}
+}
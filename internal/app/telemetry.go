@@ -0,0 +1,110 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"backend-context-engineering-template/config"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/grafana/pyroscope-go"
+	"github.com/sirupsen/logrus"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.uber.org/fx"
+)
+
+// TelemetryModule provides the metrics registry and ships logs and
+// profiles as configured.
+var TelemetryModule = fx.Module("telemetry",
+	fx.Provide(provideTelemetry),
+	fx.Invoke(func(_ *telemetry.Registry, logger *logrus.Logger) {
+		logger.Info("Starting application...")
+	}),
+)
+
+// provideTelemetry builds the metrics registry, ships logrus entries and
+// starts the profiler. Logs and metrics share one resource so every signal
+// carries the same service attributes. They are flushed once everything
+// else has stopped, so the final shutdown logs and metrics are still
+// shipped.
+func provideTelemetry(lc fx.Lifecycle, cfg *config.Config, clk clock.Clock, logger *logrus.Logger) *telemetry.Registry {
+	resource := telemetry.NewResource(cfg.Telemetry.ServiceName, cfg.App.Env, cfg.Telemetry.ResourceAttributes)
+	otlpConfig := telemetry.OTLPConfig{Endpoint: cfg.Telemetry.OTLPEndpoint, Headers: cfg.Telemetry.OTLPHeaders}
+
+	var metricReaders []sdkmetric.Reader
+	switch cfg.Telemetry.MetricsExporter {
+	case "", "none", "prometheus":
+	case "otlp":
+		reader, err := telemetry.NewOTLPMetricReader(context.Background(), otlpConfig, cfg.Telemetry.MetricExportInterval)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to create OTLP metric exporter")
+		}
+		metricReaders = append(metricReaders, reader)
+	default:
+		logger.WithField("exporter", cfg.Telemetry.MetricsExporter).Fatal("Unsupported metrics exporter")
+	}
+	registry := telemetry.NewRegistry(resource, metricReaders...)
+	registry.RegisterRuntimeMetrics(clk)
+
+	var loggerProvider *sdklog.LoggerProvider
+	switch cfg.Telemetry.LogsExporter {
+	case "", "none":
+	case "otlp":
+		provider, err := telemetry.NewLoggerProvider(context.Background(), otlpConfig, resource, cfg.Telemetry.LogsBatchSize, cfg.Telemetry.LogsExportInterval)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to create OTLP log exporter")
+		}
+		loggerProvider = provider
+		logger.AddHook(telemetry.NewLogHook(loggerProvider))
+	default:
+		logger.WithField("exporter", cfg.Telemetry.LogsExporter).Fatal("Unsupported logs exporter")
+	}
+
+	var profiler *pyroscope.Profiler
+	if cfg.Profiling.Enabled {
+		profilingConfig := pyroscope.Config{
+			ApplicationName: cfg.Telemetry.ServiceName,
+			ServerAddress:   cfg.Profiling.ServerAddress,
+			Tags:            map[string]string{"version": cfg.App.Version, "env": cfg.App.Env},
+			UploadRate:      cfg.Profiling.Interval,
+			Logger:          logger,
+			ProfileTypes:    []pyroscope.ProfileType{pyroscope.ProfileCPU, pyroscope.ProfileAllocObjects, pyroscope.ProfileAllocSpace},
+		}
+		if cfg.Profiling.AuthToken != "" {
+			profilingConfig.HTTPHeaders = map[string]string{"Authorization": "Bearer " + cfg.Profiling.AuthToken}
+		}
+		var err error
+		profiler, err = pyroscope.Start(profilingConfig)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to start profiler")
+		}
+	}
+
+	lc.Append(fx.StopHook(func() {
+		if profiler != nil {
+			// Stop uploads the profiles collected so far.
+			if err := profiler.Stop(); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to stop profiler: %v\n", err)
+			}
+		}
+		// The shutdown deadline may have passed by now; the exporters get
+		// a deadline of their own.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		// Export failures go to stderr rather than back through the
+		// logger, which would feed them into the failing pipeline.
+		if err := registry.Shutdown(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to export metrics: %v\n", err)
+		}
+		if loggerProvider != nil {
+			if err := loggerProvider.Shutdown(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to export logs: %v\n", err)
+			}
+		}
+	}))
+	return registry
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/config"
+
+	"github.com/sirupsen/logrus"
+	"go.uber.org/fx"
+)
+
+// ShutdownTimeout bounds how long stopping the service waits for requests
+// in flight and work in progress to finish.
+const ShutdownTimeout = 30 * time.Second
+
+// WorkersModule runs the Workers other modules contribute.
+var WorkersModule = fx.Module("workers",
+	fx.Provide(func() *Shutdown { return &Shutdown{} }),
+	fx.Invoke(runWorkers),
+)
+
+// Worker is a background job that runs from start-up until the service
+// stops. Modules contribute them to the "workers" group.
+type Worker struct {
+	Run func(ctx context.Context)
+	// PrimaryOnly workers write on a schedule; passive regions leave them
+	// to the primary region so they don't run twice.
+	PrimaryOnly bool
+	// Unfinished is logged when the worker has not returned by the
+	// shutdown deadline. Workers without it are not waited for.
+	Unfinished string
+}
+
+// Shutdown is the deadline shared by the stop hooks that wait for work to
+// finish, so together they take at most ShutdownTimeout. It starts when
+// the first of them runs. Closing connections and flushing telemetry come
+// after and run even once it has passed, so the application itself is
+// stopped without a deadline.
+type Shutdown struct {
+	once sync.Once
+	ctx  context.Context
+}
+
+// Context returns a context that is done at the shutdown deadline.
+func (s *Shutdown) Context() context.Context {
+	s.once.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(ShutdownTimeout, cancel)
+		s.ctx = ctx
+	})
+	return s.ctx
+}
+
+// WaitOnStop waits for wait when the service stops, logging unfinished if
+// it has not returned by the shutdown deadline.
+func (s *Shutdown) WaitOnStop(lc fx.Lifecycle, wait func(), unfinished string, logger *logrus.Logger) {
+	lc.Append(fx.StopHook(func() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			wait()
+		}()
+		select {
+		case <-done:
+		case <-s.Context().Done():
+			logger.Warn(unfinished)
+		}
+	}))
+}
+
+// passive reports whether this instance is in a passive region, which
+// serves requests but leaves jobs that write on a schedule to the primary
+// region.
+func passive(cfg *config.Config) bool {
+	return cfg.Region.Name != cfg.Region.Primary
+}
+
+type workerParams struct {
+	fx.In
+
+	Lifecycle fx.Lifecycle
+	Config    *config.Config
+	Shutdown  *Shutdown
+	Workers   []Worker `group:"workers"`
+	Logger    *logrus.Logger
+}
+
+// runWorkers starts the workers with the service and cancels them when it
+// stops, once the servers have.
+func runWorkers(p workerParams) {
+	passive := passive(p.Config)
+	ctx, cancel := context.WithCancel(context.Background())
+	type running struct {
+		done       chan struct{}
+		unfinished string
+	}
+	var waits []running
+
+	p.Lifecycle.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			if passive {
+				p.Logger.WithField("region", p.Config.Region.Name).WithField("primary_region", p.Config.Region.Primary).Info("Passive region, scheduled jobs are disabled")
+			}
+			for _, worker := range p.Workers {
+				if worker.PrimaryOnly && passive {
+					continue
+				}
+				done := make(chan struct{})
+				if worker.Unfinished != "" {
+					waits = append(waits, running{done: done, unfinished: worker.Unfinished})
+				}
+				go func() {
+					defer close(done)
+					worker.Run(ctx)
+				}()
+			}
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			deadline := p.Shutdown.Context()
+			for _, w := range waits {
+				select {
+				case <-w.done:
+				case <-deadline.Done():
+					p.Logger.Warn(w.unfinished)
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"backend-context-engineering-template/config"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+)
+
+func TestRunWorkers(t *testing.T) {
+	tests := []struct {
+		name     string
+		region   string
+		expected []string
+	}{
+		{name: "primary region runs every worker", region: "eu", expected: []string{"cache", "scheduler"}},
+		{name: "passive region skips primary-only workers", region: "us", expected: []string{"cache"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Region.Name = tt.region
+			cfg.Region.Primary = "eu"
+
+			var mu sync.Mutex
+			var started, stopped []string
+			worker := func(name string) func(ctx context.Context) {
+				return func(ctx context.Context) {
+					mu.Lock()
+					started = append(started, name)
+					mu.Unlock()
+					<-ctx.Done()
+					mu.Lock()
+					stopped = append(stopped, name)
+					mu.Unlock()
+				}
+			}
+
+			app := fxtest.New(t,
+				fx.Supply(cfg, logrus.New()),
+				fx.Provide(func() workersResult {
+					return workersResult{Workers: []Worker{
+						{Run: worker("cache"), Unfinished: "cache did not stop"},
+						{Run: worker("scheduler"), PrimaryOnly: true, Unfinished: "scheduler did not stop"},
+					}}
+				}),
+				WorkersModule,
+			)
+			app.RequireStart()
+			app.RequireStop()
+
+			// Stop waits for workers with an Unfinished message to return.
+			mu.Lock()
+			defer mu.Unlock()
+			assert.ElementsMatch(t, tt.expected, stopped)
+			assert.ElementsMatch(t, tt.expected, started)
+		})
+	}
+}
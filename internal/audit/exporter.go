@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+// Cursor is the position of the last exported entry in commit order:
+// entries are ordered by the transaction that wrote them, then by ID.
+// Ordering by ID alone would skip an entry whose transaction commits after
+// one holding a higher ID was already exported.
+type Cursor struct {
+	TxID int64
+	ID   int64
+}
+
+// Source is where audit entries and per-sink export cursors are stored.
+type Source interface {
+	// GetAfter returns up to limit committed entries after cursor, and the
+	// cursor of the last one. It must never return an entry while one that
+	// sorts before it may still commit.
+	GetAfter(ctx context.Context, after Cursor, limit int) ([]*domain.AuditEntry, Cursor, error)
+	GetCursor(ctx context.Context, sink string) (Cursor, error)
+	SaveCursor(ctx context.Context, sink string, cursor Cursor) error
+}
+
+// Sink delivers a batch of entries to an external system. Send must only
+// return nil once the receiver has accepted the whole batch.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, entries []*domain.AuditEntry) error
+}
+
+type Config struct {
+	BatchSize  int
+	Interval   time.Duration
+	MaxBackoff time.Duration
+}
+
+// Exporter ships audit entries to one sink with at-least-once delivery. The
+// cursor only advances after the sink accepts a batch, so a crash or failed
+// send replays entries instead of losing them. Only one batch is in flight
+// at a time: when the sink slows down, entries queue up in the database
+// rather than in memory.
+type Exporter struct {
+	source Source
+	sink   Sink
+	cfg    Config
+	logger *logrus.Logger
+}
+
+func NewExporter(source Source, sink Sink, cfg Config, logger *logrus.Logger) *Exporter {
+	return &Exporter{
+		source: source,
+		sink:   sink,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (e *Exporter) Run(ctx context.Context) {
+	logger := e.logger.WithField("sink", e.sink.Name())
+	logger.Info("Audit exporter started")
+	defer logger.Info("Audit exporter stopped")
+
+	var cursor Cursor
+	if !e.retry(ctx, logger, "load export cursor", func() (err error) {
+		cursor, err = e.source.GetCursor(ctx, e.sink.Name())
+		return err
+	}) {
+		return
+	}
+
+	for {
+		var batch []*domain.AuditEntry
+		var next Cursor
+		if !e.retry(ctx, logger, "read audit entries", func() (err error) {
+			batch, next, err = e.source.GetAfter(ctx, cursor, e.cfg.BatchSize)
+			return err
+		}) {
+			return
+		}
+
+		if len(batch) > 0 {
+			if !e.retry(ctx, logger, "send audit entries", func() error {
+				return e.sink.Send(ctx, batch)
+			}) {
+				return
+			}
+
+			if !e.retry(ctx, logger, "save export cursor", func() error {
+				return e.source.SaveCursor(ctx, e.sink.Name(), next)
+			}) {
+				return
+			}
+			cursor = next
+
+			// A full batch means we are behind; keep going without waiting.
+			if len(batch) == e.cfg.BatchSize {
+				continue
+			}
+		}
+
+		if !sleep(ctx, e.cfg.Interval) {
+			return
+		}
+	}
+}
+
+// retry runs fn until it succeeds, backing off exponentially between
+// attempts. It returns false if ctx is cancelled first.
+func (e *Exporter) retry(ctx context.Context, logger *logrus.Entry, op string, fn func() error) bool {
+	backoff := e.cfg.Interval
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		logger.WithError(err).WithFields(logrus.Fields{
+			"operation": op,
+			"attempt":   attempt,
+			"backoff":   backoff,
+		}).Warn("Audit export failed, retrying")
+
+		if !sleep(ctx, backoff) {
+			return false
+		}
+		backoff *= 2
+		if backoff > e.cfg.MaxBackoff {
+			backoff = e.cfg.MaxBackoff
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
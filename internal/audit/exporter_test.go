@@ -0,0 +1,293 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySource orders entries like the postgres source: by the transaction
+// that wrote them, then by ID, hiding transactions from inFlight on.
+type memorySource struct {
+	mu       sync.Mutex
+	entries  []*memoryEntry
+	inFlight int64
+	cursors  map[string]Cursor
+}
+
+type memoryEntry struct {
+	txID  int64
+	entry *domain.AuditEntry
+}
+
+func newMemorySource(n int) *memorySource {
+	s := &memorySource{cursors: make(map[string]Cursor)}
+	for i := 1; i <= n; i++ {
+		s.add(int64(i), int64(i))
+	}
+	s.inFlight = int64(n) + 1
+	return s
+}
+
+func (s *memorySource) add(txID, id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, &memoryEntry{txID: txID, entry: &domain.AuditEntry{ID: id, Method: http.MethodPost}})
+	sort.Slice(s.entries, func(i, j int) bool {
+		a, b := s.entries[i], s.entries[j]
+		return a.txID < b.txID || a.txID == b.txID && a.entry.ID < b.entry.ID
+	})
+}
+
+func (s *memorySource) commitUpTo(txID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight = txID + 1
+}
+
+func (s *memorySource) GetAfter(_ context.Context, after Cursor, limit int) ([]*domain.AuditEntry, Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var batch []*domain.AuditEntry
+	last := after
+	for _, e := range s.entries {
+		if e.txID >= s.inFlight || len(batch) == limit {
+			break
+		}
+		if e.txID < after.TxID || e.txID == after.TxID && e.entry.ID <= after.ID {
+			continue
+		}
+		batch = append(batch, e.entry)
+		last = Cursor{TxID: e.txID, ID: e.entry.ID}
+	}
+	return batch, last, nil
+}
+
+func (s *memorySource) GetCursor(_ context.Context, sink string) (Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cursors[sink], nil
+}
+
+func (s *memorySource) SaveCursor(_ context.Context, sink string, cursor Cursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cursors[sink] = cursor
+	return nil
+}
+
+type flakySink struct {
+	mu       sync.Mutex
+	failures int
+	received []int64
+	attempts int
+}
+
+func (s *flakySink) Name() string { return "test" }
+
+func (s *flakySink) Send(_ context.Context, entries []*domain.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts++
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("collector unavailable")
+	}
+	for _, entry := range entries {
+		s.received = append(s.received, entry.ID)
+	}
+	return nil
+}
+
+func TestExporter_DeliversAllEntriesDespiteFailures(t *testing.T) {
+	source := newMemorySource(7)
+	sink := &flakySink{failures: 2}
+
+	exporter := NewExporter(source, sink, Config{
+		BatchSize:  3,
+		Interval:   time.Millisecond,
+		MaxBackoff: 5 * time.Millisecond,
+	}, logrus.New())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		exporter.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		cursor, _ := source.GetCursor(ctx, "test")
+		return cursor.ID == 7
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7}, sink.received)
+	assert.Equal(t, 5, sink.attempts, "three batches plus two failed attempts")
+}
+
+func TestExporter_ResumesFromCursor(t *testing.T) {
+	source := newMemorySource(5)
+	source.cursors["test"] = Cursor{TxID: 3, ID: 3}
+	sink := &flakySink{}
+
+	exporter := NewExporter(source, sink, Config{BatchSize: 10, Interval: time.Millisecond, MaxBackoff: time.Millisecond}, logrus.New())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go exporter.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		sink.mu.Lock()
+		defer sink.mu.Unlock()
+		return len(sink.received) == 2
+	}, time.Second, time.Millisecond)
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	assert.Equal(t, []int64{4, 5}, sink.received)
+}
+
+func TestExporter_WaitsForLateCommits(t *testing.T) {
+	source := newMemorySource(0)
+	// Transaction 1 took ID 1 but is still running when transaction 2
+	// commits ID 2.
+	source.add(1, 1)
+	source.add(2, 2)
+	source.commitUpTo(0)
+	sink := &flakySink{}
+
+	exporter := NewExporter(source, sink, Config{BatchSize: 10, Interval: time.Millisecond, MaxBackoff: time.Millisecond}, logrus.New())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		exporter.Run(ctx)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	sink.mu.Lock()
+	assert.Empty(t, sink.received, "nothing may be exported while an earlier transaction is in flight")
+	sink.mu.Unlock()
+	source.commitUpTo(2)
+
+	require.Eventually(t, func() bool {
+		cursor, _ := source.GetCursor(ctx, "test")
+		return cursor == Cursor{TxID: 2, ID: 2}
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	assert.Equal(t, []int64{1, 2}, sink.received, "the entry committed late must not be skipped")
+}
+
+func TestHTTPSink_Send(t *testing.T) {
+	var got httpBatch
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.Client(), server.URL, "secret")
+	err := sink.Send(context.Background(), []*domain.AuditEntry{{ID: 1, Route: "/api/v1/products"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", auth)
+	require.Len(t, got.Entries, 1)
+	assert.Equal(t, "/api/v1/products", got.Entries[0].Route)
+}
+
+func TestHTTPSink_RejectedBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.Client(), server.URL, "")
+	assert.Error(t, sink.Send(context.Background(), []*domain.AuditEntry{{ID: 1}}))
+}
+
+func TestSyslogSink_OctetCountedTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	messages := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		for i := 0; i < 2; i++ {
+			prefix, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(prefix))
+			buf := make([]byte, n)
+			if _, err := io.ReadFull(reader, buf); err != nil {
+				return
+			}
+			messages <- string(buf)
+		}
+	}()
+
+	sink := NewSyslogSink("tcp", listener.Addr().String(), "product-service")
+	defer sink.Close()
+
+	occurred := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	err = sink.Send(context.Background(), []*domain.AuditEntry{
+		{ID: 1, Method: http.MethodPost, OccurredAt: occurred},
+		{ID: 2, Method: http.MethodDelete, OccurredAt: occurred},
+	})
+	require.NoError(t, err)
+
+	first := <-messages
+	assert.True(t, strings.HasPrefix(first, "<110>1 2026-01-02T03:04:05Z "), first)
+	assert.Contains(t, first, `product-service - audit [audit@32473 id="1"] {`)
+	assert.Contains(t, <-messages, `"method":"DELETE"`)
+}
+
+func TestKafkaMessages(t *testing.T) {
+	occurred := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	messages, err := kafkaMessages([]*domain.AuditEntry{
+		{ID: 7, Method: http.MethodDelete, Route: "/api/v1/products/:id", OccurredAt: occurred},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "7", string(messages[0].Key))
+	assert.Equal(t, occurred, messages[0].Time)
+	assert.Contains(t, string(messages[0].Value), `"route":"/api/v1/products/:id"`)
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type httpBatch struct {
+	Entries []*domain.AuditEntry `json:"entries"`
+}
+
+// HTTPSink posts batches as JSON to an HTTPS collector. Any 2xx response
+// acknowledges the batch.
+type HTTPSink struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+// NewHTTPSink returns a sink posting to url. token, when set, is sent as a
+// bearer token.
+func NewHTTPSink(client *http.Client, url, token string) *HTTPSink {
+	return &HTTPSink{client: client, url: url, token: token}
+}
+
+func (s *HTTPSink) Name() string {
+	return "http"
+}
+
+func (s *HTTPSink) Send(ctx context.Context, entries []*domain.AuditEntry) error {
+	body, err := json.Marshal(httpBatch{Entries: entries})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("audit collector request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
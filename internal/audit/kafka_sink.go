@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaSink publishes each entry as a JSON message to a Kafka topic, keyed
+// by entry ID. A batch is acknowledged once every in-sync replica has
+// written it.
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}}
+}
+
+func (s *KafkaSink) Name() string {
+	return "kafka"
+}
+
+func (s *KafkaSink) Send(ctx context.Context, entries []*domain.AuditEntry) error {
+	messages, err := kafkaMessages(entries)
+	if err != nil {
+		return err
+	}
+	if err := s.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("write audit entries to kafka: %w", err)
+	}
+	return nil
+}
+
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
+
+func kafkaMessages(entries []*domain.AuditEntry) ([]kafka.Message, error) {
+	messages := make([]kafka.Message, 0, len(entries))
+	for _, entry := range entries {
+		value, err := json.Marshal(entry)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, kafka.Message{
+			Key:   []byte(strconv.FormatInt(entry.ID, 10)),
+			Value: value,
+			Time:  entry.OccurredAt,
+		})
+	}
+	return messages, nil
+}
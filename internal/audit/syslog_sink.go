@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// priority is facility "log audit" (13) at severity "informational" (6).
+const priority = 13*8 + 6
+
+// SyslogSink writes one RFC 5424 message per entry, with the entry as JSON in
+// the message body. Over TCP messages use octet-counting framing (RFC 6587),
+// so the batch is acknowledged once it is written to the connection; over
+// UDP each message is one datagram and delivery is best effort.
+type SyslogSink struct {
+	network  string
+	addr     string
+	appName  string
+	hostname string
+	dialer   net.Dialer
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func NewSyslogSink(network, addr, appName string) *SyslogSink {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogSink{
+		network:  network,
+		addr:     addr,
+		appName:  appName,
+		hostname: hostname,
+		dialer:   net.Dialer{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *SyslogSink) Name() string {
+	return "syslog"
+}
+
+func (s *SyslogSink) Send(ctx context.Context, entries []*domain.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		conn, err := s.dialer.DialContext(ctx, s.network, s.addr)
+		if err != nil {
+			return fmt.Errorf("syslog dial failed: %w", err)
+		}
+		s.conn = conn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		s.conn.SetWriteDeadline(deadline)
+	} else {
+		s.conn.SetWriteDeadline(time.Time{})
+	}
+
+	for _, entry := range entries {
+		msg, err := s.format(entry)
+		if err != nil {
+			return err
+		}
+		if s.network != "udp" {
+			msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+		}
+		if _, err := s.conn.Write(msg); err != nil {
+			// Drop the connection so the retry reconnects; the whole batch
+			// is resent, which at-least-once delivery allows.
+			s.conn.Close()
+			s.conn = nil
+			return fmt.Errorf("syslog write failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func (s *SyslogSink) format(entry *domain.AuditEntry) ([]byte, error) {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	header := fmt.Sprintf("<%d>1 %s %s %s - audit [audit@32473 id=\"%d\"] ",
+		priority, entry.OccurredAt.UTC().Format(time.RFC3339Nano), s.hostname, s.appName, entry.ID)
+	return append([]byte(header), body...), nil
+}
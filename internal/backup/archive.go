@@ -0,0 +1,204 @@
+package backup
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Archives are sealed in chunks so they can be encrypted and decrypted
+// while streaming. A header carries a random nonce prefix; each chunk's
+// nonce is that prefix, the chunk number and a final-chunk flag, so
+// reordered, dropped or truncated chunks fail authentication.
+const (
+	chunkSize   = 64 << 10
+	magic       = "PBAK"
+	version     = 1
+	prefixSize  = 7
+	headerSize  = len(magic) + 1 + prefixSize
+	maxChunks   = 1<<32 - 1
+	lastChunk   = 1
+	middleChunk = 0
+)
+
+var ErrCorrupt = errors.New("backup archive is corrupt, truncated or was sealed with another key")
+
+// ParseKey decodes a base64-encoded 32-byte AES-256 key.
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode backup key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("backup key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+type encrypter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	header  []byte
+	counter uint32
+	buf     []byte
+	sealed  []byte
+}
+
+// NewEncrypter returns a writer that seals everything written to it into
+// w. Close must be called to write the final chunk.
+func NewEncrypter(w io.Writer, key []byte) (io.WriteCloser, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	header[len(magic)] = version
+	if _, err := io.ReadFull(rand.Reader, header[len(magic)+1:]); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce prefix: %w", err)
+	}
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encrypter{
+		w:      w,
+		aead:   aead,
+		header: header,
+		buf:    make([]byte, 0, chunkSize),
+		sealed: make([]byte, 0, chunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (e *encrypter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// A full chunk is only sealed once more data arrives, so the last
+		// chunk is always sealed by Close with the final flag.
+		if len(e.buf) == chunkSize {
+			if err := e.seal(middleChunk); err != nil {
+				return written, err
+			}
+		}
+		n := copy(e.buf[len(e.buf):chunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (e *encrypter) Close() error {
+	return e.seal(lastChunk)
+}
+
+func (e *encrypter) seal(flag byte) error {
+	if e.counter == maxChunks {
+		return errors.New("backup archive too large")
+	}
+	e.sealed = e.aead.Seal(e.sealed[:0], chunkNonce(e.header, e.counter, flag), e.buf, e.header)
+	e.counter++
+	e.buf = e.buf[:0]
+	_, err := e.w.Write(e.sealed)
+	return err
+}
+
+type decrypter struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	header  []byte
+	counter uint32
+	chunk   []byte
+	plain   []byte
+	done    bool
+}
+
+// NewDecrypter returns a reader of the plaintext sealed in r. Reads fail
+// with ErrCorrupt if any chunk fails authentication or the stream ends
+// before its final chunk.
+func NewDecrypter(r io.Reader, key []byte) (io.Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrCorrupt
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, errors.New("not a backup archive")
+	}
+	if header[len(magic)] != version {
+		return nil, fmt.Errorf("unsupported backup archive version %d", header[len(magic)])
+	}
+
+	return &decrypter{
+		r:      bufio.NewReaderSize(r, chunkSize+aead.Overhead()+1),
+		aead:   aead,
+		header: header,
+		chunk:  make([]byte, chunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (d *decrypter) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decrypter) open() error {
+	n, err := io.ReadFull(d.r, d.chunk)
+	flag := byte(middleChunk)
+	switch {
+	case err == io.ErrUnexpectedEOF:
+		flag = lastChunk
+	case err == io.EOF:
+		return ErrCorrupt
+	case err != nil:
+		return err
+	default:
+		if _, peekErr := d.r.Peek(1); peekErr == io.EOF {
+			flag = lastChunk
+		}
+	}
+
+	plain, err := d.aead.Open(d.chunk[:0], chunkNonce(d.header, d.counter, flag), d.chunk[:n], d.header)
+	if err != nil {
+		return ErrCorrupt
+	}
+	d.counter++
+	d.plain = plain
+	d.done = flag == lastChunk
+	return nil
+}
+
+func chunkNonce(header []byte, counter uint32, flag byte) []byte {
+	nonce := make([]byte, 0, prefixSize+5)
+	nonce = append(nonce, header[len(magic)+1:]...)
+	nonce = binary.BigEndian.AppendUint32(nonce, counter)
+	return append(nonce, flag)
+}
@@ -0,0 +1,93 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(t *testing.T) []byte {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func seal(t *testing.T, key, plain []byte) []byte {
+	var sealed bytes.Buffer
+	enc, err := NewEncrypter(&sealed, key)
+	require.NoError(t, err)
+	_, err = enc.Write(plain)
+	require.NoError(t, err)
+	require.NoError(t, enc.Close())
+	return sealed.Bytes()
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	dec, err := NewDecrypter(bytes.NewReader(sealed), key)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(dec)
+}
+
+func TestArchive_RoundTrip(t *testing.T) {
+	key := testKey(t)
+
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 3*chunkSize + 17} {
+		plain := make([]byte, size)
+		_, err := rand.Read(plain)
+		require.NoError(t, err)
+
+		got, err := open(key, seal(t, key, plain))
+		require.NoError(t, err, "size %d", size)
+		assert.True(t, bytes.Equal(plain, got), "size %d", size)
+	}
+}
+
+func TestArchive_RejectsTampering(t *testing.T) {
+	key := testKey(t)
+	plain := make([]byte, 2*chunkSize+100)
+	sealed := seal(t, key, plain)
+	fullChunk := chunkSize + 16
+
+	tests := []struct {
+		name   string
+		key    []byte
+		sealed []byte
+	}{
+		{name: "wrong key", key: testKey(t), sealed: sealed},
+		{name: "flipped bit", key: key, sealed: func() []byte {
+			tampered := bytes.Clone(sealed)
+			tampered[headerSize+10] ^= 1
+			return tampered
+		}()},
+		{name: "truncated at chunk boundary", key: key, sealed: sealed[:headerSize+fullChunk]},
+		{name: "truncated mid chunk", key: key, sealed: sealed[:len(sealed)-5]},
+		{name: "missing final chunk", key: key, sealed: sealed[:headerSize+2*fullChunk]},
+		{name: "header only", key: key, sealed: sealed[:headerSize]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := open(tt.key, tt.sealed)
+			assert.ErrorIs(t, err, ErrCorrupt)
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	_, err := ParseKey(base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	assert.NoError(t, err)
+
+	_, err = ParseKey(base64.StdEncoding.EncodeToString(make([]byte, 16)))
+	assert.Error(t, err)
+
+	_, err = ParseKey("not base64!")
+	assert.Error(t, err)
+}
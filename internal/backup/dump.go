@@ -0,0 +1,250 @@
+// Package backup takes logical backups of the service's tables, restores
+// them and verifies that a backup restores cleanly.
+package backup
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Tables are dumped and restored in this order, which puts every table
+// after the tables its foreign keys reference.
+var Tables = []string{
+	"products",
+	"product_feeds",
+	"product_feed_runs",
+	"secrets",
+	"connectors",
+	"connector_checkpoints",
+	"connector_product_links",
+	"connector_syncs",
+	"product_trash",
+	"audit_logs",
+	"audit_export_cursors",
+	"two_factor_enrollments",
+}
+
+const formatVersion = 1
+
+// Counts is the number of rows per table.
+type Counts map[string]int64
+
+// record is one line of a dump: a header first, then one row per line
+// and a trailer with the row counts, so a dump cut short is detected.
+type record struct {
+	Version   int             `json:"version,omitempty"`
+	CreatedAt *time.Time      `json:"created_at,omitempty"`
+	Table     string          `json:"table,omitempty"`
+	Row       json.RawMessage `json:"row,omitempty"`
+	Counts    Counts          `json:"counts,omitempty"`
+}
+
+// Dump writes every row of Tables in schema to w as JSON lines. It reads
+// from one repeatable-read snapshot, so the dump is consistent while
+// writes continue. The header records createdAt as the dump's time.
+func Dump(ctx context.Context, db *sql.DB, schema string, createdAt time.Time, w io.Writer) (Counts, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin snapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	buffered := bufio.NewWriter(w)
+	enc := json.NewEncoder(buffered)
+
+	createdAt = createdAt.UTC()
+	if err := enc.Encode(record{Version: formatVersion, CreatedAt: &createdAt}); err != nil {
+		return nil, err
+	}
+
+	counts := make(Counts, len(Tables))
+	for _, table := range Tables {
+		n, err := dumpTable(ctx, tx, schema, table, enc)
+		if err != nil {
+			return nil, err
+		}
+		counts[table] = n
+	}
+
+	if err := enc.Encode(record{Counts: counts}); err != nil {
+		return nil, err
+	}
+	return counts, buffered.Flush()
+}
+
+func dumpTable(ctx context.Context, tx *sql.Tx, schema, table string, enc *json.Encoder) (int64, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT row_to_json(t)::text FROM `+qualified(schema, table)+` t`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to dump %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var n int64
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return n, fmt.Errorf("failed to dump %s: %w", table, err)
+		}
+		if err := enc.Encode(record{Table: table, Row: json.RawMessage(row)}); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return n, fmt.Errorf("failed to dump %s: %w", table, err)
+	}
+	return n, nil
+}
+
+// Restore loads a dump into the existing, empty tables of schema in one
+// transaction and moves their ID sequences past the restored rows.
+func Restore(ctx context.Context, db *sql.DB, schema string, r io.Reader) (Counts, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin restore: %w", err)
+	}
+	defer tx.Rollback()
+
+	counts, err := restoreRows(ctx, tx, schema, r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range Tables {
+		if err := resetSequence(ctx, tx, schema, table); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit restore: %w", err)
+	}
+	return counts, nil
+}
+
+// Verify restores a dump into a scratch schema shaped like schema, counts
+// the rows that landed and rolls everything back. It fails if any count
+// differs from what the dump recorded.
+func Verify(ctx context.Context, db *sql.DB, schema string, r io.Reader) (Counts, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin verification: %w", err)
+	}
+	defer tx.Rollback()
+
+	scratch := fmt.Sprintf("backup_verify_%d", time.Now().UnixNano())
+	if _, err := tx.ExecContext(ctx, `CREATE SCHEMA `+pq.QuoteIdentifier(scratch)); err != nil {
+		return nil, fmt.Errorf("failed to create scratch schema: %w", err)
+	}
+	for _, table := range Tables {
+		// Foreign keys are not copied by LIKE, which is fine for counting.
+		if _, err := tx.ExecContext(ctx, `CREATE TABLE `+qualified(scratch, table)+` (LIKE `+qualified(schema, table)+` INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`); err != nil {
+			return nil, fmt.Errorf("failed to create scratch %s: %w", table, err)
+		}
+	}
+
+	want, err := restoreRows(ctx, tx, scratch, r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range Tables {
+		var got int64
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+qualified(scratch, table)).Scan(&got); err != nil {
+			return nil, fmt.Errorf("failed to count scratch %s: %w", table, err)
+		}
+		if got != want[table] {
+			return nil, fmt.Errorf("verification failed: %s has %d rows, backup recorded %d", table, got, want[table])
+		}
+	}
+	return want, nil
+}
+
+// resetSequence moves the sequence behind table's serial id column, if it
+// has one, past the largest restored id.
+func resetSequence(ctx context.Context, tx *sql.Tx, schema, table string) error {
+	var seq sql.NullString
+	err := tx.QueryRowContext(ctx, `
+		SELECT pg_get_serial_sequence($1, attname)
+		FROM pg_attribute
+		WHERE attrelid = $1::regclass AND attname = 'id' AND NOT attisdropped
+	`, qualified(schema, table)).Scan(&seq)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !seq.Valid) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find %s ID sequence: %w", table, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `SELECT setval($1, COALESCE((SELECT MAX(id) FROM `+qualified(schema, table)+`), 0) + 1, false)`, seq.String)
+	if err != nil {
+		return fmt.Errorf("failed to reset %s ID sequence: %w", table, err)
+	}
+	return nil
+}
+
+// restoreRows inserts every row of the dump into schema and checks the
+// inserted counts against the dump's trailer.
+func restoreRows(ctx context.Context, tx *sql.Tx, schema string, r io.Reader) (Counts, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	var header record
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("failed to read backup header: %w", err)
+	}
+	if header.Version != formatVersion {
+		return nil, fmt.Errorf("unsupported backup format version %d", header.Version)
+	}
+
+	inserts := make(map[string]*sql.Stmt, len(Tables))
+	for _, table := range Tables {
+		target := qualified(schema, table)
+		stmt, err := tx.PrepareContext(ctx, `INSERT INTO `+target+` SELECT * FROM json_populate_record(NULL::`+target+`, $1)`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare %s restore: %w", table, err)
+		}
+		defer stmt.Close()
+		inserts[table] = stmt
+	}
+
+	restored := make(Counts, len(Tables))
+	for {
+		var rec record
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("backup ended without its trailer, it is truncated")
+			}
+			return nil, fmt.Errorf("failed to read backup: %w", err)
+		}
+
+		if rec.Counts != nil {
+			for _, table := range Tables {
+				if restored[table] != rec.Counts[table] {
+					return nil, fmt.Errorf("restored %d %s rows, backup recorded %d", restored[table], table, rec.Counts[table])
+				}
+			}
+			return restored, nil
+		}
+
+		stmt, ok := inserts[rec.Table]
+		if !ok {
+			return nil, fmt.Errorf("backup contains unknown table %q", rec.Table)
+		}
+		if _, err := stmt.ExecContext(ctx, string(rec.Row)); err != nil {
+			return nil, fmt.Errorf("failed to restore %s row: %w", rec.Table, err)
+		}
+		restored[rec.Table]++
+	}
+}
+
+func qualified(schema, table string) string {
+	return pq.QuoteIdentifier(schema) + "." + pq.QuoteIdentifier(table)
+}
@@ -0,0 +1,186 @@
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Store keeps backup archives, e.g. an S3 bucket.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+type Config struct {
+	// Schema holds the tables to back up, normally "public".
+	Schema string
+	// Prefix is prepended to archive object keys.
+	Prefix   string
+	Interval time.Duration
+	// Verify restores every scheduled backup into a scratch schema.
+	Verify bool
+}
+
+// Job takes compressed, encrypted backups into a Store and restores or
+// verifies them.
+type Job struct {
+	db     *sql.DB
+	store  Store
+	key    []byte
+	cfg    Config
+	logger *logrus.Logger
+	clock  clock.Clock
+}
+
+// NewJob seals archives with key, a 32-byte AES-256 key.
+func NewJob(db *sql.DB, store Store, key []byte, cfg Config, clk clock.Clock, logger *logrus.Logger) *Job {
+	return &Job{
+		db:     db,
+		store:  store,
+		key:    key,
+		cfg:    cfg,
+		logger: logger,
+		clock:  clk,
+	}
+}
+
+// Run backs up every interval until ctx is cancelled.
+func (j *Job) Run(ctx context.Context) {
+	j.logger.WithFields(logrus.Fields{"interval": j.cfg.Interval, "verify": j.cfg.Verify}).Info("Backup job started")
+
+	ticker := j.clock.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Backup job stopped")
+			return
+		case <-ticker.C():
+			if err := j.runOnce(ctx); err != nil && ctx.Err() == nil {
+				j.logger.WithError(err).Error("Scheduled backup failed")
+			}
+		}
+	}
+}
+
+func (j *Job) runOnce(ctx context.Context) error {
+	key, counts, err := j.Backup(ctx)
+	if err != nil {
+		return err
+	}
+	if !j.cfg.Verify {
+		return nil
+	}
+	if _, err := j.Verify(ctx, key); err != nil {
+		return fmt.Errorf("backup %s: %w", key, err)
+	}
+	j.logger.WithFields(logrus.Fields{"key": key, "counts": counts}).Info("Backup verified")
+	return nil
+}
+
+// Backup streams a dump through gzip and encryption into the store and
+// returns the object key and row counts.
+func (j *Job) Backup(ctx context.Context) (string, Counts, error) {
+	start := j.clock.Now()
+	key := j.cfg.Prefix + "product-db-" + start.UTC().Format("20060102T150405Z") + ".jsonl.gz.enc"
+
+	pr, pw := io.Pipe()
+	type result struct {
+		counts Counts
+		err    error
+	}
+	dumped := make(chan result, 1)
+	go func() {
+		counts, err := j.write(ctx, pw)
+		pw.CloseWithError(err)
+		dumped <- result{counts, err}
+	}()
+
+	putErr := j.store.Put(ctx, key, pr)
+	// Unblock the dump if the upload stopped reading early.
+	pr.CloseWithError(errors.New("upload stopped"))
+	res := <-dumped
+
+	if res.err != nil {
+		return "", nil, fmt.Errorf("failed to dump database: %w", res.err)
+	}
+	if putErr != nil {
+		return "", nil, fmt.Errorf("failed to upload backup: %w", putErr)
+	}
+
+	j.logger.WithFields(logrus.Fields{
+		"key":      key,
+		"counts":   res.counts,
+		"duration": j.clock.Now().Sub(start),
+	}).Info("Backup complete")
+	return key, res.counts, nil
+}
+
+func (j *Job) write(ctx context.Context, w io.Writer) (Counts, error) {
+	enc, err := NewEncrypter(w, j.key)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(enc)
+
+	counts, err := Dump(ctx, j.db, j.cfg.Schema, j.clock.Now(), gz)
+	if err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return counts, enc.Close()
+}
+
+// Restore loads the backup at key into the configured schema, whose
+// tables must exist and be empty.
+func (j *Job) Restore(ctx context.Context, key string) (Counts, error) {
+	var counts Counts
+	err := j.read(ctx, key, func(r io.Reader) (err error) {
+		counts, err = Restore(ctx, j.db, j.cfg.Schema, r)
+		return err
+	})
+	return counts, err
+}
+
+// Verify restores the backup at key into a scratch schema and row-counts
+// it without changing any data.
+func (j *Job) Verify(ctx context.Context, key string) (Counts, error) {
+	var counts Counts
+	err := j.read(ctx, key, func(r io.Reader) (err error) {
+		counts, err = Verify(ctx, j.db, j.cfg.Schema, r)
+		return err
+	})
+	return counts, err
+}
+
+func (j *Job) read(ctx context.Context, key string, fn func(io.Reader) error) error {
+	body, err := j.store.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to download backup: %w", err)
+	}
+	defer body.Close()
+
+	dec, err := NewDecrypter(body, j.key)
+	if err != nil {
+		return err
+	}
+	gz, err := gzip.NewReader(dec)
+	if err != nil {
+		return fmt.Errorf("failed to decompress backup: %w", err)
+	}
+	defer gz.Close()
+
+	return fn(gz)
+}
@@ -0,0 +1,89 @@
+package catalog
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Catalog struct {
+	Products []ProductSpec `yaml:"products"`
+}
+
+type ProductSpec struct {
+	StoreID           int64             `yaml:"store_id"`
+	Name              string            `yaml:"name"`
+	Description       string            `yaml:"description"`
+	DescriptionFormat string            `yaml:"description_format"`
+	Amount            quantity.Quantity `yaml:"amount"`
+	Unit              string            `yaml:"unit"`
+	Price             float64           `yaml:"price"`
+	Status            string            `yaml:"status"`
+}
+
+// Load decodes a catalog file. Unknown keys are rejected so typos surface as
+// errors instead of silently producing an empty plan.
+func Load(r io.Reader) (*Catalog, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var c Catalog
+	if err := decoder.Decode(&c); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("decode catalog: %w", err)
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *Catalog) Validate() error {
+	seen := make(map[productKey]bool, len(c.Products))
+	for i, spec := range c.Products {
+		if spec.Status == "" {
+			c.Products[i].Status = domain.ProductStatusActive
+		}
+		if spec.DescriptionFormat == "" {
+			c.Products[i].DescriptionFormat = domain.DescriptionFormatPlain
+		}
+		if spec.Unit == "" {
+			c.Products[i].Unit = domain.UnitPiece
+		}
+
+		product := spec.toDomain()
+		if err := product.Validate(); err != nil {
+			return fmt.Errorf("products[%d] %q: %w", i, spec.Name, err)
+		}
+
+		key := productKey{storeID: spec.StoreID, name: spec.Name}
+		if seen[key] {
+			return fmt.Errorf("products[%d]: duplicate product %q in store %d", i, spec.Name, spec.StoreID)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func (s ProductSpec) toDomain() *domain.Product {
+	return &domain.Product{
+		StoreID:           s.StoreID,
+		Name:              s.Name,
+		Description:       sql.NullString{String: s.Description, Valid: s.Description != ""},
+		DescriptionFormat: s.DescriptionFormat,
+		Amount:            s.Amount,
+		Unit:              s.Unit,
+		Price:             s.Price,
+		Status:            s.Status,
+	}
+}
+
+type productKey struct {
+	storeID int64
+	name    string
+}
@@ -0,0 +1,174 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"backend-context-engineering-template/pkg/client"
+)
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+type Change struct {
+	Action  Action
+	Spec    ProductSpec
+	Current *client.Product
+}
+
+type Plan struct {
+	Changes []Change
+}
+
+type ProductAPI interface {
+	CreateProduct(ctx context.Context, input client.ProductInput) (*client.Product, error)
+	UpdateProduct(ctx context.Context, id int64, input client.ProductInput) (*client.Product, error)
+	DeleteProduct(ctx context.Context, id int64) error
+}
+
+// BuildPlan diffs the desired catalog against live products. Products are
+// matched by store and name, and only stores that appear in the catalog are
+// managed: live products in other stores are left untouched.
+func BuildPlan(desired *Catalog, live []client.Product) *Plan {
+	managed := make(map[int64]bool)
+	wanted := make(map[productKey]bool, len(desired.Products))
+	for _, spec := range desired.Products {
+		managed[spec.StoreID] = true
+		wanted[productKey{storeID: spec.StoreID, name: spec.Name}] = true
+	}
+
+	current := make(map[productKey]*client.Product, len(live))
+	plan := &Plan{}
+	for i := range live {
+		product := &live[i]
+		if !managed[product.StoreID] {
+			continue
+		}
+
+		key := productKey{storeID: product.StoreID, name: product.Name}
+		if _, exists := current[key]; exists || !wanted[key] {
+			plan.Changes = append(plan.Changes, Change{Action: ActionDelete, Current: product})
+			continue
+		}
+		current[key] = product
+	}
+
+	for _, spec := range desired.Products {
+		existing, ok := current[productKey{storeID: spec.StoreID, name: spec.Name}]
+		switch {
+		case !ok:
+			plan.Changes = append(plan.Changes, Change{Action: ActionCreate, Spec: spec})
+		case differs(existing, spec):
+			plan.Changes = append(plan.Changes, Change{Action: ActionUpdate, Spec: spec, Current: existing})
+		}
+	}
+
+	sort.SliceStable(plan.Changes, func(i, j int) bool {
+		return plan.Changes[i].storeID() < plan.Changes[j].storeID()
+	})
+	return plan
+}
+
+func (p *Plan) IsEmpty() bool {
+	return len(p.Changes) == 0
+}
+
+func (p *Plan) Write(w io.Writer) {
+	counts := make(map[Action]int)
+	for _, change := range p.Changes {
+		counts[change.Action]++
+		switch change.Action {
+		case ActionCreate:
+			fmt.Fprintf(w, "  + store %d: %q (amount=%s %s price=%.2f status=%s)\n",
+				change.Spec.StoreID, change.Spec.Name, change.Spec.Amount, change.Spec.Unit, change.Spec.Price, change.Spec.Status)
+		case ActionUpdate:
+			fmt.Fprintf(w, "  ~ store %d: %q #%d\n", change.Spec.StoreID, change.Spec.Name, change.Current.ID)
+			writeFieldDiff(w, change.Current, change.Spec)
+		case ActionDelete:
+			fmt.Fprintf(w, "  - store %d: %q #%d\n", change.Current.StoreID, change.Current.Name, change.Current.ID)
+		}
+	}
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to delete.\n",
+		counts[ActionCreate], counts[ActionUpdate], counts[ActionDelete])
+}
+
+// Apply executes the plan in order and stops at the first failure, returning
+// the number of changes that were applied.
+func (p *Plan) Apply(ctx context.Context, api ProductAPI) (int, error) {
+	for i, change := range p.Changes {
+		var err error
+		switch change.Action {
+		case ActionCreate:
+			_, err = api.CreateProduct(ctx, change.Spec.toInput())
+		case ActionUpdate:
+			// Sending the version the plan was built from refuses the
+			// update if the product was changed since.
+			input := change.Spec.toInput()
+			input.Version = change.Current.Version
+			_, err = api.UpdateProduct(ctx, change.Current.ID, input)
+		case ActionDelete:
+			err = api.DeleteProduct(ctx, change.Current.ID)
+		}
+		if err != nil {
+			return i, fmt.Errorf("%s product: %w", change.Action, err)
+		}
+	}
+	return len(p.Changes), nil
+}
+
+func (c Change) storeID() int64 {
+	if c.Current != nil {
+		return c.Current.StoreID
+	}
+	return c.Spec.StoreID
+}
+
+func (s ProductSpec) toInput() client.ProductInput {
+	return client.ProductInput{
+		StoreID:           s.StoreID,
+		Name:              s.Name,
+		Description:       s.Description,
+		DescriptionFormat: s.DescriptionFormat,
+		Amount:            s.Amount,
+		Unit:              s.Unit,
+		Price:             s.Price,
+		Status:            s.Status,
+	}
+}
+
+func differs(current *client.Product, spec ProductSpec) bool {
+	return current.Description != spec.Description ||
+		current.DescriptionFormat != spec.DescriptionFormat ||
+		current.Amount != spec.Amount ||
+		current.Unit != spec.Unit ||
+		current.Price != spec.Price ||
+		current.Status != spec.Status
+}
+
+func writeFieldDiff(w io.Writer, current *client.Product, spec ProductSpec) {
+	if current.Description != spec.Description {
+		fmt.Fprintf(w, "      description: %q -> %q\n", current.Description, spec.Description)
+	}
+	if current.DescriptionFormat != spec.DescriptionFormat {
+		fmt.Fprintf(w, "      description_format: %s -> %s\n", current.DescriptionFormat, spec.DescriptionFormat)
+	}
+	if current.Amount != spec.Amount {
+		fmt.Fprintf(w, "      amount: %s -> %s\n", current.Amount, spec.Amount)
+	}
+	if current.Unit != spec.Unit {
+		fmt.Fprintf(w, "      unit: %s -> %s\n", current.Unit, spec.Unit)
+	}
+	if current.Price != spec.Price {
+		fmt.Fprintf(w, "      price: %.2f -> %.2f\n", current.Price, spec.Price)
+	}
+	if current.Status != spec.Status {
+		fmt.Fprintf(w, "      status: %s -> %s\n", current.Status, spec.Status)
+	}
+}
@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"backend-context-engineering-template/pkg/client"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockProductAPI struct {
+	mock.Mock
+}
+
+func (m *MockProductAPI) CreateProduct(ctx context.Context, input client.ProductInput) (*client.Product, error) {
+	args := m.Called(ctx, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*client.Product), args.Error(1)
+}
+
+func (m *MockProductAPI) UpdateProduct(ctx context.Context, id int64, input client.ProductInput) (*client.Product, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*client.Product), args.Error(1)
+}
+
+func (m *MockProductAPI) DeleteProduct(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+const testCatalog = `
+products:
+  - store_id: 1
+    name: Shirt
+    amount: 10
+    price: 19.99
+  - store_id: 1
+    name: Hat
+    description: Wool
+    amount: 5
+    price: 9.5
+`
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "valid catalog", input: testCatalog},
+		{name: "empty file", input: ""},
+		{name: "unknown key", input: "products:\n  - store_id: 1\n    nmae: Shirt\n", wantErr: true},
+		{name: "invalid product", input: "products:\n  - store_id: 1\n    name: Shirt\n    price: 0\n", wantErr: true},
+		{name: "weighed product", input: "products:\n  - {store_id: 1, name: Flour, amount: 2.5, unit: kg, price: 3}\n"},
+		{name: "fractional pieces", input: "products:\n  - {store_id: 1, name: Shirt, amount: 2.5, price: 3}\n", wantErr: true},
+		{name: "duplicate product", input: "products:\n  - {store_id: 1, name: A, price: 1}\n  - {store_id: 1, name: A, price: 2}\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := Load(strings.NewReader(tt.input))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, spec := range c.Products {
+				assert.Equal(t, "active", spec.Status)
+			}
+		})
+	}
+}
+
+func TestBuildPlan(t *testing.T) {
+	desired, err := Load(strings.NewReader(testCatalog))
+	require.NoError(t, err)
+
+	live := []client.Product{
+		{ID: 1, StoreID: 1, Name: "Shirt", Amount: quantity.New(10), Unit: "piece", Price: 19.99, Status: "active", DescriptionFormat: "plain"},
+		{ID: 2, StoreID: 1, Name: "Hat", Amount: quantity.New(1), Unit: "piece", Price: 9.5, Status: "active", DescriptionFormat: "plain"},
+		{ID: 3, StoreID: 1, Name: "Scarf", Amount: quantity.New(1), Price: 5, Status: "active"},
+		{ID: 4, StoreID: 2, Name: "Unmanaged", Amount: quantity.New(1), Price: 5, Status: "active"},
+	}
+
+	plan := BuildPlan(desired, live)
+
+	require.Len(t, plan.Changes, 2)
+	assert.Equal(t, ActionDelete, plan.Changes[0].Action)
+	assert.Equal(t, int64(3), plan.Changes[0].Current.ID)
+	assert.Equal(t, ActionUpdate, plan.Changes[1].Action)
+	assert.Equal(t, int64(2), plan.Changes[1].Current.ID)
+
+	var out bytes.Buffer
+	plan.Write(&out)
+	assert.Contains(t, out.String(), "Plan: 0 to create, 1 to update, 1 to delete.")
+	assert.Contains(t, out.String(), `description: "" -> "Wool"`)
+}
+
+func TestPlan_Apply(t *testing.T) {
+	plan := &Plan{Changes: []Change{
+		{Action: ActionCreate, Spec: ProductSpec{StoreID: 1, Name: "New", Price: 1, Status: "active"}},
+		{Action: ActionUpdate, Spec: ProductSpec{StoreID: 1, Name: "Hat", Price: 2, Status: "active"}, Current: &client.Product{ID: 6, Version: 4}},
+		{Action: ActionDelete, Current: &client.Product{ID: 7}},
+		{Action: ActionDelete, Current: &client.Product{ID: 8}},
+	}}
+
+	api := &MockProductAPI{}
+	api.On("CreateProduct", mock.Anything, mock.Anything).Return(&client.Product{ID: 9}, nil)
+	api.On("UpdateProduct", mock.Anything, int64(6), mock.MatchedBy(func(input client.ProductInput) bool {
+		return input.Version == 4
+	})).Return(&client.Product{ID: 6, Version: 5}, nil)
+	api.On("DeleteProduct", mock.Anything, int64(7)).Return(errors.New("boom"))
+
+	applied, err := plan.Apply(context.Background(), api)
+	assert.Error(t, err)
+	assert.Equal(t, 2, applied)
+	api.AssertExpectations(t)
+	api.AssertNotCalled(t, "DeleteProduct", mock.Anything, int64(8))
+}
@@ -0,0 +1,93 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxTagsPerPurge is how many cache tags Cloudflare purges in one call.
+const maxTagsPerPurge = 30
+
+// CloudflareClient purges a Cloudflare zone's cached responses by cache
+// tag, Cloudflare's name for surrogate keys.
+type CloudflareClient struct {
+	client  *http.Client
+	baseURL string
+	zoneID  string
+	token   string
+}
+
+// NewCloudflareClient calls the Cloudflare API at baseURL, normally
+// https://api.cloudflare.com/client/v4, with an API token allowed to purge
+// the cache of zoneID.
+func NewCloudflareClient(client *http.Client, baseURL, zoneID, token string) *CloudflareClient {
+	return &CloudflareClient{
+		client:  client,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		zoneID:  zoneID,
+		token:   token,
+	}
+}
+
+// Purge removes the responses tagged with any of keys, so the CDN fetches
+// them again on their next request.
+func (c *CloudflareClient) Purge(ctx context.Context, keys []string) error {
+	for start := 0; start < len(keys); start += maxTagsPerPurge {
+		end := start + maxTagsPerPurge
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if err := c.purge(ctx, keys[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (c *CloudflareClient) purge(ctx context.Context, keys []string) error {
+	body, err := json.Marshal(map[string][]string{"tags": keys})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/zones/"+c.zoneID+"/purge_cache", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cloudflare purge request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result cloudflareResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&result)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if decodeErr == nil && len(result.Errors) > 0 {
+			return fmt.Errorf("cloudflare purge returned status %d: %s", resp.StatusCode, result.Errors[0].Message)
+		}
+		return fmt.Errorf("cloudflare purge returned status %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return fmt.Errorf("failed to decode cloudflare purge response: %w", decodeErr)
+	}
+	if !result.Success {
+		return errors.New("cloudflare purge was not successful")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxKeysPerPurge is how many surrogate keys Fastly purges in one call.
+const maxKeysPerPurge = 256
+
+// FastlyClient purges a Fastly service's cached responses by surrogate
+// key.
+type FastlyClient struct {
+	client    *http.Client
+	baseURL   string
+	serviceID string
+	token     string
+}
+
+// NewFastlyClient calls the Fastly API at baseURL, normally
+// https://api.fastly.com, with an API token allowed to purge serviceID.
+func NewFastlyClient(client *http.Client, baseURL, serviceID, token string) *FastlyClient {
+	return &FastlyClient{
+		client:    client,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		serviceID: serviceID,
+		token:     token,
+	}
+}
+
+// Purge marks the responses tagged with any of keys as stale, so the CDN
+// fetches them again on their next request.
+func (c *FastlyClient) Purge(ctx context.Context, keys []string) error {
+	for start := 0; start < len(keys); start += maxKeysPerPurge {
+		end := start + maxKeysPerPurge
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if err := c.purge(ctx, keys[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *FastlyClient) purge(ctx context.Context, keys []string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/service/"+c.serviceID+"/purge", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Fastly-Key", c.token)
+	req.Header.Set("Surrogate-Key", strings.Join(keys, " "))
+	// Soft purges serve the stale response while it is fetched again,
+	// sparing the origin a stampede after a busy product changes.
+	req.Header.Set("Fastly-Soft-Purge", "1")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fastly purge request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("fastly purge returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+// Package cdn keeps responses cached by a CDN in step with the catalog.
+// Cacheable responses are tagged with surrogate keys (see the router's
+// CachePolicies) and the Purger purges those keys when products change,
+// through a Client for Fastly or Cloudflare.
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ProductListKey tags the product list responses that are not limited to
+// one store.
+const ProductListKey = "products"
+
+// ProductKey tags the responses that show the product with id, including
+// the list pages it is on.
+func ProductKey(id int64) string {
+	return "product:" + strconv.FormatInt(id, 10)
+}
+
+// StoreKey tags the product list responses limited to the store with id.
+func StoreKey(id int64) string {
+	return "store:" + strconv.FormatInt(id, 10)
+}
+
+// Client purges cached responses by surrogate key.
+type Client interface {
+	Purge(ctx context.Context, keys []string) error
+}
+
+// Config controls how purges are batched.
+type Config struct {
+	// Window is how long changes are collected before their keys are
+	// purged, so a burst of writes costs one purge call.
+	Window time.Duration
+	// MaxPending caps the keys held in memory between purges.
+	MaxPending int
+	// MaxAttempts is how often a purge is tried within a flush, waiting
+	// RetryBackoff, then twice that, between attempts. Keys whose purge
+	// still fails wait for the next flush.
+	MaxAttempts  int
+	RetryBackoff time.Duration
+}
+
+// Purger purges the surrogate keys of changed products. It is a product
+// event sink; keys are collected in memory and purged every Window, so
+// publishing never waits on the CDN.
+type Purger struct {
+	client Client
+	cfg    Config
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	pending  map[string]struct{}
+	dropping bool
+
+	purges  *telemetry.Counter
+	retries *telemetry.Counter
+}
+
+func NewPurger(client Client, registry *telemetry.Registry, cfg Config, clk clock.Clock, logger *logrus.Logger) *Purger {
+	return &Purger{
+		client:  client,
+		cfg:     cfg,
+		logger:  logger,
+		clock:   clk,
+		pending: make(map[string]struct{}),
+		purges:  registry.NewCounter("cdn_purge_keys_total", "Surrogate keys sent to the CDN to purge, by result.", "result"),
+		retries: registry.NewCounter("cdn_purge_retries_total", "CDN purge calls tried again after failing."),
+	}
+}
+
+// Publish queues the keys of the responses that event makes stale: the
+// product's own, which include the list pages already showing it, and,
+// since any change can move it onto a list, its store's lists and the
+// lists across stores.
+func (p *Purger) Publish(ctx context.Context, event domain.ProductEvent) {
+	keys := []string{ProductKey(event.ProductID), ProductListKey}
+	if event.StoreID > 0 {
+		keys = append(keys, StoreKey(event.StoreID))
+	}
+	p.add(keys...)
+}
+
+func (p *Purger) add(keys ...string) {
+	p.mu.Lock()
+	for _, key := range keys {
+		if _, ok := p.pending[key]; ok {
+			continue
+		}
+		if len(p.pending) >= p.cfg.MaxPending {
+			warn := !p.dropping
+			p.dropping = true
+			p.mu.Unlock()
+
+			p.purges.Inc("dropped")
+			if warn {
+				p.logger.WithField("max_pending", p.cfg.MaxPending).Warn("CDN purge buffer is full, dropping keys")
+			}
+			return
+		}
+		p.pending[key] = struct{}{}
+	}
+	p.mu.Unlock()
+}
+
+// Run purges queued keys every Window until ctx is cancelled, then purges
+// what is left.
+func (p *Purger) Run(ctx context.Context) {
+	p.logger.WithField("window", p.cfg.Window).Info("CDN purger started")
+
+	ticker := p.clock.NewTicker(p.cfg.Window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := p.Flush(context.WithoutCancel(ctx)); err != nil {
+				p.logger.WithError(err).Error("Failed to purge CDN keys on shutdown")
+			}
+			p.logger.Info("CDN purger stopped")
+			return
+		case <-ticker.C():
+			if err := p.Flush(ctx); err != nil && ctx.Err() == nil {
+				p.logger.WithError(err).Error("Failed to purge CDN keys")
+			}
+		}
+	}
+}
+
+// Flush purges the queued keys. On failure they are kept for the next
+// flush.
+func (p *Purger) Flush(ctx context.Context) error {
+	p.mu.Lock()
+	flushed := p.pending
+	p.pending = make(map[string]struct{})
+	p.dropping = false
+	p.mu.Unlock()
+
+	if len(flushed) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(flushed))
+	for key := range flushed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if err := p.purge(ctx, keys); err != nil {
+		p.purges.Add(float64(len(keys)), "failed")
+		p.add(keys...)
+		return fmt.Errorf("failed to purge %d CDN keys: %w", len(keys), err)
+	}
+	p.purges.Add(float64(len(keys)), "purged")
+	return nil
+}
+
+func (p *Purger) purge(ctx context.Context, keys []string) error {
+	backoff := p.cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := p.client.Purge(ctx, keys)
+		if err == nil {
+			return nil
+		}
+		if attempt >= p.cfg.MaxAttempts || ctx.Err() != nil || !p.sleep(ctx, backoff) {
+			return err
+		}
+		p.retries.Inc()
+		backoff *= 2
+	}
+}
+
+func (p *Purger) sleep(ctx context.Context, duration time.Duration) bool {
+	if duration <= 0 {
+		return true
+	}
+	ticker := p.clock.NewTicker(duration)
+	defer ticker.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-ticker.C():
+		return true
+	}
+}
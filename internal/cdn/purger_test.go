@@ -0,0 +1,228 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastlyServer records the surrogate keys of each purge it is sent.
+type fastlyServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	purges   [][]string
+	status   int
+	failures int
+}
+
+func newFastlyServer(t *testing.T) *fastlyServer {
+	s := &fastlyServer{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/service/svc-1/purge", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("Fastly-Key"))
+		assert.Equal(t, "1", r.Header.Get("Fastly-Soft-Purge"))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.purges = append(s.purges, strings.Fields(r.Header.Get("Surrogate-Key")))
+		if s.failures > 0 {
+			s.failures--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(s.status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fastlyServer) fail(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+// failNext answers the next n purges with 503.
+func (s *fastlyServer) failNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+}
+
+func (s *fastlyServer) sent() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]string(nil), s.purges...)
+}
+
+func newTestPurger(t *testing.T, clk clock.Clock) (*Purger, *fastlyServer, *telemetry.Registry) {
+	server := newFastlyServer(t)
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	client := NewFastlyClient(server.Client(), server.URL+"/", "svc-1", "secret")
+	purger := NewPurger(client, registry, Config{Window: time.Second, MaxPending: 100, MaxAttempts: 1}, clk, logrus.New())
+	return purger, server, registry
+}
+
+func metrics(t *testing.T, registry *telemetry.Registry) string {
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	return buf.String()
+}
+
+func TestPurger_PurgesChangedProductsOnce(t *testing.T) {
+	purger, server, registry := newTestPurger(t, clock.Real())
+	ctx := context.Background()
+
+	purger.Publish(ctx, domain.ProductEvent{Type: domain.ProductEventUpdated, StoreID: 1, ProductID: 10})
+	purger.Publish(ctx, domain.ProductEvent{Type: domain.StockEventChanged, StoreID: 1, ProductID: 10})
+	purger.Publish(ctx, domain.ProductEvent{Type: domain.ProductEventDeleted, StoreID: 2, ProductID: 7})
+	require.NoError(t, purger.Flush(ctx))
+	require.NoError(t, purger.Flush(ctx))
+
+	assert.Equal(t, [][]string{{"product:10", "product:7", "products", "store:1", "store:2"}}, server.sent())
+	assert.Contains(t, metrics(t, registry), `cdn_purge_keys_total{result="purged"} 5`)
+}
+
+func TestPurger_RetriesFailedPurges(t *testing.T) {
+	purger, server, registry := newTestPurger(t, clock.Real())
+	ctx := context.Background()
+
+	server.fail(http.StatusServiceUnavailable)
+	purger.Publish(ctx, domain.ProductEvent{Type: domain.ProductEventCreated, ProductID: 1})
+	assert.ErrorContains(t, purger.Flush(ctx), "status 503")
+
+	server.fail(http.StatusOK)
+	purger.Publish(ctx, domain.ProductEvent{Type: domain.ProductEventUpdated, ProductID: 2})
+	require.NoError(t, purger.Flush(ctx))
+
+	sent := server.sent()
+	require.Len(t, sent, 2)
+	assert.Equal(t, []string{"product:1", "product:2", "products"}, sent[1])
+	assert.Contains(t, metrics(t, registry), `cdn_purge_keys_total{result="failed"} 2`)
+}
+
+func TestPurger_RetriesWithinAFlush(t *testing.T) {
+	purger, server, registry := newTestPurger(t, clock.Real())
+	purger.cfg.MaxAttempts = 3
+	purger.cfg.RetryBackoff = time.Millisecond
+	ctx := context.Background()
+
+	server.failNext(2)
+	purger.Publish(ctx, domain.ProductEvent{Type: domain.ProductEventUpdated, StoreID: 4, ProductID: 1})
+	require.NoError(t, purger.Flush(ctx))
+
+	assert.Len(t, server.sent(), 3)
+	assert.Empty(t, purger.pending)
+	out := metrics(t, registry)
+	assert.Contains(t, out, `cdn_purge_retries_total 2`)
+	assert.Contains(t, out, `cdn_purge_keys_total{result="purged"} 3`)
+}
+
+func TestPurger_DropsWhenFull(t *testing.T) {
+	purger, _, registry := newTestPurger(t, clock.Real())
+	purger.cfg.MaxPending = 3
+	ctx := context.Background()
+
+	for id := int64(1); id <= 3; id++ {
+		purger.Publish(ctx, domain.ProductEvent{Type: domain.ProductEventUpdated, ProductID: id})
+	}
+
+	assert.Len(t, purger.pending, 3)
+	assert.Contains(t, metrics(t, registry), `cdn_purge_keys_total{result="dropped"} 1`)
+}
+
+func TestPurger_RunPurgesEveryWindowAndOnShutdown(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	purger, server, _ := newTestPurger(t, clk)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		purger.Run(ctx)
+	}()
+	clk.BlockUntilTickers(1)
+
+	purger.Publish(ctx, domain.ProductEvent{Type: domain.ProductEventUpdated, ProductID: 1})
+	clk.Advance(time.Second)
+	assert.Eventually(t, func() bool { return len(server.sent()) == 1 }, time.Second, time.Millisecond)
+
+	purger.Publish(ctx, domain.ProductEvent{Type: domain.ProductEventUpdated, ProductID: 2})
+	cancel()
+	<-done
+	assert.Equal(t, [][]string{{"product:1", "products"}, {"product:2", "products"}}, server.sent())
+}
+
+func TestFastlyClient_PurgesInChunks(t *testing.T) {
+	server := newFastlyServer(t)
+	client := NewFastlyClient(server.Client(), server.URL, "svc-1", "secret")
+
+	keys := make([]string, maxKeysPerPurge+1)
+	for i := range keys {
+		keys[i] = ProductKey(int64(i))
+	}
+	require.NoError(t, client.Purge(context.Background(), keys))
+
+	sent := server.sent()
+	require.Len(t, sent, 2)
+	assert.Len(t, sent[0], maxKeysPerPurge)
+	assert.Equal(t, []string{ProductKey(maxKeysPerPurge)}, sent[1])
+}
+
+func TestCloudflareClient_PurgesTagsInChunks(t *testing.T) {
+	var mu sync.Mutex
+	var purges [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/client/v4/zones/zone-1/purge_cache", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var body struct {
+			Tags []string `json:"tags"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		purges = append(purges, body.Tags)
+		mu.Unlock()
+		w.Write([]byte(`{"success":true,"errors":[]}`))
+	}))
+	defer server.Close()
+	client := NewCloudflareClient(server.Client(), server.URL+"/client/v4/", "zone-1", "secret")
+
+	keys := make([]string, maxTagsPerPurge+1)
+	for i := range keys {
+		keys[i] = ProductKey(int64(i))
+	}
+	require.NoError(t, client.Purge(context.Background(), keys))
+
+	require.Len(t, purges, 2)
+	assert.Len(t, purges[0], maxTagsPerPurge)
+	assert.Equal(t, []string{ProductKey(maxTagsPerPurge)}, purges[1])
+}
+
+func TestCloudflareClient_ReportsAPIErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"success":false,"errors":[{"code":1012,"message":"Request must contain one of purge_everything, files, tags"}]}`))
+	}))
+	defer server.Close()
+	client := NewCloudflareClient(server.Client(), server.URL, "zone-1", "secret")
+
+	err := client.Purge(context.Background(), []string{ProductListKey})
+	assert.ErrorContains(t, err, "status 400: Request must contain one of")
+}
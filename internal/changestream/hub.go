@@ -0,0 +1,136 @@
+// Package changestream pushes product changes to the clients connected to
+// GET /api/v1/products/stream as they happen, so dashboards need not poll
+// the list. A Hub holds this instance's subscriptions and is a product
+// event sink. The outbox relay hands each event to the sinks of one
+// instance only, so with Redis a RedisBroadcaster carries events to the
+// hubs of all of them.
+package changestream
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/telemetry"
+)
+
+// Types are the event types streamed.
+var Types = []string{domain.ProductEventCreated, domain.ProductEventUpdated, domain.ProductEventDeleted}
+
+var (
+	ErrTooManySubscribers = errors.New("too many clients are subscribed to the stream")
+	ErrClosed             = errors.New("the stream is closed")
+)
+
+// Config sizes the hub.
+type Config struct {
+	// Buffer is how many events a subscription holds for a client that
+	// has not read them yet. A client that falls further behind is
+	// disconnected, so it reloads rather than silently missing changes.
+	Buffer int
+	// MaxSubscribers caps the subscriptions held at once.
+	MaxSubscribers int
+}
+
+// Hub hands events to the subscriptions of this instance. Publishing never
+// waits on a client.
+type Hub struct {
+	cfg Config
+
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+
+	disconnects *telemetry.Counter
+}
+
+func NewHub(registry *telemetry.Registry, cfg Config) *Hub {
+	h := &Hub{
+		cfg:         cfg,
+		subs:        make(map[*Subscription]struct{}),
+		disconnects: registry.NewCounter("product_stream_disconnects_total", "Stream clients disconnected for falling behind."),
+	}
+	registry.RegisterGaugeFunc("product_stream_subscribers", "Clients subscribed to the product stream.", func() []telemetry.Sample {
+		return []telemetry.Sample{{Value: float64(h.Subscribers())}}
+	})
+	return h
+}
+
+// Subscribers returns how many subscriptions are open.
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Subscription receives the events published after it was made.
+type Subscription struct {
+	hub    *Hub
+	events chan domain.ProductEvent
+}
+
+// Subscribe adds a subscription. The caller must Close it.
+func (h *Hub) Subscribe() (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrClosed
+	}
+	if len(h.subs) >= h.cfg.MaxSubscribers {
+		return nil, ErrTooManySubscribers
+	}
+	sub := &Subscription{hub: h, events: make(chan domain.ProductEvent, h.cfg.Buffer)}
+	h.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// Events delivers the subscription's events. It is closed when the
+// subscription fell behind or the hub closed.
+func (s *Subscription) Events() <-chan domain.ProductEvent {
+	return s.events
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// Publish hands a streamed event to every subscription.
+func (h *Hub) Publish(ctx context.Context, event domain.ProductEvent) {
+	if !slices.Contains(Types, event.Type) {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub)
+			h.disconnects.Inc()
+		}
+	}
+}
+
+// Close ends every subscription and refuses new ones, so the streams end
+// and do not hold up the server's shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for sub := range h.subs {
+		h.remove(sub)
+	}
+}
+
+// remove drops sub and closes its events; h.mu is held.
+func (h *Hub) remove(sub *Subscription) {
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.events)
+	}
+}
@@ -0,0 +1,124 @@
+package changestream
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHub(cfg Config) *Hub {
+	return NewHub(telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil)), cfg)
+}
+
+func TestHub_Publish(t *testing.T) {
+	hub := newTestHub(Config{Buffer: 4, MaxSubscribers: 2})
+	first, err := hub.Subscribe()
+	require.NoError(t, err)
+	defer first.Close()
+	second, err := hub.Subscribe()
+	require.NoError(t, err)
+	defer second.Close()
+
+	_, err = hub.Subscribe()
+	assert.ErrorIs(t, err, ErrTooManySubscribers)
+
+	hub.Publish(context.Background(), domain.ProductEvent{ID: "1", Type: domain.StockEventChanged})
+	hub.Publish(context.Background(), domain.ProductEvent{ID: "2", Type: domain.ProductEventUpdated})
+
+	for _, sub := range []*Subscription{first, second} {
+		event := <-sub.Events()
+		assert.Equal(t, "2", event.ID, "only created, updated and deleted events are streamed")
+		assert.Empty(t, sub.Events())
+	}
+}
+
+func TestHub_DisconnectsSlowSubscribers(t *testing.T) {
+	hub := newTestHub(Config{Buffer: 1, MaxSubscribers: 2})
+	slow, err := hub.Subscribe()
+	require.NoError(t, err)
+	defer slow.Close()
+	fast, err := hub.Subscribe()
+	require.NoError(t, err)
+	defer fast.Close()
+
+	hub.Publish(context.Background(), domain.ProductEvent{ID: "1", Type: domain.ProductEventCreated})
+	<-fast.Events()
+	hub.Publish(context.Background(), domain.ProductEvent{ID: "2", Type: domain.ProductEventDeleted})
+
+	assert.Equal(t, "1", (<-slow.Events()).ID)
+	_, open := <-slow.Events()
+	assert.False(t, open, "a subscriber that fell behind is disconnected")
+	assert.Equal(t, "2", (<-fast.Events()).ID)
+
+	// Its place is free again.
+	another, err := hub.Subscribe()
+	require.NoError(t, err)
+	another.Close()
+}
+
+func TestHub_Close(t *testing.T) {
+	hub := newTestHub(Config{Buffer: 1, MaxSubscribers: 1})
+	sub, err := hub.Subscribe()
+	require.NoError(t, err)
+
+	hub.Close()
+	_, open := <-sub.Events()
+	assert.False(t, open)
+	sub.Close()
+
+	_, err = hub.Subscribe()
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestRedisBroadcaster(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	hub := newTestHub(Config{Buffer: 4, MaxSubscribers: 1})
+	sub, err := hub.Subscribe()
+	require.NoError(t, err)
+	defer sub.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- NewRedisBroadcaster(client, "test:stream", logger).Subscribe(ctx, hub)
+	}()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+	require.Eventually(t, func() bool {
+		return server.PubSubNumSub("test:stream")["test:stream"] == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// Another instance's relay publishes the event.
+	event := domain.ProductEvent{
+		ID:        "0192",
+		Type:      domain.ProductEventUpdated,
+		StoreID:   3,
+		ProductID: 7,
+		Product:   &domain.ProductEventData{ID: 7, StoreID: 3, Name: "Rye Flour", Status: domain.ProductStatusDraft},
+	}
+	NewRedisBroadcaster(client, "test:stream", logger).Publish(ctx, event)
+
+	select {
+	case got := <-sub.Events():
+		assert.Equal(t, event, got)
+	case <-time.After(time.Second):
+		t.Fatal("the broadcast event did not reach the hub")
+	}
+}
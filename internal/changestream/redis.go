@@ -0,0 +1,69 @@
+package changestream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// RedisBroadcaster carries streamed events over a Redis channel to the hub
+// of every instance, this one included. Events broadcast while an
+// instance's subscription is down do not reach its clients.
+type RedisBroadcaster struct {
+	client  redis.UniversalClient
+	channel string
+	logger  *logrus.Logger
+}
+
+func NewRedisBroadcaster(client redis.UniversalClient, channel string, logger *logrus.Logger) *RedisBroadcaster {
+	return &RedisBroadcaster{client: client, channel: channel, logger: logger}
+}
+
+// Publish broadcasts a streamed event. One that cannot be sent is logged
+// and reaches no client.
+func (b *RedisBroadcaster) Publish(ctx context.Context, event domain.ProductEvent) {
+	if !slices.Contains(Types, event.Type) {
+		return
+	}
+	data, err := json.Marshal(event)
+	if err == nil {
+		err = b.client.Publish(ctx, b.channel, data).Err()
+	}
+	if err != nil {
+		b.logger.WithError(err).WithFields(logrus.Fields{"event_id": event.ID, "event_type": event.Type}).Warn("Failed to broadcast product event to streams")
+	}
+}
+
+// Subscribe hands every broadcast event to hub until ctx is done.
+func (b *RedisBroadcaster) Subscribe(ctx context.Context, hub *Hub) error {
+	sub := b.client.Subscribe(ctx, b.channel)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("subscribe to product stream: %w", err)
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			var event domain.ProductEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				b.logger.WithError(err).Warn("Discarding malformed product stream message")
+				continue
+			}
+			hub.Publish(ctx, event)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// ExternalProduct is a product as reported by an external system. ExternalID
+// is opaque to the core and only interpreted by the adapter that produced it.
+// Unit is empty when the system does not report one; the linked product then
+// keeps its own unit.
+type ExternalProduct struct {
+	ExternalID  string
+	Name        string
+	Description string
+	Amount      int64
+	Unit        string
+	Price       float64
+	UpdatedAt   time.Time
+}
+
+// StockPriceUpdate pushes the local stock level and price of a linked product
+// back to the external system. External systems count stock in whole units,
+// so products with a fractional amount are not pushed.
+type StockPriceUpdate struct {
+	ExternalID string
+	Amount     int64
+	Price      float64
+}
+
+// Page is one page of pulled products. Next is empty once the pull is done.
+type Page struct {
+	Products []ExternalProduct
+	Next     string
+}
+
+type Connector interface {
+	Kind() string
+	// PullProducts returns the page at cursor; an empty cursor starts a new
+	// pull of everything changed since the given time (zero means all).
+	PullProducts(ctx context.Context, cursor string, since time.Time) (*Page, error)
+	PushStockPrice(ctx context.Context, updates []StockPriceUpdate) error
+}
+
+// Factory builds a connector from its stored settings and decrypted
+// credentials.
+type Factory func(settings map[string]string, credentials []byte, client *http.Client) (Connector, error)
+
+type Registry struct {
+	client *http.Client
+
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+func NewRegistry(client *http.Client) *Registry {
+	return &Registry{
+		client:    client,
+		factories: make(map[string]Factory),
+	}
+}
+
+func (r *Registry) Register(kind string, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.factories[kind] = factory
+}
+
+func (r *Registry) Supports(kind string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.factories[kind]
+	return ok
+}
+
+func (r *Registry) Kinds() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	kinds := make([]string, 0, len(r.factories))
+	for kind := range r.factories {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func (r *Registry) New(kind string, settings map[string]string, credentials []byte) (Connector, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[kind]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedConnector, kind)
+	}
+
+	connector, err := factory(settings, credentials, r.client)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidConnector, err.Error())
+	}
+
+	return connector, nil
+}
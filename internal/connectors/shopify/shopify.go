@@ -0,0 +1,240 @@
+package shopify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-context-engineering-template/internal/connectors"
+	"backend-context-engineering-template/internal/domain"
+)
+
+const (
+	defaultAPIVersion = "2024-01"
+	pageSize          = 250
+)
+
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+type Connector struct {
+	baseURL    string
+	token      string
+	locationID string
+	client     *http.Client
+}
+
+// New is a connectors.Factory. Settings: shop_domain (required),
+// api_version, and location_id (required to push stock levels).
+func New(settings map[string]string, credentials []byte, client *http.Client) (connectors.Connector, error) {
+	shop := strings.TrimSpace(settings["shop_domain"])
+	if shop == "" {
+		return nil, errors.New("shop_domain setting is required")
+	}
+	if len(credentials) == 0 {
+		return nil, errors.New("access token is required")
+	}
+
+	version := settings["api_version"]
+	if version == "" {
+		version = defaultAPIVersion
+	}
+
+	baseURL := shop
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "https://" + baseURL
+	}
+
+	return &Connector{
+		baseURL:    strings.TrimRight(baseURL, "/") + "/admin/api/" + version,
+		token:      string(credentials),
+		locationID: settings["location_id"],
+		client:     client,
+	}, nil
+}
+
+func (c *Connector) Kind() string {
+	return domain.ConnectorKindShopify
+}
+
+type productsResponse struct {
+	Products []struct {
+		ID        int64     `json:"id"`
+		Title     string    `json:"title"`
+		BodyHTML  string    `json:"body_html"`
+		UpdatedAt time.Time `json:"updated_at"`
+		Variants  []struct {
+			ID                int64  `json:"id"`
+			Title             string `json:"title"`
+			Price             string `json:"price"`
+			InventoryQuantity int64  `json:"inventory_quantity"`
+			InventoryItemID   int64  `json:"inventory_item_id"`
+		} `json:"variants"`
+	} `json:"products"`
+}
+
+func (c *Connector) PullProducts(ctx context.Context, cursor string, since time.Time) (*connectors.Page, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(pageSize))
+	if cursor != "" {
+		// Shopify rejects any other filter alongside page_info.
+		query.Set("page_info", cursor)
+	} else if !since.IsZero() {
+		query.Set("updated_at_min", since.UTC().Format(time.RFC3339))
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, "/products.json?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body productsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode shopify products: %w", err)
+	}
+
+	page := &connectors.Page{Next: nextPageInfo(resp.Header.Get("Link"))}
+	for _, p := range body.Products {
+		for _, v := range p.Variants {
+			price, err := strconv.ParseFloat(v.Price, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid price %q for variant %d", v.Price, v.ID)
+			}
+
+			name := p.Title
+			if len(p.Variants) > 1 && v.Title != "" {
+				name = p.Title + " - " + v.Title
+			}
+
+			page.Products = append(page.Products, connectors.ExternalProduct{
+				ExternalID:  encodeExternalID(v.ID, v.InventoryItemID),
+				Name:        name,
+				Description: p.BodyHTML,
+				Amount:      v.InventoryQuantity,
+				Price:       price,
+				UpdatedAt:   p.UpdatedAt,
+			})
+		}
+	}
+
+	return page, nil
+}
+
+func (c *Connector) PushStockPrice(ctx context.Context, updates []connectors.StockPriceUpdate) error {
+	for _, update := range updates {
+		variantID, inventoryItemID, err := decodeExternalID(update.ExternalID)
+		if err != nil {
+			return err
+		}
+
+		variant := map[string]interface{}{
+			"variant": map[string]interface{}{
+				"id":    variantID,
+				"price": strconv.FormatFloat(update.Price, 'f', 2, 64),
+			},
+		}
+		if err := c.send(ctx, http.MethodPut, fmt.Sprintf("/variants/%d.json", variantID), variant); err != nil {
+			return fmt.Errorf("failed to push price for variant %d: %w", variantID, err)
+		}
+
+		if c.locationID == "" {
+			continue
+		}
+
+		level := map[string]interface{}{
+			"location_id":       c.locationID,
+			"inventory_item_id": inventoryItemID,
+			"available":         update.Amount,
+		}
+		if err := c.send(ctx, http.MethodPost, "/inventory_levels/set.json", level); err != nil {
+			return fmt.Errorf("failed to push stock for variant %d: %w", variantID, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Connector) send(ctx context.Context, method, path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(ctx, method, path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (c *Connector) do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build shopify request: %w", err)
+	}
+	req.Header.Set("X-Shopify-Access-Token", c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("shopify request failed: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("shopify responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return resp, nil
+}
+
+func nextPageInfo(link string) string {
+	match := nextLinkPattern.FindStringSubmatch(link)
+	if match == nil {
+		return ""
+	}
+
+	u, err := url.Parse(match[1])
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("page_info")
+}
+
+func encodeExternalID(variantID, inventoryItemID int64) string {
+	return strconv.FormatInt(variantID, 10) + ":" + strconv.FormatInt(inventoryItemID, 10)
+}
+
+func decodeExternalID(externalID string) (int64, int64, error) {
+	parts := strings.SplitN(externalID, ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("malformed shopify external id %q", externalID)
+	}
+
+	variantID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed shopify external id %q", externalID)
+	}
+
+	inventoryItemID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed shopify external id %q", externalID)
+	}
+
+	return variantID, inventoryItemID, nil
+}
@@ -0,0 +1,74 @@
+package shopify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/connectors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnector_PullProducts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get("X-Shopify-Access-Token"))
+		assert.Equal(t, "/admin/api/2024-01/products.json", r.URL.Path)
+
+		if r.URL.Query().Get("page_info") == "" {
+			w.Header().Set("Link", `<https://shop.example.com/admin/api/2024-01/products.json?limit=250&page_info=abc>; rel="next"`)
+		}
+		w.Write([]byte(`{"products":[{"id":1,"title":"Shirt","body_html":"Cotton","variants":[
+			{"id":11,"title":"S","price":"10.00","inventory_quantity":3,"inventory_item_id":111},
+			{"id":12,"title":"M","price":"12.50","inventory_quantity":0,"inventory_item_id":112}]}]}`))
+	}))
+	defer server.Close()
+
+	c, err := New(map[string]string{"shop_domain": server.URL}, []byte("token"), server.Client())
+	require.NoError(t, err)
+
+	page, err := c.PullProducts(context.Background(), "", time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", page.Next)
+	require.Len(t, page.Products, 2)
+	assert.Equal(t, "Shirt - S", page.Products[0].Name)
+	assert.Equal(t, "11:111", page.Products[0].ExternalID)
+	assert.Equal(t, 12.5, page.Products[1].Price)
+
+	page, err = c.PullProducts(context.Background(), "abc", time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Next)
+}
+
+func TestConnector_PushStockPrice(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c, err := New(map[string]string{"shop_domain": server.URL, "location_id": "9"}, []byte("token"), server.Client())
+	require.NoError(t, err)
+
+	err = c.PushStockPrice(context.Background(), []connectors.StockPriceUpdate{
+		{ExternalID: "11:111", Amount: 4, Price: 9.99},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"PUT /admin/api/2024-01/variants/11.json",
+		"POST /admin/api/2024-01/inventory_levels/set.json",
+	}, paths)
+}
+
+func TestNew_RequiresShopDomain(t *testing.T) {
+	_, err := New(map[string]string{}, []byte("token"), http.DefaultClient)
+	assert.Error(t, err)
+}
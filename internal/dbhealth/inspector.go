@@ -0,0 +1,143 @@
+// Package dbhealth turns Postgres activity statistics into a report of
+// bloated tables, unused indexes and tables read mostly by sequential
+// scans, with a recommendation for each.
+package dbhealth
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+)
+
+// Store reads the statistics of the service's tables.
+type Store interface {
+	TableStats(ctx context.Context) ([]domain.TableStats, error)
+	IndexStats(ctx context.Context) ([]domain.IndexStats, error)
+	StatsSince(ctx context.Context) (sql.NullTime, error)
+}
+
+// Thresholds decide what counts as a finding. Small tables are skipped by
+// every check: vacuuming or scanning them is cheap either way.
+type Thresholds struct {
+	// MinRows is the live row count below which a table is not checked.
+	MinRows int64
+	// DeadTupleRatio flags tables whose dead rows exceed this share of
+	// live rows.
+	DeadTupleRatio float64
+	// SeqScanRows flags tables whose sequential scans read this many rows
+	// on average while outnumbering index scans.
+	SeqScanRows int64
+}
+
+var DefaultThresholds = Thresholds{
+	MinRows:        1000,
+	DeadTupleRatio: 0.2,
+	SeqScanRows:    1000,
+}
+
+type Inspector struct {
+	store      Store
+	thresholds Thresholds
+	clock      clock.Clock
+}
+
+func NewInspector(store Store, thresholds Thresholds, clk clock.Clock) *Inspector {
+	return &Inspector{
+		store:      store,
+		thresholds: thresholds,
+		clock:      clk,
+	}
+}
+
+func (i *Inspector) Report(ctx context.Context) (*domain.DBHealthReport, error) {
+	tables, err := i.store.TableStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	indexes, err := i.store.IndexStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	since, err := i.store.StatsSince(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.DBHealthReport{
+		GeneratedAt: i.clock.Now(),
+		StatsSince:  since,
+		Tables:      tables,
+		Indexes:     indexes,
+		Findings:    Analyze(tables, indexes, i.thresholds),
+	}, nil
+}
+
+// Analyze applies the checks to the statistics. Findings are grouped by
+// check, then ordered by table and index.
+func Analyze(tables []domain.TableStats, indexes []domain.IndexStats, thresholds Thresholds) []domain.DBHealthFinding {
+	checked := make(map[string]bool, len(tables))
+	findings := []domain.DBHealthFinding{}
+
+	for _, table := range tables {
+		if table.LiveTuples < thresholds.MinRows {
+			continue
+		}
+		checked[table.Table] = true
+
+		if ratio := float64(table.DeadTuples) / float64(table.LiveTuples); ratio > thresholds.DeadTupleRatio {
+			detail := fmt.Sprintf("%d dead rows, %.0f%% of %d live rows", table.DeadTuples, ratio*100, table.LiveTuples)
+			if !table.LastVacuum.Valid {
+				detail += "; never vacuumed"
+			}
+			findings = append(findings, domain.DBHealthFinding{
+				Check:  domain.DBHealthCheckDeadTuples,
+				Table:  table.Table,
+				Detail: detail,
+				Recommendation: fmt.Sprintf("Run VACUUM (ANALYZE) %s, and if it recurs lower autovacuum_vacuum_scale_factor for the table.",
+					table.Table),
+			})
+		}
+
+		if table.SeqScans > table.IndexScans && table.SeqScans > 0 {
+			if perScan := table.SeqTuplesRead / table.SeqScans; perScan >= thresholds.SeqScanRows {
+				findings = append(findings, domain.DBHealthFinding{
+					Check: domain.DBHealthCheckSeqScans,
+					Table: table.Table,
+					Detail: fmt.Sprintf("%d sequential scans reading %d rows each on average, %d index scans",
+						table.SeqScans, perScan, table.IndexScans),
+					Recommendation: "EXPLAIN the queries filtering this table and index the columns they filter or sort on.",
+				})
+			}
+		}
+	}
+
+	for _, index := range indexes {
+		// Unique indexes enforce constraints even when no query uses them.
+		if index.Unique || index.Scans > 0 || !checked[index.Table] {
+			continue
+		}
+		findings = append(findings, domain.DBHealthFinding{
+			Check:  domain.DBHealthCheckUnusedIndex,
+			Table:  index.Table,
+			Index:  index.Index,
+			Detail: fmt.Sprintf("never scanned, %d bytes", index.Bytes),
+			Recommendation: fmt.Sprintf("If no replica or rare job uses it, run DROP INDEX CONCURRENTLY %s.",
+				index.Index),
+		})
+	}
+
+	sort.SliceStable(findings, func(a, b int) bool {
+		if findings[a].Check != findings[b].Check {
+			return findings[a].Check < findings[b].Check
+		}
+		if findings[a].Table != findings[b].Table {
+			return findings[a].Table < findings[b].Table
+		}
+		return findings[a].Index < findings[b].Index
+	})
+	return findings
+}
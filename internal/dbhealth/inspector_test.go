@@ -0,0 +1,89 @@
+package dbhealth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	vacuumed := sql.NullTime{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	tables := []domain.TableStats{
+		// Bloated and scanned sequentially.
+		{Table: "products", LiveTuples: 10000, DeadTuples: 5000, SeqScans: 40, SeqTuplesRead: 400000, IndexScans: 10},
+		// Healthy.
+		{Table: "product_trash", LiveTuples: 5000, DeadTuples: 100, SeqScans: 2, SeqTuplesRead: 10000, IndexScans: 900, LastVacuum: vacuumed},
+		// Too small to matter, whatever its stats say.
+		{Table: "product_feeds", LiveTuples: 10, DeadTuples: 90, SeqScans: 500, SeqTuplesRead: 5000},
+		// Sequential scans that read few rows each are cheap.
+		{Table: "audit_logs", LiveTuples: 200000, DeadTuples: 0, SeqScans: 100, SeqTuplesRead: 50000, LastVacuum: vacuumed},
+	}
+	indexes := []domain.IndexStats{
+		{Table: "products", Index: "products_pkey", Scans: 0, Unique: true},
+		{Table: "products", Index: "idx_products_store_id", Scans: 0, Bytes: 8192},
+		{Table: "product_trash", Index: "idx_product_trash_store_id", Scans: 12},
+		{Table: "product_feeds", Index: "idx_product_feeds_store_id", Scans: 0},
+	}
+
+	findings := Analyze(tables, indexes, DefaultThresholds)
+
+	require.Len(t, findings, 3)
+	assert.Equal(t, domain.DBHealthFinding{
+		Check:          domain.DBHealthCheckDeadTuples,
+		Table:          "products",
+		Detail:         "5000 dead rows, 50% of 10000 live rows; never vacuumed",
+		Recommendation: "Run VACUUM (ANALYZE) products, and if it recurs lower autovacuum_vacuum_scale_factor for the table.",
+	}, findings[0])
+	assert.Equal(t, domain.DBHealthCheckSeqScans, findings[1].Check)
+	assert.Equal(t, "products", findings[1].Table)
+	assert.Equal(t, "40 sequential scans reading 10000 rows each on average, 10 index scans", findings[1].Detail)
+	assert.Equal(t, domain.DBHealthCheckUnusedIndex, findings[2].Check)
+	assert.Equal(t, "idx_products_store_id", findings[2].Index, "unique and small-table indexes are not reported")
+}
+
+func TestAnalyze_NoFindings(t *testing.T) {
+	findings := Analyze(nil, nil, DefaultThresholds)
+	assert.NotNil(t, findings)
+	assert.Empty(t, findings)
+}
+
+type fakeStore struct {
+	tables []domain.TableStats
+	err    error
+}
+
+func (s *fakeStore) TableStats(ctx context.Context) ([]domain.TableStats, error) {
+	return s.tables, s.err
+}
+
+func (s *fakeStore) IndexStats(ctx context.Context) ([]domain.IndexStats, error) {
+	return nil, nil
+}
+
+func (s *fakeStore) StatsSince(ctx context.Context) (sql.NullTime, error) {
+	return sql.NullTime{}, nil
+}
+
+func TestInspector_Report(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{tables: []domain.TableStats{{Table: "products", LiveTuples: 2000, DeadTuples: 1000}}}
+	inspector := NewInspector(store, DefaultThresholds, clock.NewFake(now))
+
+	report, err := inspector.Report(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, now, report.GeneratedAt)
+	assert.Len(t, report.Tables, 1)
+	assert.Len(t, report.Findings, 1)
+
+	store.err = errors.New("permission denied")
+	_, err = inspector.Report(context.Background())
+	assert.EqualError(t, err, "permission denied")
+}
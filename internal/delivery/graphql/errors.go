@@ -0,0 +1,87 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/logger"
+)
+
+// queryError is an error shown to clients. Its code, in the error's
+// extensions, is the error the REST API answers with in the same case.
+type queryError struct {
+	code    string
+	message string
+	// field is the input field that was refused, if any.
+	field string
+}
+
+func (e *queryError) Error() string {
+	return e.message
+}
+
+func (e *queryError) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.code}
+	if e.field != "" {
+		extensions["field"] = e.field
+	}
+	return extensions
+}
+
+func invalidInput(field, message string) error {
+	return &queryError{code: "validation_error", message: message, field: field}
+}
+
+// productError maps use case errors to what the product endpoints of the
+// REST API answer with.
+func productError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound):
+		return &queryError{code: "product_not_found", message: "Product not found"}
+	case errors.Is(err, domain.ErrInvalidProduct):
+		return &queryError{code: "invalid_product", message: err.Error(), field: fieldOf(err)}
+	case errors.Is(err, domain.ErrDuplicateProduct):
+		return &queryError{code: "duplicate_product", message: "Product with this name already exists"}
+	case errors.Is(err, domain.ErrConflict):
+		return &queryError{code: "version_conflict", message: "The product was changed since this version; fetch it again and reapply your changes"}
+	case errors.Is(err, domain.ErrStoreNotFound):
+		return &queryError{code: "store_not_found", message: "The product's store does not exist"}
+	case errors.Is(err, domain.ErrProductInBundle):
+		return &queryError{code: "product_in_bundle", message: "Product is a component of a bundle; remove it from the bundle first"}
+	case errors.Is(err, domain.ErrContentRejected):
+		return &queryError{code: "content_rejected", message: err.Error()}
+	case errors.Is(err, domain.ErrConfirmationRequired):
+		return &queryError{code: "confirmation_required", message: err.Error() + "; retry with confirmMassOperation: true"}
+	default:
+		return internalError(ctx, err)
+	}
+}
+
+// storeError maps use case errors to what the store endpoints of the REST
+// API answer with.
+func storeError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrStoreNotFound):
+		return &queryError{code: "store_not_found", message: "Store not found"}
+	case errors.Is(err, domain.ErrInvalidStore):
+		return &queryError{code: "invalid_store", message: err.Error(), field: fieldOf(err)}
+	case errors.Is(err, domain.ErrStoreHasProducts):
+		return &queryError{code: "store_has_products", message: "The store still has products; delete or move them first"}
+	default:
+		return internalError(ctx, err)
+	}
+}
+
+func internalError(ctx context.Context, err error) error {
+	logger.FromContext(ctx).WithError(err).Error("Internal server error")
+	return &queryError{code: "internal_server_error", message: "An internal error occurred"}
+}
+
+func fieldOf(err error) string {
+	var fieldErr *domain.FieldError
+	if errors.As(err, &fieldErr) {
+		return fieldErr.Field
+	}
+	return ""
+}
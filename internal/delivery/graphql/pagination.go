@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"strconv"
+
+	"backend-context-engineering-template/pkg/pagination"
+)
+
+// maxPageSize is the most items a connection returns at once. One more
+// is fetched to tell whether there is a next page, which stays within
+// the use cases' limit of 100.
+const maxPageSize = 50
+
+// page returns the page first and after ask for, in the order sort among
+// sorts.
+func page(first int32, after *string, sorts pagination.SortSpec, sort string) (pagination.Page, error) {
+	p := pagination.Page{Limit: int(first), Sort: sort}
+	if p.Limit < 1 || p.Limit > maxPageSize {
+		return pagination.Page{}, invalidInput("first", "first must be between 1 and "+strconv.Itoa(maxPageSize))
+	}
+	if after != nil {
+		cursor, err := pagination.DecodeCursor(*after)
+		if err != nil {
+			return pagination.Page{}, invalidInput("after", "after must be a cursor from a previous page")
+		}
+		if !sorts.Same(cursor.Sort, sort) {
+			return pagination.Page{}, invalidInput("after", "after is a cursor from a listing in another order")
+		}
+		p.Offset = cursor.Offset
+	}
+	return p, nil
+}
+
+type connection[T any] struct {
+	edges    []*edge[T]
+	pageInfo *pageInfo
+}
+
+// newConnection builds the page from nodes, fetched with one more than
+// its limit. An edge's cursor is where the page after it starts.
+func newConnection[T any](nodes []T, p pagination.Page) *connection[T] {
+	hasNext := len(nodes) > p.Limit
+	if hasNext {
+		nodes = nodes[:p.Limit]
+	}
+	c := &connection[T]{
+		edges:    make([]*edge[T], len(nodes)),
+		pageInfo: &pageInfo{hasNextPage: hasNext},
+	}
+	for i, node := range nodes {
+		c.edges[i] = &edge[T]{cursor: pagination.Cursor{Offset: p.Offset + i + 1, Sort: p.Sort}.Encode(), node: node}
+	}
+	if len(c.edges) > 0 {
+		end := c.edges[len(c.edges)-1].cursor
+		c.pageInfo.endCursor = &end
+	}
+	return c
+}
+
+func (c *connection[T]) Edges() []*edge[T] {
+	return c.edges
+}
+
+func (c *connection[T]) PageInfo() *pageInfo {
+	return c.pageInfo
+}
+
+type edge[T any] struct {
+	cursor string
+	node   T
+}
+
+func (e *edge[T]) Cursor() string {
+	return e.cursor
+}
+
+func (e *edge[T]) Node() T {
+	return e.node
+}
+
+type pageInfo struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (p *pageInfo) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p *pageInfo) EndCursor() *string {
+	return p.endCursor
+}
@@ -0,0 +1,255 @@
+package graphql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/pagination"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type productResolver struct {
+	product *domain.Product
+	stores  usecase.StoreUseCaseInterface
+}
+
+func (r *productResolver) ID() graphql.ID {
+	return formatID(r.product.ID)
+}
+
+func (r *productResolver) StoreID() graphql.ID {
+	return formatID(r.product.StoreID)
+}
+
+// Store is looked up once per request however many of its products are
+// resolved.
+func (r *productResolver) Store(ctx context.Context) (*storeResolver, error) {
+	lookup := stateFrom(ctx).lookupStore(r.product.StoreID)
+	lookup.once.Do(func() {
+		lookup.store, lookup.err = r.stores.GetStore(ctx, r.product.StoreID)
+	})
+	if errors.Is(lookup.err, domain.ErrStoreNotFound) {
+		return nil, nil
+	}
+	if lookup.err != nil {
+		return nil, storeError(ctx, lookup.err)
+	}
+	return &storeResolver{store: lookup.store}, nil
+}
+
+func (r *productResolver) Name() string {
+	return r.product.Name
+}
+
+func (r *productResolver) Description() *string {
+	if !r.product.Description.Valid {
+		return nil
+	}
+	return &r.product.Description.String
+}
+
+func (r *productResolver) DescriptionFormat() string {
+	return r.product.DescriptionFormat
+}
+
+func (r *productResolver) Amount() string {
+	return r.product.Amount.String()
+}
+
+func (r *productResolver) Unit() string {
+	return r.product.Unit
+}
+
+func (r *productResolver) Price() float64 {
+	return r.product.Price
+}
+
+func (r *productResolver) Status() string {
+	return r.product.Status
+}
+
+func (r *productResolver) ModerationStatus() string {
+	return r.product.ModerationStatus
+}
+
+func (r *productResolver) AllowBackorder() bool {
+	return r.product.AllowBackorder
+}
+
+func (r *productResolver) BackorderLimit() string {
+	return r.product.BackorderLimit.String()
+}
+
+func (r *productResolver) PreorderReleaseDate() *graphql.Time {
+	return optionalTime(r.product.PreorderReleaseDate)
+}
+
+func (r *productResolver) LowStockThreshold() string {
+	return r.product.LowStockThreshold.String()
+}
+
+func (r *productResolver) Availability(ctx context.Context) string {
+	return r.product.Availability(stateFrom(ctx).now)
+}
+
+func (r *productResolver) PublishAt() *graphql.Time {
+	return optionalTime(r.product.PublishAt)
+}
+
+func (r *productResolver) UnpublishAt() *graphql.Time {
+	return optionalTime(r.product.UnpublishAt)
+}
+
+func (r *productResolver) Published(ctx context.Context) bool {
+	return r.product.IsPublished(stateFrom(ctx).now)
+}
+
+func (r *productResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.product.CreatedAt}
+}
+
+func (r *productResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.product.UpdatedAt}
+}
+
+func (r *productResolver) Version() int32 {
+	return int32(r.product.Version)
+}
+
+func optionalTime(t *time.Time) *graphql.Time {
+	if t == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *t}
+}
+
+// productSorts are the orders products are listed in, newest first.
+var productSorts = pagination.SortSpec{domain.ProductSortNewest, domain.ProductSortPopularity}
+
+type productFilterInput struct {
+	Name       *string
+	StoreID    *graphql.ID
+	CategoryID *graphql.ID
+	MinPrice   *float64
+	MaxPrice   *float64
+	InStock    *bool
+	Sort       *string
+}
+
+// toDomain converts the filter; the use case validates it.
+func (f *productFilterInput) toDomain() (domain.ProductFilter, error) {
+	filter := domain.ProductFilter{
+		MinPrice: f.MinPrice,
+		MaxPrice: f.MaxPrice,
+		InStock:  f.InStock,
+	}
+	if f.Name != nil {
+		filter.Name = *f.Name
+	}
+	if f.StoreID != nil {
+		storeID, err := parseID(*f.StoreID, "storeId")
+		if err != nil {
+			return domain.ProductFilter{}, err
+		}
+		filter.StoreID = &storeID
+	}
+	if f.CategoryID != nil {
+		categoryID, err := parseID(*f.CategoryID, "categoryId")
+		if err != nil {
+			return domain.ProductFilter{}, err
+		}
+		filter.CategoryID = &categoryID
+	}
+	if f.Sort != nil {
+		switch *f.Sort {
+		case "NEWEST":
+			filter.Sort = domain.ProductSortNewest
+		case "POPULARITY":
+			filter.Sort = domain.ProductSortPopularity
+		}
+	}
+	return filter, nil
+}
+
+type productInput struct {
+	StoreID             graphql.ID
+	Name                string
+	Description         *string
+	DescriptionFormat   *string
+	Amount              string
+	Unit                *string
+	Price               float64
+	Status              *string
+	AllowBackorder      *bool
+	BackorderLimit      *string
+	PreorderReleaseDate *graphql.Time
+	LowStockThreshold   *string
+	PublishAt           *graphql.Time
+	UnpublishAt         *graphql.Time
+}
+
+// toDomain converts the input, parsing its quantities. The rest is
+// validated by the use case.
+func (in *productInput) toDomain() (*domain.Product, error) {
+	storeID, err := parseID(in.StoreID, "storeId")
+	if err != nil {
+		return nil, err
+	}
+	product := &domain.Product{
+		StoreID:             storeID,
+		Name:                in.Name,
+		DescriptionFormat:   deref(in.DescriptionFormat),
+		Unit:                deref(in.Unit),
+		Price:               in.Price,
+		Status:              deref(in.Status),
+		AllowBackorder:      in.AllowBackorder != nil && *in.AllowBackorder,
+		PreorderReleaseDate: inputTime(in.PreorderReleaseDate),
+		PublishAt:           inputTime(in.PublishAt),
+		UnpublishAt:         inputTime(in.UnpublishAt),
+	}
+	if in.Description != nil && *in.Description != "" {
+		product.Description = sql.NullString{String: *in.Description, Valid: true}
+	}
+	if product.Amount, err = parseQuantity("amount", &in.Amount); err != nil {
+		return nil, err
+	}
+	if product.BackorderLimit, err = parseQuantity("backorderLimit", in.BackorderLimit); err != nil {
+		return nil, err
+	}
+	if product.LowStockThreshold, err = parseQuantity("lowStockThreshold", in.LowStockThreshold); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func parseQuantity(field string, s *string) (quantity.Quantity, error) {
+	if s == nil || *s == "" {
+		return quantity.Quantity{}, nil
+	}
+	q, err := quantity.Parse(*s)
+	if err != nil {
+		return quantity.Quantity{}, invalidInput(field, fmt.Sprintf("%s must be a decimal number", field))
+	}
+	return q, nil
+}
+
+func inputTime(t *graphql.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return &t.Time
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
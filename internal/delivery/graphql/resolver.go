@@ -0,0 +1,228 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// resolver resolves the Query and Mutation fields.
+type resolver struct {
+	products usecase.ProductUseCaseInterface
+	stores   usecase.StoreUseCaseInterface
+}
+
+func (r *resolver) Product(ctx context.Context, args struct{ ID graphql.ID }) (*productResolver, error) {
+	id, err := parseID(args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+	product, err := r.products.GetProduct(ctx, id)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, productError(ctx, err)
+	}
+	// Drafts are shown only to their store's owners; to anyone else they
+	// do not exist.
+	if product.Status == domain.ProductStatusDraft && !stateFrom(ctx).ownsStore(product.StoreID) {
+		return nil, nil
+	}
+	return r.product(product), nil
+}
+
+func (r *resolver) Products(ctx context.Context, args struct {
+	First  int32
+	After  *string
+	Filter *productFilterInput
+}) (*connection[*productResolver], error) {
+	var filter domain.ProductFilter
+	if args.Filter != nil {
+		var err error
+		if filter, err = args.Filter.toDomain(); err != nil {
+			return nil, err
+		}
+	}
+	p, err := page(args.First, args.After, productSorts, filter.Sort)
+	if err != nil {
+		return nil, err
+	}
+	products, err := r.products.GetProducts(ctx, filter, p.Limit+1, p.Offset)
+	if err != nil {
+		return nil, productError(ctx, err)
+	}
+	nodes := make([]*productResolver, len(products))
+	for i, product := range products {
+		nodes[i] = r.product(product)
+	}
+	return newConnection(nodes, p), nil
+}
+
+func (r *resolver) Store(ctx context.Context, args struct{ ID graphql.ID }) (*storeResolver, error) {
+	id, err := parseID(args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+	store, err := r.stores.GetStore(ctx, id)
+	if errors.Is(err, domain.ErrStoreNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, storeError(ctx, err)
+	}
+	return &storeResolver{store: store}, nil
+}
+
+func (r *resolver) Stores(ctx context.Context, args struct {
+	First int32
+	After *string
+}) (*connection[*storeResolver], error) {
+	p, err := page(args.First, args.After, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	stores, err := r.stores.GetStores(ctx, p.Limit+1, p.Offset)
+	if err != nil {
+		return nil, storeError(ctx, err)
+	}
+	nodes := make([]*storeResolver, len(stores))
+	for i, store := range stores {
+		nodes[i] = &storeResolver{store: store}
+	}
+	return newConnection(nodes, p), nil
+}
+
+func (r *resolver) CreateProduct(ctx context.Context, args struct{ Input productInput }) (*productResolver, error) {
+	if err := writable(ctx); err != nil {
+		return nil, err
+	}
+	product, err := args.Input.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	created, err := r.products.CreateProduct(ctx, product)
+	if err != nil {
+		return nil, productError(ctx, err)
+	}
+	return r.product(created), nil
+}
+
+func (r *resolver) UpdateProduct(ctx context.Context, args struct {
+	ID      graphql.ID
+	Version int32
+	Input   productInput
+}) (*productResolver, error) {
+	if err := writable(ctx); err != nil {
+		return nil, err
+	}
+	id, err := parseID(args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+	if args.Version < 0 {
+		return nil, invalidInput("version", "version must not be negative")
+	}
+	product, err := args.Input.toDomain()
+	if err != nil {
+		return nil, err
+	}
+	product.Version = int64(args.Version)
+	updated, err := r.products.UpdateProduct(ctx, id, product)
+	if err != nil {
+		return nil, productError(ctx, err)
+	}
+	return r.product(updated), nil
+}
+
+func (r *resolver) DeleteProduct(ctx context.Context, args struct {
+	ID                   graphql.ID
+	ConfirmMassOperation bool
+}) (graphql.ID, error) {
+	if err := writable(ctx); err != nil {
+		return "", err
+	}
+	id, err := parseID(args.ID, "id")
+	if err != nil {
+		return "", err
+	}
+	if args.ConfirmMassOperation {
+		ctx = usecase.WithMassOperationConfirmed(ctx)
+	}
+	if err := r.products.DeleteProduct(ctx, id); err != nil {
+		return "", productError(ctx, err)
+	}
+	return args.ID, nil
+}
+
+func (r *resolver) CreateStore(ctx context.Context, args struct{ Input storeInput }) (*storeResolver, error) {
+	if err := writable(ctx); err != nil {
+		return nil, err
+	}
+	created, err := r.stores.CreateStore(ctx, args.Input.toDomain())
+	if err != nil {
+		return nil, storeError(ctx, err)
+	}
+	return &storeResolver{store: created}, nil
+}
+
+func (r *resolver) UpdateStore(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input storeInput
+}) (*storeResolver, error) {
+	if err := writable(ctx); err != nil {
+		return nil, err
+	}
+	id, err := parseID(args.ID, "id")
+	if err != nil {
+		return nil, err
+	}
+	updated, err := r.stores.UpdateStore(ctx, id, args.Input.toDomain())
+	if err != nil {
+		return nil, storeError(ctx, err)
+	}
+	return &storeResolver{store: updated}, nil
+}
+
+func (r *resolver) DeleteStore(ctx context.Context, args struct{ ID graphql.ID }) (graphql.ID, error) {
+	if err := writable(ctx); err != nil {
+		return "", err
+	}
+	id, err := parseID(args.ID, "id")
+	if err != nil {
+		return "", err
+	}
+	if err := r.stores.DeleteStore(ctx, id); err != nil {
+		return "", storeError(ctx, err)
+	}
+	return args.ID, nil
+}
+
+func (r *resolver) product(product *domain.Product) *productResolver {
+	return &productResolver{product: product, stores: r.stores}
+}
+
+// writable refuses mutations in read-only requests.
+func writable(ctx context.Context) error {
+	if stateFrom(ctx).readOnly {
+		return &queryError{code: "method_not_allowed", message: "Mutations must be sent with POST"}
+	}
+	return nil
+}
+
+func parseID(id graphql.ID, field string) (int64, error) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, invalidInput(field, field+" must be a positive number")
+	}
+	return n, nil
+}
+
+func formatID(id int64) graphql.ID {
+	return graphql.ID(strconv.FormatInt(id, 10))
+}
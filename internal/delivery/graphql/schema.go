@@ -0,0 +1,132 @@
+// Package graphql serves products and stores over GraphQL from the same use
+// cases as the REST API, so clients can fetch the fields they show and
+// nothing more. The HTTP handler in front of it authenticates callers like
+// any other route; resolvers apply the REST API's rules on top, such as
+// hiding drafts from everyone but their store's owners.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/graph-gophers/graphql-go"
+	gqllog "github.com/graph-gophers/graphql-go/log"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxDepth bounds how deeply queries nest, so one request cannot fan
+	// out into an unbounded number of lookups.
+	maxDepth = 8
+	// maxQueryLength caps the query text, which is parsed before any limit
+	// on its shape applies.
+	maxQueryLength = 16 << 10
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// Caller is who a request was made by.
+type Caller interface {
+	// OwnsStore reports whether the caller may see the store's drafts.
+	OwnsStore(storeID int64) bool
+}
+
+// Request is a GraphQL request as clients send it, in a POST body or in
+// the query string of a GET.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Schema executes requests against the use cases.
+type Schema struct {
+	schema *graphql.Schema
+	clock  clock.Clock
+}
+
+func NewSchema(products usecase.ProductUseCaseInterface, stores usecase.StoreUseCaseInterface, clk clock.Clock) *Schema {
+	return &Schema{
+		schema: graphql.MustParseSchema(schemaSDL, &resolver{products: products, stores: stores},
+			graphql.UseStringDescriptions(),
+			graphql.MaxDepth(maxDepth),
+			graphql.MaxQueryLength(maxQueryLength),
+			graphql.Logger(gqllog.LoggerFunc(logPanic)),
+		),
+		clock: clk,
+	}
+}
+
+// Exec runs the request for caller. A read-only request is refused any
+// mutation, which is how GET requests are kept free of side effects.
+func (s *Schema) Exec(ctx context.Context, caller Caller, req Request, readOnly bool) *graphql.Response {
+	ctx = context.WithValue(ctx, requestKey{}, &requestState{
+		caller:   caller,
+		readOnly: readOnly,
+		now:      s.clock.Now(),
+		stores:   make(map[int64]*storeLookup),
+	})
+	return s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
+
+// logPanic logs a resolver's panic with the request's fields; the field
+// resolves to an error and the rest of the response is still served.
+func logPanic(ctx context.Context, value any) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"panic": value,
+		"stack": string(debug.Stack()),
+	}).Error("GraphQL resolver panicked")
+}
+
+type requestKey struct{}
+
+// requestState is what the resolvers of one request share. Fields are
+// resolved concurrently.
+type requestState struct {
+	caller   Caller
+	readOnly bool
+	// now is when availability is judged, the same instant for every
+	// product in the response.
+	now time.Time
+
+	mu sync.Mutex
+	// stores memoizes Product.store, which would otherwise look the same
+	// store up once for every product on a page.
+	stores map[int64]*storeLookup
+}
+
+type storeLookup struct {
+	once  sync.Once
+	store *domain.Store
+	err   error
+}
+
+func stateFrom(ctx context.Context) *requestState {
+	return ctx.Value(requestKey{}).(*requestState)
+}
+
+func (s *requestState) ownsStore(storeID int64) bool {
+	return s.caller != nil && s.caller.OwnsStore(storeID)
+}
+
+// lookupStore returns the request's lookup of the store, which the first
+// caller to get it performs.
+func (s *requestState) lookupStore(id int64) *storeLookup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lookup, ok := s.stores[id]
+	if !ok {
+		lookup = &storeLookup{}
+		s.stores[id] = lookup
+	}
+	return lookup
+}
@@ -0,0 +1,323 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockProductUseCase struct {
+	mock.Mock
+}
+
+func (m *MockProductUseCase) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	args := m.Called(ctx, product)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) GetProduct(ctx context.Context, id int64) (*domain.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) GetProducts(ctx context.Context, filter domain.ProductFilter, limit, offset int) ([]*domain.Product, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) GetProductsByAvailability(ctx context.Context, availability string, limit, offset int) ([]*domain.Product, error) {
+	args := m.Called(ctx, availability, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) StreamProducts(ctx context.Context, fn func(*domain.Product) error) error {
+	args := m.Called(ctx)
+	for _, product := range args.Get(0).([]*domain.Product) {
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockProductUseCase) UpdateProduct(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
+	args := m.Called(ctx, id, product)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) UpdateProductPartial(ctx context.Context, id int64, patch *domain.ProductPatch) (*domain.Product, error) {
+	args := m.Called(ctx, id, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) DeleteProduct(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockProductUseCase) WriteProducts(ctx context.Context, items []domain.BulkProductItem) ([]*domain.BulkProductResult, error) {
+	args := m.Called(ctx, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.BulkProductResult), args.Error(1)
+}
+
+func (m *MockProductUseCase) ImportProducts(ctx context.Context, file io.Reader) (*domain.ProductImportReport, error) {
+	args := m.Called(ctx, file)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProductImportReport), args.Error(1)
+}
+
+type MockStoreUseCase struct {
+	mock.Mock
+}
+
+func (m *MockStoreUseCase) CreateStore(ctx context.Context, store *domain.Store) (*domain.Store, error) {
+	args := m.Called(ctx, store)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreUseCase) GetStore(ctx context.Context, id int64) (*domain.Store, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreUseCase) GetStores(ctx context.Context, limit, offset int) ([]*domain.Store, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreUseCase) UpdateStore(ctx context.Context, id int64, store *domain.Store) (*domain.Store, error) {
+	args := m.Called(ctx, id, store)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreUseCase) DeleteStore(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type testCaller []int64
+
+func (c testCaller) OwnsStore(storeID int64) bool {
+	for _, id := range c {
+		if id == storeID {
+			return true
+		}
+	}
+	return false
+}
+
+func testProduct() *domain.Product {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &domain.Product{
+		ID:                1,
+		StoreID:           1,
+		Name:              "Flour",
+		DescriptionFormat: domain.DescriptionFormatPlain,
+		Amount:            quantity.MustParse("2.5"),
+		Unit:              domain.UnitKilogram,
+		Price:             3.2,
+		Status:            domain.ProductStatusActive,
+		ModerationStatus:  domain.ModerationStatusApproved,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		Version:           3,
+	}
+}
+
+// exec runs query and returns its data, failing the test on errors.
+func exec(t *testing.T, schema *Schema, caller Caller, query string, variables map[string]any) map[string]any {
+	t.Helper()
+	resp := schema.Exec(context.Background(), caller, Request{Query: query, Variables: variables}, false)
+	require.Empty(t, resp.Errors)
+	var data map[string]any
+	require.NoError(t, json.Unmarshal(resp.Data, &data))
+	return data
+}
+
+func errorCode(t *testing.T, schema *Schema, query string, readOnly bool) string {
+	t.Helper()
+	resp := schema.Exec(context.Background(), nil, Request{Query: query}, readOnly)
+	require.Len(t, resp.Errors, 1)
+	return resp.Errors[0].Extensions["code"].(string)
+}
+
+func TestSchema_Product(t *testing.T) {
+	products := new(MockProductUseCase)
+	products.On("GetProduct", mock.Anything, int64(1)).Return(testProduct(), nil)
+	stores := new(MockStoreUseCase)
+	schema := NewSchema(products, stores, clock.Real())
+
+	data := exec(t, schema, nil, `{ product(id: "1") { id name amount unit availability version } }`, nil)
+
+	assert.Equal(t, map[string]any{
+		"id": "1", "name": "Flour", "amount": "2.5", "unit": domain.UnitKilogram,
+		"availability": domain.AvailabilityInStock, "version": float64(3),
+	}, data["product"])
+	stores.AssertNotCalled(t, "GetStore", mock.Anything, mock.Anything)
+}
+
+func TestSchema_ProductHidesDrafts(t *testing.T) {
+	draft := testProduct()
+	draft.Status = domain.ProductStatusDraft
+	products := new(MockProductUseCase)
+	products.On("GetProduct", mock.Anything, int64(1)).Return(draft, nil)
+	products.On("GetProduct", mock.Anything, int64(2)).Return(nil, domain.ErrProductNotFound)
+	schema := NewSchema(products, new(MockStoreUseCase), clock.Real())
+
+	data := exec(t, schema, nil, `{ product(id: "1") { id } }`, nil)
+	assert.Nil(t, data["product"])
+
+	data = exec(t, schema, testCaller{1}, `{ product(id: "1") { status } }`, nil)
+	assert.Equal(t, map[string]any{"status": domain.ProductStatusDraft}, data["product"])
+
+	data = exec(t, schema, testCaller{1}, `{ product(id: "2") { id } }`, nil)
+	assert.Nil(t, data["product"])
+}
+
+func TestSchema_ProductsPaginate(t *testing.T) {
+	second := testProduct()
+	second.ID = 2
+	third := testProduct()
+	third.ID = 3
+	storeID := int64(1)
+	filter := domain.ProductFilter{StoreID: &storeID, Sort: domain.ProductSortPopularity}
+	products := new(MockProductUseCase)
+	products.On("GetProducts", mock.Anything, filter, 3, 0).Return([]*domain.Product{testProduct(), second, third}, nil)
+	products.On("GetProducts", mock.Anything, filter, 3, 2).Return([]*domain.Product{third}, nil)
+	stores := new(MockStoreUseCase)
+	// Once per request, however many of its products are on the page.
+	stores.On("GetStore", mock.Anything, int64(1)).Return(&domain.Store{ID: 1, Name: "Mill"}, nil).Twice()
+	schema := NewSchema(products, stores, clock.Real())
+	query := `query($after: String) {
+		products(first: 2, after: $after, filter: {storeId: "1", sort: POPULARITY}) {
+			edges { node { id store { name } } }
+			pageInfo { hasNextPage endCursor }
+		}
+	}`
+
+	data := exec(t, schema, nil, query, nil)
+	page := data["products"].(map[string]any)
+	edges := page["edges"].([]any)
+	require.Len(t, edges, 2)
+	assert.Equal(t, map[string]any{"id": "2", "store": map[string]any{"name": "Mill"}}, edges[1].(map[string]any)["node"])
+	pageInfo := page["pageInfo"].(map[string]any)
+	assert.Equal(t, true, pageInfo["hasNextPage"])
+
+	data = exec(t, schema, nil, query, map[string]any{"after": pageInfo["endCursor"]})
+	page = data["products"].(map[string]any)
+	assert.Len(t, page["edges"], 1)
+	assert.Equal(t, false, page["pageInfo"].(map[string]any)["hasNextPage"])
+	products.AssertExpectations(t)
+	stores.AssertExpectations(t)
+}
+
+func TestSchema_RejectsInvalidArguments(t *testing.T) {
+	schema := NewSchema(new(MockProductUseCase), new(MockStoreUseCase), clock.Real())
+
+	assert.Equal(t, "validation_error", errorCode(t, schema, `{ products(first: 51) { pageInfo { hasNextPage } } }`, false))
+	assert.Equal(t, "validation_error", errorCode(t, schema, `{ stores(after: "nope") { pageInfo { hasNextPage } } }`, false))
+	assert.Equal(t, "validation_error", errorCode(t, schema, `{ product(id: "abc") { id } }`, false))
+	assert.Equal(t, "validation_error", errorCode(t, schema, `mutation { createProduct(input: {storeId: "1", name: "Flour", amount: "many", price: 1}) { id } }`, false))
+}
+
+func TestSchema_CreateProduct(t *testing.T) {
+	products := new(MockProductUseCase)
+	products.On("CreateProduct", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+		return p.StoreID == 1 && p.Name == "Flour" && p.Amount.String() == "2.5" && p.Unit == domain.UnitKilogram
+	})).Return(testProduct(), nil)
+	schema := NewSchema(products, new(MockStoreUseCase), clock.Real())
+
+	data := exec(t, schema, nil, `mutation { createProduct(input: {storeId: "1", name: "Flour", amount: "2.5", unit: "kg", price: 3.2}) { id } }`, nil)
+
+	assert.Equal(t, map[string]any{"id": "1"}, data["createProduct"])
+	products.AssertExpectations(t)
+}
+
+func TestSchema_MutationsMapErrors(t *testing.T) {
+	products := new(MockProductUseCase)
+	products.On("UpdateProduct", mock.Anything, int64(1), mock.Anything).Return(nil, domain.ErrConflict)
+	products.On("DeleteProduct", mock.Anything, int64(1)).Return(domain.ErrProductInBundle)
+	products.On("DeleteProduct", mock.Anything, int64(2)).Return(errors.New("connection reset"))
+	stores := new(MockStoreUseCase)
+	stores.On("DeleteStore", mock.Anything, int64(1)).Return(domain.ErrStoreHasProducts)
+	schema := NewSchema(products, stores, clock.Real())
+
+	assert.Equal(t, "version_conflict", errorCode(t, schema, `mutation { updateProduct(id: "1", version: 2, input: {storeId: "1", name: "Flour", amount: "1", price: 1}) { id } }`, false))
+	assert.Equal(t, "product_in_bundle", errorCode(t, schema, `mutation { deleteProduct(id: "1") }`, false))
+	assert.Equal(t, "internal_server_error", errorCode(t, schema, `mutation { deleteProduct(id: "2") }`, false))
+	assert.Equal(t, "store_has_products", errorCode(t, schema, `mutation { deleteStore(id: "1") }`, false))
+}
+
+func TestSchema_DeleteProductConfirmsMassOperation(t *testing.T) {
+	products := new(MockProductUseCase)
+	products.On("DeleteProduct", mock.MatchedBy(usecase.MassOperationConfirmed), int64(1)).Return(nil)
+	schema := NewSchema(products, new(MockStoreUseCase), clock.Real())
+
+	data := exec(t, schema, nil, `mutation { deleteProduct(id: "1", confirmMassOperation: true) }`, nil)
+
+	assert.Equal(t, "1", data["deleteProduct"])
+	products.AssertExpectations(t)
+}
+
+func TestSchema_ReadOnlyRefusesMutations(t *testing.T) {
+	stores := new(MockStoreUseCase)
+	schema := NewSchema(new(MockProductUseCase), stores, clock.Real())
+
+	assert.Equal(t, "method_not_allowed", errorCode(t, schema, `mutation { createStore(input: {name: "Mill"}) { id } }`, true))
+	stores.AssertNotCalled(t, "CreateStore", mock.Anything, mock.Anything)
+}
+
+func TestSchema_LimitsDepth(t *testing.T) {
+	schema := NewSchema(new(MockProductUseCase), new(MockStoreUseCase), clock.Real())
+
+	resp := schema.Exec(context.Background(), nil, Request{
+		Query: `{ __schema { types { fields { type { ofType { ofType { ofType { ofType { name } } } } } } } } }`,
+	}, false)
+
+	assert.NotEmpty(t, resp.Errors)
+	assert.Empty(t, resp.Data)
+}
@@ -0,0 +1,35 @@
+package graphql
+
+import (
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type storeResolver struct {
+	store *domain.Store
+}
+
+func (r *storeResolver) ID() graphql.ID {
+	return formatID(r.store.ID)
+}
+
+func (r *storeResolver) Name() string {
+	return r.store.Name
+}
+
+func (r *storeResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.store.CreatedAt}
+}
+
+func (r *storeResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.store.UpdatedAt}
+}
+
+type storeInput struct {
+	Name string
+}
+
+func (in *storeInput) toDomain() *domain.Store {
+	return &domain.Store{Name: in.Name}
+}
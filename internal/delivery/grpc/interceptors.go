@@ -0,0 +1,332 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/apikey"
+	"backend-context-engineering-template/pkg/authtoken"
+	"backend-context-engineering-template/pkg/lifecycle"
+	"backend-context-engineering-template/pkg/telemetry"
+	"backend-context-engineering-template/pkg/workload"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type contextKey int
+
+const (
+	apiKeyContextKey contextKey = iota
+	serviceRoleContextKey
+	userContextKey
+)
+
+// APIKey returns the validated key the call was made with, or nil.
+func APIKey(ctx context.Context) *apikey.Key {
+	key, _ := ctx.Value(apiKeyContextKey).(*apikey.Key)
+	return key
+}
+
+// User returns the user the call was authenticated as, or nil. Only the
+// ID and email from the access token are set.
+func User(ctx context.Context) *domain.User {
+	user, _ := ctx.Value(userContextKey).(*domain.User)
+	return user
+}
+
+// ServiceRole returns the role of the calling workload, or "" for callers
+// that are not authenticated workloads.
+func ServiceRole(ctx context.Context) string {
+	role, _ := ctx.Value(serviceRoleContextKey).(string)
+	return role
+}
+
+// serverStream overrides the context of a stream and validates the
+// messages it receives.
+type serverStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	validate bool
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.validate {
+		return validate(m)
+	}
+	return nil
+}
+
+func withContext(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &serverStream{ServerStream: ss, ctx: ctx}
+}
+
+func loggingUnary(logger *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func loggingStream(logger *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(ss.Context(), logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logCall(ctx context.Context, logger *logrus.Logger, method string, start time.Time, err error) {
+	fields := logrus.Fields{
+		"method":  method,
+		"code":    status.Code(err).String(),
+		"latency": time.Since(start),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		fields["peer"] = p.Addr.String()
+	}
+	if err != nil {
+		fields["error"] = status.Convert(err).Message()
+	}
+	logger.WithFields(fields).Info("gRPC Request")
+}
+
+type metrics struct {
+	requests *telemetry.Counter
+	duration *telemetry.Histogram
+}
+
+// newMetrics counts calls and records their latency per method, like the
+// HTTP Metrics middleware does per route.
+func newMetrics(registry *telemetry.Registry) *metrics {
+	return &metrics{
+		requests: registry.NewCounter("grpc_server_requests_total", "gRPC calls served.", "rpc.method", "rpc.grpc.status_code"),
+		duration: registry.NewHistogram("grpc_server_request_duration_seconds", "gRPC call latency.", telemetry.DefaultDurationBuckets, "rpc.method"),
+	}
+}
+
+func (m *metrics) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	m.record(info.FullMethod, start, err)
+	return resp, err
+}
+
+func (m *metrics) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	m.record(info.FullMethod, start, err)
+	return err
+}
+
+func (m *metrics) record(method string, start time.Time, err error) {
+	m.requests.Inc(method, status.Code(err).String())
+	m.duration.Observe(time.Since(start).Seconds(), method)
+}
+
+// recoveryUnary turns a panicking handler into an Internal error, so one
+// bad call does not take the process down.
+func recoveryUnary(logger *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer recoverCall(logger, info.FullMethod, &err)
+		return handler(ctx, req)
+	}
+}
+
+func recoveryStream(logger *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer recoverCall(logger, info.FullMethod, &err)
+		return handler(srv, ss)
+	}
+}
+
+func recoverCall(logger *logrus.Logger, method string, err *error) {
+	if r := recover(); r != nil {
+		logger.WithFields(logrus.Fields{
+			"method": method,
+			"panic":  r,
+			"stack":  string(debug.Stack()),
+		}).Error("Recovered from panic")
+		*err = status.Error(codes.Internal, "An internal error occurred")
+	}
+}
+
+// authenticator identifies callers the way the HTTP APIKey, User and
+// Workload middleware do: by a validated x-api-key, by a user's access
+// token, or by workload identity from a client certificate or bearer
+// token. Calls without credentials pass through anonymously.
+type authenticator struct {
+	apiKeys  apikey.Store
+	tokens   *authtoken.Issuer
+	verifier *workload.Verifier
+	roles    workload.Roles
+	logger   *logrus.Logger
+}
+
+func (a *authenticator) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, withContext(ss, ctx))
+}
+
+func (a *authenticator) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if raw := first(md, "x-api-key"); raw != "" {
+		key, err := a.apiKeys.Lookup(ctx, apikey.Hash(raw))
+		switch {
+		case err == nil:
+			return context.WithValue(ctx, apiKeyContextKey, key), nil
+		case errors.Is(err, apikey.ErrNotFound):
+			return nil, status.Error(codes.Unauthenticated, "The x-api-key is unknown or revoked")
+		default:
+			a.logger.WithError(err).Error("Failed to look up API key")
+			return nil, status.Error(codes.Unavailable, "API keys cannot be checked right now")
+		}
+	}
+
+	if token, ok := bearerToken(md); ok && a.tokens != nil && a.tokens.Issued(token) {
+		claims, err := a.tokens.Verify(token, authtoken.KindAccess)
+		if err != nil {
+			a.logger.WithError(err).Debug("Rejected access token")
+			return nil, status.Error(codes.Unauthenticated, "The access token is invalid or expired")
+		}
+		id, _ := claims.UserID()
+		return context.WithValue(ctx, userContextKey, &domain.User{ID: id, Email: claims.Email}), nil
+	}
+
+	var (
+		identity workload.Identity
+		found    bool
+	)
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+			if id, err := workload.FromCertificate(info.State.PeerCertificates[0]); err == nil {
+				identity, found = id, true
+			}
+		}
+	}
+	if token, ok := bearerToken(md); !found && ok && a.verifier != nil {
+		id, err := a.verifier.Verify(ctx, token)
+		if err != nil {
+			a.logger.WithError(err).Warn("Rejected workload token")
+			return nil, status.Error(codes.Unauthenticated, "The workload identity token is invalid")
+		}
+		identity, found = id, true
+	}
+	if !found {
+		return ctx, nil
+	}
+
+	role, err := a.roles.Role(identity)
+	if err != nil {
+		if !errors.Is(err, workload.ErrUnmappedRole) {
+			a.logger.WithError(err).Error("Failed to resolve workload role")
+		}
+		return nil, status.Error(codes.PermissionDenied, "Workload "+identity.ID+" has no service role")
+	}
+	return context.WithValue(ctx, serviceRoleContextKey, role), nil
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func bearerToken(md metadata.MD) (string, bool) {
+	header := first(md, "authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return "", false
+	}
+	token := strings.TrimSpace(header[7:])
+	return token, token != ""
+}
+
+// inFlightUnary counts calls as in flight so a drain waits for them, like
+// the HTTP InFlight middleware. Health checks are not counted. manager may
+// be nil.
+func inFlightUnary(manager *lifecycle.Manager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if manager == nil || isHealthCheck(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		manager.Begin()
+		defer manager.End()
+		return handler(ctx, req)
+	}
+}
+
+func inFlightStream(manager *lifecycle.Manager) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if manager == nil || isHealthCheck(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		manager.Begin()
+		defer manager.End()
+		return handler(srv, ss)
+	}
+}
+
+func isHealthCheck(method string) bool {
+	return strings.HasPrefix(method, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
+
+// validator is implemented by request messages that check their own
+// fields, as generated by protoc-gen-validate.
+type validator interface {
+	Validate() error
+}
+
+func validate(req any) error {
+	if v, ok := req.(validator); ok {
+		if err := v.Validate(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	return nil
+}
+
+// validateUnary rejects invalid requests with InvalidArgument before they
+// reach the handler.
+func validateUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := validate(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func validateStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ss.Context(), validate: true})
+}
@@ -0,0 +1,257 @@
+package grpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	productv1 "backend-context-engineering-template/api/product/v1"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultListLimit = 10
+	// streamTimeout bounds StreamProducts like the HTTP stream, so a slow
+	// reader cannot hold a database connection indefinitely.
+	streamTimeout = 5 * time.Minute
+)
+
+// productService serves product.v1.ProductService from the same use case
+// as /api/v1/products.
+type productService struct {
+	productv1.UnimplementedProductServiceServer
+
+	productUseCase usecase.ProductUseCaseInterface
+	// protect turns away anonymous callers, like ProtectProducts on HTTP.
+	protect bool
+	clock   clock.Clock
+	logger  *logrus.Logger
+}
+
+func (s *productService) CreateProduct(ctx context.Context, req *productv1.CreateProductRequest) (*productv1.Product, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	product, err := s.productUseCase.CreateProduct(ctx, toDomainProduct(req.GetProduct()))
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return toProtoProduct(product, s.clock.Now()), nil
+}
+
+func (s *productService) GetProduct(ctx context.Context, req *productv1.GetProductRequest) (*productv1.Product, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	product, err := s.productUseCase.GetProduct(ctx, req.GetId())
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	if product.Status == domain.ProductStatusDraft && !ownsStore(ctx, product.StoreID) {
+		return nil, s.toStatus(domain.ErrProductNotFound)
+	}
+	return toProtoProduct(product, s.clock.Now()), nil
+}
+
+func (s *productService) ListProducts(ctx context.Context, req *productv1.ListProductsRequest) (*productv1.ListProductsResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	offset := int(req.GetOffset())
+
+	var products []*domain.Product
+	var err error
+	if availability := req.GetAvailability(); availability != "" {
+		products, err = s.productUseCase.GetProductsByAvailability(ctx, availability, limit, offset)
+	} else {
+		products, err = s.productUseCase.GetProducts(ctx, domain.ProductFilter{}, limit, offset)
+	}
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+
+	now := s.clock.Now()
+	resp := &productv1.ListProductsResponse{
+		Products: make([]*productv1.Product, len(products)),
+		Total:    int32(len(products)),
+		Limit:    int32(limit),
+		Offset:   int32(offset),
+	}
+	for i, product := range products {
+		resp.Products[i] = toProtoProduct(product, now)
+	}
+	return resp, nil
+}
+
+func (s *productService) StreamProducts(req *productv1.StreamProductsRequest, stream grpc.ServerStreamingServer[productv1.Product]) error {
+	ctx, cancel := context.WithTimeout(stream.Context(), streamTimeout)
+	defer cancel()
+
+	if err := s.authorize(ctx); err != nil {
+		return err
+	}
+
+	now := s.clock.Now()
+	err := s.productUseCase.StreamProducts(ctx, func(product *domain.Product) error {
+		return stream.Send(toProtoProduct(product, now))
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return s.toStatus(err)
+	}
+	return nil
+}
+
+func (s *productService) UpdateProduct(ctx context.Context, req *productv1.UpdateProductRequest) (*productv1.Product, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	product, err := s.productUseCase.UpdateProduct(ctx, req.GetId(), toDomainProduct(req.GetProduct()))
+	if err != nil {
+		return nil, s.toStatus(err)
+	}
+	return toProtoProduct(product, s.clock.Now()), nil
+}
+
+func (s *productService) DeleteProduct(ctx context.Context, req *productv1.DeleteProductRequest) (*productv1.DeleteProductResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if req.GetConfirmMassOperation() {
+		ctx = usecase.WithMassOperationConfirmed(ctx)
+	}
+	if err := s.productUseCase.DeleteProduct(ctx, req.GetId()); err != nil {
+		return nil, s.toStatus(err)
+	}
+	return &productv1.DeleteProductResponse{}, nil
+}
+
+func (s *productService) authorize(ctx context.Context) error {
+	if s.protect && APIKey(ctx) == nil && User(ctx) == nil && ServiceRole(ctx) == "" {
+		return status.Error(codes.Unauthenticated, "An x-api-key, access token or workload identity is required")
+	}
+	return nil
+}
+
+// ownsStore reports whether the call was made with an API key for the
+// store. Drafts are shown only to such callers; preview links are served
+// over HTTP.
+func ownsStore(ctx context.Context, storeID int64) bool {
+	key := APIKey(ctx)
+	return key != nil && slices.Contains(key.StoreIDs, storeID)
+}
+
+// toStatus maps use case errors to the codes closest to the HTTP API's
+// statuses.
+func (s *productService) toStatus(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound):
+		return status.Error(codes.NotFound, "Product not found")
+	case errors.Is(err, domain.ErrInvalidProduct):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrDuplicateProduct):
+		return status.Error(codes.AlreadyExists, "Product with this name already exists")
+	case errors.Is(err, domain.ErrConflict):
+		return status.Error(codes.Aborted, "The product was changed since this version")
+	case errors.Is(err, domain.ErrStoreNotFound):
+		return status.Error(codes.FailedPrecondition, "The product's store does not exist")
+	case errors.Is(err, domain.ErrProductInBundle):
+		return status.Error(codes.FailedPrecondition, "Product is a component of a bundle; remove it from the bundle first")
+	case errors.Is(err, domain.ErrContentRejected):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrConfirmationRequired):
+		return status.Error(codes.FailedPrecondition, err.Error()+"; retry with confirm_mass_operation set")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		s.logger.WithError(err).Error("Internal server error")
+		return status.Error(codes.Internal, "An internal error occurred")
+	}
+}
+
+// toDomainProduct converts a validated ProductInput. Quantities have
+// already been checked to parse.
+func toDomainProduct(input *productv1.ProductInput) *domain.Product {
+	description := sql.NullString{}
+	if input.GetDescription() != "" {
+		description = sql.NullString{String: input.GetDescription(), Valid: true}
+	}
+
+	product := &domain.Product{
+		StoreID:           input.GetStoreId(),
+		Name:              input.GetName(),
+		Description:       description,
+		DescriptionFormat: input.GetDescriptionFormat(),
+		Amount:            parseQuantity(input.GetAmount()),
+		Unit:              input.GetUnit(),
+		Price:             input.GetPrice(),
+		Status:            input.GetStatus(),
+
+		AllowBackorder:    input.GetAllowBackorder(),
+		BackorderLimit:    parseQuantity(input.GetBackorderLimit()),
+		LowStockThreshold: parseQuantity(input.GetLowStockThreshold()),
+	}
+	if ts := input.GetPreorderReleaseDate(); ts != nil {
+		releaseDate := ts.AsTime()
+		product.PreorderReleaseDate = &releaseDate
+	}
+	return product
+}
+
+func parseQuantity(s string) quantity.Quantity {
+	if s == "" {
+		return quantity.Quantity{}
+	}
+	q, _ := quantity.Parse(s)
+	return q
+}
+
+// toProtoProduct builds the message with the product's availability at
+// now.
+func toProtoProduct(product *domain.Product, now time.Time) *productv1.Product {
+	msg := &productv1.Product{
+		Id:                product.ID,
+		StoreId:           product.StoreID,
+		Name:              product.Name,
+		Description:       product.Description.String,
+		DescriptionFormat: product.DescriptionFormat,
+		Amount:            product.Amount.String(),
+		Unit:              product.Unit,
+		Price:             product.Price,
+		Status:            product.Status,
+		ModerationStatus:  product.ModerationStatus,
+		ModerationReason:  product.ModerationReason.String,
+
+		AllowBackorder:    product.AllowBackorder,
+		BackorderLimit:    product.BackorderLimit.String(),
+		LowStockThreshold: product.LowStockThreshold.String(),
+		Availability:      product.Availability(now),
+
+		CreatedAt: timestamppb.New(product.CreatedAt),
+		UpdatedAt: timestamppb.New(product.UpdatedAt),
+	}
+	if product.PreorderReleaseDate != nil {
+		msg.PreorderReleaseDate = timestamppb.New(*product.PreorderReleaseDate)
+	}
+	return msg
+}
@@ -0,0 +1,256 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	productv1 "backend-context-engineering-template/api/product/v1"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/apikey"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/quantity"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type MockProductUseCase struct {
+	mock.Mock
+}
+
+func (m *MockProductUseCase) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	args := m.Called(ctx, product)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) GetProduct(ctx context.Context, id int64) (*domain.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) GetProducts(ctx context.Context, filter domain.ProductFilter, limit, offset int) ([]*domain.Product, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) GetProductsByAvailability(ctx context.Context, availability string, limit, offset int) ([]*domain.Product, error) {
+	args := m.Called(ctx, availability, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) StreamProducts(ctx context.Context, fn func(*domain.Product) error) error {
+	args := m.Called(ctx)
+	for _, product := range args.Get(0).([]*domain.Product) {
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockProductUseCase) UpdateProduct(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
+	args := m.Called(ctx, id, product)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) UpdateProductPartial(ctx context.Context, id int64, patch *domain.ProductPatch) (*domain.Product, error) {
+	args := m.Called(ctx, id, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) DeleteProduct(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockProductUseCase) WriteProducts(ctx context.Context, items []domain.BulkProductItem) ([]*domain.BulkProductResult, error) {
+	args := m.Called(ctx, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.BulkProductResult), args.Error(1)
+}
+
+func (m *MockProductUseCase) ImportProducts(ctx context.Context, file io.Reader) (*domain.ProductImportReport, error) {
+	args := m.Called(ctx, file)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProductImportReport), args.Error(1)
+}
+
+func startProductService(t *testing.T, products usecase.ProductUseCaseInterface, protect bool) productv1.ProductServiceClient {
+	t.Helper()
+
+	conn := serve(t, ServerDeps{
+		APIKeys:         apikey.NewMemoryStore(&apikey.Key{ID: 1, Name: "test", Hash: apikey.Hash("secret-key"), StoreIDs: []int64{1}}),
+		Products:        products,
+		ProtectProducts: protect,
+		Clock:           clock.Real(),
+		Registry:        telemetry.NewRegistry(telemetry.Resource{}),
+		Logger:          logrus.New(),
+	})
+	return productv1.NewProductServiceClient(conn)
+}
+
+func testProduct() *domain.Product {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &domain.Product{
+		ID:                1,
+		StoreID:           1,
+		Name:              "Flour",
+		DescriptionFormat: domain.DescriptionFormatPlain,
+		Amount:            quantity.MustParse("2.5"),
+		Unit:              domain.UnitKilogram,
+		Price:             3.2,
+		Status:            domain.ProductStatusActive,
+		ModerationStatus:  domain.ModerationStatusApproved,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+}
+
+func TestProductService_CreateProduct(t *testing.T) {
+	products := new(MockProductUseCase)
+	products.On("CreateProduct", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+		return p.Name == "Flour" && p.Amount == quantity.MustParse("2.5") && p.Unit == domain.UnitKilogram
+	})).Return(testProduct(), nil)
+	client := startProductService(t, products, false)
+
+	resp, err := client.CreateProduct(context.Background(), &productv1.CreateProductRequest{
+		Product: &productv1.ProductInput{StoreId: 1, Name: "Flour", Amount: "2.5", Unit: "kg", Price: 3.2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Id)
+	assert.Equal(t, "2.5", resp.Amount)
+	assert.Equal(t, domain.AvailabilityInStock, resp.Availability)
+	assert.Equal(t, int64(1704067200), resp.CreatedAt.GetSeconds())
+	products.AssertExpectations(t)
+}
+
+func TestProductService_RejectsInvalidRequests(t *testing.T) {
+	client := startProductService(t, new(MockProductUseCase), false)
+
+	_, err := client.CreateProduct(context.Background(), &productv1.CreateProductRequest{
+		Product: &productv1.ProductInput{StoreId: 1, Name: "Flour", Amount: "a lot"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.GetProduct(context.Background(), &productv1.GetProductRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestProductService_MapsErrors(t *testing.T) {
+	products := new(MockProductUseCase)
+	products.On("GetProduct", mock.Anything, int64(404)).Return(nil, domain.ErrProductNotFound)
+	products.On("DeleteProduct", mock.Anything, int64(1)).Return(domain.ErrConfirmationRequired)
+	client := startProductService(t, products, false)
+
+	_, err := client.GetProduct(context.Background(), &productv1.GetProductRequest{Id: 404})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.DeleteProduct(context.Background(), &productv1.DeleteProductRequest{Id: 1})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestProductService_GetProductHidesDrafts(t *testing.T) {
+	draft := testProduct()
+	draft.Status = domain.ProductStatusDraft
+	products := new(MockProductUseCase)
+	products.On("GetProduct", mock.Anything, int64(1)).Return(draft, nil)
+	client := startProductService(t, products, false)
+
+	_, err := client.GetProduct(context.Background(), &productv1.GetProductRequest{Id: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-key")
+	product, err := client.GetProduct(ctx, &productv1.GetProductRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ProductStatusDraft, product.GetStatus())
+}
+
+func TestProductService_DeleteProductConfirmsMassOperation(t *testing.T) {
+	products := new(MockProductUseCase)
+	products.On("DeleteProduct", mock.MatchedBy(usecase.MassOperationConfirmed), int64(1)).Return(nil)
+	client := startProductService(t, products, false)
+
+	_, err := client.DeleteProduct(context.Background(), &productv1.DeleteProductRequest{Id: 1, ConfirmMassOperation: true})
+	require.NoError(t, err)
+	products.AssertExpectations(t)
+}
+
+func TestProductService_ListProducts(t *testing.T) {
+	products := new(MockProductUseCase)
+	products.On("GetProducts", mock.Anything, domain.ProductFilter{}, 10, 0).Return([]*domain.Product{testProduct()}, nil)
+	products.On("GetProductsByAvailability", mock.Anything, domain.AvailabilityLowStock, 5, 10).Return([]*domain.Product{}, nil)
+	client := startProductService(t, products, false)
+
+	resp, err := client.ListProducts(context.Background(), &productv1.ListProductsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, resp.Products, 1)
+	assert.Equal(t, int32(10), resp.Limit)
+
+	resp, err = client.ListProducts(context.Background(), &productv1.ListProductsRequest{Limit: 5, Offset: 10, Availability: domain.AvailabilityLowStock})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Products)
+	products.AssertExpectations(t)
+}
+
+func TestProductService_StreamProducts(t *testing.T) {
+	second := testProduct()
+	second.ID = 2
+	products := new(MockProductUseCase)
+	products.On("StreamProducts", mock.Anything).Return([]*domain.Product{testProduct(), second}, nil)
+	client := startProductService(t, products, false)
+
+	stream, err := client.StreamProducts(context.Background(), &productv1.StreamProductsRequest{})
+	require.NoError(t, err)
+
+	var ids []int64
+	for {
+		product, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, product.Id)
+	}
+	assert.Equal(t, []int64{1, 2}, ids)
+}
+
+func TestProductService_ProtectProducts(t *testing.T) {
+	products := new(MockProductUseCase)
+	products.On("GetProduct", mock.Anything, int64(1)).Return(testProduct(), nil)
+	client := startProductService(t, products, true)
+
+	_, err := client.GetProduct(context.Background(), &productv1.GetProductRequest{Id: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-key")
+	_, err = client.GetProduct(ctx, &productv1.GetProductRequest{Id: 1})
+	assert.NoError(t, err)
+}
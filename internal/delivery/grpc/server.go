@@ -0,0 +1,133 @@
+// Package grpc serves the service over gRPC next to the HTTP API. Its
+// interceptors mirror the HTTP middleware stack, so both transports log,
+// measure, authenticate and validate calls the same way.
+package grpc
+
+import (
+	"context"
+
+	productv1 "backend-context-engineering-template/api/product/v1"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/apikey"
+	"backend-context-engineering-template/pkg/authtoken"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/lifecycle"
+	"backend-context-engineering-template/pkg/telemetry"
+	"backend-context-engineering-template/pkg/workload"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// ServerDeps is everything NewServer wires into the server.
+type ServerDeps struct {
+	APIKeys apikey.Store
+	// UserTokens may be nil when user accounts are disabled.
+	UserTokens *authtoken.Issuer
+	// WorkloadVerifier may be nil to accept client certificates only.
+	WorkloadVerifier *workload.Verifier
+	WorkloadRoles    workload.Roles
+	// Reflection lists the server's services to tools such as grpcurl. It
+	// is meant for non-production environments.
+	Reflection bool
+
+	// Products serves product.v1.ProductService when set.
+	Products usecase.ProductUseCaseInterface
+	// ProtectProducts turns away anonymous callers from ProductService.
+	ProtectProducts bool
+
+	Clock            clock.Clock
+	LifecycleManager *lifecycle.Manager
+	Registry         *telemetry.Registry
+	Logger           *logrus.Logger
+	// Options are extra server options, such as TLS credentials.
+	Options []grpc.ServerOption
+}
+
+// Server is a gRPC server with the standard health service registered. It
+// reports not serving until SetServing(true).
+type Server struct {
+	*grpc.Server
+	health *health.Server
+}
+
+func NewServer(deps ServerDeps) *Server {
+	metrics := newMetrics(deps.Registry)
+	auth := &authenticator{
+		apiKeys:  deps.APIKeys,
+		tokens:   deps.UserTokens,
+		verifier: deps.WorkloadVerifier,
+		roles:    deps.WorkloadRoles,
+		logger:   deps.Logger,
+	}
+
+	opts := append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			loggingUnary(deps.Logger),
+			metrics.unary,
+			recoveryUnary(deps.Logger),
+			auth.unary,
+			inFlightUnary(deps.LifecycleManager),
+			validateUnary,
+		),
+		grpc.ChainStreamInterceptor(
+			loggingStream(deps.Logger),
+			metrics.stream,
+			recoveryStream(deps.Logger),
+			auth.stream,
+			inFlightStream(deps.LifecycleManager),
+			validateStream,
+		),
+	}, deps.Options...)
+
+	s := &Server{
+		Server: grpc.NewServer(opts...),
+		health: health.NewServer(),
+	}
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(s.Server, s.health)
+	if deps.Products != nil {
+		productv1.RegisterProductServiceServer(s.Server, &productService{
+			productUseCase: deps.Products,
+			protect:        deps.ProtectProducts,
+			clock:          deps.Clock,
+			logger:         deps.Logger,
+		})
+	}
+	if deps.Reflection {
+		reflection.Register(s.Server)
+	}
+	return s
+}
+
+// SetServing sets the status the health service reports for the server.
+// It follows the lifecycle manager's readiness through OnReadyChange.
+func (s *Server) SetServing(serving bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	s.health.SetServingStatus("", status)
+}
+
+// Shutdown reports not serving, stops accepting calls and waits for
+// running ones until ctx is done, when the rest are cancelled.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		s.GracefulStop()
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.Stop()
+		<-stopped
+	}
+}
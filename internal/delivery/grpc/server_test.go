@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"backend-context-engineering-template/pkg/apikey"
+	"backend-context-engineering-template/pkg/lifecycle"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func startServer(t *testing.T, manager *lifecycle.Manager) *grpc.ClientConn {
+	t.Helper()
+
+	return serve(t, ServerDeps{
+		APIKeys:          apikey.NewMemoryStore(&apikey.Key{ID: 1, Name: "test", Hash: apikey.Hash("secret-key")}),
+		Reflection:       true,
+		LifecycleManager: manager,
+		Registry:         telemetry.NewRegistry(telemetry.Resource{}),
+		Logger:           logrus.New(),
+	})
+}
+
+// serve starts a server over an in-memory listener and returns a client
+// connection to it.
+func serve(t *testing.T, deps ServerDeps) *grpc.ClientConn {
+	t.Helper()
+
+	server := NewServer(deps)
+	if deps.LifecycleManager != nil {
+		deps.LifecycleManager.OnReadyChange(server.SetServing)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = server.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Shutdown(context.Background())
+		<-done
+	})
+	return conn
+}
+
+func TestServer_HealthFollowsReadiness(t *testing.T) {
+	manager := lifecycle.New()
+	client := healthpb.NewHealthClient(startServer(t, manager))
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	manager.SetReady(true)
+	resp, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+	assert.Zero(t, manager.InFlight(), "health checks are not counted as in flight")
+}
+
+func TestServer_RejectsUnknownAPIKey(t *testing.T) {
+	client := healthpb.NewHealthClient(startServer(t, lifecycle.New()))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "wrong-key")
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "secret-key")
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+type checkedRequest struct {
+	err error
+}
+
+func (r checkedRequest) Validate() error {
+	return r.err
+}
+
+func TestInterceptors(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+	ok := func(ctx context.Context, req any) (any, error) { return "done", nil }
+
+	_, err := validateUnary(context.Background(), checkedRequest{err: errors.New("name is required")}, info, ok)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "name is required", status.Convert(err).Message())
+
+	resp, err := validateUnary(context.Background(), checkedRequest{}, info, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", resp)
+
+	_, err = recoveryUnary(logrus.New())(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
@@ -0,0 +1,15 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// AnalyticsRoutes serves product stats and takes in storefront events.
+func AnalyticsRoutes(handler *handlers.AnalyticsHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Products.GET("/:id/stats", handler.GetProductStats)
+		// Storefronts report events anonymously, so ingestion is open to
+		// every caller.
+		r.API.POST("/analytics/events", handler.RecordEvents)
+	})
+}
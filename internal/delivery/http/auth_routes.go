@@ -0,0 +1,17 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// AuthRoutes registers and logs in users under /api/v1/auth.
+func AuthRoutes(handler *handlers.AuthHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		auth := r.API.Group("/auth")
+		{
+			auth.POST("/register", handler.Register)
+			auth.POST("/login", handler.Login)
+			auth.POST("/refresh", handler.Refresh)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// BundleRoutes serves bundles under /api/v1/bundles.
+func BundleRoutes(handler *handlers.BundleHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		bundles := r.API.Group("/bundles")
+		{
+			bundles.GET("/:id", handler.GetBundle)
+			bundles.PUT("/:id", handler.SaveBundle)
+			bundles.DELETE("/:id", handler.DeleteBundle)
+			bundles.POST("/:id/sales", handler.SellBundle)
+		}
+	})
+}
@@ -0,0 +1,13 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// CacheRoutes serves the cache's hot keys and stats under /admin.
+func CacheRoutes(handler *handlers.CacheHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Admin.GET("/cache/hot-keys", handler.GetHotKeys)
+		r.Admin.GET("/cache/stats", handler.GetStats)
+	})
+}
@@ -0,0 +1,13 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// CatalogDiffRoutes serves the catalog diff. It is built from product
+// revisions, which only Postgres keeps.
+func CatalogDiffRoutes(handler *handlers.CatalogDiffHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Products.GET("/diff", handler.GetDiff)
+	})
+}
@@ -0,0 +1,21 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// CatalogSnapshotRoutes serves stores' catalog snapshots and the restores
+// rolling the catalog back to them.
+func CatalogSnapshotRoutes(handler *handlers.CatalogSnapshotHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		snapshots := r.API.Group("/stores/:id/snapshots")
+		{
+			snapshots.POST("", handler.CreateSnapshot)
+			snapshots.GET("", handler.GetSnapshots)
+			snapshots.GET("/:snapshot_id", handler.GetSnapshot)
+			snapshots.GET("/:snapshot_id/preview", handler.PreviewRestore)
+			snapshots.POST("/:snapshot_id/restores", handler.StartRestore)
+		}
+		r.API.GET("/stores/:id/restores/:restore_id", handler.GetRestore)
+	})
+}
@@ -0,0 +1,23 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// CategoryRoutes serves categories under /api/v1/categories and the
+// categories of a product under /api/v1/products/:id/categories.
+func CategoryRoutes(handler *handlers.CategoryHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		categories := r.API.Group("/categories")
+		{
+			categories.POST("", handler.CreateCategory)
+			categories.GET("", handler.GetCategories)
+			categories.GET("/:id", handler.GetCategory)
+			categories.PUT("/:id", handler.UpdateCategory)
+			categories.DELETE("/:id", handler.DeleteCategory)
+		}
+
+		r.Products.GET("/:id/categories", handler.GetProductCategories)
+		r.Products.PUT("/:id/categories", handler.SetProductCategories)
+	})
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// ConnectorRoutes serves connectors and their syncs under
+// /admin/connectors.
+func ConnectorRoutes(handler *handlers.ConnectorHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		connectors := r.Admin.Group("/connectors")
+		{
+			connectors.POST("", handler.CreateConnector)
+			connectors.GET("/:id", handler.GetConnector)
+			connectors.GET("", handler.GetConnectors)
+			connectors.DELETE("/:id", handler.DeleteConnector)
+			connectors.POST("/:id/syncs", handler.SyncConnector)
+			connectors.GET("/:id/syncs", handler.GetConnectorSyncs)
+			connectors.GET("/:id/syncs/:sync_id", handler.GetConnectorSync)
+		}
+	})
+}
@@ -0,0 +1,13 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// DBHealthRoutes reports on the database's health and indexes.
+func DBHealthRoutes(handler *handlers.DBHealthHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Admin.GET("/db/health", handler.GetReport)
+		r.Admin.GET("/db/index-advice", handler.GetIndexAdvice)
+	})
+}
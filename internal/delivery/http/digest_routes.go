@@ -0,0 +1,18 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// DigestRoutes serves stores' digest settings under
+// /api/v1/digest-settings.
+func DigestRoutes(handler *handlers.DigestHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		digests := r.API.Group("/digest-settings")
+		{
+			digests.GET("/:store_id", handler.GetSettings)
+			digests.PUT("/:store_id", handler.SaveSettings)
+			digests.DELETE("/:store_id", handler.DeleteSettings)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type AnalyticsEventRequest struct {
+	ProductID  int64      `json:"product_id" binding:"required,gt=0"`
+	Type       string     `json:"type" binding:"required,oneof=view add_to_cart purchase"`
+	OccurredAt *time.Time `json:"occurred_at"`
+}
+
+type RecordAnalyticsEventsRequest struct {
+	Events []AnalyticsEventRequest `json:"events" binding:"required,min=1,max=500,dive"`
+}
+
+// RecordAnalyticsEventsResponse counts the events kept and those dropped
+// because the service is behind on writing them.
+type RecordAnalyticsEventsResponse struct {
+	Accepted int `json:"accepted"`
+	Dropped  int `json:"dropped"`
+}
+
+// ProductStatsQuery selects the window of GET /products/:id/stats: the
+// last days UTC days, or all time when days is omitted.
+type ProductStatsQuery struct {
+	Days int `form:"days" binding:"omitempty,min=1,max=366"`
+}
+
+type ProductStatsResponse struct {
+	ProductID  int64  `json:"product_id"`
+	Views      int64  `json:"views"`
+	AddToCarts int64  `json:"add_to_carts"`
+	Purchases  int64  `json:"purchases"`
+	Since      string `json:"since,omitempty"`
+}
+
+func (r *RecordAnalyticsEventsRequest) ToDomain() []domain.AnalyticsEvent {
+	events := make([]domain.AnalyticsEvent, len(r.Events))
+	for i, event := range r.Events {
+		events[i] = domain.AnalyticsEvent{
+			ProductID: event.ProductID,
+			Type:      event.Type,
+		}
+		if event.OccurredAt != nil {
+			events[i].OccurredAt = *event.OccurredAt
+		}
+	}
+	return events
+}
+
+func ToProductStatsResponse(stats *domain.ProductStats) ProductStatsResponse {
+	response := ProductStatsResponse{
+		ProductID:  stats.ProductID,
+		Views:      stats.Views,
+		AddToCarts: stats.AddToCarts,
+		Purchases:  stats.Purchases,
+	}
+	if !stats.Since.IsZero() {
+		response.Since = stats.Since.Format(time.DateOnly)
+	}
+	return response
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// BulkConfirmationResponse answers a bulk operation held back for
+// confirmation. The caller confirms it by repeating the request with
+// confirmation_token before expires_at.
+type BulkConfirmationResponse struct {
+	Error             string `json:"error"`
+	Message           string `json:"message"`
+	Operation         string `json:"operation"`
+	StoreID           int64  `json:"store_id"`
+	Affected          int64  `json:"affected"`
+	ConfirmationToken string `json:"confirmation_token"`
+	ExpiresAt         string `json:"expires_at"`
+}
+
+func ToBulkConfirmationResponse(confirmation *domain.BulkConfirmation, message string) BulkConfirmationResponse {
+	return BulkConfirmationResponse{
+		Error:             "bulk_confirmation_required",
+		Message:           message,
+		Operation:         confirmation.Operation.Kind,
+		StoreID:           confirmation.Operation.StoreID,
+		Affected:          confirmation.Operation.Affected,
+		ConfirmationToken: confirmation.Token,
+		ExpiresAt:         confirmation.ExpiresAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+type BundleComponentRequest struct {
+	ProductID int64             `json:"product_id" binding:"required,min=1"`
+	Quantity  quantity.Quantity `json:"quantity"`
+}
+
+type SaveBundleRequest struct {
+	Components []BundleComponentRequest `json:"components" binding:"required,min=1,dive"`
+}
+
+type SellBundleRequest struct {
+	Count int64 `json:"count" binding:"required,min=1"`
+}
+
+type BundleComponentResponse struct {
+	ProductID int64             `json:"product_id"`
+	Name      string            `json:"name"`
+	Quantity  quantity.Quantity `json:"quantity"`
+	Unit      string            `json:"unit"`
+	Stock     quantity.Quantity `json:"stock"`
+	Available int64             `json:"available"`
+	CreatedAt string            `json:"created_at"`
+}
+
+// BundleResponse reports Available, the number of whole bundles the
+// components' stock makes, in place of the bundle product's own amount.
+type BundleResponse struct {
+	ID         int64                     `json:"id"`
+	StoreID    int64                     `json:"store_id"`
+	Name       string                    `json:"name"`
+	Price      float64                   `json:"price"`
+	Status     string                    `json:"status"`
+	Available  int64                     `json:"available"`
+	Components []BundleComponentResponse `json:"components"`
+}
+
+func (r *SaveBundleRequest) ToDomain() []domain.BundleComponent {
+	components := make([]domain.BundleComponent, len(r.Components))
+	for i, component := range r.Components {
+		components[i] = domain.BundleComponent{ProductID: component.ProductID, Quantity: component.Quantity}
+	}
+	return components
+}
+
+func ToBundleResponse(bundle *domain.Bundle) BundleResponse {
+	response := BundleResponse{
+		ID:         bundle.Product.ID,
+		StoreID:    bundle.Product.StoreID,
+		Name:       bundle.Product.Name,
+		Price:      bundle.Product.Price,
+		Status:     bundle.Product.Status,
+		Available:  bundle.Available,
+		Components: make([]BundleComponentResponse, len(bundle.Components)),
+	}
+	for i, component := range bundle.Components {
+		response.Components[i] = BundleComponentResponse{
+			ProductID: component.ProductID,
+			Name:      component.Name,
+			Quantity:  component.Quantity,
+			Unit:      component.Unit,
+			Stock:     component.Stock,
+			Available: component.Available,
+			CreatedAt: component.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return response
+}
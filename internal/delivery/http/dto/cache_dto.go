@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"sort"
+
+	"backend-context-engineering-template/pkg/cache"
+	"backend-context-engineering-template/pkg/hotkeys"
+)
+
+type HotKeyResponse struct {
+	ProductID int64  `json:"product_id"`
+	Hits      uint64 `json:"hits"`
+}
+
+type HotKeysResponse struct {
+	HotKeys []HotKeyResponse `json:"hot_keys"`
+	Count   int              `json:"count"`
+}
+
+func ToHotKeysResponse(keys []hotkeys.HotKey) HotKeysResponse {
+	responses := make([]HotKeyResponse, len(keys))
+	for i, key := range keys {
+		responses[i] = HotKeyResponse{ProductID: key.ID, Hits: key.Hits}
+	}
+
+	return HotKeysResponse{
+		HotKeys: responses,
+		Count:   len(responses),
+	}
+}
+
+type CacheStatsResponse struct {
+	Tiers     []CacheTierStatsResponse     `json:"tiers"`
+	Endpoints []CacheEndpointStatsResponse `json:"endpoints"`
+}
+
+type CacheTierStatsResponse struct {
+	Tier          string  `json:"tier"`
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	Expired       int64   `json:"expired"`
+	HitRatio      float64 `json:"hit_ratio"`
+	BytesSaved    int64   `json:"bytes_saved"`
+	Invalidations int64   `json:"invalidations"`
+}
+
+type CacheEndpointStatsResponse struct {
+	Endpoint   string  `json:"endpoint"`
+	Tier       string  `json:"tier"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	Expired    int64   `json:"expired"`
+	HitRatio   float64 `json:"hit_ratio"`
+	BytesSaved int64   `json:"bytes_saved"`
+}
+
+// ToCacheStatsResponse reports each endpoint's lookups and their totals
+// per tier.
+func ToCacheStatsResponse(endpoints []cache.EndpointStats, invalidations map[string]int64) CacheStatsResponse {
+	totals := make(map[string]*cache.EndpointStats)
+	for tier := range invalidations {
+		totals[tier] = &cache.EndpointStats{Tier: tier}
+	}
+
+	response := CacheStatsResponse{Endpoints: make([]CacheEndpointStatsResponse, len(endpoints))}
+	for i, e := range endpoints {
+		response.Endpoints[i] = CacheEndpointStatsResponse{
+			Endpoint:   e.Endpoint,
+			Tier:       e.Tier,
+			Hits:       e.Hits,
+			Misses:     e.Misses,
+			Expired:    e.Expired,
+			HitRatio:   e.HitRatio(),
+			BytesSaved: e.BytesSaved,
+		}
+
+		total, ok := totals[e.Tier]
+		if !ok {
+			total = &cache.EndpointStats{Tier: e.Tier}
+			totals[e.Tier] = total
+		}
+		total.Hits += e.Hits
+		total.Misses += e.Misses
+		total.Expired += e.Expired
+		total.BytesSaved += e.BytesSaved
+	}
+
+	response.Tiers = make([]CacheTierStatsResponse, 0, len(totals))
+	for tier, total := range totals {
+		response.Tiers = append(response.Tiers, CacheTierStatsResponse{
+			Tier:          tier,
+			Hits:          total.Hits,
+			Misses:        total.Misses,
+			Expired:       total.Expired,
+			HitRatio:      total.HitRatio(),
+			BytesSaved:    total.BytesSaved,
+			Invalidations: invalidations[tier],
+		})
+	}
+	sort.Slice(response.Tiers, func(i, j int) bool { return response.Tiers[i].Tier < response.Tiers[j].Tier })
+	return response
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type ProductDiffEntry struct {
+	ID      int64  `json:"id"`
+	StoreID int64  `json:"store_id"`
+	Name    string `json:"name"`
+	// Fields lists the changed fields of a changed product.
+	Fields []string `json:"fields,omitempty"`
+}
+
+type CatalogDiffResponse struct {
+	From    string             `json:"from"`
+	To      string             `json:"to"`
+	Created []ProductDiffEntry `json:"created"`
+	Deleted []ProductDiffEntry `json:"deleted"`
+	Changed []ProductDiffEntry `json:"changed"`
+}
+
+func ToCatalogDiffResponse(diff *domain.CatalogDiff) CatalogDiffResponse {
+	response := CatalogDiffResponse{
+		From:    diff.From.Format(time.RFC3339),
+		To:      diff.To.Format(time.RFC3339),
+		Created: make([]ProductDiffEntry, len(diff.Created)),
+		Deleted: make([]ProductDiffEntry, len(diff.Deleted)),
+		Changed: make([]ProductDiffEntry, len(diff.Changed)),
+	}
+	for i, product := range diff.Created {
+		response.Created[i] = toProductDiffEntry(product, nil)
+	}
+	for i, product := range diff.Deleted {
+		response.Deleted[i] = toProductDiffEntry(product, nil)
+	}
+	for i, changed := range diff.Changed {
+		response.Changed[i] = toProductDiffEntry(changed.Product, changed.Fields)
+	}
+	return response
+}
+
+func toProductDiffEntry(product *domain.Product, fields []string) ProductDiffEntry {
+	return ProductDiffEntry{
+		ID:      product.ID,
+		StoreID: product.StoreID,
+		Name:    product.Name,
+		Fields:  fields,
+	}
+}
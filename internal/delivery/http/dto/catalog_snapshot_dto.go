@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type CatalogSnapshotResponse struct {
+	ID        int64  `json:"id"`
+	StoreID   int64  `json:"store_id"`
+	Products  int    `json:"products"`
+	TakenAt   string `json:"taken_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+type CatalogSnapshotListResponse struct {
+	Snapshots []CatalogSnapshotResponse `json:"snapshots"`
+	Total     int                       `json:"total"`
+	Limit     int                       `json:"limit"`
+	Offset    int                       `json:"offset"`
+}
+
+type CatalogRestoreResponse struct {
+	ID         int64  `json:"id"`
+	StoreID    int64  `json:"store_id"`
+	SnapshotID int64  `json:"snapshot_id"`
+	Status     string `json:"status"`
+	Created    int    `json:"created"`
+	Updated    int    `json:"updated"`
+	Deleted    int    `json:"deleted"`
+	Error      string `json:"error,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	StartedAt  string `json:"started_at,omitempty"`
+	FinishedAt string `json:"finished_at,omitempty"`
+}
+
+func ToCatalogSnapshotResponse(snapshot *domain.CatalogSnapshot) CatalogSnapshotResponse {
+	return CatalogSnapshotResponse{
+		ID:        snapshot.ID,
+		StoreID:   snapshot.StoreID,
+		Products:  snapshot.Products,
+		TakenAt:   snapshot.TakenAt.Format(time.RFC3339),
+		CreatedAt: snapshot.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToCatalogSnapshotListResponse(snapshots []*domain.CatalogSnapshot, limit, offset int) CatalogSnapshotListResponse {
+	responses := make([]CatalogSnapshotResponse, len(snapshots))
+	for i, snapshot := range snapshots {
+		responses[i] = ToCatalogSnapshotResponse(snapshot)
+	}
+
+	return CatalogSnapshotListResponse{
+		Snapshots: responses,
+		Total:     len(snapshots),
+		Limit:     limit,
+		Offset:    offset,
+	}
+}
+
+func ToCatalogRestoreResponse(restore *domain.CatalogRestore) CatalogRestoreResponse {
+	startedAt := ""
+	if restore.StartedAt.Valid {
+		startedAt = restore.StartedAt.Time.Format(time.RFC3339)
+	}
+	finishedAt := ""
+	if restore.FinishedAt.Valid {
+		finishedAt = restore.FinishedAt.Time.Format(time.RFC3339)
+	}
+
+	return CatalogRestoreResponse{
+		ID:         restore.ID,
+		StoreID:    restore.StoreID,
+		SnapshotID: restore.SnapshotID,
+		Status:     string(restore.Status),
+		Created:    restore.Created,
+		Updated:    restore.Updated,
+		Deleted:    restore.Deleted,
+		Error:      restore.Error.String,
+		CreatedAt:  restore.CreatedAt.Format(time.RFC3339),
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+	}
+}
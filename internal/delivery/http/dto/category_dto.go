@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"database/sql"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type SaveCategoryRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	ParentID *int64 `json:"parent_id" binding:"omitempty,gt=0"`
+}
+
+// SetProductCategoriesRequest replaces the categories a product is in; an
+// empty list takes it out of every category.
+type SetProductCategoriesRequest struct {
+	CategoryIDs []int64 `json:"category_ids" binding:"required,max=50,dive,gt=0"`
+}
+
+type CategoryResponse struct {
+	ID        int64  `json:"id"`
+	ParentID  *int64 `json:"parent_id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type CategoryListResponse struct {
+	Categories []CategoryResponse `json:"categories"`
+	Total      int                `json:"total"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+}
+
+// ProductCategoryListResponse lists the categories a product is in.
+type ProductCategoryListResponse struct {
+	Categories []CategoryResponse `json:"categories"`
+	Total      int                `json:"total"`
+}
+
+func (r *SaveCategoryRequest) ToDomain() *domain.Category {
+	category := &domain.Category{Name: r.Name}
+	if r.ParentID != nil {
+		category.ParentID = sql.NullInt64{Int64: *r.ParentID, Valid: true}
+	}
+	return category
+}
+
+func ToCategoryResponse(category *domain.Category) CategoryResponse {
+	response := CategoryResponse{
+		ID:        category.ID,
+		Name:      category.Name,
+		CreatedAt: category.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: category.UpdatedAt.Format(time.RFC3339),
+	}
+	if category.ParentID.Valid {
+		response.ParentID = &category.ParentID.Int64
+	}
+	return response
+}
+
+func toCategoryResponses(categories []*domain.Category) []CategoryResponse {
+	responses := make([]CategoryResponse, len(categories))
+	for i, category := range categories {
+		responses[i] = ToCategoryResponse(category)
+	}
+	return responses
+}
+
+func ToCategoryListResponse(categories []*domain.Category, limit, offset int) CategoryListResponse {
+	return CategoryListResponse{
+		Categories: toCategoryResponses(categories),
+		Total:      len(categories),
+		Limit:      limit,
+		Offset:     offset,
+	}
+}
+
+func ToProductCategoryListResponse(categories []*domain.Category) ProductCategoryListResponse {
+	return ProductCategoryListResponse{
+		Categories: toCategoryResponses(categories),
+		Total:      len(categories),
+	}
+}
@@ -0,0 +1,133 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type CreateConnectorRequest struct {
+	StoreID     int64             `json:"store_id" binding:"required,min=1"`
+	Kind        string            `json:"kind" binding:"required"`
+	Settings    map[string]string `json:"settings"`
+	Credentials string            `json:"credentials" binding:"required"`
+	Enabled     *bool             `json:"enabled"`
+}
+
+type ConnectorResponse struct {
+	ID        int64             `json:"id"`
+	StoreID   int64             `json:"store_id"`
+	Kind      string            `json:"kind"`
+	Settings  map[string]string `json:"settings"`
+	Enabled   bool              `json:"enabled"`
+	CreatedAt string            `json:"created_at"`
+	UpdatedAt string            `json:"updated_at"`
+}
+
+type ConnectorListResponse struct {
+	Connectors []ConnectorResponse `json:"connectors"`
+	Total      int                 `json:"total"`
+	Limit      int                 `json:"limit"`
+	Offset     int                 `json:"offset"`
+}
+
+type ConnectorSyncResponse struct {
+	ID          int64  `json:"id"`
+	ConnectorID int64  `json:"connector_id"`
+	Status      string `json:"status"`
+	Pulled      int    `json:"pulled"`
+	Created     int    `json:"created"`
+	Updated     int    `json:"updated"`
+	Pushed      int    `json:"pushed"`
+	Failed      int    `json:"failed"`
+	Error       string `json:"error,omitempty"`
+	StartedAt   string `json:"started_at"`
+	FinishedAt  string `json:"finished_at,omitempty"`
+}
+
+type ConnectorSyncListResponse struct {
+	Syncs  []ConnectorSyncResponse `json:"syncs"`
+	Total  int                     `json:"total"`
+	Limit  int                     `json:"limit"`
+	Offset int                     `json:"offset"`
+}
+
+func (r *CreateConnectorRequest) ToDomain() *domain.Connector {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return &domain.Connector{
+		StoreID:  r.StoreID,
+		Kind:     r.Kind,
+		Settings: r.Settings,
+		Enabled:  enabled,
+	}
+}
+
+func ToConnectorResponse(connector *domain.Connector) ConnectorResponse {
+	settings := connector.Settings
+	if settings == nil {
+		settings = map[string]string{}
+	}
+
+	return ConnectorResponse{
+		ID:        connector.ID,
+		StoreID:   connector.StoreID,
+		Kind:      connector.Kind,
+		Settings:  settings,
+		Enabled:   connector.Enabled,
+		CreatedAt: connector.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: connector.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToConnectorListResponse(connectors []*domain.Connector, limit, offset int) ConnectorListResponse {
+	responses := make([]ConnectorResponse, len(connectors))
+	for i, connector := range connectors {
+		responses[i] = ToConnectorResponse(connector)
+	}
+
+	return ConnectorListResponse{
+		Connectors: responses,
+		Total:      len(connectors),
+		Limit:      limit,
+		Offset:     offset,
+	}
+}
+
+func ToConnectorSyncResponse(run *domain.ConnectorSync) ConnectorSyncResponse {
+	finishedAt := ""
+	if run.FinishedAt.Valid {
+		finishedAt = run.FinishedAt.Time.Format(time.RFC3339)
+	}
+
+	return ConnectorSyncResponse{
+		ID:          run.ID,
+		ConnectorID: run.ConnectorID,
+		Status:      string(run.Status),
+		Pulled:      run.Pulled,
+		Created:     run.Created,
+		Updated:     run.Updated,
+		Pushed:      run.Pushed,
+		Failed:      run.Failed,
+		Error:       run.Error.String,
+		StartedAt:   run.StartedAt.Format(time.RFC3339),
+		FinishedAt:  finishedAt,
+	}
+}
+
+func ToConnectorSyncListResponse(runs []*domain.ConnectorSync, limit, offset int) ConnectorSyncListResponse {
+	responses := make([]ConnectorSyncResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = ToConnectorSyncResponse(run)
+	}
+
+	return ConnectorSyncListResponse{
+		Syncs:  responses,
+		Total:  len(runs),
+		Limit:  limit,
+		Offset: offset,
+	}
+}
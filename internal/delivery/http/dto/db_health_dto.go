@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type DBHealthResponse struct {
+	GeneratedAt string                    `json:"generated_at"`
+	StatsSince  string                    `json:"stats_since,omitempty"`
+	Findings    []DBHealthFindingResponse `json:"findings"`
+	Tables      []TableStatsResponse      `json:"tables"`
+	Indexes     []IndexStatsResponse      `json:"indexes"`
+}
+
+type DBHealthFindingResponse struct {
+	Check          string `json:"check"`
+	Table          string `json:"table"`
+	Index          string `json:"index,omitempty"`
+	Detail         string `json:"detail"`
+	Recommendation string `json:"recommendation"`
+}
+
+type TableStatsResponse struct {
+	Table           string `json:"table"`
+	LiveRows        int64  `json:"live_rows"`
+	DeadRows        int64  `json:"dead_rows"`
+	SeqScans        int64  `json:"seq_scans"`
+	SeqRowsRead     int64  `json:"seq_rows_read"`
+	IndexScans      int64  `json:"index_scans"`
+	TotalBytes      int64  `json:"total_bytes"`
+	LastVacuum      string `json:"last_vacuum,omitempty"`
+	LastAutoanalyze string `json:"last_autoanalyze,omitempty"`
+}
+
+type IndexStatsResponse struct {
+	Table   string   `json:"table"`
+	Index   string   `json:"index"`
+	Columns []string `json:"columns"`
+	Scans   int64    `json:"scans"`
+	Bytes   int64    `json:"bytes"`
+	Unique  bool     `json:"unique"`
+}
+
+func ToDBHealthResponse(report *domain.DBHealthReport) DBHealthResponse {
+	response := DBHealthResponse{
+		GeneratedAt: report.GeneratedAt.Format(time.RFC3339),
+		Findings:    make([]DBHealthFindingResponse, len(report.Findings)),
+		Tables:      make([]TableStatsResponse, len(report.Tables)),
+		Indexes:     make([]IndexStatsResponse, len(report.Indexes)),
+	}
+	if report.StatsSince.Valid {
+		response.StatsSince = report.StatsSince.Time.Format(time.RFC3339)
+	}
+
+	for i, finding := range report.Findings {
+		response.Findings[i] = DBHealthFindingResponse(finding)
+	}
+	for i, table := range report.Tables {
+		response.Tables[i] = TableStatsResponse{
+			Table:       table.Table,
+			LiveRows:    table.LiveTuples,
+			DeadRows:    table.DeadTuples,
+			SeqScans:    table.SeqScans,
+			SeqRowsRead: table.SeqTuplesRead,
+			IndexScans:  table.IndexScans,
+			TotalBytes:  table.TotalBytes,
+		}
+		if table.LastVacuum.Valid {
+			response.Tables[i].LastVacuum = table.LastVacuum.Time.Format(time.RFC3339)
+		}
+		if table.LastAutoanalyze.Valid {
+			response.Tables[i].LastAutoanalyze = table.LastAutoanalyze.Time.Format(time.RFC3339)
+		}
+	}
+	for i, index := range report.Indexes {
+		response.Indexes[i] = IndexStatsResponse(index)
+		if response.Indexes[i].Columns == nil {
+			response.Indexes[i].Columns = []string{}
+		}
+	}
+	return response
+}
+
+type IndexAdviceReportResponse struct {
+	GeneratedAt string                  `json:"generated_at"`
+	Advice      []IndexAdviceResponse   `json:"advice"`
+	Patterns    []AccessPatternResponse `json:"patterns"`
+}
+
+type IndexAdviceResponse struct {
+	Kind      string   `json:"kind"`
+	Table     string   `json:"table"`
+	Index     string   `json:"index"`
+	Columns   []string `json:"columns"`
+	Uses      int64    `json:"uses,omitempty"`
+	Patterns  []string `json:"patterns,omitempty"`
+	Statement string   `json:"statement"`
+}
+
+type AccessPatternResponse struct {
+	Pattern string `json:"pattern"`
+	Uses    int64  `json:"uses"`
+}
+
+func ToIndexAdviceReportResponse(report *domain.IndexAdviceReport) IndexAdviceReportResponse {
+	response := IndexAdviceReportResponse{
+		GeneratedAt: report.GeneratedAt.Format(time.RFC3339),
+		Advice:      make([]IndexAdviceResponse, len(report.Advice)),
+		Patterns:    make([]AccessPatternResponse, len(report.Patterns)),
+	}
+	for i, advice := range report.Advice {
+		response.Advice[i] = IndexAdviceResponse(advice)
+		if response.Advice[i].Columns == nil {
+			response.Advice[i].Columns = []string{}
+		}
+	}
+	for i, usage := range report.Patterns {
+		response.Patterns[i] = AccessPatternResponse{Pattern: usage.Pattern.String(), Uses: usage.Uses}
+	}
+	return response
+}
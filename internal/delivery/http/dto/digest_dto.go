@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type SaveDigestSettingsRequest struct {
+	Channel string `json:"channel" binding:"required,oneof=email webhook"`
+	Target  string `json:"target" binding:"required"`
+}
+
+type DigestSettingsResponse struct {
+	StoreID     int64  `json:"store_id"`
+	Channel     string `json:"channel"`
+	Target      string `json:"target"`
+	LastSentDay string `json:"last_sent_day,omitempty"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+func (r *SaveDigestSettingsRequest) ToDomain(storeID int64) *domain.DigestSettings {
+	return &domain.DigestSettings{
+		StoreID: storeID,
+		Channel: r.Channel,
+		Target:  r.Target,
+	}
+}
+
+func ToDigestSettingsResponse(settings *domain.DigestSettings) DigestSettingsResponse {
+	response := DigestSettingsResponse{
+		StoreID:   settings.StoreID,
+		Channel:   settings.Channel,
+		Target:    settings.Target,
+		CreatedAt: settings.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: settings.UpdatedAt.Format(time.RFC3339),
+	}
+	if settings.LastSentDay.Valid {
+		response.LastSentDay = settings.LastSentDay.Time.Format(time.DateOnly)
+	}
+	return response
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type EventSchemaResponse struct {
+	Type    string          `json:"type"`
+	Version int             `json:"version"`
+	Current bool            `json:"current"`
+	Schema  json.RawMessage `json:"schema"`
+}
+
+type EventSchemaListResponse struct {
+	Schemas []EventSchemaResponse `json:"schemas"`
+}
+
+func ToEventSchemaListResponse(schemas []domain.EventSchema) EventSchemaListResponse {
+	responses := make([]EventSchemaResponse, len(schemas))
+	for i, schema := range schemas {
+		responses[i] = EventSchemaResponse{
+			Type:    schema.Type,
+			Version: schema.Version,
+			Current: schema.Current,
+			Schema:  schema.Schema,
+		}
+	}
+	return EventSchemaListResponse{Schemas: responses}
+}
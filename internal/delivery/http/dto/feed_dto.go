@@ -0,0 +1,192 @@
+package dto
+
+import (
+	"database/sql"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type CreateFeedRequest struct {
+	StoreID         int64  `json:"store_id" binding:"required,min=1"`
+	URL             string `json:"url" binding:"required,url"`
+	Format          string `json:"format" binding:"required,oneof=csv json"`
+	IntervalMinutes int64  `json:"interval_minutes" binding:"required,min=5"`
+	Enabled         *bool  `json:"enabled"`
+	// MappingID reads the CSV through one of the store's import mappings.
+	MappingID *int64 `json:"mapping_id" binding:"omitempty,min=1"`
+	// MatchBy and OnDuplicate default to matching by name and updating
+	// the matched product.
+	MatchBy     []string `json:"match_by" binding:"omitempty,dive,oneof=sku barcode name fuzzy_name"`
+	OnDuplicate string   `json:"on_duplicate" binding:"omitempty,oneof=skip update create"`
+}
+
+type FeedResponse struct {
+	ID              int64    `json:"id"`
+	StoreID         int64    `json:"store_id"`
+	URL             string   `json:"url"`
+	Format          string   `json:"format"`
+	IntervalMinutes int64    `json:"interval_minutes"`
+	Enabled         bool     `json:"enabled"`
+	MappingID       int64    `json:"mapping_id,omitempty"`
+	MatchBy         []string `json:"match_by"`
+	OnDuplicate     string   `json:"on_duplicate"`
+	LastRunAt       string   `json:"last_run_at,omitempty"`
+	CreatedAt       string   `json:"created_at"`
+	UpdatedAt       string   `json:"updated_at"`
+}
+
+type FeedListResponse struct {
+	Feeds  []FeedResponse `json:"feeds"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+type FeedRunResponse struct {
+	ID          int64    `json:"id"`
+	FeedID      int64    `json:"feed_id"`
+	Status      string   `json:"status"`
+	Created     int      `json:"created"`
+	Updated     int      `json:"updated"`
+	Deactivated int      `json:"deactivated"`
+	Unchanged   int      `json:"unchanged"`
+	Skipped     int      `json:"skipped"`
+	Failed      int      `json:"failed"`
+	Errors      []string `json:"errors"`
+	// Duplicates lists the rows matched to an existing product by a rule,
+	// and what the run did with each.
+	Duplicates []FeedDuplicateResponse `json:"duplicates"`
+	// ImagesQueued counts the image URLs downloaded after the run; see
+	// the run's images for their outcomes.
+	ImagesQueued int    `json:"images_queued"`
+	StartedAt    string `json:"started_at"`
+	FinishedAt   string `json:"finished_at,omitempty"`
+}
+
+type FeedDuplicateResponse struct {
+	Item       int    `json:"item"`
+	Name       string `json:"name"`
+	ProductID  int64  `json:"product_id"`
+	MatchedBy  string `json:"matched_by"`
+	Resolution string `json:"resolution"`
+}
+
+type FeedRunListResponse struct {
+	Runs   []FeedRunResponse `json:"runs"`
+	Total  int               `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+func (r *CreateFeedRequest) ToDomain() *domain.Feed {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	mappingID := sql.NullInt64{}
+	if r.MappingID != nil {
+		mappingID = sql.NullInt64{Int64: *r.MappingID, Valid: true}
+	}
+
+	return &domain.Feed{
+		StoreID:         r.StoreID,
+		URL:             r.URL,
+		Format:          domain.FeedFormat(r.Format),
+		IntervalSeconds: r.IntervalMinutes * 60,
+		Enabled:         enabled,
+		MappingID:       mappingID,
+		MatchBy:         r.MatchBy,
+		OnDuplicate:     r.OnDuplicate,
+	}
+}
+
+func ToFeedResponse(feed *domain.Feed) FeedResponse {
+	lastRunAt := ""
+	if feed.LastRunAt.Valid {
+		lastRunAt = feed.LastRunAt.Time.Format(time.RFC3339)
+	}
+
+	matchBy := feed.MatchBy
+	if matchBy == nil {
+		matchBy = []string{}
+	}
+
+	return FeedResponse{
+		ID:              feed.ID,
+		StoreID:         feed.StoreID,
+		URL:             feed.URL,
+		Format:          string(feed.Format),
+		IntervalMinutes: feed.IntervalSeconds / 60,
+		Enabled:         feed.Enabled,
+		MappingID:       feed.MappingID.Int64,
+		MatchBy:         matchBy,
+		OnDuplicate:     feed.OnDuplicate,
+		LastRunAt:       lastRunAt,
+		CreatedAt:       feed.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       feed.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToFeedListResponse(feeds []*domain.Feed, limit, offset int) FeedListResponse {
+	feedResponses := make([]FeedResponse, len(feeds))
+	for i, feed := range feeds {
+		feedResponses[i] = ToFeedResponse(feed)
+	}
+
+	return FeedListResponse{
+		Feeds:  feedResponses,
+		Total:  len(feeds),
+		Limit:  limit,
+		Offset: offset,
+	}
+}
+
+func ToFeedRunResponse(run *domain.FeedRun) FeedRunResponse {
+	finishedAt := ""
+	if run.FinishedAt.Valid {
+		finishedAt = run.FinishedAt.Time.Format(time.RFC3339)
+	}
+
+	errs := run.Errors
+	if errs == nil {
+		errs = []string{}
+	}
+
+	duplicates := make([]FeedDuplicateResponse, len(run.Duplicates))
+	for i, duplicate := range run.Duplicates {
+		duplicates[i] = FeedDuplicateResponse(duplicate)
+	}
+
+	return FeedRunResponse{
+		ID:           run.ID,
+		FeedID:       run.FeedID,
+		Status:       string(run.Status),
+		Created:      run.Created,
+		Updated:      run.Updated,
+		Deactivated:  run.Deactivated,
+		Unchanged:    run.Unchanged,
+		Skipped:      run.Skipped,
+		Failed:       run.Failed,
+		Errors:       errs,
+		Duplicates:   duplicates,
+		ImagesQueued: run.ImagesQueued,
+		StartedAt:    run.StartedAt.Format(time.RFC3339),
+		FinishedAt:   finishedAt,
+	}
+}
+
+func ToFeedRunListResponse(runs []*domain.FeedRun, limit, offset int) FeedRunListResponse {
+	runResponses := make([]FeedRunResponse, len(runs))
+	for i, run := range runs {
+		runResponses[i] = ToFeedRunResponse(run)
+	}
+
+	return FeedRunListResponse{
+		Runs:   runResponses,
+		Total:  len(runs),
+		Limit:  limit,
+		Offset: offset,
+	}
+}
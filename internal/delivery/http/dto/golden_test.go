@@ -0,0 +1,410 @@
+package dto
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/digest"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/events"
+	"backend-context-engineering-template/pkg/cache"
+	"backend-context-engineering-template/pkg/health"
+	"backend-context-engineering-template/pkg/hotkeys"
+	"backend-context-engineering-template/pkg/pagination"
+	"backend-context-engineering-template/pkg/quantity"
+	"backend-context-engineering-template/pkg/replication"
+	"backend-context-engineering-template/pkg/session"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run `go test ./internal/delivery/http/dto -update` after an intentional
+// response change and review the golden diff with the code.
+var update = flag.Bool("update", false, "rewrite golden files with the current responses")
+
+var (
+	createdAt   = time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	updatedAt   = time.Date(2024, 3, 2, 10, 45, 0, 0, time.UTC)
+	releaseDate = time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC)
+	sunsetDate  = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func fullProduct() *domain.Product {
+	return &domain.Product{
+		ID:                42,
+		StoreID:           7,
+		Name:              "Espresso Beans",
+		Description:       sql.NullString{String: "**Dark** roast", Valid: true},
+		DescriptionFormat: domain.DescriptionFormatMarkdown,
+		Amount:            quantity.New(12),
+		Unit:              domain.UnitPiece,
+		Price:             18.5,
+		Status:            domain.ProductStatusActive,
+		ModerationStatus:  domain.ModerationStatusRejected,
+		ModerationReason:  sql.NullString{String: "blocked term", Valid: true},
+		CreatedAt:         createdAt,
+		UpdatedAt:         updatedAt,
+		Version:           3,
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+	return u
+}
+
+func TestGoldenResponses(t *testing.T) {
+	rendered := ToProductResponse(fullProduct(), updatedAt)
+	rendered.RenderDescription()
+
+	tests := []struct {
+		name     string
+		response any
+	}{
+		{name: "product", response: ToProductResponse(fullProduct(), updatedAt)},
+		{name: "product_nulls_and_zero_times", response: ToProductResponse(&domain.Product{ID: 1, StoreID: 1, Name: "Bare"}, updatedAt)},
+		{name: "product_rendered_description", response: rendered},
+		{name: "product_weighed", response: ToProductResponse(&domain.Product{
+			ID: 43, StoreID: 7, Name: "Rye Flour", DescriptionFormat: domain.DescriptionFormatPlain, Amount: quantity.MustParse("2.375"),
+			Unit: domain.UnitKilogram, Price: 3.2, Status: domain.ProductStatusActive, ModerationStatus: domain.ModerationStatusApproved,
+			CreatedAt: createdAt, UpdatedAt: updatedAt,
+		}, updatedAt)},
+		{name: "product_backorder", response: ToProductResponse(&domain.Product{
+			ID: 44, StoreID: 7, Name: "Grinder", DescriptionFormat: domain.DescriptionFormatPlain, Amount: quantity.New(-2),
+			Unit: domain.UnitPiece, Price: 89, Status: domain.ProductStatusActive, ModerationStatus: domain.ModerationStatusApproved,
+			AllowBackorder: true, BackorderLimit: quantity.New(10),
+			CreatedAt: createdAt, UpdatedAt: updatedAt,
+		}, updatedAt)},
+		{name: "product_preorder", response: ToProductResponse(&domain.Product{
+			ID: 45, StoreID: 7, Name: "Kettle", DescriptionFormat: domain.DescriptionFormatPlain,
+			Unit: domain.UnitPiece, Price: 45, Status: domain.ProductStatusActive, ModerationStatus: domain.ModerationStatusApproved,
+			AllowBackorder: true, BackorderLimit: quantity.New(50), PreorderReleaseDate: &releaseDate,
+			CreatedAt: createdAt, UpdatedAt: updatedAt,
+		}, updatedAt)},
+		{name: "product_low_stock", response: ToProductResponse(&domain.Product{
+			ID: 46, StoreID: 7, Name: "Filter Papers", DescriptionFormat: domain.DescriptionFormatPlain, Amount: quantity.New(3),
+			Unit: domain.UnitPiece, Price: 4.5, Status: domain.ProductStatusActive, ModerationStatus: domain.ModerationStatusApproved,
+			LowStockThreshold: quantity.New(5),
+			CreatedAt:         createdAt, UpdatedAt: updatedAt,
+		}, updatedAt)},
+		{name: "product_discontinued", response: ToProductResponse(&domain.Product{
+			ID: 47, StoreID: 7, Name: "Old Blend", DescriptionFormat: domain.DescriptionFormatPlain, Amount: quantity.New(8),
+			Unit: domain.UnitPiece, Price: 12, Status: domain.ProductStatusInactive, ModerationStatus: domain.ModerationStatusApproved,
+			CreatedAt: createdAt, UpdatedAt: updatedAt,
+		}, updatedAt)},
+		{name: "product_scheduled", response: ToProductResponse(&domain.Product{
+			ID: 48, StoreID: 7, Name: "Holiday Blend", DescriptionFormat: domain.DescriptionFormatPlain, Amount: quantity.New(100),
+			Unit: domain.UnitPiece, Price: 16, Status: domain.ProductStatusActive, ModerationStatus: domain.ModerationStatusApproved,
+			PublishAt: &releaseDate, UnpublishAt: &sunsetDate,
+			CreatedAt: createdAt, UpdatedAt: updatedAt,
+		}, updatedAt)},
+		{name: "product_list", response: ToProductListResponse([]*domain.Product{fullProduct()}, pagination.Page{Limit: 10}, updatedAt)},
+		{name: "product_list_empty", response: ToProductListResponse(nil, pagination.Page{Limit: 10, Offset: 20}, updatedAt)},
+		{name: "product_list_next_cursor", response: ToProductListResponse([]*domain.Product{fullProduct()}, pagination.Page{Limit: 1, Offset: 4, Sort: domain.ProductSortPopularity}, updatedAt)},
+		{name: "list_envelope", response: ToListResponse([]ProductResponse{ToProductResponse(fullProduct(), updatedAt)},
+			pagination.Page{Limit: 1, Offset: 4, Sort: domain.ProductSortPopularity}, mustParseURL(t, "/api/v1/products?limit=1&offset=4&sort=popularity"))},
+		{name: "list_envelope_last_page", response: ToListResponse([]StoreResponse(nil), pagination.Page{Limit: 10}, mustParseURL(t, "/api/v1/stores"))},
+		{name: "catalog_diff", response: ToCatalogDiffResponse(&domain.CatalogDiff{
+			From:    createdAt,
+			To:      updatedAt,
+			Created: []*domain.Product{{ID: 43, StoreID: 7, Name: "Green Tea"}},
+			Changed: []domain.ChangedProduct{{Product: fullProduct(), Fields: []string{"amount", "price"}}},
+		})},
+		{name: "catalog_diff_empty", response: ToCatalogDiffResponse(&domain.CatalogDiff{From: createdAt, To: updatedAt})},
+		{name: "error", response: ErrorResponse{Error: "product_not_found"}},
+		{name: "trash_list", response: ToTrashListResponse([]*domain.TrashedProduct{{
+			Product:   *fullProduct(),
+			TrashedAt: updatedAt,
+			PurgeAt:   updatedAt.Add(30 * 24 * time.Hour),
+		}}, 10, 0, updatedAt)},
+		{name: "empty_trash", response: EmptyTrashResponse{Deleted: 3}},
+		{name: "bulk_confirmation", response: ToBulkConfirmationResponse(&domain.BulkConfirmation{
+			Operation: domain.BulkOperation{Kind: domain.BulkOperationEmptyTrash, StoreID: 7, Affected: 250},
+			Token:     "a.b.c",
+			ExpiresAt: updatedAt.Add(10 * time.Minute),
+		}, "mass operation requires confirmation: empty_trash affects 250 rows of store 7")},
+		{name: "bulk_products", response: ToBulkProductsResponse([]*domain.BulkProductResult{
+			{Product: fullProduct(), Created: true},
+			{Product: &domain.Product{ID: 43, StoreID: 7, Name: "Kettle", DescriptionFormat: domain.DescriptionFormatPlain, Unit: domain.UnitPiece,
+				Price: 30, Status: domain.ProductStatusActive, ModerationStatus: domain.ModerationStatusApproved,
+				CreatedAt: createdAt, UpdatedAt: updatedAt}},
+		}, updatedAt)},
+		{name: "bulk_products_rejected", response: BulkProductsErrorResponse{
+			Error:   "bulk_write_rejected",
+			Message: "Some products were rejected, so none were saved",
+			Results: []BulkProductResult{RejectedBulkItem(1, ErrorResponse{Error: "product_not_found", Message: "Product not found"})},
+		}},
+		{name: "product_import", response: ToProductImportResponse(&domain.ProductImportReport{
+			Created: 2, Updated: 1, Failed: 1,
+			Errors: []domain.ProductImportError{{Line: 4, Error: `invalid product data: invalid price "abc"`}},
+		})},
+		{name: "feed", response: ToFeedResponse(&domain.Feed{
+			ID: 3, StoreID: 7, URL: "https://example.com/feed.csv", Format: domain.FeedFormatCSV,
+			IntervalSeconds: 3600, Enabled: true,
+			MatchBy:     []string{domain.DuplicateMatchSKU, domain.DuplicateMatchFuzzyName},
+			OnDuplicate: domain.DuplicateSkip,
+			LastRunAt:   sql.NullTime{Time: updatedAt, Valid: true},
+			CreatedAt:   createdAt, UpdatedAt: updatedAt,
+		})},
+		{name: "feed_never_run", response: ToFeedListResponse([]*domain.Feed{{
+			ID: 4, StoreID: 7, URL: "https://example.com/feed.json", Format: domain.FeedFormatJSON,
+			IntervalSeconds: 300, CreatedAt: createdAt, UpdatedAt: createdAt,
+		}}, 10, 0)},
+		{name: "feed_run_running", response: ToFeedRunResponse(&domain.FeedRun{
+			ID: 9, FeedID: 3, Status: domain.FeedRunStatusRunning, StartedAt: createdAt,
+		})},
+		{name: "feed_run_list", response: ToFeedRunListResponse([]*domain.FeedRun{{
+			ID: 10, FeedID: 3, Status: domain.FeedRunStatusFailed, Created: 1, Skipped: 1, Failed: 2,
+			Errors: []string{"row 2: price must be positive", "row 5: name is required"},
+			Duplicates: []domain.FeedDuplicate{
+				{Item: 3, Name: "Rye bread 500 g", ProductID: 12, MatchedBy: domain.DuplicateMatchFuzzyName, Resolution: domain.DuplicateSkip},
+			},
+			ImagesQueued: 2,
+			StartedAt:    createdAt, FinishedAt: sql.NullTime{Time: updatedAt, Valid: true},
+		}}, 10, 0)},
+		{name: "feed_run_images", response: ToImageImportListResponse([]*domain.ImageImport{
+			{ID: 1, RunID: 10, ProductID: 12, URL: "https://cdn.example.com/rye.jpg", Status: domain.ImageImportAttached,
+				ImageID: sql.NullInt64{Int64: 4, Valid: true}, CreatedAt: createdAt, FinishedAt: sql.NullTime{Time: updatedAt, Valid: true}},
+			{ID: 2, RunID: 10, ProductID: 12, URL: "https://cdn.example.com/rye.pdf", Status: domain.ImageImportFailed,
+				Error: `unsupported image type "application/pdf"`, CreatedAt: createdAt, FinishedAt: sql.NullTime{Time: updatedAt, Valid: true}},
+			{ID: 3, RunID: 10, ProductID: 13, URL: "https://cdn.example.com/flour.png", Status: domain.ImageImportPending, CreatedAt: createdAt},
+		})},
+		{name: "product_images", response: ToProductImageListResponse([]*domain.ProductImage{{
+			ID: 4, ProductID: 12, ContentHash: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+			ContentType: "image/jpeg", Size: 48213, ObjectKey: "images/9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08.jpg",
+			SourceURL: "https://cdn.example.com/rye.jpg", CreatedAt: createdAt,
+		}})},
+		{name: "dependency_readiness", response: DependencyReadinessResponse{
+			Status: "not_ready", Lifecycle: "ready", InFlight: 3,
+			Dependencies: ToDependencyResponses(health.Report{Status: health.StatusDown, Checks: []health.Result{
+				{Name: "postgres", Status: health.StatusDown, Latency: 2 * time.Second, Err: context.DeadlineExceeded},
+				{Name: "postgres_replica", Optional: true, Status: health.StatusUp, Latency: 1850 * time.Microsecond},
+				{Name: "redis", Status: health.StatusUp, Latency: 420 * time.Microsecond},
+			}}),
+		}},
+		{name: "product_stats", response: ToProductStatsResponse(&domain.ProductStats{
+			ProductID: 12, StoreID: 7, Views: 1520, AddToCarts: 84, Purchases: 31,
+			Since: time.Date(2024, 2, 24, 0, 0, 0, 0, time.UTC),
+		})},
+		{name: "import_mapping_list", response: ToImportMappingListResponse([]*domain.ImportMapping{{
+			ID: 2, StoreID: 7, Name: "Acme",
+			Columns: []domain.ColumnMapping{
+				{Field: domain.ImportFieldName, Column: "Product Title"},
+				{Field: domain.ImportFieldPrice, Column: "PriceCents", Transform: domain.ImportTransformCents},
+				{Field: domain.ImportFieldUnit, Default: domain.UnitKilogram},
+			},
+			CreatedAt: createdAt, UpdatedAt: updatedAt,
+		}})},
+		{name: "import_preview", response: ToImportPreviewResponse([]domain.ImportPreviewRow{
+			{Line: 2, Item: domain.FeedItem{Name: "Rye Flour", Amount: quantity.MustParse("2.5"), Unit: domain.UnitKilogram, Price: 3.49,
+				ImageURLs: []string{"https://cdn.example.com/flour.png"}}},
+			{Line: 3, Error: `invalid price in cents "12.50"`},
+		})},
+		{name: "connector_nil_settings", response: ToConnectorListResponse([]*domain.Connector{{
+			ID: 5, StoreID: 7, Kind: domain.ConnectorKindShopify, Enabled: true,
+			CreatedAt: createdAt, UpdatedAt: updatedAt,
+		}}, 10, 0)},
+		{name: "connector_sync_list", response: ToConnectorSyncListResponse([]*domain.ConnectorSync{
+			{ID: 1, ConnectorID: 5, Status: domain.ConnectorSyncStatusRunning, StartedAt: createdAt},
+			{
+				ID: 2, ConnectorID: 5, Status: domain.ConnectorSyncStatusFailed, Pulled: 4, Failed: 4,
+				Error:     sql.NullString{String: "unauthorized", Valid: true},
+				StartedAt: createdAt, FinishedAt: sql.NullTime{Time: updatedAt, Valid: true},
+			},
+		}, 10, 0)},
+		{name: "session_list", response: ToSessionListResponse([]*session.Session{
+			{ID: "s1", Identity: "key:abc", TokenHash: "hash", CSRFToken: "csrf", UserAgent: "curl/8", ClientIP: "10.0.0.1",
+				CreatedAt: createdAt, LastSeenAt: updatedAt, ExpiresAt: updatedAt.Add(time.Hour)},
+			{ID: "s2", Identity: "key:abc", CreatedAt: createdAt, LastSeenAt: createdAt, ExpiresAt: createdAt.Add(time.Hour)},
+		}, "s1")},
+		{name: "create_session", response: CreateSessionResponse{
+			Session:   ToSessionResponse(&session.Session{ID: "s1", CreatedAt: createdAt, LastSeenAt: createdAt, ExpiresAt: updatedAt}, "s1"),
+			CSRFToken: "csrf-token",
+		}},
+		{name: "two_factor_setup", response: ToTwoFactorSetupResponse(&domain.TwoFactorSetup{
+			Secret: "JBSWY3DPEHPK3PXP", ProvisioningURI: "otpauth://totp/product-service:key:abc?secret=JBSWY3DPEHPK3PXP",
+		})},
+		{name: "recovery_codes", response: RecoveryCodesResponse{RecoveryCodes: []string{"aaaa-bbbb", "cccc-dddd"}}},
+		{name: "hot_keys_empty", response: ToHotKeysResponse(nil)},
+		{name: "cache_stats", response: ToCacheStatsResponse([]cache.EndpointStats{
+			{Endpoint: "GET /api/v1/products/:id", Tier: cache.TierMemory, Hits: 75, Misses: 25, Expired: 5, BytesSaved: 10500},
+			{Endpoint: cache.EndpointOther, Tier: cache.TierMemory, Hits: 0, Misses: 20},
+		}, map[string]int64{cache.TierMemory: 12})},
+		{name: "cache_stats_empty", response: ToCacheStatsResponse(nil, map[string]int64{})},
+		{name: "hot_keys", response: ToHotKeysResponse([]hotkeys.HotKey{{ID: 42, Hits: 1500}})},
+		{name: "readiness", response: ReadinessResponse{Status: "draining", InFlight: 2}},
+		{name: "drain", response: DrainResponse{Drained: true}},
+		{name: "replication", response: ReplicationResponse{
+			Status: "degraded", Region: "us-east-1", PrimaryRegion: "eu-west-1", Role: "secondary",
+			Replica: ToReplicaStatusResponse(replication.Status{Lag: 7500 * time.Millisecond, MeasuredAt: updatedAt}),
+		}},
+		{name: "replication_unmeasured", response: ReplicationResponse{
+			Status: "degraded", Region: "eu-west-1", PrimaryRegion: "eu-west-1", Role: "primary",
+			Replica: ToReplicaStatusResponse(replication.Status{Err: errors.New("connection refused")}),
+		}},
+		{name: "webhook_list", response: ToWebhookListResponse([]*domain.WebhookSubscription{{
+			ID: 2, URL: "https://hooks.example.com/products", Description: "ERP sync", CreatedAt: createdAt, UpdatedAt: updatedAt,
+		}}, 10, 0)},
+		{name: "webhook_paused", response: ToWebhookResponse(&domain.WebhookSubscription{
+			ID: 3, URL: "https://hooks.example.com/dead", PausedAt: sql.NullTime{Time: updatedAt, Valid: true},
+			PauseReason: "20 consecutive deliveries failed", CreatedAt: createdAt, UpdatedAt: updatedAt,
+		})},
+		{name: "webhook_scoped", response: ToWebhookResponse(&domain.WebhookSubscription{
+			ID: 5, URL: "https://hooks.example.com/store-7", StoreID: sql.NullInt64{Int64: 7, Valid: true},
+			EventTypes: []string{domain.ProductEventCreated, domain.ProductEventDeleted}, CreatedAt: createdAt, UpdatedAt: createdAt,
+		})},
+		{name: "webhook_secret", response: ToWebhookSecretResponse(&domain.WebhookSigningSecret{
+			StoreID: 7, Current: "whsec_new", Previous: "whsec_old", RotatedAt: createdAt, PreviousExpiresAt: updatedAt,
+		})},
+		{name: "webhook_health", response: ToWebhookHealthResponse(&domain.WebhookHealth{
+			SubscriptionID: 3, Deliveries: 120, Failures: 20, ConsecutiveFailures: 20, SuccessRate: 0.6,
+			AvgLatency: 2500 * time.Millisecond, LastSuccessAt: sql.NullTime{Time: createdAt, Valid: true},
+			LastFailureAt: sql.NullTime{Time: updatedAt, Valid: true}, LastError: "webhook returned status 503",
+		})},
+		{name: "webhook_health_no_deliveries", response: ToWebhookHealthResponse(&domain.WebhookHealth{SubscriptionID: 4})},
+		// Webhook deliveries are not API responses, but subscribers depend
+		// on their shape all the same.
+		{name: "webhook_payload", response: domain.WebhookPayload{Bulk: true, Events: []domain.ProductEvent{
+			{ID: "0190a5e4-8f00-7000-8000-000000000001", Type: domain.ProductEventUpdated, SchemaVersion: 3, StoreID: 7, ProductID: 42, OccurredAt: updatedAt, Product: domain.NewProductEventData(fullProduct())},
+			{ID: "0190a5e4-8f00-7000-8000-000000000002", Type: domain.StockEventChanged, SchemaVersion: 3, StoreID: 7, ProductID: 42, OccurredAt: updatedAt, Stock: &domain.StockChange{PreviousAmount: quantity.New(15), Amount: quantity.New(12), Unit: domain.UnitPiece}},
+		}}},
+		{name: "webhook_cloudevents_batch", response: []events.CloudEvent{events.NewCloudEvent(domain.ProductEvent{
+			ID: "0190a5e4-8f00-7000-8000-000000000002", Type: domain.StockEventChanged, SchemaVersion: 3, StoreID: 7, ProductID: 42, OccurredAt: updatedAt,
+			Stock: &domain.StockChange{PreviousAmount: quantity.New(15), Amount: quantity.New(12), Unit: domain.UnitPiece},
+		}, "/product-service")}},
+		{name: "digest_payload", response: digest.NewPayload(&domain.CatalogDigest{StoreID: 7, Day: createdAt.Truncate(24 * time.Hour), Changes: []domain.CatalogChange{
+			{StoreID: 7, Day: createdAt.Truncate(24 * time.Hour), Type: domain.ProductEventUpdated, ProductID: 42, ProductName: "Desk lamp", Changes: 3, LastAt: createdAt},
+		}}, digest.Message{Subject: "Catalog changes for store 7 on 2024-03-01", Body: "1 products in store 7 changed on 2024-03-01 (UTC).\n"})},
+		{name: "digest_settings", response: ToDigestSettingsResponse(&domain.DigestSettings{
+			StoreID: 7, Channel: domain.DigestChannelWebhook, Target: "https://hooks.example.com/digest",
+			LastSentDay: sql.NullTime{Time: createdAt.Truncate(24 * time.Hour), Valid: true}, CreatedAt: createdAt, UpdatedAt: updatedAt,
+		})},
+		{name: "store_list", response: ToStoreListResponse([]*domain.Store{
+			{ID: 7, Name: "Corner Shop", CreatedAt: createdAt, UpdatedAt: updatedAt},
+		}, 10, 0)},
+		{name: "reconciliation_run_list", response: ToReconciliationRunListResponse([]*domain.ReconciliationRun{
+			{ID: 1, StoreID: 7, Source: domain.ReconciliationSourceFeed, SourceID: 3, Policy: domain.ReconciliationPolicyReport,
+				Status: domain.ReconciliationRunStatusRunning, StartedAt: createdAt},
+			{
+				ID: 2, StoreID: 7, Source: domain.ReconciliationSourceConnector, SourceID: 5, Policy: domain.ReconciliationPolicySourceWins,
+				Status: domain.ReconciliationRunStatusSucceeded, Checked: 40, Discrepancies: 3, Healed: 2,
+				StartedAt: createdAt, FinishedAt: sql.NullTime{Time: updatedAt, Valid: true},
+			},
+		}, 10, 0)},
+		{name: "discrepancy_list", response: ToDiscrepancyListResponse([]*domain.Discrepancy{
+			{ID: 1, RunID: 2, Name: "Rye Flour", Kind: domain.DiscrepancyMissingLocally},
+			{ID: 2, RunID: 2, ProductID: sql.NullInt64{Int64: 42, Valid: true}, Name: "Espresso Beans", Kind: domain.DiscrepancyFieldMismatch,
+				Field: "price", LocalValue: "12.5", SourceValue: "13", Healed: true},
+		}, 10, 0)},
+		{name: "catalog_snapshot_list", response: ToCatalogSnapshotListResponse([]*domain.CatalogSnapshot{
+			{ID: 4, StoreID: 7, ObjectKey: "snapshots/store-7/a.jsonl.gz", Products: 120, TakenAt: createdAt, CreatedAt: createdAt},
+		}, 10, 0)},
+		{name: "catalog_restore", response: ToCatalogRestoreResponse(&domain.CatalogRestore{
+			ID: 8, StoreID: 7, SnapshotID: 4, Status: domain.CatalogRestoreStatusSucceeded,
+			Created: 2, Updated: 5, Deleted: 1, CreatedAt: createdAt,
+			StartedAt: sql.NullTime{Time: createdAt, Valid: true}, FinishedAt: sql.NullTime{Time: updatedAt, Valid: true},
+		})},
+		{name: "pricing_policy_list", response: ToPricingPolicyListResponse([]*domain.PricingPolicy{
+			{StoreID: 7, Currency: "CHF", Rounding: "increment", Step: 5, Direction: "nearest", Decimals: 2, CreatedAt: createdAt, UpdatedAt: updatedAt},
+			{StoreID: 7, Currency: "USD", Rounding: "ending", Ending: 99, Direction: "down", Decimals: 2, CreatedAt: createdAt, UpdatedAt: updatedAt},
+		})},
+		{name: "price_quote", response: ToPriceQuoteResponse(&domain.PriceQuote{
+			ProductID: 42, StoreID: 7, BasePrice: 49.9, Adjustment: domain.PriceAdjustment{Currency: "CHF", Rate: 0.93, DiscountPercent: 10},
+			Policy:    domain.PricingPolicy{StoreID: 7, Currency: "CHF", Rounding: "increment", Step: 5, Direction: "nearest", Decimals: 2},
+			Unrounded: 4177, Price: 4175,
+		})},
+		{name: "bundle", response: ToBundleResponse(&domain.Bundle{
+			Product: &domain.Product{ID: 50, StoreID: 7, Name: "Breakfast Kit", Price: 24.9, Status: domain.ProductStatusActive},
+			Components: []domain.BundleComponent{
+				{BundleID: 50, ProductID: 42, Name: "Espresso Beans", Quantity: quantity.New(2), Unit: domain.UnitPiece, Stock: quantity.New(12), Available: 6, CreatedAt: createdAt},
+				{BundleID: 50, ProductID: 43, Name: "Rye Flour", Quantity: quantity.MustParse("0.5"), Unit: domain.UnitKilogram, Stock: quantity.MustParse("2.375"), Available: 4, CreatedAt: createdAt},
+			},
+			Available: 4,
+		})},
+		{name: "order", response: ToOrderResponse(&domain.Order{
+			ID: 31, StoreID: 7, Status: domain.OrderStatusPlaced, Total: 30.98, CreatedAt: createdAt,
+			Items: []domain.OrderItem{
+				{OrderID: 31, ProductID: 42, Name: "Espresso Beans", Quantity: quantity.New(2), Unit: domain.UnitPiece, UnitPrice: 12.5},
+				{OrderID: 31, ProductID: 43, Name: "Rye Flour", Quantity: quantity.MustParse("1.25"), Unit: domain.UnitKilogram, UnitPrice: 4.78},
+			},
+		})},
+		{name: "event_schema_list", response: ToEventSchemaListResponse([]domain.EventSchema{
+			{Type: domain.ProductEventCreated, Version: 1, Schema: json.RawMessage(`{"title":"product.created v1","type":"object"}`)},
+			{Type: domain.ProductEventCreated, Version: 2, Current: true, Schema: json.RawMessage(`{"title":"product.created v2","type":"object"}`)},
+		})},
+		{name: "retention_report", response: ToRetentionReportResponse([]domain.RetentionReport{
+			{
+				Rule:   domain.RetentionRule{Name: "audit_logs", Table: "audit_logs", MaxAge: 365 * 24 * time.Hour},
+				Cutoff: updatedAt, Eligible: 1200, Oldest: sql.NullTime{Time: createdAt, Valid: true},
+			},
+			{Rule: domain.RetentionRule{Name: "tombstones", Table: "product_trash", MaxAge: 30 * 24 * time.Hour}, Cutoff: updatedAt},
+		})},
+		{name: "retention_report_no_rules", response: ToRetentionReportResponse(nil)},
+		{name: "db_health", response: ToDBHealthResponse(&domain.DBHealthReport{
+			GeneratedAt: updatedAt,
+			StatsSince:  sql.NullTime{Time: createdAt, Valid: true},
+			Tables: []domain.TableStats{{
+				Table: "products", LiveTuples: 10000, DeadTuples: 5000, SeqScans: 40, SeqTuplesRead: 400000,
+				IndexScans: 10, TotalBytes: 1 << 20, LastAutoanalyze: sql.NullTime{Time: createdAt, Valid: true},
+			}},
+			Indexes: []domain.IndexStats{
+				{Table: "products", Index: "products_pkey", Columns: []string{"id"}, Scans: 10, Bytes: 16384, Unique: true},
+				{Table: "products", Index: "idx_products_store_id", Bytes: 8192},
+			},
+			Findings: []domain.DBHealthFinding{{
+				Check: domain.DBHealthCheckUnusedIndex, Table: "products", Index: "idx_products_store_id",
+				Detail: "never scanned, 8192 bytes", Recommendation: "If no replica or rare job uses it, run DROP INDEX CONCURRENTLY idx_products_store_id.",
+			}},
+		})},
+		{name: "db_health_empty", response: ToDBHealthResponse(&domain.DBHealthReport{GeneratedAt: updatedAt})},
+		{name: "index_advice", response: ToIndexAdviceReportResponse(&domain.IndexAdviceReport{
+			GeneratedAt: updatedAt,
+			Patterns: []domain.AccessPatternUsage{{
+				Pattern: domain.AccessPattern{Table: "products", Equals: []string{"store_id"}, Sort: []string{"id"}}, Uses: 300,
+			}},
+			Advice: []domain.IndexAdvice{
+				{
+					Kind: domain.IndexAdviceMissing, Table: "products", Index: "idx_products_store_id_id", Columns: []string{"store_id", "id"},
+					Uses: 300, Patterns: []string{"products WHERE store_id = ? ORDER BY id"},
+					Statement: "CREATE INDEX CONCURRENTLY idx_products_store_id_id ON products (store_id, id);",
+				},
+				{Kind: domain.IndexAdviceUnused, Table: "products", Index: "idx_products_lower_name", Statement: "DROP INDEX CONCURRENTLY idx_products_lower_name;"},
+			},
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertGolden(t, tt.name, tt.response)
+		})
+	}
+}
+
+func assertGolden(t *testing.T, name string, response any) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(response, "", "  ")
+	require.NoError(t, err)
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err, "missing golden file, run with -update to create it")
+	assert.Equal(t, string(want), string(got), "response shape changed; run with -update if intended")
+}
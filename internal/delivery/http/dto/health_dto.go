@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"backend-context-engineering-template/pkg/health"
+)
+
+type LivenessResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// DependencyResponse is one dependency's ping. Optional dependencies only
+// degrade readiness.
+type DependencyResponse struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Optional  bool    `json:"optional,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// DependencyReadinessResponse answers /readyz. Lifecycle is ready,
+// warming_up or draining.
+type DependencyReadinessResponse struct {
+	Status       string               `json:"status"`
+	Lifecycle    string               `json:"lifecycle"`
+	InFlight     int64                `json:"in_flight"`
+	Dependencies []DependencyResponse `json:"dependencies"`
+}
+
+func ToDependencyResponses(report health.Report) []DependencyResponse {
+	responses := make([]DependencyResponse, len(report.Checks))
+	for i, result := range report.Checks {
+		responses[i] = DependencyResponse{
+			Name:      result.Name,
+			Status:    result.Status,
+			Optional:  result.Optional,
+			LatencyMs: float64(result.Latency.Microseconds()) / 1000,
+		}
+		if result.Err != nil {
+			responses[i].Error = result.Err.Error()
+		}
+	}
+	return responses
+}
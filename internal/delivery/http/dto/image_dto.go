@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type ProductImageResponse struct {
+	ID          int64  `json:"id"`
+	ProductID   int64  `json:"product_id"`
+	ContentHash string `json:"content_hash"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	ObjectKey   string `json:"object_key"`
+	SourceURL   string `json:"source_url,omitempty"`
+	Position    int    `json:"position"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type ProductImageListResponse struct {
+	Images []ProductImageResponse `json:"images"`
+	Total  int                    `json:"total"`
+}
+
+// ImageImportResponse is the outcome of one image URL of a feed run:
+// pending, attached, duplicate or failed.
+type ImageImportResponse struct {
+	ID         int64  `json:"id"`
+	ProductID  int64  `json:"product_id"`
+	URL        string `json:"url"`
+	Status     string `json:"status"`
+	ImageID    int64  `json:"image_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	FinishedAt string `json:"finished_at,omitempty"`
+}
+
+type ImageImportListResponse struct {
+	Images []ImageImportResponse `json:"images"`
+	Total  int                   `json:"total"`
+}
+
+func ToProductImageResponse(image *domain.ProductImage) ProductImageResponse {
+	return ProductImageResponse{
+		ID:          image.ID,
+		ProductID:   image.ProductID,
+		ContentHash: image.ContentHash,
+		ContentType: image.ContentType,
+		Size:        image.Size,
+		ObjectKey:   image.ObjectKey,
+		SourceURL:   image.SourceURL,
+		Position:    image.Position,
+		CreatedAt:   image.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToProductImageListResponse(images []*domain.ProductImage) ProductImageListResponse {
+	responses := make([]ProductImageResponse, len(images))
+	for i, image := range images {
+		responses[i] = ToProductImageResponse(image)
+	}
+
+	return ProductImageListResponse{
+		Images: responses,
+		Total:  len(images),
+	}
+}
+
+func ToImageImportResponse(imp *domain.ImageImport) ImageImportResponse {
+	finishedAt := ""
+	if imp.FinishedAt.Valid {
+		finishedAt = imp.FinishedAt.Time.Format(time.RFC3339)
+	}
+
+	return ImageImportResponse{
+		ID:         imp.ID,
+		ProductID:  imp.ProductID,
+		URL:        imp.URL,
+		Status:     imp.Status,
+		ImageID:    imp.ImageID.Int64,
+		Error:      imp.Error,
+		CreatedAt:  imp.CreatedAt.Format(time.RFC3339),
+		FinishedAt: finishedAt,
+	}
+}
+
+func ToImageImportListResponse(imports []*domain.ImageImport) ImageImportListResponse {
+	responses := make([]ImageImportResponse, len(imports))
+	for i, imp := range imports {
+		responses[i] = ToImageImportResponse(imp)
+	}
+
+	return ImageImportListResponse{
+		Images: responses,
+		Total:  len(imports),
+	}
+}
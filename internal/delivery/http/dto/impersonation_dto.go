@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// ImpersonateRequest says why the admin is acting as the user, and for how
+// many seconds; the longest lifetime allowed applies when expires_in is
+// omitted.
+type ImpersonateRequest struct {
+	Reason    string `json:"reason" binding:"required"`
+	ExpiresIn int64  `json:"expires_in" binding:"omitempty,min=1,max=86400"`
+}
+
+// ImpersonationResponse carries an access token that acts as the user
+// until expires_at. It comes without a refresh token.
+type ImpersonationResponse struct {
+	UserID         int64  `json:"user_id"`
+	Email          string `json:"email"`
+	AccessToken    string `json:"access_token"`
+	TokenType      string `json:"token_type"`
+	ExpiresAt      string `json:"expires_at"`
+	ImpersonatedBy string `json:"impersonated_by"`
+}
+
+func (r *ImpersonateRequest) TTL() time.Duration {
+	return time.Duration(r.ExpiresIn) * time.Second
+}
+
+func ToImpersonationResponse(impersonation *domain.Impersonation) ImpersonationResponse {
+	return ImpersonationResponse{
+		UserID:         impersonation.User.ID,
+		Email:          impersonation.User.Email,
+		AccessToken:    impersonation.AccessToken,
+		TokenType:      "Bearer",
+		ExpiresAt:      impersonation.ExpiresAt.Format(time.RFC3339),
+		ImpersonatedBy: impersonation.ImpersonatedBy,
+	}
+}
@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+type SaveImportMappingRequest struct {
+	Name    string                `json:"name" binding:"required,max=100"`
+	Columns []ColumnMappingSchema `json:"columns" binding:"required,min=1,dive"`
+}
+
+type ColumnMappingSchema struct {
+	Field     string `json:"field" binding:"required,oneof=name description amount unit price sku barcode on_duplicate image_urls"`
+	Column    string `json:"column,omitempty"`
+	Transform string `json:"transform,omitempty" binding:"omitempty,oneof=cents lowercase uppercase"`
+	Default   string `json:"default,omitempty"`
+}
+
+type ImportMappingResponse struct {
+	ID        int64                 `json:"id"`
+	StoreID   int64                 `json:"store_id"`
+	Name      string                `json:"name"`
+	Columns   []ColumnMappingSchema `json:"columns"`
+	CreatedAt string                `json:"created_at"`
+	UpdatedAt string                `json:"updated_at"`
+}
+
+type ImportMappingListResponse struct {
+	Mappings []ImportMappingResponse `json:"mappings"`
+	Total    int                     `json:"total"`
+}
+
+type FeedItemResponse struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Amount      quantity.Quantity `json:"amount"`
+	Unit        string            `json:"unit,omitempty"`
+	Price       float64           `json:"price"`
+	SKU         string            `json:"sku,omitempty"`
+	Barcode     string            `json:"barcode,omitempty"`
+	OnDuplicate string            `json:"on_duplicate,omitempty"`
+	ImageURLs   []string          `json:"image_urls,omitempty"`
+}
+
+type ImportPreviewRowResponse struct {
+	Line  int               `json:"line"`
+	Item  *FeedItemResponse `json:"item,omitempty"`
+	Error string            `json:"error,omitempty"`
+}
+
+type ImportPreviewResponse struct {
+	Rows []ImportPreviewRowResponse `json:"rows"`
+}
+
+func (r *SaveImportMappingRequest) ToDomain(storeID int64) *domain.ImportMapping {
+	columns := make([]domain.ColumnMapping, len(r.Columns))
+	for i, column := range r.Columns {
+		columns[i] = domain.ColumnMapping(column)
+	}
+
+	return &domain.ImportMapping{
+		StoreID: storeID,
+		Name:    r.Name,
+		Columns: columns,
+	}
+}
+
+func ToImportMappingResponse(mapping *domain.ImportMapping) ImportMappingResponse {
+	columns := make([]ColumnMappingSchema, len(mapping.Columns))
+	for i, column := range mapping.Columns {
+		columns[i] = ColumnMappingSchema(column)
+	}
+
+	return ImportMappingResponse{
+		ID:        mapping.ID,
+		StoreID:   mapping.StoreID,
+		Name:      mapping.Name,
+		Columns:   columns,
+		CreatedAt: mapping.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: mapping.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToImportMappingListResponse(mappings []*domain.ImportMapping) ImportMappingListResponse {
+	responses := make([]ImportMappingResponse, len(mappings))
+	for i, mapping := range mappings {
+		responses[i] = ToImportMappingResponse(mapping)
+	}
+
+	return ImportMappingListResponse{
+		Mappings: responses,
+		Total:    len(mappings),
+	}
+}
+
+func ToImportPreviewResponse(rows []domain.ImportPreviewRow) ImportPreviewResponse {
+	responses := make([]ImportPreviewRowResponse, len(rows))
+	for i, row := range rows {
+		responses[i] = ImportPreviewRowResponse{Line: row.Line, Error: row.Error}
+		if row.Error == "" {
+			responses[i].Item = &FeedItemResponse{
+				Name:        row.Item.Name,
+				Description: row.Item.Description,
+				Amount:      row.Item.Amount,
+				Unit:        row.Item.Unit,
+				Price:       row.Item.Price,
+				SKU:         row.Item.SKU,
+				Barcode:     row.Item.Barcode,
+				OnDuplicate: row.Item.OnDuplicate,
+				ImageURLs:   row.Item.ImageURLs,
+			}
+		}
+	}
+
+	return ImportPreviewResponse{Rows: responses}
+}
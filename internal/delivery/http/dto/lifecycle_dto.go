@@ -0,0 +1,15 @@
+package dto
+
+type DrainRequest struct {
+	TimeoutSeconds int `json:"timeout_seconds" binding:"omitempty,min=1,max=3600"`
+}
+
+type DrainResponse struct {
+	Drained  bool  `json:"drained"`
+	InFlight int64 `json:"in_flight"`
+}
+
+type ReadinessResponse struct {
+	Status   string `json:"status"`
+	InFlight int64  `json:"in_flight"`
+}
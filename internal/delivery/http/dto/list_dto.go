@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"net/url"
+
+	"backend-context-engineering-template/pkg/pagination"
+)
+
+// ListResponse is the envelope paged lists are answered with: the page's
+// items, where the page is, and links to it and to the next one. Each list
+// endpoint of v1 also has a response of its own shape, which it keeps
+// answering with while v1 clients are served in compatibility mode.
+type ListResponse[T any] struct {
+	Data  []T       `json:"data"`
+	Meta  ListMeta  `json:"meta"`
+	Links ListLinks `json:"links"`
+}
+
+type ListMeta struct {
+	// Count is how many items the page holds, not how many the whole
+	// list does.
+	Count  int `json:"count"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	// Cursor is sent back as cursor for the next page. It is left out
+	// when the page was not full.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// ListLinks are the URLs of the page and the next one, relative to the
+// host.
+type ListLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+}
+
+// ToListResponse wraps the items of page, which the request for self
+// asked for.
+func ToListResponse[T any](items []T, page pagination.Page, self *url.URL) ListResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	response := ListResponse[T]{
+		Data:  items,
+		Meta:  ListMeta{Count: len(items), Limit: page.Limit, Offset: page.Offset},
+		Links: ListLinks{Self: self.RequestURI()},
+	}
+	if next, ok := page.Next(len(items)); ok {
+		response.Meta.Cursor = next.Encode()
+		query := self.Query()
+		query.Del("offset")
+		query.Set("cursor", response.Meta.Cursor)
+		response.Links.Next = (&url.URL{Path: self.Path, RawQuery: query.Encode()}).RequestURI()
+	}
+	return response
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// SaveConfigSettingRequest replaces a setting's value. Version is the
+// version the value was based on, as last read; 0 for a setting that was
+// never changed.
+type SaveConfigSettingRequest struct {
+	Value   json.RawMessage `json:"value" binding:"required"`
+	Version *int64          `json:"version" binding:"required,min=0"`
+}
+
+// ConfigSettingResponse is a setting of the live configuration. A setting
+// that was never changed has version 0, its default value and no
+// updated_at.
+type ConfigSettingResponse struct {
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	Version   int64           `json:"version"`
+	UpdatedBy string          `json:"updated_by,omitempty"`
+	UpdatedAt string          `json:"updated_at,omitempty"`
+}
+
+type ConfigSettingsResponse struct {
+	Settings []ConfigSettingResponse `json:"settings"`
+}
+
+func ToConfigSettingResponse(setting *domain.ConfigSetting) ConfigSettingResponse {
+	resp := ConfigSettingResponse{
+		Key:       setting.Key,
+		Value:     setting.Value,
+		Version:   setting.Version,
+		UpdatedBy: setting.UpdatedBy,
+	}
+	if !setting.UpdatedAt.IsZero() {
+		resp.UpdatedAt = setting.UpdatedAt.Format(time.RFC3339)
+	}
+	return resp
+}
+
+func ToConfigSettingsResponse(settings []*domain.ConfigSetting) ConfigSettingsResponse {
+	resp := ConfigSettingsResponse{Settings: make([]ConfigSettingResponse, len(settings))}
+	for i, setting := range settings {
+		resp.Settings[i] = ToConfigSettingResponse(setting)
+	}
+	return resp
+}
@@ -0,0 +1,15 @@
+package dto
+
+import "backend-context-engineering-template/internal/domain"
+
+type ReviewProductRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+func (r *ReviewProductRequest) ToDomain() domain.ModerationResult {
+	return domain.ModerationResult{
+		Status: r.Status,
+		Reason: r.Reason,
+	}
+}
@@ -0,0 +1,90 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+type OrderItemRequest struct {
+	ProductID int64             `json:"product_id" binding:"required,min=1"`
+	Quantity  quantity.Quantity `json:"quantity"`
+}
+
+type CreateOrderRequest struct {
+	StoreID int64              `json:"store_id" binding:"required,gt=0"`
+	Items   []OrderItemRequest `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+type OrderItemResponse struct {
+	ProductID int64             `json:"product_id"`
+	Name      string            `json:"name"`
+	Quantity  quantity.Quantity `json:"quantity"`
+	Unit      string            `json:"unit"`
+	UnitPrice float64           `json:"unit_price"`
+	Total     float64           `json:"total"`
+}
+
+type OrderResponse struct {
+	ID        int64               `json:"id"`
+	StoreID   int64               `json:"store_id"`
+	Status    string              `json:"status"`
+	Total     float64             `json:"total"`
+	Items     []OrderItemResponse `json:"items"`
+	CreatedAt string              `json:"created_at"`
+}
+
+type OrderListResponse struct {
+	Orders []OrderResponse `json:"orders"`
+	Total  int             `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+func (r *CreateOrderRequest) ToDomain() *domain.Order {
+	order := &domain.Order{
+		StoreID: r.StoreID,
+		Items:   make([]domain.OrderItem, len(r.Items)),
+	}
+	for i, item := range r.Items {
+		order.Items[i] = domain.OrderItem{ProductID: item.ProductID, Quantity: item.Quantity}
+	}
+	return order
+}
+
+func ToOrderResponse(order *domain.Order) OrderResponse {
+	response := OrderResponse{
+		ID:        order.ID,
+		StoreID:   order.StoreID,
+		Status:    order.Status,
+		Total:     order.Total,
+		Items:     make([]OrderItemResponse, len(order.Items)),
+		CreatedAt: order.CreatedAt.Format(time.RFC3339),
+	}
+	for i, item := range order.Items {
+		response.Items[i] = OrderItemResponse{
+			ProductID: item.ProductID,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			Unit:      item.Unit,
+			UnitPrice: item.UnitPrice,
+			Total:     item.Total(),
+		}
+	}
+	return response
+}
+
+func ToOrderListResponse(orders []*domain.Order, limit, offset int) OrderListResponse {
+	responses := make([]OrderResponse, len(orders))
+	for i, order := range orders {
+		responses[i] = ToOrderResponse(order)
+	}
+
+	return OrderListResponse{
+		Orders: responses,
+		Total:  len(responses),
+		Limit:  limit,
+		Offset: offset,
+	}
+}
@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// CreatePreviewTokenRequest sets how many seconds the preview link lasts;
+// the service default applies when it is omitted. The body may be empty.
+type CreatePreviewTokenRequest struct {
+	ExpiresIn int64 `json:"expires_in" binding:"omitempty,min=1,max=31536000"`
+}
+
+// PreviewTokenResponse carries the token and the path of the product with
+// it, which anyone may open until expires_at.
+type PreviewTokenResponse struct {
+	ProductID int64  `json:"product_id"`
+	Token     string `json:"token"`
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+func (r *CreatePreviewTokenRequest) TTL() time.Duration {
+	return time.Duration(r.ExpiresIn) * time.Second
+}
+
+func ToPreviewTokenResponse(preview *domain.PreviewToken) PreviewTokenResponse {
+	return PreviewTokenResponse{
+		ProductID: preview.ProductID,
+		Token:     preview.Token,
+		URL:       fmt.Sprintf("/api/v1/products/%d?preview_token=%s", preview.ProductID, url.QueryEscape(preview.Token)),
+		ExpiresAt: preview.ExpiresAt.Format(time.RFC3339),
+	}
+}
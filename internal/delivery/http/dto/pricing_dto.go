@@ -0,0 +1,133 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/pricing"
+)
+
+// SavePricingPolicyRequest takes step and ending in major units, such as
+// a step of 0.05 or an ending of 0.99. Decimals defaults to 2.
+type SavePricingPolicyRequest struct {
+	Rounding  string  `json:"rounding" binding:"required,oneof=none increment ending"`
+	Step      float64 `json:"step" binding:"min=0"`
+	Ending    float64 `json:"ending" binding:"min=0"`
+	Direction string  `json:"direction" binding:"omitempty,oneof=nearest up down"`
+	Decimals  *int    `json:"decimals" binding:"omitempty,min=0,max=4"`
+}
+
+type PricingPolicyResponse struct {
+	StoreID   int64   `json:"store_id"`
+	Currency  string  `json:"currency"`
+	Rounding  string  `json:"rounding"`
+	Step      float64 `json:"step,omitempty"`
+	Ending    float64 `json:"ending,omitempty"`
+	Direction string  `json:"direction"`
+	Decimals  int     `json:"decimals"`
+	CreatedAt string  `json:"created_at"`
+	UpdatedAt string  `json:"updated_at"`
+}
+
+type PricingPolicyListResponse struct {
+	Policies []PricingPolicyResponse `json:"policies"`
+}
+
+// PriceQuoteQuery converts a product's price at rate, which defaults to
+// 1, and takes discount_percent off the converted price.
+type PriceQuoteQuery struct {
+	Currency        string  `form:"currency" binding:"required"`
+	Rate            float64 `form:"rate"`
+	DiscountPercent float64 `form:"discount_percent"`
+}
+
+type PriceQuoteResponse struct {
+	ProductID       int64   `json:"product_id"`
+	StoreID         int64   `json:"store_id"`
+	BasePrice       float64 `json:"base_price"`
+	Currency        string  `json:"currency"`
+	Rate            float64 `json:"rate"`
+	DiscountPercent float64 `json:"discount_percent,omitempty"`
+	Unrounded       float64 `json:"unrounded"`
+	Price           float64 `json:"price"`
+	DisplayPrice    string  `json:"display_price"`
+	Rounding        string  `json:"rounding"`
+}
+
+// ToDomain converts the request to a policy in minor units. It fails when
+// step or ending has more digits than the currency's decimals.
+func (r *SavePricingPolicyRequest) ToDomain(storeID int64, currency string) (*domain.PricingPolicy, error) {
+	policy := &domain.PricingPolicy{
+		StoreID:   storeID,
+		Currency:  currency,
+		Rounding:  r.Rounding,
+		Direction: r.Direction,
+		Decimals:  2,
+	}
+	if policy.Direction == "" {
+		policy.Direction = pricing.DirectionNearest
+	}
+	if r.Decimals != nil {
+		policy.Decimals = *r.Decimals
+	}
+
+	var err error
+	if policy.Step, err = pricing.ExactMinor(r.Step, policy.Decimals); err != nil {
+		return nil, fmt.Errorf("step: %w", err)
+	}
+	if policy.Ending, err = pricing.ExactMinor(r.Ending, policy.Decimals); err != nil {
+		return nil, fmt.Errorf("ending: %w", err)
+	}
+	return policy, nil
+}
+
+func (q *PriceQuoteQuery) ToDomain() domain.PriceAdjustment {
+	adjustment := domain.PriceAdjustment{
+		Currency:        q.Currency,
+		Rate:            q.Rate,
+		DiscountPercent: q.DiscountPercent,
+	}
+	if adjustment.Rate == 0 {
+		adjustment.Rate = 1
+	}
+	return adjustment
+}
+
+func ToPricingPolicyResponse(policy *domain.PricingPolicy) PricingPolicyResponse {
+	return PricingPolicyResponse{
+		StoreID:   policy.StoreID,
+		Currency:  policy.Currency,
+		Rounding:  policy.Rounding,
+		Step:      pricing.FromMinor(policy.Step, policy.Decimals),
+		Ending:    pricing.FromMinor(policy.Ending, policy.Decimals),
+		Direction: policy.Direction,
+		Decimals:  policy.Decimals,
+		CreatedAt: policy.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: policy.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToPricingPolicyListResponse(policies []*domain.PricingPolicy) PricingPolicyListResponse {
+	response := PricingPolicyListResponse{Policies: make([]PricingPolicyResponse, len(policies))}
+	for i, policy := range policies {
+		response.Policies[i] = ToPricingPolicyResponse(policy)
+	}
+	return response
+}
+
+func ToPriceQuoteResponse(quote *domain.PriceQuote) PriceQuoteResponse {
+	decimals := quote.Policy.Decimals
+	return PriceQuoteResponse{
+		ProductID:       quote.ProductID,
+		StoreID:         quote.StoreID,
+		BasePrice:       quote.BasePrice,
+		Currency:        quote.Adjustment.Currency,
+		Rate:            quote.Adjustment.Rate,
+		DiscountPercent: quote.Adjustment.DiscountPercent,
+		Unrounded:       pricing.FromMinor(quote.Unrounded, decimals),
+		Price:           pricing.FromMinor(quote.Price, decimals),
+		DisplayPrice:    quote.Adjustment.Currency + " " + pricing.Format(quote.Price, decimals),
+		Rounding:        quote.Policy.Rounding,
+	}
+}
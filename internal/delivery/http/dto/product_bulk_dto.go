@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// Statuses of the items of a bulk product write.
+const (
+	BulkItemCreated  = "created"
+	BulkItemUpdated  = "updated"
+	BulkItemRejected = "rejected"
+)
+
+// BulkProductsRequest carries the products of a bulk write. Items are
+// bound without validation, so each can be validated, and refused, on its
+// own.
+type BulkProductsRequest struct {
+	Products []BulkProductItemRequest `json:"products" binding:"required"`
+}
+
+// BulkProductItemRequest replaces the product with id, like a PUT, or
+// creates a new product when id is omitted.
+type BulkProductItemRequest struct {
+	ID int64 `json:"id" binding:"omitempty,min=1"`
+	CreateProductRequest
+}
+
+// BulkProductResult reports one item of a bulk write, at its index in the
+// request. Rejected items carry the error that refused them.
+type BulkProductResult struct {
+	Index   int              `json:"index"`
+	Status  string           `json:"status"`
+	Product *ProductResponse `json:"product,omitempty"`
+	Error   *ErrorResponse   `json:"error,omitempty"`
+}
+
+// BulkProductsResponse lists a result per item, in the order of the
+// request.
+type BulkProductsResponse struct {
+	Results []BulkProductResult `json:"results"`
+}
+
+// BulkProductsErrorResponse refuses a bulk write, listing the items that
+// were rejected. None of the write was saved.
+type BulkProductsErrorResponse struct {
+	Error   string              `json:"error"`
+	Message string              `json:"message"`
+	Results []BulkProductResult `json:"results"`
+}
+
+func (r *BulkProductItemRequest) ToDomain() domain.BulkProductItem {
+	return domain.BulkProductItem{ID: r.ID, Product: r.CreateProductRequest.ToDomain()}
+}
+
+func ToBulkProductsResponse(results []*domain.BulkProductResult, now time.Time) BulkProductsResponse {
+	response := BulkProductsResponse{Results: make([]BulkProductResult, len(results))}
+	for i, result := range results {
+		product := ToProductResponse(result.Product, now)
+		status := BulkItemUpdated
+		if result.Created {
+			status = BulkItemCreated
+		}
+		response.Results[i] = BulkProductResult{Index: i, Status: status, Product: &product}
+	}
+	return response
+}
+
+// RejectedBulkItem is the result of an item refused with err.
+func RejectedBulkItem(index int, err ErrorResponse) BulkProductResult {
+	return BulkProductResult{Index: index, Status: BulkItemRejected, Error: &err}
+}
@@ -2,36 +2,161 @@ package dto
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/pagination"
+	"backend-context-engineering-template/pkg/quantity"
+	"backend-context-engineering-template/pkg/richtext"
 )
 
 type CreateProductRequest struct {
-	StoreID     int64   `json:"store_id" binding:"required,min=1"`
-	Name        string  `json:"name" binding:"required,min=1,max=100"`
-	Description string  `json:"description" binding:"max=1000"`
-	Amount      int64   `json:"amount" binding:"required,min=0"`
-	Price       float64 `json:"price" binding:"required,min=0"`
+	StoreID           int64              `json:"store_id" binding:"required,min=1"`
+	Name              string             `json:"name" binding:"required,min=1,max=100"`
+	Description       string             `json:"description" binding:"max=1000"`
+	DescriptionFormat string             `json:"description_format" binding:"omitempty,oneof=plain markdown html"`
+	Amount            *quantity.Quantity `json:"amount" binding:"required"`
+	Unit              string             `json:"unit" binding:"omitempty,oneof=piece kg liter"`
+	Price             float64            `json:"price" binding:"required,min=0"`
+	Status            string             `json:"status" binding:"omitempty,oneof=active inactive draft"`
+
+	AllowBackorder      bool              `json:"allow_backorder"`
+	BackorderLimit      quantity.Quantity `json:"backorder_limit"`
+	PreorderReleaseDate *time.Time        `json:"preorder_release_date"`
+	LowStockThreshold   quantity.Quantity `json:"low_stock_threshold"`
+	PublishAt           *time.Time        `json:"publish_at"`
+	UnpublishAt         *time.Time        `json:"unpublish_at"`
 }
 
 type UpdateProductRequest struct {
-	StoreID     int64   `json:"store_id" binding:"required,min=1"`
-	Name        string  `json:"name" binding:"required,min=1,max=100"`
-	Description string  `json:"description" binding:"max=1000"`
-	Amount      int64   `json:"amount" binding:"required,min=0"`
-	Price       float64 `json:"price" binding:"required,min=0"`
+	StoreID           int64              `json:"store_id" binding:"required,min=1"`
+	Name              string             `json:"name" binding:"required,min=1,max=100"`
+	Description       string             `json:"description" binding:"max=1000"`
+	DescriptionFormat string             `json:"description_format" binding:"omitempty,oneof=plain markdown html"`
+	Amount            *quantity.Quantity `json:"amount" binding:"required"`
+	Unit              string             `json:"unit" binding:"omitempty,oneof=piece kg liter"`
+	Price             float64            `json:"price" binding:"required,min=0"`
+	Status            string             `json:"status" binding:"omitempty,oneof=active inactive draft"`
+
+	AllowBackorder      bool              `json:"allow_backorder"`
+	BackorderLimit      quantity.Quantity `json:"backorder_limit"`
+	PreorderReleaseDate *time.Time        `json:"preorder_release_date"`
+	LowStockThreshold   quantity.Quantity `json:"low_stock_threshold"`
+	PublishAt           *time.Time        `json:"publish_at"`
+	UnpublishAt         *time.Time        `json:"unpublish_at"`
+
+	// Version is the product version the update was made against; it
+	// may be sent in If-Match instead.
+	Version *int64 `json:"version" binding:"omitempty,min=1"`
+}
+
+// PatchProductRequest changes only the fields it sets. An empty
+// description clears it, as does null for any of the dates.
+type PatchProductRequest struct {
+	StoreID           *int64             `json:"store_id" binding:"omitempty,min=1"`
+	Name              *string            `json:"name" binding:"omitempty,min=1,max=100"`
+	Description       *string            `json:"description" binding:"omitempty,max=1000"`
+	DescriptionFormat *string            `json:"description_format" binding:"omitempty,oneof=plain markdown html"`
+	Amount            *quantity.Quantity `json:"amount"`
+	Unit              *string            `json:"unit" binding:"omitempty,oneof=piece kg liter"`
+	Price             *float64           `json:"price" binding:"omitempty,min=0"`
+	Status            *string            `json:"status" binding:"omitempty,oneof=active inactive draft"`
+
+	AllowBackorder      *bool              `json:"allow_backorder"`
+	BackorderLimit      *quantity.Quantity `json:"backorder_limit"`
+	PreorderReleaseDate NullableTime       `json:"preorder_release_date"`
+	LowStockThreshold   *quantity.Quantity `json:"low_stock_threshold"`
+	PublishAt           NullableTime       `json:"publish_at"`
+	UnpublishAt         NullableTime       `json:"unpublish_at"`
+
+	// Version is as in UpdateProductRequest.
+	Version *int64 `json:"version" binding:"omitempty,min=1"`
+}
+
+// NullableTime tells a null in a request apart from a missing field:
+// Set is true for both a time and null.
+type NullableTime struct {
+	Set  bool
+	Time *time.Time
+}
+
+// ToDomain returns nil when the field was missing, and an invalid time
+// for null.
+func (t NullableTime) ToDomain() *sql.NullTime {
+	if !t.Set {
+		return nil
+	}
+	if t.Time == nil {
+		return &sql.NullTime{}
+	}
+	return &sql.NullTime{Time: *t.Time, Valid: true}
+}
+
+func (t *NullableTime) UnmarshalJSON(data []byte) error {
+	t.Set = true
+	if string(data) == "null" {
+		t.Time = nil
+		return nil
+	}
+	var value time.Time
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	t.Time = &value
+	return nil
 }
 
 type ProductResponse struct {
-	ID          int64   `json:"id"`
-	StoreID     int64   `json:"store_id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Amount      int64   `json:"amount"`
-	Price       float64 `json:"price"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID                int64             `json:"id"`
+	StoreID           int64             `json:"store_id"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	DescriptionFormat string            `json:"description_format"`
+	DescriptionHTML   string            `json:"description_html,omitempty"`
+	Amount            quantity.Quantity `json:"amount"`
+	Unit              string            `json:"unit"`
+	Price             float64           `json:"price"`
+	Status            string            `json:"status"`
+	ModerationStatus  string            `json:"moderation_status"`
+	ModerationReason  string            `json:"moderation_reason,omitempty"`
+
+	AllowBackorder      bool              `json:"allow_backorder"`
+	BackorderLimit      quantity.Quantity `json:"backorder_limit"`
+	PreorderReleaseDate string            `json:"preorder_release_date,omitempty"`
+	LowStockThreshold   quantity.Quantity `json:"low_stock_threshold"`
+	// Availability is in_stock, low_stock, backorder, preorder,
+	// out_of_stock or discontinued, as of when the response was built.
+	Availability string `json:"availability"`
+
+	PublishAt   string `json:"publish_at,omitempty"`
+	UnpublishAt string `json:"unpublish_at,omitempty"`
+	// Published tells whether the product is listed, as of when the
+	// response was built.
+	Published bool `json:"published"`
+
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	// Version is sent back, as the version field or in If-Match, to
+	// update the product.
+	Version int64 `json:"version"`
+}
+
+// ProductListQuery filters GET /products; the sort is read with the page.
+// Every parameter is optional; name matches part of the product name,
+// ignoring case.
+type ProductListQuery struct {
+	Name       string   `form:"name" binding:"omitempty,max=100"`
+	StoreID    *int64   `form:"store_id" binding:"omitempty,gt=0"`
+	CategoryID *int64   `form:"category_id" binding:"omitempty,gt=0"`
+	MinPrice   *float64 `form:"min_price" binding:"omitempty,gte=0"`
+	MaxPrice   *float64 `form:"max_price" binding:"omitempty,gte=0"`
+	InStock    *bool    `form:"in_stock"`
+}
+
+// ProductStreamQuery limits GET /products/stream to one store's products.
+type ProductStreamQuery struct {
+	StoreID *int64 `form:"store_id" binding:"omitempty,gt=0"`
 }
 
 type ProductListResponse struct {
@@ -39,11 +164,17 @@ type ProductListResponse struct {
 	Total    int               `json:"total"`
 	Limit    int               `json:"limit"`
 	Offset   int               `json:"offset"`
+	// NextCursor is sent back as cursor for the next page. It is left out
+	// when the page was not full.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
+	// Fields lists the fields of the request that were refused, when the
+	// error is about them.
+	Fields []FieldError `json:"fields,omitempty"`
 }
 
 func (r *CreateProductRequest) ToDomain() *domain.Product {
@@ -53,11 +184,21 @@ func (r *CreateProductRequest) ToDomain() *domain.Product {
 	}
 
 	return &domain.Product{
-		StoreID:     r.StoreID,
-		Name:        r.Name,
-		Description: description,
-		Amount:      r.Amount,
-		Price:       r.Price,
+		StoreID:           r.StoreID,
+		Name:              r.Name,
+		Description:       description,
+		DescriptionFormat: r.DescriptionFormat,
+		Amount:            *r.Amount,
+		Unit:              r.Unit,
+		Price:             r.Price,
+		Status:            r.Status,
+
+		AllowBackorder:      r.AllowBackorder,
+		BackorderLimit:      r.BackorderLimit,
+		PreorderReleaseDate: r.PreorderReleaseDate,
+		LowStockThreshold:   r.LowStockThreshold,
+		PublishAt:           r.PublishAt,
+		UnpublishAt:         r.UnpublishAt,
 	}
 }
 
@@ -68,42 +209,124 @@ func (r *UpdateProductRequest) ToDomain() *domain.Product {
 	}
 
 	return &domain.Product{
-		StoreID:     r.StoreID,
-		Name:        r.Name,
-		Description: description,
-		Amount:      r.Amount,
-		Price:       r.Price,
+		StoreID:           r.StoreID,
+		Name:              r.Name,
+		Description:       description,
+		DescriptionFormat: r.DescriptionFormat,
+		Amount:            *r.Amount,
+		Unit:              r.Unit,
+		Price:             r.Price,
+		Status:            r.Status,
+
+		AllowBackorder:      r.AllowBackorder,
+		BackorderLimit:      r.BackorderLimit,
+		PreorderReleaseDate: r.PreorderReleaseDate,
+		LowStockThreshold:   r.LowStockThreshold,
+		PublishAt:           r.PublishAt,
+		UnpublishAt:         r.UnpublishAt,
+	}
+}
+
+func (r *PatchProductRequest) ToDomain() *domain.ProductPatch {
+	patch := &domain.ProductPatch{
+		StoreID:           r.StoreID,
+		Name:              r.Name,
+		DescriptionFormat: r.DescriptionFormat,
+		Amount:            r.Amount,
+		Unit:              r.Unit,
+		Price:             r.Price,
+		Status:            r.Status,
+
+		AllowBackorder:      r.AllowBackorder,
+		BackorderLimit:      r.BackorderLimit,
+		PreorderReleaseDate: r.PreorderReleaseDate.ToDomain(),
+		LowStockThreshold:   r.LowStockThreshold,
+		PublishAt:           r.PublishAt.ToDomain(),
+		UnpublishAt:         r.UnpublishAt.ToDomain(),
+	}
+	if r.Description != nil {
+		patch.Description = &sql.NullString{String: *r.Description, Valid: *r.Description != ""}
+	}
+	return patch
+}
+
+func (q *ProductListQuery) ToDomain() domain.ProductFilter {
+	return domain.ProductFilter{
+		Name:       q.Name,
+		StoreID:    q.StoreID,
+		CategoryID: q.CategoryID,
+		MinPrice:   q.MinPrice,
+		MaxPrice:   q.MaxPrice,
+		InStock:    q.InStock,
 	}
 }
 
-func ToProductResponse(product *domain.Product) ProductResponse {
+// ToProductResponse builds the response with the product's availability
+// at now.
+func ToProductResponse(product *domain.Product, now time.Time) ProductResponse {
 	description := ""
 	if product.Description.Valid {
 		description = product.Description.String
 	}
 
+	releaseDate := formatOptionalTime(product.PreorderReleaseDate)
+
 	return ProductResponse{
-		ID:          product.ID,
-		StoreID:     product.StoreID,
-		Name:        product.Name,
-		Description: description,
-		Amount:      product.Amount,
-		Price:       product.Price,
-		CreatedAt:   product.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   product.UpdatedAt.Format(time.RFC3339),
+		ID:                product.ID,
+		StoreID:           product.StoreID,
+		Name:              product.Name,
+		Description:       description,
+		DescriptionFormat: product.DescriptionFormat,
+		Amount:            product.Amount,
+		Unit:              product.Unit,
+		Price:             product.Price,
+		Status:            product.Status,
+		ModerationStatus:  product.ModerationStatus,
+		ModerationReason:  product.ModerationReason.String,
+
+		AllowBackorder:      product.AllowBackorder,
+		BackorderLimit:      product.BackorderLimit,
+		PreorderReleaseDate: releaseDate,
+		LowStockThreshold:   product.LowStockThreshold,
+		Availability:        product.Availability(now),
+
+		PublishAt:   formatOptionalTime(product.PublishAt),
+		UnpublishAt: formatOptionalTime(product.UnpublishAt),
+		Published:   product.IsPublished(now),
+
+		CreatedAt: product.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: product.UpdatedAt.Format(time.RFC3339),
+		Version:   product.Version,
 	}
 }
 
-func ToProductListResponse(products []*domain.Product, limit, offset int) ProductListResponse {
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// RenderDescription fills DescriptionHTML with the description rendered from
+// its stored format and sanitized for direct embedding in a storefront.
+func (r *ProductResponse) RenderDescription() {
+	r.DescriptionHTML = richtext.ToHTML(r.DescriptionFormat, r.Description)
+}
+
+func ToProductListResponse(products []*domain.Product, page pagination.Page, now time.Time) ProductListResponse {
 	productResponses := make([]ProductResponse, len(products))
 	for i, product := range products {
-		productResponses[i] = ToProductResponse(product)
+		productResponses[i] = ToProductResponse(product, now)
 	}
 
-	return ProductListResponse{
+	response := ProductListResponse{
 		Products: productResponses,
 		Total:    len(products),
-		Limit:    limit,
-		Offset:   offset,
+		Limit:    page.Limit,
+		Offset:   page.Offset,
+	}
+	if next, ok := page.Next(len(products)); ok {
+		response.NextCursor = next.Encode()
 	}
+	return response
 }
@@ -0,0 +1,29 @@
+package dto
+
+import "backend-context-engineering-template/internal/domain"
+
+type ProductImportErrorResponse struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+type ProductImportResponse struct {
+	Created int                          `json:"created"`
+	Updated int                          `json:"updated"`
+	Failed  int                          `json:"failed"`
+	Errors  []ProductImportErrorResponse `json:"errors"`
+}
+
+func ToProductImportResponse(report *domain.ProductImportReport) ProductImportResponse {
+	errors := make([]ProductImportErrorResponse, len(report.Errors))
+	for i, rowErr := range report.Errors {
+		errors[i] = ProductImportErrorResponse(rowErr)
+	}
+
+	return ProductImportResponse{
+		Created: report.Created,
+		Updated: report.Updated,
+		Failed:  report.Failed,
+		Errors:  errors,
+	}
+}
@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// ReconcileRequest starts a reconciliation. Policy defaults to report.
+type ReconcileRequest struct {
+	Source   string `json:"source" binding:"required"`
+	SourceID int64  `json:"source_id" binding:"required,min=1"`
+	Policy   string `json:"policy"`
+}
+
+type ReconciliationRunResponse struct {
+	ID            int64  `json:"id"`
+	StoreID       int64  `json:"store_id"`
+	Source        string `json:"source"`
+	SourceID      int64  `json:"source_id"`
+	Policy        string `json:"policy"`
+	Status        string `json:"status"`
+	Checked       int    `json:"checked"`
+	Discrepancies int    `json:"discrepancies"`
+	Healed        int    `json:"healed"`
+	Failed        int    `json:"failed"`
+	Error         string `json:"error,omitempty"`
+	StartedAt     string `json:"started_at"`
+	FinishedAt    string `json:"finished_at,omitempty"`
+}
+
+type ReconciliationRunListResponse struct {
+	Runs   []ReconciliationRunResponse `json:"runs"`
+	Total  int                         `json:"total"`
+	Limit  int                         `json:"limit"`
+	Offset int                         `json:"offset"`
+}
+
+type DiscrepancyResponse struct {
+	ID          int64  `json:"id"`
+	ProductID   *int64 `json:"product_id"`
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Field       string `json:"field,omitempty"`
+	LocalValue  string `json:"local_value,omitempty"`
+	SourceValue string `json:"source_value,omitempty"`
+	Healed      bool   `json:"healed"`
+}
+
+type DiscrepancyListResponse struct {
+	Discrepancies []DiscrepancyResponse `json:"discrepancies"`
+	Total         int                   `json:"total"`
+	Limit         int                   `json:"limit"`
+	Offset        int                   `json:"offset"`
+}
+
+func (r *ReconcileRequest) PolicyOrDefault() string {
+	if r.Policy == "" {
+		return domain.ReconciliationPolicyReport
+	}
+	return r.Policy
+}
+
+func ToReconciliationRunResponse(run *domain.ReconciliationRun) ReconciliationRunResponse {
+	finishedAt := ""
+	if run.FinishedAt.Valid {
+		finishedAt = run.FinishedAt.Time.Format(time.RFC3339)
+	}
+
+	return ReconciliationRunResponse{
+		ID:            run.ID,
+		StoreID:       run.StoreID,
+		Source:        run.Source,
+		SourceID:      run.SourceID,
+		Policy:        run.Policy,
+		Status:        string(run.Status),
+		Checked:       run.Checked,
+		Discrepancies: run.Discrepancies,
+		Healed:        run.Healed,
+		Failed:        run.Failed,
+		Error:         run.Error.String,
+		StartedAt:     run.StartedAt.Format(time.RFC3339),
+		FinishedAt:    finishedAt,
+	}
+}
+
+func ToReconciliationRunListResponse(runs []*domain.ReconciliationRun, limit, offset int) ReconciliationRunListResponse {
+	responses := make([]ReconciliationRunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = ToReconciliationRunResponse(run)
+	}
+
+	return ReconciliationRunListResponse{
+		Runs:   responses,
+		Total:  len(runs),
+		Limit:  limit,
+		Offset: offset,
+	}
+}
+
+func ToDiscrepancyListResponse(discrepancies []*domain.Discrepancy, limit, offset int) DiscrepancyListResponse {
+	responses := make([]DiscrepancyResponse, len(discrepancies))
+	for i, d := range discrepancies {
+		var productID *int64
+		if d.ProductID.Valid {
+			id := d.ProductID.Int64
+			productID = &id
+		}
+		responses[i] = DiscrepancyResponse{
+			ID:          d.ID,
+			ProductID:   productID,
+			Name:        d.Name,
+			Kind:        d.Kind,
+			Field:       d.Field,
+			LocalValue:  d.LocalValue,
+			SourceValue: d.SourceValue,
+			Healed:      d.Healed,
+		}
+	}
+
+	return DiscrepancyListResponse{
+		Discrepancies: responses,
+		Total:         len(discrepancies),
+		Limit:         limit,
+		Offset:        offset,
+	}
+}
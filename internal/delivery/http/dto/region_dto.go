@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/pkg/replication"
+)
+
+type ReplicationResponse struct {
+	Status        string                 `json:"status"`
+	Region        string                 `json:"region"`
+	PrimaryRegion string                 `json:"primary_region"`
+	Role          string                 `json:"role"`
+	Replica       *ReplicaStatusResponse `json:"replica,omitempty"`
+}
+
+type ReplicaStatusResponse struct {
+	Healthy    bool    `json:"healthy"`
+	LagSeconds float64 `json:"lag_seconds"`
+	MeasuredAt string  `json:"measured_at,omitempty"`
+	Error      string  `json:"error,omitempty"`
+}
+
+func ToReplicaStatusResponse(status replication.Status) *ReplicaStatusResponse {
+	response := &ReplicaStatusResponse{
+		Healthy:    status.Healthy,
+		LagSeconds: status.Lag.Seconds(),
+	}
+	if !status.MeasuredAt.IsZero() {
+		response.MeasuredAt = status.MeasuredAt.Format(time.RFC3339)
+	}
+	if status.Err != nil {
+		response.Error = status.Err.Error()
+	}
+	return response
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type RetentionReportResponse struct {
+	DryRun bool                    `json:"dry_run"`
+	Rules  []RetentionRuleResponse `json:"rules"`
+}
+
+type RetentionRuleResponse struct {
+	Rule       string  `json:"rule"`
+	Table      string  `json:"table"`
+	MaxAgeDays float64 `json:"max_age_days"`
+	Cutoff     string  `json:"cutoff"`
+	Eligible   int64   `json:"eligible"`
+	Oldest     string  `json:"oldest,omitempty"`
+}
+
+func ToRetentionReportResponse(reports []domain.RetentionReport) RetentionReportResponse {
+	rules := make([]RetentionRuleResponse, len(reports))
+	for i, report := range reports {
+		rules[i] = RetentionRuleResponse{
+			Rule:       report.Rule.Name,
+			Table:      report.Rule.Table,
+			MaxAgeDays: report.Rule.MaxAge.Hours() / 24,
+			Cutoff:     report.Cutoff.Format(time.RFC3339),
+			Eligible:   report.Eligible,
+		}
+		if report.Oldest.Valid {
+			rules[i].Oldest = report.Oldest.Time.Format(time.RFC3339)
+		}
+	}
+	return RetentionReportResponse{DryRun: true, Rules: rules}
+}
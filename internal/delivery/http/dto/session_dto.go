@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/pkg/session"
+)
+
+// CreateSessionRequest carries the second factor for identities enrolled
+// in two-factor authentication: a TOTP code or a recovery code.
+type CreateSessionRequest struct {
+	Code string `json:"code"`
+}
+
+type SessionResponse struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	ClientIP   string `json:"client_ip"`
+	CreatedAt  string `json:"created_at"`
+	LastSeenAt string `json:"last_seen_at"`
+	ExpiresAt  string `json:"expires_at"`
+	Current    bool   `json:"current"`
+}
+
+type CreateSessionResponse struct {
+	Session   SessionResponse `json:"session"`
+	CSRFToken string          `json:"csrf_token"`
+}
+
+type SessionListResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+	Total    int               `json:"total"`
+}
+
+type CSRFTokenResponse struct {
+	CSRFToken string `json:"csrf_token"`
+}
+
+func ToSessionResponse(s *session.Session, currentID string) SessionResponse {
+	return SessionResponse{
+		ID:         s.ID,
+		UserAgent:  s.UserAgent,
+		ClientIP:   s.ClientIP,
+		CreatedAt:  s.CreatedAt.Format(time.RFC3339),
+		LastSeenAt: s.LastSeenAt.Format(time.RFC3339),
+		ExpiresAt:  s.ExpiresAt.Format(time.RFC3339),
+		Current:    s.ID == currentID,
+	}
+}
+
+func ToSessionListResponse(sessions []*session.Session, currentID string) SessionListResponse {
+	responses := make([]SessionResponse, len(sessions))
+	for i, s := range sessions {
+		responses[i] = ToSessionResponse(s, currentID)
+	}
+
+	return SessionListResponse{
+		Sessions: responses,
+		Total:    len(responses),
+	}
+}
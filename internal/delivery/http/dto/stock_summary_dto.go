@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type StockSummaryResponse struct {
+	StoreID      int64  `json:"store_id"`
+	Products     int64  `json:"products"`
+	InStock      int64  `json:"in_stock"`
+	LowStock     int64  `json:"low_stock"`
+	Backorder    int64  `json:"backorder"`
+	OutOfStock   int64  `json:"out_of_stock"`
+	Discontinued int64  `json:"discontinued"`
+	CalculatedAt string `json:"calculated_at"`
+}
+
+func ToStockSummaryResponse(summary *domain.StockSummary) StockSummaryResponse {
+	return StockSummaryResponse{
+		StoreID:      summary.StoreID,
+		Products:     summary.Products,
+		InStock:      summary.InStock,
+		LowStock:     summary.LowStock,
+		Backorder:    summary.Backorder,
+		OutOfStock:   summary.OutOfStock,
+		Discontinued: summary.Discontinued,
+		CalculatedAt: summary.CalculatedAt.Format(time.RFC3339),
+	}
+}
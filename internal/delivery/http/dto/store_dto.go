@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type SaveStoreRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type StoreResponse struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type StoreListResponse struct {
+	Stores []StoreResponse `json:"stores"`
+	Total  int             `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+func (r *SaveStoreRequest) ToDomain() *domain.Store {
+	return &domain.Store{Name: r.Name}
+}
+
+func ToStoreResponse(store *domain.Store) StoreResponse {
+	return StoreResponse{
+		ID:        store.ID,
+		Name:      store.Name,
+		CreatedAt: store.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: store.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToStoreListResponse(stores []*domain.Store, limit, offset int) StoreListResponse {
+	responses := make([]StoreResponse, len(stores))
+	for i, store := range stores {
+		responses[i] = ToStoreResponse(store)
+	}
+
+	return StoreListResponse{
+		Stores: responses,
+		Total:  len(stores),
+		Limit:  limit,
+		Offset: offset,
+	}
+}
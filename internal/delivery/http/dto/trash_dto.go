@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type TrashedProductResponse struct {
+	ProductResponse
+	TrashedAt string `json:"trashed_at"`
+	PurgeAt   string `json:"purge_at"`
+}
+
+type TrashListResponse struct {
+	Products []TrashedProductResponse `json:"products"`
+	Total    int                      `json:"total"`
+	Limit    int                      `json:"limit"`
+	Offset   int                      `json:"offset"`
+}
+
+type EmptyTrashResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+func ToTrashListResponse(products []*domain.TrashedProduct, limit, offset int, now time.Time) TrashListResponse {
+	responses := make([]TrashedProductResponse, len(products))
+	for i, product := range products {
+		responses[i] = TrashedProductResponse{
+			ProductResponse: ToProductResponse(&product.Product, now),
+			TrashedAt:       product.TrashedAt.Format(time.RFC3339),
+			PurgeAt:         product.PurgeAt.Format(time.RFC3339),
+		}
+	}
+
+	return TrashListResponse{
+		Products: responses,
+		Total:    len(products),
+		Limit:    limit,
+		Offset:   offset,
+	}
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "backend-context-engineering-template/internal/domain"
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func ToTwoFactorSetupResponse(setup *domain.TwoFactorSetup) TwoFactorSetupResponse {
+	return TwoFactorSetupResponse{
+		Secret:          setup.Secret,
+		ProvisioningURI: setup.ProvisioningURI,
+	}
+}
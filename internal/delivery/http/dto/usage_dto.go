@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type UsageDiscrepancyEntry struct {
+	StoreID       int64  `json:"store_id"`
+	Hour          string `json:"hour"`
+	Kind          string `json:"kind"`
+	RecordedCalls int64  `json:"recorded_calls"`
+	EmittedCalls  int64  `json:"emitted_calls"`
+}
+
+type UsageReconciliationResponse struct {
+	From          string                  `json:"from"`
+	To            string                  `json:"to"`
+	Records       int                     `json:"records"`
+	Emitted       int                     `json:"emitted"`
+	RecordedCalls int64                   `json:"recorded_calls"`
+	EmittedCalls  int64                   `json:"emitted_calls"`
+	Discrepancies []UsageDiscrepancyEntry `json:"discrepancies"`
+}
+
+func ToUsageReconciliationResponse(reconciliation *domain.UsageReconciliation) UsageReconciliationResponse {
+	response := UsageReconciliationResponse{
+		From:          reconciliation.From.Format(time.RFC3339),
+		To:            reconciliation.To.Format(time.RFC3339),
+		Records:       reconciliation.Records,
+		Emitted:       reconciliation.Emitted,
+		RecordedCalls: reconciliation.RecordedCalls,
+		EmittedCalls:  reconciliation.EmittedCalls,
+		Discrepancies: make([]UsageDiscrepancyEntry, len(reconciliation.Discrepancies)),
+	}
+	for i, discrepancy := range reconciliation.Discrepancies {
+		response.Discrepancies[i] = UsageDiscrepancyEntry{
+			StoreID:       discrepancy.StoreID,
+			Hour:          discrepancy.Hour.Format(time.RFC3339),
+			Kind:          discrepancy.Kind,
+			RecordedCalls: discrepancy.RecordedCalls,
+			EmittedCalls:  discrepancy.EmittedCalls,
+		}
+	}
+	return response
+}
@@ -0,0 +1,157 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/pagination"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError says which field of a request was refused, by which rule.
+// Field is the path clients send it at, such as "price" or
+// "items[2].quantity".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func init() {
+	// Binding errors name fields by their JSON (or query) names, the ones
+	// clients know, rather than by the Go field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// ValidationErrorResponse describes a request that could not be bound:
+// malformed JSON, a value of the wrong type or a binding tag it broke.
+// Fields lists the fields at fault when the failure names them.
+func ValidationErrorResponse(err error) ErrorResponse {
+	resp := ErrorResponse{Error: "validation_error", Message: err.Error(), Fields: FieldErrors(err)}
+	if len(resp.Fields) > 0 {
+		messages := make([]string, len(resp.Fields))
+		for i, field := range resp.Fields {
+			messages[i] = field.Message
+		}
+		resp.Message = strings.Join(messages, "; ")
+	}
+	return resp
+}
+
+// FieldErrors lists the fields err names: binding tags a request broke, a
+// JSON value of the wrong type, the field a domain Validate refused or a
+// refused pagination parameter. It returns nil for errors that name no
+// field.
+func FieldErrors(err error) []FieldError {
+	var bindingErrs validator.ValidationErrors
+	if errors.As(err, &bindingErrs) {
+		fields := make([]FieldError, len(bindingErrs))
+		for i, fe := range bindingErrs {
+			fields[i] = bindingFieldError(fe)
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s must be %s", typeErr.Field, jsonTypeName(typeErr.Type)),
+		}}
+	}
+
+	var domainErr *domain.FieldError
+	if errors.As(err, &domainErr) {
+		return []FieldError{{Field: domainErr.Field, Rule: domainErr.Rule, Message: domainErr.Message}}
+	}
+
+	var pageErr *pagination.Error
+	if errors.As(err, &pageErr) {
+		return []FieldError{{Field: pageErr.Param, Rule: pageErr.Rule, Message: pageErr.Message}}
+	}
+
+	return nil
+}
+
+func bindingFieldError(fe validator.FieldError) FieldError {
+	// The namespace starts with the request type's name, which clients
+	// never see.
+	field := fe.Namespace()
+	if _, rest, ok := strings.Cut(field, "."); ok {
+		field = rest
+	}
+	return FieldError{Field: field, Rule: fe.Tag(), Message: bindingMessage(field, fe)}
+}
+
+func bindingMessage(field string, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "min", "max", "len":
+		bound := map[string]string{"min": "at least", "max": "at most", "len": "exactly"}[fe.Tag()]
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters long", field, bound, fe.Param())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("%s must have %s %s items", field, bound, fe.Param())
+		default:
+			return fmt.Sprintf("%s must be %s %s", field, bound, fe.Param())
+		}
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", field, fe.Param())
+	case "gte":
+		return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+	case "lt":
+		return fmt.Sprintf("%s must be less than %s", field, fe.Param())
+	case "lte":
+		return fmt.Sprintf("%s must be at most %s", field, fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", field, strings.Join(strings.Fields(fe.Param()), ", "))
+	case "url":
+		return field + " must be a URL"
+	case "email":
+		return field + " must be an email address"
+	default:
+		return fmt.Sprintf("%s does not satisfy %s", field, fe.Tag())
+	}
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
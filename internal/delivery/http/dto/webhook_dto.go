@@ -0,0 +1,131 @@
+package dto
+
+import (
+	"database/sql"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// CreateWebhookRequest subscribes to every store and event type unless
+// store_id or event_types narrow it.
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url"`
+	Description string   `json:"description" binding:"max=255"`
+	StoreID     int64    `json:"store_id" binding:"omitempty,gt=0"`
+	EventTypes  []string `json:"event_types"`
+}
+
+type WebhookResponse struct {
+	ID          int64    `json:"id"`
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	StoreID     int64    `json:"store_id,omitempty"`
+	EventTypes  []string `json:"event_types,omitempty"`
+	Status      string   `json:"status"`
+	PausedAt    string   `json:"paused_at,omitempty"`
+	PauseReason string   `json:"pause_reason,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// WebhookSecretResponse shows a newly rotated signing secret. It is the
+// only time the secret is returned.
+type WebhookSecretResponse struct {
+	StoreID           int64  `json:"store_id,omitempty"`
+	Secret            string `json:"secret"`
+	RotatedAt         string `json:"rotated_at"`
+	PreviousExpiresAt string `json:"previous_expires_at,omitempty"`
+}
+
+type WebhookListResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+	Total    int               `json:"total"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
+
+type WebhookHealthResponse struct {
+	SubscriptionID      int64   `json:"subscription_id"`
+	Deliveries          int64   `json:"deliveries"`
+	Failures            int64   `json:"failures"`
+	ConsecutiveFailures int     `json:"consecutive_failures"`
+	SuccessRate         float64 `json:"success_rate"`
+	AvgLatencyMs        int64   `json:"avg_latency_ms"`
+	LastSuccessAt       string  `json:"last_success_at,omitempty"`
+	LastFailureAt       string  `json:"last_failure_at,omitempty"`
+	LastError           string  `json:"last_error,omitempty"`
+}
+
+func (r *CreateWebhookRequest) ToDomain() *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		URL:         r.URL,
+		Description: r.Description,
+		StoreID:     sql.NullInt64{Int64: r.StoreID, Valid: r.StoreID > 0},
+		EventTypes:  r.EventTypes,
+	}
+}
+
+func ToWebhookResponse(subscription *domain.WebhookSubscription) WebhookResponse {
+	response := WebhookResponse{
+		ID:          subscription.ID,
+		URL:         subscription.URL,
+		Description: subscription.Description,
+		StoreID:     subscription.StoreID.Int64,
+		EventTypes:  subscription.EventTypes,
+		Status:      "active",
+		CreatedAt:   subscription.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   subscription.UpdatedAt.Format(time.RFC3339),
+	}
+	if subscription.PausedAt.Valid {
+		response.Status = "paused"
+		response.PausedAt = subscription.PausedAt.Time.Format(time.RFC3339)
+		response.PauseReason = subscription.PauseReason
+	}
+	return response
+}
+
+func ToWebhookListResponse(subscriptions []*domain.WebhookSubscription, limit, offset int) WebhookListResponse {
+	responses := make([]WebhookResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		responses[i] = ToWebhookResponse(subscription)
+	}
+
+	return WebhookListResponse{
+		Webhooks: responses,
+		Total:    len(responses),
+		Limit:    limit,
+		Offset:   offset,
+	}
+}
+
+func ToWebhookHealthResponse(health *domain.WebhookHealth) WebhookHealthResponse {
+	response := WebhookHealthResponse{
+		SubscriptionID:      health.SubscriptionID,
+		Deliveries:          health.Deliveries,
+		Failures:            health.Failures,
+		ConsecutiveFailures: health.ConsecutiveFailures,
+		SuccessRate:         health.SuccessRate,
+		AvgLatencyMs:        health.AvgLatency.Milliseconds(),
+		LastError:           health.LastError,
+	}
+	if health.LastSuccessAt.Valid {
+		response.LastSuccessAt = health.LastSuccessAt.Time.Format(time.RFC3339)
+	}
+	if health.LastFailureAt.Valid {
+		response.LastFailureAt = health.LastFailureAt.Time.Format(time.RFC3339)
+	}
+	return response
+}
+
+func ToWebhookSecretResponse(secret *domain.WebhookSigningSecret) WebhookSecretResponse {
+	response := WebhookSecretResponse{
+		StoreID:   secret.StoreID,
+		Secret:    secret.Current,
+		RotatedAt: secret.RotatedAt.Format(time.RFC3339),
+	}
+	if secret.Previous != "" {
+		response.PreviousExpiresAt = secret.PreviousExpiresAt.Format(time.RFC3339)
+	}
+	return response
+}
@@ -0,0 +1,13 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// EventSchemaRoutes serves the schemas of the published events.
+func EventSchemaRoutes(handler *handlers.EventSchemaHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.API.GET("/event-schemas", handler.GetSchemas)
+		r.API.GET("/event-schemas/:type/:version", handler.GetSchema)
+	})
+}
@@ -0,0 +1,22 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// FeedRoutes serves product feeds and their runs under /api/v1/feeds.
+func FeedRoutes(handler *handlers.FeedHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		feeds := r.API.Group("/feeds")
+		{
+			feeds.POST("", handler.CreateFeed)
+			feeds.GET("/:id", handler.GetFeed)
+			feeds.GET("", handler.GetFeeds)
+			feeds.DELETE("/:id", handler.DeleteFeed)
+			feeds.POST("/:id/runs", handler.RunFeed)
+			feeds.GET("/:id/runs", handler.GetFeedRuns)
+			feeds.GET("/:id/runs/:run_id", handler.GetFeedRun)
+			feeds.GET("/:id/runs/:run_id/images", handler.GetFeedRunImages)
+		}
+	})
+}
@@ -0,0 +1,13 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// GraphQLRoutes serves the GraphQL API at /graphql.
+func GraphQLRoutes(handler *handlers.GraphQLHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Root.GET("/graphql", handler.Serve)
+		r.Root.POST("/graphql", handler.Serve)
+	})
+}
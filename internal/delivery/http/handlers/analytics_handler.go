@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	analyticsUseCase usecase.AnalyticsUseCaseInterface
+}
+
+func NewAnalyticsHandler(analyticsUseCase usecase.AnalyticsUseCaseInterface) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsUseCase: analyticsUseCase,
+	}
+}
+
+// RecordEvents answers 202: events are counted in memory and written in
+// the background.
+func (h *AnalyticsHandler) RecordEvents(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req dto.RecordAnalyticsEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	accepted, err := h.analyticsUseCase.RecordEvents(ctx, req.ToDomain())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.RecordAnalyticsEventsResponse{
+		Accepted: accepted,
+		Dropped:  len(req.Events) - accepted,
+	})
+}
+
+// GetProductStats is for the product's store owner.
+func (h *AnalyticsHandler) GetProductStats(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	productID, ok := parseIDParam(c, "id", "Product")
+	if !ok {
+		return
+	}
+
+	var query dto.ProductStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_query",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	stats, err := h.analyticsUseCase.GetProductStats(ctx, productID, query.Days)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	middleware.SetStoreID(c, stats.StoreID)
+	if !requireStoreOwner(c, stats.StoreID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToProductStatsResponse(stats))
+}
+
+func (h *AnalyticsHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "product_not_found",
+			Message: "Product not found",
+		})
+	case errors.Is(err, domain.ErrInvalidProduct):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_product",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidAnalyticsEvent):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_analytics_event",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAnalyticsUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsUseCase) RecordEvents(ctx context.Context, events []domain.AnalyticsEvent) (int, error) {
+	args := m.Called(ctx, events)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAnalyticsUseCase) GetProductStats(ctx context.Context, productID int64, days int) (*domain.ProductStats, error) {
+	args := m.Called(ctx, productID, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProductStats), args.Error(1)
+}
+
+func setupAnalyticsTestRouter(handler *AnalyticsHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	r.POST("/api/v1/analytics/events", handler.RecordEvents)
+	r.GET("/api/v1/products/:id/stats", handler.GetProductStats)
+
+	return r
+}
+
+func TestAnalyticsHandler_RecordEvents(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         interface{}
+		mockFn       func(*MockAnalyticsUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "accepted with some dropped",
+			body: map[string]interface{}{"events": []map[string]interface{}{
+				{"product_id": 1, "type": "view"},
+				{"product_id": 1, "type": "add_to_cart", "occurred_at": "2024-03-01T10:00:00Z"},
+			}},
+			mockFn: func(m *MockAnalyticsUseCase) {
+				m.On("RecordEvents", mock.Anything, mock.MatchedBy(func(events []domain.AnalyticsEvent) bool {
+					return len(events) == 2 && events[0].OccurredAt.IsZero() && !events[1].OccurredAt.IsZero()
+				})).Return(1, nil)
+			},
+			expectedCode: http.StatusAccepted,
+			expectedBody: `{"accepted":1,"dropped":1}`,
+		},
+		{
+			name:         "unknown type",
+			body:         map[string]interface{}{"events": []map[string]interface{}{{"product_id": 1, "type": "click"}}},
+			mockFn:       func(m *MockAnalyticsUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "empty batch",
+			body:         map[string]interface{}{"events": []map[string]interface{}{}},
+			mockFn:       func(m *MockAnalyticsUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "future event",
+			body: map[string]interface{}{"events": []map[string]interface{}{{"product_id": 1, "type": "view"}}},
+			mockFn: func(m *MockAnalyticsUseCase) {
+				m.On("RecordEvents", mock.Anything, mock.Anything).Return(0, domain.ErrInvalidAnalyticsEvent)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockAnalyticsUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupAnalyticsTestRouter(NewAnalyticsHandler(mockUseCase))
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/analytics/events", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAnalyticsHandler_GetProductStats(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		apiKey       string
+		mockFn       func(*MockAnalyticsUseCase)
+		expectedCode int
+	}{
+		{
+			name:   "owner reads stats",
+			path:   "/api/v1/products/4/stats?days=7",
+			apiKey: testAPIKey,
+			mockFn: func(m *MockAnalyticsUseCase) {
+				m.On("GetProductStats", mock.Anything, int64(4), 7).Return(&domain.ProductStats{ProductID: 4, StoreID: 3, Views: 12}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "another store's product",
+			path:   "/api/v1/products/4/stats",
+			apiKey: testAPIKey,
+			mockFn: func(m *MockAnalyticsUseCase) {
+				m.On("GetProductStats", mock.Anything, int64(4), 0).Return(&domain.ProductStats{ProductID: 4, StoreID: 4}, nil)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "anonymous caller",
+			path: "/api/v1/products/4/stats",
+			mockFn: func(m *MockAnalyticsUseCase) {
+				m.On("GetProductStats", mock.Anything, int64(4), 0).Return(&domain.ProductStats{ProductID: 4, StoreID: 3}, nil)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "days out of range",
+			path:         "/api/v1/products/4/stats?days=1000",
+			apiKey:       testAPIKey,
+			mockFn:       func(m *MockAnalyticsUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "product not found",
+			path:   "/api/v1/products/9/stats",
+			apiKey: testAPIKey,
+			mockFn: func(m *MockAnalyticsUseCase) {
+				m.On("GetProductStats", mock.Anything, int64(9), 0).Return(nil, domain.ErrProductNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockAnalyticsUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupAnalyticsTestRouter(NewAnalyticsHandler(mockUseCase))
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/lockout"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	authUseCase usecase.AuthUseCaseInterface
+	guard       *lockout.Guard
+}
+
+// NewAuthHandler builds the register, login and refresh endpoints. guard
+// may be nil to disable brute-force protection of logins.
+func NewAuthHandler(authUseCase usecase.AuthUseCaseInterface, guard *lockout.Guard) *AuthHandler {
+	return &AuthHandler{
+		authUseCase: authUseCase,
+		guard:       guard,
+	}
+}
+
+func (h *AuthHandler) Register(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req dto.RegisterRequest
+	if !bindAuthRequest(c, &req) {
+		return
+	}
+
+	user, err := h.authUseCase.Register(ctx, req.Email, req.Password)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToUserResponse(user))
+}
+
+// Login exchanges an email and password, plus a two-factor code when the
+// user is enrolled, for an access and refresh token. Failed logins count
+// against the email and the client IP.
+func (h *AuthHandler) Login(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req dto.LoginRequest
+	if !bindAuthRequest(c, &req) {
+		return
+	}
+
+	var tokens *domain.AuthTokens
+	err := guardedVerify(ctx, c, h.guard, "email:"+domain.NormalizeEmail(req.Email), func() error {
+		var err error
+		tokens, err = h.authUseCase.Login(ctx, req.Email, req.Password, req.Code)
+		return err
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTokenResponse(tokens))
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req dto.RefreshTokenRequest
+	if !bindAuthRequest(c, &req) {
+		return
+	}
+
+	tokens, err := h.authUseCase.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToTokenResponse(tokens))
+}
+
+func bindAuthRequest(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func (h *AuthHandler) handleError(c *gin.Context, err error) {
+	if handleTwoFactorError(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrInvalidUser):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrEmailTaken):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "email_taken",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "invalid_credentials",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidRefreshToken):
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "invalid_refresh_token",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
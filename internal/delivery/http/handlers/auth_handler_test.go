@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/lockout"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAuthUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAuthUseCase) Register(ctx context.Context, email, password string) (*domain.User, error) {
+	args := m.Called(ctx, email, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockAuthUseCase) Login(ctx context.Context, email, password, code string) (*domain.AuthTokens, error) {
+	args := m.Called(ctx, email, password, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AuthTokens), args.Error(1)
+}
+
+func (m *MockAuthUseCase) Refresh(ctx context.Context, refreshToken string) (*domain.AuthTokens, error) {
+	args := m.Called(ctx, refreshToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.AuthTokens), args.Error(1)
+}
+
+func setupAuthTestRouter(handler *AuthHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	auth := r.Group("/api/v1/auth")
+	{
+		auth.POST("/register", handler.Register)
+		auth.POST("/login", handler.Login)
+		auth.POST("/refresh", handler.Refresh)
+	}
+
+	return r
+}
+
+func TestAuthHandler(t *testing.T) {
+	tokens := &domain.AuthTokens{
+		AccessToken:      "access",
+		AccessExpiresIn:  15 * time.Minute,
+		RefreshToken:     "refresh",
+		RefreshExpiresAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		body         string
+		mockFn       func(*MockAuthUseCase)
+		expectedCode int
+		expectedErr  string
+		expectedBody string
+	}{
+		{
+			name: "register",
+			path: "/api/v1/auth/register",
+			body: `{"email":"ada@example.com","password":"correct horse"}`,
+			mockFn: func(m *MockAuthUseCase) {
+				m.On("Register", mock.Anything, "ada@example.com", "correct horse").
+					Return(&domain.User{ID: 1, Email: "ada@example.com", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, nil)
+			},
+			expectedCode: http.StatusCreated,
+			expectedBody: `{"id":1,"email":"ada@example.com","created_at":"2024-01-01T00:00:00Z"}`,
+		},
+		{
+			name:         "register without password",
+			path:         "/api/v1/auth/register",
+			body:         `{"email":"ada@example.com"}`,
+			mockFn:       func(m *MockAuthUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "invalid_request",
+		},
+		{
+			name: "register with short password",
+			path: "/api/v1/auth/register",
+			body: `{"email":"ada@example.com","password":"short"}`,
+			mockFn: func(m *MockAuthUseCase) {
+				m.On("Register", mock.Anything, "ada@example.com", "short").Return(nil, domain.ErrInvalidUser)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "invalid_user",
+		},
+		{
+			name: "register taken email",
+			path: "/api/v1/auth/register",
+			body: `{"email":"ada@example.com","password":"correct horse"}`,
+			mockFn: func(m *MockAuthUseCase) {
+				m.On("Register", mock.Anything, "ada@example.com", "correct horse").Return(nil, domain.ErrEmailTaken)
+			},
+			expectedCode: http.StatusConflict,
+			expectedErr:  "email_taken",
+		},
+		{
+			name: "login",
+			path: "/api/v1/auth/login",
+			body: `{"email":"ada@example.com","password":"correct horse"}`,
+			mockFn: func(m *MockAuthUseCase) {
+				m.On("Login", mock.Anything, "ada@example.com", "correct horse", "").Return(tokens, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"access_token":"access","token_type":"Bearer","expires_in":900,"refresh_token":"refresh","refresh_expires_at":"2024-02-01T00:00:00Z"}`,
+		},
+		{
+			name: "login with wrong password",
+			path: "/api/v1/auth/login",
+			body: `{"email":"ada@example.com","password":"wrong horse"}`,
+			mockFn: func(m *MockAuthUseCase) {
+				m.On("Login", mock.Anything, "ada@example.com", "wrong horse", "").Return(nil, domain.ErrInvalidCredentials)
+			},
+			expectedCode: http.StatusUnauthorized,
+			expectedErr:  "invalid_credentials",
+		},
+		{
+			name: "login without two-factor code",
+			path: "/api/v1/auth/login",
+			body: `{"email":"ada@example.com","password":"correct horse"}`,
+			mockFn: func(m *MockAuthUseCase) {
+				m.On("Login", mock.Anything, "ada@example.com", "correct horse", "").Return(nil, domain.ErrTwoFactorRequired)
+			},
+			expectedCode: http.StatusUnauthorized,
+			expectedErr:  "two_factor_required",
+		},
+		{
+			name: "login with invalid two-factor code",
+			path: "/api/v1/auth/login",
+			body: `{"email":"ada@example.com","password":"correct horse","code":"000000"}`,
+			mockFn: func(m *MockAuthUseCase) {
+				m.On("Login", mock.Anything, "ada@example.com", "correct horse", "000000").Return(nil, domain.ErrInvalidTwoFactorCode)
+			},
+			expectedCode: http.StatusUnauthorized,
+			expectedErr:  "invalid_two_factor_code",
+		},
+		{
+			name: "login before required enrollment",
+			path: "/api/v1/auth/login",
+			body: `{"email":"ada@example.com","password":"correct horse"}`,
+			mockFn: func(m *MockAuthUseCase) {
+				m.On("Login", mock.Anything, "ada@example.com", "correct horse", "").Return(nil, domain.ErrTwoFactorEnrollment)
+			},
+			expectedCode: http.StatusForbidden,
+			expectedErr:  "two_factor_enrollment_required",
+		},
+		{
+			name: "refresh",
+			path: "/api/v1/auth/refresh",
+			body: `{"refresh_token":"refresh"}`,
+			mockFn: func(m *MockAuthUseCase) {
+				m.On("Refresh", mock.Anything, "refresh").Return(tokens, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "refresh with invalid token",
+			path: "/api/v1/auth/refresh",
+			body: `{"refresh_token":"access"}`,
+			mockFn: func(m *MockAuthUseCase) {
+				m.On("Refresh", mock.Anything, "access").Return(nil, domain.ErrInvalidRefreshToken)
+			},
+			expectedCode: http.StatusUnauthorized,
+			expectedErr:  "invalid_refresh_token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := new(MockAuthUseCase)
+			tt.mockFn(mockUseCase)
+			router := setupAuthTestRouter(NewAuthHandler(mockUseCase, nil))
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedErr != "" {
+				var resp dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.expectedErr, resp.Error)
+			}
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_LoginLockout(t *testing.T) {
+	guard := lockout.NewGuard(ratelimit.NewMemoryStore(clock.Real()), lockout.Config{
+		MaxFailures:     2,
+		MaxIPFailures:   10,
+		Window:          time.Minute,
+		LockoutDuration: time.Minute,
+	}, nil, clock.Real(), logrus.New())
+
+	mockUseCase := new(MockAuthUseCase)
+	mockUseCase.On("Login", mock.Anything, mock.Anything, "wrong horse", "").Return(nil, domain.ErrInvalidCredentials).Twice()
+	router := setupAuthTestRouter(NewAuthHandler(mockUseCase, guard))
+
+	send := func(email string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBufferString(`{"email":"`+email+`","password":"wrong horse"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send("ada@example.com").Code)
+	assert.Equal(t, http.StatusUnauthorized, send("Ada@Example.com").Code)
+
+	w := send("ada@example.com")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	mockUseCase.AssertExpectations(t)
+}
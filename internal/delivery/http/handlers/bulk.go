@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/apikey"
+
+	"github.com/gin-gonic/gin"
+)
+
+// confirmBulkOperationHeader carries the token that confirms a bulk
+// operation held back by an earlier attempt.
+const confirmBulkOperationHeader = "X-Confirm-Bulk-Operation"
+
+// bulkCaller identifies the caller to the bulk guard. Admins and API keys
+// with the bulk scope are elevated.
+func bulkCaller(c *gin.Context) usecase.BulkCaller {
+	key := middleware.CurrentAPIKey(c)
+	return usecase.BulkCaller{
+		Actor:    middleware.ClientIdentity(c),
+		ClientIP: c.ClientIP(),
+		Elevated: middleware.IsAdmin(c) || (key != nil && key.HasScope(apikey.ScopeBulk)),
+		Token:    c.GetHeader(confirmBulkOperationHeader),
+	}
+}
+
+// handleBulkError writes the response for errors of the bulk guard and
+// reports whether err was one.
+func handleBulkError(c *gin.Context, err error) bool {
+	var held *domain.BulkConfirmationError
+	switch {
+	case errors.As(err, &held):
+		c.JSON(http.StatusPreconditionRequired, dto.ToBulkConfirmationResponse(held.Confirmation,
+			err.Error()+"; repeat the request with "+confirmBulkOperationHeader+": <confirmation_token> before expires_at"))
+	case errors.Is(err, domain.ErrInsufficientScope):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{
+			Error:   "insufficient_scope",
+			Message: err.Error() + "; it needs an API key with the " + apikey.ScopeBulk + " scope",
+		})
+	case errors.Is(err, domain.ErrInvalidConfirmationToken):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{
+			Error:   "invalid_confirmation_token",
+			Message: err.Error() + "; repeat the request without " + confirmBulkOperationHeader + " for a new one",
+		})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BundleHandler struct {
+	bundleUseCase usecase.BundleUseCaseInterface
+}
+
+func NewBundleHandler(bundleUseCase usecase.BundleUseCaseInterface) *BundleHandler {
+	return &BundleHandler{
+		bundleUseCase: bundleUseCase,
+	}
+}
+
+func (h *BundleHandler) GetBundle(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Bundle")
+	if !ok {
+		return
+	}
+
+	bundle, err := h.bundleUseCase.GetBundle(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	middleware.SetStoreID(c, bundle.Product.StoreID)
+
+	c.JSON(http.StatusOK, dto.ToBundleResponse(bundle))
+}
+
+func (h *BundleHandler) SaveBundle(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Bundle")
+	if !ok {
+		return
+	}
+
+	var req dto.SaveBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind save bundle request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	bundle, err := h.bundleUseCase.SaveBundle(ctx, id, req.ToDomain())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	middleware.SetStoreID(c, bundle.Product.StoreID)
+
+	c.JSON(http.StatusOK, dto.ToBundleResponse(bundle))
+}
+
+func (h *BundleHandler) DeleteBundle(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Bundle")
+	if !ok {
+		return
+	}
+
+	if err := h.bundleUseCase.DeleteBundle(ctx, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *BundleHandler) SellBundle(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Bundle")
+	if !ok {
+		return
+	}
+
+	var req dto.SellBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind sell bundle request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	bundle, err := h.bundleUseCase.SellBundle(ctx, id, req.Count)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	middleware.SetStoreID(c, bundle.Product.StoreID)
+
+	c.JSON(http.StatusOK, dto.ToBundleResponse(bundle))
+}
+
+func (h *BundleHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrBundleNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "bundle_not_found",
+			Message: "Product is not a bundle",
+		})
+	case errors.Is(err, domain.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "product_not_found",
+			Message: "Product not found",
+		})
+	case errors.Is(err, domain.ErrInvalidBundle):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_bundle",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "insufficient_stock",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockBundleUseCase struct {
+	mock.Mock
+}
+
+func (m *MockBundleUseCase) GetBundle(ctx context.Context, id int64) (*domain.Bundle, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Bundle), args.Error(1)
+}
+
+func (m *MockBundleUseCase) SaveBundle(ctx context.Context, id int64, components []domain.BundleComponent) (*domain.Bundle, error) {
+	args := m.Called(ctx, id, components)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Bundle), args.Error(1)
+}
+
+func (m *MockBundleUseCase) DeleteBundle(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockBundleUseCase) SellBundle(ctx context.Context, id int64, count int64) (*domain.Bundle, error) {
+	args := m.Called(ctx, id, count)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Bundle), args.Error(1)
+}
+
+func setupBundleTestRouter(handler *BundleHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	bundles := r.Group("/api/v1/bundles")
+	{
+		bundles.GET("/:id", handler.GetBundle)
+		bundles.PUT("/:id", handler.SaveBundle)
+		bundles.DELETE("/:id", handler.DeleteBundle)
+		bundles.POST("/:id/sales", handler.SellBundle)
+	}
+
+	return r
+}
+
+func testBundle(available int64) *domain.Bundle {
+	return &domain.Bundle{
+		Product: &domain.Product{ID: 50, StoreID: 7, Name: "Breakfast Kit"},
+		Components: []domain.BundleComponent{
+			{BundleID: 50, ProductID: 42, Quantity: quantity.MustParse("0.5"), Unit: domain.UnitKilogram, Stock: quantity.New(2), Available: 4},
+		},
+		Available: available,
+	}
+}
+
+func TestBundleHandler_SaveBundle(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		mockFn     func(*MockBundleUseCase)
+		wantStatus int
+	}{
+		{
+			name: "fractional component",
+			body: `{"components":[{"product_id":42,"quantity":0.5}]}`,
+			mockFn: func(m *MockBundleUseCase) {
+				want := []domain.BundleComponent{{ProductID: 42, Quantity: quantity.MustParse("0.5")}}
+				m.On("SaveBundle", mock.Anything, int64(50), want).Return(testBundle(4), nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "no components",
+			body:       `{"components":[]}`,
+			mockFn:     func(m *MockBundleUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing product",
+			body:       `{"components":[{"quantity":1}]}`,
+			mockFn:     func(m *MockBundleUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "rejected by use case",
+			body: `{"components":[{"product_id":50,"quantity":1}]}`,
+			mockFn: func(m *MockBundleUseCase) {
+				m.On("SaveBundle", mock.Anything, int64(50), mock.Anything).Return(nil, domain.ErrInvalidBundle)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockBundleUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupBundleTestRouter(NewBundleHandler(mockUseCase))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/bundles/50", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBundleHandler_GetBundle(t *testing.T) {
+	mockUseCase := &MockBundleUseCase{}
+	mockUseCase.On("GetBundle", mock.Anything, int64(50)).Return(testBundle(4), nil)
+	mockUseCase.On("GetBundle", mock.Anything, int64(42)).Return(nil, domain.ErrBundleNotFound)
+
+	router := setupBundleTestRouter(NewBundleHandler(mockUseCase))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/bundles/50", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp dto.BundleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(4), resp.Available)
+	require.Len(t, resp.Components, 1)
+	assert.Equal(t, quantity.MustParse("0.5"), resp.Components[0].Quantity)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/bundles/42", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestBundleHandler_SellBundle(t *testing.T) {
+	mockUseCase := &MockBundleUseCase{}
+	mockUseCase.On("SellBundle", mock.Anything, int64(50), int64(1)).Return(testBundle(3), nil)
+	mockUseCase.On("SellBundle", mock.Anything, int64(50), int64(9)).Return(nil, domain.ErrInsufficientStock)
+
+	router := setupBundleTestRouter(NewBundleHandler(mockUseCase))
+
+	sell := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/bundles/50/sales", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := sell(`{"count":1}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp dto.BundleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(3), resp.Available)
+
+	assert.Equal(t, http.StatusConflict, sell(`{"count":9}`).Code)
+	assert.Equal(t, http.StatusBadRequest, sell(`{"count":0}`).Code)
+}
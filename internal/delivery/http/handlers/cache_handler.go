@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/cache"
+	"backend-context-engineering-template/pkg/hotkeys"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CacheHandler struct {
+	tracker *hotkeys.Tracker
+	stats   *cache.Stats
+}
+
+func NewCacheHandler(tracker *hotkeys.Tracker, stats *cache.Stats) *CacheHandler {
+	return &CacheHandler{
+		tracker: tracker,
+		stats:   stats,
+	}
+}
+
+func (h *CacheHandler) GetHotKeys(c *gin.Context) {
+	keys := h.tracker.Hot()
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 && limit < len(keys) {
+		keys = keys[:limit]
+	}
+
+	c.JSON(http.StatusOK, dto.ToHotKeysResponse(keys))
+}
+
+// GetStats reports cache effectiveness per endpoint and per tier since the
+// instance started.
+func (h *CacheHandler) GetStats(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.ToCacheStatsResponse(h.stats.Endpoints(), h.stats.Invalidations()))
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/cache"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/hotkeys"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCacheTestRouter(handler *CacheHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.GET("/admin/cache/hot-keys", handler.GetHotKeys)
+	r.GET("/admin/cache/stats", handler.GetStats)
+
+	return r
+}
+
+func TestCacheHandler_GetHotKeys(t *testing.T) {
+	tracker := hotkeys.NewTracker(10, 3, nil, clock.Real())
+	for i := 0; i < 5; i++ {
+		tracker.Record(1)
+	}
+	for i := 0; i < 3; i++ {
+		tracker.Record(2)
+	}
+	tracker.Record(3)
+
+	router := setupCacheTestRouter(NewCacheHandler(tracker, cache.NewStats()))
+
+	tests := []struct {
+		name        string
+		query       string
+		expectedIDs []int64
+	}{
+		{
+			name:        "all hot keys",
+			expectedIDs: []int64{1, 2},
+		},
+		{
+			name:        "limited",
+			query:       "?limit=1",
+			expectedIDs: []int64{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/cache/hot-keys"+tt.query, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var resp dto.HotKeysResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+			ids := make([]int64, 0, len(resp.HotKeys))
+			for _, key := range resp.HotKeys {
+				ids = append(ids, key.ProductID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+			assert.Equal(t, len(tt.expectedIDs), resp.Count)
+		})
+	}
+}
+
+func TestCacheHandler_GetStats(t *testing.T) {
+	stats := cache.NewStats()
+	ctx := cache.WithEndpoint(context.Background(), "GET /api/v1/products/:id")
+	stats.Hit(ctx, cache.TierMemory, 100)
+	stats.Miss(ctx, cache.TierMemory, false)
+	stats.Invalidated(cache.TierMemory)
+
+	router := setupCacheTestRouter(NewCacheHandler(hotkeys.NewTracker(10, 3, nil, clock.Real()), stats))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/cache/stats", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.CacheStatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Endpoints, 1)
+	assert.Equal(t, 0.5, response.Endpoints[0].HitRatio)
+	require.Len(t, response.Tiers, 1)
+	assert.Equal(t, int64(1), response.Tiers[0].Invalidations)
+	assert.Equal(t, int64(100), response.Tiers[0].BytesSaved)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogDiffHandler struct {
+	diffUseCase usecase.CatalogDiffUseCaseInterface
+}
+
+func NewCatalogDiffHandler(diffUseCase usecase.CatalogDiffUseCaseInterface) *CatalogDiffHandler {
+	return &CatalogDiffHandler{
+		diffUseCase: diffUseCase,
+	}
+}
+
+// GetDiff compares the catalog at the from query parameter with the
+// catalog at to, or now when to is left out. Both are RFC 3339 times.
+func (h *CatalogDiffHandler) GetDiff(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	from, ok := parseTimeQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseTimeQuery(c, "to")
+	if !ok {
+		return
+	}
+
+	diff, err := h.diffUseCase.GetDiff(ctx, from, to)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCatalogDiffResponse(diff))
+}
+
+func (h *CatalogDiffHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidDiffRange):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_diff_range",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
+
+// parseTimeQuery reads an optional RFC 3339 query parameter; a missing one
+// is the zero time.
+func parseTimeQuery(c *gin.Context, param string) (time.Time, bool) {
+	value := c.Query(param)
+	if value == "" {
+		return time.Time{}, true
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_" + param,
+			Message: param + " must be an RFC 3339 time",
+		})
+		return time.Time{}, false
+	}
+	return t, true
+}
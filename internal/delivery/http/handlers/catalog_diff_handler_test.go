@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCatalogDiffUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCatalogDiffUseCase) GetDiff(ctx context.Context, from, to time.Time) (*domain.CatalogDiff, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogDiff), args.Error(1)
+}
+
+func setupCatalogDiffTestRouter(handler *CatalogDiffHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/v1/products/diff", handler.GetDiff)
+	return r
+}
+
+func TestCatalogDiffHandler_GetDiff(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		query      string
+		mockFn     func(*MockCatalogDiffUseCase)
+		wantStatus int
+	}{
+		{
+			name:  "diff",
+			query: "?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z",
+			mockFn: func(m *MockCatalogDiffUseCase) {
+				m.On("GetDiff", mock.Anything, from, to).Return(&domain.CatalogDiff{
+					From:    from,
+					To:      to,
+					Created: []*domain.Product{{ID: 1, StoreID: 1, Name: "Added"}},
+					Changed: []domain.ChangedProduct{{Product: &domain.Product{ID: 2, StoreID: 1, Name: "Kept"}, Fields: []string{"price"}}},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "to defaults to now",
+			query: "?from=2026-03-01T00:00:00Z",
+			mockFn: func(m *MockCatalogDiffUseCase) {
+				m.On("GetDiff", mock.Anything, from, time.Time{}).Return(&domain.CatalogDiff{From: from, To: to}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unparseable from",
+			query:      "?from=yesterday",
+			mockFn:     func(m *MockCatalogDiffUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid range",
+			query: "?from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z",
+			mockFn: func(m *MockCatalogDiffUseCase) {
+				m.On("GetDiff", mock.Anything, to, from).Return(nil, domain.ErrInvalidDiffRange)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := new(MockCatalogDiffUseCase)
+			tt.mockFn(mockUseCase)
+
+			router := setupCatalogDiffTestRouter(NewCatalogDiffHandler(mockUseCase))
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products/diff"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCatalogDiffHandler_GetDiff_Response(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	mockUseCase := new(MockCatalogDiffUseCase)
+	mockUseCase.On("GetDiff", mock.Anything, from, to).Return(&domain.CatalogDiff{
+		From:    from,
+		To:      to,
+		Deleted: []*domain.Product{{ID: 3, StoreID: 1, Name: "Removed"}},
+	}, nil)
+
+	router := setupCatalogDiffTestRouter(NewCatalogDiffHandler(mockUseCase))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/diff?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response dto.CatalogDiffResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Empty(t, response.Created)
+	assert.Equal(t, []dto.ProductDiffEntry{{ID: 3, StoreID: 1, Name: "Removed"}}, response.Deleted)
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CatalogSnapshotHandler serves a store's catalog snapshots and restores
+// under /api/v1/stores/:id. Every route takes ownership of the store.
+type CatalogSnapshotHandler struct {
+	snapshotUseCase usecase.CatalogSnapshotUseCaseInterface
+	// timeout bounds taking a snapshot and previewing a restore, which
+	// read the whole catalog.
+	timeout time.Duration
+}
+
+func NewCatalogSnapshotHandler(snapshotUseCase usecase.CatalogSnapshotUseCaseInterface, timeout time.Duration) *CatalogSnapshotHandler {
+	return &CatalogSnapshotHandler{
+		snapshotUseCase: snapshotUseCase,
+		timeout:         timeout,
+	}
+}
+
+func (h *CatalogSnapshotHandler) CreateSnapshot(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "id", "Store")
+	if !ok || !requireStoreOwner(c, storeID) {
+		return
+	}
+
+	snapshot, err := h.snapshotUseCase.CreateSnapshot(ctx, storeID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToCatalogSnapshotResponse(snapshot))
+}
+
+func (h *CatalogSnapshotHandler) GetSnapshots(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "id", "Store")
+	if !ok || !requireStoreOwner(c, storeID) {
+		return
+	}
+
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	snapshots, err := h.snapshotUseCase.GetSnapshots(ctx, storeID, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToCatalogSnapshotListResponse(snapshots, page.Limit, page.Offset)
+	writeList(c, response, response.Snapshots, page)
+}
+
+func (h *CatalogSnapshotHandler) GetSnapshot(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, snapshotID, ok := h.parseSnapshotParams(c)
+	if !ok {
+		return
+	}
+
+	snapshot, err := h.snapshotUseCase.GetSnapshot(ctx, storeID, snapshotID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCatalogSnapshotResponse(snapshot))
+}
+
+// PreviewRestore returns the diff restoring the snapshot would apply to
+// the catalog as it is now.
+func (h *CatalogSnapshotHandler) PreviewRestore(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+	defer cancel()
+
+	storeID, snapshotID, ok := h.parseSnapshotParams(c)
+	if !ok {
+		return
+	}
+
+	diff, err := h.snapshotUseCase.PreviewRestore(ctx, storeID, snapshotID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCatalogDiffResponse(diff))
+}
+
+// StartRestore accepts a restore of the snapshot, which runs in the
+// background; poll GetRestore for its outcome.
+func (h *CatalogSnapshotHandler) StartRestore(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, snapshotID, ok := h.parseSnapshotParams(c)
+	if !ok {
+		return
+	}
+
+	restore, err := h.snapshotUseCase.StartRestore(ctx, storeID, snapshotID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.ToCatalogRestoreResponse(restore))
+}
+
+func (h *CatalogSnapshotHandler) GetRestore(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "id", "Store")
+	if !ok || !requireStoreOwner(c, storeID) {
+		return
+	}
+	restoreID, ok := parseIDParam(c, "restore_id", "Restore")
+	if !ok {
+		return
+	}
+
+	restore, err := h.snapshotUseCase.GetRestore(ctx, storeID, restoreID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCatalogRestoreResponse(restore))
+}
+
+func (h *CatalogSnapshotHandler) parseSnapshotParams(c *gin.Context) (int64, int64, bool) {
+	storeID, ok := parseIDParam(c, "id", "Store")
+	if !ok || !requireStoreOwner(c, storeID) {
+		return 0, 0, false
+	}
+	snapshotID, ok := parseIDParam(c, "snapshot_id", "Snapshot")
+	if !ok {
+		return 0, 0, false
+	}
+	return storeID, snapshotID, true
+}
+
+func (h *CatalogSnapshotHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrStoreNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "store_not_found",
+			Message: "Store not found",
+		})
+	case errors.Is(err, domain.ErrSnapshotNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "snapshot_not_found",
+			Message: "Catalog snapshot not found",
+		})
+	case errors.Is(err, domain.ErrRestoreNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "restore_not_found",
+			Message: "Catalog restore not found",
+		})
+	case errors.Is(err, domain.ErrRestoreInProgress):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "restore_in_progress",
+			Message: "A restore of this store is already in progress",
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCatalogSnapshotUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCatalogSnapshotUseCase) CreateSnapshot(ctx context.Context, storeID int64) (*domain.CatalogSnapshot, error) {
+	args := m.Called(ctx, storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogSnapshot), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotUseCase) GetSnapshot(ctx context.Context, storeID, id int64) (*domain.CatalogSnapshot, error) {
+	args := m.Called(ctx, storeID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogSnapshot), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotUseCase) GetSnapshots(ctx context.Context, storeID int64, limit, offset int) ([]*domain.CatalogSnapshot, error) {
+	args := m.Called(ctx, storeID, limit, offset)
+	return args.Get(0).([]*domain.CatalogSnapshot), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotUseCase) PreviewRestore(ctx context.Context, storeID, snapshotID int64) (*domain.CatalogDiff, error) {
+	args := m.Called(ctx, storeID, snapshotID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogDiff), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotUseCase) StartRestore(ctx context.Context, storeID, snapshotID int64) (*domain.CatalogRestore, error) {
+	args := m.Called(ctx, storeID, snapshotID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogRestore), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotUseCase) GetRestore(ctx context.Context, storeID, id int64) (*domain.CatalogRestore, error) {
+	args := m.Called(ctx, storeID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogRestore), args.Error(1)
+}
+
+func setupCatalogSnapshotTestRouter(handler *CatalogSnapshotHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	snapshots := r.Group("/api/v1/stores/:id/snapshots")
+	{
+		snapshots.POST("", handler.CreateSnapshot)
+		snapshots.GET("", handler.GetSnapshots)
+		snapshots.GET("/:snapshot_id", handler.GetSnapshot)
+		snapshots.GET("/:snapshot_id/preview", handler.PreviewRestore)
+		snapshots.POST("/:snapshot_id/restores", handler.StartRestore)
+	}
+	r.GET("/api/v1/stores/:id/restores/:restore_id", handler.GetRestore)
+
+	return r
+}
+
+func TestCatalogSnapshotHandler_CreateSnapshot(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		apiKey       string
+		mockFn       func(*MockCatalogSnapshotUseCase)
+		expectedCode int
+	}{
+		{
+			name:   "owner takes a snapshot",
+			path:   "/api/v1/stores/3/snapshots",
+			apiKey: testAPIKey,
+			mockFn: func(m *MockCatalogSnapshotUseCase) {
+				m.On("CreateSnapshot", mock.Anything, int64(3)).Return(&domain.CatalogSnapshot{ID: 4, StoreID: 3, Products: 12}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "anonymous",
+			path:         "/api/v1/stores/3/snapshots",
+			mockFn:       func(m *MockCatalogSnapshotUseCase) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "another store",
+			path:         "/api/v1/stores/4/snapshots",
+			apiKey:       testAPIKey,
+			mockFn:       func(m *MockCatalogSnapshotUseCase) {},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:   "unknown store",
+			path:   "/api/v1/stores/5/snapshots",
+			apiKey: testAPIKey,
+			mockFn: func(m *MockCatalogSnapshotUseCase) {
+				m.On("CreateSnapshot", mock.Anything, int64(5)).Return(nil, domain.ErrStoreNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockCatalogSnapshotUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupCatalogSnapshotTestRouter(NewCatalogSnapshotHandler(mockUseCase, time.Minute))
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCatalogSnapshotHandler_PreviewRestore(t *testing.T) {
+	mockUseCase := &MockCatalogSnapshotUseCase{}
+	mockUseCase.On("PreviewRestore", mock.Anything, int64(3), int64(4)).Return(&domain.CatalogDiff{
+		Deleted: []*domain.Product{{ID: 9, StoreID: 3, Name: "Rye"}},
+	}, nil)
+	router := setupCatalogSnapshotTestRouter(NewCatalogSnapshotHandler(mockUseCase, time.Minute))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/stores/3/snapshots/4/preview", nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response dto.CatalogDiffResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Deleted, 1)
+	assert.Equal(t, int64(9), response.Deleted[0].ID)
+}
+
+func TestCatalogSnapshotHandler_StartRestore(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockFn       func(*MockCatalogSnapshotUseCase)
+		expectedCode int
+	}{
+		{
+			name: "accepted",
+			mockFn: func(m *MockCatalogSnapshotUseCase) {
+				m.On("StartRestore", mock.Anything, int64(3), int64(4)).Return(
+					&domain.CatalogRestore{ID: 8, StoreID: 3, SnapshotID: 4, Status: domain.CatalogRestoreStatusPending}, nil)
+			},
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name: "unknown snapshot",
+			mockFn: func(m *MockCatalogSnapshotUseCase) {
+				m.On("StartRestore", mock.Anything, int64(3), int64(4)).Return(nil, domain.ErrSnapshotNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "already restoring",
+			mockFn: func(m *MockCatalogSnapshotUseCase) {
+				m.On("StartRestore", mock.Anything, int64(3), int64(4)).Return(nil, domain.ErrRestoreInProgress)
+			},
+			expectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockCatalogSnapshotUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupCatalogSnapshotTestRouter(NewCatalogSnapshotHandler(mockUseCase, time.Minute))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/3/snapshots/4/restores", nil)
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryHandler serves /api/v1/categories and the categories of
+// products. Anyone may read categories; only admins change the tree, while
+// putting a product in categories is open to whoever may write products.
+// Either way, a product whose categories change gets a new version and an
+// updated event, which purges it from caches and the CDN.
+type CategoryHandler struct {
+	categoryUseCase usecase.CategoryUseCaseInterface
+}
+
+func NewCategoryHandler(categoryUseCase usecase.CategoryUseCaseInterface) *CategoryHandler {
+	return &CategoryHandler{
+		categoryUseCase: categoryUseCase,
+	}
+}
+
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	if !requireCategoryAdmin(c) {
+		return
+	}
+
+	var req dto.SaveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind create category request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	category, err := h.categoryUseCase.CreateCategory(ctx, req.ToDomain())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToCategoryResponse(category))
+}
+
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Category")
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryUseCase.GetCategory(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCategoryResponse(category))
+}
+
+func (h *CategoryHandler) GetCategories(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	categories, err := h.categoryUseCase.GetCategories(ctx, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToCategoryListResponse(categories, page.Limit, page.Offset)
+	writeList(c, response, response.Categories, page)
+}
+
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Category")
+	if !ok || !requireCategoryAdmin(c) {
+		return
+	}
+
+	var req dto.SaveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind update category request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	category, err := h.categoryUseCase.UpdateCategory(ctx, id, req.ToDomain())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToCategoryResponse(category))
+}
+
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Category")
+	if !ok || !requireCategoryAdmin(c) {
+		return
+	}
+
+	if err := h.categoryUseCase.DeleteCategory(ctx, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *CategoryHandler) GetProductCategories(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Product")
+	if !ok {
+		return
+	}
+
+	categories, err := h.categoryUseCase.GetProductCategories(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToProductCategoryListResponse(categories))
+}
+
+func (h *CategoryHandler) SetProductCategories(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Product")
+	if !ok {
+		return
+	}
+
+	var req dto.SetProductCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind set product categories request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	categories, err := h.categoryUseCase.SetProductCategories(ctx, id, req.CategoryIDs)
+	if errors.Is(err, domain.ErrCategoryNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error:   "category_not_found",
+			Message: "A category in category_ids does not exist",
+		})
+		return
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToProductCategoryListResponse(categories))
+}
+
+// requireCategoryAdmin lets admins through. Anonymous callers get 401 and
+// everyone else 403.
+func requireCategoryAdmin(c *gin.Context) bool {
+	if !requireAuthenticated(c) {
+		return false
+	}
+	if middleware.IsAdmin(c) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, dto.ErrorResponse{
+		Error:   "admin_required",
+		Message: "Only admins may manage categories",
+	})
+	return false
+}
+
+func (h *CategoryHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "category_not_found",
+			Message: "Category not found",
+		})
+	case errors.Is(err, domain.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "product_not_found",
+			Message: "Product not found",
+		})
+	case errors.Is(err, domain.ErrInvalidCategory):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_category",
+			Message: err.Error(),
+			Fields:  dto.FieldErrors(err),
+		})
+	case errors.Is(err, domain.ErrInvalidProduct):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_product",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrParentCategoryNotFound):
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error:   "parent_category_not_found",
+			Message: "The category's parent does not exist",
+		})
+	case errors.Is(err, domain.ErrDuplicateCategory):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "duplicate_category",
+			Message: "A category with this name already exists under the parent",
+		})
+	case errors.Is(err, domain.ErrCategoryCycle):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "category_cycle",
+			Message: "A category cannot be moved under itself or one of its subcategories",
+		})
+	case errors.Is(err, domain.ErrCategoryHasChildren):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "category_has_children",
+			Message: "The category still has subcategories; delete or move them first",
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
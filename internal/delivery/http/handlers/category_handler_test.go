@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCategoryUseCase struct {
+	mock.Mock
+}
+
+func (m *MockCategoryUseCase) CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) GetCategory(ctx context.Context, id int64) (*domain.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) GetCategories(ctx context.Context, limit, offset int) ([]*domain.Category, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) UpdateCategory(ctx context.Context, id int64, category *domain.Category) (*domain.Category, error) {
+	args := m.Called(ctx, id, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) DeleteCategory(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryUseCase) GetProductCategories(ctx context.Context, productID int64) ([]*domain.Category, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Category), args.Error(1)
+}
+
+func (m *MockCategoryUseCase) SetProductCategories(ctx context.Context, productID int64, categoryIDs []int64) ([]*domain.Category, error) {
+	args := m.Called(ctx, productID, categoryIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Category), args.Error(1)
+}
+
+func setupCategoryTestRouter(handler *CategoryHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	categories := r.Group("/api/v1/categories")
+	{
+		categories.POST("", handler.CreateCategory)
+		categories.GET("", handler.GetCategories)
+		categories.GET("/:id", handler.GetCategory)
+		categories.PUT("/:id", handler.UpdateCategory)
+		categories.DELETE("/:id", handler.DeleteCategory)
+	}
+	r.GET("/api/v1/products/:id/categories", handler.GetProductCategories)
+	r.PUT("/api/v1/products/:id/categories", handler.SetProductCategories)
+
+	return r
+}
+
+func TestCategoryHandler_ManageRequiresAdmin(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		anonymous    bool
+		expectedCode int
+	}{
+		{name: "create as store owner", method: http.MethodPost, target: "/api/v1/categories", expectedCode: http.StatusForbidden},
+		{name: "create anonymously", method: http.MethodPost, target: "/api/v1/categories", anonymous: true, expectedCode: http.StatusUnauthorized},
+		{name: "update as store owner", method: http.MethodPut, target: "/api/v1/categories/1", expectedCode: http.StatusForbidden},
+		{name: "delete anonymously", method: http.MethodDelete, target: "/api/v1/categories/1", anonymous: true, expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockCategoryUseCase{}
+			router := setupCategoryTestRouter(NewCategoryHandler(mockUseCase))
+
+			req := httptest.NewRequest(tt.method, tt.target, bytes.NewBufferString(`{"name":"Drinks"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if !tt.anonymous {
+				req.Header.Set("X-API-Key", testAPIKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryHandler_GetCategory(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		mockFn       func(*MockCategoryUseCase)
+		expectedCode int
+	}{
+		{
+			name: "found",
+			id:   "2",
+			mockFn: func(m *MockCategoryUseCase) {
+				m.On("GetCategory", mock.Anything, int64(2)).Return(&domain.Category{ID: 2, ParentID: sql.NullInt64{Int64: 1, Valid: true}, Name: "Tea"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "not found",
+			id:   "9",
+			mockFn: func(m *MockCategoryUseCase) {
+				m.On("GetCategory", mock.Anything, int64(9)).Return(nil, domain.ErrCategoryNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid ID",
+			id:           "abc",
+			mockFn:       func(m *MockCategoryUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockCategoryUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupCategoryTestRouter(NewCategoryHandler(mockUseCase))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/categories/"+tt.id, nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				var resp dto.CategoryResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				require.NotNil(t, resp.ParentID)
+				assert.Equal(t, int64(1), *resp.ParentID)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryHandler_SetProductCategories(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		mockFn       func(*MockCategoryUseCase)
+		expectedCode int
+	}{
+		{
+			name: "replaced",
+			body: `{"category_ids":[2,3]}`,
+			mockFn: func(m *MockCategoryUseCase) {
+				m.On("SetProductCategories", mock.Anything, int64(7), []int64{2, 3}).Return([]*domain.Category{{ID: 2}, {ID: 3}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "unknown category",
+			body: `{"category_ids":[99]}`,
+			mockFn: func(m *MockCategoryUseCase) {
+				m.On("SetProductCategories", mock.Anything, int64(7), []int64{99}).Return(nil, domain.ErrCategoryNotFound)
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "unknown product",
+			body: `{"category_ids":[]}`,
+			mockFn: func(m *MockCategoryUseCase) {
+				m.On("SetProductCategories", mock.Anything, int64(7), []int64{}).Return(nil, domain.ErrProductNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "missing category_ids",
+			body:         `{}`,
+			mockFn:       func(m *MockCategoryUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid category ID",
+			body:         `{"category_ids":[0]}`,
+			mockFn:       func(m *MockCategoryUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockCategoryUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupCategoryTestRouter(NewCategoryHandler(mockUseCase))
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/products/7/categories", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
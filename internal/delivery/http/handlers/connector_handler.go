@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConnectorHandler struct {
+	connectorUseCase usecase.ConnectorUseCaseInterface
+	syncTimeout      time.Duration
+}
+
+func NewConnectorHandler(connectorUseCase usecase.ConnectorUseCaseInterface, syncTimeout time.Duration) *ConnectorHandler {
+	return &ConnectorHandler{
+		connectorUseCase: connectorUseCase,
+		syncTimeout:      syncTimeout,
+	}
+}
+
+func (h *ConnectorHandler) CreateConnector(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req dto.CreateConnectorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind create connector request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	connector, err := h.connectorUseCase.CreateConnector(ctx, req.ToDomain(), []byte(req.Credentials))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToConnectorResponse(connector))
+}
+
+func (h *ConnectorHandler) GetConnector(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Connector")
+	if !ok {
+		return
+	}
+
+	connector, err := h.connectorUseCase.GetConnector(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToConnectorResponse(connector))
+}
+
+func (h *ConnectorHandler) GetConnectors(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	connectors, err := h.connectorUseCase.GetConnectors(ctx, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToConnectorListResponse(connectors, page.Limit, page.Offset)
+	writeList(c, response, response.Connectors, page)
+}
+
+func (h *ConnectorHandler) DeleteConnector(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Connector")
+	if !ok {
+		return
+	}
+
+	if err := h.connectorUseCase.DeleteConnector(ctx, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *ConnectorHandler) SyncConnector(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.syncTimeout)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Connector")
+	if !ok {
+		return
+	}
+
+	run, err := h.connectorUseCase.SyncConnector(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToConnectorSyncResponse(run))
+}
+
+func (h *ConnectorHandler) GetConnectorSyncs(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Connector")
+	if !ok {
+		return
+	}
+
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	runs, err := h.connectorUseCase.GetConnectorSyncs(ctx, id, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToConnectorSyncListResponse(runs, page.Limit, page.Offset)
+	writeList(c, response, response.Syncs, page)
+}
+
+func (h *ConnectorHandler) GetConnectorSync(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Connector")
+	if !ok {
+		return
+	}
+
+	syncID, ok := parseIDParam(c, "sync_id", "Sync")
+	if !ok {
+		return
+	}
+
+	run, err := h.connectorUseCase.GetConnectorSync(ctx, id, syncID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToConnectorSyncResponse(run))
+}
+
+func (h *ConnectorHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrConnectorNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "connector_not_found",
+			Message: "Connector not found",
+		})
+	case errors.Is(err, domain.ErrConnectorSyncNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "connector_sync_not_found",
+			Message: "Connector sync not found",
+		})
+	case errors.Is(err, domain.ErrInvalidConnector), errors.Is(err, domain.ErrUnsupportedConnector):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_connector",
+			Message: err.Error(),
+			Fields:  dto.FieldErrors(err),
+		})
+	case errors.Is(err, domain.ErrConnectorSyncInProgress):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "connector_sync_in_progress",
+			Message: "A sync for this connector is already in progress",
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockConnectorUseCase struct {
+	mock.Mock
+}
+
+func (m *MockConnectorUseCase) CreateConnector(ctx context.Context, connector *domain.Connector, credentials []byte) (*domain.Connector, error) {
+	args := m.Called(ctx, connector, credentials)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Connector), args.Error(1)
+}
+
+func (m *MockConnectorUseCase) GetConnector(ctx context.Context, id int64) (*domain.Connector, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Connector), args.Error(1)
+}
+
+func (m *MockConnectorUseCase) GetConnectors(ctx context.Context, limit, offset int) ([]*domain.Connector, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*domain.Connector), args.Error(1)
+}
+
+func (m *MockConnectorUseCase) DeleteConnector(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockConnectorUseCase) SyncConnector(ctx context.Context, id int64) (*domain.ConnectorSync, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ConnectorSync), args.Error(1)
+}
+
+func (m *MockConnectorUseCase) GetConnectorSync(ctx context.Context, connectorID, syncID int64) (*domain.ConnectorSync, error) {
+	args := m.Called(ctx, connectorID, syncID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ConnectorSync), args.Error(1)
+}
+
+func (m *MockConnectorUseCase) GetConnectorSyncs(ctx context.Context, connectorID int64, limit, offset int) ([]*domain.ConnectorSync, error) {
+	args := m.Called(ctx, connectorID, limit, offset)
+	return args.Get(0).([]*domain.ConnectorSync), args.Error(1)
+}
+
+func setupConnectorTestRouter(handler *ConnectorHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	connectors := r.Group("/admin/connectors")
+	{
+		connectors.POST("", handler.CreateConnector)
+		connectors.GET("/:id", handler.GetConnector)
+		connectors.POST("/:id/syncs", handler.SyncConnector)
+		connectors.GET("/:id/syncs/:sync_id", handler.GetConnectorSync)
+	}
+
+	return r
+}
+
+func TestConnectorHandler_CreateConnector(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		requestBody  interface{}
+		mockFn       func(*MockConnectorUseCase)
+		expectedCode int
+	}{
+		{
+			name: "successful creation",
+			requestBody: map[string]interface{}{
+				"store_id":    1,
+				"kind":        "shopify",
+				"settings":    map[string]string{"shop_domain": "demo.myshopify.com"},
+				"credentials": "shpat_secret",
+			},
+			mockFn: func(m *MockConnectorUseCase) {
+				m.On("CreateConnector", mock.Anything, mock.Anything, []byte("shpat_secret")).Return(
+					&domain.Connector{ID: 1, StoreID: 1, Kind: "shopify"}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "missing credentials",
+			requestBody: map[string]interface{}{
+				"store_id": 1,
+				"kind":     "shopify",
+			},
+			mockFn:       func(m *MockConnectorUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "unsupported kind",
+			requestBody: map[string]interface{}{
+				"store_id":    1,
+				"kind":        "sap",
+				"credentials": "x",
+			},
+			mockFn: func(m *MockConnectorUseCase) {
+				m.On("CreateConnector", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrUnsupportedConnector)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockConnectorUseCase{}
+			tt.mockFn(mockUseCase)
+
+			handler := NewConnectorHandler(mockUseCase, time.Minute)
+			router := setupConnectorTestRouter(handler)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/admin/connectors", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.NotContains(t, w.Body.String(), "shpat_secret")
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConnectorHandler_SyncConnector(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		id           string
+		mockFn       func(*MockConnectorUseCase)
+		expectedCode int
+	}{
+		{
+			name: "successful sync",
+			id:   "1",
+			mockFn: func(m *MockConnectorUseCase) {
+				m.On("SyncConnector", mock.Anything, int64(1)).Return(
+					&domain.ConnectorSync{ID: 3, ConnectorID: 1, Status: domain.ConnectorSyncStatusSucceeded}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "connector not found",
+			id:   "2",
+			mockFn: func(m *MockConnectorUseCase) {
+				m.On("SyncConnector", mock.Anything, int64(2)).Return(nil, domain.ErrConnectorNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "sync in progress",
+			id:   "1",
+			mockFn: func(m *MockConnectorUseCase) {
+				m.On("SyncConnector", mock.Anything, int64(1)).Return(nil, domain.ErrConnectorSyncInProgress)
+			},
+			expectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockConnectorUseCase{}
+			tt.mockFn(mockUseCase)
+
+			handler := NewConnectorHandler(mockUseCase, time.Minute)
+			router := setupConnectorTestRouter(handler)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/connectors/"+tt.id+"/syncs", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend-context-engineering-template/internal/dbhealth"
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/indexadvisor"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DBHealthHandler struct {
+	inspector *dbhealth.Inspector
+	advisor   *indexadvisor.Advisor
+}
+
+func NewDBHealthHandler(inspector *dbhealth.Inspector, advisor *indexadvisor.Advisor) *DBHealthHandler {
+	return &DBHealthHandler{
+		inspector: inspector,
+		advisor:   advisor,
+	}
+}
+
+// GetReport reports table bloat, unused indexes and sequential-scan-heavy
+// tables with a recommendation for each.
+func (h *DBHealthHandler) GetReport(c *gin.Context) {
+	report, err := h.inspector.Report(c.Request.Context())
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Failed to build database health report")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToDBHealthResponse(report))
+}
+
+// GetIndexAdvice suggests indexes for the list query shapes this instance
+// has served, and flags indexes none of them need.
+func (h *DBHealthHandler) GetIndexAdvice(c *gin.Context) {
+	report, err := h.advisor.Report(c.Request.Context())
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Failed to build index advice")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToIndexAdviceReportResponse(report))
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/internal/dbhealth"
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/indexadvisor"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubDBHealthStore struct {
+	err error
+}
+
+func (s *stubDBHealthStore) TableStats(ctx context.Context) ([]domain.TableStats, error) {
+	return []domain.TableStats{{Table: "products", LiveTuples: 5000, DeadTuples: 4000}}, s.err
+}
+
+func (s *stubDBHealthStore) IndexStats(ctx context.Context) ([]domain.IndexStats, error) {
+	return []domain.IndexStats{{Table: "products", Index: "products_pkey", Columns: []string{"id"}, Unique: true}}, s.err
+}
+
+func (s *stubDBHealthStore) StatsSince(ctx context.Context) (sql.NullTime, error) {
+	return sql.NullTime{}, nil
+}
+
+func setupDBHealthTestRouter(store dbhealth.Store) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	tracker := indexadvisor.NewTracker()
+	tracker.Record(domain.AccessPattern{Table: "products", Equals: []string{"store_id"}, Sort: []string{"id"}})
+	handler := NewDBHealthHandler(dbhealth.NewInspector(store, dbhealth.DefaultThresholds, clock.Real()),
+		indexadvisor.NewAdvisor(tracker, store, 1, clock.Real()))
+	r.GET("/admin/db/health", handler.GetReport)
+	r.GET("/admin/db/index-advice", handler.GetIndexAdvice)
+
+	return r
+}
+
+func TestDBHealthHandler_GetReport(t *testing.T) {
+	router := setupDBHealthTestRouter(&stubDBHealthStore{})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/db/health", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.DBHealthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Findings, 1)
+	assert.Equal(t, domain.DBHealthCheckDeadTuples, response.Findings[0].Check)
+	assert.Len(t, response.Tables, 1)
+	assert.Len(t, response.Indexes, 1)
+	assert.Empty(t, response.StatsSince)
+}
+
+func TestDBHealthHandler_GetReport_StoreError(t *testing.T) {
+	router := setupDBHealthTestRouter(&stubDBHealthStore{err: errors.New("permission denied")})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/db/health", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestDBHealthHandler_GetIndexAdvice(t *testing.T) {
+	router := setupDBHealthTestRouter(&stubDBHealthStore{})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/db/index-advice", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.IndexAdviceReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Advice, 1)
+	assert.Equal(t, domain.IndexAdviceMissing, response.Advice[0].Kind)
+	assert.Equal(t, []string{"store_id", "id"}, response.Advice[0].Columns)
+	assert.Equal(t, []dto.AccessPatternResponse{{Pattern: "products WHERE store_id = ? ORDER BY id", Uses: 1}}, response.Patterns)
+}
+
+func TestDBHealthHandler_GetIndexAdvice_StoreError(t *testing.T) {
+	router := setupDBHealthTestRouter(&stubDBHealthStore{err: errors.New("permission denied")})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/db/index-advice", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
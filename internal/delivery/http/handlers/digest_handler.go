@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DigestHandler struct {
+	digestUseCase usecase.DigestUseCaseInterface
+}
+
+func NewDigestHandler(digestUseCase usecase.DigestUseCaseInterface) *DigestHandler {
+	return &DigestHandler{
+		digestUseCase: digestUseCase,
+	}
+}
+
+func (h *DigestHandler) GetSettings(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "store_id", "Store")
+	if !ok {
+		return
+	}
+	middleware.SetStoreID(c, storeID)
+	if !requireStoreOwner(c, storeID) {
+		return
+	}
+
+	settings, err := h.digestUseCase.GetSettings(ctx, storeID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToDigestSettingsResponse(settings))
+}
+
+func (h *DigestHandler) SaveSettings(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "store_id", "Store")
+	if !ok {
+		return
+	}
+	middleware.SetStoreID(c, storeID)
+	if !requireStoreOwner(c, storeID) {
+		return
+	}
+
+	var req dto.SaveDigestSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind save digest settings request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	settings, err := h.digestUseCase.SaveSettings(ctx, req.ToDomain(storeID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToDigestSettingsResponse(settings))
+}
+
+func (h *DigestHandler) DeleteSettings(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "store_id", "Store")
+	if !ok {
+		return
+	}
+	middleware.SetStoreID(c, storeID)
+	if !requireStoreOwner(c, storeID) {
+		return
+	}
+
+	if err := h.digestUseCase.DeleteSettings(ctx, storeID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *DigestHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrDigestSettingsNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "digest_settings_not_found",
+			Message: "Store is not subscribed to the digest",
+		})
+	case errors.Is(err, domain.ErrInvalidDigestSettings):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Fields:  dto.FieldErrors(err),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
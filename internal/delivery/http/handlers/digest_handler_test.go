@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockDigestUseCase struct {
+	mock.Mock
+}
+
+func (m *MockDigestUseCase) GetSettings(ctx context.Context, storeID int64) (*domain.DigestSettings, error) {
+	args := m.Called(ctx, storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DigestSettings), args.Error(1)
+}
+
+func (m *MockDigestUseCase) SaveSettings(ctx context.Context, settings *domain.DigestSettings) (*domain.DigestSettings, error) {
+	args := m.Called(ctx, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DigestSettings), args.Error(1)
+}
+
+func (m *MockDigestUseCase) DeleteSettings(ctx context.Context, storeID int64) error {
+	args := m.Called(ctx, storeID)
+	return args.Error(0)
+}
+
+func setupDigestTestRouter(handler *DigestHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	digests := r.Group("/api/v1/digest-settings")
+	{
+		digests.GET("/:store_id", handler.GetSettings)
+		digests.PUT("/:store_id", handler.SaveSettings)
+		digests.DELETE("/:store_id", handler.DeleteSettings)
+	}
+
+	return r
+}
+
+func digestRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", testAPIKey)
+	return req
+}
+
+func TestDigestHandler_GetSettings(t *testing.T) {
+	mockUseCase := &MockDigestUseCase{}
+	mockUseCase.On("GetSettings", mock.Anything, int64(3)).Return(&domain.DigestSettings{
+		StoreID: 3, Channel: domain.DigestChannelEmail, Target: "owner@example.com",
+		LastSentDay: sql.NullTime{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+	}, nil)
+	mockUseCase.On("GetSettings", mock.Anything, int64(5)).Return(nil, domain.ErrDigestSettingsNotFound)
+
+	router := setupDigestTestRouter(NewDigestHandler(mockUseCase))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, digestRequest(http.MethodGet, "/api/v1/digest-settings/3", ""))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.DigestSettingsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "2024-03-01", resp.LastSentDay)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, digestRequest(http.MethodGet, "/api/v1/digest-settings/5", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, digestRequest(http.MethodGet, "/api/v1/digest-settings/abc", ""))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestDigestHandler_SaveSettings(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		mockFn       func(*MockDigestUseCase)
+		expectedCode int
+	}{
+		{
+			name: "subscribe by email",
+			body: `{"channel":"email","target":"owner@example.com"}`,
+			mockFn: func(m *MockDigestUseCase) {
+				m.On("SaveSettings", mock.Anything, &domain.DigestSettings{StoreID: 3, Channel: "email", Target: "owner@example.com"}).
+					Return(&domain.DigestSettings{StoreID: 3, Channel: "email", Target: "owner@example.com"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "unknown channel",
+			body:         `{"channel":"sms","target":"+15550100"}`,
+			mockFn:       func(m *MockDigestUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid target",
+			body: `{"channel":"webhook","target":"hooks.example.com"}`,
+			mockFn: func(m *MockDigestUseCase) {
+				m.On("SaveSettings", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidDigestSettings)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockDigestUseCase{}
+			tt.mockFn(mockUseCase)
+
+			router := setupDigestTestRouter(NewDigestHandler(mockUseCase))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, digestRequest(http.MethodPut, "/api/v1/digest-settings/3", tt.body))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDigestHandler_DeleteSettings(t *testing.T) {
+	mockUseCase := &MockDigestUseCase{}
+	mockUseCase.On("DeleteSettings", mock.Anything, int64(3)).Return(nil)
+
+	router := setupDigestTestRouter(NewDigestHandler(mockUseCase))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, digestRequest(http.MethodDelete, "/api/v1/digest-settings/3", ""))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestDigestHandler_StoreNotOwned(t *testing.T) {
+	mockUseCase := &MockDigestUseCase{}
+	router := setupDigestTestRouter(NewDigestHandler(mockUseCase))
+
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, digestRequest(method, "/api/v1/digest-settings/4", `{"channel":"email","target":"owner@example.com"}`))
+		assert.Equal(t, http.StatusForbidden, w.Code, method)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/api/v1/digest-settings/3", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code, method)
+	}
+
+	mockUseCase.AssertNotCalled(t, "GetSettings", mock.Anything, mock.Anything)
+	mockUseCase.AssertNotCalled(t, "SaveSettings", mock.Anything, mock.Anything)
+	mockUseCase.AssertNotCalled(t, "DeleteSettings", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/events"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventSchemaHandler struct {
+	schemas *events.Registry
+}
+
+func NewEventSchemaHandler(schemas *events.Registry) *EventSchemaHandler {
+	return &EventSchemaHandler{
+		schemas: schemas,
+	}
+}
+
+// GetSchemas lists every version of every published event schema.
+func (h *EventSchemaHandler) GetSchemas(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.ToEventSchemaListResponse(h.schemas.Schemas()))
+}
+
+// GetSchema serves one schema document as is, so subscribers can point a
+// JSON Schema validator straight at it.
+func (h *EventSchemaHandler) GetSchema(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_version",
+			Message: "Schema version must be a valid number",
+		})
+		return
+	}
+
+	schema, ok := h.schemas.Schema(c.Param("type"), version)
+	if !ok {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "event_schema_not_found",
+			Message: "Event schema not found",
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/schema+json", schema.Schema)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/events"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupEventSchemaTestRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	schemas, err := events.NewRegistry()
+	require.NoError(t, err)
+	handler := NewEventSchemaHandler(schemas)
+	r.GET("/api/v1/event-schemas", handler.GetSchemas)
+	r.GET("/api/v1/event-schemas/:type/:version", handler.GetSchema)
+
+	return r
+}
+
+func TestEventSchemaHandler_GetSchemas(t *testing.T) {
+	router := setupEventSchemaTestRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/event-schemas", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.EventSchemaListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.NotEmpty(t, response.Schemas)
+	assert.Equal(t, "product.created", response.Schemas[0].Type)
+	assert.Equal(t, 1, response.Schemas[0].Version)
+	assert.False(t, response.Schemas[0].Current)
+	assert.Contains(t, string(response.Schemas[0].Schema), `"title":"product.created v1"`)
+}
+
+func TestEventSchemaHandler_GetSchema(t *testing.T) {
+	router := setupEventSchemaTestRouter(t)
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "found", path: "/api/v1/event-schemas/stock.changed/1", expectedCode: http.StatusOK, expectedBody: `"$id": "/api/v1/event-schemas/stock.changed/1"`},
+		{name: "unknown version", path: "/api/v1/event-schemas/stock.changed/9", expectedCode: http.StatusNotFound, expectedBody: "event_schema_not_found"},
+		{name: "unknown type", path: "/api/v1/event-schemas/order.created/1", expectedCode: http.StatusNotFound, expectedBody: "event_schema_not_found"},
+		{name: "invalid version", path: "/api/v1/event-schemas/stock.changed/v1", expectedCode: http.StatusBadRequest, expectedBody: "invalid_version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler struct {
+	feedUseCase usecase.FeedUseCaseInterface
+	runTimeout  time.Duration
+}
+
+func NewFeedHandler(feedUseCase usecase.FeedUseCaseInterface, runTimeout time.Duration) *FeedHandler {
+	return &FeedHandler{
+		feedUseCase: feedUseCase,
+		runTimeout:  runTimeout,
+	}
+}
+
+func (h *FeedHandler) CreateFeed(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req dto.CreateFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind create feed request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	feed, err := h.feedUseCase.RegisterFeed(ctx, req.ToDomain())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToFeedResponse(feed))
+}
+
+func (h *FeedHandler) GetFeed(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Feed")
+	if !ok {
+		return
+	}
+
+	feed, err := h.feedUseCase.GetFeed(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToFeedResponse(feed))
+}
+
+func (h *FeedHandler) GetFeeds(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	feeds, err := h.feedUseCase.GetFeeds(ctx, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToFeedListResponse(feeds, page.Limit, page.Offset)
+	writeList(c, response, response.Feeds, page)
+}
+
+func (h *FeedHandler) DeleteFeed(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Feed")
+	if !ok {
+		return
+	}
+
+	if err := h.feedUseCase.DeleteFeed(ctx, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// RunFeed triggers an immediate run outside the feed's schedule and returns
+// the run report once it has finished.
+func (h *FeedHandler) RunFeed(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.runTimeout)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Feed")
+	if !ok {
+		return
+	}
+
+	run, err := h.feedUseCase.RunFeed(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToFeedRunResponse(run))
+}
+
+func (h *FeedHandler) GetFeedRuns(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Feed")
+	if !ok {
+		return
+	}
+
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	runs, err := h.feedUseCase.GetFeedRuns(ctx, id, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToFeedRunListResponse(runs, page.Limit, page.Offset)
+	writeList(c, response, response.Runs, page)
+}
+
+func (h *FeedHandler) GetFeedRun(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Feed")
+	if !ok {
+		return
+	}
+
+	runID, ok := parseIDParam(c, "run_id", "Feed run")
+	if !ok {
+		return
+	}
+
+	run, err := h.feedUseCase.GetFeedRun(ctx, id, runID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToFeedRunResponse(run))
+}
+
+// GetFeedRunImages lists what became of the image URLs of the run's rows.
+func (h *FeedHandler) GetFeedRunImages(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Feed")
+	if !ok {
+		return
+	}
+
+	runID, ok := parseIDParam(c, "run_id", "Feed run")
+	if !ok {
+		return
+	}
+
+	images, err := h.feedUseCase.GetFeedRunImages(ctx, id, runID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToImageImportListResponse(images))
+}
+
+func (h *FeedHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrFeedNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "feed_not_found",
+			Message: "Feed not found",
+		})
+	case errors.Is(err, domain.ErrFeedRunNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "feed_run_not_found",
+			Message: "Feed run not found",
+		})
+	case errors.Is(err, domain.ErrInvalidFeed):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_feed",
+			Message: err.Error(),
+			Fields:  dto.FieldErrors(err),
+		})
+	case errors.Is(err, domain.ErrFeedRunInProgress):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "feed_run_in_progress",
+			Message: "A run for this feed is already in progress",
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockFeedUseCase struct {
+	mock.Mock
+}
+
+func (m *MockFeedUseCase) RegisterFeed(ctx context.Context, feed *domain.Feed) (*domain.Feed, error) {
+	args := m.Called(ctx, feed)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Feed), args.Error(1)
+}
+
+func (m *MockFeedUseCase) GetFeed(ctx context.Context, id int64) (*domain.Feed, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Feed), args.Error(1)
+}
+
+func (m *MockFeedUseCase) GetFeeds(ctx context.Context, limit, offset int) ([]*domain.Feed, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*domain.Feed), args.Error(1)
+}
+
+func (m *MockFeedUseCase) DeleteFeed(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockFeedUseCase) RunFeed(ctx context.Context, id int64) (*domain.FeedRun, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FeedRun), args.Error(1)
+}
+
+func (m *MockFeedUseCase) RunDueFeeds(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockFeedUseCase) GetFeedRun(ctx context.Context, feedID, runID int64) (*domain.FeedRun, error) {
+	args := m.Called(ctx, feedID, runID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FeedRun), args.Error(1)
+}
+
+func (m *MockFeedUseCase) GetFeedRuns(ctx context.Context, feedID int64, limit, offset int) ([]*domain.FeedRun, error) {
+	args := m.Called(ctx, feedID, limit, offset)
+	return args.Get(0).([]*domain.FeedRun), args.Error(1)
+}
+
+func (m *MockFeedUseCase) GetFeedRunImages(ctx context.Context, feedID, runID int64) ([]*domain.ImageImport, error) {
+	args := m.Called(ctx, feedID, runID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ImageImport), args.Error(1)
+}
+
+func setupFeedTestRouter(handler *FeedHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	feeds := r.Group("/api/v1/feeds")
+	{
+		feeds.POST("", handler.CreateFeed)
+		feeds.GET("/:id", handler.GetFeed)
+		feeds.GET("", handler.GetFeeds)
+		feeds.DELETE("/:id", handler.DeleteFeed)
+		feeds.POST("/:id/runs", handler.RunFeed)
+		feeds.GET("/:id/runs", handler.GetFeedRuns)
+		feeds.GET("/:id/runs/:run_id", handler.GetFeedRun)
+		feeds.GET("/:id/runs/:run_id/images", handler.GetFeedRunImages)
+	}
+
+	return r
+}
+
+func TestFeedHandler_CreateFeed(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		requestBody  interface{}
+		mockFn       func(*MockFeedUseCase)
+		expectedCode int
+	}{
+		{
+			name: "successful creation",
+			requestBody: map[string]interface{}{
+				"store_id":         1,
+				"url":              "https://supplier.example.com/feed.csv",
+				"format":           "csv",
+				"interval_minutes": 60,
+			},
+			mockFn: func(m *MockFeedUseCase) {
+				m.On("RegisterFeed", mock.Anything, mock.MatchedBy(func(f *domain.Feed) bool {
+					return f.IntervalSeconds == 3600 && f.Enabled
+				})).Return(&domain.Feed{ID: 1, StoreID: 1, Format: domain.FeedFormatCSV}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "unsupported format",
+			requestBody: map[string]interface{}{
+				"store_id":         1,
+				"url":              "https://supplier.example.com/feed.xml",
+				"format":           "xml",
+				"interval_minutes": 60,
+			},
+			mockFn:       func(m *MockFeedUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "duplicate detection",
+			requestBody: map[string]interface{}{
+				"store_id":         1,
+				"url":              "https://supplier.example.com/feed.csv",
+				"format":           "csv",
+				"interval_minutes": 60,
+				"match_by":         []string{"sku", "fuzzy_name"},
+				"on_duplicate":     "skip",
+			},
+			mockFn: func(m *MockFeedUseCase) {
+				m.On("RegisterFeed", mock.Anything, mock.MatchedBy(func(f *domain.Feed) bool {
+					return len(f.MatchBy) == 2 && f.MatchBy[1] == domain.DuplicateMatchFuzzyName &&
+						f.OnDuplicate == domain.DuplicateSkip
+				})).Return(&domain.Feed{ID: 1, StoreID: 1, Format: domain.FeedFormatCSV}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "unknown match rule",
+			requestBody: map[string]interface{}{
+				"store_id":         1,
+				"url":              "https://supplier.example.com/feed.csv",
+				"format":           "csv",
+				"interval_minutes": 60,
+				"match_by":         []string{"color"},
+			},
+			mockFn:       func(m *MockFeedUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "domain validation error",
+			requestBody: map[string]interface{}{
+				"store_id":         1,
+				"url":              "https://supplier.example.com/feed.csv",
+				"format":           "csv",
+				"interval_minutes": 60,
+			},
+			mockFn: func(m *MockFeedUseCase) {
+				m.On("RegisterFeed", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidFeed)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockFeedUseCase{}
+			tt.mockFn(mockUseCase)
+
+			handler := NewFeedHandler(mockUseCase, time.Minute)
+			router := setupFeedTestRouter(handler)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/feeds", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFeedHandler_RunFeed(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		id           string
+		mockFn       func(*MockFeedUseCase)
+		expectedCode int
+	}{
+		{
+			name: "successful run",
+			id:   "1",
+			mockFn: func(m *MockFeedUseCase) {
+				m.On("RunFeed", mock.Anything, int64(1)).Return(
+					&domain.FeedRun{ID: 5, FeedID: 1, Status: domain.FeedRunStatusSucceeded, Created: 2}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "invalid ID",
+			id:           "abc",
+			mockFn:       func(m *MockFeedUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "feed not found",
+			id:   "9",
+			mockFn: func(m *MockFeedUseCase) {
+				m.On("RunFeed", mock.Anything, int64(9)).Return(nil, domain.ErrFeedNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "run already in progress",
+			id:   "1",
+			mockFn: func(m *MockFeedUseCase) {
+				m.On("RunFeed", mock.Anything, int64(1)).Return(nil, domain.ErrFeedRunInProgress)
+			},
+			expectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockFeedUseCase{}
+			tt.mockFn(mockUseCase)
+
+			handler := NewFeedHandler(mockUseCase, time.Minute)
+			router := setupFeedTestRouter(handler)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/feeds/"+tt.id+"/runs", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFeedHandler_GetFeedRun(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		path         string
+		mockFn       func(*MockFeedUseCase)
+		expectedCode int
+	}{
+		{
+			name: "successful retrieval",
+			path: "/api/v1/feeds/1/runs/5",
+			mockFn: func(m *MockFeedUseCase) {
+				m.On("GetFeedRun", mock.Anything, int64(1), int64(5)).Return(
+					&domain.FeedRun{ID: 5, FeedID: 1, Status: domain.FeedRunStatusSucceeded}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "run not found",
+			path: "/api/v1/feeds/1/runs/6",
+			mockFn: func(m *MockFeedUseCase) {
+				m.On("GetFeedRun", mock.Anything, int64(1), int64(6)).Return(nil, domain.ErrFeedRunNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid run ID",
+			path:         "/api/v1/feeds/1/runs/x",
+			mockFn:       func(m *MockFeedUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockFeedUseCase{}
+			tt.mockFn(mockUseCase)
+
+			handler := NewFeedHandler(mockUseCase, time.Minute)
+			router := setupFeedTestRouter(handler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFeedHandler_GetFeedRunImages(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		path         string
+		mockFn       func(*MockFeedUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "successful retrieval",
+			path: "/api/v1/feeds/1/runs/5/images",
+			mockFn: func(m *MockFeedUseCase) {
+				m.On("GetFeedRunImages", mock.Anything, int64(1), int64(5)).Return([]*domain.ImageImport{
+					{ID: 1, RunID: 5, ProductID: 2, URL: "https://cdn.example.com/a.jpg", Status: domain.ImageImportPending},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"status":"pending"`,
+		},
+		{
+			name: "run not found",
+			path: "/api/v1/feeds/1/runs/6/images",
+			mockFn: func(m *MockFeedUseCase) {
+				m.On("GetFeedRunImages", mock.Anything, int64(1), int64(6)).Return(nil, domain.ErrFeedRunNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid run ID",
+			path:         "/api/v1/feeds/1/runs/x/images",
+			mockFn:       func(m *MockFeedUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockFeedUseCase{}
+			tt.mockFn(mockUseCase)
+
+			handler := NewFeedHandler(mockUseCase, time.Minute)
+			router := setupFeedTestRouter(handler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
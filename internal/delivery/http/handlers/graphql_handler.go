@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/graphql"
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLHandler serves the GraphQL API. Queries may be sent with GET or
+// POST, mutations only with POST, so that they go through the CSRF and
+// maintenance checks every other change does.
+type GraphQLHandler struct {
+	schema *graphql.Schema
+	// protect turns away anonymous callers, like ProtectProducts does on
+	// /api/v1/products.
+	protect bool
+}
+
+func NewGraphQLHandler(schema *graphql.Schema, protect bool) *GraphQLHandler {
+	return &GraphQLHandler{schema: schema, protect: protect}
+}
+
+// Serve executes one request. Errors in executing it are reported in the
+// response's errors with a 200, as GraphQL clients expect; only requests
+// that cannot be executed at all get another status.
+func (h *GraphQLHandler) Serve(c *gin.Context) {
+	if h.protect && !middleware.Authenticated(c) {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "authentication_required",
+			Message: "A bearer token, X-API-Key header or session cookie is required",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req graphql.Request
+	readOnly := c.Request.Method == http.MethodGet
+	if readOnly {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+					Error:   "validation_error",
+					Message: "variables must be a JSON object",
+				})
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "query is required",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.schema.Exec(ctx, graphQLCaller{c}, req, readOnly))
+}
+
+// graphQLCaller lets resolvers ask who made the request.
+type graphQLCaller struct {
+	c *gin.Context
+}
+
+func (caller graphQLCaller) OwnsStore(storeID int64) bool {
+	return middleware.OwnsStore(caller.c, storeID)
+}
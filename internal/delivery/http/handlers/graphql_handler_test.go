@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"backend-context-engineering-template/internal/delivery/graphql"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGraphQLTestRouter(products *MockProductUseCase, stores *MockStoreUseCase, protect bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	handler := NewGraphQLHandler(graphql.NewSchema(products, stores, clock.Real()), protect)
+	r.GET("/graphql", handler.Serve)
+	r.POST("/graphql", handler.Serve)
+	return r
+}
+
+type graphQLTestResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func TestGraphQLHandler_Post(t *testing.T) {
+	draft := &domain.Product{ID: 7, StoreID: 3, Name: "Rye Flour", Price: 4, Status: domain.ProductStatusDraft}
+	products := new(MockProductUseCase)
+	products.On("GetProduct", mock.Anything, int64(7)).Return(draft, nil)
+	router := setupGraphQLTestRouter(products, new(MockStoreUseCase), false)
+	body, _ := json.Marshal(map[string]any{
+		"query":     `query($id: ID!) { product(id: $id) { name } }`,
+		"variables": map[string]any{"id": "7"},
+	})
+
+	// The draft is shown to its store's owner only.
+	for _, owner := range []bool{false, true} {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if owner {
+			req.Header.Set("X-API-Key", testAPIKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp graphQLTestResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		if owner {
+			assert.Equal(t, map[string]any{"name": "Rye Flour"}, resp.Data["product"])
+		} else {
+			assert.Nil(t, resp.Data["product"])
+		}
+	}
+}
+
+func TestGraphQLHandler_GetRefusesMutations(t *testing.T) {
+	stores := new(MockStoreUseCase)
+	stores.On("GetStore", mock.Anything, int64(3)).Return(&domain.Store{ID: 3, Name: "Corner Shop"}, nil)
+	router := setupGraphQLTestRouter(new(MockProductUseCase), stores, false)
+
+	query := url.Values{"query": {`{ store(id: "3") { name } }`}}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp graphQLTestResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, map[string]any{"name": "Corner Shop"}, resp.Data["store"])
+
+	query = url.Values{"query": {`mutation { deleteStore(id: "3") }`}}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	resp = graphQLTestResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "method_not_allowed", resp.Errors[0].Extensions["code"])
+	stores.AssertNotCalled(t, "DeleteStore", mock.Anything, mock.Anything)
+}
+
+func TestGraphQLHandler_RejectsRequests(t *testing.T) {
+	tests := []struct {
+		name         string
+		protect      bool
+		request      func() *http.Request
+		expectedCode int
+	}{
+		{
+			name:    "anonymous while protected",
+			protect: true,
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/graphql?query=%7Bstores%7BpageInfo%7BhasNextPage%7D%7D%7D", nil)
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "missing query",
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(`{}`))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid variables",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/graphql?query=%7Bstores%7BpageInfo%7BhasNextPage%7D%7D%7D&variables=nope", nil)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupGraphQLTestRouter(new(MockProductUseCase), new(MockStoreUseCase), tt.protect)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, tt.request())
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+		})
+	}
+}
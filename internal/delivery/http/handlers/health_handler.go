@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/health"
+	"backend-context-engineering-template/pkg/lifecycle"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler serves the Kubernetes probes. Liveness only says the
+// process answers, so a database outage does not get every pod
+// restarted; readiness also pings the dependencies.
+type HealthHandler struct {
+	checker *health.Checker
+	manager *lifecycle.Manager
+}
+
+func NewHealthHandler(checker *health.Checker, manager *lifecycle.Manager) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+		manager: manager,
+	}
+}
+
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.LivenessResponse{
+		Status:  "ok",
+		Message: "Service is healthy",
+	})
+}
+
+// Ready answers 503 until warm-up succeeds, once draining starts and while
+// a required dependency is down. A down optional dependency answers 200
+// with status degraded.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.checker.Check(c.Request.Context())
+	for _, result := range report.Checks {
+		if result.Err != nil {
+			logger.FromContext(c.Request.Context()).WithError(result.Err).WithField("dependency", result.Name).Warn("Dependency health check failed")
+		}
+	}
+
+	state := "ready"
+	switch {
+	case h.manager.Draining():
+		state = "draining"
+	case !h.manager.Ready():
+		state = "warming_up"
+	}
+
+	code, status := http.StatusOK, "ready"
+	switch {
+	case state != "ready" || report.Status == health.StatusDown:
+		code, status = http.StatusServiceUnavailable, "not_ready"
+	case report.Status == health.StatusDegraded:
+		status = "degraded"
+	}
+
+	c.JSON(code, dto.DependencyReadinessResponse{
+		Status:       status,
+		Lifecycle:    state,
+		InFlight:     h.manager.InFlight(),
+		Dependencies: dto.ToDependencyResponses(report),
+	})
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/health"
+	"backend-context-engineering-template/pkg/lifecycle"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupHealthTestRouter(handler *HealthHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.GET("/healthz", handler.Live)
+	r.GET("/readyz", handler.Ready)
+
+	return r
+}
+
+func TestHealthHandler_Live(t *testing.T) {
+	failing := health.Check{Name: "postgres", Ping: func(ctx context.Context) error { return errors.New("connection refused") }}
+	handler := NewHealthHandler(health.NewChecker(time.Second, clock.Real(), failing), lifecycle.New())
+
+	w := httptest.NewRecorder()
+	setupHealthTestRouter(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code, "liveness does not depend on the database")
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name           string
+		ready          bool
+		draining       bool
+		checks         []health.Check
+		expectedCode   int
+		expectedStatus string
+		expectedState  string
+	}{
+		{
+			name:           "dependencies up",
+			ready:          true,
+			checks:         []health.Check{{Name: "postgres", Ping: up}, {Name: "redis", Ping: up}},
+			expectedCode:   http.StatusOK,
+			expectedStatus: "ready",
+			expectedState:  "ready",
+		},
+		{
+			name:           "database down",
+			ready:          true,
+			checks:         []health.Check{{Name: "postgres", Ping: down}, {Name: "redis", Ping: up}},
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: "not_ready",
+			expectedState:  "ready",
+		},
+		{
+			name:           "replica down",
+			ready:          true,
+			checks:         []health.Check{{Name: "postgres", Ping: up}, {Name: "postgres_replica", Optional: true, Ping: down}},
+			expectedCode:   http.StatusOK,
+			expectedStatus: "degraded",
+			expectedState:  "ready",
+		},
+		{
+			name:           "warming up",
+			checks:         []health.Check{{Name: "postgres", Ping: up}},
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: "not_ready",
+			expectedState:  "warming_up",
+		},
+		{
+			name:           "draining",
+			ready:          true,
+			draining:       true,
+			checks:         []health.Check{{Name: "postgres", Ping: up}},
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: "not_ready",
+			expectedState:  "draining",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := lifecycle.New()
+			manager.SetReady(tt.ready)
+			if tt.draining {
+				manager.Drain(context.Background())
+			}
+			handler := NewHealthHandler(health.NewChecker(time.Second, clock.Real(), tt.checks...), manager)
+
+			w := httptest.NewRecorder()
+			setupHealthTestRouter(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			var response dto.DependencyReadinessResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedStatus, response.Status)
+			assert.Equal(t, tt.expectedState, response.Lifecycle)
+			require.Len(t, response.Dependencies, len(tt.checks))
+			for i, check := range tt.checks {
+				assert.Equal(t, check.Name, response.Dependencies[i].Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImageHandler struct {
+	imageUseCase usecase.ImageUseCaseInterface
+}
+
+func NewImageHandler(imageUseCase usecase.ImageUseCaseInterface) *ImageHandler {
+	return &ImageHandler{
+		imageUseCase: imageUseCase,
+	}
+}
+
+func (h *ImageHandler) GetProductImages(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	productID, ok := parseIDParam(c, "id", "Product")
+	if !ok {
+		return
+	}
+
+	images, err := h.imageUseCase.GetProductImages(ctx, productID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToProductImageListResponse(images))
+}
+
+func (h *ImageHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "product_not_found",
+			Message: "Product not found",
+		})
+	case errors.Is(err, domain.ErrInvalidProduct):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_product",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationHandler lets admins act as a user for support. It is routed
+// under /admin, so only admins reach it.
+type ImpersonationHandler struct {
+	impersonationUseCase usecase.ImpersonationUseCaseInterface
+}
+
+func NewImpersonationHandler(impersonationUseCase usecase.ImpersonationUseCaseInterface) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationUseCase: impersonationUseCase,
+	}
+}
+
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	userID, ok := parseIDParam(c, "user_id", "User")
+	if !ok {
+		return
+	}
+
+	var req dto.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	impersonation, err := h.impersonationUseCase.Impersonate(ctx, domain.ImpersonationRequest{
+		UserID:   userID,
+		Admin:    middleware.ClientIdentity(c),
+		ClientIP: c.ClientIP(),
+		Reason:   req.Reason,
+		TTL:      req.TTL(),
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToImpersonationResponse(impersonation))
+}
+
+func (h *ImpersonationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "user_not_found",
+			Message: "User not found",
+		})
+	case errors.Is(err, domain.ErrInvalidImpersonation):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_impersonation",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockImpersonationUseCase struct {
+	mock.Mock
+}
+
+func (m *MockImpersonationUseCase) Impersonate(ctx context.Context, req domain.ImpersonationRequest) (*domain.Impersonation, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Impersonation), args.Error(1)
+}
+
+func setupImpersonationTestRouter(handler *ImpersonationHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/admin/impersonate/:user_id", handler.Impersonate)
+	return r
+}
+
+func TestImpersonationHandler_Impersonate(t *testing.T) {
+	impersonation := &domain.Impersonation{
+		User:           &domain.User{ID: 42, Email: "ada@example.com"},
+		ImpersonatedBy: "workload:support-console",
+		AccessToken:    "access",
+		ExpiresAt:      time.Date(2024, 1, 1, 0, 15, 0, 0, time.UTC),
+	}
+	request := func(ttl time.Duration) any {
+		return mock.MatchedBy(func(req domain.ImpersonationRequest) bool {
+			return req.UserID == 42 && req.Reason == "ticket 1234" && req.TTL == ttl && req.Admin != ""
+		})
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		body         string
+		mockFn       func(*MockImpersonationUseCase)
+		expectedCode int
+		expectedErr  string
+		expectedBody string
+	}{
+		{
+			name: "impersonate",
+			path: "/admin/impersonate/42",
+			body: `{"reason":"ticket 1234","expires_in":300}`,
+			mockFn: func(m *MockImpersonationUseCase) {
+				m.On("Impersonate", mock.Anything, request(5*time.Minute)).Return(impersonation, nil)
+			},
+			expectedCode: http.StatusCreated,
+			expectedBody: `{"user_id":42,"email":"ada@example.com","access_token":"access","token_type":"Bearer","expires_at":"2024-01-01T00:15:00Z","impersonated_by":"workload:support-console"}`,
+		},
+		{
+			name:         "without a reason",
+			path:         "/admin/impersonate/42",
+			body:         `{}`,
+			mockFn:       func(m *MockImpersonationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "validation_error",
+		},
+		{
+			name:         "invalid user ID",
+			path:         "/admin/impersonate/ada",
+			body:         `{"reason":"ticket 1234"}`,
+			mockFn:       func(m *MockImpersonationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "invalid_id",
+		},
+		{
+			name: "too long",
+			path: "/admin/impersonate/42",
+			body: `{"reason":"ticket 1234","expires_in":3600}`,
+			mockFn: func(m *MockImpersonationUseCase) {
+				m.On("Impersonate", mock.Anything, request(time.Hour)).Return(nil, domain.ErrInvalidImpersonation)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "invalid_impersonation",
+		},
+		{
+			name: "unknown user",
+			path: "/admin/impersonate/42",
+			body: `{"reason":"ticket 1234"}`,
+			mockFn: func(m *MockImpersonationUseCase) {
+				m.On("Impersonate", mock.Anything, request(0)).Return(nil, domain.ErrUserNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+			expectedErr:  "user_not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := new(MockImpersonationUseCase)
+			tt.mockFn(mockUseCase)
+			router := setupImpersonationTestRouter(NewImpersonationHandler(mockUseCase))
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedErr != "" {
+				var resp dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.expectedErr, resp.Error)
+			}
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSampleBytes caps the sample CSV a mapping is tested with.
+const maxImportSampleBytes = 1 << 20
+
+// ImportMappingHandler serves a store's import mappings under
+// /api/v1/stores/:id/import-mappings. Every route takes ownership of the
+// store.
+type ImportMappingHandler struct {
+	mappingUseCase usecase.ImportMappingUseCaseInterface
+}
+
+func NewImportMappingHandler(mappingUseCase usecase.ImportMappingUseCaseInterface) *ImportMappingHandler {
+	return &ImportMappingHandler{
+		mappingUseCase: mappingUseCase,
+	}
+}
+
+func (h *ImportMappingHandler) CreateMapping(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "id", "Store")
+	if !ok || !requireStoreOwner(c, storeID) {
+		return
+	}
+
+	var req dto.SaveImportMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind create import mapping request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	mapping, err := h.mappingUseCase.CreateMapping(ctx, req.ToDomain(storeID))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToImportMappingResponse(mapping))
+}
+
+func (h *ImportMappingHandler) GetMappings(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "id", "Store")
+	if !ok || !requireStoreOwner(c, storeID) {
+		return
+	}
+
+	mappings, err := h.mappingUseCase.GetMappings(ctx, storeID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToImportMappingListResponse(mappings))
+}
+
+func (h *ImportMappingHandler) GetMapping(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, mappingID, ok := h.parseMappingParams(c)
+	if !ok {
+		return
+	}
+
+	mapping, err := h.mappingUseCase.GetMapping(ctx, storeID, mappingID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToImportMappingResponse(mapping))
+}
+
+func (h *ImportMappingHandler) UpdateMapping(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, mappingID, ok := h.parseMappingParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SaveImportMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind update import mapping request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	mapping := req.ToDomain(storeID)
+	mapping.ID = mappingID
+
+	updated, err := h.mappingUseCase.UpdateMapping(ctx, mapping)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToImportMappingResponse(updated))
+}
+
+func (h *ImportMappingHandler) DeleteMapping(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, mappingID, ok := h.parseMappingParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mappingUseCase.DeleteMapping(ctx, storeID, mappingID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// TestMapping reads the first rows of the CSV sent as the request body
+// with the mapping; ?rows= sets how many, up to 100.
+func (h *ImportMappingHandler) TestMapping(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, mappingID, ok := h.parseMappingParams(c)
+	if !ok {
+		return
+	}
+
+	rows := 10
+	if rowsParam := c.Query("rows"); rowsParam != "" {
+		parsed, err := strconv.Atoi(rowsParam)
+		if err != nil || parsed <= 0 || parsed > domain.MaxImportPreviewRows {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "rows must be a number between 1 and 100",
+			})
+			return
+		}
+		rows = parsed
+	}
+
+	sample := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSampleBytes)
+	preview, err := h.mappingUseCase.TestMapping(ctx, storeID, mappingID, sample, rows)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToImportPreviewResponse(preview))
+}
+
+func (h *ImportMappingHandler) parseMappingParams(c *gin.Context) (int64, int64, bool) {
+	storeID, ok := parseIDParam(c, "id", "Store")
+	if !ok || !requireStoreOwner(c, storeID) {
+		return 0, 0, false
+	}
+	mappingID, ok := parseIDParam(c, "mapping_id", "Import mapping")
+	if !ok {
+		return 0, 0, false
+	}
+	return storeID, mappingID, true
+}
+
+func (h *ImportMappingHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrStoreNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "store_not_found",
+			Message: "Store not found",
+		})
+	case errors.Is(err, domain.ErrImportMappingNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "import_mapping_not_found",
+			Message: "Import mapping not found",
+		})
+	case errors.Is(err, domain.ErrInvalidImportMapping):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_import_mapping",
+			Message: err.Error(),
+			Fields:  dto.FieldErrors(err),
+		})
+	case errors.Is(err, domain.ErrImportMappingExists):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "import_mapping_exists",
+			Message: "The store already has an import mapping with this name",
+		})
+	case errors.Is(err, domain.ErrImportMappingInUse):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "import_mapping_in_use",
+			Message: "The import mapping is used by a feed",
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockImportMappingUseCase struct {
+	mock.Mock
+}
+
+func (m *MockImportMappingUseCase) CreateMapping(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error) {
+	args := m.Called(ctx, mapping)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImportMapping), args.Error(1)
+}
+
+func (m *MockImportMappingUseCase) GetMapping(ctx context.Context, storeID, id int64) (*domain.ImportMapping, error) {
+	args := m.Called(ctx, storeID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImportMapping), args.Error(1)
+}
+
+func (m *MockImportMappingUseCase) GetMappings(ctx context.Context, storeID int64) ([]*domain.ImportMapping, error) {
+	args := m.Called(ctx, storeID)
+	return args.Get(0).([]*domain.ImportMapping), args.Error(1)
+}
+
+func (m *MockImportMappingUseCase) UpdateMapping(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error) {
+	args := m.Called(ctx, mapping)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImportMapping), args.Error(1)
+}
+
+func (m *MockImportMappingUseCase) DeleteMapping(ctx context.Context, storeID, id int64) error {
+	args := m.Called(ctx, storeID, id)
+	return args.Error(0)
+}
+
+func (m *MockImportMappingUseCase) TestMapping(ctx context.Context, storeID, id int64, sample io.Reader, rows int) ([]domain.ImportPreviewRow, error) {
+	args := m.Called(ctx, storeID, id, sample, rows)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ImportPreviewRow), args.Error(1)
+}
+
+func setupImportMappingTestRouter(handler *ImportMappingHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	mappings := r.Group("/api/v1/stores/:id/import-mappings")
+	{
+		mappings.POST("", handler.CreateMapping)
+		mappings.GET("", handler.GetMappings)
+		mappings.GET("/:mapping_id", handler.GetMapping)
+		mappings.PUT("/:mapping_id", handler.UpdateMapping)
+		mappings.DELETE("/:mapping_id", handler.DeleteMapping)
+		mappings.POST("/:mapping_id/test", handler.TestMapping)
+	}
+
+	return r
+}
+
+func TestImportMappingHandler_CreateMapping(t *testing.T) {
+	validBody := map[string]interface{}{
+		"name": "Acme",
+		"columns": []map[string]string{
+			{"field": "name", "column": "Product Title"},
+			{"field": "price", "column": "PriceCents", "transform": "cents"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		body         interface{}
+		mockFn       func(*MockImportMappingUseCase)
+		expectedCode int
+	}{
+		{
+			name: "owner creates a mapping",
+			path: "/api/v1/stores/3/import-mappings",
+			body: validBody,
+			mockFn: func(m *MockImportMappingUseCase) {
+				m.On("CreateMapping", mock.Anything, mock.MatchedBy(func(mapping *domain.ImportMapping) bool {
+					return mapping.StoreID == 3 && len(mapping.Columns) == 2 &&
+						mapping.Columns[1].Transform == domain.ImportTransformCents
+				})).Return(&domain.ImportMapping{ID: 2, StoreID: 3, Name: "Acme"}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "unknown transform",
+			path: "/api/v1/stores/3/import-mappings",
+			body: map[string]interface{}{
+				"name":    "Acme",
+				"columns": []map[string]string{{"field": "price", "column": "Price", "transform": "euros"}},
+			},
+			mockFn:       func(m *MockImportMappingUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "another store",
+			path:         "/api/v1/stores/4/import-mappings",
+			body:         validBody,
+			mockFn:       func(m *MockImportMappingUseCase) {},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name: "name taken",
+			path: "/api/v1/stores/3/import-mappings",
+			body: validBody,
+			mockFn: func(m *MockImportMappingUseCase) {
+				m.On("CreateMapping", mock.Anything, mock.Anything).Return(nil, domain.ErrImportMappingExists)
+			},
+			expectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockImportMappingUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupImportMappingTestRouter(NewImportMappingHandler(mockUseCase))
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImportMappingHandler_DeleteMapping_InUse(t *testing.T) {
+	mockUseCase := &MockImportMappingUseCase{}
+	mockUseCase.On("DeleteMapping", mock.Anything, int64(3), int64(2)).Return(domain.ErrImportMappingInUse)
+	router := setupImportMappingTestRouter(NewImportMappingHandler(mockUseCase))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/stores/3/import-mappings/2", nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestImportMappingHandler_TestMapping(t *testing.T) {
+	t.Run("previews the requested rows", func(t *testing.T) {
+		mockUseCase := &MockImportMappingUseCase{}
+		mockUseCase.On("TestMapping", mock.Anything, int64(3), int64(2), mock.Anything, 5).Return([]domain.ImportPreviewRow{
+			{Line: 2, Item: domain.FeedItem{Name: "Rye", Price: 3.49}},
+			{Line: 3, Error: `invalid price "n/a"`},
+		}, nil)
+		router := setupImportMappingTestRouter(NewImportMappingHandler(mockUseCase))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/3/import-mappings/2/test?rows=5",
+			strings.NewReader("Product Title,Price\nRye,3.49\nFlour,n/a\n"))
+		req.Header.Set("Content-Type", "text/csv")
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response dto.ImportPreviewResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Rows, 2)
+		assert.Equal(t, "Rye", response.Rows[0].Item.Name)
+		assert.Nil(t, response.Rows[1].Item)
+		assert.NotEmpty(t, response.Rows[1].Error)
+	})
+
+	t.Run("too many rows", func(t *testing.T) {
+		mockUseCase := &MockImportMappingUseCase{}
+		router := setupImportMappingTestRouter(NewImportMappingHandler(mockUseCase))
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/stores/3/import-mappings/2/test?rows=500", strings.NewReader(""))
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUseCase.AssertExpectations(t)
+	})
+}
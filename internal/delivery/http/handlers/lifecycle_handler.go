@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/lifecycle"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type LifecycleHandler struct {
+	manager      *lifecycle.Manager
+	drainTimeout time.Duration
+}
+
+func NewLifecycleHandler(manager *lifecycle.Manager, drainTimeout time.Duration) *LifecycleHandler {
+	return &LifecycleHandler{
+		manager:      manager,
+		drainTimeout: drainTimeout,
+	}
+}
+
+func (h *LifecycleHandler) Ready(c *gin.Context) {
+	if !h.manager.Ready() {
+		c.JSON(http.StatusServiceUnavailable, dto.ReadinessResponse{
+			Status:   "not_ready",
+			InFlight: h.manager.InFlight(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ReadinessResponse{
+		Status:   "ready",
+		InFlight: h.manager.InFlight(),
+	})
+}
+
+func (h *LifecycleHandler) Drain(c *gin.Context) {
+	var req dto.DrainRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	timeout := h.drainTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"action":    "drain",
+		"timeout":   timeout,
+		"in_flight": h.manager.InFlight(),
+	}).Info("Draining connections")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	remaining := h.manager.Drain(ctx)
+	if remaining > 0 {
+		logger.FromContext(ctx).WithField("in_flight", remaining).Warn("Drain deadline reached with requests still in flight")
+	}
+
+	c.JSON(http.StatusOK, dto.DrainResponse{
+		Drained:  remaining == 0,
+		InFlight: remaining,
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/lifecycle"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLifecycleTestRouter(handler *LifecycleHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	r.POST("/admin/drain", handler.Drain)
+	r.GET("/ready", handler.Ready)
+
+	return r
+}
+
+func TestLifecycleHandler_Drain(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		body          string
+		inFlight      int
+		finishAfter   time.Duration
+		expectedCode  int
+		expectDrained bool
+	}{
+		{
+			name:          "nothing in flight",
+			expectedCode:  http.StatusOK,
+			expectDrained: true,
+		},
+		{
+			name:          "waits for in-flight requests",
+			inFlight:      2,
+			finishAfter:   100 * time.Millisecond,
+			expectedCode:  http.StatusOK,
+			expectDrained: true,
+		},
+		{
+			name:          "deadline reached",
+			body:          `{"timeout_seconds": 1}`,
+			inFlight:      1,
+			expectedCode:  http.StatusOK,
+			expectDrained: false,
+		},
+		{
+			name:         "invalid timeout",
+			body:         `{"timeout_seconds": -5}`,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := lifecycle.New()
+			manager.SetReady(true)
+			for i := 0; i < tt.inFlight; i++ {
+				manager.Begin()
+			}
+			if tt.finishAfter > 0 {
+				time.AfterFunc(tt.finishAfter, func() {
+					for i := 0; i < tt.inFlight; i++ {
+						manager.End()
+					}
+				})
+			}
+
+			router := setupLifecycleTestRouter(NewLifecycleHandler(manager, 5*time.Second))
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/drain", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+
+			var resp dto.DrainResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expectDrained, resp.Drained)
+			assert.False(t, manager.Ready())
+
+			select {
+			case <-manager.Drained():
+			default:
+				t.Fatal("drained signal was not sent")
+			}
+
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		})
+	}
+}
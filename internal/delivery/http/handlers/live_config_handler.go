@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LiveConfigHandler reads and changes the live configuration for the admin
+// dashboard. It is routed under /admin, so only admins reach it.
+type LiveConfigHandler struct {
+	liveConfigUseCase usecase.LiveConfigUseCaseInterface
+}
+
+func NewLiveConfigHandler(liveConfigUseCase usecase.LiveConfigUseCaseInterface) *LiveConfigHandler {
+	return &LiveConfigHandler{
+		liveConfigUseCase: liveConfigUseCase,
+	}
+}
+
+func (h *LiveConfigHandler) GetSettings(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	settings, err := h.liveConfigUseCase.GetSettings(ctx)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToConfigSettingsResponse(settings))
+}
+
+func (h *LiveConfigHandler) GetSetting(c *gin.Context) {
+	h.getSetting(c, c.Param("key"))
+}
+
+func (h *LiveConfigHandler) SaveSetting(c *gin.Context) {
+	h.saveSetting(c, c.Param("key"))
+}
+
+func (h *LiveConfigHandler) GetStoreSettings(c *gin.Context) {
+	storeID, ok := parseIDParam(c, "store_id", "Store")
+	if !ok {
+		return
+	}
+	h.getSetting(c, domain.StoreSettingsKey(storeID))
+}
+
+func (h *LiveConfigHandler) SaveStoreSettings(c *gin.Context) {
+	storeID, ok := parseIDParam(c, "store_id", "Store")
+	if !ok {
+		return
+	}
+	h.saveSetting(c, domain.StoreSettingsKey(storeID))
+}
+
+func (h *LiveConfigHandler) getSetting(c *gin.Context, key string) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	setting, err := h.liveConfigUseCase.GetSetting(ctx, key)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToConfigSettingResponse(setting))
+}
+
+func (h *LiveConfigHandler) saveSetting(c *gin.Context, key string) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req dto.SaveConfigSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	setting, err := h.liveConfigUseCase.SaveSetting(ctx, domain.ConfigChange{
+		Key:      key,
+		Value:    req.Value,
+		Version:  *req.Version,
+		Actor:    middleware.ClientIdentity(c),
+		ClientIP: c.ClientIP(),
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToConfigSettingResponse(setting))
+}
+
+func (h *LiveConfigHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrConfigSettingNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "setting_not_found",
+			Message: "Setting not found",
+		})
+	case errors.Is(err, domain.ErrStoreNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "store_not_found",
+			Message: "Store not found",
+		})
+	case errors.Is(err, domain.ErrInvalidConfigSetting):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_setting",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrConfigConflict):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "version_conflict",
+			Message: "The setting was changed since this version; fetch it again and reapply your changes",
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
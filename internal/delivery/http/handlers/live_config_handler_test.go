@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockLiveConfigUseCase struct {
+	mock.Mock
+}
+
+func (m *MockLiveConfigUseCase) GetSettings(ctx context.Context) ([]*domain.ConfigSetting, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ConfigSetting), args.Error(1)
+}
+
+func (m *MockLiveConfigUseCase) GetSetting(ctx context.Context, key string) (*domain.ConfigSetting, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ConfigSetting), args.Error(1)
+}
+
+func (m *MockLiveConfigUseCase) SaveSetting(ctx context.Context, change domain.ConfigChange) (*domain.ConfigSetting, error) {
+	args := m.Called(ctx, change)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ConfigSetting), args.Error(1)
+}
+
+func setupLiveConfigTestRouter(handler *LiveConfigHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin/config", handler.GetSettings)
+	r.GET("/admin/config/:key", handler.GetSetting)
+	r.PUT("/admin/config/:key", handler.SaveSetting)
+	r.GET("/admin/stores/:store_id/settings", handler.GetStoreSettings)
+	r.PUT("/admin/stores/:store_id/settings", handler.SaveStoreSettings)
+	return r
+}
+
+func TestLiveConfigHandler(t *testing.T) {
+	maintenance := &domain.ConfigSetting{
+		Key:       domain.ConfigMaintenance,
+		Value:     json.RawMessage(`{"enabled":true,"message":""}`),
+		Version:   4,
+		UpdatedBy: "workload:admin-console",
+		UpdatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	change := func(key string, version int64) any {
+		return mock.MatchedBy(func(c domain.ConfigChange) bool {
+			return c.Key == key && c.Version == version && c.ClientIP != ""
+		})
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		mockFn       func(*MockLiveConfigUseCase)
+		expectedCode int
+		expectedErr  string
+		expectedBody string
+	}{
+		{
+			name:   "list settings",
+			method: http.MethodGet,
+			path:   "/admin/config",
+			mockFn: func(m *MockLiveConfigUseCase) {
+				m.On("GetSettings", mock.Anything).Return([]*domain.ConfigSetting{
+					{Key: domain.ConfigFeatureFlags, Value: json.RawMessage(`{}`)},
+					maintenance,
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"settings":[{"key":"feature_flags","value":{},"version":0},` +
+				`{"key":"maintenance","value":{"enabled":true,"message":""},"version":4,"updated_by":"workload:admin-console","updated_at":"2026-03-01T12:00:00Z"}]}`,
+		},
+		{
+			name:   "save setting",
+			method: http.MethodPut,
+			path:   "/admin/config/maintenance",
+			body:   `{"value":{"enabled":true},"version":3}`,
+			mockFn: func(m *MockLiveConfigUseCase) {
+				m.On("SaveSetting", mock.Anything, change(domain.ConfigMaintenance, 3)).Return(maintenance, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "save without a version",
+			method:       http.MethodPut,
+			path:         "/admin/config/maintenance",
+			body:         `{"value":{"enabled":true}}`,
+			mockFn:       func(m *MockLiveConfigUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "validation_error",
+		},
+		{
+			name:   "stale version",
+			method: http.MethodPut,
+			path:   "/admin/config/maintenance",
+			body:   `{"value":{"enabled":false},"version":2}`,
+			mockFn: func(m *MockLiveConfigUseCase) {
+				m.On("SaveSetting", mock.Anything, change(domain.ConfigMaintenance, 2)).Return(nil, domain.ErrConfigConflict)
+			},
+			expectedCode: http.StatusConflict,
+			expectedErr:  "version_conflict",
+		},
+		{
+			name:   "invalid value",
+			method: http.MethodPut,
+			path:   "/admin/config/rate_limit",
+			body:   `{"value":{"rps":0,"burst":1},"version":0}`,
+			mockFn: func(m *MockLiveConfigUseCase) {
+				m.On("SaveSetting", mock.Anything, change(domain.ConfigRateLimit, 0)).Return(nil, domain.ErrInvalidConfigSetting)
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "invalid_setting",
+		},
+		{
+			name:   "unknown setting",
+			method: http.MethodGet,
+			path:   "/admin/config/log_level",
+			mockFn: func(m *MockLiveConfigUseCase) {
+				m.On("GetSetting", mock.Anything, "log_level").Return(nil, domain.ErrConfigSettingNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+			expectedErr:  "setting_not_found",
+		},
+		{
+			name:   "save store settings",
+			method: http.MethodPut,
+			path:   "/admin/stores/7/settings",
+			body:   `{"value":{"feature_flags":{"bundles":true}},"version":0}`,
+			mockFn: func(m *MockLiveConfigUseCase) {
+				m.On("SaveSetting", mock.Anything, change("store:7", 0)).Return(&domain.ConfigSetting{Key: "store:7", Version: 1}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "unknown store",
+			method: http.MethodGet,
+			path:   "/admin/stores/8/settings",
+			mockFn: func(m *MockLiveConfigUseCase) {
+				m.On("GetSetting", mock.Anything, "store:8").Return(nil, domain.ErrStoreNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+			expectedErr:  "store_not_found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := new(MockLiveConfigUseCase)
+			tt.mockFn(mockUseCase)
+			router := setupLiveConfigTestRouter(NewLiveConfigHandler(mockUseCase))
+
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedErr != "" {
+				var resp dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.expectedErr, resp.Error)
+			}
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
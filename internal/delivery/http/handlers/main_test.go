@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"testing"
+
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/pkg/apikey"
+	"go.uber.org/goleak"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+// testAPIKey and testBulkAPIKey are the keys issuedTestKeys accepts. Their
+// holders own stores 3 and 5, and testBulkAPIKey has the bulk scope.
+const (
+	testAPIKey     = "secret-key"
+	testBulkAPIKey = "bulk-key"
+)
+
+func issuedTestKeys() gin.HandlerFunc {
+	return middleware.APIKey(apikey.NewMemoryStore(&apikey.Key{
+		ID:       1,
+		Name:     "test",
+		Hash:     apikey.Hash(testAPIKey),
+		StoreIDs: []int64{3, 5},
+	}, &apikey.Key{
+		ID:       2,
+		Name:     "bulk",
+		Hash:     apikey.Hash(testBulkAPIKey),
+		StoreIDs: []int64{3, 5},
+		Scopes:   []string{apikey.ScopeBulk},
+	}), logrus.New())
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ModerationHandler struct {
+	moderationUseCase usecase.ModerationUseCaseInterface
+	clock             clock.Clock
+}
+
+func NewModerationHandler(moderationUseCase usecase.ModerationUseCaseInterface, clk clock.Clock) *ModerationHandler {
+	return &ModerationHandler{
+		moderationUseCase: moderationUseCase,
+		clock:             clk,
+	}
+}
+
+func (h *ModerationHandler) GetProductsForReview(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	products, err := h.moderationUseCase.GetProductsForReview(ctx, c.Query("status"), page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToProductListResponse(products, page, h.clock.Now())
+	writeList(c, response, response.Products, page)
+}
+
+func (h *ModerationHandler) ReviewProduct(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Product")
+	if !ok {
+		return
+	}
+
+	var req dto.ReviewProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind review product request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	product, err := h.moderationUseCase.ReviewProduct(ctx, id, req.ToDomain())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToProductResponse(product, h.clock.Now()))
+}
+
+func (h *ModerationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "product_not_found",
+			Message: "Product not found",
+		})
+	case errors.Is(err, domain.ErrInvalidProduct):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
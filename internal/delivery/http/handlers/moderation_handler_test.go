@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockModerationUseCase struct {
+	mock.Mock
+}
+
+func (m *MockModerationUseCase) GetProductsForReview(ctx context.Context, status string, limit, offset int) ([]*domain.Product, error) {
+	args := m.Called(ctx, status, limit, offset)
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func (m *MockModerationUseCase) ReviewProduct(ctx context.Context, id int64, result domain.ModerationResult) (*domain.Product, error) {
+	args := m.Called(ctx, id, result)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func setupModerationTestRouter(handler *ModerationHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	moderation := r.Group("/admin/moderation")
+	{
+		moderation.GET("/products", handler.GetProductsForReview)
+		moderation.POST("/products/:id/review", handler.ReviewProduct)
+	}
+
+	return r
+}
+
+func TestModerationHandler_GetProductsForReview(t *testing.T) {
+
+	mockUseCase := &MockModerationUseCase{}
+	mockUseCase.On("GetProductsForReview", mock.Anything, "pending", 10, 0).Return(
+		[]*domain.Product{{ID: 1, Name: "Shirt", ModerationStatus: domain.ModerationStatusPending}}, nil)
+
+	router := setupModerationTestRouter(NewModerationHandler(mockUseCase, clock.Real()))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/moderation/products?status=pending", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"moderation_status":"pending"`)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestModerationHandler_ReviewProduct(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		id           string
+		requestBody  interface{}
+		mockFn       func(*MockModerationUseCase)
+		expectedCode int
+	}{
+		{
+			name:        "approve",
+			id:          "1",
+			requestBody: map[string]interface{}{"status": "approved"},
+			mockFn: func(m *MockModerationUseCase) {
+				m.On("ReviewProduct", mock.Anything, int64(1), domain.ModerationResult{Status: "approved"}).Return(
+					&domain.Product{ID: 1, ModerationStatus: domain.ModerationStatusApproved}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid status",
+			id:           "1",
+			requestBody:  map[string]interface{}{"status": "pending"},
+			mockFn:       func(m *MockModerationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid id",
+			id:           "abc",
+			requestBody:  map[string]interface{}{"status": "approved"},
+			mockFn:       func(m *MockModerationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:        "product not found",
+			id:          "9",
+			requestBody: map[string]interface{}{"status": "rejected", "reason": "spam"},
+			mockFn: func(m *MockModerationUseCase) {
+				m.On("ReviewProduct", mock.Anything, int64(9), mock.Anything).Return(nil, domain.ErrProductNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockModerationUseCase{}
+			tt.mockFn(mockUseCase)
+
+			router := setupModerationTestRouter(NewModerationHandler(mockUseCase, clock.Real()))
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/admin/moderation/products/"+tt.id+"/review", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrderHandler places and reads orders. Callers place and see the orders
+// of stores they own; listing across stores is for admins only.
+type OrderHandler struct {
+	orderUseCase usecase.OrderUseCaseInterface
+}
+
+func NewOrderHandler(orderUseCase usecase.OrderUseCaseInterface) *OrderHandler {
+	return &OrderHandler{
+		orderUseCase: orderUseCase,
+	}
+}
+
+func (h *OrderHandler) CreateOrder(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req dto.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind create order request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+	middleware.SetStoreID(c, req.StoreID)
+	if !requireStoreOwner(c, req.StoreID) {
+		return
+	}
+
+	order, err := h.orderUseCase.CreateOrder(ctx, req.ToDomain())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToOrderResponse(order))
+}
+
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Order")
+	if !ok {
+		return
+	}
+	if !requireAuthenticated(c) {
+		return
+	}
+
+	order, err := h.orderUseCase.GetOrder(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	middleware.SetStoreID(c, order.StoreID)
+	if !requireStoreOwner(c, order.StoreID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToOrderResponse(order))
+}
+
+func (h *OrderHandler) GetOrders(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseStoreIDQuery(c)
+	if !ok || !requireStoreOwner(c, storeID) {
+		return
+	}
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	orders, err := h.orderUseCase.GetOrders(ctx, storeID, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToOrderListResponse(orders, page.Limit, page.Offset)
+	writeList(c, response, response.Orders, page)
+}
+
+func (h *OrderHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "order_not_found",
+			Message: "Order not found",
+		})
+	case errors.Is(err, domain.ErrInvalidOrder):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_order",
+			Message: err.Error(),
+			Fields:  dto.FieldErrors(err),
+		})
+	case errors.Is(err, domain.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "insufficient_stock",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
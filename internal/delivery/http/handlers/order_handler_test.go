@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockOrderUseCase struct {
+	mock.Mock
+}
+
+func (m *MockOrderUseCase) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	args := m.Called(ctx, order)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *MockOrderUseCase) GetOrder(ctx context.Context, id int64) (*domain.Order, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *MockOrderUseCase) GetOrders(ctx context.Context, storeID int64, limit, offset int) ([]*domain.Order, error) {
+	args := m.Called(ctx, storeID, limit, offset)
+	return args.Get(0).([]*domain.Order), args.Error(1)
+}
+
+func setupOrderTestRouter(handler *OrderHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	orders := r.Group("/api/v1/orders")
+	{
+		orders.POST("", handler.CreateOrder)
+		orders.GET("/:id", handler.GetOrder)
+		orders.GET("", handler.GetOrders)
+	}
+
+	return r
+}
+
+func TestOrderHandler_CreateOrder(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		requestBody  interface{}
+		apiKey       string
+		mockFn       func(*MockOrderUseCase)
+		expectedCode int
+	}{
+		{
+			name:        "successful order",
+			requestBody: map[string]interface{}{"store_id": 3, "items": []map[string]interface{}{{"product_id": 42, "quantity": 2}}},
+			apiKey:      testAPIKey,
+			mockFn: func(m *MockOrderUseCase) {
+				m.On("CreateOrder", mock.Anything, mock.MatchedBy(func(o *domain.Order) bool {
+					return o.StoreID == 3 && len(o.Items) == 1 && o.Items[0].ProductID == 42 && o.Items[0].Quantity == quantity.New(2)
+				})).Return(&domain.Order{ID: 31, StoreID: 3, Status: domain.OrderStatusPlaced}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "no items",
+			requestBody:  map[string]interface{}{"store_id": 3, "items": []map[string]interface{}{}},
+			apiKey:       testAPIKey,
+			mockFn:       func(m *MockOrderUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "anonymous",
+			requestBody:  map[string]interface{}{"store_id": 3, "items": []map[string]interface{}{{"product_id": 42, "quantity": 2}}},
+			mockFn:       func(m *MockOrderUseCase) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "another store",
+			requestBody:  map[string]interface{}{"store_id": 4, "items": []map[string]interface{}{{"product_id": 42, "quantity": 2}}},
+			apiKey:       testAPIKey,
+			mockFn:       func(m *MockOrderUseCase) {},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:        "insufficient stock",
+			requestBody: map[string]interface{}{"store_id": 3, "items": []map[string]interface{}{{"product_id": 42, "quantity": 20}}},
+			apiKey:      testAPIKey,
+			mockFn: func(m *MockOrderUseCase) {
+				m.On("CreateOrder", mock.Anything, mock.Anything).Return(nil, domain.ErrInsufficientStock)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:        "invalid order",
+			requestBody: map[string]interface{}{"store_id": 3, "items": []map[string]interface{}{{"product_id": 60, "quantity": 1}}},
+			apiKey:      testAPIKey,
+			mockFn: func(m *MockOrderUseCase) {
+				m.On("CreateOrder", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidOrder)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockOrderUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupOrderTestRouter(NewOrderHandler(mockUseCase))
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderHandler_GetOrder(t *testing.T) {
+	mockUseCase := &MockOrderUseCase{}
+	mockUseCase.On("GetOrder", mock.Anything, int64(31)).Return(&domain.Order{ID: 31, StoreID: 3, Items: []domain.OrderItem{{ProductID: 42, Quantity: quantity.New(2), UnitPrice: 12.5}}}, nil)
+	mockUseCase.On("GetOrder", mock.Anything, int64(32)).Return(&domain.Order{ID: 32, StoreID: 4}, nil)
+	mockUseCase.On("GetOrder", mock.Anything, int64(33)).Return(nil, domain.ErrOrderNotFound)
+	router := setupOrderTestRouter(NewOrderHandler(mockUseCase))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/orders/31")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response dto.OrderResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Items, 1)
+	assert.Equal(t, 25.0, response.Items[0].Total)
+
+	assert.Equal(t, http.StatusForbidden, get("/api/v1/orders/32").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/orders/33").Code)
+}
+
+func TestOrderHandler_GetOrders(t *testing.T) {
+	mockUseCase := &MockOrderUseCase{}
+	mockUseCase.On("GetOrders", mock.Anything, int64(3), 20, 0).Return([]*domain.Order{{ID: 31, StoreID: 3}}, nil)
+	router := setupOrderTestRouter(NewOrderHandler(mockUseCase))
+
+	list := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-API-Key", testAPIKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := list("/api/v1/orders?store_id=3&limit=20")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response dto.OrderListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Orders, 1)
+
+	assert.Equal(t, http.StatusForbidden, list("/api/v1/orders?store_id=4").Code)
+	assert.Equal(t, http.StatusForbidden, list("/api/v1/orders").Code, "listing every store's orders is for admins")
+}
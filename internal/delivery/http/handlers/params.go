@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/pkg/pagination"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseIDParam reads the numeric path parameter param, answering 400 when
+// it is not a number. entity names the ID in the message.
+func parseIDParam(c *gin.Context, param, entity string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(param), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_id",
+			Message: entity + " ID must be a valid number",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// bindPage reads the page a list request asks for, answering 400 when a
+// pagination parameter is refused.
+func bindPage(c *gin.Context, sorts pagination.SortSpec) (pagination.Page, bool) {
+	page, err := pagination.Bind(c, sorts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return pagination.Page{}, false
+	}
+	return page, true
+}
+
+// writeList answers a list request with the page of items, in the
+// dto.ListResponse envelope or, in compatibility mode, as legacy, the
+// list's v1 response holding the same items.
+func writeList[T any](c *gin.Context, legacy any, items []T, page pagination.Page) {
+	if !middleware.ListEnvelopeEnabled(c) {
+		c.JSON(http.StatusOK, legacy)
+		return
+	}
+	c.JSON(http.StatusOK, dto.ToListResponse(items, page, c.Request.URL))
+}
+
+// requireAuthenticated lets the caller through if it is authenticated and
+// answers 401 otherwise.
+func requireAuthenticated(c *gin.Context) bool {
+	if middleware.Authenticated(c) {
+		return true
+	}
+	c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+		Error:   "authentication_required",
+		Message: "A bearer token, X-API-Key header or session cookie is required",
+	})
+	return false
+}
+
+// requireStoreOwner lets the caller through if it may manage the store.
+// Anonymous callers get 401 and callers that do not own the store 403.
+func requireStoreOwner(c *gin.Context, storeID int64) bool {
+	if !requireAuthenticated(c) {
+		return false
+	}
+	if middleware.OwnsStore(c, storeID) {
+		return true
+	}
+
+	message := "The caller does not own this store"
+	if storeID <= 0 {
+		message = "Only admins may manage settings that span stores; send store_id"
+	}
+	c.JSON(http.StatusForbidden, dto.ErrorResponse{
+		Error:   "store_not_owned",
+		Message: message,
+	})
+	return false
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreviewHandler hands out preview links. The links themselves are served
+// by ProductHandler.GetProduct.
+type PreviewHandler struct {
+	previewUseCase usecase.PreviewUseCaseInterface
+}
+
+func NewPreviewHandler(previewUseCase usecase.PreviewUseCaseInterface) *PreviewHandler {
+	return &PreviewHandler{
+		previewUseCase: previewUseCase,
+	}
+}
+
+// CreatePreviewToken is for the product's store owner.
+func (h *PreviewHandler) CreatePreviewToken(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	productID, ok := parseIDParam(c, "id", "Product")
+	if !ok {
+		return
+	}
+	if !requireAuthenticated(c) {
+		return
+	}
+
+	var req dto.CreatePreviewTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	preview, err := h.previewUseCase.CreatePreviewToken(ctx, productID, req.TTL())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	middleware.SetStoreID(c, preview.StoreID)
+	if !requireStoreOwner(c, preview.StoreID) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToPreviewTokenResponse(preview))
+}
+
+func (h *PreviewHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "product_not_found",
+			Message: "Product not found",
+		})
+	case errors.Is(err, domain.ErrInvalidProduct):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_product",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidPreviewTTL):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_preview_ttl",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
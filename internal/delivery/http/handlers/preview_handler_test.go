@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPreviewUseCase struct {
+	mock.Mock
+}
+
+func (m *MockPreviewUseCase) CreatePreviewToken(ctx context.Context, productID int64, ttl time.Duration) (*domain.PreviewToken, error) {
+	args := m.Called(ctx, productID, ttl)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PreviewToken), args.Error(1)
+}
+
+func (m *MockPreviewUseCase) GetPreview(ctx context.Context, productID int64, token string) (*domain.Product, error) {
+	args := m.Called(ctx, productID, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func TestPreviewHandler_CreatePreviewToken(t *testing.T) {
+	expiresAt := time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		body         string
+		apiKey       string
+		mockFn       func(*MockPreviewUseCase)
+		expectedCode int
+	}{
+		{
+			name:   "owner gets a link for the default lifetime",
+			apiKey: testAPIKey,
+			mockFn: func(m *MockPreviewUseCase) {
+				m.On("CreatePreviewToken", mock.Anything, int64(7), time.Duration(0)).
+					Return(&domain.PreviewToken{ProductID: 7, StoreID: 3, Token: "a.b.c", ExpiresAt: expiresAt}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "owner picks the lifetime",
+			body:   `{"expires_in": 3600}`,
+			apiKey: testAPIKey,
+			mockFn: func(m *MockPreviewUseCase) {
+				m.On("CreatePreviewToken", mock.Anything, int64(7), time.Hour).
+					Return(&domain.PreviewToken{ProductID: 7, StoreID: 3, Token: "a.b.c", ExpiresAt: expiresAt}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "lifetime past the maximum",
+			body:   `{"expires_in": 31536000}`,
+			apiKey: testAPIKey,
+			mockFn: func(m *MockPreviewUseCase) {
+				m.On("CreatePreviewToken", mock.Anything, int64(7), 365*24*time.Hour).
+					Return(nil, domain.ErrInvalidPreviewTTL)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "another store's product",
+			apiKey: testAPIKey,
+			mockFn: func(m *MockPreviewUseCase) {
+				m.On("CreatePreviewToken", mock.Anything, int64(7), time.Duration(0)).
+					Return(&domain.PreviewToken{ProductID: 7, StoreID: 4, Token: "a.b.c", ExpiresAt: expiresAt}, nil)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "anonymous caller",
+			mockFn:       func(m *MockPreviewUseCase) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockPreviewUseCase{}
+			tt.mockFn(mockUseCase)
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(issuedTestKeys())
+			router.POST("/api/v1/products/:id/preview-tokens", NewPreviewHandler(mockUseCase).CreatePreviewToken)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/products/7/preview-tokens", bytes.NewBufferString(tt.body))
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if w.Code == http.StatusCreated {
+				var response dto.PreviewTokenResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "/api/v1/products/7?preview_token=a.b.c", response.URL)
+				assert.Equal(t, "2024-03-03T12:00:00Z", response.ExpiresAt)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_GetProductDrafts(t *testing.T) {
+	draft := &domain.Product{ID: 7, StoreID: 3, Name: "Spring collection", Status: domain.ProductStatusDraft}
+
+	tests := []struct {
+		name         string
+		path         string
+		apiKey       string
+		mockFn       func(*MockProductUseCase, *MockPreviewUseCase)
+		expectedCode int
+	}{
+		{
+			name: "hidden from anonymous callers",
+			path: "/api/v1/products/7",
+			mockFn: func(products *MockProductUseCase, previews *MockPreviewUseCase) {
+				products.On("GetProduct", mock.Anything, int64(7)).Return(draft, nil)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "shown to the store's owner",
+			path:   "/api/v1/products/7",
+			apiKey: testAPIKey,
+			mockFn: func(products *MockProductUseCase, previews *MockPreviewUseCase) {
+				products.On("GetProduct", mock.Anything, int64(7)).Return(draft, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "shown through a preview link",
+			path: "/api/v1/products/7?preview_token=a.b.c",
+			mockFn: func(products *MockProductUseCase, previews *MockPreviewUseCase) {
+				previews.On("GetPreview", mock.Anything, int64(7), "a.b.c").Return(draft, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "expired preview link",
+			path: "/api/v1/products/7?preview_token=a.b.c",
+			mockFn: func(products *MockProductUseCase, previews *MockPreviewUseCase) {
+				previews.On("GetPreview", mock.Anything, int64(7), "a.b.c").Return(nil, domain.ErrInvalidPreviewToken)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products := &MockProductUseCase{}
+			previews := &MockPreviewUseCase{}
+			tt.mockFn(products, previews)
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(issuedTestKeys())
+			router.GET("/api/v1/products/:id", NewProductHandler(products, previews, clock.Real()).GetProduct)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			products.AssertExpectations(t)
+			previews.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PricingHandler struct {
+	pricingUseCase usecase.PricingUseCaseInterface
+}
+
+func NewPricingHandler(pricingUseCase usecase.PricingUseCaseInterface) *PricingHandler {
+	return &PricingHandler{
+		pricingUseCase: pricingUseCase,
+	}
+}
+
+func (h *PricingHandler) GetPolicies(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "store_id", "Store")
+	if !ok {
+		return
+	}
+	middleware.SetStoreID(c, storeID)
+
+	policies, err := h.pricingUseCase.GetPolicies(ctx, storeID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPricingPolicyListResponse(policies))
+}
+
+func (h *PricingHandler) SavePolicy(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "store_id", "Store")
+	if !ok {
+		return
+	}
+	middleware.SetStoreID(c, storeID)
+
+	var req dto.SavePricingPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind save pricing policy request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	policy, err := req.ToDomain(storeID, c.Param("currency"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	saved, err := h.pricingUseCase.SavePolicy(ctx, policy)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPricingPolicyResponse(saved))
+}
+
+func (h *PricingHandler) DeletePolicy(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "store_id", "Store")
+	if !ok {
+		return
+	}
+	middleware.SetStoreID(c, storeID)
+
+	if err := h.pricingUseCase.DeletePolicy(ctx, storeID, c.Param("currency")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PricingHandler) QuotePrice(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	productID, ok := parseIDParam(c, "id", "Product")
+	if !ok {
+		return
+	}
+
+	var query dto.PriceQuoteQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	quote, err := h.pricingUseCase.QuotePrice(ctx, productID, query.ToDomain())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	middleware.SetStoreID(c, quote.StoreID)
+
+	c.JSON(http.StatusOK, dto.ToPriceQuoteResponse(quote))
+}
+
+func (h *PricingHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrPricingPolicyNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "pricing_policy_not_found",
+			Message: "Store has no pricing policy for this currency",
+		})
+	case errors.Is(err, domain.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "product_not_found",
+			Message: "Product not found",
+		})
+	case errors.Is(err, domain.ErrInvalidPricingPolicy), errors.Is(err, domain.ErrInvalidPriceQuote):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPricingUseCase struct {
+	mock.Mock
+}
+
+func (m *MockPricingUseCase) GetPolicies(ctx context.Context, storeID int64) ([]*domain.PricingPolicy, error) {
+	args := m.Called(ctx, storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PricingPolicy), args.Error(1)
+}
+
+func (m *MockPricingUseCase) SavePolicy(ctx context.Context, policy *domain.PricingPolicy) (*domain.PricingPolicy, error) {
+	args := m.Called(ctx, policy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PricingPolicy), args.Error(1)
+}
+
+func (m *MockPricingUseCase) DeletePolicy(ctx context.Context, storeID int64, currency string) error {
+	args := m.Called(ctx, storeID, currency)
+	return args.Error(0)
+}
+
+func (m *MockPricingUseCase) QuotePrice(ctx context.Context, productID int64, adjustment domain.PriceAdjustment) (*domain.PriceQuote, error) {
+	args := m.Called(ctx, productID, adjustment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PriceQuote), args.Error(1)
+}
+
+func setupPricingTestRouter(handler *PricingHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	policies := r.Group("/api/v1/pricing-policies")
+	{
+		policies.GET("/:store_id", handler.GetPolicies)
+		policies.PUT("/:store_id/:currency", handler.SavePolicy)
+		policies.DELETE("/:store_id/:currency", handler.DeletePolicy)
+	}
+	r.GET("/api/v1/products/:id/price", handler.QuotePrice)
+
+	return r
+}
+
+func TestPricingHandler_SavePolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		mockFn     func(*MockPricingUseCase)
+		wantStatus int
+	}{
+		{
+			name: "increment in major units",
+			body: `{"rounding":"increment","step":0.05}`,
+			mockFn: func(m *MockPricingUseCase) {
+				want := &domain.PricingPolicy{StoreID: 3, Currency: "CHF", Rounding: "increment", Step: 5, Direction: "nearest", Decimals: 2}
+				m.On("SavePolicy", mock.Anything, want).Return(want, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "zero decimals",
+			body: `{"rounding":"increment","step":10,"direction":"up","decimals":0}`,
+			mockFn: func(m *MockPricingUseCase) {
+				want := &domain.PricingPolicy{StoreID: 3, Currency: "CHF", Rounding: "increment", Step: 10, Direction: "up", Decimals: 0}
+				m.On("SavePolicy", mock.Anything, want).Return(want, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "step finer than decimals",
+			body:       `{"rounding":"increment","step":0.005}`,
+			mockFn:     func(m *MockPricingUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown rounding",
+			body:       `{"rounding":"bankers"}`,
+			mockFn:     func(m *MockPricingUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "rejected by use case",
+			body: `{"rounding":"increment"}`,
+			mockFn: func(m *MockPricingUseCase) {
+				m.On("SavePolicy", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidPricingPolicy)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockPricingUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupPricingTestRouter(NewPricingHandler(mockUseCase))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/pricing-policies/3/CHF", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPricingHandler_DeletePolicy(t *testing.T) {
+	mockUseCase := &MockPricingUseCase{}
+	mockUseCase.On("DeletePolicy", mock.Anything, int64(3), "CHF").Return(nil)
+	mockUseCase.On("DeletePolicy", mock.Anything, int64(3), "USD").Return(domain.ErrPricingPolicyNotFound)
+
+	router := setupPricingTestRouter(NewPricingHandler(mockUseCase))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/pricing-policies/3/CHF", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/pricing-policies/3/USD", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPricingHandler_QuotePrice(t *testing.T) {
+	mockUseCase := &MockPricingUseCase{}
+	mockUseCase.On("QuotePrice", mock.Anything, int64(42), domain.PriceAdjustment{Currency: "CHF", Rate: 1, DiscountPercent: 10}).Return(&domain.PriceQuote{
+		ProductID: 42, StoreID: 7, BasePrice: 12.4,
+		Adjustment: domain.PriceAdjustment{Currency: "CHF", Rate: 1, DiscountPercent: 10},
+		Policy:     domain.PricingPolicy{Currency: "CHF", Rounding: "increment", Step: 5, Direction: "nearest", Decimals: 2},
+		Unrounded:  1116, Price: 1115,
+	}, nil)
+	mockUseCase.On("QuotePrice", mock.Anything, int64(43), mock.Anything).Return(nil, domain.ErrProductNotFound)
+
+	router := setupPricingTestRouter(NewPricingHandler(mockUseCase))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/42/price?currency=CHF&discount_percent=10", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp dto.PriceQuoteResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 11.15, resp.Price)
+	assert.Equal(t, "CHF 11.15", resp.DisplayPrice)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/43/price?currency=CHF", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/42/price", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
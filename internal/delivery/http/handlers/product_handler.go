@@ -2,28 +2,59 @@ package handlers
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
 	"backend-context-engineering-template/internal/domain"
 	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"backend-context-engineering-template/pkg/pagination"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
+	"github.com/gin-gonic/gin/binding"
 )
 
+const (
+	streamFlushEvery = 100
+	// streamTimeout bounds a full-catalog stream so a slow reader cannot
+	// hold a database connection indefinitely.
+	streamTimeout = 5 * time.Minute
+
+	// importTimeout bounds a product import, which saves its rows one by
+	// one.
+	importTimeout = 5 * time.Minute
+	// maxProductImportBytes caps the CSV file of a product import.
+	maxProductImportBytes = 32 << 20
+
+	// confirmMassOperationHeader lets a caller push deletes through while the
+	// store's delete rate is flagged as anomalous.
+	confirmMassOperationHeader = "X-Confirm-Mass-Operation"
+)
+
+// productSorts are the orders GET /products lists in, newest first unless
+// asked otherwise.
+var productSorts = pagination.SortSpec{domain.ProductSortNewest, domain.ProductSortPopularity}
+
 type ProductHandler struct {
 	productUseCase usecase.ProductUseCaseInterface
-	logger         *logrus.Logger
+	previews       usecase.PreviewUseCaseInterface
+	clock          clock.Clock
 }
 
-func NewProductHandler(productUseCase usecase.ProductUseCaseInterface, logger *logrus.Logger) *ProductHandler {
+// NewProductHandler serves preview links when previews is set.
+func NewProductHandler(productUseCase usecase.ProductUseCaseInterface, previews usecase.PreviewUseCaseInterface, clk clock.Clock) *ProductHandler {
 	return &ProductHandler{
 		productUseCase: productUseCase,
-		logger:         logger,
+		previews:       previews,
+		clock:          clk,
 	}
 }
 
@@ -33,25 +64,219 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 
 	var req dto.CreateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.WithError(err).Error("Failed to bind create product request")
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: err.Error(),
-		})
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind create product request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
 		return
 	}
 
 	product := req.ToDomain()
+	middleware.SetStoreID(c, product.StoreID)
 	createdProduct, err := h.productUseCase.CreateProduct(ctx, product)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	response := dto.ToProductResponse(createdProduct)
+	setVersionETag(c, createdProduct)
+	response := dto.ToProductResponse(createdProduct, h.clock.Now())
 	c.JSON(http.StatusCreated, response)
 }
 
+// WriteProducts creates and replaces up to domain.MaxBulkProducts products
+// in one transaction. If any item is refused, none is saved and the
+// response lists the refused items by index.
+func (h *ProductHandler) WriteProducts(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req dto.BulkProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind bulk products request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+	if len(req.Products) == 0 || len(req.Products) > domain.MaxBulkProducts {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "products must hold 1 to " + strconv.Itoa(domain.MaxBulkProducts) + " items",
+		})
+		return
+	}
+
+	var rejected []dto.BulkProductResult
+	items := make([]domain.BulkProductItem, len(req.Products))
+	for i := range req.Products {
+		if err := binding.Validator.ValidateStruct(&req.Products[i]); err != nil {
+			rejected = append(rejected, dto.RejectedBulkItem(i, dto.ValidationErrorResponse(err)))
+			continue
+		}
+		items[i] = req.Products[i].ToDomain()
+	}
+	if len(rejected) > 0 {
+		h.rejectBulkWrite(c, rejected)
+		return
+	}
+
+	results, err := h.productUseCase.WriteProducts(ctx, items)
+	var refused *domain.BulkWriteError
+	if errors.As(err, &refused) {
+		for _, item := range refused.Items {
+			rejected = append(rejected, dto.RejectedBulkItem(item.Index, bulkItemErrorResponse(item.Err)))
+		}
+		h.rejectBulkWrite(c, rejected)
+		return
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToBulkProductsResponse(results, h.clock.Now()))
+}
+
+func (h *ProductHandler) rejectBulkWrite(c *gin.Context, rejected []dto.BulkProductResult) {
+	c.JSON(http.StatusUnprocessableEntity, dto.BulkProductsErrorResponse{
+		Error:   "bulk_write_rejected",
+		Message: "Some products were rejected, so none were saved",
+		Results: rejected,
+	})
+}
+
+// bulkItemErrorResponse describes why an item of a bulk write was refused,
+// with the codes the single-product endpoints use.
+func bulkItemErrorResponse(err error) dto.ErrorResponse {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound):
+		return dto.ErrorResponse{Error: "product_not_found", Message: "Product not found"}
+	case errors.Is(err, domain.ErrDuplicateProduct):
+		return dto.ErrorResponse{Error: "duplicate_product", Message: "Product with this name already exists"}
+	case errors.Is(err, domain.ErrStoreNotFound):
+		return dto.ErrorResponse{Error: "store_not_found", Message: "The product's store does not exist"}
+	case errors.Is(err, domain.ErrContentRejected):
+		return dto.ErrorResponse{Error: "content_rejected", Message: err.Error()}
+	default:
+		return dto.ErrorResponse{Error: "invalid_product", Message: err.Error(), Fields: dto.FieldErrors(err)}
+	}
+}
+
+// ImportProducts reads the CSV file uploaded as the "file" field of a
+// multipart form and saves its rows. The file is parsed as it arrives
+// rather than buffered, and the report lists the rows that were not saved.
+func (h *ProductHandler) ImportProducts(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), importTimeout)
+	defer cancel()
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxProductImportBytes)
+	parts, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "The CSV file must be uploaded as multipart/form-data",
+		})
+		return
+	}
+
+	for {
+		part, err := parts.NextPart()
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				h.handleError(c, err)
+				return
+			}
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "The form has no file field",
+			})
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		report, err := h.productUseCase.ImportProducts(ctx, part)
+		part.Close()
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.ToProductImportResponse(report))
+		return
+	}
+}
+
+// ExportProducts streams the listed catalog as a CSV file that
+// ImportProducts reads back. csv is the only format, and the default.
+// Like the JSON stream, an error after the first row truncates the file.
+func (h *ProductHandler) ExportProducts(c *gin.Context) {
+	if format := c.DefaultQuery("format", "csv"); format != "csv" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "format must be csv",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), streamTimeout)
+	defer cancel()
+
+	now := h.clock.Now()
+	writer := csv.NewWriter(c.Writer)
+	started := false
+	start := func() error {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="products-`+now.UTC().Format("2006-01-02")+`.csv"`)
+		c.Status(http.StatusOK)
+		started = true
+		return writer.Write(domain.ProductCSVColumns)
+	}
+
+	count := 0
+	err := h.productUseCase.StreamProducts(ctx, func(product *domain.Product) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(product.CSVRecord()); err != nil {
+			return err
+		}
+
+		count++
+		if count%streamFlushEvery == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+
+	if err != nil {
+		if !started {
+			h.handleError(c, err)
+			return
+		}
+		writer.Flush()
+		logger.FromContext(ctx).WithError(err).WithField("exported", count).Error("Product export aborted")
+		return
+	}
+
+	if !started {
+		if err := start(); err != nil {
+			logger.FromContext(ctx).WithError(err).Error("Failed to write product export")
+			return
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		logger.FromContext(ctx).WithError(err).WithField("exported", count).Error("Product export aborted")
+	}
+}
+
 func (h *ProductHandler) GetProduct(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
@@ -66,42 +291,170 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := h.productUseCase.GetProduct(ctx, id)
+	var product *domain.Product
+	token := c.Query("preview_token")
+	previewed := token != "" && h.previews != nil
+	if previewed {
+		// A preview shows a draft to whoever holds the link; it must not
+		// be cached where others could be served it.
+		middleware.NoStore(c)
+		product, err = h.previews.GetPreview(ctx, id, token)
+	} else {
+		product, err = h.productUseCase.GetProduct(ctx, id)
+	}
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
+	middleware.SetStoreID(c, product.StoreID)
 
-	response := dto.ToProductResponse(product)
+	// Drafts are shown only to their store's owners and through preview
+	// links; to anyone else they do not exist.
+	if product.Status == domain.ProductStatusDraft && !previewed && !middleware.OwnsStore(c, product.StoreID) {
+		h.handleError(c, domain.ErrProductNotFound)
+		return
+	}
+
+	setVersionETag(c, product)
+	response := dto.ToProductResponse(product, h.clock.Now())
+	if renderHTML(c) {
+		response.RenderDescription()
+	}
 	c.JSON(http.StatusOK, response)
 }
 
 func (h *ProductHandler) GetProducts(c *gin.Context) {
+	if c.Query("stream") == "true" {
+		// The stream is the whole catalog in id order; rather than
+		// silently ignore list parameters, refuse them.
+		var query dto.ProductListQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+			return
+		}
+		filter := query.ToDomain()
+		if !filter.IsEmpty() || c.Query("availability") != "" || c.Query("sort") != "" {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "stream cannot be combined with filters, availability or sort",
+			})
+			return
+		}
+		h.streamProducts(c)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
-	limit := 10
-	if limitParam := c.Query("limit"); limitParam != "" {
-		if l, err := strconv.Atoi(limitParam); err == nil && l > 0 {
-			limit = l
+	page, ok := bindPage(c, productSorts)
+	if !ok {
+		return
+	}
+
+	var query dto.ProductListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+	filter := query.ToDomain()
+	filter.Sort = page.Sort
+	if filter.StoreID != nil {
+		middleware.SetStoreID(c, *filter.StoreID)
+	}
+
+	var products []*domain.Product
+	var err error
+	if availability := c.Query("availability"); availability != "" {
+		if !filter.IsEmpty() {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "availability cannot be combined with other filters or sort",
+			})
+			return
 		}
+		products, err = h.productUseCase.GetProductsByAvailability(ctx, availability, page.Limit, page.Offset)
+	} else {
+		products, err = h.productUseCase.GetProducts(ctx, filter, page.Limit, page.Offset)
 	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	listed := make([]int64, len(products))
+	for i, product := range products {
+		listed[i] = product.ID
+	}
+	middleware.SetListedProducts(c, listed)
 
-	offset := 0
-	if offsetParam := c.Query("offset"); offsetParam != "" {
-		if o, err := strconv.Atoi(offsetParam); err == nil && o >= 0 {
-			offset = o
+	response := dto.ToProductListResponse(products, page, h.clock.Now())
+	if renderHTML(c) {
+		for i := range response.Products {
+			response.Products[i].RenderDescription()
 		}
 	}
+	writeList(c, response, response.Products, page)
+}
+
+// streamProducts writes every product as one JSON array, encoding element by
+// element and flushing periodically. Errors before the first element produce
+// a normal error response; later errors can only truncate the array, which
+// clients detect as invalid JSON.
+func (h *ProductHandler) streamProducts(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), streamTimeout)
+	defer cancel()
+
+	render := renderHTML(c)
+	now := h.clock.Now()
+	started := false
+	count := 0
+
+	err := h.productUseCase.StreamProducts(ctx, func(product *domain.Product) error {
+		response := dto.ToProductResponse(product, now)
+		if render {
+			response.RenderDescription()
+		}
+
+		data, err := json.Marshal(response)
+		if err != nil {
+			return err
+		}
+
+		if !started {
+			c.Header("Content-Type", "application/json; charset=utf-8")
+			c.Status(http.StatusOK)
+			c.Writer.WriteString("[")
+			started = true
+		} else {
+			c.Writer.WriteString(",")
+		}
+		if _, err := c.Writer.Write(data); err != nil {
+			return err
+		}
+
+		count++
+		if count%streamFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
 
-	products, err := h.productUseCase.GetProducts(ctx, limit, offset)
 	if err != nil {
-		h.handleError(c, err)
+		if !started {
+			h.handleError(c, err)
+			return
+		}
+		logger.FromContext(ctx).WithError(err).WithField("streamed", count).Error("Product stream aborted")
 		return
 	}
 
-	response := dto.ToProductListResponse(products, limit, offset)
-	c.JSON(http.StatusOK, response)
+	if !started {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+		c.Status(http.StatusOK)
+		c.Writer.WriteString("[")
+	}
+	c.Writer.WriteString("]")
 }
 
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
@@ -120,22 +473,62 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 
 	var req dto.UpdateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.WithError(err).Error("Failed to bind update product request")
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "validation_error",
-			Message: err.Error(),
-		})
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind update product request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+	version, ok := expectedVersion(c, req.Version)
+	if !ok {
 		return
 	}
 
 	product := req.ToDomain()
+	product.Version = version
+	middleware.SetStoreID(c, product.StoreID)
 	updatedProduct, err := h.productUseCase.UpdateProduct(ctx, id, product)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	response := dto.ToProductResponse(updatedProduct)
+	setVersionETag(c, updatedProduct)
+	response := dto.ToProductResponse(updatedProduct, h.clock.Now())
+	c.JSON(http.StatusOK, response)
+}
+
+// PatchProduct updates only the fields in the request body, unlike
+// UpdateProduct which replaces the product.
+func (h *ProductHandler) PatchProduct(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Product")
+	if !ok {
+		return
+	}
+
+	var req dto.PatchProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind patch product request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+	version, ok := expectedVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	patch := req.ToDomain()
+	patch.Version = version
+	updatedProduct, err := h.productUseCase.UpdateProductPartial(ctx, id, patch)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	middleware.SetStoreID(c, updatedProduct.StoreID)
+
+	setVersionETag(c, updatedProduct)
+	response := dto.ToProductResponse(updatedProduct, h.clock.Now())
 	c.JSON(http.StatusOK, response)
 }
 
@@ -153,6 +546,10 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 		return
 	}
 
+	if c.GetHeader(confirmMassOperationHeader) == "true" {
+		ctx = usecase.WithMassOperationConfirmed(ctx)
+	}
+
 	if err := h.productUseCase.DeleteProduct(ctx, id); err != nil {
 		h.handleError(c, err)
 		return
@@ -172,17 +569,105 @@ func (h *ProductHandler) handleError(c *gin.Context, err error) {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error:   "invalid_product",
 			Message: err.Error(),
+			Fields:  dto.FieldErrors(err),
 		})
 	case errors.Is(err, domain.ErrDuplicateProduct):
 		c.JSON(http.StatusConflict, dto.ErrorResponse{
 			Error:   "duplicate_product",
 			Message: "Product with this name already exists",
 		})
+	case errors.Is(err, domain.ErrConflict):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "version_conflict",
+			Message: "The product was changed since this version; fetch it again and reapply your changes",
+		})
+	case errors.Is(err, domain.ErrStoreNotFound):
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error:   "store_not_found",
+			Message: "The product's store does not exist",
+		})
+	case errors.Is(err, domain.ErrProductInBundle):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "product_in_bundle",
+			Message: "Product is a component of a bundle; remove it from the bundle first",
+		})
+	case errors.Is(err, domain.ErrContentRejected):
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error:   "content_rejected",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrConfirmationRequired):
+		c.JSON(http.StatusPreconditionRequired, dto.ErrorResponse{
+			Error:   "confirmation_required",
+			Message: err.Error() + "; retry with " + confirmMassOperationHeader + ": true",
+		})
+	case errors.Is(err, domain.ErrInvalidProductImport):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_product_import",
+			Message: err.Error(),
+		})
+	case errors.As(err, new(*http.MaxBytesError)):
+		c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+			Error:   "file_too_large",
+			Message: "The file must not exceed 32 MB",
+		})
+	case errors.Is(err, domain.ErrInvalidPreviewToken):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{
+			Error:   "invalid_preview_token",
+			Message: "The preview link is invalid or has expired",
+		})
 	default:
-		h.logger.WithError(err).Error("Internal server error")
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "internal_server_error",
 			Message: "An internal error occurred",
 		})
 	}
 }
+
+// expectedVersion returns the product version an update was made against,
+// from If-Match or the body's version field. If-Match: * updates whatever
+// the current version is. Without either the update is refused, so that
+// no client overwrites a change it has not seen.
+func expectedVersion(c *gin.Context, body *int64) (int64, bool) {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		if body == nil {
+			c.JSON(http.StatusPreconditionRequired, dto.ErrorResponse{
+				Error:   "version_required",
+				Message: "Send the version of the product being updated in If-Match or the version field",
+			})
+			return 0, false
+		}
+		return *body, true
+	}
+
+	if ifMatch == "*" {
+		return 0, true
+	}
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+	if err != nil || version <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_if_match",
+			Message: "If-Match must be the product's ETag",
+		})
+		return 0, false
+	}
+	if body != nil && *body != version {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_if_match",
+			Message: "If-Match and the version field name different versions",
+		})
+		return 0, false
+	}
+	return version, true
+}
+
+// setVersionETag sends the product's version as its ETag, for If-Match.
+func setVersionETag(c *gin.Context, product *domain.Product) {
+	c.Header("ETag", `"`+strconv.FormatInt(product.Version, 10)+`"`)
+}
+
+func renderHTML(c *gin.Context) bool {
+	return c.Query("render") == "html"
+}
@@ -5,16 +5,28 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
 	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/pagination"
+	"backend-context-engineering-template/pkg/quantity"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockProductUseCase struct {
@@ -37,11 +49,29 @@ func (m *MockProductUseCase) GetProduct(ctx context.Context, id int64) (*domain.
 	return args.Get(0).(*domain.Product), args.Error(1)
 }
 
-func (m *MockProductUseCase) GetProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockProductUseCase) GetProducts(ctx context.Context, filter domain.ProductFilter, limit, offset int) ([]*domain.Product, error) {
+	args := m.Called(ctx, filter, limit, offset)
 	return args.Get(0).([]*domain.Product), args.Error(1)
 }
 
+func (m *MockProductUseCase) GetProductsByAvailability(ctx context.Context, availability string, limit, offset int) ([]*domain.Product, error) {
+	args := m.Called(ctx, availability, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func (m *MockProductUseCase) StreamProducts(ctx context.Context, fn func(*domain.Product) error) error {
+	args := m.Called(ctx)
+	for _, product := range args.Get(0).([]*domain.Product) {
+		if err := fn(product); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
 func (m *MockProductUseCase) UpdateProduct(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
 	args := m.Called(ctx, id, product)
 	if args.Get(0) == nil {
@@ -50,11 +80,35 @@ func (m *MockProductUseCase) UpdateProduct(ctx context.Context, id int64, produc
 	return args.Get(0).(*domain.Product), args.Error(1)
 }
 
+func (m *MockProductUseCase) UpdateProductPartial(ctx context.Context, id int64, patch *domain.ProductPatch) (*domain.Product, error) {
+	args := m.Called(ctx, id, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
 func (m *MockProductUseCase) DeleteProduct(ctx context.Context, id int64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockProductUseCase) WriteProducts(ctx context.Context, items []domain.BulkProductItem) ([]*domain.BulkProductResult, error) {
+	args := m.Called(ctx, items)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.BulkProductResult), args.Error(1)
+}
+
+func (m *MockProductUseCase) ImportProducts(ctx context.Context, file io.Reader) (*domain.ProductImportReport, error) {
+	args := m.Called(ctx, file)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProductImportReport), args.Error(1)
+}
+
 func setupTestRouter(handler *ProductHandler) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -63,9 +117,13 @@ func setupTestRouter(handler *ProductHandler) *gin.Engine {
 	products := api.Group("/products")
 	{
 		products.POST("", handler.CreateProduct)
+		products.POST("/bulk", handler.WriteProducts)
+		products.POST("/import", handler.ImportProducts)
+		products.GET("/export", handler.ExportProducts)
 		products.GET("/:id", handler.GetProduct)
 		products.GET("", handler.GetProducts)
 		products.PUT("/:id", handler.UpdateProduct)
+		products.PATCH("/:id", handler.PatchProduct)
 		products.DELETE("/:id", handler.DeleteProduct)
 	}
 
@@ -73,7 +131,6 @@ func setupTestRouter(handler *ProductHandler) *gin.Engine {
 }
 
 func TestProductHandler_CreateProduct(t *testing.T) {
-	logger := logrus.New()
 
 	tests := []struct {
 		name         string
@@ -97,7 +154,7 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 						StoreID:     1,
 						Name:        "Test Product",
 						Description: sql.NullString{String: "Test Description", Valid: true},
-						Amount:      10,
+						Amount:      quantity.New(10),
 						Price:       29.99,
 					}, nil)
 			},
@@ -120,6 +177,69 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 			mockFn:       func(m *MockProductUseCase) {},
 			expectedCode: http.StatusBadRequest,
 		},
+		{
+			name: "weighed product",
+			requestBody: map[string]interface{}{
+				"store_id": 1,
+				"name":     "Rye Flour",
+				"amount":   2.375,
+				"unit":     "kg",
+				"price":    3.2,
+			},
+			mockFn: func(m *MockProductUseCase) {
+				m.On("CreateProduct", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+					return p.Amount == quantity.MustParse("2.375") && p.Unit == domain.UnitKilogram
+				})).Return(&domain.Product{ID: 2, StoreID: 1, Name: "Rye Flour", Amount: quantity.MustParse("2.375"), Unit: domain.UnitKilogram, Price: 3.2}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "unknown store",
+			requestBody: map[string]interface{}{
+				"store_id": 404,
+				"name":     "Test Product",
+				"amount":   1,
+				"price":    29.99,
+			},
+			mockFn: func(m *MockProductUseCase) {
+				m.On("CreateProduct", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to create product: %w", domain.ErrStoreNotFound))
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "validation error - missing amount",
+			requestBody: map[string]interface{}{
+				"store_id": 1,
+				"name":     "Test Product",
+				"price":    29.99,
+			},
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "validation error - amount finer than a thousandth",
+			requestBody: map[string]interface{}{
+				"store_id": 1,
+				"name":     "Test Product",
+				"amount":   0.0005,
+				"unit":     "kg",
+				"price":    29.99,
+			},
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "validation error - unknown unit",
+			requestBody: map[string]interface{}{
+				"store_id": 1,
+				"name":     "Test Product",
+				"amount":   1,
+				"unit":     "gallon",
+				"price":    29.99,
+			},
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
 		{
 			name: "domain error",
 			requestBody: map[string]interface{}{
@@ -135,6 +255,20 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 			},
 			expectedCode: http.StatusBadRequest,
 		},
+		{
+			name: "content rejected by moderation",
+			requestBody: map[string]interface{}{
+				"store_id": 1,
+				"name":     "Blocked Product",
+				"amount":   10,
+				"price":    29.99,
+			},
+			mockFn: func(m *MockProductUseCase) {
+				m.On("CreateProduct", mock.Anything, mock.Anything).Return(
+					(*domain.Product)(nil), domain.ErrContentRejected)
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
 	}
 
 	for _, tt := range tests {
@@ -142,7 +276,7 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 			mockUseCase := &MockProductUseCase{}
 			tt.mockFn(mockUseCase)
 
-			handler := NewProductHandler(mockUseCase, logger)
+			handler := NewProductHandler(mockUseCase, nil, clock.Real())
 			router := setupTestRouter(handler)
 
 			var body []byte
@@ -164,8 +298,85 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 	}
 }
 
+func TestProductHandler_CreateProduct_FieldErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		mockFn       func(*MockProductUseCase)
+		expectedCode int
+		expected     dto.ErrorResponse
+	}{
+		{
+			name:         "binding tags",
+			body:         `{"name":"","amount":1,"price":-1,"unit":"gallon"}`,
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expected: dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "store_id is required; name is required; unit must be one of piece, kg, liter; price must be at least 0",
+				Fields: []dto.FieldError{
+					{Field: "store_id", Rule: "required", Message: "store_id is required"},
+					{Field: "name", Rule: "required", Message: "name is required"},
+					{Field: "unit", Rule: "oneof", Message: "unit must be one of piece, kg, liter"},
+					{Field: "price", Rule: "min", Message: "price must be at least 0"},
+				},
+			},
+		},
+		{
+			name:         "wrong type",
+			body:         `{"store_id":1,"name":"Tea","amount":1,"price":"cheap"}`,
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expected: dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "price must be a number",
+				Fields:  []dto.FieldError{{Field: "price", Rule: "type", Message: "price must be a number"}},
+			},
+		},
+		{
+			name: "domain validation",
+			body: `{"store_id":1,"name":"Tea","amount":1,"price":2,"allow_backorder":false,"backorder_limit":5}`,
+			mockFn: func(m *MockProductUseCase) {
+				product := &domain.Product{StoreID: 1, Name: "Tea", Price: 2, BackorderLimit: quantity.New(5)}
+				m.On("CreateProduct", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: %w", domain.ErrInvalidProduct, product.Validate()))
+			},
+			expectedCode: http.StatusBadRequest,
+			expected: dto.ErrorResponse{
+				Error:   "invalid_product",
+				Message: "invalid product data: backorder_limit requires allow_backorder",
+				Fields:  []dto.FieldError{{Field: "backorder_limit", Rule: "requires", Message: "backorder_limit requires allow_backorder"}},
+			},
+		},
+		{
+			name:         "malformed JSON names no field",
+			body:         `{"store_id":`,
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expected:     dto.ErrorResponse{Error: "validation_error", Message: "unexpected EOF"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockProductUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupTestRouter(NewProductHandler(mockUseCase, nil, clock.Real()))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			var resp dto.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.expected, resp)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
 func TestProductHandler_GetProduct(t *testing.T) {
-	logger := logrus.New()
 
 	tests := []struct {
 		name         string
@@ -182,7 +393,7 @@ func TestProductHandler_GetProduct(t *testing.T) {
 						ID:      1,
 						StoreID: 1,
 						Name:    "Test Product",
-						Amount:  10,
+						Amount:  quantity.New(10),
 						Price:   29.99,
 					}, nil)
 			},
@@ -210,7 +421,7 @@ func TestProductHandler_GetProduct(t *testing.T) {
 			mockUseCase := &MockProductUseCase{}
 			tt.mockFn(mockUseCase)
 
-			handler := NewProductHandler(mockUseCase, logger)
+			handler := NewProductHandler(mockUseCase, nil, clock.Real())
 			router := setupTestRouter(handler)
 
 			req := httptest.NewRequest(http.MethodGet, "/api/v1/products/"+tt.id, nil)
@@ -225,21 +436,21 @@ func TestProductHandler_GetProduct(t *testing.T) {
 }
 
 func TestProductHandler_GetProducts(t *testing.T) {
-	logger := logrus.New()
 
 	tests := []struct {
 		name         string
 		query        string
 		mockFn       func(*MockProductUseCase)
 		expectedCode int
+		expectedBody string
 	}{
 		{
 			name:  "successful retrieval",
 			query: "",
 			mockFn: func(m *MockProductUseCase) {
-				m.On("GetProducts", mock.Anything, 10, 0).Return(
+				m.On("GetProducts", mock.Anything, domain.ProductFilter{}, 10, 0).Return(
 					[]*domain.Product{
-						{ID: 1, Name: "Product 1", StoreID: 1, Amount: 5, Price: 19.99},
+						{ID: 1, Name: "Product 1", StoreID: 1, Amount: quantity.New(5), Price: 19.99},
 					}, nil)
 			},
 			expectedCode: http.StatusOK,
@@ -248,11 +459,123 @@ func TestProductHandler_GetProducts(t *testing.T) {
 			name:  "with pagination",
 			query: "?limit=5&offset=10",
 			mockFn: func(m *MockProductUseCase) {
-				m.On("GetProducts", mock.Anything, 5, 10).Return(
+				m.On("GetProducts", mock.Anything, domain.ProductFilter{}, 5, 10).Return(
 					[]*domain.Product{}, nil)
 			},
 			expectedCode: http.StatusOK,
 		},
+		{
+			name:  "render markdown descriptions as html",
+			query: "?render=html",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("GetProducts", mock.Anything, domain.ProductFilter{}, 10, 0).Return(
+					[]*domain.Product{
+						{ID: 1, Name: "Product 1", StoreID: 1, Price: 19.99,
+							Description:       sql.NullString{String: "**Soft** <b>cotton</b>", Valid: true},
+							DescriptionFormat: domain.DescriptionFormatMarkdown},
+					}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"description_html":"\u003cp\u003e\u003cstrong\u003eSoft\u003c/strong\u003e \u0026lt;b\u0026gt;cotton\u0026lt;/b\u0026gt;\u003c/p\u003e"`,
+		},
+		{
+			name:  "filter by availability",
+			query: "?availability=low_stock",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("GetProductsByAvailability", mock.Anything, domain.AvailabilityLowStock, 10, 0).Return(
+					[]*domain.Product{
+						{ID: 1, Name: "Product 1", StoreID: 1, Amount: quantity.New(2), LowStockThreshold: quantity.New(5), Price: 19.99},
+					}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"availability":"low_stock"`,
+		},
+		{
+			name:  "search and filter",
+			query: "?name=shirt&store_id=3&min_price=10&max_price=25.5&in_stock=true",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter domain.ProductFilter) bool {
+					return filter.Name == "shirt" && *filter.StoreID == 3 && *filter.MinPrice == 10 &&
+						*filter.MaxPrice == 25.5 && *filter.InStock
+				}), 10, 0).Return([]*domain.Product{}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "sort by popularity",
+			query: "?sort=popularity&store_id=3",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter domain.ProductFilter) bool {
+					return *filter.StoreID == 3 && filter.Sort == domain.ProductSortPopularity
+				}), 10, 0).Return([]*domain.Product{}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "filter by category",
+			query: "?category_id=4",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("GetProducts", mock.Anything, mock.MatchedBy(func(filter domain.ProductFilter) bool {
+					return filter.CategoryID != nil && *filter.CategoryID == 4
+				}), 10, 0).Return([]*domain.Product{}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid category",
+			query:        "?category_id=0",
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "validation_error",
+		},
+		{
+			name:  "next page from a cursor",
+			query: "?limit=1&cursor=" + pagination.Cursor{Offset: 5, Sort: domain.ProductSortPopularity}.Encode(),
+			mockFn: func(m *MockProductUseCase) {
+				m.On("GetProducts", mock.Anything, domain.ProductFilter{Sort: domain.ProductSortPopularity}, 1, 5).Return(
+					[]*domain.Product{{ID: 6, Name: "Product 6", StoreID: 1, Price: 19.99}}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"next_cursor":"` + pagination.Cursor{Offset: 6, Sort: domain.ProductSortPopularity}.Encode() + `"`,
+		},
+		{
+			name:         "cursor with another sort",
+			query:        "?sort=newest&cursor=" + pagination.Cursor{Offset: 5, Sort: domain.ProductSortPopularity}.Encode(),
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "validation_error",
+		},
+		{
+			name:         "unknown sort",
+			query:        "?sort=price",
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "validation_error",
+		},
+		{
+			name:         "malformed price",
+			query:        "?min_price=cheap",
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "validation_error",
+		},
+		{
+			name:         "availability with a filter",
+			query:        "?availability=low_stock&name=shirt",
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "availability cannot be combined",
+		},
+		{
+			name:  "unknown availability",
+			query: "?availability=sold",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("GetProductsByAvailability", mock.Anything, "sold", 10, 0).Return(
+					nil, fmt.Errorf("%w: unknown availability %q", domain.ErrInvalidProduct, "sold"))
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "invalid_product",
+		},
 	}
 
 	for _, tt := range tests {
@@ -260,7 +583,7 @@ func TestProductHandler_GetProducts(t *testing.T) {
 			mockUseCase := &MockProductUseCase{}
 			tt.mockFn(mockUseCase)
 
-			handler := NewProductHandler(mockUseCase, logger)
+			handler := NewProductHandler(mockUseCase, nil, clock.Real())
 			router := setupTestRouter(handler)
 
 			req := httptest.NewRequest(http.MethodGet, "/api/v1/products"+tt.query, nil)
@@ -269,43 +592,236 @@ func TestProductHandler_GetProducts(t *testing.T) {
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
 			mockUseCase.AssertExpectations(t)
 		})
 	}
 }
 
+func TestProductHandler_GetProducts_ListEnvelope(t *testing.T) {
+	mockUseCase := &MockProductUseCase{}
+	mockUseCase.On("GetProducts", mock.Anything, domain.ProductFilter{}, 2, 0).Return([]*domain.Product{
+		{ID: 1, Name: "Product 1", StoreID: 1, Price: 19.99},
+		{ID: 2, Name: "Product 2", StoreID: 1, Price: 9.99},
+	}, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ListEnvelope(true))
+	router.GET("/api/v1/products", NewProductHandler(mockUseCase, nil, clock.Real()).GetProducts)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products?limit=2", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response dto.ListResponse[dto.ProductResponse]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, "Product 2", response.Data[1].Name)
+	cursor := pagination.Cursor{Offset: 2}.Encode()
+	assert.Equal(t, dto.ListMeta{Count: 2, Limit: 2, Cursor: cursor}, response.Meta)
+	assert.Equal(t, dto.ListLinks{Self: "/api/v1/products?limit=2", Next: "/api/v1/products?cursor=" + cursor + "&limit=2"}, response.Links)
+}
+
+func TestProductHandler_GetProducts_OversizedLimit(t *testing.T) {
+	products := make([]*domain.Product, pagination.MaxLimit)
+	for i := range products {
+		products[i] = &domain.Product{ID: int64(i + 1), StoreID: 1}
+	}
+	mockUseCase := &MockProductUseCase{}
+	mockUseCase.On("GetProducts", mock.Anything, domain.ProductFilter{}, pagination.MaxLimit, 0).Return(products, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ListEnvelope(true))
+	router.GET("/api/v1/products", NewProductHandler(mockUseCase, nil, clock.Real()).GetProducts)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products?limit=500", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response dto.ListResponse[dto.ProductResponse]
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, pagination.MaxLimit, response.Meta.Limit)
+	assert.Equal(t, pagination.Cursor{Offset: pagination.MaxLimit}.Encode(), response.Meta.Cursor)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestProductHandler_StreamProducts(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		mockFn       func(*MockProductUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "streams a json array",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("StreamProducts", mock.Anything).Return(
+					[]*domain.Product{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "empty catalog",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("StreamProducts", mock.Anything).Return([]*domain.Product{}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: "[]",
+		},
+		{
+			name: "error before first element",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("StreamProducts", mock.Anything).Return([]*domain.Product{}, errors.New("database error"))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockProductUseCase{}
+			tt.mockFn(mockUseCase)
+
+			handler := NewProductHandler(mockUseCase, nil, clock.Real())
+			router := setupTestRouter(handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products?stream=true", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedCode == http.StatusOK {
+				var products []map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &products))
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_StreamProducts_RejectsListParameters(t *testing.T) {
+	mockUseCase := &MockProductUseCase{}
+	router := setupTestRouter(NewProductHandler(mockUseCase, nil, clock.Real()))
+
+	for _, query := range []string{"store_id=1", "category_id=2", "name=lamp", "in_stock=true", "availability=in_stock", "sort=popularity", "store_id=abc"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products?stream=true&"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockUseCase.AssertNotCalled(t, "StreamProducts", mock.Anything)
+}
+
 func TestProductHandler_UpdateProduct(t *testing.T) {
-	logger := logrus.New()
+	body := map[string]interface{}{
+		"store_id":    1,
+		"name":        "Updated Product",
+		"description": "Updated Description",
+		"amount":      15,
+		"price":       39.99,
+	}
+	withVersion := func(version interface{}) map[string]interface{} {
+		versioned := map[string]interface{}{"version": version}
+		for k, v := range body {
+			versioned[k] = v
+		}
+		return versioned
+	}
+	updated := &domain.Product{
+		ID:          1,
+		StoreID:     1,
+		Name:        "Updated Product",
+		Description: sql.NullString{String: "Updated Description", Valid: true},
+		Amount:      quantity.New(15),
+		Price:       39.99,
+		Version:     4,
+	}
+	atVersion := func(version int64) interface{} {
+		return mock.MatchedBy(func(product *domain.Product) bool { return product.Version == version })
+	}
 
 	tests := []struct {
 		name         string
 		id           string
+		ifMatch      string
 		requestBody  interface{}
 		mockFn       func(*MockProductUseCase)
 		expectedCode int
+		expectedETag string
 	}{
 		{
-			name: "successful update",
-			id:   "1",
-			requestBody: map[string]interface{}{
-				"store_id":    1,
-				"name":        "Updated Product",
-				"description": "Updated Description",
-				"amount":      15,
-				"price":       39.99,
+			name:        "successful update",
+			id:          "1",
+			ifMatch:     `"3"`,
+			requestBody: body,
+			mockFn: func(m *MockProductUseCase) {
+				m.On("UpdateProduct", mock.Anything, int64(1), atVersion(3)).Return(updated, nil)
 			},
+			expectedCode: http.StatusOK,
+			expectedETag: `"4"`,
+		},
+		{
+			name:        "version in the body",
+			id:          "1",
+			requestBody: withVersion(3),
 			mockFn: func(m *MockProductUseCase) {
-				m.On("UpdateProduct", mock.Anything, int64(1), mock.Anything).Return(
-					&domain.Product{
-						ID:          1,
-						StoreID:     1,
-						Name:        "Updated Product",
-						Description: sql.NullString{String: "Updated Description", Valid: true},
-						Amount:      15,
-						Price:       39.99,
-					}, nil)
+				m.On("UpdateProduct", mock.Anything, int64(1), atVersion(3)).Return(updated, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedETag: `"4"`,
+		},
+		{
+			name:        "If-Match any version",
+			id:          "1",
+			ifMatch:     "*",
+			requestBody: body,
+			mockFn: func(m *MockProductUseCase) {
+				m.On("UpdateProduct", mock.Anything, int64(1), atVersion(0)).Return(updated, nil)
 			},
 			expectedCode: http.StatusOK,
+			expectedETag: `"4"`,
+		},
+		{
+			name:         "without a version",
+			id:           "1",
+			requestBody:  body,
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:         "malformed If-Match",
+			id:           "1",
+			ifMatch:      `"abc"`,
+			requestBody:  body,
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "If-Match and body disagree",
+			id:           "1",
+			ifMatch:      `"3"`,
+			requestBody:  withVersion(2),
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:        "stale version",
+			id:          "1",
+			ifMatch:     `W/"2"`,
+			requestBody: body,
+			mockFn: func(m *MockProductUseCase) {
+				m.On("UpdateProduct", mock.Anything, int64(1), atVersion(2)).Return(
+					(*domain.Product)(nil), domain.ErrConflict)
+			},
+			expectedCode: http.StatusConflict,
 		},
 		{
 			name:         "invalid ID",
@@ -315,15 +831,10 @@ func TestProductHandler_UpdateProduct(t *testing.T) {
 			expectedCode: http.StatusBadRequest,
 		},
 		{
-			name: "product not found",
-			id:   "999",
-			requestBody: map[string]interface{}{
-				"store_id":    1,
-				"name":        "Updated Product",
-				"description": "Updated Description",
-				"amount":      15,
-				"price":       39.99,
-			},
+			name:        "product not found",
+			id:          "999",
+			ifMatch:     `"1"`,
+			requestBody: body,
 			mockFn: func(m *MockProductUseCase) {
 				m.On("UpdateProduct", mock.Anything, int64(999), mock.Anything).Return(
 					(*domain.Product)(nil), domain.ErrProductNotFound)
@@ -337,12 +848,246 @@ func TestProductHandler_UpdateProduct(t *testing.T) {
 			mockUseCase := &MockProductUseCase{}
 			tt.mockFn(mockUseCase)
 
-			handler := NewProductHandler(mockUseCase, logger)
+			handler := NewProductHandler(mockUseCase, nil, clock.Real())
 			router := setupTestRouter(handler)
 
 			body, _ := json.Marshal(tt.requestBody)
 			req := httptest.NewRequest(http.MethodPut, "/api/v1/products/"+tt.id, bytes.NewBuffer(body))
 			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedETag, w.Header().Get("ETag"))
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_WriteProducts(t *testing.T) {
+	item := func(name string) map[string]interface{} {
+		return map[string]interface{}{"store_id": 1, "name": name, "amount": 5, "price": 9.99}
+	}
+
+	tests := []struct {
+		name         string
+		requestBody  interface{}
+		mockFn       func(*MockProductUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "creates and updates",
+			requestBody: map[string]interface{}{"products": []interface{}{
+				item("Widget"),
+				map[string]interface{}{"id": 4, "store_id": 1, "name": "Kettle", "amount": 2, "price": 30},
+			}},
+			mockFn: func(m *MockProductUseCase) {
+				m.On("WriteProducts", mock.Anything, mock.MatchedBy(func(items []domain.BulkProductItem) bool {
+					return len(items) == 2 && items[0].ID == 0 && items[0].Product.Name == "Widget" &&
+						items[1].ID == 4 && items[1].Product.Name == "Kettle"
+				})).Return([]*domain.BulkProductResult{
+					{Product: &domain.Product{ID: 9, StoreID: 1, Name: "Widget"}, Created: true},
+					{Product: &domain.Product{ID: 4, StoreID: 1, Name: "Kettle"}},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "invalid items are rejected by index",
+			requestBody: map[string]interface{}{"products": []interface{}{
+				item("Widget"),
+				map[string]interface{}{"store_id": 1, "amount": 5, "price": 9.99},
+				map[string]interface{}{"id": -1, "store_id": 1, "name": "Kettle", "amount": 5, "price": 9.99},
+			}},
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `[{"index":1,"error":"validation_error"},{"index":2,"error":"validation_error"}]`,
+		},
+		{
+			name:        "items refused by the use case",
+			requestBody: map[string]interface{}{"products": []interface{}{item("Widget"), item("Gadget")}},
+			mockFn: func(m *MockProductUseCase) {
+				m.On("WriteProducts", mock.Anything, mock.Anything).Return(nil, &domain.BulkWriteError{Items: []domain.BulkItemError{
+					{Index: 1, Err: domain.ErrContentRejected},
+				}})
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `[{"index":1,"error":"content_rejected"}]`,
+		},
+		{
+			name:         "no products",
+			requestBody:  map[string]interface{}{"products": []interface{}{}},
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:        "missing store",
+			requestBody: map[string]interface{}{"products": []interface{}{item("Widget")}},
+			mockFn: func(m *MockProductUseCase) {
+				m.On("WriteProducts", mock.Anything, mock.Anything).Return(nil, domain.ErrStoreNotFound)
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockProductUseCase{}
+			tt.mockFn(mockUseCase)
+
+			handler := NewProductHandler(mockUseCase, nil, clock.Real())
+			router := setupTestRouter(handler)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/products/bulk", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				var resp dto.BulkProductsErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, "bulk_write_rejected", resp.Error)
+				type rejection struct {
+					Index int    `json:"index"`
+					Error string `json:"error"`
+				}
+				var expected, got []rejection
+				require.NoError(t, json.Unmarshal([]byte(tt.expectedBody), &expected))
+				for _, result := range resp.Results {
+					assert.Equal(t, dto.BulkItemRejected, result.Status)
+					got = append(got, rejection{Index: result.Index, Error: result.Error.Error})
+				}
+				assert.Equal(t, expected, got)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductHandler_PatchProduct(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		id           string
+		ifMatch      string
+		requestBody  string
+		mockFn       func(*MockProductUseCase)
+		expectedCode int
+	}{
+		{
+			name:        "only the price",
+			id:          "1",
+			ifMatch:     `"1"`,
+			requestBody: `{"price": 12.5}`,
+			mockFn: func(m *MockProductUseCase) {
+				m.On("UpdateProductPartial", mock.Anything, int64(1), mock.MatchedBy(func(patch *domain.ProductPatch) bool {
+					return patch.Price != nil && *patch.Price == 12.5 &&
+						patch.Name == nil && patch.Amount == nil && patch.PreorderReleaseDate == nil
+				})).Return(&domain.Product{ID: 1, StoreID: 1, Name: "Widget", Price: 12.5}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:        "null release date clears it",
+			id:          "1",
+			ifMatch:     `"1"`,
+			requestBody: `{"preorder_release_date": null, "description": ""}`,
+			mockFn: func(m *MockProductUseCase) {
+				m.On("UpdateProductPartial", mock.Anything, int64(1), mock.MatchedBy(func(patch *domain.ProductPatch) bool {
+					return patch.PreorderReleaseDate != nil && !patch.PreorderReleaseDate.Valid &&
+						patch.Description != nil && !patch.Description.Valid
+				})).Return(&domain.Product{ID: 1, StoreID: 1, Name: "Widget"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:        "schedules a launch and clears the sunset",
+			id:          "1",
+			requestBody: `{"publish_at": "2024-06-01T09:00:00Z", "unpublish_at": null, "version": 1}`,
+			mockFn: func(m *MockProductUseCase) {
+				m.On("UpdateProductPartial", mock.Anything, int64(1), mock.MatchedBy(func(patch *domain.ProductPatch) bool {
+					return patch.PublishAt != nil && patch.PublishAt.Valid &&
+						patch.PublishAt.Time.Equal(time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)) &&
+						patch.UnpublishAt != nil && !patch.UnpublishAt.Valid && patch.PreorderReleaseDate == nil
+				})).Return(&domain.Product{ID: 1, StoreID: 1, Name: "Widget"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:        "stale version",
+			id:          "1",
+			ifMatch:     `"1"`,
+			requestBody: `{"price": 12.5}`,
+			mockFn: func(m *MockProductUseCase) {
+				m.On("UpdateProductPartial", mock.Anything, int64(1), mock.MatchedBy(func(patch *domain.ProductPatch) bool {
+					return patch.Version == 1
+				})).Return(nil, domain.ErrConflict)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "without a version",
+			id:           "1",
+			requestBody:  `{"price": 12.5}`,
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:         "invalid unit",
+			id:           "1",
+			requestBody:  `{"unit": "gallon"}`,
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid ID",
+			id:           "invalid",
+			requestBody:  `{"price": 12.5}`,
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:        "empty patch",
+			id:          "1",
+			requestBody: `{"version": 2}`,
+			mockFn: func(m *MockProductUseCase) {
+				m.On("UpdateProductPartial", mock.Anything, int64(1), &domain.ProductPatch{Version: 2}).Return(nil, domain.ErrInvalidProduct)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:        "product not found",
+			id:          "999",
+			ifMatch:     `"1"`,
+			requestBody: `{"price": 12.5}`,
+			mockFn: func(m *MockProductUseCase) {
+				m.On("UpdateProductPartial", mock.Anything, int64(999), mock.Anything).Return(nil, domain.ErrProductNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockProductUseCase{}
+			tt.mockFn(mockUseCase)
+
+			handler := NewProductHandler(mockUseCase, nil, clock.Real())
+			router := setupTestRouter(handler)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/products/"+tt.id, strings.NewReader(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
 			w := httptest.NewRecorder()
 
 			router.ServeHTTP(w, req)
@@ -354,11 +1099,11 @@ func TestProductHandler_UpdateProduct(t *testing.T) {
 }
 
 func TestProductHandler_DeleteProduct(t *testing.T) {
-	logger := logrus.New()
 
 	tests := []struct {
 		name         string
 		id           string
+		headers      map[string]string
 		mockFn       func(*MockProductUseCase)
 		expectedCode int
 	}{
@@ -384,6 +1129,23 @@ func TestProductHandler_DeleteProduct(t *testing.T) {
 			},
 			expectedCode: http.StatusNotFound,
 		},
+		{
+			name: "anomalous delete rate needs confirmation",
+			id:   "1",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("DeleteProduct", mock.Anything, int64(1)).Return(domain.ErrConfirmationRequired)
+			},
+			expectedCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:    "confirmation header is passed to the use case",
+			id:      "1",
+			headers: map[string]string{"X-Confirm-Mass-Operation": "true"},
+			mockFn: func(m *MockProductUseCase) {
+				m.On("DeleteProduct", mock.MatchedBy(usecase.MassOperationConfirmed), int64(1)).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
 	}
 
 	for _, tt := range tests {
@@ -391,10 +1153,13 @@ func TestProductHandler_DeleteProduct(t *testing.T) {
 			mockUseCase := &MockProductUseCase{}
 			tt.mockFn(mockUseCase)
 
-			handler := NewProductHandler(mockUseCase, logger)
+			handler := NewProductHandler(mockUseCase, nil, clock.Real())
 			router := setupTestRouter(handler)
 
 			req := httptest.NewRequest(http.MethodDelete, "/api/v1/products/"+tt.id, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
 			w := httptest.NewRecorder()
 
 			router.ServeHTTP(w, req)
@@ -404,3 +1169,162 @@ func TestProductHandler_DeleteProduct(t *testing.T) {
 		})
 	}
 }
+
+func TestProductHandler_ImportProducts(t *testing.T) {
+	csvFile := "store_id,name,amount,price\n7,Widget,1,9.99\n"
+	upload := func(field string) (*bytes.Buffer, string) {
+		body := &bytes.Buffer{}
+		form := multipart.NewWriter(body)
+		form.WriteField("note", "weekly sync")
+		part, _ := form.CreateFormFile(field, "products.csv")
+		part.Write([]byte(csvFile))
+		form.Close()
+		return body, form.FormDataContentType()
+	}
+
+	tests := []struct {
+		name         string
+		field        string
+		mockFn       func(*MockProductUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:  "reports the import",
+			field: "file",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("ImportProducts", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					data, err := io.ReadAll(args.Get(1).(io.Reader))
+					require.NoError(t, err)
+					assert.Equal(t, csvFile, string(data))
+				}).Return(&domain.ProductImportReport{
+					Created: 1, Failed: 1,
+					Errors: []domain.ProductImportError{{Line: 3, Error: "invalid product data: name is required"}},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `"errors":[{"line":3,"error":"invalid product data: name is required"}]`,
+		},
+		{
+			name:         "no file field",
+			field:        "upload",
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "unusable header",
+			field: "file",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("ImportProducts", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: csv header is missing \"price\" column", domain.ErrInvalidProductImport))
+			},
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "invalid_product_import",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockProductUseCase{}
+			tt.mockFn(mockUseCase)
+
+			handler := NewProductHandler(mockUseCase, nil, clock.Real())
+			router := setupTestRouter(handler)
+
+			body, contentType := upload(tt.field)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/products/import", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("refuses a body that is not a form", func(t *testing.T) {
+		handler := NewProductHandler(&MockProductUseCase{}, nil, clock.Real())
+		router := setupTestRouter(handler)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/products/import", strings.NewReader(csvFile))
+		req.Header.Set("Content-Type", "text/csv")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestProductHandler_ExportProducts(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	publishAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		query        string
+		mockFn       func(*MockProductUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "streams a csv file",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("StreamProducts", mock.Anything).Return([]*domain.Product{
+					{ID: 1, StoreID: 7, Name: "Widget, large", DescriptionFormat: domain.DescriptionFormatPlain, Amount: quantity.MustParse("1.5"),
+						Unit: domain.UnitKilogram, Price: 9.99, Status: domain.ProductStatusActive, PublishAt: &publishAt},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: "id,store_id,name,description,description_format,amount,unit,price,status,low_stock_threshold,allow_backorder,backorder_limit,preorder_release_date,publish_at,unpublish_at\n" +
+				"1,7,\"Widget, large\",,plain,1.5,kg,9.99,active,0,false,0,,2026-03-02T09:00:00Z,\n",
+		},
+		{
+			name: "empty catalog",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("StreamProducts", mock.Anything).Return([]*domain.Product{}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: strings.Join(domain.ProductCSVColumns, ",") + "\n",
+		},
+		{
+			name:         "unknown format",
+			query:        "?format=xlsx",
+			mockFn:       func(m *MockProductUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "error before first row",
+			mockFn: func(m *MockProductUseCase) {
+				m.On("StreamProducts", mock.Anything).Return([]*domain.Product{}, errors.New("database error"))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockProductUseCase{}
+			tt.mockFn(mockUseCase)
+
+			handler := NewProductHandler(mockUseCase, nil, fake)
+			router := setupTestRouter(handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products/export"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="products-2026-03-01.csv"`, w.Header().Get("Content-Disposition"))
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
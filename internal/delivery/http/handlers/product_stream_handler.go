@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/changestream"
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProductStreamHandler streams product changes as server-sent events.
+type ProductStreamHandler struct {
+	hub *changestream.Hub
+	// heartbeat is how often an idle stream sends a comment, so proxies
+	// do not close it.
+	heartbeat time.Duration
+	clock     clock.Clock
+}
+
+func NewProductStreamHandler(hub *changestream.Hub, heartbeat time.Duration, clk clock.Clock) *ProductStreamHandler {
+	return &ProductStreamHandler{hub: hub, heartbeat: heartbeat, clock: clk}
+}
+
+// Stream sends each product change as an event named after its type, with
+// the event's ID and the event as data, until the client leaves or the hub
+// closes as the instance drains. Drafts' changes are sent only to their
+// store's owners. There is no replay: a client that reconnects reloads
+// what it shows.
+func (h *ProductStreamHandler) Stream(c *gin.Context) {
+	var query dto.ProductStreamQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+	if query.StoreID != nil {
+		middleware.SetStoreID(c, *query.StoreID)
+	}
+
+	sub, err := h.hub.Subscribe()
+	if err != nil {
+		message := "The product stream is not accepting clients"
+		if errors.Is(err, changestream.ErrTooManySubscribers) {
+			message = "Too many clients are connected to the product stream, try again later"
+		}
+		c.Header("Retry-After", "30")
+		c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+			Error:   "stream_unavailable",
+			Message: message,
+		})
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	// Tells nginx not to buffer the stream.
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.WriteString(": connected\n\n")
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	ticker := h.clock.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			_, err = c.Writer.WriteString(": heartbeat\n\n")
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Fell behind, or the server is shutting down.
+				return
+			}
+			if query.StoreID != nil && event.StoreID != *query.StoreID {
+				continue
+			}
+			if event.Product != nil && event.Product.Status == domain.ProductStatusDraft && !middleware.OwnsStore(c, event.StoreID) {
+				continue
+			}
+			err = writeEvent(c, event)
+		}
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).Debug("Product stream client went away")
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeEvent(c *gin.Context, event domain.ProductEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/changestream"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/lifecycle"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupProductStreamTestRouter() (*gin.Engine, *changestream.Hub) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	hub := changestream.NewHub(telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil)),
+		changestream.Config{Buffer: 8, MaxSubscribers: 10})
+	handler := NewProductStreamHandler(hub, time.Hour, clock.Real())
+	r.GET("/api/v1/products/stream", handler.Stream)
+	return r, hub
+}
+
+func TestProductStreamHandler_Stream(t *testing.T) {
+	draft := domain.ProductEvent{
+		ID: "e-1", Type: domain.ProductEventCreated, StoreID: 3, ProductID: 7,
+		Product: &domain.ProductEventData{ID: 7, StoreID: 3, Name: "Rye Flour", Status: domain.ProductStatusDraft},
+	}
+	active := domain.ProductEvent{
+		ID: "e-2", Type: domain.ProductEventUpdated, StoreID: 4, ProductID: 8,
+		Product: &domain.ProductEventData{ID: 8, StoreID: 4, Name: "Spelt Flour", Status: domain.ProductStatusActive},
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		apiKey string
+		want   []string
+	}{
+		{name: "anonymous", want: []string{"e-2"}},
+		{name: "store owner sees drafts", apiKey: testAPIKey, want: []string{"e-1", "e-2"}},
+		{name: "one store", query: "?store_id=3", apiKey: testAPIKey, want: []string{"e-1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, hub := setupProductStreamTestRouter()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products/stream"+tt.query, nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				router.ServeHTTP(w, req)
+			}()
+			require.Eventually(t, func() bool {
+				return hub.Subscribers() == 1
+			}, time.Second, 10*time.Millisecond)
+
+			hub.Publish(context.Background(), draft)
+			hub.Publish(context.Background(), active)
+			// Closing the hub ends the stream once the events are sent.
+			hub.Close()
+			<-done
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+			var ids []string
+			for _, line := range strings.Split(w.Body.String(), "\n") {
+				if id, ok := strings.CutPrefix(line, "id: "); ok {
+					ids = append(ids, id)
+				}
+			}
+			assert.Equal(t, tt.want, ids)
+			if len(tt.want) > 0 && tt.want[len(tt.want)-1] == "e-2" {
+				assert.Contains(t, w.Body.String(), "event: product.updated\ndata: {\"id\":\"e-2\"")
+			}
+		})
+	}
+}
+
+func TestProductStreamHandler_Refused(t *testing.T) {
+	router, hub := setupProductStreamTestRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/stream?store_id=abc", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	hub.Close()
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/stream", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), "stream_unavailable")
+}
+
+func TestProductStreamHandler_EndsOnDrain(t *testing.T) {
+	router, hub := setupProductStreamTestRouter()
+	manager := lifecycle.New()
+	manager.OnDrain(hub.Close)
+
+	manager.Begin()
+	go func() {
+		defer manager.End()
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/products/stream", nil))
+	}()
+	require.Eventually(t, func() bool {
+		return hub.Subscribers() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Equal(t, int64(0), manager.Drain(ctx), "the stream ends instead of holding the drain up")
+	assert.NoError(t, ctx.Err())
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReconciliationHandler serves /admin/reconciliations, where operators run
+// reconciliations and read their reports.
+type ReconciliationHandler struct {
+	reconciliationUseCase usecase.ReconciliationUseCaseInterface
+	runTimeout            time.Duration
+}
+
+func NewReconciliationHandler(reconciliationUseCase usecase.ReconciliationUseCaseInterface, runTimeout time.Duration) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationUseCase: reconciliationUseCase,
+		runTimeout:            runTimeout,
+	}
+}
+
+func (h *ReconciliationHandler) Reconcile(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.runTimeout)
+	defer cancel()
+
+	var req dto.ReconcileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind reconcile request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	run, err := h.reconciliationUseCase.Reconcile(ctx, req.Source, req.SourceID, req.PolicyOrDefault())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToReconciliationRunResponse(run))
+}
+
+func (h *ReconciliationHandler) GetRuns(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	runs, err := h.reconciliationUseCase.GetRuns(ctx, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToReconciliationRunListResponse(runs, page.Limit, page.Offset)
+	writeList(c, response, response.Runs, page)
+}
+
+func (h *ReconciliationHandler) GetRun(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Reconciliation")
+	if !ok {
+		return
+	}
+
+	run, err := h.reconciliationUseCase.GetRun(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToReconciliationRunResponse(run))
+}
+
+func (h *ReconciliationHandler) GetDiscrepancies(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Reconciliation")
+	if !ok {
+		return
+	}
+
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	discrepancies, err := h.reconciliationUseCase.GetDiscrepancies(ctx, id, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToDiscrepancyListResponse(discrepancies, page.Limit, page.Offset)
+	writeList(c, response, response.Discrepancies, page)
+}
+
+func (h *ReconciliationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrReconciliationNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "reconciliation_not_found",
+			Message: "Reconciliation run not found",
+		})
+	case errors.Is(err, domain.ErrFeedNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "feed_not_found",
+			Message: "Feed not found",
+		})
+	case errors.Is(err, domain.ErrConnectorNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "connector_not_found",
+			Message: "Connector not found",
+		})
+	case errors.Is(err, domain.ErrInvalidReconciliation):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_reconciliation",
+			Message: err.Error(),
+			Fields:  dto.FieldErrors(err),
+		})
+	case errors.Is(err, domain.ErrReconciliationInProgress):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "reconciliation_in_progress",
+			Message: "A reconciliation of this source is already in progress",
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
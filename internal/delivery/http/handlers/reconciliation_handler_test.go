@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReconciliationUseCase struct {
+	mock.Mock
+}
+
+func (m *MockReconciliationUseCase) Reconcile(ctx context.Context, source string, sourceID int64, policy string) (*domain.ReconciliationRun, error) {
+	args := m.Called(ctx, source, sourceID, policy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReconciliationRun), args.Error(1)
+}
+
+func (m *MockReconciliationUseCase) ReconcileAll(ctx context.Context, policy string) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *MockReconciliationUseCase) GetRun(ctx context.Context, id int64) (*domain.ReconciliationRun, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReconciliationRun), args.Error(1)
+}
+
+func (m *MockReconciliationUseCase) GetRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*domain.ReconciliationRun), args.Error(1)
+}
+
+func (m *MockReconciliationUseCase) GetDiscrepancies(ctx context.Context, runID int64, limit, offset int) ([]*domain.Discrepancy, error) {
+	args := m.Called(ctx, runID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Discrepancy), args.Error(1)
+}
+
+func setupReconciliationTestRouter(handler *ReconciliationHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	reconciliations := r.Group("/admin/reconciliations")
+	{
+		reconciliations.POST("", handler.Reconcile)
+		reconciliations.GET("", handler.GetRuns)
+		reconciliations.GET("/:id", handler.GetRun)
+		reconciliations.GET("/:id/discrepancies", handler.GetDiscrepancies)
+	}
+
+	return r
+}
+
+func TestReconciliationHandler_Reconcile(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		mockFn       func(*MockReconciliationUseCase)
+		expectedCode int
+	}{
+		{
+			name: "defaults to report",
+			body: `{"source":"feed","source_id":7}`,
+			mockFn: func(m *MockReconciliationUseCase) {
+				m.On("Reconcile", mock.Anything, "feed", int64(7), domain.ReconciliationPolicyReport).Return(
+					&domain.ReconciliationRun{ID: 1, Source: "feed", SourceID: 7, Status: domain.ReconciliationRunStatusSucceeded}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name: "unknown policy",
+			body: `{"source":"feed","source_id":7,"policy":"last_writer"}`,
+			mockFn: func(m *MockReconciliationUseCase) {
+				m.On("Reconcile", mock.Anything, "feed", int64(7), "last_writer").Return(nil, domain.ErrInvalidReconciliation)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "unknown connector",
+			body: `{"source":"connector","source_id":3,"policy":"source_wins"}`,
+			mockFn: func(m *MockReconciliationUseCase) {
+				m.On("Reconcile", mock.Anything, "connector", int64(3), "source_wins").Return(nil, domain.ErrConnectorNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "already running",
+			body: `{"source":"feed","source_id":7}`,
+			mockFn: func(m *MockReconciliationUseCase) {
+				m.On("Reconcile", mock.Anything, "feed", int64(7), domain.ReconciliationPolicyReport).Return(nil, domain.ErrReconciliationInProgress)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "missing source ID",
+			body:         `{"source":"feed"}`,
+			mockFn:       func(m *MockReconciliationUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockReconciliationUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupReconciliationTestRouter(NewReconciliationHandler(mockUseCase, time.Minute))
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/reconciliations", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReconciliationHandler_GetDiscrepancies(t *testing.T) {
+	t.Run("lists the run's discrepancies", func(t *testing.T) {
+		mockUseCase := &MockReconciliationUseCase{}
+		mockUseCase.On("GetDiscrepancies", mock.Anything, int64(4), 10, 0).Return([]*domain.Discrepancy{
+			{ID: 1, RunID: 4, Name: "Rye Flour", Kind: domain.DiscrepancyMissingLocally},
+		}, nil)
+		router := setupReconciliationTestRouter(NewReconciliationHandler(mockUseCase, time.Minute))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/reconciliations/4/discrepancies", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var resp dto.DiscrepancyListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Discrepancies, 1)
+		assert.Nil(t, resp.Discrepancies[0].ProductID)
+		mockUseCase.AssertExpectations(t)
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		mockUseCase := &MockReconciliationUseCase{}
+		mockUseCase.On("GetDiscrepancies", mock.Anything, int64(9), 10, 0).Return(nil, domain.ErrReconciliationNotFound)
+		router := setupReconciliationTestRouter(NewReconciliationHandler(mockUseCase, time.Minute))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/reconciliations/9/discrepancies", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/replication"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RegionRolePrimary   = "primary"
+	RegionRoleSecondary = "secondary"
+)
+
+// RegionHandler reports this instance's region and its read replica's lag.
+type RegionHandler struct {
+	region        string
+	primaryRegion string
+	monitor       *replication.Monitor
+}
+
+// NewRegionHandler returns a handler for region. monitor is nil when no
+// read replica is configured.
+func NewRegionHandler(region, primaryRegion string, monitor *replication.Monitor) *RegionHandler {
+	return &RegionHandler{
+		region:        region,
+		primaryRegion: primaryRegion,
+		monitor:       monitor,
+	}
+}
+
+// GetReplication always answers 200: a lagging replica only moves reads to
+// the primary, so it degrades the instance rather than taking it down.
+func (h *RegionHandler) GetReplication(c *gin.Context) {
+	response := dto.ReplicationResponse{
+		Status:        "ok",
+		Region:        h.region,
+		PrimaryRegion: h.primaryRegion,
+		Role:          RegionRolePrimary,
+	}
+	if h.region != h.primaryRegion {
+		response.Role = RegionRoleSecondary
+	}
+
+	if h.monitor != nil {
+		response.Replica = dto.ToReplicaStatusResponse(h.monitor.Status())
+		if !response.Replica.Healthy {
+			response.Status = "degraded"
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/replication"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegionHandler_GetReplication(t *testing.T) {
+	tests := []struct {
+		name            string
+		region          string
+		lag             time.Duration
+		probeErr        error
+		noReplica       bool
+		expectedStatus  string
+		expectedRole    string
+		expectedHealthy bool
+	}{
+		{
+			name:           "single region without replica",
+			region:         "eu-west-1",
+			noReplica:      true,
+			expectedStatus: "ok",
+			expectedRole:   RegionRolePrimary,
+		},
+		{
+			name:            "secondary with caught-up replica",
+			region:          "us-east-1",
+			lag:             200 * time.Millisecond,
+			expectedStatus:  "ok",
+			expectedRole:    RegionRoleSecondary,
+			expectedHealthy: true,
+		},
+		{
+			name:           "lagging replica",
+			region:         "us-east-1",
+			lag:            time.Minute,
+			expectedStatus: "degraded",
+			expectedRole:   RegionRoleSecondary,
+		},
+		{
+			name:           "unreachable replica",
+			region:         "us-east-1",
+			probeErr:       errors.New("connection refused"),
+			expectedStatus: "degraded",
+			expectedRole:   RegionRoleSecondary,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var monitor *replication.Monitor
+			if !tt.noReplica {
+				monitor = replication.NewMonitor(func(ctx context.Context) (time.Duration, error) {
+					return tt.lag, tt.probeErr
+				}, replication.Config{Interval: time.Minute, MaxLag: 5 * time.Second}, clock.Real(), logrus.New())
+				monitor.Measure(context.Background())
+			}
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.GET("/health/replication", NewRegionHandler(tt.region, "eu-west-1", monitor).GetReplication)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/replication", nil))
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response dto.ReplicationResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedStatus, response.Status)
+			assert.Equal(t, tt.expectedRole, response.Role)
+			assert.Equal(t, "eu-west-1", response.PrimaryRegion)
+
+			if tt.noReplica {
+				assert.Nil(t, response.Replica)
+				return
+			}
+			require.NotNil(t, response.Replica)
+			assert.Equal(t, tt.expectedHealthy, response.Replica.Healthy)
+			if tt.probeErr != nil {
+				assert.Equal(t, tt.probeErr.Error(), response.Replica.Error)
+			} else {
+				assert.Equal(t, tt.lag.Seconds(), response.Replica.LagSeconds)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/retention"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RetentionHandler struct {
+	worker *retention.Worker
+}
+
+func NewRetentionHandler(worker *retention.Worker) *RetentionHandler {
+	return &RetentionHandler{
+		worker: worker,
+	}
+}
+
+// GetReport shows what each retention rule would delete if it ran now,
+// without deleting anything.
+func (h *RetentionHandler) GetReport(c *gin.Context) {
+	reports, err := h.worker.Report(c.Request.Context())
+	if err != nil {
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Failed to build retention report")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToRetentionReportResponse(reports))
+}
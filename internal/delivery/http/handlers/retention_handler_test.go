@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/retention"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubRetentionStore struct {
+	eligible int64
+	err      error
+	deletes  int
+}
+
+func (s *stubRetentionStore) CountExpired(ctx context.Context, rule domain.RetentionRule, cutoff time.Time) (int64, sql.NullTime, error) {
+	return s.eligible, sql.NullTime{}, s.err
+}
+
+func (s *stubRetentionStore) DeleteExpired(ctx context.Context, rule domain.RetentionRule, cutoff time.Time, limit int) (int64, error) {
+	s.deletes++
+	return 0, nil
+}
+
+func setupRetentionTestRouter(store retention.Store) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	worker := retention.NewWorker(store, retention.Rules(365*24*time.Hour, 30*24*time.Hour, 0, 0, 0, 0, 0), registry, retention.Config{}, clock.Real(), logrus.New())
+	r.GET("/admin/retention/report", NewRetentionHandler(worker).GetReport)
+
+	return r
+}
+
+func TestRetentionHandler_GetReport(t *testing.T) {
+	store := &stubRetentionStore{eligible: 7}
+	router := setupRetentionTestRouter(store)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/retention/report", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.RetentionReportResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.DryRun)
+	require.Len(t, response.Rules, 2)
+	assert.Equal(t, "audit_logs", response.Rules[0].Rule)
+	assert.Equal(t, int64(7), response.Rules[0].Eligible)
+	assert.Equal(t, "tombstones", response.Rules[1].Rule)
+	assert.Zero(t, store.deletes, "the report must not delete")
+}
+
+func TestRetentionHandler_GetReport_StoreError(t *testing.T) {
+	router := setupRetentionTestRouter(&stubRetentionStore{err: errors.New("connection refused")})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/retention/report", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var response dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "internal_server_error", response.Error)
+}
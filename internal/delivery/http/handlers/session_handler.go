@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/lockout"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/apikey"
+	"backend-context-engineering-template/pkg/logger"
+	"backend-context-engineering-template/pkg/session"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type SessionHandler struct {
+	manager   *session.Manager
+	cookie    session.CookieConfig
+	twoFactor usecase.TwoFactorUseCaseInterface
+	guard     *lockout.Guard
+}
+
+// NewSessionHandler builds the session endpoints. twoFactor may be nil when
+// two-factor authentication is unavailable, and guard nil to disable
+// brute-force protection.
+func NewSessionHandler(manager *session.Manager, cookie session.CookieConfig, twoFactor usecase.TwoFactorUseCaseInterface, guard *lockout.Guard) *SessionHandler {
+	return &SessionHandler{
+		manager:   manager,
+		cookie:    cookie,
+		twoFactor: twoFactor,
+		guard:     guard,
+	}
+}
+
+// CreateSession exchanges an API key, plus a two-factor code when the
+// identity is enrolled, for a browser session cookie.
+func (h *SessionHandler) CreateSession(c *gin.Context) {
+	if middleware.CurrentAPIKey(c) == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "api_key_required",
+			Message: "Sessions are created with an X-API-Key header",
+		})
+		return
+	}
+
+	var req dto.CreateSessionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_request",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	identity := middleware.ClientIdentity(c)
+	if h.twoFactor != nil {
+		ctx := c.Request.Context()
+		err := guardedVerify(ctx, c, h.guard, identity, func() error {
+			return h.twoFactor.Verify(ctx, identity, twoFactorRole(c), req.Code)
+		})
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+	}
+
+	s, token, err := h.manager.Create(c.Request.Context(), identity, middleware.CurrentAPIKey(c).StoreIDs, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"action":     "create_session",
+		"session_id": s.ID,
+	}).Info("Session created")
+
+	h.cookie.Write(c.Writer, token, s.ExpiresAt)
+	c.JSON(http.StatusCreated, dto.CreateSessionResponse{
+		Session:   dto.ToSessionResponse(s, s.ID),
+		CSRFToken: s.CSRFToken,
+	})
+}
+
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	sessions, err := h.manager.List(c.Request.Context(), middleware.ClientIdentity(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToSessionListResponse(sessions, h.currentID(c)))
+}
+
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.manager.Revoke(c.Request.Context(), middleware.ClientIdentity(c), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+		"action":     "revoke_session",
+		"session_id": id,
+	}).Info("Session revoked")
+
+	if id == h.currentID(c) {
+		h.cookie.Clear(c.Writer)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetCSRFToken returns the CSRF token of the current cookie session.
+func (h *SessionHandler) GetCSRFToken(c *gin.Context) {
+	s := middleware.CurrentSession(c)
+	if s == nil {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "session_required",
+			Message: "CSRF tokens are only issued to cookie sessions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CSRFTokenResponse{CSRFToken: s.CSRFToken})
+}
+
+func (h *SessionHandler) currentID(c *gin.Context) string {
+	if s := middleware.CurrentSession(c); s != nil {
+		return s.ID
+	}
+	return ""
+}
+
+func (h *SessionHandler) handleError(c *gin.Context, err error) {
+	if handleTwoFactorError(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, session.ErrNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "session_not_found",
+			Message: "Session not found",
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
+
+// twoFactorRole picks the role whose two-factor policy applies to a
+// session request.
+func twoFactorRole(c *gin.Context) string {
+	switch {
+	case middleware.IsAdmin(c):
+		return domain.TwoFactorRoleAdmin
+	case middleware.CurrentAPIKey(c).HasScope(apikey.ScopeBulk):
+		return domain.TwoFactorRoleBulk
+	default:
+		return domain.TwoFactorRoleAPIKey
+	}
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/session"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSessionTestRouter(handler *SessionHandler, manager *session.Manager, cookie session.CookieConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+	r.Use(middleware.Session(manager, cookie, logrus.New()))
+
+	r.POST("/api/v1/me/sessions", handler.CreateSession)
+	r.GET("/api/v1/me/sessions", handler.GetSessions)
+	r.DELETE("/api/v1/me/sessions/:id", handler.RevokeSession)
+	r.GET("/api/v1/me/csrf", handler.GetCSRFToken)
+
+	return r
+}
+
+func TestSessionHandler(t *testing.T) {
+	manager := session.NewManager(session.NewMemoryStore(clock.Real()), session.Config{TTL: time.Hour}, clock.Real())
+	cookie := session.CookieConfig{Name: "session", Secure: true, SameSite: http.SameSiteStrictMode}
+	router := setupSessionTestRouter(NewSessionHandler(manager, cookie, nil, nil), manager, cookie)
+
+	// Creating a session needs an API key.
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/me/sessions", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/me/sessions", nil)
+	req.Header.Set("X-API-Key", "secret-key")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created dto.CreateSessionResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.CSRFToken)
+	assert.True(t, created.Session.Current)
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	sessionCookie := cookies[0]
+	assert.True(t, sessionCookie.HttpOnly)
+	assert.True(t, sessionCookie.Secure)
+	assert.Equal(t, http.SameSiteStrictMode, sessionCookie.SameSite)
+
+	// The cookie alone identifies the same caller as the API key.
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/me/sessions", nil)
+	req.AddCookie(sessionCookie)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var list dto.SessionListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Total)
+	assert.Equal(t, created.Session.ID, list.Sessions[0].ID)
+	assert.True(t, list.Sessions[0].Current)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/me/csrf", nil)
+	req.AddCookie(sessionCookie)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var csrf dto.CSRFTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &csrf))
+	assert.Equal(t, created.CSRFToken, csrf.CSRFToken)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/me/csrf", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/me/sessions/unknown", nil)
+	req.AddCookie(sessionCookie)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// Revoking the current session also clears its cookie.
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/me/sessions/"+created.Session.ID, nil)
+	req.AddCookie(sessionCookie)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	require.Len(t, w.Result().Cookies(), 1)
+	assert.Equal(t, -1, w.Result().Cookies()[0].MaxAge)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/me/csrf", nil)
+	req.AddCookie(sessionCookie)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StockSummaryHandler serves /api/v1/stores/:id/stock-summary. Anyone may
+// read it, like the store's products it counts.
+type StockSummaryHandler struct {
+	stockSummaryUseCase usecase.StockSummaryUseCaseInterface
+}
+
+func NewStockSummaryHandler(stockSummaryUseCase usecase.StockSummaryUseCaseInterface) *StockSummaryHandler {
+	return &StockSummaryHandler{
+		stockSummaryUseCase: stockSummaryUseCase,
+	}
+}
+
+func (h *StockSummaryHandler) GetStockSummary(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseIDParam(c, "id", "Store")
+	if !ok {
+		return
+	}
+	middleware.SetStoreID(c, storeID)
+
+	summary, err := h.stockSummaryUseCase.GetStockSummary(ctx, storeID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStockSummaryResponse(summary))
+}
+
+func (h *StockSummaryHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrStoreNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "store_not_found",
+			Message: "Store not found",
+		})
+	case errors.Is(err, domain.ErrInvalidStore):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_store",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStockSummaryUseCase struct {
+	mock.Mock
+}
+
+func (m *MockStockSummaryUseCase) RecalculateStockSummary(ctx context.Context, storeID int64) (*domain.StockSummary, error) {
+	args := m.Called(ctx, storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockSummary), args.Error(1)
+}
+
+func (m *MockStockSummaryUseCase) GetStockSummary(ctx context.Context, storeID int64) (*domain.StockSummary, error) {
+	args := m.Called(ctx, storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockSummary), args.Error(1)
+}
+
+func TestStockSummaryHandler_GetStockSummary(t *testing.T) {
+	calculatedAt := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		path         string
+		setupMock    func(*MockStockSummaryUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name: "summary",
+			path: "/api/v1/stores/3/stock-summary",
+			setupMock: func(m *MockStockSummaryUseCase) {
+				m.On("GetStockSummary", mock.Anything, int64(3)).Return(&domain.StockSummary{
+					StoreID: 3, Products: 4, InStock: 2, LowStock: 1, OutOfStock: 1, CalculatedAt: calculatedAt,
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "unknown store",
+			path: "/api/v1/stores/9/stock-summary",
+			setupMock: func(m *MockStockSummaryUseCase) {
+				m.On("GetStockSummary", mock.Anything, int64(9)).Return(nil, domain.ErrStoreNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+			expectedBody: "store_not_found",
+		},
+		{
+			name:         "invalid store ID",
+			path:         "/api/v1/stores/abc/stock-summary",
+			setupMock:    func(m *MockStockSummaryUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := new(MockStockSummaryUseCase)
+			tt.setupMock(useCase)
+			handler := NewStockSummaryHandler(useCase)
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.GET("/api/v1/stores/:id/stock-summary", handler.GetStockSummary)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			if tt.expectedCode == http.StatusOK {
+				var response dto.StockSummaryResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, dto.StockSummaryResponse{
+					StoreID: 3, Products: 4, InStock: 2, LowStock: 1, OutOfStock: 1, CalculatedAt: "2026-03-02T12:00:00Z",
+				}, response)
+			}
+			useCase.AssertExpectations(t)
+		})
+	}
+}
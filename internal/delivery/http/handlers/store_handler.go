@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoreHandler serves /api/v1/stores. Anyone may read stores; only admins
+// create them, and renaming or deleting one takes ownership of it.
+type StoreHandler struct {
+	storeUseCase usecase.StoreUseCaseInterface
+}
+
+func NewStoreHandler(storeUseCase usecase.StoreUseCaseInterface) *StoreHandler {
+	return &StoreHandler{
+		storeUseCase: storeUseCase,
+	}
+}
+
+func (h *StoreHandler) CreateStore(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	if !requireAuthenticated(c) {
+		return
+	}
+	if !middleware.IsAdmin(c) {
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{
+			Error:   "store_not_owned",
+			Message: "Only admins may create stores",
+		})
+		return
+	}
+
+	var req dto.SaveStoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind create store request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	store, err := h.storeUseCase.CreateStore(ctx, req.ToDomain())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToStoreResponse(store))
+}
+
+func (h *StoreHandler) GetStore(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Store")
+	if !ok {
+		return
+	}
+
+	store, err := h.storeUseCase.GetStore(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStoreResponse(store))
+}
+
+func (h *StoreHandler) GetStores(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	stores, err := h.storeUseCase.GetStores(ctx, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToStoreListResponse(stores, page.Limit, page.Offset)
+	writeList(c, response, response.Stores, page)
+}
+
+func (h *StoreHandler) UpdateStore(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Store")
+	if !ok || !requireStoreOwner(c, id) {
+		return
+	}
+
+	var req dto.SaveStoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind update store request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+
+	store, err := h.storeUseCase.UpdateStore(ctx, id, req.ToDomain())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToStoreResponse(store))
+}
+
+func (h *StoreHandler) DeleteStore(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Store")
+	if !ok || !requireStoreOwner(c, id) {
+		return
+	}
+
+	if err := h.storeUseCase.DeleteStore(ctx, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *StoreHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrStoreNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "store_not_found",
+			Message: "Store not found",
+		})
+	case errors.Is(err, domain.ErrInvalidStore):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_store",
+			Message: err.Error(),
+			Fields:  dto.FieldErrors(err),
+		})
+	case errors.Is(err, domain.ErrStoreHasProducts):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "store_has_products",
+			Message: "The store still has products; delete or move them first",
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStoreUseCase struct {
+	mock.Mock
+}
+
+func (m *MockStoreUseCase) CreateStore(ctx context.Context, store *domain.Store) (*domain.Store, error) {
+	args := m.Called(ctx, store)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreUseCase) GetStore(ctx context.Context, id int64) (*domain.Store, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreUseCase) GetStores(ctx context.Context, limit, offset int) ([]*domain.Store, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreUseCase) UpdateStore(ctx context.Context, id int64, store *domain.Store) (*domain.Store, error) {
+	args := m.Called(ctx, id, store)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreUseCase) DeleteStore(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func setupStoreTestRouter(handler *StoreHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	stores := r.Group("/api/v1/stores")
+	{
+		stores.POST("", handler.CreateStore)
+		stores.GET("", handler.GetStores)
+		stores.GET("/:id", handler.GetStore)
+		stores.PUT("/:id", handler.UpdateStore)
+		stores.DELETE("/:id", handler.DeleteStore)
+	}
+
+	return r
+}
+
+func TestStoreHandler_CreateStore(t *testing.T) {
+	tests := []struct {
+		name         string
+		anonymous    bool
+		expectedCode int
+	}{
+		{name: "store owner is not an admin", expectedCode: http.StatusForbidden},
+		{name: "anonymous", anonymous: true, expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockStoreUseCase{}
+			router := setupStoreTestRouter(NewStoreHandler(mockUseCase))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/stores", bytes.NewBufferString(`{"name":"Corner Shop"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if !tt.anonymous {
+				req.Header.Set("X-API-Key", testAPIKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStoreHandler_GetStore(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		mockFn       func(*MockStoreUseCase)
+		expectedCode int
+	}{
+		{
+			name: "found",
+			id:   "3",
+			mockFn: func(m *MockStoreUseCase) {
+				m.On("GetStore", mock.Anything, int64(3)).Return(&domain.Store{ID: 3, Name: "Corner Shop"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "not found",
+			id:   "9",
+			mockFn: func(m *MockStoreUseCase) {
+				m.On("GetStore", mock.Anything, int64(9)).Return(nil, domain.ErrStoreNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid ID",
+			id:           "abc",
+			mockFn:       func(m *MockStoreUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockStoreUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupStoreTestRouter(NewStoreHandler(mockUseCase))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/stores/"+tt.id, nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				var resp dto.StoreResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, "Corner Shop", resp.Name)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStoreHandler_UpdateStore(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		body         string
+		mockFn       func(*MockStoreUseCase)
+		expectedCode int
+	}{
+		{
+			name: "owned store",
+			id:   "3",
+			body: `{"name":"Corner Market"}`,
+			mockFn: func(m *MockStoreUseCase) {
+				m.On("UpdateStore", mock.Anything, int64(3), &domain.Store{Name: "Corner Market"}).Return(&domain.Store{ID: 3, Name: "Corner Market"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "another owner's store",
+			id:           "4",
+			body:         `{"name":"Corner Market"}`,
+			mockFn:       func(m *MockStoreUseCase) {},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "missing name",
+			id:           "3",
+			body:         `{}`,
+			mockFn:       func(m *MockStoreUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockStoreUseCase{}
+			tt.mockFn(mockUseCase)
+			router := setupStoreTestRouter(NewStoreHandler(mockUseCase))
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/stores/"+tt.id, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStoreHandler_DeleteStore(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "deleted", expectedCode: http.StatusNoContent},
+		{name: "has products", mockErr: domain.ErrStoreHasProducts, expectedCode: http.StatusConflict},
+		{name: "not found", mockErr: domain.ErrStoreNotFound, expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockStoreUseCase{}
+			mockUseCase.On("DeleteStore", mock.Anything, int64(5)).Return(tt.mockErr)
+			router := setupStoreTestRouter(NewStoreHandler(mockUseCase))
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/stores/5", nil)
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	trashUseCase usecase.TrashUseCaseInterface
+	clock        clock.Clock
+}
+
+func NewTrashHandler(trashUseCase usecase.TrashUseCaseInterface, clk clock.Clock) *TrashHandler {
+	return &TrashHandler{
+		trashUseCase: trashUseCase,
+		clock:        clk,
+	}
+}
+
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseStoreIDQuery(c)
+	if !ok {
+		return
+	}
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	products, err := h.trashUseCase.GetTrash(ctx, storeID, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToTrashListResponse(products, page.Limit, page.Offset, h.clock.Now())
+	writeList(c, response, response.Products, page)
+}
+
+func (h *TrashHandler) RestoreProduct(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Product")
+	if !ok {
+		return
+	}
+
+	product, err := h.trashUseCase.RestoreProduct(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	middleware.SetStoreID(c, product.StoreID)
+
+	c.JSON(http.StatusOK, dto.ToProductResponse(product, h.clock.Now()))
+}
+
+func (h *TrashHandler) EmptyTrash(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseStoreIDQuery(c)
+	if !ok {
+		return
+	}
+	if storeID == 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_store_id",
+			Message: "store_id is required",
+		})
+		return
+	}
+
+	if c.GetHeader(confirmMassOperationHeader) == "true" {
+		ctx = usecase.WithMassOperationConfirmed(ctx)
+	}
+	ctx = usecase.WithBulkCaller(ctx, bulkCaller(c))
+
+	deleted, err := h.trashUseCase.EmptyTrash(ctx, storeID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.EmptyTrashResponse{Deleted: deleted})
+}
+
+func (h *TrashHandler) handleError(c *gin.Context, err error) {
+	if handleBulkError(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrTrashedProductNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "trashed_product_not_found",
+			Message: "Product not found in trash",
+		})
+	case errors.Is(err, domain.ErrInvalidProduct):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrDuplicateProduct):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "duplicate_product",
+			Message: "Product with this name already exists",
+		})
+	case errors.Is(err, domain.ErrStoreNotFound):
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error:   "store_not_found",
+			Message: "The product's store does not exist",
+		})
+	case errors.Is(err, domain.ErrConfirmationRequired):
+		c.JSON(http.StatusPreconditionRequired, dto.ErrorResponse{
+			Error:   "confirmation_required",
+			Message: err.Error() + "; retry with " + confirmMassOperationHeader + ": true",
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
+
+// parseStoreIDQuery reads the optional store_id filter; zero means all
+// stores.
+func parseStoreIDQuery(c *gin.Context) (int64, bool) {
+	param := c.Query("store_id")
+	if param == "" {
+		return 0, true
+	}
+
+	storeID, err := strconv.ParseInt(param, 10, 64)
+	if err != nil || storeID <= 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_store_id",
+			Message: "store_id must be a positive number",
+		})
+		return 0, false
+	}
+	middleware.SetStoreID(c, storeID)
+	return storeID, true
+}
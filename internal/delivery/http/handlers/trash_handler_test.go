@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTrashUseCase struct {
+	mock.Mock
+}
+
+func (m *MockTrashUseCase) GetTrash(ctx context.Context, storeID int64, limit, offset int) ([]*domain.TrashedProduct, error) {
+	args := m.Called(ctx, storeID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TrashedProduct), args.Error(1)
+}
+
+func (m *MockTrashUseCase) RestoreProduct(ctx context.Context, id int64) (*domain.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockTrashUseCase) EmptyTrash(ctx context.Context, storeID int64) (int64, error) {
+	args := m.Called(ctx, storeID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTrashUseCase) PurgeExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func setupTrashTestRouter(handler *TrashHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	trash := r.Group("/api/v1/trash")
+	{
+		trash.GET("", handler.GetTrash)
+		trash.DELETE("", handler.EmptyTrash)
+		trash.POST("/:id/restore", handler.RestoreProduct)
+	}
+
+	return r
+}
+
+func TestTrashHandler_GetTrash(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		query        string
+		mockFn       func(*MockTrashUseCase)
+		expectedCode int
+	}{
+		{
+			name:  "list all stores",
+			query: "",
+			mockFn: func(m *MockTrashUseCase) {
+				m.On("GetTrash", mock.Anything, int64(0), 10, 0).Return([]*domain.TrashedProduct{
+					{Product: domain.Product{ID: 1, StoreID: 1, Name: "Deleted"}},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:  "filter by store",
+			query: "?store_id=2&limit=5",
+			mockFn: func(m *MockTrashUseCase) {
+				m.On("GetTrash", mock.Anything, int64(2), 5, 0).Return([]*domain.TrashedProduct{}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "invalid store ID",
+			query:        "?store_id=abc",
+			mockFn:       func(m *MockTrashUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockTrashUseCase{}
+			tt.mockFn(mockUseCase)
+
+			router := setupTrashTestRouter(NewTrashHandler(mockUseCase, clock.Real()))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/trash"+tt.query, nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrashHandler_RestoreProduct(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		id           string
+		mockFn       func(*MockTrashUseCase)
+		expectedCode int
+	}{
+		{
+			name: "successful restore",
+			id:   "1",
+			mockFn: func(m *MockTrashUseCase) {
+				m.On("RestoreProduct", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Name: "Restored"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "not in trash",
+			id:   "2",
+			mockFn: func(m *MockTrashUseCase) {
+				m.On("RestoreProduct", mock.Anything, int64(2)).Return(nil, domain.ErrTrashedProductNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "invalid ID",
+			id:           "abc",
+			mockFn:       func(m *MockTrashUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockTrashUseCase{}
+			tt.mockFn(mockUseCase)
+
+			router := setupTrashTestRouter(NewTrashHandler(mockUseCase, clock.Real()))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/trash/"+tt.id+"/restore", nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrashHandler_EmptyTrash(t *testing.T) {
+	held := &domain.BulkConfirmationError{Confirmation: &domain.BulkConfirmation{
+		Operation: domain.BulkOperation{Kind: domain.BulkOperationEmptyTrash, StoreID: 4, Affected: 250},
+		Token:     "a.b.c",
+		ExpiresAt: time.Date(2026, 3, 1, 12, 10, 0, 0, time.UTC),
+	}}
+	bulkCaller := func(elevated bool, token string) any {
+		return mock.MatchedBy(func(ctx context.Context) bool {
+			caller := usecase.BulkCallerFrom(ctx)
+			return caller.Elevated == elevated && caller.Token == token && caller.Actor != ""
+		})
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		confirm      bool
+		apiKey       string
+		token        string
+		mockFn       func(*MockTrashUseCase)
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:    "confirmed",
+			query:   "?store_id=4",
+			confirm: true,
+			mockFn: func(m *MockTrashUseCase) {
+				m.On("EmptyTrash", mock.MatchedBy(usecase.MassOperationConfirmed), int64(4)).Return(int64(7), nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"deleted":7}`,
+		},
+		{
+			name:  "unconfirmed",
+			query: "?store_id=4",
+			mockFn: func(m *MockTrashUseCase) {
+				m.On("EmptyTrash", mock.Anything, int64(4)).Return(int64(0), domain.ErrConfirmationRequired)
+			},
+			expectedCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:         "every store",
+			confirm:      true,
+			mockFn:       func(m *MockTrashUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "bulk operation held for confirmation",
+			query:  "?store_id=4",
+			apiKey: testBulkAPIKey,
+			mockFn: func(m *MockTrashUseCase) {
+				m.On("EmptyTrash", bulkCaller(true, ""), int64(4)).Return(int64(0), held)
+			},
+			expectedCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:   "bulk operation confirmed",
+			query:  "?store_id=4",
+			apiKey: testBulkAPIKey,
+			token:  "a.b.c",
+			mockFn: func(m *MockTrashUseCase) {
+				m.On("EmptyTrash", bulkCaller(true, "a.b.c"), int64(4)).Return(int64(250), nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"deleted":250}`,
+		},
+		{
+			name:   "bulk operation with a used token",
+			query:  "?store_id=4",
+			apiKey: testBulkAPIKey,
+			token:  "a.b.c",
+			mockFn: func(m *MockTrashUseCase) {
+				m.On("EmptyTrash", mock.Anything, int64(4)).Return(int64(0), domain.ErrInvalidConfirmationToken)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:    "bulk operation without the bulk scope",
+			query:   "?store_id=4",
+			confirm: true,
+			apiKey:  testAPIKey,
+			mockFn: func(m *MockTrashUseCase) {
+				m.On("EmptyTrash", bulkCaller(false, ""), int64(4)).Return(int64(0), domain.ErrInsufficientScope)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockTrashUseCase{}
+			tt.mockFn(mockUseCase)
+
+			router := setupTrashTestRouter(NewTrashHandler(mockUseCase, clock.Real()))
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/trash"+tt.query, nil)
+			if tt.confirm {
+				req.Header.Set("X-Confirm-Mass-Operation", "true")
+			}
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			if tt.token != "" {
+				req.Header.Set("X-Confirm-Bulk-Operation", tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			if w.Code == http.StatusPreconditionRequired && tt.apiKey != "" {
+				var response dto.BulkConfirmationResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "bulk_confirmation_required", response.Error)
+				assert.Equal(t, "a.b.c", response.ConfirmationToken)
+				assert.Equal(t, int64(250), response.Affected)
+				assert.Equal(t, "2026-03-01T12:10:00Z", response.ExpiresAt)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
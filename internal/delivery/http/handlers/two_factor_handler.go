@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/lockout"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	twoFactorUseCase usecase.TwoFactorUseCaseInterface
+	guard            *lockout.Guard
+}
+
+// NewTwoFactorHandler builds the enrollment endpoints. guard may be nil to
+// disable brute-force protection.
+func NewTwoFactorHandler(twoFactorUseCase usecase.TwoFactorUseCaseInterface, guard *lockout.Guard) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorUseCase: twoFactorUseCase,
+		guard:            guard,
+	}
+}
+
+func (h *TwoFactorHandler) Setup(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	if !requireAuthenticated(c) {
+		return
+	}
+
+	setup, err := h.twoFactorUseCase.Setup(ctx, middleware.ClientIdentity(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToTwoFactorSetupResponse(setup))
+}
+
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	if !requireAuthenticated(c) {
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if !bindTwoFactorCode(c, &req) {
+		return
+	}
+
+	codes, err := h.twoFactorUseCase.Confirm(ctx, middleware.ClientIdentity(c), req.Code)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	if !requireAuthenticated(c) {
+		return
+	}
+
+	var req dto.TwoFactorCodeRequest
+	if !bindTwoFactorCode(c, &req) {
+		return
+	}
+
+	identity := middleware.ClientIdentity(c)
+	err := guardedVerify(ctx, c, h.guard, identity, func() error {
+		return h.twoFactorUseCase.Disable(ctx, identity, req.Code)
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetLoginMetrics reports failed login, delay and lockout counts since
+// startup.
+func (h *TwoFactorHandler) GetLoginMetrics(c *gin.Context) {
+	if h.guard == nil {
+		c.JSON(http.StatusOK, lockout.Metrics{})
+		return
+	}
+	c.JSON(http.StatusOK, h.guard.Metrics())
+}
+
+func bindTwoFactorCode(c *gin.Context, req *dto.TwoFactorCodeRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// guardedVerify runs verify, which checks a password or second factor,
+// behind the brute-force guard: locked out callers are refused, wrong
+// credentials count as failures and valid ones clear the identity's
+// failures.
+func guardedVerify(ctx context.Context, c *gin.Context, guard *lockout.Guard, identity string, verify func() error) error {
+	if guard == nil {
+		return verify()
+	}
+
+	if err := guard.Check(ctx, identity, c.ClientIP()); err != nil {
+		return err
+	}
+
+	err := verify()
+	switch {
+	case err == nil:
+		guard.Success(ctx, identity)
+	case errors.Is(err, domain.ErrInvalidTwoFactorCode), errors.Is(err, domain.ErrInvalidCredentials):
+		guard.Failure(ctx, identity, c.ClientIP())
+	}
+	return err
+}
+
+// handleTwoFactorError writes the response for two-factor errors and
+// reports whether err was one. It is shared with the session handler,
+// which verifies the second factor at login.
+func handleTwoFactorError(c *gin.Context, err error) bool {
+	var locked *domain.LoginLockedError
+	switch {
+	case errors.As(err, &locked):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{
+			Error:   "login_locked",
+			Message: "Too many failed attempts, try again later",
+		})
+	case errors.Is(err, domain.ErrTwoFactorRequired):
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "two_factor_required",
+			Message: "A two-factor code is required",
+		})
+	case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "invalid_two_factor_code",
+			Message: "The two-factor code is invalid",
+		})
+	case errors.Is(err, domain.ErrTwoFactorEnrollment):
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{
+			Error:   "two_factor_enrollment_required",
+			Message: "Two-factor authentication must be enabled at /auth/2fa/setup first",
+		})
+	case errors.Is(err, domain.ErrTwoFactorNotEnrolled):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "two_factor_not_enrolled",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrTwoFactorAlreadyEnrolled):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "two_factor_already_enrolled",
+			Message: err.Error(),
+		})
+	default:
+		return false
+	}
+	return true
+}
+
+func (h *TwoFactorHandler) handleError(c *gin.Context, err error) {
+	if handleTwoFactorError(c, err) {
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+		Error:   "internal_server_error",
+		Message: "An internal error occurred",
+	})
+}
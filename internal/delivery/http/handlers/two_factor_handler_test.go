@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/lockout"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/ratelimit"
+	"backend-context-engineering-template/pkg/session"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTwoFactorUseCase struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorUseCase) Setup(ctx context.Context, identity string) (*domain.TwoFactorSetup, error) {
+	args := m.Called(ctx, identity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TwoFactorSetup), args.Error(1)
+}
+
+func (m *MockTwoFactorUseCase) Confirm(ctx context.Context, identity, code string) ([]string, error) {
+	args := m.Called(ctx, identity, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTwoFactorUseCase) Verify(ctx context.Context, identity, role, code string) error {
+	args := m.Called(ctx, identity, role, code)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorUseCase) Disable(ctx context.Context, identity, code string) error {
+	args := m.Called(ctx, identity, code)
+	return args.Error(0)
+}
+
+func setupTwoFactorTestRouter(handler *TwoFactorHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	twoFactor := r.Group("/auth/2fa")
+	{
+		twoFactor.POST("/setup", handler.Setup)
+		twoFactor.POST("/confirm", handler.Confirm)
+		twoFactor.POST("/disable", handler.Disable)
+	}
+
+	return r
+}
+
+func TestTwoFactorHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		apiKey       string
+		body         string
+		mockFn       func(*MockTwoFactorUseCase)
+		expectedCode int
+		expectedErr  string
+	}{
+		{
+			name:         "setup without credentials",
+			path:         "/auth/2fa/setup",
+			mockFn:       func(m *MockTwoFactorUseCase) {},
+			expectedCode: http.StatusUnauthorized,
+			expectedErr:  "authentication_required",
+		},
+		{
+			name:   "setup",
+			path:   "/auth/2fa/setup",
+			apiKey: "secret-key",
+			mockFn: func(m *MockTwoFactorUseCase) {
+				m.On("Setup", mock.Anything, mock.Anything).Return(&domain.TwoFactorSetup{Secret: "SECRET", ProvisioningURI: "otpauth://totp/x"}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "setup when already enrolled",
+			path:   "/auth/2fa/setup",
+			apiKey: "secret-key",
+			mockFn: func(m *MockTwoFactorUseCase) {
+				m.On("Setup", mock.Anything, mock.Anything).Return(nil, domain.ErrTwoFactorAlreadyEnrolled)
+			},
+			expectedCode: http.StatusConflict,
+			expectedErr:  "two_factor_already_enrolled",
+		},
+		{
+			name:         "confirm without code",
+			path:         "/auth/2fa/confirm",
+			apiKey:       "secret-key",
+			body:         `{}`,
+			mockFn:       func(m *MockTwoFactorUseCase) {},
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "invalid_request",
+		},
+		{
+			name:   "confirm with invalid code",
+			path:   "/auth/2fa/confirm",
+			apiKey: "secret-key",
+			body:   `{"code":"123456"}`,
+			mockFn: func(m *MockTwoFactorUseCase) {
+				m.On("Confirm", mock.Anything, mock.Anything, "123456").Return(nil, domain.ErrInvalidTwoFactorCode)
+			},
+			expectedCode: http.StatusUnauthorized,
+			expectedErr:  "invalid_two_factor_code",
+		},
+		{
+			name:   "confirm",
+			path:   "/auth/2fa/confirm",
+			apiKey: "secret-key",
+			body:   `{"code":"123456"}`,
+			mockFn: func(m *MockTwoFactorUseCase) {
+				m.On("Confirm", mock.Anything, mock.Anything, "123456").Return([]string{"abcde-fghij"}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "disable when not enrolled",
+			path:   "/auth/2fa/disable",
+			apiKey: "secret-key",
+			body:   `{"code":"123456"}`,
+			mockFn: func(m *MockTwoFactorUseCase) {
+				m.On("Disable", mock.Anything, mock.Anything, "123456").Return(domain.ErrTwoFactorNotEnrolled)
+			},
+			expectedCode: http.StatusNotFound,
+			expectedErr:  "two_factor_not_enrolled",
+		},
+		{
+			name:   "disable",
+			path:   "/auth/2fa/disable",
+			apiKey: "secret-key",
+			body:   `{"code":"123456"}`,
+			mockFn: func(m *MockTwoFactorUseCase) {
+				m.On("Disable", mock.Anything, mock.Anything, "123456").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := new(MockTwoFactorUseCase)
+			tt.mockFn(mockUseCase)
+			router := setupTwoFactorTestRouter(NewTwoFactorHandler(mockUseCase, nil))
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedErr != "" {
+				var resp dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.expectedErr, resp.Error)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSessionHandler_CreateSession_TwoFactor(t *testing.T) {
+	manager := session.NewManager(session.NewMemoryStore(clock.Real()), session.Config{TTL: time.Hour}, clock.Real())
+	cookie := session.CookieConfig{Name: "session"}
+
+	tests := []struct {
+		name         string
+		key          string
+		body         string
+		verifyErr    error
+		expectedRole string
+		expectedCode int
+		expectedErr  string
+	}{
+		{name: "code missing", verifyErr: domain.ErrTwoFactorRequired, expectedCode: http.StatusUnauthorized, expectedErr: "two_factor_required"},
+		{name: "code invalid", body: `{"code":"000000"}`, verifyErr: domain.ErrInvalidTwoFactorCode, expectedCode: http.StatusUnauthorized, expectedErr: "invalid_two_factor_code"},
+		{name: "enrollment required", verifyErr: domain.ErrTwoFactorEnrollment, expectedCode: http.StatusForbidden, expectedErr: "two_factor_enrollment_required"},
+		{name: "code valid", body: `{"code":"123456"}`, expectedCode: http.StatusCreated},
+		{name: "bulk key", key: testBulkAPIKey, body: `{"code":"123456"}`, expectedRole: domain.TwoFactorRoleBulk, expectedCode: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req dto.CreateSessionRequest
+			if tt.body != "" {
+				require.NoError(t, json.Unmarshal([]byte(tt.body), &req))
+			}
+			key, role := testAPIKey, domain.TwoFactorRoleAPIKey
+			if tt.key != "" {
+				key, role = tt.key, tt.expectedRole
+			}
+			mockUseCase := new(MockTwoFactorUseCase)
+			mockUseCase.On("Verify", mock.Anything, mock.Anything, role, req.Code).Return(tt.verifyErr)
+			router := setupSessionTestRouter(NewSessionHandler(manager, cookie, mockUseCase, nil), manager, cookie)
+
+			httpReq := httptest.NewRequest(http.MethodPost, "/api/v1/me/sessions", bytes.NewBufferString(tt.body))
+			httpReq.Header.Set("Content-Type", "application/json")
+			httpReq.Header.Set("X-API-Key", key)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httpReq)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedErr != "" {
+				var resp dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tt.expectedErr, resp.Error)
+				assert.Empty(t, w.Result().Cookies())
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSessionHandler_CreateSession_Lockout(t *testing.T) {
+	manager := session.NewManager(session.NewMemoryStore(clock.Real()), session.Config{TTL: time.Hour}, clock.Real())
+	cookie := session.CookieConfig{Name: "session"}
+	guard := lockout.NewGuard(ratelimit.NewMemoryStore(clock.Real()), lockout.Config{
+		MaxFailures:     2,
+		MaxIPFailures:   10,
+		Window:          time.Minute,
+		LockoutDuration: time.Minute,
+	}, nil, clock.Real(), logrus.New())
+
+	mockUseCase := new(MockTwoFactorUseCase)
+	mockUseCase.On("Verify", mock.Anything, mock.Anything, domain.TwoFactorRoleAPIKey, "000000").Return(domain.ErrInvalidTwoFactorCode).Twice()
+	router := setupSessionTestRouter(NewSessionHandler(manager, cookie, mockUseCase, guard), manager, cookie)
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/me/sessions", bytes.NewBufferString(`{"code":"000000"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", "secret-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send().Code)
+	assert.Equal(t, http.StatusUnauthorized, send().Code)
+
+	w := send()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	mockUseCase.AssertExpectations(t)
+	assert.Equal(t, int64(1), guard.Metrics().IdentityLockouts)
+}
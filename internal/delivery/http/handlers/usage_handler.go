@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UsageHandler struct {
+	usageUseCase usecase.UsageUseCaseInterface
+}
+
+func NewUsageHandler(usageUseCase usecase.UsageUseCaseInterface) *UsageHandler {
+	return &UsageHandler{
+		usageUseCase: usageUseCase,
+	}
+}
+
+// Reconcile compares the usage emitted to billing with the usage recorded
+// for the hours from the from query parameter up to to. Both are optional
+// RFC 3339 times; the default is the last day.
+func (h *UsageHandler) Reconcile(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	from, ok := parseTimeQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseTimeQuery(c, "to")
+	if !ok {
+		return
+	}
+
+	reconciliation, err := h.usageUseCase.Reconcile(ctx, from, to)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToUsageReconciliationResponse(reconciliation))
+}
+
+func (h *UsageHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidUsagePeriod):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_usage_period",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
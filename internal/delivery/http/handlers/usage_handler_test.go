@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockUsageUseCase struct {
+	mock.Mock
+}
+
+func (m *MockUsageUseCase) Reconcile(ctx context.Context, from, to time.Time) (*domain.UsageReconciliation, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UsageReconciliation), args.Error(1)
+}
+
+func setupUsageTestRouter(handler *UsageHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin/usage/reconciliation", handler.Reconcile)
+	return r
+}
+
+func TestUsageHandler_Reconcile(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		query      string
+		mockFn     func(*MockUsageUseCase)
+		wantStatus int
+	}{
+		{
+			name:  "period",
+			query: "?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z",
+			mockFn: func(m *MockUsageUseCase) {
+				m.On("Reconcile", mock.Anything, from, to).Return(&domain.UsageReconciliation{From: from, To: to}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "default period",
+			query: "",
+			mockFn: func(m *MockUsageUseCase) {
+				m.On("Reconcile", mock.Anything, time.Time{}, time.Time{}).Return(&domain.UsageReconciliation{From: from, To: to}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "unparseable to",
+			query:      "?to=tomorrow",
+			mockFn:     func(m *MockUsageUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "invalid period",
+			query: "?from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z",
+			mockFn: func(m *MockUsageUseCase) {
+				m.On("Reconcile", mock.Anything, to, from).Return(nil, domain.ErrInvalidUsagePeriod)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "repository error",
+			query: "",
+			mockFn: func(m *MockUsageUseCase) {
+				m.On("Reconcile", mock.Anything, time.Time{}, time.Time{}).Return(nil, errors.New("database down"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := new(MockUsageUseCase)
+			tt.mockFn(mockUseCase)
+
+			router := setupUsageTestRouter(NewUsageHandler(mockUseCase))
+			req := httptest.NewRequest(http.MethodGet, "/admin/usage/reconciliation"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUsageHandler_Reconcile_Response(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	mockUseCase := new(MockUsageUseCase)
+	mockUseCase.On("Reconcile", mock.Anything, time.Time{}, time.Time{}).Return(&domain.UsageReconciliation{
+		From:          from,
+		To:            to,
+		Records:       2,
+		Emitted:       1,
+		RecordedCalls: 10,
+		EmittedCalls:  6,
+		Discrepancies: []domain.UsageDiscrepancy{
+			{StoreID: 3, Hour: from.Add(9 * time.Hour), Kind: domain.UsageNotEmitted, RecordedCalls: 4},
+		},
+	}, nil)
+
+	router := setupUsageTestRouter(NewUsageHandler(mockUseCase))
+	req := httptest.NewRequest(http.MethodGet, "/admin/usage/reconciliation", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response dto.UsageReconciliationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "2026-03-01T00:00:00Z", response.From)
+	assert.Equal(t, 1, response.Emitted)
+	assert.Equal(t, []dto.UsageDiscrepancyEntry{
+		{StoreID: 3, Hour: "2026-03-01T09:00:00Z", Kind: domain.UsageNotEmitted, RecordedCalls: 4},
+	}, response.Discrepancies)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler manages webhook subscriptions. Callers see and manage the
+// subscriptions of stores they own; subscriptions that span stores are for
+// admins only.
+type WebhookHandler struct {
+	webhookUseCase usecase.WebhookUseCaseInterface
+}
+
+func NewWebhookHandler(webhookUseCase usecase.WebhookUseCaseInterface) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUseCase: webhookUseCase,
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to bind create webhook request")
+		c.JSON(http.StatusBadRequest, dto.ValidationErrorResponse(err))
+		return
+	}
+	if !requireStoreOwner(c, req.StoreID) {
+		return
+	}
+
+	subscription, err := h.webhookUseCase.CreateWebhook(ctx, req.ToDomain())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToWebhookResponse(subscription))
+}
+
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Webhook")
+	if !ok {
+		return
+	}
+
+	subscription, ok := h.authorize(ctx, c, id)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToWebhookResponse(subscription))
+}
+
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseStoreIDQuery(c)
+	if !ok || !requireStoreOwner(c, storeID) {
+		return
+	}
+	page, ok := bindPage(c, nil)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := h.webhookUseCase.GetWebhooks(ctx, storeID, page.Limit, page.Offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response := dto.ToWebhookListResponse(subscriptions, page.Limit, page.Offset)
+	writeList(c, response, response.Webhooks, page)
+}
+
+// GetWebhookHealth reports delivery health as seen by the instance that
+// serves the request.
+func (h *WebhookHandler) GetWebhookHealth(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Webhook")
+	if !ok {
+		return
+	}
+	if _, ok := h.authorize(ctx, c, id); !ok {
+		return
+	}
+
+	health, err := h.webhookUseCase.GetWebhookHealth(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToWebhookHealthResponse(health))
+}
+
+func (h *WebhookHandler) ResumeWebhook(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Webhook")
+	if !ok {
+		return
+	}
+	if _, ok := h.authorize(ctx, c, id); !ok {
+		return
+	}
+
+	subscription, err := h.webhookUseCase.ResumeWebhook(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToWebhookResponse(subscription))
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	id, ok := parseIDParam(c, "id", "Webhook")
+	if !ok {
+		return
+	}
+	if _, ok := h.authorize(ctx, c, id); !ok {
+		return
+	}
+
+	if err := h.webhookUseCase.DeleteWebhook(ctx, id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// authorize loads the subscription and checks the caller owns its store.
+func (h *WebhookHandler) authorize(ctx context.Context, c *gin.Context, id int64) (*domain.WebhookSubscription, bool) {
+	if !requireAuthenticated(c) {
+		return nil, false
+	}
+
+	subscription, err := h.webhookUseCase.GetWebhook(ctx, id)
+	if err != nil {
+		h.handleError(c, err)
+		return nil, false
+	}
+	if !requireStoreOwner(c, subscription.StoreID.Int64) {
+		return nil, false
+	}
+	return subscription, true
+}
+
+func (h *WebhookHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "webhook_not_found",
+			Message: "Webhook subscription not found",
+		})
+	case errors.Is(err, domain.ErrInvalidWebhook):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_webhook",
+			Message: err.Error(),
+			Fields:  dto.FieldErrors(err),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockWebhookUseCase struct {
+	mock.Mock
+}
+
+func (m *MockWebhookUseCase) CreateWebhook(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	args := m.Called(ctx, subscription)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookUseCase) GetWebhook(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookUseCase) GetWebhooks(ctx context.Context, storeID int64, limit, offset int) ([]*domain.WebhookSubscription, error) {
+	args := m.Called(ctx, storeID, limit, offset)
+	return args.Get(0).([]*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookUseCase) GetWebhookHealth(ctx context.Context, id int64) (*domain.WebhookHealth, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookHealth), args.Error(1)
+}
+
+func (m *MockWebhookUseCase) ResumeWebhook(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookUseCase) DeleteWebhook(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func setupWebhookTestRouter(handler *WebhookHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	webhooks := r.Group("/api/v1/webhooks")
+	{
+		webhooks.POST("", handler.CreateWebhook)
+		webhooks.GET("/:id", handler.GetWebhook)
+		webhooks.GET("", handler.GetWebhooks)
+		webhooks.DELETE("/:id", handler.DeleteWebhook)
+		webhooks.GET("/:id/health", handler.GetWebhookHealth)
+		webhooks.POST("/:id/resume", handler.ResumeWebhook)
+	}
+
+	return r
+}
+
+func ownedWebhook(id, storeID int64) *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{ID: id, URL: "https://hooks.example.com/products", StoreID: sql.NullInt64{Int64: storeID, Valid: true}}
+}
+
+func webhookRequest(method, path string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("X-API-Key", testAPIKey)
+	return req
+}
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		requestBody  interface{}
+		mockFn       func(*MockWebhookUseCase)
+		expectedCode int
+	}{
+		{
+			name:        "successful creation",
+			requestBody: map[string]interface{}{"url": "https://hooks.example.com/products", "description": "ERP", "store_id": 3},
+			mockFn: func(m *MockWebhookUseCase) {
+				m.On("CreateWebhook", mock.Anything, mock.MatchedBy(func(s *domain.WebhookSubscription) bool {
+					return s.URL == "https://hooks.example.com/products" && s.Description == "ERP" && s.StoreID.Int64 == 3
+				})).Return(&domain.WebhookSubscription{ID: 1, URL: "https://hooks.example.com/products"}, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "another store",
+			requestBody:  map[string]interface{}{"url": "https://hooks.example.com/products", "store_id": 4},
+			mockFn:       func(m *MockWebhookUseCase) {},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "every store is for admins",
+			requestBody:  map[string]interface{}{"url": "https://hooks.example.com/products"},
+			mockFn:       func(m *MockWebhookUseCase) {},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "missing url",
+			requestBody:  map[string]interface{}{"description": "ERP", "store_id": 3},
+			mockFn:       func(m *MockWebhookUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:        "domain validation error",
+			requestBody: map[string]interface{}{"url": "ftp://hooks.example.com/products", "store_id": 3},
+			mockFn: func(m *MockWebhookUseCase) {
+				m.On("CreateWebhook", mock.Anything, mock.Anything).Return(nil, domain.ErrInvalidWebhook)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockWebhookUseCase{}
+			tt.mockFn(mockUseCase)
+
+			router := setupWebhookTestRouter(NewWebhookHandler(mockUseCase))
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_DeleteWebhook(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		id           string
+		mockFn       func(*MockWebhookUseCase)
+		expectedCode int
+	}{
+		{
+			name: "successful deletion",
+			id:   "1",
+			mockFn: func(m *MockWebhookUseCase) {
+				m.On("GetWebhook", mock.Anything, int64(1)).Return(ownedWebhook(1, 3), nil)
+				m.On("DeleteWebhook", mock.Anything, int64(1)).Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "invalid ID",
+			id:           "abc",
+			mockFn:       func(m *MockWebhookUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "not found",
+			id:   "9",
+			mockFn: func(m *MockWebhookUseCase) {
+				m.On("GetWebhook", mock.Anything, int64(9)).Return(nil, domain.ErrWebhookNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "another store",
+			id:   "2",
+			mockFn: func(m *MockWebhookUseCase) {
+				m.On("GetWebhook", mock.Anything, int64(2)).Return(ownedWebhook(2, 4), nil)
+			},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockWebhookUseCase{}
+			tt.mockFn(mockUseCase)
+
+			router := setupWebhookTestRouter(NewWebhookHandler(mockUseCase))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, webhookRequest(http.MethodDelete, "/api/v1/webhooks/"+tt.id))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_ResumeWebhook(t *testing.T) {
+	mockUseCase := &MockWebhookUseCase{}
+	mockUseCase.On("GetWebhook", mock.Anything, int64(1)).Return(ownedWebhook(1, 3), nil)
+	mockUseCase.On("GetWebhook", mock.Anything, int64(9)).Return(nil, domain.ErrWebhookNotFound)
+	mockUseCase.On("ResumeWebhook", mock.Anything, int64(1)).Return(&domain.WebhookSubscription{ID: 1}, nil)
+	router := setupWebhookTestRouter(NewWebhookHandler(mockUseCase))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, webhookRequest(http.MethodPost, "/api/v1/webhooks/1/resume"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"active"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, webhookRequest(http.MethodPost, "/api/v1/webhooks/9/resume"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestWebhookHandler_GetWebhookHealth(t *testing.T) {
+	mockUseCase := &MockWebhookUseCase{}
+	mockUseCase.On("GetWebhook", mock.Anything, int64(1)).Return(ownedWebhook(1, 3), nil)
+	mockUseCase.On("GetWebhookHealth", mock.Anything, int64(1)).Return(&domain.WebhookHealth{
+		SubscriptionID: 1, Deliveries: 4, Failures: 1, SuccessRate: 0.75, AvgLatency: 120 * time.Millisecond,
+	}, nil)
+	router := setupWebhookTestRouter(NewWebhookHandler(mockUseCase))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, webhookRequest(http.MethodGet, "/api/v1/webhooks/1/health"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.WebhookHealthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 0.75, response.SuccessRate)
+	assert.Equal(t, int64(120), response.AvgLatencyMs)
+	mockUseCase.AssertExpectations(t)
+}
+
+func TestWebhookHandler_GetWebhooks(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		mockFn       func(*MockWebhookUseCase)
+		expectedCode int
+	}{
+		{
+			name:  "own store",
+			query: "?store_id=5",
+			mockFn: func(m *MockWebhookUseCase) {
+				m.On("GetWebhooks", mock.Anything, int64(5), 10, 0).Return([]*domain.WebhookSubscription{ownedWebhook(1, 5)}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "another store",
+			query:        "?store_id=4",
+			mockFn:       func(m *MockWebhookUseCase) {},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "every store is for admins",
+			mockFn:       func(m *MockWebhookUseCase) {},
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockWebhookUseCase{}
+			tt.mockFn(mockUseCase)
+
+			router := setupWebhookTestRouter(NewWebhookHandler(mockUseCase))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, webhookRequest(http.MethodGet, "/api/v1/webhooks"+tt.query))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+
+	t.Run("anonymous", func(t *testing.T) {
+		router := setupWebhookTestRouter(NewWebhookHandler(&MockWebhookUseCase{}))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/webhooks?store_id=5", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookSecretHandler manages webhook signing secrets. Callers manage the
+// secrets of stores they own; without store_id the requests act on the
+// secret of subscriptions that span stores, which only admins may do.
+type WebhookSecretHandler struct {
+	webhookSecretUseCase usecase.WebhookSecretUseCaseInterface
+}
+
+func NewWebhookSecretHandler(webhookSecretUseCase usecase.WebhookSecretUseCaseInterface) *WebhookSecretHandler {
+	return &WebhookSecretHandler{
+		webhookSecretUseCase: webhookSecretUseCase,
+	}
+}
+
+func (h *WebhookSecretHandler) RotateSecret(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseStoreIDQuery(c)
+	if !ok || !requireStoreOwner(c, storeID) {
+		return
+	}
+
+	secret, err := h.webhookSecretUseCase.RotateSecret(ctx, storeID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToWebhookSecretResponse(secret))
+}
+
+func (h *WebhookSecretHandler) RevokePreviousSecret(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	storeID, ok := parseStoreIDQuery(c)
+	if !ok || !requireStoreOwner(c, storeID) {
+		return
+	}
+
+	if err := h.webhookSecretUseCase.RevokePreviousSecret(ctx, storeID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WebhookSecretHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWebhookSecretNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "webhook_secret_not_found",
+			Message: "No webhook secret has been issued for this store",
+		})
+	case errors.Is(err, domain.ErrInvalidWebhook):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	default:
+		logger.FromContext(c.Request.Context()).WithError(err).Error("Internal server error")
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "internal_server_error",
+			Message: "An internal error occurred",
+		})
+	}
+}
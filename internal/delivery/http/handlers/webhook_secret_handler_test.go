@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockWebhookSecretUseCase struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSecretUseCase) RotateSecret(ctx context.Context, storeID int64) (*domain.WebhookSigningSecret, error) {
+	args := m.Called(ctx, storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSigningSecret), args.Error(1)
+}
+
+func (m *MockWebhookSecretUseCase) RevokePreviousSecret(ctx context.Context, storeID int64) error {
+	args := m.Called(ctx, storeID)
+	return args.Error(0)
+}
+
+func setupWebhookSecretTestRouter(handler *WebhookSecretHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedTestKeys())
+
+	secrets := r.Group("/api/v1/webhook-secrets")
+	{
+		secrets.POST("/rotate", handler.RotateSecret)
+		secrets.DELETE("/previous", handler.RevokePreviousSecret)
+	}
+
+	return r
+}
+
+func TestWebhookSecretHandler_RotateSecret(t *testing.T) {
+	rotatedAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		query        string
+		anonymous    bool
+		mockFn       func(*MockWebhookSecretUseCase)
+		expectedCode int
+	}{
+		{
+			name:  "store secret",
+			query: "?store_id=3",
+			mockFn: func(m *MockWebhookSecretUseCase) {
+				m.On("RotateSecret", mock.Anything, int64(3)).Return(&domain.WebhookSigningSecret{StoreID: 3, Current: "whsec_new", RotatedAt: rotatedAt}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "global secret is for admins",
+			query:        "",
+			mockFn:       func(m *MockWebhookSecretUseCase) {},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "another store's secret",
+			query:        "?store_id=4",
+			mockFn:       func(m *MockWebhookSecretUseCase) {},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "anonymous",
+			query:        "?store_id=3",
+			anonymous:    true,
+			mockFn:       func(m *MockWebhookSecretUseCase) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "invalid store ID",
+			query:        "?store_id=-1",
+			mockFn:       func(m *MockWebhookSecretUseCase) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "store error",
+			query: "?store_id=3",
+			mockFn: func(m *MockWebhookSecretUseCase) {
+				m.On("RotateSecret", mock.Anything, int64(3)).Return(nil, errors.New("db down"))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockWebhookSecretUseCase{}
+			tt.mockFn(mockUseCase)
+
+			router := setupWebhookSecretTestRouter(NewWebhookSecretHandler(mockUseCase))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/webhook-secrets/rotate"+tt.query, nil)
+			if !tt.anonymous {
+				req.Header.Set("X-API-Key", testAPIKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				var resp dto.WebhookSecretResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, "whsec_new", resp.Secret)
+			}
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookSecretHandler_RevokePreviousSecret(t *testing.T) {
+	tests := []struct {
+		name         string
+		mockErr      error
+		expectedCode int
+	}{
+		{name: "revoked", expectedCode: http.StatusNoContent},
+		{name: "no secret", mockErr: domain.ErrWebhookSecretNotFound, expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := &MockWebhookSecretUseCase{}
+			mockUseCase.On("RevokePreviousSecret", mock.Anything, int64(5)).Return(tt.mockErr)
+
+			router := setupWebhookSecretTestRouter(NewWebhookSecretHandler(mockUseCase))
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/webhook-secrets/previous?store_id=5", nil)
+			req.Header.Set("X-API-Key", testAPIKey)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			mockUseCase.AssertExpectations(t)
+		})
+	}
+}
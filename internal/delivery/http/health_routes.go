@@ -0,0 +1,15 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// HealthRoutes serves the Kubernetes probes; /health is kept for
+// existing checks.
+func HealthRoutes(handler *handlers.HealthHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Root.GET("/health", handler.Live)
+		r.Root.GET("/healthz", handler.Live)
+		r.Root.GET("/readyz", handler.Ready)
+	})
+}
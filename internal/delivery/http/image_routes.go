@@ -0,0 +1,13 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// ImageRoutes serves the images attached to products. Images are
+// stored in S3 and are left out without a bucket.
+func ImageRoutes(handler *handlers.ImageHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Products.GET("/:id/images", handler.GetProductImages)
+	})
+}
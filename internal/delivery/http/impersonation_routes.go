@@ -0,0 +1,12 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// ImpersonationRoutes lets admins act as a user.
+func ImpersonationRoutes(handler *handlers.ImpersonationHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Admin.POST("/impersonate/:user_id", handler.Impersonate)
+	})
+}
@@ -0,0 +1,20 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// ImportMappingRoutes serves stores' import mappings.
+func ImportMappingRoutes(handler *handlers.ImportMappingHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		importMappings := r.API.Group("/stores/:id/import-mappings")
+		{
+			importMappings.POST("", handler.CreateMapping)
+			importMappings.GET("", handler.GetMappings)
+			importMappings.GET("/:mapping_id", handler.GetMapping)
+			importMappings.PUT("/:mapping_id", handler.UpdateMapping)
+			importMappings.DELETE("/:mapping_id", handler.DeleteMapping)
+			importMappings.POST("/:mapping_id/test", handler.TestMapping)
+		}
+	})
+}
@@ -0,0 +1,13 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// LifecycleRoutes serves readiness and drains the instance.
+func LifecycleRoutes(handler *handlers.LifecycleHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Root.GET("/ready", handler.Ready)
+		r.Admin.POST("/drain", handler.Drain)
+	})
+}
@@ -0,0 +1,16 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// LiveConfigRoutes serves the live configuration under /admin.
+func LiveConfigRoutes(handler *handlers.LiveConfigHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Admin.GET("/config", handler.GetSettings)
+		r.Admin.GET("/config/:key", handler.GetSetting)
+		r.Admin.PUT("/config/:key", handler.SaveSetting)
+		r.Admin.GET("/stores/:store_id/settings", handler.GetStoreSettings)
+		r.Admin.PUT("/stores/:store_id/settings", handler.SaveStoreSettings)
+	})
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/apikey"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const apiKeyContextKey = "api_key"
+
+// APIKey checks the X-API-Key header against the issued keys. Unknown and
+// revoked keys get 401; requests without the header pass through and are
+// identified by session, workload or client IP instead.
+func APIKey(store apikey.Store, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.GetHeader("X-API-Key")
+		if raw == "" {
+			c.Next()
+			return
+		}
+
+		key, err := store.Lookup(c.Request.Context(), apikey.Hash(raw))
+		switch {
+		case err == nil:
+			c.Set(apiKeyContextKey, key)
+		case errors.Is(err, apikey.ErrNotFound):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error:   "invalid_api_key",
+				Message: "The X-API-Key is unknown or revoked",
+			})
+			return
+		default:
+			logger.WithError(err).Error("Failed to look up API key")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+				Error:   "api_key_unavailable",
+				Message: "API keys cannot be checked right now",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CurrentAPIKey returns the validated key the request was sent with, or nil.
+func CurrentAPIKey(c *gin.Context) *apikey.Key {
+	if value, ok := c.Get(apiKeyContextKey); ok {
+		return value.(*apikey.Key)
+	}
+	return nil
+}
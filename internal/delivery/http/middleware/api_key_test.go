@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/pkg/apikey"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// issuedKeys accepts the given raw keys as valid API keys.
+func issuedKeys(raw ...string) gin.HandlerFunc {
+	keys := make([]*apikey.Key, len(raw))
+	for i, key := range raw {
+		keys[i] = &apikey.Key{ID: int64(i + 1), Name: key, Hash: apikey.Hash(key)}
+	}
+	return APIKey(apikey.NewMemoryStore(keys...), logrus.New())
+}
+
+func TestAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(issuedKeys("secret-key"))
+	r.GET("/whoami", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"identity": ClientIdentity(c), "authenticated": Authenticated(c)})
+	})
+
+	tests := []struct {
+		name         string
+		apiKey       string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "issued key",
+			apiKey:       "secret-key",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"identity":"key:` + apikey.Hash("secret-key")[:32] + `","authenticated":true}`,
+		},
+		{
+			name:         "unknown key",
+			apiKey:       "made-up",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "no key",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"identity":"ip:192.0.2.1","authenticated":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
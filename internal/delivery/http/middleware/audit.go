@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const auditWriteTimeout = 5 * time.Second
+
+type AuditRecorder interface {
+	Create(ctx context.Context, entry *domain.AuditEntry) error
+}
+
+// Audit records every state-changing request once it has been handled.
+// Reads are not audited. A failed write is logged rather than failing the
+// request, which has already been served.
+func Audit(recorder AuditRecorder, clk clock.Clock, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		entry := &domain.AuditEntry{
+			Actor:          ClientIdentity(c),
+			ImpersonatedBy: ImpersonatedBy(c),
+			ClientIP:       c.ClientIP(),
+			Method:         c.Request.Method,
+			Route:          route,
+			Path:           c.Request.URL.Path,
+			StatusCode:     c.Writer.Status(),
+			OccurredAt:     clk.Now().UTC(),
+		}
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), auditWriteTimeout)
+		defer cancel()
+
+		if err := recorder.Create(ctx, entry); err != nil {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"method": entry.Method,
+				"path":   entry.Path,
+			}).Error("Failed to record audit entry")
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAuditRecorder struct {
+	entries []*domain.AuditEntry
+}
+
+func (r *recordingAuditRecorder) Create(_ context.Context, entry *domain.AuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := &recordingAuditRecorder{}
+
+	r := gin.New()
+	r.Use(issuedKeys("secret-key"))
+	r.Use(Audit(recorder, clock.Real(), logrus.New()))
+	r.GET("/api/v1/products/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.DELETE("/api/v1/products/:id", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products/1", nil))
+	assert.Empty(t, recorder.entries, "reads are not audited")
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/products/1", nil)
+	req.Header.Set("X-API-Key", "secret-key")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Len(t, recorder.entries, 1)
+	entry := recorder.entries[0]
+	assert.Equal(t, http.MethodDelete, entry.Method)
+	assert.Equal(t, "/api/v1/products/:id", entry.Route)
+	assert.Equal(t, "/api/v1/products/1", entry.Path)
+	assert.Equal(t, http.StatusNoContent, entry.StatusCode)
+	assert.Regexp(t, `^key:[0-9a-f]{32}$`, entry.Actor)
+	assert.NotContains(t, entry.Actor, "secret-key")
+}
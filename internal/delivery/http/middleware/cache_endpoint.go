@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"backend-context-engineering-template/pkg/cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheEndpoint tags the request context with the matched route, so cache
+// lookups made while serving it are counted per endpoint.
+func CacheEndpoint() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		c.Request = c.Request.WithContext(cache.WithEndpoint(c.Request.Context(), c.Request.Method+" "+route))
+		c.Next()
+	}
+}
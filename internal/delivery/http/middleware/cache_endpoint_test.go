@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/pkg/cache"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var endpoint string
+	r := gin.New()
+	r.Use(CacheEndpoint())
+	r.GET("/api/v1/products/:id", func(c *gin.Context) {
+		endpoint = cache.EndpointFromContext(c.Request.Context())
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/products/42", nil))
+	assert.Equal(t, "GET /api/v1/products/:id", endpoint)
+}
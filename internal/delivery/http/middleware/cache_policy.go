@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	noStoreContextKey        = "cache_no_store"
+	listedProductsContextKey = "cache_listed_products"
+)
+
+// SurrogateKeyHeader is the response header a CDN reads surrogate keys
+// from and how the keys in it are separated.
+type SurrogateKeyHeader struct {
+	Name      string
+	Separator string
+}
+
+var (
+	FastlySurrogateKeys = SurrogateKeyHeader{Name: "Surrogate-Key", Separator: " "}
+	CloudflareCacheTags = SurrogateKeyHeader{Name: "Cache-Tag", Separator: ","}
+)
+
+// CachePolicy says how long shared caches such as a CDN may keep a route's
+// responses and which surrogate keys tag them for purging.
+type CachePolicy struct {
+	// MaxAge is how long browsers may reuse a response.
+	MaxAge time.Duration
+	// SharedMaxAge is how long a CDN may keep a response; it is normally
+	// far longer than MaxAge because the CDN is purged when the data
+	// changes and browsers are not.
+	SharedMaxAge time.Duration
+	// StaleWhileRevalidate is how long after expiry a cached response may
+	// still be served while a fresh one is fetched.
+	StaleWhileRevalidate time.Duration
+	// SurrogateKeys returns the keys that purge the response; it may be
+	// nil.
+	SurrogateKeys func(c *gin.Context) []string
+}
+
+func (p CachePolicy) header() string {
+	value := "public, max-age=" + seconds(p.MaxAge) + ", s-maxage=" + seconds(p.SharedMaxAge)
+	if p.StaleWhileRevalidate > 0 {
+		value += ", stale-while-revalidate=" + seconds(p.StaleWhileRevalidate)
+	}
+	return value
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+// CacheControl sets Cache-Control on the responses of the routes in
+// policies, keyed like RouteCosts. Only successful responses to anonymous
+// requests are made public: anything sent with credentials may differ per
+// caller, and errors are not worth keeping, so those get "private,
+// no-store". Routes without a policy are left alone. Surrogate keys are
+// written to keyHeader.
+func CacheControl(policies map[string]CachePolicy, keyHeader SurrogateKeyHeader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, ok := cachePolicy(c, policies)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		anonymous := !hasCredentials(c.Request)
+		c.Writer = &cacheControlWriter{
+			ResponseWriter: c.Writer,
+			apply: func(w gin.ResponseWriter) {
+				header := w.Header()
+				if anonymous && w.Status() == http.StatusOK && !c.GetBool(noStoreContextKey) {
+					header.Set("Cache-Control", policy.header())
+					if policy.SurrogateKeys != nil {
+						if keys := policy.SurrogateKeys(c); len(keys) > 0 {
+							header.Set(keyHeader.Name, strings.Join(keys, keyHeader.Separator))
+						}
+					}
+				} else {
+					header.Set("Cache-Control", "private, no-store")
+				}
+				// A CDN must not answer a request with credentials from
+				// the response cached for one without.
+				header.Add("Vary", "Authorization, X-API-Key, Cookie")
+			},
+		}
+		c.Next()
+	}
+}
+
+// NoStore keeps the response to c out of shared caches even when its route
+// has a cache policy, for responses that depend on something other than
+// the URL, such as a preview token.
+func NoStore(c *gin.Context) {
+	c.Set(noStoreContextKey, true)
+}
+
+// SetListedProducts records the products a list response shows, so its
+// cache policy can tag it with their surrogate keys.
+func SetListedProducts(c *gin.Context, ids []int64) {
+	c.Set(listedProductsContextKey, ids)
+}
+
+// ListedProducts returns the products recorded by SetListedProducts.
+func ListedProducts(c *gin.Context) []int64 {
+	ids, _ := c.Get(listedProductsContextKey)
+	listed, _ := ids.([]int64)
+	return listed
+}
+
+func cachePolicy(c *gin.Context, policies map[string]CachePolicy) (CachePolicy, bool) {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return CachePolicy{}, false
+	}
+	route := "GET " + c.FullPath()
+	// Streams are cached only when their own key has a policy.
+	if c.Query("stream") == "true" {
+		route += "?stream=true"
+	}
+	policy, ok := policies[route]
+	return policy, ok
+}
+
+func hasCredentials(r *http.Request) bool {
+	return r.Header.Get("Authorization") != "" ||
+		r.Header.Get("X-API-Key") != "" ||
+		r.Header.Get("Cookie") != "" ||
+		(r.TLS != nil && len(r.TLS.PeerCertificates) > 0)
+}
+
+// cacheControlWriter sets the caching headers just before the response
+// header is written, once the status is known.
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	apply   func(w gin.ResponseWriter)
+	applied bool
+}
+
+func (w *cacheControlWriter) before() {
+	if !w.applied && !w.ResponseWriter.Written() {
+		w.applied = true
+		w.apply(w.ResponseWriter)
+	}
+}
+
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.before()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheControlWriter) Write(data []byte) (int, error) {
+	w.before()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.before()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *cacheControlWriter) Flush() {
+	w.before()
+	w.ResponseWriter.Flush()
+}
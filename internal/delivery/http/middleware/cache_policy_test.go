@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(CacheControl(map[string]CachePolicy{
+		"GET /items": {
+			MaxAge:       30 * time.Second,
+			SharedMaxAge: time.Minute,
+			SurrogateKeys: func(c *gin.Context) []string {
+				keys := []string{"items"}
+				for _, id := range ListedProducts(c) {
+					keys = append(keys, "item-"+strconv.FormatInt(id, 10))
+				}
+				return keys
+			},
+		},
+		"GET /items/:id": {
+			MaxAge:               30 * time.Second,
+			SharedMaxAge:         time.Hour,
+			StaleWhileRevalidate: 10 * time.Second,
+			SurrogateKeys: func(c *gin.Context) []string {
+				return []string{"items", "item-" + c.Param("id")}
+			},
+		},
+	}, FastlySurrogateKeys))
+	r.GET("/items", func(c *gin.Context) {
+		SetListedProducts(c, []int64{3, 5})
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	r.GET("/items/:id", func(c *gin.Context) {
+		switch c.Param("id") {
+		case "missing":
+			c.JSON(http.StatusNotFound, gin.H{"error": "not_found"})
+			return
+		case "preview":
+			NoStore(c)
+		}
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+	r.GET("/other", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	tests := []struct {
+		name         string
+		target       string
+		header       string
+		cacheControl string
+		surrogateKey string
+	}{
+		{
+			name:         "anonymous read is public",
+			target:       "/items/7",
+			cacheControl: "public, max-age=30, s-maxage=3600, stale-while-revalidate=10",
+			surrogateKey: "items item-7",
+		},
+		{
+			name:         "list is tagged with the listed items",
+			target:       "/items",
+			cacheControl: "public, max-age=30, s-maxage=60",
+			surrogateKey: "items item-3 item-5",
+		},
+		{
+			name:         "api key",
+			target:       "/items/7",
+			header:       "X-API-Key",
+			cacheControl: "private, no-store",
+		},
+		{
+			name:         "session cookie",
+			target:       "/items/7",
+			header:       "Cookie",
+			cacheControl: "private, no-store",
+		},
+		{
+			name:         "errors are not cached",
+			target:       "/items/missing",
+			cacheControl: "private, no-store",
+		},
+		{
+			name:         "handler opted out",
+			target:       "/items/preview",
+			cacheControl: "private, no-store",
+		},
+		{
+			name:   "route without a policy",
+			target: "/other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, "secret")
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.cacheControl, w.Header().Get("Cache-Control"))
+			assert.Equal(t, tt.surrogateKey, w.Header().Get("Surrogate-Key"))
+			if tt.cacheControl != "" {
+				assert.Equal(t, "Authorization, X-API-Key, Cookie", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestCacheControl_CloudflareCacheTags(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(CacheControl(map[string]CachePolicy{
+		"GET /items": {
+			MaxAge:       30 * time.Second,
+			SharedMaxAge: time.Minute,
+			SurrogateKeys: func(c *gin.Context) []string {
+				return []string{"items", "store:1"}
+			},
+		},
+	}, CloudflareCacheTags))
+	r.GET("/items", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	assert.Equal(t, "items,store:1", w.Header().Get("Cache-Tag"))
+	assert.Empty(t, w.Header().Get("Surrogate-Key"))
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultRequestCost = 1
+
+// CostLimiter meters requests in units per API key (or client IP when no key
+// is sent). Every request is charged its route cost, but once the budget for
+// the window is spent only expensive routes are refused; plain reads keep
+// working so a throttled client is never locked out entirely.
+type CostLimiter struct {
+	store  ratelimit.Store
+	limit  int64
+	window time.Duration
+	costs  map[string]int64
+	logger *logrus.Logger
+}
+
+func NewCostLimiter(store ratelimit.Store, limit int64, window time.Duration, costs map[string]int64, logger *logrus.Logger) *CostLimiter {
+	return &CostLimiter{
+		store:  store,
+		limit:  limit,
+		window: window,
+		costs:  costs,
+		logger: logger,
+	}
+}
+
+func (l *CostLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cost := l.cost(c)
+
+		used, resetAt, err := l.store.Consume(c.Request.Context(), l.key(c), cost, l.window)
+		if err != nil {
+			// Fail open: metering problems must not take the API down.
+			l.logger.WithError(err).Warn("Rate limit store unavailable")
+			c.Next()
+			return
+		}
+
+		remaining := l.limit - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		c.Header("X-RateLimit-Cost", strconv.FormatInt(cost, 10))
+		c.Header("X-RateLimit-Limit", strconv.FormatInt(l.limit, 10))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+		if used > l.limit && cost > defaultRequestCost {
+			retryAfter := int64(time.Until(resetAt).Seconds()) + 1
+			c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.ErrorResponse{
+				Error:   "rate_limited",
+				Message: "Request budget exhausted for expensive operations, retry after the window resets",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func (l *CostLimiter) cost(c *gin.Context) int64 {
+	route := c.Request.Method + " " + c.FullPath()
+	if c.Query("stream") == "true" {
+		if cost, ok := l.costs[route+"?stream=true"]; ok {
+			return cost
+		}
+	}
+	if cost, ok := l.costs[route]; ok {
+		return cost
+	}
+	return defaultRequestCost
+}
+
+func (l *CostLimiter) key(c *gin.Context) string {
+	return "cost:" + ClientIdentity(c)
+}
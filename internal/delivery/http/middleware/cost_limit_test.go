@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCostLimitRouter(limit int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	limiter := NewCostLimiter(ratelimit.NewMemoryStore(clock.Real()), limit, time.Minute, map[string]int64{
+		"POST /export":           4,
+		"GET /items?stream=true": 3,
+	}, logrus.New())
+	r.Use(issuedKeys("key-a", "key-b"))
+	r.Use(limiter.Middleware())
+
+	r.GET("/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/export", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return r
+}
+
+func TestCostLimiter(t *testing.T) {
+	router := setupCostLimitRouter(5)
+
+	do := func(method, path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/export", "key-a")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "4", w.Header().Get("X-RateLimit-Cost"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = do(http.MethodPost, "/export", "key-a")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// Cheap requests still go through once the budget is spent.
+	w = do(http.MethodGet, "/items", "key-a")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Cost"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	// Budgets are tracked per API key.
+	w = do(http.MethodPost, "/export", "key-b")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Streaming a route is priced separately from a page of it.
+	w = do(http.MethodGet, "/items?stream=true", "key-b")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "3", w.Header().Get("X-RateLimit-Cost"))
+}
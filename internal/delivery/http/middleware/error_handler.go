@@ -12,7 +12,7 @@ import (
 func ErrorHandler(logger *logrus.Logger) gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
 		if err, ok := recovered.(string); ok {
-			logger.WithFields(logrus.Fields{
+			logger.WithContext(c.Request.Context()).WithFields(logrus.Fields{
 				"error":  err,
 				"path":   c.Request.URL.Path,
 				"method": c.Request.Method,
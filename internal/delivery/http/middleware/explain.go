@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"time"
+
+	"backend-context-engineering-template/pkg/explain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ExplainSlow records the queries each request runs and, when the request
+// takes longer than the capturer's threshold, hands the slowest one to the
+// capturer to log its plan.
+func ExplainSlow(capturer *explain.Capturer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := explain.WithRecorder(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		start := time.Now()
+		c.Next()
+		latency := time.Since(start)
+
+		if latency < capturer.Threshold() {
+			return
+		}
+		query, ok := explain.Slowest(ctx)
+		if !ok {
+			return
+		}
+		capturer.Capture(logrus.Fields{
+			"method":      c.Request.Method,
+			"route":       c.FullPath(),
+			"status_code": c.Writer.Status(),
+			"latency":     latency,
+		}, query)
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/explain"
+	"backend-context-engineering-template/pkg/ratelimit"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainSlow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	// A zero budget counts every capture attempt as skipped without
+	// touching a database.
+	capturer := explain.NewCapturer(nil, ratelimit.NewMemoryStore(clock.Real()), registry, explain.Config{
+		Threshold:  20 * time.Millisecond,
+		SampleRate: 1,
+	}, logrus.New())
+
+	r := gin.New()
+	r.Use(ExplainSlow(capturer))
+	r.GET("/fast", func(c *gin.Context) {
+		explain.Track(c.Request.Context(), "SELECT 1")()
+	})
+	r.GET("/slow", func(c *gin.Context) {
+		explain.Track(c.Request.Context(), "SELECT 1")()
+		time.Sleep(30 * time.Millisecond)
+	})
+	r.GET("/slow-without-queries", func(c *gin.Context) {
+		time.Sleep(30 * time.Millisecond)
+	})
+
+	for _, path := range []string{"/fast", "/slow", "/slow-without-queries"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	capturer.Wait()
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `explain_captures_total{result="skipped"} 1`)
+}
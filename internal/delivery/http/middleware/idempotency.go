@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed for a retry.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	idempotencyWriteTimeout = 5 * time.Second
+	// maxIdempotentBodyBytes caps the request bodies read to hash them.
+	maxIdempotentBodyBytes = 1 << 20
+)
+
+// replayedHeaders are the response headers recorded with the body, so
+// retries learn where the resource was created and its version.
+var replayedHeaders = []string{"Location", "ETag"}
+
+// IdempotencyStore records requests sent with an Idempotency-Key and their
+// responses.
+type IdempotencyStore interface {
+	// Reserve records key as in progress and returns nil, unless its owner
+	// already used the key. It then returns the existing record instead,
+	// unless that was created before expiredBefore, or is still in
+	// progress since before abandonedBefore, when key replaces it.
+	Reserve(ctx context.Context, key *domain.IdempotencyKey, expiredBefore, abandonedBefore time.Time) (*domain.IdempotencyKey, error)
+	// Complete records the response to key's request.
+	Complete(ctx context.Context, key *domain.IdempotencyKey) error
+	// Release forgets a key whose request failed, so a retry runs again.
+	Release(ctx context.Context, owner, key string) error
+}
+
+type IdempotencyConfig struct {
+	// TTL is how long a response is replayed to retries.
+	TTL time.Duration
+	// LockTimeout is how long a request in progress holds its key. A
+	// request that outlives it, or an instance that stopped while handling
+	// it, lets a retry run again.
+	LockTimeout time.Duration
+}
+
+// Idempotency makes the listed routes safe to retry: a request sent with an
+// Idempotency-Key header is handled once per caller and key, and retries of
+// it get the first response, with its Location and ETag headers, marked
+// with Idempotent-Replayed. Reusing a key for a different request is
+// refused with 422, retrying while the first request is still being
+// handled with 409, and a body over 1 MB with 413. Server errors are not
+// recorded, so the request can be retried. Routes are keyed by method and
+// route, as in RouteCosts. If the store fails, the request is handled
+// without a key rather than refused.
+func Idempotency(store IdempotencyStore, routes map[string]bool, cfg IdempotencyConfig, clk clock.Clock, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !routes[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_idempotency_key",
+				Message: "The Idempotency-Key header must be at most 255 characters",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIdempotentBodyBytes))
+		if errors.As(err, new(*http.MaxBytesError)) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+				Error:   "request_too_large",
+				Message: "The request body must not exceed 1 MB",
+			})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "Failed to read request body",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		log := logger.WithContext(ctx).WithField("idempotency_key", key)
+		now := clk.Now().UTC()
+		record := &domain.IdempotencyKey{
+			Owner:       ClientIdentity(c),
+			Key:         key,
+			RequestHash: requestHash(c.Request, body),
+			CreatedAt:   now,
+		}
+
+		existing, err := store.Reserve(ctx, record, now.Add(-cfg.TTL), now.Add(-cfg.LockTimeout))
+		switch {
+		case err != nil:
+			log.WithError(err).Warn("Failed to reserve idempotency key, handling the request without it")
+			c.Next()
+			return
+		case existing == nil:
+		case existing.RequestHash != record.RequestHash:
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+				Error:   "idempotency_key_reused",
+				Message: "The Idempotency-Key was already used for a different request",
+			})
+			return
+		case !existing.Completed():
+			c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "idempotency_key_in_progress",
+				Message: "A request with this Idempotency-Key is still being handled, retry later",
+			})
+			return
+		default:
+			for name, value := range existing.Headers {
+				c.Header(name, value)
+			}
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+			c.Abort()
+			return
+		}
+
+		// The store is written once the handler is done, however long it
+		// took, and even if the client went away meanwhile.
+		writeContext := func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.WithoutCancel(ctx), idempotencyWriteTimeout)
+		}
+		release := func() {
+			writeCtx, cancel := writeContext()
+			defer cancel()
+			if err := store.Release(writeCtx, record.Owner, record.Key); err != nil {
+				log.WithError(err).Error("Failed to release idempotency key")
+			}
+		}
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				release()
+				panic(recovered)
+			}
+		}()
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		record.StatusCode = writer.Status()
+		if record.StatusCode >= http.StatusInternalServerError {
+			release()
+			return
+		}
+		record.ContentType = writer.Header().Get("Content-Type")
+		record.Headers = make(map[string]string)
+		for _, name := range replayedHeaders {
+			if value := writer.Header().Get(name); value != "" {
+				record.Headers[name] = value
+			}
+		}
+		record.Body = writer.body.Bytes()
+		writeCtx, cancel := writeContext()
+		defer cancel()
+		if err := store.Complete(writeCtx, record); err != nil {
+			log.WithError(err).Error("Failed to record idempotent response")
+		}
+	}
+}
+
+// requestHash identifies a request by its method, URL and body.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// recordingWriter keeps a copy of the response body.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
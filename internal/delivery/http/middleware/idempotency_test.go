@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/apikey"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryIdempotencyStore keeps keys in a map, as the postgres repository
+// keeps them in a table.
+type memoryIdempotencyStore struct {
+	keys map[string]*domain.IdempotencyKey
+	err  error
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{keys: map[string]*domain.IdempotencyKey{}}
+}
+
+func (s *memoryIdempotencyStore) Reserve(_ context.Context, key *domain.IdempotencyKey, expiredBefore, abandonedBefore time.Time) (*domain.IdempotencyKey, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	existing, ok := s.keys[key.Owner+" "+key.Key]
+	if ok && !existing.CreatedAt.Before(expiredBefore) && (existing.Completed() || !existing.CreatedAt.Before(abandonedBefore)) {
+		copied := *existing
+		return &copied, nil
+	}
+	copied := *key
+	s.keys[key.Owner+" "+key.Key] = &copied
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(_ context.Context, key *domain.IdempotencyKey) error {
+	copied := *key
+	s.keys[key.Owner+" "+key.Key] = &copied
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, owner, key string) error {
+	delete(s.keys, owner+" "+key)
+	return nil
+}
+
+func newIdempotencyRouter(store IdempotencyStore, fake *clock.Fake, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedKeys("secret-key", "other-key"))
+	r.Use(Idempotency(store, map[string]bool{"POST /products": true},
+		IdempotencyConfig{TTL: 24 * time.Hour, LockTimeout: time.Minute}, fake, logrus.New()))
+	r.POST("/products", handler)
+	r.POST("/other", handler)
+	return r
+}
+
+func postWithKey(r http.Handler, path, apiKey, idempotencyKey, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("X-API-Key", apiKey)
+	if idempotencyKey != "" {
+		req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysResponse(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	created := 0
+	r := newIdempotencyRouter(newMemoryIdempotencyStore(), fake, func(c *gin.Context) {
+		created++
+		c.JSON(http.StatusCreated, gin.H{"id": created})
+	})
+
+	first := postWithKey(r, "/products", "secret-key", "key-1", `{"name":"Desk"}`)
+	require.Equal(t, http.StatusCreated, first.Code)
+	retry := postWithKey(r, "/products", "secret-key", "key-1", `{"name":"Desk"}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "application/json; charset=utf-8", retry.Header().Get("Content-Type"))
+	assert.Equal(t, 1, created)
+
+	postWithKey(r, "/products", "other-key", "key-1", `{"name":"Desk"}`)
+	assert.Equal(t, 2, created, "keys are per caller")
+	postWithKey(r, "/products", "secret-key", "", `{"name":"Desk"}`)
+	assert.Equal(t, 3, created, "requests without a key are not deduplicated")
+	postWithKey(r, "/other", "secret-key", "key-1", `{"name":"Desk"}`)
+	assert.Equal(t, 4, created, "other routes ignore the header")
+
+	fake.Advance(25 * time.Hour)
+	expired := postWithKey(r, "/products", "secret-key", "key-1", `{"name":"Desk"}`)
+	assert.Empty(t, expired.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 5, created, "keys expire after the TTL")
+}
+
+func TestIdempotency_RefusesReuseAndConcurrentRetries(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	store := newMemoryIdempotencyStore()
+	r := newIdempotencyRouter(store, fake, func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	require.Equal(t, http.StatusCreated, postWithKey(r, "/products", "secret-key", "key-1", `{"name":"Desk"}`).Code)
+	w := postWithKey(r, "/products", "secret-key", "key-1", `{"name":"Chair"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "idempotency_key_reused")
+
+	// A request still in progress holds its key until the lock times out.
+	owner := "key:" + apikey.Hash("secret-key")[:32]
+	store.keys[owner+" key-2"] = &domain.IdempotencyKey{
+		Owner: owner, Key: "key-2", RequestHash: store.keys[owner+" key-1"].RequestHash, CreatedAt: fake.Now(),
+	}
+	w = postWithKey(r, "/products", "secret-key", "key-2", `{"name":"Desk"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "idempotency_key_in_progress")
+
+	fake.Advance(2 * time.Minute)
+	assert.Equal(t, http.StatusCreated, postWithKey(r, "/products", "secret-key", "key-2", `{"name":"Desk"}`).Code)
+
+	w = postWithKey(r, "/products", "secret-key", strings.Repeat("k", 256), `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestIdempotency_ServerErrorsAreRetried(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	calls := 0
+	r := newIdempotencyRouter(newMemoryIdempotencyStore(), fake, func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.JSON(http.StatusServiceUnavailable, gin.H{})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	assert.Equal(t, http.StatusServiceUnavailable, postWithKey(r, "/products", "secret-key", "key-1", `{}`).Code)
+	assert.Equal(t, http.StatusCreated, postWithKey(r, "/products", "secret-key", "key-1", `{}`).Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_StoreFailureHandlesRequest(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	store := newMemoryIdempotencyStore()
+	store.err = errors.New("database is down")
+	r := newIdempotencyRouter(store, fake, func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	assert.Equal(t, http.StatusCreated, postWithKey(r, "/products", "secret-key", "key-1", `{}`).Code)
+}
+
+func TestIdempotency_ReplaysLocationAndETag(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	r := newIdempotencyRouter(newMemoryIdempotencyStore(), fake, func(c *gin.Context) {
+		c.Header("Location", "/api/v1/products/7")
+		c.Header("ETag", `"1"`)
+		c.Header("X-Request-Id", "not replayed")
+		c.JSON(http.StatusCreated, gin.H{"id": 7})
+	})
+
+	require.Equal(t, http.StatusCreated, postWithKey(r, "/products", "secret-key", "key-1", `{}`).Code)
+	retry := postWithKey(r, "/products", "secret-key", "key-1", `{}`)
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "/api/v1/products/7", retry.Header().Get("Location"))
+	assert.Equal(t, `"1"`, retry.Header().Get("ETag"))
+	assert.Empty(t, retry.Header().Get("X-Request-Id"))
+}
+
+func TestIdempotency_RefusesLargeBodies(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	store := newMemoryIdempotencyStore()
+	r := newIdempotencyRouter(store, fake, func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	w := postWithKey(r, "/products", "secret-key", "key-1", strings.Repeat("x", maxIdempotentBodyBytes+1))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "request_too_large")
+	assert.Empty(t, store.keys)
+}
+
+// deadlineStore records the deadline of the context Complete is called
+// with.
+type deadlineStore struct {
+	*memoryIdempotencyStore
+	deadline time.Time
+}
+
+func (s *deadlineStore) Complete(ctx context.Context, key *domain.IdempotencyKey) error {
+	s.deadline, _ = ctx.Deadline()
+	return s.memoryIdempotencyStore.Complete(ctx, key)
+}
+
+func TestIdempotency_WriteTimeoutStartsAfterHandler(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	store := &deadlineStore{memoryIdempotencyStore: newMemoryIdempotencyStore()}
+	var handled time.Time
+	r := newIdempotencyRouter(store, fake, func(c *gin.Context) {
+		time.Sleep(50 * time.Millisecond)
+		handled = time.Now()
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	require.Equal(t, http.StatusCreated, postWithKey(r, "/products", "secret-key", "key-1", `{}`).Code)
+	assert.False(t, store.deadline.Before(handled.Add(idempotencyWriteTimeout)), "the write timeout starts once the handler returns")
+}
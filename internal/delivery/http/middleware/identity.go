@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// clientIdentity names the caller by the hash of its validated API key, or
+// by client IP when it sent none.
+func clientIdentity(c *gin.Context) string {
+	if key := CurrentAPIKey(c); key != nil {
+		return "key:" + key.Hash[:32]
+	}
+	return "ip:" + c.ClientIP()
+}
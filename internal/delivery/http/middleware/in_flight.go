@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"backend-context-engineering-template/pkg/lifecycle"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InFlight counts requests against the lifecycle manager so a drain can wait
+// for them. Probe and drain routes are excluded; they must never hold up a
+// drain themselves.
+func InFlight(manager *lifecycle.Manager, excludedPaths ...string) gin.HandlerFunc {
+	excluded := make(map[string]bool, len(excludedPaths))
+	for _, path := range excludedPaths {
+		excluded[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if excluded[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		manager.Begin()
+		defer manager.End()
+		c.Next()
+	}
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const listEnvelopeContextKey = "list_envelope"
+
+// ListEnvelope sets whether paged lists are answered with the
+// dto.ListResponse envelope. Without it, v1 clients keep getting each
+// list's own shape.
+func ListEnvelope(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(listEnvelopeContextKey, enabled)
+		c.Next()
+	}
+}
+
+// ListEnvelopeEnabled reports whether ListEnvelope enabled the envelope
+// for the request.
+func ListEnvelopeEnabled(c *gin.Context) bool {
+	return c.GetBool(listEnvelopeContextKey)
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"strings"
+
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// traceparentHeader carries the W3C trace context of the caller's trace.
+const traceparentHeader = "traceparent"
+
+// LogFields starts the fields every line logged through
+// logger.FromContext(c.Request.Context()) carries: the authenticated
+// caller, the admin impersonating them and the caller's trace. SetStoreID
+// adds the store once a handler knows it. The request ID is added by its
+// own hook. It must run after the authentication middleware.
+func LogFields() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields := logrus.Fields{}
+		if Authenticated(c) {
+			fields["caller"] = ClientIdentity(c)
+		}
+		if actor := ImpersonatedBy(c); actor != "" {
+			fields[impersonatedByContextKey] = actor
+		}
+		if traceID, ok := parseTraceparent(c.GetHeader(traceparentHeader)); ok {
+			fields["trace_id"] = traceID
+		}
+		c.Request = c.Request.WithContext(logger.NewContext(c.Request.Context(), fields))
+		c.Next()
+	}
+}
+
+// parseTraceparent returns the trace ID of a version 00 traceparent
+// header. Anything else is ignored rather than logged, as callers control
+// it.
+func parseTraceparent(header string) (string, bool) {
+	parts := strings.Split(header, "-")
+	if len(parts) != 4 || parts[0] != "00" || !isHex(parts[1], 32) || !isHex(parts[2], 16) || !isHex(parts[3], 2) {
+		return "", false
+	}
+	if strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+func isHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
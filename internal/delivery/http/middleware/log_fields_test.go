@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	appLogger := logger.New("info")
+	appLogger.SetOutput(&buf)
+	t.Cleanup(func() { logger.SetDefault(logrus.StandardLogger()) })
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if c.GetHeader("X-Test-Identity") != "" {
+			c.Set(identityContextKey, c.GetHeader("X-Test-Identity"))
+			c.Set(serviceRoleContextKey, "catalog-reader")
+		}
+	})
+	r.Use(LogFields())
+	r.GET("/api/v1/stores/:id", func(c *gin.Context) {
+		// The context is taken before the store is known, as handlers do.
+		ctx := c.Request.Context()
+		SetStoreID(c, 7)
+		logger.FromContext(ctx).Info("Store read")
+	})
+
+	tests := []struct {
+		name        string
+		identity    string
+		traceparent string
+		expected    map[string]any
+		absent      []string
+	}{
+		{
+			name:        "workload with a trace",
+			identity:    "workload:indexer",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expected:    map[string]any{"caller": "workload:indexer", "trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "store_id": float64(7)},
+		},
+		{
+			name:        "anonymous with an invalid trace",
+			traceparent: "00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+			expected:    map[string]any{"store_id": float64(7)},
+			absent:      []string{"caller", "trace_id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/stores/7", nil)
+			if tt.identity != "" {
+				req.Header.Set("X-Test-Identity", tt.identity)
+			}
+			req.Header.Set(traceparentHeader, tt.traceparent)
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			var entry map[string]any
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(buf.String())), &entry))
+			for key, value := range tt.expected {
+				assert.Equal(t, value, entry[key], key)
+			}
+			for _, key := range tt.absent {
+				assert.NotContains(t, entry, key)
+			}
+		})
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	traceID, ok := parseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+
+	for _, header := range []string{
+		"",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		`00-4bf92f3577b34da6a3ce929d0e0e47"6-00f067aa0ba902b7-01`,
+	} {
+		_, ok := parseTraceparent(header)
+		assert.False(t, ok, header)
+	}
+}
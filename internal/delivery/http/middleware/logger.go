@@ -3,13 +3,15 @@ package middleware
 import (
 	"time"
 
+	"backend-context-engineering-template/pkg/requestid"
+
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
 func Logger(logger *logrus.Logger) gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		logger.WithFields(logrus.Fields{
+		fields := logrus.Fields{
 			"client_ip":   param.ClientIP,
 			"timestamp":   param.TimeStamp.Format(time.RFC3339),
 			"method":      param.Method,
@@ -19,7 +21,14 @@ func Logger(logger *logrus.Logger) gin.HandlerFunc {
 			"latency":     param.Latency,
 			"user_agent":  param.Request.UserAgent(),
 			"error":       param.ErrorMessage,
-		}).Info("HTTP Request")
+		}
+		if id, ok := storeID(param.Keys); ok {
+			fields["store_id"] = id
+		}
+		if id, ok := param.Keys[requestIDContextKey].(string); ok {
+			fields[requestid.Field] = id
+		}
+		logger.WithFields(fields).Info("HTTP Request")
 
 		return ""
 	})
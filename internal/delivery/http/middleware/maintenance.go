@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maintenanceRetryAfter is the Retry-After sent while in maintenance mode;
+// how long maintenance lasts is not known in advance.
+const maintenanceRetryAfter = "60"
+
+// MaintenanceSource says whether the service is in maintenance mode.
+type MaintenanceSource interface {
+	Maintenance() domain.MaintenanceMode
+}
+
+// Maintenance refuses state-changing requests with 503 while the service
+// is in maintenance mode, so operators can freeze the catalog without
+// taking reads down. Admin routes are exempt, which keeps maintenance mode
+// itself and the other operator tools usable.
+func Maintenance(source MaintenanceSource) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		mode := source.Maintenance()
+		if !mode.Enabled || strings.HasPrefix(c.Request.URL.Path, "/admin/") {
+			c.Next()
+			return
+		}
+
+		message := mode.Message
+		if message == "" {
+			message = "The service is in maintenance mode and does not accept changes"
+		}
+		c.Header("Retry-After", maintenanceRetryAfter)
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+			Error:   "maintenance",
+			Message: message,
+		})
+	}
+}
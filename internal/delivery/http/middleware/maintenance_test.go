@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticMaintenance domain.MaintenanceMode
+
+func (m staticMaintenance) Maintenance() domain.MaintenanceMode {
+	return domain.MaintenanceMode(m)
+}
+
+func TestMaintenance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	on := staticMaintenance{Enabled: true, Message: "Catalog migration until 14:00 UTC"}
+
+	tests := []struct {
+		name         string
+		mode         staticMaintenance
+		method       string
+		path         string
+		expectedCode int
+	}{
+		{name: "writes pass when off", method: http.MethodPost, path: "/api/v1/products", expectedCode: http.StatusOK},
+		{name: "reads pass when on", mode: on, method: http.MethodGet, path: "/api/v1/products", expectedCode: http.StatusOK},
+		{name: "writes are refused when on", mode: on, method: http.MethodPut, path: "/api/v1/products", expectedCode: http.StatusServiceUnavailable},
+		{name: "admin writes pass when on", mode: on, method: http.MethodPut, path: "/admin/config/maintenance", expectedCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(Maintenance(tt.mode))
+			r.Handle(tt.method, tt.path, func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusServiceUnavailable {
+				assert.Equal(t, "60", w.Header().Get("Retry-After"))
+				assert.JSONEq(t, `{"error":"maintenance","message":"Catalog migration until 14:00 UTC"}`, w.Body.String())
+			}
+		})
+	}
+}
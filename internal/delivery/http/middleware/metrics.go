@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"backend-context-engineering-template/pkg/logger"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const storeIDContextKey = "store_id"
+
+// Metrics counts requests and records their latency per route. Unmatched
+// paths are reported under one "unmatched" route to keep cardinality
+// bounded. Requests a handler tagged with SetStoreID are also recorded per
+// store, where stores limits the label to the busiest stores; a nil stores
+// disables per-store metrics.
+func Metrics(registry *telemetry.Registry, stores *telemetry.TopK) gin.HandlerFunc {
+	requests := registry.NewCounter("http_server_requests_total", "HTTP requests served.", "http.request.method", "http.route", "http.response.status_code")
+	duration := registry.NewHistogram("http_server_request_duration_seconds", "HTTP request latency.", telemetry.DefaultDurationBuckets, "http.request.method", "http.route")
+	storeRequests := registry.NewCounter("http_server_store_requests_total", "HTTP requests per store by outcome.", "store_id", "outcome")
+	storeDuration := registry.NewHistogram("http_server_store_request_duration_seconds", "HTTP request latency per store.", telemetry.DefaultDurationBuckets, "store_id")
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		elapsed := time.Since(start).Seconds()
+		requests.Inc(c.Request.Method, route, strconv.Itoa(c.Writer.Status()))
+		duration.Observe(elapsed, c.Request.Method, route)
+
+		if id, ok := storeID(c.Keys); ok && stores != nil {
+			store := stores.Label(strconv.FormatInt(id, 10))
+			storeRequests.Inc(store, outcome(c.Writer.Status()))
+			storeDuration.Observe(elapsed, store)
+		}
+	}
+}
+
+// outcome classifies a status so per-store error rates can be derived
+// without a label per status code.
+func outcome(status int) string {
+	switch {
+	case status >= 500:
+		return "server_error"
+	case status >= 400:
+		return "client_error"
+	default:
+		return "success"
+	}
+}
+
+// SetStoreID tags the request with the store it acts on, for per-store
+// metrics and request logs; lines logged from then on through its context
+// name the store too. Handlers call it once the store is known.
+func SetStoreID(c *gin.Context, storeID int64) {
+	if storeID > 0 {
+		c.Set(storeIDContextKey, storeID)
+		logger.AddFields(c.Request.Context(), logrus.Fields{storeIDContextKey: storeID})
+	}
+}
+
+func storeID(keys map[string]any) (int64, bool) {
+	id, ok := keys[storeIDContextKey].(int64)
+	return id, ok
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+
+	r := gin.New()
+	r.Use(Metrics(registry, nil))
+	r.GET("/api/v1/products/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/api/v1/products/1", "/api/v1/products/2", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `http_server_requests_total{http_request_method="GET",http_response_status_code="200",http_route="/api/v1/products/:id"} 2`)
+	assert.Contains(t, buf.String(), `http_server_requests_total{http_request_method="GET",http_response_status_code="404",http_route="unmatched"} 1`)
+	assert.Contains(t, buf.String(), `http_server_request_duration_seconds_count{http_request_method="GET",http_route="/api/v1/products/:id"} 2`)
+}
+
+func TestMetrics_PerStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+
+	r := gin.New()
+	r.Use(Metrics(registry, telemetry.NewTopK(1)))
+	r.GET("/stores/:id", func(c *gin.Context) {
+		id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+		SetStoreID(c, id)
+		if id == 2 {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/stores/1", "/stores/1", "/stores/1", "/stores/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `http_server_store_requests_total{outcome="success",store_id="1"} 3`)
+	assert.Contains(t, buf.String(), `http_server_store_requests_total{outcome="client_error",store_id="other"} 1`)
+	assert.Contains(t, buf.String(), `http_server_store_request_duration_seconds_count{store_id="1"} 3`)
+	assert.NotContains(t, buf.String(), `store_id="2"`)
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+var queryCountBuckets = []float64{1, 2, 5, 10, 20, 50, 100, 200}
+
+// QueryBudget counts the database statements each request runs and warns
+// when a request runs more than budget, which usually means a query was
+// put in a loop. The request is served either way; the warning and
+// http_server_query_budget_exceeded_total are for logs and alerts. exempt
+// lists the routes, keyed like RouteCosts, whose queries grow with their
+// input, such as bulk writes and streams; they are counted but not held
+// to the budget.
+func QueryBudget(budget int64, exempt map[string]bool, registry *telemetry.Registry, logger *logrus.Logger) gin.HandlerFunc {
+	queries := registry.NewHistogram("http_server_request_db_queries", "Database statements run per HTTP request.", queryCountBuckets, "http.request.method", "http.route")
+	exceeded := registry.NewCounter("http_server_query_budget_exceeded_total", "HTTP requests that ran more database statements than the budget.", "http.request.method", "http.route")
+
+	return func(c *gin.Context) {
+		ctx := database.WithQueryCount(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		count := database.QueryCount(ctx)
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		queries.Observe(float64(count), c.Request.Method, route)
+
+		if count <= budget || exemptRoute(c, exempt) {
+			return
+		}
+		exceeded.Inc(c.Request.Method, route)
+		logger.WithFields(logrus.Fields{
+			"method":      c.Request.Method,
+			"route":       route,
+			"status_code": c.Writer.Status(),
+			"queries":     count,
+			"budget":      budget,
+		}).Warn("Request exceeded its database query budget")
+	}
+}
+
+func exemptRoute(c *gin.Context, exempt map[string]bool) bool {
+	route := c.Request.Method + " " + c.FullPath()
+	if c.Query("stream") == "true" && exempt[route+"?stream=true"] {
+		return true
+	}
+	return exempt[route]
+}
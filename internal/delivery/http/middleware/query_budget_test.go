@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// execConnector opens connections that accept every exec.
+type execConnector struct{}
+
+func (execConnector) Connect(ctx context.Context) (driver.Conn, error) { return execConn{}, nil }
+func (execConnector) Driver() driver.Driver                            { return nil }
+
+type execConn struct{}
+
+func (execConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (execConn) Close() error                              { return nil }
+func (execConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (execConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func TestQueryBudget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	logger, hook := test.NewNullLogger()
+	db := sql.OpenDB(database.CountingConnector(execConnector{}))
+	defer db.Close()
+
+	// Each request runs as many statements as ?n= asks for.
+	run := func(c *gin.Context) {
+		n, _ := strconv.Atoi(c.Query("n"))
+		for i := 0; i < n; i++ {
+			_, err := db.ExecContext(c.Request.Context(), "UPDATE products SET amount = amount")
+			require.NoError(t, err)
+		}
+		c.Status(http.StatusOK)
+	}
+
+	r := gin.New()
+	r.Use(QueryBudget(2, map[string]bool{"POST /items/bulk": true}, registry, logger))
+	r.GET("/items", run)
+	r.POST("/items/bulk", run)
+
+	for _, target := range []string{"/items?n=2", "/items?n=3"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusOK, w.Code, "requests over the budget are still served")
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/items/bulk?n=10", nil))
+
+	require.Len(t, hook.AllEntries(), 1)
+	entry := hook.LastEntry()
+	assert.Equal(t, "Request exceeded its database query budget", entry.Message)
+	assert.Equal(t, int64(3), entry.Data["queries"])
+	assert.Equal(t, "/items", entry.Data["route"])
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `http_server_query_budget_exceeded_total{http_request_method="GET",http_route="/items"} 1`)
+	assert.Contains(t, buf.String(), `http_server_request_db_queries_count{http_request_method="GET",http_route="/items"} 2`)
+	assert.Contains(t, buf.String(), `http_server_request_db_queries_sum{http_request_method="POST",http_route="/items/bulk"} 10`)
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RateLimiter caps the request rate per API key, or per client IP for
+// callers without one. Unlike CostLimiter, which meters the units spent on
+// expensive routes, it refuses every request past the rate, reads included.
+type RateLimiter struct {
+	limiter ratelimit.Limiter
+	logger  *logrus.Logger
+}
+
+func NewRateLimiter(limiter ratelimit.Limiter, logger *logrus.Logger) *RateLimiter {
+	return &RateLimiter{
+		limiter: limiter,
+		logger:  logger,
+	}
+}
+
+// adjustableLimiter is a limiter whose rate can change while it serves.
+type adjustableLimiter interface {
+	SetLimit(rate float64, burst int64)
+}
+
+// SetLimit changes the rate and burst of every caller. Limiters that cannot
+// change their rate while serving keep the one they were created with.
+func (l *RateLimiter) SetLimit(rate float64, burst int64) {
+	if limiter, ok := l.limiter.(adjustableLimiter); ok {
+		limiter.SetLimit(rate, burst)
+	}
+}
+
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, retryAfter, err := l.limiter.Allow(c.Request.Context(), "rate:"+clientIdentity(c))
+		if err != nil {
+			// Fail open: limiter problems must not take the API down.
+			l.logger.WithError(err).Warn("Rate limiter unavailable")
+			c.Next()
+			return
+		}
+
+		if !allowed {
+			seconds := int64(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			c.Header("Retry-After", strconv.FormatInt(seconds, 10))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.ErrorResponse{
+				Error:   "rate_limited",
+				Message: "Too many requests, retry after " + strconv.FormatInt(seconds, 10) + "s",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
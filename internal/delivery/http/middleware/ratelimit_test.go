@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	return false, 0, errors.New("redis down")
+}
+
+func setupRateLimitRouter(limiter ratelimit.Limiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(issuedKeys("key-a", "key-b"))
+	r.Use(NewRateLimiter(limiter, logrus.New()).Middleware())
+	r.GET("/items", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func TestRateLimiter(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	router := setupRateLimitRouter(ratelimit.NewTokenBucket(0.5, 2, fake))
+
+	do := func(apiKey, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.RemoteAddr = ip + ":1234"
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, do("key-a", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, do("key-a", "10.0.0.2").Code)
+	w := do("key-a", "10.0.0.3")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "an API key is limited wherever it calls from")
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+
+	// Callers without a key are limited per IP, apart from the keys.
+	assert.Equal(t, http.StatusOK, do("key-b", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, do("", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, do("", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, do("", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, do("", "10.0.0.2").Code)
+
+	fake.Advance(2 * time.Second)
+	assert.Equal(t, http.StatusOK, do("key-a", "10.0.0.3").Code)
+}
+
+func TestRateLimiter_FailsOpen(t *testing.T) {
+	router := setupRateLimitRouter(failingLimiter{})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
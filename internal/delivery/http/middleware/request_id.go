@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"time"
+
+	"backend-context-engineering-template/pkg/idgen"
+	"backend-context-engineering-template/pkg/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+const requestIDContextKey = "request_id"
+
+// RequestID gives every request an ID: the caller's X-Request-ID when it
+// is a valid one, such as one a proxy in front already assigned, or a new
+// version 7 UUID. The ID is echoed in the response and carried by the
+// request's context, which adds it to the log lines written with
+// logger.WithContext(ctx) and passes it on to the services called.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			// A request still gets an ID if random bits run out; it is
+			// only used for logs.
+			uuid, _ := idgen.UUIDv7At(time.Now())
+			id = uuid.String()
+		}
+
+		c.Set(requestIDContextKey, id)
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+		c.Next()
+	}
+}
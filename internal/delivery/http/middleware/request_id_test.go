@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-context-engineering-template/pkg/requestid"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(requestid.Hook{})
+
+	r := gin.New()
+	r.Use(RequestID())
+	r.GET("/api/v1/products", func(c *gin.Context) {
+		logger.WithContext(c.Request.Context()).Info("Listing products")
+		c.String(http.StatusOK, requestid.FromContext(c.Request.Context()))
+	})
+
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "caller's ID is kept", header: "edge-42", expected: "edge-42"},
+		{name: "missing ID is generated", expected: `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{name: "invalid ID is replaced", header: "bad\"id", expected: `^[0-9a-f]{8}-[0-9a-f]{4}-7`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
+			if tt.header != "" {
+				req.Header.Set(requestid.Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(requestid.Header)
+			assert.Regexp(t, tt.expected, id)
+			assert.Equal(t, id, w.Body.String(), "handlers see the echoed ID")
+			assert.Contains(t, buf.String(), `"request_id":"`+id+`"`)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/session"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionAndCSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	manager := session.NewManager(session.NewMemoryStore(clock.Real()), session.Config{TTL: time.Hour}, clock.Real())
+	cookie := session.CookieConfig{Name: "session", SameSite: http.SameSiteLaxMode}
+
+	s, token, err := manager.Create(context.Background(), "key:abc", nil, "", "")
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(issuedKeys("secret-key"))
+	r.Use(Session(manager, cookie, logrus.New()))
+	r.Use(CSRF())
+	r.GET("/whoami", func(c *gin.Context) { c.String(http.StatusOK, ClientIdentity(c)) })
+	r.POST("/products", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		cookie       string
+		apiKey       string
+		csrf         string
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "cookie identifies caller", method: http.MethodGet, path: "/whoami", cookie: token, expectedCode: http.StatusOK, expectedBody: "key:abc"},
+		{name: "safe method needs no csrf token", method: http.MethodGet, path: "/whoami", cookie: token, expectedCode: http.StatusOK},
+		{name: "unsafe method without csrf token", method: http.MethodPost, path: "/products", cookie: token, expectedCode: http.StatusForbidden},
+		{name: "unsafe method with wrong csrf token", method: http.MethodPost, path: "/products", cookie: token, csrf: "wrong", expectedCode: http.StatusForbidden},
+		{name: "unsafe method with csrf token", method: http.MethodPost, path: "/products", cookie: token, csrf: s.CSRFToken, expectedCode: http.StatusCreated},
+		{name: "api key ignores cookie", method: http.MethodPost, path: "/products", cookie: token, apiKey: "secret-key", expectedCode: http.StatusCreated},
+		{name: "unknown cookie is anonymous", method: http.MethodPost, path: "/products", cookie: "unknown", expectedCode: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.AddCookie(&http.Cookie{Name: "session", Value: tt.cookie})
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			if tt.csrf != "" {
+				req.Header.Set(CSRFHeader, tt.csrf)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
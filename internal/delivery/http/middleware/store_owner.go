@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const adminContextKey = "admin"
+
+// Admin marks workloads with the admin role, which may act on every store.
+// It must run after Workload.
+func Admin(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role != "" && ServiceRole(c) == role {
+			c.Set(adminContextKey, true)
+		}
+		c.Next()
+	}
+}
+
+// IsAdmin reports whether the caller is a workload with the admin role.
+func IsAdmin(c *gin.Context) bool {
+	return c.GetBool(adminContextKey)
+}
+
+// OwnsStore reports whether the caller may manage the store. Admins may
+// manage every store, and storeID 0 stands for settings that span stores,
+// which only admins may manage. API key and session callers may manage the
+// stores their key owns.
+func OwnsStore(c *gin.Context, storeID int64) bool {
+	if IsAdmin(c) {
+		return true
+	}
+	if storeID <= 0 {
+		return false
+	}
+
+	var owned []int64
+	if key := CurrentAPIKey(c); key != nil {
+		owned = key.StoreIDs
+	} else if s := CurrentSession(c); s != nil {
+		owned = s.StoreIDs
+	}
+	for _, id := range owned {
+		if id == storeID {
+			return true
+		}
+	}
+	return false
+}
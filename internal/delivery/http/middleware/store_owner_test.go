@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"backend-context-engineering-template/pkg/apikey"
+	"backend-context-engineering-template/pkg/session"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnsStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keys := APIKey(apikey.NewMemoryStore(&apikey.Key{ID: 1, Hash: apikey.Hash("owner-key"), StoreIDs: []int64{3}}), logrus.New())
+	caller := func(role string, s *session.Session) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if role != "" {
+				c.Set(serviceRoleContextKey, role)
+			}
+			if s != nil {
+				c.Set(sessionContextKey, s)
+			}
+		}
+	}
+
+	tests := []struct {
+		name    string
+		role    string
+		session *session.Session
+		apiKey  string
+		storeID int64
+		owns    bool
+	}{
+		{name: "key owns store", apiKey: "owner-key", storeID: 3, owns: true},
+		{name: "key does not own store", apiKey: "owner-key", storeID: 4},
+		{name: "key cannot span stores", apiKey: "owner-key", storeID: 0},
+		{name: "session owns store", session: &session.Session{StoreIDs: []int64{4}}, storeID: 4, owns: true},
+		{name: "admin owns every store", role: "admin", storeID: 4, owns: true},
+		{name: "admin can span stores", role: "admin", storeID: 0, owns: true},
+		{name: "other workload", role: "catalog-reader", storeID: 3},
+		{name: "anonymous", storeID: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(keys, caller(tt.role, tt.session), Admin("admin"))
+			r.GET("/stores/:id", func(c *gin.Context) {
+				id, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+				c.JSON(http.StatusOK, OwnsStore(c, id))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/stores/"+strconv.FormatInt(tt.storeID, 10), nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, strconv.FormatBool(tt.owns), w.Body.String())
+		})
+	}
+}
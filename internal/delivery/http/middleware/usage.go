@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageRecorder counts the billable API calls served for stores.
+type UsageRecorder interface {
+	RecordCall(storeID int64)
+}
+
+// Usage meters each request a handler tagged with SetStoreID as an API
+// call of that store. Requests that failed on the server and those of
+// admins, who act for the operators rather than the store, are not
+// billed.
+func Usage(recorder UsageRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		id, ok := storeID(c.Keys)
+		if !ok || c.Writer.Status() >= http.StatusInternalServerError || IsAdmin(c) {
+			return
+		}
+		recorder.RecordCall(id)
+	}
+}
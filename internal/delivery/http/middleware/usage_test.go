@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingUsageRecorder map[int64]int
+
+func (r countingUsageRecorder) RecordCall(storeID int64) {
+	r[storeID]++
+}
+
+func TestUsage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		storeID  int64
+		admin    bool
+		status   int
+		expected countingUsageRecorder
+	}{
+		{name: "store call", storeID: 4, status: http.StatusOK, expected: countingUsageRecorder{4: 1}},
+		{name: "client error is billed", storeID: 4, status: http.StatusNotFound, expected: countingUsageRecorder{4: 1}},
+		{name: "server error is not billed", storeID: 4, status: http.StatusInternalServerError, expected: countingUsageRecorder{}},
+		{name: "admin call is not billed", storeID: 4, admin: true, status: http.StatusOK, expected: countingUsageRecorder{}},
+		{name: "untagged call", status: http.StatusOK, expected: countingUsageRecorder{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := countingUsageRecorder{}
+			r := gin.New()
+			r.Use(Usage(recorder))
+			r.GET("/api/v1/products", func(c *gin.Context) {
+				if tt.admin {
+					c.Set(adminContextKey, true)
+				}
+				SetStoreID(c, tt.storeID)
+				c.Status(tt.status)
+			})
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/products", nil))
+
+			assert.Equal(t, tt.expected, recorder)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/authtoken"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/workload"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := clock.NewFake(time.Now())
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	tokens := authtoken.NewIssuer(secret, "product-service", 15*time.Minute, 24*time.Hour, fake)
+	verifier := workload.NewVerifier(map[string]*workload.KeySet{}, "product-service")
+
+	r := gin.New()
+	r.Use(User(tokens, logrus.New()))
+	r.Use(Workload(verifier, workload.Roles{}, logrus.New()))
+	r.GET("/whoami", RequireAuthenticated(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"identity": ClientIdentity(c), "email": CurrentUser(c).Email})
+	})
+
+	pair, err := tokens.Issue(42, "ada@example.com")
+	require.NoError(t, err)
+	expired, err := authtoken.NewIssuer(secret, "product-service", time.Minute, time.Hour, clock.NewFake(fake.Now().Add(-time.Hour))).Issue(42, "ada@example.com")
+	require.NoError(t, err)
+	foreign, err := authtoken.NewIssuer(secret, "billing-service", time.Minute, time.Hour, fake).Issue(42, "ada@example.com")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		bearer       string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "access token",
+			bearer:       pair.AccessToken,
+			expectedCode: http.StatusOK,
+			expectedBody: `{"identity":"user:42","email":"ada@example.com"}`,
+		},
+		{
+			name:         "refresh token",
+			bearer:       pair.RefreshToken,
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: `{"error":"invalid_access_token","message":"The access token is invalid or expired"}`,
+		},
+		{
+			name:         "expired access token",
+			bearer:       expired.AccessToken,
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: `{"error":"invalid_access_token","message":"The access token is invalid or expired"}`,
+		},
+		{
+			name:         "other issuer is left to workload",
+			bearer:       foreign.AccessToken,
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: `{"error":"invalid_workload_token","message":"The workload identity token is invalid"}`,
+		},
+		{
+			name:         "anonymous",
+			expectedCode: http.StatusUnauthorized,
+			expectedBody: `{"error":"authentication_required","message":"A bearer token, X-API-Key header or session cookie is required"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestUser_Impersonation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := clock.NewFake(time.Now())
+	tokens := authtoken.NewIssuer([]byte("0123456789abcdef0123456789abcdef"), "product-service", 15*time.Minute, 24*time.Hour, fake)
+	recorder := &recordingAuditRecorder{}
+
+	r := gin.New()
+	r.Use(User(tokens, logrus.New()))
+	r.Use(Audit(recorder, fake, logrus.New()))
+	r.POST("/items", RequireAuthenticated(), func(c *gin.Context) { c.Status(http.StatusCreated) })
+	r.POST("/auth/2fa/disable", ForbidImpersonation(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	do := func(path, bearer string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	impersonation, _, err := tokens.IssueImpersonation(42, "ada@example.com", "workload:support-console", 5*time.Minute)
+	require.NoError(t, err)
+	w := do("/items", impersonation)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "workload:support-console", w.Header().Get(ImpersonatedByHeader))
+	require.Len(t, recorder.entries, 1)
+	assert.Equal(t, "user:42", recorder.entries[0].Actor)
+	assert.Equal(t, "workload:support-console", recorder.entries[0].ImpersonatedBy)
+
+	w = do("/auth/2fa/disable", impersonation)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"error":"impersonation_forbidden","message":"This endpoint cannot be called while impersonating a user"}`, w.Body.String())
+
+	pair, err := tokens.Issue(42, "ada@example.com")
+	require.NoError(t, err)
+	w = do("/items", pair.AccessToken)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get(ImpersonatedByHeader))
+	assert.Empty(t, recorder.entries[len(recorder.entries)-1].ImpersonatedBy)
+	assert.Equal(t, http.StatusOK, do("/auth/2fa/disable", pair.AccessToken).Code)
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"backend-context-engineering-template/internal/delivery/http/dto"
+	"backend-context-engineering-template/pkg/workload"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const serviceRoleContextKey = "service_role"
+
+// Workload authenticates internal services by workload identity: an
+// X.509-SVID client certificate verified by the TLS server, or a JWT-SVID
+// or cloud identity token sent as a bearer token. Authenticated workloads
+// must be mapped to a service role. verifier may be nil to accept client
+// certificates only. Requests with an X-API-Key and users authenticated by
+// User are left alone.
+func Workload(verifier *workload.Verifier, roles workload.Roles, logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") != "" || CurrentUser(c) != nil {
+			c.Next()
+			return
+		}
+
+		var (
+			identity workload.Identity
+			found    bool
+		)
+		if tls := c.Request.TLS; tls != nil && len(tls.VerifiedChains) > 0 {
+			if id, err := workload.FromCertificate(tls.PeerCertificates[0]); err == nil {
+				identity, found = id, true
+			}
+		}
+		if token, ok := bearerToken(c); !found && ok && verifier != nil {
+			id, err := verifier.Verify(c.Request.Context(), token)
+			if err != nil {
+				logger.WithError(err).Warn("Rejected workload token")
+				c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+					Error:   "invalid_workload_token",
+					Message: "The workload identity token is invalid",
+				})
+				return
+			}
+			identity, found = id, true
+		}
+		if !found {
+			c.Next()
+			return
+		}
+
+		role, err := roles.Role(identity)
+		if err != nil {
+			if !errors.Is(err, workload.ErrUnmappedRole) {
+				logger.WithError(err).Error("Failed to resolve workload role")
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+				Error:   "workload_not_authorized",
+				Message: "Workload " + identity.ID + " has no service role",
+			})
+			return
+		}
+
+		c.Set(identityContextKey, "workload:"+identity.ID)
+		c.Set(serviceRoleContextKey, role)
+		c.Next()
+	}
+}
+
+// RequireRole only lets authenticated workloads with one of the given
+// service roles through. Other callers get 401, and workloads with another
+// role get 403.
+func RequireRole(allowed ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := ServiceRole(c)
+		if role == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error:   "workload_identity_required",
+				Message: "This endpoint requires a workload identity",
+			})
+			return
+		}
+		for _, want := range allowed {
+			if role == want {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Error:   "role_not_authorized",
+			Message: "Service role " + role + " may not call this endpoint",
+		})
+	}
+}
+
+// ServiceRole returns the role of the calling workload, or "" for callers
+// that are not authenticated workloads.
+func ServiceRole(c *gin.Context) string {
+	return c.GetString(serviceRoleContextKey)
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return "", false
+	}
+	token := strings.TrimSpace(header[7:])
+	return token, token != ""
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"backend-context-engineering-template/pkg/workload"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	roles, err := workload.ParseRoles([]string{"spiffe://prod.example|spiffe://prod.example/ns/search/sa/indexer=catalog-reader"})
+	require.NoError(t, err)
+	verifier := workload.NewVerifier(map[string]*workload.KeySet{}, "product-service")
+
+	r := gin.New()
+	r.Use(Workload(verifier, roles, logrus.New()))
+	r.GET("/whoami", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"identity": ClientIdentity(c), "role": ServiceRole(c)})
+	})
+
+	svid := func(id string) *tls.ConnectionState {
+		uri, err := url.Parse(id)
+		require.NoError(t, err)
+		cert := &x509.Certificate{URIs: []*url.URL{uri}}
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		tls          *tls.ConnectionState
+		bearer       string
+		apiKey       string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "mapped X.509-SVID",
+			tls:          svid("spiffe://prod.example/ns/search/sa/indexer"),
+			expectedCode: http.StatusOK,
+			expectedBody: `{"identity":"workload:spiffe://prod.example/ns/search/sa/indexer","role":"catalog-reader"}`,
+		},
+		{
+			name:         "unmapped X.509-SVID",
+			tls:          svid("spiffe://prod.example/ns/other/sa/default"),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "invalid bearer token",
+			bearer:       "not-a-token",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "api key wins over bearer token",
+			bearer:       "not-a-token",
+			apiKey:       "secret-key",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "anonymous",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+			req.TLS = tt.tls
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	withRole := func(role string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if role != "" {
+				c.Set(serviceRoleContextKey, role)
+			}
+		}
+	}
+
+	tests := []struct {
+		name         string
+		role         string
+		expectedCode int
+	}{
+		{name: "admin", role: "admin", expectedCode: http.StatusOK},
+		{name: "other role", role: "catalog-reader", expectedCode: http.StatusForbidden},
+		{name: "not a workload", expectedCode: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/admin", withRole(tt.role), RequireRole("admin"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+			assert.Equal(t, tt.expectedCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// ModerationRoutes serves the product review queue under
+// /admin/moderation.
+func ModerationRoutes(handler *handlers.ModerationHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		moderation := r.Admin.Group("/moderation")
+		{
+			moderation.GET("/products", handler.GetProductsForReview)
+			moderation.POST("/products/:id/review", handler.ReviewProduct)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package http
+
+import "github.com/gin-gonic/gin"
+
+// Routes are the groups a Module registers its routes on. They carry the
+// middleware SetupRouter puts in front of them.
+type Routes struct {
+	// API is /api/v1, behind authentication, limits and metering.
+	API *gin.RouterGroup
+	// Products is /api/v1/products, which ProtectProducts closes to
+	// anonymous callers.
+	Products *gin.RouterGroup
+	// Admin is /admin, which only workloads with the admin role may call.
+	Admin *gin.RouterGroup
+	// Root is the engine, for probes and the few routes outside the
+	// groups above.
+	Root gin.IRouter
+}
+
+// Module is a feature's HTTP surface. SetupRouter registers the routes of
+// every module it is given, so it knows neither the features nor their
+// handler types; a feature that is turned off contributes no module.
+type Module interface {
+	RegisterRoutes(r Routes)
+}
+
+// ModuleFunc lets a function be a Module.
+type ModuleFunc func(r Routes)
+
+func (f ModuleFunc) RegisterRoutes(r Routes) {
+	f(r)
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// OrderRoutes serves orders under /api/v1/orders.
+func OrderRoutes(handler *handlers.OrderHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		orders := r.API.Group("/orders")
+		{
+			orders.POST("", handler.CreateOrder)
+			orders.GET("/:id", handler.GetOrder)
+			orders.GET("", handler.GetOrders)
+		}
+	})
+}
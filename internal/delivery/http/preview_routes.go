@@ -0,0 +1,12 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// PreviewRoutes creates preview links to products.
+func PreviewRoutes(handler *handlers.PreviewHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Products.POST("/:id/preview-tokens", handler.CreatePreviewToken)
+	})
+}
@@ -0,0 +1,19 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// PricingRoutes serves stores' pricing policies and the prices they
+// quote.
+func PricingRoutes(handler *handlers.PricingHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		pricingPolicies := r.API.Group("/pricing-policies")
+		{
+			pricingPolicies.GET("/:store_id", handler.GetPolicies)
+			pricingPolicies.PUT("/:store_id/:currency", handler.SavePolicy)
+			pricingPolicies.DELETE("/:store_id/:currency", handler.DeletePolicy)
+		}
+		r.API.GET("/products/:id/price", handler.QuotePrice)
+	})
+}
@@ -0,0 +1,20 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// ProductRoutes serves the catalog under /api/v1/products.
+func ProductRoutes(handler *handlers.ProductHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Products.POST("", handler.CreateProduct)
+		r.Products.POST("/bulk", handler.WriteProducts)
+		r.Products.POST("/import", handler.ImportProducts)
+		r.Products.GET("/export", handler.ExportProducts)
+		r.Products.GET("/:id", handler.GetProduct)
+		r.Products.GET("", handler.GetProducts)
+		r.Products.PUT("/:id", handler.UpdateProduct)
+		r.Products.PATCH("/:id", handler.PatchProduct)
+		r.Products.DELETE("/:id", handler.DeleteProduct)
+	})
+}
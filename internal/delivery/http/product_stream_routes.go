@@ -0,0 +1,13 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// ProductStreamRoutes serves the stream of product changes at
+// /api/v1/products/stream.
+func ProductStreamRoutes(handler *handlers.ProductStreamHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Products.GET("/stream", handler.Stream)
+	})
+}
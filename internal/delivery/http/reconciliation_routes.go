@@ -0,0 +1,19 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// ReconciliationRoutes serves catalog reconciliations under
+// /admin/reconciliations.
+func ReconciliationRoutes(handler *handlers.ReconciliationHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		reconciliations := r.Admin.Group("/reconciliations")
+		{
+			reconciliations.POST("", handler.Reconcile)
+			reconciliations.GET("", handler.GetRuns)
+			reconciliations.GET("/:id", handler.GetRun)
+			reconciliations.GET("/:id/discrepancies", handler.GetDiscrepancies)
+		}
+	})
+}
@@ -0,0 +1,12 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// RegionRoutes reports the region's replication.
+func RegionRoutes(handler *handlers.RegionHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Root.GET("/health/replication", handler.GetReplication)
+	})
+}
@@ -0,0 +1,12 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// RetentionRoutes reports what the retention rules would delete.
+func RetentionRoutes(handler *handlers.RetentionHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Admin.GET("/retention/report", handler.GetReport)
+	})
+}
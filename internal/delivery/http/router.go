@@ -1,39 +1,250 @@
 package http
 
 import (
-	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend-context-engineering-template/internal/cdn"
 	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/explain"
+	"backend-context-engineering-template/pkg/lifecycle"
+	"backend-context-engineering-template/pkg/telemetry"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
-func SetupRouter(productHandler *handlers.ProductHandler, logger *logrus.Logger) *gin.Engine {
+// RouteCosts prices routes in rate limit units, keyed by method and route
+// pattern. A key ending in ?stream=true prices the route's streaming form.
+// Routes not listed cost one unit.
+var RouteCosts = map[string]int64{
+	"GET /api/v1/products":                                    2,
+	"GET /api/v1/products?stream=true":                        50,
+	"GET /api/v1/products/stream":                             10,
+	"GET /api/v1/products/diff":                               25,
+	"POST /api/v1/products/bulk":                              25,
+	"POST /api/v1/products/import":                            50,
+	"GET /api/v1/products/export":                             50,
+	"GET /api/v1/feeds":                                       2,
+	"POST /api/v1/feeds/:id/runs":                             25,
+	"GET /admin/moderation/products":                          2,
+	"POST /admin/connectors/:id/syncs":                        50,
+	"POST /admin/reconciliations":                             50,
+	"DELETE /api/v1/trash":                                    25,
+	"POST /api/v1/stores/:id/snapshots":                       25,
+	"GET /api/v1/stores/:id/snapshots/:snapshot_id/preview":   25,
+	"POST /api/v1/stores/:id/snapshots/:snapshot_id/restores": 50,
+	"GET /graphql":                                            2,
+	"POST /graphql":                                           2,
+}
+
+// UnbudgetedRoutes run a number of queries that grows with their input, so
+// the per-request query budget does not apply to them. Keys are those of
+// RouteCosts.
+var UnbudgetedRoutes = map[string]bool{
+	"GET /api/v1/products?stream=true":                        true,
+	"POST /api/v1/products/bulk":                              true,
+	"POST /api/v1/products/import":                            true,
+	"GET /api/v1/products/export":                             true,
+	"POST /api/v1/feeds/:id/runs":                             true,
+	"POST /admin/connectors/:id/syncs":                        true,
+	"POST /admin/reconciliations":                             true,
+	"DELETE /api/v1/trash":                                    true,
+	"POST /api/v1/stores/:id/snapshots":                       true,
+	"POST /api/v1/stores/:id/snapshots/:snapshot_id/restores": true,
+	"GET /graphql":                                            true,
+	"POST /graphql":                                           true,
+}
+
+// IdempotentRoutes take an Idempotency-Key header, so clients can retry
+// creating a product or an order after a timeout without creating it twice.
+// Keys are those of RouteCosts.
+var IdempotentRoutes = map[string]bool{
+	"POST /api/v1/products": true,
+	"POST /api/v1/orders":   true,
+}
+
+// CachePolicies lets a CDN cache the anonymous catalog reads. Responses
+// are tagged with the surrogate keys cdn.Purger purges when products
+// change, so the CDN may keep them far longer than browsers. List pages
+// carry the keys of the products on them, so one that moves to another
+// store leaves its old store's lists too. Keys are those of RouteCosts.
+var CachePolicies = map[string]middleware.CachePolicy{
+	"GET /api/v1/products": {
+		MaxAge:               30 * time.Second,
+		SharedMaxAge:         5 * time.Minute,
+		StaleWhileRevalidate: 30 * time.Second,
+		SurrogateKeys: func(c *gin.Context) []string {
+			keys := []string{cdn.ProductListKey}
+			if storeID, err := strconv.ParseInt(c.Query("store_id"), 10, 64); err == nil {
+				keys = []string{cdn.StoreKey(storeID)}
+			}
+			for _, id := range middleware.ListedProducts(c) {
+				keys = append(keys, cdn.ProductKey(id))
+			}
+			return keys
+		},
+	},
+	"GET /api/v1/products/:id": {
+		MaxAge:               30 * time.Second,
+		SharedMaxAge:         time.Hour,
+		StaleWhileRevalidate: 30 * time.Second,
+		SurrogateKeys: func(c *gin.Context) []string {
+			id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				return nil
+			}
+			return []string{cdn.ProductKey(id)}
+		},
+	},
+}
+
+// RouterDeps is everything SetupRouter wires into the engine. Middleware
+// documented as optional may be nil; it is then left out.
+type RouterDeps struct {
+	// Modules are the features whose routes are served.
+	Modules []Module
+
+	APIKeyMiddleware   gin.HandlerFunc
+	SessionMiddleware  gin.HandlerFunc
+	WorkloadMiddleware gin.HandlerFunc
+	// UserMiddleware authenticates users' access tokens; it is optional.
+	UserMiddleware gin.HandlerFunc
+	// ProtectProducts turns away anonymous callers from /api/v1/products.
+	ProtectProducts bool
+	// ListEnvelope answers paged lists with the dto.ListResponse envelope
+	// rather than the shapes v1 clients know.
+	ListEnvelope bool
+	// AdminRole is the service role a workload needs to call /admin.
+	AdminRole string
+	// RateLimiter, CostLimiter, AuditRecorder and ExplainCapturer are
+	// optional.
+	RateLimiter     *middleware.RateLimiter
+	CostLimiter     *middleware.CostLimiter
+	AuditRecorder   middleware.AuditRecorder
+	ExplainCapturer *explain.Capturer
+	// QueryBudget is how many database statements a request may run
+	// before it is logged as exceeding its budget; zero disables counting.
+	QueryBudget int64
+	// CachePolicies makes the listed routes cacheable by a CDN; without
+	// them no caching headers are set. SurrogateKeyHeader is where the
+	// CDN in front reads their surrogate keys from.
+	CachePolicies      map[string]middleware.CachePolicy
+	SurrogateKeyHeader middleware.SurrogateKeyHeader
+	// Maintenance refuses changes outside /admin while maintenance mode is
+	// on; it is optional.
+	Maintenance middleware.MaintenanceSource
+	// UsageRecorder meters the API calls served for stores; it is
+	// optional.
+	UsageRecorder middleware.UsageRecorder
+	// IdempotencyStore records requests to IdempotentRoutes, so retries
+	// get the first response; it is optional.
+	IdempotencyStore  middleware.IdempotencyStore
+	IdempotencyConfig middleware.IdempotencyConfig
+
+	Clock            clock.Clock
+	LifecycleManager *lifecycle.Manager
+	Registry         *telemetry.Registry
+	StoreLabels      *telemetry.TopK
+	// MetricsHandler serves /metrics when set.
+	MetricsHandler http.Handler
+	// AdminUI serves the operators' dashboard under /admin-ui/ when set.
+	AdminUI http.Handler
+	// APIDocs serves the OpenAPI spec and the page reading it under
+	// /swagger/ when set.
+	APIDocs http.Handler
+	Logger  *logrus.Logger
+}
+
+func SetupRouter(deps RouterDeps) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.New()
-	r.Use(middleware.Logger(logger))
-	r.Use(middleware.ErrorHandler(logger))
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Logger(deps.Logger))
+	r.Use(middleware.Metrics(deps.Registry, deps.StoreLabels))
+	if deps.UsageRecorder != nil {
+		r.Use(middleware.Usage(deps.UsageRecorder))
+	}
+	r.Use(middleware.CacheEndpoint())
+	if deps.ExplainCapturer != nil {
+		r.Use(middleware.ExplainSlow(deps.ExplainCapturer))
+	}
+	if deps.QueryBudget > 0 {
+		r.Use(middleware.QueryBudget(deps.QueryBudget, UnbudgetedRoutes, deps.Registry, deps.Logger))
+	}
+	if deps.CachePolicies != nil {
+		r.Use(middleware.CacheControl(deps.CachePolicies, deps.SurrogateKeyHeader))
+	}
+	r.Use(middleware.ErrorHandler(deps.Logger))
+	r.Use(deps.APIKeyMiddleware)
+	r.Use(deps.SessionMiddleware)
+	if deps.UserMiddleware != nil {
+		r.Use(deps.UserMiddleware)
+	}
+	r.Use(deps.WorkloadMiddleware)
+	r.Use(middleware.Admin(deps.AdminRole))
+	r.Use(middleware.LogFields())
+	if deps.AuditRecorder != nil {
+		r.Use(middleware.Audit(deps.AuditRecorder, deps.Clock, deps.Logger))
+	}
+	r.Use(middleware.InFlight(deps.LifecycleManager, "/health", "/healthz", "/health/replication", "/ready", "/readyz", "/admin/drain", "/metrics"))
+	if deps.Maintenance != nil {
+		r.Use(middleware.Maintenance(deps.Maintenance))
+	}
+	if deps.RateLimiter != nil {
+		r.Use(deps.RateLimiter.Middleware())
+	}
+	if deps.CostLimiter != nil {
+		r.Use(deps.CostLimiter.Middleware())
+	}
+	r.Use(middleware.CSRF())
+	r.Use(middleware.ListEnvelope(deps.ListEnvelope))
+	if deps.IdempotencyStore != nil {
+		r.Use(middleware.Idempotency(deps.IdempotencyStore, IdempotentRoutes, deps.IdempotencyConfig, deps.Clock, deps.Logger))
+	}
 
 	api := r.Group("/api/v1")
-	{
-		products := api.Group("/products")
-		{
-			products.POST("", productHandler.CreateProduct)
-			products.GET("/:id", productHandler.GetProduct)
-			products.GET("", productHandler.GetProducts)
-			products.PUT("/:id", productHandler.UpdateProduct)
-			products.DELETE("/:id", productHandler.DeleteProduct)
-		}
-	}
-
-	// Health check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"message": "Service is healthy",
-		})
-	})
+	products := api.Group("/products")
+	if deps.ProtectProducts {
+		// Preview links are opened by people without an account;
+		// GetProduct checks their token.
+		products.Use(middleware.RequireAuthenticatedUnless(isPreview))
+	}
+	// Admin endpoints are for operators' tooling, which calls them with a
+	// workload identity mapped to the admin role.
+	admin := r.Group("/admin", middleware.RequireRole(deps.AdminRole))
+
+	routes := Routes{API: api, Products: products, Admin: admin, Root: r}
+	for _, module := range deps.Modules {
+		module.RegisterRoutes(routes)
+	}
+
+	// Prometheus scrapes metrics here when pull export is configured.
+	if deps.MetricsHandler != nil {
+		r.GET("/metrics", gin.WrapH(deps.MetricsHandler))
+	}
+
+	// The dashboard is static; the API calls it makes are authorized like
+	// any other caller's.
+	if deps.AdminUI != nil {
+		adminUI := gin.WrapH(http.StripPrefix("/admin-ui", deps.AdminUI))
+		r.GET("/admin-ui/*filepath", adminUI)
+		r.HEAD("/admin-ui/*filepath", adminUI)
+	}
+
+	if deps.APIDocs != nil {
+		apiDocs := gin.WrapH(http.StripPrefix("/swagger", deps.APIDocs))
+		r.GET("/swagger/*filepath", apiDocs)
+		r.HEAD("/swagger/*filepath", apiDocs)
+	}
 
 	return r
 }
+
+// isPreview reports whether the request opens a preview link.
+func isPreview(c *gin.Context) bool {
+	return c.Request.Method == http.MethodGet && c.FullPath() == "/api/v1/products/:id" && c.Query("preview_token") != ""
+}
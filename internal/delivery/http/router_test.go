@@ -0,0 +1,265 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/adminui"
+	"backend-context-engineering-template/internal/apidocs"
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+	"backend-context-engineering-template/pkg/apikey"
+	"backend-context-engineering-template/pkg/lifecycle"
+	"backend-context-engineering-template/pkg/telemetry"
+	"backend-context-engineering-template/pkg/workload"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupRouter_AdminDrain(t *testing.T) {
+	logger := logrus.New()
+	roles, err := workload.ParseRoles([]string{
+		"spiffe://prod.example|spiffe://prod.example/ns/ops/sa/deployer=admin",
+		"spiffe://prod.example|spiffe://prod.example/ns/search/sa/indexer=catalog-reader",
+	})
+	require.NoError(t, err)
+	verifier := workload.NewVerifier(map[string]*workload.KeySet{}, "product-service")
+
+	svid := func(id string) *tls.ConnectionState {
+		uri, err := url.Parse(id)
+		require.NoError(t, err)
+		cert := &x509.Certificate{URIs: []*url.URL{uri}}
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		tls          *tls.ConnectionState
+		apiKey       string
+		expectedCode int
+		expectDrain  bool
+	}{
+		{
+			name:         "anonymous",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "api key",
+			apiKey:       "secret-key",
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "workload without the admin role",
+			tls:          svid("spiffe://prod.example/ns/search/sa/indexer"),
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "admin workload",
+			tls:          svid("spiffe://prod.example/ns/ops/sa/deployer"),
+			expectedCode: http.StatusOK,
+			expectDrain:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := lifecycle.New()
+			manager.SetReady(true)
+
+			r := SetupRouter(RouterDeps{
+				Modules:            []Module{LifecycleRoutes(handlers.NewLifecycleHandler(manager, time.Second))},
+				APIKeyMiddleware:   middleware.APIKey(apikey.NewMemoryStore(&apikey.Key{ID: 1, Hash: apikey.Hash("secret-key")}), logger),
+				SessionMiddleware:  func(c *gin.Context) {},
+				WorkloadMiddleware: middleware.Workload(verifier, roles, logger),
+				AdminRole:          "admin",
+				LifecycleManager:   manager,
+				Registry:           telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil)),
+				StoreLabels:        telemetry.NewTopK(10),
+				Logger:             logger,
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/drain", nil)
+			req.TLS = tt.tls
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, !tt.expectDrain, manager.Ready())
+		})
+	}
+}
+
+func TestSetupRouter_ProductDiffRoute(t *testing.T) {
+	logger := logrus.New()
+	manager := lifecycle.New()
+	manager.SetReady(true)
+
+	r := SetupRouter(RouterDeps{
+		Modules:            []Module{CatalogDiffRoutes(handlers.NewCatalogDiffHandler(nil))},
+		APIKeyMiddleware:   func(c *gin.Context) {},
+		SessionMiddleware:  func(c *gin.Context) {},
+		WorkloadMiddleware: func(c *gin.Context) {},
+		LifecycleManager:   manager,
+		Registry:           telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil)),
+		StoreLabels:        telemetry.NewTopK(10),
+		Logger:             logger,
+	})
+
+	// The static diff route takes precedence over /products/:id.
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/diff?from=yesterday", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid_from")
+}
+
+func TestSetupRouter_AdminUI(t *testing.T) {
+	logger := logrus.New()
+	manager := lifecycle.New()
+	ui, err := adminui.New("")
+	require.NoError(t, err)
+
+	r := SetupRouter(RouterDeps{
+		APIKeyMiddleware:   func(c *gin.Context) {},
+		SessionMiddleware:  func(c *gin.Context) {},
+		WorkloadMiddleware: func(c *gin.Context) {},
+		LifecycleManager:   manager,
+		Registry:           telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil)),
+		StoreLabels:        telemetry.NewTopK(10),
+		AdminUI:            ui,
+		Logger:             logger,
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin-ui", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/admin-ui/", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin-ui/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<title>Product Service Admin</title>")
+	assert.Equal(t, adminui.ContentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+}
+
+func TestSetupRouter_APIDocs(t *testing.T) {
+	docs, err := apidocs.New("")
+	require.NoError(t, err)
+
+	r := SetupRouter(RouterDeps{
+		APIKeyMiddleware:   func(c *gin.Context) {},
+		SessionMiddleware:  func(c *gin.Context) {},
+		WorkloadMiddleware: func(c *gin.Context) {},
+		LifecycleManager:   lifecycle.New(),
+		Registry:           telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil)),
+		StoreLabels:        telemetry.NewTopK(10),
+		APIDocs:            docs,
+		Logger:             logrus.New(),
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<title>Product Service API</title>")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"openapi":"3.0.3"`)
+}
+
+// The spec's operations are written by hand, so this keeps them in step
+// with the routes the modules register.
+func TestAPIDocs_DescribeEveryRoute(t *testing.T) {
+	r := SetupRouter(RouterDeps{
+		Modules: []Module{
+			AnalyticsRoutes(nil), AuthRoutes(nil), BundleRoutes(nil), CacheRoutes(nil),
+			CatalogDiffRoutes(nil), CatalogSnapshotRoutes(nil), CategoryRoutes(nil), ConnectorRoutes(nil),
+			DBHealthRoutes(nil), DigestRoutes(nil), EventSchemaRoutes(nil), FeedRoutes(nil),
+			GraphQLRoutes(nil), HealthRoutes(nil), ImageRoutes(nil), ImpersonationRoutes(nil),
+			ImportMappingRoutes(nil), LifecycleRoutes(nil), LiveConfigRoutes(nil),
+			ModerationRoutes(nil), OrderRoutes(nil), PreviewRoutes(nil), PricingRoutes(nil),
+			ProductRoutes(nil), ProductStreamRoutes(nil), ReconciliationRoutes(nil),
+			RegionRoutes(nil), RetentionRoutes(nil), SessionRoutes(nil), StockSummaryRoutes(nil),
+			StoreRoutes(nil), TrashRoutes(nil), TwoFactorRoutes(nil), UsageRoutes(nil),
+			WebhookRoutes(nil), WebhookSecretRoutes(nil),
+		},
+		APIKeyMiddleware:   func(c *gin.Context) {},
+		SessionMiddleware:  func(c *gin.Context) {},
+		WorkloadMiddleware: func(c *gin.Context) {},
+		LifecycleManager:   lifecycle.New(),
+		Registry:           telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil)),
+		StoreLabels:        telemetry.NewTopK(10),
+		Logger:             logrus.New(),
+	})
+
+	spec, err := apidocs.Spec("")
+	require.NoError(t, err)
+	paths := spec["paths"].(map[string]any)
+
+	param := regexp.MustCompile(`:([a-z_]+)`)
+	routes := make(map[string]bool)
+	for _, route := range r.Routes() {
+		path := param.ReplaceAllString(route.Path, "{$1}")
+		operation := strings.ToLower(route.Method) + " " + path
+		routes[operation] = true
+
+		item, _ := paths[path].(map[string]any)
+		assert.Contains(t, item, strings.ToLower(route.Method), "%s is not in openapi.yaml", operation)
+	}
+	for path, item := range paths {
+		for method := range item.(map[string]any) {
+			if method != "parameters" {
+				assert.True(t, routes[method+" "+path], "%s %s is in openapi.yaml but not routed", method, path)
+			}
+		}
+	}
+}
+
+func TestSetupRouter_ModuleRoutes(t *testing.T) {
+	logger := logrus.New()
+	manager := lifecycle.New()
+	manager.SetReady(true)
+
+	r := SetupRouter(RouterDeps{
+		Modules: []Module{ModuleFunc(func(r Routes) {
+			r.API.GET("/widgets", func(c *gin.Context) { c.String(http.StatusOK, "widgets") })
+			r.Admin.GET("/widgets", func(c *gin.Context) { c.String(http.StatusOK, "admin widgets") })
+		})},
+		APIKeyMiddleware:   func(c *gin.Context) {},
+		SessionMiddleware:  func(c *gin.Context) {},
+		WorkloadMiddleware: func(c *gin.Context) {},
+		LifecycleManager:   manager,
+		Registry:           telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil)),
+		StoreLabels:        telemetry.NewTopK(10),
+		Logger:             logger,
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/widgets", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "widgets", w.Body.String())
+
+	// Admin routes are behind the admin role whichever module adds them.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/widgets", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
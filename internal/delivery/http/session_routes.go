@@ -0,0 +1,18 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// SessionRoutes serves the caller's sessions under /api/v1/me.
+func SessionRoutes(handler *handlers.SessionHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		me := r.API.Group("/me")
+		{
+			me.POST("/sessions", handler.CreateSession)
+			me.GET("/sessions", handler.GetSessions)
+			me.DELETE("/sessions/:id", handler.RevokeSession)
+			me.GET("/csrf", handler.GetCSRFToken)
+		}
+	})
+}
@@ -0,0 +1,12 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// StockSummaryRoutes serves the stores' stock summaries.
+func StockSummaryRoutes(handler *handlers.StockSummaryHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.API.GET("/stores/:id/stock-summary", handler.GetStockSummary)
+	})
+}
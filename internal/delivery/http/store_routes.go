@@ -0,0 +1,19 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// StoreRoutes serves stores under /api/v1/stores.
+func StoreRoutes(handler *handlers.StoreHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		stores := r.API.Group("/stores")
+		{
+			stores.POST("", handler.CreateStore)
+			stores.GET("", handler.GetStores)
+			stores.GET("/:id", handler.GetStore)
+			stores.PUT("/:id", handler.UpdateStore)
+			stores.DELETE("/:id", handler.DeleteStore)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// TrashRoutes serves deleted products under /api/v1/trash.
+func TrashRoutes(handler *handlers.TrashHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		trash := r.API.Group("/trash")
+		{
+			trash.GET("", handler.GetTrash)
+			trash.DELETE("", handler.EmptyTrash)
+			trash.POST("/:id/restore", handler.RestoreProduct)
+		}
+	})
+}
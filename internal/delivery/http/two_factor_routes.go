@@ -0,0 +1,22 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+	"backend-context-engineering-template/internal/delivery/http/middleware"
+)
+
+// TwoFactorRoutes serves two-factor enrollment under /auth/2fa and the
+// login metrics under /admin.
+func TwoFactorRoutes(handler *handlers.TwoFactorHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		// Admins impersonating a user may not change the user's second
+		// factor.
+		twoFactor := r.Root.Group("/auth/2fa", middleware.ForbidImpersonation())
+		{
+			twoFactor.POST("/setup", handler.Setup)
+			twoFactor.POST("/confirm", handler.Confirm)
+			twoFactor.POST("/disable", handler.Disable)
+		}
+		r.Admin.GET("/auth/login-metrics", handler.GetLoginMetrics)
+	})
+}
@@ -0,0 +1,12 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// UsageRoutes reconciles metered usage under /admin.
+func UsageRoutes(handler *handlers.UsageHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		r.Admin.GET("/usage/reconciliation", handler.Reconcile)
+	})
+}
@@ -0,0 +1,20 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// WebhookRoutes serves webhook subscriptions under /api/v1/webhooks.
+func WebhookRoutes(handler *handlers.WebhookHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		webhooks := r.API.Group("/webhooks")
+		{
+			webhooks.POST("", handler.CreateWebhook)
+			webhooks.GET("/:id", handler.GetWebhook)
+			webhooks.GET("", handler.GetWebhooks)
+			webhooks.DELETE("/:id", handler.DeleteWebhook)
+			webhooks.GET("/:id/health", handler.GetWebhookHealth)
+			webhooks.POST("/:id/resume", handler.ResumeWebhook)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	"backend-context-engineering-template/internal/delivery/http/handlers"
+)
+
+// WebhookSecretRoutes rotates the webhook signing secret under
+// /api/v1/webhook-secrets.
+func WebhookSecretRoutes(handler *handlers.WebhookSecretHandler) Module {
+	return ModuleFunc(func(r Routes) {
+		webhookSecrets := r.API.Group("/webhook-secrets")
+		{
+			webhookSecrets.POST("/rotate", handler.RotateSecret)
+			webhookSecrets.DELETE("/previous", handler.RevokePreviousSecret)
+		}
+	})
+}
@@ -0,0 +1,391 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/webhooks"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/secrets"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type storeDay struct {
+	storeID int64
+	day     time.Time
+}
+
+// fakeStore keeps settings and changes in memory the way DigestRepository
+// keeps them in Postgres.
+type fakeStore struct {
+	mu        sync.Mutex
+	settings  map[int64]*domain.DigestSettings
+	changes   map[storeDay][]domain.CatalogChange
+	recordErr error
+}
+
+func newFakeStore(settings ...*domain.DigestSettings) *fakeStore {
+	s := &fakeStore{settings: make(map[int64]*domain.DigestSettings), changes: make(map[storeDay][]domain.CatalogChange)}
+	for _, setting := range settings {
+		s.settings[setting.StoreID] = setting
+	}
+	return s
+}
+
+func (s *fakeStore) RecordChanges(ctx context.Context, changes []domain.CatalogChange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.recordErr != nil {
+		return s.recordErr
+	}
+	for _, change := range changes {
+		key := storeDay{change.StoreID, change.Day}
+		merged := false
+		for i, existing := range s.changes[key] {
+			if existing.Type == change.Type && existing.ProductID == change.ProductID {
+				s.changes[key][i].Changes += change.Changes
+				merged = true
+			}
+		}
+		if !merged {
+			s.changes[key] = append(s.changes[key], change)
+		}
+	}
+	return nil
+}
+
+func (s *fakeStore) GetUnsentDigests(ctx context.Context, day time.Time) ([]*domain.DigestSettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*domain.DigestSettings
+	for _, setting := range s.settings {
+		if !setting.LastSentDay.Valid || setting.LastSentDay.Time.Before(day) {
+			copied := *setting
+			due = append(due, &copied)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].StoreID < due[j].StoreID })
+	return due, nil
+}
+
+func (s *fakeStore) ClaimDigest(ctx context.Context, storeID int64, day time.Time, previous sql.NullTime) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	setting := s.settings[storeID]
+	if setting.LastSentDay != previous {
+		return false, nil
+	}
+	setting.LastSentDay = sql.NullTime{Time: day, Valid: true}
+	return true, nil
+}
+
+func (s *fakeStore) ReleaseDigest(ctx context.Context, storeID int64, day time.Time, previous sql.NullTime) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.settings[storeID].LastSentDay = previous
+	return nil
+}
+
+func (s *fakeStore) GetChanges(ctx context.Context, storeID int64, day time.Time) ([]domain.CatalogChange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changes := append([]domain.CatalogChange(nil), s.changes[storeDay{storeID, day}]...)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ProductID < changes[j].ProductID })
+	return changes, nil
+}
+
+type recordingSender struct {
+	mu       sync.Mutex
+	messages []Message
+	err      error
+}
+
+func (s *recordingSender) Send(ctx context.Context, settings *domain.DigestSettings, digest *domain.CatalogDigest, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+var (
+	yesterday = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	now       = time.Date(2024, 3, 2, 2, 0, 0, 0, time.UTC)
+)
+
+func newTestJob(t *testing.T, store *fakeStore, senders map[string]Sender, at time.Time) (*Job, *Recorder, *telemetry.Registry) {
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	cfg := Config{Interval: time.Minute, SendAfter: time.Hour, FlushInterval: time.Second, MaxPending: 100}
+
+	renderer, err := NewRenderer("")
+	require.NoError(t, err)
+
+	recorder := NewRecorder(store, registry, cfg, clock.Real(), logrus.New())
+	job := NewJob(store, recorder, senders, renderer, registry, cfg, clock.NewFake(at), logrus.New())
+	return job, recorder, registry
+}
+
+func event(eventType string, storeID, productID int64, name string, at time.Time) domain.ProductEvent {
+	e := domain.ProductEvent{Type: eventType, StoreID: storeID, ProductID: productID, OccurredAt: at}
+	if name != "" {
+		e.Product = &domain.ProductEventData{ID: productID, StoreID: storeID, Name: name}
+	}
+	return e
+}
+
+func metrics(t *testing.T, registry *telemetry.Registry) string {
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	return buf.String()
+}
+
+func TestRecorder_TalliesPerProductAndDay(t *testing.T) {
+	store := newFakeStore()
+	_, recorder, registry := newTestJob(t, store, nil, now)
+	ctx := context.Background()
+
+	recorder.Publish(ctx, event(domain.ProductEventUpdated, 1, 10, "Lamp", yesterday.Add(9*time.Hour)))
+	recorder.Publish(ctx, event(domain.ProductEventUpdated, 1, 10, "Desk lamp", yesterday.Add(17*time.Hour)))
+	recorder.Publish(ctx, event(domain.StockEventChanged, 1, 10, "", yesterday.Add(17*time.Hour)))
+	recorder.Publish(ctx, event(domain.ProductEventUpdated, 1, 10, "Desk lamp", yesterday.Add(25*time.Hour)))
+	require.NoError(t, recorder.Flush(ctx))
+
+	changes, _ := store.GetChanges(ctx, 1, yesterday)
+	require.Len(t, changes, 2)
+	byType := map[string]domain.CatalogChange{changes[0].Type: changes[0], changes[1].Type: changes[1]}
+	assert.Equal(t, 2, byType[domain.ProductEventUpdated].Changes)
+	assert.Equal(t, "Desk lamp", byType[domain.ProductEventUpdated].ProductName)
+	assert.Equal(t, yesterday.Add(17*time.Hour), byType[domain.ProductEventUpdated].LastAt)
+	assert.Equal(t, 1, byType[domain.StockEventChanged].Changes)
+
+	next, _ := store.GetChanges(ctx, 1, yesterday.AddDate(0, 0, 1))
+	assert.Len(t, next, 1)
+	assert.Contains(t, metrics(t, registry), `digest_events_total{result="recorded"} 4`)
+}
+
+func TestRecorder_KeepsChangesWhenWriteFails(t *testing.T) {
+	store := newFakeStore()
+	store.recordErr = errors.New("db down")
+	_, recorder, _ := newTestJob(t, store, nil, now)
+	ctx := context.Background()
+
+	recorder.Publish(ctx, event(domain.ProductEventCreated, 1, 10, "Lamp", yesterday))
+	require.Error(t, recorder.Flush(ctx))
+	recorder.Publish(ctx, event(domain.ProductEventCreated, 1, 10, "Lamp", yesterday))
+
+	store.recordErr = nil
+	require.NoError(t, recorder.Flush(ctx))
+	changes, _ := store.GetChanges(ctx, 1, yesterday)
+	require.Len(t, changes, 1)
+	assert.Equal(t, 2, changes[0].Changes)
+}
+
+func TestRecorder_DropsWhenFull(t *testing.T) {
+	_, recorder, registry := newTestJob(t, newFakeStore(), nil, now)
+	recorder.cfg.MaxPending = 1
+	ctx := context.Background()
+
+	recorder.Publish(ctx, event(domain.ProductEventCreated, 1, 10, "Lamp", yesterday))
+	recorder.Publish(ctx, event(domain.ProductEventUpdated, 1, 10, "Lamp", yesterday))
+	recorder.Publish(ctx, event(domain.ProductEventCreated, 1, 10, "Lamp", yesterday))
+
+	output := metrics(t, registry)
+	assert.Contains(t, output, `digest_events_total{result="recorded"} 2`)
+	assert.Contains(t, output, `digest_events_total{result="dropped"} 1`)
+}
+
+func TestJob_SendDue(t *testing.T) {
+	store := newFakeStore(
+		&domain.DigestSettings{StoreID: 1, Channel: domain.DigestChannelEmail, Target: "owner@example.com"},
+		&domain.DigestSettings{StoreID: 2, Channel: domain.DigestChannelEmail, Target: "quiet@example.com"},
+		&domain.DigestSettings{StoreID: 3, Channel: domain.DigestChannelEmail, Target: "done@example.com", LastSentDay: sql.NullTime{Time: yesterday, Valid: true}},
+	)
+	sender := &recordingSender{}
+	job, recorder, registry := newTestJob(t, store, map[string]Sender{domain.DigestChannelEmail: sender}, now)
+	ctx := context.Background()
+
+	recorder.Publish(ctx, event(domain.ProductEventCreated, 1, 10, "Lamp", yesterday.Add(time.Hour)))
+	recorder.Publish(ctx, event(domain.ProductEventUpdated, 1, 11, "Chair", yesterday.Add(time.Hour)))
+	recorder.Publish(ctx, event(domain.ProductEventUpdated, 1, 11, "Chair", yesterday.Add(2*time.Hour)))
+	recorder.Publish(ctx, event(domain.StockEventChanged, 1, 12, "", yesterday.Add(time.Hour)))
+	recorder.Publish(ctx, event(domain.ProductEventCreated, 3, 30, "Table", yesterday.Add(time.Hour)))
+
+	require.NoError(t, job.SendDue(ctx))
+
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, "Catalog changes for store 1 on 2024-03-01", sender.messages[0].Subject)
+	assert.Equal(t, `3 products in store 1 changed on 2024-03-01 (UTC).
+
+Created: 1
+  - Lamp
+
+Updated: 1
+  - Chair (2 times)
+
+Stock changed: 1
+  - Product 12
+`, sender.messages[0].Body)
+
+	assert.True(t, store.settings[1].LastSentDay.Valid)
+	assert.True(t, store.settings[2].LastSentDay.Valid, "an empty day is not retried")
+
+	output := metrics(t, registry)
+	assert.Contains(t, output, `digests_total{channel="email",result="sent"} 1`)
+	assert.Contains(t, output, `digests_total{channel="email",result="empty"} 1`)
+
+	// The digest is sent once a day.
+	require.NoError(t, job.SendDue(ctx))
+	assert.Len(t, sender.messages, 1)
+}
+
+func TestJob_SendDueWaitsForSendAfter(t *testing.T) {
+	store := newFakeStore(&domain.DigestSettings{StoreID: 1, Channel: domain.DigestChannelEmail, Target: "owner@example.com"})
+	sender := &recordingSender{}
+	job, recorder, _ := newTestJob(t, store, map[string]Sender{domain.DigestChannelEmail: sender}, yesterday.AddDate(0, 0, 1).Add(30*time.Minute))
+
+	recorder.Publish(context.Background(), event(domain.ProductEventCreated, 1, 10, "Lamp", yesterday))
+	require.NoError(t, job.SendDue(context.Background()))
+
+	assert.Empty(t, sender.messages)
+	assert.False(t, store.settings[1].LastSentDay.Valid)
+}
+
+func TestJob_SendDueRetriesFailures(t *testing.T) {
+	store := newFakeStore(
+		&domain.DigestSettings{StoreID: 1, Channel: domain.DigestChannelWebhook, Target: "https://hooks.example.com/digest"},
+		&domain.DigestSettings{StoreID: 2, Channel: domain.DigestChannelEmail, Target: "owner@example.com"},
+	)
+	sender := &recordingSender{err: errors.New("503")}
+	job, recorder, registry := newTestJob(t, store, map[string]Sender{domain.DigestChannelWebhook: sender}, now)
+	ctx := context.Background()
+
+	recorder.Publish(ctx, event(domain.ProductEventCreated, 1, 10, "Lamp", yesterday))
+	recorder.Publish(ctx, event(domain.ProductEventCreated, 2, 20, "Desk", yesterday))
+	require.NoError(t, job.SendDue(ctx))
+
+	assert.False(t, store.settings[1].LastSentDay.Valid)
+	assert.False(t, store.settings[2].LastSentDay.Valid)
+	output := metrics(t, registry)
+	assert.Contains(t, output, `digests_total{channel="webhook",result="failed"} 1`)
+	assert.Contains(t, output, `digests_total{channel="email",result="failed"} 1`)
+
+	sender.err = nil
+	require.NoError(t, job.SendDue(ctx))
+	assert.Len(t, sender.messages, 1)
+	assert.True(t, store.settings[1].LastSentDay.Valid)
+}
+
+func TestRenderer_ListsAtMostMaxListed(t *testing.T) {
+	renderer, err := NewRenderer("")
+	require.NoError(t, err)
+
+	digest := &domain.CatalogDigest{StoreID: 1, Day: yesterday}
+	for i := int64(1); i <= maxListed+5; i++ {
+		digest.Changes = append(digest.Changes, domain.CatalogChange{Type: domain.ProductEventDeleted, ProductID: i, ProductName: "Old", Changes: 1})
+	}
+
+	message, err := renderer.Render(digest)
+	require.NoError(t, err)
+	assert.Equal(t, maxListed, strings.Count(message.Body, "  - Old"))
+	assert.Contains(t, message.Body, "Deleted: 25\n")
+	assert.Contains(t, message.Body, "  ... and 5 more\n")
+}
+
+func TestNewRenderer_CustomTemplate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "digest.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(`{{define "subject"}}Store {{.StoreID}}{{end}}{{define "body"}}{{.Total}} changed{{end}}`), 0o600))
+
+	renderer, err := NewRenderer(path)
+	require.NoError(t, err)
+	message, err := renderer.Render(&domain.CatalogDigest{StoreID: 4, Day: yesterday, Changes: []domain.CatalogChange{{Type: domain.ProductEventCreated, ProductID: 1}}})
+	require.NoError(t, err)
+	assert.Equal(t, Message{Subject: "Store 4", Body: "1 changed"}, message)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{{define "body"}}only a body{{end}}`), 0o600))
+	_, err = NewRenderer(path)
+	assert.ErrorContains(t, err, `"subject"`)
+}
+
+func TestWebhookSender_Send(t *testing.T) {
+	var received domain.DigestPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	digest := &domain.CatalogDigest{StoreID: 1, Day: yesterday, Changes: []domain.CatalogChange{
+		{StoreID: 1, Day: yesterday, Type: domain.ProductEventCreated, ProductID: 10, ProductName: "Lamp", Changes: 1},
+	}}
+	settings := &domain.DigestSettings{StoreID: 1, Channel: domain.DigestChannelWebhook, Target: server.URL}
+	require.NoError(t, NewWebhookSender(server.Client(), nil, clock.Real()).Send(context.Background(), settings, digest, Message{Body: "1 changed"}))
+
+	assert.Equal(t, "2024-03-01", received.Day)
+	assert.Equal(t, map[string]int{
+		domain.ProductEventCreated: 1, domain.ProductEventUpdated: 0, domain.ProductEventDeleted: 0,
+		domain.ProductEventPublished: 0, domain.ProductEventUnpublished: 0, domain.StockEventChanged: 0,
+	}, received.Counts)
+	assert.Equal(t, "1 changed", received.Summary)
+}
+
+type storeSecrets map[string][]byte
+
+func (s storeSecrets) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, ok := s[key]; ok {
+		return value, nil
+	}
+	return nil, secrets.ErrNotFound
+}
+
+func TestWebhookSender_Send_SignsWithStoreSecret(t *testing.T) {
+	now := time.Date(2024, 3, 2, 6, 0, 0, 0, time.UTC)
+	secret, err := json.Marshal(domain.WebhookSigningSecret{StoreID: 1, Current: "whsec_digest", RotatedAt: now.Add(-time.Hour)})
+	require.NoError(t, err)
+
+	var signature string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(webhooks.SignatureHeader)
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	digest := &domain.CatalogDigest{StoreID: 1, Day: yesterday}
+	settings := &domain.DigestSettings{StoreID: 1, Channel: domain.DigestChannelWebhook, Target: server.URL}
+	sender := NewWebhookSender(server.Client(), storeSecrets{domain.WebhookSecretKey(1): secret}, clock.NewFake(now))
+	require.NoError(t, sender.Send(context.Background(), settings, digest, Message{Body: "nothing changed"}))
+
+	assert.Equal(t, webhooks.Sign([]string{"whsec_digest"}, now, body), signature)
+
+	// Stores without a secret get unsigned digests.
+	settings.StoreID = 2
+	require.NoError(t, sender.Send(context.Background(), settings, &domain.CatalogDigest{StoreID: 2, Day: yesterday}, Message{}))
+	assert.Empty(t, signature)
+}
@@ -0,0 +1,183 @@
+// Package digest sends stores a daily summary of their catalog changes, by
+// email or webhook, for merchants who would rather not receive every
+// change as it happens.
+package digest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+const dayLayout = "2006-01-02"
+
+type Config struct {
+	// Interval is how often the job checks for digests to send.
+	Interval time.Duration
+	// SendAfter is how long after UTC midnight the previous day's digests
+	// are sent, so changes recorded by every instance have been written.
+	SendAfter time.Duration
+	// FlushInterval is how often recorded changes are written.
+	FlushInterval time.Duration
+	// MaxPending caps the changes held in memory between writes.
+	MaxPending int
+}
+
+// Store holds digest settings and recorded changes.
+type Store interface {
+	// GetUnsentDigests returns the settings of stores whose digest for day
+	// has not been sent.
+	GetUnsentDigests(ctx context.Context, day time.Time) ([]*domain.DigestSettings, error)
+	// ClaimDigest marks the store's digest for day as sent, unless another
+	// instance changed its last sent day from previous first.
+	ClaimDigest(ctx context.Context, storeID int64, day time.Time, previous sql.NullTime) (bool, error)
+	// ReleaseDigest undoes a claim so the digest is sent again.
+	ReleaseDigest(ctx context.Context, storeID int64, day time.Time, previous sql.NullTime) error
+	GetChanges(ctx context.Context, storeID int64, day time.Time) ([]domain.CatalogChange, error)
+}
+
+// Sender delivers a rendered digest over one channel.
+type Sender interface {
+	Send(ctx context.Context, settings *domain.DigestSettings, digest *domain.CatalogDigest, message Message) error
+}
+
+var (
+	errChannelDisabled = errors.New("digest channel is not configured")
+	errEmptyDigest     = errors.New("no catalog changes")
+)
+
+type Job struct {
+	store    Store
+	recorder *Recorder
+	senders  map[string]Sender
+	renderer *Renderer
+	cfg      Config
+	logger   *logrus.Logger
+	clock    clock.Clock
+
+	sent *telemetry.Counter
+}
+
+// NewJob builds a digest job. senders maps channels to their sender;
+// stores on a channel without one are counted as failed.
+func NewJob(store Store, recorder *Recorder, senders map[string]Sender, renderer *Renderer, registry *telemetry.Registry, cfg Config, clk clock.Clock, logger *logrus.Logger) *Job {
+	return &Job{
+		store:    store,
+		recorder: recorder,
+		senders:  senders,
+		renderer: renderer,
+		cfg:      cfg,
+		logger:   logger,
+		clock:    clk,
+		sent:     registry.NewCounter("digests_total", "Daily digests by channel and result.", "channel", "result"),
+	}
+}
+
+// Run sends due digests every Interval until ctx is cancelled.
+func (j *Job) Run(ctx context.Context) {
+	j.logger.WithFields(logrus.Fields{"interval": j.cfg.Interval, "send_after": j.cfg.SendAfter}).Info("Digest job started")
+
+	ticker := j.clock.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("Digest job stopped")
+			return
+		case <-ticker.C():
+			if err := j.SendDue(ctx); err != nil && ctx.Err() == nil {
+				j.logger.WithError(err).Error("Failed to send digests")
+			}
+		}
+	}
+}
+
+// SendDue sends the previous UTC day's digest to every store that has not
+// received it, once SendAfter has passed. A store without changes that day
+// is skipped. A failed send is retried on the next run.
+func (j *Job) SendDue(ctx context.Context) error {
+	now := j.clock.Now()
+	today := startOfDay(now)
+	if now.Sub(today) < j.cfg.SendAfter {
+		return nil
+	}
+	day := today.AddDate(0, 0, -1)
+
+	if err := j.recorder.Flush(ctx); err != nil {
+		return err
+	}
+
+	due, err := j.store.GetUnsentDigests(ctx, day)
+	if err != nil {
+		return fmt.Errorf("failed to get unsent digests: %w", err)
+	}
+
+	for _, settings := range due {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		result := j.send(ctx, settings, day)
+		j.sent.Inc(settings.Channel, result)
+	}
+	return nil
+}
+
+// send sends one store's digest and returns the result to report.
+func (j *Job) send(ctx context.Context, settings *domain.DigestSettings, day time.Time) string {
+	logger := j.logger.WithFields(logrus.Fields{"store_id": settings.StoreID, "channel": settings.Channel, "day": day.Format(dayLayout)})
+
+	claimed, err := j.store.ClaimDigest(ctx, settings.StoreID, day, settings.LastSentDay)
+	if err != nil {
+		logger.WithError(err).Error("Failed to claim digest")
+		return "failed"
+	}
+	if !claimed {
+		return "claimed_elsewhere"
+	}
+
+	err = j.deliver(ctx, settings, day)
+	if errors.Is(err, errEmptyDigest) {
+		return "empty"
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to send digest")
+		if err := j.store.ReleaseDigest(context.WithoutCancel(ctx), settings.StoreID, day, settings.LastSentDay); err != nil {
+			logger.WithError(err).Error("Failed to release digest, it will not be retried")
+		}
+		return "failed"
+	}
+
+	logger.Info("Digest sent")
+	return "sent"
+}
+
+func (j *Job) deliver(ctx context.Context, settings *domain.DigestSettings, day time.Time) error {
+	sender, ok := j.senders[settings.Channel]
+	if !ok {
+		return fmt.Errorf("%w: %s", errChannelDisabled, settings.Channel)
+	}
+
+	changes, err := j.store.GetChanges(ctx, settings.StoreID, day)
+	if err != nil {
+		return fmt.Errorf("failed to get catalog changes: %w", err)
+	}
+	if len(changes) == 0 {
+		return errEmptyDigest
+	}
+
+	digest := &domain.CatalogDigest{StoreID: settings.StoreID, Day: day, Changes: changes}
+	message, err := j.renderer.Render(digest)
+	if err != nil {
+		return err
+	}
+	return sender.Send(ctx, settings, digest, message)
+}
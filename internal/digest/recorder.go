@@ -0,0 +1,167 @@
+package digest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ChangeStore persists recorded catalog changes.
+type ChangeStore interface {
+	// RecordChanges adds the changes to those already stored for the same
+	// store, day, type and product.
+	RecordChanges(ctx context.Context, changes []domain.CatalogChange) error
+}
+
+type changeKey struct {
+	storeID   int64
+	day       time.Time
+	eventType string
+	productID int64
+}
+
+// Recorder collects published product events into per-day catalog
+// changes. Events are tallied in memory and written every FlushInterval,
+// so publishing never waits on the database.
+type Recorder struct {
+	store  ChangeStore
+	cfg    Config
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	pending  map[changeKey]*domain.CatalogChange
+	dropping bool
+
+	events *telemetry.Counter
+}
+
+func NewRecorder(store ChangeStore, registry *telemetry.Registry, cfg Config, clk clock.Clock, logger *logrus.Logger) *Recorder {
+	return &Recorder{
+		store:   store,
+		cfg:     cfg,
+		logger:  logger,
+		clock:   clk,
+		pending: make(map[changeKey]*domain.CatalogChange),
+		events:  registry.NewCounter("digest_events_total", "Product events recorded for digests, by result.", "result"),
+	}
+}
+
+func (r *Recorder) Publish(ctx context.Context, event domain.ProductEvent) {
+	key := changeKey{
+		storeID:   event.StoreID,
+		day:       startOfDay(event.OccurredAt),
+		eventType: event.Type,
+		productID: event.ProductID,
+	}
+
+	r.mu.Lock()
+	change, ok := r.pending[key]
+	if !ok {
+		if len(r.pending) >= r.cfg.MaxPending {
+			warn := !r.dropping
+			r.dropping = true
+			r.mu.Unlock()
+
+			r.events.Inc("dropped")
+			if warn {
+				r.logger.WithField("max_pending", r.cfg.MaxPending).Warn("Digest change buffer is full, dropping events")
+			}
+			return
+		}
+		change = &domain.CatalogChange{StoreID: key.storeID, Day: key.day, Type: key.eventType, ProductID: key.productID}
+		r.pending[key] = change
+	}
+	change.Changes++
+	if event.Product != nil {
+		change.ProductName = event.Product.Name
+	}
+	if event.OccurredAt.After(change.LastAt) {
+		change.LastAt = event.OccurredAt
+	}
+	r.mu.Unlock()
+
+	r.events.Inc("recorded")
+}
+
+// Run writes recorded changes every FlushInterval until ctx is cancelled,
+// then writes what is left.
+func (r *Recorder) Run(ctx context.Context) {
+	r.logger.WithField("interval", r.cfg.FlushInterval).Info("Digest recorder started")
+
+	ticker := r.clock.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := r.Flush(context.WithoutCancel(ctx)); err != nil {
+				r.logger.WithError(err).Error("Failed to write digest changes on shutdown")
+			}
+			r.logger.Info("Digest recorder stopped")
+			return
+		case <-ticker.C():
+			if err := r.Flush(ctx); err != nil && ctx.Err() == nil {
+				r.logger.WithError(err).Error("Failed to write digest changes")
+			}
+		}
+	}
+}
+
+// Flush writes the recorded changes. On failure they are kept for the next
+// flush.
+func (r *Recorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	flushed := r.pending
+	r.pending = make(map[changeKey]*domain.CatalogChange)
+	r.dropping = false
+	r.mu.Unlock()
+
+	if len(flushed) == 0 {
+		return nil
+	}
+
+	changes := make([]domain.CatalogChange, 0, len(flushed))
+	for _, change := range flushed {
+		changes = append(changes, *change)
+	}
+	if err := r.store.RecordChanges(ctx, changes); err != nil {
+		r.requeue(flushed)
+		return fmt.Errorf("failed to record catalog changes: %w", err)
+	}
+	return nil
+}
+
+// requeue merges changes that failed to be written back into those
+// recorded since.
+func (r *Recorder) requeue(flushed map[changeKey]*domain.CatalogChange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, change := range flushed {
+		current, ok := r.pending[key]
+		if !ok {
+			r.pending[key] = change
+			continue
+		}
+		current.Changes += change.Changes
+		if current.ProductName == "" {
+			current.ProductName = change.ProductName
+		}
+		if change.LastAt.After(current.LastAt) {
+			current.LastAt = change.LastAt
+		}
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,110 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"text/template"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+//go:embed templates/digest.tmpl
+var templateFiles embed.FS
+
+// maxListed caps the products named per section; the rest are counted.
+const maxListed = 20
+
+// Message is a rendered digest.
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Renderer renders digests from a text/template file that defines a
+// "subject" and a "body" template.
+type Renderer struct {
+	tmpl *template.Template
+}
+
+// NewRenderer loads the template at path, or the built-in one if path is
+// empty.
+func NewRenderer(path string) (*Renderer, error) {
+	var tmpl *template.Template
+	var err error
+	if path == "" {
+		tmpl, err = template.ParseFS(templateFiles, "templates/digest.tmpl")
+	} else {
+		tmpl, err = template.ParseFiles(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse digest template: %w", err)
+	}
+
+	for _, name := range []string{"subject", "body"} {
+		if tmpl.Lookup(name) == nil {
+			return nil, fmt.Errorf("digest template does not define %q", name)
+		}
+	}
+	return &Renderer{tmpl: tmpl}, nil
+}
+
+type templateData struct {
+	StoreID  int64
+	Day      string
+	Total    int
+	Sections []section
+}
+
+type section struct {
+	Title    string
+	Count    int
+	Products []domain.CatalogChange
+	More     int
+}
+
+var sectionTitles = []struct {
+	eventType string
+	title     string
+}{
+	{domain.ProductEventCreated, "Created"},
+	{domain.ProductEventUpdated, "Updated"},
+	{domain.ProductEventDeleted, "Deleted"},
+	{domain.ProductEventPublished, "Published"},
+	{domain.ProductEventUnpublished, "Unpublished"},
+	{domain.StockEventChanged, "Stock changed"},
+}
+
+func (r *Renderer) Render(digest *domain.CatalogDigest) (Message, error) {
+	data := templateData{StoreID: digest.StoreID, Day: digest.Day.Format(dayLayout)}
+
+	products := make(map[int64]bool)
+	for _, s := range sectionTitles {
+		current := section{Title: s.title}
+		for _, change := range digest.Changes {
+			if change.Type != s.eventType {
+				continue
+			}
+			products[change.ProductID] = true
+			current.Count++
+			if len(current.Products) < maxListed {
+				current.Products = append(current.Products, change)
+			} else {
+				current.More++
+			}
+		}
+		if current.Count > 0 {
+			data.Sections = append(data.Sections, current)
+		}
+	}
+	data.Total = len(products)
+
+	var subject, body bytes.Buffer
+	if err := r.tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render digest subject: %w", err)
+	}
+	if err := r.tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return Message{}, fmt.Errorf("failed to render digest body: %w", err)
+	}
+	return Message{Subject: subject.String(), Body: body.String()}, nil
+}
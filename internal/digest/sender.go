@@ -0,0 +1,95 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/webhooks"
+	"backend-context-engineering-template/pkg/clock"
+)
+
+// Mailer sends a plain-text email.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// EmailSender mails the rendered digest to the store's address.
+type EmailSender struct {
+	mailer Mailer
+}
+
+func NewEmailSender(mailer Mailer) *EmailSender {
+	return &EmailSender{mailer: mailer}
+}
+
+func (s *EmailSender) Send(ctx context.Context, settings *domain.DigestSettings, digest *domain.CatalogDigest, message Message) error {
+	return s.mailer.Send(ctx, settings.Target, message.Subject, message.Body)
+}
+
+// WebhookSender posts the digest as a DigestPayload to the store's URL,
+// signed with the store's webhook secret like product event deliveries.
+type WebhookSender struct {
+	client  *http.Client
+	secrets webhooks.SecretStore
+	clock   clock.Clock
+}
+
+// NewWebhookSender builds a sender. secrets may be nil, in which case
+// digests are sent unsigned.
+func NewWebhookSender(client *http.Client, secrets webhooks.SecretStore, clk clock.Clock) *WebhookSender {
+	return &WebhookSender{client: client, secrets: secrets, clock: clk}
+}
+
+func (s *WebhookSender) Send(ctx context.Context, settings *domain.DigestSettings, digest *domain.CatalogDigest, message Message) error {
+	body, err := json.Marshal(NewPayload(digest, message))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, settings.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.secrets != nil {
+		now := s.clock.Now()
+		keys, err := webhooks.SigningSecrets(ctx, s.secrets, settings.StoreID, now)
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			req.Header.Set(webhooks.SignatureHeader, webhooks.Sign(keys, now, body))
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("digest request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("digest webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// NewPayload builds the webhook body for a digest. Counts has an entry for
+// every event type, zero included.
+func NewPayload(digest *domain.CatalogDigest, message Message) domain.DigestPayload {
+	counts := make(map[string]int, len(domain.EventTypes))
+	for _, eventType := range domain.EventTypes {
+		counts[eventType] = digest.Count(eventType)
+	}
+	return domain.DigestPayload{
+		StoreID: digest.StoreID,
+		Day:     digest.Day.Format(dayLayout),
+		Counts:  counts,
+		Changes: digest.Changes,
+		Summary: message.Body,
+	}
+}
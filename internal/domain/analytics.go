@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Storefront interactions with a product that clients report.
+const (
+	AnalyticsEventView      = "view"
+	AnalyticsEventAddToCart = "add_to_cart"
+	AnalyticsEventPurchase  = "purchase"
+)
+
+// How much one event of each type adds to a product's popularity score.
+const (
+	PopularityWeightView      = 1
+	PopularityWeightAddToCart = 5
+	PopularityWeightPurchase  = 20
+)
+
+// MaxAnalyticsBatch caps the events accepted in one request.
+const MaxAnalyticsBatch = 500
+
+// AnalyticsEvent is one interaction with a product. OccurredAt defaults to
+// when the event is received.
+type AnalyticsEvent struct {
+	ProductID  int64     `json:"product_id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (e *AnalyticsEvent) Validate() error {
+	if e.ProductID <= 0 {
+		return errors.New("product_id must be positive")
+	}
+
+	switch e.Type {
+	case AnalyticsEventView, AnalyticsEventAddToCart, AnalyticsEventPurchase:
+	default:
+		return errors.New("type must be view, add_to_cart or purchase")
+	}
+
+	return nil
+}
+
+// AnalyticsCount counts a product's events of one type on a UTC day.
+type AnalyticsCount struct {
+	ProductID int64     `json:"product_id" db:"product_id"`
+	Day       time.Time `json:"day" db:"day"`
+	Type      string    `json:"type" db:"event_type"`
+	Events    int64     `json:"events" db:"events"`
+}
+
+// ProductStats totals a product's events since Since, or ever when Since
+// is zero.
+type ProductStats struct {
+	ProductID  int64     `json:"product_id"`
+	StoreID    int64     `json:"store_id"`
+	Views      int64     `json:"views"`
+	AddToCarts int64     `json:"add_to_carts"`
+	Purchases  int64     `json:"purchases"`
+	Since      time.Time `json:"since"`
+}
+
+// PopularityScoring decays a product's weighted event counts by age, so
+// products that stopped selling drop down the popularity order. An event
+// HalfLife before Day counts half as much as one on Day.
+type PopularityScoring struct {
+	// Day is the UTC day scores are computed for.
+	Day      time.Time
+	HalfLife time.Duration
+}
+
+// HalfLifeDays is HalfLife in days, at least one.
+func (s PopularityScoring) HalfLifeDays() float64 {
+	return math.Max(s.HalfLife.Hours()/24, 1)
+}
+
+// Since is the first day whose events are scored. Older events would weigh
+// less than a thousandth of one on Day and are left out.
+func (s PopularityScoring) Since() time.Time {
+	return s.Day.AddDate(0, 0, -int(math.Ceil(10*s.HalfLifeDays())))
+}
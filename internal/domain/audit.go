@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// AuditEntry records one state-changing API call: who made it, what it
+// targeted and how it ended. Entries recorded by a use case rather than per
+// request name an Event and describe it in Detail instead; their request
+// fields other than Actor and ClientIP are empty. When an admin acted as
+// the user named in Actor, ImpersonatedBy names the admin.
+type AuditEntry struct {
+	ID             int64     `json:"id" db:"id"`
+	Actor          string    `json:"actor" db:"actor"`
+	ImpersonatedBy string    `json:"impersonated_by,omitempty" db:"impersonated_by"`
+	ClientIP       string    `json:"client_ip" db:"client_ip"`
+	Method         string    `json:"method" db:"method"`
+	Route          string    `json:"route" db:"route"`
+	Path           string    `json:"path" db:"path"`
+	StatusCode     int       `json:"status_code" db:"status_code"`
+	Event          string    `json:"event,omitempty" db:"event"`
+	Detail         string    `json:"detail,omitempty" db:"detail"`
+	OccurredAt     time.Time `json:"occurred_at" db:"occurred_at"`
+}
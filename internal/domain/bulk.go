@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Bulk operation kinds.
+const (
+	BulkOperationEmptyTrash = "empty_trash"
+)
+
+// Audit events recorded for the two steps of a bulk confirmation.
+const (
+	AuditEventBulkRequested = "bulk_operation.requested"
+	AuditEventBulkConfirmed = "bulk_operation.confirmed"
+)
+
+// BulkOperation is a mutation about to touch Affected rows of a store at
+// once.
+type BulkOperation struct {
+	Kind     string `json:"kind"`
+	StoreID  int64  `json:"store_id"`
+	Affected int64  `json:"affected"`
+}
+
+func (o BulkOperation) String() string {
+	return fmt.Sprintf("%s store=%d rows=%d", o.Kind, o.StoreID, o.Affected)
+}
+
+// BulkConfirmation is handed out when a bulk operation is held back. The
+// same caller confirms the operation by repeating it with Token before
+// ExpiresAt; the token is only good for an operation of the same size.
+type BulkConfirmation struct {
+	Operation BulkOperation
+	Token     string
+	ExpiresAt time.Time
+}
+
+// BulkConfirmationError is returned for a bulk operation held back until
+// its caller confirms it. It matches ErrConfirmationRequired with
+// errors.Is.
+type BulkConfirmationError struct {
+	Confirmation *BulkConfirmation
+}
+
+func (e *BulkConfirmationError) Error() string {
+	return fmt.Sprintf("%s: %s affects %d rows of store %d", ErrConfirmationRequired,
+		e.Confirmation.Operation.Kind, e.Confirmation.Operation.Affected, e.Confirmation.Operation.StoreID)
+}
+
+func (e *BulkConfirmationError) Unwrap() error {
+	return ErrConfirmationRequired
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+// MaxBundleComponents caps how many products one bundle is made of.
+const MaxBundleComponents = 50
+
+// BundleComponent is a product a bundle is made of and how much of it goes
+// into one bundle, in the component's unit. Name, Unit and Stock are the
+// component product's, filled when a bundle is read.
+type BundleComponent struct {
+	BundleID  int64             `json:"bundle_id" db:"bundle_id"`
+	ProductID int64             `json:"product_id" db:"product_id"`
+	Quantity  quantity.Quantity `json:"quantity" db:"quantity"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+
+	Name  string            `json:"name"`
+	Unit  string            `json:"unit"`
+	Stock quantity.Quantity `json:"stock"`
+	// BackorderLimit is how far below zero the product's stock may go,
+	// or zero when it does not allow backorders.
+	BackorderLimit quantity.Quantity `json:"backorder_limit"`
+	Available      int64             `json:"available"`
+}
+
+// Bundle is a product sold as a set of other products. Its own amount is
+// not used: Available, the number of whole bundles the components' stock
+// can make, is derived from them.
+type Bundle struct {
+	Product    *Product
+	Components []BundleComponent
+	Available  int64
+}
+
+// StockDecrement takes Amount off a product's stock.
+type StockDecrement struct {
+	ProductID int64
+	Amount    quantity.Quantity
+}
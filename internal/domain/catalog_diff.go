@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// ProductChange is a product's state at the start and at the end of a
+// period, from its revisions. Before is nil when the product did not
+// exist at the start, After when it no longer existed at the end.
+type ProductChange struct {
+	ProductID int64
+	Before    *Product
+	After     *Product
+}
+
+// ChangedProduct is a product that existed throughout a period, with the
+// fields that differ between its start and end states.
+type ChangedProduct struct {
+	Product *Product
+	Fields  []string
+}
+
+// CatalogDiff compares the catalog at From with the catalog at To.
+// Products created and deleted in between appear in neither list, and
+// products changed back to how they were are not Changed.
+type CatalogDiff struct {
+	From    time.Time
+	To      time.Time
+	Created []*Product
+	Deleted []*Product
+	Changed []ChangedProduct
+}
+
+// ChangedFields lists the fields that differ between two states of a
+// product, by their JSON names. Timestamps are not compared.
+func ChangedFields(before, after *Product) []string {
+	var fields []string
+	add := func(name string, changed bool) {
+		if changed {
+			fields = append(fields, name)
+		}
+	}
+
+	add("store_id", before.StoreID != after.StoreID)
+	add("name", before.Name != after.Name)
+	add("description", before.Description != after.Description)
+	add("description_format", before.DescriptionFormat != after.DescriptionFormat)
+	add("amount", before.Amount != after.Amount)
+	add("unit", before.Unit != after.Unit)
+	add("price", before.Price != after.Price)
+	add("status", before.Status != after.Status)
+	add("moderation_status", before.ModerationStatus != after.ModerationStatus)
+	add("moderation_reason", before.ModerationReason != after.ModerationReason)
+	add("allow_backorder", before.AllowBackorder != after.AllowBackorder)
+	add("backorder_limit", before.BackorderLimit != after.BackorderLimit)
+	add("preorder_release_date", !sameTime(before.PreorderReleaseDate, after.PreorderReleaseDate))
+	add("low_stock_threshold", before.LowStockThreshold != after.LowStockThreshold)
+	add("publish_at", !sameTime(before.PublishAt, after.PublishAt))
+	add("unpublish_at", !sameTime(before.UnpublishAt, after.UnpublishAt))
+	return fields
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
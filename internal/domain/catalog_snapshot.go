@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// CatalogSnapshot is a store's products as they were at TakenAt. The
+// products themselves are kept as an object in storage under ObjectKey.
+type CatalogSnapshot struct {
+	ID        int64     `json:"id" db:"id"`
+	StoreID   int64     `json:"store_id" db:"store_id"`
+	ObjectKey string    `json:"object_key" db:"object_key"`
+	Products  int       `json:"products" db:"products"`
+	TakenAt   time.Time `json:"taken_at" db:"taken_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type CatalogRestoreStatus string
+
+const (
+	CatalogRestoreStatusPending   CatalogRestoreStatus = "pending"
+	CatalogRestoreStatusRunning   CatalogRestoreStatus = "running"
+	CatalogRestoreStatusSucceeded CatalogRestoreStatus = "succeeded"
+	CatalogRestoreStatusFailed    CatalogRestoreStatus = "failed"
+)
+
+// CatalogRestore rolls a store's catalog back to a snapshot. Created,
+// Updated and Deleted count the products it wrote once it succeeded.
+type CatalogRestore struct {
+	ID         int64                `json:"id" db:"id"`
+	StoreID    int64                `json:"store_id" db:"store_id"`
+	SnapshotID int64                `json:"snapshot_id" db:"snapshot_id"`
+	Status     CatalogRestoreStatus `json:"status" db:"status"`
+	Created    int                  `json:"created" db:"created"`
+	Updated    int                  `json:"updated" db:"updated"`
+	Deleted    int                  `json:"deleted" db:"deleted"`
+	Error      sql.NullString       `json:"error" db:"error"`
+	CreatedAt  time.Time            `json:"created_at" db:"created_at"`
+	StartedAt  sql.NullTime         `json:"started_at" db:"started_at"`
+	FinishedAt sql.NullTime         `json:"finished_at" db:"finished_at"`
+}
+
+// PlanRestore works out what restoring a snapshot over the current
+// catalog does: snapshot products that no longer exist are Created,
+// current products missing from the snapshot are Deleted, and snapshot
+// products whose state differs from the current one are Changed, in their
+// snapshot state. Products are matched by ID. The diff's times are left
+// to the caller.
+func PlanRestore(current, snapshot []*Product) *CatalogDiff {
+	byID := make(map[int64]*Product, len(current))
+	for _, p := range current {
+		byID[p.ID] = p
+	}
+
+	diff := &CatalogDiff{}
+	kept := make(map[int64]bool, len(snapshot))
+	for _, p := range snapshot {
+		kept[p.ID] = true
+		existing, ok := byID[p.ID]
+		if !ok {
+			diff.Created = append(diff.Created, p)
+			continue
+		}
+		if fields := ChangedFields(existing, p); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, ChangedProduct{Product: p, Fields: fields})
+		}
+	}
+
+	for _, p := range current {
+		if !kept[p.ID] {
+			diff.Deleted = append(diff.Deleted, p)
+		}
+	}
+
+	return diff
+}
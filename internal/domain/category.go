@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Category groups products. Categories form a tree: a category without a
+// parent is a root, and one cannot be deleted while it has subcategories.
+// A product can be in any number of categories.
+type Category struct {
+	ID        int64         `json:"id" db:"id"`
+	ParentID  sql.NullInt64 `json:"parent_id" db:"parent_id"`
+	Name      string        `json:"name" db:"name"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// MaxProductCategories caps the categories one product is in.
+const MaxProductCategories = 50
+
+func (c *Category) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return invalidField("name", "required", "name is required")
+	}
+
+	if len(c.Name) > 100 {
+		return invalidField("name", "max", "name must not exceed 100 characters")
+	}
+
+	if c.ParentID.Valid && c.ParentID.Int64 <= 0 {
+		return invalidField("parent_id", "gt", "parent_id must be positive")
+	}
+
+	return nil
+}
+
+// ValidateCategoryIDs checks the categories a product is put in.
+func ValidateCategoryIDs(ids []int64) error {
+	if len(ids) > MaxProductCategories {
+		return invalidFieldf("category_ids", "max", "a product can be in at most %d categories", MaxProductCategories)
+	}
+
+	for _, id := range ids {
+		if id <= 0 {
+			return invalidField("category_ids", "gt", "category_ids must be positive")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+)
+
+const ConnectorKindShopify = "shopify"
+
+type Connector struct {
+	ID        int64             `json:"id" db:"id"`
+	StoreID   int64             `json:"store_id" db:"store_id"`
+	Kind      string            `json:"kind" db:"kind"`
+	Settings  map[string]string `json:"settings" db:"settings"`
+	Enabled   bool              `json:"enabled" db:"enabled"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+func (c *Connector) Validate() error {
+	if c.StoreID <= 0 {
+		return invalidField("store_id", "gt", "store_id must be positive")
+	}
+
+	if c.Kind == "" {
+		return invalidField("kind", "required", "kind is required")
+	}
+
+	return nil
+}
+
+// SecretKey is the key under which the connector's credentials are kept in
+// the secrets store.
+func (c *Connector) SecretKey() string {
+	return "connector/" + c.Kind + "/" + strconv.FormatInt(c.ID, 10)
+}
+
+// ConnectorCheckpoint records how far a connector has synced so an
+// interrupted pull resumes from the last page and completed pulls only ask
+// for changes since the previous run.
+type ConnectorCheckpoint struct {
+	ConnectorID int64        `json:"connector_id" db:"connector_id"`
+	Cursor      string       `json:"cursor" db:"cursor"`
+	Since       sql.NullTime `json:"since" db:"since"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+type ConnectorSyncStatus string
+
+const (
+	ConnectorSyncStatusRunning   ConnectorSyncStatus = "running"
+	ConnectorSyncStatusSucceeded ConnectorSyncStatus = "succeeded"
+	ConnectorSyncStatusFailed    ConnectorSyncStatus = "failed"
+)
+
+type ConnectorSync struct {
+	ID          int64               `json:"id" db:"id"`
+	ConnectorID int64               `json:"connector_id" db:"connector_id"`
+	Status      ConnectorSyncStatus `json:"status" db:"status"`
+	Pulled      int                 `json:"pulled" db:"pulled"`
+	Created     int                 `json:"created" db:"created"`
+	Updated     int                 `json:"updated" db:"updated"`
+	Pushed      int                 `json:"pushed" db:"pushed"`
+	Failed      int                 `json:"failed" db:"failed"`
+	Error       sql.NullString      `json:"error" db:"error"`
+	StartedAt   time.Time           `json:"started_at" db:"started_at"`
+	FinishedAt  sql.NullTime        `json:"finished_at" db:"finished_at"`
+}
+
+// ProductLink maps a local product to its identifier in an external system.
+type ProductLink struct {
+	ConnectorID int64     `json:"connector_id" db:"connector_id"`
+	ProductID   int64     `json:"product_id" db:"product_id"`
+	ExternalID  string    `json:"external_id" db:"external_id"`
+	SyncedAt    time.Time `json:"synced_at" db:"synced_at"`
+}
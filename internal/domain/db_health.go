@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TableStats are a table's cumulative activity counters from
+// pg_stat_user_tables, counted since StatsSince.
+type TableStats struct {
+	Table           string
+	LiveTuples      int64
+	DeadTuples      int64
+	SeqScans        int64
+	SeqTuplesRead   int64
+	IndexScans      int64
+	TotalBytes      int64
+	LastVacuum      sql.NullTime
+	LastAutoanalyze sql.NullTime
+}
+
+type IndexStats struct {
+	Table string
+	Index string
+	// Columns are the indexed columns in order. Expressions are left out.
+	Columns []string
+	Scans   int64
+	Bytes   int64
+	Unique  bool
+}
+
+const (
+	DBHealthCheckDeadTuples  = "dead_tuples"
+	DBHealthCheckUnusedIndex = "unused_index"
+	DBHealthCheckSeqScans    = "seq_scans"
+)
+
+// DBHealthFinding is one problem found by a health check and what to do
+// about it.
+type DBHealthFinding struct {
+	Check          string
+	Table          string
+	Index          string
+	Detail         string
+	Recommendation string
+}
+
+type DBHealthReport struct {
+	GeneratedAt time.Time
+	// StatsSince is when the statistics were last reset; counters only
+	// cover activity after it.
+	StatsSince sql.NullTime
+	Tables     []TableStats
+	Indexes    []IndexStats
+	Findings   []DBHealthFinding
+}
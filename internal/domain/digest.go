@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"database/sql"
+	"net/mail"
+	"net/url"
+	"time"
+)
+
+const (
+	DigestChannelEmail   = "email"
+	DigestChannelWebhook = "webhook"
+)
+
+// DigestSettings subscribes a store to a daily digest of its catalog
+// changes, sent to Target: an email address or a webhook URL depending on
+// Channel. LastSentDay is the most recent day a digest was sent for.
+type DigestSettings struct {
+	StoreID     int64        `json:"store_id" db:"store_id"`
+	Channel     string       `json:"channel" db:"channel"`
+	Target      string       `json:"target" db:"target"`
+	LastSentDay sql.NullTime `json:"last_sent_day" db:"last_sent_day"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+func (s *DigestSettings) Validate() error {
+	if s.StoreID <= 0 {
+		return invalidField("store_id", "gt", "store_id must be positive")
+	}
+
+	switch s.Channel {
+	case DigestChannelEmail:
+		address, err := mail.ParseAddress(s.Target)
+		if err != nil || address.Address != s.Target {
+			return invalidField("target", "email", "target must be an email address")
+		}
+	case DigestChannelWebhook:
+		u, err := url.Parse(s.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return invalidField("target", "url", "target must be an absolute http or https URL")
+		}
+	default:
+		return invalidField("channel", "oneof", "channel must be email or webhook")
+	}
+
+	return nil
+}
+
+// CatalogChange records that a product had events of one type on a day.
+// Changes counts the events; repeated updates to a product in a day are
+// one row.
+type CatalogChange struct {
+	StoreID     int64     `json:"store_id" db:"store_id"`
+	Day         time.Time `json:"day" db:"day"`
+	Type        string    `json:"type" db:"event_type"`
+	ProductID   int64     `json:"product_id" db:"product_id"`
+	ProductName string    `json:"product_name" db:"product_name"`
+	Changes     int       `json:"changes" db:"changes"`
+	LastAt      time.Time `json:"last_at" db:"last_at"`
+}
+
+// CatalogDigest summarizes one store's catalog changes on one UTC day.
+type CatalogDigest struct {
+	StoreID int64
+	Day     time.Time
+	Changes []CatalogChange
+}
+
+// Count returns how many products had changes of the given type.
+func (d *CatalogDigest) Count(eventType string) int {
+	count := 0
+	for _, change := range d.Changes {
+		if change.Type == eventType {
+			count++
+		}
+	}
+	return count
+}
+
+// DigestPayload is the body of a digest sent to a webhook. Summary is the
+// same rendered text an email digest would carry.
+type DigestPayload struct {
+	StoreID int64           `json:"store_id"`
+	Day     string          `json:"day"`
+	Counts  map[string]int  `json:"counts"`
+	Changes []CatalogChange `json:"changes"`
+	Summary string          `json:"summary"`
+}
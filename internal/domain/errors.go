@@ -3,7 +3,106 @@ package domain
 import "errors"
 
 var (
-	ErrProductNotFound  = errors.New("product not found")
-	ErrInvalidProduct   = errors.New("invalid product data")
-	ErrDuplicateProduct = errors.New("product with this name already exists")
+	ErrProductNotFound        = errors.New("product not found")
+	ErrInvalidProduct         = errors.New("invalid product data")
+	ErrDuplicateProduct       = errors.New("product with this name already exists")
+	ErrContentRejected        = errors.New("product content rejected by moderation")
+	ErrConfirmationRequired   = errors.New("mass operation requires confirmation")
+	ErrTrashedProductNotFound = errors.New("product not found in trash")
+	ErrInvalidDiffRange       = errors.New("invalid diff range")
+	ErrBulkWriteRejected      = errors.New("bulk write rejected")
+	ErrInvalidProductImport   = errors.New("invalid product import")
+	ErrConflict               = errors.New("product was changed since the given version")
+	ErrUnsupportedFilter      = errors.New("product filter not supported")
+
+	ErrFeedNotFound      = errors.New("feed not found")
+	ErrInvalidFeed       = errors.New("invalid feed data")
+	ErrFeedRunNotFound   = errors.New("feed run not found")
+	ErrFeedRunInProgress = errors.New("feed run already in progress")
+	ErrFeedFetchFailed   = errors.New("failed to fetch feed")
+	ErrFeedShrunk        = errors.New("feed is missing too much of the catalog")
+
+	ErrImportMappingNotFound = errors.New("import mapping not found")
+	ErrInvalidImportMapping  = errors.New("invalid import mapping")
+	ErrImportMappingExists   = errors.New("an import mapping with this name already exists")
+	ErrImportMappingInUse    = errors.New("import mapping is used by a feed")
+
+	ErrConnectorNotFound       = errors.New("connector not found")
+	ErrInvalidConnector        = errors.New("invalid connector data")
+	ErrUnsupportedConnector    = errors.New("unsupported connector kind")
+	ErrConnectorSyncNotFound   = errors.New("connector sync not found")
+	ErrConnectorSyncInProgress = errors.New("connector sync already in progress")
+
+	ErrWebhookNotFound = errors.New("webhook subscription not found")
+	ErrInvalidWebhook  = errors.New("invalid webhook subscription")
+
+	ErrWebhookSecretNotFound = errors.New("webhook signing secret not found")
+
+	ErrDigestSettingsNotFound = errors.New("digest settings not found")
+	ErrInvalidDigestSettings  = errors.New("invalid digest settings")
+
+	ErrPricingPolicyNotFound = errors.New("pricing policy not found")
+	ErrInvalidPricingPolicy  = errors.New("invalid pricing policy")
+	ErrInvalidPriceQuote     = errors.New("invalid price quote")
+
+	ErrBundleNotFound    = errors.New("bundle not found")
+	ErrInvalidBundle     = errors.New("invalid bundle")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrProductInBundle   = errors.New("product is a component of a bundle")
+
+	ErrOrderNotFound = errors.New("order not found")
+	ErrInvalidOrder  = errors.New("invalid order")
+
+	ErrTwoFactorNotEnrolled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnrolled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorRequired        = errors.New("two-factor code required")
+	ErrTwoFactorEnrollment      = errors.New("two-factor enrollment required")
+	ErrInvalidTwoFactorCode     = errors.New("invalid two-factor code")
+	ErrLoginLocked              = errors.New("too many failed login attempts")
+
+	ErrStoreNotFound    = errors.New("store not found")
+	ErrInvalidStore     = errors.New("invalid store data")
+	ErrStoreHasProducts = errors.New("store still has products")
+
+	ErrCategoryNotFound       = errors.New("category not found")
+	ErrInvalidCategory        = errors.New("invalid category data")
+	ErrDuplicateCategory      = errors.New("a category with this name already exists under the parent")
+	ErrParentCategoryNotFound = errors.New("parent category not found")
+	ErrCategoryHasChildren    = errors.New("category still has subcategories")
+	ErrCategoryCycle          = errors.New("category cannot be moved under itself or its subcategories")
+
+	ErrStockSummaryNotFound = errors.New("stock summary not found")
+
+	ErrConfigSettingNotFound = errors.New("configuration setting not found")
+	ErrInvalidConfigSetting  = errors.New("invalid configuration setting")
+	ErrConfigConflict        = errors.New("setting was changed since the given version")
+
+	ErrReconciliationNotFound   = errors.New("reconciliation run not found")
+	ErrInvalidReconciliation    = errors.New("invalid reconciliation")
+	ErrReconciliationInProgress = errors.New("a reconciliation of this source is already in progress")
+
+	ErrImageTooLarge    = errors.New("image exceeds the size limit")
+	ErrUnsupportedImage = errors.New("unsupported image type")
+
+	ErrInvalidAnalyticsEvent = errors.New("invalid analytics event")
+
+	ErrInvalidUsagePeriod = errors.New("invalid usage period")
+
+	ErrSnapshotNotFound  = errors.New("catalog snapshot not found")
+	ErrSnapshotCorrupt   = errors.New("catalog snapshot is corrupt or truncated")
+	ErrRestoreNotFound   = errors.New("catalog restore not found")
+	ErrRestoreInProgress = errors.New("a restore of this store is already in progress")
+
+	ErrUserNotFound         = errors.New("user not found")
+	ErrInvalidUser          = errors.New("invalid user data")
+	ErrEmailTaken           = errors.New("a user with this email already exists")
+	ErrInvalidCredentials   = errors.New("invalid email or password")
+	ErrInvalidRefreshToken  = errors.New("invalid refresh token")
+	ErrInvalidImpersonation = errors.New("invalid impersonation request")
+
+	ErrInvalidPreviewToken = errors.New("invalid or expired preview token")
+	ErrInvalidPreviewTTL   = errors.New("invalid preview token lifetime")
+
+	ErrInsufficientScope        = errors.New("operation requires an elevated scope")
+	ErrInvalidConfirmationToken = errors.New("invalid, expired or used confirmation token")
 )
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+const (
+	ProductEventCreated     = "product.created"
+	ProductEventUpdated     = "product.updated"
+	ProductEventDeleted     = "product.deleted"
+	ProductEventPublished   = "product.published"
+	ProductEventUnpublished = "product.unpublished"
+	StockEventChanged       = "stock.changed"
+)
+
+// EventTypes lists every event type the service publishes.
+var EventTypes = []string{
+	ProductEventCreated, ProductEventUpdated, ProductEventDeleted,
+	ProductEventPublished, ProductEventUnpublished, StockEventChanged,
+}
+
+// ProductEvent is published after a product mutation is committed.
+// SchemaVersion names the registered schema the event conforms to; it is
+// set when the event is published.
+type ProductEvent struct {
+	ID            string            `json:"id"`
+	Type          string            `json:"type"`
+	SchemaVersion int               `json:"schema_version"`
+	StoreID       int64             `json:"store_id"`
+	ProductID     int64             `json:"product_id"`
+	OccurredAt    time.Time         `json:"occurred_at"`
+	Product       *ProductEventData `json:"product,omitempty"`
+	Stock         *StockChange      `json:"stock,omitempty"`
+}
+
+// StockChange is the payload of a stock.changed event. Unit is the unit
+// of both amounts; a change of unit is published with the new unit.
+type StockChange struct {
+	PreviousAmount quantity.Quantity `json:"previous_amount"`
+	Amount         quantity.Quantity `json:"amount"`
+	Unit           string            `json:"unit"`
+}
+
+// EventSchema is one version of the JSON schema for an event type.
+// Current marks the version events of that type are published with.
+type EventSchema struct {
+	Type    string
+	Version int
+	Current bool
+	Schema  json.RawMessage
+}
+
+// ProductEventData is the product as published in events: as written, or
+// as it was before a delete. Subscribers depend on its shape, so it is kept
+// apart from Product.
+type ProductEventData struct {
+	ID                int64             `json:"id"`
+	StoreID           int64             `json:"store_id"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	DescriptionFormat string            `json:"description_format"`
+	Amount            quantity.Quantity `json:"amount"`
+	Unit              string            `json:"unit"`
+	Price             float64           `json:"price"`
+	Status            string            `json:"status"`
+	ModerationStatus  string            `json:"moderation_status"`
+
+	AllowBackorder      bool              `json:"allow_backorder"`
+	BackorderLimit      quantity.Quantity `json:"backorder_limit"`
+	PreorderReleaseDate *time.Time        `json:"preorder_release_date"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewProductEventData(p *Product) *ProductEventData {
+	return &ProductEventData{
+		ID:                p.ID,
+		StoreID:           p.StoreID,
+		Name:              p.Name,
+		Description:       p.Description.String,
+		DescriptionFormat: p.DescriptionFormat,
+		Amount:            p.Amount,
+		Unit:              p.Unit,
+		Price:             p.Price,
+		Status:            p.Status,
+		ModerationStatus:  p.ModerationStatus,
+
+		AllowBackorder:      p.AllowBackorder,
+		BackorderLimit:      p.BackorderLimit,
+		PreorderReleaseDate: p.PreorderReleaseDate,
+
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
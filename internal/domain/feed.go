@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"database/sql"
+	"net/url"
+	"time"
+
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+type FeedFormat string
+
+const (
+	FeedFormatCSV  FeedFormat = "csv"
+	FeedFormatJSON FeedFormat = "json"
+)
+
+const (
+	MinFeedIntervalSeconds = 300
+	MaxFeedIntervalSeconds = 7 * 24 * 60 * 60
+)
+
+type Feed struct {
+	ID              int64      `json:"id" db:"id"`
+	StoreID         int64      `json:"store_id" db:"store_id"`
+	URL             string     `json:"url" db:"url"`
+	Format          FeedFormat `json:"format" db:"format"`
+	IntervalSeconds int64      `json:"interval_seconds" db:"interval_seconds"`
+	Enabled         bool       `json:"enabled" db:"enabled"`
+	// MappingID names the store's import mapping a CSV feed is read
+	// with. Without one the CSV's header must use the feed item fields.
+	MappingID sql.NullInt64 `json:"mapping_id" db:"mapping_id"`
+	// MatchBy lists the rules a row without a product of its own is
+	// matched to an existing product with, in order; OnDuplicate is what
+	// is done with a match when the row does not say.
+	MatchBy     []string     `json:"match_by" db:"match_by"`
+	OnDuplicate string       `json:"on_duplicate" db:"on_duplicate"`
+	LastRunAt   sql.NullTime `json:"last_run_at" db:"last_run_at"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+func (f *Feed) Validate() error {
+	if f.StoreID <= 0 {
+		return invalidField("store_id", "gt", "store_id must be positive")
+	}
+
+	u, err := url.Parse(f.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return invalidField("url", "url", "url must be an absolute http or https URL")
+	}
+
+	if f.Format != FeedFormatCSV && f.Format != FeedFormatJSON {
+		return invalidField("format", "oneof", "format must be csv or json")
+	}
+
+	if f.MappingID.Valid && f.Format != FeedFormatCSV {
+		return invalidField("mapping_id", "requires", "an import mapping only applies to csv feeds")
+	}
+
+	if err := validateMatchBy(f.MatchBy); err != nil {
+		return err
+	}
+
+	if f.OnDuplicate != "" && !ValidDuplicateResolution(f.OnDuplicate) {
+		return invalidField("on_duplicate", "oneof", "on_duplicate must be skip, update or create")
+	}
+
+	if f.IntervalSeconds < MinFeedIntervalSeconds || f.IntervalSeconds > MaxFeedIntervalSeconds {
+		return invalidField("interval_minutes", "range", "interval must be between 5 minutes and 7 days")
+	}
+
+	return nil
+}
+
+// IsDue reports whether the feed should be fetched at the given time.
+func (f *Feed) IsDue(now time.Time) bool {
+	if !f.Enabled {
+		return false
+	}
+	if !f.LastRunAt.Valid {
+		return true
+	}
+	return !now.Before(f.LastRunAt.Time.Add(time.Duration(f.IntervalSeconds) * time.Second))
+}
+
+// FeedItem is a single product row as published by an external feed. An
+// item without a unit is counted in pieces. OnDuplicate overrides the
+// feed's resolution when the item matches an existing product. ImageURLs
+// are downloaded and attached to the product after the run.
+type FeedItem struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Amount      quantity.Quantity `json:"amount"`
+	Unit        string            `json:"unit"`
+	Price       float64           `json:"price"`
+	SKU         string            `json:"sku,omitempty"`
+	Barcode     string            `json:"barcode,omitempty"`
+	OnDuplicate string            `json:"on_duplicate,omitempty"`
+	ImageURLs   []string          `json:"image_urls,omitempty"`
+}
+
+type FeedRunStatus string
+
+const (
+	FeedRunStatusRunning   FeedRunStatus = "running"
+	FeedRunStatusSucceeded FeedRunStatus = "succeeded"
+	FeedRunStatusFailed    FeedRunStatus = "failed"
+)
+
+type FeedRun struct {
+	ID          int64         `json:"id" db:"id"`
+	FeedID      int64         `json:"feed_id" db:"feed_id"`
+	Status      FeedRunStatus `json:"status" db:"status"`
+	Created     int           `json:"created" db:"created"`
+	Updated     int           `json:"updated" db:"updated"`
+	Deactivated int           `json:"deactivated" db:"deactivated"`
+	Unchanged   int           `json:"unchanged" db:"unchanged"`
+	Skipped     int           `json:"skipped" db:"skipped"`
+	Failed      int           `json:"failed" db:"failed"`
+	Errors      []string      `json:"errors" db:"errors"`
+	// Duplicates lists the rows matched to an existing product they were
+	// not linked to.
+	Duplicates []FeedDuplicate `json:"duplicates" db:"duplicates"`
+	// ImagesQueued counts the image URLs left to download in the
+	// background; their outcomes are the run's image imports.
+	ImagesQueued int          `json:"images_queued" db:"images_queued"`
+	StartedAt    time.Time    `json:"started_at" db:"started_at"`
+	FinishedAt   sql.NullTime `json:"finished_at" db:"finished_at"`
+}
@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Rules a feed matches its rows to products already in the catalog with.
+// SKU and barcode match products another row with the same code imported;
+// fuzzy_name ignores case, spacing and punctuation and tolerates small
+// typos, but never matches names whose numbers differ.
+const (
+	DuplicateMatchSKU       = "sku"
+	DuplicateMatchBarcode   = "barcode"
+	DuplicateMatchName      = "name"
+	DuplicateMatchFuzzyName = "fuzzy_name"
+)
+
+// Resolutions of a row that matches an existing product: leave the product
+// as it is, update it from the row, or create a new product anyway.
+const (
+	DuplicateSkip   = "skip"
+	DuplicateUpdate = "update"
+	DuplicateCreate = "create"
+)
+
+// DefaultMatchBy is how feeds matched rows before match rules existed.
+var DefaultMatchBy = []string{DuplicateMatchName}
+
+// MinFuzzySimilarity is how alike two normalized names must be, from 0 to
+// 1, to be a fuzzy match.
+const MinFuzzySimilarity = 0.85
+
+func validateMatchBy(rules []string) error {
+	seen := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		switch rule {
+		case DuplicateMatchSKU, DuplicateMatchBarcode, DuplicateMatchName, DuplicateMatchFuzzyName:
+		default:
+			return invalidFieldf(fmt.Sprintf("match_by[%d]", i), "oneof", "match_by[%d]: must be sku, barcode, name or fuzzy_name", i)
+		}
+		if seen[rule] {
+			return invalidFieldf(fmt.Sprintf("match_by[%d]", i), "unique", "match_by[%d]: %q is listed more than once", i, rule)
+		}
+		seen[rule] = true
+	}
+	return nil
+}
+
+// ValidDuplicateResolution reports whether resolution is skip, update or
+// create.
+func ValidDuplicateResolution(resolution string) bool {
+	switch resolution {
+	case DuplicateSkip, DuplicateUpdate, DuplicateCreate:
+		return true
+	}
+	return false
+}
+
+// FeedProductLink ties a feed row to the product it imported. Key is the
+// row's Key when it was imported.
+type FeedProductLink struct {
+	FeedID    int64  `json:"feed_id" db:"feed_id"`
+	Key       string `json:"item_key" db:"item_key"`
+	ProductID int64  `json:"product_id" db:"product_id"`
+	SKU       string `json:"sku" db:"sku"`
+	Barcode   string `json:"barcode" db:"barcode"`
+}
+
+// FeedDuplicate is a row a run matched to an existing product, and what
+// the run did with it. Item counts rows from 1.
+type FeedDuplicate struct {
+	Item       int    `json:"item"`
+	Name       string `json:"name"`
+	ProductID  int64  `json:"product_id"`
+	MatchedBy  string `json:"matched_by"`
+	Resolution string `json:"resolution"`
+}
+
+// Key identifies the item across runs of its feed: by SKU, else barcode,
+// else name.
+func (i FeedItem) Key() string {
+	switch {
+	case i.SKU != "":
+		return "sku:" + i.SKU
+	case i.Barcode != "":
+		return "barcode:" + i.Barcode
+	default:
+		return "name:" + i.Name
+	}
+}
+
+// NormalizeName reduces a name to its lowercase letters and digits, the
+// form fuzzy name matching compares.
+func NormalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// NameSimilarity rates how alike two normalized names are, from 0 to 1.
+// Names whose digits differ, such as sizes or model numbers, rate 0, as
+// does any pair too far apart to be a fuzzy match.
+func NameSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if digits(a) != digits(b) {
+		return 0
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	maxDistance := int(float64(longest) * (1 - MinFuzzySimilarity))
+	distance, ok := boundedLevenshtein(ra, rb, maxDistance)
+	if !ok {
+		return 0
+	}
+	return 1 - float64(distance)/float64(longest)
+}
+
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// boundedLevenshtein returns the edit distance between a and b, giving up
+// once it exceeds limit.
+func boundedLevenshtein(a, b []rune, limit int) (int, bool) {
+	if diff := len(a) - len(b); diff > limit || -diff > limit {
+		return 0, false
+	}
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if current[j] < rowMin {
+				rowMin = current[j]
+			}
+		}
+		if rowMin > limit {
+			return 0, false
+		}
+		previous, current = current, previous
+	}
+
+	if previous[len(b)] > limit {
+		return 0, false
+	}
+	return previous[len(b)], true
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// IdempotencyKey records a request sent with an Idempotency-Key header, so
+// retries of it get the first response instead of running again. Keys are
+// unique per Owner, the identity of the caller. RequestHash tells a retry
+// from a different request reusing the key. StatusCode is zero while the
+// first request is still being handled.
+type IdempotencyKey struct {
+	Owner       string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	// Headers are the response headers replayed with Body, such as the
+	// Location of a created resource.
+	Headers   map[string]string
+	Body      []byte
+	CreatedAt time.Time
+}
+
+// Completed reports whether the response to the request is recorded.
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
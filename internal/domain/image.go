@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MaxImagesPerItem caps the image URLs imported for one feed row; the rest
+// are reported as failed.
+const MaxImagesPerItem = 10
+
+// imageExtensions are the image types products accept, by the content type
+// sniffed from the image itself.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// ImageExtension returns the file extension of an accepted image type.
+func ImageExtension(contentType string) (string, bool) {
+	extension, ok := imageExtensions[contentType]
+	return extension, ok
+}
+
+// ProductImage is an image attached to a product. The image itself is kept
+// in object storage under ObjectKey, which is derived from ContentHash, so
+// products sharing an image share the object.
+type ProductImage struct {
+	ID          int64     `json:"id" db:"id"`
+	ProductID   int64     `json:"product_id" db:"product_id"`
+	ContentHash string    `json:"content_hash" db:"content_hash"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size_bytes"`
+	ObjectKey   string    `json:"object_key" db:"object_key"`
+	SourceURL   string    `json:"source_url" db:"source_url"`
+	Position    int       `json:"position" db:"position"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// Outcomes of an image URL a feed run imports. A duplicate is an image
+// whose content the product already has.
+const (
+	ImageImportPending   = "pending"
+	ImageImportAttached  = "attached"
+	ImageImportDuplicate = "duplicate"
+	ImageImportFailed    = "failed"
+)
+
+// ImageImport is one image URL of a feed run and what became of it.
+// ImageID is the image the URL was attached as, or the image it
+// duplicates.
+type ImageImport struct {
+	ID         int64         `json:"id" db:"id"`
+	RunID      int64         `json:"run_id" db:"run_id"`
+	ProductID  int64         `json:"product_id" db:"product_id"`
+	URL        string        `json:"url" db:"url"`
+	Status     string        `json:"status" db:"status"`
+	ImageID    sql.NullInt64 `json:"image_id" db:"image_id"`
+	Error      string        `json:"error" db:"error"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+	FinishedAt sql.NullTime  `json:"finished_at" db:"finished_at"`
+}
+
+// DownloadedImage is an image fetched for import, up to the size limit.
+type DownloadedImage struct {
+	Data        []byte
+	ContentType string
+}
+
+// SplitImageURLs reads the image URLs of a CSV cell, separated by "|".
+func SplitImageURLs(value string) []string {
+	var urls []string
+	for _, part := range strings.Split(value, "|") {
+		if part = strings.TrimSpace(part); part != "" {
+			urls = append(urls, part)
+		}
+	}
+	return urls
+}
+
+func ValidateImageURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("image url must be an absolute http or https URL")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AuditEventImpersonationStarted is recorded when an admin is issued a
+// token to act as a user.
+const AuditEventImpersonationStarted = "impersonation.started"
+
+// MaxImpersonationReasonLength caps the reason an admin gives for
+// impersonating a user.
+const MaxImpersonationReasonLength = 500
+
+// ImpersonationRequest is an admin's request to act as a user for support.
+// A zero TTL asks for the longest impersonation allowed.
+type ImpersonationRequest struct {
+	UserID   int64
+	Admin    string
+	ClientIP string
+	Reason   string
+	TTL      time.Duration
+}
+
+func (r ImpersonationRequest) Validate(maxTTL time.Duration) error {
+	if r.UserID <= 0 {
+		return fmt.Errorf("%w: user ID must be positive", ErrInvalidImpersonation)
+	}
+	if r.Admin == "" {
+		return fmt.Errorf("%w: the admin is unknown", ErrInvalidImpersonation)
+	}
+	if strings.TrimSpace(r.Reason) == "" {
+		return fmt.Errorf("%w: a reason is required", ErrInvalidImpersonation)
+	}
+	if len(r.Reason) > MaxImpersonationReasonLength {
+		return fmt.Errorf("%w: reason must be at most %d characters", ErrInvalidImpersonation, MaxImpersonationReasonLength)
+	}
+	if r.TTL < 0 || r.TTL > maxTTL {
+		return fmt.Errorf("%w: lifetime must be at most %s", ErrInvalidImpersonation, maxTTL)
+	}
+	return nil
+}
+
+// Impersonation is an access token that lets an admin act as a user until
+// ExpiresAt. Everything done with it is audited as the user, tagged with
+// ImpersonatedBy.
+type Impersonation struct {
+	User           *User
+	ImpersonatedBy string
+	AccessToken    string
+	ExpiresAt      time.Time
+}
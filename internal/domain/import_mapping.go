@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+// Feed item fields an import mapping can fill.
+const (
+	ImportFieldName        = "name"
+	ImportFieldDescription = "description"
+	ImportFieldAmount      = "amount"
+	ImportFieldUnit        = "unit"
+	ImportFieldPrice       = "price"
+	ImportFieldSKU         = "sku"
+	ImportFieldBarcode     = "barcode"
+	ImportFieldOnDuplicate = "on_duplicate"
+	ImportFieldImageURLs   = "image_urls"
+)
+
+// Transforms applied to a column's value before it is parsed. Cents reads
+// a whole number of minor units, so 1999 is a price of 19.99.
+const (
+	ImportTransformCents     = "cents"
+	ImportTransformLowercase = "lowercase"
+	ImportTransformUppercase = "uppercase"
+)
+
+const MaxImportPreviewRows = 100
+
+// ImportMapping tells the import pipeline how to read a supplier's CSV
+// whose column names do not match the feed item fields.
+type ImportMapping struct {
+	ID        int64           `json:"id" db:"id"`
+	StoreID   int64           `json:"store_id" db:"store_id"`
+	Name      string          `json:"name" db:"name"`
+	Columns   []ColumnMapping `json:"columns" db:"columns"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// ColumnMapping fills Field from the CSV column named Column, matched
+// case-insensitively. Default is used when the column is empty, or for
+// every row when no Column is given.
+type ColumnMapping struct {
+	Field     string `json:"field"`
+	Column    string `json:"column,omitempty"`
+	Transform string `json:"transform,omitempty"`
+	Default   string `json:"default,omitempty"`
+}
+
+func (m *ImportMapping) Validate() error {
+	if m.StoreID <= 0 {
+		return invalidField("store_id", "gt", "store_id must be positive")
+	}
+
+	if strings.TrimSpace(m.Name) == "" {
+		return invalidField("name", "required", "name is required")
+	}
+
+	if len(m.Name) > 100 {
+		return invalidField("name", "max", "name must not exceed 100 characters")
+	}
+
+	mapped := make(map[string]bool, len(m.Columns))
+	for i, column := range m.Columns {
+		switch column.Field {
+		case ImportFieldName, ImportFieldDescription, ImportFieldAmount, ImportFieldUnit, ImportFieldPrice,
+			ImportFieldSKU, ImportFieldBarcode, ImportFieldOnDuplicate, ImportFieldImageURLs:
+		default:
+			return invalidFieldf(fmt.Sprintf("columns[%d].field", i), "oneof", "columns[%d]: field must be name, description, amount, unit, price, sku, barcode, on_duplicate or image_urls", i)
+		}
+		if mapped[column.Field] {
+			return invalidFieldf(fmt.Sprintf("columns[%d].field", i), "unique", "columns[%d]: field %q is mapped more than once", i, column.Field)
+		}
+		mapped[column.Field] = true
+
+		if strings.TrimSpace(column.Column) == "" && column.Default == "" {
+			return invalidFieldf(fmt.Sprintf("columns[%d].column", i), "required", "columns[%d]: a column or a default is required", i)
+		}
+
+		switch column.Transform {
+		case "":
+		case ImportTransformCents:
+			if column.Field != ImportFieldPrice {
+				return invalidFieldf(fmt.Sprintf("columns[%d].transform", i), "oneof", "columns[%d]: the cents transform only applies to price", i)
+			}
+		case ImportTransformLowercase, ImportTransformUppercase:
+			if column.Field == ImportFieldAmount || column.Field == ImportFieldPrice {
+				return invalidFieldf(fmt.Sprintf("columns[%d].transform", i), "oneof", "columns[%d]: the %s transform only applies to text fields", i, column.Transform)
+			}
+		default:
+			return invalidFieldf(fmt.Sprintf("columns[%d].transform", i), "oneof", "columns[%d]: transform must be cents, lowercase or uppercase", i)
+		}
+
+		if column.Default != "" {
+			if err := column.parse(&FeedItem{}, column.Default); err != nil {
+				return invalidFieldf(fmt.Sprintf("columns[%d].default", i), "type", "columns[%d]: invalid default: %s", i, err.Error())
+			}
+		}
+	}
+
+	for _, required := range []string{ImportFieldName, ImportFieldPrice} {
+		if !mapped[required] {
+			return invalidFieldf("columns", "required", "field %q must be mapped", required)
+		}
+	}
+
+	return nil
+}
+
+// Bind resolves the mapping's columns against a CSV header. A column the
+// header lacks is an error unless the mapping has a default for it.
+func (m *ImportMapping) Bind(header []string) (*BoundImportMapping, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	bound := &BoundImportMapping{
+		columns: m.Columns,
+		index:   make([]int, len(m.Columns)),
+	}
+	for i, column := range m.Columns {
+		bound.index[i] = -1
+		if column.Column == "" {
+			continue
+		}
+		position, ok := positions[strings.ToLower(strings.TrimSpace(column.Column))]
+		if !ok && column.Default == "" {
+			return nil, fmt.Errorf("csv header is missing %q column", column.Column)
+		}
+		if ok {
+			bound.index[i] = position
+		}
+	}
+
+	return bound, nil
+}
+
+// BoundImportMapping is a mapping resolved against one CSV header.
+type BoundImportMapping struct {
+	columns []ColumnMapping
+	index   []int
+}
+
+// Item maps one CSV record to a feed item.
+func (b *BoundImportMapping) Item(record []string) (FeedItem, error) {
+	var item FeedItem
+	for i, column := range b.columns {
+		value := ""
+		if position := b.index[i]; position >= 0 && position < len(record) {
+			value = strings.TrimSpace(record[position])
+		}
+		if value == "" {
+			value = column.Default
+		}
+		if err := column.parse(&item, value); err != nil {
+			return FeedItem{}, err
+		}
+	}
+	return item, nil
+}
+
+// parse transforms value and stores it in the item's field.
+func (c ColumnMapping) parse(item *FeedItem, value string) error {
+	switch c.Transform {
+	case ImportTransformLowercase:
+		value = strings.ToLower(value)
+	case ImportTransformUppercase:
+		value = strings.ToUpper(value)
+	}
+
+	switch c.Field {
+	case ImportFieldName:
+		item.Name = value
+	case ImportFieldDescription:
+		item.Description = value
+	case ImportFieldUnit:
+		item.Unit = value
+	case ImportFieldSKU:
+		item.SKU = value
+	case ImportFieldBarcode:
+		item.Barcode = value
+	case ImportFieldOnDuplicate:
+		if value != "" && !ValidDuplicateResolution(value) {
+			return fmt.Errorf("invalid on_duplicate %q", value)
+		}
+		item.OnDuplicate = value
+	case ImportFieldImageURLs:
+		item.ImageURLs = SplitImageURLs(value)
+	case ImportFieldAmount:
+		if value == "" {
+			return nil
+		}
+		amount, err := quantity.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid amount %q", value)
+		}
+		item.Amount = amount
+	case ImportFieldPrice:
+		if c.Transform == ImportTransformCents {
+			cents, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid price in cents %q", value)
+			}
+			item.Price = float64(cents) / 100
+			return nil
+		}
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid price %q", value)
+		}
+		item.Price = price
+	}
+	return nil
+}
+
+// ImportPreviewRow is one parsed CSV row: the item, or why it could not
+// be read. Line is the row's line in the file, the header being line 1.
+type ImportPreviewRow struct {
+	Line  int
+	Item  FeedItem
+	Error string
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "encoding/json"
+
+// InboundMessage is a message received from an external system such as an
+// ERP or a queue. ID is the sender's message ID, unique within Source, and
+// stays the same when the message is redelivered.
+type InboundMessage struct {
+	Source  string
+	ID      string
+	Payload json.RawMessage
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// AccessPattern is the shape of a list query: the columns it filters by
+// equality, an optional range-filtered column and its sort columns.
+type AccessPattern struct {
+	Table  string
+	Equals []string
+	Range  string
+	Sort   []string
+}
+
+func (p AccessPattern) String() string {
+	var b strings.Builder
+	b.WriteString(p.Table)
+
+	var conditions []string
+	for _, column := range p.Equals {
+		conditions = append(conditions, column+" = ?")
+	}
+	if p.Range != "" {
+		conditions = append(conditions, p.Range+" > ?")
+	}
+	if len(conditions) > 0 {
+		b.WriteString(" WHERE " + strings.Join(conditions, " AND "))
+	}
+	if len(p.Sort) > 0 {
+		b.WriteString(" ORDER BY " + strings.Join(p.Sort, ", "))
+	}
+	return b.String()
+}
+
+type AccessPatternUsage struct {
+	Pattern AccessPattern
+	Uses    int64
+}
+
+const (
+	IndexAdviceMissing = "missing_index"
+	IndexAdviceUnused  = "unused_index"
+)
+
+// IndexAdvice suggests creating an index that would serve recorded access
+// patterns, or dropping one that serves none and is never scanned.
+type IndexAdvice struct {
+	Kind    string
+	Table   string
+	Index   string
+	Columns []string
+	// Uses is how often the patterns the advice is based on ran.
+	Uses      int64
+	Patterns  []string
+	Statement string
+}
+
+type IndexAdviceReport struct {
+	GeneratedAt time.Time
+	Patterns    []AccessPatternUsage
+	Advice      []IndexAdvice
+}
@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keys of the live configuration, the settings operators change while the
+// service runs. Each setting is stored and versioned on its own, so changes
+// to different settings never conflict. Stores have a setting each, keyed
+// by StoreSettingsKey.
+const (
+	ConfigFeatureFlags = "feature_flags"
+	ConfigRateLimit    = "rate_limit"
+	ConfigMaintenance  = "maintenance"
+
+	storeSettingsPrefix = "store:"
+)
+
+// AuditEventConfigChanged is recorded for every change to the live
+// configuration.
+const AuditEventConfigChanged = "config.changed"
+
+// GlobalConfigKeys lists the settings that apply to the whole service.
+var GlobalConfigKeys = []string{ConfigFeatureFlags, ConfigRateLimit, ConfigMaintenance}
+
+const (
+	maxFeatureFlags           = 200
+	maxMaintenanceMessageSize = 500
+)
+
+var featureFlagName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// StoreSettingsKey is the key of the settings of the store with id.
+func StoreSettingsKey(storeID int64) string {
+	return storeSettingsPrefix + strconv.FormatInt(storeID, 10)
+}
+
+// ConfigSetting is one setting of the live configuration as stored. Value
+// is the JSON of the setting's type: FeatureFlags, RateLimitSettings,
+// MaintenanceMode or StoreSettings. Version starts at 1 and goes up with
+// every change; a setting that was never changed has version 0 and its
+// default value.
+type ConfigSetting struct {
+	Key       string          `json:"key" db:"key"`
+	Value     json.RawMessage `json:"value" db:"value"`
+	Version   int64           `json:"version" db:"version"`
+	UpdatedBy string          `json:"updated_by" db:"updated_by"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// ConfigChange replaces the value of a setting. Version is the version the
+// change was based on, 0 for a setting that was never changed; the change
+// is refused if the setting has been changed since.
+type ConfigChange struct {
+	Key      string
+	Value    json.RawMessage
+	Version  int64
+	Actor    string
+	ClientIP string
+}
+
+// FeatureFlags turns features on or off by name. Flags that are not
+// listed are off.
+type FeatureFlags map[string]bool
+
+func (f FeatureFlags) Validate() error {
+	if len(f) > maxFeatureFlags {
+		return fmt.Errorf("at most %d feature flags may be set", maxFeatureFlags)
+	}
+	for name := range f {
+		if !featureFlagName.MatchString(name) {
+			return fmt.Errorf("feature flag name %q must be lowercase letters, digits, '_', '.' or '-', at most 64 characters", name)
+		}
+	}
+	return nil
+}
+
+// RateLimitSettings is the request rate allowed per API key or client IP.
+type RateLimitSettings struct {
+	RPS   float64 `json:"rps"`
+	Burst int64   `json:"burst"`
+}
+
+func (s RateLimitSettings) Validate() error {
+	if s.RPS <= 0 {
+		return errors.New("rps must be greater than 0")
+	}
+	if s.Burst < 1 {
+		return errors.New("burst must be at least 1")
+	}
+	return nil
+}
+
+// MaintenanceMode refuses changes through the API while Enabled, telling
+// callers Message.
+type MaintenanceMode struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
+
+func (m MaintenanceMode) Validate() error {
+	if len(m.Message) > maxMaintenanceMessageSize {
+		return fmt.Errorf("message must not exceed %d characters", maxMaintenanceMessageSize)
+	}
+	return nil
+}
+
+// StoreSettings are a store's own settings. Its feature flags override the
+// service's for requests about the store.
+type StoreSettings struct {
+	FeatureFlags FeatureFlags `json:"feature_flags"`
+}
+
+func (s StoreSettings) Validate() error {
+	return s.FeatureFlags.Validate()
+}
+
+// ParseStoreSettingsKey returns the store of a StoreSettingsKey.
+func ParseStoreSettingsKey(key string) (int64, bool) {
+	if !strings.HasPrefix(key, storeSettingsPrefix) {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(key, storeSettingsPrefix), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// DecodeConfigValue decodes and validates the value of the setting key,
+// returning one of the setting types. Unknown fields are refused so a typo
+// does not silently reset a setting.
+func DecodeConfigValue(key string, value []byte) (any, error) {
+	var target interface{ Validate() error }
+	switch {
+	case key == ConfigFeatureFlags:
+		target = &FeatureFlags{}
+	case key == ConfigRateLimit:
+		target = &RateLimitSettings{}
+	case key == ConfigMaintenance:
+		target = &MaintenanceMode{}
+	default:
+		if _, ok := ParseStoreSettingsKey(key); !ok {
+			return nil, fmt.Errorf("unknown setting %q", key)
+		}
+		target = &StoreSettings{}
+	}
+
+	if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+		return nil, fmt.Errorf("%s value must not be null", key)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return nil, fmt.Errorf("invalid %s value: %s", key, err.Error())
+	}
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch v := target.(type) {
+	case *FeatureFlags:
+		return *v, nil
+	case *RateLimitSettings:
+		return *v, nil
+	case *MaintenanceMode:
+		return *v, nil
+	default:
+		return *target.(*StoreSettings), nil
+	}
+}
+
+// LiveConfig is the live configuration an instance applies, decoded from
+// the settings it last loaded.
+type LiveConfig struct {
+	FeatureFlags FeatureFlags
+	// RateLimit is nil until operators change it; the configured limit
+	// applies until then.
+	RateLimit   *RateLimitSettings
+	Maintenance MaintenanceMode
+	Stores      map[int64]StoreSettings
+}
+
+// FeatureEnabled reports whether the feature is on for requests about
+// storeID, or for the service as a whole when storeID is 0.
+func (c *LiveConfig) FeatureEnabled(name string, storeID int64) bool {
+	if settings, ok := c.Stores[storeID]; ok {
+		if enabled, ok := settings.FeatureFlags[name]; ok {
+			return enabled
+		}
+	}
+	return c.FeatureFlags[name]
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Login lockout subjects.
+const (
+	LockoutSubjectIdentity = "identity"
+	LockoutSubjectIP       = "ip"
+)
+
+// Lockout describes a subject temporarily barred from logging in after
+// too many failed attempts.
+type Lockout struct {
+	Subject     string    `json:"subject"`
+	Key         string    `json:"key"`
+	Failures    int64     `json:"failures"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// LoginLockedError is returned while an identity or client IP is locked
+// out. It matches ErrLoginLocked with errors.Is.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLoginLocked, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginLockedError) Unwrap() error {
+	return ErrLoginLocked
+}
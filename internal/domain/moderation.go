@@ -0,0 +1,20 @@
+package domain
+
+const (
+	ModerationStatusPending  = "pending"
+	ModerationStatusApproved = "approved"
+	ModerationStatusRejected = "rejected"
+)
+
+type ModerationResult struct {
+	Status string
+	Reason string
+}
+
+func IsValidModerationStatus(status string) bool {
+	switch status {
+	case ModerationStatusPending, ModerationStatusApproved, ModerationStatusRejected:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+const (
+	MutationCreate = "create"
+	MutationUpdate = "update"
+	MutationDelete = "delete"
+)
+
+// MutationAnomaly describes a store whose mutation rate jumped well above
+// its recent baseline, e.g. a runaway integration deleting its catalog.
+type MutationAnomaly struct {
+	StoreID    int64     `json:"store_id"`
+	Kind       string    `json:"kind"`
+	Count      int64     `json:"count"`
+	Baseline   float64   `json:"baseline"`
+	Window     string    `json:"window"`
+	DetectedAt time.Time `json:"detected_at"`
+}
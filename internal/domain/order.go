@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+const OrderStatusPlaced = "placed"
+
+// MaxOrderItems caps how many products one order is for.
+const MaxOrderItems = 100
+
+// OrderItem is a product an order is for and how much of it, in the
+// product's unit. Name, Unit and UnitPrice are the product's when the order
+// was placed, so later edits to the product do not change the order.
+type OrderItem struct {
+	OrderID   int64             `json:"order_id" db:"order_id"`
+	ProductID int64             `json:"product_id" db:"product_id"`
+	Name      string            `json:"name" db:"name"`
+	Quantity  quantity.Quantity `json:"quantity" db:"quantity"`
+	Unit      string            `json:"unit" db:"unit"`
+	UnitPrice float64           `json:"unit_price" db:"unit_price"`
+}
+
+// Total is the item's price, rounded to the cent.
+func (i OrderItem) Total() float64 {
+	return math.Round(i.UnitPrice*i.Quantity.Float64()*100) / 100
+}
+
+// Order is a sale of one store's products. Placing it takes its items off
+// stock.
+type Order struct {
+	ID        int64       `json:"id" db:"id"`
+	StoreID   int64       `json:"store_id" db:"store_id"`
+	Status    string      `json:"status" db:"status"`
+	Items     []OrderItem `json:"items"`
+	Total     float64     `json:"total" db:"total"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+}
+
+func (o *Order) Validate() error {
+	if o.StoreID <= 0 {
+		return invalidField("store_id", "gt", "store_id must be positive")
+	}
+
+	if len(o.Items) == 0 {
+		return invalidField("items", "min", "an order needs at least one item")
+	}
+
+	if len(o.Items) > MaxOrderItems {
+		return invalidFieldf("items", "max", "an order has at most %d items", MaxOrderItems)
+	}
+
+	seen := make(map[int64]bool, len(o.Items))
+	for i, item := range o.Items {
+		if item.ProductID <= 0 {
+			return invalidField(fmt.Sprintf("items[%d].product_id", i), "gt", "product_id must be positive")
+		}
+		if seen[item.ProductID] {
+			return invalidFieldf(fmt.Sprintf("items[%d].product_id", i), "unique", "product %d is listed twice", item.ProductID)
+		}
+		seen[item.ProductID] = true
+
+		if item.Quantity.Sign() <= 0 {
+			return invalidFieldf(fmt.Sprintf("items[%d].quantity", i), "gt", "quantity of product %d must be positive", item.ProductID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// PreviewToken lets anyone holding it view the product until ExpiresAt,
+// even while it is a draft.
+type PreviewToken struct {
+	ProductID int64
+	StoreID   int64
+	Token     string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// PricingPolicy is how a store rounds and shows prices in one currency.
+// Step and Ending are in minor units: with two decimals, a Step of 5
+// rounds to multiples of 0.05 and an Ending of 99 to prices ending in .99.
+type PricingPolicy struct {
+	StoreID   int64     `json:"store_id" db:"store_id"`
+	Currency  string    `json:"currency" db:"currency"`
+	Rounding  string    `json:"rounding" db:"rounding"`
+	Step      int64     `json:"step" db:"step"`
+	Ending    int64     `json:"ending" db:"ending"`
+	Direction string    `json:"direction" db:"direction"`
+	Decimals  int       `json:"decimals" db:"decimals"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// PriceAdjustment describes a price to compute from a product's base
+// price: converted at Rate into Currency, then discounted.
+type PriceAdjustment struct {
+	Currency        string
+	Rate            float64
+	DiscountPercent float64
+}
+
+// PriceQuote is a computed price after the store's rounding. Unrounded is
+// the price before rounding, in minor units.
+type PriceQuote struct {
+	ProductID  int64
+	StoreID    int64
+	BasePrice  float64
+	Adjustment PriceAdjustment
+	Policy     PricingPolicy
+	Unrounded  int64
+	Price      int64
+}
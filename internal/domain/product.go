@@ -2,44 +2,184 @@ package domain
 
 import (
 	"database/sql"
-	"errors"
 	"time"
+
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+// A draft product is kept out of listings and can only be viewed by its
+// store's owners or with a preview token.
+const (
+	ProductStatusActive   = "active"
+	ProductStatusInactive = "inactive"
+	ProductStatusDraft    = "draft"
+)
+
+const (
+	DescriptionFormatPlain    = "plain"
+	DescriptionFormatMarkdown = "markdown"
+	DescriptionFormatHTML     = "html"
+)
+
+// Units a product's amount is counted in. Pieces are whole; weights and
+// volumes go down to the gram and the milliliter.
+const (
+	UnitPiece    = "piece"
+	UnitKilogram = "kg"
+	UnitLiter    = "liter"
+)
+
+var unitDecimals = map[string]int{
+	UnitPiece:    0,
+	UnitKilogram: 3,
+	UnitLiter:    3,
+}
+
+// UnitDecimals returns how many decimals an amount in unit may have. An
+// empty unit is a piece.
+func UnitDecimals(unit string) (int, bool) {
+	if unit == "" {
+		unit = UnitPiece
+	}
+	decimals, ok := unitDecimals[unit]
+	return decimals, ok
+}
+
+// Availability statuses, derived from a product's status, stock, low stock
+// threshold and backorder settings.
+const (
+	AvailabilityInStock      = "in_stock"
+	AvailabilityLowStock     = "low_stock"
+	AvailabilityBackorder    = "backorder"
+	AvailabilityPreorder     = "preorder"
+	AvailabilityOutOfStock   = "out_of_stock"
+	AvailabilityDiscontinued = "discontinued"
 )
 
+func IsValidAvailability(availability string) bool {
+	switch availability {
+	case AvailabilityInStock, AvailabilityLowStock, AvailabilityBackorder,
+		AvailabilityPreorder, AvailabilityOutOfStock, AvailabilityDiscontinued:
+		return true
+	}
+	return false
+}
+
 type Product struct {
-	ID          int64          `json:"id" db:"id"`
-	StoreID     int64          `json:"store_id" db:"store_id"`
-	Name        string         `json:"name" db:"name"`
-	Description sql.NullString `json:"description" db:"description"`
-	Amount      int64          `json:"amount" db:"amount"`
-	Price       float64        `json:"price" db:"price"`
-	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+	ID                int64             `json:"id" db:"id"`
+	StoreID           int64             `json:"store_id" db:"store_id"`
+	Name              string            `json:"name" db:"name"`
+	Description       sql.NullString    `json:"description" db:"description"`
+	DescriptionFormat string            `json:"description_format" db:"description_format"`
+	Amount            quantity.Quantity `json:"amount" db:"amount"`
+	Unit              string            `json:"unit" db:"unit"`
+	Price             float64           `json:"price" db:"price"`
+	Status            string            `json:"status" db:"status"`
+	ModerationStatus  string            `json:"moderation_status" db:"moderation_status"`
+	ModerationReason  sql.NullString    `json:"moderation_reason" db:"moderation_reason"`
+	// LowStockThreshold is the amount at or below which a product in
+	// stock is reported as low_stock. Zero turns low_stock off.
+	LowStockThreshold quantity.Quantity `json:"low_stock_threshold" db:"low_stock_threshold"`
+	// AllowBackorder lets the product be sold past zero stock, down to
+	// -BackorderLimit.
+	AllowBackorder bool              `json:"allow_backorder" db:"allow_backorder"`
+	BackorderLimit quantity.Quantity `json:"backorder_limit" db:"backorder_limit"`
+	// PreorderReleaseDate marks a product sold ahead of its release, as a
+	// backorder.
+	PreorderReleaseDate *time.Time `json:"preorder_release_date" db:"preorder_release_date"`
+	// PublishAt and UnpublishAt bound the window in which the product is
+	// listed. Either may be nil to leave that end open.
+	PublishAt   *time.Time `json:"publish_at" db:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at" db:"unpublish_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	// Version goes up with every write to the product. An update that
+	// names the version it was made against fails with ErrConflict once
+	// the product has moved past it; zero skips the check.
+	Version int64 `json:"version" db:"version"`
 }
 
 func (p *Product) Validate() error {
 	if p.StoreID <= 0 {
-		return errors.New("store_id must be positive")
+		return invalidField("store_id", "gt", "store_id must be positive")
 	}
 
 	if p.Name == "" {
-		return errors.New("name is required")
+		return invalidField("name", "required", "name is required")
 	}
 
 	if len(p.Name) > 100 {
-		return errors.New("name must not exceed 100 characters")
+		return invalidField("name", "max", "name must not exceed 100 characters")
 	}
 
 	if p.Description.Valid && len(p.Description.String) > 1000 {
-		return errors.New("description must not exceed 1000 characters")
+		return invalidField("description", "max", "description must not exceed 1000 characters")
+	}
+
+	switch p.DescriptionFormat {
+	case "", DescriptionFormatPlain, DescriptionFormatMarkdown, DescriptionFormatHTML:
+	default:
+		return invalidField("description_format", "oneof", "description_format must be plain, markdown or html")
+	}
+
+	decimals, ok := UnitDecimals(p.Unit)
+	if !ok {
+		return invalidField("unit", "oneof", "unit must be piece, kg or liter")
+	}
+
+	if p.BackorderLimit.Sign() < 0 {
+		return invalidField("backorder_limit", "gte", "backorder_limit must be non-negative")
+	}
+
+	if p.BackorderLimit.Sign() > 0 && !p.AllowBackorder {
+		return invalidField("backorder_limit", "requires", "backorder_limit requires allow_backorder")
+	}
+
+	if p.PreorderReleaseDate != nil && !p.AllowBackorder {
+		return invalidField("preorder_release_date", "requires", "preorder_release_date requires allow_backorder")
 	}
 
-	if p.Amount < 0 {
-		return errors.New("amount must be non-negative")
+	if p.PublishAt != nil && p.UnpublishAt != nil && !p.UnpublishAt.After(*p.PublishAt) {
+		return invalidField("unpublish_at", "gtfield", "unpublish_at must be after publish_at")
+	}
+
+	if p.Amount.Sign() < 0 && (!p.AllowBackorder || p.Sellable().Sign() < 0) {
+		return invalidField("amount", "gte", "amount must be non-negative, or at least -backorder_limit when backorders are allowed")
+	}
+
+	if p.Amount.Decimals() > decimals {
+		if decimals == 0 {
+			return invalidField("amount", "decimals", "amount must be a whole number of pieces")
+		}
+		return invalidFieldf("amount", "decimals", "amount must have at most %d decimals for unit %s", decimals, p.Unit)
+	}
+
+	if p.LowStockThreshold.Sign() < 0 {
+		return invalidField("low_stock_threshold", "gte", "low_stock_threshold must be non-negative")
+	}
+
+	if p.LowStockThreshold.Decimals() > decimals {
+		if decimals == 0 {
+			return invalidField("low_stock_threshold", "decimals", "low_stock_threshold must be a whole number of pieces")
+		}
+		return invalidFieldf("low_stock_threshold", "decimals", "low_stock_threshold must have at most %d decimals for unit %s", decimals, p.Unit)
+	}
+
+	if p.BackorderLimit.Decimals() > decimals {
+		if decimals == 0 {
+			return invalidField("backorder_limit", "decimals", "backorder_limit must be a whole number of pieces")
+		}
+		return invalidFieldf("backorder_limit", "decimals", "backorder_limit must have at most %d decimals for unit %s", decimals, p.Unit)
 	}
 
 	if !p.IsValidPrice() {
-		return errors.New("price must be positive")
+		return invalidField("price", "gt", "price must be positive")
+	}
+
+	switch p.Status {
+	case "", ProductStatusActive, ProductStatusInactive, ProductStatusDraft:
+	default:
+		return invalidField("status", "oneof", "status must be active, inactive or draft")
 	}
 
 	return nil
@@ -48,3 +188,65 @@ func (p *Product) Validate() error {
 func (p *Product) IsValidPrice() bool {
 	return p.Price > 0
 }
+
+// Sellable returns how much of the product can still be sold: its stock,
+// plus the backorder limit when backorders are allowed.
+func (p *Product) Sellable() quantity.Quantity {
+	if !p.AllowBackorder {
+		return p.Amount
+	}
+	sellable, err := p.Amount.Add(p.BackorderLimit)
+	if err != nil {
+		return p.Amount
+	}
+	return sellable
+}
+
+// StockAvailability reports the product's availability from its status and
+// stock alone, without pre-orders. It is discontinued when inactive, in
+// stock above the low stock threshold, low on stock down to zero, then
+// backordered until the backorder limit is reached. The products table
+// keeps it in its stock_availability column.
+func (p *Product) StockAvailability() string {
+	switch {
+	case p.Status == ProductStatusInactive:
+		return AvailabilityDiscontinued
+	case p.Amount.Cmp(p.LowStockThreshold) > 0:
+		return AvailabilityInStock
+	case p.Amount.Sign() > 0:
+		return AvailabilityLowStock
+	case p.Sellable().Sign() > 0:
+		return AvailabilityBackorder
+	default:
+		return AvailabilityOutOfStock
+	}
+}
+
+// Availability reports the product's availability at now: a product that
+// can still be sold is a pre-order until its release date, and otherwise
+// has its StockAvailability.
+func (p *Product) Availability(now time.Time) string {
+	availability := p.StockAvailability()
+	switch availability {
+	case AvailabilityInStock, AvailabilityLowStock, AvailabilityBackorder:
+		if p.PreorderReleaseDate != nil && now.Before(*p.PreorderReleaseDate) {
+			return AvailabilityPreorder
+		}
+	}
+	return availability
+}
+
+// IsPublished reports whether the product is listed at now: from its
+// PublishAt, included, until its UnpublishAt, excluded.
+func (p *Product) IsPublished(now time.Time) bool {
+	if p.PublishAt != nil && now.Before(*p.PublishAt) {
+		return false
+	}
+	return p.UnpublishAt == nil || now.Before(*p.UnpublishAt)
+}
+
+// IsListed reports whether listings include the product at now: it is
+// published and not a draft.
+func (p *Product) IsListed(now time.Time) bool {
+	return p.Status != ProductStatusDraft && p.IsPublished(now)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "fmt"
+
+// MaxBulkProducts caps how many products one bulk write may carry.
+const MaxBulkProducts = 500
+
+// BulkProductItem is one product of a bulk write. It replaces the product
+// with ID, like a PUT, or creates a new one when ID is 0.
+type BulkProductItem struct {
+	ID      int64
+	Product *Product
+}
+
+// BulkProductResult is the product one item of a bulk write saved.
+type BulkProductResult struct {
+	Product *Product
+	Created bool
+}
+
+// BulkItemError is why one item of a bulk write was refused. Index is the
+// item's position in the write.
+type BulkItemError struct {
+	Index int
+	Err   error
+}
+
+func (e BulkItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e BulkItemError) Unwrap() error {
+	return e.Err
+}
+
+// BulkWriteError refuses a bulk write because of the items it lists, in
+// order. Nothing of the write is saved. It matches ErrBulkWriteRejected
+// with errors.Is.
+type BulkWriteError struct {
+	Items []BulkItemError
+}
+
+func (e *BulkWriteError) Error() string {
+	return fmt.Sprintf("%s: %d of the items were refused, first %v", ErrBulkWriteRejected, len(e.Items), e.Items[0])
+}
+
+func (e *BulkWriteError) Unwrap() error {
+	return ErrBulkWriteRejected
+}
@@ -0,0 +1,210 @@
+package domain
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+// ProductCSVColumns are the columns of a product export, in order. An
+// import reads the same columns, so an exported file can be edited and
+// imported back.
+var ProductCSVColumns = []string{
+	"id",
+	"store_id",
+	"name",
+	"description",
+	"description_format",
+	"amount",
+	"unit",
+	"price",
+	"status",
+	"low_stock_threshold",
+	"allow_backorder",
+	"backorder_limit",
+	"preorder_release_date",
+	"publish_at",
+	"unpublish_at",
+}
+
+// requiredProductCSVColumns must be in an import's header.
+var requiredProductCSVColumns = []string{"store_id", "name", "amount", "price"}
+
+// CSVRecord formats the product as a row of ProductCSVColumns. Times are
+// RFC 3339 in UTC.
+func (p *Product) CSVRecord() []string {
+	return []string{
+		strconv.FormatInt(p.ID, 10),
+		strconv.FormatInt(p.StoreID, 10),
+		p.Name,
+		p.Description.String,
+		p.DescriptionFormat,
+		p.Amount.String(),
+		p.Unit,
+		strconv.FormatFloat(p.Price, 'f', -1, 64),
+		p.Status,
+		p.LowStockThreshold.String(),
+		strconv.FormatBool(p.AllowBackorder),
+		p.BackorderLimit.String(),
+		formatCSVTime(p.PreorderReleaseDate),
+		formatCSVTime(p.PublishAt),
+		formatCSVTime(p.UnpublishAt),
+	}
+}
+
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// ProductCSVHeader is an import's header, resolved against
+// ProductCSVColumns. Columns are matched case-insensitively, in any
+// order; columns it does not know are ignored.
+type ProductCSVHeader struct {
+	index map[string]int
+}
+
+// ParseProductCSVHeader fails when the header lacks one of the columns a
+// product needs.
+func ParseProductCSVHeader(header []string) (*ProductCSVHeader, error) {
+	h := &ProductCSVHeader{index: make(map[string]int, len(header))}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if i == 0 {
+			// Spreadsheets often save a UTF-8 byte order mark.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		if _, ok := h.index[name]; ok {
+			if !isProductCSVColumn(name) {
+				continue
+			}
+			return nil, fmt.Errorf("csv header has %q more than once", name)
+		}
+		h.index[name] = i
+	}
+
+	for _, column := range requiredProductCSVColumns {
+		if _, ok := h.index[column]; !ok {
+			return nil, fmt.Errorf("csv header is missing %q column", column)
+		}
+	}
+	return h, nil
+}
+
+func isProductCSVColumn(name string) bool {
+	for _, column := range ProductCSVColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Product reads a row into the ID of the product it replaces, 0 for a new
+// product, and the product. Missing and empty cells leave the field at
+// its zero value, as an omitted field of a PUT does.
+func (h *ProductCSVHeader) Product(record []string) (int64, *Product, error) {
+	cell := func(column string) string {
+		if i, ok := h.index[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var id int64
+	if value := cell("id"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			return 0, nil, fmt.Errorf("invalid id %q", value)
+		}
+		id = parsed
+	}
+
+	product := &Product{
+		Name:              cell("name"),
+		DescriptionFormat: cell("description_format"),
+		Unit:              cell("unit"),
+		Status:            cell("status"),
+	}
+	if description := cell("description"); description != "" {
+		product.Description = sql.NullString{String: description, Valid: true}
+	}
+
+	storeID, err := strconv.ParseInt(cell("store_id"), 10, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid store_id %q", cell("store_id"))
+	}
+	product.StoreID = storeID
+
+	if product.Price, err = strconv.ParseFloat(cell("price"), 64); err != nil {
+		return 0, nil, fmt.Errorf("invalid price %q", cell("price"))
+	}
+
+	if value := cell("allow_backorder"); value != "" {
+		if product.AllowBackorder, err = strconv.ParseBool(value); err != nil {
+			return 0, nil, fmt.Errorf("invalid allow_backorder %q", value)
+		}
+	}
+
+	quantities := []struct {
+		column   string
+		into     *quantity.Quantity
+		required bool
+	}{
+		{"amount", &product.Amount, true},
+		{"low_stock_threshold", &product.LowStockThreshold, false},
+		{"backorder_limit", &product.BackorderLimit, false},
+	}
+	for _, q := range quantities {
+		value := cell(q.column)
+		if value == "" && !q.required {
+			continue
+		}
+		if *q.into, err = quantity.Parse(value); err != nil {
+			return 0, nil, fmt.Errorf("invalid %s %q", q.column, value)
+		}
+	}
+
+	times := []struct {
+		column string
+		into   **time.Time
+	}{
+		{"preorder_release_date", &product.PreorderReleaseDate},
+		{"publish_at", &product.PublishAt},
+		{"unpublish_at", &product.UnpublishAt},
+	}
+	for _, t := range times {
+		value := cell(t.column)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid %s %q, want RFC 3339", t.column, value)
+		}
+		*t.into = &parsed
+	}
+
+	return id, product, nil
+}
+
+// ProductImportReport tells how an import went. Line numbers count the
+// header as line 1.
+type ProductImportReport struct {
+	Created int
+	Updated int
+	Failed  int
+	Errors  []ProductImportError
+}
+
+// ProductImportError is why one row of an import was not saved.
+type ProductImportError struct {
+	Line  int
+	Error string
+}
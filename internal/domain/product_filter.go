@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"strings"
+)
+
+// Orders a product listing can be sorted in.
+const (
+	ProductSortNewest     = "newest"
+	ProductSortPopularity = "popularity"
+)
+
+// ProductFilter narrows a product listing. The zero filter matches every
+// product; nil fields are not filtered on.
+type ProductFilter struct {
+	// Name matches products whose name contains it, ignoring case.
+	Name    string
+	StoreID *int64
+	// CategoryID matches products in the category or any of its
+	// subcategories.
+	CategoryID *int64
+	MinPrice   *float64
+	MaxPrice   *float64
+	// InStock matches products with stock on hand, or without any when
+	// false. Backorders do not count as stock.
+	InStock *bool
+	// Sort orders the listing: newest first, the default, or by
+	// popularity score, highest first.
+	Sort string
+}
+
+// IsEmpty reports whether the filter matches every product, newest first.
+func (f *ProductFilter) IsEmpty() bool {
+	unsorted := *f
+	if unsorted.Sort == ProductSortNewest {
+		unsorted.Sort = ""
+	}
+	return unsorted == ProductFilter{}
+}
+
+func (f *ProductFilter) Validate() error {
+	if f.StoreID != nil && *f.StoreID <= 0 {
+		return invalidField("store_id", "gt", "store_id must be positive")
+	}
+	if f.CategoryID != nil && *f.CategoryID <= 0 {
+		return invalidField("category_id", "gt", "category_id must be positive")
+	}
+	if f.MinPrice != nil && *f.MinPrice < 0 {
+		return invalidField("min_price", "gte", "min_price must not be negative")
+	}
+	if f.MaxPrice != nil && *f.MaxPrice < 0 {
+		return invalidField("max_price", "gte", "max_price must not be negative")
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return invalidField("min_price", "ltefield", "min_price must not be greater than max_price")
+	}
+	switch f.Sort {
+	case "", ProductSortNewest, ProductSortPopularity:
+	default:
+		return invalidField("sort", "oneof", "sort must be newest or popularity")
+	}
+	return nil
+}
+
+// Matches reports whether the product passes the filter. A product does
+// not know its categories, so CategoryID is not checked; repositories that
+// filter with Matches refuse it with ErrUnsupportedFilter instead.
+func (f *ProductFilter) Matches(product *Product) bool {
+	if f.Name != "" && !strings.Contains(strings.ToLower(product.Name), strings.ToLower(f.Name)) {
+		return false
+	}
+	if f.StoreID != nil && product.StoreID != *f.StoreID {
+		return false
+	}
+	if f.MinPrice != nil && product.Price < *f.MinPrice {
+		return false
+	}
+	if f.MaxPrice != nil && product.Price > *f.MaxPrice {
+		return false
+	}
+	if f.InStock != nil && (product.Amount.Sign() > 0) != *f.InStock {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+// ProductPatch is a partial update: nil fields are left as they are.
+// Description, PreorderReleaseDate, PublishAt and UnpublishAt are cleared
+// with an invalid value.
+// Moderation is not set by clients; the product use case fills it in when
+// the patch changes content that is screened. Version, when not zero, is
+// the version of the product the patch was made against, as in an update.
+type ProductPatch struct {
+	StoreID             *int64
+	Name                *string
+	Description         *sql.NullString
+	DescriptionFormat   *string
+	Amount              *quantity.Quantity
+	Unit                *string
+	Price               *float64
+	Status              *string
+	AllowBackorder      *bool
+	BackorderLimit      *quantity.Quantity
+	PreorderReleaseDate *sql.NullTime
+	LowStockThreshold   *quantity.Quantity
+	PublishAt           *sql.NullTime
+	UnpublishAt         *sql.NullTime
+	Moderation          *ModerationResult
+	Version             int64
+}
+
+// IsEmpty reports whether the patch changes nothing.
+func (p *ProductPatch) IsEmpty() bool {
+	return *p == ProductPatch{Version: p.Version}
+}
+
+// ChangesContent reports whether the patch touches the fields moderation
+// screens.
+func (p *ProductPatch) ChangesContent() bool {
+	return p.Name != nil || p.Description != nil || p.DescriptionFormat != nil
+}
+
+// Apply writes the patched fields into product.
+func (p *ProductPatch) Apply(product *Product) {
+	if p.StoreID != nil {
+		product.StoreID = *p.StoreID
+	}
+	if p.Name != nil {
+		product.Name = *p.Name
+	}
+	if p.Description != nil {
+		product.Description = *p.Description
+	}
+	if p.DescriptionFormat != nil {
+		product.DescriptionFormat = *p.DescriptionFormat
+	}
+	if p.Amount != nil {
+		product.Amount = *p.Amount
+	}
+	if p.Unit != nil {
+		product.Unit = *p.Unit
+	}
+	if p.Price != nil {
+		product.Price = *p.Price
+	}
+	if p.Status != nil {
+		product.Status = *p.Status
+	}
+	if p.AllowBackorder != nil {
+		product.AllowBackorder = *p.AllowBackorder
+	}
+	if p.BackorderLimit != nil {
+		product.BackorderLimit = *p.BackorderLimit
+	}
+	if p.PreorderReleaseDate != nil {
+		product.PreorderReleaseDate = nullTimePtr(*p.PreorderReleaseDate)
+	}
+	if p.LowStockThreshold != nil {
+		product.LowStockThreshold = *p.LowStockThreshold
+	}
+	if p.PublishAt != nil {
+		product.PublishAt = nullTimePtr(*p.PublishAt)
+	}
+	if p.UnpublishAt != nil {
+		product.UnpublishAt = nullTimePtr(*p.UnpublishAt)
+	}
+	if p.Moderation != nil {
+		product.ModerationStatus = p.Moderation.Status
+		product.ModerationReason = sql.NullString{String: p.Moderation.Reason, Valid: p.Moderation.Reason != ""}
+	}
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
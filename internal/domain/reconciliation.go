@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+// Reconciliation sources: a registered feed, or a connector whose full
+// catalog is pulled.
+const (
+	ReconciliationSourceFeed      = "feed"
+	ReconciliationSourceConnector = "connector"
+)
+
+// Conflict-resolution policies decide which discrepancies a run heals.
+const (
+	// ReconciliationPolicyReport only reports discrepancies.
+	ReconciliationPolicyReport = "report"
+	// ReconciliationPolicySourceWins heals every discrepancy from the source.
+	ReconciliationPolicySourceWins = "source_wins"
+	// ReconciliationPolicyNewestWins heals a product only when the source
+	// changed it after the local copy was last written. Feed items carry no
+	// time, so they count as changed when the feed is fetched.
+	ReconciliationPolicyNewestWins = "newest_wins"
+)
+
+// Discrepancy kinds. Products missing from the source are reported but
+// never healed; deactivating them is left to feed runs.
+const (
+	DiscrepancyMissingLocally  = "missing_locally"
+	DiscrepancyMissingInSource = "missing_in_source"
+	DiscrepancyFieldMismatch   = "field_mismatch"
+)
+
+func IsReconciliationPolicy(policy string) bool {
+	switch policy {
+	case ReconciliationPolicyReport, ReconciliationPolicySourceWins, ReconciliationPolicyNewestWins:
+		return true
+	}
+	return false
+}
+
+// ReferenceProduct is a product as the source of truth has it. ProductID
+// is the local product it is linked to, or 0 to match it by name.
+type ReferenceProduct struct {
+	ProductID   int64
+	Name        string
+	Description string
+	Amount      quantity.Quantity
+	// Unit is empty when the source does not report one.
+	Unit      string
+	Price     float64
+	UpdatedAt time.Time
+}
+
+// ReferenceSnapshot is a source's full catalog for one store. Scope lists
+// the local products the source accounts for; nil means the whole store.
+type ReferenceSnapshot struct {
+	StoreID  int64
+	TakenAt  time.Time
+	Products []ReferenceProduct
+	Scope    map[int64]bool
+}
+
+type ReconciliationRunStatus string
+
+const (
+	ReconciliationRunStatusRunning   ReconciliationRunStatus = "running"
+	ReconciliationRunStatusSucceeded ReconciliationRunStatus = "succeeded"
+	ReconciliationRunStatusFailed    ReconciliationRunStatus = "failed"
+)
+
+// ReconciliationRun compares a store's catalog with a source's snapshot.
+// Discrepancies counts every discrepancy found, also those past the ones
+// kept for the run.
+type ReconciliationRun struct {
+	ID            int64                   `json:"id" db:"id"`
+	StoreID       int64                   `json:"store_id" db:"store_id"`
+	Source        string                  `json:"source" db:"source"`
+	SourceID      int64                   `json:"source_id" db:"source_id"`
+	Policy        string                  `json:"policy" db:"policy"`
+	Status        ReconciliationRunStatus `json:"status" db:"status"`
+	Checked       int                     `json:"checked" db:"checked"`
+	Discrepancies int                     `json:"discrepancies" db:"discrepancies"`
+	Healed        int                     `json:"healed" db:"healed"`
+	Failed        int                     `json:"failed" db:"failed"`
+	Error         sql.NullString          `json:"error" db:"error"`
+	StartedAt     time.Time               `json:"started_at" db:"started_at"`
+	FinishedAt    sql.NullTime            `json:"finished_at" db:"finished_at"`
+}
+
+func (r *ReconciliationRun) Validate() error {
+	if r.Source != ReconciliationSourceFeed && r.Source != ReconciliationSourceConnector {
+		return invalidField("source", "oneof", "source must be feed or connector")
+	}
+
+	if r.SourceID <= 0 {
+		return invalidField("source_id", "gt", "source_id must be positive")
+	}
+
+	if !IsReconciliationPolicy(r.Policy) {
+		return invalidField("policy", "oneof", "policy must be report, source_wins or newest_wins")
+	}
+
+	return nil
+}
+
+// Discrepancy is one difference between the local catalog and the source.
+// Field, LocalValue and SourceValue are set for field mismatches.
+type Discrepancy struct {
+	ID          int64         `json:"id" db:"id"`
+	RunID       int64         `json:"run_id" db:"run_id"`
+	ProductID   sql.NullInt64 `json:"product_id" db:"product_id"`
+	Name        string        `json:"name" db:"name"`
+	Kind        string        `json:"kind" db:"kind"`
+	Field       string        `json:"field" db:"field"`
+	LocalValue  string        `json:"local_value" db:"local_value"`
+	SourceValue string        `json:"source_value" db:"source_value"`
+	Healed      bool          `json:"healed" db:"healed"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RetentionRule deletes rows of Table whose TimeColumn is older than
+// MaxAge. Condition, when set, further restricts which rows are eligible.
+type RetentionRule struct {
+	Name       string
+	Table      string
+	TimeColumn string
+	Condition  string
+	MaxAge     time.Duration
+}
+
+// RetentionReport is what a rule would delete if it ran now.
+type RetentionReport struct {
+	Rule     RetentionRule
+	Cutoff   time.Time
+	Eligible int64
+	Oldest   sql.NullTime
+}
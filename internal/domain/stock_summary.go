@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// StockSummary counts a store's products by their stock availability as
+// of CalculatedAt. It is recalculated in the background after the store's
+// products change, so it may briefly lag behind them.
+type StockSummary struct {
+	StoreID      int64     `json:"store_id" db:"store_id"`
+	Products     int64     `json:"products" db:"products"`
+	InStock      int64     `json:"in_stock" db:"in_stock"`
+	LowStock     int64     `json:"low_stock" db:"low_stock"`
+	Backorder    int64     `json:"backorder" db:"backorder"`
+	OutOfStock   int64     `json:"out_of_stock" db:"out_of_stock"`
+	Discontinued int64     `json:"discontinued" db:"discontinued"`
+	CalculatedAt time.Time `json:"calculated_at" db:"calculated_at"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Store owns products. Products can only be created in a store that
+// exists, and a store cannot be deleted while it has products.
+type Store struct {
+	ID        int64     `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (s *Store) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return invalidField("name", "required", "name is required")
+	}
+
+	if len(s.Name) > 100 {
+		return invalidField("name", "max", "name must not exceed 100 characters")
+	}
+
+	return nil
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// TrashedProduct is a deleted product kept in the recycle bin until it is
+// restored or purged.
+type TrashedProduct struct {
+	Product
+	TrashedAt time.Time `json:"trashed_at" db:"trashed_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
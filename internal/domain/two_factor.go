@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Two-factor enforcement policies.
+const (
+	// TwoFactorPolicyOptional asks for a code only from identities that
+	// enrolled.
+	TwoFactorPolicyOptional = "optional"
+	// TwoFactorPolicyRequired refuses sessions to identities that have not
+	// enrolled.
+	TwoFactorPolicyRequired = "required"
+)
+
+// Roles two-factor policies can be set for.
+const (
+	// TwoFactorRoleUser is a user signing in with email and password.
+	TwoFactorRoleUser = "user"
+	// TwoFactorRoleAPIKey is an API key holder opening a session.
+	TwoFactorRoleAPIKey = "api_key"
+	// TwoFactorRoleBulk is an API key holder whose key has the bulk scope.
+	TwoFactorRoleBulk = "bulk"
+	// TwoFactorRoleAdmin is a caller with the admin workload role.
+	TwoFactorRoleAdmin = "admin"
+)
+
+// TwoFactorPolicies holds the enforcement policy for each role, with
+// Default for roles that have none.
+type TwoFactorPolicies struct {
+	Default string
+	Roles   map[string]string
+}
+
+// ParseTwoFactorPolicies reads "role=policy" entries such as
+// "admin=required" on top of defaultPolicy.
+func ParseTwoFactorPolicies(defaultPolicy string, entries []string) (TwoFactorPolicies, error) {
+	if !validTwoFactorPolicy(defaultPolicy) {
+		return TwoFactorPolicies{}, fmt.Errorf("unsupported two-factor policy %q", defaultPolicy)
+	}
+	policies := TwoFactorPolicies{Default: defaultPolicy, Roles: make(map[string]string, len(entries))}
+	for _, entry := range entries {
+		role, policy, ok := strings.Cut(entry, "=")
+		if !ok || !validTwoFactorRole(role) || !validTwoFactorPolicy(policy) {
+			return TwoFactorPolicies{}, fmt.Errorf("invalid two-factor role policy %q, want role=optional|required", entry)
+		}
+		policies.Roles[role] = policy
+	}
+	return policies, nil
+}
+
+// For returns the policy that applies to role.
+func (p TwoFactorPolicies) For(role string) string {
+	if policy, ok := p.Roles[role]; ok {
+		return policy
+	}
+	return p.Default
+}
+
+func validTwoFactorPolicy(policy string) bool {
+	return policy == TwoFactorPolicyOptional || policy == TwoFactorPolicyRequired
+}
+
+func validTwoFactorRole(role string) bool {
+	switch role {
+	case TwoFactorRoleUser, TwoFactorRoleAPIKey, TwoFactorRoleBulk, TwoFactorRoleAdmin:
+		return true
+	}
+	return false
+}
+
+// TwoFactorEnrollment is an identity's TOTP enrollment. The TOTP secret is
+// kept in the secrets store under SecretKey; recovery codes are stored
+// hashed and removed once used.
+type TwoFactorEnrollment struct {
+	Identity           string
+	RecoveryCodeHashes []string
+	CreatedAt          time.Time
+	ConfirmedAt        *time.Time
+}
+
+// Confirmed reports whether enrollment was completed with a valid code.
+// Unconfirmed enrollments are not enforced.
+func (e *TwoFactorEnrollment) Confirmed() bool {
+	return e.ConfirmedAt != nil
+}
+
+func (e *TwoFactorEnrollment) SecretKey() string {
+	return TwoFactorSecretKey(e.Identity)
+}
+
+func TwoFactorSecretKey(identity string) string {
+	return "two_factor:" + identity
+}
+
+// TwoFactorSetup is what an authenticator app needs to enroll.
+type TwoFactorSetup struct {
+	Secret          string
+	ProvisioningURI string
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxUsageReconciliationPeriod caps the period one usage reconciliation
+// compares.
+const MaxUsageReconciliationPeriod = 31 * 24 * time.Hour
+
+// UsageCalls is a number of billable API calls one instance served for a
+// store within an hour. Instances add theirs to the same hour.
+type UsageCalls struct {
+	StoreID int64
+	Hour    time.Time
+	Calls   int64
+}
+
+// UsageRecord is a store's metered usage for one hour, the unit billing is
+// sent: the API calls served in the hour, and the store's product count and
+// storage when the hour was closed. EmittedAt is nil until the billing
+// pipeline accepted the record.
+type UsageRecord struct {
+	StoreID      int64      `json:"store_id" db:"store_id"`
+	Hour         time.Time  `json:"hour" db:"hour"`
+	APICalls     int64      `json:"api_calls" db:"api_calls"`
+	StorageBytes int64      `json:"storage_bytes" db:"storage_bytes"`
+	ProductCount int64      `json:"product_count" db:"product_count"`
+	RecordedAt   time.Time  `json:"recorded_at" db:"recorded_at"`
+	EmittedAt    *time.Time `json:"emitted_at,omitempty" db:"emitted_at"`
+}
+
+// EventID identifies the record to the billing pipeline. A record may be
+// sent more than once, always with the same ID, so the pipeline can drop
+// repeats.
+func (r *UsageRecord) EventID() string {
+	return fmt.Sprintf("usage-%d-%s", r.StoreID, r.Hour.UTC().Format("2006010215"))
+}
+
+// UsageComparison sets the API calls the instances recorded for a store and
+// hour against the record closed for it. Record is nil while the hour has
+// not been closed.
+type UsageComparison struct {
+	StoreID       int64
+	Hour          time.Time
+	RecordedCalls int64
+	Record        *UsageRecord
+}
+
+// Usage discrepancy kinds.
+const (
+	// UsageNotEmitted is an hour closed but not yet accepted by billing.
+	UsageNotEmitted = "not_emitted"
+	// UsageNotClosed is an hour past its grace period with recorded calls
+	// but no record, such as one the meter skipped while not running or
+	// a store created after the hour was closed.
+	UsageNotClosed = "not_closed"
+	// UsageCallsMismatch is an hour whose calls changed after it was
+	// closed, such as when an instance wrote its calls late.
+	UsageCallsMismatch = "calls_mismatch"
+)
+
+// UsageDiscrepancy is a store and hour whose emitted usage differs from
+// the recorded usage.
+type UsageDiscrepancy struct {
+	StoreID       int64
+	Hour          time.Time
+	Kind          string
+	RecordedCalls int64
+	EmittedCalls  int64
+}
+
+// UsageReconciliation compares the usage emitted to billing over a period
+// with the usage recorded.
+type UsageReconciliation struct {
+	From          time.Time
+	To            time.Time
+	Records       int
+	Emitted       int
+	RecordedCalls int64
+	EmittedCalls  int64
+	Discrepancies []UsageDiscrepancy
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Password length limits. bcrypt ignores everything past 72 bytes, so
+// longer passwords are refused rather than silently truncated.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// User is an account that signs in with email and password. Only the
+// bcrypt hash of the password is kept.
+type User struct {
+	ID           int64
+	Email        string
+	PasswordHash string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Identity names the user for sessions, two-factor enrollment, lockout
+// and audit, like the identities of API key and workload callers.
+func (u *User) Identity() string {
+	return fmt.Sprintf("user:%d", u.ID)
+}
+
+// NormalizeEmail lowercases and trims an email address so lookups do not
+// depend on how it was typed.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ValidateRegistration checks the email and password a user registers
+// with.
+func ValidateRegistration(email, password string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return fmt.Errorf("%w: email is not a valid address", ErrInvalidUser)
+	}
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return fmt.Errorf("%w: password must be %d to %d bytes long", ErrInvalidUser, MinPasswordLength, MaxPasswordLength)
+	}
+	return nil
+}
+
+// AuthTokens are the tokens issued at login and refresh.
+type AuthTokens struct {
+	AccessToken      string
+	AccessExpiresIn  time.Duration
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "fmt"
+
+// FieldError is a validation failure that names the field at fault, as
+// clients send it, and the rule it broke, so API responses can point at
+// the field. Rules follow the names of the binding tags where one fits:
+// required, min, max, gt, gte, oneof, url, email.
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+func invalidField(field, rule, message string) error {
+	return &FieldError{Field: field, Rule: rule, Message: message}
+}
+
+func invalidFieldf(field, rule, format string, args ...any) error {
+	return &FieldError{Field: field, Rule: rule, Message: fmt.Sprintf(format, args...)}
+}
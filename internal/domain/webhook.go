@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"database/sql"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// WebhookSubscription receives product events at URL. A subscription with
+// a StoreID only receives that store's events, and one with EventTypes
+// only events of those types; unset, it receives everything. PausedAt is
+// set while deliveries are suspended because the endpoint kept failing.
+type WebhookSubscription struct {
+	ID          int64         `json:"id" db:"id"`
+	URL         string        `json:"url" db:"url"`
+	Description string        `json:"description" db:"description"`
+	StoreID     sql.NullInt64 `json:"store_id" db:"store_id"`
+	EventTypes  []string      `json:"event_types" db:"event_types"`
+	PausedAt    sql.NullTime  `json:"paused_at" db:"paused_at"`
+	PauseReason string        `json:"pause_reason" db:"pause_reason"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+func (s *WebhookSubscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return invalidField("url", "url", "url must be an absolute http or https URL")
+	}
+
+	if s.StoreID.Valid && s.StoreID.Int64 <= 0 {
+		return invalidField("store_id", "gt", "store_id must be positive")
+	}
+
+	for _, eventType := range s.EventTypes {
+		if !slices.Contains(EventTypes, eventType) {
+			return invalidFieldf("event_types", "oneof", "unknown event type %q", eventType)
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether the event is within the subscription's scope.
+func (s *WebhookSubscription) Matches(event ProductEvent) bool {
+	if s.StoreID.Valid && s.StoreID.Int64 != event.StoreID {
+		return false
+	}
+	return len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, event.Type)
+}
+
+// WebhookSigningSecret signs deliveries to one store's subscriptions, or
+// to unscoped subscriptions for store 0. After a rotation the previous
+// secret keeps signing alongside the new one until PreviousExpiresAt, so
+// subscribers can switch over without rejecting deliveries.
+type WebhookSigningSecret struct {
+	StoreID           int64     `json:"store_id"`
+	Current           string    `json:"current"`
+	Previous          string    `json:"previous,omitempty"`
+	RotatedAt         time.Time `json:"rotated_at"`
+	PreviousExpiresAt time.Time `json:"previous_expires_at,omitempty"`
+}
+
+// Active returns the secrets deliveries are signed with at the given time,
+// current first.
+func (s *WebhookSigningSecret) Active(at time.Time) []string {
+	if s.Previous != "" && at.Before(s.PreviousExpiresAt) {
+		return []string{s.Current, s.Previous}
+	}
+	return []string{s.Current}
+}
+
+func WebhookSecretKey(storeID int64) string {
+	if storeID == 0 {
+		return "webhook_signing:global"
+	}
+	return "webhook_signing:store:" + strconv.FormatInt(storeID, 10)
+}
+
+// WebhookPayload is the body of one webhook delivery. Events published close
+// together are coalesced into a single delivery, in which case Bulk is set
+// so subscribers can tell an import from a trickle of edits.
+type WebhookPayload struct {
+	Bulk   bool           `json:"bulk"`
+	Events []ProductEvent `json:"events"`
+}
+
+// WebhookHealth scores a subscription by its recent deliveries from this
+// instance. SuccessRate and AvgLatency cover the most recent deliveries
+// only, so an endpoint that recovers is not held back by old failures.
+type WebhookHealth struct {
+	SubscriptionID      int64
+	Deliveries          int64
+	Failures            int64
+	ConsecutiveFailures int
+	SuccessRate         float64
+	AvgLatency          time.Duration
+	LastSuccessAt       sql.NullTime
+	LastFailureAt       sql.NullTime
+	LastError           string
+}
+
+// WebhookPause is sent to operators when a subscription is paused
+// automatically.
+type WebhookPause struct {
+	SubscriptionID      int64     `json:"subscription_id"`
+	URL                 string    `json:"url"`
+	Reason              string    `json:"reason"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	SuccessRate         float64   `json:"success_rate"`
+	LastError           string    `json:"last_error"`
+	PausedAt            time.Time `json:"paused_at"`
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+const (
+	// FormatNative frames events as the service's own envelope.
+	FormatNative = "native"
+	// FormatCloudEvents frames each event as a CloudEvents 1.0 event.
+	FormatCloudEvents = "cloudevents"
+
+	// ModeStructured carries the whole CloudEvent in the message body.
+	ModeStructured = "structured"
+	// ModeBinary carries the attributes in message headers and only the
+	// data in the body.
+	ModeBinary = "binary"
+)
+
+const CloudEventsSpecVersion = "1.0"
+
+// Encoding selects how events are framed on the wire.
+type Encoding struct {
+	Format string
+	// Mode is the CloudEvents content mode; it is ignored for FormatNative.
+	Mode string
+	// Source is the CloudEvents source attribute identifying this service.
+	Source string
+}
+
+func (e Encoding) Validate() error {
+	switch e.Format {
+	case FormatNative:
+		return nil
+	case FormatCloudEvents:
+	default:
+		return fmt.Errorf("unsupported event format %q", e.Format)
+	}
+	if e.Mode != ModeStructured && e.Mode != ModeBinary {
+		return fmt.Errorf("unsupported CloudEvents mode %q", e.Mode)
+	}
+	if e.Source == "" {
+		return fmt.Errorf("CloudEvents source is required")
+	}
+	return nil
+}
+
+// CloudEvent is an event in the CloudEvents 1.0 JSON format. Data is the
+// event as described by its registered schema; schemaversion is an
+// extension attribute naming that schema's version.
+type CloudEvent struct {
+	SpecVersion     string              `json:"specversion"`
+	ID              string              `json:"id"`
+	Source          string              `json:"source"`
+	Type            string              `json:"type"`
+	Subject         string              `json:"subject"`
+	Time            time.Time           `json:"time"`
+	DataContentType string              `json:"datacontenttype"`
+	SchemaVersion   int                 `json:"schemaversion"`
+	Data            domain.ProductEvent `json:"data"`
+}
+
+func NewCloudEvent(event domain.ProductEvent, source string) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              event.ID,
+		Source:          source,
+		Type:            event.Type,
+		Subject:         "products/" + strconv.FormatInt(event.ProductID, 10),
+		Time:            event.OccurredAt,
+		DataContentType: "application/json",
+		SchemaVersion:   event.SchemaVersion,
+		Data:            event,
+	}
+}
+
+// Attributes returns the context attributes by name, for bindings that
+// carry them outside the body. datacontenttype is left out: bindings map it
+// to their own content type.
+func (e CloudEvent) Attributes() map[string]string {
+	return map[string]string{
+		"specversion":   e.SpecVersion,
+		"id":            e.ID,
+		"source":        e.Source,
+		"type":          e.Type,
+		"subject":       e.Subject,
+		"time":          e.Time.Format(time.RFC3339Nano),
+		"schemaversion": strconv.Itoa(e.SchemaVersion),
+	}
+}
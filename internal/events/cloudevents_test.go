@@ -0,0 +1,51 @@
+package events
+
+import (
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoding_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		encoding Encoding
+		valid    bool
+	}{
+		{name: "native", encoding: Encoding{Format: FormatNative}, valid: true},
+		{name: "structured", encoding: Encoding{Format: FormatCloudEvents, Mode: ModeStructured, Source: "/product-service"}, valid: true},
+		{name: "binary", encoding: Encoding{Format: FormatCloudEvents, Mode: ModeBinary, Source: "/product-service"}, valid: true},
+		{name: "unknown format", encoding: Encoding{Format: "avro"}},
+		{name: "unknown mode", encoding: Encoding{Format: FormatCloudEvents, Mode: "batch", Source: "/product-service"}},
+		{name: "missing source", encoding: Encoding{Format: FormatCloudEvents, Mode: ModeBinary}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.encoding.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewCloudEvent(t *testing.T) {
+	event := productEvent(domain.ProductEventDeleted, 1)
+	cloudEvent := NewCloudEvent(event, "/product-service")
+
+	assert.Equal(t, event, cloudEvent.Data)
+	assert.Equal(t, map[string]string{
+		"specversion":   "1.0",
+		"id":            event.ID,
+		"source":        "/product-service",
+		"type":          "product.deleted",
+		"subject":       "products/42",
+		"time":          "2024-03-02T10:45:00Z",
+		"schemaversion": "1",
+	}, cloudEvent.Attributes())
+}
@@ -0,0 +1,112 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+)
+
+// messageWriter is the part of kafka.Writer the producer uses.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, messages ...kafka.Message) error
+	Close() error
+}
+
+// KafkaProducer writes product events to a Kafka topic, one message per
+// event keyed by product ID, so each product's events stay in order. A
+// batch is acknowledged once every in-sync replica has written it. The
+// outbox relay calls it, and retries a batch that fails.
+type KafkaProducer struct {
+	writer   messageWriter
+	encoding Encoding
+	logger   *logrus.Logger
+
+	events *telemetry.Counter
+}
+
+// NewKafkaProducer frames messages with encoding; the zero value writes
+// the native event.
+func NewKafkaProducer(brokers []string, topic string, registry *telemetry.Registry, encoding Encoding, logger *logrus.Logger) *KafkaProducer {
+	return newKafkaProducer(&kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}, registry, encoding, logger)
+}
+
+func newKafkaProducer(writer messageWriter, registry *telemetry.Registry, encoding Encoding, logger *logrus.Logger) *KafkaProducer {
+	if encoding.Format == "" {
+		encoding.Format = FormatNative
+	}
+	return &KafkaProducer{
+		writer:   writer,
+		encoding: encoding,
+		logger:   logger,
+		events:   registry.NewCounter("kafka_events_total", "Product events written to Kafka by result.", "result"),
+	}
+}
+
+// Send writes the events and returns once Kafka has them all. An event
+// that cannot be encoded is dropped, as retrying would not help.
+func (p *KafkaProducer) Send(ctx context.Context, events []domain.ProductEvent) error {
+	messages := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		message, err := p.encode(event)
+		if err != nil {
+			p.events.Inc("dropped")
+			p.logger.WithContext(ctx).WithError(err).WithField("event_id", event.ID).Error("Failed to encode product event, dropping it")
+			continue
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	if err := p.writer.WriteMessages(ctx, messages...); err != nil {
+		p.events.Add(float64(len(messages)), "failed")
+		return fmt.Errorf("write product events to kafka: %w", err)
+	}
+	p.events.Add(float64(len(messages)), "sent")
+	return nil
+}
+
+func (p *KafkaProducer) Close() error {
+	return p.writer.Close()
+}
+
+// encode frames an event as configured. CloudEvents binary mode carries
+// the attributes in ce_ headers, as in the CloudEvents Kafka binding.
+func (p *KafkaProducer) encode(event domain.ProductEvent) (kafka.Message, error) {
+	message := kafka.Message{
+		Key:  []byte(strconv.FormatInt(event.ProductID, 10)),
+		Time: event.OccurredAt,
+	}
+
+	var value any = event
+	contentType := "application/json"
+	if p.encoding.Format == FormatCloudEvents {
+		cloudEvent := NewCloudEvent(event, p.encoding.Source)
+		if p.encoding.Mode == ModeBinary {
+			for name, attribute := range cloudEvent.Attributes() {
+				message.Headers = append(message.Headers, kafka.Header{Key: "ce_" + name, Value: []byte(attribute)})
+			}
+			value, contentType = cloudEvent.Data, cloudEvent.DataContentType
+		} else {
+			value, contentType = cloudEvent, "application/cloudevents+json"
+		}
+	}
+	message.Headers = append(message.Headers, kafka.Header{Key: "content-type", Value: []byte(contentType)})
+
+	var err error
+	message.Value, err = json.Marshal(value)
+	return message, err
+}
@@ -0,0 +1,92 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingWriter struct {
+	messages []kafka.Message
+	err      error
+}
+
+func (w *recordingWriter) WriteMessages(ctx context.Context, messages ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.messages = append(w.messages, messages...)
+	return nil
+}
+
+func (w *recordingWriter) Close() error {
+	return nil
+}
+
+func newTestKafkaProducer(writer messageWriter, encoding Encoding) *KafkaProducer {
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	return newKafkaProducer(writer, registry, encoding, logrus.New())
+}
+
+func header(message kafka.Message, key string) string {
+	for _, h := range message.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func TestKafkaProducer_WritesEvents(t *testing.T) {
+	writer := &recordingWriter{}
+	producer := newTestKafkaProducer(writer, Encoding{})
+
+	require.NoError(t, producer.Send(context.Background(), []domain.ProductEvent{
+		productEvent(domain.ProductEventCreated, 2),
+		productEvent(domain.ProductEventUpdated, 2),
+	}))
+
+	require.Len(t, writer.messages, 2)
+	message := writer.messages[0]
+	assert.Equal(t, "42", string(message.Key))
+	assert.Equal(t, occurredAt, message.Time)
+	assert.Equal(t, "application/json", header(message, "content-type"))
+
+	var event domain.ProductEvent
+	require.NoError(t, json.Unmarshal(message.Value, &event))
+	assert.Equal(t, domain.ProductEventCreated, event.Type)
+	assert.Equal(t, 2, event.SchemaVersion)
+	assert.Contains(t, string(writer.messages[1].Value), `"type":"product.updated"`)
+}
+
+func TestKafkaProducer_ReturnsWriteErrors(t *testing.T) {
+	writer := &recordingWriter{err: errors.New("broker unavailable")}
+	producer := newTestKafkaProducer(writer, Encoding{})
+
+	err := producer.Send(context.Background(), []domain.ProductEvent{productEvent(domain.ProductEventCreated, 1)})
+	assert.ErrorContains(t, err, "broker unavailable")
+}
+
+func TestKafkaProducer_CloudEventsBinary(t *testing.T) {
+	writer := &recordingWriter{}
+	producer := newTestKafkaProducer(writer, Encoding{Format: FormatCloudEvents, Mode: ModeBinary, Source: "/product-service"})
+
+	require.NoError(t, producer.Send(context.Background(), []domain.ProductEvent{productEvent(domain.ProductEventDeleted, 3)}))
+
+	require.Len(t, writer.messages, 1)
+	message := writer.messages[0]
+	assert.Equal(t, "product.deleted", header(message, "ce_type"))
+	assert.Equal(t, "products/42", header(message, "ce_subject"))
+	assert.Equal(t, "3", header(message, "ce_schemaversion"))
+	assert.Equal(t, "application/json", header(message, "content-type"))
+	assert.NotContains(t, string(message.Value), "specversion")
+}
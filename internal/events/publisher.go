@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Sink receives events that passed validation.
+type Sink interface {
+	Publish(ctx context.Context, event domain.ProductEvent)
+}
+
+// Sinks hands each event to every sink in order.
+type Sinks []Sink
+
+func (s Sinks) Publish(ctx context.Context, event domain.ProductEvent) {
+	for _, sink := range s {
+		sink.Publish(ctx, event)
+	}
+}
+
+// ValidatingPublisher stamps each event with the current schema version of
+// its type and hands it on only if it conforms to that schema. A
+// non-conforming event is a bug in the service, so it is logged and
+// dropped rather than sent to subscribers who would fail to parse it.
+type ValidatingPublisher struct {
+	schemas *Registry
+	next    Sink
+	logger  *logrus.Logger
+
+	rejected *telemetry.Counter
+}
+
+func NewValidatingPublisher(schemas *Registry, next Sink, registry *telemetry.Registry, logger *logrus.Logger) *ValidatingPublisher {
+	return &ValidatingPublisher{
+		schemas:  schemas,
+		next:     next,
+		logger:   logger,
+		rejected: registry.NewCounter("event_schema_violations_total", "Events dropped because they did not match their schema.", "type"),
+	}
+}
+
+func (p *ValidatingPublisher) Publish(ctx context.Context, event domain.ProductEvent) {
+	logger := p.logger.WithFields(logrus.Fields{"event_id": event.ID, "event_type": event.Type})
+
+	version, ok := p.schemas.Current(event.Type)
+	if !ok {
+		p.rejected.Inc(event.Type)
+		logger.Error("No schema registered for event type, event not published")
+		return
+	}
+	event.SchemaVersion = version
+
+	if err := p.schemas.Validate(event); err != nil {
+		p.rejected.Inc(event.Type)
+		logger.WithError(err).WithField("schema_version", version).Error("Event does not match its schema, event not published")
+		return
+	}
+	p.next.Publish(ctx, event)
+}
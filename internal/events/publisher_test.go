@@ -0,0 +1,56 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSink struct {
+	events []domain.ProductEvent
+}
+
+func (s *recordingSink) Publish(ctx context.Context, event domain.ProductEvent) {
+	s.events = append(s.events, event)
+}
+
+func TestValidatingPublisher(t *testing.T) {
+	schemas, err := NewRegistry()
+	require.NoError(t, err)
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	sink := &recordingSink{}
+	publisher := NewValidatingPublisher(schemas, sink, registry, logrus.New())
+
+	broken := productEvent(domain.ProductEventUpdated, 0)
+	broken.Product.Status = "archived"
+	unknown := productEvent("product.archived", 0)
+
+	publisher.Publish(context.Background(), productEvent(domain.ProductEventCreated, 0))
+	publisher.Publish(context.Background(), broken)
+	publisher.Publish(context.Background(), unknown)
+
+	require.Len(t, sink.events, 1)
+	assert.Equal(t, domain.ProductEventCreated, sink.events[0].Type)
+	assert.Equal(t, 5, sink.events[0].SchemaVersion, "stamped with the current version")
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `event_schema_violations_total{type="product.updated"} 1`)
+	assert.Contains(t, buf.String(), `event_schema_violations_total{type="product.archived"} 1`)
+}
+
+func TestSinks_PublishesToEverySink(t *testing.T) {
+	first, second := &recordingSink{}, &recordingSink{}
+
+	Sinks{first, second}.Publish(context.Background(), domain.ProductEvent{ID: "1", Type: domain.ProductEventCreated})
+
+	assert.Len(t, first.events, 1)
+	assert.Len(t, second.events, 1)
+}
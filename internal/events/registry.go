@@ -0,0 +1,126 @@
+// Package events holds the versioned JSON schemas of the events the
+// service publishes and checks every outgoing event against them, so a
+// payload change that would break subscribers fails loudly here instead.
+package events
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/jsonschema"
+)
+
+// Schemas are named <type>.v<version>.json. Changing a published version
+// breaks its subscribers: add a new version instead. The highest version of
+// a type is the one events are published with.
+//
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+var schemaFileName = regexp.MustCompile(`^([a-z_]+\.[a-z_]+)\.v([1-9][0-9]*)\.json$`)
+
+type schemaKey struct {
+	eventType string
+	version   int
+}
+
+type Registry struct {
+	schemas map[schemaKey]*jsonschema.Schema
+	raw     map[schemaKey]json.RawMessage
+	current map[string]int
+}
+
+// NewRegistry loads the embedded schemas.
+func NewRegistry() (*Registry, error) {
+	entries, err := schemaFiles.ReadDir("schemas")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list event schemas: %w", err)
+	}
+
+	r := &Registry{
+		schemas: make(map[schemaKey]*jsonschema.Schema),
+		raw:     make(map[schemaKey]json.RawMessage),
+		current: make(map[string]int),
+	}
+	for _, entry := range entries {
+		match := schemaFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("event schema %s is not named <type>.v<version>.json", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[2])
+		key := schemaKey{eventType: match[1], version: version}
+
+		data, err := schemaFiles.ReadFile(path.Join("schemas", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event schema %s: %w", entry.Name(), err)
+		}
+		schema, err := jsonschema.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("event schema %s: %w", entry.Name(), err)
+		}
+
+		r.schemas[key] = schema
+		r.raw[key] = data
+		r.current[key.eventType] = max(r.current[key.eventType], version)
+	}
+	return r, nil
+}
+
+// Current returns the version events of the given type are published with.
+func (r *Registry) Current(eventType string) (int, bool) {
+	version, ok := r.current[eventType]
+	return version, ok
+}
+
+// Schemas lists every registered schema by type, then version.
+func (r *Registry) Schemas() []domain.EventSchema {
+	schemas := make([]domain.EventSchema, 0, len(r.raw))
+	for key := range r.raw {
+		schemas = append(schemas, r.describe(key))
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		if schemas[i].Type != schemas[j].Type {
+			return schemas[i].Type < schemas[j].Type
+		}
+		return schemas[i].Version < schemas[j].Version
+	})
+	return schemas
+}
+
+func (r *Registry) Schema(eventType string, version int) (domain.EventSchema, bool) {
+	key := schemaKey{eventType: eventType, version: version}
+	if _, ok := r.raw[key]; !ok {
+		return domain.EventSchema{}, false
+	}
+	return r.describe(key), true
+}
+
+func (r *Registry) describe(key schemaKey) domain.EventSchema {
+	return domain.EventSchema{
+		Type:    key.eventType,
+		Version: key.version,
+		Current: r.current[key.eventType] == key.version,
+		Schema:  r.raw[key],
+	}
+}
+
+// Validate checks an event against the schema its type and schema version
+// name.
+func (r *Registry) Validate(event domain.ProductEvent) error {
+	schema, ok := r.schemas[schemaKey{eventType: event.Type, version: event.SchemaVersion}]
+	if !ok {
+		return fmt.Errorf("no schema registered for %s v%d", event.Type, event.SchemaVersion)
+	}
+
+	document, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	return schema.Validate(document)
+}
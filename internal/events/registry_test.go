@@ -0,0 +1,155 @@
+package events
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/jsonschema"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var occurredAt = time.Date(2024, 3, 2, 10, 45, 0, 0, time.UTC)
+
+func testProduct() *domain.Product {
+	return &domain.Product{
+		ID:                42,
+		StoreID:           7,
+		Name:              "Espresso Beans",
+		Description:       sql.NullString{String: "Dark roast", Valid: true},
+		DescriptionFormat: domain.DescriptionFormatPlain,
+		Amount:            quantity.New(12),
+		Unit:              domain.UnitPiece,
+		Price:             18.5,
+		Status:            domain.ProductStatusActive,
+		ModerationStatus:  domain.ModerationStatusApproved,
+		CreatedAt:         occurredAt,
+		UpdatedAt:         occurredAt,
+	}
+}
+
+func productEvent(eventType string, version int) domain.ProductEvent {
+	return domain.ProductEvent{
+		ID: "0190a5e4-8f00-7000-8000-000000000001", Type: eventType, SchemaVersion: version,
+		StoreID: 7, ProductID: 42, OccurredAt: occurredAt, Product: domain.NewProductEventData(testProduct()),
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	r, err := NewRegistry()
+	require.NoError(t, err)
+
+	var listed []string
+	for _, schema := range r.Schemas() {
+		listed = append(listed, schema.Type)
+		assert.NotEmpty(t, schema.Schema)
+	}
+	assert.Equal(t, []string{
+		"product.created", "product.created", "product.created", "product.created", "product.created",
+		"product.deleted", "product.deleted", "product.deleted", "product.deleted",
+		"product.published", "product.unpublished",
+		"product.updated", "product.updated", "product.updated", "product.updated",
+		"stock.changed", "stock.changed", "stock.changed",
+	}, listed)
+
+	version, ok := r.Current(domain.ProductEventCreated)
+	assert.True(t, ok)
+	assert.Equal(t, 5, version)
+
+	v1, ok := r.Schema(domain.ProductEventCreated, 1)
+	require.True(t, ok)
+	assert.False(t, v1.Current)
+
+	_, ok = r.Schema(domain.ProductEventCreated, 6)
+	assert.False(t, ok)
+	_, ok = r.Current("order.created")
+	assert.False(t, ok)
+}
+
+// Every event the service publishes must conform to the current schema of
+// its type.
+func TestRegistry_ValidatesPublishedEvents(t *testing.T) {
+	r, err := NewRegistry()
+	require.NoError(t, err)
+
+	stock := domain.ProductEvent{
+		ID: "0190a5e4-8f00-7000-8000-000000000002", Type: domain.StockEventChanged,
+		StoreID: 7, ProductID: 42, OccurredAt: occurredAt,
+		Stock: &domain.StockChange{PreviousAmount: quantity.New(12), Amount: quantity.New(0), Unit: domain.UnitPiece},
+	}
+	weighed := productEvent(domain.ProductEventUpdated, 0)
+	weighed.Product.Amount = quantity.MustParse("2.375")
+	weighed.Product.Unit = domain.UnitKilogram
+	releaseDate := occurredAt.Add(30 * 24 * time.Hour)
+	backordered := productEvent(domain.ProductEventUpdated, 0)
+	backordered.Product.Amount = quantity.New(-3)
+	backordered.Product.AllowBackorder = true
+	backordered.Product.BackorderLimit = quantity.New(10)
+	backordered.Product.PreorderReleaseDate = &releaseDate
+	oversold := stock
+	draft := productEvent(domain.ProductEventCreated, 0)
+	draft.Product.Status = domain.ProductStatusDraft
+	oversold.Stock = &domain.StockChange{PreviousAmount: quantity.New(1), Amount: quantity.New(-2), Unit: domain.UnitPiece}
+	for _, event := range []domain.ProductEvent{
+		productEvent(domain.ProductEventCreated, 0),
+		productEvent(domain.ProductEventUpdated, 0),
+		productEvent(domain.ProductEventDeleted, 0),
+		productEvent(domain.ProductEventPublished, 0),
+		productEvent(domain.ProductEventUnpublished, 0),
+		stock,
+		weighed,
+		backordered,
+		oversold,
+		draft,
+	} {
+		event.SchemaVersion, _ = r.Current(event.Type)
+		assert.NoError(t, r.Validate(event), event.Type)
+	}
+
+	// v1 subscribers keep validating what is published today for whole
+	// pieces; fractional amounts need the current version.
+	assert.NoError(t, r.Validate(productEvent(domain.ProductEventCreated, 1)))
+	weighed.SchemaVersion = 1
+	assert.ErrorContains(t, r.Validate(weighed), "$.product.amount")
+	// Negative amounts of backordered products need the current version.
+	backordered.SchemaVersion = 2
+	assert.ErrorContains(t, r.Validate(backordered), "$.product.amount")
+	// So does the draft status.
+	draft.SchemaVersion = 4
+	assert.ErrorContains(t, r.Validate(draft), "$.product.status")
+}
+
+func TestRegistry_ValidateRejects(t *testing.T) {
+	r, err := NewRegistry()
+	require.NoError(t, err)
+
+	missingProduct := productEvent(domain.ProductEventUpdated, 1)
+	missingProduct.Product = nil
+
+	negativeStock := domain.ProductEvent{
+		ID: "x", Type: domain.StockEventChanged, SchemaVersion: 1, StoreID: 7, ProductID: 42, OccurredAt: occurredAt,
+		Stock: &domain.StockChange{PreviousAmount: quantity.New(1), Amount: quantity.New(-1)},
+	}
+
+	tests := []struct {
+		name      string
+		event     domain.ProductEvent
+		violation string
+	}{
+		{name: "missing payload", event: missingProduct, violation: "$.product: is required"},
+		{name: "invalid value", event: negativeStock, violation: "$.stock.amount: must be at least 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var validationErr *jsonschema.ValidationError
+			require.ErrorAs(t, r.Validate(tt.event), &validationErr)
+			assert.Contains(t, validationErr.Violations, tt.violation)
+		})
+	}
+
+	assert.ErrorContains(t, r.Validate(productEvent(domain.ProductEventCreated, 0)), "no schema registered")
+}
@@ -0,0 +1,78 @@
+// Package inbox applies inbound messages exactly once. Brokers and ERP
+// integrations deliver at least once, so the same message can arrive
+// again after a timeout or a crash. The consumer records each message ID
+// in the same database transaction as the message's side effects: either
+// both are committed, or neither is and the redelivery is applied as new.
+package inbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Store records processed messages.
+type Store interface {
+	// Apply records the message and runs fn in one transaction, unless the
+	// message is already recorded. An error from fn rolls both back.
+	Apply(ctx context.Context, message domain.InboundMessage, at time.Time, fn func(tx *sql.Tx) error) (bool, error)
+}
+
+// Handler applies a message. Its side effects must be written through tx:
+// anything written outside it is not rolled back with the inbox record and
+// may be applied again on redelivery.
+type Handler func(ctx context.Context, tx *sql.Tx, message domain.InboundMessage) error
+
+var ErrInvalidMessage = errors.New("inbound message needs a source and an ID")
+
+type Consumer struct {
+	store  Store
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	messages *telemetry.Counter
+}
+
+func NewConsumer(store Store, registry *telemetry.Registry, clk clock.Clock, logger *logrus.Logger) *Consumer {
+	return &Consumer{
+		store:    store,
+		logger:   logger,
+		clock:    clk,
+		messages: registry.NewCounter("inbox_messages_total", "Inbound messages by source and result.", "source", "result"),
+	}
+}
+
+// Consume applies the message with handler unless it was applied before,
+// and reports whether it was applied now. A duplicate is not an error: the
+// caller should acknowledge it to the broker like any applied message. On
+// error nothing was recorded, so the message can be retried.
+func (c *Consumer) Consume(ctx context.Context, message domain.InboundMessage, handler Handler) (bool, error) {
+	if message.Source == "" || message.ID == "" {
+		return false, ErrInvalidMessage
+	}
+	logger := c.logger.WithFields(logrus.Fields{"source": message.Source, "message_id": message.ID})
+
+	applied, err := c.store.Apply(ctx, message, c.clock.Now(), func(tx *sql.Tx) error {
+		return handler(ctx, tx, message)
+	})
+	switch {
+	case err != nil:
+		c.messages.Inc(message.Source, "failed")
+		logger.WithError(err).Warn("Failed to apply inbound message")
+		return false, err
+	case !applied:
+		c.messages.Inc(message.Source, "duplicate")
+		logger.Info("Skipping inbound message that was already applied")
+		return false, nil
+	default:
+		c.messages.Inc(message.Source, "applied")
+		return true, nil
+	}
+}
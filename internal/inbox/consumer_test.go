@@ -0,0 +1,122 @@
+package inbox
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore records messages the way InboxRepository does: a failing fn
+// leaves nothing recorded.
+type fakeStore struct {
+	mu        sync.Mutex
+	processed map[string]time.Time
+}
+
+func (s *fakeStore) Apply(ctx context.Context, message domain.InboundMessage, at time.Time, fn func(tx *sql.Tx) error) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := message.Source + "/" + message.ID
+	if _, ok := s.processed[key]; ok {
+		return false, nil
+	}
+	if err := fn(nil); err != nil {
+		return false, err
+	}
+	s.processed[key] = at
+	return true, nil
+}
+
+var now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestConsumer() (*Consumer, *fakeStore, *telemetry.Registry) {
+	store := &fakeStore{processed: make(map[string]time.Time)}
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	c := NewConsumer(store, registry, clock.NewFake(now), logrus.New())
+	return c, store, registry
+}
+
+func TestConsumer_AppliesEachMessageOnce(t *testing.T) {
+	c, store, registry := newTestConsumer()
+
+	var applied []string
+	handler := func(ctx context.Context, tx *sql.Tx, message domain.InboundMessage) error {
+		applied = append(applied, message.Source+"/"+message.ID)
+		return nil
+	}
+
+	for _, message := range []domain.InboundMessage{
+		{Source: "erp", ID: "1"},
+		{Source: "erp", ID: "1"},
+		{Source: "erp", ID: "2"},
+		{Source: "queue", ID: "1"},
+	} {
+		_, err := c.Consume(context.Background(), message, handler)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"erp/1", "erp/2", "queue/1"}, applied, "IDs are unique per source")
+	assert.Equal(t, now, store.processed["erp/1"])
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `inbox_messages_total{result="applied",source="erp"} 2`)
+	assert.Contains(t, buf.String(), `inbox_messages_total{result="duplicate",source="erp"} 1`)
+}
+
+func TestConsumer_RetriesAfterFailure(t *testing.T) {
+	c, _, registry := newTestConsumer()
+	message := domain.InboundMessage{Source: "erp", ID: "7"}
+
+	attempts := 0
+	handler := func(ctx context.Context, tx *sql.Tx, message domain.InboundMessage) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("stock update failed")
+		}
+		return nil
+	}
+
+	applied, err := c.Consume(context.Background(), message, handler)
+	assert.Error(t, err)
+	assert.False(t, applied)
+
+	applied, err = c.Consume(context.Background(), message, handler)
+	require.NoError(t, err)
+	assert.True(t, applied, "a failed message was not recorded, so the redelivery applies it")
+
+	applied, err = c.Consume(context.Background(), message, handler)
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Equal(t, 2, attempts)
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `inbox_messages_total{result="failed",source="erp"} 1`)
+}
+
+func TestConsumer_RejectsMessagesWithoutID(t *testing.T) {
+	c, _, _ := newTestConsumer()
+	handler := func(ctx context.Context, tx *sql.Tx, message domain.InboundMessage) error {
+		t.Fatal("handler must not run")
+		return nil
+	}
+
+	_, err := c.Consume(context.Background(), domain.InboundMessage{Source: "erp"}, handler)
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+	_, err = c.Consume(context.Background(), domain.InboundMessage{ID: "1"}, handler)
+	assert.ErrorIs(t, err, ErrInvalidMessage)
+}
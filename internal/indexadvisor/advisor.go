@@ -0,0 +1,201 @@
+// Package indexadvisor counts the filter and sort shapes list queries run
+// with and compares them against the existing indexes, suggesting
+// composite indexes that are missing and flagging ones nothing uses.
+package indexadvisor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+)
+
+// Tracker counts access patterns since the process started. A nil Tracker
+// records nothing, so repositories can be built without one.
+type Tracker struct {
+	mu     sync.Mutex
+	counts map[string]*domain.AccessPatternUsage
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{counts: make(map[string]*domain.AccessPatternUsage)}
+}
+
+func (t *Tracker) Record(pattern domain.AccessPattern) {
+	if t == nil {
+		return
+	}
+	key := pattern.String()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage, ok := t.counts[key]
+	if !ok {
+		usage = &domain.AccessPatternUsage{Pattern: pattern}
+		t.counts[key] = usage
+	}
+	usage.Uses++
+}
+
+// Usage returns the recorded patterns, most used first.
+func (t *Tracker) Usage() []domain.AccessPatternUsage {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	usage := make([]domain.AccessPatternUsage, 0, len(t.counts))
+	for _, u := range t.counts {
+		usage = append(usage, *u)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Uses != usage[j].Uses {
+			return usage[i].Uses > usage[j].Uses
+		}
+		return usage[i].Pattern.String() < usage[j].Pattern.String()
+	})
+	return usage
+}
+
+// IndexSource lists the existing indexes with their columns and scan
+// counts.
+type IndexSource interface {
+	IndexStats(ctx context.Context) ([]domain.IndexStats, error)
+}
+
+type Advisor struct {
+	tracker *Tracker
+	indexes IndexSource
+	// minUses keeps rarely used patterns from producing advice.
+	minUses int64
+	clock   clock.Clock
+}
+
+func NewAdvisor(tracker *Tracker, indexes IndexSource, minUses int64, clk clock.Clock) *Advisor {
+	return &Advisor{
+		tracker: tracker,
+		indexes: indexes,
+		minUses: minUses,
+		clock:   clk,
+	}
+}
+
+func (a *Advisor) Report(ctx context.Context) (*domain.IndexAdviceReport, error) {
+	indexes, err := a.indexes.IndexStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := a.tracker.Usage()
+	return &domain.IndexAdviceReport{
+		GeneratedAt: a.clock.Now(),
+		Patterns:    usage,
+		Advice:      Advise(usage, indexes, a.minUses),
+	}, nil
+}
+
+// Advise suggests one index for each group of patterns used at least
+// minUses times that no existing index serves, and flags never-scanned
+// non-unique indexes that serve no recorded pattern. Missing indexes come
+// first, most used first.
+func Advise(usage []domain.AccessPatternUsage, indexes []domain.IndexStats, minUses int64) []domain.IndexAdvice {
+	missing := make(map[string]*domain.IndexAdvice)
+	served := make(map[string]bool)
+
+	for _, u := range usage {
+		columns, equals := indexColumns(u.Pattern)
+		if len(columns) == 0 {
+			continue
+		}
+
+		covered := false
+		for _, index := range indexes {
+			if index.Table == u.Pattern.Table && covers(index.Columns, columns, equals) {
+				served[index.Index] = true
+				covered = true
+			}
+		}
+		if covered || u.Uses < minUses {
+			continue
+		}
+
+		key := u.Pattern.Table + "(" + strings.Join(columns, ",") + ")"
+		advice, ok := missing[key]
+		if !ok {
+			name := "idx_" + u.Pattern.Table + "_" + strings.Join(columns, "_")
+			advice = &domain.IndexAdvice{
+				Kind:    domain.IndexAdviceMissing,
+				Table:   u.Pattern.Table,
+				Index:   name,
+				Columns: columns,
+				Statement: fmt.Sprintf("CREATE INDEX CONCURRENTLY %s ON %s (%s);",
+					name, u.Pattern.Table, strings.Join(columns, ", ")),
+			}
+			missing[key] = advice
+		}
+		advice.Uses += u.Uses
+		advice.Patterns = append(advice.Patterns, u.Pattern.String())
+	}
+
+	advice := make([]domain.IndexAdvice, 0, len(missing))
+	for _, a := range missing {
+		advice = append(advice, *a)
+	}
+	sort.Slice(advice, func(i, j int) bool {
+		if advice[i].Uses != advice[j].Uses {
+			return advice[i].Uses > advice[j].Uses
+		}
+		return advice[i].Index < advice[j].Index
+	})
+
+	for _, index := range indexes {
+		if index.Unique || index.Scans > 0 || served[index.Index] {
+			continue
+		}
+		advice = append(advice, domain.IndexAdvice{
+			Kind:      domain.IndexAdviceUnused,
+			Table:     index.Table,
+			Index:     index.Index,
+			Columns:   index.Columns,
+			Statement: fmt.Sprintf("DROP INDEX CONCURRENTLY %s;", index.Index),
+		})
+	}
+	return advice
+}
+
+// indexColumns returns the columns of the index that serves pattern:
+// equality columns, in name order, then the range column, then the sort
+// columns not already included. The second result is how many leading
+// columns are equality columns, whose order does not matter.
+func indexColumns(pattern domain.AccessPattern) ([]string, int) {
+	columns := slices.Clone(pattern.Equals)
+	slices.Sort(columns)
+	equals := len(columns)
+
+	for _, column := range append([]string{pattern.Range}, pattern.Sort...) {
+		if column != "" && !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns, equals
+}
+
+// covers reports whether an index on indexed serves a pattern needing
+// columns, the first equals of which may appear in any order.
+func covers(indexed, columns []string, equals int) bool {
+	if len(indexed) < len(columns) {
+		return false
+	}
+
+	leading := slices.Clone(indexed[:equals])
+	slices.Sort(leading)
+	return slices.Equal(leading, columns[:equals]) && slices.Equal(indexed[equals:len(columns)], columns[equals:])
+}
@@ -0,0 +1,130 @@
+package indexadvisor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	byStore      = domain.AccessPattern{Table: "products", Equals: []string{"store_id"}, Sort: []string{"id"}}
+	byModeration = domain.AccessPattern{Table: "products", Equals: []string{"moderation_status"}, Sort: []string{"updated_at", "id"}}
+	afterID      = domain.AccessPattern{Table: "products", Range: "id", Sort: []string{"id"}}
+	newest       = domain.AccessPattern{Table: "products", Sort: []string{"created_at"}}
+)
+
+func TestAccessPattern_String(t *testing.T) {
+	assert.Equal(t, "products WHERE store_id = ? ORDER BY id", byStore.String())
+	assert.Equal(t, "products WHERE id > ? ORDER BY id", afterID.String())
+	assert.Equal(t, "products", domain.AccessPattern{Table: "products"}.String())
+}
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tracker.Record(byStore)
+		}()
+	}
+	wg.Wait()
+	tracker.Record(newest)
+
+	assert.Equal(t, []domain.AccessPatternUsage{
+		{Pattern: byStore, Uses: 10},
+		{Pattern: newest, Uses: 1},
+	}, tracker.Usage())
+
+	var disabled *Tracker
+	disabled.Record(byStore)
+	assert.Empty(t, disabled.Usage())
+}
+
+func TestAdvise(t *testing.T) {
+	usage := []domain.AccessPatternUsage{
+		{Pattern: byModeration, Uses: 500},
+		{Pattern: byStore, Uses: 300},
+		{Pattern: afterID, Uses: 200},
+		{Pattern: newest, Uses: 5},
+		// Same index as byStore: grouped into one suggestion.
+		{Pattern: domain.AccessPattern{Table: "products", Equals: []string{"store_id"}, Range: "id"}, Uses: 150},
+	}
+	indexes := []domain.IndexStats{
+		{Table: "products", Index: "products_pkey", Columns: []string{"id"}, Unique: true},
+		// Serves no pattern in full: moderation lists also sort.
+		{Table: "products", Index: "idx_products_moderation_status", Columns: []string{"moderation_status"}, Scans: 40},
+		{Table: "products", Index: "idx_products_name", Columns: []string{"name"}},
+		{Table: "product_trash", Index: "idx_product_trash_store_id", Columns: []string{"store_id"}},
+	}
+
+	advice := Advise(usage, indexes, 100)
+
+	require.Len(t, advice, 4)
+	assert.Equal(t, domain.IndexAdvice{
+		Kind:      domain.IndexAdviceMissing,
+		Table:     "products",
+		Index:     "idx_products_moderation_status_updated_at_id",
+		Columns:   []string{"moderation_status", "updated_at", "id"},
+		Uses:      500,
+		Patterns:  []string{"products WHERE moderation_status = ? ORDER BY updated_at, id"},
+		Statement: "CREATE INDEX CONCURRENTLY idx_products_moderation_status_updated_at_id ON products (moderation_status, updated_at, id);",
+	}, advice[0])
+	assert.Equal(t, "idx_products_store_id_id", advice[1].Index)
+	assert.Equal(t, int64(450), advice[1].Uses)
+	assert.Len(t, advice[1].Patterns, 2)
+
+	assert.Equal(t, domain.IndexAdviceUnused, advice[2].Kind, "the primary key serves afterID and unused patterns below minUses are ignored")
+	assert.Equal(t, "idx_products_name", advice[2].Index)
+	assert.Equal(t, "DROP INDEX CONCURRENTLY idx_products_name;", advice[2].Statement)
+	assert.Equal(t, "idx_product_trash_store_id", advice[3].Index)
+}
+
+func TestAdvise_EqualityColumnsInAnyOrder(t *testing.T) {
+	usage := []domain.AccessPatternUsage{{
+		Pattern: domain.AccessPattern{Table: "products", Equals: []string{"store_id", "status"}, Sort: []string{"id"}},
+		Uses:    1000,
+	}}
+	indexes := []domain.IndexStats{
+		{Table: "products", Index: "idx_products_store_status", Columns: []string{"store_id", "status", "id", "name"}},
+	}
+
+	assert.Empty(t, Advise(usage, indexes, 1), "a wider index with the equality columns swapped still serves the pattern")
+}
+
+type stubIndexSource struct {
+	indexes []domain.IndexStats
+	err     error
+}
+
+func (s stubIndexSource) IndexStats(ctx context.Context) ([]domain.IndexStats, error) {
+	return s.indexes, s.err
+}
+
+func TestAdvisor_Report(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker()
+	tracker.Record(byStore)
+
+	advisor := NewAdvisor(tracker, stubIndexSource{}, 1, clock.NewFake(now))
+
+	report, err := advisor.Report(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, now, report.GeneratedAt)
+	assert.Len(t, report.Patterns, 1)
+	require.Len(t, report.Advice, 1)
+	assert.Equal(t, "idx_products_store_id_id", report.Advice[0].Index)
+
+	_, err = NewAdvisor(tracker, stubIndexSource{err: errors.New("permission denied")}, 1, clock.Real()).Report(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,219 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"github.com/sirupsen/logrus"
+)
+
+const notifyTimeout = 10 * time.Second
+
+type Config struct {
+	// MaxFailures locks an identity after this many failed logins within
+	// Window.
+	MaxFailures int64
+	// MaxIPFailures locks a client IP after this many failed logins within
+	// Window, across all identities it tried.
+	MaxIPFailures int64
+	Window        time.Duration
+	// LockoutDuration is how long a locked identity or IP is refused.
+	LockoutDuration time.Duration
+	// BaseDelay is the delay before checking a login after the first
+	// failure; it doubles with each further failure up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// Store holds failure counters in fixed windows. ratelimit.RedisStore
+// shares them between instances; ratelimit.MemoryStore keeps them in this
+// process.
+type Store interface {
+	Consume(ctx context.Context, key string, units int64, window time.Duration) (used int64, resetAt time.Time, err error)
+	Reset(ctx context.Context, key string) error
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, lockout domain.Lockout) error
+}
+
+// Metrics counts login attempts seen by the guard.
+type Metrics struct {
+	Failures         int64 `json:"failures"`
+	Rejected         int64 `json:"rejected"`
+	Delayed          int64 `json:"delayed"`
+	IdentityLockouts int64 `json:"identity_lockouts"`
+	IPLockouts       int64 `json:"ip_lockouts"`
+}
+
+// Guard protects login endpoints against credential brute forcing. It
+// tracks failed attempts per identity and per client IP, slows down
+// repeated failures and locks a subject out once it crosses its limit.
+type Guard struct {
+	store     Store
+	cfg       Config
+	notifiers []Notifier
+	logger    *logrus.Logger
+	sleep     func(ctx context.Context, d time.Duration) error
+	clock     clock.Clock
+
+	mu      sync.Mutex
+	metrics Metrics
+}
+
+func NewGuard(store Store, cfg Config, notifiers []Notifier, clk clock.Clock, logger *logrus.Logger) *Guard {
+	return &Guard{
+		store:     store,
+		cfg:       cfg,
+		notifiers: notifiers,
+		logger:    logger,
+		sleep:     sleep,
+		clock:     clk,
+	}
+}
+
+// Check is called before verifying a login. It returns a
+// *domain.LoginLockedError while the identity or IP is locked out and
+// otherwise waits out the progressive delay earned by earlier failures.
+func (g *Guard) Check(ctx context.Context, identity, clientIP string) error {
+	for _, key := range []string{lockKey(domain.LockoutSubjectIdentity, identity), lockKey(domain.LockoutSubjectIP, clientIP)} {
+		locked, resetAt, err := g.store.Consume(ctx, key, 0, g.cfg.LockoutDuration)
+		if err != nil {
+			return err
+		}
+		if locked > 0 {
+			g.record(func(m *Metrics) { m.Rejected++ })
+			return &domain.LoginLockedError{RetryAfter: resetAt.Sub(g.clock.Now())}
+		}
+	}
+
+	failures, _, err := g.store.Consume(ctx, failureKey(domain.LockoutSubjectIdentity, identity), 0, g.cfg.Window)
+	if err != nil {
+		return err
+	}
+	if delay := g.delay(failures); delay > 0 {
+		g.record(func(m *Metrics) { m.Delayed++ })
+		return g.sleep(ctx, delay)
+	}
+	return nil
+}
+
+// Failure records a failed login and locks the identity or IP out when it
+// reaches its limit.
+func (g *Guard) Failure(ctx context.Context, identity, clientIP string) {
+	g.record(func(m *Metrics) { m.Failures++ })
+
+	g.fail(ctx, domain.LockoutSubjectIdentity, identity, g.cfg.MaxFailures)
+	g.fail(ctx, domain.LockoutSubjectIP, clientIP, g.cfg.MaxIPFailures)
+}
+
+// Success clears the identity's failures. The IP's are kept so one valid
+// account cannot be used to reset the counter while guessing others.
+func (g *Guard) Success(ctx context.Context, identity string) {
+	if err := g.store.Reset(ctx, failureKey(domain.LockoutSubjectIdentity, identity)); err != nil {
+		g.logger.WithError(err).Warn("Failed to reset login failures")
+	}
+}
+
+func (g *Guard) Metrics() Metrics {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.metrics
+}
+
+func (g *Guard) fail(ctx context.Context, subject, key string, limit int64) {
+	failures, _, err := g.store.Consume(ctx, failureKey(subject, key), 1, g.cfg.Window)
+	if err != nil {
+		g.logger.WithError(err).Warn("Failed to record login failure")
+		return
+	}
+	if limit <= 0 || failures < limit {
+		return
+	}
+
+	// The lock window must start now, not when an earlier Check first
+	// touched the key, so drop any stale window before locking.
+	g.store.Reset(ctx, failureKey(subject, key))
+	g.store.Reset(ctx, lockKey(subject, key))
+	_, lockedUntil, err := g.store.Consume(ctx, lockKey(subject, key), 1, g.cfg.LockoutDuration)
+	if err != nil {
+		g.logger.WithError(err).Warn("Failed to lock out login")
+		return
+	}
+
+	if subject == domain.LockoutSubjectIP {
+		g.record(func(m *Metrics) { m.IPLockouts++ })
+	} else {
+		g.record(func(m *Metrics) { m.IdentityLockouts++ })
+	}
+
+	lockout := domain.Lockout{
+		Subject:     subject,
+		Key:         key,
+		Failures:    failures,
+		LockedUntil: lockedUntil,
+	}
+	g.logger.WithFields(logrus.Fields{
+		"action":       "login_lockout",
+		"subject":      subject,
+		"key":          key,
+		"failures":     failures,
+		"locked_until": lockedUntil,
+	}).Warn("Login locked out after repeated failures")
+
+	for _, notifier := range g.notifiers {
+		go func(n Notifier) {
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			defer cancel()
+			if err := n.Notify(ctx, lockout); err != nil {
+				g.logger.WithError(err).Warn("Failed to send lockout notification")
+			}
+		}(notifier)
+	}
+}
+
+func (g *Guard) delay(failures int64) time.Duration {
+	if failures <= 0 || g.cfg.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := g.cfg.BaseDelay
+	for i := int64(1); i < failures && delay < g.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	if g.cfg.MaxDelay > 0 && delay > g.cfg.MaxDelay {
+		delay = g.cfg.MaxDelay
+	}
+	return delay
+}
+
+func (g *Guard) record(update func(*Metrics)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	update(&g.metrics)
+}
+
+func failureKey(subject, key string) string {
+	return "login:failures:" + subject + ":" + key
+}
+
+func lockKey(subject, key string) string {
+	return "login:locked:" + subject + ":" + key
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
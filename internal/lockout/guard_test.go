@@ -0,0 +1,143 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/ratelimit"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	mu       sync.Mutex
+	lockouts []domain.Lockout
+	done     chan struct{}
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, lockout domain.Lockout) error {
+	n.mu.Lock()
+	n.lockouts = append(n.lockouts, lockout)
+	n.mu.Unlock()
+	n.done <- struct{}{}
+	return nil
+}
+
+func newTestGuard(notifiers ...Notifier) (*Guard, *[]time.Duration) {
+	return newTestGuardWithStore(ratelimit.NewMemoryStore(clock.Real()), notifiers...)
+}
+
+func newTestGuardWithStore(store Store, notifiers ...Notifier) (*Guard, *[]time.Duration) {
+	guard := NewGuard(store, Config{
+		MaxFailures:     3,
+		MaxIPFailures:   5,
+		Window:          time.Minute,
+		LockoutDuration: time.Minute,
+		BaseDelay:       100 * time.Millisecond,
+		MaxDelay:        300 * time.Millisecond,
+	}, notifiers, clock.Real(), logrus.New())
+
+	var delays []time.Duration
+	guard.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return guard, &delays
+}
+
+func TestGuard_LocksIdentity(t *testing.T) {
+	ctx := context.Background()
+	notifier := &recordingNotifier{done: make(chan struct{}, 1)}
+	guard, delays := newTestGuard(notifier)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, guard.Check(ctx, "key:abc", "10.0.0.1"))
+		guard.Failure(ctx, "key:abc", "10.0.0.1")
+	}
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *delays, "delays double per failure")
+
+	err := guard.Check(ctx, "key:abc", "10.0.0.2")
+	var locked *domain.LoginLockedError
+	require.True(t, errors.As(err, &locked))
+	assert.ErrorIs(t, err, domain.ErrLoginLocked)
+	assert.InDelta(t, time.Minute.Seconds(), locked.RetryAfter.Seconds(), 1)
+
+	assert.NoError(t, guard.Check(ctx, "key:other", "10.0.0.1"), "the IP is still under its limit")
+
+	<-notifier.done
+	require.Len(t, notifier.lockouts, 1)
+	assert.Equal(t, domain.LockoutSubjectIdentity, notifier.lockouts[0].Subject)
+	assert.Equal(t, "key:abc", notifier.lockouts[0].Key)
+
+	metrics := guard.Metrics()
+	assert.Equal(t, int64(3), metrics.Failures)
+	assert.Equal(t, int64(1), metrics.IdentityLockouts)
+	assert.Equal(t, int64(1), metrics.Rejected)
+}
+
+func TestGuard_SharedStore(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	first, _ := newTestGuardWithStore(ratelimit.NewRedisStore(client, "test:", clock.Real()))
+	second, _ := newTestGuardWithStore(ratelimit.NewRedisStore(client, "test:", clock.Real()))
+
+	// Spreading guesses over instances does not reset the count.
+	first.Failure(ctx, "key:abc", "10.0.0.1")
+	second.Failure(ctx, "key:abc", "10.0.0.2")
+	first.Failure(ctx, "key:abc", "10.0.0.3")
+
+	err := second.Check(ctx, "key:abc", "10.0.0.4")
+	assert.ErrorIs(t, err, domain.ErrLoginLocked)
+
+	server.FastForward(time.Minute)
+	assert.NoError(t, second.Check(ctx, "key:abc", "10.0.0.4"), "the lockout expires in Redis")
+}
+
+func TestGuard_LocksIP(t *testing.T) {
+	ctx := context.Background()
+	guard, _ := newTestGuard()
+
+	// Spreading guesses over identities still trips the per-IP limit.
+	for i := 0; i < 5; i++ {
+		identity := "key:" + string(rune('a'+i))
+		require.NoError(t, guard.Check(ctx, identity, "10.0.0.1"))
+		guard.Failure(ctx, identity, "10.0.0.1")
+	}
+
+	assert.ErrorIs(t, guard.Check(ctx, "key:new", "10.0.0.1"), domain.ErrLoginLocked)
+	assert.NoError(t, guard.Check(ctx, "key:new", "10.0.0.2"))
+	assert.Equal(t, int64(1), guard.Metrics().IPLockouts)
+}
+
+func TestGuard_SuccessResetsIdentity(t *testing.T) {
+	ctx := context.Background()
+	guard, delays := newTestGuard()
+
+	guard.Failure(ctx, "key:abc", "10.0.0.1")
+	guard.Failure(ctx, "key:abc", "10.0.0.1")
+	guard.Success(ctx, "key:abc")
+	guard.Failure(ctx, "key:abc", "10.0.0.1")
+
+	require.NoError(t, guard.Check(ctx, "key:abc", "10.0.0.1"))
+	assert.Equal(t, []time.Duration{100 * time.Millisecond}, *delays)
+}
+
+func TestGuard_DelayIsCapped(t *testing.T) {
+	guard, _ := newTestGuard()
+
+	assert.Equal(t, time.Duration(0), guard.delay(0))
+	assert.Equal(t, 100*time.Millisecond, guard.delay(1))
+	assert.Equal(t, 300*time.Millisecond, guard.delay(10))
+}
@@ -0,0 +1,34 @@
+package metering
+
+import (
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// usageEvent is a usage record as the billing pipeline receives it. The
+// period is [period_start, period_end); api_calls is the total for the
+// period, and product_count and storage_bytes what the store held when the
+// period was closed.
+type usageEvent struct {
+	EventID      string    `json:"event_id"`
+	StoreID      int64     `json:"store_id"`
+	PeriodStart  time.Time `json:"period_start"`
+	PeriodEnd    time.Time `json:"period_end"`
+	APICalls     int64     `json:"api_calls"`
+	StorageBytes int64     `json:"storage_bytes"`
+	ProductCount int64     `json:"product_count"`
+}
+
+func newUsageEvent(record *domain.UsageRecord) usageEvent {
+	start := record.Hour.UTC()
+	return usageEvent{
+		EventID:      record.EventID(),
+		StoreID:      record.StoreID,
+		PeriodStart:  start,
+		PeriodEnd:    start.Add(time.Hour),
+		APICalls:     record.APICalls,
+		StorageBytes: record.StorageBytes,
+		ProductCount: record.ProductCount,
+	}
+}
@@ -0,0 +1,63 @@
+package metering
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaSink publishes each usage event as a JSON message to a Kafka topic,
+// keyed by store so a store's events stay in order. A batch is
+// acknowledged once every in-sync replica has written it.
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}}
+}
+
+func (s *KafkaSink) Name() string {
+	return "kafka"
+}
+
+func (s *KafkaSink) Send(ctx context.Context, records []*domain.UsageRecord) error {
+	messages, err := kafkaMessages(records)
+	if err != nil {
+		return err
+	}
+	if err := s.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("write usage events to kafka: %w", err)
+	}
+	return nil
+}
+
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
+
+func kafkaMessages(records []*domain.UsageRecord) ([]kafka.Message, error) {
+	messages := make([]kafka.Message, 0, len(records))
+	for _, record := range records {
+		value, err := json.Marshal(newUsageEvent(record))
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, kafka.Message{
+			Key:   []byte(strconv.FormatInt(record.StoreID, 10)),
+			Value: value,
+			Time:  record.RecordedAt,
+		})
+	}
+	return messages, nil
+}
@@ -0,0 +1,183 @@
+package metering
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxCatchUpHours caps the hours closed at once after the meter was not
+// running. Older hours are left for reconciliation to report, since the
+// products and storage they record would be today's rather than theirs.
+const maxCatchUpHours = 24
+
+type Config struct {
+	// Interval is how often hours are closed and records emitted.
+	Interval time.Duration
+	// Grace is how long after an hour ends it is closed, leaving every
+	// instance time to write the calls it served in it.
+	Grace time.Duration
+	// BatchSize caps the records sent to the sink at once.
+	BatchSize int
+	// ClaimTTL is how long records being sent are held from other
+	// instances; a send that takes longer may be repeated.
+	ClaimTTL time.Duration
+}
+
+// RecordStore keeps the hourly usage records and whether they were
+// emitted.
+type RecordStore interface {
+	// LatestHour returns the latest hour closed, or the zero time when no
+	// hour was.
+	LatestHour(ctx context.Context) (time.Time, error)
+	// RecordHour closes hour, recording each store's calls in it along
+	// with its products and storage now, and returns how many records it
+	// created. Stores whose hour is already closed are left as they are.
+	RecordHour(ctx context.Context, hour time.Time) (int64, error)
+	// ClaimPending claims up to limit records that were not emitted and
+	// are not claimed by another instance at now, holding them until
+	// until.
+	ClaimPending(ctx context.Context, limit int, now, until time.Time) ([]*domain.UsageRecord, error)
+	MarkEmitted(ctx context.Context, records []*domain.UsageRecord, at time.Time) error
+	ReleaseClaims(ctx context.Context, records []*domain.UsageRecord) error
+}
+
+// Sink delivers usage records to the billing pipeline. Send must only
+// return nil once the pipeline has accepted the whole batch.
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, records []*domain.UsageRecord) error
+}
+
+// Meter closes each hour once its grace period has passed and emits the
+// records to the sink with at-least-once delivery: a record is only marked
+// emitted after the sink accepts it, so a failed send is repeated on the
+// next tick. The pipeline drops repeats by the records' event IDs.
+type Meter struct {
+	store  RecordStore
+	sink   Sink
+	cfg    Config
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	// closedThrough is the latest hour this meter closed or found closed.
+	closedThrough time.Time
+
+	records *telemetry.Counter
+}
+
+func NewMeter(store RecordStore, sink Sink, registry *telemetry.Registry, cfg Config, clk clock.Clock, logger *logrus.Logger) *Meter {
+	return &Meter{
+		store:   store,
+		sink:    sink,
+		cfg:     cfg,
+		logger:  logger,
+		clock:   clk,
+		records: registry.NewCounter("usage_records_emitted_total", "Hourly usage records sent to the billing pipeline, by result.", "result"),
+	}
+}
+
+// Run closes hours and emits records every Interval until ctx is
+// cancelled.
+func (m *Meter) Run(ctx context.Context) {
+	m.logger.WithFields(logrus.Fields{
+		"interval": m.cfg.Interval,
+		"grace":    m.cfg.Grace,
+		"sink":     m.sink.Name(),
+	}).Info("Usage meter started")
+
+	ticker := m.clock.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info("Usage meter stopped")
+			return
+		case <-ticker.C():
+			if err := m.Tick(ctx); err != nil && ctx.Err() == nil {
+				m.logger.WithError(err).Error("Failed to meter usage")
+			}
+		}
+	}
+}
+
+// Tick closes the hours whose grace period has passed, then emits every
+// record not yet emitted.
+func (m *Meter) Tick(ctx context.Context) error {
+	if err := m.closeHours(ctx); err != nil {
+		return err
+	}
+	return m.emit(ctx)
+}
+
+func (m *Meter) closeHours(ctx context.Context) error {
+	last := startOfHour(m.clock.Now().Add(-m.cfg.Grace)).Add(-time.Hour)
+
+	if m.closedThrough.IsZero() {
+		latest, err := m.store.LatestHour(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get latest usage hour: %w", err)
+		}
+		if latest.IsZero() {
+			latest = last.Add(-time.Hour)
+		}
+		m.closedThrough = latest.UTC()
+	}
+
+	first := m.closedThrough.Add(time.Hour)
+	if oldest := last.Add(-(maxCatchUpHours - 1) * time.Hour); first.Before(oldest) {
+		m.logger.WithFields(logrus.Fields{"from": first, "to": oldest}).Warn("Usage hours were not closed in time and are skipped")
+		first = oldest
+	}
+
+	for hour := first; !hour.After(last); hour = hour.Add(time.Hour) {
+		created, err := m.store.RecordHour(ctx, hour)
+		if err != nil {
+			return fmt.Errorf("failed to close usage hour %s: %w", hour.Format(time.RFC3339), err)
+		}
+		m.closedThrough = hour
+		m.logger.WithFields(logrus.Fields{"hour": hour, "records": created}).Info("Usage hour closed")
+	}
+	return nil
+}
+
+func (m *Meter) emit(ctx context.Context) error {
+	for {
+		now := m.clock.Now()
+		records, err := m.store.ClaimPending(ctx, m.cfg.BatchSize, now, now.Add(m.cfg.ClaimTTL))
+		if err != nil {
+			return fmt.Errorf("failed to claim usage records: %w", err)
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		if err := m.sink.Send(ctx, records); err != nil {
+			m.records.Add(float64(len(records)), "failed")
+			// Released records are retried on the next tick rather than
+			// once the claim runs out.
+			if releaseErr := m.store.ReleaseClaims(context.WithoutCancel(ctx), records); releaseErr != nil {
+				m.logger.WithError(releaseErr).Warn("Failed to release usage record claims")
+			}
+			return fmt.Errorf("failed to send usage records to %s: %w", m.sink.Name(), err)
+		}
+		// The sink has the records now, so marking them must not be cut
+		// short; a record left unmarked is sent again.
+		if err := m.store.MarkEmitted(context.WithoutCancel(ctx), records, m.clock.Now()); err != nil {
+			return fmt.Errorf("failed to mark usage records emitted: %w", err)
+		}
+		m.records.Add(float64(len(records)), "emitted")
+
+		// A full batch means more are waiting; keep going.
+		if len(records) < m.cfg.BatchSize {
+			return nil
+		}
+	}
+}
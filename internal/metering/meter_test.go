@@ -0,0 +1,224 @@
+package metering
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRecordStore closes hours for store 1 and claims records the way
+// UsageRepository does in Postgres.
+type fakeRecordStore struct {
+	records  []*domain.UsageRecord
+	claimed  map[string]bool
+	closed   []time.Time
+	closeErr error
+}
+
+func (s *fakeRecordStore) LatestHour(ctx context.Context) (time.Time, error) {
+	var latest time.Time
+	for _, record := range s.records {
+		if record.Hour.After(latest) {
+			latest = record.Hour
+		}
+	}
+	return latest, nil
+}
+
+func (s *fakeRecordStore) RecordHour(ctx context.Context, hour time.Time) (int64, error) {
+	if s.closeErr != nil {
+		return 0, s.closeErr
+	}
+	s.closed = append(s.closed, hour)
+	for _, record := range s.records {
+		if record.Hour.Equal(hour) {
+			return 0, nil
+		}
+	}
+	s.records = append(s.records, &domain.UsageRecord{StoreID: 1, Hour: hour, APICalls: 10})
+	return 1, nil
+}
+
+func (s *fakeRecordStore) ClaimPending(ctx context.Context, limit int, now, until time.Time) ([]*domain.UsageRecord, error) {
+	if s.claimed == nil {
+		s.claimed = make(map[string]bool)
+	}
+	var claimed []*domain.UsageRecord
+	for _, record := range s.records {
+		if len(claimed) == limit {
+			break
+		}
+		if record.EmittedAt == nil && !s.claimed[record.EventID()] {
+			s.claimed[record.EventID()] = true
+			claimed = append(claimed, record)
+		}
+	}
+	return claimed, nil
+}
+
+func (s *fakeRecordStore) MarkEmitted(ctx context.Context, records []*domain.UsageRecord, at time.Time) error {
+	for _, record := range records {
+		record.EmittedAt = &at
+		delete(s.claimed, record.EventID())
+	}
+	return nil
+}
+
+func (s *fakeRecordStore) ReleaseClaims(ctx context.Context, records []*domain.UsageRecord) error {
+	for _, record := range records {
+		delete(s.claimed, record.EventID())
+	}
+	return nil
+}
+
+type fakeSink struct {
+	sent    []string
+	sendErr error
+}
+
+func (s *fakeSink) Name() string {
+	return "fake"
+}
+
+func (s *fakeSink) Send(ctx context.Context, records []*domain.UsageRecord) error {
+	if s.sendErr != nil {
+		return s.sendErr
+	}
+	for _, record := range records {
+		s.sent = append(s.sent, record.EventID())
+	}
+	return nil
+}
+
+func newTestMeter(store *fakeRecordStore, sink *fakeSink, clk clock.Clock) *Meter {
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	cfg := Config{Interval: time.Minute, Grace: 5 * time.Minute, BatchSize: 2, ClaimTTL: time.Minute}
+	return NewMeter(store, sink, registry, cfg, clk, logrus.New())
+}
+
+func TestMeter_ClosesHourAfterGrace(t *testing.T) {
+	store := &fakeRecordStore{}
+	sink := &fakeSink{}
+	clk := clock.NewFake(hour.Add(time.Hour + 4*time.Minute))
+	meter := newTestMeter(store, sink, clk)
+
+	require.NoError(t, meter.Tick(context.Background()))
+	assert.Equal(t, []time.Time{hour.Add(-time.Hour)}, store.closed, "the last hour is still in grace")
+
+	clk.Advance(time.Minute)
+	require.NoError(t, meter.Tick(context.Background()))
+	require.NoError(t, meter.Tick(context.Background()))
+
+	assert.Equal(t, []time.Time{hour.Add(-time.Hour), hour}, store.closed)
+	assert.Equal(t, []string{"usage-1-2026030108", "usage-1-2026030109"}, sink.sent)
+}
+
+func TestMeter_CatchesUpFromLatestHour(t *testing.T) {
+	store := &fakeRecordStore{records: []*domain.UsageRecord{{StoreID: 1, Hour: hour, EmittedAt: &hour}}}
+	sink := &fakeSink{}
+	meter := newTestMeter(store, sink, clock.NewFake(hour.Add(4*time.Hour+10*time.Minute)))
+
+	require.NoError(t, meter.Tick(context.Background()))
+
+	assert.Equal(t, []time.Time{hour.Add(time.Hour), hour.Add(2 * time.Hour), hour.Add(3 * time.Hour)}, store.closed)
+	assert.Len(t, sink.sent, 3, "records are sent in full batches until none are left")
+}
+
+func TestMeter_CapsCatchUp(t *testing.T) {
+	store := &fakeRecordStore{records: []*domain.UsageRecord{{StoreID: 1, Hour: hour, EmittedAt: &hour}}}
+	meter := newTestMeter(store, &fakeSink{}, clock.NewFake(hour.Add(72*time.Hour+10*time.Minute)))
+
+	require.NoError(t, meter.Tick(context.Background()))
+
+	require.Len(t, store.closed, maxCatchUpHours)
+	assert.Equal(t, hour.Add(71*time.Hour), store.closed[len(store.closed)-1])
+}
+
+func TestMeter_ResendsAfterFailedSend(t *testing.T) {
+	store := &fakeRecordStore{records: []*domain.UsageRecord{{StoreID: 1, Hour: hour}}}
+	sink := &fakeSink{sendErr: errors.New("pipeline down")}
+	meter := newTestMeter(store, sink, clock.NewFake(hour.Add(time.Hour+10*time.Minute)))
+
+	require.Error(t, meter.Tick(context.Background()))
+	assert.Nil(t, store.records[0].EmittedAt)
+	assert.Empty(t, store.claimed, "a failed send releases its claims")
+
+	sink.sendErr = nil
+	require.NoError(t, meter.Tick(context.Background()))
+
+	assert.Equal(t, []string{"usage-1-2026030109"}, sink.sent)
+	assert.NotNil(t, store.records[0].EmittedAt)
+}
+
+func TestMeter_StopsWhenHourFailsToClose(t *testing.T) {
+	store := &fakeRecordStore{records: []*domain.UsageRecord{{StoreID: 1, Hour: hour}}, closeErr: errors.New("database down")}
+	sink := &fakeSink{}
+	meter := newTestMeter(store, sink, clock.NewFake(hour.Add(3*time.Hour)))
+
+	require.Error(t, meter.Tick(context.Background()))
+	assert.Empty(t, sink.sent)
+
+	store.closeErr = nil
+	require.NoError(t, meter.Tick(context.Background()))
+	assert.Equal(t, []time.Time{hour.Add(time.Hour)}, store.closed, "the failed hour is closed on the next tick")
+}
+
+func TestHTTPSink_Send(t *testing.T) {
+	var got httpBatch
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.Client(), server.URL, "secret")
+	err := sink.Send(context.Background(), []*domain.UsageRecord{{StoreID: 4, Hour: hour, APICalls: 12, StorageBytes: 2048, ProductCount: 3}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret", auth)
+	require.Len(t, got.Events, 1)
+	assert.Equal(t, usageEvent{
+		EventID:      "usage-4-2026030109",
+		StoreID:      4,
+		PeriodStart:  hour,
+		PeriodEnd:    hour.Add(time.Hour),
+		APICalls:     12,
+		StorageBytes: 2048,
+		ProductCount: 3,
+	}, got.Events[0])
+}
+
+func TestHTTPSink_RejectedBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.Client(), server.URL, "")
+	assert.Error(t, sink.Send(context.Background(), []*domain.UsageRecord{{StoreID: 1, Hour: hour}}))
+}
+
+func TestKafkaMessages(t *testing.T) {
+	recorded := hour.Add(65 * time.Minute)
+	messages, err := kafkaMessages([]*domain.UsageRecord{{StoreID: 7, Hour: hour, APICalls: 5, RecordedAt: recorded}})
+
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "7", string(messages[0].Key))
+	assert.Equal(t, recorded, messages[0].Time)
+	assert.Contains(t, string(messages[0].Value), `"event_id":"usage-7-2026030109"`)
+}
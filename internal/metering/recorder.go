@@ -0,0 +1,157 @@
+// Package metering measures each store's billable usage and emits it to
+// the billing pipeline by the hour: the API calls the service served for
+// the store, and the products and storage it holds.
+package metering
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+type RecorderConfig struct {
+	// FlushInterval is how often recorded calls are written.
+	FlushInterval time.Duration
+	// MaxPending caps the counts held in memory between writes.
+	MaxPending int
+}
+
+// CallStore persists API call counts.
+type CallStore interface {
+	// RecordCalls adds the calls to those already stored for the same
+	// store and hour.
+	RecordCalls(ctx context.Context, calls []domain.UsageCalls) error
+}
+
+type callKey struct {
+	storeID int64
+	hour    time.Time
+}
+
+// Recorder tallies API calls per store and hour in memory and writes the
+// counts every FlushInterval, so requests never wait on the database. When
+// MaxPending counts are held, calls that would add another are dropped.
+type Recorder struct {
+	store  CallStore
+	cfg    RecorderConfig
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	pending  map[callKey]*domain.UsageCalls
+	dropping bool
+
+	calls *telemetry.Counter
+}
+
+func NewRecorder(store CallStore, registry *telemetry.Registry, cfg RecorderConfig, clk clock.Clock, logger *logrus.Logger) *Recorder {
+	return &Recorder{
+		store:   store,
+		cfg:     cfg,
+		logger:  logger,
+		clock:   clk,
+		pending: make(map[callKey]*domain.UsageCalls),
+		calls:   registry.NewCounter("usage_api_calls_total", "Billable API calls metered, by result.", "result"),
+	}
+}
+
+// RecordCall counts one API call for the store in the current hour.
+func (r *Recorder) RecordCall(storeID int64) {
+	key := callKey{storeID: storeID, hour: startOfHour(r.clock.Now())}
+
+	r.mu.Lock()
+	count, ok := r.pending[key]
+	if !ok {
+		if len(r.pending) >= r.cfg.MaxPending {
+			warn := !r.dropping
+			r.dropping = true
+			r.mu.Unlock()
+
+			r.calls.Inc("dropped")
+			if warn {
+				r.logger.WithField("max_pending", r.cfg.MaxPending).Warn("Usage call buffer is full, dropping calls")
+			}
+			return
+		}
+		count = &domain.UsageCalls{StoreID: key.storeID, Hour: key.hour}
+		r.pending[key] = count
+	}
+	count.Calls++
+	r.mu.Unlock()
+
+	r.calls.Inc("recorded")
+}
+
+// Run writes recorded calls every FlushInterval until ctx is cancelled,
+// then writes what is left.
+func (r *Recorder) Run(ctx context.Context) {
+	r.logger.WithField("interval", r.cfg.FlushInterval).Info("Usage recorder started")
+
+	ticker := r.clock.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := r.Flush(context.WithoutCancel(ctx)); err != nil {
+				r.logger.WithError(err).Error("Failed to write usage calls on shutdown")
+			}
+			r.logger.Info("Usage recorder stopped")
+			return
+		case <-ticker.C():
+			if err := r.Flush(ctx); err != nil && ctx.Err() == nil {
+				r.logger.WithError(err).Error("Failed to write usage calls")
+			}
+		}
+	}
+}
+
+// Flush writes the recorded calls. On failure they are kept for the next
+// flush.
+func (r *Recorder) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	flushed := r.pending
+	r.pending = make(map[callKey]*domain.UsageCalls)
+	r.dropping = false
+	r.mu.Unlock()
+
+	if len(flushed) == 0 {
+		return nil
+	}
+
+	calls := make([]domain.UsageCalls, 0, len(flushed))
+	for _, count := range flushed {
+		calls = append(calls, *count)
+	}
+	if err := r.store.RecordCalls(ctx, calls); err != nil {
+		r.requeue(flushed)
+		return fmt.Errorf("failed to record usage calls: %w", err)
+	}
+	return nil
+}
+
+// requeue merges calls that failed to be written back into those recorded
+// since.
+func (r *Recorder) requeue(flushed map[callKey]*domain.UsageCalls) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, count := range flushed {
+		if current, ok := r.pending[key]; ok {
+			current.Calls += count.Calls
+			continue
+		}
+		r.pending[key] = count
+	}
+}
+
+func startOfHour(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Hour)
+}
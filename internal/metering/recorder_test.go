@@ -0,0 +1,127 @@
+package metering
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCallStore adds up calls the way UsageRepository does in Postgres.
+type fakeCallStore struct {
+	mu        sync.Mutex
+	calls     map[callKey]int64
+	recordErr error
+}
+
+func (s *fakeCallStore) RecordCalls(ctx context.Context, calls []domain.UsageCalls) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.recordErr != nil {
+		return s.recordErr
+	}
+	if s.calls == nil {
+		s.calls = make(map[callKey]int64)
+	}
+	for _, count := range calls {
+		s.calls[callKey{count.StoreID, count.Hour}] += count.Calls
+	}
+	return nil
+}
+
+func (s *fakeCallStore) get(storeID int64, hour time.Time) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[callKey{storeID, hour}]
+}
+
+var hour = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func newTestRecorder(store *fakeCallStore, clk clock.Clock, maxPending int) (*Recorder, *telemetry.Registry) {
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	cfg := RecorderConfig{FlushInterval: time.Second, MaxPending: maxPending}
+	return NewRecorder(store, registry, cfg, clk, logrus.New()), registry
+}
+
+func metrics(t *testing.T, registry *telemetry.Registry) string {
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	return buf.String()
+}
+
+func TestRecorder_TalliesPerStoreAndHour(t *testing.T) {
+	store := &fakeCallStore{}
+	clk := clock.NewFake(hour.Add(59 * time.Minute))
+	recorder, registry := newTestRecorder(store, clk, 100)
+
+	recorder.RecordCall(1)
+	recorder.RecordCall(1)
+	recorder.RecordCall(2)
+	clk.Advance(time.Minute)
+	recorder.RecordCall(1)
+	require.NoError(t, recorder.Flush(context.Background()))
+
+	assert.Equal(t, int64(2), store.get(1, hour))
+	assert.Equal(t, int64(1), store.get(2, hour))
+	assert.Equal(t, int64(1), store.get(1, hour.Add(time.Hour)))
+	assert.Contains(t, metrics(t, registry), `usage_api_calls_total{result="recorded"} 4`)
+}
+
+func TestRecorder_KeepsCallsWhenWriteFails(t *testing.T) {
+	store := &fakeCallStore{recordErr: errors.New("database down")}
+	clk := clock.NewFake(hour)
+	recorder, _ := newTestRecorder(store, clk, 100)
+
+	recorder.RecordCall(1)
+	require.Error(t, recorder.Flush(context.Background()))
+
+	store.recordErr = nil
+	recorder.RecordCall(1)
+	require.NoError(t, recorder.Flush(context.Background()))
+
+	assert.Equal(t, int64(2), store.get(1, hour))
+}
+
+func TestRecorder_DropsCallsBeyondMaxPending(t *testing.T) {
+	store := &fakeCallStore{}
+	clk := clock.NewFake(hour)
+	recorder, registry := newTestRecorder(store, clk, 1)
+
+	recorder.RecordCall(1)
+	recorder.RecordCall(2)
+	recorder.RecordCall(1)
+	require.NoError(t, recorder.Flush(context.Background()))
+
+	assert.Equal(t, int64(2), store.get(1, hour))
+	assert.Equal(t, int64(0), store.get(2, hour))
+	assert.Contains(t, metrics(t, registry), `usage_api_calls_total{result="dropped"} 1`)
+}
+
+func TestRecorder_FlushesOnShutdown(t *testing.T) {
+	store := &fakeCallStore{}
+	recorder, _ := newTestRecorder(store, clock.NewFake(hour), 100)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		recorder.Run(ctx)
+	}()
+
+	recorder.RecordCall(3)
+	cancel()
+	<-done
+
+	assert.Equal(t, int64(1), store.get(3, hour))
+}
@@ -0,0 +1,47 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// Blocklist rejects products whose name or description contains a blocked
+// term. Matching is case-insensitive and works on whole words, so "ass" does
+// not match "glass"; multi-word terms match as phrases.
+type Blocklist struct {
+	terms []string
+}
+
+func NewBlocklist(terms []string) *Blocklist {
+	b := &Blocklist{}
+	for _, term := range terms {
+		if normalized := normalize(term); normalized != "" {
+			b.terms = append(b.terms, normalized)
+		}
+	}
+	return b
+}
+
+func (b *Blocklist) Moderate(ctx context.Context, product *domain.Product) (*domain.ModerationResult, error) {
+	text := " " + normalize(product.Name+" "+product.Description.String) + " "
+	for _, term := range b.terms {
+		if strings.Contains(text, " "+term+" ") {
+			return &domain.ModerationResult{
+				Status: domain.ModerationStatusRejected,
+				Reason: fmt.Sprintf("contains blocked term %q", term),
+			}, nil
+		}
+	}
+	return &domain.ModerationResult{Status: domain.ModerationStatusApproved}, nil
+}
+
+func normalize(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(words, " ")
+}
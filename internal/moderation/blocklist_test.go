@@ -0,0 +1,40 @@
+package moderation
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlocklist_Moderate(t *testing.T) {
+	blocklist := NewBlocklist([]string{"Scam", "free money", " ", "ärger"})
+
+	tests := []struct {
+		name        string
+		productName string
+		description string
+		want        string
+	}{
+		{name: "clean", productName: "Glass vase", want: domain.ModerationStatusApproved},
+		{name: "case insensitive", productName: "Not a SCAM!", want: domain.ModerationStatusRejected},
+		{name: "whole words only", productName: "Scampi platter", want: domain.ModerationStatusApproved},
+		{name: "phrase across punctuation", productName: "Shirt", description: "Get free, money now", want: domain.ModerationStatusRejected},
+		{name: "unicode term", productName: "Kein Ärger", want: domain.ModerationStatusRejected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := blocklist.Moderate(context.Background(), &domain.Product{
+				Name:        tt.productName,
+				Description: sql.NullString{String: tt.description, Valid: tt.description != ""},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, result.Status)
+		})
+	}
+}
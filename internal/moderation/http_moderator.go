@@ -0,0 +1,73 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type moderationRequest struct {
+	ProductID   int64  `json:"product_id"`
+	StoreID     int64  `json:"store_id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type moderationResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// HTTPModerator asks an external moderation service for a verdict. The
+// service receives the product content as JSON and answers with
+// {"status": "approved|rejected|pending", "reason": "..."}.
+type HTTPModerator struct {
+	client *http.Client
+	url    string
+}
+
+func NewHTTPModerator(client *http.Client, url string) *HTTPModerator {
+	return &HTTPModerator{client: client, url: url}
+}
+
+func (m *HTTPModerator) Moderate(ctx context.Context, product *domain.Product) (*domain.ModerationResult, error) {
+	body, err := json.Marshal(moderationRequest{
+		ProductID:   product.ID,
+		StoreID:     product.StoreID,
+		Name:        product.Name,
+		Description: product.Description.String,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("moderation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("moderation service returned status %d", resp.StatusCode)
+	}
+
+	var verdict moderationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return nil, fmt.Errorf("failed to decode moderation response: %w", err)
+	}
+	if !domain.IsValidModerationStatus(verdict.Status) {
+		return nil, fmt.Errorf("moderation service returned unknown status %q", verdict.Status)
+	}
+
+	return &domain.ModerationResult{Status: verdict.Status, Reason: verdict.Reason}, nil
+}
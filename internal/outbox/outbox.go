@@ -0,0 +1,167 @@
+// Package outbox delivers product events at least once. A change writes
+// its events to an outbox table in the transaction that writes the change,
+// so either both are committed or neither is. A relay then publishes the
+// committed events and marks them sent. An event is only lost if its
+// change is; if the broker is down or the process dies, it waits in the
+// table. An event may be published twice when marking it sent fails, so
+// subscribers deduplicate by event ID.
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Store keeps the outbox.
+type Store interface {
+	// Add writes the event in the transaction ctx carries, if any.
+	Add(ctx context.Context, event domain.ProductEvent) error
+	// Relay passes the oldest pending events, up to limit, to fn, and marks
+	// them sent at sentAt if fn returns nil. It returns how many it passed.
+	// One relay at a time holds the pending events; Relay returns none to
+	// the others, so events are published in order.
+	Relay(ctx context.Context, limit int, sentAt time.Time, fn func(ctx context.Context, events []domain.ProductEvent) error) (int, error)
+}
+
+// Broker sends events to a message broker. Send must only return nil once
+// the broker has accepted the whole batch.
+type Broker interface {
+	Send(ctx context.Context, events []domain.ProductEvent) error
+}
+
+// Sink receives relayed events in this process, like events.Sink.
+type Sink interface {
+	Publish(ctx context.Context, event domain.ProductEvent)
+}
+
+// Writer is the publisher changes write their events through. It is an
+// events.Sink, so it goes behind the schema validation.
+type Writer struct {
+	store  Store
+	logger *logrus.Logger
+
+	written *telemetry.Counter
+}
+
+func NewWriter(store Store, registry *telemetry.Registry, logger *logrus.Logger) *Writer {
+	return &Writer{
+		store:   store,
+		logger:  logger,
+		written: registry.NewCounter("outbox_writes_total", "Product events written to the outbox by result.", "result"),
+	}
+}
+
+// Publish writes the event to the outbox. Inside a transaction a failed
+// write aborts it, so the change that raised the event is not committed
+// without it.
+func (w *Writer) Publish(ctx context.Context, event domain.ProductEvent) {
+	if err := w.store.Add(ctx, event); err != nil {
+		w.written.Inc("failed")
+		w.logger.WithContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"event_id":   event.ID,
+			"event_type": event.Type,
+		}).Error("Failed to write product event to the outbox")
+		return
+	}
+	w.written.Inc("written")
+}
+
+type Config struct {
+	// PollInterval is how often the relay looks for committed events.
+	PollInterval time.Duration
+	// BatchSize caps the events published at once. Zero uses
+	// DefaultBatchSize.
+	BatchSize int
+}
+
+const DefaultBatchSize = 100
+
+// Relay publishes committed events: to the broker first, then, once the
+// broker has them, to the sinks in this process. While the broker is down
+// events wait in the outbox and the sinks get none either, so every
+// subscriber sees them in the same order.
+type Relay struct {
+	store  Store
+	broker Broker
+	sinks  Sink
+	cfg    Config
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	relayed *telemetry.Counter
+}
+
+// NewRelay builds the relay. broker may be nil when events are only
+// published in this process.
+func NewRelay(store Store, broker Broker, sinks Sink, registry *telemetry.Registry, cfg Config, clk clock.Clock, logger *logrus.Logger) *Relay {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	return &Relay{
+		store:   store,
+		broker:  broker,
+		sinks:   sinks,
+		cfg:     cfg,
+		logger:  logger,
+		clock:   clk,
+		relayed: registry.NewCounter("outbox_relayed_total", "Product events relayed from the outbox by result.", "result"),
+	}
+}
+
+// Run relays events every PollInterval until ctx is cancelled. Events still
+// pending then are relayed by the next run.
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.WithField("interval", r.cfg.PollInterval).Info("Outbox relay started")
+
+	ticker := r.clock.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C():
+			if _, err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+				r.logger.WithError(err).Error("Failed to relay product events from the outbox")
+			}
+		}
+	}
+}
+
+// RelayPending relays batches until the outbox has no pending events, and
+// returns how many it relayed.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := r.store.Relay(ctx, r.cfg.BatchSize, r.clock.Now(), r.publish)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		// A full batch means more may be waiting.
+		if n < r.cfg.BatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (r *Relay) publish(ctx context.Context, events []domain.ProductEvent) error {
+	if r.broker != nil {
+		if err := r.broker.Send(ctx, events); err != nil {
+			r.relayed.Add(float64(len(events)), "failed")
+			return err
+		}
+	}
+	for _, event := range events {
+		r.sinks.Publish(ctx, event)
+	}
+	r.relayed.Add(float64(len(events)), "relayed")
+	return nil
+}
@@ -0,0 +1,198 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore keeps the outbox in a slice, as the postgres repository keeps
+// it in a table.
+type memoryStore struct {
+	mu     sync.Mutex
+	events []domain.ProductEvent
+	sentAt []time.Time
+	err    error
+}
+
+func (s *memoryStore) Add(_ context.Context, event domain.ProductEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	s.sentAt = append(s.sentAt, time.Time{})
+	return nil
+}
+
+func (s *memoryStore) Relay(ctx context.Context, limit int, sentAt time.Time, fn func(ctx context.Context, events []domain.ProductEvent) error) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []int
+	for i := range s.events {
+		if s.sentAt[i].IsZero() && len(pending) < limit {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	batch := make([]domain.ProductEvent, len(pending))
+	for j, i := range pending {
+		batch[j] = s.events[i]
+	}
+	if err := fn(ctx, batch); err != nil {
+		return 0, err
+	}
+	for _, i := range pending {
+		s.sentAt[i] = sentAt
+	}
+	return len(pending), nil
+}
+
+func (s *memoryStore) pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, sentAt := range s.sentAt {
+		if sentAt.IsZero() {
+			n++
+		}
+	}
+	return n
+}
+
+type recordingBroker struct {
+	mu      sync.Mutex
+	batches [][]domain.ProductEvent
+	err     error
+}
+
+func (b *recordingBroker) Send(_ context.Context, events []domain.ProductEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.err != nil {
+		return b.err
+	}
+	b.batches = append(b.batches, events)
+	return nil
+}
+
+type recordingSink struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (s *recordingSink) Publish(_ context.Context, event domain.ProductEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids = append(s.ids, event.ID)
+}
+
+func (s *recordingSink) published() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.ids...)
+}
+
+func newTestRegistry() *telemetry.Registry {
+	return telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+}
+
+func addEvents(t *testing.T, writer *Writer, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		writer.Publish(context.Background(), domain.ProductEvent{ID: id, Type: domain.ProductEventUpdated, ProductID: 1})
+	}
+}
+
+func TestRelay_PublishesToBrokerThenSinks(t *testing.T) {
+	store := &memoryStore{}
+	broker := &recordingBroker{}
+	sink := &recordingSink{}
+	clk := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	registry := newTestRegistry()
+	relay := NewRelay(store, broker, sink, registry, Config{PollInterval: time.Second, BatchSize: 2}, clk, logrus.New())
+
+	addEvents(t, NewWriter(store, registry, logrus.New()), "e1", "e2", "e3")
+	relayed, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, relayed)
+	require.Len(t, broker.batches, 2, "events are sent in batches of BatchSize")
+	assert.Len(t, broker.batches[0], 2)
+	assert.Equal(t, []string{"e1", "e2", "e3"}, sink.published())
+	assert.Zero(t, store.pending())
+	assert.Equal(t, clk.Now(), store.sentAt[0])
+}
+
+func TestRelay_KeepsEventsWhileBrokerIsDown(t *testing.T) {
+	store := &memoryStore{}
+	broker := &recordingBroker{err: errors.New("broker unavailable")}
+	sink := &recordingSink{}
+	registry := newTestRegistry()
+	relay := NewRelay(store, broker, sink, registry, Config{PollInterval: time.Second}, clock.NewFake(time.Now()), logrus.New())
+
+	addEvents(t, NewWriter(store, registry, logrus.New()), "e1", "e2")
+	_, err := relay.RelayPending(context.Background())
+	assert.ErrorContains(t, err, "broker unavailable")
+	assert.Equal(t, 2, store.pending())
+	assert.Empty(t, sink.published(), "sinks wait for the broker")
+
+	broker.err = nil
+	relayed, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, relayed)
+	assert.Equal(t, []string{"e1", "e2"}, sink.published())
+}
+
+func TestRelay_RunRelaysEveryInterval(t *testing.T) {
+	store := &memoryStore{}
+	sink := &recordingSink{}
+	clk := clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	registry := newTestRegistry()
+	relay := NewRelay(store, nil, sink, registry, Config{PollInterval: time.Second}, clk, logrus.New())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		relay.Run(ctx)
+	}()
+	clk.BlockUntilTickers(1)
+
+	addEvents(t, NewWriter(store, registry, logrus.New()), "e1")
+	clk.Advance(time.Second)
+	assert.Eventually(t, func() bool { return len(sink.published()) == 1 }, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestWriter_CountsFailedWrites(t *testing.T) {
+	store := &memoryStore{err: errors.New("database is down")}
+	registry := newTestRegistry()
+
+	addEvents(t, NewWriter(store, registry, logrus.New()), "e1")
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `outbox_writes_total{result="failed"} 1`)
+}
@@ -0,0 +1,31 @@
+package cached
+
+import (
+	"context"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+)
+
+// BundleRepository drops the components of a bundle sale from the product
+// cache, since their stock is written around ProductRepository.
+type BundleRepository struct {
+	usecase.BundleRepository
+	products *ProductRepository
+}
+
+func NewBundleRepository(next usecase.BundleRepository, products *ProductRepository) *BundleRepository {
+	return &BundleRepository{
+		BundleRepository: next,
+		products:         products,
+	}
+}
+
+func (r *BundleRepository) DecrementStock(ctx context.Context, decrements []domain.StockDecrement) ([]*domain.Product, error) {
+	defer func() {
+		for _, decrement := range decrements {
+			r.products.Invalidate(decrement.ProductID)
+		}
+	}()
+	return r.BundleRepository.DecrementStock(ctx, decrements)
+}
@@ -0,0 +1,38 @@
+package cached
+
+import (
+	"context"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+)
+
+// CatalogSnapshotRepository drops the products a restore wrote from the
+// product cache, since restores write around ProductRepository.
+type CatalogSnapshotRepository struct {
+	usecase.CatalogSnapshotRepository
+	products *ProductRepository
+}
+
+func NewCatalogSnapshotRepository(next usecase.CatalogSnapshotRepository, products *ProductRepository) *CatalogSnapshotRepository {
+	return &CatalogSnapshotRepository{
+		CatalogSnapshotRepository: next,
+		products:                  products,
+	}
+}
+
+func (r *CatalogSnapshotRepository) RestoreCatalog(ctx context.Context, storeID int64, snapshot []*domain.Product) (*domain.CatalogDiff, error) {
+	diff, err := r.CatalogSnapshotRepository.RestoreCatalog(ctx, storeID, snapshot)
+	if diff != nil {
+		for _, p := range diff.Created {
+			r.products.Invalidate(p.ID)
+		}
+		for _, changed := range diff.Changed {
+			r.products.Invalidate(changed.Product.ID)
+		}
+		for _, p := range diff.Deleted {
+			r.products.Invalidate(p.ID)
+		}
+	}
+	return diff, err
+}
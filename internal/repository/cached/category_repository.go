@@ -0,0 +1,45 @@
+package cached
+
+import (
+	"context"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+)
+
+// CategoryRepository drops the products whose categories change from the
+// product cache, since their versions are bumped around ProductRepository.
+type CategoryRepository struct {
+	usecase.CategoryRepository
+	products *ProductRepository
+}
+
+func NewCategoryRepository(next usecase.CategoryRepository, products *ProductRepository) *CategoryRepository {
+	return &CategoryRepository{
+		CategoryRepository: next,
+		products:           products,
+	}
+}
+
+func (r *CategoryRepository) Update(ctx context.Context, id int64, category *domain.Category) (*domain.Category, []*domain.Product, error) {
+	updated, products, err := r.CategoryRepository.Update(ctx, id, category)
+	r.written(ctx, products)
+	return updated, products, err
+}
+
+func (r *CategoryRepository) Delete(ctx context.Context, id int64) ([]*domain.Product, error) {
+	products, err := r.CategoryRepository.Delete(ctx, id)
+	r.written(ctx, products)
+	return products, err
+}
+
+func (r *CategoryRepository) SetForProduct(ctx context.Context, productID int64, categoryIDs []int64) ([]*domain.Category, *domain.Product, error) {
+	defer r.products.written(ctx, productID)
+	return r.CategoryRepository.SetForProduct(ctx, productID, categoryIDs)
+}
+
+func (r *CategoryRepository) written(ctx context.Context, products []*domain.Product) {
+	for _, product := range products {
+		r.products.written(ctx, product.ID)
+	}
+}
@@ -0,0 +1,31 @@
+package cached
+
+import (
+	"context"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+)
+
+// OrderRepository drops an order's products from the product cache, since
+// their stock is written around ProductRepository.
+type OrderRepository struct {
+	usecase.OrderRepository
+	products *ProductRepository
+}
+
+func NewOrderRepository(next usecase.OrderRepository, products *ProductRepository) *OrderRepository {
+	return &OrderRepository{
+		OrderRepository: next,
+		products:        products,
+	}
+}
+
+func (r *OrderRepository) Place(ctx context.Context, order *domain.Order) ([]*domain.Product, error) {
+	defer func() {
+		for _, item := range order.Items {
+			r.products.written(ctx, item.ProductID)
+		}
+	}()
+	return r.OrderRepository.Place(ctx, order)
+}
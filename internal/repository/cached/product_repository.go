@@ -0,0 +1,268 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/cache"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/hotkeys"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const peerPublishTimeout = time.Second
+
+// ProductRepository is a read-through cache in front of another product
+// repository. Single-product reads are served from the LRU; writes go to the
+// underlying repository and invalidate the cached entry. When a hot-key
+// tracker is set, products it reports as hot are pinned in the LRU with the
+// longer hotTTL so viral products stay cached. When peers is set, writes
+// also evict the product from the other instances' caches, and reads bypass
+// the cache while they cannot.
+type ProductRepository struct {
+	usecase.ProductRepository
+	cache   *cache.LRU[int64, *domain.Product]
+	tracker *hotkeys.Tracker
+	hotTTL  time.Duration
+	stats   *cache.Stats
+	peers   Peers
+
+	// fillMu and generation keep a read that raced a write from caching
+	// the product it loaded before that write: every write bumps the
+	// generation, and a read only fills the cache if it has not moved.
+	fillMu     sync.Mutex
+	generation uint64
+
+	// bypassing is set while peers are unavailable.
+	bypassing atomic.Bool
+}
+
+// Peers tells the other instances to drop a product from their caches.
+type Peers interface {
+	Publish(ctx context.Context, id int64) error
+	// Available reports whether invalidations currently reach the other
+	// instances and theirs reach this one.
+	Available() bool
+}
+
+// NewProductRepository wraps next with a cache. tracker may be nil to
+// disable hot-key detection, stats to not count lookups, and peers to keep
+// invalidations on this instance.
+func NewProductRepository(next usecase.ProductRepository, lru *cache.LRU[int64, *domain.Product], tracker *hotkeys.Tracker, hotTTL time.Duration, stats *cache.Stats, peers Peers) *ProductRepository {
+	return &ProductRepository{
+		ProductRepository: next,
+		cache:             lru,
+		tracker:           tracker,
+		hotTTL:            hotTTL,
+		stats:             stats,
+		peers:             peers,
+	}
+}
+
+// GetByID bypasses the cache within a transaction, which may read writes
+// that are later rolled back.
+func (r *ProductRepository) GetByID(ctx context.Context, id int64) (*domain.Product, error) {
+	if database.InTransaction(ctx) {
+		return r.ProductRepository.GetByID(ctx, id)
+	}
+	if !r.coherent() {
+		r.stats.Miss(ctx, cache.TierMemory, false)
+		return r.ProductRepository.GetByID(ctx, id)
+	}
+
+	hot := r.tracker != nil && r.tracker.Record(id)
+
+	product, ok, expired := r.cache.Lookup(id)
+	if ok {
+		r.stats.Hit(ctx, cache.TierMemory, rowSize(product))
+		if hot {
+			r.cache.Promote(id, r.hotTTL)
+		}
+		return clone(product), nil
+	}
+	r.stats.Miss(ctx, cache.TierMemory, expired)
+
+	generation := r.currentGeneration()
+	product, err := r.ProductRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.fill(id, product, generation, hot)
+	return product, nil
+}
+
+func (r *ProductRepository) Update(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
+	defer r.written(ctx, id)
+	return r.ProductRepository.Update(ctx, id, product)
+}
+
+func (r *ProductRepository) Patch(ctx context.Context, id int64, patch *domain.ProductPatch) (*domain.Product, error) {
+	defer r.written(ctx, id)
+	return r.ProductRepository.Patch(ctx, id, patch)
+}
+
+func (r *ProductRepository) UpdateModeration(ctx context.Context, id int64, result domain.ModerationResult) (*domain.Product, error) {
+	defer r.written(ctx, id)
+	return r.ProductRepository.UpdateModeration(ctx, id, result)
+}
+
+func (r *ProductRepository) Delete(ctx context.Context, id int64) error {
+	defer r.written(ctx, id)
+	return r.ProductRepository.Delete(ctx, id)
+}
+
+// written invalidates id after a write, and again when the caller's
+// transaction commits, in case a read outside it cached the old row in
+// between.
+func (r *ProductRepository) written(ctx context.Context, id int64) {
+	r.Invalidate(id)
+	if database.InTransaction(ctx) {
+		database.AfterCommit(ctx, func() { r.Invalidate(id) })
+	}
+}
+
+func (r *ProductRepository) currentGeneration() uint64 {
+	r.fillMu.Lock()
+	defer r.fillMu.Unlock()
+	return r.generation
+}
+
+// fill caches product unless a write finished after generation was read,
+// in which case product may predate it.
+func (r *ProductRepository) fill(id int64, product *domain.Product, generation uint64, hot bool) {
+	r.fillMu.Lock()
+	defer r.fillMu.Unlock()
+
+	if r.generation != generation {
+		return
+	}
+	if hot {
+		r.cache.SetWithTTL(id, clone(product), r.hotTTL, true)
+	} else {
+		r.cache.Set(id, clone(product))
+	}
+}
+
+// Invalidate drops id from this instance's cache and the other instances',
+// as every write does.
+func (r *ProductRepository) Invalidate(id int64) {
+	r.Evict(id)
+
+	if r.peers == nil || !r.peers.Available() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), peerPublishTimeout)
+	defer cancel()
+	if err := r.peers.Publish(ctx, id); err != nil {
+		logger.FromContext(ctx).WithError(err).WithField("product_id", id).Warn("Failed to invalidate product on other instances")
+	}
+}
+
+// Evict drops id from this instance's cache, for example after another
+// instance wrote it.
+func (r *ProductRepository) Evict(id int64) {
+	r.fillMu.Lock()
+	defer r.fillMu.Unlock()
+
+	r.generation++
+	r.cache.Delete(id)
+	r.stats.Invalidated(cache.TierMemory)
+}
+
+// coherent reports whether the cache can be trusted not to hold products
+// other instances changed. It cannot while invalidations are not exchanged
+// with them, so reads bypass it; once they are again, whatever it held from
+// before is dropped.
+func (r *ProductRepository) coherent() bool {
+	if r.peers == nil {
+		return true
+	}
+	if !r.peers.Available() {
+		r.bypassing.Store(true)
+		return false
+	}
+	if r.bypassing.CompareAndSwap(true, false) {
+		r.fillMu.Lock()
+		r.generation++
+		r.cache.Purge()
+		r.fillMu.Unlock()
+	}
+	return true
+}
+
+// Prime loads the given products into the cache, skipping ones that no
+// longer exist. It returns how many were cached.
+func (r *ProductRepository) Prime(ctx context.Context, ids []int64) (int, error) {
+	if !r.coherent() {
+		return 0, nil
+	}
+	primed := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return primed, err
+		}
+
+		generation := r.currentGeneration()
+		product, err := r.ProductRepository.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, domain.ErrProductNotFound) {
+				continue
+			}
+			return primed, err
+		}
+
+		r.fill(id, product, generation, false)
+		primed++
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action": "prime_cache",
+		"primed": primed,
+		"wanted": len(ids),
+	}).Info("Product cache primed")
+
+	return primed, nil
+}
+
+// HotKeys returns up to n ids of the most read products: those the tracker
+// reports as hot first, then the most hit cached entries.
+func (r *ProductRepository) HotKeys(n int) []int64 {
+	ids := make([]int64, 0, n)
+	seen := make(map[int64]bool, n)
+	add := func(id int64) {
+		if len(ids) < n && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if r.tracker != nil {
+		for _, key := range r.tracker.Hot() {
+			add(key.ID)
+		}
+	}
+	for _, id := range r.cache.Hottest(n) {
+		add(id)
+	}
+	return ids
+}
+
+// rowSize estimates the bytes a products row costs to read: its text
+// columns plus eight bytes for each fixed-width one.
+func rowSize(product *domain.Product) int64 {
+	const fixedColumns = 12
+	return int64(len(product.Name) + len(product.Description.String) + len(product.DescriptionFormat) +
+		len(product.Status) + len(product.ModerationStatus) + len(product.ModerationReason.String) + fixedColumns*8)
+}
+
+func clone(product *domain.Product) *domain.Product {
+	copied := *product
+	return &copied
+}
@@ -0,0 +1,307 @@
+package cached
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/cache"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/hotkeys"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockProductRepository struct {
+	usecase.ProductRepository
+	mock.Mock
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id int64) (*domain.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) Update(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
+	args := m.Called(ctx, id, product)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func newTestRepository(next *MockProductRepository) *ProductRepository {
+	return NewProductRepository(next, cache.New[int64, *domain.Product](10, 0), nil, 0, nil, nil)
+}
+
+func TestProductRepository_GetByID_ReadThrough(t *testing.T) {
+	next := new(MockProductRepository)
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Name: "Test"}, nil).Once()
+	repo := newTestRepository(next)
+
+	first, err := repo.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	first.Name = "Mutated"
+
+	second, err := repo.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Test", second.Name, "callers must not be able to mutate cached products")
+
+	next.AssertExpectations(t)
+}
+
+func TestProductRepository_Delete_Invalidates(t *testing.T) {
+	next := new(MockProductRepository)
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1}, nil).Once()
+	next.On("Delete", mock.Anything, int64(1)).Return(nil)
+	next.On("GetByID", mock.Anything, int64(1)).Return(nil, domain.ErrProductNotFound).Once()
+	repo := newTestRepository(next)
+
+	_, err := repo.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(context.Background(), 1))
+
+	_, err = repo.GetByID(context.Background(), 1)
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	next.AssertExpectations(t)
+}
+
+type stubBundleRepository struct {
+	usecase.BundleRepository
+}
+
+func (stubBundleRepository) DecrementStock(ctx context.Context, decrements []domain.StockDecrement) ([]*domain.Product, error) {
+	return nil, nil
+}
+
+func TestBundleRepository_DecrementStock_Invalidates(t *testing.T) {
+	next := new(MockProductRepository)
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Amount: quantity.New(5)}, nil).Once()
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Amount: quantity.New(3)}, nil).Once()
+	products := newTestRepository(next)
+	bundles := NewBundleRepository(stubBundleRepository{}, products)
+
+	_, err := products.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	_, err = bundles.DecrementStock(context.Background(), []domain.StockDecrement{{ProductID: 1, Amount: quantity.New(2)}})
+	require.NoError(t, err)
+
+	product, err := products.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, quantity.New(3), product.Amount)
+	next.AssertExpectations(t)
+}
+
+type stubCatalogSnapshotRepository struct {
+	usecase.CatalogSnapshotRepository
+}
+
+func (stubCatalogSnapshotRepository) RestoreCatalog(ctx context.Context, storeID int64, snapshot []*domain.Product) (*domain.CatalogDiff, error) {
+	return &domain.CatalogDiff{Changed: []domain.ChangedProduct{{Product: snapshot[0], Fields: []string{"name"}}}}, nil
+}
+
+func TestCatalogSnapshotRepository_RestoreCatalog_Invalidates(t *testing.T) {
+	next := new(MockProductRepository)
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Name: "New"}, nil).Once()
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Name: "Old"}, nil).Once()
+	products := newTestRepository(next)
+	snapshots := NewCatalogSnapshotRepository(stubCatalogSnapshotRepository{}, products)
+
+	_, err := products.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	_, err = snapshots.RestoreCatalog(context.Background(), 3, []*domain.Product{{ID: 1, Name: "Old"}})
+	require.NoError(t, err)
+
+	product, err := products.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Old", product.Name)
+	next.AssertExpectations(t)
+}
+
+func TestProductRepository_GetByID_DoesNotCacheReadRacingWrite(t *testing.T) {
+	next := new(MockProductRepository)
+	repo := newTestRepository(next)
+	update := &domain.Product{Name: "New"}
+
+	// The update lands while the first read is loading the old row.
+	next.On("GetByID", mock.Anything, int64(1)).Run(func(mock.Arguments) {
+		_, err := repo.Update(context.Background(), 1, update)
+		require.NoError(t, err)
+	}).Return(&domain.Product{ID: 1, Name: "Old"}, nil).Once()
+	next.On("Update", mock.Anything, int64(1), update).Return(&domain.Product{ID: 1, Name: "New"}, nil).Once()
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Name: "New"}, nil).Once()
+
+	_, err := repo.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+
+	got, err := repo.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "New", got.Name)
+	next.AssertExpectations(t)
+}
+
+func TestProductRepository_Prime(t *testing.T) {
+	next := new(MockProductRepository)
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1}, nil).Once()
+	next.On("GetByID", mock.Anything, int64(2)).Return(nil, domain.ErrProductNotFound).Once()
+	repo := newTestRepository(next)
+
+	primed, err := repo.Prime(context.Background(), []int64{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, 1, primed)
+
+	_, err = repo.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, repo.HotKeys(10))
+	next.AssertExpectations(t)
+}
+
+func TestProductRepository_GetByID_PinsHotProducts(t *testing.T) {
+	next := new(MockProductRepository)
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1}, nil).Once()
+	next.On("GetByID", mock.Anything, int64(2)).Return(&domain.Product{ID: 2}, nil).Once()
+	next.On("GetByID", mock.Anything, int64(3)).Return(&domain.Product{ID: 3}, nil).Once()
+
+	tracker := hotkeys.NewTracker(10, 2, nil, clock.Real())
+	repo := NewProductRepository(next, cache.New[int64, *domain.Product](2, time.Minute), tracker, time.Hour, nil, nil)
+
+	for i := 0; i < 2; i++ {
+		_, err := repo.GetByID(context.Background(), 1)
+		require.NoError(t, err)
+	}
+	_, err := repo.GetByID(context.Background(), 2)
+	require.NoError(t, err)
+	_, err = repo.GetByID(context.Background(), 3)
+	require.NoError(t, err)
+
+	_, err = repo.GetByID(context.Background(), 1)
+	require.NoError(t, err, "hot product should still be cached")
+	assert.Equal(t, []int64{1}, repo.HotKeys(1))
+	next.AssertExpectations(t)
+}
+
+func TestProductRepository_CountsLookups(t *testing.T) {
+	next := new(MockProductRepository)
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Name: "Widget"}, nil)
+	next.On("Delete", mock.Anything, int64(1)).Return(nil)
+	stats := cache.NewStats()
+	repo := NewProductRepository(next, cache.New[int64, *domain.Product](10, 0), nil, 0, stats, nil)
+
+	ctx := cache.WithEndpoint(context.Background(), "GET /api/v1/products/:id")
+	for i := 0; i < 3; i++ {
+		_, err := repo.GetByID(ctx, 1)
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.Delete(ctx, 1))
+
+	assert.Equal(t, []cache.EndpointStats{{
+		Endpoint: "GET /api/v1/products/:id", Tier: cache.TierMemory,
+		Hits: 2, Misses: 1, BytesSaved: 2 * (6 + 96),
+	}}, stats.Endpoints())
+	assert.Equal(t, map[string]int64{cache.TierMemory: 1}, stats.Invalidations())
+}
+
+func TestProductRepository_Update_InvalidatesPeers(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	writerNext := new(MockProductRepository)
+	writerNext.On("Update", mock.Anything, int64(1), mock.Anything).Return(&domain.Product{ID: 1, Name: "New"}, nil)
+	writer := NewProductRepository(writerNext, cache.New[int64, *domain.Product](10, 0), nil, 0, nil,
+		cache.NewRedisInvalidator(client, "test:invalidations", nil))
+
+	readerNext := new(MockProductRepository)
+	readerNext.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Name: "Old"}, nil).Once()
+	readerNext.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Name: "New"}, nil).Once()
+	reader := newTestRepository(readerNext)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	evicted := make(chan int64, 1)
+	done := make(chan error)
+	go func() {
+		done <- cache.NewRedisInvalidator(client, "test:invalidations", nil).Subscribe(ctx, func(id int64) {
+			reader.Evict(id)
+			evicted <- id
+		})
+	}()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+	require.Eventually(t, func() bool {
+		return server.PubSubNumSub("test:invalidations")["test:invalidations"] == 1
+	}, time.Second, 10*time.Millisecond)
+
+	product, err := reader.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Old", product.Name)
+
+	_, err = writer.Update(ctx, 1, &domain.Product{ID: 1, Name: "New"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), <-evicted)
+
+	product, err = reader.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "New", product.Name, "the other instance must not serve the stale product")
+	readerNext.AssertExpectations(t)
+}
+
+type stubPeers struct {
+	available bool
+	published []int64
+}
+
+func (p *stubPeers) Publish(ctx context.Context, id int64) error {
+	p.published = append(p.published, id)
+	return nil
+}
+
+func (p *stubPeers) Available() bool {
+	return p.available
+}
+
+func TestProductRepository_GetByID_BypassesCacheWhilePeersUnavailable(t *testing.T) {
+	ctx := context.Background()
+	next := new(MockProductRepository)
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Name: "Old"}, nil).Once()
+	next.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, Name: "New"}, nil).Twice()
+	peers := &stubPeers{available: true}
+	repo := NewProductRepository(next, cache.New[int64, *domain.Product](10, 0), nil, 0, nil, peers)
+
+	product, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Old", product.Name)
+
+	// Another instance writes while invalidations are not exchanged.
+	peers.available = false
+	product, err = repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "New", product.Name, "reads bypass the cache")
+	repo.Invalidate(2)
+	assert.Empty(t, peers.published, "invalidations are not published while peers are unavailable")
+
+	peers.available = true
+	product, err = repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "New", product.Name, "the cache is emptied when peers are back")
+	product, err = repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "New", product.Name)
+	next.AssertExpectations(t)
+}
@@ -0,0 +1,72 @@
+package cached
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/repository/memory"
+	"backend-context-engineering-template/pkg/cache"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestProductRepository_ConcurrentUpdates hammers one product with writers
+// and readers and checks that the cache ends up agreeing with the
+// underlying repository and that no update was torn.
+func TestProductRepository_ConcurrentUpdates(t *testing.T) {
+	const writers, readers, rounds = 16, 16, 50
+
+	ctx := context.Background()
+	next := memory.NewProductRepository(memory.NewStore(clock.Real()))
+	repo := NewProductRepository(next, cache.New[int64, *domain.Product](10, 0), nil, 0, nil, nil)
+
+	product, err := repo.Create(ctx, &domain.Product{StoreID: 1, Name: "writer-0", Amount: quantity.New(0), Price: 0})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for w := 1; w <= writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				n := w*rounds + r
+				_, err := repo.Update(ctx, product.ID, &domain.Product{StoreID: 1, Name: fmt.Sprintf("writer-%d", n), Amount: quantity.New(int64(n)), Price: float64(n)})
+				assert.NoError(t, err)
+			}
+		}(w)
+	}
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				got, err := repo.GetByID(ctx, product.ID)
+				if assert.NoError(t, err) {
+					assertUntorn(t, got)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stored, err := next.GetByID(ctx, product.ID)
+	require.NoError(t, err)
+	assertUntorn(t, stored)
+
+	cached, err := repo.GetByID(ctx, product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, stored, cached, "cache must not keep a version older than the last write")
+}
+
+// assertUntorn checks that every field came from the same update.
+func assertUntorn(t *testing.T, product *domain.Product) {
+	t.Helper()
+	assert.Equal(t, fmt.Sprintf("writer-%s", product.Amount), product.Name)
+	assert.Equal(t, product.Amount.Float64(), product.Price)
+}
@@ -0,0 +1,209 @@
+package feed
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/logger"
+	"backend-context-engineering-template/pkg/quantity"
+	"github.com/sirupsen/logrus"
+)
+
+// MappingSource looks up the import mapping a CSV feed is read with.
+type MappingSource interface {
+	GetByID(ctx context.Context, storeID, id int64) (*domain.ImportMapping, error)
+}
+
+type HTTPFetcher struct {
+	client   *http.Client
+	mappings MappingSource
+	maxBytes int64
+}
+
+func NewHTTPFetcher(client *http.Client, mappings MappingSource, maxBytes int64) *HTTPFetcher {
+	return &HTTPFetcher{
+		client:   client,
+		mappings: mappings,
+		maxBytes: maxBytes,
+	}
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, feed *domain.Feed) ([]domain.FeedItem, error) {
+	var mapping *domain.ImportMapping
+	if feed.MappingID.Valid {
+		var err error
+		mapping, err = f.mappings.GetByID(ctx, feed.StoreID, feed.MappingID.Int64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load import mapping: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build feed request: %w", err)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed responded with status %d", resp.StatusCode)
+	}
+
+	body := io.LimitReader(resp.Body, f.maxBytes+1)
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed body: %w", err)
+	}
+	if int64(len(data)) > f.maxBytes {
+		return nil, fmt.Errorf("feed exceeds %d bytes", f.maxBytes)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"feed_id": feed.ID,
+		"bytes":   len(data),
+	}).Debug("Feed downloaded")
+
+	switch feed.Format {
+	case domain.FeedFormatCSV:
+		if mapping != nil {
+			return ParseMappedCSV(bytes.NewReader(data), mapping)
+		}
+		return ParseCSV(bytes.NewReader(data))
+	case domain.FeedFormatJSON:
+		return ParseJSON(data)
+	default:
+		return nil, fmt.Errorf("unsupported feed format %q", feed.Format)
+	}
+}
+
+// ParseCSV reads a header-addressed CSV feed. The name and price columns are
+// required; description, amount, unit, sku, barcode, on_duplicate and
+// image_urls, separated by "|", are optional.
+func ParseCSV(r io.Reader) ([]domain.FeedItem, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return []domain.FeedItem{}, nil
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header is missing %q column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	items := []domain.FeedItem{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv row %d: %w", line, err)
+		}
+
+		item := domain.FeedItem{
+			Name:        field(record, "name"),
+			Description: field(record, "description"),
+			Unit:        field(record, "unit"),
+			SKU:         field(record, "sku"),
+			Barcode:     field(record, "barcode"),
+			OnDuplicate: field(record, "on_duplicate"),
+			ImageURLs:   domain.SplitImageURLs(field(record, "image_urls")),
+		}
+
+		if amount := field(record, "amount"); amount != "" {
+			item.Amount, err = quantity.Parse(amount)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid amount %q", line, amount)
+			}
+		}
+
+		price := field(record, "price")
+		item.Price, err = strconv.ParseFloat(price, 64)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid price %q", line, price)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// ParseMappedCSV reads a CSV feed whose columns are named by the supplier,
+// filling the items through the store's import mapping.
+func ParseMappedCSV(r io.Reader, mapping *domain.ImportMapping) ([]domain.FeedItem, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return []domain.FeedItem{}, nil
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	bound, err := mapping.Bind(header)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []domain.FeedItem{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv row %d: %w", line, err)
+		}
+
+		item, err := bound.Item(record)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", line, err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// ParseJSON reads a feed published as a JSON array of items.
+func ParseJSON(data []byte) ([]domain.FeedItem, error) {
+	items := []domain.FeedItem{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode json feed: %w", err)
+	}
+	return items, nil
+}
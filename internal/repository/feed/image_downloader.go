@@ -0,0 +1,60 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// ImageDownloader fetches the images feed rows link to. Images are read
+// up to maxBytes and their type is sniffed from the content, since
+// suppliers' servers often label images wrongly.
+type ImageDownloader struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+func NewImageDownloader(client *http.Client, maxBytes int64) *ImageDownloader {
+	return &ImageDownloader{
+		client:   client,
+		maxBytes: maxBytes,
+	}
+}
+
+func (d *ImageDownloader) Download(ctx context.Context, url string) (*domain.DownloadedImage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build image request: %w", err)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image responded with status %d", resp.StatusCode)
+	}
+	if resp.ContentLength > d.maxBytes {
+		return nil, fmt.Errorf("%w of %d bytes", domain.ErrImageTooLarge, d.maxBytes)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, d.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image body: %w", err)
+	}
+	if int64(len(data)) > d.maxBytes {
+		return nil, fmt.Errorf("%w of %d bytes", domain.ErrImageTooLarge, d.maxBytes)
+	}
+
+	contentType := http.DetectContentType(data)
+	if _, ok := domain.ImageExtension(contentType); !ok {
+		return nil, fmt.Errorf("%w %q", domain.ErrUnsupportedImage, contentType)
+	}
+
+	return &domain.DownloadedImage{Data: data, ContentType: contentType}, nil
+}
@@ -0,0 +1,271 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type ProductRepository struct {
+	store *Store
+}
+
+func NewProductRepository(store *Store) *ProductRepository {
+	return &ProductRepository{store: store}
+}
+
+func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return clone(r.create(product)), nil
+}
+
+// CreateMany creates the products under one lock, so readers see all of
+// them or none.
+func (r *ProductRepository) CreateMany(ctx context.Context, products []*domain.Product) ([]*domain.Product, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	created := make([]*domain.Product, len(products))
+	for i, product := range products {
+		created[i] = clone(r.create(product))
+	}
+	return created, nil
+}
+
+// create stores a copy of product under a new ID. The caller holds the
+// store's lock.
+func (r *ProductRepository) create(product *domain.Product) *domain.Product {
+	s := r.store
+	s.nextID++
+	created := clone(product)
+	created.ID = s.nextID
+	if created.DescriptionFormat == "" {
+		created.DescriptionFormat = domain.DescriptionFormatPlain
+	}
+	if created.Unit == "" {
+		created.Unit = domain.UnitPiece
+	}
+	if created.Status == "" {
+		created.Status = domain.ProductStatusActive
+	}
+	if created.ModerationStatus == "" {
+		created.ModerationStatus = domain.ModerationStatusApproved
+	}
+	created.CreatedAt = s.clock.Now()
+	created.UpdatedAt = created.CreatedAt
+	created.Version = 1
+	s.products[created.ID] = created
+	return created
+}
+
+func (r *ProductRepository) GetByID(ctx context.Context, id int64) (*domain.Product, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	product, ok := s.products[id]
+	if !ok {
+		return nil, domain.ErrProductNotFound
+	}
+	return clone(product), nil
+}
+
+// GetAll lists the products published at now that pass the filter newest
+// first, as the Postgres repository does. Categories are only kept in
+// Postgres, so a category filter returns ErrUnsupportedFilter.
+func (r *ProductRepository) GetAll(ctx context.Context, filter domain.ProductFilter, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	if filter.CategoryID != nil {
+		return nil, fmt.Errorf("%w: %w: category_id needs the Postgres repository", domain.ErrInvalidProduct, domain.ErrUnsupportedFilter)
+	}
+	products := r.filter(func(p *domain.Product) bool { return p.IsListed(now) && filter.Matches(p) })
+	sort.Slice(products, func(i, j int) bool {
+		if !products[i].CreatedAt.Equal(products[j].CreatedAt) {
+			return products[i].CreatedAt.After(products[j].CreatedAt)
+		}
+		return products[i].ID > products[j].ID
+	})
+	return page(products, limit, offset), nil
+}
+
+func (r *ProductRepository) GetByAvailability(ctx context.Context, availability string, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	products := r.filter(func(p *domain.Product) bool { return p.IsListed(now) && p.Availability(now) == availability })
+	sort.Slice(products, func(i, j int) bool {
+		if !products[i].CreatedAt.Equal(products[j].CreatedAt) {
+			return products[i].CreatedAt.After(products[j].CreatedAt)
+		}
+		return products[i].ID > products[j].ID
+	})
+	return page(products, limit, offset), nil
+}
+
+func (r *ProductRepository) GetPublishTransitions(ctx context.Context, from, until time.Time) ([]*domain.Product, error) {
+	within := func(t *time.Time) bool { return t != nil && t.After(from) && !t.After(until) }
+	products := r.filter(func(p *domain.Product) bool {
+		return p.Status != domain.ProductStatusDraft && (within(p.PublishAt) || within(p.UnpublishAt))
+	})
+	sortByID(products)
+	return products, nil
+}
+
+func (r *ProductRepository) GetAfterID(ctx context.Context, afterID int64, limit int) ([]*domain.Product, error) {
+	products := r.filter(func(p *domain.Product) bool { return p.ID > afterID })
+	sortByID(products)
+	return page(products, limit, 0), nil
+}
+
+func (r *ProductRepository) GetAllByStore(ctx context.Context, storeID int64) ([]*domain.Product, error) {
+	products := r.filter(func(p *domain.Product) bool { return p.StoreID == storeID })
+	sortByID(products)
+	return products, nil
+}
+
+func (r *ProductRepository) Update(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.products[id]
+	if !ok {
+		return nil, domain.ErrProductNotFound
+	}
+	if product.Version != 0 && product.Version != existing.Version {
+		return nil, domain.ErrConflict
+	}
+
+	updated := clone(existing)
+	updated.StoreID = product.StoreID
+	updated.Name = product.Name
+	updated.Description = product.Description
+	updated.Amount = product.Amount
+	updated.Price = product.Price
+	updated.AllowBackorder = product.AllowBackorder
+	updated.BackorderLimit = product.BackorderLimit
+	updated.PreorderReleaseDate = product.PreorderReleaseDate
+	updated.LowStockThreshold = product.LowStockThreshold
+	updated.PublishAt = product.PublishAt
+	updated.UnpublishAt = product.UnpublishAt
+	if product.DescriptionFormat != "" {
+		updated.DescriptionFormat = product.DescriptionFormat
+	}
+	if product.Unit != "" {
+		updated.Unit = product.Unit
+	}
+	if product.Status != "" {
+		updated.Status = product.Status
+	}
+	if product.ModerationStatus != "" {
+		updated.ModerationStatus = product.ModerationStatus
+		updated.ModerationReason = product.ModerationReason
+	}
+	updated.UpdatedAt = s.clock.Now()
+	updated.Version++
+	s.products[id] = updated
+
+	return clone(updated), nil
+}
+
+func (r *ProductRepository) Patch(ctx context.Context, id int64, patch *domain.ProductPatch) (*domain.Product, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.products[id]
+	if !ok {
+		return nil, domain.ErrProductNotFound
+	}
+
+	if patch.Version != 0 && patch.Version != existing.Version {
+		return nil, domain.ErrConflict
+	}
+
+	updated := clone(existing)
+	patch.Apply(updated)
+	updated.UpdatedAt = s.clock.Now()
+	updated.Version++
+	s.products[id] = updated
+
+	return clone(updated), nil
+}
+
+func (r *ProductRepository) GetByModerationStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Product, error) {
+	products := r.filter(func(p *domain.Product) bool { return p.ModerationStatus == status })
+	sort.Slice(products, func(i, j int) bool {
+		if !products[i].UpdatedAt.Equal(products[j].UpdatedAt) {
+			return products[i].UpdatedAt.Before(products[j].UpdatedAt)
+		}
+		return products[i].ID < products[j].ID
+	})
+	return page(products, limit, offset), nil
+}
+
+func (r *ProductRepository) UpdateModeration(ctx context.Context, id int64, result domain.ModerationResult) (*domain.Product, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.products[id]
+	if !ok {
+		return nil, domain.ErrProductNotFound
+	}
+
+	updated := clone(existing)
+	updated.ModerationStatus = result.Status
+	updated.ModerationReason = sql.NullString{String: result.Reason, Valid: result.Reason != ""}
+	updated.UpdatedAt = s.clock.Now()
+	s.products[id] = updated
+
+	return clone(updated), nil
+}
+
+// Delete moves the product into the trash.
+func (r *ProductRepository) Delete(ctx context.Context, id int64) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	product, ok := s.products[id]
+	if !ok {
+		return domain.ErrProductNotFound
+	}
+	delete(s.products, id)
+	s.trash[id] = &domain.TrashedProduct{Product: *product, TrashedAt: s.clock.Now()}
+
+	return nil
+}
+
+func (r *ProductRepository) filter(keep func(*domain.Product) bool) []*domain.Product {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var products []*domain.Product
+	for _, product := range s.products {
+		if keep(product) {
+			products = append(products, clone(product))
+		}
+	}
+	return products
+}
+
+func sortByID(products []*domain.Product) {
+	sort.Slice(products, func(i, j int) bool { return products[i].ID < products[j].ID })
+}
+
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
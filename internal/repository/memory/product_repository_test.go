@@ -0,0 +1,245 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := NewProductRepository(NewStore(clock.Real()))
+
+	created, err := repo.Create(ctx, &domain.Product{StoreID: 1, Name: "Widget", Amount: quantity.New(5), Price: 9.5})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.ID)
+	assert.Equal(t, domain.ProductStatusActive, created.Status)
+	assert.Equal(t, domain.ModerationStatusApproved, created.ModerationStatus)
+
+	created.Name = "mutated by caller"
+	got, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Widget", got.Name, "callers must not alias stored products")
+
+	updated, err := repo.Update(ctx, 1, &domain.Product{StoreID: 1, Name: "Gadget", Amount: quantity.New(3), Price: 4})
+	require.NoError(t, err)
+	assert.Equal(t, "Gadget", updated.Name)
+	assert.Equal(t, domain.ProductStatusActive, updated.Status, "empty status keeps the current one")
+
+	_, err = repo.Update(ctx, 2, &domain.Product{StoreID: 1, Name: "Missing"})
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+
+	require.NoError(t, repo.Delete(ctx, 1))
+	_, err = repo.GetByID(ctx, 1)
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, 1), domain.ErrProductNotFound)
+}
+
+func TestProductRepository_Versions(t *testing.T) {
+	ctx := context.Background()
+	repo := NewProductRepository(NewStore(clock.Real()))
+
+	created, err := repo.Create(ctx, &domain.Product{StoreID: 1, Name: "Widget", Amount: quantity.New(5), Price: 9.5})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.Version)
+
+	updated, err := repo.Update(ctx, created.ID, &domain.Product{StoreID: 1, Name: "Widget", Amount: quantity.New(4), Price: 9.5, Version: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.Version)
+
+	_, err = repo.Update(ctx, created.ID, &domain.Product{StoreID: 1, Name: "Stale", Amount: quantity.New(4), Price: 9.5, Version: 1})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	price := 8.0
+	_, err = repo.Patch(ctx, created.ID, &domain.ProductPatch{Price: &price, Version: 1})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	// Version 0 skips the check.
+	patched, err := repo.Patch(ctx, created.ID, &domain.ProductPatch{Price: &price})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), patched.Version)
+	assert.Equal(t, "Widget", patched.Name)
+}
+
+func TestProductRepository_CreateMany(t *testing.T) {
+	ctx := context.Background()
+	repo := NewProductRepository(NewStore(clock.Real()))
+
+	created, err := repo.CreateMany(ctx, []*domain.Product{
+		{StoreID: 1, Name: "Widget", Amount: quantity.New(5), Price: 9.5},
+		{StoreID: 1, Name: "Gadget", Amount: quantity.New(3), Price: 4, Status: domain.ProductStatusDraft},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	assert.Equal(t, int64(1), created[0].ID)
+	assert.Equal(t, "Gadget", created[1].Name)
+	assert.Equal(t, domain.ProductStatusDraft, created[1].Status)
+
+	got, err := repo.GetByID(ctx, created[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Gadget", got.Name)
+}
+
+func TestProductRepository_Listing(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	store := NewStore(fake)
+	repo := NewProductRepository(store)
+
+	for i, storeID := range []int64{1, 2, 1, 2, 1} {
+		fake.Advance(time.Second)
+		_, err := repo.Create(ctx, &domain.Product{StoreID: storeID, Name: string(rune('a' + i))})
+		require.NoError(t, err)
+	}
+
+	newest, err := repo.GetAll(ctx, domain.ProductFilter{}, fake.Now(), 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{5, 4}, ids(newest))
+
+	rest, err := repo.GetAll(ctx, domain.ProductFilter{}, fake.Now(), 10, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, ids(rest))
+
+	storeID := int64(2)
+	filtered, err := repo.GetAll(ctx, domain.ProductFilter{StoreID: &storeID}, fake.Now(), 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 2}, ids(filtered))
+
+	categoryID := int64(1)
+	_, err = repo.GetAll(ctx, domain.ProductFilter{CategoryID: &categoryID}, fake.Now(), 10, 0)
+	assert.ErrorIs(t, err, domain.ErrUnsupportedFilter, "categories are only kept in Postgres")
+	assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+
+	after, err := repo.GetAfterID(ctx, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, ids(after))
+
+	byStore, err := repo.GetAllByStore(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3, 5}, ids(byStore))
+}
+
+func TestProductRepository_PublishingWindows(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := NewProductRepository(NewStore(clock.NewFake(now)))
+	launch, sunset := now.Add(time.Hour), now.Add(2*time.Hour)
+
+	for _, product := range []*domain.Product{
+		{Name: "always"},
+		{Name: "launch", PublishAt: &launch},
+		{Name: "sunset", UnpublishAt: &sunset},
+		{Name: "limited", PublishAt: &launch, UnpublishAt: &sunset},
+		{Name: "draft", PublishAt: &launch, Status: domain.ProductStatusDraft},
+	} {
+		product.StoreID = 1
+		_, err := repo.Create(ctx, product)
+		require.NoError(t, err)
+	}
+
+	listed := func(at time.Time) []int64 {
+		products, err := repo.GetAll(ctx, domain.ProductFilter{}, at, 10, 0)
+		require.NoError(t, err)
+		return ids(products)
+	}
+	assert.Equal(t, []int64{3, 1}, listed(now))
+	assert.Equal(t, []int64{4, 3, 2, 1}, listed(launch), "a window opens at publish_at, but not for drafts")
+	assert.Equal(t, []int64{2, 1}, listed(sunset), "and closes at unpublish_at")
+
+	transitions, err := repo.GetPublishTransitions(ctx, now, launch)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 4}, ids(transitions))
+
+	transitions, err = repo.GetPublishTransitions(ctx, launch, sunset)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, ids(transitions))
+}
+
+func TestProductRepository_GetByAvailability(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := NewProductRepository(NewStore(clock.NewFake(now)))
+	release := now.Add(24 * time.Hour)
+
+	for _, product := range []*domain.Product{
+		{Name: "in stock", Amount: quantity.New(10), LowStockThreshold: quantity.New(5)},
+		{Name: "low stock", Amount: quantity.New(5), LowStockThreshold: quantity.New(5)},
+		{Name: "out of stock"},
+		{Name: "backorder", AllowBackorder: true, BackorderLimit: quantity.New(3)},
+		{Name: "preorder", AllowBackorder: true, BackorderLimit: quantity.New(3), PreorderReleaseDate: &release},
+		{Name: "discontinued", Amount: quantity.New(10), Status: domain.ProductStatusInactive},
+	} {
+		product.StoreID = 1
+		_, err := repo.Create(ctx, product)
+		require.NoError(t, err)
+	}
+
+	names := func(availability string, at time.Time) []string {
+		products, err := repo.GetByAvailability(ctx, availability, at, 10, 0)
+		require.NoError(t, err)
+		var names []string
+		for _, product := range products {
+			names = append(names, product.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"in stock"}, names(domain.AvailabilityInStock, now))
+	assert.Equal(t, []string{"low stock"}, names(domain.AvailabilityLowStock, now))
+	assert.Equal(t, []string{"out of stock"}, names(domain.AvailabilityOutOfStock, now))
+	assert.Equal(t, []string{"backorder"}, names(domain.AvailabilityBackorder, now))
+	assert.Equal(t, []string{"preorder"}, names(domain.AvailabilityPreorder, now))
+	assert.Equal(t, []string{"discontinued"}, names(domain.AvailabilityDiscontinued, now))
+
+	// Once released, a pre-order is sold as a backorder.
+	assert.Empty(t, names(domain.AvailabilityPreorder, release))
+	assert.Equal(t, []string{"preorder", "backorder"}, names(domain.AvailabilityBackorder, release))
+}
+
+func TestTrashRepository(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(clock.Real())
+	products := NewProductRepository(store)
+	trash := NewTrashRepository(store)
+
+	for _, storeID := range []int64{1, 1, 2} {
+		created, err := products.Create(ctx, &domain.Product{StoreID: storeID, Name: "Widget"})
+		require.NoError(t, err)
+		require.NoError(t, products.Delete(ctx, created.ID))
+	}
+
+	trashed, err := trash.GetAll(ctx, 1, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, trashed, 2)
+
+	restored, err := trash.Restore(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), restored.ID)
+	_, err = products.GetByID(ctx, 1)
+	assert.NoError(t, err)
+	_, err = trash.Restore(ctx, 1)
+	assert.ErrorIs(t, err, domain.ErrTrashedProductNotFound)
+
+	deleted, err := trash.Empty(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	purged, err := trash.PurgeBefore(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
+
+func ids(products []*domain.Product) []int64 {
+	result := make([]int64, len(products))
+	for i, product := range products {
+		result[i] = product.ID
+	}
+	return result
+}
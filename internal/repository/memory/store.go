@@ -0,0 +1,46 @@
+// Package memory holds product and trash repositories backed by maps. They
+// stand in for Postgres in load tests so results measure the service rather
+// than the database.
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+)
+
+// Store is the shared state of the product and trash repositories, so a
+// delete moves a product into the trash atomically as it does in Postgres.
+type Store struct {
+	mu       sync.RWMutex
+	nextID   int64
+	products map[int64]*domain.Product
+	trash    map[int64]*domain.TrashedProduct
+	clock    clock.Clock
+}
+
+func NewStore(clk clock.Clock) *Store {
+	return &Store{
+		products: make(map[int64]*domain.Product),
+		trash:    make(map[int64]*domain.TrashedProduct),
+		clock:    clk,
+	}
+}
+
+func clone(product *domain.Product) *domain.Product {
+	copied := *product
+	copied.PreorderReleaseDate = cloneTime(product.PreorderReleaseDate)
+	copied.PublishAt = cloneTime(product.PublishAt)
+	copied.UnpublishAt = cloneTime(product.UnpublishAt)
+	return &copied
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
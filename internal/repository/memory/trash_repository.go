@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type TrashRepository struct {
+	store *Store
+}
+
+func NewTrashRepository(store *Store) *TrashRepository {
+	return &TrashRepository{store: store}
+}
+
+// GetAll lists trashed products, most recently deleted first. A storeID of
+// zero lists every store.
+func (r *TrashRepository) GetAll(ctx context.Context, storeID int64, limit, offset int) ([]*domain.TrashedProduct, error) {
+	s := r.store
+	s.mu.RLock()
+	var products []*domain.TrashedProduct
+	for _, product := range s.trash {
+		if storeID == 0 || product.StoreID == storeID {
+			copied := *product
+			products = append(products, &copied)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(products, func(i, j int) bool {
+		if !products[i].TrashedAt.Equal(products[j].TrashedAt) {
+			return products[i].TrashedAt.After(products[j].TrashedAt)
+		}
+		return products[i].ID > products[j].ID
+	})
+	return page(products, limit, offset), nil
+}
+
+// Restore moves a trashed product back under its original ID.
+func (r *TrashRepository) Restore(ctx context.Context, id int64) (*domain.Product, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trashed, ok := s.trash[id]
+	if !ok {
+		return nil, domain.ErrTrashedProductNotFound
+	}
+	delete(s.trash, id)
+	product := clone(&trashed.Product)
+	s.products[id] = product
+
+	return clone(product), nil
+}
+
+// Count returns how many products are in the trash. A storeID of zero
+// counts every store.
+func (r *TrashRepository) Count(ctx context.Context, storeID int64) (int64, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int64
+	for _, product := range s.trash {
+		if storeID == 0 || product.StoreID == storeID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Empty permanently deletes trashed products. A storeID of zero empties the
+// whole bin.
+func (r *TrashRepository) Empty(ctx context.Context, storeID int64) (int64, error) {
+	return r.remove(func(p *domain.TrashedProduct) bool { return storeID == 0 || p.StoreID == storeID }), nil
+}
+
+func (r *TrashRepository) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.remove(func(p *domain.TrashedProduct) bool { return p.TrashedAt.Before(before) }), nil
+}
+
+func (r *TrashRepository) remove(match func(*domain.TrashedProduct) bool) int64 {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed int64
+	for id, product := range s.trash {
+		if match(product) {
+			delete(s.trash, id)
+			removed++
+		}
+	}
+	return removed
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type AnalyticsRepository struct {
+	db *sql.DB
+}
+
+func NewAnalyticsRepository(db *sql.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{
+		db: db,
+	}
+}
+
+// RecordCounts adds the counts in one transaction.
+func (r *AnalyticsRepository) RecordCounts(ctx context.Context, counts []domain.AnalyticsCount) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin analytics transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO product_analytics_daily (product_id, day, event_type, events)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (product_id, day, event_type) DO UPDATE
+		SET events = product_analytics_daily.events + EXCLUDED.events
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare analytics count insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, count := range counts {
+		if _, err := stmt.ExecContext(ctx, count.ProductID, count.Day, count.Type, count.Events); err != nil {
+			return fmt.Errorf("failed to record analytics count: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit analytics counts: %w", err)
+	}
+	return nil
+}
+
+// GetProductStats totals the product's events on days from since on. A
+// zero since totals every day.
+func (r *AnalyticsRepository) GetProductStats(ctx context.Context, productID int64, since time.Time) (*domain.ProductStats, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(events) FILTER (WHERE event_type = $3), 0),
+			COALESCE(SUM(events) FILTER (WHERE event_type = $4), 0),
+			COALESCE(SUM(events) FILTER (WHERE event_type = $5), 0)
+		FROM product_analytics_daily
+		WHERE product_id = $1 AND day >= $2
+	`
+
+	stats := &domain.ProductStats{ProductID: productID, Since: since}
+	err := r.db.QueryRowContext(ctx, query, productID, since,
+		domain.AnalyticsEventView,
+		domain.AnalyticsEventAddToCart,
+		domain.AnalyticsEventPurchase,
+	).Scan(&stats.Views, &stats.AddToCarts, &stats.Purchases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// RefreshPopularity sets every product's popularity score to its weighted
+// event counts from scoring.Since() to scoring.Day, decayed by age, and
+// returns how many scores changed. Only products with events in that range
+// or a score left from before are written, and only when their score moved.
+func (r *AnalyticsRepository) RefreshPopularity(ctx context.Context, scoring domain.PopularityScoring) (int64, error) {
+	query := `
+		WITH scores AS (
+			SELECT product_id, ROUND(SUM(events
+				* CASE event_type WHEN $3 THEN $4::float8 WHEN $5 THEN $6::float8 WHEN $7 THEN $8::float8 ELSE 0 END
+				* POWER(0.5, ($1::date - day) / $9::float8))::numeric, 4)::float8 AS score
+			FROM product_analytics_daily
+			WHERE day BETWEEN $2::date AND $1::date
+			GROUP BY product_id
+		)
+		UPDATE products p
+		SET popularity_score = COALESCE(s.score, 0)
+		FROM (
+			SELECT id FROM products WHERE popularity_score <> 0
+			UNION
+			SELECT product_id FROM scores
+		) AS touched
+		LEFT JOIN scores s ON s.product_id = touched.id
+		WHERE p.id = touched.id AND p.popularity_score IS DISTINCT FROM COALESCE(s.score, 0)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		scoring.Day,
+		scoring.Since(),
+		domain.AnalyticsEventView, domain.PopularityWeightView,
+		domain.AnalyticsEventAddToCart, domain.PopularityWeightAddToCart,
+		domain.AnalyticsEventPurchase, domain.PopularityWeightPurchase,
+		scoring.HalfLifeDays(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh popularity scores: %w", err)
+	}
+
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return changed, nil
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"backend-context-engineering-template/internal/audit"
+	"backend-context-engineering-template/internal/domain"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+const auditColumns = `id, actor, impersonated_by, client_ip, method, route, path, status_code, event, detail, occurred_at`
+
+func (r *AuditRepository) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	query := `
+		INSERT INTO audit_logs (actor, impersonated_by, client_ip, method, route, path, status_code, event, detail, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		entry.Actor,
+		entry.ImpersonatedBy,
+		entry.ClientIP,
+		entry.Method,
+		entry.Route,
+		entry.Path,
+		entry.StatusCode,
+		entry.Event,
+		entry.Detail,
+		entry.OccurredAt,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetAfter returns up to limit entries after cursor in commit order: by the
+// transaction that wrote them, then by ID. Only transactions older than every
+// transaction still in flight are read, so an entry that commits late can
+// never land behind a cursor that was already saved.
+func (r *AuditRepository) GetAfter(ctx context.Context, after audit.Cursor, limit int) ([]*domain.AuditEntry, audit.Cursor, error) {
+	query := `
+		SELECT ` + auditColumns + `, tx_id
+		FROM audit_logs
+		WHERE (tx_id, id) > ($1, $2)
+		  AND tx_id < pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+		ORDER BY tx_id, id
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, after.TxID, after.ID, limit)
+	if err != nil {
+		return nil, after, fmt.Errorf("failed to get audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	last := after
+	var entries []*domain.AuditEntry
+	for rows.Next() {
+		entry := &domain.AuditEntry{}
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.Actor,
+			&entry.ImpersonatedBy,
+			&entry.ClientIP,
+			&entry.Method,
+			&entry.Route,
+			&entry.Path,
+			&entry.StatusCode,
+			&entry.Event,
+			&entry.Detail,
+			&entry.OccurredAt,
+			&last.TxID,
+		); err != nil {
+			return nil, after, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		last.ID = entry.ID
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, after, fmt.Errorf("failed to iterate over audit entries: %w", err)
+	}
+
+	return entries, last, nil
+}
+
+func (r *AuditRepository) GetCursor(ctx context.Context, sink string) (audit.Cursor, error) {
+	query := `SELECT last_tx_id, last_id FROM audit_export_cursors WHERE sink = $1`
+
+	var cursor audit.Cursor
+	err := r.db.QueryRowContext(ctx, query, sink).Scan(&cursor.TxID, &cursor.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return audit.Cursor{}, nil
+		}
+		return audit.Cursor{}, fmt.Errorf("failed to get audit export cursor: %w", err)
+	}
+
+	return cursor, nil
+}
+
+func (r *AuditRepository) SaveCursor(ctx context.Context, sink string, cursor audit.Cursor) error {
+	query := `
+		INSERT INTO audit_export_cursors (sink, last_tx_id, last_id, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (sink) DO UPDATE SET last_tx_id = EXCLUDED.last_tx_id, last_id = EXCLUDED.last_id, updated_at = NOW()
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, sink, cursor.TxID, cursor.ID); err != nil {
+		return fmt.Errorf("failed to save audit export cursor: %w", err)
+	}
+
+	return nil
+}
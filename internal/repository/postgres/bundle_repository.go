@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type BundleRepository struct {
+	db *sql.DB
+}
+
+func NewBundleRepository(db *sql.DB) *BundleRepository {
+	return &BundleRepository{
+		db: db,
+	}
+}
+
+// GetComponents returns the bundle's components with their products' name,
+// unit, current stock and backorder limit. A product that is not a bundle
+// has none.
+func (r *BundleRepository) GetComponents(ctx context.Context, bundleID int64) ([]domain.BundleComponent, error) {
+	query := `
+		SELECT bc.bundle_id, bc.product_id, bc.quantity, bc.created_at, p.name, p.unit, p.amount,
+			CASE WHEN p.allow_backorder THEN p.backorder_limit ELSE 0 END
+		FROM bundle_components bc
+		JOIN products p ON p.id = bc.product_id
+		WHERE bc.bundle_id = $1
+		ORDER BY bc.product_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, bundleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bundle components: %w", err)
+	}
+	defer rows.Close()
+
+	components := []domain.BundleComponent{}
+	for rows.Next() {
+		var component domain.BundleComponent
+		if err := rows.Scan(
+			&component.BundleID,
+			&component.ProductID,
+			&component.Quantity,
+			&component.CreatedAt,
+			&component.Name,
+			&component.Unit,
+			&component.Stock,
+			&component.BackorderLimit,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan bundle component: %w", err)
+		}
+		components = append(components, component)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate bundle components: %w", err)
+	}
+
+	return components, nil
+}
+
+// IsComponent reports whether any bundle is made with the product.
+func (r *BundleRepository) IsComponent(ctx context.Context, productID int64) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM bundle_components WHERE product_id = $1)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, productID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check bundle components: %w", err)
+	}
+	return exists, nil
+}
+
+// SetComponents replaces the bundle's components in one transaction.
+func (r *BundleRepository) SetComponents(ctx context.Context, bundleID int64, components []domain.BundleComponent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin bundle transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM bundle_components WHERE bundle_id = $1`, bundleID); err != nil {
+		return fmt.Errorf("failed to clear bundle components: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO bundle_components (bundle_id, product_id, quantity, created_at)
+		VALUES ($1, $2, $3, NOW())
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare bundle component insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, component := range components {
+		if _, err := stmt.ExecContext(ctx, bundleID, component.ProductID, component.Quantity); err != nil {
+			return fmt.Errorf("failed to save bundle component: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit bundle components: %w", err)
+	}
+	return nil
+}
+
+func (r *BundleRepository) DeleteComponents(ctx context.Context, bundleID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM bundle_components WHERE bundle_id = $1`, bundleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete bundle components: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrBundleNotFound
+	}
+
+	return nil
+}
+
+// DecrementStock takes every decrement off its product's stock in one
+// transaction and returns the products as written. Products that allow
+// backorders may go down to their negative backorder limit. If any product
+// has too little stock, nothing is taken and ErrInsufficientStock is
+// returned.
+// Rows are locked in product ID order so concurrent sales cannot deadlock.
+func (r *BundleRepository) DecrementStock(ctx context.Context, decrements []domain.StockDecrement) ([]*domain.Product, error) {
+	ordered := append([]domain.StockDecrement(nil), decrements...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ProductID < ordered[j].ProductID })
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin stock transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, withRevision(`
+		UPDATE products
+		SET amount = amount - $1, updated_at = NOW(), version = version + 1
+		WHERE id = $2 AND amount - $1 >= -(CASE WHEN allow_backorder THEN backorder_limit ELSE 0 END)
+		RETURNING `+productColumns))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare stock decrement: %w", err)
+	}
+	defer stmt.Close()
+
+	products := make([]*domain.Product, 0, len(ordered))
+	for _, decrement := range ordered {
+		product, err := scanProduct(stmt.QueryRowContext(ctx, decrement.Amount, decrement.ProductID))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, fmt.Errorf("%w: product %d", domain.ErrInsufficientStock, decrement.ProductID)
+			}
+			return nil, fmt.Errorf("failed to decrement stock: %w", err)
+		}
+		products = append(products, product)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit stock decrement: %w", err)
+	}
+	return products, nil
+}
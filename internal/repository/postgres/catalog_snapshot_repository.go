@@ -0,0 +1,443 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"github.com/lib/pq"
+)
+
+const (
+	catalogSnapshotColumns = `id, store_id, object_key, products, taken_at, created_at`
+	catalogRestoreColumns  = `id, store_id, snapshot_id, status, created, updated, deleted, error, created_at, started_at, finished_at`
+)
+
+type CatalogSnapshotRepository struct {
+	db *sql.DB
+}
+
+func NewCatalogSnapshotRepository(db *sql.DB) *CatalogSnapshotRepository {
+	return &CatalogSnapshotRepository{
+		db: db,
+	}
+}
+
+// Capture reads the store's products from one repeatable-read snapshot,
+// so writes that continue meanwhile are not seen. The snapshot is taken
+// at the transaction's first statement, whose NOW() is returned.
+func (r *CatalogSnapshotRepository) Capture(ctx context.Context, storeID int64, fn func(*domain.Product) error) (time.Time, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to begin snapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	var takenAt time.Time
+	if err := tx.QueryRowContext(ctx, `SELECT NOW() FROM stores WHERE id = $1`, storeID).Scan(&takenAt); err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, domain.ErrStoreNotFound
+		}
+		return time.Time{}, fmt.Errorf("failed to begin snapshot: %w", err)
+	}
+
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE store_id = $1
+		ORDER BY id
+	`
+
+	rows, err := tx.QueryContext(ctx, query, storeID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read store products: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to scan product: %w", err)
+		}
+		if err := fn(product); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return time.Time{}, fmt.Errorf("failed to iterate over products: %w", err)
+	}
+
+	return takenAt, nil
+}
+
+func (r *CatalogSnapshotRepository) CreateSnapshot(ctx context.Context, snapshot *domain.CatalogSnapshot) (*domain.CatalogSnapshot, error) {
+	query := `
+		INSERT INTO catalog_snapshots (store_id, object_key, products, taken_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + catalogSnapshotColumns
+
+	result, err := scanCatalogSnapshot(r.db.QueryRowContext(ctx, query,
+		snapshot.StoreID,
+		snapshot.ObjectKey,
+		snapshot.Products,
+		snapshot.TakenAt,
+	))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+			return nil, domain.ErrStoreNotFound
+		}
+		return nil, fmt.Errorf("failed to create catalog snapshot: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *CatalogSnapshotRepository) GetSnapshot(ctx context.Context, storeID, id int64) (*domain.CatalogSnapshot, error) {
+	query := `SELECT ` + catalogSnapshotColumns + ` FROM catalog_snapshots WHERE id = $1 AND store_id = $2`
+
+	snapshot, err := scanCatalogSnapshot(r.db.QueryRowContext(ctx, query, id, storeID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSnapshotNotFound
+		}
+		return nil, fmt.Errorf("failed to get catalog snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func (r *CatalogSnapshotRepository) GetSnapshots(ctx context.Context, storeID int64, limit, offset int) ([]*domain.CatalogSnapshot, error) {
+	query := `
+		SELECT ` + catalogSnapshotColumns + `
+		FROM catalog_snapshots
+		WHERE store_id = $1
+		ORDER BY taken_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, storeID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*domain.CatalogSnapshot
+	for rows.Next() {
+		snapshot, err := scanCatalogSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan catalog snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over catalog snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+func (r *CatalogSnapshotRepository) PlanRestore(ctx context.Context, storeID int64, snapshot []*domain.Product) (*domain.CatalogDiff, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin restore preview: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := restoreTargets(ctx, tx, storeID, snapshot, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.PlanRestore(current, snapshot), nil
+}
+
+// RestoreCatalog locks the store's products, and the snapshot's products
+// wherever they are now, before planning, so the plan it writes is the
+// one it returns. Snapshot products moved to another store are moved
+// back, and recreated products are taken out of the trash.
+func (r *CatalogSnapshotRepository) RestoreCatalog(ctx context.Context, storeID int64, snapshot []*domain.Product) (*domain.CatalogDiff, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin restore: %w", err)
+	}
+	defer tx.Rollback()
+
+	current, err := restoreTargets(ctx, tx, storeID, snapshot, true)
+	if err != nil {
+		return nil, err
+	}
+	diff := domain.PlanRestore(current, snapshot)
+
+	if len(diff.Deleted) > 0 {
+		ids := make([]int64, 0, len(diff.Deleted))
+		for _, p := range diff.Deleted {
+			ids = append(ids, p.ID)
+		}
+		_, err := tx.ExecContext(ctx, `
+			WITH moved AS (
+				DELETE FROM products WHERE id = ANY($1)
+				RETURNING `+productColumns+`
+			),
+			trashed AS (
+				INSERT INTO product_trash (`+productColumns+`, trashed_at)
+				SELECT `+productColumns+`, NOW() FROM moved
+			)
+			INSERT INTO product_revisions (`+productColumns+`, deleted, recorded_at)
+			SELECT `+productColumns+`, TRUE, NOW() FROM moved
+		`, pq.Array(ids))
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+				return nil, domain.ErrProductInBundle
+			}
+			return nil, fmt.Errorf("failed to delete products: %w", err)
+		}
+	}
+
+	if len(diff.Created) > 0 {
+		ids := make([]int64, 0, len(diff.Created))
+		for _, p := range diff.Created {
+			ids = append(ids, p.ID)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM product_trash WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+			return nil, fmt.Errorf("failed to take restored products out of the trash: %w", err)
+		}
+	}
+
+	insert := withRevision(`
+		INSERT INTO products (` + productColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, NOW(), $19)
+		RETURNING ` + productColumns)
+	for i, p := range diff.Created {
+		// Recreated products go on from their version in the snapshot.
+		written, err := scanProduct(tx.QueryRowContext(ctx, insert, append(restoredProductArgs(p), p.CreatedAt, p.Version+1)...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to recreate product %d: %w", p.ID, err)
+		}
+		diff.Created[i] = written
+	}
+
+	update := withRevision(`
+		UPDATE products
+		SET store_id = $2, name = $3, description = $4, description_format = $5, amount = $6,
+			unit = $7, price = $8, status = $9, moderation_status = $10, moderation_reason = $11,
+			allow_backorder = $12, backorder_limit = $13, preorder_release_date = $14,
+			low_stock_threshold = $15, publish_at = $16, unpublish_at = $17, updated_at = NOW(),
+			version = version + 1
+		WHERE id = $1
+		RETURNING ` + productColumns)
+	for i, changed := range diff.Changed {
+		written, err := scanProduct(tx.QueryRowContext(ctx, update, restoredProductArgs(changed.Product)...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore product %d: %w", changed.Product.ID, err)
+		}
+		diff.Changed[i].Product = written
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit restore: %w", err)
+	}
+
+	return diff, nil
+}
+
+// restoreTargets reads the products a restore of the store to snapshot
+// touches: the store's products and the snapshot's, wherever they are now.
+func restoreTargets(ctx context.Context, tx *sql.Tx, storeID int64, snapshot []*domain.Product, lock bool) ([]*domain.Product, error) {
+	ids := make([]int64, 0, len(snapshot))
+	for _, p := range snapshot {
+		ids = append(ids, p.ID)
+	}
+
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE store_id = $1 OR id = ANY($2)
+		ORDER BY id
+	`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+
+	rows, err := tx.QueryContext(ctx, query, storeID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store products: %w", err)
+	}
+	defer rows.Close()
+
+	var products []*domain.Product
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, product)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over products: %w", err)
+	}
+
+	return products, nil
+}
+
+// restoredProductArgs lists a product's columns in productColumns order,
+// up to the timestamps.
+func restoredProductArgs(p *domain.Product) []any {
+	return []any{
+		p.ID,
+		p.StoreID,
+		p.Name,
+		p.Description,
+		p.DescriptionFormat,
+		p.Amount,
+		p.Unit,
+		p.Price,
+		p.Status,
+		p.ModerationStatus,
+		p.ModerationReason,
+		p.AllowBackorder,
+		p.BackorderLimit,
+		p.PreorderReleaseDate,
+		p.LowStockThreshold,
+		p.PublishAt,
+		p.UnpublishAt,
+	}
+}
+
+func (r *CatalogSnapshotRepository) CreateRestore(ctx context.Context, restore *domain.CatalogRestore, staleBefore time.Time) (*domain.CatalogRestore, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin restore transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE catalog_restores
+		SET status = $1, error = 'interrupted', finished_at = NOW()
+		WHERE store_id = $2 AND status IN ($3, $4) AND created_at < $5
+	`,
+		domain.CatalogRestoreStatusFailed,
+		restore.StoreID,
+		domain.CatalogRestoreStatusPending,
+		domain.CatalogRestoreStatusRunning,
+		staleBefore,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fail interrupted restores: %w", err)
+	}
+
+	query := `
+		INSERT INTO catalog_restores (store_id, snapshot_id, status, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + catalogRestoreColumns
+
+	result, err := scanCatalogRestore(tx.QueryRowContext(ctx, query,
+		restore.StoreID,
+		restore.SnapshotID,
+		restore.Status,
+		restore.CreatedAt,
+	))
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505":
+				return nil, domain.ErrRestoreInProgress
+			case "23503":
+				return nil, domain.ErrSnapshotNotFound
+			}
+		}
+		return nil, fmt.Errorf("failed to create catalog restore: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit catalog restore: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *CatalogSnapshotRepository) UpdateRestore(ctx context.Context, restore *domain.CatalogRestore) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE catalog_restores
+		SET status = $1, created = $2, updated = $3, deleted = $4, error = $5, started_at = $6, finished_at = $7
+		WHERE id = $8
+	`,
+		restore.Status,
+		restore.Created,
+		restore.Updated,
+		restore.Deleted,
+		restore.Error,
+		restore.StartedAt,
+		restore.FinishedAt,
+		restore.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update catalog restore: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrRestoreNotFound
+	}
+
+	return nil
+}
+
+func (r *CatalogSnapshotRepository) GetRestore(ctx context.Context, storeID, id int64) (*domain.CatalogRestore, error) {
+	query := `SELECT ` + catalogRestoreColumns + ` FROM catalog_restores WHERE id = $1 AND store_id = $2`
+
+	restore, err := scanCatalogRestore(r.db.QueryRowContext(ctx, query, id, storeID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRestoreNotFound
+		}
+		return nil, fmt.Errorf("failed to get catalog restore: %w", err)
+	}
+
+	return restore, nil
+}
+
+func scanCatalogSnapshot(row rowScanner) (*domain.CatalogSnapshot, error) {
+	snapshot := &domain.CatalogSnapshot{}
+	err := row.Scan(
+		&snapshot.ID,
+		&snapshot.StoreID,
+		&snapshot.ObjectKey,
+		&snapshot.Products,
+		&snapshot.TakenAt,
+		&snapshot.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func scanCatalogRestore(row rowScanner) (*domain.CatalogRestore, error) {
+	restore := &domain.CatalogRestore{}
+	err := row.Scan(
+		&restore.ID,
+		&restore.StoreID,
+		&restore.SnapshotID,
+		&restore.Status,
+		&restore.Created,
+		&restore.Updated,
+		&restore.Deleted,
+		&restore.Error,
+		&restore.CreatedAt,
+		&restore.StartedAt,
+		&restore.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return restore, nil
+}
@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/sqlutil"
+	"github.com/lib/pq"
+)
+
+const categoryColumns = `id, parent_id, name, created_at, updated_at`
+
+// categoryErrors maps the errors of reading and writing categories. A
+// parent that does not exist violates the parent_id foreign key.
+var categoryErrors = sqlutil.Errors{
+	NoRows: domain.ErrCategoryNotFound,
+	Codes: map[string]error{
+		sqlutil.ForeignKeyViolation: domain.ErrParentCategoryNotFound,
+		sqlutil.UniqueViolation:     domain.ErrDuplicateCategory,
+	},
+}
+
+// categoryDeleteErrors maps the errors of deleting a category, which its
+// subcategories refuse through their parent_id foreign key.
+var categoryDeleteErrors = sqlutil.Errors{
+	Codes: map[string]error{sqlutil.ForeignKeyViolation: domain.ErrCategoryHasChildren},
+}
+
+// productCategoryErrors maps the errors of putting a product in
+// categories, where an unknown category violates the foreign key.
+var productCategoryErrors = sqlutil.Errors{
+	NoRows: domain.ErrProductNotFound,
+	Codes:  map[string]error{sqlutil.ForeignKeyViolation: domain.ErrCategoryNotFound},
+}
+
+// CategoryRepository keeps the category tree and the categories products
+// are in. Writes that change what a product is in bump its version, so they
+// reach caches and subscribers like any other product change; they join the
+// caller's transaction if it has one.
+type CategoryRepository struct {
+	db *sql.DB
+	tx *database.SQLTxManager
+}
+
+func NewCategoryRepository(db *sql.DB) *CategoryRepository {
+	return &CategoryRepository{
+		db: db,
+		tx: database.NewTxManager(db),
+	}
+}
+
+func (r *CategoryRepository) Create(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	query := `
+		INSERT INTO categories (parent_id, name, created_at, updated_at)
+		VALUES ($1, $2, NOW(), NOW())
+		RETURNING ` + categoryColumns
+
+	created, err := sqlutil.QueryOne(ctx, r.db, scanCategory, query, category.ParentID, category.Name)
+	if err != nil {
+		return nil, categoryErrors.Map(err, "create category")
+	}
+
+	return created, nil
+}
+
+func (r *CategoryRepository) GetByID(ctx context.Context, id int64) (*domain.Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1`
+
+	category, err := sqlutil.QueryOne(ctx, r.db, scanCategory, query, id)
+	if err != nil {
+		return nil, categoryErrors.Map(err, "get category")
+	}
+
+	return category, nil
+}
+
+func (r *CategoryRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Category, error) {
+	query := `
+		SELECT ` + categoryColumns + `
+		FROM categories
+		ORDER BY id
+		LIMIT $1 OFFSET $2
+	`
+
+	categories, err := sqlutil.QueryMany(ctx, r.db, scanCategory, query, limit, offset)
+	if err != nil {
+		return nil, categoryErrors.Map(err, "get categories")
+	}
+
+	return categories, nil
+}
+
+// Update renames a category or moves it under another parent, returning
+// the products in it or its subcategories with their versions bumped. Moves
+// are serialized by locking the table, so two concurrent moves cannot make
+// a cycle that neither sees; moving under the category itself or one of its
+// subcategories returns ErrCategoryCycle.
+func (r *CategoryRepository) Update(ctx context.Context, id int64, category *domain.Category) (*domain.Category, []*domain.Product, error) {
+	var (
+		updated  *domain.Category
+		products []*domain.Product
+	)
+	err := r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := database.Conn(ctx, r.db)
+		if category.ParentID.Valid {
+			if _, err := tx.ExecContext(ctx, `LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+				return fmt.Errorf("failed to lock categories: %w", err)
+			}
+
+			// Walk up from the new parent; reaching the category means it
+			// would become its own ancestor.
+			var cycle bool
+			err := tx.QueryRowContext(ctx, `
+				WITH RECURSIVE ancestors AS (
+					SELECT id, parent_id FROM categories WHERE id = $1
+					UNION
+					SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+				)
+				SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = $2)
+			`, category.ParentID.Int64, id).Scan(&cycle)
+			if err != nil {
+				return fmt.Errorf("failed to check category ancestors: %w", err)
+			}
+			if cycle {
+				return domain.ErrCategoryCycle
+			}
+		}
+
+		query := `
+			UPDATE categories
+			SET parent_id = $1, name = $2, updated_at = NOW()
+			WHERE id = $3
+			RETURNING ` + categoryColumns
+
+		var err error
+		if updated, err = sqlutil.QueryOne(ctx, tx, scanCategory, query, category.ParentID, category.Name, id); err != nil {
+			return categoryErrors.Map(err, "update category")
+		}
+		products, err = touchCategoryProducts(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, products, nil
+}
+
+// Delete removes a category and takes its products out of it, returning
+// them with their versions bumped. The parent_id foreign key refuses while
+// the category has subcategories.
+func (r *CategoryRepository) Delete(ctx context.Context, id int64) ([]*domain.Product, error) {
+	var products []*domain.Product
+	err := r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := database.Conn(ctx, r.db)
+
+		var err error
+		if products, err = touchCategoryProducts(ctx, tx, id); err != nil {
+			return err
+		}
+
+		rowsAffected, err := sqlutil.Exec(ctx, tx, `DELETE FROM categories WHERE id = $1`, id)
+		if err != nil {
+			return categoryDeleteErrors.Map(err, "delete category")
+		}
+		if rowsAffected == 0 {
+			return domain.ErrCategoryNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+const productCategoriesQuery = `
+	SELECT ` + categoryColumns + `
+	FROM categories
+	WHERE id IN (SELECT category_id FROM product_categories WHERE product_id = $1)
+	ORDER BY id
+`
+
+// GetByProduct lists the categories a product is in, returning
+// ErrProductNotFound for products that do not exist or are in the trash.
+func (r *CategoryRepository) GetByProduct(ctx context.Context, productID int64) ([]*domain.Category, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)`, productID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check product: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrProductNotFound
+	}
+
+	categories, err := sqlutil.QueryMany(ctx, r.db, scanCategory, productCategoriesQuery, productID)
+	if err != nil {
+		return nil, categoryErrors.Map(err, "get product categories")
+	}
+
+	return categories, nil
+}
+
+// SetForProduct replaces the categories a product is in, returning them and
+// the product with its version bumped. Bumping the version locks the
+// product row, so concurrent replacements apply one after the other.
+func (r *CategoryRepository) SetForProduct(ctx context.Context, productID int64, categoryIDs []int64) ([]*domain.Category, *domain.Product, error) {
+	var (
+		categories []*domain.Category
+		product    *domain.Product
+	)
+	err := r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := database.Conn(ctx, r.db)
+
+		touch := withRevision(`
+			UPDATE products
+			SET updated_at = NOW(), version = version + 1
+			WHERE id = $1
+			RETURNING ` + productColumns)
+
+		var err error
+		if product, err = sqlutil.QueryOne(ctx, tx, scanProduct, touch, productID); err != nil {
+			return productCategoryErrors.Map(err, "touch product")
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM product_categories WHERE product_id = $1`, productID); err != nil {
+			return productCategoryErrors.Map(err, "clear product categories")
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO product_categories (product_id, category_id, created_at)
+			SELECT $1, category_id, NOW() FROM unnest($2::BIGINT[]) AS category_id
+			ON CONFLICT DO NOTHING
+		`, productID, pq.Array(categoryIDs))
+		if err != nil {
+			return productCategoryErrors.Map(err, "set product categories")
+		}
+
+		if categories, err = sqlutil.QueryMany(ctx, tx, scanCategory, productCategoriesQuery, productID); err != nil {
+			return productCategoryErrors.Map(err, "get product categories")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return categories, product, nil
+}
+
+// touchCategoryProducts bumps the version of the products in the category
+// or its subcategories and returns them.
+func touchCategoryProducts(ctx context.Context, tx database.Querier, categoryID int64) ([]*domain.Product, error) {
+	query := withRevision(`
+		UPDATE products
+		SET updated_at = NOW(), version = version + 1
+		WHERE id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM categories WHERE id = $1
+				UNION
+				SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+			)
+			SELECT pc.product_id FROM product_categories pc JOIN subtree s ON s.id = pc.category_id
+		)
+		RETURNING ` + productColumns)
+
+	products, err := sqlutil.QueryMany(ctx, tx, scanProduct, query, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to touch category products: %w", err)
+	}
+	return products, nil
+}
+
+func scanCategory(row sqlutil.Row) (*domain.Category, error) {
+	category := &domain.Category{}
+	err := row.Scan(
+		&category.ID,
+		&category.ParentID,
+		&category.Name,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
@@ -0,0 +1,345 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type ConnectorRepository struct {
+	db *sql.DB
+}
+
+func NewConnectorRepository(db *sql.DB) *ConnectorRepository {
+	return &ConnectorRepository{
+		db: db,
+	}
+}
+
+func (r *ConnectorRepository) Create(ctx context.Context, connector *domain.Connector) (*domain.Connector, error) {
+	query := `
+		INSERT INTO connectors (store_id, kind, settings, enabled, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING id, store_id, kind, settings, enabled, created_at, updated_at
+	`
+
+	settings, err := json.Marshal(nonNilSettings(connector.Settings))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode connector settings: %w", err)
+	}
+
+	result, err := scanConnector(r.db.QueryRowContext(ctx, query,
+		connector.StoreID,
+		connector.Kind,
+		settings,
+		connector.Enabled,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connector: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *ConnectorRepository) GetByID(ctx context.Context, id int64) (*domain.Connector, error) {
+	query := `
+		SELECT id, store_id, kind, settings, enabled, created_at, updated_at
+		FROM connectors
+		WHERE id = $1
+	`
+
+	connector, err := scanConnector(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrConnectorNotFound
+		}
+		return nil, fmt.Errorf("failed to get connector: %w", err)
+	}
+
+	return connector, nil
+}
+
+func (r *ConnectorRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Connector, error) {
+	query := `
+		SELECT id, store_id, kind, settings, enabled, created_at, updated_at
+		FROM connectors
+		ORDER BY id
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connectors: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*domain.Connector
+	for rows.Next() {
+		connector, err := scanConnector(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan connector: %w", err)
+		}
+		list = append(list, connector)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over connectors: %w", err)
+	}
+
+	return list, nil
+}
+
+func (r *ConnectorRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM connectors WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete connector: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrConnectorNotFound
+	}
+
+	return nil
+}
+
+// GetCheckpoint returns an empty checkpoint for connectors that never synced.
+func (r *ConnectorRepository) GetCheckpoint(ctx context.Context, connectorID int64) (*domain.ConnectorCheckpoint, error) {
+	query := `
+		SELECT connector_id, cursor, since, updated_at
+		FROM connector_checkpoints
+		WHERE connector_id = $1
+	`
+
+	checkpoint := &domain.ConnectorCheckpoint{}
+	err := r.db.QueryRowContext(ctx, query, connectorID).Scan(
+		&checkpoint.ConnectorID,
+		&checkpoint.Cursor,
+		&checkpoint.Since,
+		&checkpoint.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &domain.ConnectorCheckpoint{ConnectorID: connectorID}, nil
+		}
+		return nil, fmt.Errorf("failed to get connector checkpoint: %w", err)
+	}
+
+	return checkpoint, nil
+}
+
+func (r *ConnectorRepository) SaveCheckpoint(ctx context.Context, checkpoint *domain.ConnectorCheckpoint) error {
+	query := `
+		INSERT INTO connector_checkpoints (connector_id, cursor, since, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (connector_id) DO UPDATE SET cursor = EXCLUDED.cursor, since = EXCLUDED.since, updated_at = NOW()
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, checkpoint.ConnectorID, checkpoint.Cursor, checkpoint.Since); err != nil {
+		return fmt.Errorf("failed to save connector checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// GetLinks returns the connector's links to products that still exist,
+// including trashed ones. Links to purged products are left out, so the next
+// sync creates the product again and relinks it.
+func (r *ConnectorRepository) GetLinks(ctx context.Context, connectorID int64) ([]*domain.ProductLink, error) {
+	query := `
+		SELECT l.connector_id, l.product_id, l.external_id, l.synced_at
+		FROM connector_product_links l
+		WHERE l.connector_id = $1
+		  AND (EXISTS (SELECT 1 FROM products p WHERE p.id = l.product_id)
+		    OR EXISTS (SELECT 1 FROM product_trash t WHERE t.id = l.product_id))
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, connectorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*domain.ProductLink
+	for rows.Next() {
+		link := &domain.ProductLink{}
+		if err := rows.Scan(&link.ConnectorID, &link.ProductID, &link.ExternalID, &link.SyncedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan product link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over product links: %w", err)
+	}
+
+	return links, nil
+}
+
+func (r *ConnectorRepository) SaveLink(ctx context.Context, link *domain.ProductLink) error {
+	query := `
+		INSERT INTO connector_product_links (connector_id, product_id, external_id, synced_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (connector_id, external_id) DO UPDATE SET product_id = EXCLUDED.product_id, synced_at = EXCLUDED.synced_at
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, link.ConnectorID, link.ProductID, link.ExternalID, link.SyncedAt); err != nil {
+		return fmt.Errorf("failed to save product link: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ConnectorRepository) CreateSync(ctx context.Context, run *domain.ConnectorSync) (*domain.ConnectorSync, error) {
+	query := `
+		INSERT INTO connector_syncs (connector_id, status, started_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, connector_id, status, pulled, created, updated, pushed, failed, error, started_at, finished_at
+	`
+
+	result, err := scanConnectorSync(r.db.QueryRowContext(ctx, query, run.ConnectorID, run.Status, run.StartedAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connector sync: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *ConnectorRepository) FinishSync(ctx context.Context, run *domain.ConnectorSync) error {
+	query := `
+		UPDATE connector_syncs
+		SET status = $1, pulled = $2, created = $3, updated = $4, pushed = $5, failed = $6, error = $7, finished_at = $8
+		WHERE id = $9
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		run.Status,
+		run.Pulled,
+		run.Created,
+		run.Updated,
+		run.Pushed,
+		run.Failed,
+		run.Error,
+		run.FinishedAt,
+		run.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish connector sync: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrConnectorSyncNotFound
+	}
+
+	return nil
+}
+
+func (r *ConnectorRepository) GetSync(ctx context.Context, connectorID, syncID int64) (*domain.ConnectorSync, error) {
+	query := `
+		SELECT id, connector_id, status, pulled, created, updated, pushed, failed, error, started_at, finished_at
+		FROM connector_syncs
+		WHERE id = $1 AND connector_id = $2
+	`
+
+	run, err := scanConnectorSync(r.db.QueryRowContext(ctx, query, syncID, connectorID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrConnectorSyncNotFound
+		}
+		return nil, fmt.Errorf("failed to get connector sync: %w", err)
+	}
+
+	return run, nil
+}
+
+func (r *ConnectorRepository) GetSyncs(ctx context.Context, connectorID int64, limit, offset int) ([]*domain.ConnectorSync, error) {
+	query := `
+		SELECT id, connector_id, status, pulled, created, updated, pushed, failed, error, started_at, finished_at
+		FROM connector_syncs
+		WHERE connector_id = $1
+		ORDER BY started_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, connectorID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connector syncs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*domain.ConnectorSync
+	for rows.Next() {
+		run, err := scanConnectorSync(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan connector sync: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over connector syncs: %w", err)
+	}
+
+	return runs, nil
+}
+
+func scanConnector(row rowScanner) (*domain.Connector, error) {
+	connector := &domain.Connector{}
+	var settings []byte
+	err := row.Scan(
+		&connector.ID,
+		&connector.StoreID,
+		&connector.Kind,
+		&settings,
+		&connector.Enabled,
+		&connector.CreatedAt,
+		&connector.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(settings, &connector.Settings); err != nil {
+		return nil, fmt.Errorf("failed to decode connector settings: %w", err)
+	}
+	return connector, nil
+}
+
+func scanConnectorSync(row rowScanner) (*domain.ConnectorSync, error) {
+	run := &domain.ConnectorSync{}
+	err := row.Scan(
+		&run.ID,
+		&run.ConnectorID,
+		&run.Status,
+		&run.Pulled,
+		&run.Created,
+		&run.Updated,
+		&run.Pushed,
+		&run.Failed,
+		&run.Error,
+		&run.StartedAt,
+		&run.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func nonNilSettings(settings map[string]string) map[string]string {
+	if settings == nil {
+		return map[string]string{}
+	}
+	return settings
+}
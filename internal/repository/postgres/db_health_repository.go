@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+	"github.com/lib/pq"
+)
+
+// DBHealthRepository reads activity statistics for the tables in the
+// connection's current schema.
+type DBHealthRepository struct {
+	db *sql.DB
+}
+
+func NewDBHealthRepository(db *sql.DB) *DBHealthRepository {
+	return &DBHealthRepository{
+		db: db,
+	}
+}
+
+func (r *DBHealthRepository) TableStats(ctx context.Context) ([]domain.TableStats, error) {
+	query := `
+		SELECT relname, n_live_tup, n_dead_tup, seq_scan, seq_tup_read, COALESCE(idx_scan, 0),
+			pg_total_relation_size(relid), GREATEST(last_vacuum, last_autovacuum), last_autoanalyze
+		FROM pg_stat_user_tables
+		WHERE schemaname = current_schema()
+		ORDER BY relname
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table stats: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []domain.TableStats
+	for rows.Next() {
+		var table domain.TableStats
+		err := rows.Scan(&table.Table, &table.LiveTuples, &table.DeadTuples, &table.SeqScans, &table.SeqTuplesRead,
+			&table.IndexScans, &table.TotalBytes, &table.LastVacuum, &table.LastAutoanalyze)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan table stats: %w", err)
+		}
+		tables = append(tables, table)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over table stats: %w", err)
+	}
+
+	return tables, nil
+}
+
+func (r *DBHealthRepository) IndexStats(ctx context.Context) ([]domain.IndexStats, error) {
+	query := `
+		SELECT s.relname, s.indexrelname,
+			ARRAY(
+				SELECT a.attname
+				FROM unnest(i.indkey) WITH ORDINALITY AS k(attnum, position)
+				JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+				ORDER BY k.position
+			)::text[],
+			s.idx_scan, pg_relation_size(s.indexrelid), i.indisunique
+		FROM pg_stat_user_indexes s
+		JOIN pg_index i ON i.indexrelid = s.indexrelid
+		WHERE s.schemaname = current_schema()
+		ORDER BY s.relname, s.indexrelname
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index stats: %w", err)
+	}
+	defer rows.Close()
+
+	var indexes []domain.IndexStats
+	for rows.Next() {
+		var index domain.IndexStats
+		if err := rows.Scan(&index.Table, &index.Index, pq.Array(&index.Columns), &index.Scans, &index.Bytes, &index.Unique); err != nil {
+			return nil, fmt.Errorf("failed to scan index stats: %w", err)
+		}
+		indexes = append(indexes, index)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over index stats: %w", err)
+	}
+
+	return indexes, nil
+}
+
+// StatsSince returns when the database's statistics were last reset, which
+// is NULL if they never were.
+func (r *DBHealthRepository) StatsSince(ctx context.Context) (sql.NullTime, error) {
+	var since sql.NullTime
+	err := r.db.QueryRowContext(ctx, `SELECT stats_reset FROM pg_stat_database WHERE datname = current_database()`).Scan(&since)
+	if err != nil {
+		return sql.NullTime{}, fmt.Errorf("failed to get stats reset time: %w", err)
+	}
+	return since, nil
+}
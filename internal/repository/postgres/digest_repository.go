@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type DigestRepository struct {
+	db *sql.DB
+}
+
+func NewDigestRepository(db *sql.DB) *DigestRepository {
+	return &DigestRepository{
+		db: db,
+	}
+}
+
+func (r *DigestRepository) GetSettings(ctx context.Context, storeID int64) (*domain.DigestSettings, error) {
+	query := `
+		SELECT store_id, channel, target, last_sent_day, created_at, updated_at
+		FROM digest_settings
+		WHERE store_id = $1
+	`
+
+	settings, err := scanDigestSettings(r.db.QueryRowContext(ctx, query, storeID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrDigestSettingsNotFound
+		}
+		return nil, fmt.Errorf("failed to get digest settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SaveSettings creates or replaces a store's settings. The last sent day
+// is kept, so changing the target does not resend a digest.
+func (r *DigestRepository) SaveSettings(ctx context.Context, settings *domain.DigestSettings) (*domain.DigestSettings, error) {
+	query := `
+		INSERT INTO digest_settings (store_id, channel, target, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (store_id) DO UPDATE
+		SET channel = EXCLUDED.channel, target = EXCLUDED.target, updated_at = NOW()
+		RETURNING store_id, channel, target, last_sent_day, created_at, updated_at
+	`
+
+	saved, err := scanDigestSettings(r.db.QueryRowContext(ctx, query, settings.StoreID, settings.Channel, settings.Target))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save digest settings: %w", err)
+	}
+
+	return saved, nil
+}
+
+func (r *DigestRepository) DeleteSettings(ctx context.Context, storeID int64) error {
+	query := `DELETE FROM digest_settings WHERE store_id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, storeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete digest settings: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrDigestSettingsNotFound
+	}
+
+	return nil
+}
+
+// RecordChanges adds the changes in one transaction. A stock change carries
+// no product name, so an empty name never overwrites a known one.
+func (r *DigestRepository) RecordChanges(ctx context.Context, changes []domain.CatalogChange) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin catalog change transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO catalog_changes (store_id, day, event_type, product_id, product_name, changes, last_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (store_id, day, event_type, product_id) DO UPDATE
+		SET changes = catalog_changes.changes + EXCLUDED.changes,
+			product_name = COALESCE(NULLIF(EXCLUDED.product_name, ''), catalog_changes.product_name),
+			last_at = GREATEST(catalog_changes.last_at, EXCLUDED.last_at)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare catalog change insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, change := range changes {
+		if _, err := stmt.ExecContext(ctx, change.StoreID, change.Day, change.Type, change.ProductID, change.ProductName, change.Changes, change.LastAt); err != nil {
+			return fmt.Errorf("failed to record catalog change: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit catalog changes: %w", err)
+	}
+	return nil
+}
+
+func (r *DigestRepository) GetUnsentDigests(ctx context.Context, day time.Time) ([]*domain.DigestSettings, error) {
+	query := `
+		SELECT store_id, channel, target, last_sent_day, created_at, updated_at
+		FROM digest_settings
+		WHERE last_sent_day IS NULL OR last_sent_day < $1
+		ORDER BY store_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unsent digests: %w", err)
+	}
+	defer rows.Close()
+
+	var due []*domain.DigestSettings
+	for rows.Next() {
+		settings, err := scanDigestSettings(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan digest settings: %w", err)
+		}
+		due = append(due, settings)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate digest settings: %w", err)
+	}
+
+	return due, nil
+}
+
+// ClaimDigest only updates the row if its last sent day is still previous,
+// so when several instances run the job one of them sends the digest.
+func (r *DigestRepository) ClaimDigest(ctx context.Context, storeID int64, day time.Time, previous sql.NullTime) (bool, error) {
+	query := `
+		UPDATE digest_settings
+		SET last_sent_day = $2
+		WHERE store_id = $1 AND last_sent_day IS NOT DISTINCT FROM $3
+	`
+
+	result, err := r.db.ExecContext(ctx, query, storeID, day, previous)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim digest: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *DigestRepository) ReleaseDigest(ctx context.Context, storeID int64, day time.Time, previous sql.NullTime) error {
+	query := `
+		UPDATE digest_settings
+		SET last_sent_day = $3
+		WHERE store_id = $1 AND last_sent_day = $2
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, storeID, day, previous); err != nil {
+		return fmt.Errorf("failed to release digest: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DigestRepository) GetChanges(ctx context.Context, storeID int64, day time.Time) ([]domain.CatalogChange, error) {
+	query := `
+		SELECT store_id, day, event_type, product_id, product_name, changes, last_at
+		FROM catalog_changes
+		WHERE store_id = $1 AND day = $2
+		ORDER BY last_at DESC, product_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, storeID, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []domain.CatalogChange
+	for rows.Next() {
+		var change domain.CatalogChange
+		if err := rows.Scan(
+			&change.StoreID,
+			&change.Day,
+			&change.Type,
+			&change.ProductID,
+			&change.ProductName,
+			&change.Changes,
+			&change.LastAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan catalog change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate catalog changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+func scanDigestSettings(row rowScanner) (*domain.DigestSettings, error) {
+	var settings domain.DigestSettings
+	err := row.Scan(
+		&settings.StoreID,
+		&settings.Channel,
+		&settings.Target,
+		&settings.LastSentDay,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
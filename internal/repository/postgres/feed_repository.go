@@ -0,0 +1,387 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"github.com/lib/pq"
+)
+
+const feedColumns = `id, store_id, url, format, interval_seconds, enabled, mapping_id, match_by, on_duplicate,
+	last_run_at, created_at, updated_at`
+
+const feedRunColumns = `id, feed_id, status, created, updated, deactivated, unchanged, skipped, failed, errors,
+	duplicates, images_queued, started_at, finished_at`
+
+type FeedRepository struct {
+	db *sql.DB
+}
+
+func NewFeedRepository(db *sql.DB) *FeedRepository {
+	return &FeedRepository{
+		db: db,
+	}
+}
+
+func (r *FeedRepository) Create(ctx context.Context, feed *domain.Feed) (*domain.Feed, error) {
+	query := `
+		INSERT INTO product_feeds (store_id, url, format, interval_seconds, enabled, mapping_id, match_by, on_duplicate,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING ` + feedColumns
+
+	matchBy, err := json.Marshal(nonNilStrings(feed.MatchBy))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed match rules: %w", err)
+	}
+
+	row := r.db.QueryRowContext(ctx, query,
+		feed.StoreID,
+		feed.URL,
+		feed.Format,
+		feed.IntervalSeconds,
+		feed.Enabled,
+		feed.MappingID,
+		matchBy,
+		feed.OnDuplicate,
+	)
+
+	result, err := scanFeed(row)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return nil, fmt.Errorf("%w: import mapping %d does not exist in store %d", domain.ErrInvalidFeed, feed.MappingID.Int64, feed.StoreID)
+		}
+		return nil, fmt.Errorf("failed to create feed: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *FeedRepository) GetByID(ctx context.Context, id int64) (*domain.Feed, error) {
+	query := `
+		SELECT ` + feedColumns + `
+		FROM product_feeds
+		WHERE id = $1
+	`
+
+	feed, err := scanFeed(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrFeedNotFound
+		}
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+
+	return feed, nil
+}
+
+func (r *FeedRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Feed, error) {
+	query := `
+		SELECT ` + feedColumns + `
+		FROM product_feeds
+		ORDER BY id
+		LIMIT $1 OFFSET $2
+	`
+
+	return r.queryFeeds(ctx, query, limit, offset)
+}
+
+func (r *FeedRepository) GetEnabled(ctx context.Context) ([]*domain.Feed, error) {
+	query := `
+		SELECT ` + feedColumns + `
+		FROM product_feeds
+		WHERE enabled = TRUE
+		ORDER BY id
+	`
+
+	return r.queryFeeds(ctx, query)
+}
+
+func (r *FeedRepository) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM product_feeds WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete feed: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrFeedNotFound
+	}
+
+	return nil
+}
+
+func (r *FeedRepository) MarkRun(ctx context.Context, id int64, at time.Time) error {
+	query := `UPDATE product_feeds SET last_run_at = $1 WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, at, id); err != nil {
+		return fmt.Errorf("failed to mark feed run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FeedRepository) CreateRun(ctx context.Context, run *domain.FeedRun) (*domain.FeedRun, error) {
+	query := `
+		INSERT INTO product_feed_runs (feed_id, status, started_at)
+		VALUES ($1, $2, $3)
+		RETURNING ` + feedRunColumns
+
+	result, err := scanFeedRun(r.db.QueryRowContext(ctx, query, run.FeedID, run.Status, run.StartedAt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create feed run: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *FeedRepository) FinishRun(ctx context.Context, run *domain.FeedRun) error {
+	query := `
+		UPDATE product_feed_runs
+		SET status = $1, created = $2, updated = $3, deactivated = $4, unchanged = $5, skipped = $6, failed = $7,
+			errors = $8, duplicates = $9, images_queued = $10, finished_at = $11
+		WHERE id = $12
+	`
+
+	errs, err := json.Marshal(nonNilStrings(run.Errors))
+	if err != nil {
+		return fmt.Errorf("failed to encode feed run errors: %w", err)
+	}
+	duplicates := run.Duplicates
+	if duplicates == nil {
+		duplicates = []domain.FeedDuplicate{}
+	}
+	encodedDuplicates, err := json.Marshal(duplicates)
+	if err != nil {
+		return fmt.Errorf("failed to encode feed run duplicates: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query,
+		run.Status,
+		run.Created,
+		run.Updated,
+		run.Deactivated,
+		run.Unchanged,
+		run.Skipped,
+		run.Failed,
+		errs,
+		encodedDuplicates,
+		run.ImagesQueued,
+		run.FinishedAt,
+		run.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish feed run: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrFeedRunNotFound
+	}
+
+	return nil
+}
+
+func (r *FeedRepository) GetRun(ctx context.Context, feedID, runID int64) (*domain.FeedRun, error) {
+	query := `
+		SELECT ` + feedRunColumns + `
+		FROM product_feed_runs
+		WHERE id = $1 AND feed_id = $2
+	`
+
+	run, err := scanFeedRun(r.db.QueryRowContext(ctx, query, runID, feedID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrFeedRunNotFound
+		}
+		return nil, fmt.Errorf("failed to get feed run: %w", err)
+	}
+
+	return run, nil
+}
+
+func (r *FeedRepository) GetRuns(ctx context.Context, feedID int64, limit, offset int) ([]*domain.FeedRun, error) {
+	query := `
+		SELECT ` + feedRunColumns + `
+		FROM product_feed_runs
+		WHERE feed_id = $1
+		ORDER BY started_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, feedID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*domain.FeedRun
+	for rows.Next() {
+		run, err := scanFeedRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan feed run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over feed runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// GetLinks returns the product links of all the store's feeds.
+func (r *FeedRepository) GetLinks(ctx context.Context, storeID int64) ([]domain.FeedProductLink, error) {
+	query := `
+		SELECT l.feed_id, l.item_key, l.product_id, COALESCE(l.sku, ''), COALESCE(l.barcode, '')
+		FROM feed_product_links l
+		JOIN product_feeds f ON f.id = l.feed_id
+		WHERE f.store_id = $1
+		ORDER BY l.feed_id, l.item_key
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed product links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []domain.FeedProductLink
+	for rows.Next() {
+		var link domain.FeedProductLink
+		if err := rows.Scan(&link.FeedID, &link.Key, &link.ProductID, &link.SKU, &link.Barcode); err != nil {
+			return nil, fmt.Errorf("failed to scan feed product link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over feed product links: %w", err)
+	}
+
+	return links, nil
+}
+
+// SaveLink links the feed row to its product, replacing the row's link.
+func (r *FeedRepository) SaveLink(ctx context.Context, link *domain.FeedProductLink) error {
+	query := `
+		INSERT INTO feed_product_links (feed_id, item_key, product_id, sku, barcode, linked_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NOW())
+		ON CONFLICT (feed_id, item_key) DO UPDATE
+		SET product_id = EXCLUDED.product_id, sku = EXCLUDED.sku, barcode = EXCLUDED.barcode, linked_at = NOW()
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, link.FeedID, link.Key, link.ProductID, link.SKU, link.Barcode); err != nil {
+		return fmt.Errorf("failed to save feed product link: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FeedRepository) queryFeeds(ctx context.Context, query string, args ...interface{}) ([]*domain.Feed, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feeds: %w", err)
+	}
+	defer rows.Close()
+
+	var feeds []*domain.Feed
+	for rows.Next() {
+		feed, err := scanFeed(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan feed: %w", err)
+		}
+		feeds = append(feeds, feed)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over feeds: %w", err)
+	}
+
+	return feeds, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanFeed(row rowScanner) (*domain.Feed, error) {
+	feed := &domain.Feed{}
+	var matchBy []byte
+	err := row.Scan(
+		&feed.ID,
+		&feed.StoreID,
+		&feed.URL,
+		&feed.Format,
+		&feed.IntervalSeconds,
+		&feed.Enabled,
+		&feed.MappingID,
+		&matchBy,
+		&feed.OnDuplicate,
+		&feed.LastRunAt,
+		&feed.CreatedAt,
+		&feed.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(matchBy, &feed.MatchBy); err != nil {
+		return nil, fmt.Errorf("failed to decode feed match rules: %w", err)
+	}
+	return feed, nil
+}
+
+func scanFeedRun(row rowScanner) (*domain.FeedRun, error) {
+	run := &domain.FeedRun{}
+	var errs, duplicates []byte
+	err := row.Scan(
+		&run.ID,
+		&run.FeedID,
+		&run.Status,
+		&run.Created,
+		&run.Updated,
+		&run.Deactivated,
+		&run.Unchanged,
+		&run.Skipped,
+		&run.Failed,
+		&errs,
+		&duplicates,
+		&run.ImagesQueued,
+		&run.StartedAt,
+		&run.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(errs, &run.Errors); err != nil {
+		return nil, fmt.Errorf("failed to decode feed run errors: %w", err)
+	}
+	if err := json.Unmarshal(duplicates, &run.Duplicates); err != nil {
+		return nil, fmt.Errorf("failed to decode feed run duplicates: %w", err)
+	}
+	return run, nil
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
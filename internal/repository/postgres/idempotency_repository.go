@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db: db,
+	}
+}
+
+// Reserve inserts the key, or takes over an expired or abandoned record of
+// it. A concurrent request with the same key waits on the unique key until
+// the insert commits, then finds the record and returns it.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, key *domain.IdempotencyKey, expiredBefore, abandonedBefore time.Time) (*domain.IdempotencyKey, error) {
+	query := `
+		INSERT INTO idempotency_keys (owner, key, request_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (owner, key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			content_type = NULL,
+			headers = '{}',
+			body = NULL,
+			created_at = EXCLUDED.created_at
+		WHERE idempotency_keys.created_at < $5
+			OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < $6)
+		RETURNING id
+	`
+	var id int64
+	err := r.db.QueryRowContext(ctx, query, key.Owner, key.Key, key.RequestHash, key.CreatedAt, expiredBefore, abandonedBefore).Scan(&id)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	query = `
+		SELECT request_hash, status_code, content_type, headers, body, created_at
+		FROM idempotency_keys
+		WHERE owner = $1 AND key = $2
+	`
+	existing := &domain.IdempotencyKey{Owner: key.Owner, Key: key.Key}
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	var headers []byte
+	err = r.db.QueryRowContext(ctx, query, key.Owner, key.Key).Scan(
+		&existing.RequestHash, &statusCode, &contentType, &headers, &existing.Body, &existing.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	if err := json.Unmarshal(headers, &existing.Headers); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotency key headers: %w", err)
+	}
+	existing.StatusCode = int(statusCode.Int64)
+	existing.ContentType = contentType.String
+	return existing, nil
+}
+
+// Complete records the response, unless the reservation was taken over by
+// another request since.
+func (r *IdempotencyRepository) Complete(ctx context.Context, key *domain.IdempotencyKey) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $4, content_type = $5, headers = $6, body = $7
+		WHERE owner = $1 AND key = $2 AND request_hash = $3 AND status_code IS NULL
+	`
+	headers, err := json.Marshal(key.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency key headers: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, query, key.Owner, key.Key, key.RequestHash, key.StatusCode, key.ContentType, headers, key.Body); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release deletes the key if its request is still in progress.
+func (r *IdempotencyRepository) Release(ctx context.Context, owner, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE owner = $1 AND key = $2 AND status_code IS NULL`
+	if _, err := r.db.ExecContext(ctx, query, owner, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
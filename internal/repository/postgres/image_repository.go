@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+	"github.com/lib/pq"
+)
+
+const productImageColumns = `id, product_id, content_hash, content_type, size_bytes, object_key, COALESCE(source_url, ''),
+	position, created_at`
+
+const imageImportColumns = `id, run_id, product_id, url, status, image_id, COALESCE(error, ''), created_at, finished_at`
+
+type ImageRepository struct {
+	db *sql.DB
+}
+
+func NewImageRepository(db *sql.DB) *ImageRepository {
+	return &ImageRepository{
+		db: db,
+	}
+}
+
+func (r *ImageRepository) GetByProduct(ctx context.Context, productID int64) ([]*domain.ProductImage, error) {
+	query := `
+		SELECT ` + productImageColumns + `
+		FROM product_images
+		WHERE product_id = $1
+		ORDER BY position, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product images: %w", err)
+	}
+	defer rows.Close()
+
+	images := []*domain.ProductImage{}
+	for rows.Next() {
+		image, err := scanProductImage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product image: %w", err)
+		}
+		images = append(images, image)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over product images: %w", err)
+	}
+
+	return images, nil
+}
+
+// HasContent reports whether any product has an image with the hash, so
+// its object is already stored.
+func (r *ImageRepository) HasContent(ctx context.Context, contentHash string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM product_images WHERE content_hash = $1)`, contentHash).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up image content: %w", err)
+	}
+	return exists, nil
+}
+
+// Attach adds the image after the product's other images. When the
+// product already has the content, the existing image is returned and
+// attached is false.
+func (r *ImageRepository) Attach(ctx context.Context, image *domain.ProductImage) (*domain.ProductImage, bool, error) {
+	query := `
+		INSERT INTO product_images (product_id, content_hash, content_type, size_bytes, object_key, source_url, position,
+			created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''),
+			(SELECT COALESCE(MAX(position) + 1, 0) FROM product_images WHERE product_id = $1), NOW())
+		ON CONFLICT (product_id, content_hash) DO NOTHING
+		RETURNING ` + productImageColumns
+
+	row := r.db.QueryRowContext(ctx, query,
+		image.ProductID,
+		image.ContentHash,
+		image.ContentType,
+		image.Size,
+		image.ObjectKey,
+		image.SourceURL,
+	)
+
+	attached, err := scanProductImage(row)
+	if err == nil {
+		return attached, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, fmt.Errorf("failed to attach product image: %w", err)
+	}
+
+	existing, err := scanProductImage(r.db.QueryRowContext(ctx, `
+		SELECT `+productImageColumns+`
+		FROM product_images
+		WHERE product_id = $1 AND content_hash = $2
+	`, image.ProductID, image.ContentHash))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get product image: %w", err)
+	}
+	return existing, false, nil
+}
+
+// GetImportedURLs lists, by product, the image URLs the products were
+// already given: those attached and those found to duplicate an attached
+// image.
+func (r *ImageRepository) GetImportedURLs(ctx context.Context, productIDs []int64) (map[int64][]string, error) {
+	query := `
+		SELECT product_id, source_url FROM product_images
+		WHERE product_id = ANY($1) AND source_url IS NOT NULL
+		UNION
+		SELECT product_id, url FROM feed_image_imports
+		WHERE product_id = ANY($1) AND status = 'duplicate'
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(productIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get imported image urls: %w", err)
+	}
+	defer rows.Close()
+
+	urls := make(map[int64][]string)
+	for rows.Next() {
+		var productID int64
+		var url string
+		if err := rows.Scan(&productID, &url); err != nil {
+			return nil, fmt.Errorf("failed to scan imported image url: %w", err)
+		}
+		urls[productID] = append(urls[productID], url)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over imported image urls: %w", err)
+	}
+
+	return urls, nil
+}
+
+// CreateImports records the imports in one transaction and sets their IDs.
+func (r *ImageRepository) CreateImports(ctx context.Context, imports []*domain.ImageImport) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO feed_image_imports (run_id, product_id, url, status, image_id, error, created_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NOW(), $7)
+		RETURNING id, created_at
+	`
+
+	for _, imp := range imports {
+		err := tx.QueryRowContext(ctx, query,
+			imp.RunID,
+			imp.ProductID,
+			imp.URL,
+			imp.Status,
+			imp.ImageID,
+			imp.Error,
+			imp.FinishedAt,
+		).Scan(&imp.ID, &imp.CreatedAt)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				return domain.ErrFeedRunNotFound
+			}
+			return fmt.Errorf("failed to create image import: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit image imports: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ImageRepository) FinishImport(ctx context.Context, imp *domain.ImageImport) error {
+	query := `
+		UPDATE feed_image_imports
+		SET status = $1, image_id = $2, error = NULLIF($3, ''), finished_at = $4
+		WHERE id = $5
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, imp.Status, imp.ImageID, imp.Error, imp.FinishedAt, imp.ID); err != nil {
+		return fmt.Errorf("failed to finish image import: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ImageRepository) GetImports(ctx context.Context, runID int64) ([]*domain.ImageImport, error) {
+	query := `
+		SELECT ` + imageImportColumns + `
+		FROM feed_image_imports
+		WHERE run_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image imports: %w", err)
+	}
+	defer rows.Close()
+
+	imports := []*domain.ImageImport{}
+	for rows.Next() {
+		imp := &domain.ImageImport{}
+		err := rows.Scan(
+			&imp.ID,
+			&imp.RunID,
+			&imp.ProductID,
+			&imp.URL,
+			&imp.Status,
+			&imp.ImageID,
+			&imp.Error,
+			&imp.CreatedAt,
+			&imp.FinishedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan image import: %w", err)
+		}
+		imports = append(imports, imp)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over image imports: %w", err)
+	}
+
+	return imports, nil
+}
+
+func scanProductImage(row rowScanner) (*domain.ProductImage, error) {
+	image := &domain.ProductImage{}
+	err := row.Scan(
+		&image.ID,
+		&image.ProductID,
+		&image.ContentHash,
+		&image.ContentType,
+		&image.Size,
+		&image.ObjectKey,
+		&image.SourceURL,
+		&image.Position,
+		&image.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return image, nil
+}
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+	"github.com/lib/pq"
+)
+
+const importMappingColumns = `id, store_id, name, columns, created_at, updated_at`
+
+type ImportMappingRepository struct {
+	db *sql.DB
+}
+
+func NewImportMappingRepository(db *sql.DB) *ImportMappingRepository {
+	return &ImportMappingRepository{
+		db: db,
+	}
+}
+
+func (r *ImportMappingRepository) Create(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error) {
+	columns, err := json.Marshal(mapping.Columns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode import mapping columns: %w", err)
+	}
+
+	query := `
+		INSERT INTO import_mappings (store_id, name, columns, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		RETURNING ` + importMappingColumns
+
+	created, err := scanImportMapping(r.db.QueryRowContext(ctx, query, mapping.StoreID, mapping.Name, columns))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case "23505":
+				return nil, domain.ErrImportMappingExists
+			case "23503":
+				return nil, domain.ErrStoreNotFound
+			}
+		}
+		return nil, fmt.Errorf("failed to create import mapping: %w", err)
+	}
+
+	return created, nil
+}
+
+func (r *ImportMappingRepository) GetByID(ctx context.Context, storeID, id int64) (*domain.ImportMapping, error) {
+	query := `SELECT ` + importMappingColumns + ` FROM import_mappings WHERE id = $1 AND store_id = $2`
+
+	mapping, err := scanImportMapping(r.db.QueryRowContext(ctx, query, id, storeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrImportMappingNotFound
+		}
+		return nil, fmt.Errorf("failed to get import mapping: %w", err)
+	}
+
+	return mapping, nil
+}
+
+func (r *ImportMappingRepository) GetAll(ctx context.Context, storeID int64) ([]*domain.ImportMapping, error) {
+	query := `SELECT ` + importMappingColumns + ` FROM import_mappings WHERE store_id = $1 ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import mappings: %w", err)
+	}
+	defer rows.Close()
+
+	mappings := []*domain.ImportMapping{}
+	for rows.Next() {
+		mapping, err := scanImportMapping(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import mapping: %w", err)
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over import mappings: %w", err)
+	}
+
+	return mappings, nil
+}
+
+func (r *ImportMappingRepository) Update(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error) {
+	columns, err := json.Marshal(mapping.Columns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode import mapping columns: %w", err)
+	}
+
+	query := `
+		UPDATE import_mappings
+		SET name = $1, columns = $2, updated_at = NOW()
+		WHERE id = $3 AND store_id = $4
+		RETURNING ` + importMappingColumns
+
+	updated, err := scanImportMapping(r.db.QueryRowContext(ctx, query, mapping.Name, columns, mapping.ID, mapping.StoreID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrImportMappingNotFound
+		}
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, domain.ErrImportMappingExists
+		}
+		return nil, fmt.Errorf("failed to update import mapping: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete removes a mapping. The feeds foreign key refuses while a feed is
+// still read with it.
+func (r *ImportMappingRepository) Delete(ctx context.Context, storeID, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM import_mappings WHERE id = $1 AND store_id = $2`, id, storeID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return domain.ErrImportMappingInUse
+		}
+		return fmt.Errorf("failed to delete import mapping: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrImportMappingNotFound
+	}
+
+	return nil
+}
+
+func scanImportMapping(row rowScanner) (*domain.ImportMapping, error) {
+	mapping := &domain.ImportMapping{}
+	var columns []byte
+	err := row.Scan(
+		&mapping.ID,
+		&mapping.StoreID,
+		&mapping.Name,
+		&columns,
+		&mapping.CreatedAt,
+		&mapping.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(columns, &mapping.Columns); err != nil {
+		return nil, fmt.Errorf("failed to decode import mapping columns: %w", err)
+	}
+	return mapping, nil
+}
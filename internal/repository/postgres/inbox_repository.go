@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type InboxRepository struct {
+	db *sql.DB
+}
+
+func NewInboxRepository(db *sql.DB) *InboxRepository {
+	return &InboxRepository{
+		db: db,
+	}
+}
+
+// Apply records the message and runs fn in one transaction. If the message
+// is already recorded, fn is not run and applied is false. A concurrent
+// delivery of the same message waits on the unique key until the first one
+// commits or rolls back, so fn never runs twice for one message.
+func (r *InboxRepository) Apply(ctx context.Context, message domain.InboundMessage, at time.Time, fn func(tx *sql.Tx) error) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin inbox transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO inbox_messages (source, message_id, processed_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (source, message_id) DO NOTHING
+	`
+	result, err := tx.ExecContext(ctx, query, message.Source, message.ID, at)
+	if err != nil {
+		return false, fmt.Errorf("failed to record inbox message: %w", err)
+	}
+	recorded, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to record inbox message: %w", err)
+	}
+	if recorded == 0 {
+		return false, nil
+	}
+
+	if err := fn(tx); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit inbox transaction: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+const liveConfigColumns = `key, value, version, updated_by, updated_at`
+
+type LiveConfigRepository struct {
+	db *sql.DB
+}
+
+func NewLiveConfigRepository(db *sql.DB) *LiveConfigRepository {
+	return &LiveConfigRepository{
+		db: db,
+	}
+}
+
+func (r *LiveConfigRepository) GetAll(ctx context.Context) ([]*domain.ConfigSetting, error) {
+	query := `SELECT ` + liveConfigColumns + ` FROM live_config ORDER BY key`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live config: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []*domain.ConfigSetting
+	for rows.Next() {
+		setting, err := scanConfigSetting(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan live config setting: %w", err)
+		}
+		settings = append(settings, setting)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate live config: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (r *LiveConfigRepository) Get(ctx context.Context, key string) (*domain.ConfigSetting, error) {
+	query := `SELECT ` + liveConfigColumns + ` FROM live_config WHERE key = $1`
+
+	setting, err := scanConfigSetting(r.db.QueryRowContext(ctx, query, key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrConfigSettingNotFound
+		}
+		return nil, fmt.Errorf("failed to get live config setting: %w", err)
+	}
+
+	return setting, nil
+}
+
+// Save inserts a setting saved at version 0 and updates one saved at a
+// later version, so two changes based on the same version never both
+// succeed: the second matches no row and is reported as a conflict.
+func (r *LiveConfigRepository) Save(ctx context.Context, setting *domain.ConfigSetting) (*domain.ConfigSetting, error) {
+	var query string
+	args := []any{setting.Key, []byte(setting.Value), setting.UpdatedBy}
+	if setting.Version == 0 {
+		query = `
+			INSERT INTO live_config (key, value, version, updated_by, updated_at)
+			VALUES ($1, $2, 1, $3, NOW())
+			ON CONFLICT (key) DO NOTHING
+			RETURNING ` + liveConfigColumns
+	} else {
+		query = `
+			UPDATE live_config
+			SET value = $2, version = version + 1, updated_by = $3, updated_at = NOW()
+			WHERE key = $1 AND version = $4
+			RETURNING ` + liveConfigColumns
+		args = append(args, setting.Version)
+	}
+
+	saved, err := scanConfigSetting(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrConfigConflict
+		}
+		return nil, fmt.Errorf("failed to save live config setting: %w", err)
+	}
+
+	return saved, nil
+}
+
+func scanConfigSetting(row rowScanner) (*domain.ConfigSetting, error) {
+	var setting domain.ConfigSetting
+	var value []byte
+	err := row.Scan(&setting.Key, &value, &setting.Version, &setting.UpdatedBy, &setting.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	setting.Value = value
+	return &setting, nil
+}
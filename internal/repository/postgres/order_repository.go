@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/quantity"
+	"backend-context-engineering-template/pkg/sqlutil"
+	"github.com/lib/pq"
+)
+
+const orderColumns = `id, store_id, status, total, created_at`
+
+const orderItemColumns = `order_id, product_id, name, quantity, unit, unit_price`
+
+var orderErrors = sqlutil.Errors{NoRows: domain.ErrOrderNotFound}
+
+type OrderRepository struct {
+	db *sql.DB
+	tx *database.SQLTxManager
+}
+
+func NewOrderRepository(db *sql.DB) *OrderRepository {
+	return &OrderRepository{
+		db: db,
+		tx: database.NewTxManager(db),
+	}
+}
+
+// Place writes the order and takes its items off stock in one transaction,
+// filling in the order's ID, total and creation time and each item's name,
+// unit and price. It returns the products as written. Each product row is
+// locked before its stock is checked, so concurrent orders cannot both
+// take the last of it; products that allow backorders may go down to their
+// negative backorder limit. If any product has too little stock, nothing
+// is written and ErrInsufficientStock is returned.
+// Rows are locked in product ID order so concurrent orders cannot deadlock.
+// Within the caller's transaction, the order is part of it and the locks
+// are held until it ends; after an error, some stock may already be taken
+// in it, so the caller must roll it back.
+func (r *OrderRepository) Place(ctx context.Context, order *domain.Order) ([]*domain.Product, error) {
+	var products []*domain.Product
+	err := r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		products, err = r.place(ctx, database.Conn(ctx, r.db), order)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *OrderRepository) place(ctx context.Context, tx database.Querier, order *domain.Order) ([]*domain.Product, error) {
+	ordered := make([]*domain.OrderItem, len(order.Items))
+	for i := range order.Items {
+		ordered[i] = &order.Items[i]
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ProductID < ordered[j].ProductID })
+
+	lock, err := tx.PrepareContext(ctx, `
+		SELECT name, unit, price, amount, CASE WHEN allow_backorder THEN backorder_limit ELSE 0 END
+		FROM products
+		WHERE id = $1
+		FOR UPDATE
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare product lock: %w", err)
+	}
+	defer lock.Close()
+
+	decrement, err := tx.PrepareContext(ctx, withRevision(`
+		UPDATE products
+		SET amount = amount - $1, updated_at = NOW(), version = version + 1
+		WHERE id = $2
+		RETURNING `+productColumns))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare stock decrement: %w", err)
+	}
+	defer decrement.Close()
+
+	products := make([]*domain.Product, 0, len(ordered))
+	for _, item := range ordered {
+		var stock, backorderLimit quantity.Quantity
+		err := lock.QueryRowContext(ctx, item.ProductID).Scan(&item.Name, &item.Unit, &item.UnitPrice, &stock, &backorderLimit)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: product %d", domain.ErrProductNotFound, item.ProductID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock product: %w", err)
+		}
+
+		sellable, err := stock.Add(backorderLimit)
+		if err != nil {
+			sellable = stock
+		}
+		if sellable.Cmp(item.Quantity) < 0 {
+			return nil, fmt.Errorf("%w: product %d", domain.ErrInsufficientStock, item.ProductID)
+		}
+
+		product, err := scanProduct(decrement.QueryRowContext(ctx, item.Quantity, item.ProductID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrement stock: %w", err)
+		}
+		products = append(products, product)
+	}
+
+	order.Status = domain.OrderStatusPlaced
+	order.Total = 0
+	for _, item := range order.Items {
+		order.Total += item.Total()
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO orders (store_id, status, total, created_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING id, created_at
+	`, order.StoreID, order.Status, order.Total).Scan(&order.ID, &order.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO order_items (order_id, product_id, name, quantity, unit, unit_price, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare order item insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		item.OrderID = order.ID
+		if _, err := stmt.ExecContext(ctx, item.OrderID, item.ProductID, item.Name, item.Quantity, item.Unit, item.UnitPrice, i); err != nil {
+			return nil, fmt.Errorf("failed to save order item: %w", err)
+		}
+	}
+
+	return products, nil
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, id int64) (*domain.Order, error) {
+	query := `SELECT ` + orderColumns + ` FROM orders WHERE id = $1`
+
+	order, err := sqlutil.QueryOne(ctx, database.Conn(ctx, r.db), scanOrder, query, id)
+	if err != nil {
+		return nil, orderErrors.Map(err, "get order")
+	}
+
+	if err := r.loadItems(ctx, []*domain.Order{order}); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetAll lists the store's orders, or every order when storeID is 0,
+// newest first.
+func (r *OrderRepository) GetAll(ctx context.Context, storeID int64, limit, offset int) ([]*domain.Order, error) {
+	query := `
+		SELECT ` + orderColumns + `
+		FROM orders
+		WHERE $1 = 0 OR store_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	orders, err := sqlutil.QueryMany(ctx, database.Conn(ctx, r.db), scanOrder, query, storeID, limit, offset)
+	if err != nil {
+		return nil, orderErrors.Map(err, "get orders")
+	}
+
+	if err := r.loadItems(ctx, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// loadItems reads the orders' items, in the order they were placed.
+func (r *OrderRepository) loadItems(ctx context.Context, orders []*domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*domain.Order, len(orders))
+	ids := make([]int64, len(orders))
+	for i, order := range orders {
+		order.Items = []domain.OrderItem{}
+		byID[order.ID] = order
+		ids[i] = order.ID
+	}
+
+	query := `
+		SELECT ` + orderItemColumns + `
+		FROM order_items
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, position
+	`
+
+	items, err := sqlutil.QueryMany(ctx, database.Conn(ctx, r.db), scanOrderItem, query, pq.Array(ids))
+	if err != nil {
+		return orderErrors.Map(err, "get order items")
+	}
+
+	for _, item := range items {
+		order := byID[item.OrderID]
+		order.Items = append(order.Items, item)
+	}
+	return nil
+}
+
+func scanOrder(row sqlutil.Row) (*domain.Order, error) {
+	order := &domain.Order{}
+	err := row.Scan(
+		&order.ID,
+		&order.StoreID,
+		&order.Status,
+		&order.Total,
+		&order.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func scanOrderItem(row sqlutil.Row) (domain.OrderItem, error) {
+	var item domain.OrderItem
+	err := row.Scan(
+		&item.OrderID,
+		&item.ProductID,
+		&item.Name,
+		&item.Quantity,
+		&item.Unit,
+		&item.UnitPrice,
+	)
+	return item, err
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/database"
+	"github.com/lib/pq"
+)
+
+// outboxLockID is the advisory lock held by the instance relaying the
+// outbox, so events are published once and in order.
+const outboxLockID = 7286411393
+
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+// Add writes the event in the transaction ctx carries, if any, so it is
+// committed or rolled back with the change that raised it.
+func (r *OutboxRepository) Add(ctx context.Context, event domain.ProductEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox event: %w", err)
+	}
+
+	query := `
+		INSERT INTO event_outbox (event_id, event_type, payload, occurred_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := database.Conn(ctx, r.db).ExecContext(ctx, query, event.ID, event.Type, payload, event.OccurredAt); err != nil {
+		return fmt.Errorf("failed to add outbox event: %w", err)
+	}
+	return nil
+}
+
+// Relay runs in a transaction holding the outbox lock, so the events are
+// marked sent with the same commit that releases it. An instance that
+// finds the lock taken relays nothing.
+func (r *OutboxRepository) Relay(ctx context.Context, limit int, sentAt time.Time, fn func(ctx context.Context, events []domain.ProductEvent) error) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin outbox transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, outboxLockID).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to lock outbox: %w", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	query := `
+		SELECT id, payload
+		FROM event_outbox
+		WHERE sent_at IS NULL
+		ORDER BY id
+		LIMIT $1
+	`
+	rows, err := tx.QueryContext(ctx, query, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	var events []domain.ProductEvent
+	for rows.Next() {
+		var id int64
+		var payload []byte
+		if err := rows.Scan(&id, &payload); err != nil {
+			return 0, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		var event domain.ProductEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return 0, fmt.Errorf("failed to decode outbox event %d: %w", id, err)
+		}
+		ids = append(ids, id)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate over outbox events: %w", err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	if err := fn(ctx, events); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE event_outbox SET sent_at = $1 WHERE id = ANY($2)`, sentAt, pq.Array(ids)); err != nil {
+		return 0, fmt.Errorf("failed to mark outbox events sent: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox transaction: %w", err)
+	}
+	return len(events), nil
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+const pricingPolicyColumns = `store_id, currency, rounding, step, ending, direction, decimals, created_at, updated_at`
+
+type PricingRepository struct {
+	db *sql.DB
+}
+
+func NewPricingRepository(db *sql.DB) *PricingRepository {
+	return &PricingRepository{
+		db: db,
+	}
+}
+
+func (r *PricingRepository) GetPolicy(ctx context.Context, storeID int64, currency string) (*domain.PricingPolicy, error) {
+	query := `SELECT ` + pricingPolicyColumns + ` FROM pricing_policies WHERE store_id = $1 AND currency = $2`
+
+	policy, err := scanPricingPolicy(r.db.QueryRowContext(ctx, query, storeID, currency))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrPricingPolicyNotFound
+		}
+		return nil, fmt.Errorf("failed to get pricing policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+func (r *PricingRepository) GetPolicies(ctx context.Context, storeID int64) ([]*domain.PricingPolicy, error) {
+	query := `SELECT ` + pricingPolicyColumns + ` FROM pricing_policies WHERE store_id = $1 ORDER BY currency`
+
+	rows, err := r.db.QueryContext(ctx, query, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pricing policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := []*domain.PricingPolicy{}
+	for rows.Next() {
+		policy, err := scanPricingPolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pricing policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate pricing policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+func (r *PricingRepository) SavePolicy(ctx context.Context, policy *domain.PricingPolicy) (*domain.PricingPolicy, error) {
+	query := `
+		INSERT INTO pricing_policies (store_id, currency, rounding, step, ending, direction, decimals, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		ON CONFLICT (store_id, currency) DO UPDATE
+		SET rounding = EXCLUDED.rounding, step = EXCLUDED.step, ending = EXCLUDED.ending,
+			direction = EXCLUDED.direction, decimals = EXCLUDED.decimals, updated_at = NOW()
+		RETURNING ` + pricingPolicyColumns
+
+	saved, err := scanPricingPolicy(r.db.QueryRowContext(ctx, query,
+		policy.StoreID, policy.Currency, policy.Rounding, policy.Step, policy.Ending, policy.Direction, policy.Decimals))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save pricing policy: %w", err)
+	}
+
+	return saved, nil
+}
+
+func (r *PricingRepository) DeletePolicy(ctx context.Context, storeID int64, currency string) error {
+	query := `DELETE FROM pricing_policies WHERE store_id = $1 AND currency = $2`
+
+	result, err := r.db.ExecContext(ctx, query, storeID, currency)
+	if err != nil {
+		return fmt.Errorf("failed to delete pricing policy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrPricingPolicyNotFound
+	}
+
+	return nil
+}
+
+func scanPricingPolicy(row rowScanner) (*domain.PricingPolicy, error) {
+	policy := &domain.PricingPolicy{}
+	err := row.Scan(
+		&policy.StoreID,
+		&policy.Currency,
+		&policy.Rounding,
+		&policy.Step,
+		&policy.Ending,
+		&policy.Direction,
+		&policy.Decimals,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
@@ -4,191 +4,670 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"backend-context-engineering-template/internal/domain"
-	"github.com/lib/pq"
-	"github.com/sirupsen/logrus"
+	"backend-context-engineering-template/internal/indexadvisor"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/explain"
+	"backend-context-engineering-template/pkg/idgen"
+	"backend-context-engineering-template/pkg/sqlutil"
 )
 
 type ProductRepository struct {
 	db     *sql.DB
-	logger *logrus.Logger
+	ids    idgen.Generator
+	access *indexadvisor.Tracker
+
+	getByIDStmt *sql.Stmt
+	getAllStmt  *sql.Stmt
 }
 
-func NewProductRepository(db *sql.DB, logger *logrus.Logger) *ProductRepository {
+// NewProductRepository returns a repository that assigns new product IDs
+// with ids, or with the products_id_seq sequence when ids is nil. List
+// queries are counted in access, which may be nil.
+func NewProductRepository(db *sql.DB, ids idgen.Generator, access *indexadvisor.Tracker) *ProductRepository {
 	return &ProductRepository{
 		db:     db,
-		logger: logger,
+		ids:    ids,
+		access: access,
 	}
 }
 
-func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	query := `
-		INSERT INTO products (store_id, name, description, amount, price, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
-		RETURNING id, store_id, name, description, amount, price, created_at, updated_at
+// productErrors maps the errors of reading and writing products. A
+// product's store must exist and its name be unique in it.
+var productErrors = sqlutil.Errors{
+	NoRows: domain.ErrProductNotFound,
+	Codes: map[string]error{
+		sqlutil.UniqueViolation:     domain.ErrDuplicateProduct,
+		sqlutil.ForeignKeyViolation: domain.ErrStoreNotFound,
+	},
+}
+
+// deleteProductErrors maps the errors of deleting a product, which the
+// foreign key of the bundles it is part of refuses.
+var deleteProductErrors = sqlutil.Errors{
+	Codes: map[string]error{sqlutil.ForeignKeyViolation: domain.ErrProductInBundle},
+}
+
+const productColumns = `id, store_id, name, description, description_format, amount, unit, price, status,
+	moderation_status, moderation_reason, allow_backorder, backorder_limit, preorder_release_date, low_stock_threshold,
+	publish_at, unpublish_at, created_at, updated_at, version`
+
+// listedAt is the condition listings put on products: that they are not
+// drafts and are published at the time bound to $3.
+const listedAt = `status <> 'draft' AND (publish_at IS NULL OR publish_at <= $3) AND (unpublish_at IS NULL OR unpublish_at > $3)`
+
+const (
+	getProductByIDQuery = `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE id = $1
+	`
+	getProductsQuery = `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE ` + listedAt + `
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
 	`
+)
+
+// Prepare compiles the hot read queries up front so the first requests after
+// a deploy don't pay for parsing and planning. Without it the repository
+// falls back to unprepared queries.
+func (r *ProductRepository) Prepare(ctx context.Context) error {
+	getByID, err := r.db.PrepareContext(ctx, getProductByIDQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare get product statement: %w", err)
+	}
+
+	getAll, err := r.db.PrepareContext(ctx, getProductsQuery)
+	if err != nil {
+		getByID.Close()
+		return fmt.Errorf("failed to prepare list products statement: %w", err)
+	}
+
+	r.getByIDStmt, r.getAllStmt = getByID, getAll
+	return nil
+}
+
+func (r *ProductRepository) Close() error {
+	for _, stmt := range []*sql.Stmt{r.getByIDStmt, r.getAllStmt} {
+		if stmt == nil {
+			continue
+		}
+		if err := stmt.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conn runs statements in the caller's transaction, if it has one.
+func (r *ProductRepository) conn(ctx context.Context) database.Querier {
+	return database.Conn(ctx, r.db)
+}
 
-	row := r.db.QueryRowContext(ctx, query,
+func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	var id sql.NullInt64
+	if r.ids != nil {
+		next, err := r.ids.NextID()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate product ID: %w", err)
+		}
+		id = sql.NullInt64{Int64: next, Valid: true}
+	}
+
+	query := withRevision(`
+		INSERT INTO products (id, store_id, name, description, description_format, amount, unit, price, status,
+			moderation_status, moderation_reason, allow_backorder, backorder_limit, preorder_release_date, low_stock_threshold,
+			publish_at, unpublish_at, created_at, updated_at)
+		VALUES (COALESCE($1, nextval('products_id_seq')), $2, $3, $4, COALESCE(NULLIF($5, ''), 'plain'), $6,
+			COALESCE(NULLIF($7, ''), 'piece'), $8, COALESCE(NULLIF($9, ''), 'active'), COALESCE(NULLIF($10, ''), 'approved'), $11,
+			$12, $13, $14, $15, $16, $17, NOW(), NOW())
+		RETURNING ` + productColumns)
+
+	row := r.conn(ctx).QueryRowContext(ctx, query,
+		id,
 		product.StoreID,
 		product.Name,
 		nullStringFromString(product.Description.String),
+		product.DescriptionFormat,
 		product.Amount,
+		product.Unit,
 		product.Price,
+		product.Status,
+		product.ModerationStatus,
+		product.ModerationReason,
+		product.AllowBackorder,
+		product.BackorderLimit,
+		product.PreorderReleaseDate,
+		product.LowStockThreshold,
+		product.PublishAt,
+		product.UnpublishAt,
 	)
 
-	result := &domain.Product{}
-	err := row.Scan(
-		&result.ID,
-		&result.StoreID,
-		&result.Name,
-		&result.Description,
-		&result.Amount,
-		&result.Price,
-		&result.CreatedAt,
-		&result.UpdatedAt,
-	)
+	result, err := scanProduct(row)
+	if err != nil {
+		return nil, productErrors.Map(err, "create product")
+	}
 
+	return result, nil
+}
+
+// CreateMany inserts the products with one multi-row INSERT. Their IDs
+// are assigned up front, so the rows it returns can be put back in the
+// order of products.
+func (r *ProductRepository) CreateMany(ctx context.Context, products []*domain.Product) ([]*domain.Product, error) {
+	if len(products) == 0 {
+		return nil, nil
+	}
+
+	ids, err := r.nextIDs(ctx, len(products))
+	if err != nil {
+		return nil, err
+	}
+
+	const columns = 17
+	values := make([]string, len(products))
+	args := make([]any, 0, len(products)*columns)
+	for i, product := range products {
+		n := i * columns
+		values[i] = fmt.Sprintf(`($%d, $%d, $%d, $%d, COALESCE(NULLIF($%d, ''), 'plain'), $%d,
+			COALESCE(NULLIF($%d, ''), 'piece'), $%d, COALESCE(NULLIF($%d, ''), 'active'), COALESCE(NULLIF($%d, ''), 'approved'), $%d,
+			$%d, $%d, $%d, $%d, $%d, $%d, NOW(), NOW())`,
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11, n+12, n+13, n+14, n+15, n+16, n+17)
+		args = append(args,
+			ids[i],
+			product.StoreID,
+			product.Name,
+			nullStringFromString(product.Description.String),
+			product.DescriptionFormat,
+			product.Amount,
+			product.Unit,
+			product.Price,
+			product.Status,
+			product.ModerationStatus,
+			product.ModerationReason,
+			product.AllowBackorder,
+			product.BackorderLimit,
+			product.PreorderReleaseDate,
+			product.LowStockThreshold,
+			product.PublishAt,
+			product.UnpublishAt,
+		)
+	}
+
+	query := withRevision(`
+		INSERT INTO products (id, store_id, name, description, description_format, amount, unit, price, status,
+			moderation_status, moderation_reason, allow_backorder, backorder_limit, preorder_release_date, low_stock_threshold,
+			publish_at, unpublish_at, created_at, updated_at)
+		VALUES ` + strings.Join(values, ",\n") + `
+		RETURNING ` + productColumns)
+
+	returned, err := sqlutil.QueryMany(ctx, r.conn(ctx), scanProduct, query, args...)
 	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code {
-			case "23505":
-				return nil, domain.ErrDuplicateProduct
+		return nil, productErrors.Map(err, "create products")
+	}
+
+	created := make(map[int64]*domain.Product, len(products))
+	for _, product := range returned {
+		created[product.ID] = product
+	}
+
+	result := make([]*domain.Product, len(products))
+	for i, id := range ids {
+		if result[i] = created[id]; result[i] == nil {
+			return nil, fmt.Errorf("failed to create products: product %d was not returned", id)
+		}
+	}
+	return result, nil
+}
+
+// nextIDs assigns n new product IDs.
+func (r *ProductRepository) nextIDs(ctx context.Context, n int) ([]int64, error) {
+	ids := make([]int64, 0, n)
+	if r.ids != nil {
+		for range n {
+			id, err := r.ids.NextID()
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate product ID: %w", err)
 			}
+			ids = append(ids, id)
 		}
-		return nil, fmt.Errorf("failed to create product: %w", err)
+		return ids, nil
 	}
 
-	return result, nil
+	rows, err := r.conn(ctx).QueryContext(ctx, `SELECT nextval('products_id_seq') FROM generate_series(1, $1)`, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate product IDs: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan product ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to generate product IDs: %w", err)
+	}
+	return ids, nil
 }
 
 func (r *ProductRepository) GetByID(ctx context.Context, id int64) (*domain.Product, error) {
-	query := `
-		SELECT id, store_id, name, description, amount, price, created_at, updated_at
-		FROM products
-		WHERE id = $1
-	`
+	defer explain.Track(ctx, getProductByIDQuery, id)()
 
-	row := r.db.QueryRowContext(ctx, query, id)
+	var row *sql.Row
+	if r.getByIDStmt != nil {
+		row = database.Stmt(ctx, r.getByIDStmt).QueryRowContext(ctx, id)
+	} else {
+		row = r.conn(ctx).QueryRowContext(ctx, getProductByIDQuery, id)
+	}
 
-	product := &domain.Product{}
-	err := row.Scan(
-		&product.ID,
-		&product.StoreID,
-		&product.Name,
-		&product.Description,
-		&product.Amount,
-		&product.Price,
-		&product.CreatedAt,
-		&product.UpdatedAt,
-	)
+	product, err := scanProduct(row)
+	if err != nil {
+		return nil, productErrors.Map(err, "get product")
+	}
+
+	return product, nil
+}
+
+// GetAll lists the products published at now, drafts aside, that pass
+// the filter, in its order. Only the unfiltered listing, newest first, uses the prepared
+// statement.
+func (r *ProductRepository) GetAll(ctx context.Context, filter domain.ProductFilter, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	if !filter.IsEmpty() {
+		return r.getFiltered(ctx, filter, now, limit, offset)
+	}
+
+	defer explain.Track(ctx, getProductsQuery, limit, offset, now)()
+	r.access.Record(domain.AccessPattern{Table: "products", Sort: []string{"created_at"}})
 
+	var rows *sql.Rows
+	var err error
+	if r.getAllStmt != nil {
+		rows, err = database.Stmt(ctx, r.getAllStmt).QueryContext(ctx, limit, offset, now)
+	} else {
+		rows, err = r.conn(ctx).QueryContext(ctx, getProductsQuery, limit, offset, now)
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, domain.ErrProductNotFound
+		return nil, productErrors.Map(err, "get products")
+	}
+
+	products, err := sqlutil.ScanAll(rows, scanProduct)
+	if err != nil {
+		return nil, productErrors.Map(err, "get products")
+	}
+
+	return products, nil
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern, so a name filter
+// matches them literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *ProductRepository) getFiltered(ctx context.Context, filter domain.ProductFilter, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	args := []any{limit, offset, now}
+	conditions := []string{listedAt}
+	var equals []string
+	var rangeColumn string
+	bind := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.StoreID != nil {
+		conditions = append(conditions, "store_id = "+bind(*filter.StoreID))
+		equals = append(equals, "store_id")
+	}
+	if filter.CategoryID != nil {
+		// The category and its subcategories; UNION stops at a category
+		// already seen.
+		conditions = append(conditions, `id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM categories WHERE id = `+bind(*filter.CategoryID)+`
+				UNION
+				SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+			)
+			SELECT pc.product_id FROM product_categories pc JOIN subtree s ON s.id = pc.category_id
+		)`)
+	}
+	if filter.Name != "" {
+		conditions = append(conditions, "name ILIKE '%' || "+bind(likeEscaper.Replace(filter.Name))+" || '%'")
+	}
+	if filter.MinPrice != nil {
+		conditions = append(conditions, "price >= "+bind(*filter.MinPrice))
+		rangeColumn = "price"
+	}
+	if filter.MaxPrice != nil {
+		conditions = append(conditions, "price <= "+bind(*filter.MaxPrice))
+		rangeColumn = "price"
+	}
+	if filter.InStock != nil {
+		if *filter.InStock {
+			conditions = append(conditions, "amount > 0")
+		} else {
+			conditions = append(conditions, "amount <= 0")
 		}
-		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
-	return product, nil
+	orderBy, sort := "created_at DESC", []string{"created_at"}
+	if filter.Sort == domain.ProductSortPopularity {
+		orderBy, sort = "popularity_score DESC, id DESC", []string{"popularity_score", "id"}
+	}
+
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + orderBy + `
+		LIMIT $1 OFFSET $2
+	`
+
+	defer explain.Track(ctx, query, args...)()
+	r.access.Record(domain.AccessPattern{Table: "products", Equals: equals, Range: rangeColumn, Sort: sort})
+	products, err := sqlutil.QueryMany(ctx, r.conn(ctx), scanProduct, query, args...)
+	if err != nil {
+		return nil, productErrors.Map(err, "get filtered products")
+	}
+
+	return products, nil
 }
 
-func (r *ProductRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
+// GetByAvailability lists products published at now, drafts aside, with
+// the availability at now, newest first. Everything but pre-orders is read from the indexed
+// stock_availability column; a product that could be sold is a pre-order
+// while its release date is after now.
+func (r *ProductRepository) GetByAvailability(ctx context.Context, availability string, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	var condition string
+	args := []any{limit, offset, now}
+	switch availability {
+	case domain.AvailabilityPreorder:
+		condition = `stock_availability IN ('in_stock', 'low_stock', 'backorder') AND preorder_release_date > $3`
+	case domain.AvailabilityInStock, domain.AvailabilityLowStock, domain.AvailabilityBackorder:
+		condition = `stock_availability = $4 AND (preorder_release_date IS NULL OR preorder_release_date <= $3)`
+		args = append(args, availability)
+	default:
+		condition = `stock_availability = $4`
+		args = append(args, availability)
+	}
+
 	query := `
-		SELECT id, store_id, name, description, amount, price, created_at, updated_at
+		SELECT ` + productColumns + `
 		FROM products
+		WHERE ` + condition + ` AND ` + listedAt + `
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	defer explain.Track(ctx, query, args...)()
+	r.access.Record(domain.AccessPattern{Table: "products", Equals: []string{"stock_availability"}, Sort: []string{"created_at"}})
+	products, err := sqlutil.QueryMany(ctx, r.conn(ctx), scanProduct, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get products: %w", err)
+		return nil, productErrors.Map(err, "get products by availability")
 	}
-	defer rows.Close()
 
-	var products []*domain.Product
-	for rows.Next() {
-		product := &domain.Product{}
-		err := rows.Scan(
-			&product.ID,
-			&product.StoreID,
-			&product.Name,
-			&product.Description,
-			&product.Amount,
-			&product.Price,
-			&product.CreatedAt,
-			&product.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan product: %w", err)
-		}
-		products = append(products, product)
+	return products, nil
+}
+
+// GetPublishTransitions lists the products whose publishing window opened
+// or closed after from and up to until, in id order.
+func (r *ProductRepository) GetPublishTransitions(ctx context.Context, from, until time.Time) ([]*domain.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE status <> 'draft'
+			AND ((publish_at > $1 AND publish_at <= $2) OR (unpublish_at > $1 AND unpublish_at <= $2))
+		ORDER BY id
+	`
+
+	defer explain.Track(ctx, query, from, until)()
+	products, err := sqlutil.QueryMany(ctx, r.conn(ctx), scanProduct, query, from, until)
+	if err != nil {
+		return nil, productErrors.Map(err, "get publish transitions")
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("failed to iterate over products: %w", err)
+	return products, nil
+}
+
+func (r *ProductRepository) GetAfterID(ctx context.Context, afterID int64, limit int) ([]*domain.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+
+	defer explain.Track(ctx, query, afterID, limit)()
+	r.access.Record(domain.AccessPattern{Table: "products", Range: "id", Sort: []string{"id"}})
+	products, err := sqlutil.QueryMany(ctx, r.conn(ctx), scanProduct, query, afterID, limit)
+	if err != nil {
+		return nil, productErrors.Map(err, "get products")
 	}
 
 	return products, nil
 }
 
-func (r *ProductRepository) Update(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
+func (r *ProductRepository) GetAllByStore(ctx context.Context, storeID int64) ([]*domain.Product, error) {
 	query := `
-		UPDATE products
-		SET store_id = $1, name = $2, description = $3, amount = $4, price = $5, updated_at = NOW()
-		WHERE id = $6
-		RETURNING id, store_id, name, description, amount, price, created_at, updated_at
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE store_id = $1
+		ORDER BY id
 	`
 
-	row := r.db.QueryRowContext(ctx, query,
+	defer explain.Track(ctx, query, storeID)()
+	r.access.Record(domain.AccessPattern{Table: "products", Equals: []string{"store_id"}, Sort: []string{"id"}})
+	products, err := sqlutil.QueryMany(ctx, r.conn(ctx), scanProduct, query, storeID)
+	if err != nil {
+		return nil, productErrors.Map(err, "get store products")
+	}
+
+	return products, nil
+}
+
+func (r *ProductRepository) Update(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
+	query := withRevision(`
+		UPDATE products
+		SET store_id = $1, name = $2, description = $3,
+			description_format = COALESCE(NULLIF($4, ''), description_format), amount = $5,
+			unit = COALESCE(NULLIF($6, ''), unit), price = $7,
+			status = COALESCE(NULLIF($8, ''), status),
+			moderation_status = COALESCE(NULLIF($9, ''), moderation_status),
+			moderation_reason = CASE WHEN NULLIF($9, '') IS NULL THEN moderation_reason ELSE $10 END,
+			allow_backorder = $11, backorder_limit = $12, preorder_release_date = $13,
+			low_stock_threshold = $14, publish_at = $15, unpublish_at = $16,
+			updated_at = NOW(), version = version + 1
+		WHERE id = $17 AND ($18::bigint = 0 OR version = $18)
+		RETURNING ` + productColumns)
+
+	row := r.conn(ctx).QueryRowContext(ctx, query,
 		product.StoreID,
 		product.Name,
 		nullStringFromString(product.Description.String),
+		product.DescriptionFormat,
 		product.Amount,
+		product.Unit,
 		product.Price,
+		product.Status,
+		product.ModerationStatus,
+		product.ModerationReason,
+		product.AllowBackorder,
+		product.BackorderLimit,
+		product.PreorderReleaseDate,
+		product.LowStockThreshold,
+		product.PublishAt,
+		product.UnpublishAt,
 		id,
+		product.Version,
 	)
 
-	result := &domain.Product{}
-	err := row.Scan(
-		&result.ID,
-		&result.StoreID,
-		&result.Name,
-		&result.Description,
-		&result.Amount,
-		&result.Price,
-		&result.CreatedAt,
-		&result.UpdatedAt,
+	result, err := scanProduct(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, r.notWritten(ctx, id, product.Version)
+		}
+		return nil, productErrors.Map(err, "update product")
+	}
+
+	return result, nil
+}
+
+// Patch updates the fields the patch sets in one statement and leaves the
+// other columns as they are in the row, not as the caller last read them.
+// Unset fields are passed as NULL; the nullable columns take a flag.
+func (r *ProductRepository) Patch(ctx context.Context, id int64, patch *domain.ProductPatch) (*domain.Product, error) {
+	query := withRevision(`
+		UPDATE products
+		SET store_id = COALESCE($1, store_id), name = COALESCE($2, name),
+			description = CASE WHEN $3 THEN $4 ELSE description END,
+			description_format = COALESCE($5, description_format), amount = COALESCE($6, amount),
+			unit = COALESCE($7, unit), price = COALESCE($8, price), status = COALESCE($9, status),
+			allow_backorder = COALESCE($10, allow_backorder), backorder_limit = COALESCE($11, backorder_limit),
+			preorder_release_date = CASE WHEN $12 THEN $13 ELSE preorder_release_date END,
+			low_stock_threshold = COALESCE($14, low_stock_threshold),
+			moderation_status = COALESCE($15, moderation_status),
+			moderation_reason = CASE WHEN $15 IS NULL THEN moderation_reason ELSE $16 END,
+			publish_at = CASE WHEN $17 THEN $18 ELSE publish_at END,
+			unpublish_at = CASE WHEN $19 THEN $20 ELSE unpublish_at END,
+			updated_at = NOW(), version = version + 1
+		WHERE id = $21 AND ($22::bigint = 0 OR version = $22)
+		RETURNING ` + productColumns)
+
+	var description sql.NullString
+	if patch.Description != nil {
+		description = nullStringFromString(patch.Description.String)
+	}
+	var releaseDate, publishAt, unpublishAt sql.NullTime
+	if patch.PreorderReleaseDate != nil {
+		releaseDate = *patch.PreorderReleaseDate
+	}
+	if patch.PublishAt != nil {
+		publishAt = *patch.PublishAt
+	}
+	if patch.UnpublishAt != nil {
+		unpublishAt = *patch.UnpublishAt
+	}
+	var moderationStatus, moderationReason sql.NullString
+	if patch.Moderation != nil {
+		moderationStatus = sql.NullString{String: patch.Moderation.Status, Valid: true}
+		moderationReason = nullStringFromString(patch.Moderation.Reason)
+	}
+
+	row := r.conn(ctx).QueryRowContext(ctx, query,
+		patch.StoreID,
+		patch.Name,
+		patch.Description != nil,
+		description,
+		patch.DescriptionFormat,
+		patch.Amount,
+		patch.Unit,
+		patch.Price,
+		patch.Status,
+		patch.AllowBackorder,
+		patch.BackorderLimit,
+		patch.PreorderReleaseDate != nil,
+		releaseDate,
+		patch.LowStockThreshold,
+		moderationStatus,
+		moderationReason,
+		patch.PublishAt != nil,
+		publishAt,
+		patch.UnpublishAt != nil,
+		unpublishAt,
+		id,
+		patch.Version,
 	)
 
+	result, err := scanProduct(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, domain.ErrProductNotFound
-		}
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code {
-			case "23505":
-				return nil, domain.ErrDuplicateProduct
-			}
+			return nil, r.notWritten(ctx, id, patch.Version)
 		}
-		return nil, fmt.Errorf("failed to update product: %w", err)
+		return nil, productErrors.Map(err, "patch product")
 	}
 
 	return result, nil
 }
 
-func (r *ProductRepository) Delete(ctx context.Context, id int64) error {
-	query := `DELETE FROM products WHERE id = $1`
+// notWritten tells why an update of the product with id at version
+// matched no row: the product is gone, or it has moved past version.
+func (r *ProductRepository) notWritten(ctx context.Context, id, version int64) error {
+	if version == 0 {
+		return domain.ErrProductNotFound
+	}
+	var exists bool
+	err := r.conn(ctx).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check product version: %w", err)
+	}
+	if !exists {
+		return domain.ErrProductNotFound
+	}
+	return domain.ErrConflict
+}
+
+func (r *ProductRepository) GetByModerationStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Product, error) {
+	query := `
+		SELECT ` + productColumns + `
+		FROM products
+		WHERE moderation_status = $1
+		ORDER BY updated_at, id
+		LIMIT $2 OFFSET $3
+	`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	defer explain.Track(ctx, query, status, limit, offset)()
+	r.access.Record(domain.AccessPattern{Table: "products", Equals: []string{"moderation_status"}, Sort: []string{"updated_at", "id"}})
+	products, err := sqlutil.QueryMany(ctx, r.conn(ctx), scanProduct, query, status, limit, offset)
 	if err != nil {
-		return fmt.Errorf("failed to delete product: %w", err)
+		return nil, productErrors.Map(err, "get products by moderation status")
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	return products, nil
+}
+
+func (r *ProductRepository) UpdateModeration(ctx context.Context, id int64, result domain.ModerationResult) (*domain.Product, error) {
+	query := withRevision(`
+		UPDATE products
+		SET moderation_status = $1, moderation_reason = $2, updated_at = NOW()
+		WHERE id = $3
+		RETURNING ` + productColumns)
+
+	row := r.conn(ctx).QueryRowContext(ctx, query, result.Status, nullStringFromString(result.Reason), id)
+
+	product, err := scanProduct(row)
+	if err != nil {
+		return nil, productErrors.Map(err, "update product moderation")
+	}
+
+	return product, nil
+}
+
+// Delete moves the product into the recycle bin in a single statement, so it
+// is never lost between the two tables, and saves a tombstone revision. See
+// TrashRepository for restoring.
+func (r *ProductRepository) Delete(ctx context.Context, id int64) error {
+	query := `
+		WITH moved AS (
+			DELETE FROM products WHERE id = $1
+			RETURNING ` + productColumns + `
+		),
+		trashed AS (
+			INSERT INTO product_trash (` + productColumns + `, trashed_at)
+			SELECT ` + productColumns + `, NOW() FROM moved
+		)
+		INSERT INTO product_revisions (` + productColumns + `, deleted, recorded_at)
+		SELECT ` + productColumns + `, TRUE, NOW() FROM moved
+	`
+
+	rowsAffected, err := sqlutil.Exec(ctx, r.conn(ctx), query, id)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return deleteProductErrors.Map(err, "delete product")
 	}
 
 	if rowsAffected == 0 {
@@ -204,3 +683,33 @@ func nullStringFromString(s string) sql.NullString {
 	}
 	return sql.NullString{String: s, Valid: true}
 }
+
+func scanProduct(row sqlutil.Row) (*domain.Product, error) {
+	product := &domain.Product{}
+	err := row.Scan(
+		&product.ID,
+		&product.StoreID,
+		&product.Name,
+		&product.Description,
+		&product.DescriptionFormat,
+		&product.Amount,
+		&product.Unit,
+		&product.Price,
+		&product.Status,
+		&product.ModerationStatus,
+		&product.ModerationReason,
+		&product.AllowBackorder,
+		&product.BackorderLimit,
+		&product.PreorderReleaseDate,
+		&product.LowStockThreshold,
+		&product.PublishAt,
+		&product.UnpublishAt,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&product.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
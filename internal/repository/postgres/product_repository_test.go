@@ -3,12 +3,17 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/database/querytest"
+	"backend-context-engineering-template/pkg/quantity"
 
-	_ "github.com/lib/pq"
-	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -21,7 +26,7 @@ func setupTestDB(t *testing.T) *sql.DB {
 	// Use environment variables or test database configuration
 	dsn := "host=localhost port=5432 user=test_user password=test_password dbname=test_db sslmode=disable"
 
-	db, err := sql.Open("postgres", dsn)
+	db, err := database.OpenPostgres(dsn)
 	if err != nil {
 		t.Skipf("Cannot connect to test database: %v", err)
 	}
@@ -39,11 +44,112 @@ func setupTestDB(t *testing.T) *sql.DB {
 			description TEXT,
 			amount INTEGER NOT NULL DEFAULT 0,
 			price NUMERIC(12,2) NOT NULL,
+			description_format VARCHAR(20) NOT NULL DEFAULT 'plain',
+			status VARCHAR(20) NOT NULL DEFAULT 'active',
+			moderation_status VARCHAR(20) NOT NULL DEFAULT 'approved',
+			moderation_reason TEXT,
+			allow_backorder BOOLEAN NOT NULL DEFAULT FALSE,
+			backorder_limit NUMERIC(15,3) NOT NULL DEFAULT 0,
+			preorder_release_date TIMESTAMP NULL,
+			low_stock_threshold NUMERIC(15,3) NOT NULL DEFAULT 0,
+			publish_at TIMESTAMP NULL,
+			unpublish_at TIMESTAMP NULL,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		);
-		
+
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS description_format VARCHAR(20) NOT NULL DEFAULT 'plain';
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS moderation_status VARCHAR(20) NOT NULL DEFAULT 'approved';
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS moderation_reason TEXT;
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS allow_backorder BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS backorder_limit NUMERIC(15,3) NOT NULL DEFAULT 0;
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS preorder_release_date TIMESTAMP NULL;
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS low_stock_threshold NUMERIC(15,3) NOT NULL DEFAULT 0;
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS popularity_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP NULL;
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP NULL;
+		ALTER TABLE products ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+		CREATE TABLE IF NOT EXISTS product_trash (
+			id INTEGER PRIMARY KEY,
+			store_id INTEGER NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			description TEXT,
+			description_format VARCHAR(20) NOT NULL DEFAULT 'plain',
+			amount INTEGER NOT NULL DEFAULT 0,
+			price NUMERIC(12,2) NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'active',
+			moderation_status VARCHAR(20) NOT NULL DEFAULT 'approved',
+			moderation_reason TEXT,
+			allow_backorder BOOLEAN NOT NULL DEFAULT FALSE,
+			backorder_limit NUMERIC(15,3) NOT NULL DEFAULT 0,
+			preorder_release_date TIMESTAMP NULL,
+			low_stock_threshold NUMERIC(15,3) NOT NULL DEFAULT 0,
+			publish_at TIMESTAMP NULL,
+			unpublish_at TIMESTAMP NULL,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			version BIGINT NOT NULL DEFAULT 1,
+			trashed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		ALTER TABLE product_trash ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+		CREATE TABLE IF NOT EXISTS product_revisions (
+			revision BIGSERIAL PRIMARY KEY,
+			id BIGINT NOT NULL,
+			store_id INTEGER NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			description TEXT,
+			description_format VARCHAR(20) NOT NULL DEFAULT 'plain',
+			amount NUMERIC(15,3) NOT NULL DEFAULT 0,
+			unit VARCHAR(10) NOT NULL DEFAULT 'piece',
+			price NUMERIC(12,2) NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'active',
+			moderation_status VARCHAR(20) NOT NULL DEFAULT 'approved',
+			moderation_reason TEXT,
+			allow_backorder BOOLEAN NOT NULL DEFAULT FALSE,
+			backorder_limit NUMERIC(15,3) NOT NULL DEFAULT 0,
+			preorder_release_date TIMESTAMP NULL,
+			low_stock_threshold NUMERIC(15,3) NOT NULL DEFAULT 0,
+			publish_at TIMESTAMP NULL,
+			unpublish_at TIMESTAMP NULL,
+			created_at TIMESTAMP,
+			updated_at TIMESTAMP,
+			version BIGINT NOT NULL DEFAULT 1,
+			deleted BOOLEAN NOT NULL DEFAULT FALSE,
+			recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		ALTER TABLE product_revisions ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+		CREATE TABLE IF NOT EXISTS stores (
+			id BIGSERIAL PRIMARY KEY,
+			name VARCHAR(100) NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS categories (
+			id BIGSERIAL PRIMARY KEY,
+			parent_id BIGINT REFERENCES categories(id),
+			name VARCHAR(100) NOT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_parent_name ON categories (COALESCE(parent_id, 0), LOWER(name));
+
+		CREATE TABLE IF NOT EXISTS product_categories (
+			product_id BIGINT NOT NULL,
+			category_id BIGINT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (product_id, category_id)
+		);
+
 		TRUNCATE TABLE products RESTART IDENTITY;
+		TRUNCATE TABLE stores RESTART IDENTITY;
+		TRUNCATE TABLE product_trash;
+		TRUNCATE TABLE product_revisions;
+		TRUNCATE TABLE product_categories, categories RESTART IDENTITY;
 	`
 
 	_, err = db.Exec(createTableSQL)
@@ -56,8 +162,7 @@ func TestProductRepository_Integration(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
 
-	logger := logrus.New()
-	repo := NewProductRepository(db, logger)
+	repo := NewProductRepository(db, nil, nil)
 	ctx := context.Background()
 
 	t.Run("Create and Get Product", func(t *testing.T) {
@@ -65,7 +170,7 @@ func TestProductRepository_Integration(t *testing.T) {
 			StoreID:     1,
 			Name:        "Integration Test Product",
 			Description: sql.NullString{String: "Test Description", Valid: true},
-			Amount:      5,
+			Amount:      quantity.New(5),
 			Price:       19.99,
 		}
 
@@ -82,7 +187,7 @@ func TestProductRepository_Integration(t *testing.T) {
 		assert.NotZero(t, created.UpdatedAt)
 
 		// Test GetByID
-		retrieved, err := repo.GetByID(ctx, created.ID)
+		retrieved, err := repo.GetByID(querytest.Budget(t, ctx, 1), created.ID)
 		require.NoError(t, err)
 		assert.Equal(t, created.ID, retrieved.ID)
 		assert.Equal(t, created.StoreID, retrieved.StoreID)
@@ -92,6 +197,28 @@ func TestProductRepository_Integration(t *testing.T) {
 		assert.Equal(t, created.Price, retrieved.Price)
 	})
 
+	t.Run("Create Many Products", func(t *testing.T) {
+		products := []*domain.Product{
+			{StoreID: 1, Name: "Bulk Product A", Amount: quantity.New(1), Price: 1},
+			{StoreID: 1, Name: "Bulk Product B", Amount: quantity.New(2), Price: 2, Status: domain.ProductStatusDraft},
+			{StoreID: 1, Name: "Bulk Product C", Amount: quantity.New(3), Price: 3},
+		}
+
+		created, err := repo.CreateMany(ctx, products)
+		require.NoError(t, err)
+		require.Len(t, created, 3)
+		for i, product := range created {
+			assert.NotZero(t, product.ID)
+			assert.Equal(t, products[i].Name, product.Name, "results are in the order of the input")
+		}
+		assert.Equal(t, domain.ProductStatusDraft, created[1].Status)
+		assert.Equal(t, domain.ProductStatusActive, created[2].Status)
+
+		retrieved, err := repo.GetByID(ctx, created[2].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Bulk Product C", retrieved.Name)
+	})
+
 	t.Run("Get Nonexistent Product", func(t *testing.T) {
 		_, err := repo.GetByID(ctx, 99999)
 		assert.ErrorIs(t, err, domain.ErrProductNotFound)
@@ -103,7 +230,7 @@ func TestProductRepository_Integration(t *testing.T) {
 			StoreID:     1,
 			Name:        "Original Product",
 			Description: sql.NullString{String: "Original Description", Valid: true},
-			Amount:      10,
+			Amount:      quantity.New(10),
 			Price:       29.99,
 		}
 
@@ -115,7 +242,7 @@ func TestProductRepository_Integration(t *testing.T) {
 			StoreID:     1,
 			Name:        "Updated Product",
 			Description: sql.NullString{String: "Updated Description", Valid: true},
-			Amount:      15,
+			Amount:      quantity.New(15),
 			Price:       39.99,
 		}
 
@@ -133,7 +260,7 @@ func TestProductRepository_Integration(t *testing.T) {
 		updateData := &domain.Product{
 			StoreID: 1,
 			Name:    "Updated Product",
-			Amount:  15,
+			Amount:  quantity.New(15),
 			Price:   39.99,
 		}
 
@@ -141,12 +268,65 @@ func TestProductRepository_Integration(t *testing.T) {
 		assert.ErrorIs(t, err, domain.ErrProductNotFound)
 	})
 
+	t.Run("Update Against a Stale Version", func(t *testing.T) {
+		created, err := repo.Create(ctx, &domain.Product{StoreID: 1, Name: "Versioned Product", Amount: quantity.New(1), Price: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), created.Version)
+
+		update := &domain.Product{StoreID: 1, Name: "Versioned Product", Amount: quantity.New(2), Price: 12, Version: created.Version}
+		updated, err := repo.Update(ctx, created.ID, update)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Version)
+
+		// A second editor still holding version 1 must not overwrite it.
+		update.Price = 11
+		_, err = repo.Update(ctx, created.ID, update)
+		assert.ErrorIs(t, err, domain.ErrConflict)
+
+		price := 11.0
+		_, err = repo.Patch(ctx, created.ID, &domain.ProductPatch{Price: &price, Version: created.Version})
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		patched, err := repo.Patch(ctx, created.ID, &domain.ProductPatch{Price: &price, Version: updated.Version})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), patched.Version)
+
+		_, err = repo.Update(ctx, 99999, update)
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	})
+
+	t.Run("Patch Product", func(t *testing.T) {
+		created, err := repo.Create(ctx, &domain.Product{
+			StoreID:     1,
+			Name:        "Patched Product",
+			Description: sql.NullString{String: "Kept Description", Valid: true},
+			Amount:      quantity.New(10),
+			Price:       29.99,
+		})
+		require.NoError(t, err)
+
+		price := 24.5
+		patched, err := repo.Patch(ctx, created.ID, &domain.ProductPatch{Price: &price})
+		require.NoError(t, err)
+		assert.Equal(t, 24.5, patched.Price)
+		assert.Equal(t, created.Name, patched.Name)
+		assert.Equal(t, created.Description, patched.Description)
+		assert.Equal(t, created.Amount, patched.Amount)
+
+		cleared, err := repo.Patch(ctx, created.ID, &domain.ProductPatch{Description: &sql.NullString{}})
+		require.NoError(t, err)
+		assert.False(t, cleared.Description.Valid)
+		assert.Equal(t, 24.5, cleared.Price)
+
+		_, err = repo.Patch(ctx, 99999, &domain.ProductPatch{Price: &price})
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	})
+
 	t.Run("Delete Product", func(t *testing.T) {
 		// Create a product first
 		product := &domain.Product{
 			StoreID: 1,
 			Name:    "Product to Delete",
-			Amount:  5,
+			Amount:  quantity.New(5),
 			Price:   19.99,
 		}
 
@@ -162,6 +342,39 @@ func TestProductRepository_Integration(t *testing.T) {
 		assert.ErrorIs(t, err, domain.ErrProductNotFound)
 	})
 
+	t.Run("Delete Moves Product to Trash and Restore", func(t *testing.T) {
+		trashRepo := NewTrashRepository(db, nil)
+
+		created, err := repo.Create(ctx, &domain.Product{
+			StoreID: 3,
+			Name:    "Product to Trash",
+			Amount:  quantity.New(5),
+			Price:   19.99,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, repo.Delete(ctx, created.ID))
+
+		trashed, err := trashRepo.GetAll(ctx, 3, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, trashed, 1)
+		assert.Equal(t, created.ID, trashed[0].ID)
+		assert.NotZero(t, trashed[0].TrashedAt)
+
+		restored, err := trashRepo.Restore(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, restored.ID)
+		assert.Equal(t, created.Name, restored.Name)
+
+		_, err = trashRepo.Restore(ctx, created.ID)
+		assert.ErrorIs(t, err, domain.ErrTrashedProductNotFound)
+
+		require.NoError(t, repo.Delete(ctx, created.ID))
+		emptied, err := trashRepo.Empty(ctx, 3)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), emptied)
+	})
+
 	t.Run("Delete Nonexistent Product", func(t *testing.T) {
 		err := repo.Delete(ctx, 99999)
 		assert.ErrorIs(t, err, domain.ErrProductNotFound)
@@ -173,9 +386,9 @@ func TestProductRepository_Integration(t *testing.T) {
 
 		// Create multiple products
 		products := []*domain.Product{
-			{StoreID: 1, Name: "Product 1", Amount: 5, Price: 19.99},
-			{StoreID: 1, Name: "Product 2", Amount: 10, Price: 29.99},
-			{StoreID: 2, Name: "Product 3", Amount: 15, Price: 39.99},
+			{StoreID: 1, Name: "Product 1", Amount: quantity.New(5), Price: 19.99},
+			{StoreID: 1, Name: "Product 2", Amount: quantity.New(10), Price: 29.99},
+			{StoreID: 2, Name: "Product 3", Amount: quantity.New(15), Price: 39.99},
 		}
 
 		for _, p := range products {
@@ -183,18 +396,19 @@ func TestProductRepository_Integration(t *testing.T) {
 			require.NoError(t, err)
 		}
 
-		// Test GetAll with no limit
-		all, err := repo.GetAll(ctx, 10, 0)
+		// Test GetAll with no limit; a page is one query however many
+		// products it holds
+		all, err := repo.GetAll(querytest.Budget(t, ctx, 1), domain.ProductFilter{}, time.Now(), 10, 0)
 		require.NoError(t, err)
 		assert.Len(t, all, 3)
 
 		// Test GetAll with limit
-		limited, err := repo.GetAll(ctx, 2, 0)
+		limited, err := repo.GetAll(ctx, domain.ProductFilter{}, time.Now(), 2, 0)
 		require.NoError(t, err)
 		assert.Len(t, limited, 2)
 
 		// Test GetAll with offset
-		offset, err := repo.GetAll(ctx, 10, 1)
+		offset, err := repo.GetAll(ctx, domain.ProductFilter{}, time.Now(), 10, 1)
 		require.NoError(t, err)
 		assert.Len(t, offset, 2)
 
@@ -202,12 +416,81 @@ func TestProductRepository_Integration(t *testing.T) {
 		assert.True(t, all[0].CreatedAt.After(all[1].CreatedAt) || all[0].CreatedAt.Equal(all[1].CreatedAt))
 	})
 
+	t.Run("Filter Products", func(t *testing.T) {
+		db.Exec("TRUNCATE TABLE products RESTART IDENTITY")
+
+		products := []*domain.Product{
+			{StoreID: 1, Name: "Blue Shirt", Amount: quantity.New(5), Price: 19.99},
+			{StoreID: 1, Name: "Red shirt", Amount: quantity.New(0), Price: 29.99},
+			{StoreID: 2, Name: "Shirt_100%", Amount: quantity.New(15), Price: 39.99},
+		}
+		for _, p := range products {
+			_, err := repo.Create(ctx, p)
+			require.NoError(t, err)
+		}
+
+		storeID := int64(1)
+		minPrice, maxPrice := 20.0, 40.0
+		inStock := true
+
+		byName, err := repo.GetAll(ctx, domain.ProductFilter{Name: "SHIRT", StoreID: &storeID}, time.Now(), 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, byName, 2)
+
+		byPrice, err := repo.GetAll(ctx, domain.ProductFilter{MinPrice: &minPrice, MaxPrice: &maxPrice, InStock: &inStock}, time.Now(), 10, 0)
+		require.NoError(t, err)
+		require.Len(t, byPrice, 1)
+		assert.Equal(t, "Shirt_100%", byPrice[0].Name)
+
+		// Wildcards in the name match literally.
+		literal, err := repo.GetAll(ctx, domain.ProductFilter{Name: "_100%"}, time.Now(), 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, literal, 1)
+
+		_, err = db.ExecContext(ctx, "UPDATE products SET popularity_score = 10 - id")
+		require.NoError(t, err)
+		popular, err := repo.GetAll(ctx, domain.ProductFilter{Sort: domain.ProductSortPopularity}, time.Now(), 10, 0)
+		require.NoError(t, err)
+		require.Len(t, popular, 3)
+		assert.Equal(t, "Blue Shirt", popular[0].Name)
+	})
+
+	t.Run("Publishing Windows", func(t *testing.T) {
+		db.Exec("TRUNCATE TABLE products RESTART IDENTITY")
+
+		now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+		launch := now.Add(time.Hour)
+		for _, p := range []*domain.Product{
+			{StoreID: 1, Name: "Listed", Amount: quantity.New(5), Price: 19.99},
+			{StoreID: 1, Name: "Launching", Amount: quantity.New(5), Price: 29.99, PublishAt: &launch},
+			{StoreID: 1, Name: "Retiring", Amount: quantity.New(5), Price: 39.99, UnpublishAt: &launch},
+		} {
+			_, err := repo.Create(ctx, p)
+			require.NoError(t, err)
+		}
+
+		before, err := repo.GetAll(ctx, domain.ProductFilter{}, now, 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, before, 2)
+
+		storeID := int64(1)
+		after, err := repo.GetAll(ctx, domain.ProductFilter{StoreID: &storeID}, launch, 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, after, 2)
+
+		transitions, err := repo.GetPublishTransitions(ctx, now, launch)
+		require.NoError(t, err)
+		require.Len(t, transitions, 2)
+		assert.Equal(t, "Launching", transitions[0].Name)
+		assert.True(t, transitions[0].PublishAt.Equal(launch))
+	})
+
 	t.Run("Product with Null Description", func(t *testing.T) {
 		product := &domain.Product{
 			StoreID:     1,
 			Name:        "Product with No Description",
 			Description: sql.NullString{Valid: false},
-			Amount:      5,
+			Amount:      quantity.New(5),
 			Price:       19.99,
 		}
 
@@ -219,4 +502,202 @@ func TestProductRepository_Integration(t *testing.T) {
 		require.NoError(t, err)
 		assert.False(t, retrieved.Description.Valid)
 	})
+
+	t.Run("Revisions Diff Writes and Deletes", func(t *testing.T) {
+		revisionRepo := NewProductRevisionRepository(db)
+
+		kept, err := repo.Create(ctx, &domain.Product{StoreID: 4, Name: "Kept", Amount: quantity.New(5), Price: 1})
+		require.NoError(t, err)
+		removed, err := repo.Create(ctx, &domain.Product{StoreID: 4, Name: "Removed", Amount: quantity.New(5), Price: 1})
+		require.NoError(t, err)
+
+		var from time.Time
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT NOW()::timestamp").Scan(&from))
+		time.Sleep(10 * time.Millisecond)
+
+		kept.Price = 2
+		_, err = repo.Update(ctx, kept.ID, kept)
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, removed.ID))
+		added, err := repo.Create(ctx, &domain.Product{StoreID: 4, Name: "Added", Amount: quantity.New(5), Price: 1})
+		require.NoError(t, err)
+
+		var to time.Time
+		require.NoError(t, db.QueryRowContext(ctx, "SELECT NOW()::timestamp").Scan(&to))
+
+		changes, err := revisionRepo.GetChanges(ctx, from, to)
+		require.NoError(t, err)
+		byID := make(map[int64]domain.ProductChange)
+		for _, change := range changes {
+			byID[change.ProductID] = change
+		}
+
+		require.Contains(t, byID, kept.ID)
+		assert.Equal(t, []string{"price"}, domain.ChangedFields(byID[kept.ID].Before, byID[kept.ID].After))
+		require.Contains(t, byID, removed.ID)
+		assert.NotNil(t, byID[removed.ID].Before)
+		assert.Nil(t, byID[removed.ID].After)
+		require.Contains(t, byID, added.ID)
+		assert.Nil(t, byID[added.ID].Before)
+		assert.NotNil(t, byID[added.ID].After)
+	})
+
+	t.Run("Writes Join the Caller's Transaction", func(t *testing.T) {
+		txManager := database.NewTxManager(db)
+		failed := errors.New("later step failed")
+
+		var rolledBack *domain.Product
+		err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			rolledBack, err = repo.Create(ctx, &domain.Product{StoreID: 4, Name: "Rolled back", Amount: quantity.New(5), Price: 1})
+			require.NoError(t, err)
+			_, err = repo.GetByID(ctx, rolledBack.ID)
+			require.NoError(t, err)
+			return failed
+		})
+		assert.ErrorIs(t, err, failed)
+		_, err = repo.GetByID(ctx, rolledBack.ID)
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+
+		var committed *domain.Product
+		hooked := false
+		err = txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			committed, err = repo.Create(ctx, &domain.Product{StoreID: 4, Name: "Committed", Amount: quantity.New(5), Price: 1})
+			database.AfterCommit(ctx, func() { hooked = true })
+			assert.False(t, hooked)
+			return err
+		})
+		require.NoError(t, err)
+		assert.True(t, hooked)
+		_, err = repo.GetByID(ctx, committed.ID)
+		assert.NoError(t, err)
+	})
+}
+
+func TestStoreRepository_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewStoreRepository(db)
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &domain.Store{Name: "Corner Shop"})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+
+	updated, err := repo.Update(ctx, created.ID, &domain.Store{Name: "Corner Market"})
+	require.NoError(t, err)
+	assert.Equal(t, "Corner Market", updated.Name)
+
+	stores, err := repo.GetAll(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, stores, 1)
+
+	require.NoError(t, repo.Delete(ctx, created.ID))
+	_, err = repo.GetByID(ctx, created.ID)
+	assert.ErrorIs(t, err, domain.ErrStoreNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, created.ID), domain.ErrStoreNotFound)
+}
+
+func TestCategoryRepository_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewCategoryRepository(db)
+	products := NewProductRepository(db, nil, nil)
+	ctx := context.Background()
+
+	drinks, err := repo.Create(ctx, &domain.Category{Name: "Drinks"})
+	require.NoError(t, err)
+	tea, err := repo.Create(ctx, &domain.Category{Name: "Tea", ParentID: sql.NullInt64{Int64: drinks.ID, Valid: true}})
+	require.NoError(t, err)
+	green, err := repo.Create(ctx, &domain.Category{Name: "Green", ParentID: sql.NullInt64{Int64: tea.ID, Valid: true}})
+	require.NoError(t, err)
+
+	_, err = repo.Create(ctx, &domain.Category{Name: "TEA", ParentID: sql.NullInt64{Int64: drinks.ID, Valid: true}})
+	assert.ErrorIs(t, err, domain.ErrDuplicateCategory)
+	_, err = repo.Create(ctx, &domain.Category{Name: "Coffee", ParentID: sql.NullInt64{Int64: 999, Valid: true}})
+	assert.ErrorIs(t, err, domain.ErrParentCategoryNotFound)
+
+	// A category cannot move under its own subcategory.
+	_, _, err = repo.Update(ctx, drinks.ID, &domain.Category{Name: "Drinks", ParentID: sql.NullInt64{Int64: green.ID, Valid: true}})
+	assert.ErrorIs(t, err, domain.ErrCategoryCycle)
+
+	sencha, err := products.Create(ctx, &domain.Product{StoreID: 1, Name: "Sencha", Amount: quantity.New(5), Price: 4.99})
+	require.NoError(t, err)
+	_, err = products.Create(ctx, &domain.Product{StoreID: 1, Name: "Mug", Amount: quantity.New(5), Price: 9.99})
+	require.NoError(t, err)
+
+	// Putting a product in categories is a change to the product.
+	set, touched, err := repo.SetForProduct(ctx, sencha.ID, []int64{green.ID})
+	require.NoError(t, err)
+	require.Len(t, set, 1)
+	assert.Equal(t, sencha.Version+1, touched.Version)
+	_, _, err = repo.SetForProduct(ctx, sencha.ID, []int64{999})
+	assert.ErrorIs(t, err, domain.ErrCategoryNotFound)
+	_, _, err = repo.SetForProduct(ctx, 999, []int64{green.ID})
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+
+	// Filtering on a category includes its subcategories.
+	inDrinks, err := products.GetAll(ctx, domain.ProductFilter{CategoryID: &drinks.ID}, time.Now(), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, inDrinks, 1)
+	assert.Equal(t, "Sencha", inDrinks[0].Name)
+
+	// Renaming or moving a category changes the products below it.
+	_, moved, err := repo.Update(ctx, tea.ID, &domain.Category{Name: "Teas", ParentID: sql.NullInt64{Int64: drinks.ID, Valid: true}})
+	require.NoError(t, err)
+	require.Len(t, moved, 1)
+	assert.Equal(t, sencha.ID, moved[0].ID)
+	assert.Equal(t, touched.Version+1, moved[0].Version)
+
+	_, err = repo.Delete(ctx, tea.ID)
+	assert.ErrorIs(t, err, domain.ErrCategoryHasChildren)
+	unchanged, err := products.GetByID(ctx, sencha.ID)
+	require.NoError(t, err)
+	assert.Equal(t, moved[0].Version, unchanged.Version, "a refused delete bumps nothing")
+
+	deleted, err := repo.Delete(ctx, green.ID)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, moved[0].Version+1, deleted[0].Version)
+	remaining, err := repo.GetByProduct(ctx, sencha.ID)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
+
+func TestProductRepository_ConcurrentUpdates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	const writers, rounds = 16, 20
+
+	repo := NewProductRepository(db, nil, nil)
+	ctx := context.Background()
+
+	created, err := repo.Create(ctx, &domain.Product{StoreID: 1, Name: "writer-0", Amount: quantity.New(0), Price: 0})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for w := 1; w <= writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				n := w*rounds + r
+				_, err := repo.Update(ctx, created.ID, &domain.Product{StoreID: 1, Name: fmt.Sprintf("writer-%d", n), Amount: quantity.New(int64(n)), Price: float64(n)})
+				assert.NoError(t, err)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// Every field must come from the same update: the last one to commit.
+	final, err := repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("writer-%s", final.Amount), final.Name)
+	assert.Equal(t, final.Amount.Float64(), final.Price)
+	assert.GreaterOrEqual(t, final.Amount.Whole(), int64(rounds))
+	assert.False(t, final.UpdatedAt.Before(created.UpdatedAt))
 }
@@ -0,0 +1,200 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+const reconciliationRunColumns = `id, store_id, source, source_id, policy, status, checked, discrepancies, healed, failed, error, started_at, finished_at`
+
+type ReconciliationRepository struct {
+	db *sql.DB
+}
+
+func NewReconciliationRepository(db *sql.DB) *ReconciliationRepository {
+	return &ReconciliationRepository{
+		db: db,
+	}
+}
+
+func (r *ReconciliationRepository) CreateRun(ctx context.Context, run *domain.ReconciliationRun) (*domain.ReconciliationRun, error) {
+	query := `
+		INSERT INTO reconciliation_runs (store_id, source, source_id, policy, status, started_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + reconciliationRunColumns
+
+	result, err := scanReconciliationRun(r.db.QueryRowContext(ctx, query,
+		run.StoreID,
+		run.Source,
+		run.SourceID,
+		run.Policy,
+		run.Status,
+		run.StartedAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reconciliation run: %w", err)
+	}
+
+	return result, nil
+}
+
+// FinishRun records the run's outcome together with the discrepancies it
+// kept, in one transaction.
+func (r *ReconciliationRepository) FinishRun(ctx context.Context, run *domain.ReconciliationRun, discrepancies []*domain.Discrepancy) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin reconciliation transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE reconciliation_runs
+		SET status = $1, checked = $2, discrepancies = $3, healed = $4, failed = $5, error = $6, finished_at = $7
+		WHERE id = $8
+	`,
+		run.Status,
+		run.Checked,
+		run.Discrepancies,
+		run.Healed,
+		run.Failed,
+		run.Error,
+		run.FinishedAt,
+		run.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish reconciliation run: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrReconciliationNotFound
+	}
+
+	if len(discrepancies) > 0 {
+		stmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO reconciliation_discrepancies (run_id, product_id, name, kind, field, local_value, source_value, healed)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare discrepancy insert: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, d := range discrepancies {
+			if _, err := stmt.ExecContext(ctx, run.ID, d.ProductID, d.Name, d.Kind, d.Field, d.LocalValue, d.SourceValue, d.Healed); err != nil {
+				return fmt.Errorf("failed to save discrepancy: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit reconciliation run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ReconciliationRepository) GetRun(ctx context.Context, id int64) (*domain.ReconciliationRun, error) {
+	query := `SELECT ` + reconciliationRunColumns + ` FROM reconciliation_runs WHERE id = $1`
+
+	run, err := scanReconciliationRun(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrReconciliationNotFound
+		}
+		return nil, fmt.Errorf("failed to get reconciliation run: %w", err)
+	}
+
+	return run, nil
+}
+
+func (r *ReconciliationRepository) GetRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, error) {
+	query := `
+		SELECT ` + reconciliationRunColumns + `
+		FROM reconciliation_runs
+		ORDER BY started_at DESC, id DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliation runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*domain.ReconciliationRun
+	for rows.Next() {
+		run, err := scanReconciliationRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reconciliation run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over reconciliation runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+func (r *ReconciliationRepository) GetDiscrepancies(ctx context.Context, runID int64, limit, offset int) ([]*domain.Discrepancy, error) {
+	query := `
+		SELECT id, run_id, product_id, name, kind, field, local_value, source_value, healed
+		FROM reconciliation_discrepancies
+		WHERE run_id = $1
+		ORDER BY id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, runID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get discrepancies: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*domain.Discrepancy
+	for rows.Next() {
+		d := &domain.Discrepancy{}
+		if err := rows.Scan(&d.ID, &d.RunID, &d.ProductID, &d.Name, &d.Kind, &d.Field, &d.LocalValue, &d.SourceValue, &d.Healed); err != nil {
+			return nil, fmt.Errorf("failed to scan discrepancy: %w", err)
+		}
+		list = append(list, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over discrepancies: %w", err)
+	}
+
+	return list, nil
+}
+
+func scanReconciliationRun(row rowScanner) (*domain.ReconciliationRun, error) {
+	run := &domain.ReconciliationRun{}
+	err := row.Scan(
+		&run.ID,
+		&run.StoreID,
+		&run.Source,
+		&run.SourceID,
+		&run.Policy,
+		&run.Status,
+		&run.Checked,
+		&run.Discrepancies,
+		&run.Healed,
+		&run.Failed,
+		&run.Error,
+		&run.StartedAt,
+		&run.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
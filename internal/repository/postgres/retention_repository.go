@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"github.com/lib/pq"
+)
+
+// RetentionRepository applies retention rules. Rule tables and columns are
+// fixed in code, never user input, and are quoted regardless.
+type RetentionRepository struct {
+	db *sql.DB
+}
+
+func NewRetentionRepository(db *sql.DB) *RetentionRepository {
+	return &RetentionRepository{
+		db: db,
+	}
+}
+
+func (r *RetentionRepository) CountExpired(ctx context.Context, rule domain.RetentionRule, cutoff time.Time) (int64, sql.NullTime, error) {
+	column := pq.QuoteIdentifier(rule.TimeColumn)
+	query := `SELECT COUNT(*), MIN(` + column + `) FROM ` + pq.QuoteIdentifier(rule.Table) + ` WHERE ` + expiredCondition(rule)
+
+	var count int64
+	var oldest sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, cutoff).Scan(&count, &oldest); err != nil {
+		return 0, sql.NullTime{}, fmt.Errorf("failed to count expired %s: %w", rule.Table, err)
+	}
+
+	return count, oldest, nil
+}
+
+// DeleteExpired deletes the oldest expired rows first, so an interrupted
+// purge still leaves the table ordered by age.
+func (r *RetentionRepository) DeleteExpired(ctx context.Context, rule domain.RetentionRule, cutoff time.Time, limit int) (int64, error) {
+	table := pq.QuoteIdentifier(rule.Table)
+	query := `
+		DELETE FROM ` + table + `
+		WHERE id IN (
+			SELECT id FROM ` + table + `
+			WHERE ` + expiredCondition(rule) + `
+			ORDER BY ` + pq.QuoteIdentifier(rule.TimeColumn) + `
+			LIMIT $2
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, cutoff, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired %s: %w", rule.Table, err)
+	}
+
+	return result.RowsAffected()
+}
+
+func expiredCondition(rule domain.RetentionRule) string {
+	condition := pq.QuoteIdentifier(rule.TimeColumn) + ` < $1`
+	if rule.Condition != "" {
+		condition += ` AND ` + rule.Condition
+	}
+	return condition
+}
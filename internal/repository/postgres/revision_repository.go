@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// recordRevision is a common table expression that saves the rows of a
+// preceding "written" expression to product_revisions. Statements that
+// write products include it so each write and its revision commit
+// together.
+const recordRevision = `
+	revision AS (
+		INSERT INTO product_revisions (` + productColumns + `, deleted, recorded_at)
+		SELECT ` + productColumns + `, FALSE, NOW() FROM written
+	)`
+
+// withRevision wraps a statement that writes products and returns
+// productColumns so the rows it writes are also saved as revisions. It
+// returns the same rows.
+func withRevision(write string) string {
+	return `
+		WITH written AS (` + write + `), ` + recordRevision + `
+		SELECT ` + productColumns + ` FROM written
+	`
+}
+
+// ProductRevisionRepository reads the product revisions that the product,
+// trash and bundle repositories save with every write.
+type ProductRevisionRepository struct {
+	db *sql.DB
+}
+
+func NewProductRevisionRepository(db *sql.DB) *ProductRevisionRepository {
+	return &ProductRevisionRepository{
+		db: db,
+	}
+}
+
+// GetChanges returns the state at from and at to of every product with a
+// revision after from and up to to, in product ID order. A product's state
+// at a time is its latest revision up to then, unless that is a tombstone.
+func (r *ProductRevisionRepository) GetChanges(ctx context.Context, from, to time.Time) ([]domain.ProductChange, error) {
+	query := `
+		WITH touched AS (
+			SELECT DISTINCT id FROM product_revisions
+			WHERE recorded_at > $1 AND recorded_at <= $2
+		),
+		edges AS (
+			SELECT DISTINCT ON (r.id, edge.at) edge.at = $2 AS at_end, r.*
+			FROM touched t
+			CROSS JOIN (VALUES ($1::timestamp), ($2::timestamp)) AS edge(at)
+			JOIN product_revisions r ON r.id = t.id AND r.recorded_at <= edge.at
+			ORDER BY r.id, edge.at, r.recorded_at DESC, r.revision DESC
+		)
+		SELECT at_end, deleted, ` + productColumns + `
+		FROM edges
+		ORDER BY id, at_end
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []domain.ProductChange
+	for rows.Next() {
+		var atEnd, deleted bool
+		product, err := scanProduct(revisionScanner{rows, &atEnd, &deleted})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product revision: %w", err)
+		}
+
+		if len(changes) == 0 || changes[len(changes)-1].ProductID != product.ID {
+			changes = append(changes, domain.ProductChange{ProductID: product.ID})
+		}
+		if deleted {
+			continue
+		}
+		change := &changes[len(changes)-1]
+		if atEnd {
+			change.After = product
+		} else {
+			change.Before = product
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over product revisions: %w", err)
+	}
+
+	return changes, nil
+}
+
+// revisionScanner scans the at_end and deleted columns in front of the
+// product columns.
+type revisionScanner struct {
+	rows    *sql.Rows
+	atEnd   *bool
+	deleted *bool
+}
+
+func (s revisionScanner) Scan(dest ...any) error {
+	return s.rows.Scan(append([]any{s.atEnd, s.deleted}, dest...)...)
+}
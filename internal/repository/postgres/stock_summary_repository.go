@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"github.com/lib/pq"
+)
+
+const stockSummaryColumns = `store_id, products, in_stock, low_stock, backorder, out_of_stock, discontinued, calculated_at`
+
+type StockSummaryRepository struct {
+	db *sql.DB
+}
+
+func NewStockSummaryRepository(db *sql.DB) *StockSummaryRepository {
+	return &StockSummaryRepository{
+		db: db,
+	}
+}
+
+// Recalculate counts the store's products and saves the summary as of
+// calculatedAt. A summary calculated later is kept, so a slow run cannot
+// overwrite a newer one.
+func (r *StockSummaryRepository) Recalculate(ctx context.Context, storeID int64, calculatedAt time.Time) (*domain.StockSummary, error) {
+	query := `
+		INSERT INTO store_stock_summaries (` + stockSummaryColumns + `)
+		SELECT $1,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE stock_availability = 'in_stock'),
+			COUNT(*) FILTER (WHERE stock_availability = 'low_stock'),
+			COUNT(*) FILTER (WHERE stock_availability = 'backorder'),
+			COUNT(*) FILTER (WHERE stock_availability = 'out_of_stock'),
+			COUNT(*) FILTER (WHERE stock_availability = 'discontinued'),
+			$2
+		FROM products
+		WHERE store_id = $1
+		ON CONFLICT (store_id) DO UPDATE
+		SET products = EXCLUDED.products,
+			in_stock = EXCLUDED.in_stock,
+			low_stock = EXCLUDED.low_stock,
+			backorder = EXCLUDED.backorder,
+			out_of_stock = EXCLUDED.out_of_stock,
+			discontinued = EXCLUDED.discontinued,
+			calculated_at = EXCLUDED.calculated_at
+		WHERE store_stock_summaries.calculated_at <= EXCLUDED.calculated_at
+		RETURNING ` + stockSummaryColumns
+
+	summary, err := scanStockSummary(r.db.QueryRowContext(ctx, query, storeID, calculatedAt.UTC()))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return nil, domain.ErrStoreNotFound
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			// A newer summary was saved meanwhile.
+			return r.GetByStoreID(ctx, storeID)
+		}
+		return nil, fmt.Errorf("failed to recalculate stock summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+func (r *StockSummaryRepository) GetByStoreID(ctx context.Context, storeID int64) (*domain.StockSummary, error) {
+	query := `SELECT ` + stockSummaryColumns + ` FROM store_stock_summaries WHERE store_id = $1`
+
+	summary, err := scanStockSummary(r.db.QueryRowContext(ctx, query, storeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrStockSummaryNotFound
+		}
+		return nil, fmt.Errorf("failed to get stock summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+func scanStockSummary(row rowScanner) (*domain.StockSummary, error) {
+	summary := &domain.StockSummary{}
+	err := row.Scan(
+		&summary.StoreID,
+		&summary.Products,
+		&summary.InStock,
+		&summary.LowStock,
+		&summary.Backorder,
+		&summary.OutOfStock,
+		&summary.Discontinued,
+		&summary.CalculatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
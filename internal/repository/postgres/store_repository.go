@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/sqlutil"
+)
+
+const storeColumns = `id, name, created_at, updated_at`
+
+// storeErrors maps the errors of reading and writing stores. Deleting a
+// store that still has products violates their foreign key.
+var storeErrors = sqlutil.Errors{
+	NoRows: domain.ErrStoreNotFound,
+	Codes:  map[string]error{sqlutil.ForeignKeyViolation: domain.ErrStoreHasProducts},
+}
+
+type StoreRepository struct {
+	db *sql.DB
+}
+
+func NewStoreRepository(db *sql.DB) *StoreRepository {
+	return &StoreRepository{
+		db: db,
+	}
+}
+
+func (r *StoreRepository) Create(ctx context.Context, store *domain.Store) (*domain.Store, error) {
+	query := `
+		INSERT INTO stores (name, created_at, updated_at)
+		VALUES ($1, NOW(), NOW())
+		RETURNING ` + storeColumns
+
+	created, err := sqlutil.QueryOne(ctx, r.db, scanStore, query, store.Name)
+	if err != nil {
+		return nil, storeErrors.Map(err, "create store")
+	}
+
+	return created, nil
+}
+
+func (r *StoreRepository) GetByID(ctx context.Context, id int64) (*domain.Store, error) {
+	query := `SELECT ` + storeColumns + ` FROM stores WHERE id = $1`
+
+	store, err := sqlutil.QueryOne(ctx, r.db, scanStore, query, id)
+	if err != nil {
+		return nil, storeErrors.Map(err, "get store")
+	}
+
+	return store, nil
+}
+
+func (r *StoreRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Store, error) {
+	query := `
+		SELECT ` + storeColumns + `
+		FROM stores
+		ORDER BY id
+		LIMIT $1 OFFSET $2
+	`
+
+	stores, err := sqlutil.QueryMany(ctx, r.db, scanStore, query, limit, offset)
+	if err != nil {
+		return nil, storeErrors.Map(err, "get stores")
+	}
+
+	return stores, nil
+}
+
+func (r *StoreRepository) Update(ctx context.Context, id int64, store *domain.Store) (*domain.Store, error) {
+	query := `
+		UPDATE stores
+		SET name = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING ` + storeColumns
+
+	updated, err := sqlutil.QueryOne(ctx, r.db, scanStore, query, store.Name, id)
+	if err != nil {
+		return nil, storeErrors.Map(err, "update store")
+	}
+
+	return updated, nil
+}
+
+// Delete removes a store. The products foreign key refuses while the store
+// still has products; trashed products are not counted and can no longer
+// be restored.
+func (r *StoreRepository) Delete(ctx context.Context, id int64) error {
+	rowsAffected, err := sqlutil.Exec(ctx, r.db, `DELETE FROM stores WHERE id = $1`, id)
+	if err != nil {
+		return storeErrors.Map(err, "delete store")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrStoreNotFound
+	}
+
+	return nil
+}
+
+func scanStore(row sqlutil.Row) (*domain.Store, error) {
+	store := &domain.Store{}
+	err := row.Scan(
+		&store.ID,
+		&store.Name,
+		&store.CreatedAt,
+		&store.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return store, nil
+}
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/indexadvisor"
+	"backend-context-engineering-template/pkg/explain"
+	"github.com/lib/pq"
+)
+
+type TrashRepository struct {
+	db     *sql.DB
+	access *indexadvisor.Tracker
+}
+
+// NewTrashRepository counts list queries in access, which may be nil.
+func NewTrashRepository(db *sql.DB, access *indexadvisor.Tracker) *TrashRepository {
+	return &TrashRepository{
+		db:     db,
+		access: access,
+	}
+}
+
+// GetAll lists trashed products, most recently deleted first. A storeID of
+// zero lists every store.
+func (r *TrashRepository) GetAll(ctx context.Context, storeID int64, limit, offset int) ([]*domain.TrashedProduct, error) {
+	query := `
+		SELECT ` + productColumns + `, trashed_at
+		FROM product_trash
+		WHERE $1 = 0 OR store_id = $1
+		ORDER BY trashed_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	defer explain.Track(ctx, query, storeID, limit, offset)()
+	pattern := domain.AccessPattern{Table: "product_trash", Sort: []string{"trashed_at", "id"}}
+	if storeID != 0 {
+		pattern.Equals = []string{"store_id"}
+	}
+	r.access.Record(pattern)
+	rows, err := r.db.QueryContext(ctx, query, storeID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trashed products: %w", err)
+	}
+	defer rows.Close()
+
+	var products []*domain.TrashedProduct
+	for rows.Next() {
+		product, err := scanTrashedProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trashed product: %w", err)
+		}
+		products = append(products, product)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over trashed products: %w", err)
+	}
+
+	return products, nil
+}
+
+// Restore moves a trashed product back into products under its original ID
+// and saves it as a revision.
+func (r *TrashRepository) Restore(ctx context.Context, id int64) (*domain.Product, error) {
+	query := `
+		WITH restored AS (
+			DELETE FROM product_trash WHERE id = $1
+			RETURNING ` + productColumns + `
+		),
+		written AS (
+			INSERT INTO products (` + productColumns + `)
+			SELECT ` + productColumns + ` FROM restored
+			RETURNING ` + productColumns + `
+		), ` + recordRevision + `
+		SELECT ` + productColumns + ` FROM written
+	`
+
+	row := r.db.QueryRowContext(ctx, query, id)
+
+	product, err := scanProduct(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTrashedProductNotFound
+		}
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505":
+				return nil, domain.ErrDuplicateProduct
+			case "23503":
+				return nil, domain.ErrStoreNotFound
+			}
+		}
+		return nil, fmt.Errorf("failed to restore product: %w", err)
+	}
+
+	return product, nil
+}
+
+// Count returns how many products are in the trash. A storeID of zero
+// counts every store.
+func (r *TrashRepository) Count(ctx context.Context, storeID int64) (int64, error) {
+	query := `SELECT COUNT(*) FROM product_trash WHERE $1 = 0 OR store_id = $1`
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, storeID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count trashed products: %w", err)
+	}
+
+	return count, nil
+}
+
+// Empty permanently deletes trashed products. A storeID of zero empties the
+// whole bin.
+func (r *TrashRepository) Empty(ctx context.Context, storeID int64) (int64, error) {
+	query := `DELETE FROM product_trash WHERE $1 = 0 OR store_id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, storeID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to empty trash: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *TrashRepository) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM product_trash WHERE trashed_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func scanTrashedProduct(row rowScanner) (*domain.TrashedProduct, error) {
+	product := &domain.TrashedProduct{}
+	err := row.Scan(
+		&product.ID,
+		&product.StoreID,
+		&product.Name,
+		&product.Description,
+		&product.DescriptionFormat,
+		&product.Amount,
+		&product.Unit,
+		&product.Price,
+		&product.Status,
+		&product.ModerationStatus,
+		&product.ModerationReason,
+		&product.AllowBackorder,
+		&product.BackorderLimit,
+		&product.PreorderReleaseDate,
+		&product.LowStockThreshold,
+		&product.PublishAt,
+		&product.UnpublishAt,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+		&product.TrashedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
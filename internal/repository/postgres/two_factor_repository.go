@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+	"github.com/lib/pq"
+)
+
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		db: db,
+	}
+}
+
+func (r *TwoFactorRepository) Get(ctx context.Context, identity string) (*domain.TwoFactorEnrollment, error) {
+	query := `
+		SELECT identity, recovery_code_hashes, created_at, confirmed_at
+		FROM two_factor_enrollments
+		WHERE identity = $1
+	`
+
+	enrollment := &domain.TwoFactorEnrollment{}
+	err := r.db.QueryRowContext(ctx, query, identity).Scan(
+		&enrollment.Identity,
+		pq.Array(&enrollment.RecoveryCodeHashes),
+		&enrollment.CreatedAt,
+		&enrollment.ConfirmedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrTwoFactorNotEnrolled
+		}
+		return nil, fmt.Errorf("failed to get two-factor enrollment: %w", err)
+	}
+
+	return enrollment, nil
+}
+
+func (r *TwoFactorRepository) Save(ctx context.Context, enrollment *domain.TwoFactorEnrollment) error {
+	query := `
+		INSERT INTO two_factor_enrollments (identity, recovery_code_hashes, created_at, confirmed_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (identity) DO UPDATE SET
+			recovery_code_hashes = EXCLUDED.recovery_code_hashes,
+			created_at = EXCLUDED.created_at,
+			confirmed_at = EXCLUDED.confirmed_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		enrollment.Identity,
+		pq.Array(enrollment.RecoveryCodeHashes),
+		enrollment.CreatedAt,
+		enrollment.ConfirmedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save two-factor enrollment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) Delete(ctx context.Context, identity string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM two_factor_enrollments WHERE identity = $1`, identity)
+	if err != nil {
+		return fmt.Errorf("failed to delete two-factor enrollment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return domain.ErrTwoFactorNotEnrolled
+	}
+
+	return nil
+}
+
+// UseRecoveryCode removes codeHash from identity's recovery codes in one
+// statement, so a code can only ever be redeemed once. It reports whether
+// the code was present.
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, identity, codeHash string) (bool, error) {
+	query := `
+		UPDATE two_factor_enrollments
+		SET recovery_code_hashes = array_remove(recovery_code_hashes, $2)
+		WHERE identity = $1 AND $2 = ANY(recovery_code_hashes)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, identity, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// UseTOTPStep records step as the last TOTP time step used by identity,
+// unless a code from that step or a later one was already accepted. The
+// compare and update happen in one statement, so concurrent logins cannot
+// both redeem the same code.
+func (r *TwoFactorRepository) UseTOTPStep(ctx context.Context, identity string, step int64) (bool, error) {
+	query := `
+		UPDATE two_factor_enrollments
+		SET last_totp_step = $2
+		WHERE identity = $1 AND (last_totp_step IS NULL OR last_totp_step < $2)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, identity, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to use totp step: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+type UsageRepository struct {
+	db *sql.DB
+}
+
+func NewUsageRepository(db *sql.DB) *UsageRepository {
+	return &UsageRepository{
+		db: db,
+	}
+}
+
+// RecordCalls adds the calls in one transaction.
+func (r *UsageRepository) RecordCalls(ctx context.Context, calls []domain.UsageCalls) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin usage transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO usage_api_calls (store_id, hour, calls)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (store_id, hour) DO UPDATE
+		SET calls = usage_api_calls.calls + EXCLUDED.calls
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare usage call insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, count := range calls {
+		if _, err := stmt.ExecContext(ctx, count.StoreID, count.Hour.UTC(), count.Calls); err != nil {
+			return fmt.Errorf("failed to record usage calls: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit usage calls: %w", err)
+	}
+	return nil
+}
+
+func (r *UsageRepository) LatestHour(ctx context.Context) (time.Time, error) {
+	var latest sql.NullTime
+	if err := r.db.QueryRowContext(ctx, `SELECT MAX(hour) FROM usage_records`).Scan(&latest); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest usage hour: %w", err)
+	}
+	return latest.Time, nil
+}
+
+// RecordHour records the hour for every store that served calls in it or
+// holds products. Storage is the size of the store's product rows and
+// images.
+func (r *UsageRepository) RecordHour(ctx context.Context, hour time.Time) (int64, error) {
+	query := `
+		INSERT INTO usage_records (store_id, hour, api_calls, storage_bytes, product_count)
+		SELECT s.id, $1, COALESCE(c.calls, 0),
+			COALESCE(p.storage_bytes, 0) + COALESCE(i.storage_bytes, 0), COALESCE(p.product_count, 0)
+		FROM stores s
+		LEFT JOIN usage_api_calls c ON c.store_id = s.id AND c.hour = $1
+		LEFT JOIN (
+			SELECT store_id, COUNT(*) AS product_count, SUM(pg_column_size(products.*)) AS storage_bytes
+			FROM products
+			GROUP BY store_id
+		) p ON p.store_id = s.id
+		LEFT JOIN (
+			SELECT pr.store_id, SUM(pi.size_bytes) AS storage_bytes
+			FROM product_images pi
+			JOIN products pr ON pr.id = pi.product_id
+			GROUP BY pr.store_id
+		) i ON i.store_id = s.id
+		WHERE c.calls > 0 OR p.product_count > 0
+		ON CONFLICT (store_id, hour) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, hour.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to record usage hour: %w", err)
+	}
+	created, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get recorded usage records: %w", err)
+	}
+	return created, nil
+}
+
+// ClaimPending claims the oldest pending records first. Instances claiming
+// at once skip each other's rows rather than wait for them.
+func (r *UsageRepository) ClaimPending(ctx context.Context, limit int, now, until time.Time) ([]*domain.UsageRecord, error) {
+	query := `
+		UPDATE usage_records u SET claimed_until = $3
+		FROM (
+			SELECT store_id, hour FROM usage_records
+			WHERE emitted_at IS NULL AND (claimed_until IS NULL OR claimed_until < $2)
+			ORDER BY hour, store_id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) pending
+		WHERE u.store_id = pending.store_id AND u.hour = pending.hour
+		RETURNING u.store_id, u.hour, u.api_calls, u.storage_bytes, u.product_count, u.recorded_at, u.emitted_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, now.UTC(), until.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim usage records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*domain.UsageRecord
+	for rows.Next() {
+		record, err := scanUsageRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan usage record: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate usage records: %w", err)
+	}
+
+	return records, nil
+}
+
+func (r *UsageRepository) MarkEmitted(ctx context.Context, records []*domain.UsageRecord, at time.Time) error {
+	return r.updateRecords(ctx, records, `
+		UPDATE usage_records SET emitted_at = $3, claimed_until = NULL
+		WHERE store_id = $1 AND hour = $2
+	`, at.UTC())
+}
+
+func (r *UsageRepository) ReleaseClaims(ctx context.Context, records []*domain.UsageRecord) error {
+	return r.updateRecords(ctx, records, `
+		UPDATE usage_records SET claimed_until = NULL
+		WHERE store_id = $1 AND hour = $2 AND emitted_at IS NULL
+	`)
+}
+
+// updateRecords runs query for each record in one transaction, with the
+// record's store and hour as $1 and $2 followed by args.
+func (r *UsageRepository) updateRecords(ctx context.Context, records []*domain.UsageRecord, query string, args ...any) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin usage transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare usage record update: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, record := range records {
+		if _, err := stmt.ExecContext(ctx, append([]any{record.StoreID, record.Hour.UTC()}, args...)...); err != nil {
+			return fmt.Errorf("failed to update usage record: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit usage records: %w", err)
+	}
+	return nil
+}
+
+// GetUsage pairs the calls recorded for each store and hour from from up
+// to to with the record closed for it, including records of hours without
+// calls.
+func (r *UsageRepository) GetUsage(ctx context.Context, from, to time.Time) ([]*domain.UsageComparison, error) {
+	query := `
+		SELECT COALESCE(c.store_id, u.store_id), COALESCE(c.hour, u.hour), COALESCE(c.calls, 0),
+			u.store_id, u.api_calls, u.storage_bytes, u.product_count, u.recorded_at, u.emitted_at
+		FROM (SELECT * FROM usage_api_calls WHERE hour >= $1 AND hour < $2) c
+		FULL OUTER JOIN (SELECT * FROM usage_records WHERE hour >= $1 AND hour < $2) u
+			ON u.store_id = c.store_id AND u.hour = c.hour
+		ORDER BY 2, 1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+	defer rows.Close()
+
+	var comparisons []*domain.UsageComparison
+	for rows.Next() {
+		var comparison domain.UsageComparison
+		var recordStoreID, apiCalls, storageBytes, productCount sql.NullInt64
+		var recordedAt, emittedAt sql.NullTime
+		if err := rows.Scan(
+			&comparison.StoreID, &comparison.Hour, &comparison.RecordedCalls,
+			&recordStoreID, &apiCalls, &storageBytes, &productCount, &recordedAt, &emittedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		if recordStoreID.Valid {
+			comparison.Record = &domain.UsageRecord{
+				StoreID:      comparison.StoreID,
+				Hour:         comparison.Hour,
+				APICalls:     apiCalls.Int64,
+				StorageBytes: storageBytes.Int64,
+				ProductCount: productCount.Int64,
+				RecordedAt:   recordedAt.Time,
+			}
+			if emittedAt.Valid {
+				comparison.Record.EmittedAt = &emittedAt.Time
+			}
+		}
+		comparisons = append(comparisons, &comparison)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate usage: %w", err)
+	}
+
+	return comparisons, nil
+}
+
+func scanUsageRecord(row rowScanner) (*domain.UsageRecord, error) {
+	var record domain.UsageRecord
+	var emittedAt sql.NullTime
+	if err := row.Scan(
+		&record.StoreID, &record.Hour, &record.APICalls, &record.StorageBytes,
+		&record.ProductCount, &record.RecordedAt, &emittedAt,
+	); err != nil {
+		return nil, err
+	}
+	if emittedAt.Valid {
+		record.EmittedAt = &emittedAt.Time
+	}
+	return &record, nil
+}
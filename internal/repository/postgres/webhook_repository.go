@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"github.com/lib/pq"
+)
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	query := `
+		INSERT INTO webhook_subscriptions (url, description, store_id, event_types, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING id, url, description, store_id, event_types, paused_at, pause_reason, created_at, updated_at
+	`
+
+	result, err := scanWebhook(r.db.QueryRowContext(ctx, query,
+		subscription.URL,
+		subscription.Description,
+		subscription.StoreID,
+		pq.Array(eventTypes(subscription.EventTypes)),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return result, nil
+}
+
+func (r *WebhookRepository) GetByID(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, description, store_id, event_types, paused_at, pause_reason, created_at, updated_at
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	subscription, err := scanWebhook(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// GetAll lists the store's subscriptions, or every subscription when
+// storeID is 0.
+func (r *WebhookRepository) GetAll(ctx context.Context, storeID int64, limit, offset int) ([]*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, description, store_id, event_types, paused_at, pause_reason, created_at, updated_at
+		FROM webhook_subscriptions
+		WHERE $1 = 0 OR store_id = $1
+		ORDER BY id
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryWebhooks(ctx, query, storeID, limit, offset)
+}
+
+// GetActive returns every subscription events should be delivered to.
+func (r *WebhookRepository) GetActive(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	query := `
+		SELECT id, url, description, store_id, event_types, paused_at, pause_reason, created_at, updated_at
+		FROM webhook_subscriptions
+		WHERE paused_at IS NULL
+		ORDER BY id
+	`
+
+	return r.queryWebhooks(ctx, query)
+}
+
+func (r *WebhookRepository) Pause(ctx context.Context, id int64, reason string, at time.Time) error {
+	query := `
+		UPDATE webhook_subscriptions
+		SET paused_at = $1, pause_reason = $2, updated_at = $1
+		WHERE id = $3 AND paused_at IS NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, at, reason, id); err != nil {
+		return fmt.Errorf("failed to pause webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// Resume clears a pause. updated_at moves to at, which tells dispatchers to
+// forget the failures that led to the pause.
+func (r *WebhookRepository) Resume(ctx context.Context, id int64, at time.Time) (*domain.WebhookSubscription, error) {
+	query := `
+		UPDATE webhook_subscriptions
+		SET paused_at = NULL, pause_reason = '', updated_at = $1
+		WHERE id = $2
+		RETURNING id, url, description, store_id, event_types, paused_at, pause_reason, created_at, updated_at
+	`
+
+	subscription, err := scanWebhook(r.db.QueryRowContext(ctx, query, at, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("failed to resume webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]*domain.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []*domain.WebhookSubscription
+	for rows.Next() {
+		subscription, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate over webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func scanWebhook(row rowScanner) (*domain.WebhookSubscription, error) {
+	subscription := &domain.WebhookSubscription{}
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&subscription.Description,
+		&subscription.StoreID,
+		pq.Array(&subscription.EventTypes),
+		&subscription.PausedAt,
+		&subscription.PauseReason,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// eventTypes stores "every type" as an empty array rather than NULL.
+func eventTypes(types []string) []string {
+	if types == nil {
+		return []string{}
+	}
+	return types
+}
@@ -0,0 +1,237 @@
+// Package replicated routes product reads to a region-local read replica.
+package replicated
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+)
+
+// Health reports whether the replica is caught up enough to serve reads.
+type Health interface {
+	Healthy() bool
+}
+
+// ProductRepository writes to the primary and reads from the replica while
+// it is healthy. To keep read-your-writes, it remembers the updated_at of
+// products this instance wrote within the last window: a replica row older
+// than that is stale and the read is retried on the primary. Lists go to
+// the primary while any such write is that recent, and any replica error
+// falls back to the primary.
+type ProductRepository struct {
+	usecase.ProductRepository
+	replica usecase.ProductRepository
+	health  Health
+	window  time.Duration
+	clock   clock.Clock
+
+	mu        sync.Mutex
+	writes    map[int64]write
+	lastWrite time.Time
+	calls     int
+}
+
+type write struct {
+	updatedAt time.Time
+	deleted   bool
+	at        time.Time
+}
+
+// NewProductRepository reads from replica and falls back to primary.
+// window should cover the largest lag health still accepts plus how stale
+// its measurement can be.
+func NewProductRepository(primary, replica usecase.ProductRepository, health Health, window time.Duration, clk clock.Clock) *ProductRepository {
+	return &ProductRepository{
+		ProductRepository: primary,
+		replica:           replica,
+		health:            health,
+		window:            window,
+		clock:             clk,
+		writes:            make(map[int64]write),
+	}
+}
+
+func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	created, err := r.ProductRepository.Create(ctx, product)
+	if err == nil {
+		r.record(created.ID, write{updatedAt: created.UpdatedAt})
+	}
+	return created, err
+}
+
+func (r *ProductRepository) CreateMany(ctx context.Context, products []*domain.Product) ([]*domain.Product, error) {
+	created, err := r.ProductRepository.CreateMany(ctx, products)
+	if err == nil {
+		for _, product := range created {
+			r.record(product.ID, write{updatedAt: product.UpdatedAt})
+		}
+	}
+	return created, err
+}
+
+func (r *ProductRepository) Update(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
+	updated, err := r.ProductRepository.Update(ctx, id, product)
+	if err == nil {
+		r.record(id, write{updatedAt: updated.UpdatedAt})
+	}
+	return updated, err
+}
+
+func (r *ProductRepository) Patch(ctx context.Context, id int64, patch *domain.ProductPatch) (*domain.Product, error) {
+	updated, err := r.ProductRepository.Patch(ctx, id, patch)
+	if err == nil {
+		r.record(id, write{updatedAt: updated.UpdatedAt})
+	}
+	return updated, err
+}
+
+func (r *ProductRepository) UpdateModeration(ctx context.Context, id int64, result domain.ModerationResult) (*domain.Product, error) {
+	updated, err := r.ProductRepository.UpdateModeration(ctx, id, result)
+	if err == nil {
+		r.record(id, write{updatedAt: updated.UpdatedAt})
+	}
+	return updated, err
+}
+
+func (r *ProductRepository) Delete(ctx context.Context, id int64) error {
+	err := r.ProductRepository.Delete(ctx, id)
+	if err == nil {
+		r.record(id, write{deleted: true})
+	}
+	return err
+}
+
+func (r *ProductRepository) GetByID(ctx context.Context, id int64) (*domain.Product, error) {
+	if !r.health.Healthy() {
+		return r.ProductRepository.GetByID(ctx, id)
+	}
+
+	w, written := r.recentWrite(id)
+	if written && w.deleted {
+		return r.ProductRepository.GetByID(ctx, id)
+	}
+
+	product, err := r.replica.GetByID(ctx, id)
+	switch {
+	case err != nil && !written && errors.Is(err, domain.ErrProductNotFound):
+		return nil, err
+	case err != nil:
+		r.fallback(ctx, "get_product", err)
+		return r.ProductRepository.GetByID(ctx, id)
+	case written && product.UpdatedAt.Before(w.updatedAt):
+		return r.ProductRepository.GetByID(ctx, id)
+	}
+	return product, nil
+}
+
+func (r *ProductRepository) GetAll(ctx context.Context, filter domain.ProductFilter, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	if r.listFromReplica() {
+		products, err := r.replica.GetAll(ctx, filter, now, limit, offset)
+		if err == nil {
+			return products, nil
+		}
+		r.fallback(ctx, "list_products", err)
+	}
+	return r.ProductRepository.GetAll(ctx, filter, now, limit, offset)
+}
+
+func (r *ProductRepository) GetByAvailability(ctx context.Context, availability string, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	if r.listFromReplica() {
+		products, err := r.replica.GetByAvailability(ctx, availability, now, limit, offset)
+		if err == nil {
+			return products, nil
+		}
+		r.fallback(ctx, "list_products_by_availability", err)
+	}
+	return r.ProductRepository.GetByAvailability(ctx, availability, now, limit, offset)
+}
+
+func (r *ProductRepository) GetAfterID(ctx context.Context, afterID int64, limit int) ([]*domain.Product, error) {
+	if r.listFromReplica() {
+		products, err := r.replica.GetAfterID(ctx, afterID, limit)
+		if err == nil {
+			return products, nil
+		}
+		r.fallback(ctx, "list_products_after_id", err)
+	}
+	return r.ProductRepository.GetAfterID(ctx, afterID, limit)
+}
+
+func (r *ProductRepository) GetAllByStore(ctx context.Context, storeID int64) ([]*domain.Product, error) {
+	if r.listFromReplica() {
+		products, err := r.replica.GetAllByStore(ctx, storeID)
+		if err == nil {
+			return products, nil
+		}
+		r.fallback(ctx, "list_store_products", err)
+	}
+	return r.ProductRepository.GetAllByStore(ctx, storeID)
+}
+
+func (r *ProductRepository) GetByModerationStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Product, error) {
+	if r.listFromReplica() {
+		products, err := r.replica.GetByModerationStatus(ctx, status, limit, offset)
+		if err == nil {
+			return products, nil
+		}
+		r.fallback(ctx, "list_products_by_moderation_status", err)
+	}
+	return r.ProductRepository.GetByModerationStatus(ctx, status, limit, offset)
+}
+
+func (r *ProductRepository) record(id int64, w write) {
+	now := r.clock.Now()
+	w.at = now
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls++
+	if r.calls%1000 == 0 {
+		r.prune(now)
+	}
+	r.writes[id] = w
+	r.lastWrite = now
+}
+
+func (r *ProductRepository) prune(now time.Time) {
+	for id, w := range r.writes {
+		if now.Sub(w.at) > r.window {
+			delete(r.writes, id)
+		}
+	}
+}
+
+func (r *ProductRepository) recentWrite(id int64) (write, bool) {
+	now := r.clock.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.writes[id]
+	if !ok || now.Sub(w.at) > r.window {
+		return write{}, false
+	}
+	return w, true
+}
+
+func (r *ProductRepository) listFromReplica() bool {
+	if !r.health.Healthy() {
+		return false
+	}
+
+	now := r.clock.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastWrite.IsZero() || now.Sub(r.lastWrite) > r.window
+}
+
+func (r *ProductRepository) fallback(ctx context.Context, action string, err error) {
+	logger.FromContext(ctx).WithError(err).WithField("action", action).Warn("Replica read failed, reading from primary")
+}
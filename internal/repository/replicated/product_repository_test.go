@@ -0,0 +1,157 @@
+package replicated
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/repository/memory"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHealth bool
+
+func (h *fakeHealth) Healthy() bool { return bool(*h) }
+
+// failingReplica fails every read, like an unreachable replica.
+type failingReplica struct {
+	usecase.ProductRepository
+}
+
+func (failingReplica) GetByID(ctx context.Context, id int64) (*domain.Product, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (failingReplica) GetAll(ctx context.Context, filter domain.ProductFilter, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	return nil, errors.New("connection refused")
+}
+
+type testRepos struct {
+	repo    *ProductRepository
+	primary *memory.ProductRepository
+	replica *memory.ProductRepository
+	health  *fakeHealth
+	clock   *clock.Fake
+}
+
+func newTestRepos(t *testing.T) testRepos {
+	healthy := fakeHealth(true)
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	primaryStore := memory.NewStore(clock.Real())
+	replicaStore := memory.NewStore(clock.Real())
+
+	r := testRepos{
+		primary: memory.NewProductRepository(primaryStore),
+		replica: memory.NewProductRepository(replicaStore),
+		health:  &healthy,
+		clock:   fake,
+	}
+	r.repo = NewProductRepository(r.primary, r.replica, r.health, time.Minute, fake)
+	return r
+}
+
+// replicate copies the primary's product to the replica, as streaming
+// replication eventually would.
+func (r testRepos) replicate(t *testing.T, id int64) {
+	t.Helper()
+	product, err := r.primary.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	_, err = r.replica.Create(context.Background(), product)
+	require.NoError(t, err)
+}
+
+func TestProductRepository_GetByID_ReadsReplica(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos(t)
+
+	_, err := r.replica.Create(ctx, &domain.Product{StoreID: 1, Name: "Replica copy"})
+	require.NoError(t, err)
+
+	got, err := r.repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Replica copy", got.Name)
+
+	*r.health = false
+	_, err = r.repo.GetByID(ctx, 1)
+	assert.ErrorIs(t, err, domain.ErrProductNotFound, "unhealthy replica must not serve reads")
+}
+
+func TestProductRepository_GetByID_ReadYourWrites(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos(t)
+
+	created, err := r.repo.Create(ctx, &domain.Product{StoreID: 1, Name: "Fresh"})
+	require.NoError(t, err)
+
+	got, err := r.repo.GetByID(ctx, created.ID)
+	require.NoError(t, err, "a product missing from the replica after our write is read from the primary")
+	assert.Equal(t, "Fresh", got.Name)
+
+	r.replicate(t, created.ID)
+	r.clock.Advance(time.Second)
+	_, err = r.repo.Update(ctx, created.ID, &domain.Product{StoreID: 1, Name: "Renamed"})
+	require.NoError(t, err)
+
+	got, err = r.repo.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", got.Name, "replica row older than our write is stale")
+
+	require.NoError(t, r.repo.Delete(ctx, created.ID))
+	_, err = r.repo.GetByID(ctx, created.ID)
+	assert.ErrorIs(t, err, domain.ErrProductNotFound, "deleted product must not be served by the replica")
+
+	r.clock.Advance(2 * time.Minute)
+	got, err = r.repo.GetByID(ctx, created.ID)
+	require.NoError(t, err, "after the window the replica is trusted again")
+	assert.Equal(t, "Fresh", got.Name)
+}
+
+func TestProductRepository_Lists(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepos(t)
+
+	_, err := r.replica.Create(ctx, &domain.Product{StoreID: 1, Name: "Replica copy"})
+	require.NoError(t, err)
+
+	products, err := r.repo.GetAll(ctx, domain.ProductFilter{}, time.Now(), 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+
+	_, err = r.repo.Create(ctx, &domain.Product{StoreID: 1, Name: "A"})
+	require.NoError(t, err)
+	_, err = r.repo.Create(ctx, &domain.Product{StoreID: 1, Name: "B"})
+	require.NoError(t, err)
+
+	products, err = r.repo.GetAllByStore(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, products, 2, "lists go to the primary right after a write")
+
+	r.clock.Advance(2 * time.Minute)
+	products, err = r.repo.GetAllByStore(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+}
+
+func TestProductRepository_FallsBackOnReplicaError(t *testing.T) {
+	ctx := context.Background()
+	healthy := fakeHealth(true)
+	primary := memory.NewProductRepository(memory.NewStore(clock.Real()))
+	repo := NewProductRepository(primary, failingReplica{}, &healthy, time.Minute, clock.Real())
+
+	_, err := primary.Create(ctx, &domain.Product{StoreID: 1, Name: "Primary"})
+	require.NoError(t, err)
+
+	got, err := repo.GetByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Primary", got.Name)
+
+	products, err := repo.GetAll(ctx, domain.ProductFilter{}, time.Now(), 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+}
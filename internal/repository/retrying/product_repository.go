@@ -0,0 +1,122 @@
+// Package retrying retries product reads through a database failover.
+package retrying
+
+import (
+	"context"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	baseBackoff = 100 * time.Millisecond
+	maxBackoff  = 2 * time.Second
+)
+
+// ProductRepository retries the reads of another product repository that
+// fail because the connection to the database was lost, for up to window
+// after the first failure, so requests ride out a primary failing over
+// rather than failing with it. Reads are idempotent, so a retry cannot do
+// anything twice. Writes are not retried, as one that failed may still have
+// been committed, and neither are reads in a transaction, which the lost
+// connection ended.
+type ProductRepository struct {
+	usecase.ProductRepository
+	window time.Duration
+	clock  clock.Clock
+}
+
+func NewProductRepository(next usecase.ProductRepository, window time.Duration, clk clock.Clock) *ProductRepository {
+	return &ProductRepository{
+		ProductRepository: next,
+		window:            window,
+		clock:             clk,
+	}
+}
+
+func (r *ProductRepository) GetByID(ctx context.Context, id int64) (*domain.Product, error) {
+	return retry(ctx, r, "get_product", func() (*domain.Product, error) {
+		return r.ProductRepository.GetByID(ctx, id)
+	})
+}
+
+func (r *ProductRepository) GetAll(ctx context.Context, filter domain.ProductFilter, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	return retry(ctx, r, "list_products", func() ([]*domain.Product, error) {
+		return r.ProductRepository.GetAll(ctx, filter, now, limit, offset)
+	})
+}
+
+func (r *ProductRepository) GetByAvailability(ctx context.Context, availability string, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	return retry(ctx, r, "list_products_by_availability", func() ([]*domain.Product, error) {
+		return r.ProductRepository.GetByAvailability(ctx, availability, now, limit, offset)
+	})
+}
+
+func (r *ProductRepository) GetAfterID(ctx context.Context, afterID int64, limit int) ([]*domain.Product, error) {
+	return retry(ctx, r, "list_products_after_id", func() ([]*domain.Product, error) {
+		return r.ProductRepository.GetAfterID(ctx, afterID, limit)
+	})
+}
+
+func (r *ProductRepository) GetAllByStore(ctx context.Context, storeID int64) ([]*domain.Product, error) {
+	return retry(ctx, r, "list_store_products", func() ([]*domain.Product, error) {
+		return r.ProductRepository.GetAllByStore(ctx, storeID)
+	})
+}
+
+func (r *ProductRepository) GetByModerationStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Product, error) {
+	return retry(ctx, r, "list_products_by_moderation_status", func() ([]*domain.Product, error) {
+		return r.ProductRepository.GetByModerationStatus(ctx, status, limit, offset)
+	})
+}
+
+func retry[T any](ctx context.Context, r *ProductRepository, action string, read func() (T, error)) (T, error) {
+	result, err := read()
+	if err == nil || database.InTransaction(ctx) || !database.IsConnectionError(err) {
+		return result, err
+	}
+
+	deadline := r.clock.Now().Add(r.window)
+	for attempt := 0; ; attempt++ {
+		wait := baseBackoff << attempt
+		if wait <= 0 || wait > maxBackoff {
+			wait = maxBackoff
+		}
+		if remaining := deadline.Sub(r.clock.Now()); remaining < wait {
+			return result, err
+		}
+
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"action":  action,
+			"attempt": attempt + 1,
+			"wait":    wait,
+		}).Warn("Database connection lost, retrying read")
+		if sleepErr := sleep(ctx, r.clock, wait); sleepErr != nil {
+			return result, err
+		}
+
+		result, err = read()
+		if err == nil || !database.IsConnectionError(err) {
+			return result, err
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, clk clock.Clock, d time.Duration) error {
+	ticker := clk.NewTicker(d)
+	defer ticker.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-ticker.C():
+		return nil
+	}
+}
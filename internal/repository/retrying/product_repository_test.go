@@ -0,0 +1,96 @@
+package retrying
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/usecase"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyRepository fails its first failures reads with err.
+type flakyRepository struct {
+	usecase.ProductRepository
+	failures int
+	err      error
+	calls    int
+}
+
+func (r *flakyRepository) GetByID(ctx context.Context, id int64) (*domain.Product, error) {
+	r.calls++
+	if r.calls <= r.failures {
+		return nil, r.err
+	}
+	return &domain.Product{ID: id}, nil
+}
+
+func (r *flakyRepository) Delete(ctx context.Context, id int64) error {
+	r.calls++
+	return r.err
+}
+
+// getByID reads in the background, advancing the clock through each
+// backoff until the read returns.
+func getByID(t *testing.T, repo *ProductRepository, fake *clock.Fake) (*domain.Product, error) {
+	type result struct {
+		product *domain.Product
+		err     error
+	}
+	done := make(chan result)
+	go func() {
+		product, err := repo.GetByID(context.Background(), 1)
+		done <- result{product, err}
+	}()
+	for {
+		select {
+		case r := <-done:
+			return r.product, r.err
+		case <-time.After(time.Millisecond):
+			fake.Advance(maxBackoff)
+		}
+	}
+}
+
+func TestProductRepository_RetriesReadsThroughFailover(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	next := &flakyRepository{failures: 3, err: fmt.Errorf("failed to get product: %w", database.ErrNoHost)}
+	repo := NewProductRepository(next, 30*time.Second, fake)
+
+	product, err := getByID(t, repo, fake)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), product.ID)
+	assert.Equal(t, 4, next.calls)
+}
+
+func TestProductRepository_GivesUpAfterWindow(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	next := &flakyRepository{failures: 100, err: driver.ErrBadConn}
+	repo := NewProductRepository(next, 5*time.Second, fake)
+
+	_, err := getByID(t, repo, fake)
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	assert.Less(t, next.calls, 10)
+}
+
+func TestProductRepository_DoesNotRetry(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	next := &flakyRepository{failures: 1, err: domain.ErrProductNotFound}
+	_, err := NewProductRepository(next, time.Minute, fake).GetByID(context.Background(), 1)
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	assert.Equal(t, 1, next.calls, "errors from the database itself are not retried")
+
+	next = &flakyRepository{err: driver.ErrBadConn}
+	err = NewProductRepository(next, time.Minute, fake).Delete(context.Background(), 1)
+	assert.True(t, errors.Is(err, driver.ErrBadConn))
+	assert.Equal(t, 1, next.calls, "writes are not retried")
+}
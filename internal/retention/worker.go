@@ -0,0 +1,231 @@
+// Package retention deletes rows that have outlived their retention
+// period, in small throttled batches so purges never hold long locks or
+// saturate the database.
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Rules returns the built-in rules. A zero max age disables a rule.
+func Rules(auditLogs, tombstones, jobRecords, inboxMessages, catalogChanges, idempotencyKeys, sentEvents time.Duration) []domain.RetentionRule {
+	all := []domain.RetentionRule{
+		{Name: "audit_logs", Table: "audit_logs", TimeColumn: "occurred_at", MaxAge: auditLogs},
+		{Name: "tombstones", Table: "product_trash", TimeColumn: "trashed_at", MaxAge: tombstones},
+		{Name: "feed_runs", Table: "product_feed_runs", TimeColumn: "started_at", Condition: "finished_at IS NOT NULL", MaxAge: jobRecords},
+		{Name: "connector_syncs", Table: "connector_syncs", TimeColumn: "started_at", Condition: "finished_at IS NOT NULL", MaxAge: jobRecords},
+		{Name: "reconciliation_runs", Table: "reconciliation_runs", TimeColumn: "started_at", Condition: "finished_at IS NOT NULL", MaxAge: jobRecords},
+		{Name: "catalog_restores", Table: "catalog_restores", TimeColumn: "created_at", Condition: "finished_at IS NOT NULL", MaxAge: jobRecords},
+		{Name: "inbox_messages", Table: "inbox_messages", TimeColumn: "processed_at", MaxAge: inboxMessages},
+		{Name: "catalog_changes", Table: "catalog_changes", TimeColumn: "last_at", MaxAge: catalogChanges},
+		{Name: "idempotency_keys", Table: "idempotency_keys", TimeColumn: "created_at", MaxAge: idempotencyKeys},
+		{Name: "sent_events", Table: "event_outbox", TimeColumn: "sent_at", MaxAge: sentEvents},
+	}
+
+	rules := make([]domain.RetentionRule, 0, len(all))
+	for _, rule := range all {
+		if rule.MaxAge > 0 {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Store counts and deletes expired rows.
+type Store interface {
+	CountExpired(ctx context.Context, rule domain.RetentionRule, cutoff time.Time) (int64, sql.NullTime, error)
+	// DeleteExpired deletes at most limit of the oldest expired rows and
+	// returns how many it deleted.
+	DeleteExpired(ctx context.Context, rule domain.RetentionRule, cutoff time.Time, limit int) (int64, error)
+}
+
+type Config struct {
+	Interval time.Duration
+	// BatchSize caps the rows one delete statement removes. Zero uses
+	// DefaultBatchSize.
+	BatchSize int
+	// BatchDelay is the pause between batches of the same rule.
+	BatchDelay time.Duration
+}
+
+type Worker struct {
+	store  Store
+	rules  []domain.RetentionRule
+	cfg    Config
+	logger *logrus.Logger
+	clock  clock.Clock
+	// scheduled are the rules a Job applies rather than Run.
+	scheduled map[string]bool
+
+	purged   *telemetry.Counter
+	runs     *telemetry.Counter
+	duration *telemetry.Histogram
+}
+
+const DefaultBatchSize = 1000
+
+func NewWorker(store Store, rules []domain.RetentionRule, registry *telemetry.Registry, cfg Config, clk clock.Clock, logger *logrus.Logger) *Worker {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	return &Worker{
+		store:     store,
+		rules:     rules,
+		cfg:       cfg,
+		logger:    logger,
+		clock:     clk,
+		scheduled: make(map[string]bool),
+		purged:    registry.NewCounter("retention_purged_rows_total", "Rows deleted by retention rules.", "rule"),
+		runs:      registry.NewCounter("retention_runs_total", "Retention rule runs by result.", "rule", "result"),
+		duration:  registry.NewHistogram("retention_run_duration_seconds", "Time to apply a retention rule, including throttling.", telemetry.DefaultDurationBuckets, "rule"),
+	}
+}
+
+// Run applies the rules no Job applies each interval until ctx is
+// cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	var rules []domain.RetentionRule
+	for _, rule := range w.rules {
+		if !w.scheduled[rule.Name] {
+			rules = append(rules, rule)
+		}
+	}
+	w.logger.WithFields(logrus.Fields{"interval": w.cfg.Interval, "rules": len(rules)}).Info("Retention worker started")
+
+	ticker := w.clock.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Retention worker stopped")
+			return
+		case <-ticker.C():
+			w.purge(ctx, rules)
+		}
+	}
+}
+
+// Purge applies every rule once. A failing rule is logged and does not
+// stop the others.
+func (w *Worker) Purge(ctx context.Context) {
+	w.purge(ctx, w.rules)
+}
+
+// Job applies some of a worker's rules when a scheduler runs it.
+type Job struct {
+	name   string
+	worker *Worker
+	rules  []domain.RetentionRule
+}
+
+// Schedule takes the named rules off the worker's interval and returns a
+// Job that applies them instead, so purges that delete a lot can be left
+// to a quiet hour. It must be called before Run.
+func (w *Worker) Schedule(name string, rules ...string) *Job {
+	job := &Job{name: name, worker: w}
+	for _, rule := range w.rules {
+		for _, scheduled := range rules {
+			if rule.Name == scheduled {
+				w.scheduled[rule.Name] = true
+				job.rules = append(job.rules, rule)
+			}
+		}
+	}
+	return job
+}
+
+func (j *Job) Name() string {
+	return j.name
+}
+
+// Run applies the job's rules once. It returns the errors of the rules
+// that failed, after trying them all.
+func (j *Job) Run(ctx context.Context) error {
+	return j.worker.purge(ctx, j.rules)
+}
+
+func (w *Worker) purge(ctx context.Context, rules []domain.RetentionRule) error {
+	var errs []error
+	for _, rule := range rules {
+		start := w.clock.Now()
+		deleted, err := w.purgeRule(ctx, rule, start.Add(-rule.MaxAge))
+		w.duration.Observe(w.clock.Now().Sub(start).Seconds(), rule.Name)
+
+		logger := w.logger.WithFields(logrus.Fields{"rule": rule.Name, "deleted": deleted})
+		if err != nil {
+			w.runs.Inc(rule.Name, "failure")
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				return errors.Join(errs...)
+			}
+			logger.WithError(err).Error("Retention rule failed")
+			continue
+		}
+		w.runs.Inc(rule.Name, "success")
+		if deleted > 0 {
+			logger.Info("Retention rule purged rows")
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (w *Worker) purgeRule(ctx context.Context, rule domain.RetentionRule, cutoff time.Time) (int64, error) {
+	var throttle clock.Ticker
+	if w.cfg.BatchDelay > 0 {
+		throttle = w.clock.NewTicker(w.cfg.BatchDelay)
+		defer throttle.Stop()
+	}
+
+	var total int64
+	for {
+		deleted, err := w.store.DeleteExpired(ctx, rule, cutoff, w.cfg.BatchSize)
+		total += deleted
+		w.purged.Add(float64(deleted), rule.Name)
+		if err != nil {
+			return total, fmt.Errorf("failed to purge %s: %w", rule.Name, err)
+		}
+		if deleted < int64(w.cfg.BatchSize) {
+			return total, nil
+		}
+
+		if throttle != nil {
+			select {
+			case <-ctx.Done():
+				return total, ctx.Err()
+			case <-throttle.C():
+			}
+		}
+	}
+}
+
+// Report is a dry run: what each rule would delete if it ran now.
+func (w *Worker) Report(ctx context.Context) ([]domain.RetentionReport, error) {
+	now := w.clock.Now()
+
+	reports := make([]domain.RetentionReport, 0, len(w.rules))
+	for _, rule := range w.rules {
+		cutoff := now.Add(-rule.MaxAge)
+		eligible, oldest, err := w.store.CountExpired(ctx, rule, cutoff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to report %s: %w", rule.Name, err)
+		}
+		reports = append(reports, domain.RetentionReport{
+			Rule:     rule,
+			Cutoff:   cutoff,
+			Eligible: eligible,
+			Oldest:   oldest,
+		})
+	}
+	return reports, nil
+}
@@ -0,0 +1,196 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore holds row timestamps per table.
+type fakeStore struct {
+	mu      sync.Mutex
+	rows    map[string][]time.Time
+	batches map[string]int
+	failing map[string]bool
+}
+
+func newFakeStore(rows map[string][]time.Time) *fakeStore {
+	return &fakeStore{rows: rows, batches: make(map[string]int), failing: make(map[string]bool)}
+}
+
+func (s *fakeStore) CountExpired(ctx context.Context, rule domain.RetentionRule, cutoff time.Time) (int64, sql.NullTime, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int64
+	var oldest sql.NullTime
+	for _, at := range s.rows[rule.Table] {
+		if at.Before(cutoff) {
+			count++
+			if !oldest.Valid || at.Before(oldest.Time) {
+				oldest = sql.NullTime{Time: at, Valid: true}
+			}
+		}
+	}
+	return count, oldest, nil
+}
+
+func (s *fakeStore) DeleteExpired(ctx context.Context, rule domain.RetentionRule, cutoff time.Time, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failing[rule.Table] {
+		return 0, errors.New("lock timeout")
+	}
+	s.batches[rule.Table]++
+
+	var kept []time.Time
+	var deleted int64
+	for _, at := range s.rows[rule.Table] {
+		if at.Before(cutoff) && deleted < int64(limit) {
+			deleted++
+			continue
+		}
+		kept = append(kept, at)
+	}
+	s.rows[rule.Table] = kept
+	return deleted, nil
+}
+
+var now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func ages(days ...int) []time.Time {
+	times := make([]time.Time, len(days))
+	for i, d := range days {
+		times[i] = now.Add(-time.Duration(d) * 24 * time.Hour)
+	}
+	return times
+}
+
+func newTestWorker(store Store, cfg Config) (*Worker, *telemetry.Registry, *clock.Fake) {
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	rules := Rules(365*24*time.Hour, 30*24*time.Hour, 14*24*time.Hour, 30*24*time.Hour, 30*24*time.Hour, 24*time.Hour, 7*24*time.Hour)
+	fake := clock.NewFake(now)
+	w := NewWorker(store, rules, registry, cfg, fake, logrus.New())
+	return w, registry, fake
+}
+
+func TestRules_SkipsDisabled(t *testing.T) {
+	rules := Rules(0, 30*24*time.Hour, 0, 0, 0, 0, 0)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "tombstones", rules[0].Name)
+}
+
+func TestWorker_Purge(t *testing.T) {
+	store := newFakeStore(map[string][]time.Time{
+		"audit_logs":        ages(400, 366, 10, 1),
+		"product_trash":     ages(31, 29),
+		"product_feed_runs": ages(20, 15, 15, 15, 15, 13),
+	})
+	store.failing["connector_syncs"] = true
+	w, registry, _ := newTestWorker(store, Config{BatchSize: 2})
+
+	w.Purge(context.Background())
+
+	assert.Len(t, store.rows["audit_logs"], 2)
+	assert.Len(t, store.rows["product_trash"], 1)
+	assert.Len(t, store.rows["product_feed_runs"], 1)
+	assert.Equal(t, 3, store.batches["product_feed_runs"], "5 expired rows in batches of 2")
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `retention_purged_rows_total{rule="feed_runs"} 5`)
+	assert.Contains(t, buf.String(), `retention_runs_total{result="success",rule="audit_logs"} 1`)
+	assert.Contains(t, buf.String(), `retention_runs_total{result="failure",rule="connector_syncs"} 1`)
+}
+
+func TestWorker_PurgeThrottlesBatches(t *testing.T) {
+	store := newFakeStore(map[string][]time.Time{"audit_logs": ages(400, 400, 400)})
+	w, _, fake := newTestWorker(store, Config{BatchSize: 1, BatchDelay: time.Second})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Purge(context.Background())
+	}()
+
+	for batch := 1; batch <= 3; batch++ {
+		fake.BlockUntilTickers(1)
+		require.Eventually(t, func() bool {
+			store.mu.Lock()
+			defer store.mu.Unlock()
+			return store.batches["audit_logs"] == batch
+		}, time.Second, time.Millisecond, "one batch per delay")
+		fake.Advance(time.Second)
+	}
+	<-done
+
+	assert.Empty(t, store.rows["audit_logs"])
+	assert.Equal(t, 4, store.batches["audit_logs"], "the last, short batch ends the rule")
+}
+
+func TestWorker_Report(t *testing.T) {
+	store := newFakeStore(map[string][]time.Time{
+		"audit_logs":    ages(400, 366, 10),
+		"product_trash": ages(5),
+	})
+	w, _, _ := newTestWorker(store, Config{BatchSize: 100})
+
+	reports, err := w.Report(context.Background())
+	require.NoError(t, err)
+	require.Len(t, reports, 10)
+
+	assert.Equal(t, "audit_logs", reports[0].Rule.Name)
+	assert.Equal(t, now.Add(-365*24*time.Hour), reports[0].Cutoff)
+	assert.Equal(t, int64(2), reports[0].Eligible)
+	assert.Equal(t, now.Add(-400*24*time.Hour), reports[0].Oldest.Time)
+
+	assert.Equal(t, int64(0), reports[1].Eligible)
+	assert.False(t, reports[1].Oldest.Valid)
+	assert.Len(t, store.rows["audit_logs"], 3, "the report deletes nothing")
+}
+
+func TestWorker_Schedule(t *testing.T) {
+	store := newFakeStore(map[string][]time.Time{
+		"audit_logs":       ages(400),
+		"product_trash":    ages(31),
+		"idempotency_keys": ages(2),
+	})
+	store.failing["idempotency_keys"] = true
+	w, _, fake := newTestWorker(store, Config{Interval: time.Hour, BatchSize: 100})
+	job := w.Schedule("nightly_cleanup", "tombstones", "idempotency_keys")
+	assert.Equal(t, "nightly_cleanup", job.Name())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Run(ctx)
+	}()
+	fake.BlockUntilTickers(1)
+	fake.Advance(time.Hour)
+	require.Eventually(t, func() bool {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return len(store.rows["audit_logs"]) == 0
+	}, time.Second, time.Millisecond)
+	cancel()
+	<-done
+	assert.Len(t, store.rows["product_trash"], 1, "the interval leaves scheduled rules to the job")
+
+	err := job.Run(context.Background())
+	assert.ErrorContains(t, err, "failed to purge idempotency_keys")
+	assert.Empty(t, store.rows["product_trash"], "a failing rule does not stop the others")
+}
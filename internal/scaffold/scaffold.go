@@ -0,0 +1,216 @@
+// Package scaffold stamps out a new aggregate laid out like Store: a domain
+// struct, a repository interface with its Postgres implementation and
+// migration, a use case, DTOs, a handler, routes, the fx module serving
+// them and tests with mocks. The generated entity has a name and
+// timestamps; fields beyond those are added by hand.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+var entityName = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+// migrationPrefix matches the number migration files start with.
+var migrationPrefix = regexp.MustCompile(`^(\d+)_`)
+
+// ErrExists is returned when a file the entity needs is already there.
+var ErrExists = errors.New("file already exists")
+
+// Entity is the name of a new aggregate in the forms the generated code
+// uses, for example PurchaseOrder, purchaseOrder, purchase_orders and
+// purchase-orders.
+type Entity struct {
+	Name       string
+	Plural     string
+	Var        string
+	VarPlural  string
+	Snake      string
+	Table      string
+	Path       string
+	Words      string
+	WordsTitle string
+	// Migration is the number of the migration creating the table.
+	Migration string
+}
+
+// NewEntity derives the forms of name, which must be an exported Go
+// identifier in PascalCase.
+func NewEntity(name string) (Entity, error) {
+	if !entityName.MatchString(name) {
+		return Entity{}, fmt.Errorf("entity name %q must be in PascalCase, like PurchaseOrder", name)
+	}
+
+	words := splitWords(name)
+	plural := make([]string, len(words))
+	copy(plural, words)
+	plural[len(plural)-1] = pluralize(plural[len(plural)-1])
+
+	entity := Entity{
+		Name:   name,
+		Plural: name[:len(name)-len(words[len(words)-1])] + pluralize(words[len(words)-1]),
+		Var:    lowerFirst(name),
+		Snake:  strings.ToLower(strings.Join(words, "_")),
+		Table:  strings.ToLower(strings.Join(plural, "_")),
+		Path:   strings.ToLower(strings.Join(plural, "-")),
+		Words:  strings.ToLower(strings.Join(words, " ")),
+	}
+	entity.VarPlural = lowerFirst(entity.Plural)
+	entity.WordsTitle = strings.ToUpper(entity.Words[:1]) + entity.Words[1:]
+	if token.IsKeyword(entity.Var) || token.IsKeyword(entity.VarPlural) {
+		return Entity{}, fmt.Errorf("entity name %q is a Go keyword once lowercased", name)
+	}
+	return entity, nil
+}
+
+// splitWords splits a PascalCase name into its words, keeping acronyms
+// such as the SKU in SKUAlias together.
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		upper := unicode.IsUpper(runes[i])
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if upper && (!unicode.IsUpper(runes[i-1]) || nextLower) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+func pluralize(word string) string {
+	lower := strings.ToLower(word)
+	switch {
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return word[:len(word)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return word + "es"
+	}
+	return word + "s"
+}
+
+func lowerFirst(name string) string {
+	words := splitWords(name)
+	return strings.ToLower(words[0]) + name[len(words[0]):]
+}
+
+// file is a generated file: the template it is rendered from and its path
+// relative to the repository root.
+type file struct {
+	template string
+	path     string
+}
+
+func (e Entity) files() []file {
+	migration := "migrations/" + e.Migration + "_create_" + e.Table + "_table"
+	return []file{
+		{"domain.go.tmpl", "internal/domain/" + e.Snake + ".go"},
+		{"usecase.go.tmpl", "internal/usecase/" + e.Snake + "_usecase.go"},
+		{"usecase_test.go.tmpl", "internal/usecase/" + e.Snake + "_usecase_test.go"},
+		{"repository.go.tmpl", "internal/repository/postgres/" + e.Snake + "_repository.go"},
+		{"dto.go.tmpl", "internal/delivery/http/dto/" + e.Snake + "_dto.go"},
+		{"handler.go.tmpl", "internal/delivery/http/handlers/" + e.Snake + "_handler.go"},
+		{"handler_test.go.tmpl", "internal/delivery/http/handlers/" + e.Snake + "_handler_test.go"},
+		{"routes.go.tmpl", "internal/delivery/http/" + e.Snake + "_routes.go"},
+		{"module.go.tmpl", "internal/app/" + e.Snake + ".go"},
+		{"migration.up.sql.tmpl", migration + ".up.sql"},
+		{"migration.down.sql.tmpl", migration + ".down.sql"},
+	}
+}
+
+// Generate writes the entity's files under root, the repository root, and
+// returns their paths relative to it. Its migration is numbered after the
+// last one in root/migrations. Nothing is written if any of the files
+// already exists.
+func Generate(root string, entity Entity) ([]string, error) {
+	number, err := nextMigration(filepath.Join(root, "migrations"))
+	if err != nil {
+		return nil, err
+	}
+	entity.Migration = fmt.Sprintf("%03d", number)
+
+	parsed, err := template.ParseFS(templates, "templates/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	files := entity.files()
+	rendered := make([][]byte, len(files))
+	for i, f := range files {
+		if _, err := os.Stat(filepath.Join(root, f.path)); err == nil {
+			return nil, fmt.Errorf("%s: %w", f.path, ErrExists)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := parsed.ExecuteTemplate(&buf, f.template, entity); err != nil {
+			return nil, fmt.Errorf("render %s: %w", f.path, err)
+		}
+		rendered[i] = buf.Bytes()
+		if strings.HasSuffix(f.path, ".go") {
+			if rendered[i], err = format.Source(rendered[i]); err != nil {
+				return nil, fmt.Errorf("format %s: %w", f.path, err)
+			}
+		}
+	}
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		path := filepath.Join(root, f.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return paths[:i], err
+		}
+		if err := os.WriteFile(path, rendered[i], 0o644); err != nil {
+			return paths[:i], err
+		}
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+// nextMigration returns the number after the highest migration in dir, or
+// 1 when there is none.
+func nextMigration(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	numbers := []int{0}
+	for _, entry := range entries {
+		if match := migrationPrefix.FindStringSubmatch(entry.Name()); match != nil {
+			n, _ := strconv.Atoi(match[1])
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+	return numbers[len(numbers)-1] + 1, nil
+}
+
+// NextSteps lists what Generate leaves to be wired by hand.
+func (e Entity) NextSteps() []string {
+	return []string{
+		fmt.Sprintf("Append %sModule to Modules in internal/app/app.go.", e.Name),
+		"Apply the new migration with go run ./cmd/migrate up.",
+		fmt.Sprintf("Add the entity's fields to domain.%s, its table, repository, DTOs and tests.", e.Name),
+	}
+}
@@ -0,0 +1,84 @@
+package scaffold
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEntity(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected Entity
+	}{
+		{
+			name: "PurchaseOrder",
+			expected: Entity{
+				Name: "PurchaseOrder", Plural: "PurchaseOrders", Var: "purchaseOrder", VarPlural: "purchaseOrders",
+				Snake: "purchase_order", Table: "purchase_orders", Path: "purchase-orders",
+				Words: "purchase order", WordsTitle: "Purchase order",
+			},
+		},
+		{
+			name: "Category",
+			expected: Entity{
+				Name: "Category", Plural: "Categories", Var: "category", VarPlural: "categories",
+				Snake: "category", Table: "categories", Path: "categories",
+				Words: "category", WordsTitle: "Category",
+			},
+		},
+		{
+			name: "SKUAlias",
+			expected: Entity{
+				Name: "SKUAlias", Plural: "SKUAliases", Var: "skuAlias", VarPlural: "skuAliases",
+				Snake: "sku_alias", Table: "sku_aliases", Path: "sku-aliases",
+				Words: "sku alias", WordsTitle: "Sku alias",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity, err := NewEntity(tt.name)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, entity)
+		})
+	}
+
+	for _, name := range []string{"", "purchaseOrder", "Purchase_Order", "Func"} {
+		_, err := NewEntity(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "migrations"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "migrations", "007_create_stores_table.up.sql"), nil, 0o644))
+
+	entity, err := NewEntity("PurchaseOrder")
+	require.NoError(t, err)
+	paths, err := Generate(root, entity)
+	require.NoError(t, err)
+
+	require.Len(t, paths, 11)
+	assert.Contains(t, paths, "migrations/008_create_purchase_orders_table.up.sql")
+	assert.Contains(t, paths, "internal/app/purchase_order.go")
+	for _, path := range paths {
+		content, err := os.ReadFile(filepath.Join(root, path))
+		require.NoError(t, err)
+		if strings.HasSuffix(path, ".go") {
+			_, err := parser.ParseFile(token.NewFileSet(), path, content, parser.AllErrors)
+			assert.NoError(t, err, path)
+		}
+	}
+
+	_, err = Generate(root, entity)
+	assert.ErrorIs(t, err, ErrExists)
+}
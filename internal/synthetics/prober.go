@@ -0,0 +1,125 @@
+// Package synthetics exercises user journeys against a running instance so
+// breakage that health checks cannot see shows up in metrics.
+package synthetics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/pkg/client"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/quantity"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// JourneyProductLifecycle creates, reads and deletes a product.
+const JourneyProductLifecycle = "product_lifecycle"
+
+type Config struct {
+	// StoreID is the dedicated store synthetic products are created in.
+	// Its trash is emptied after every run.
+	StoreID  int64
+	Interval time.Duration
+	// Timeout bounds one whole journey.
+	Timeout time.Duration
+}
+
+// Prober runs the journeys on a schedule and records their outcome and
+// per-step latency.
+type Prober struct {
+	api    *client.Client
+	cfg    Config
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	runs     *telemetry.Counter
+	duration *telemetry.Histogram
+}
+
+func NewProber(api *client.Client, registry *telemetry.Registry, cfg Config, clk clock.Clock, logger *logrus.Logger) *Prober {
+	return &Prober{
+		api:      api,
+		cfg:      cfg,
+		logger:   logger,
+		clock:    clk,
+		runs:     registry.NewCounter("synthetic_checks_total", "Synthetic journey runs by result.", "journey", "step", "result"),
+		duration: registry.NewHistogram("synthetic_check_step_duration_seconds", "Latency of successful synthetic journey steps.", telemetry.DefaultDurationBuckets, "journey", "step"),
+	}
+}
+
+// Run checks every interval until ctx is cancelled.
+func (p *Prober) Run(ctx context.Context) {
+	p.logger.WithFields(logrus.Fields{"interval": p.cfg.Interval, "store_id": p.cfg.StoreID}).Info("Synthetic prober started")
+
+	ticker := p.clock.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("Synthetic prober stopped")
+			return
+		case <-ticker.C():
+			if err := p.Check(ctx); err != nil && ctx.Err() == nil {
+				p.logger.WithError(err).WithField("journey", JourneyProductLifecycle).Warn("Synthetic check failed")
+			}
+		}
+	}
+}
+
+// Check runs the product lifecycle journey once. The result is counted
+// under the step that failed, or under "complete" on success.
+func (p *Prober) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	var product *client.Product
+	steps := []struct {
+		name string
+		run  func() error
+	}{
+		{"create", func() error {
+			var err error
+			product, err = p.api.CreateProduct(ctx, client.ProductInput{
+				StoreID: p.cfg.StoreID,
+				Name:    fmt.Sprintf("synthetic-check-%d", p.clock.Now().UnixNano()),
+				Amount:  quantity.New(1),
+				Price:   1,
+				Status:  "inactive",
+			})
+			return err
+		}},
+		{"read", func() error {
+			got, err := p.api.GetProduct(ctx, product.ID)
+			if err != nil {
+				return err
+			}
+			if got.Name != product.Name {
+				return fmt.Errorf("read back name %q, created %q", got.Name, product.Name)
+			}
+			return nil
+		}},
+		{"delete", func() error {
+			return p.api.DeleteProduct(ctx, product.ID)
+		}},
+		{"empty_trash", func() error {
+			_, err := p.api.EmptyTrash(ctx, p.cfg.StoreID)
+			return err
+		}},
+	}
+
+	for _, step := range steps {
+		start := p.clock.Now()
+		if err := step.run(); err != nil {
+			p.runs.Inc(JourneyProductLifecycle, step.name, "failure")
+			return fmt.Errorf("%s: %w", step.name, err)
+		}
+		p.duration.Observe(p.clock.Now().Sub(start).Seconds(), JourneyProductLifecycle, step.name)
+	}
+
+	p.runs.Inc(JourneyProductLifecycle, "complete", "success")
+	return nil
+}
@@ -0,0 +1,113 @@
+package synthetics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/client"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI serves the product and trash endpoints the journey uses.
+// failStep makes that step return 500.
+func fakeAPI(t *testing.T, failStep string) *httptest.Server {
+	var product client.Product
+	fail := func(step string, w http.ResponseWriter) bool {
+		if step == failStep {
+			w.WriteHeader(http.StatusInternalServerError)
+			return true
+		}
+		return false
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/products", func(w http.ResponseWriter, r *http.Request) {
+		if fail("create", w) {
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&product))
+		assert.Equal(t, int64(99), product.StoreID)
+		product.ID = 7
+		w.WriteHeader(http.StatusCreated)
+		require.NoError(t, json.NewEncoder(w).Encode(product))
+	})
+	mux.HandleFunc("GET /api/v1/products/7", func(w http.ResponseWriter, r *http.Request) {
+		if fail("read", w) {
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(product))
+	})
+	mux.HandleFunc("DELETE /api/v1/products/7", func(w http.ResponseWriter, r *http.Request) {
+		if fail("delete", w) {
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /api/v1/trash", func(w http.ResponseWriter, r *http.Request) {
+		if fail("empty_trash", w) {
+			return
+		}
+		assert.Equal(t, "99", r.URL.Query().Get("store_id"))
+		assert.Equal(t, "true", r.Header.Get("X-Confirm-Mass-Operation"))
+		w.Write([]byte(`{"deleted":1}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestProber_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		failStep    string
+		expectedErr string
+		expected    string
+	}{
+		{
+			name:     "journey succeeds",
+			expected: `synthetic_checks_total{journey="product_lifecycle",result="success",step="complete"} 1`,
+		},
+		{
+			name:        "read fails",
+			failStep:    "read",
+			expectedErr: "read: api error 500",
+			expected:    `synthetic_checks_total{journey="product_lifecycle",result="failure",step="read"} 1`,
+		},
+		{
+			name:        "trash cleanup fails",
+			failStep:    "empty_trash",
+			expectedErr: "empty_trash: api error 500",
+			expected:    `synthetic_checks_total{journey="product_lifecycle",result="failure",step="empty_trash"} 1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := fakeAPI(t, tt.failStep)
+			defer server.Close()
+
+			registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+			prober := NewProber(client.New(server.URL, server.Client()), registry,
+				Config{StoreID: 99, Interval: time.Minute, Timeout: 5 * time.Second}, clock.Real(), logrus.New())
+
+			err := prober.Check(context.Background())
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			var buf bytes.Buffer
+			require.NoError(t, registry.WritePrometheus(&buf))
+			assert.Contains(t, buf.String(), tt.expected)
+		})
+	}
+}
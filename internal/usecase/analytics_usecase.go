@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+)
+
+// analyticsClockSkew is how far in the future a client's event time may
+// be.
+const analyticsClockSkew = 5 * time.Minute
+
+// AnalyticsUseCase takes in storefront events and reports the counts
+// recorded for a product.
+type AnalyticsUseCase struct {
+	recorder    AnalyticsRecorder
+	repo        AnalyticsRepository
+	productRepo ProductRepository
+	clock       clock.Clock
+}
+
+func NewAnalyticsUseCase(recorder AnalyticsRecorder, repo AnalyticsRepository, productRepo ProductRepository, clk clock.Clock) *AnalyticsUseCase {
+	return &AnalyticsUseCase{
+		recorder:    recorder,
+		repo:        repo,
+		productRepo: productRepo,
+		clock:       clk,
+	}
+}
+
+// RecordEvents validates the batch and hands it to the recorder, returning
+// how many events were kept. A batch with an invalid event is refused
+// whole. Product IDs are not looked up, so events for unknown products are
+// counted too.
+func (uc *AnalyticsUseCase) RecordEvents(ctx context.Context, events []domain.AnalyticsEvent) (int, error) {
+	if len(events) == 0 || len(events) > domain.MaxAnalyticsBatch {
+		return 0, fmt.Errorf("%w: send 1 to %d events", domain.ErrInvalidAnalyticsEvent, domain.MaxAnalyticsBatch)
+	}
+
+	now := uc.clock.Now()
+	for i := range events {
+		event := &events[i]
+		if err := event.Validate(); err != nil {
+			return 0, fmt.Errorf("%w: event %d: %s", domain.ErrInvalidAnalyticsEvent, i+1, err.Error())
+		}
+		if event.OccurredAt.IsZero() {
+			event.OccurredAt = now
+		} else if event.OccurredAt.After(now.Add(analyticsClockSkew)) {
+			return 0, fmt.Errorf("%w: event %d: occurred_at is in the future", domain.ErrInvalidAnalyticsEvent, i+1)
+		}
+	}
+
+	return uc.recorder.Record(events), nil
+}
+
+// GetProductStats totals the product's events over the last days UTC
+// days, today included, or ever when days is not positive. Events still
+// buffered are not counted yet.
+func (uc *AnalyticsUseCase) GetProductStats(ctx context.Context, productID int64, days int) (*domain.ProductStats, error) {
+	if productID <= 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", domain.ErrInvalidProduct)
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var since time.Time
+	if days > 0 {
+		year, month, day := uc.clock.Now().UTC().Date()
+		since = time.Date(year, month, day-(days-1), 0, 0, 0, 0, time.UTC)
+	}
+
+	stats, err := uc.repo.GetProductStats(ctx, productID, since)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get product stats from repository")
+		return nil, fmt.Errorf("failed to get product stats: %w", err)
+	}
+	stats.StoreID = product.StoreID
+
+	return stats, nil
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAnalyticsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnalyticsRepository) GetProductStats(ctx context.Context, productID int64, since time.Time) (*domain.ProductStats, error) {
+	args := m.Called(ctx, productID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProductStats), args.Error(1)
+}
+
+// keepingRecorder keeps up to capacity events, like a recorder whose
+// buffer fills up.
+type keepingRecorder struct {
+	capacity int
+	events   []domain.AnalyticsEvent
+}
+
+func (r *keepingRecorder) Record(events []domain.AnalyticsEvent) int {
+	kept := min(len(events), r.capacity-len(r.events))
+	r.events = append(r.events, events[:kept]...)
+	return kept
+}
+
+func TestAnalyticsUseCase_RecordEvents(t *testing.T) {
+	now := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("defaults the event time and reports dropped events", func(t *testing.T) {
+		recorder := &keepingRecorder{capacity: 2}
+		uc := NewAnalyticsUseCase(recorder, &MockAnalyticsRepository{}, &MockProductRepository{}, clock.NewFake(now))
+
+		accepted, err := uc.RecordEvents(ctx, []domain.AnalyticsEvent{
+			{ProductID: 1, Type: domain.AnalyticsEventView},
+			{ProductID: 1, Type: domain.AnalyticsEventAddToCart, OccurredAt: now.Add(-time.Hour)},
+			{ProductID: 2, Type: domain.AnalyticsEventPurchase},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, accepted)
+		assert.Equal(t, now, recorder.events[0].OccurredAt)
+		assert.Equal(t, now.Add(-time.Hour), recorder.events[1].OccurredAt)
+	})
+
+	tests := []struct {
+		name   string
+		events []domain.AnalyticsEvent
+	}{
+		{name: "empty batch", events: nil},
+		{name: "batch too large", events: make([]domain.AnalyticsEvent, domain.MaxAnalyticsBatch+1)},
+		{name: "unknown type", events: []domain.AnalyticsEvent{{ProductID: 1, Type: "click"}}},
+		{name: "missing product", events: []domain.AnalyticsEvent{{Type: domain.AnalyticsEventView}}},
+		{name: "future event", events: []domain.AnalyticsEvent{{ProductID: 1, Type: domain.AnalyticsEventView, OccurredAt: now.Add(time.Hour)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &keepingRecorder{capacity: 10}
+			uc := NewAnalyticsUseCase(recorder, &MockAnalyticsRepository{}, &MockProductRepository{}, clock.NewFake(now))
+
+			_, err := uc.RecordEvents(ctx, tt.events)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidAnalyticsEvent)
+			assert.Empty(t, recorder.events, "an invalid batch is refused whole")
+		})
+	}
+}
+
+func TestAnalyticsUseCase_GetProductStats(t *testing.T) {
+	now := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("totals the last days", func(t *testing.T) {
+		repo := &MockAnalyticsRepository{}
+		productRepo := &MockProductRepository{}
+		since := time.Date(2024, 2, 27, 0, 0, 0, 0, time.UTC)
+		productRepo.On("GetByID", mock.Anything, int64(4)).Return(&domain.Product{ID: 4, StoreID: 3}, nil)
+		repo.On("GetProductStats", mock.Anything, int64(4), since).Return(&domain.ProductStats{ProductID: 4, Views: 12, Since: since}, nil)
+
+		uc := NewAnalyticsUseCase(&keepingRecorder{}, repo, productRepo, clock.NewFake(now))
+		stats, err := uc.GetProductStats(ctx, 4, 5)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), stats.Views)
+		assert.Equal(t, int64(3), stats.StoreID)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("all time", func(t *testing.T) {
+		repo := &MockAnalyticsRepository{}
+		productRepo := &MockProductRepository{}
+		productRepo.On("GetByID", mock.Anything, int64(4)).Return(&domain.Product{ID: 4, StoreID: 3}, nil)
+		repo.On("GetProductStats", mock.Anything, int64(4), time.Time{}).Return(&domain.ProductStats{ProductID: 4}, nil)
+
+		uc := NewAnalyticsUseCase(&keepingRecorder{}, repo, productRepo, clock.NewFake(now))
+		_, err := uc.GetProductStats(ctx, 4, 0)
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		productRepo := &MockProductRepository{}
+		productRepo.On("GetByID", mock.Anything, int64(9)).Return(nil, domain.ErrProductNotFound)
+
+		uc := NewAnalyticsUseCase(&keepingRecorder{}, &MockAnalyticsRepository{}, productRepo, clock.NewFake(now))
+		_, err := uc.GetProductStats(ctx, 9, 0)
+
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	})
+}
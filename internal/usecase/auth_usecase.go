@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/authtoken"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// unknownUserHash is compared against when a login names an unknown
+// email, so the response time does not reveal which emails are
+// registered.
+var unknownUserHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("unknown user"), bcrypt.DefaultCost)
+	return hash
+})
+
+// AuthUseCase registers users and exchanges their credentials, or a
+// refresh token, for a pair of JWTs. Users enrolled in two-factor
+// authentication also need a code to log in.
+type AuthUseCase struct {
+	repo      UserRepository
+	tokens    *authtoken.Issuer
+	twoFactor TwoFactorUseCaseInterface
+}
+
+// NewAuthUseCase builds the use case. twoFactor may be nil when two-factor
+// authentication is unavailable.
+func NewAuthUseCase(repo UserRepository, tokens *authtoken.Issuer, twoFactor TwoFactorUseCaseInterface) *AuthUseCase {
+	return &AuthUseCase{
+		repo:      repo,
+		tokens:    tokens,
+		twoFactor: twoFactor,
+	}
+}
+
+func (uc *AuthUseCase) Register(ctx context.Context, email, password string) (*domain.User, error) {
+	email = domain.NormalizeEmail(email)
+	if err := domain.ValidateRegistration(email, password); err != nil {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user, err := uc.repo.Create(ctx, &domain.User{Email: email, PasswordHash: string(hash)})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":  "register_user",
+		"user_id": user.ID,
+	}).Info("User registered")
+
+	return user, nil
+}
+
+// Login checks the password, then the second factor: code is a TOTP or
+// recovery code, and may be empty for users who have not enrolled.
+func (uc *AuthUseCase) Login(ctx context.Context, email, password, code string) (*domain.AuthTokens, error) {
+	user, err := uc.repo.GetByEmail(ctx, domain.NormalizeEmail(email))
+	if errors.Is(err, domain.ErrUserNotFound) {
+		bcrypt.CompareHashAndPassword(unknownUserHash(), []byte(password))
+		return nil, domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+	if uc.twoFactor != nil {
+		if err := uc.twoFactor.Verify(ctx, user.Identity(), domain.TwoFactorRoleUser, code); err != nil {
+			return nil, err
+		}
+	}
+
+	return uc.issue(user)
+}
+
+// Refresh issues a new pair for a valid refresh token whose user still
+// exists.
+func (uc *AuthUseCase) Refresh(ctx context.Context, refreshToken string) (*domain.AuthTokens, error) {
+	claims, err := uc.tokens.Verify(refreshToken, authtoken.KindRefresh)
+	if err != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	id, err := claims.UserID()
+	if err != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	user, err := uc.repo.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.issue(user)
+}
+
+func (uc *AuthUseCase) issue(user *domain.User) (*domain.AuthTokens, error) {
+	pair, err := uc.tokens.Issue(user.ID, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuthTokens{
+		AccessToken:      pair.AccessToken,
+		AccessExpiresIn:  pair.AccessExpiresAt.Sub(pair.IssuedAt),
+		RefreshToken:     pair.RefreshToken,
+		RefreshExpiresAt: pair.RefreshExpiresAt,
+	}, nil
+}
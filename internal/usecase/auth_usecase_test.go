@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/authtoken"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/totp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id int64) (*domain.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func newTestAuthUseCase(repo UserRepository) (*AuthUseCase, *authtoken.Issuer) {
+	return newTestAuthUseCaseWithTwoFactor(repo, nil)
+}
+
+func newTestAuthUseCaseWithTwoFactor(repo UserRepository, twoFactor TwoFactorUseCaseInterface) (*AuthUseCase, *authtoken.Issuer) {
+	tokens := authtoken.NewIssuer([]byte("0123456789abcdef0123456789abcdef"), "product-service", 15*time.Minute, 24*time.Hour, clock.Real())
+	return NewAuthUseCase(repo, tokens, twoFactor), tokens
+}
+
+func TestAuthUseCase_Register(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hashes the password", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		uc, _ := newTestAuthUseCase(mockRepo)
+
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Email == "ada@example.com" &&
+				bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte("correct horse")) == nil
+		})).Return(&domain.User{ID: 1, Email: "ada@example.com"}, nil)
+
+		user, err := uc.Register(ctx, " Ada@Example.com ", "correct horse")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), user.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+	}{
+		{name: "invalid email", email: "ada", password: "correct horse"},
+		{name: "display name", email: "Ada <ada@example.com>", password: "correct horse"},
+		{name: "short password", email: "ada@example.com", password: "short"},
+		{name: "long password", email: "ada@example.com", password: string(make([]byte, domain.MaxPasswordLength+1))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _ := newTestAuthUseCase(new(MockUserRepository))
+			_, err := uc.Register(ctx, tt.email, tt.password)
+			assert.ErrorIs(t, err, domain.ErrInvalidUser)
+		})
+	}
+
+	t.Run("email taken", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		uc, _ := newTestAuthUseCase(mockRepo)
+		mockRepo.On("Create", ctx, mock.Anything).Return(nil, domain.ErrEmailTaken)
+
+		_, err := uc.Register(ctx, "ada@example.com", "correct horse")
+		assert.ErrorIs(t, err, domain.ErrEmailTaken)
+	})
+}
+
+func TestAuthUseCase_LoginAndRefresh(t *testing.T) {
+	ctx := context.Background()
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &domain.User{ID: 7, Email: "ada@example.com", PasswordHash: string(hash)}
+
+	mockRepo := new(MockUserRepository)
+	uc, tokens := newTestAuthUseCase(mockRepo)
+	mockRepo.On("GetByEmail", ctx, "ada@example.com").Return(user, nil)
+	mockRepo.On("GetByEmail", ctx, "bob@example.com").Return(nil, domain.ErrUserNotFound)
+
+	_, err = uc.Login(ctx, "ada@example.com", "wrong horse", "")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	_, err = uc.Login(ctx, "bob@example.com", "correct horse", "")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+
+	issued, err := uc.Login(ctx, "ADA@example.com", "correct horse", "")
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, issued.AccessExpiresIn)
+	claims, err := tokens.Verify(issued.AccessToken, authtoken.KindAccess)
+	require.NoError(t, err)
+	assert.Equal(t, "7", claims.Subject)
+
+	mockRepo.On("GetByID", ctx, int64(7)).Return(user, nil).Once()
+	refreshed, err := uc.Refresh(ctx, issued.RefreshToken)
+	require.NoError(t, err)
+	_, err = tokens.Verify(refreshed.AccessToken, authtoken.KindAccess)
+	assert.NoError(t, err)
+
+	_, err = uc.Refresh(ctx, issued.AccessToken)
+	assert.ErrorIs(t, err, domain.ErrInvalidRefreshToken)
+
+	mockRepo.On("GetByID", ctx, int64(7)).Return(nil, domain.ErrUserNotFound).Once()
+	_, err = uc.Refresh(ctx, issued.RefreshToken)
+	assert.ErrorIs(t, err, domain.ErrInvalidRefreshToken)
+}
+
+func TestAuthUseCase_Login_TwoFactor(t *testing.T) {
+	ctx := context.Background()
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &domain.User{ID: 7, Email: "ada@example.com", PasswordHash: string(hash)}
+
+	t.Run("enrolled", func(t *testing.T) {
+		secret, err := totp.GenerateSecret()
+		require.NoError(t, err)
+		code, err := totp.Code(secret, time.Now())
+		require.NoError(t, err)
+		confirmedAt := time.Now()
+
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", ctx, "ada@example.com").Return(user, nil)
+		twoFactorRepo := new(MockTwoFactorRepository)
+		twoFactorRepo.On("Get", ctx, user.Identity()).Return(&domain.TwoFactorEnrollment{Identity: user.Identity(), ConfirmedAt: &confirmedAt}, nil)
+		twoFactorRepo.On("UseRecoveryCode", ctx, user.Identity(), mock.Anything).Return(false, nil)
+		twoFactorRepo.On("UseTOTPStep", ctx, user.Identity(), mock.Anything).Return(true, nil)
+		secrets := new(MockSecretStore)
+		secrets.On("Get", ctx, domain.TwoFactorSecretKey(user.Identity())).Return([]byte(secret), nil)
+		uc, _ := newTestAuthUseCaseWithTwoFactor(mockRepo, NewTwoFactorUseCase(twoFactorRepo, secrets, "product-service", optionalPolicy, clock.Real()))
+
+		_, err = uc.Login(ctx, "ada@example.com", "correct horse", "")
+		assert.ErrorIs(t, err, domain.ErrTwoFactorRequired)
+		_, err = uc.Login(ctx, "ada@example.com", "correct horse", "abcde-fghij")
+		assert.ErrorIs(t, err, domain.ErrInvalidTwoFactorCode)
+		_, err = uc.Login(ctx, "ada@example.com", "wrong horse", code)
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+
+		issued, err := uc.Login(ctx, "ada@example.com", "correct horse", code)
+		require.NoError(t, err)
+		assert.NotEmpty(t, issued.AccessToken)
+	})
+
+	t.Run("required for users", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", ctx, "ada@example.com").Return(user, nil)
+		twoFactorRepo := new(MockTwoFactorRepository)
+		twoFactorRepo.On("Get", ctx, user.Identity()).Return(nil, domain.ErrTwoFactorNotEnrolled)
+		policies := domain.TwoFactorPolicies{
+			Default: domain.TwoFactorPolicyOptional,
+			Roles:   map[string]string{domain.TwoFactorRoleUser: domain.TwoFactorPolicyRequired},
+		}
+		uc, _ := newTestAuthUseCaseWithTwoFactor(mockRepo, NewTwoFactorUseCase(twoFactorRepo, new(MockSecretStore), "product-service", policies, clock.Real()))
+
+		_, err := uc.Login(ctx, "ada@example.com", "correct horse", "")
+		assert.ErrorIs(t, err, domain.ErrTwoFactorEnrollment)
+	})
+}
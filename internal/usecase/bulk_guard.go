@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// usedTokenGrace keeps a used confirmation token counted for a while after
+// it expires, covering the clock skew its signer tolerates.
+const usedTokenGrace = time.Minute
+
+// BulkGuard holds back mutations that touch more than threshold rows. Only
+// elevated callers may run them, and only in two steps: the first attempt
+// is refused with a confirmation token, and the operation runs once the
+// same caller repeats it with that token within the confirmation window.
+// Both steps are written to the audit log.
+type BulkGuard struct {
+	signer    BulkTokenSigner
+	used      UsageCounter
+	audit     AuditRecorder
+	threshold int64
+	ttl       time.Duration
+	clock     clock.Clock
+}
+
+// NewBulkGuard issues confirmation tokens valid for ttl. used remembers
+// which tokens were honored, so each confirms one operation. audit may be
+// nil, in which case the steps are only logged.
+func NewBulkGuard(signer BulkTokenSigner, used UsageCounter, audit AuditRecorder, threshold int64, ttl time.Duration, clk clock.Clock) *BulkGuard {
+	return &BulkGuard{
+		signer:    signer,
+		used:      used,
+		audit:     audit,
+		threshold: threshold,
+		ttl:       ttl,
+		clock:     clk,
+	}
+}
+
+// Check returns nil when op may run: it touches at most the threshold, or
+// the caller on ctx confirmed it, in which case confirmed is true.
+// Otherwise it returns ErrInsufficientScope for callers that are not
+// elevated, a *domain.BulkConfirmationError carrying a new token for
+// callers that sent none, or ErrInvalidConfirmationToken. A token only
+// confirms an operation of the size it was issued for, so a store that
+// changed in between has to be confirmed again. A nil guard lets every
+// operation through unconfirmed.
+func (g *BulkGuard) Check(ctx context.Context, op domain.BulkOperation) (confirmed bool, err error) {
+	if g == nil || op.Affected <= g.threshold {
+		return false, nil
+	}
+
+	caller := BulkCallerFrom(ctx)
+	if !caller.Elevated {
+		return false, fmt.Errorf("%w: %s touches %d rows, more than %d", domain.ErrInsufficientScope, op.Kind, op.Affected, g.threshold)
+	}
+	if caller.Token == "" {
+		return false, g.request(ctx, caller, op)
+	}
+	if err := g.confirm(ctx, caller, op); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (g *BulkGuard) request(ctx context.Context, caller BulkCaller, op domain.BulkOperation) error {
+	token, _, expiresAt, err := g.signer.Issue(caller.Actor, op.String(), g.ttl)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to sign confirmation token")
+		return fmt.Errorf("failed to request confirmation: %w", err)
+	}
+	if err := g.record(ctx, caller, domain.AuditEventBulkRequested, op); err != nil {
+		return err
+	}
+
+	return &domain.BulkConfirmationError{Confirmation: &domain.BulkConfirmation{
+		Operation: op,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}}
+}
+
+func (g *BulkGuard) confirm(ctx context.Context, caller BulkCaller, op domain.BulkOperation) error {
+	id, err := g.signer.Verify(caller.Token, caller.Actor, op.String())
+	if err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrInvalidConfirmationToken, err.Error())
+	}
+
+	used, _, err := g.used.Consume(ctx, "bulk-confirmation:"+id, 1, g.ttl+usedTokenGrace)
+	if err != nil {
+		return fmt.Errorf("failed to check confirmation token: %w", err)
+	}
+	if used > 1 {
+		return fmt.Errorf("%w: already used", domain.ErrInvalidConfirmationToken)
+	}
+
+	return g.record(ctx, caller, domain.AuditEventBulkConfirmed, op)
+}
+
+// record writes a step to the audit log. The operation is refused when it
+// cannot be recorded.
+func (g *BulkGuard) record(ctx context.Context, caller BulkCaller, event string, op domain.BulkOperation) error {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":    event,
+		"actor":     caller.Actor,
+		"operation": op.Kind,
+		"store_id":  op.StoreID,
+		"affected":  op.Affected,
+	}).Info("Recording bulk operation confirmation step")
+
+	if g.audit == nil {
+		return nil
+	}
+	entry := &domain.AuditEntry{
+		Actor:      caller.Actor,
+		ClientIP:   caller.ClientIP,
+		Event:      event,
+		Detail:     op.String(),
+		OccurredAt: g.clock.Now().UTC(),
+	}
+	if err := g.audit.Create(ctx, entry); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to record bulk operation audit event")
+		return fmt.Errorf("failed to record %s: %w", event, err)
+	}
+	return nil
+}
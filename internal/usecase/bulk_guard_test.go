@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/confirmtoken"
+	"backend-context-engineering-template/pkg/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingAudit struct {
+	mu      sync.Mutex
+	entries []*domain.AuditEntry
+	err     error
+}
+
+func (a *recordingAudit) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.err != nil {
+		return a.err
+	}
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+func (a *recordingAudit) events() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	events := make([]string, len(a.entries))
+	for i, entry := range a.entries {
+		events[i] = entry.Event
+	}
+	return events
+}
+
+func TestBulkGuard_Check(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	op := domain.BulkOperation{Kind: domain.BulkOperationEmptyTrash, StoreID: 4, Affected: 250}
+	owner := BulkCaller{Actor: "key:abc", ClientIP: "10.0.0.1", Elevated: true}
+
+	setup := func() (*BulkGuard, *recordingAudit) {
+		audit := &recordingAudit{}
+		signer := confirmtoken.NewSigner([]byte("0123456789abcdef0123456789abcdef"), fake)
+		return NewBulkGuard(signer, ratelimit.NewMemoryStore(fake), audit, 100, 10*time.Minute, fake), audit
+	}
+	hold := func(t *testing.T, guard *BulkGuard, caller BulkCaller) *domain.BulkConfirmation {
+		_, err := guard.Check(WithBulkCaller(context.Background(), caller), op)
+		var held *domain.BulkConfirmationError
+		require.ErrorAs(t, err, &held)
+		assert.ErrorIs(t, err, domain.ErrConfirmationRequired)
+		return held.Confirmation
+	}
+	confirm := func(guard *BulkGuard, caller BulkCaller, token string, op domain.BulkOperation) (bool, error) {
+		caller.Token = token
+		return guard.Check(WithBulkCaller(context.Background(), caller), op)
+	}
+
+	t.Run("small operations pass unconfirmed", func(t *testing.T) {
+		guard, audit := setup()
+		confirmed, err := guard.Check(context.Background(), domain.BulkOperation{Kind: domain.BulkOperationEmptyTrash, StoreID: 4, Affected: 100})
+		assert.NoError(t, err)
+		assert.False(t, confirmed)
+		assert.Empty(t, audit.events())
+	})
+
+	t.Run("callers without the elevated scope are refused", func(t *testing.T) {
+		guard, audit := setup()
+		_, err := guard.Check(WithBulkCaller(context.Background(), BulkCaller{Actor: "key:abc"}), op)
+		assert.ErrorIs(t, err, domain.ErrInsufficientScope)
+		assert.Empty(t, audit.events())
+	})
+
+	t.Run("held, then confirmed once", func(t *testing.T) {
+		guard, audit := setup()
+		held := hold(t, guard, owner)
+		assert.Equal(t, op, held.Operation)
+		assert.Equal(t, fake.Now().Add(10*time.Minute), held.ExpiresAt)
+
+		fake.Advance(5 * time.Minute)
+		confirmed, err := confirm(guard, owner, held.Token, op)
+		require.NoError(t, err)
+		assert.True(t, confirmed)
+
+		_, err = confirm(guard, owner, held.Token, op)
+		assert.ErrorIs(t, err, domain.ErrInvalidConfirmationToken)
+
+		assert.Equal(t, []string{domain.AuditEventBulkRequested, domain.AuditEventBulkConfirmed}, audit.events())
+		assert.Equal(t, "key:abc", audit.entries[1].Actor)
+		assert.Equal(t, "10.0.0.1", audit.entries[1].ClientIP)
+		assert.Equal(t, "empty_trash store=4 rows=250", audit.entries[1].Detail)
+	})
+
+	t.Run("tokens expire", func(t *testing.T) {
+		guard, _ := setup()
+		held := hold(t, guard, owner)
+		fake.Advance(11 * time.Minute)
+		_, err := confirm(guard, owner, held.Token, op)
+		assert.ErrorIs(t, err, domain.ErrInvalidConfirmationToken)
+	})
+
+	t.Run("tokens only confirm the operation they were issued for", func(t *testing.T) {
+		guard, _ := setup()
+		held := hold(t, guard, owner)
+
+		grown := op
+		grown.Affected = 251
+		_, err := confirm(guard, owner, held.Token, grown)
+		assert.ErrorIs(t, err, domain.ErrInvalidConfirmationToken)
+
+		_, err = confirm(guard, BulkCaller{Actor: "key:def", Elevated: true}, held.Token, op)
+		assert.ErrorIs(t, err, domain.ErrInvalidConfirmationToken)
+	})
+
+	t.Run("refused when the audit log is unavailable", func(t *testing.T) {
+		guard, audit := setup()
+		audit.err = errors.New("database error")
+		_, err := guard.Check(WithBulkCaller(context.Background(), owner), op)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrConfirmationRequired)
+	})
+}
+
+func TestTrashUseCase_EmptyTrashBulk(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	signer := confirmtoken.NewSigner([]byte("0123456789abcdef0123456789abcdef"), fake)
+	guard := NewBulkGuard(signer, ratelimit.NewMemoryStore(fake), &recordingAudit{}, 100, 10*time.Minute, fake)
+	caller := BulkCaller{Actor: "key:abc", Elevated: true}
+
+	repo := &MockTrashRepository{}
+	repo.On("Count", mock.Anything, int64(4)).Return(int64(250), nil)
+	repo.On("Empty", mock.Anything, int64(4)).Return(int64(250), nil).Once()
+	uc := NewTrashUseCase(repo, guard, 7*24*time.Hour, fake)
+
+	_, err := uc.EmptyTrash(WithBulkCaller(context.Background(), caller), 4)
+	var held *domain.BulkConfirmationError
+	require.ErrorAs(t, err, &held)
+
+	// The mass operation header alone does not confirm a bulk operation.
+	_, err = uc.EmptyTrash(WithMassOperationConfirmed(context.Background()), 4)
+	assert.ErrorIs(t, err, domain.ErrInsufficientScope)
+
+	caller.Token = held.Confirmation.Token
+	deleted, err := uc.EmptyTrash(WithBulkCaller(context.Background(), caller), 4)
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), deleted)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// BundleUseCase manages products sold as a set of other products. A
+// bundle's availability is derived from its components' stock, and selling
+// it takes the components off stock together.
+type BundleUseCase struct {
+	bundleRepo  BundleRepository
+	productRepo ProductRepository
+	events      EventPublisher
+	clock       clock.Clock
+}
+
+// NewBundleUseCase builds the bundle use case. events may be nil when
+// nothing subscribes to stock changes.
+func NewBundleUseCase(bundleRepo BundleRepository, productRepo ProductRepository, events EventPublisher, clk clock.Clock) *BundleUseCase {
+	return &BundleUseCase{
+		bundleRepo:  bundleRepo,
+		productRepo: productRepo,
+		events:      events,
+		clock:       clk,
+	}
+}
+
+func (uc *BundleUseCase) GetBundle(ctx context.Context, id int64) (*domain.Bundle, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid bundle ID", domain.ErrInvalidBundle)
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get product from repository")
+		return nil, err
+	}
+
+	return uc.load(ctx, product)
+}
+
+// SaveBundle makes the product a bundle of the given components, replacing
+// any it had. Components must be products of the same store that are not
+// bundles themselves, each listed once, in an amount their unit allows.
+func (uc *BundleUseCase) SaveBundle(ctx context.Context, id int64, components []domain.BundleComponent) (*domain.Bundle, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":     "save_bundle",
+		"bundle_id":  id,
+		"components": len(components),
+	}).Info("Saving bundle")
+
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid bundle ID", domain.ErrInvalidBundle)
+	}
+	if len(components) == 0 {
+		return nil, fmt.Errorf("%w: a bundle needs at least one component", domain.ErrInvalidBundle)
+	}
+	if len(components) > domain.MaxBundleComponents {
+		return nil, fmt.Errorf("%w: a bundle has at most %d components", domain.ErrInvalidBundle, domain.MaxBundleComponents)
+	}
+
+	bundle, err := uc.productRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get product from repository")
+		return nil, err
+	}
+
+	isComponent, err := uc.bundleRepo.IsComponent(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to check bundle components")
+		return nil, fmt.Errorf("failed to save bundle: %w", err)
+	}
+	if isComponent {
+		return nil, fmt.Errorf("%w: product %d is a component of another bundle", domain.ErrInvalidBundle, id)
+	}
+
+	seen := make(map[int64]bool, len(components))
+	for _, component := range components {
+		if err := uc.validateComponent(ctx, bundle, component, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.bundleRepo.SetComponents(ctx, id, components); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to save bundle components in repository")
+		return nil, fmt.Errorf("failed to save bundle: %w", err)
+	}
+
+	return uc.load(ctx, bundle)
+}
+
+func (uc *BundleUseCase) DeleteBundle(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return fmt.Errorf("%w: invalid bundle ID", domain.ErrInvalidBundle)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":    "delete_bundle",
+		"bundle_id": id,
+	}).Info("Deleting bundle components")
+
+	if err := uc.bundleRepo.DeleteComponents(ctx, id); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to delete bundle components from repository")
+		return err
+	}
+
+	return nil
+}
+
+// SellBundle takes count bundles' worth of every component off stock in
+// one transaction, and publishes a stock change for each component. If any
+// component is short, nothing is taken and ErrInsufficientStock is
+// returned.
+func (uc *BundleUseCase) SellBundle(ctx context.Context, id int64, count int64) (*domain.Bundle, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":    "sell_bundle",
+		"bundle_id": id,
+		"count":     count,
+	}).Info("Selling bundle")
+
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid bundle ID", domain.ErrInvalidBundle)
+	}
+	if count <= 0 {
+		return nil, fmt.Errorf("%w: count must be positive", domain.ErrInvalidBundle)
+	}
+
+	bundle, err := uc.GetBundle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Available < count {
+		return nil, fmt.Errorf("%w: %d of bundle %d available", domain.ErrInsufficientStock, bundle.Available, id)
+	}
+
+	decrements := make([]domain.StockDecrement, len(bundle.Components))
+	for i, component := range bundle.Components {
+		amount, err := component.Quantity.Mul(count)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %d of bundle %d available", domain.ErrInsufficientStock, bundle.Available, id)
+		}
+		decrements[i] = domain.StockDecrement{ProductID: component.ProductID, Amount: amount}
+	}
+
+	updated, err := uc.bundleRepo.DecrementStock(ctx, decrements)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to decrement bundle component stock")
+		if errors.Is(err, domain.ErrInsufficientStock) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to sell bundle: %w", err)
+	}
+
+	sold := make(map[int64]domain.StockDecrement, len(decrements))
+	for _, decrement := range decrements {
+		sold[decrement.ProductID] = decrement
+	}
+	for _, product := range updated {
+		previous, err := product.Amount.Add(sold[product.ID].Amount)
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).WithField("product_id", product.ID).Warn("Failed to derive previous stock for event")
+			continue
+		}
+		emitEvent(ctx, uc.events, uc.clock, domain.ProductEvent{
+			Type:      domain.StockEventChanged,
+			StoreID:   product.StoreID,
+			ProductID: product.ID,
+			Stock: &domain.StockChange{
+				PreviousAmount: previous,
+				Amount:         product.Amount,
+				Unit:           product.Unit,
+			},
+		})
+	}
+
+	return uc.load(ctx, bundle.Product)
+}
+
+func (uc *BundleUseCase) validateComponent(ctx context.Context, bundle *domain.Product, component domain.BundleComponent, seen map[int64]bool) error {
+	if component.ProductID <= 0 {
+		return fmt.Errorf("%w: invalid component product ID", domain.ErrInvalidBundle)
+	}
+	if component.ProductID == bundle.ID {
+		return fmt.Errorf("%w: a bundle cannot contain itself", domain.ErrInvalidBundle)
+	}
+	if seen[component.ProductID] {
+		return fmt.Errorf("%w: product %d is listed twice", domain.ErrInvalidBundle, component.ProductID)
+	}
+	seen[component.ProductID] = true
+
+	if component.Quantity.Sign() <= 0 {
+		return fmt.Errorf("%w: quantity of product %d must be positive", domain.ErrInvalidBundle, component.ProductID)
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, component.ProductID)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return fmt.Errorf("%w: product %d not found", domain.ErrInvalidBundle, component.ProductID)
+	}
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get component product from repository")
+		return fmt.Errorf("failed to save bundle: %w", err)
+	}
+	if product.StoreID != bundle.StoreID {
+		return fmt.Errorf("%w: product %d belongs to another store", domain.ErrInvalidBundle, component.ProductID)
+	}
+	if decimals, _ := domain.UnitDecimals(product.Unit); component.Quantity.Decimals() > decimals {
+		return fmt.Errorf("%w: quantity of product %d has too many decimals for unit %s", domain.ErrInvalidBundle, component.ProductID, product.Unit)
+	}
+
+	nested, err := uc.bundleRepo.GetComponents(ctx, component.ProductID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get bundle components from repository")
+		return fmt.Errorf("failed to save bundle: %w", err)
+	}
+	if len(nested) > 0 {
+		return fmt.Errorf("%w: product %d is itself a bundle", domain.ErrInvalidBundle, component.ProductID)
+	}
+	return nil
+}
+
+// load reads the product's components and derives how many whole bundles
+// their stock, including what may be backordered, makes. A product without
+// components is not a bundle.
+func (uc *BundleUseCase) load(ctx context.Context, product *domain.Product) (*domain.Bundle, error) {
+	components, err := uc.bundleRepo.GetComponents(ctx, product.ID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get bundle components from repository")
+		return nil, fmt.Errorf("failed to get bundle: %w", err)
+	}
+	if len(components) == 0 {
+		return nil, domain.ErrBundleNotFound
+	}
+
+	bundle := &domain.Bundle{Product: product, Components: components}
+	for i := range bundle.Components {
+		component := &bundle.Components[i]
+		sellable, err := component.Stock.Add(component.BackorderLimit)
+		if err != nil {
+			sellable = component.Stock
+		}
+		component.Available = sellable.Fits(component.Quantity)
+		if i == 0 || component.Available < bundle.Available {
+			bundle.Available = component.Available
+		}
+	}
+	return bundle, nil
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockBundleRepository struct {
+	mock.Mock
+}
+
+func (m *MockBundleRepository) GetComponents(ctx context.Context, bundleID int64) ([]domain.BundleComponent, error) {
+	args := m.Called(ctx, bundleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.BundleComponent), args.Error(1)
+}
+
+func (m *MockBundleRepository) IsComponent(ctx context.Context, productID int64) (bool, error) {
+	args := m.Called(ctx, productID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBundleRepository) SetComponents(ctx context.Context, bundleID int64, components []domain.BundleComponent) error {
+	args := m.Called(ctx, bundleID, components)
+	return args.Error(0)
+}
+
+func (m *MockBundleRepository) DeleteComponents(ctx context.Context, bundleID int64) error {
+	args := m.Called(ctx, bundleID)
+	return args.Error(0)
+}
+
+func (m *MockBundleRepository) DecrementStock(ctx context.Context, decrements []domain.StockDecrement) ([]*domain.Product, error) {
+	args := m.Called(ctx, decrements)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func TestBundleUseCase_GetBundle_DerivesAvailability(t *testing.T) {
+	products := &MockProductRepository{}
+	products.On("GetByID", mock.Anything, int64(50)).Return(&domain.Product{ID: 50, StoreID: 7, Amount: quantity.New(100)}, nil)
+	bundles := &MockBundleRepository{}
+	bundles.On("GetComponents", mock.Anything, int64(50)).Return([]domain.BundleComponent{
+		{ProductID: 42, Quantity: quantity.New(2), Stock: quantity.New(9)},
+		{ProductID: 43, Quantity: quantity.MustParse("0.25"), Unit: domain.UnitKilogram, Stock: quantity.MustParse("1.1")},
+	}, nil)
+
+	bundle, err := NewBundleUseCase(bundles, products, nil, clock.Real()).GetBundle(context.Background(), 50)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4), bundle.Components[0].Available)
+	assert.Equal(t, int64(4), bundle.Components[1].Available)
+	assert.Equal(t, int64(4), bundle.Available, "the bundle's own amount is ignored")
+}
+
+func TestBundleUseCase_GetBundle_CountsBackorders(t *testing.T) {
+	products := &MockProductRepository{}
+	products.On("GetByID", mock.Anything, int64(50)).Return(&domain.Product{ID: 50, StoreID: 7}, nil)
+	bundles := &MockBundleRepository{}
+	bundles.On("GetComponents", mock.Anything, int64(50)).Return([]domain.BundleComponent{
+		{ProductID: 42, Quantity: quantity.New(2), Stock: quantity.New(-1), BackorderLimit: quantity.New(10)},
+		{ProductID: 43, Quantity: quantity.New(1), Stock: quantity.New(6)},
+	}, nil)
+
+	bundle, err := NewBundleUseCase(bundles, products, nil, clock.Real()).GetBundle(context.Background(), 50)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4), bundle.Components[0].Available, "nine more may be backordered")
+	assert.Equal(t, int64(6), bundle.Components[1].Available)
+	assert.Equal(t, int64(4), bundle.Available)
+}
+
+func TestBundleUseCase_GetBundle_NotABundle(t *testing.T) {
+	products := &MockProductRepository{}
+	products.On("GetByID", mock.Anything, int64(42)).Return(&domain.Product{ID: 42, StoreID: 7}, nil)
+	bundles := &MockBundleRepository{}
+	bundles.On("GetComponents", mock.Anything, int64(42)).Return([]domain.BundleComponent{}, nil)
+
+	_, err := NewBundleUseCase(bundles, products, nil, clock.Real()).GetBundle(context.Background(), 42)
+	assert.ErrorIs(t, err, domain.ErrBundleNotFound)
+}
+
+func TestBundleUseCase_SaveBundle(t *testing.T) {
+	bundleProduct := &domain.Product{ID: 50, StoreID: 7}
+	catalog := map[int64]*domain.Product{
+		42: {ID: 42, StoreID: 7, Unit: domain.UnitPiece},
+		43: {ID: 43, StoreID: 7, Unit: domain.UnitKilogram},
+		44: {ID: 44, StoreID: 8, Unit: domain.UnitPiece},
+		45: {ID: 45, StoreID: 7, Unit: domain.UnitPiece},
+	}
+
+	tests := []struct {
+		name       string
+		components []domain.BundleComponent
+		wantErr    bool
+	}{
+		{
+			name: "pieces and weight",
+			components: []domain.BundleComponent{
+				{ProductID: 42, Quantity: quantity.New(2)},
+				{ProductID: 43, Quantity: quantity.MustParse("0.5")},
+			},
+		},
+		{name: "empty", wantErr: true},
+		{name: "contains itself", components: []domain.BundleComponent{{ProductID: 50, Quantity: quantity.New(1)}}, wantErr: true},
+		{
+			name: "listed twice",
+			components: []domain.BundleComponent{
+				{ProductID: 42, Quantity: quantity.New(1)},
+				{ProductID: 42, Quantity: quantity.New(1)},
+			},
+			wantErr: true,
+		},
+		{name: "zero quantity", components: []domain.BundleComponent{{ProductID: 42}}, wantErr: true},
+		{name: "fractional piece", components: []domain.BundleComponent{{ProductID: 42, Quantity: quantity.MustParse("0.5")}}, wantErr: true},
+		{name: "other store", components: []domain.BundleComponent{{ProductID: 44, Quantity: quantity.New(1)}}, wantErr: true},
+		{name: "nested bundle", components: []domain.BundleComponent{{ProductID: 45, Quantity: quantity.New(1)}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products := &MockProductRepository{}
+			products.On("GetByID", mock.Anything, int64(50)).Return(bundleProduct, nil).Maybe()
+			for id, product := range catalog {
+				products.On("GetByID", mock.Anything, id).Return(product, nil).Maybe()
+			}
+			bundles := &MockBundleRepository{}
+			bundles.On("IsComponent", mock.Anything, int64(50)).Return(false, nil).Maybe()
+			bundles.On("GetComponents", mock.Anything, int64(50)).Return(tt.components, nil).Maybe()
+			bundles.On("GetComponents", mock.Anything, int64(45)).Return([]domain.BundleComponent{{ProductID: 42, Quantity: quantity.New(1)}}, nil).Maybe()
+			bundles.On("GetComponents", mock.Anything, mock.Anything).Return([]domain.BundleComponent{}, nil).Maybe()
+			if !tt.wantErr {
+				bundles.On("SetComponents", mock.Anything, int64(50), tt.components).Return(nil)
+			}
+
+			_, err := NewBundleUseCase(bundles, products, nil, clock.Real()).SaveBundle(context.Background(), 50, tt.components)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidBundle)
+				bundles.AssertNotCalled(t, "SetComponents", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				bundles.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestBundleUseCase_SaveBundle_RejectsComponentAsBundle(t *testing.T) {
+	products := &MockProductRepository{}
+	products.On("GetByID", mock.Anything, int64(42)).Return(&domain.Product{ID: 42, StoreID: 7}, nil)
+	bundles := &MockBundleRepository{}
+	bundles.On("IsComponent", mock.Anything, int64(42)).Return(true, nil)
+
+	_, err := NewBundleUseCase(bundles, products, nil, clock.Real()).SaveBundle(context.Background(), 42,
+		[]domain.BundleComponent{{ProductID: 43, Quantity: quantity.New(1)}})
+	assert.ErrorIs(t, err, domain.ErrInvalidBundle)
+}
+
+func TestBundleUseCase_SellBundle(t *testing.T) {
+	products := &MockProductRepository{}
+	products.On("GetByID", mock.Anything, int64(50)).Return(&domain.Product{ID: 50, StoreID: 7}, nil)
+	bundles := &MockBundleRepository{}
+	bundles.On("GetComponents", mock.Anything, int64(50)).Return([]domain.BundleComponent{
+		{ProductID: 42, Quantity: quantity.New(2), Unit: domain.UnitPiece, Stock: quantity.New(9)},
+		{ProductID: 43, Quantity: quantity.MustParse("0.25"), Unit: domain.UnitKilogram, Stock: quantity.New(2)},
+	}, nil)
+	bundles.On("DecrementStock", mock.Anything, []domain.StockDecrement{
+		{ProductID: 42, Amount: quantity.New(6)},
+		{ProductID: 43, Amount: quantity.MustParse("0.75")},
+	}).Return([]*domain.Product{
+		{ID: 42, StoreID: 7, Amount: quantity.New(3), Unit: domain.UnitPiece},
+		{ID: 43, StoreID: 7, Amount: quantity.MustParse("1.25"), Unit: domain.UnitKilogram},
+	}, nil)
+
+	events := &recordingPublisher{}
+	uc := NewBundleUseCase(bundles, products, events, clock.Real())
+
+	_, err := uc.SellBundle(context.Background(), 50, 3)
+	require.NoError(t, err)
+	require.Len(t, events.events, 2)
+	assert.Equal(t, domain.StockEventChanged, events.events[1].Type)
+	assert.Equal(t, &domain.StockChange{PreviousAmount: quantity.New(2), Amount: quantity.MustParse("1.25"), Unit: domain.UnitKilogram}, events.events[1].Stock)
+
+	_, err = uc.SellBundle(context.Background(), 50, 5)
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock, "only 4 bundles are available")
+	bundles.AssertNumberOfCalls(t, "DecrementStock", 1)
+}
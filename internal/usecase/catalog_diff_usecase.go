@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CatalogDiffUseCase compares the catalog between two points in time from
+// the product revisions, for reconciling external systems after an
+// incident.
+type CatalogDiffUseCase struct {
+	revisionRepo ProductRevisionRepository
+	clock        clock.Clock
+}
+
+func NewCatalogDiffUseCase(revisionRepo ProductRevisionRepository, clk clock.Clock) *CatalogDiffUseCase {
+	return &CatalogDiffUseCase{
+		revisionRepo: revisionRepo,
+		clock:        clk,
+	}
+}
+
+// GetDiff lists the products created, deleted and changed between from and
+// to. A zero to means now. Both are compared in UTC, like the timestamps the
+// database writes.
+func (uc *CatalogDiffUseCase) GetDiff(ctx context.Context, from, to time.Time) (*domain.CatalogDiff, error) {
+	if to.IsZero() {
+		to = uc.clock.Now()
+	}
+	from, to = from.UTC(), to.UTC()
+	if from.IsZero() {
+		return nil, fmt.Errorf("%w: from is required", domain.ErrInvalidDiffRange)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", domain.ErrInvalidDiffRange)
+	}
+
+	changes, err := uc.revisionRepo.GetChanges(ctx, from, to)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get product revisions from repository")
+		return nil, err
+	}
+
+	diff := &domain.CatalogDiff{From: from, To: to}
+	for _, change := range changes {
+		switch {
+		case change.Before == nil && change.After == nil:
+			// Created and deleted within the period.
+		case change.Before == nil:
+			diff.Created = append(diff.Created, change.After)
+		case change.After == nil:
+			diff.Deleted = append(diff.Deleted, change.Before)
+		default:
+			if fields := domain.ChangedFields(change.Before, change.After); len(fields) > 0 {
+				diff.Changed = append(diff.Changed, domain.ChangedProduct{Product: change.After, Fields: fields})
+			}
+		}
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":  "catalog_diff",
+		"from":    from,
+		"to":      to,
+		"created": len(diff.Created),
+		"deleted": len(diff.Deleted),
+		"changed": len(diff.Changed),
+	}).Info("Built catalog diff")
+
+	return diff, nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockProductRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductRevisionRepository) GetChanges(ctx context.Context, from, to time.Time) ([]domain.ProductChange, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.ProductChange), args.Error(1)
+}
+
+func TestCatalogDiffUseCase_GetDiff(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	from := now.Add(-time.Hour)
+
+	kept := &domain.Product{ID: 1, Name: "Kept", Price: 1}
+	repriced := *kept
+	repriced.Price = 2
+	unchanged := &domain.Product{ID: 2, Name: "Unchanged", Price: 1}
+	removed := &domain.Product{ID: 3, Name: "Removed"}
+	added := &domain.Product{ID: 4, Name: "Added"}
+
+	repo := new(MockProductRevisionRepository)
+	repo.On("GetChanges", mock.Anything, from, now).Return([]domain.ProductChange{
+		{ProductID: 1, Before: kept, After: &repriced},
+		{ProductID: 2, Before: unchanged, After: unchanged},
+		{ProductID: 3, Before: removed},
+		{ProductID: 4, After: added},
+		{ProductID: 5},
+	}, nil)
+	uc := NewCatalogDiffUseCase(repo, clock.NewFake(now))
+
+	diff, err := uc.GetDiff(context.Background(), from, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, now, diff.To)
+	assert.Equal(t, []*domain.Product{added}, diff.Created)
+	assert.Equal(t, []*domain.Product{removed}, diff.Deleted)
+	assert.Equal(t, []domain.ChangedProduct{{Product: &repriced, Fields: []string{"price"}}}, diff.Changed)
+	repo.AssertExpectations(t)
+}
+
+func TestCatalogDiffUseCase_GetDiff_InvalidRange(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	uc := NewCatalogDiffUseCase(new(MockProductRevisionRepository), clock.NewFake(now))
+
+	_, err := uc.GetDiff(context.Background(), time.Time{}, now)
+	assert.ErrorIs(t, err, domain.ErrInvalidDiffRange)
+
+	_, err = uc.GetDiff(context.Background(), now, now.Add(-time.Hour))
+	assert.ErrorIs(t, err, domain.ErrInvalidDiffRange)
+}
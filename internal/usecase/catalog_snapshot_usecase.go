@@ -0,0 +1,390 @@
+package usecase
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/idgen"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+const snapshotFormatVersion = 1
+
+// snapshotRecord is one line of a snapshot object: a header first, then
+// one product per line and a trailer with the product count, so an object
+// cut short is detected.
+type snapshotRecord struct {
+	Version int             `json:"version,omitempty"`
+	StoreID int64           `json:"store_id,omitempty"`
+	Product *domain.Product `json:"product,omitempty"`
+	Count   *int            `json:"count,omitempty"`
+}
+
+// CatalogSnapshotUseCase takes snapshots of stores' catalogs into storage
+// and restores them. Restores run in the background, one per store at a
+// time, and publish an event for every product they write.
+type CatalogSnapshotUseCase struct {
+	repo           CatalogSnapshotRepository
+	storage        SnapshotStorage
+	prefix         string
+	events         EventPublisher
+	restoreTimeout time.Duration
+	clock          clock.Clock
+
+	wg sync.WaitGroup
+}
+
+// NewCatalogSnapshotUseCase keeps snapshot objects in storage under
+// prefix. events may be nil.
+func NewCatalogSnapshotUseCase(repo CatalogSnapshotRepository, storage SnapshotStorage, prefix string, events EventPublisher, restoreTimeout time.Duration, clk clock.Clock) *CatalogSnapshotUseCase {
+	return &CatalogSnapshotUseCase{
+		repo:           repo,
+		storage:        storage,
+		prefix:         prefix,
+		events:         events,
+		restoreTimeout: restoreTimeout,
+		clock:          clk,
+	}
+}
+
+// CreateSnapshot streams the store's products from a consistent read
+// through gzip into storage, then records the snapshot.
+func (uc *CatalogSnapshotUseCase) CreateSnapshot(ctx context.Context, storeID int64) (*domain.CatalogSnapshot, error) {
+	logger := logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "create_catalog_snapshot",
+		"store_id": storeID,
+	})
+
+	if storeID <= 0 {
+		return nil, domain.ErrStoreNotFound
+	}
+
+	start := uc.clock.Now()
+	id, err := idgen.UUIDv7At(start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate snapshot key: %w", err)
+	}
+	key := fmt.Sprintf("%sstore-%d/%s.jsonl.gz", uc.prefix, storeID, id)
+
+	pr, pw := io.Pipe()
+	type result struct {
+		takenAt time.Time
+		count   int
+		err     error
+	}
+	captured := make(chan result, 1)
+	go func() {
+		takenAt, count, err := uc.write(ctx, storeID, pw)
+		pw.CloseWithError(err)
+		captured <- result{takenAt, count, err}
+	}()
+
+	putErr := uc.storage.Put(ctx, key, pr)
+	// Unblock the capture if the upload stopped reading early.
+	pr.CloseWithError(errors.New("upload stopped"))
+	res := <-captured
+
+	if res.err != nil {
+		if !errors.Is(res.err, domain.ErrStoreNotFound) {
+			logger.WithError(res.err).Error("Failed to capture catalog")
+		}
+		return nil, res.err
+	}
+	if putErr != nil {
+		logger.WithError(putErr).Error("Failed to upload catalog snapshot")
+		return nil, fmt.Errorf("failed to upload snapshot: %w", putErr)
+	}
+
+	snapshot, err := uc.repo.CreateSnapshot(ctx, &domain.CatalogSnapshot{
+		StoreID:   storeID,
+		ObjectKey: key,
+		Products:  res.count,
+		TakenAt:   res.takenAt,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to record catalog snapshot")
+		return nil, err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"snapshot_id": snapshot.ID,
+		"key":         key,
+		"products":    res.count,
+		"duration":    uc.clock.Now().Sub(start),
+	}).Info("Catalog snapshot taken")
+	return snapshot, nil
+}
+
+func (uc *CatalogSnapshotUseCase) write(ctx context.Context, storeID int64, w io.Writer) (time.Time, int, error) {
+	gz := gzip.NewWriter(w)
+	buffered := bufio.NewWriter(gz)
+	enc := json.NewEncoder(buffered)
+
+	if err := enc.Encode(snapshotRecord{Version: snapshotFormatVersion, StoreID: storeID}); err != nil {
+		return time.Time{}, 0, err
+	}
+
+	count := 0
+	takenAt, err := uc.repo.Capture(ctx, storeID, func(product *domain.Product) error {
+		count++
+		return enc.Encode(snapshotRecord{Product: product})
+	})
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	if err := enc.Encode(snapshotRecord{Count: &count}); err != nil {
+		return time.Time{}, 0, err
+	}
+	if err := buffered.Flush(); err != nil {
+		return time.Time{}, 0, err
+	}
+	return takenAt, count, gz.Close()
+}
+
+// load reads a snapshot's products back from storage.
+func (uc *CatalogSnapshotUseCase) load(ctx context.Context, snapshot *domain.CatalogSnapshot) ([]*domain.Product, error) {
+	body, err := uc.storage.Get(ctx, snapshot.ObjectKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download snapshot: %w", err)
+	}
+	defer body.Close()
+
+	return readSnapshot(body, snapshot.StoreID)
+}
+
+func readSnapshot(r io.Reader, storeID int64) ([]*domain.Product, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrSnapshotCorrupt, err.Error())
+	}
+	defer gz.Close()
+
+	dec := json.NewDecoder(bufio.NewReader(gz))
+
+	var header snapshotRecord
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrSnapshotCorrupt, err.Error())
+	}
+	if header.Version != snapshotFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", domain.ErrSnapshotCorrupt, header.Version)
+	}
+	if header.StoreID != storeID {
+		return nil, fmt.Errorf("%w: snapshot is of store %d", domain.ErrSnapshotCorrupt, header.StoreID)
+	}
+
+	var products []*domain.Product
+	for {
+		var rec snapshotRecord
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("%w: snapshot ended without its trailer", domain.ErrSnapshotCorrupt)
+			}
+			return nil, fmt.Errorf("%w: %s", domain.ErrSnapshotCorrupt, err.Error())
+		}
+
+		if rec.Count != nil {
+			if *rec.Count != len(products) {
+				return nil, fmt.Errorf("%w: read %d products, snapshot recorded %d", domain.ErrSnapshotCorrupt, len(products), *rec.Count)
+			}
+			// Reading to the end checks the gzip checksum, which a cut
+			// after the trailer would otherwise skip.
+			if _, err := io.Copy(io.Discard, gz); err != nil {
+				return nil, fmt.Errorf("%w: %s", domain.ErrSnapshotCorrupt, err.Error())
+			}
+			return products, nil
+		}
+		if rec.Product == nil {
+			return nil, fmt.Errorf("%w: unexpected record", domain.ErrSnapshotCorrupt)
+		}
+		products = append(products, rec.Product)
+	}
+}
+
+func (uc *CatalogSnapshotUseCase) GetSnapshot(ctx context.Context, storeID, id int64) (*domain.CatalogSnapshot, error) {
+	if storeID <= 0 || id <= 0 {
+		return nil, domain.ErrSnapshotNotFound
+	}
+	return uc.repo.GetSnapshot(ctx, storeID, id)
+}
+
+func (uc *CatalogSnapshotUseCase) GetSnapshots(ctx context.Context, storeID int64, limit, offset int) ([]*domain.CatalogSnapshot, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	snapshots, err := uc.repo.GetSnapshots(ctx, storeID, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get catalog snapshots from repository")
+		return nil, fmt.Errorf("failed to get catalog snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// PreviewRestore returns what restoring the snapshot would do now, as a
+// diff from the current catalog to the snapshot's.
+func (uc *CatalogSnapshotUseCase) PreviewRestore(ctx context.Context, storeID, snapshotID int64) (*domain.CatalogDiff, error) {
+	snapshot, err := uc.GetSnapshot(ctx, storeID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := uc.load(ctx, snapshot)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithField("snapshot_id", snapshot.ID).Error("Failed to load catalog snapshot")
+		return nil, err
+	}
+
+	diff, err := uc.repo.PlanRestore(ctx, storeID, products)
+	if err != nil {
+		return nil, err
+	}
+	diff.From = uc.clock.Now()
+	diff.To = snapshot.TakenAt
+	return diff, nil
+}
+
+// StartRestore records a pending restore and runs it in the background.
+// A restore left unfinished for twice the restore timeout was interrupted,
+// e.g. by a restart, and no longer blocks a new one.
+func (uc *CatalogSnapshotUseCase) StartRestore(ctx context.Context, storeID, snapshotID int64) (*domain.CatalogRestore, error) {
+	snapshot, err := uc.GetSnapshot(ctx, storeID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.clock.Now()
+	restore, err := uc.repo.CreateRestore(ctx, &domain.CatalogRestore{
+		StoreID:    storeID,
+		SnapshotID: snapshot.ID,
+		Status:     domain.CatalogRestoreStatusPending,
+		CreatedAt:  now,
+	}, now.Add(-2*uc.restoreTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":      "start_catalog_restore",
+		"store_id":    storeID,
+		"snapshot_id": snapshot.ID,
+		"restore_id":  restore.ID,
+	}).Info("Catalog restore started")
+
+	uc.wg.Add(1)
+	go func() {
+		defer uc.wg.Done()
+		uc.runRestore(ctx, snapshot, *restore)
+	}()
+
+	return restore, nil
+}
+
+// runRestore outlives the request that started it, but keeps the values
+// of its ctx, such as the request ID its log lines carry.
+func (uc *CatalogSnapshotUseCase) runRestore(ctx context.Context, snapshot *domain.CatalogSnapshot, restore domain.CatalogRestore) {
+	logger := logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":      "catalog_restore",
+		"store_id":    restore.StoreID,
+		"snapshot_id": snapshot.ID,
+		"restore_id":  restore.ID,
+	})
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), uc.restoreTimeout)
+	defer cancel()
+
+	restore.Status = domain.CatalogRestoreStatusRunning
+	restore.StartedAt = sql.NullTime{Time: uc.clock.Now(), Valid: true}
+	if err := uc.repo.UpdateRestore(ctx, &restore); err != nil {
+		logger.WithError(err).Error("Failed to mark catalog restore running")
+	}
+
+	diff, err := uc.restore(ctx, snapshot)
+	if err != nil {
+		logger.WithError(err).Error("Catalog restore failed")
+		restore.Status = domain.CatalogRestoreStatusFailed
+		restore.Error = sql.NullString{String: err.Error(), Valid: true}
+	} else {
+		restore.Status = domain.CatalogRestoreStatusSucceeded
+		restore.Created = len(diff.Created)
+		restore.Updated = len(diff.Changed)
+		restore.Deleted = len(diff.Deleted)
+		uc.publish(ctx, diff)
+	}
+	restore.FinishedAt = sql.NullTime{Time: uc.clock.Now(), Valid: true}
+
+	// The restore's own deadline may have passed; its outcome is still
+	// recorded.
+	finishCtx, finishCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer finishCancel()
+	if err := uc.repo.UpdateRestore(finishCtx, &restore); err != nil {
+		logger.WithError(err).Error("Failed to record catalog restore outcome")
+		return
+	}
+
+	logger.WithFields(logrus.Fields{
+		"status":  restore.Status,
+		"created": restore.Created,
+		"updated": restore.Updated,
+		"deleted": restore.Deleted,
+	}).Info("Catalog restore finished")
+}
+
+func (uc *CatalogSnapshotUseCase) restore(ctx context.Context, snapshot *domain.CatalogSnapshot) (*domain.CatalogDiff, error) {
+	products, err := uc.load(ctx, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return uc.repo.RestoreCatalog(ctx, snapshot.StoreID, products)
+}
+
+func (uc *CatalogSnapshotUseCase) publish(ctx context.Context, diff *domain.CatalogDiff) {
+	emit := func(eventType string, product *domain.Product) {
+		emitEvent(ctx, uc.events, uc.clock, domain.ProductEvent{
+			Type:      eventType,
+			StoreID:   product.StoreID,
+			ProductID: product.ID,
+			Product:   domain.NewProductEventData(product),
+		})
+	}
+
+	for _, product := range diff.Created {
+		emit(domain.ProductEventCreated, product)
+	}
+	for _, changed := range diff.Changed {
+		emit(domain.ProductEventUpdated, changed.Product)
+	}
+	for _, product := range diff.Deleted {
+		emit(domain.ProductEventDeleted, product)
+	}
+}
+
+// Wait blocks until in-flight restores finish.
+func (uc *CatalogSnapshotUseCase) Wait() {
+	uc.wg.Wait()
+}
+
+func (uc *CatalogSnapshotUseCase) GetRestore(ctx context.Context, storeID, id int64) (*domain.CatalogRestore, error) {
+	if storeID <= 0 || id <= 0 {
+		return nil, domain.ErrRestoreNotFound
+	}
+	return uc.repo.GetRestore(ctx, storeID, id)
+}
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCatalogSnapshotRepository struct {
+	mock.Mock
+}
+
+func (m *MockCatalogSnapshotRepository) Capture(ctx context.Context, storeID int64, fn func(*domain.Product) error) (time.Time, error) {
+	args := m.Called(ctx, storeID, fn)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotRepository) CreateSnapshot(ctx context.Context, snapshot *domain.CatalogSnapshot) (*domain.CatalogSnapshot, error) {
+	args := m.Called(ctx, snapshot)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogSnapshot), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotRepository) GetSnapshot(ctx context.Context, storeID, id int64) (*domain.CatalogSnapshot, error) {
+	args := m.Called(ctx, storeID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogSnapshot), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotRepository) GetSnapshots(ctx context.Context, storeID int64, limit, offset int) ([]*domain.CatalogSnapshot, error) {
+	args := m.Called(ctx, storeID, limit, offset)
+	return args.Get(0).([]*domain.CatalogSnapshot), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotRepository) PlanRestore(ctx context.Context, storeID int64, snapshot []*domain.Product) (*domain.CatalogDiff, error) {
+	args := m.Called(ctx, storeID, snapshot)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogDiff), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotRepository) RestoreCatalog(ctx context.Context, storeID int64, snapshot []*domain.Product) (*domain.CatalogDiff, error) {
+	args := m.Called(ctx, storeID, snapshot)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogDiff), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotRepository) CreateRestore(ctx context.Context, restore *domain.CatalogRestore, staleBefore time.Time) (*domain.CatalogRestore, error) {
+	args := m.Called(ctx, restore, staleBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogRestore), args.Error(1)
+}
+
+func (m *MockCatalogSnapshotRepository) UpdateRestore(ctx context.Context, restore *domain.CatalogRestore) error {
+	args := m.Called(ctx, restore)
+	return args.Error(0)
+}
+
+func (m *MockCatalogSnapshotRepository) GetRestore(ctx context.Context, storeID, id int64) (*domain.CatalogRestore, error) {
+	args := m.Called(ctx, storeID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogRestore), args.Error(1)
+}
+
+type memorySnapshotStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *memorySnapshotStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.objects == nil {
+		s.objects = make(map[string][]byte)
+	}
+	s.objects[key] = data
+	return nil
+}
+
+func (s *memorySnapshotStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return io.NopCloser(bytes.NewReader(s.objects[key])), nil
+}
+
+func snapshotProducts() []*domain.Product {
+	releaseDate := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	return []*domain.Product{
+		{ID: 1, StoreID: 3, Name: "Flour", Amount: quantity.New(2), Unit: domain.UnitKilogram, Price: 3.5, Status: domain.ProductStatusActive},
+		{ID: 2, StoreID: 3, Name: "Stollen", Amount: quantity.New(0), Unit: domain.UnitPiece, Price: 12, Status: domain.ProductStatusActive, PreorderReleaseDate: &releaseDate},
+	}
+}
+
+func TestCatalogSnapshotUseCase_SnapshotRoundTrip(t *testing.T) {
+	repo := &MockCatalogSnapshotRepository{}
+	storage := &memorySnapshotStorage{}
+	takenAt := time.Date(2026, 1, 10, 8, 0, 0, 0, time.UTC)
+
+	repo.On("Capture", mock.Anything, int64(3), mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*domain.Product) error)
+		for _, p := range snapshotProducts() {
+			require.NoError(t, fn(p))
+		}
+	}).Return(takenAt, nil)
+	var key string
+	repo.On("CreateSnapshot", mock.Anything, mock.MatchedBy(func(s *domain.CatalogSnapshot) bool {
+		key = s.ObjectKey
+		return s.StoreID == 3 && s.Products == 2 && s.TakenAt.Equal(takenAt)
+	})).Return(&domain.CatalogSnapshot{ID: 4, StoreID: 3, Products: 2, TakenAt: takenAt}, nil)
+
+	uc := NewCatalogSnapshotUseCase(repo, storage, "snapshots/", nil, time.Minute, clock.Real())
+	snapshot, err := uc.CreateSnapshot(context.Background(), 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), snapshot.ID)
+	assert.Regexp(t, `^snapshots/store-3/[0-9a-f-]{36}\.jsonl\.gz$`, key)
+
+	snapshot.ObjectKey = key
+	repo.On("GetSnapshot", mock.Anything, int64(3), int64(4)).Return(snapshot, nil)
+	repo.On("PlanRestore", mock.Anything, int64(3), snapshotProducts()).Return(&domain.CatalogDiff{}, nil)
+
+	diff, err := uc.PreviewRestore(context.Background(), 3, 4)
+
+	require.NoError(t, err)
+	assert.Equal(t, takenAt, diff.To)
+	repo.AssertExpectations(t)
+}
+
+func TestReadSnapshot_Truncated(t *testing.T) {
+	repo := &MockCatalogSnapshotRepository{}
+	repo.On("Capture", mock.Anything, int64(3), mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*domain.Product) error)
+		for _, p := range snapshotProducts() {
+			require.NoError(t, fn(p))
+		}
+	}).Return(time.Now(), nil)
+
+	uc := NewCatalogSnapshotUseCase(repo, nil, "", nil, time.Minute, clock.Real())
+	var buf bytes.Buffer
+	_, _, err := uc.write(context.Background(), 3, &buf)
+	require.NoError(t, err)
+
+	products, err := readSnapshot(bytes.NewReader(buf.Bytes()), 3)
+	require.NoError(t, err)
+	assert.Len(t, products, 2)
+
+	_, err = readSnapshot(bytes.NewReader(buf.Bytes()), 5)
+	assert.ErrorIs(t, err, domain.ErrSnapshotCorrupt, "another store's snapshot")
+
+	_, err = readSnapshot(bytes.NewReader(buf.Bytes()[:buf.Len()-10]), 3)
+	assert.ErrorIs(t, err, domain.ErrSnapshotCorrupt)
+}
+
+func TestCatalogSnapshotUseCase_StartRestore(t *testing.T) {
+	snapshot := &domain.CatalogSnapshot{ID: 4, StoreID: 3, ObjectKey: "snapshots/store-3/a.jsonl.gz"}
+
+	setup := func(t *testing.T) (*MockCatalogSnapshotRepository, *CatalogSnapshotUseCase, *recordingPublisher) {
+		repo := &MockCatalogSnapshotRepository{}
+		storage := &memorySnapshotStorage{}
+		events := &recordingPublisher{}
+		uc := NewCatalogSnapshotUseCase(repo, storage, "", events, time.Minute, clock.Real())
+
+		repo.On("Capture", mock.Anything, int64(3), mock.Anything).Run(func(args mock.Arguments) {
+			fn := args.Get(2).(func(*domain.Product) error)
+			for _, p := range snapshotProducts() {
+				require.NoError(t, fn(p))
+			}
+		}).Return(time.Now(), nil)
+		var buf bytes.Buffer
+		_, _, err := uc.write(context.Background(), 3, &buf)
+		require.NoError(t, err)
+		require.NoError(t, storage.Put(context.Background(), snapshot.ObjectKey, &buf))
+
+		repo.On("GetSnapshot", mock.Anything, int64(3), int64(4)).Return(snapshot, nil)
+		return repo, uc, events
+	}
+
+	t.Run("restores in the background", func(t *testing.T) {
+		repo, uc, events := setup(t)
+		repo.On("CreateRestore", mock.Anything, mock.MatchedBy(func(r *domain.CatalogRestore) bool {
+			return r.StoreID == 3 && r.SnapshotID == 4 && r.Status == domain.CatalogRestoreStatusPending
+		}), mock.Anything).Return(&domain.CatalogRestore{ID: 8, StoreID: 3, SnapshotID: 4, Status: domain.CatalogRestoreStatusPending}, nil)
+		var statuses []domain.CatalogRestoreStatus
+		var finished domain.CatalogRestore
+		repo.On("UpdateRestore", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			restore := args.Get(1).(*domain.CatalogRestore)
+			statuses = append(statuses, restore.Status)
+			finished = *restore
+		}).Return(nil)
+		products := snapshotProducts()
+		repo.On("RestoreCatalog", mock.Anything, int64(3), products).Return(&domain.CatalogDiff{
+			Created: []*domain.Product{products[1]},
+			Changed: []domain.ChangedProduct{{Product: products[0], Fields: []string{"amount"}}},
+			Deleted: []*domain.Product{{ID: 9, StoreID: 3, Name: "Rye"}},
+		}, nil)
+
+		restore, err := uc.StartRestore(context.Background(), 3, 4)
+		require.NoError(t, err)
+		assert.Equal(t, domain.CatalogRestoreStatusPending, restore.Status)
+		uc.Wait()
+
+		assert.Equal(t, []domain.CatalogRestoreStatus{domain.CatalogRestoreStatusRunning, domain.CatalogRestoreStatusSucceeded}, statuses)
+		assert.Equal(t, 1, finished.Created)
+		assert.Equal(t, 1, finished.Updated)
+		assert.Equal(t, 1, finished.Deleted)
+		assert.True(t, finished.FinishedAt.Valid)
+		require.Len(t, events.events, 3)
+		assert.Equal(t, domain.ProductEventCreated, events.events[0].Type)
+		assert.Equal(t, domain.ProductEventUpdated, events.events[1].Type)
+		assert.Equal(t, domain.ProductEventDeleted, events.events[2].Type)
+		assert.Equal(t, int64(9), events.events[2].ProductID)
+	})
+
+	t.Run("failed restore is recorded", func(t *testing.T) {
+		repo, uc, events := setup(t)
+		repo.On("CreateRestore", mock.Anything, mock.Anything, mock.Anything).Return(&domain.CatalogRestore{ID: 8, StoreID: 3, SnapshotID: 4}, nil)
+		var finished domain.CatalogRestore
+		repo.On("UpdateRestore", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			finished = *args.Get(1).(*domain.CatalogRestore)
+		}).Return(nil)
+		repo.On("RestoreCatalog", mock.Anything, int64(3), mock.Anything).Return(nil, domain.ErrProductInBundle)
+
+		_, err := uc.StartRestore(context.Background(), 3, 4)
+		require.NoError(t, err)
+		uc.Wait()
+
+		assert.Equal(t, domain.CatalogRestoreStatusFailed, finished.Status)
+		assert.Equal(t, domain.ErrProductInBundle.Error(), finished.Error.String)
+		assert.Empty(t, events.events)
+	})
+
+	t.Run("one restore at a time", func(t *testing.T) {
+		repo, uc, _ := setup(t)
+		repo.On("CreateRestore", mock.Anything, mock.Anything, mock.Anything).Return(nil, domain.ErrRestoreInProgress)
+
+		_, err := uc.StartRestore(context.Background(), 3, 4)
+		assert.ErrorIs(t, err, domain.ErrRestoreInProgress)
+		repo.AssertNotCalled(t, "RestoreCatalog", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
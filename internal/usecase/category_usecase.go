@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// CategoryUseCase manages the category tree and the categories products
+// are in. Changing what a product is in, by putting it in categories or by
+// renaming, moving or deleting one of its categories, publishes an updated
+// event for the product like any other product change.
+type CategoryUseCase struct {
+	tx           database.TxManager
+	categoryRepo CategoryRepository
+	events       EventPublisher
+	clock        clock.Clock
+}
+
+// NewCategoryUseCase builds the category use case. tx runs each write in one
+// transaction with the events it publishes. events may be nil when nothing
+// subscribes to product changes.
+func NewCategoryUseCase(tx database.TxManager, categoryRepo CategoryRepository, events EventPublisher, clk clock.Clock) *CategoryUseCase {
+	return &CategoryUseCase{
+		tx:           tx,
+		categoryRepo: categoryRepo,
+		events:       events,
+		clock:        clk,
+	}
+}
+
+func (uc *CategoryUseCase) CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action": "create_category",
+		"name":   category.Name,
+	}).Info("Creating category")
+
+	if err := category.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Category validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidCategory, err)
+	}
+
+	created, err := uc.categoryRepo.Create(ctx, category)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to create category in repository")
+		return nil, fmt.Errorf("failed to create category: %w", err)
+	}
+
+	return created, nil
+}
+
+func (uc *CategoryUseCase) GetCategory(ctx context.Context, id int64) (*domain.Category, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid category ID", domain.ErrInvalidCategory)
+	}
+
+	category, err := uc.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get category from repository")
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (uc *CategoryUseCase) GetCategories(ctx context.Context, limit, offset int) ([]*domain.Category, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	categories, err := uc.categoryRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get categories from repository")
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+
+	return categories, nil
+}
+
+// UpdateCategory renames a category or moves it under another parent. A
+// category cannot be moved under itself or one of its subcategories.
+func (uc *CategoryUseCase) UpdateCategory(ctx context.Context, id int64, category *domain.Category) (*domain.Category, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid category ID", domain.ErrInvalidCategory)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":      "update_category",
+		"category_id": id,
+	}).Info("Updating category")
+
+	if err := category.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Category validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidCategory, err)
+	}
+
+	if category.ParentID.Valid && category.ParentID.Int64 == id {
+		return nil, domain.ErrCategoryCycle
+	}
+
+	var updated *domain.Category
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var (
+			products []*domain.Product
+			err      error
+		)
+		if updated, products, err = uc.categoryRepo.Update(ctx, id, category); err != nil {
+			return err
+		}
+		uc.publish(ctx, products...)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to update category in repository")
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// DeleteCategory removes a category without subcategories, taking its
+// products out of it.
+func (uc *CategoryUseCase) DeleteCategory(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return fmt.Errorf("%w: invalid category ID", domain.ErrInvalidCategory)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":      "delete_category",
+		"category_id": id,
+	}).Info("Deleting category")
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		products, err := uc.categoryRepo.Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+		uc.publish(ctx, products...)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to delete category from repository")
+		return err
+	}
+
+	return nil
+}
+
+func (uc *CategoryUseCase) GetProductCategories(ctx context.Context, productID int64) ([]*domain.Category, error) {
+	if productID <= 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", domain.ErrInvalidProduct)
+	}
+
+	categories, err := uc.categoryRepo.GetByProduct(ctx, productID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get product categories from repository")
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// SetProductCategories replaces the categories a product is in; an empty
+// list takes it out of every category.
+func (uc *CategoryUseCase) SetProductCategories(ctx context.Context, productID int64, categoryIDs []int64) ([]*domain.Category, error) {
+	if productID <= 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", domain.ErrInvalidProduct)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":     "set_product_categories",
+		"product_id": productID,
+		"categories": len(categoryIDs),
+	}).Info("Setting product categories")
+
+	ids := slices.Clone(categoryIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if err := domain.ValidateCategoryIDs(ids); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Category validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidCategory, err)
+	}
+
+	var categories []*domain.Category
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var (
+			product *domain.Product
+			err     error
+		)
+		if categories, product, err = uc.categoryRepo.SetForProduct(ctx, productID, ids); err != nil {
+			return err
+		}
+		uc.publish(ctx, product)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to set product categories in repository")
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// publish announces the products whose categories changed.
+func (uc *CategoryUseCase) publish(ctx context.Context, products ...*domain.Product) {
+	for _, product := range products {
+		emitEvent(ctx, uc.events, uc.clock, domain.ProductEvent{
+			Type:      domain.ProductEventUpdated,
+			StoreID:   product.StoreID,
+			ProductID: product.ID,
+			Product:   domain.NewProductEventData(product),
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Create(ctx context.Context, category *domain.Category) (*domain.Category, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetByID(ctx context.Context, id int64) (*domain.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Category, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, id int64, category *domain.Category) (*domain.Category, []*domain.Product, error) {
+	args := m.Called(ctx, id, category)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	products, _ := args.Get(1).([]*domain.Product)
+	return args.Get(0).(*domain.Category), products, args.Error(2)
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id int64) ([]*domain.Product, error) {
+	args := m.Called(ctx, id)
+	products, _ := args.Get(0).([]*domain.Product)
+	return products, args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetByProduct(ctx context.Context, productID int64) ([]*domain.Category, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) SetForProduct(ctx context.Context, productID int64, categoryIDs []int64) ([]*domain.Category, *domain.Product, error) {
+	args := m.Called(ctx, productID, categoryIDs)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]*domain.Category), args.Get(1).(*domain.Product), args.Error(2)
+}
+
+func TestCategoryUseCase_CreateCategory(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		category *domain.Category
+		mockFn   func(*MockCategoryRepository)
+		wantErr  error
+	}{
+		{
+			name:     "root category",
+			category: &domain.Category{Name: "Drinks"},
+			mockFn: func(m *MockCategoryRepository) {
+				m.On("Create", mock.Anything, mock.Anything).Return(&domain.Category{ID: 1, Name: "Drinks"}, nil)
+			},
+		},
+		{
+			name:     "unknown parent",
+			category: &domain.Category{Name: "Tea", ParentID: sql.NullInt64{Int64: 9, Valid: true}},
+			mockFn: func(m *MockCategoryRepository) {
+				m.On("Create", mock.Anything, mock.Anything).Return(nil, domain.ErrParentCategoryNotFound)
+			},
+			wantErr: domain.ErrParentCategoryNotFound,
+		},
+		{
+			name:     "blank name",
+			category: &domain.Category{Name: "  "},
+			mockFn:   func(m *MockCategoryRepository) {},
+			wantErr:  domain.ErrInvalidCategory,
+		},
+		{
+			name:     "name too long",
+			category: &domain.Category{Name: strings.Repeat("a", 101)},
+			mockFn:   func(m *MockCategoryRepository) {},
+			wantErr:  domain.ErrInvalidCategory,
+		},
+		{
+			name:     "invalid parent",
+			category: &domain.Category{Name: "Tea", ParentID: sql.NullInt64{Int64: -1, Valid: true}},
+			mockFn:   func(m *MockCategoryRepository) {},
+			wantErr:  domain.ErrInvalidCategory,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCategoryRepository)
+			tt.mockFn(repo)
+			uc := NewCategoryUseCase(database.NopTxManager{}, repo, nil, clock.Real())
+
+			created, err := uc.CreateCategory(ctx, tt.category)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, created)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), created.ID)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryUseCase_UpdateCategory_OwnParent(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	uc := NewCategoryUseCase(database.NopTxManager{}, repo, nil, clock.Real())
+
+	_, err := uc.UpdateCategory(context.Background(), 4, &domain.Category{Name: "Tea", ParentID: sql.NullInt64{Int64: 4, Valid: true}})
+	assert.ErrorIs(t, err, domain.ErrCategoryCycle)
+	repo.AssertExpectations(t)
+}
+
+func TestCategoryUseCase_GetCategories(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	repo.On("GetAll", mock.Anything, 100, 0).Return([]*domain.Category{}, nil)
+	uc := NewCategoryUseCase(database.NopTxManager{}, repo, nil, clock.Real())
+
+	_, err := uc.GetCategories(context.Background(), 1000, -5)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestCategoryUseCase_DeleteCategory(t *testing.T) {
+	repo := new(MockCategoryRepository)
+	repo.On("Delete", mock.Anything, int64(1)).Return(nil, domain.ErrCategoryHasChildren)
+	uc := NewCategoryUseCase(database.NopTxManager{}, repo, nil, clock.Real())
+
+	assert.ErrorIs(t, uc.DeleteCategory(context.Background(), 1), domain.ErrCategoryHasChildren)
+	assert.ErrorIs(t, uc.DeleteCategory(context.Background(), 0), domain.ErrInvalidCategory)
+	repo.AssertExpectations(t)
+}
+
+func TestCategoryUseCase_SetProductCategories(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		productID   int64
+		categoryIDs []int64
+		mockFn      func(*MockCategoryRepository)
+		wantErr     error
+	}{
+		{
+			name:        "duplicates removed",
+			productID:   7,
+			categoryIDs: []int64{3, 1, 3},
+			mockFn: func(m *MockCategoryRepository) {
+				m.On("SetForProduct", mock.Anything, int64(7), []int64{1, 3}).Return([]*domain.Category{{ID: 1}, {ID: 3}}, &domain.Product{ID: 7}, nil)
+			},
+		},
+		{
+			name:        "no categories",
+			productID:   7,
+			categoryIDs: []int64{},
+			mockFn: func(m *MockCategoryRepository) {
+				m.On("SetForProduct", mock.Anything, int64(7), []int64{}).Return([]*domain.Category{}, &domain.Product{ID: 7}, nil)
+			},
+		},
+		{
+			name:        "unknown category",
+			productID:   7,
+			categoryIDs: []int64{99},
+			mockFn: func(m *MockCategoryRepository) {
+				m.On("SetForProduct", mock.Anything, int64(7), []int64{99}).Return(nil, nil, domain.ErrCategoryNotFound)
+			},
+			wantErr: domain.ErrCategoryNotFound,
+		},
+		{
+			name:        "invalid category ID",
+			productID:   7,
+			categoryIDs: []int64{0},
+			mockFn:      func(m *MockCategoryRepository) {},
+			wantErr:     domain.ErrInvalidCategory,
+		},
+		{
+			name:        "invalid product ID",
+			productID:   0,
+			categoryIDs: []int64{1},
+			mockFn:      func(m *MockCategoryRepository) {},
+			wantErr:     domain.ErrInvalidProduct,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCategoryRepository)
+			tt.mockFn(repo)
+			uc := NewCategoryUseCase(database.NopTxManager{}, repo, nil, clock.Real())
+
+			_, err := uc.SetProductCategories(ctx, tt.productID, tt.categoryIDs)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCategoryUseCase_PublishesProductEvents(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockCategoryRepository)
+	repo.On("SetForProduct", mock.Anything, int64(7), []int64{1}).
+		Return([]*domain.Category{{ID: 1}}, &domain.Product{ID: 7, StoreID: 3}, nil)
+	repo.On("Update", mock.Anything, int64(1), mock.Anything).
+		Return(&domain.Category{ID: 1, Name: "Tea"}, []*domain.Product{{ID: 7, StoreID: 3}, {ID: 8, StoreID: 4}}, nil)
+	repo.On("Delete", mock.Anything, int64(1)).
+		Return([]*domain.Product{{ID: 8, StoreID: 4}}, nil)
+
+	events := &recordingPublisher{}
+	uc := NewCategoryUseCase(database.NopTxManager{}, repo, events, clock.Real())
+
+	_, err := uc.SetProductCategories(ctx, 7, []int64{1})
+	require.NoError(t, err)
+	_, err = uc.UpdateCategory(ctx, 1, &domain.Category{Name: "Tea"})
+	require.NoError(t, err)
+	require.NoError(t, uc.DeleteCategory(ctx, 1))
+
+	var published [][2]int64
+	for _, event := range events.events {
+		assert.Equal(t, domain.ProductEventUpdated, event.Type)
+		assert.NotEmpty(t, event.ID)
+		published = append(published, [2]int64{event.ProductID, event.StoreID})
+	}
+	assert.Equal(t, [][2]int64{{7, 3}, {7, 3}, {8, 4}, {8, 4}}, published)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import "context"
+
+type massOperationKey struct{}
+
+// WithMassOperationConfirmed marks ctx as carrying the caller's explicit
+// confirmation that a mass-destructive operation is intended.
+func WithMassOperationConfirmed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, massOperationKey{}, true)
+}
+
+func MassOperationConfirmed(ctx context.Context) bool {
+	confirmed, _ := ctx.Value(massOperationKey{}).(bool)
+	return confirmed
+}
+
+type bulkCallerKey struct{}
+
+// BulkCaller is who asks for a bulk operation. Elevated callers may have
+// operations past the bulk threshold held for confirmation; Token is the
+// confirmation token they repeat the operation with.
+type BulkCaller struct {
+	Actor    string
+	ClientIP string
+	Elevated bool
+	Token    string
+}
+
+// WithBulkCaller identifies the caller of bulk operations run with ctx.
+func WithBulkCaller(ctx context.Context, caller BulkCaller) context.Context {
+	return context.WithValue(ctx, bulkCallerKey{}, caller)
+}
+
+func BulkCallerFrom(ctx context.Context) BulkCaller {
+	caller, _ := ctx.Value(bulkCallerKey{}).(BulkCaller)
+	return caller
+}
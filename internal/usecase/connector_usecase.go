@@ -0,0 +1,423 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"backend-context-engineering-template/internal/connectors"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"backend-context-engineering-template/pkg/quantity"
+	"github.com/sirupsen/logrus"
+)
+
+type ConnectorUseCase struct {
+	connectorRepo ConnectorRepository
+	productRepo   ProductRepository
+	products      ProductWriter
+	secrets       SecretStore
+	factory       ConnectorFactory
+	clock         clock.Clock
+
+	mu      sync.Mutex
+	running map[int64]struct{}
+}
+
+// NewConnectorUseCase builds the connector use case. The catalog is read
+// from productRepo and written through products.
+func NewConnectorUseCase(connectorRepo ConnectorRepository, productRepo ProductRepository, products ProductWriter, secrets SecretStore, factory ConnectorFactory, clk clock.Clock) *ConnectorUseCase {
+	return &ConnectorUseCase{
+		connectorRepo: connectorRepo,
+		productRepo:   productRepo,
+		products:      products,
+		secrets:       secrets,
+		factory:       factory,
+		clock:         clk,
+		running:       make(map[int64]struct{}),
+	}
+}
+
+func (uc *ConnectorUseCase) CreateConnector(ctx context.Context, connector *domain.Connector, credentials []byte) (*domain.Connector, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "create_connector",
+		"store_id": connector.StoreID,
+		"kind":     connector.Kind,
+	}).Info("Creating connector")
+
+	if err := connector.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Connector validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidConnector, err)
+	}
+
+	if !uc.factory.Supports(connector.Kind) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedConnector, connector.Kind)
+	}
+
+	// Build the adapter once up front so bad settings are rejected before
+	// anything is persisted.
+	if _, err := uc.factory.New(connector.Kind, connector.Settings, credentials); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.connectorRepo.Create(ctx, connector)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to create connector in repository")
+		return nil, fmt.Errorf("failed to create connector: %w", err)
+	}
+
+	if err := uc.secrets.Put(ctx, created.SecretKey(), credentials); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to store connector credentials")
+		if delErr := uc.connectorRepo.Delete(ctx, created.ID); delErr != nil {
+			logger.FromContext(ctx).WithError(delErr).Error("Failed to roll back connector without credentials")
+		}
+		return nil, fmt.Errorf("failed to store connector credentials: %w", err)
+	}
+
+	return created, nil
+}
+
+func (uc *ConnectorUseCase) GetConnector(ctx context.Context, id int64) (*domain.Connector, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid connector ID", domain.ErrInvalidConnector)
+	}
+
+	connector, err := uc.connectorRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get connector from repository")
+		return nil, err
+	}
+
+	return connector, nil
+}
+
+func (uc *ConnectorUseCase) GetConnectors(ctx context.Context, limit, offset int) ([]*domain.Connector, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	list, err := uc.connectorRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get connectors from repository")
+		return nil, fmt.Errorf("failed to get connectors: %w", err)
+	}
+
+	return list, nil
+}
+
+func (uc *ConnectorUseCase) DeleteConnector(ctx context.Context, id int64) error {
+	connector, err := uc.GetConnector(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.connectorRepo.Delete(ctx, id); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to delete connector from repository")
+		return err
+	}
+
+	if err := uc.secrets.Delete(ctx, connector.SecretKey()); err != nil {
+		logger.FromContext(ctx).WithError(err).Warn("Failed to delete connector credentials")
+	}
+
+	return nil
+}
+
+// SyncConnector pulls products from the external system into the connector's
+// store, then pushes stock and price for linked products changed locally
+// since the previous successful sync. Progress is checkpointed per page so a
+// failed sync resumes where it stopped.
+func (uc *ConnectorUseCase) SyncConnector(ctx context.Context, id int64) (*domain.ConnectorSync, error) {
+	connector, err := uc.GetConnector(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !uc.acquire(connector.ID) {
+		return nil, domain.ErrConnectorSyncInProgress
+	}
+	defer uc.release(connector.ID)
+
+	logger := logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":       "sync_connector",
+		"connector_id": connector.ID,
+		"kind":         connector.Kind,
+		"store_id":     connector.StoreID,
+	})
+	logger.Info("Starting connector sync")
+
+	credentials, err := uc.secrets.Get(ctx, connector.SecretKey())
+	if err != nil {
+		logger.WithError(err).Error("Failed to load connector credentials")
+		return nil, fmt.Errorf("failed to load connector credentials: %w", err)
+	}
+
+	adapter, err := uc.factory.New(connector.Kind, connector.Settings, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := uc.connectorRepo.CreateSync(ctx, &domain.ConnectorSync{
+		ConnectorID: connector.ID,
+		Status:      domain.ConnectorSyncStatusRunning,
+		StartedAt:   uc.clock.Now(),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to create connector sync")
+		return nil, fmt.Errorf("failed to start connector sync: %w", err)
+	}
+
+	run.Status = domain.ConnectorSyncStatusSucceeded
+	if err := uc.sync(ctx, connector, adapter, run); err != nil {
+		logger.WithError(err).Error("Connector sync failed")
+		run.Status = domain.ConnectorSyncStatusFailed
+		run.Error = sql.NullString{String: err.Error(), Valid: true}
+	}
+	run.FinishedAt = sql.NullTime{Time: uc.clock.Now(), Valid: true}
+
+	if err := uc.connectorRepo.FinishSync(ctx, run); err != nil {
+		logger.WithError(err).Error("Failed to finish connector sync")
+		return nil, fmt.Errorf("failed to finish connector sync: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"sync_id": run.ID,
+		"status":  run.Status,
+		"pulled":  run.Pulled,
+		"pushed":  run.Pushed,
+	}).Info("Connector sync finished")
+
+	return run, nil
+}
+
+func (uc *ConnectorUseCase) GetConnectorSync(ctx context.Context, connectorID, syncID int64) (*domain.ConnectorSync, error) {
+	if connectorID <= 0 || syncID <= 0 {
+		return nil, fmt.Errorf("%w: invalid connector sync ID", domain.ErrInvalidConnector)
+	}
+
+	run, err := uc.connectorRepo.GetSync(ctx, connectorID, syncID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get connector sync from repository")
+		return nil, err
+	}
+
+	return run, nil
+}
+
+func (uc *ConnectorUseCase) GetConnectorSyncs(ctx context.Context, connectorID int64, limit, offset int) ([]*domain.ConnectorSync, error) {
+	if _, err := uc.GetConnector(ctx, connectorID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	runs, err := uc.connectorRepo.GetSyncs(ctx, connectorID, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get connector syncs from repository")
+		return nil, fmt.Errorf("failed to get connector syncs: %w", err)
+	}
+
+	return runs, nil
+}
+
+func (uc *ConnectorUseCase) sync(ctx context.Context, connector *domain.Connector, adapter connectors.Connector, run *domain.ConnectorSync) error {
+	startedAt := uc.clock.Now()
+
+	checkpoint, err := uc.connectorRepo.GetCheckpoint(ctx, connector.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+
+	links, err := uc.connectorRepo.GetLinks(ctx, connector.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load product links: %w", err)
+	}
+
+	catalog, err := uc.productRepo.GetAllByStore(ctx, connector.StoreID)
+	if err != nil {
+		return fmt.Errorf("failed to load store catalog: %w", err)
+	}
+
+	byID := make(map[int64]*domain.Product, len(catalog))
+	byName := make(map[string]*domain.Product, len(catalog))
+	for _, product := range catalog {
+		byID[product.ID] = product
+		byName[product.Name] = product
+	}
+
+	linked := make(map[string]int64, len(links))
+	for _, link := range links {
+		linked[link.ExternalID] = link.ProductID
+	}
+
+	pulled := make(map[int64]bool)
+	for {
+		page, err := adapter.PullProducts(ctx, checkpoint.Cursor, checkpoint.Since.Time)
+		if err != nil {
+			return fmt.Errorf("failed to pull products: %w", err)
+		}
+
+		for _, external := range page.Products {
+			run.Pulled++
+
+			if productID, ok := linked[external.ExternalID]; ok && byID[productID] == nil {
+				// The linked product is in the trash. Recreating it would
+				// leave a duplicate once it is restored, so it is left alone
+				// until it is restored or purged.
+				continue
+			}
+
+			productID, err := uc.upsert(ctx, connector, external, linked, byID, byName, run)
+			if err != nil {
+				run.Failed++
+				logger.FromContext(ctx).WithError(err).WithField("external_id", external.ExternalID).Warn("Failed to apply pulled product")
+				continue
+			}
+			pulled[productID] = true
+
+			if linked[external.ExternalID] != productID {
+				link := &domain.ProductLink{
+					ConnectorID: connector.ID,
+					ProductID:   productID,
+					ExternalID:  external.ExternalID,
+					SyncedAt:    uc.clock.Now(),
+				}
+				if err := uc.connectorRepo.SaveLink(ctx, link); err != nil {
+					return fmt.Errorf("failed to save product link: %w", err)
+				}
+				linked[external.ExternalID] = productID
+			}
+		}
+
+		checkpoint.Cursor = page.Next
+		if err := uc.connectorRepo.SaveCheckpoint(ctx, checkpoint); err != nil {
+			return fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+
+		if page.Next == "" {
+			break
+		}
+	}
+
+	var updates []connectors.StockPriceUpdate
+	for externalID, productID := range linked {
+		product, ok := byID[productID]
+		if !ok || pulled[productID] {
+			continue
+		}
+		if checkpoint.Since.Valid && !product.UpdatedAt.After(checkpoint.Since.Time) {
+			continue
+		}
+		if product.Amount.Decimals() > 0 {
+			run.Failed++
+			logger.FromContext(ctx).WithFields(logrus.Fields{
+				"external_id": externalID,
+				"amount":      product.Amount.String(),
+			}).Warn("Not pushing fractional amount to connector")
+			continue
+		}
+		updates = append(updates, connectors.StockPriceUpdate{
+			ExternalID: externalID,
+			Amount:     product.Amount.Whole(),
+			Price:      product.Price,
+		})
+	}
+
+	if len(updates) > 0 {
+		if err := adapter.PushStockPrice(ctx, updates); err != nil {
+			return fmt.Errorf("failed to push stock and price: %w", err)
+		}
+		run.Pushed = len(updates)
+	}
+
+	checkpoint.Cursor = ""
+	checkpoint.Since = sql.NullTime{Time: startedAt, Valid: true}
+	if err := uc.connectorRepo.SaveCheckpoint(ctx, checkpoint); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *ConnectorUseCase) upsert(ctx context.Context, connector *domain.Connector, external connectors.ExternalProduct, linked map[string]int64, byID map[int64]*domain.Product, byName map[string]*domain.Product, run *domain.ConnectorSync) (int64, error) {
+	existing := byID[linked[external.ExternalID]]
+	if existing == nil {
+		existing = byName[external.Name]
+	}
+
+	unit := external.Unit
+	if unit == "" && existing != nil {
+		unit = existing.Unit
+	}
+	amount, err := quantity.FromInt(external.Amount)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", domain.ErrInvalidProduct, err.Error())
+	}
+	desired := itemToProduct(connector.StoreID, domain.FeedItem{
+		Name:        external.Name,
+		Description: external.Description,
+		Amount:      amount,
+		Unit:        unit,
+		Price:       external.Price,
+	})
+	if err := desired.Validate(); err != nil {
+		return 0, fmt.Errorf("%w: %w", domain.ErrInvalidProduct, err)
+	}
+
+	if existing == nil {
+		created, err := uc.products.CreateProduct(ctx, desired)
+		if err != nil {
+			return 0, err
+		}
+		byID[created.ID] = created
+		byName[created.Name] = created
+		run.Created++
+		return created.ID, nil
+	}
+
+	if existing.Name != desired.Name || productDiffers(existing, desired) {
+		updated, err := uc.products.UpdateProduct(ctx, existing.ID, desired)
+		if err != nil {
+			return 0, err
+		}
+		byID[updated.ID] = updated
+		byName[updated.Name] = updated
+		run.Updated++
+	}
+
+	return existing.ID, nil
+}
+
+func (uc *ConnectorUseCase) acquire(connectorID int64) bool {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if _, ok := uc.running[connectorID]; ok {
+		return false
+	}
+	uc.running[connectorID] = struct{}{}
+	return true
+}
+
+func (uc *ConnectorUseCase) release(connectorID int64) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	delete(uc.running, connectorID)
+}
@@ -0,0 +1,323 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/connectors"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockConnectorRepository struct {
+	mock.Mock
+}
+
+func (m *MockConnectorRepository) Create(ctx context.Context, connector *domain.Connector) (*domain.Connector, error) {
+	args := m.Called(ctx, connector)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Connector), args.Error(1)
+}
+
+func (m *MockConnectorRepository) GetByID(ctx context.Context, id int64) (*domain.Connector, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Connector), args.Error(1)
+}
+
+func (m *MockConnectorRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Connector, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*domain.Connector), args.Error(1)
+}
+
+func (m *MockConnectorRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockConnectorRepository) GetCheckpoint(ctx context.Context, connectorID int64) (*domain.ConnectorCheckpoint, error) {
+	args := m.Called(ctx, connectorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ConnectorCheckpoint), args.Error(1)
+}
+
+func (m *MockConnectorRepository) SaveCheckpoint(ctx context.Context, checkpoint *domain.ConnectorCheckpoint) error {
+	args := m.Called(ctx, checkpoint)
+	return args.Error(0)
+}
+
+func (m *MockConnectorRepository) GetLinks(ctx context.Context, connectorID int64) ([]*domain.ProductLink, error) {
+	args := m.Called(ctx, connectorID)
+	return args.Get(0).([]*domain.ProductLink), args.Error(1)
+}
+
+func (m *MockConnectorRepository) SaveLink(ctx context.Context, link *domain.ProductLink) error {
+	args := m.Called(ctx, link)
+	return args.Error(0)
+}
+
+func (m *MockConnectorRepository) CreateSync(ctx context.Context, run *domain.ConnectorSync) (*domain.ConnectorSync, error) {
+	args := m.Called(ctx, run)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ConnectorSync), args.Error(1)
+}
+
+func (m *MockConnectorRepository) FinishSync(ctx context.Context, run *domain.ConnectorSync) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *MockConnectorRepository) GetSync(ctx context.Context, connectorID, syncID int64) (*domain.ConnectorSync, error) {
+	args := m.Called(ctx, connectorID, syncID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ConnectorSync), args.Error(1)
+}
+
+func (m *MockConnectorRepository) GetSyncs(ctx context.Context, connectorID int64, limit, offset int) ([]*domain.ConnectorSync, error) {
+	args := m.Called(ctx, connectorID, limit, offset)
+	return args.Get(0).([]*domain.ConnectorSync), args.Error(1)
+}
+
+type MockSecretStore struct {
+	mock.Mock
+}
+
+func (m *MockSecretStore) Put(ctx context.Context, key string, value []byte) error {
+	args := m.Called(ctx, key, value)
+	return args.Error(0)
+}
+
+func (m *MockSecretStore) Get(ctx context.Context, key string) ([]byte, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockSecretStore) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+type fakeConnector struct {
+	pages  []*connectors.Page
+	pushed []connectors.StockPriceUpdate
+}
+
+func (f *fakeConnector) Kind() string {
+	return "fake"
+}
+
+func (f *fakeConnector) PullProducts(ctx context.Context, cursor string, since time.Time) (*connectors.Page, error) {
+	page := f.pages[0]
+	f.pages = f.pages[1:]
+	return page, nil
+}
+
+func (f *fakeConnector) PushStockPrice(ctx context.Context, updates []connectors.StockPriceUpdate) error {
+	f.pushed = append(f.pushed, updates...)
+	return nil
+}
+
+type fakeFactory struct {
+	connector connectors.Connector
+	err       error
+}
+
+func (f *fakeFactory) Supports(kind string) bool {
+	return kind == "fake"
+}
+
+func (f *fakeFactory) New(kind string, settings map[string]string, credentials []byte) (connectors.Connector, error) {
+	return f.connector, f.err
+}
+
+func TestConnectorUseCase_CreateConnector(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores credentials in the secrets store", func(t *testing.T) {
+		repo := &MockConnectorRepository{}
+		secretStore := &MockSecretStore{}
+
+		repo.On("Create", mock.Anything, mock.Anything).Return(&domain.Connector{ID: 4, StoreID: 1, Kind: "fake"}, nil)
+		secretStore.On("Put", mock.Anything, "connector/fake/4", []byte("token")).Return(nil)
+
+		uc := NewConnectorUseCase(repo, &MockProductRepository{}, nil, secretStore, &fakeFactory{connector: &fakeConnector{}}, clock.Real())
+		got, err := uc.CreateConnector(ctx, &domain.Connector{StoreID: 1, Kind: "fake"}, []byte("token"))
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), got.ID)
+		repo.AssertExpectations(t)
+		secretStore.AssertExpectations(t)
+	})
+
+	t.Run("unsupported kind", func(t *testing.T) {
+		uc := NewConnectorUseCase(&MockConnectorRepository{}, &MockProductRepository{}, nil, &MockSecretStore{}, &fakeFactory{}, clock.Real())
+		_, err := uc.CreateConnector(ctx, &domain.Connector{StoreID: 1, Kind: "erp"}, []byte("token"))
+
+		assert.ErrorIs(t, err, domain.ErrUnsupportedConnector)
+	})
+
+	t.Run("rolls back when credentials cannot be stored", func(t *testing.T) {
+		repo := &MockConnectorRepository{}
+		secretStore := &MockSecretStore{}
+
+		repo.On("Create", mock.Anything, mock.Anything).Return(&domain.Connector{ID: 4, StoreID: 1, Kind: "fake"}, nil)
+		repo.On("Delete", mock.Anything, int64(4)).Return(nil)
+		secretStore.On("Put", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+		uc := NewConnectorUseCase(repo, &MockProductRepository{}, nil, secretStore, &fakeFactory{connector: &fakeConnector{}}, clock.Real())
+		_, err := uc.CreateConnector(ctx, &domain.Connector{StoreID: 1, Kind: "fake"}, []byte("token"))
+
+		assert.Error(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestConnectorUseCase_SyncConnector(t *testing.T) {
+	ctx := context.Background()
+
+	since := time.Now().Add(-time.Hour)
+	connector := &domain.Connector{ID: 2, StoreID: 5, Kind: "fake"}
+
+	repo := &MockConnectorRepository{}
+	productRepo := &MockProductRepository{}
+	secretStore := &MockSecretStore{}
+	adapter := &fakeConnector{pages: []*connectors.Page{
+		{Products: []connectors.ExternalProduct{{ExternalID: "a", Name: "Pulled", Amount: 3, Price: 9}}, Next: "p2"},
+		{Products: []connectors.ExternalProduct{{ExternalID: "b", Name: "Matched", Amount: 1, Price: 4}}},
+	}}
+
+	repo.On("GetByID", mock.Anything, int64(2)).Return(connector, nil)
+	secretStore.On("Get", mock.Anything, "connector/fake/2").Return([]byte("token"), nil)
+	repo.On("CreateSync", mock.Anything, mock.Anything).Return(&domain.ConnectorSync{ID: 1, ConnectorID: 2}, nil)
+	repo.On("GetCheckpoint", mock.Anything, int64(2)).Return(
+		&domain.ConnectorCheckpoint{ConnectorID: 2, Since: sql.NullTime{Time: since, Valid: true}}, nil)
+	repo.On("GetLinks", mock.Anything, int64(2)).Return([]*domain.ProductLink{
+		{ConnectorID: 2, ProductID: 30, ExternalID: "c"},
+	}, nil)
+	productRepo.On("GetAllByStore", mock.Anything, int64(5)).Return([]*domain.Product{
+		{ID: 20, StoreID: 5, Name: "Matched", Amount: quantity.New(1), Unit: domain.UnitPiece, Price: 4, Status: domain.ProductStatusActive},
+		{ID: 30, StoreID: 5, Name: "Local edit", Amount: quantity.New(7), Price: 2, Status: domain.ProductStatusActive, UpdatedAt: time.Now()},
+	}, nil)
+	productRepo.On("Create", mock.Anything, mock.Anything).Return(&domain.Product{ID: 10, StoreID: 5, Name: "Pulled"}, nil)
+	repo.On("SaveLink", mock.Anything, mock.Anything).Return(nil)
+	repo.On("SaveCheckpoint", mock.Anything, mock.Anything).Return(nil)
+	repo.On("FinishSync", mock.Anything, mock.Anything).Return(nil)
+
+	uc := NewConnectorUseCase(repo, productRepo, NewProductUseCase(database.NopTxManager{}, productRepo, nil, nil, nil, clock.Real()), secretStore, &fakeFactory{connector: adapter}, clock.Real())
+	run, err := uc.SyncConnector(ctx, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.ConnectorSyncStatusSucceeded, run.Status)
+	assert.Equal(t, 2, run.Pulled)
+	assert.Equal(t, 1, run.Created)
+	assert.Equal(t, 0, run.Updated)
+	assert.Equal(t, 1, run.Pushed)
+	assert.Equal(t, []connectors.StockPriceUpdate{{ExternalID: "c", Amount: 7, Price: 2}}, adapter.pushed)
+
+	repo.AssertNumberOfCalls(t, "SaveLink", 2)
+	repo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+	secretStore.AssertExpectations(t)
+}
+
+func TestConnectorUseCase_SyncConnector_LeavesTrashedProducts(t *testing.T) {
+	ctx := context.Background()
+
+	connector := &domain.Connector{ID: 2, StoreID: 5, Kind: "fake"}
+
+	repo := &MockConnectorRepository{}
+	productRepo := &MockProductRepository{}
+	secretStore := &MockSecretStore{}
+	adapter := &fakeConnector{pages: []*connectors.Page{
+		{Products: []connectors.ExternalProduct{{ExternalID: "a", Name: "Trashed", Amount: 3, Price: 9}}},
+	}}
+
+	repo.On("GetByID", mock.Anything, int64(2)).Return(connector, nil)
+	secretStore.On("Get", mock.Anything, "connector/fake/2").Return([]byte("token"), nil)
+	repo.On("CreateSync", mock.Anything, mock.Anything).Return(&domain.ConnectorSync{ID: 1, ConnectorID: 2}, nil)
+	repo.On("GetCheckpoint", mock.Anything, int64(2)).Return(&domain.ConnectorCheckpoint{ConnectorID: 2}, nil)
+	// Product 40 is linked but in the trash, so the catalog lacks it.
+	repo.On("GetLinks", mock.Anything, int64(2)).Return([]*domain.ProductLink{
+		{ConnectorID: 2, ProductID: 40, ExternalID: "a"},
+	}, nil)
+	productRepo.On("GetAllByStore", mock.Anything, int64(5)).Return([]*domain.Product{}, nil)
+	repo.On("SaveCheckpoint", mock.Anything, mock.Anything).Return(nil)
+	repo.On("FinishSync", mock.Anything, mock.Anything).Return(nil)
+
+	uc := NewConnectorUseCase(repo, productRepo, NewProductUseCase(database.NopTxManager{}, productRepo, nil, nil, nil, clock.Real()), secretStore, &fakeFactory{connector: adapter}, clock.Real())
+	run, err := uc.SyncConnector(ctx, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.ConnectorSyncStatusSucceeded, run.Status)
+	assert.Equal(t, 1, run.Pulled)
+	assert.Equal(t, 0, run.Created)
+	assert.Empty(t, adapter.pushed)
+
+	repo.AssertNotCalled(t, "SaveLink", mock.Anything, mock.Anything)
+	productRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+}
+
+func TestConnectorUseCase_SyncConnector_KeepsUnits(t *testing.T) {
+	ctx := context.Background()
+
+	connector := &domain.Connector{ID: 2, StoreID: 5, Kind: "fake"}
+
+	repo := &MockConnectorRepository{}
+	productRepo := &MockProductRepository{}
+	secretStore := &MockSecretStore{}
+	adapter := &fakeConnector{pages: []*connectors.Page{
+		{Products: []connectors.ExternalProduct{{ExternalID: "flour", Name: "Flour", Amount: 5, Price: 3}}},
+	}}
+
+	repo.On("GetByID", mock.Anything, int64(2)).Return(connector, nil)
+	secretStore.On("Get", mock.Anything, "connector/fake/2").Return([]byte("token"), nil)
+	repo.On("CreateSync", mock.Anything, mock.Anything).Return(&domain.ConnectorSync{ID: 1, ConnectorID: 2}, nil)
+	repo.On("GetCheckpoint", mock.Anything, int64(2)).Return(&domain.ConnectorCheckpoint{ConnectorID: 2}, nil)
+	repo.On("GetLinks", mock.Anything, int64(2)).Return([]*domain.ProductLink{
+		{ConnectorID: 2, ProductID: 50, ExternalID: "flour"},
+		{ConnectorID: 2, ProductID: 60, ExternalID: "sugar"},
+	}, nil)
+	productRepo.On("GetAllByStore", mock.Anything, int64(5)).Return([]*domain.Product{
+		{ID: 50, StoreID: 5, Name: "Flour", Amount: quantity.New(5), Unit: domain.UnitKilogram, Price: 3, Status: domain.ProductStatusActive},
+		{ID: 60, StoreID: 5, Name: "Sugar", Amount: quantity.MustParse("2.5"), Unit: domain.UnitKilogram, Price: 2, Status: domain.ProductStatusActive},
+	}, nil)
+	repo.On("SaveCheckpoint", mock.Anything, mock.Anything).Return(nil)
+	repo.On("FinishSync", mock.Anything, mock.Anything).Return(nil)
+
+	uc := NewConnectorUseCase(repo, productRepo, NewProductUseCase(database.NopTxManager{}, productRepo, nil, nil, nil, clock.Real()), secretStore, &fakeFactory{connector: adapter}, clock.Real())
+	run, err := uc.SyncConnector(ctx, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.ConnectorSyncStatusSucceeded, run.Status)
+	assert.Equal(t, 0, run.Updated, "a kg product must not be rewritten to pieces")
+	assert.Equal(t, 1, run.Failed, "a fractional amount must not be truncated")
+	assert.Empty(t, adapter.pushed)
+
+	productRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+	productRepo.AssertExpectations(t)
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// DigestUseCase manages which stores receive a daily digest of their
+// catalog changes, and where it is sent.
+type DigestUseCase struct {
+	digestRepo DigestRepository
+}
+
+func NewDigestUseCase(digestRepo DigestRepository) *DigestUseCase {
+	return &DigestUseCase{
+		digestRepo: digestRepo,
+	}
+}
+
+func (uc *DigestUseCase) GetSettings(ctx context.Context, storeID int64) (*domain.DigestSettings, error) {
+	if storeID <= 0 {
+		return nil, fmt.Errorf("%w: invalid store ID", domain.ErrInvalidDigestSettings)
+	}
+
+	settings, err := uc.digestRepo.GetSettings(ctx, storeID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get digest settings from repository")
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// SaveSettings subscribes the store to the digest, or changes where it is
+// sent.
+func (uc *DigestUseCase) SaveSettings(ctx context.Context, settings *domain.DigestSettings) (*domain.DigestSettings, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "save_digest_settings",
+		"store_id": settings.StoreID,
+		"channel":  settings.Channel,
+	}).Info("Saving digest settings")
+
+	if err := settings.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Digest settings validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidDigestSettings, err)
+	}
+
+	saved, err := uc.digestRepo.SaveSettings(ctx, settings)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to save digest settings in repository")
+		return nil, fmt.Errorf("failed to save digest settings: %w", err)
+	}
+
+	return saved, nil
+}
+
+func (uc *DigestUseCase) DeleteSettings(ctx context.Context, storeID int64) error {
+	if storeID <= 0 {
+		return fmt.Errorf("%w: invalid store ID", domain.ErrInvalidDigestSettings)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "delete_digest_settings",
+		"store_id": storeID,
+	}).Info("Deleting digest settings")
+
+	if err := uc.digestRepo.DeleteSettings(ctx, storeID); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to delete digest settings from repository")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDigestRepository struct {
+	mock.Mock
+}
+
+func (m *MockDigestRepository) GetSettings(ctx context.Context, storeID int64) (*domain.DigestSettings, error) {
+	args := m.Called(ctx, storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DigestSettings), args.Error(1)
+}
+
+func (m *MockDigestRepository) SaveSettings(ctx context.Context, settings *domain.DigestSettings) (*domain.DigestSettings, error) {
+	args := m.Called(ctx, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DigestSettings), args.Error(1)
+}
+
+func (m *MockDigestRepository) DeleteSettings(ctx context.Context, storeID int64) error {
+	args := m.Called(ctx, storeID)
+	return args.Error(0)
+}
+
+func TestDigestUseCase_SaveSettings(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		settings *domain.DigestSettings
+		mockFn   func(*MockDigestRepository)
+		wantErr  bool
+	}{
+		{
+			name:     "email",
+			settings: &domain.DigestSettings{StoreID: 1, Channel: domain.DigestChannelEmail, Target: "owner@example.com"},
+			mockFn: func(m *MockDigestRepository) {
+				m.On("SaveSettings", mock.Anything, mock.Anything).Return(&domain.DigestSettings{StoreID: 1}, nil)
+			},
+		},
+		{
+			name:     "webhook",
+			settings: &domain.DigestSettings{StoreID: 1, Channel: domain.DigestChannelWebhook, Target: "https://hooks.example.com/digest"},
+			mockFn: func(m *MockDigestRepository) {
+				m.On("SaveSettings", mock.Anything, mock.Anything).Return(&domain.DigestSettings{StoreID: 1}, nil)
+			},
+		},
+		{
+			name:     "email with display name",
+			settings: &domain.DigestSettings{StoreID: 1, Channel: domain.DigestChannelEmail, Target: "Owner <owner@example.com>"},
+			mockFn:   func(m *MockDigestRepository) {},
+			wantErr:  true,
+		},
+		{
+			name:     "webhook without scheme",
+			settings: &domain.DigestSettings{StoreID: 1, Channel: domain.DigestChannelWebhook, Target: "hooks.example.com"},
+			mockFn:   func(m *MockDigestRepository) {},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown channel",
+			settings: &domain.DigestSettings{StoreID: 1, Channel: "sms", Target: "+15550100"},
+			mockFn:   func(m *MockDigestRepository) {},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid store",
+			settings: &domain.DigestSettings{Channel: domain.DigestChannelEmail, Target: "owner@example.com"},
+			mockFn:   func(m *MockDigestRepository) {},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockDigestRepository{}
+			tt.mockFn(repo)
+
+			uc := NewDigestUseCase(repo)
+			_, err := uc.SaveSettings(ctx, tt.settings)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidDigestSettings)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDigestUseCase_DeleteSettings(t *testing.T) {
+	repo := &MockDigestRepository{}
+	repo.On("DeleteSettings", mock.Anything, int64(2)).Return(domain.ErrDigestSettingsNotFound)
+
+	uc := NewDigestUseCase(repo)
+
+	assert.ErrorIs(t, uc.DeleteSettings(context.Background(), 2), domain.ErrDigestSettingsNotFound)
+	assert.ErrorIs(t, uc.DeleteSettings(context.Background(), 0), domain.ErrInvalidDigestSettings)
+	repo.AssertExpectations(t)
+}
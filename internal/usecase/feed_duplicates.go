@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// Actions a feed run takes for one row.
+const (
+	feedRowCreate = "create"
+	feedRowSync   = "sync"
+	feedRowSkip   = "skip"
+)
+
+// feedRow is a row of a feed run as planned before anything is written.
+type feedRow struct {
+	index   int
+	item    domain.FeedItem
+	desired *domain.Product
+	action  string
+	// product is the existing product the row syncs or skips.
+	product *domain.Product
+	// duplicate is set when the row was matched by a rule rather than by
+	// its link.
+	duplicate *domain.FeedDuplicate
+	// relink is set when the row's link must be saved once it has a
+	// product.
+	relink bool
+	err    error
+}
+
+// feedMatcher resolves a feed's rows against the store's catalog: first
+// by the links earlier runs saved, then by the feed's match rules.
+type feedMatcher struct {
+	feed    *domain.Feed
+	byID    map[int64]*domain.Product
+	catalog []*domain.Product
+	// links are the feed's own links by row key.
+	links map[string]domain.FeedProductLink
+	// bySKU and byBarcode hold the products any of the store's feeds
+	// linked to a code.
+	bySKU      map[string][]int64
+	byBarcode  map[string][]int64
+	byName     map[string][]*domain.Product
+	normalized map[int64]string
+	// claimed maps each product a row resolved to to that row.
+	claimed map[int64]int
+}
+
+func newFeedMatcher(feed *domain.Feed, catalog []*domain.Product, links []domain.FeedProductLink) *feedMatcher {
+	m := &feedMatcher{
+		feed:       feed,
+		byID:       make(map[int64]*domain.Product, len(catalog)),
+		catalog:    catalog,
+		links:      make(map[string]domain.FeedProductLink),
+		bySKU:      make(map[string][]int64),
+		byBarcode:  make(map[string][]int64),
+		byName:     make(map[string][]*domain.Product, len(catalog)),
+		normalized: make(map[int64]string, len(catalog)),
+		claimed:    make(map[int64]int),
+	}
+	for _, product := range catalog {
+		m.byID[product.ID] = product
+		m.byName[product.Name] = append(m.byName[product.Name], product)
+		m.normalized[product.ID] = domain.NormalizeName(product.Name)
+	}
+	for _, link := range links {
+		if _, ok := m.byID[link.ProductID]; !ok {
+			continue
+		}
+		if link.FeedID == feed.ID {
+			m.links[link.Key] = link
+		}
+		if link.SKU != "" {
+			m.bySKU[link.SKU] = append(m.bySKU[link.SKU], link.ProductID)
+		}
+		if link.Barcode != "" {
+			m.byBarcode[link.Barcode] = append(m.byBarcode[link.Barcode], link.ProductID)
+		}
+	}
+	return m
+}
+
+// plan resolves every row. Linked rows are resolved first, so a rule
+// cannot match a product another row of the feed is linked to. A row that
+// is invalid still claims its product, so a bad row does not deactivate
+// the product it stands for.
+func (m *feedMatcher) plan(items []domain.FeedItem) []*feedRow {
+	rows := make([]*feedRow, len(items))
+	keys := make(map[string]int, len(items))
+	var unlinked []*feedRow
+	for i, item := range items {
+		row := &feedRow{index: i, item: item, desired: itemToProduct(m.feed.StoreID, item)}
+		rows[i] = row
+
+		key := item.Key()
+		if first, ok := keys[key]; ok {
+			row.err = fmt.Errorf("duplicate of item %d in feed", first+1)
+			continue
+		}
+		keys[key] = i
+
+		if err := row.desired.Validate(); err != nil {
+			row.err = err
+		} else if item.OnDuplicate != "" && !domain.ValidDuplicateResolution(item.OnDuplicate) {
+			row.err = errors.New("on_duplicate must be skip, update or create")
+		}
+
+		link, ok := m.links[key]
+		if !ok {
+			unlinked = append(unlinked, row)
+			continue
+		}
+		if first, ok := m.claimed[link.ProductID]; ok {
+			row.err = fmt.Errorf("duplicate of item %d in feed", first+1)
+			continue
+		}
+		m.claimed[link.ProductID] = i
+		row.action = feedRowSync
+		row.product = m.byID[link.ProductID]
+		row.relink = link.SKU != item.SKU || link.Barcode != item.Barcode
+	}
+
+	for _, row := range unlinked {
+		product, rule := m.match(row.item)
+		if row.err != nil {
+			if product != nil {
+				m.claimed[product.ID] = row.index
+			}
+			continue
+		}
+		row.relink = true
+		if product == nil {
+			row.action = feedRowCreate
+			continue
+		}
+
+		resolution := row.item.OnDuplicate
+		if resolution == "" {
+			resolution = m.feed.OnDuplicate
+		}
+		if resolution == "" {
+			resolution = domain.DuplicateUpdate
+		}
+		row.duplicate = &domain.FeedDuplicate{
+			Item:       row.index + 1,
+			Name:       row.item.Name,
+			ProductID:  product.ID,
+			MatchedBy:  rule,
+			Resolution: resolution,
+		}
+
+		switch resolution {
+		case domain.DuplicateSkip:
+			m.claimed[product.ID] = row.index
+			row.action = feedRowSkip
+			row.product = product
+			row.relink = false
+		case domain.DuplicateUpdate:
+			m.claimed[product.ID] = row.index
+			row.action = feedRowSync
+			row.product = product
+		default:
+			row.action = feedRowCreate
+		}
+	}
+
+	return rows
+}
+
+// match finds an unclaimed product for the item with the feed's rules,
+// tried in order, and returns the rule that found it.
+func (m *feedMatcher) match(item domain.FeedItem) (*domain.Product, string) {
+	rules := m.feed.MatchBy
+	if len(rules) == 0 {
+		rules = domain.DefaultMatchBy
+	}
+
+	for _, rule := range rules {
+		var product *domain.Product
+		switch rule {
+		case domain.DuplicateMatchSKU:
+			if item.SKU != "" {
+				product = m.firstUnclaimed(m.bySKU[item.SKU])
+			}
+		case domain.DuplicateMatchBarcode:
+			if item.Barcode != "" {
+				product = m.firstUnclaimed(m.byBarcode[item.Barcode])
+			}
+		case domain.DuplicateMatchName:
+			for _, candidate := range m.byName[item.Name] {
+				if _, ok := m.claimed[candidate.ID]; !ok {
+					product = candidate
+					break
+				}
+			}
+		case domain.DuplicateMatchFuzzyName:
+			product = m.fuzzyMatch(item.Name)
+		}
+		if product != nil {
+			return product, rule
+		}
+	}
+	return nil, ""
+}
+
+func (m *feedMatcher) firstUnclaimed(ids []int64) *domain.Product {
+	for _, id := range ids {
+		if _, ok := m.claimed[id]; !ok {
+			return m.byID[id]
+		}
+	}
+	return nil
+}
+
+// fuzzyMatch returns the unclaimed product whose name is most like name,
+// the earliest in the catalog on a tie.
+func (m *feedMatcher) fuzzyMatch(name string) *domain.Product {
+	normalized := domain.NormalizeName(name)
+	if normalized == "" {
+		return nil
+	}
+
+	var best *domain.Product
+	bestSimilarity := domain.MinFuzzySimilarity
+	for _, product := range m.catalog {
+		if _, ok := m.claimed[product.ID]; ok {
+			continue
+		}
+		similarity := domain.NameSimilarity(normalized, m.normalized[product.ID])
+		if similarity > bestSimilarity || (best == nil && similarity == bestSimilarity) {
+			best, bestSimilarity = product, similarity
+		}
+	}
+	return best
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+)
+
+// FeedScheduler periodically runs every registered feed whose interval has
+// elapsed.
+type FeedScheduler struct {
+	feedUseCase FeedUseCaseInterface
+	interval    time.Duration
+	clock       clock.Clock
+}
+
+func NewFeedScheduler(feedUseCase FeedUseCaseInterface, interval time.Duration, clk clock.Clock) *FeedScheduler {
+	return &FeedScheduler{
+		feedUseCase: feedUseCase,
+		interval:    interval,
+		clock:       clk,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (s *FeedScheduler) Run(ctx context.Context) {
+	logger.FromContext(ctx).WithField("interval", s.interval).Info("Feed scheduler started")
+
+	ticker := s.clock.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.FromContext(ctx).Info("Feed scheduler stopped")
+			return
+		case <-ticker.C():
+			if err := s.feedUseCase.RunDueFeeds(ctx); err != nil && ctx.Err() == nil {
+				logger.FromContext(ctx).WithError(err).Error("Failed to run due feeds")
+			}
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type FeedUseCase struct {
+	feedRepo    FeedRepository
+	productRepo ProductRepository
+	products    ProductWriter
+	fetcher     FeedFetcher
+	images      FeedImageImporter
+	maxShrink   float64
+	clock       clock.Clock
+
+	mu      sync.Mutex
+	running map[int64]struct{}
+}
+
+// NewFeedUseCase builds the feed use case. The catalog is read from
+// productRepo and written through products. A run is aborted when its feed
+// is empty or leaves out more than maxShrink (0 to 1) of the store's active
+// products, so a truncated download does not deactivate the catalog.
+// images may be nil, in which case rows' image URLs are ignored.
+func NewFeedUseCase(feedRepo FeedRepository, productRepo ProductRepository, products ProductWriter, fetcher FeedFetcher, images FeedImageImporter, maxShrink float64, clk clock.Clock) *FeedUseCase {
+	return &FeedUseCase{
+		feedRepo:    feedRepo,
+		productRepo: productRepo,
+		products:    products,
+		fetcher:     fetcher,
+		images:      images,
+		maxShrink:   maxShrink,
+		clock:       clk,
+		running:     make(map[int64]struct{}),
+	}
+}
+
+func (uc *FeedUseCase) RegisterFeed(ctx context.Context, feed *domain.Feed) (*domain.Feed, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "register_feed",
+		"store_id": feed.StoreID,
+		"format":   feed.Format,
+	}).Info("Registering product feed")
+
+	if len(feed.MatchBy) == 0 {
+		feed.MatchBy = append([]string(nil), domain.DefaultMatchBy...)
+	}
+	if feed.OnDuplicate == "" {
+		feed.OnDuplicate = domain.DuplicateUpdate
+	}
+
+	if err := feed.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Feed validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidFeed, err)
+	}
+
+	createdFeed, err := uc.feedRepo.Create(ctx, feed)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to create feed in repository")
+		return nil, fmt.Errorf("failed to register feed: %w", err)
+	}
+
+	return createdFeed, nil
+}
+
+func (uc *FeedUseCase) GetFeed(ctx context.Context, id int64) (*domain.Feed, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid feed ID", domain.ErrInvalidFeed)
+	}
+
+	feed, err := uc.feedRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get feed from repository")
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+func (uc *FeedUseCase) GetFeeds(ctx context.Context, limit, offset int) ([]*domain.Feed, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	feeds, err := uc.feedRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get feeds from repository")
+		return nil, fmt.Errorf("failed to get feeds: %w", err)
+	}
+
+	return feeds, nil
+}
+
+func (uc *FeedUseCase) DeleteFeed(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return fmt.Errorf("%w: invalid feed ID", domain.ErrInvalidFeed)
+	}
+
+	if err := uc.feedRepo.Delete(ctx, id); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to delete feed from repository")
+		return err
+	}
+
+	return nil
+}
+
+// RunFeed fetches the feed, diffs it against the store's current catalog and
+// applies the resulting creates, updates and deactivations. The returned run
+// is the persisted report, also when the fetch itself failed.
+func (uc *FeedUseCase) RunFeed(ctx context.Context, id int64) (*domain.FeedRun, error) {
+	feed, err := uc.GetFeed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !uc.acquire(feed.ID) {
+		return nil, domain.ErrFeedRunInProgress
+	}
+	defer uc.release(feed.ID)
+
+	logger := logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "run_feed",
+		"feed_id":  feed.ID,
+		"store_id": feed.StoreID,
+	})
+	logger.Info("Running product feed")
+
+	startedAt := uc.clock.Now()
+	run, err := uc.feedRepo.CreateRun(ctx, &domain.FeedRun{
+		FeedID:    feed.ID,
+		Status:    domain.FeedRunStatusRunning,
+		StartedAt: startedAt,
+	})
+	if err != nil {
+		logger.WithError(err).Error("Failed to create feed run")
+		return nil, fmt.Errorf("failed to start feed run: %w", err)
+	}
+
+	if err := uc.feedRepo.MarkRun(ctx, feed.ID, startedAt); err != nil {
+		logger.WithError(err).Warn("Failed to record feed run time")
+	}
+
+	runErr := uc.apply(ctx, feed, run)
+
+	run.Status = domain.FeedRunStatusSucceeded
+	if runErr != nil {
+		run.Status = domain.FeedRunStatusFailed
+		run.Errors = append(run.Errors, runErr.Error())
+	}
+	run.FinishedAt = sql.NullTime{Time: uc.clock.Now(), Valid: true}
+
+	if err := uc.feedRepo.FinishRun(ctx, run); err != nil {
+		logger.WithError(err).Error("Failed to finish feed run")
+		return nil, fmt.Errorf("failed to finish feed run: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"run_id":      run.ID,
+		"status":      run.Status,
+		"created":     run.Created,
+		"updated":     run.Updated,
+		"deactivated": run.Deactivated,
+		"skipped":     run.Skipped,
+		"duplicates":  len(run.Duplicates),
+		"images":      run.ImagesQueued,
+		"failed":      run.Failed,
+	}).Info("Product feed run finished")
+
+	return run, nil
+}
+
+func (uc *FeedUseCase) RunDueFeeds(ctx context.Context) error {
+	feeds, err := uc.feedRepo.GetEnabled(ctx)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get enabled feeds")
+		return fmt.Errorf("failed to get enabled feeds: %w", err)
+	}
+
+	now := uc.clock.Now()
+	for _, feed := range feeds {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !feed.IsDue(now) {
+			continue
+		}
+		if _, err := uc.RunFeed(ctx, feed.ID); err != nil && !errors.Is(err, domain.ErrFeedRunInProgress) {
+			logger.FromContext(ctx).WithError(err).WithField("feed_id", feed.ID).Error("Scheduled feed run failed")
+		}
+	}
+
+	return nil
+}
+
+func (uc *FeedUseCase) GetFeedRun(ctx context.Context, feedID, runID int64) (*domain.FeedRun, error) {
+	if feedID <= 0 || runID <= 0 {
+		return nil, fmt.Errorf("%w: invalid feed run ID", domain.ErrInvalidFeed)
+	}
+
+	run, err := uc.feedRepo.GetRun(ctx, feedID, runID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get feed run from repository")
+		return nil, err
+	}
+
+	return run, nil
+}
+
+func (uc *FeedUseCase) GetFeedRuns(ctx context.Context, feedID int64, limit, offset int) ([]*domain.FeedRun, error) {
+	if _, err := uc.GetFeed(ctx, feedID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	runs, err := uc.feedRepo.GetRuns(ctx, feedID, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get feed runs from repository")
+		return nil, fmt.Errorf("failed to get feed runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// GetFeedRunImages lists the outcomes of the run's image URLs. Runs from
+// before image import was set up have none.
+func (uc *FeedUseCase) GetFeedRunImages(ctx context.Context, feedID, runID int64) ([]*domain.ImageImport, error) {
+	if _, err := uc.GetFeedRun(ctx, feedID, runID); err != nil {
+		return nil, err
+	}
+	if uc.images == nil {
+		return []*domain.ImageImport{}, nil
+	}
+	return uc.images.GetImports(ctx, runID)
+}
+
+func (uc *FeedUseCase) apply(ctx context.Context, feed *domain.Feed, run *domain.FeedRun) error {
+	items, err := uc.fetcher.Fetch(ctx, feed)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrFeedFetchFailed, err.Error())
+	}
+
+	current, err := uc.productRepo.GetAllByStore(ctx, feed.StoreID)
+	if err != nil {
+		return fmt.Errorf("failed to load store catalog: %w", err)
+	}
+
+	links, err := uc.feedRepo.GetLinks(ctx, feed.StoreID)
+	if err != nil {
+		return fmt.Errorf("failed to load feed links: %w", err)
+	}
+
+	matcher := newFeedMatcher(feed, current, links)
+	rows := matcher.plan(items)
+
+	if err := uc.checkShrink(len(items), current, matcher.claimed); err != nil {
+		return err
+	}
+
+	var images []domain.ImageImport
+	for _, row := range rows {
+		if row.err != nil {
+			run.Failed++
+			run.Errors = append(run.Errors, fmt.Sprintf("item %d: %s", row.index+1, row.err.Error()))
+			continue
+		}
+		if row.duplicate != nil {
+			run.Duplicates = append(run.Duplicates, *row.duplicate)
+		}
+
+		var productID int64
+		switch {
+		case row.action == feedRowSkip:
+			run.Skipped++
+			continue
+		case row.action == feedRowCreate:
+			created, err := uc.products.CreateProduct(ctx, row.desired)
+			if err != nil {
+				run.Failed++
+				run.Errors = append(run.Errors, fmt.Sprintf("item %d: %s", row.index+1, err.Error()))
+				continue
+			}
+			productID = created.ID
+			run.Created++
+		case row.product.Name != row.desired.Name || productDiffers(row.product, row.desired):
+			if _, err := uc.products.UpdateProduct(ctx, row.product.ID, row.desired); err != nil {
+				run.Failed++
+				run.Errors = append(run.Errors, fmt.Sprintf("item %d: %s", row.index+1, err.Error()))
+				continue
+			}
+			productID = row.product.ID
+			run.Updated++
+		default:
+			productID = row.product.ID
+			run.Unchanged++
+		}
+
+		if row.relink {
+			uc.link(ctx, feed, row.item, productID)
+		}
+		for _, url := range row.item.ImageURLs {
+			images = append(images, domain.ImageImport{ProductID: productID, URL: url})
+		}
+	}
+
+	for _, product := range current {
+		if _, ok := matcher.claimed[product.ID]; ok || product.Status == domain.ProductStatusInactive {
+			continue
+		}
+
+		deactivated := *product
+		deactivated.Status = domain.ProductStatusInactive
+		if _, err := uc.products.UpdateProduct(ctx, product.ID, &deactivated); err != nil {
+			run.Failed++
+			run.Errors = append(run.Errors, fmt.Sprintf("product %d: %s", product.ID, err.Error()))
+			continue
+		}
+		run.Deactivated++
+	}
+
+	if len(images) > 0 {
+		uc.queueImages(ctx, run, images)
+	}
+
+	return nil
+}
+
+// queueImages hands the rows' images to the importer. The products are
+// saved by then, so a failure is reported without failing the run.
+func (uc *FeedUseCase) queueImages(ctx context.Context, run *domain.FeedRun, images []domain.ImageImport) {
+	if uc.images == nil {
+		run.Errors = append(run.Errors, "image_urls ignored: image import is not configured")
+		return
+	}
+
+	queued, err := uc.images.QueueImports(ctx, run.ID, images)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithField("run_id", run.ID).Error("Failed to queue feed images")
+		run.Errors = append(run.Errors, fmt.Sprintf("images: %s", err.Error()))
+		return
+	}
+	run.ImagesQueued = queued
+}
+
+// link saves the row's link to its product. A link that is not saved only
+// means the next run matches the row again, so failures are logged.
+func (uc *FeedUseCase) link(ctx context.Context, feed *domain.Feed, item domain.FeedItem, productID int64) {
+	err := uc.feedRepo.SaveLink(ctx, &domain.FeedProductLink{
+		FeedID:    feed.ID,
+		Key:       item.Key(),
+		ProductID: productID,
+		SKU:       item.SKU,
+		Barcode:   item.Barcode,
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+			"feed_id":    feed.ID,
+			"product_id": productID,
+		}).Warn("Failed to link feed item to product")
+	}
+}
+
+// checkShrink refuses a feed that would deactivate too many of the store's
+// active products: those no row resolved to.
+func (uc *FeedUseCase) checkShrink(items int, current []*domain.Product, claimed map[int64]int) error {
+	active, missing := 0, 0
+	for _, product := range current {
+		if product.Status != domain.ProductStatusActive {
+			continue
+		}
+		active++
+		if _, ok := claimed[product.ID]; !ok {
+			missing++
+		}
+	}
+
+	if active == 0 {
+		return nil
+	}
+	if items == 0 {
+		return fmt.Errorf("%w: feed is empty but the store has %d active products", domain.ErrFeedShrunk, active)
+	}
+	if float64(missing) > uc.maxShrink*float64(active) {
+		return fmt.Errorf("%w: %d of %d active products are missing", domain.ErrFeedShrunk, missing, active)
+	}
+	return nil
+}
+
+func (uc *FeedUseCase) acquire(feedID int64) bool {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if _, ok := uc.running[feedID]; ok {
+		return false
+	}
+	uc.running[feedID] = struct{}{}
+	return true
+}
+
+func (uc *FeedUseCase) release(feedID int64) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	delete(uc.running, feedID)
+}
+
+func itemToProduct(storeID int64, item domain.FeedItem) *domain.Product {
+	description := sql.NullString{}
+	if item.Description != "" {
+		description = sql.NullString{String: item.Description, Valid: true}
+	}
+
+	unit := item.Unit
+	if unit == "" {
+		unit = domain.UnitPiece
+	}
+
+	return &domain.Product{
+		StoreID:     storeID,
+		Name:        item.Name,
+		Description: description,
+		Amount:      item.Amount,
+		Unit:        unit,
+		Price:       item.Price,
+		Status:      domain.ProductStatusActive,
+	}
+}
+
+func productDiffers(existing, desired *domain.Product) bool {
+	return existing.Description != desired.Description ||
+		existing.Amount != desired.Amount ||
+		existing.Unit != desired.Unit ||
+		existing.Price != desired.Price ||
+		existing.Status != desired.Status
+}
@@ -0,0 +1,553 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockFeedRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeedRepository) Create(ctx context.Context, feed *domain.Feed) (*domain.Feed, error) {
+	args := m.Called(ctx, feed)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Feed), args.Error(1)
+}
+
+func (m *MockFeedRepository) GetByID(ctx context.Context, id int64) (*domain.Feed, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Feed), args.Error(1)
+}
+
+func (m *MockFeedRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Feed, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*domain.Feed), args.Error(1)
+}
+
+func (m *MockFeedRepository) GetEnabled(ctx context.Context) ([]*domain.Feed, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.Feed), args.Error(1)
+}
+
+func (m *MockFeedRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) MarkRun(ctx context.Context, id int64, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) CreateRun(ctx context.Context, run *domain.FeedRun) (*domain.FeedRun, error) {
+	args := m.Called(ctx, run)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FeedRun), args.Error(1)
+}
+
+func (m *MockFeedRepository) FinishRun(ctx context.Context, run *domain.FeedRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *MockFeedRepository) GetRun(ctx context.Context, feedID, runID int64) (*domain.FeedRun, error) {
+	args := m.Called(ctx, feedID, runID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FeedRun), args.Error(1)
+}
+
+func (m *MockFeedRepository) GetRuns(ctx context.Context, feedID int64, limit, offset int) ([]*domain.FeedRun, error) {
+	args := m.Called(ctx, feedID, limit, offset)
+	return args.Get(0).([]*domain.FeedRun), args.Error(1)
+}
+
+func (m *MockFeedRepository) GetLinks(ctx context.Context, storeID int64) ([]domain.FeedProductLink, error) {
+	args := m.Called(ctx, storeID)
+	return args.Get(0).([]domain.FeedProductLink), args.Error(1)
+}
+
+func (m *MockFeedRepository) SaveLink(ctx context.Context, link *domain.FeedProductLink) error {
+	args := m.Called(ctx, link)
+	return args.Error(0)
+}
+
+type MockFeedFetcher struct {
+	mock.Mock
+}
+
+func (m *MockFeedFetcher) Fetch(ctx context.Context, feed *domain.Feed) ([]domain.FeedItem, error) {
+	args := m.Called(ctx, feed)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.FeedItem), args.Error(1)
+}
+
+type MockFeedImageImporter struct {
+	mock.Mock
+}
+
+func (m *MockFeedImageImporter) QueueImports(ctx context.Context, runID int64, requested []domain.ImageImport) (int, error) {
+	args := m.Called(ctx, runID, requested)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockFeedImageImporter) GetImports(ctx context.Context, runID int64) ([]*domain.ImageImport, error) {
+	args := m.Called(ctx, runID)
+	return args.Get(0).([]*domain.ImageImport), args.Error(1)
+}
+
+func TestFeedUseCase_RegisterFeed(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		feed    *domain.Feed
+		mockFn  func(*MockFeedRepository)
+		wantErr bool
+		errType error
+	}{
+		{
+			name: "successful registration",
+			feed: &domain.Feed{
+				StoreID:         1,
+				URL:             "https://supplier.example.com/feed.csv",
+				Format:          domain.FeedFormatCSV,
+				IntervalSeconds: 3600,
+				Enabled:         true,
+			},
+			mockFn: func(m *MockFeedRepository) {
+				m.On("Create", mock.Anything, mock.MatchedBy(func(f *domain.Feed) bool {
+					return len(f.MatchBy) == 1 && f.MatchBy[0] == domain.DuplicateMatchName &&
+						f.OnDuplicate == domain.DuplicateUpdate
+				})).Return(&domain.Feed{ID: 1}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid url",
+			feed: &domain.Feed{
+				StoreID:         1,
+				URL:             "ftp://supplier.example.com/feed.csv",
+				Format:          domain.FeedFormatCSV,
+				IntervalSeconds: 3600,
+			},
+			mockFn:  func(m *MockFeedRepository) {},
+			wantErr: true,
+			errType: domain.ErrInvalidFeed,
+		},
+		{
+			name: "interval too short",
+			feed: &domain.Feed{
+				StoreID:         1,
+				URL:             "https://supplier.example.com/feed.json",
+				Format:          domain.FeedFormatJSON,
+				IntervalSeconds: 60,
+			},
+			mockFn:  func(m *MockFeedRepository) {},
+			wantErr: true,
+			errType: domain.ErrInvalidFeed,
+		},
+		{
+			name: "unknown match rule",
+			feed: &domain.Feed{
+				StoreID:         1,
+				URL:             "https://supplier.example.com/feed.json",
+				Format:          domain.FeedFormatJSON,
+				IntervalSeconds: 3600,
+				MatchBy:         []string{domain.DuplicateMatchSKU, "color"},
+			},
+			mockFn:  func(m *MockFeedRepository) {},
+			wantErr: true,
+			errType: domain.ErrInvalidFeed,
+		},
+		{
+			name: "import mapping on a json feed",
+			feed: &domain.Feed{
+				StoreID:         1,
+				URL:             "https://supplier.example.com/feed.json",
+				Format:          domain.FeedFormatJSON,
+				IntervalSeconds: 3600,
+				MappingID:       sql.NullInt64{Int64: 2, Valid: true},
+			},
+			mockFn:  func(m *MockFeedRepository) {},
+			wantErr: true,
+			errType: domain.ErrInvalidFeed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feedRepo := &MockFeedRepository{}
+			tt.mockFn(feedRepo)
+
+			uc := NewFeedUseCase(feedRepo, &MockProductRepository{}, nil, &MockFeedFetcher{}, nil, 0.5, clock.Real())
+			_, err := uc.RegisterFeed(ctx, tt.feed)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errType != nil {
+					assert.ErrorIs(t, err, tt.errType)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			feedRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFeedUseCase_RunFeed(t *testing.T) {
+	ctx := context.Background()
+
+	feed := &domain.Feed{
+		ID:              7,
+		StoreID:         3,
+		URL:             "https://supplier.example.com/feed.csv",
+		Format:          domain.FeedFormatCSV,
+		IntervalSeconds: 3600,
+		Enabled:         true,
+	}
+
+	t.Run("applies creates, updates and deactivations", func(t *testing.T) {
+		feedRepo := &MockFeedRepository{}
+		productRepo := &MockProductRepository{}
+		fetcher := &MockFeedFetcher{}
+
+		feedRepo.On("GetByID", mock.Anything, int64(7)).Return(feed, nil)
+		feedRepo.On("CreateRun", mock.Anything, mock.Anything).Return(
+			&domain.FeedRun{ID: 11, FeedID: 7, Status: domain.FeedRunStatusRunning}, nil)
+		feedRepo.On("MarkRun", mock.Anything, int64(7), mock.Anything).Return(nil)
+		feedRepo.On("FinishRun", mock.Anything, mock.Anything).Return(nil)
+
+		fetcher.On("Fetch", mock.Anything, feed).Return([]domain.FeedItem{
+			{Name: "New", Amount: quantity.MustParse("1.25"), Unit: domain.UnitKilogram, Price: 5},
+			{Name: "Changed", Amount: quantity.New(9), Price: 10},
+			{Name: "Same", Amount: quantity.New(2), Price: 3},
+			{Name: "", Amount: quantity.New(1), Price: 1},
+		}, nil)
+
+		productRepo.On("GetAllByStore", mock.Anything, int64(3)).Return([]*domain.Product{
+			{ID: 1, StoreID: 3, Name: "Changed", Amount: quantity.New(1), Unit: domain.UnitPiece, Price: 10, Status: domain.ProductStatusActive},
+			{ID: 2, StoreID: 3, Name: "Same", Amount: quantity.New(2), Unit: domain.UnitPiece, Price: 3, Status: domain.ProductStatusActive},
+			{ID: 3, StoreID: 3, Name: "Gone", Amount: quantity.New(4), Unit: domain.UnitPiece, Price: 8, Status: domain.ProductStatusActive},
+			{ID: 4, StoreID: 3, Name: "Already gone", Amount: quantity.New(0), Unit: domain.UnitPiece, Price: 8, Status: domain.ProductStatusInactive},
+		}, nil)
+		feedRepo.On("GetLinks", mock.Anything, int64(3)).Return([]domain.FeedProductLink{
+			{FeedID: 7, Key: "name:Changed", ProductID: 1},
+			{FeedID: 7, Key: "name:Same", ProductID: 2},
+			{FeedID: 7, Key: "name:Gone", ProductID: 3},
+		}, nil)
+		feedRepo.On("SaveLink", mock.Anything, &domain.FeedProductLink{FeedID: 7, Key: "name:New", ProductID: 5}).Return(nil)
+		productRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Name == "New" && p.StoreID == 3 && p.Unit == domain.UnitKilogram
+		})).Return(&domain.Product{ID: 5}, nil)
+		productRepo.On("Update", mock.Anything, int64(1), mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Amount == quantity.New(9)
+		})).Return(&domain.Product{ID: 1}, nil)
+		productRepo.On("Update", mock.Anything, int64(3), mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Status == domain.ProductStatusInactive
+		})).Return(&domain.Product{ID: 3}, nil)
+
+		uc := NewFeedUseCase(feedRepo, productRepo, NewProductUseCase(database.NopTxManager{}, productRepo, nil, nil, nil, clock.Real()), fetcher, nil, 0.5, clock.Real())
+		run, err := uc.RunFeed(ctx, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.FeedRunStatusSucceeded, run.Status)
+		assert.Equal(t, 1, run.Created)
+		assert.Equal(t, 1, run.Updated)
+		assert.Equal(t, 1, run.Deactivated)
+		assert.Equal(t, 1, run.Unchanged)
+		assert.Equal(t, 1, run.Failed)
+		assert.Len(t, run.Errors, 1)
+		assert.Empty(t, run.Duplicates, "linked rows are not duplicates")
+		assert.True(t, run.FinishedAt.Valid)
+
+		feedRepo.AssertExpectations(t)
+		productRepo.AssertExpectations(t)
+		fetcher.AssertExpectations(t)
+	})
+
+	t.Run("duplicates are matched by rule and resolved", func(t *testing.T) {
+		matching := *feed
+		matching.MatchBy = []string{domain.DuplicateMatchSKU, domain.DuplicateMatchFuzzyName}
+		matching.OnDuplicate = domain.DuplicateUpdate
+
+		feedRepo := &MockFeedRepository{}
+		productRepo := &MockProductRepository{}
+		fetcher := &MockFeedFetcher{}
+
+		feedRepo.On("GetByID", mock.Anything, int64(7)).Return(&matching, nil)
+		feedRepo.On("CreateRun", mock.Anything, mock.Anything).Return(
+			&domain.FeedRun{ID: 15, FeedID: 7, Status: domain.FeedRunStatusRunning}, nil)
+		feedRepo.On("MarkRun", mock.Anything, int64(7), mock.Anything).Return(nil)
+		feedRepo.On("FinishRun", mock.Anything, mock.Anything).Return(nil)
+
+		fetcher.On("Fetch", mock.Anything, &matching).Return([]domain.FeedItem{
+			{Name: "Rye bread 500 g", SKU: "RB-500", Amount: quantity.New(3), Price: 2.5},
+			{Name: "Whole milk 1 L", Amount: quantity.New(9), Price: 1.2, OnDuplicate: domain.DuplicateSkip},
+			{Name: "Chedar Cheese", Amount: quantity.New(4), Price: 6, OnDuplicate: domain.DuplicateCreate},
+			{Name: "Butter", Amount: quantity.New(1), Price: 2},
+			{Name: "Rye Bread 500g", SKU: "RB-501", Amount: quantity.New(1), Price: 2.5, OnDuplicate: "merge"},
+		}, nil)
+
+		productRepo.On("GetAllByStore", mock.Anything, int64(3)).Return([]*domain.Product{
+			{ID: 1, StoreID: 3, Name: "Rye Bread 500g", Amount: quantity.New(3), Unit: domain.UnitPiece, Price: 2.5, Status: domain.ProductStatusActive},
+			{ID: 2, StoreID: 3, Name: "Whole Milk 1L", Amount: quantity.New(5), Unit: domain.UnitPiece, Price: 1.2, Status: domain.ProductStatusActive},
+			{ID: 3, StoreID: 3, Name: "Butter", Amount: quantity.New(1), Unit: domain.UnitPiece, Price: 2, Status: domain.ProductStatusActive},
+			{ID: 4, StoreID: 3, Name: "Cheddar Cheese", Amount: quantity.New(4), Unit: domain.UnitPiece, Price: 6, Status: domain.ProductStatusActive},
+		}, nil)
+		feedRepo.On("GetLinks", mock.Anything, int64(3)).Return([]domain.FeedProductLink{
+			{FeedID: 8, Key: "sku:RB-500", ProductID: 1, SKU: "RB-500"},
+		}, nil)
+
+		productRepo.On("Update", mock.Anything, int64(1), mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Name == "Rye bread 500 g"
+		})).Return(&domain.Product{ID: 1}, nil)
+		productRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Name == "Chedar Cheese"
+		})).Return(&domain.Product{ID: 9}, nil)
+		productRepo.On("Update", mock.Anything, int64(4), mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Status == domain.ProductStatusInactive
+		})).Return(&domain.Product{ID: 4}, nil)
+		feedRepo.On("SaveLink", mock.Anything, &domain.FeedProductLink{FeedID: 7, Key: "sku:RB-500", ProductID: 1, SKU: "RB-500"}).Return(nil)
+		feedRepo.On("SaveLink", mock.Anything, &domain.FeedProductLink{FeedID: 7, Key: "name:Chedar Cheese", ProductID: 9}).Return(nil)
+		feedRepo.On("SaveLink", mock.Anything, &domain.FeedProductLink{FeedID: 7, Key: "name:Butter", ProductID: 3}).Return(nil)
+
+		uc := NewFeedUseCase(feedRepo, productRepo, NewProductUseCase(database.NopTxManager{}, productRepo, nil, nil, nil, clock.Real()), fetcher, nil, 0.5, clock.Real())
+		run, err := uc.RunFeed(ctx, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.FeedRunStatusSucceeded, run.Status)
+		assert.Equal(t, 1, run.Created)
+		assert.Equal(t, 1, run.Updated)
+		assert.Equal(t, 1, run.Unchanged)
+		assert.Equal(t, 1, run.Skipped)
+		assert.Equal(t, 1, run.Deactivated, "the product a row was created beside is not in the feed")
+		assert.Equal(t, 1, run.Failed, "an unknown on_duplicate fails its row")
+		assert.Equal(t, []domain.FeedDuplicate{
+			{Item: 1, Name: "Rye bread 500 g", ProductID: 1, MatchedBy: domain.DuplicateMatchSKU, Resolution: domain.DuplicateUpdate},
+			{Item: 2, Name: "Whole milk 1 L", ProductID: 2, MatchedBy: domain.DuplicateMatchFuzzyName, Resolution: domain.DuplicateSkip},
+			{Item: 3, Name: "Chedar Cheese", ProductID: 4, MatchedBy: domain.DuplicateMatchFuzzyName, Resolution: domain.DuplicateCreate},
+			{Item: 4, Name: "Butter", ProductID: 3, MatchedBy: domain.DuplicateMatchFuzzyName, Resolution: domain.DuplicateUpdate},
+		}, run.Duplicates)
+
+		feedRepo.AssertExpectations(t)
+		productRepo.AssertExpectations(t)
+	})
+
+	t.Run("imported items are moderated", func(t *testing.T) {
+		feedRepo := &MockFeedRepository{}
+		productRepo := &MockProductRepository{}
+		fetcher := &MockFeedFetcher{}
+
+		feedRepo.On("GetByID", mock.Anything, int64(7)).Return(feed, nil)
+		feedRepo.On("CreateRun", mock.Anything, mock.Anything).Return(
+			&domain.FeedRun{ID: 13, FeedID: 7, Status: domain.FeedRunStatusRunning}, nil)
+		feedRepo.On("MarkRun", mock.Anything, int64(7), mock.Anything).Return(nil)
+		feedRepo.On("FinishRun", mock.Anything, mock.Anything).Return(nil)
+		fetcher.On("Fetch", mock.Anything, feed).Return([]domain.FeedItem{
+			{Name: "Blocked", Amount: quantity.New(1), Price: 1},
+		}, nil)
+		productRepo.On("GetAllByStore", mock.Anything, int64(3)).Return([]*domain.Product{}, nil)
+		feedRepo.On("GetLinks", mock.Anything, int64(3)).Return([]domain.FeedProductLink{}, nil)
+
+		moderator := rejectingModerator{name: "Blocked"}
+		uc := NewFeedUseCase(feedRepo, productRepo, NewProductUseCase(database.NopTxManager{}, productRepo, moderator, nil, nil, clock.Real()), fetcher, nil, 0.5, clock.Real())
+		run, err := uc.RunFeed(ctx, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, run.Created)
+		assert.Equal(t, 1, run.Failed)
+		productRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("row images are queued for their products", func(t *testing.T) {
+		setup := func() (*MockFeedRepository, *MockProductRepository, *MockFeedFetcher) {
+			feedRepo := &MockFeedRepository{}
+			productRepo := &MockProductRepository{}
+			fetcher := &MockFeedFetcher{}
+
+			feedRepo.On("GetByID", mock.Anything, int64(7)).Return(feed, nil)
+			feedRepo.On("CreateRun", mock.Anything, mock.Anything).Return(
+				&domain.FeedRun{ID: 14, FeedID: 7, Status: domain.FeedRunStatusRunning}, nil)
+			feedRepo.On("MarkRun", mock.Anything, int64(7), mock.Anything).Return(nil)
+			feedRepo.On("FinishRun", mock.Anything, mock.Anything).Return(nil)
+			fetcher.On("Fetch", mock.Anything, feed).Return([]domain.FeedItem{
+				{Name: "Same", Amount: quantity.New(2), Price: 3, ImageURLs: []string{"https://cdn.example.com/a.png", "https://cdn.example.com/b.png"}},
+			}, nil)
+			productRepo.On("GetAllByStore", mock.Anything, int64(3)).Return([]*domain.Product{
+				{ID: 2, StoreID: 3, Name: "Same", Amount: quantity.New(2), Unit: domain.UnitPiece, Price: 3, Status: domain.ProductStatusActive},
+			}, nil)
+			feedRepo.On("GetLinks", mock.Anything, int64(3)).Return([]domain.FeedProductLink{
+				{FeedID: 7, Key: "name:Same", ProductID: 2},
+			}, nil)
+			return feedRepo, productRepo, fetcher
+		}
+
+		feedRepo, productRepo, fetcher := setup()
+		images := &MockFeedImageImporter{}
+		images.On("QueueImports", mock.Anything, int64(14), []domain.ImageImport{
+			{ProductID: 2, URL: "https://cdn.example.com/a.png"},
+			{ProductID: 2, URL: "https://cdn.example.com/b.png"},
+		}).Return(2, nil)
+
+		uc := NewFeedUseCase(feedRepo, productRepo, NewProductUseCase(database.NopTxManager{}, productRepo, nil, nil, nil, clock.Real()), fetcher, images, 0.5, clock.Real())
+		run, err := uc.RunFeed(ctx, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, run.Unchanged)
+		assert.Equal(t, 2, run.ImagesQueued)
+		assert.Empty(t, run.Errors)
+		images.AssertExpectations(t)
+
+		feedRepo, productRepo, fetcher = setup()
+		uc = NewFeedUseCase(feedRepo, productRepo, NewProductUseCase(database.NopTxManager{}, productRepo, nil, nil, nil, clock.Real()), fetcher, nil, 0.5, clock.Real())
+		run, err = uc.RunFeed(ctx, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, run.ImagesQueued)
+		assert.Equal(t, []string{"image_urls ignored: image import is not configured"}, run.Errors)
+	})
+
+	t.Run("empty or shrunk feeds are refused", func(t *testing.T) {
+		catalog := []*domain.Product{
+			{ID: 1, StoreID: 3, Name: "A", Amount: quantity.New(1), Unit: domain.UnitPiece, Price: 1, Status: domain.ProductStatusActive},
+			{ID: 2, StoreID: 3, Name: "B", Amount: quantity.New(1), Unit: domain.UnitPiece, Price: 1, Status: domain.ProductStatusActive},
+			{ID: 3, StoreID: 3, Name: "C", Amount: quantity.New(1), Unit: domain.UnitPiece, Price: 1, Status: domain.ProductStatusActive},
+		}
+
+		for name, items := range map[string][]domain.FeedItem{
+			"empty":  {},
+			"shrunk": {{Name: "A", Amount: quantity.New(1), Price: 1}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				feedRepo := &MockFeedRepository{}
+				productRepo := &MockProductRepository{}
+				fetcher := &MockFeedFetcher{}
+
+				feedRepo.On("GetByID", mock.Anything, int64(7)).Return(feed, nil)
+				feedRepo.On("CreateRun", mock.Anything, mock.Anything).Return(
+					&domain.FeedRun{ID: 14, FeedID: 7, Status: domain.FeedRunStatusRunning}, nil)
+				feedRepo.On("MarkRun", mock.Anything, int64(7), mock.Anything).Return(nil)
+				feedRepo.On("FinishRun", mock.Anything, mock.MatchedBy(func(r *domain.FeedRun) bool {
+					return r.Status == domain.FeedRunStatusFailed
+				})).Return(nil)
+				fetcher.On("Fetch", mock.Anything, feed).Return(items, nil)
+				productRepo.On("GetAllByStore", mock.Anything, int64(3)).Return(catalog, nil)
+				feedRepo.On("GetLinks", mock.Anything, int64(3)).Return([]domain.FeedProductLink{}, nil)
+
+				uc := NewFeedUseCase(feedRepo, productRepo, NewProductUseCase(database.NopTxManager{}, productRepo, nil, nil, nil, clock.Real()), fetcher, nil, 0.5, clock.Real())
+				run, err := uc.RunFeed(ctx, 7)
+
+				require.NoError(t, err)
+				assert.Equal(t, domain.FeedRunStatusFailed, run.Status)
+				assert.Equal(t, 0, run.Deactivated)
+				productRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+				feedRepo.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("fetch failure is recorded on the run", func(t *testing.T) {
+		feedRepo := &MockFeedRepository{}
+		fetcher := &MockFeedFetcher{}
+
+		feedRepo.On("GetByID", mock.Anything, int64(7)).Return(feed, nil)
+		feedRepo.On("CreateRun", mock.Anything, mock.Anything).Return(
+			&domain.FeedRun{ID: 12, FeedID: 7, Status: domain.FeedRunStatusRunning}, nil)
+		feedRepo.On("MarkRun", mock.Anything, int64(7), mock.Anything).Return(nil)
+		feedRepo.On("FinishRun", mock.Anything, mock.MatchedBy(func(r *domain.FeedRun) bool {
+			return r.Status == domain.FeedRunStatusFailed
+		})).Return(nil)
+		fetcher.On("Fetch", mock.Anything, feed).Return(nil, errors.New("connection refused"))
+
+		uc := NewFeedUseCase(feedRepo, &MockProductRepository{}, nil, fetcher, nil, 0.5, clock.Real())
+		run, err := uc.RunFeed(ctx, 7)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.FeedRunStatusFailed, run.Status)
+		assert.Len(t, run.Errors, 1)
+
+		feedRepo.AssertExpectations(t)
+		fetcher.AssertExpectations(t)
+	})
+
+	t.Run("feed not found", func(t *testing.T) {
+		feedRepo := &MockFeedRepository{}
+		feedRepo.On("GetByID", mock.Anything, int64(99)).Return(nil, domain.ErrFeedNotFound)
+
+		uc := NewFeedUseCase(feedRepo, &MockProductRepository{}, nil, &MockFeedFetcher{}, nil, 0.5, clock.Real())
+		_, err := uc.RunFeed(ctx, 99)
+
+		assert.ErrorIs(t, err, domain.ErrFeedNotFound)
+		feedRepo.AssertExpectations(t)
+	})
+}
+
+func TestFeedUseCase_RunDueFeeds(t *testing.T) {
+	ctx := context.Background()
+
+	due := &domain.Feed{ID: 1, StoreID: 1, Enabled: true, IntervalSeconds: 3600,
+		LastRunAt: sql.NullTime{Time: time.Now().Add(-2 * time.Hour), Valid: true}}
+	notDue := &domain.Feed{ID: 2, StoreID: 1, Enabled: true, IntervalSeconds: 3600,
+		LastRunAt: sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true}}
+
+	feedRepo := &MockFeedRepository{}
+	productRepo := &MockProductRepository{}
+	fetcher := &MockFeedFetcher{}
+
+	feedRepo.On("GetEnabled", mock.Anything).Return([]*domain.Feed{due, notDue}, nil)
+	feedRepo.On("GetByID", mock.Anything, int64(1)).Return(due, nil)
+	feedRepo.On("CreateRun", mock.Anything, mock.Anything).Return(&domain.FeedRun{ID: 1, FeedID: 1}, nil)
+	feedRepo.On("MarkRun", mock.Anything, int64(1), mock.Anything).Return(nil)
+	feedRepo.On("FinishRun", mock.Anything, mock.Anything).Return(nil)
+	fetcher.On("Fetch", mock.Anything, due).Return([]domain.FeedItem{}, nil)
+	productRepo.On("GetAllByStore", mock.Anything, int64(1)).Return([]*domain.Product{}, nil)
+	feedRepo.On("GetLinks", mock.Anything, int64(1)).Return([]domain.FeedProductLink{}, nil)
+
+	uc := NewFeedUseCase(feedRepo, productRepo, NewProductUseCase(database.NopTxManager{}, productRepo, nil, nil, nil, clock.Real()), fetcher, nil, 0.5, clock.Real())
+	err := uc.RunDueFeeds(ctx)
+
+	assert.NoError(t, err)
+	feedRepo.AssertNotCalled(t, "GetByID", mock.Anything, int64(2))
+	feedRepo.AssertExpectations(t)
+	fetcher.AssertExpectations(t)
+}
+
+// rejectingModerator rejects products with one name.
+type rejectingModerator struct {
+	name string
+}
+
+func (m rejectingModerator) Screen(ctx context.Context, product *domain.Product) error {
+	if product.Name == m.name {
+		return domain.ErrContentRejected
+	}
+	return nil
+}
+
+func (rejectingModerator) Submit(product *domain.Product) {}
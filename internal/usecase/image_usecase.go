@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// ImageUseCase attaches images to products. Images a feed run links to
+// are downloaded in the background, one run's images at a time, and
+// stored once per content hash however many products use them.
+type ImageUseCase struct {
+	repo          ImageRepository
+	productRepo   ProductRepository
+	storage       ImageStorage
+	downloader    ImageDownloader
+	prefix        string
+	importTimeout time.Duration
+	clock         clock.Clock
+
+	wg sync.WaitGroup
+}
+
+// NewImageUseCase keeps image objects in storage under prefix. A run's
+// images that are not attached within importTimeout are reported as
+// failed.
+func NewImageUseCase(repo ImageRepository, productRepo ProductRepository, storage ImageStorage, downloader ImageDownloader, prefix string, importTimeout time.Duration, clk clock.Clock) *ImageUseCase {
+	return &ImageUseCase{
+		repo:          repo,
+		productRepo:   productRepo,
+		storage:       storage,
+		downloader:    downloader,
+		prefix:        prefix,
+		importTimeout: importTimeout,
+		clock:         clk,
+	}
+}
+
+func (uc *ImageUseCase) GetProductImages(ctx context.Context, productID int64) ([]*domain.ProductImage, error) {
+	if productID <= 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", domain.ErrInvalidProduct)
+	}
+
+	if _, err := uc.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	images, err := uc.repo.GetByProduct(ctx, productID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get product images from repository")
+		return nil, fmt.Errorf("failed to get product images: %w", err)
+	}
+
+	return images, nil
+}
+
+// QueueImports records the run's image URLs and downloads them in the
+// background, returning how many were queued. URLs a product was already
+// given are left out. Invalid URLs, and those past a product's first
+// MaxImagesPerItem, are recorded as failed straight away.
+func (uc *ImageUseCase) QueueImports(ctx context.Context, runID int64, requested []domain.ImageImport) (int, error) {
+	productIDs := make([]int64, 0, len(requested))
+	seen := make(map[int64]map[string]bool)
+	for _, req := range requested {
+		if seen[req.ProductID] == nil {
+			seen[req.ProductID] = make(map[string]bool)
+			productIDs = append(productIDs, req.ProductID)
+		}
+	}
+
+	imported, err := uc.repo.GetImportedURLs(ctx, productIDs)
+	if err != nil {
+		return 0, err
+	}
+	for productID, urls := range imported {
+		for _, url := range urls {
+			seen[productID][url] = true
+		}
+	}
+
+	now := uc.clock.Now()
+	counts := make(map[int64]int)
+	var imports, pending []*domain.ImageImport
+	for _, req := range requested {
+		if seen[req.ProductID][req.URL] {
+			continue
+		}
+		seen[req.ProductID][req.URL] = true
+
+		imp := &domain.ImageImport{
+			RunID:     runID,
+			ProductID: req.ProductID,
+			URL:       req.URL,
+			Status:    domain.ImageImportPending,
+		}
+		counts[req.ProductID]++
+		if err := domain.ValidateImageURL(req.URL); err != nil {
+			imp.Error = err.Error()
+		} else if counts[req.ProductID] > domain.MaxImagesPerItem {
+			imp.Error = fmt.Sprintf("only %d images are imported per item", domain.MaxImagesPerItem)
+		}
+		if imp.Error != "" {
+			imp.Status = domain.ImageImportFailed
+			imp.FinishedAt = sql.NullTime{Time: now, Valid: true}
+		}
+
+		imports = append(imports, imp)
+		if imp.Status == domain.ImageImportPending {
+			pending = append(pending, imp)
+		}
+	}
+
+	if len(imports) == 0 {
+		return 0, nil
+	}
+	if err := uc.repo.CreateImports(ctx, imports); err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	uc.wg.Add(1)
+	go func() {
+		defer uc.wg.Done()
+		uc.runImports(ctx, runID, pending)
+	}()
+
+	return len(pending), nil
+}
+
+// runImports outlives the caller's ctx, but keeps its values, such as the
+// request ID its log lines carry.
+func (uc *ImageUseCase) runImports(ctx context.Context, runID int64, imports []*domain.ImageImport) {
+	logger := logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action": "import_images",
+		"run_id": runID,
+	})
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), uc.importTimeout)
+	defer cancel()
+
+	counts := make(map[string]int)
+	for _, imp := range imports {
+		if err := ctx.Err(); err != nil {
+			imp.Status = domain.ImageImportFailed
+			imp.Error = "image import timed out"
+		} else {
+			uc.importImage(ctx, imp)
+		}
+		imp.FinishedAt = sql.NullTime{Time: uc.clock.Now(), Valid: true}
+		counts[imp.Status]++
+
+		// The import's own deadline may have passed; its outcome is still
+		// recorded.
+		finishCtx, finishCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := uc.repo.FinishImport(finishCtx, imp); err != nil {
+			logger.WithError(err).WithField("url", imp.URL).Error("Failed to record image import outcome")
+		}
+		finishCancel()
+	}
+
+	logger.WithFields(logrus.Fields{
+		"attached":  counts[domain.ImageImportAttached],
+		"duplicate": counts[domain.ImageImportDuplicate],
+		"failed":    counts[domain.ImageImportFailed],
+	}).Info("Image import finished")
+}
+
+// importImage downloads the image, stores it unless its content is
+// already stored, and attaches it to the product.
+func (uc *ImageUseCase) importImage(ctx context.Context, imp *domain.ImageImport) {
+	fail := func(err error) {
+		imp.Status = domain.ImageImportFailed
+		imp.Error = err.Error()
+	}
+
+	downloaded, err := uc.downloader.Download(ctx, imp.URL)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	sum := sha256.Sum256(downloaded.Data)
+	hash := hex.EncodeToString(sum[:])
+	extension, _ := domain.ImageExtension(downloaded.ContentType)
+	key := uc.prefix + hash + extension
+
+	stored, err := uc.repo.HasContent(ctx, hash)
+	if err != nil {
+		fail(err)
+		return
+	}
+	if !stored {
+		if err := uc.storage.Put(ctx, key, bytes.NewReader(downloaded.Data)); err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	image, attached, err := uc.repo.Attach(ctx, &domain.ProductImage{
+		ProductID:   imp.ProductID,
+		ContentHash: hash,
+		ContentType: downloaded.ContentType,
+		Size:        int64(len(downloaded.Data)),
+		ObjectKey:   key,
+		SourceURL:   imp.URL,
+	})
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	imp.Status = domain.ImageImportAttached
+	if !attached {
+		imp.Status = domain.ImageImportDuplicate
+	}
+	imp.ImageID = sql.NullInt64{Int64: image.ID, Valid: true}
+}
+
+func (uc *ImageUseCase) GetImports(ctx context.Context, runID int64) ([]*domain.ImageImport, error) {
+	imports, err := uc.repo.GetImports(ctx, runID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get image imports from repository")
+		return nil, fmt.Errorf("failed to get image imports: %w", err)
+	}
+	return imports, nil
+}
+
+// Wait blocks until queued image imports finish.
+func (uc *ImageUseCase) Wait() {
+	uc.wg.Wait()
+}
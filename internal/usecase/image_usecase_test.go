@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockImageRepository struct {
+	mock.Mock
+}
+
+func (m *MockImageRepository) GetByProduct(ctx context.Context, productID int64) ([]*domain.ProductImage, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]*domain.ProductImage), args.Error(1)
+}
+
+func (m *MockImageRepository) HasContent(ctx context.Context, contentHash string) (bool, error) {
+	args := m.Called(ctx, contentHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockImageRepository) Attach(ctx context.Context, image *domain.ProductImage) (*domain.ProductImage, bool, error) {
+	args := m.Called(ctx, image)
+	if args.Get(0) == nil {
+		return nil, false, args.Error(2)
+	}
+	return args.Get(0).(*domain.ProductImage), args.Bool(1), args.Error(2)
+}
+
+func (m *MockImageRepository) GetImportedURLs(ctx context.Context, productIDs []int64) (map[int64][]string, error) {
+	args := m.Called(ctx, productIDs)
+	return args.Get(0).(map[int64][]string), args.Error(1)
+}
+
+func (m *MockImageRepository) CreateImports(ctx context.Context, imports []*domain.ImageImport) error {
+	args := m.Called(ctx, imports)
+	return args.Error(0)
+}
+
+func (m *MockImageRepository) FinishImport(ctx context.Context, imp *domain.ImageImport) error {
+	args := m.Called(ctx, imp)
+	return args.Error(0)
+}
+
+func (m *MockImageRepository) GetImports(ctx context.Context, runID int64) ([]*domain.ImageImport, error) {
+	args := m.Called(ctx, runID)
+	return args.Get(0).([]*domain.ImageImport), args.Error(1)
+}
+
+type MockImageDownloader struct {
+	mock.Mock
+}
+
+func (m *MockImageDownloader) Download(ctx context.Context, url string) (*domain.DownloadedImage, error) {
+	args := m.Called(ctx, url)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.DownloadedImage), args.Error(1)
+}
+
+func pngImage(content string) *domain.DownloadedImage {
+	return &domain.DownloadedImage{Data: []byte("\x89PNG\r\n\x1a\n" + content), ContentType: "image/png"}
+}
+
+func imageHash(image *domain.DownloadedImage) string {
+	sum := sha256.Sum256(image.Data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestImageUseCase_QueueImports(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("imports new urls in the background", func(t *testing.T) {
+		repo := &MockImageRepository{}
+		downloader := &MockImageDownloader{}
+		storage := &memorySnapshotStorage{}
+
+		fresh, shared := pngImage("fresh"), pngImage("shared")
+		repo.On("GetImportedURLs", mock.Anything, []int64{2}).Return(map[int64][]string{
+			2: {"https://cdn.example.com/old.jpg"},
+		}, nil)
+		var created []domain.ImageImport
+		repo.On("CreateImports", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			for i, imp := range args.Get(1).([]*domain.ImageImport) {
+				imp.ID = int64(i + 1)
+				created = append(created, *imp)
+			}
+		}).Return(nil)
+		downloader.On("Download", mock.Anything, "https://cdn.example.com/fresh.png").Return(fresh, nil)
+		downloader.On("Download", mock.Anything, "https://cdn.example.com/shared.png").Return(shared, nil)
+		downloader.On("Download", mock.Anything, "https://cdn.example.com/broken.png").Return(nil, domain.ErrUnsupportedImage)
+		repo.On("HasContent", mock.Anything, imageHash(fresh)).Return(false, nil)
+		repo.On("HasContent", mock.Anything, imageHash(shared)).Return(true, nil)
+		repo.On("Attach", mock.Anything, mock.MatchedBy(func(image *domain.ProductImage) bool {
+			return image.ContentHash == imageHash(fresh) && image.ObjectKey == "images/"+imageHash(fresh)+".png"
+		})).Return(&domain.ProductImage{ID: 8}, true, nil)
+		repo.On("Attach", mock.Anything, mock.MatchedBy(func(image *domain.ProductImage) bool {
+			return image.ContentHash == imageHash(shared)
+		})).Return(&domain.ProductImage{ID: 4}, false, nil)
+		finished := make(map[string]domain.ImageImport)
+		repo.On("FinishImport", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			imp := args.Get(1).(*domain.ImageImport)
+			finished[imp.URL] = *imp
+		}).Return(nil)
+
+		uc := NewImageUseCase(repo, &MockProductRepository{}, storage, downloader, "images/", time.Minute, clock.Real())
+		queued, err := uc.QueueImports(ctx, 11, []domain.ImageImport{
+			{ProductID: 2, URL: "https://cdn.example.com/old.jpg"},
+			{ProductID: 2, URL: "https://cdn.example.com/fresh.png"},
+			{ProductID: 2, URL: "https://cdn.example.com/fresh.png"},
+			{ProductID: 2, URL: "ftp://cdn.example.com/x.png"},
+			{ProductID: 2, URL: "https://cdn.example.com/shared.png"},
+			{ProductID: 2, URL: "https://cdn.example.com/broken.png"},
+		})
+		require.NoError(t, err)
+		uc.Wait()
+
+		assert.Equal(t, 3, queued)
+		require.Len(t, created, 4, "imported and repeated urls are left out")
+		assert.Equal(t, domain.ImageImportFailed, created[1].Status)
+		assert.True(t, created[1].FinishedAt.Valid)
+
+		assert.Equal(t, domain.ImageImportAttached, finished["https://cdn.example.com/fresh.png"].Status)
+		assert.Equal(t, int64(8), finished["https://cdn.example.com/fresh.png"].ImageID.Int64)
+		assert.Equal(t, domain.ImageImportDuplicate, finished["https://cdn.example.com/shared.png"].Status)
+		assert.Equal(t, int64(4), finished["https://cdn.example.com/shared.png"].ImageID.Int64)
+		assert.Equal(t, domain.ImageImportFailed, finished["https://cdn.example.com/broken.png"].Status)
+		assert.Equal(t, domain.ErrUnsupportedImage.Error(), finished["https://cdn.example.com/broken.png"].Error)
+
+		assert.Len(t, storage.objects, 1, "stored content is not uploaded again")
+		assert.Contains(t, storage.objects, "images/"+imageHash(fresh)+".png")
+		repo.AssertExpectations(t)
+		downloader.AssertExpectations(t)
+	})
+
+	t.Run("urls past the per-item limit fail", func(t *testing.T) {
+		repo := &MockImageRepository{}
+		downloader := &MockImageDownloader{}
+
+		var requested []domain.ImageImport
+		for i := 0; i <= domain.MaxImagesPerItem; i++ {
+			requested = append(requested, domain.ImageImport{ProductID: 5, URL: fmt.Sprintf("https://cdn.example.com/%d.png", i)})
+		}
+		repo.On("GetImportedURLs", mock.Anything, []int64{5}).Return(map[int64][]string{}, nil)
+		var created []*domain.ImageImport
+		repo.On("CreateImports", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			created = args.Get(1).([]*domain.ImageImport)
+		}).Return(nil)
+		downloader.On("Download", mock.Anything, mock.Anything).Return(nil, domain.ErrImageTooLarge)
+		repo.On("FinishImport", mock.Anything, mock.Anything).Return(nil)
+
+		uc := NewImageUseCase(repo, &MockProductRepository{}, &memorySnapshotStorage{}, downloader, "images/", time.Minute, clock.Real())
+		queued, err := uc.QueueImports(ctx, 12, requested)
+		require.NoError(t, err)
+		uc.Wait()
+
+		assert.Equal(t, domain.MaxImagesPerItem, queued)
+		require.Len(t, created, domain.MaxImagesPerItem+1)
+		assert.Equal(t, domain.ImageImportFailed, created[domain.MaxImagesPerItem].Status)
+		assert.Contains(t, created[domain.MaxImagesPerItem].Error, "images are imported per item")
+		downloader.AssertNumberOfCalls(t, "Download", domain.MaxImagesPerItem)
+	})
+}
+
+func TestImageUseCase_GetProductImages(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown product", func(t *testing.T) {
+		productRepo := &MockProductRepository{}
+		productRepo.On("GetByID", mock.Anything, int64(9)).Return(nil, domain.ErrProductNotFound)
+
+		uc := NewImageUseCase(&MockImageRepository{}, productRepo, nil, nil, "images/", time.Minute, clock.Real())
+		_, err := uc.GetProductImages(ctx, 9)
+
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	})
+
+	t.Run("lists the product's images", func(t *testing.T) {
+		repo := &MockImageRepository{}
+		productRepo := &MockProductRepository{}
+		productRepo.On("GetByID", mock.Anything, int64(2)).Return(&domain.Product{ID: 2}, nil)
+		repo.On("GetByProduct", mock.Anything, int64(2)).Return([]*domain.ProductImage{{ID: 8, ProductID: 2}}, nil)
+
+		uc := NewImageUseCase(repo, productRepo, nil, nil, "images/", time.Minute, clock.Real())
+		images, err := uc.GetProductImages(ctx, 2)
+
+		require.NoError(t, err)
+		assert.Len(t, images, 1)
+	})
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/authtoken"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonationUseCase_Impersonate(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	tokens := authtoken.NewIssuer([]byte("0123456789abcdef0123456789abcdef"), "product-service", 15*time.Minute, 24*time.Hour, fake)
+	request := domain.ImpersonationRequest{
+		UserID:   42,
+		Admin:    "workload:support-console",
+		ClientIP: "10.0.0.1",
+		Reason:   "ticket 1234: cannot see their feeds",
+	}
+
+	setup := func() (*ImpersonationUseCase, *MockUserRepository, *recordingAudit) {
+		repo := new(MockUserRepository)
+		audit := &recordingAudit{}
+		return NewImpersonationUseCase(repo, tokens, audit, 15*time.Minute, fake), repo, audit
+	}
+
+	t.Run("issues a token tagged with the admin", func(t *testing.T) {
+		uc, repo, audit := setup()
+		repo.On("GetByID", ctx, int64(42)).Return(&domain.User{ID: 42, Email: "ada@example.com"}, nil)
+
+		req := request
+		req.TTL = 5 * time.Minute
+		impersonation, err := uc.Impersonate(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, fake.Now().Add(5*time.Minute), impersonation.ExpiresAt)
+		assert.Equal(t, "workload:support-console", impersonation.ImpersonatedBy)
+
+		claims, err := tokens.Verify(impersonation.AccessToken, authtoken.KindAccess)
+		require.NoError(t, err)
+		assert.Equal(t, "42", claims.Subject)
+		assert.Equal(t, "workload:support-console", claims.ImpersonatedBy())
+
+		require.Len(t, audit.entries, 1)
+		entry := audit.entries[0]
+		assert.Equal(t, domain.AuditEventImpersonationStarted, entry.Event)
+		assert.Equal(t, "workload:support-console", entry.Actor)
+		assert.Equal(t, "10.0.0.1", entry.ClientIP)
+		assert.Equal(t, "user:42 until 2026-03-01T12:05:00Z: ticket 1234: cannot see their feeds", entry.Detail)
+	})
+
+	t.Run("defaults to the longest lifetime", func(t *testing.T) {
+		uc, repo, _ := setup()
+		repo.On("GetByID", ctx, int64(42)).Return(&domain.User{ID: 42, Email: "ada@example.com"}, nil)
+
+		impersonation, err := uc.Impersonate(ctx, request)
+		require.NoError(t, err)
+		assert.Equal(t, fake.Now().Add(15*time.Minute), impersonation.ExpiresAt)
+	})
+
+	invalid := []struct {
+		name   string
+		modify func(req *domain.ImpersonationRequest)
+	}{
+		{name: "no reason", modify: func(req *domain.ImpersonationRequest) { req.Reason = " " }},
+		{name: "long reason", modify: func(req *domain.ImpersonationRequest) {
+			req.Reason = strings.Repeat("a", domain.MaxImpersonationReasonLength+1)
+		}},
+		{name: "too long", modify: func(req *domain.ImpersonationRequest) { req.TTL = 16 * time.Minute }},
+		{name: "negative lifetime", modify: func(req *domain.ImpersonationRequest) { req.TTL = -time.Minute }},
+		{name: "unknown admin", modify: func(req *domain.ImpersonationRequest) { req.Admin = "" }},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _, audit := setup()
+			req := request
+			tt.modify(&req)
+			_, err := uc.Impersonate(ctx, req)
+			assert.ErrorIs(t, err, domain.ErrInvalidImpersonation)
+			assert.Empty(t, audit.entries)
+		})
+	}
+
+	t.Run("unknown user", func(t *testing.T) {
+		uc, repo, audit := setup()
+		repo.On("GetByID", ctx, int64(42)).Return(nil, domain.ErrUserNotFound)
+
+		_, err := uc.Impersonate(ctx, request)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		assert.Empty(t, audit.entries)
+	})
+
+	t.Run("refused when the audit log is unavailable", func(t *testing.T) {
+		uc, repo, audit := setup()
+		repo.On("GetByID", ctx, int64(42)).Return(&domain.User{ID: 42, Email: "ada@example.com"}, nil)
+		audit.err = errors.New("database error")
+
+		impersonation, err := uc.Impersonate(ctx, request)
+		assert.Error(t, err)
+		assert.Nil(t, impersonation)
+	})
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// ImportMappingUseCase manages the stores' import mappings, which CSV
+// feeds are read with.
+type ImportMappingUseCase struct {
+	mappingRepo ImportMappingRepository
+}
+
+func NewImportMappingUseCase(mappingRepo ImportMappingRepository) *ImportMappingUseCase {
+	return &ImportMappingUseCase{
+		mappingRepo: mappingRepo,
+	}
+}
+
+func (uc *ImportMappingUseCase) CreateMapping(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "create_import_mapping",
+		"store_id": mapping.StoreID,
+		"name":     mapping.Name,
+	}).Info("Creating import mapping")
+
+	if err := mapping.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Import mapping validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidImportMapping, err)
+	}
+
+	created, err := uc.mappingRepo.Create(ctx, mapping)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to create import mapping in repository")
+		return nil, fmt.Errorf("failed to create import mapping: %w", err)
+	}
+
+	return created, nil
+}
+
+func (uc *ImportMappingUseCase) GetMapping(ctx context.Context, storeID, id int64) (*domain.ImportMapping, error) {
+	if storeID <= 0 || id <= 0 {
+		return nil, fmt.Errorf("%w: invalid store or mapping ID", domain.ErrInvalidImportMapping)
+	}
+
+	mapping, err := uc.mappingRepo.GetByID(ctx, storeID, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get import mapping from repository")
+		return nil, err
+	}
+
+	return mapping, nil
+}
+
+func (uc *ImportMappingUseCase) GetMappings(ctx context.Context, storeID int64) ([]*domain.ImportMapping, error) {
+	if storeID <= 0 {
+		return nil, fmt.Errorf("%w: invalid store ID", domain.ErrInvalidImportMapping)
+	}
+
+	mappings, err := uc.mappingRepo.GetAll(ctx, storeID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get import mappings from repository")
+		return nil, fmt.Errorf("failed to get import mappings: %w", err)
+	}
+
+	return mappings, nil
+}
+
+func (uc *ImportMappingUseCase) UpdateMapping(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error) {
+	if mapping.ID <= 0 {
+		return nil, fmt.Errorf("%w: invalid mapping ID", domain.ErrInvalidImportMapping)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":     "update_import_mapping",
+		"store_id":   mapping.StoreID,
+		"mapping_id": mapping.ID,
+	}).Info("Updating import mapping")
+
+	if err := mapping.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Import mapping validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidImportMapping, err)
+	}
+
+	updated, err := uc.mappingRepo.Update(ctx, mapping)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to update import mapping in repository")
+		return nil, fmt.Errorf("failed to update import mapping: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (uc *ImportMappingUseCase) DeleteMapping(ctx context.Context, storeID, id int64) error {
+	if storeID <= 0 || id <= 0 {
+		return fmt.Errorf("%w: invalid store or mapping ID", domain.ErrInvalidImportMapping)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":     "delete_import_mapping",
+		"store_id":   storeID,
+		"mapping_id": id,
+	}).Info("Deleting import mapping")
+
+	if err := uc.mappingRepo.Delete(ctx, storeID, id); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to delete import mapping from repository")
+		return err
+	}
+
+	return nil
+}
+
+// TestMapping reads up to rows rows of a sample CSV with the mapping, so
+// a supplier's file can be checked before a feed imports it. Rows that
+// cannot be read are returned with their error instead of failing the
+// preview; a header the mapping does not fit fails it.
+func (uc *ImportMappingUseCase) TestMapping(ctx context.Context, storeID, id int64, sample io.Reader, rows int) ([]domain.ImportPreviewRow, error) {
+	if rows <= 0 {
+		rows = 10
+	}
+	if rows > domain.MaxImportPreviewRows {
+		rows = domain.MaxImportPreviewRows
+	}
+
+	mapping, err := uc.GetMapping(ctx, storeID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(sample)
+	reader.TrimLeadingSpace = true
+	// Rows may be ragged; the mapping reads missing cells as empty.
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return []domain.ImportPreviewRow{}, nil
+		}
+		return nil, fmt.Errorf("%w: failed to read csv header: %s", domain.ErrInvalidImportMapping, err.Error())
+	}
+
+	bound, err := mapping.Bind(header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidImportMapping, err.Error())
+	}
+
+	preview := []domain.ImportPreviewRow{}
+	for line := 2; len(preview) < rows; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		row := domain.ImportPreviewRow{Line: line}
+		if err != nil {
+			row.Error = err.Error()
+		} else if row.Item, err = bound.Item(record); err != nil {
+			row.Error = err.Error()
+		}
+		preview = append(preview, row)
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockImportMappingRepository struct {
+	mock.Mock
+}
+
+func (m *MockImportMappingRepository) Create(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error) {
+	args := m.Called(ctx, mapping)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImportMapping), args.Error(1)
+}
+
+func (m *MockImportMappingRepository) GetByID(ctx context.Context, storeID, id int64) (*domain.ImportMapping, error) {
+	args := m.Called(ctx, storeID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImportMapping), args.Error(1)
+}
+
+func (m *MockImportMappingRepository) GetAll(ctx context.Context, storeID int64) ([]*domain.ImportMapping, error) {
+	args := m.Called(ctx, storeID)
+	return args.Get(0).([]*domain.ImportMapping), args.Error(1)
+}
+
+func (m *MockImportMappingRepository) Update(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error) {
+	args := m.Called(ctx, mapping)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ImportMapping), args.Error(1)
+}
+
+func (m *MockImportMappingRepository) Delete(ctx context.Context, storeID, id int64) error {
+	args := m.Called(ctx, storeID, id)
+	return args.Error(0)
+}
+
+func supplierMapping() *domain.ImportMapping {
+	return &domain.ImportMapping{
+		ID:      2,
+		StoreID: 3,
+		Name:    "Acme",
+		Columns: []domain.ColumnMapping{
+			{Field: domain.ImportFieldName, Column: "Product Title"},
+			{Field: domain.ImportFieldPrice, Column: "PriceCents", Transform: domain.ImportTransformCents},
+			{Field: domain.ImportFieldAmount, Column: "Qty", Default: "0"},
+			{Field: domain.ImportFieldUnit, Column: "UOM", Transform: domain.ImportTransformLowercase, Default: "piece"},
+		},
+	}
+}
+
+func TestImportMappingUseCase_CreateMapping(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		mapping func(*domain.ImportMapping)
+		mockFn  func(*MockImportMappingRepository)
+		wantErr error
+	}{
+		{
+			name:    "valid mapping",
+			mapping: func(m *domain.ImportMapping) {},
+			mockFn: func(m *MockImportMappingRepository) {
+				m.On("Create", mock.Anything, mock.Anything).Return(supplierMapping(), nil)
+			},
+		},
+		{
+			name: "price not mapped",
+			mapping: func(m *domain.ImportMapping) {
+				m.Columns = m.Columns[:1]
+			},
+			mockFn:  func(m *MockImportMappingRepository) {},
+			wantErr: domain.ErrInvalidImportMapping,
+		},
+		{
+			name: "field mapped twice",
+			mapping: func(m *domain.ImportMapping) {
+				m.Columns = append(m.Columns, domain.ColumnMapping{Field: domain.ImportFieldName, Column: "Title"})
+			},
+			mockFn:  func(m *MockImportMappingRepository) {},
+			wantErr: domain.ErrInvalidImportMapping,
+		},
+		{
+			name: "cents on a text field",
+			mapping: func(m *domain.ImportMapping) {
+				m.Columns[0].Transform = domain.ImportTransformCents
+			},
+			mockFn:  func(m *MockImportMappingRepository) {},
+			wantErr: domain.ErrInvalidImportMapping,
+		},
+		{
+			name: "default that does not parse",
+			mapping: func(m *domain.ImportMapping) {
+				m.Columns[2].Default = "some"
+			},
+			mockFn:  func(m *MockImportMappingRepository) {},
+			wantErr: domain.ErrInvalidImportMapping,
+		},
+		{
+			name:    "name taken",
+			mapping: func(m *domain.ImportMapping) {},
+			mockFn: func(m *MockImportMappingRepository) {
+				m.On("Create", mock.Anything, mock.Anything).Return(nil, domain.ErrImportMappingExists)
+			},
+			wantErr: domain.ErrImportMappingExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &MockImportMappingRepository{}
+			tt.mockFn(mockRepo)
+			uc := NewImportMappingUseCase(mockRepo)
+
+			mapping := supplierMapping()
+			mapping.ID = 0
+			tt.mapping(mapping)
+
+			_, err := uc.CreateMapping(ctx, mapping)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImportMappingUseCase_TestMapping(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("previews the first rows", func(t *testing.T) {
+		mockRepo := &MockImportMappingRepository{}
+		mockRepo.On("GetByID", mock.Anything, int64(3), int64(2)).Return(supplierMapping(), nil)
+		uc := NewImportMappingUseCase(mockRepo)
+
+		sample := strings.Join([]string{
+			"SKU,Product Title,PriceCents,Qty,UOM",
+			"A1,Rye Bread,349,12,PIECE",
+			"A2,Flour,1999,,",
+			"A3,Butter,12.50,4,piece",
+			"A4,Milk,129,6,liter",
+		}, "\n")
+
+		rows, err := uc.TestMapping(ctx, 3, 2, strings.NewReader(sample), 3)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		assert.Equal(t, 2, rows[0].Line)
+		assert.Equal(t, domain.FeedItem{Name: "Rye Bread", Price: 3.49, Amount: quantity.New(12), Unit: "piece"}, rows[0].Item)
+		assert.Empty(t, rows[0].Error)
+
+		assert.Equal(t, domain.FeedItem{Name: "Flour", Price: 19.99, Unit: "piece"}, rows[1].Item)
+
+		assert.Equal(t, 4, rows[2].Line)
+		assert.Contains(t, rows[2].Error, "invalid price in cents")
+	})
+
+	t.Run("header without a mapped column", func(t *testing.T) {
+		mockRepo := &MockImportMappingRepository{}
+		mockRepo.On("GetByID", mock.Anything, int64(3), int64(2)).Return(supplierMapping(), nil)
+		uc := NewImportMappingUseCase(mockRepo)
+
+		_, err := uc.TestMapping(ctx, 3, 2, strings.NewReader("name,price\nRye,3.49\n"), 10)
+		assert.ErrorIs(t, err, domain.ErrInvalidImportMapping)
+	})
+
+	t.Run("unknown mapping", func(t *testing.T) {
+		mockRepo := &MockImportMappingRepository{}
+		mockRepo.On("GetByID", mock.Anything, int64(3), int64(2)).Return(nil, domain.ErrImportMappingNotFound)
+		uc := NewImportMappingUseCase(mockRepo)
+
+		_, err := uc.TestMapping(ctx, 3, 2, strings.NewReader(""), 10)
+		assert.ErrorIs(t, err, domain.ErrImportMappingNotFound)
+	})
+}
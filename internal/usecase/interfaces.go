@@ -2,22 +2,529 @@ package usecase
 
 import (
 	"context"
+	"io"
+	"time"
 
+	"backend-context-engineering-template/internal/connectors"
 	"backend-context-engineering-template/internal/domain"
 )
 
 type ProductRepository interface {
 	Create(ctx context.Context, product *domain.Product) (*domain.Product, error)
+	// CreateMany creates the products in one statement and returns them in
+	// the same order.
+	CreateMany(ctx context.Context, products []*domain.Product) ([]*domain.Product, error)
 	GetByID(ctx context.Context, id int64) (*domain.Product, error)
-	GetAll(ctx context.Context, limit, offset int) ([]*domain.Product, error)
+	// GetAll and GetByAvailability list only products listed at now:
+	// published and not drafts.
+	GetAll(ctx context.Context, filter domain.ProductFilter, now time.Time, limit, offset int) ([]*domain.Product, error)
+	GetByAvailability(ctx context.Context, availability string, now time.Time, limit, offset int) ([]*domain.Product, error)
+	GetAfterID(ctx context.Context, afterID int64, limit int) ([]*domain.Product, error)
+	GetAllByStore(ctx context.Context, storeID int64) ([]*domain.Product, error)
 	Update(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error)
+	// Patch writes only the fields the patch sets.
+	Patch(ctx context.Context, id int64, patch *domain.ProductPatch) (*domain.Product, error)
 	Delete(ctx context.Context, id int64) error
+	GetByModerationStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Product, error)
+	UpdateModeration(ctx context.Context, id int64, result domain.ModerationResult) (*domain.Product, error)
 }
 
 type ProductUseCaseInterface interface {
 	CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
 	GetProduct(ctx context.Context, id int64) (*domain.Product, error)
-	GetProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error)
+	GetProducts(ctx context.Context, filter domain.ProductFilter, limit, offset int) ([]*domain.Product, error)
+	GetProductsByAvailability(ctx context.Context, availability string, limit, offset int) ([]*domain.Product, error)
+	StreamProducts(ctx context.Context, fn func(*domain.Product) error) error
 	UpdateProduct(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error)
+	UpdateProductPartial(ctx context.Context, id int64, patch *domain.ProductPatch) (*domain.Product, error)
 	DeleteProduct(ctx context.Context, id int64) error
+	WriteProducts(ctx context.Context, items []domain.BulkProductItem) ([]*domain.BulkProductResult, error)
+	ImportProducts(ctx context.Context, file io.Reader) (*domain.ProductImportReport, error)
+}
+
+// ProductWriter saves products with the validation, moderation and events
+// of the product API. Imports write through it rather than the repository.
+type ProductWriter interface {
+	CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
+	UpdateProduct(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error)
+}
+
+type ContentModerator interface {
+	Moderate(ctx context.Context, product *domain.Product) (*domain.ModerationResult, error)
+}
+
+type ProductModerator interface {
+	Screen(ctx context.Context, product *domain.Product) error
+	Submit(product *domain.Product)
+}
+
+type TrashRepository interface {
+	GetAll(ctx context.Context, storeID int64, limit, offset int) ([]*domain.TrashedProduct, error)
+	Restore(ctx context.Context, id int64) (*domain.Product, error)
+	Count(ctx context.Context, storeID int64) (int64, error)
+	Empty(ctx context.Context, storeID int64) (int64, error)
+	PurgeBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type TrashUseCaseInterface interface {
+	GetTrash(ctx context.Context, storeID int64, limit, offset int) ([]*domain.TrashedProduct, error)
+	RestoreProduct(ctx context.Context, id int64) (*domain.Product, error)
+	EmptyTrash(ctx context.Context, storeID int64) (int64, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type ProductRevisionRepository interface {
+	GetChanges(ctx context.Context, from, to time.Time) ([]domain.ProductChange, error)
+}
+
+type CatalogDiffUseCaseInterface interface {
+	GetDiff(ctx context.Context, from, to time.Time) (*domain.CatalogDiff, error)
+}
+
+type MutationMonitor interface {
+	Record(ctx context.Context, storeID int64, kind string)
+	RequiresConfirmation(storeID int64, kind string) bool
+}
+
+// BulkTokenSigner signs the tokens that confirm held-back bulk operations.
+// Verify returns the token's unique ID.
+type BulkTokenSigner interface {
+	Issue(actor, operation string, ttl time.Duration) (token, id string, expiresAt time.Time, err error)
+	Verify(token, actor, operation string) (id string, err error)
+}
+
+// UsageCounter counts uses of a key within a fixed window.
+// ratelimit.RedisStore shares the counts between instances;
+// ratelimit.MemoryStore keeps them in this process.
+type UsageCounter interface {
+	Consume(ctx context.Context, key string, units int64, window time.Duration) (used int64, resetAt time.Time, err error)
+}
+
+// AuditRecorder writes audit events raised by use cases to the audit log.
+type AuditRecorder interface {
+	Create(ctx context.Context, entry *domain.AuditEntry) error
+}
+
+// EventPublisher receives product events. ProductUseCase publishes them
+// within the transaction of the mutation, so a publisher writing to the
+// database, like the outbox, commits or rolls back with it. Publish must
+// not block the request.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.ProductEvent)
+}
+
+// PublishScheduleRepository finds the products other than drafts whose
+// publishing window opened or closed after from and up to until.
+type PublishScheduleRepository interface {
+	GetPublishTransitions(ctx context.Context, from, until time.Time) ([]*domain.Product, error)
+}
+
+// PreviewSigner signs the tokens of product preview links.
+type PreviewSigner interface {
+	Issue(productID int64, ttl time.Duration) (string, time.Time, error)
+	Verify(token string, productID int64) error
+}
+
+type PreviewUseCaseInterface interface {
+	CreatePreviewToken(ctx context.Context, productID int64, ttl time.Duration) (*domain.PreviewToken, error)
+	GetPreview(ctx context.Context, productID int64, token string) (*domain.Product, error)
+}
+
+// ProductCache drops a product from the product cache on every instance.
+type ProductCache interface {
+	Invalidate(id int64)
+}
+
+type ModerationUseCaseInterface interface {
+	GetProductsForReview(ctx context.Context, status string, limit, offset int) ([]*domain.Product, error)
+	ReviewProduct(ctx context.Context, id int64, result domain.ModerationResult) (*domain.Product, error)
+}
+
+type FeedRepository interface {
+	Create(ctx context.Context, feed *domain.Feed) (*domain.Feed, error)
+	GetByID(ctx context.Context, id int64) (*domain.Feed, error)
+	GetAll(ctx context.Context, limit, offset int) ([]*domain.Feed, error)
+	GetEnabled(ctx context.Context) ([]*domain.Feed, error)
+	Delete(ctx context.Context, id int64) error
+	MarkRun(ctx context.Context, id int64, at time.Time) error
+	CreateRun(ctx context.Context, run *domain.FeedRun) (*domain.FeedRun, error)
+	FinishRun(ctx context.Context, run *domain.FeedRun) error
+	GetRun(ctx context.Context, feedID, runID int64) (*domain.FeedRun, error)
+	GetRuns(ctx context.Context, feedID int64, limit, offset int) ([]*domain.FeedRun, error)
+	GetLinks(ctx context.Context, storeID int64) ([]domain.FeedProductLink, error)
+	SaveLink(ctx context.Context, link *domain.FeedProductLink) error
+}
+
+type FeedFetcher interface {
+	Fetch(ctx context.Context, feed *domain.Feed) ([]domain.FeedItem, error)
+}
+
+type FeedUseCaseInterface interface {
+	RegisterFeed(ctx context.Context, feed *domain.Feed) (*domain.Feed, error)
+	GetFeed(ctx context.Context, id int64) (*domain.Feed, error)
+	GetFeeds(ctx context.Context, limit, offset int) ([]*domain.Feed, error)
+	DeleteFeed(ctx context.Context, id int64) error
+	RunFeed(ctx context.Context, id int64) (*domain.FeedRun, error)
+	RunDueFeeds(ctx context.Context) error
+	GetFeedRun(ctx context.Context, feedID, runID int64) (*domain.FeedRun, error)
+	GetFeedRuns(ctx context.Context, feedID int64, limit, offset int) ([]*domain.FeedRun, error)
+	GetFeedRunImages(ctx context.Context, feedID, runID int64) ([]*domain.ImageImport, error)
+}
+
+type ImageRepository interface {
+	GetByProduct(ctx context.Context, productID int64) ([]*domain.ProductImage, error)
+	HasContent(ctx context.Context, contentHash string) (bool, error)
+	// Attach returns the product's existing image with the same content,
+	// and false, instead of adding it twice.
+	Attach(ctx context.Context, image *domain.ProductImage) (*domain.ProductImage, bool, error)
+	GetImportedURLs(ctx context.Context, productIDs []int64) (map[int64][]string, error)
+	CreateImports(ctx context.Context, imports []*domain.ImageImport) error
+	FinishImport(ctx context.Context, imp *domain.ImageImport) error
+	GetImports(ctx context.Context, runID int64) ([]*domain.ImageImport, error)
+}
+
+// ImageStorage keeps image objects, e.g. an S3 bucket.
+type ImageStorage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+}
+
+// ImageDownloader fetches an image, enforcing the size and type limits.
+type ImageDownloader interface {
+	Download(ctx context.Context, url string) (*domain.DownloadedImage, error)
+}
+
+// FeedImageImporter attaches the images of a feed run's rows to their
+// products in the background.
+type FeedImageImporter interface {
+	QueueImports(ctx context.Context, runID int64, requested []domain.ImageImport) (int, error)
+	GetImports(ctx context.Context, runID int64) ([]*domain.ImageImport, error)
+}
+
+type ImageUseCaseInterface interface {
+	GetProductImages(ctx context.Context, productID int64) ([]*domain.ProductImage, error)
+}
+
+type ImportMappingRepository interface {
+	Create(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error)
+	GetByID(ctx context.Context, storeID, id int64) (*domain.ImportMapping, error)
+	GetAll(ctx context.Context, storeID int64) ([]*domain.ImportMapping, error)
+	Update(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error)
+	Delete(ctx context.Context, storeID, id int64) error
+}
+
+type ImportMappingUseCaseInterface interface {
+	CreateMapping(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error)
+	GetMapping(ctx context.Context, storeID, id int64) (*domain.ImportMapping, error)
+	GetMappings(ctx context.Context, storeID int64) ([]*domain.ImportMapping, error)
+	UpdateMapping(ctx context.Context, mapping *domain.ImportMapping) (*domain.ImportMapping, error)
+	DeleteMapping(ctx context.Context, storeID, id int64) error
+	TestMapping(ctx context.Context, storeID, id int64, csv io.Reader, rows int) ([]domain.ImportPreviewRow, error)
+}
+
+type ConnectorRepository interface {
+	Create(ctx context.Context, connector *domain.Connector) (*domain.Connector, error)
+	GetByID(ctx context.Context, id int64) (*domain.Connector, error)
+	GetAll(ctx context.Context, limit, offset int) ([]*domain.Connector, error)
+	Delete(ctx context.Context, id int64) error
+	GetCheckpoint(ctx context.Context, connectorID int64) (*domain.ConnectorCheckpoint, error)
+	SaveCheckpoint(ctx context.Context, checkpoint *domain.ConnectorCheckpoint) error
+	GetLinks(ctx context.Context, connectorID int64) ([]*domain.ProductLink, error)
+	SaveLink(ctx context.Context, link *domain.ProductLink) error
+	CreateSync(ctx context.Context, sync *domain.ConnectorSync) (*domain.ConnectorSync, error)
+	FinishSync(ctx context.Context, sync *domain.ConnectorSync) error
+	GetSync(ctx context.Context, connectorID, syncID int64) (*domain.ConnectorSync, error)
+	GetSyncs(ctx context.Context, connectorID int64, limit, offset int) ([]*domain.ConnectorSync, error)
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	GetByID(ctx context.Context, id int64) (*domain.WebhookSubscription, error)
+	GetAll(ctx context.Context, storeID int64, limit, offset int) ([]*domain.WebhookSubscription, error)
+	Resume(ctx context.Context, id int64, at time.Time) (*domain.WebhookSubscription, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// WebhookMonitor reports delivery health kept by the webhook dispatcher.
+type WebhookMonitor interface {
+	Health(id int64) (domain.WebhookHealth, bool)
+}
+
+type WebhookUseCaseInterface interface {
+	CreateWebhook(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	GetWebhook(ctx context.Context, id int64) (*domain.WebhookSubscription, error)
+	GetWebhooks(ctx context.Context, storeID int64, limit, offset int) ([]*domain.WebhookSubscription, error)
+	GetWebhookHealth(ctx context.Context, id int64) (*domain.WebhookHealth, error)
+	ResumeWebhook(ctx context.Context, id int64) (*domain.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+}
+
+type WebhookSecretUseCaseInterface interface {
+	RotateSecret(ctx context.Context, storeID int64) (*domain.WebhookSigningSecret, error)
+	RevokePreviousSecret(ctx context.Context, storeID int64) error
+}
+
+type StoreRepository interface {
+	Create(ctx context.Context, store *domain.Store) (*domain.Store, error)
+	GetByID(ctx context.Context, id int64) (*domain.Store, error)
+	GetAll(ctx context.Context, limit, offset int) ([]*domain.Store, error)
+	Update(ctx context.Context, id int64, store *domain.Store) (*domain.Store, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type StoreUseCaseInterface interface {
+	CreateStore(ctx context.Context, store *domain.Store) (*domain.Store, error)
+	GetStore(ctx context.Context, id int64) (*domain.Store, error)
+	GetStores(ctx context.Context, limit, offset int) ([]*domain.Store, error)
+	UpdateStore(ctx context.Context, id int64, store *domain.Store) (*domain.Store, error)
+	DeleteStore(ctx context.Context, id int64) error
+}
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *domain.Category) (*domain.Category, error)
+	GetByID(ctx context.Context, id int64) (*domain.Category, error)
+	GetAll(ctx context.Context, limit, offset int) ([]*domain.Category, error)
+	// Update and Delete also return the products in the category or its
+	// subcategories, with their versions bumped.
+	Update(ctx context.Context, id int64, category *domain.Category) (*domain.Category, []*domain.Product, error)
+	Delete(ctx context.Context, id int64) ([]*domain.Product, error)
+	GetByProduct(ctx context.Context, productID int64) ([]*domain.Category, error)
+	// SetForProduct replaces the product's categories and bumps its
+	// version, returning ErrCategoryNotFound when one does not exist.
+	SetForProduct(ctx context.Context, productID int64, categoryIDs []int64) ([]*domain.Category, *domain.Product, error)
+}
+
+type CategoryUseCaseInterface interface {
+	CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
+	GetCategory(ctx context.Context, id int64) (*domain.Category, error)
+	GetCategories(ctx context.Context, limit, offset int) ([]*domain.Category, error)
+	UpdateCategory(ctx context.Context, id int64, category *domain.Category) (*domain.Category, error)
+	DeleteCategory(ctx context.Context, id int64) error
+	GetProductCategories(ctx context.Context, productID int64) ([]*domain.Category, error)
+	SetProductCategories(ctx context.Context, productID int64, categoryIDs []int64) ([]*domain.Category, error)
+}
+
+type StockSummaryRepository interface {
+	Recalculate(ctx context.Context, storeID int64, calculatedAt time.Time) (*domain.StockSummary, error)
+	GetByStoreID(ctx context.Context, storeID int64) (*domain.StockSummary, error)
+}
+
+type StockSummaryUseCaseInterface interface {
+	RecalculateStockSummary(ctx context.Context, storeID int64) (*domain.StockSummary, error)
+	GetStockSummary(ctx context.Context, storeID int64) (*domain.StockSummary, error)
+}
+
+type DigestRepository interface {
+	GetSettings(ctx context.Context, storeID int64) (*domain.DigestSettings, error)
+	SaveSettings(ctx context.Context, settings *domain.DigestSettings) (*domain.DigestSettings, error)
+	DeleteSettings(ctx context.Context, storeID int64) error
+}
+
+type DigestUseCaseInterface interface {
+	GetSettings(ctx context.Context, storeID int64) (*domain.DigestSettings, error)
+	SaveSettings(ctx context.Context, settings *domain.DigestSettings) (*domain.DigestSettings, error)
+	DeleteSettings(ctx context.Context, storeID int64) error
+}
+
+// AnalyticsRecorder buffers analytics events until they are written.
+type AnalyticsRecorder interface {
+	// Record returns how many of the events were kept; the rest were
+	// dropped because the buffer is full.
+	Record(events []domain.AnalyticsEvent) int
+}
+
+type AnalyticsRepository interface {
+	GetProductStats(ctx context.Context, productID int64, since time.Time) (*domain.ProductStats, error)
+}
+
+type AnalyticsUseCaseInterface interface {
+	RecordEvents(ctx context.Context, events []domain.AnalyticsEvent) (int, error)
+	GetProductStats(ctx context.Context, productID int64, days int) (*domain.ProductStats, error)
+}
+
+type PricingRepository interface {
+	GetPolicy(ctx context.Context, storeID int64, currency string) (*domain.PricingPolicy, error)
+	GetPolicies(ctx context.Context, storeID int64) ([]*domain.PricingPolicy, error)
+	SavePolicy(ctx context.Context, policy *domain.PricingPolicy) (*domain.PricingPolicy, error)
+	DeletePolicy(ctx context.Context, storeID int64, currency string) error
+}
+
+type PricingUseCaseInterface interface {
+	GetPolicies(ctx context.Context, storeID int64) ([]*domain.PricingPolicy, error)
+	SavePolicy(ctx context.Context, policy *domain.PricingPolicy) (*domain.PricingPolicy, error)
+	DeletePolicy(ctx context.Context, storeID int64, currency string) error
+	QuotePrice(ctx context.Context, productID int64, adjustment domain.PriceAdjustment) (*domain.PriceQuote, error)
+}
+
+type BundleRepository interface {
+	GetComponents(ctx context.Context, bundleID int64) ([]domain.BundleComponent, error)
+	IsComponent(ctx context.Context, productID int64) (bool, error)
+	SetComponents(ctx context.Context, bundleID int64, components []domain.BundleComponent) error
+	DeleteComponents(ctx context.Context, bundleID int64) error
+	DecrementStock(ctx context.Context, decrements []domain.StockDecrement) ([]*domain.Product, error)
+}
+
+type BundleUseCaseInterface interface {
+	GetBundle(ctx context.Context, id int64) (*domain.Bundle, error)
+	SaveBundle(ctx context.Context, id int64, components []domain.BundleComponent) (*domain.Bundle, error)
+	DeleteBundle(ctx context.Context, id int64) error
+	SellBundle(ctx context.Context, id int64, count int64) (*domain.Bundle, error)
+}
+
+type OrderRepository interface {
+	Place(ctx context.Context, order *domain.Order) ([]*domain.Product, error)
+	GetByID(ctx context.Context, id int64) (*domain.Order, error)
+	GetAll(ctx context.Context, storeID int64, limit, offset int) ([]*domain.Order, error)
+}
+
+type OrderUseCaseInterface interface {
+	CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error)
+	GetOrder(ctx context.Context, id int64) (*domain.Order, error)
+	GetOrders(ctx context.Context, storeID int64, limit, offset int) ([]*domain.Order, error)
+}
+
+type SecretStore interface {
+	Put(ctx context.Context, key string, value []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type TwoFactorRepository interface {
+	Get(ctx context.Context, identity string) (*domain.TwoFactorEnrollment, error)
+	Save(ctx context.Context, enrollment *domain.TwoFactorEnrollment) error
+	Delete(ctx context.Context, identity string) error
+	UseRecoveryCode(ctx context.Context, identity, codeHash string) (bool, error)
+	UseTOTPStep(ctx context.Context, identity string, step int64) (bool, error)
+}
+
+type TwoFactorUseCaseInterface interface {
+	Setup(ctx context.Context, identity string) (*domain.TwoFactorSetup, error)
+	Confirm(ctx context.Context, identity, code string) ([]string, error)
+	Verify(ctx context.Context, identity, role, code string) error
+	Disable(ctx context.Context, identity, code string) error
+}
+
+type ConnectorFactory interface {
+	Supports(kind string) bool
+	New(kind string, settings map[string]string, credentials []byte) (connectors.Connector, error)
+}
+
+type ConnectorUseCaseInterface interface {
+	CreateConnector(ctx context.Context, connector *domain.Connector, credentials []byte) (*domain.Connector, error)
+	GetConnector(ctx context.Context, id int64) (*domain.Connector, error)
+	GetConnectors(ctx context.Context, limit, offset int) ([]*domain.Connector, error)
+	DeleteConnector(ctx context.Context, id int64) error
+	SyncConnector(ctx context.Context, id int64) (*domain.ConnectorSync, error)
+	GetConnectorSync(ctx context.Context, connectorID, syncID int64) (*domain.ConnectorSync, error)
+	GetConnectorSyncs(ctx context.Context, connectorID int64, limit, offset int) ([]*domain.ConnectorSync, error)
+}
+
+type ReconciliationRepository interface {
+	CreateRun(ctx context.Context, run *domain.ReconciliationRun) (*domain.ReconciliationRun, error)
+	FinishRun(ctx context.Context, run *domain.ReconciliationRun, discrepancies []*domain.Discrepancy) error
+	GetRun(ctx context.Context, id int64) (*domain.ReconciliationRun, error)
+	GetRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, error)
+	GetDiscrepancies(ctx context.Context, runID int64, limit, offset int) ([]*domain.Discrepancy, error)
+}
+
+// ReferenceSource is an external source of truth the catalog is
+// reconciled against, such as feeds or connectors, each addressed by ID.
+type ReferenceSource interface {
+	// StoreID returns the store the source feeds, or the source's not
+	// found error.
+	StoreID(ctx context.Context, id int64) (int64, error)
+	Snapshot(ctx context.Context, id int64) (*domain.ReferenceSnapshot, error)
+	// Enabled lists the sources scheduled reconciliation covers.
+	Enabled(ctx context.Context) ([]int64, error)
+}
+
+type ReconciliationUseCaseInterface interface {
+	Reconcile(ctx context.Context, source string, sourceID int64, policy string) (*domain.ReconciliationRun, error)
+	ReconcileAll(ctx context.Context, policy string) error
+	GetRun(ctx context.Context, id int64) (*domain.ReconciliationRun, error)
+	GetRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, error)
+	GetDiscrepancies(ctx context.Context, runID int64, limit, offset int) ([]*domain.Discrepancy, error)
+}
+
+type CatalogSnapshotRepository interface {
+	// Capture calls fn with each of the store's products as of one
+	// consistent point in time, which it returns. It returns
+	// ErrStoreNotFound for unknown stores.
+	Capture(ctx context.Context, storeID int64, fn func(*domain.Product) error) (time.Time, error)
+	CreateSnapshot(ctx context.Context, snapshot *domain.CatalogSnapshot) (*domain.CatalogSnapshot, error)
+	GetSnapshot(ctx context.Context, storeID, id int64) (*domain.CatalogSnapshot, error)
+	GetSnapshots(ctx context.Context, storeID int64, limit, offset int) ([]*domain.CatalogSnapshot, error)
+	// PlanRestore compares the store's catalog with snapshot products, as
+	// RestoreCatalog would, without writing anything.
+	PlanRestore(ctx context.Context, storeID int64, snapshot []*domain.Product) (*domain.CatalogDiff, error)
+	// RestoreCatalog makes the store's catalog match snapshot products in
+	// one transaction and returns what it wrote. Deleted products go to
+	// the trash.
+	RestoreCatalog(ctx context.Context, storeID int64, snapshot []*domain.Product) (*domain.CatalogDiff, error)
+	// CreateRestore first fails the store's unfinished restores created
+	// before staleBefore, which were interrupted, and returns
+	// ErrRestoreInProgress if another is still unfinished.
+	CreateRestore(ctx context.Context, restore *domain.CatalogRestore, staleBefore time.Time) (*domain.CatalogRestore, error)
+	UpdateRestore(ctx context.Context, restore *domain.CatalogRestore) error
+	GetRestore(ctx context.Context, storeID, id int64) (*domain.CatalogRestore, error)
+}
+
+// SnapshotStorage keeps snapshot objects, e.g. an S3 bucket.
+type SnapshotStorage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+type CatalogSnapshotUseCaseInterface interface {
+	CreateSnapshot(ctx context.Context, storeID int64) (*domain.CatalogSnapshot, error)
+	GetSnapshot(ctx context.Context, storeID, id int64) (*domain.CatalogSnapshot, error)
+	GetSnapshots(ctx context.Context, storeID int64, limit, offset int) ([]*domain.CatalogSnapshot, error)
+	PreviewRestore(ctx context.Context, storeID, snapshotID int64) (*domain.CatalogDiff, error)
+	StartRestore(ctx context.Context, storeID, snapshotID int64) (*domain.CatalogRestore, error)
+	GetRestore(ctx context.Context, storeID, id int64) (*domain.CatalogRestore, error)
+}
+
+type UserRepository interface {
+	Create(ctx context.Context, user *domain.User) (*domain.User, error)
+	GetByID(ctx context.Context, id int64) (*domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+}
+
+type AuthUseCaseInterface interface {
+	Register(ctx context.Context, email, password string) (*domain.User, error)
+	Login(ctx context.Context, email, password, code string) (*domain.AuthTokens, error)
+	Refresh(ctx context.Context, refreshToken string) (*domain.AuthTokens, error)
+}
+
+type ImpersonationUseCaseInterface interface {
+	Impersonate(ctx context.Context, req domain.ImpersonationRequest) (*domain.Impersonation, error)
+}
+
+// LiveConfigRepository keeps the live configuration settings. Save writes
+// setting only if the stored version is still setting.Version, 0 meaning
+// none is stored yet, and returns domain.ErrConfigConflict otherwise.
+type LiveConfigRepository interface {
+	GetAll(ctx context.Context) ([]*domain.ConfigSetting, error)
+	Get(ctx context.Context, key string) (*domain.ConfigSetting, error)
+	Save(ctx context.Context, setting *domain.ConfigSetting) (*domain.ConfigSetting, error)
+}
+
+// RateLimitAdjuster changes the request rate limit while the service runs.
+type RateLimitAdjuster interface {
+	SetLimit(rate float64, burst int64)
+}
+
+type LiveConfigUseCaseInterface interface {
+	GetSettings(ctx context.Context) ([]*domain.ConfigSetting, error)
+	GetSetting(ctx context.Context, key string) (*domain.ConfigSetting, error)
+	SaveSetting(ctx context.Context, change domain.ConfigChange) (*domain.ConfigSetting, error)
+}
+
+// UsageRepository pairs the API calls recorded for each store and hour
+// with the usage record closed for it.
+type UsageRepository interface {
+	GetUsage(ctx context.Context, from, to time.Time) ([]*domain.UsageComparison, error)
+}
+
+type UsageUseCaseInterface interface {
+	Reconcile(ctx context.Context, from, to time.Time) (*domain.UsageReconciliation, error)
 }
@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LiveConfigUseCase manages the live configuration: feature flags, the
+// rate limit, maintenance mode and store settings. Every instance applies
+// the settings it last loaded and reloads them every refresh interval, so
+// a change made through one instance reaches the others within it.
+type LiveConfigUseCase struct {
+	repo             LiveConfigRepository
+	stores           StoreRepository
+	audit            AuditRecorder
+	rateLimiter      RateLimitAdjuster
+	defaultRateLimit domain.RateLimitSettings
+	interval         time.Duration
+	clock            clock.Clock
+
+	current atomic.Pointer[domain.LiveConfig]
+
+	// mu orders refreshes, so an older load never undoes a newer one's
+	// rate limit.
+	mu               sync.Mutex
+	appliedRateLimit domain.RateLimitSettings
+}
+
+// NewLiveConfigUseCase applies rate limit changes to rateLimiter, starting
+// from defaultRateLimit. rateLimiter is nil when rate limiting is off, in
+// which case the rate limit cannot be changed. audit may be nil, in which
+// case changes are only logged.
+func NewLiveConfigUseCase(repo LiveConfigRepository, stores StoreRepository, audit AuditRecorder, rateLimiter RateLimitAdjuster, defaultRateLimit domain.RateLimitSettings, interval time.Duration, clk clock.Clock) *LiveConfigUseCase {
+	uc := &LiveConfigUseCase{
+		repo:             repo,
+		stores:           stores,
+		audit:            audit,
+		rateLimiter:      rateLimiter,
+		defaultRateLimit: defaultRateLimit,
+		interval:         interval,
+		clock:            clk,
+		appliedRateLimit: defaultRateLimit,
+	}
+	uc.current.Store(&domain.LiveConfig{})
+	return uc
+}
+
+// Current returns the live configuration this instance applies.
+func (uc *LiveConfigUseCase) Current() *domain.LiveConfig {
+	return uc.current.Load()
+}
+
+// Maintenance returns whether the service is in maintenance mode.
+func (uc *LiveConfigUseCase) Maintenance() domain.MaintenanceMode {
+	return uc.Current().Maintenance
+}
+
+// FeatureEnabled reports whether the feature is on for storeID, or for the
+// whole service when storeID is 0.
+func (uc *LiveConfigUseCase) FeatureEnabled(name string, storeID int64) bool {
+	return uc.Current().FeatureEnabled(name, storeID)
+}
+
+// Run reloads the settings every refresh interval until ctx is cancelled.
+func (uc *LiveConfigUseCase) Run(ctx context.Context) {
+	logger.FromContext(ctx).WithField("interval", uc.interval).Info("Live config refresher started")
+
+	ticker := uc.clock.NewTicker(uc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.FromContext(ctx).Info("Live config refresher stopped")
+			return
+		case <-ticker.C():
+			if err := uc.Refresh(ctx); err != nil && ctx.Err() == nil {
+				logger.FromContext(ctx).WithError(err).Error("Failed to refresh live config")
+			}
+		}
+	}
+}
+
+// Refresh loads the settings and applies them. A stored value that no
+// longer decodes is skipped, leaving that setting at its default.
+func (uc *LiveConfigUseCase) Refresh(ctx context.Context) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	settings, err := uc.repo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load live config: %w", err)
+	}
+
+	config := &domain.LiveConfig{Stores: make(map[int64]domain.StoreSettings)}
+	for _, setting := range settings {
+		value, err := domain.DecodeConfigValue(setting.Key, setting.Value)
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).WithField("key", setting.Key).Warn("Ignoring invalid live config setting")
+			continue
+		}
+		switch v := value.(type) {
+		case domain.FeatureFlags:
+			config.FeatureFlags = v
+		case domain.RateLimitSettings:
+			config.RateLimit = &v
+		case domain.MaintenanceMode:
+			config.Maintenance = v
+		case domain.StoreSettings:
+			storeID, _ := domain.ParseStoreSettingsKey(setting.Key)
+			config.Stores[storeID] = v
+		}
+	}
+
+	rateLimit := uc.defaultRateLimit
+	if config.RateLimit != nil {
+		rateLimit = *config.RateLimit
+	}
+	if uc.rateLimiter != nil && rateLimit != uc.appliedRateLimit {
+		uc.rateLimiter.SetLimit(rateLimit.RPS, rateLimit.Burst)
+		uc.appliedRateLimit = rateLimit
+		logger.FromContext(ctx).WithFields(logrus.Fields{"rps": rateLimit.RPS, "burst": rateLimit.Burst}).Info("Rate limit changed")
+	}
+
+	previous := uc.current.Swap(config)
+	if previous.Maintenance.Enabled != config.Maintenance.Enabled {
+		logger.FromContext(ctx).WithField("enabled", config.Maintenance.Enabled).Warn("Maintenance mode changed")
+	}
+	return nil
+}
+
+// GetSettings returns the service's settings, including those never
+// changed, followed by the store settings that were set.
+func (uc *LiveConfigUseCase) GetSettings(ctx context.Context) ([]*domain.ConfigSetting, error) {
+	stored, err := uc.repo.GetAll(ctx)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get live config from repository")
+		return nil, err
+	}
+
+	byKey := make(map[string]*domain.ConfigSetting, len(stored))
+	for _, setting := range stored {
+		byKey[setting.Key] = setting
+	}
+
+	settings := make([]*domain.ConfigSetting, 0, len(stored)+len(domain.GlobalConfigKeys))
+	for _, key := range domain.GlobalConfigKeys {
+		if setting, ok := byKey[key]; ok {
+			settings = append(settings, setting)
+		} else {
+			settings = append(settings, uc.defaultSetting(key))
+		}
+	}
+	for _, setting := range stored {
+		if _, ok := domain.ParseStoreSettingsKey(setting.Key); ok {
+			settings = append(settings, setting)
+		}
+	}
+	return settings, nil
+}
+
+func (uc *LiveConfigUseCase) GetSetting(ctx context.Context, key string) (*domain.ConfigSetting, error) {
+	if err := uc.checkKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	setting, err := uc.repo.Get(ctx, key)
+	if errors.Is(err, domain.ErrConfigSettingNotFound) {
+		return uc.defaultSetting(key), nil
+	}
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get live config setting from repository")
+		return nil, err
+	}
+	return setting, nil
+}
+
+// SaveSetting replaces a setting's value unless it was changed since
+// change.Version, records the change in the audit log and applies it to
+// this instance at once.
+func (uc *LiveConfigUseCase) SaveSetting(ctx context.Context, change domain.ConfigChange) (*domain.ConfigSetting, error) {
+	logger := logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":  domain.AuditEventConfigChanged,
+		"key":     change.Key,
+		"actor":   change.Actor,
+		"version": change.Version,
+	})
+
+	if err := uc.checkKey(ctx, change.Key); err != nil {
+		return nil, err
+	}
+	value, err := domain.DecodeConfigValue(change.Key, change.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidConfigSetting, err.Error())
+	}
+	if change.Key == domain.ConfigRateLimit && uc.rateLimiter == nil {
+		return nil, fmt.Errorf("%w: rate limiting is off; RATE_LIMIT_RPS turns it on", domain.ErrInvalidConfigSetting)
+	}
+	// Stored values are re-encoded, so they hold no unknown fields and
+	// read the same in the audit log whatever the request's formatting.
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", change.Key, err)
+	}
+
+	saved, err := uc.repo.Save(ctx, &domain.ConfigSetting{
+		Key:       change.Key,
+		Value:     encoded,
+		Version:   change.Version,
+		UpdatedBy: change.Actor,
+	})
+	if err != nil {
+		if !errors.Is(err, domain.ErrConfigConflict) {
+			logger.WithError(err).Error("Failed to save live config setting in repository")
+		}
+		return nil, err
+	}
+	logger.WithField("new_version", saved.Version).Info("Live config changed")
+
+	// The change is already made, so a failed audit write is logged
+	// rather than reported as a failed change.
+	if uc.audit != nil {
+		entry := &domain.AuditEntry{
+			Actor:      change.Actor,
+			ClientIP:   change.ClientIP,
+			Event:      domain.AuditEventConfigChanged,
+			Detail:     fmt.Sprintf("%s version %d: %s", saved.Key, saved.Version, encoded),
+			OccurredAt: uc.clock.Now().UTC(),
+		}
+		if err := uc.audit.Create(ctx, entry); err != nil {
+			logger.WithError(err).Error("Failed to record live config audit event")
+		}
+	}
+
+	if err := uc.Refresh(ctx); err != nil {
+		logger.WithError(err).Warn("Failed to apply live config change; it applies at the next refresh")
+	}
+	return saved, nil
+}
+
+// checkKey refuses unknown settings and the settings of stores that do
+// not exist.
+func (uc *LiveConfigUseCase) checkKey(ctx context.Context, key string) error {
+	for _, global := range domain.GlobalConfigKeys {
+		if key == global {
+			return nil
+		}
+	}
+	storeID, ok := domain.ParseStoreSettingsKey(key)
+	if !ok {
+		return domain.ErrConfigSettingNotFound
+	}
+	if _, err := uc.stores.GetByID(ctx, storeID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uc *LiveConfigUseCase) defaultSetting(key string) *domain.ConfigSetting {
+	var value any
+	switch key {
+	case domain.ConfigFeatureFlags:
+		value = domain.FeatureFlags{}
+	case domain.ConfigRateLimit:
+		value = uc.defaultRateLimit
+	case domain.ConfigMaintenance:
+		value = domain.MaintenanceMode{}
+	default:
+		value = domain.StoreSettings{FeatureFlags: domain.FeatureFlags{}}
+	}
+	encoded, _ := json.Marshal(value)
+	return &domain.ConfigSetting{Key: key, Value: encoded}
+}
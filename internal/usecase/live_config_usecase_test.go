@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLiveConfig keeps settings like the Postgres repository does,
+// versions included.
+type memoryLiveConfig struct {
+	mu       sync.Mutex
+	settings map[string]domain.ConfigSetting
+	clock    clock.Clock
+}
+
+func (m *memoryLiveConfig) GetAll(ctx context.Context) ([]*domain.ConfigSetting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var settings []*domain.ConfigSetting
+	for _, setting := range m.settings {
+		setting := setting
+		settings = append(settings, &setting)
+	}
+	return settings, nil
+}
+
+func (m *memoryLiveConfig) Get(ctx context.Context, key string) (*domain.ConfigSetting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	setting, ok := m.settings[key]
+	if !ok {
+		return nil, domain.ErrConfigSettingNotFound
+	}
+	return &setting, nil
+}
+
+func (m *memoryLiveConfig) Save(ctx context.Context, setting *domain.ConfigSetting) (*domain.ConfigSetting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.settings[setting.Key].Version != setting.Version {
+		return nil, domain.ErrConfigConflict
+	}
+	saved := *setting
+	saved.Version++
+	saved.UpdatedAt = m.clock.Now()
+	m.settings[setting.Key] = saved
+	return &saved, nil
+}
+
+type recordingRateLimiter struct {
+	limits []domain.RateLimitSettings
+}
+
+func (r *recordingRateLimiter) SetLimit(rate float64, burst int64) {
+	r.limits = append(r.limits, domain.RateLimitSettings{RPS: rate, Burst: burst})
+}
+
+func TestLiveConfigUseCase_SaveSetting(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	defaults := domain.RateLimitSettings{RPS: 10, Burst: 20}
+
+	setup := func(limiter RateLimitAdjuster) (*LiveConfigUseCase, *MockStoreRepository, *recordingAudit) {
+		repo := &memoryLiveConfig{settings: make(map[string]domain.ConfigSetting), clock: fake}
+		stores := new(MockStoreRepository)
+		audit := &recordingAudit{}
+		return NewLiveConfigUseCase(repo, stores, audit, limiter, defaults, time.Minute, fake), stores, audit
+	}
+	change := func(key, value string, version int64) domain.ConfigChange {
+		return domain.ConfigChange{Key: key, Value: json.RawMessage(value), Version: version, Actor: "workload:admin-console", ClientIP: "10.0.0.1"}
+	}
+
+	t.Run("applies and audits changes", func(t *testing.T) {
+		limiter := &recordingRateLimiter{}
+		uc, _, audit := setup(limiter)
+
+		saved, err := uc.SaveSetting(ctx, change(domain.ConfigMaintenance, `{"enabled": true, "message": "Migrating"}`, 0))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), saved.Version)
+		assert.Equal(t, domain.MaintenanceMode{Enabled: true, Message: "Migrating"}, uc.Maintenance())
+
+		_, err = uc.SaveSetting(ctx, change(domain.ConfigRateLimit, `{"rps":5,"burst":5}`, 0))
+		require.NoError(t, err)
+		assert.Equal(t, []domain.RateLimitSettings{{RPS: 5, Burst: 5}}, limiter.limits)
+
+		require.Len(t, audit.entries, 2)
+		entry := audit.entries[0]
+		assert.Equal(t, domain.AuditEventConfigChanged, entry.Event)
+		assert.Equal(t, "workload:admin-console", entry.Actor)
+		assert.Equal(t, "10.0.0.1", entry.ClientIP)
+		assert.Equal(t, `maintenance version 1: {"enabled":true,"message":"Migrating"}`, entry.Detail)
+	})
+
+	t.Run("refuses stale versions", func(t *testing.T) {
+		uc, _, audit := setup(nil)
+
+		_, err := uc.SaveSetting(ctx, change(domain.ConfigFeatureFlags, `{"new-checkout":true}`, 0))
+		require.NoError(t, err)
+		_, err = uc.SaveSetting(ctx, change(domain.ConfigFeatureFlags, `{"new-checkout":false}`, 0))
+		assert.ErrorIs(t, err, domain.ErrConfigConflict)
+		saved, err := uc.SaveSetting(ctx, change(domain.ConfigFeatureFlags, `{"new-checkout":false}`, 1))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), saved.Version)
+		assert.False(t, uc.FeatureEnabled("new-checkout", 0))
+		assert.Len(t, audit.entries, 2)
+	})
+
+	t.Run("store flags override the service's", func(t *testing.T) {
+		uc, stores, _ := setup(nil)
+		stores.On("GetByID", ctx, int64(3)).Return(&domain.Store{ID: 3}, nil)
+		stores.On("GetByID", ctx, int64(4)).Return(nil, domain.ErrStoreNotFound)
+
+		_, err := uc.SaveSetting(ctx, change(domain.ConfigFeatureFlags, `{"bundles":true}`, 0))
+		require.NoError(t, err)
+		_, err = uc.SaveSetting(ctx, change(domain.StoreSettingsKey(3), `{"feature_flags":{"bundles":false}}`, 0))
+		require.NoError(t, err)
+		assert.False(t, uc.FeatureEnabled("bundles", 3))
+		assert.True(t, uc.FeatureEnabled("bundles", 5))
+
+		_, err = uc.SaveSetting(ctx, change(domain.StoreSettingsKey(4), `{"feature_flags":{}}`, 0))
+		assert.ErrorIs(t, err, domain.ErrStoreNotFound)
+	})
+
+	invalid := []struct {
+		name  string
+		key   string
+		value string
+		err   error
+	}{
+		{name: "unknown field", key: domain.ConfigMaintenance, value: `{"enable":true}`, err: domain.ErrInvalidConfigSetting},
+		{name: "null value", key: domain.ConfigFeatureFlags, value: `null`, err: domain.ErrInvalidConfigSetting},
+		{name: "bad flag name", key: domain.ConfigFeatureFlags, value: `{"New Checkout":true}`, err: domain.ErrInvalidConfigSetting},
+		{name: "zero rate", key: domain.ConfigRateLimit, value: `{"rps":0,"burst":1}`, err: domain.ErrInvalidConfigSetting},
+		{name: "rate limiting off", key: domain.ConfigRateLimit, value: `{"rps":5,"burst":5}`, err: domain.ErrInvalidConfigSetting},
+		{name: "unknown setting", key: "log_level", value: `"debug"`, err: domain.ErrConfigSettingNotFound},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, _, audit := setup(nil)
+			_, err := uc.SaveSetting(ctx, change(tt.key, tt.value, 0))
+			assert.ErrorIs(t, err, tt.err)
+			assert.Empty(t, audit.entries)
+		})
+	}
+}
+
+func TestLiveConfigUseCase_GetSettings(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	repo := &memoryLiveConfig{settings: map[string]domain.ConfigSetting{
+		domain.ConfigMaintenance:   {Key: domain.ConfigMaintenance, Value: json.RawMessage(`{"enabled":true,"message":""}`), Version: 3},
+		domain.StoreSettingsKey(7): {Key: domain.StoreSettingsKey(7), Value: json.RawMessage(`{"feature_flags":{"bundles":true}}`), Version: 1},
+		domain.ConfigFeatureFlags:  {Key: domain.ConfigFeatureFlags, Value: json.RawMessage(`{"Bad Name":true}`), Version: 2},
+	}, clock: fake}
+	uc := NewLiveConfigUseCase(repo, new(MockStoreRepository), nil, nil, domain.RateLimitSettings{RPS: 10, Burst: 20}, time.Minute, fake)
+
+	settings, err := uc.GetSettings(ctx)
+	require.NoError(t, err)
+	require.Len(t, settings, 4)
+	assert.Equal(t, domain.ConfigFeatureFlags, settings[0].Key)
+	assert.Equal(t, int64(2), settings[0].Version)
+	assert.Equal(t, domain.ConfigRateLimit, settings[1].Key)
+	assert.Equal(t, int64(0), settings[1].Version)
+	assert.JSONEq(t, `{"rps":10,"burst":20}`, string(settings[1].Value))
+	assert.Equal(t, int64(3), settings[2].Version)
+	assert.Equal(t, "store:7", settings[3].Key)
+
+	// The invalid flags are skipped rather than failing the refresh.
+	require.NoError(t, uc.Refresh(ctx))
+	assert.True(t, uc.Maintenance().Enabled)
+	assert.True(t, uc.FeatureEnabled("bundles", 7))
+	assert.Empty(t, uc.Current().FeatureFlags)
+}
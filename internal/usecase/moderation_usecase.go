@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// ModerationUseCase runs the moderation pipeline for product content.
+// Screeners run synchronously on every create and update and can reject the
+// request outright or flag the product for review. The optional reviewer is
+// an external service consulted asynchronously after the product is saved.
+type ModerationUseCase struct {
+	productRepo   ProductRepository
+	screeners     []ContentModerator
+	reviewer      ContentModerator
+	reviewTimeout time.Duration
+
+	wg sync.WaitGroup
+}
+
+func NewModerationUseCase(productRepo ProductRepository, screeners []ContentModerator, reviewer ContentModerator, reviewTimeout time.Duration) *ModerationUseCase {
+	return &ModerationUseCase{
+		productRepo:   productRepo,
+		screeners:     screeners,
+		reviewer:      reviewer,
+		reviewTimeout: reviewTimeout,
+	}
+}
+
+func (uc *ModerationUseCase) Screen(ctx context.Context, product *domain.Product) error {
+	for _, screener := range uc.screeners {
+		result, err := screener.Moderate(ctx, product)
+		if err != nil {
+			return fmt.Errorf("failed to moderate product: %w", err)
+		}
+
+		switch result.Status {
+		case domain.ModerationStatusRejected:
+			return fmt.Errorf("%w: %s", domain.ErrContentRejected, result.Reason)
+		case domain.ModerationStatusPending:
+			product.ModerationStatus = domain.ModerationStatusPending
+			product.ModerationReason = sql.NullString{String: result.Reason, Valid: result.Reason != ""}
+			return nil
+		}
+	}
+
+	if uc.reviewer != nil {
+		product.ModerationStatus = domain.ModerationStatusPending
+	} else {
+		product.ModerationStatus = domain.ModerationStatusApproved
+	}
+	product.ModerationReason = sql.NullString{}
+	return nil
+}
+
+// Submit hands a saved product to the external reviewer. Products flagged by
+// a screener already carry a reason and wait for an admin instead.
+func (uc *ModerationUseCase) Submit(product *domain.Product) {
+	if uc.reviewer == nil || product.ModerationStatus != domain.ModerationStatusPending || product.ModerationReason.Valid {
+		return
+	}
+
+	uc.wg.Add(1)
+	go func() {
+		defer uc.wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), uc.reviewTimeout)
+		defer cancel()
+
+		logger := logger.FromContext(ctx).WithFields(logrus.Fields{
+			"action":     "review_product",
+			"product_id": product.ID,
+		})
+
+		result, err := uc.reviewer.Moderate(ctx, product)
+		if err != nil {
+			logger.WithError(err).Warn("External moderation failed, product stays pending")
+			return
+		}
+		if result.Status == domain.ModerationStatusPending && result.Reason == "" {
+			return
+		}
+
+		if _, err := uc.productRepo.UpdateModeration(ctx, product.ID, *result); err != nil {
+			logger.WithError(err).Error("Failed to store moderation result")
+			return
+		}
+
+		logger.WithField("moderation_status", result.Status).Info("Product reviewed")
+	}()
+}
+
+// Wait blocks until in-flight external reviews finish.
+func (uc *ModerationUseCase) Wait() {
+	uc.wg.Wait()
+}
+
+func (uc *ModerationUseCase) GetProductsForReview(ctx context.Context, status string, limit, offset int) ([]*domain.Product, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action": "get_products_for_review",
+		"status": status,
+		"limit":  limit,
+		"offset": offset,
+	}).Info("Retrieving products for moderation review")
+
+	if status == "" {
+		status = domain.ModerationStatusPending
+	}
+	if !domain.IsValidModerationStatus(status) {
+		return nil, fmt.Errorf("%w: unknown moderation status %q", domain.ErrInvalidProduct, status)
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	products, err := uc.productRepo.GetByModerationStatus(ctx, status, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get products for review from repository")
+		return nil, fmt.Errorf("failed to get products for review: %w", err)
+	}
+
+	return products, nil
+}
+
+func (uc *ModerationUseCase) ReviewProduct(ctx context.Context, id int64, result domain.ModerationResult) (*domain.Product, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":            "review_product",
+		"product_id":        id,
+		"moderation_status": result.Status,
+	}).Info("Recording moderation decision")
+
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", domain.ErrInvalidProduct)
+	}
+	if result.Status != domain.ModerationStatusApproved && result.Status != domain.ModerationStatusRejected {
+		return nil, fmt.Errorf("%w: review status must be approved or rejected", domain.ErrInvalidProduct)
+	}
+
+	product, err := uc.productRepo.UpdateModeration(ctx, id, result)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to store moderation decision")
+		return nil, err
+	}
+
+	return product, nil
+}
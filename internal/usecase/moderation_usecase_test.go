@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockContentModerator struct {
+	mock.Mock
+}
+
+func (m *MockContentModerator) Moderate(ctx context.Context, product *domain.Product) (*domain.ModerationResult, error) {
+	args := m.Called(ctx, product)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ModerationResult), args.Error(1)
+}
+
+func TestModerationUseCase_Screen(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		verdict      *domain.ModerationResult
+		withReviewer bool
+		wantStatus   string
+		wantReason   string
+		wantErr      bool
+		errType      error
+	}{
+		{
+			name:       "approved without reviewer",
+			verdict:    &domain.ModerationResult{Status: domain.ModerationStatusApproved},
+			wantStatus: domain.ModerationStatusApproved,
+		},
+		{
+			name:         "pending when reviewer configured",
+			verdict:      &domain.ModerationResult{Status: domain.ModerationStatusApproved},
+			withReviewer: true,
+			wantStatus:   domain.ModerationStatusPending,
+		},
+		{
+			name:       "flagged for review",
+			verdict:    &domain.ModerationResult{Status: domain.ModerationStatusPending, Reason: "borderline"},
+			wantStatus: domain.ModerationStatusPending,
+			wantReason: "borderline",
+		},
+		{
+			name:    "rejected",
+			verdict: &domain.ModerationResult{Status: domain.ModerationStatusRejected, Reason: "blocked term"},
+			wantErr: true,
+			errType: domain.ErrContentRejected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			screener := &MockContentModerator{}
+			screener.On("Moderate", mock.Anything, mock.Anything).Return(tt.verdict, nil)
+
+			var reviewer ContentModerator
+			if tt.withReviewer {
+				reviewer = &MockContentModerator{}
+			}
+
+			uc := NewModerationUseCase(&MockProductRepository{}, []ContentModerator{screener}, reviewer, time.Second)
+			product := &domain.Product{StoreID: 1, Name: "Shirt"}
+
+			err := uc.Screen(ctx, product)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.errType)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, product.ModerationStatus)
+			assert.Equal(t, tt.wantReason, product.ModerationReason.String)
+		})
+	}
+}
+
+func TestModerationUseCase_Submit(t *testing.T) {
+
+	t.Run("stores reviewer verdict", func(t *testing.T) {
+		repo := &MockProductRepository{}
+		reviewer := &MockContentModerator{}
+		verdict := &domain.ModerationResult{Status: domain.ModerationStatusRejected, Reason: "spam"}
+		reviewer.On("Moderate", mock.Anything, mock.Anything).Return(verdict, nil)
+		repo.On("UpdateModeration", mock.Anything, int64(5), *verdict).Return(&domain.Product{ID: 5}, nil)
+
+		uc := NewModerationUseCase(repo, nil, reviewer, time.Second)
+		uc.Submit(&domain.Product{ID: 5, ModerationStatus: domain.ModerationStatusPending})
+		uc.Wait()
+
+		reviewer.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("reviewer failure leaves product pending", func(t *testing.T) {
+		repo := &MockProductRepository{}
+		reviewer := &MockContentModerator{}
+		reviewer.On("Moderate", mock.Anything, mock.Anything).Return(nil, errors.New("timeout"))
+
+		uc := NewModerationUseCase(repo, nil, reviewer, time.Second)
+		uc.Submit(&domain.Product{ID: 5, ModerationStatus: domain.ModerationStatusPending})
+		uc.Wait()
+
+		repo.AssertNotCalled(t, "UpdateModeration", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("flagged products skip the reviewer", func(t *testing.T) {
+		reviewer := &MockContentModerator{}
+
+		uc := NewModerationUseCase(&MockProductRepository{}, nil, reviewer, time.Second)
+		uc.Submit(&domain.Product{
+			ID:               5,
+			ModerationStatus: domain.ModerationStatusPending,
+			ModerationReason: sql.NullString{String: "borderline", Valid: true},
+		})
+		uc.Wait()
+
+		reviewer.AssertNotCalled(t, "Moderate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestModerationUseCase_ReviewProduct(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		id      int64
+		result  domain.ModerationResult
+		mockFn  func(*MockProductRepository)
+		wantErr bool
+		errType error
+	}{
+		{
+			name:   "approve",
+			id:     1,
+			result: domain.ModerationResult{Status: domain.ModerationStatusApproved},
+			mockFn: func(m *MockProductRepository) {
+				m.On("UpdateModeration", mock.Anything, int64(1), mock.Anything).Return(
+					&domain.Product{ID: 1, ModerationStatus: domain.ModerationStatusApproved}, nil)
+			},
+		},
+		{
+			name:    "pending is not a decision",
+			id:      1,
+			result:  domain.ModerationResult{Status: domain.ModerationStatusPending},
+			mockFn:  func(m *MockProductRepository) {},
+			wantErr: true,
+			errType: domain.ErrInvalidProduct,
+		},
+		{
+			name:   "product not found",
+			id:     2,
+			result: domain.ModerationResult{Status: domain.ModerationStatusRejected},
+			mockFn: func(m *MockProductRepository) {
+				m.On("UpdateModeration", mock.Anything, int64(2), mock.Anything).Return(nil, domain.ErrProductNotFound)
+			},
+			wantErr: true,
+			errType: domain.ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockProductRepository{}
+			tt.mockFn(repo)
+
+			uc := NewModerationUseCase(repo, nil, nil, time.Second)
+			_, err := uc.ReviewProduct(ctx, tt.id, tt.result)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.errType)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModerationUseCase_GetProductsForReview(t *testing.T) {
+
+	repo := &MockProductRepository{}
+	repo.On("GetByModerationStatus", mock.Anything, domain.ModerationStatusPending, 10, 0).Return([]*domain.Product{{ID: 1}}, nil)
+
+	uc := NewModerationUseCase(repo, nil, nil, time.Second)
+
+	products, err := uc.GetProductsForReview(context.Background(), "", 0, -1)
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+
+	_, err = uc.GetProductsForReview(context.Background(), "bogus", 10, 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// OrderUseCase places orders for a store's products and takes what they
+// order off stock.
+type OrderUseCase struct {
+	tx          database.TxManager
+	orderRepo   OrderRepository
+	productRepo ProductRepository
+	bundleRepo  BundleRepository
+	events      EventPublisher
+	clock       clock.Clock
+}
+
+// NewOrderUseCase builds the order use case. events may be nil when
+// nothing subscribes to stock changes.
+func NewOrderUseCase(tx database.TxManager, orderRepo OrderRepository, productRepo ProductRepository, bundleRepo BundleRepository, events EventPublisher, clk clock.Clock) *OrderUseCase {
+	return &OrderUseCase{
+		tx:          tx,
+		orderRepo:   orderRepo,
+		productRepo: productRepo,
+		bundleRepo:  bundleRepo,
+		events:      events,
+		clock:       clk,
+	}
+}
+
+// CreateOrder places the order and takes its items off stock in one
+// transaction, publishing a stock change for each product. Items must be
+// active products of the order's store, in an amount their unit allows.
+// Bundles are sold through SellBundle instead. If any product is short,
+// nothing is taken and ErrInsufficientStock is returned. The items are
+// checked and the events published in the same transaction, or in a
+// transaction of the caller's that it joins.
+func (uc *OrderUseCase) CreateOrder(ctx context.Context, order *domain.Order) (*domain.Order, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "create_order",
+		"store_id": order.StoreID,
+		"items":    len(order.Items),
+	}).Info("Creating order")
+
+	if err := order.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Order validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidOrder, err)
+	}
+
+	var updated []*domain.Product
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, item := range order.Items {
+			if err := uc.validateItem(ctx, order.StoreID, item); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if updated, err = uc.placeOrder(ctx, order); err != nil {
+			return err
+		}
+		uc.publishStockChanges(ctx, order, updated)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "create_order",
+		"order_id": order.ID,
+		"total":    order.Total,
+	}).Info("Order placed")
+
+	return order, nil
+}
+
+func (uc *OrderUseCase) GetOrder(ctx context.Context, id int64) (*domain.Order, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid order ID", domain.ErrInvalidOrder)
+	}
+
+	order, err := uc.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get order from repository")
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetOrders lists the store's orders, or every order when storeID is 0,
+// newest first.
+func (uc *OrderUseCase) GetOrders(ctx context.Context, storeID int64, limit, offset int) ([]*domain.Order, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	orders, err := uc.orderRepo.GetAll(ctx, storeID, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get orders from repository")
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// publishStockChanges publishes a stock change for each product the order
+// took stock from.
+func (uc *OrderUseCase) publishStockChanges(ctx context.Context, order *domain.Order, updated []*domain.Product) {
+	ordered := make(map[int64]domain.OrderItem, len(order.Items))
+	for _, item := range order.Items {
+		ordered[item.ProductID] = item
+	}
+	for _, product := range updated {
+		previous, err := product.Amount.Add(ordered[product.ID].Quantity)
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).WithField("product_id", product.ID).Warn("Failed to derive previous stock for event")
+			continue
+		}
+		emitEvent(ctx, uc.events, uc.clock, domain.ProductEvent{
+			Type:      domain.StockEventChanged,
+			StoreID:   product.StoreID,
+			ProductID: product.ID,
+			Stock: &domain.StockChange{
+				PreviousAmount: previous,
+				Amount:         product.Amount,
+				Unit:           product.Unit,
+			},
+		})
+	}
+}
+
+func (uc *OrderUseCase) placeOrder(ctx context.Context, order *domain.Order) ([]*domain.Product, error) {
+	updated, err := uc.orderRepo.Place(ctx, order)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to place order in repository")
+		switch {
+		case errors.Is(err, domain.ErrInsufficientStock):
+			return nil, err
+		case errors.Is(err, domain.ErrProductNotFound):
+			return nil, fmt.Errorf("%w: %s", domain.ErrInvalidOrder, err.Error())
+		}
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+	return updated, nil
+}
+
+func (uc *OrderUseCase) validateItem(ctx context.Context, storeID int64, item domain.OrderItem) error {
+	product, err := uc.productRepo.GetByID(ctx, item.ProductID)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return fmt.Errorf("%w: product %d not found", domain.ErrInvalidOrder, item.ProductID)
+	}
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get ordered product from repository")
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+	if product.StoreID != storeID {
+		return fmt.Errorf("%w: product %d belongs to another store", domain.ErrInvalidOrder, item.ProductID)
+	}
+	if product.Status != domain.ProductStatusActive {
+		return fmt.Errorf("%w: product %d is not active", domain.ErrInvalidOrder, item.ProductID)
+	}
+	if !product.IsPublished(uc.clock.Now()) {
+		return fmt.Errorf("%w: product %d is not published", domain.ErrInvalidOrder, item.ProductID)
+	}
+	if decimals, _ := domain.UnitDecimals(product.Unit); item.Quantity.Decimals() > decimals {
+		return fmt.Errorf("%w: quantity of product %d has too many decimals for unit %s", domain.ErrInvalidOrder, item.ProductID, product.Unit)
+	}
+
+	components, err := uc.bundleRepo.GetComponents(ctx, item.ProductID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get bundle components from repository")
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+	if len(components) > 0 {
+		return fmt.Errorf("%w: product %d is a bundle", domain.ErrInvalidOrder, item.ProductID)
+	}
+	return nil
+}
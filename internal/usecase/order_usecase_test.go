@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) Place(ctx context.Context, order *domain.Order) ([]*domain.Product, error) {
+	args := m.Called(ctx, order)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetByID(ctx context.Context, id int64) (*domain.Order, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) GetAll(ctx context.Context, storeID int64, limit, offset int) ([]*domain.Order, error) {
+	args := m.Called(ctx, storeID, limit, offset)
+	return args.Get(0).([]*domain.Order), args.Error(1)
+}
+
+func orderableProducts() *MockProductRepository {
+	launch := time.Now().Add(24 * time.Hour)
+	products := &MockProductRepository{}
+	products.On("GetByID", mock.Anything, int64(42)).Return(&domain.Product{ID: 42, StoreID: 7, Unit: domain.UnitPiece, Status: domain.ProductStatusActive}, nil)
+	products.On("GetByID", mock.Anything, int64(43)).Return(&domain.Product{ID: 43, StoreID: 7, Unit: domain.UnitKilogram, Status: domain.ProductStatusActive}, nil)
+	products.On("GetByID", mock.Anything, int64(44)).Return(&domain.Product{ID: 44, StoreID: 7, Unit: domain.UnitPiece, Status: domain.ProductStatusInactive}, nil)
+	products.On("GetByID", mock.Anything, int64(45)).Return(&domain.Product{ID: 45, StoreID: 7, Unit: domain.UnitPiece, Status: domain.ProductStatusActive, PublishAt: &launch}, nil)
+	products.On("GetByID", mock.Anything, int64(50)).Return(&domain.Product{ID: 50, StoreID: 7, Unit: domain.UnitPiece, Status: domain.ProductStatusActive}, nil)
+	products.On("GetByID", mock.Anything, int64(60)).Return(&domain.Product{ID: 60, StoreID: 8, Unit: domain.UnitPiece, Status: domain.ProductStatusActive}, nil)
+	products.On("GetByID", mock.Anything, int64(99)).Return(nil, domain.ErrProductNotFound)
+	return products
+}
+
+func orderBundles() *MockBundleRepository {
+	bundles := &MockBundleRepository{}
+	bundles.On("GetComponents", mock.Anything, int64(50)).Return([]domain.BundleComponent{{BundleID: 50, ProductID: 42, Quantity: quantity.New(1)}}, nil)
+	bundles.On("GetComponents", mock.Anything, mock.Anything).Return([]domain.BundleComponent{}, nil)
+	return bundles
+}
+
+func TestOrderUseCase_CreateOrder(t *testing.T) {
+	orders := &MockOrderRepository{}
+	orders.On("Place", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		order := args.Get(1).(*domain.Order)
+		order.ID = 31
+		order.Status = domain.OrderStatusPlaced
+	}).Return([]*domain.Product{
+		{ID: 42, StoreID: 7, Amount: quantity.New(3), Unit: domain.UnitPiece},
+		{ID: 43, StoreID: 7, Amount: quantity.MustParse("0.75"), Unit: domain.UnitKilogram},
+	}, nil)
+
+	events := &recordingPublisher{}
+	uc := NewOrderUseCase(database.NopTxManager{}, orders, orderableProducts(), orderBundles(), events, clock.Real())
+
+	order, err := uc.CreateOrder(context.Background(), &domain.Order{StoreID: 7, Items: []domain.OrderItem{
+		{ProductID: 43, Quantity: quantity.MustParse("1.25")},
+		{ProductID: 42, Quantity: quantity.New(2)},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(31), order.ID)
+	require.Len(t, events.events, 2)
+	assert.Equal(t, domain.StockEventChanged, events.events[1].Type)
+	assert.Equal(t, &domain.StockChange{PreviousAmount: quantity.New(2), Amount: quantity.MustParse("0.75"), Unit: domain.UnitKilogram}, events.events[1].Stock)
+}
+
+func TestOrderUseCase_CreateOrder_Rejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []domain.OrderItem
+		err   string
+	}{
+		{name: "no items", err: "at least one item"},
+		{name: "product listed twice", items: []domain.OrderItem{{ProductID: 42, Quantity: quantity.New(1)}, {ProductID: 42, Quantity: quantity.New(2)}}, err: "listed twice"},
+		{name: "zero quantity", items: []domain.OrderItem{{ProductID: 42}}, err: "must be positive"},
+		{name: "unknown product", items: []domain.OrderItem{{ProductID: 99, Quantity: quantity.New(1)}}, err: "product 99 not found"},
+		{name: "product of another store", items: []domain.OrderItem{{ProductID: 60, Quantity: quantity.New(1)}}, err: "another store"},
+		{name: "inactive product", items: []domain.OrderItem{{ProductID: 44, Quantity: quantity.New(1)}}, err: "not active"},
+		{name: "product not yet published", items: []domain.OrderItem{{ProductID: 45, Quantity: quantity.New(1)}}, err: "not published"},
+		{name: "fraction of a piece", items: []domain.OrderItem{{ProductID: 42, Quantity: quantity.MustParse("0.5")}}, err: "too many decimals"},
+		{name: "bundle", items: []domain.OrderItem{{ProductID: 50, Quantity: quantity.New(1)}}, err: "is a bundle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders := &MockOrderRepository{}
+			uc := NewOrderUseCase(database.NopTxManager{}, orders, orderableProducts(), orderBundles(), nil, clock.Real())
+
+			_, err := uc.CreateOrder(context.Background(), &domain.Order{StoreID: 7, Items: tt.items})
+
+			assert.ErrorIs(t, err, domain.ErrInvalidOrder)
+			assert.ErrorContains(t, err, tt.err)
+			orders.AssertNotCalled(t, "Place", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestOrderUseCase_CreateOrder_InsufficientStock(t *testing.T) {
+	orders := &MockOrderRepository{}
+	orders.On("Place", mock.Anything, mock.Anything).Return(nil, domain.ErrInsufficientStock)
+	events := &recordingPublisher{}
+	uc := NewOrderUseCase(database.NopTxManager{}, orders, orderableProducts(), orderBundles(), events, clock.Real())
+
+	_, err := uc.CreateOrder(context.Background(), &domain.Order{StoreID: 7, Items: []domain.OrderItem{{ProductID: 42, Quantity: quantity.New(20)}}})
+
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+	assert.Empty(t, events.events)
+}
+
+func TestOrderUseCase_GetOrders_ClampsLimit(t *testing.T) {
+	orders := &MockOrderRepository{}
+	orders.On("GetAll", mock.Anything, int64(7), 100, 0).Return([]*domain.Order{}, nil)
+
+	_, err := NewOrderUseCase(database.NopTxManager{}, orders, nil, nil, nil, clock.Real()).GetOrders(context.Background(), 7, 500, -3)
+
+	require.NoError(t, err)
+	orders.AssertExpectations(t)
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/logger"
+)
+
+// PreviewUseCase hands out preview links, which show a product to anyone
+// holding one, drafts included, until they expire.
+type PreviewUseCase struct {
+	productRepo ProductRepository
+	signer      PreviewSigner
+	defaultTTL  time.Duration
+	maxTTL      time.Duration
+}
+
+// NewPreviewUseCase issues tokens valid for defaultTTL unless asked for a
+// lifetime up to maxTTL.
+func NewPreviewUseCase(productRepo ProductRepository, signer PreviewSigner, defaultTTL, maxTTL time.Duration) *PreviewUseCase {
+	return &PreviewUseCase{
+		productRepo: productRepo,
+		signer:      signer,
+		defaultTTL:  defaultTTL,
+		maxTTL:      maxTTL,
+	}
+}
+
+// CreatePreviewToken signs a token for the product that expires after ttl,
+// or after the default lifetime when ttl is 0. Tokens cannot be revoked;
+// they only expire.
+func (uc *PreviewUseCase) CreatePreviewToken(ctx context.Context, productID int64, ttl time.Duration) (*domain.PreviewToken, error) {
+	if productID <= 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", domain.ErrInvalidProduct)
+	}
+	if ttl == 0 {
+		ttl = uc.defaultTTL
+	}
+	if ttl < 0 || ttl > uc.maxTTL {
+		return nil, fmt.Errorf("%w: must be positive and at most %s", domain.ErrInvalidPreviewTTL, uc.maxTTL)
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := uc.signer.Issue(productID, ttl)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to sign preview token")
+		return nil, fmt.Errorf("failed to create preview token: %w", err)
+	}
+
+	return &domain.PreviewToken{
+		ProductID: productID,
+		StoreID:   product.StoreID,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// GetPreview returns the product if token was issued for it and has not
+// expired.
+func (uc *PreviewUseCase) GetPreview(ctx context.Context, productID int64, token string) (*domain.Product, error) {
+	if productID <= 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", domain.ErrInvalidProduct)
+	}
+	if err := uc.signer.Verify(token, productID); err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidPreviewToken, err.Error())
+	}
+
+	return uc.productRepo.GetByID(ctx, productID)
+}
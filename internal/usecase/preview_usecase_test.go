@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/previewtoken"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewUseCase(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC))
+	signer := previewtoken.NewSigner([]byte("0123456789abcdef0123456789abcdef"), fake)
+	draft := &domain.Product{ID: 7, StoreID: 3, Name: "Spring collection", Status: domain.ProductStatusDraft}
+
+	repo := &MockProductRepository{}
+	repo.On("GetByID", mock.Anything, int64(7)).Return(draft, nil)
+	repo.On("GetByID", mock.Anything, int64(8)).Return(nil, domain.ErrProductNotFound)
+	uc := NewPreviewUseCase(repo, signer, 24*time.Hour, 7*24*time.Hour)
+
+	t.Run("issues a token for the product's default lifetime", func(t *testing.T) {
+		preview, err := uc.CreatePreviewToken(ctx, 7, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), preview.StoreID)
+		assert.Equal(t, fake.Now().Add(24*time.Hour), preview.ExpiresAt)
+
+		product, err := uc.GetPreview(ctx, 7, preview.Token)
+		require.NoError(t, err)
+		assert.Equal(t, draft, product)
+
+		_, err = uc.GetPreview(ctx, 8, preview.Token)
+		assert.ErrorIs(t, err, domain.ErrInvalidPreviewToken)
+	})
+
+	t.Run("rejects lifetimes past the maximum", func(t *testing.T) {
+		_, err := uc.CreatePreviewToken(ctx, 7, 8*24*time.Hour)
+		assert.ErrorIs(t, err, domain.ErrInvalidPreviewTTL)
+		_, err = uc.CreatePreviewToken(ctx, 7, -time.Hour)
+		assert.ErrorIs(t, err, domain.ErrInvalidPreviewTTL)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		_, err := uc.CreatePreviewToken(ctx, 8, time.Hour)
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		preview, err := uc.CreatePreviewToken(ctx, 7, time.Hour)
+		require.NoError(t, err)
+		fake.Advance(2 * time.Hour)
+		_, err = uc.GetPreview(ctx, 7, preview.Token)
+		assert.ErrorIs(t, err, domain.ErrInvalidPreviewToken)
+	})
+}
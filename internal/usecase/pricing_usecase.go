@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/logger"
+	"backend-context-engineering-template/pkg/pricing"
+	"github.com/sirupsen/logrus"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// defaultDecimals is used for currencies a store has no policy for.
+const defaultDecimals = 2
+
+// PricingUseCase computes promotional and converted prices and rounds them
+// by the store's pricing policy for the target currency.
+type PricingUseCase struct {
+	pricingRepo PricingRepository
+	productRepo ProductRepository
+}
+
+func NewPricingUseCase(pricingRepo PricingRepository, productRepo ProductRepository) *PricingUseCase {
+	return &PricingUseCase{
+		pricingRepo: pricingRepo,
+		productRepo: productRepo,
+	}
+}
+
+func (uc *PricingUseCase) GetPolicies(ctx context.Context, storeID int64) ([]*domain.PricingPolicy, error) {
+	if storeID <= 0 {
+		return nil, fmt.Errorf("%w: invalid store ID", domain.ErrInvalidPricingPolicy)
+	}
+
+	policies, err := uc.pricingRepo.GetPolicies(ctx, storeID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get pricing policies from repository")
+		return nil, fmt.Errorf("failed to get pricing policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+func (uc *PricingUseCase) SavePolicy(ctx context.Context, policy *domain.PricingPolicy) (*domain.PricingPolicy, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "save_pricing_policy",
+		"store_id": policy.StoreID,
+		"currency": policy.Currency,
+	}).Info("Saving pricing policy")
+
+	if err := validatePricingPolicy(policy); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Pricing policy validation failed")
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidPricingPolicy, err.Error())
+	}
+
+	saved, err := uc.pricingRepo.SavePolicy(ctx, policy)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to save pricing policy in repository")
+		return nil, fmt.Errorf("failed to save pricing policy: %w", err)
+	}
+
+	return saved, nil
+}
+
+func (uc *PricingUseCase) DeletePolicy(ctx context.Context, storeID int64, currency string) error {
+	if storeID <= 0 || !currencyCode.MatchString(currency) {
+		return fmt.Errorf("%w: invalid store ID or currency", domain.ErrInvalidPricingPolicy)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "delete_pricing_policy",
+		"store_id": storeID,
+		"currency": currency,
+	}).Info("Deleting pricing policy")
+
+	if err := uc.pricingRepo.DeletePolicy(ctx, storeID, currency); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to delete pricing policy from repository")
+		return err
+	}
+
+	return nil
+}
+
+// QuotePrice converts the product's price at the given rate, applies the
+// discount, and rounds the result by the store's policy for the currency.
+// Without a policy the price is only rounded to the cent.
+func (uc *PricingUseCase) QuotePrice(ctx context.Context, productID int64, adjustment domain.PriceAdjustment) (*domain.PriceQuote, error) {
+	if productID <= 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", domain.ErrInvalidPriceQuote)
+	}
+	if !currencyCode.MatchString(adjustment.Currency) {
+		return nil, fmt.Errorf("%w: currency must be a three-letter ISO 4217 code", domain.ErrInvalidPriceQuote)
+	}
+	if adjustment.Rate <= 0 {
+		return nil, fmt.Errorf("%w: rate must be positive", domain.ErrInvalidPriceQuote)
+	}
+	if adjustment.DiscountPercent < 0 || adjustment.DiscountPercent >= 100 {
+		return nil, fmt.Errorf("%w: discount_percent must be at least 0 and below 100", domain.ErrInvalidPriceQuote)
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get product from repository")
+		return nil, err
+	}
+
+	policy, err := uc.pricingRepo.GetPolicy(ctx, product.StoreID, adjustment.Currency)
+	if errors.Is(err, domain.ErrPricingPolicyNotFound) {
+		policy = &domain.PricingPolicy{
+			StoreID:   product.StoreID,
+			Currency:  adjustment.Currency,
+			Rounding:  pricing.RoundNone,
+			Direction: pricing.DirectionNearest,
+			Decimals:  defaultDecimals,
+		}
+	} else if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get pricing policy from repository")
+		return nil, fmt.Errorf("failed to get pricing policy: %w", err)
+	}
+
+	computed := product.Price * adjustment.Rate * (100 - adjustment.DiscountPercent) / 100
+	unrounded, err := pricing.ToMinor(computed, policy.Decimals)
+	if err != nil {
+		return nil, fmt.Errorf("%w: computed price: %s", domain.ErrInvalidPriceQuote, err.Error())
+	}
+
+	return &domain.PriceQuote{
+		ProductID:  product.ID,
+		StoreID:    product.StoreID,
+		BasePrice:  product.Price,
+		Adjustment: adjustment,
+		Policy:     *policy,
+		Unrounded:  unrounded,
+		Price:      roundingRule(policy).Apply(unrounded),
+	}, nil
+}
+
+func validatePricingPolicy(policy *domain.PricingPolicy) error {
+	if policy.StoreID <= 0 {
+		return errors.New("store_id must be positive")
+	}
+	if !currencyCode.MatchString(policy.Currency) {
+		return errors.New("currency must be a three-letter ISO 4217 code")
+	}
+	if policy.Decimals < 0 || policy.Decimals > pricing.MaxDecimals {
+		return fmt.Errorf("decimals must be between 0 and %d", pricing.MaxDecimals)
+	}
+	return roundingRule(policy).Validate()
+}
+
+func roundingRule(policy *domain.PricingPolicy) pricing.Rule {
+	return pricing.Rule{
+		Strategy:  policy.Rounding,
+		Step:      policy.Step,
+		Ending:    policy.Ending,
+		Direction: policy.Direction,
+	}
+}
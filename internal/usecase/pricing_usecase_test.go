@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/pricing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPricingRepository struct {
+	mock.Mock
+}
+
+func (m *MockPricingRepository) GetPolicy(ctx context.Context, storeID int64, currency string) (*domain.PricingPolicy, error) {
+	args := m.Called(ctx, storeID, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PricingPolicy), args.Error(1)
+}
+
+func (m *MockPricingRepository) GetPolicies(ctx context.Context, storeID int64) ([]*domain.PricingPolicy, error) {
+	args := m.Called(ctx, storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PricingPolicy), args.Error(1)
+}
+
+func (m *MockPricingRepository) SavePolicy(ctx context.Context, policy *domain.PricingPolicy) (*domain.PricingPolicy, error) {
+	args := m.Called(ctx, policy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PricingPolicy), args.Error(1)
+}
+
+func (m *MockPricingRepository) DeletePolicy(ctx context.Context, storeID int64, currency string) error {
+	args := m.Called(ctx, storeID, currency)
+	return args.Error(0)
+}
+
+func TestPricingUseCase_SavePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		policy  *domain.PricingPolicy
+		wantErr bool
+	}{
+		{
+			name:   "increment",
+			policy: &domain.PricingPolicy{StoreID: 1, Currency: "CHF", Rounding: pricing.RoundIncrement, Step: 5, Direction: pricing.DirectionNearest, Decimals: 2},
+		},
+		{
+			name:   "charm ending",
+			policy: &domain.PricingPolicy{StoreID: 1, Currency: "USD", Rounding: pricing.RoundEnding, Ending: 99, Direction: pricing.DirectionUp, Decimals: 2},
+		},
+		{
+			name:   "zero decimal currency",
+			policy: &domain.PricingPolicy{StoreID: 1, Currency: "JPY", Rounding: pricing.RoundIncrement, Step: 10, Direction: pricing.DirectionDown, Decimals: 0},
+		},
+		{
+			name:    "lowercase currency",
+			policy:  &domain.PricingPolicy{StoreID: 1, Currency: "chf", Rounding: pricing.RoundNone, Direction: pricing.DirectionNearest, Decimals: 2},
+			wantErr: true,
+		},
+		{
+			name:    "too many decimals",
+			policy:  &domain.PricingPolicy{StoreID: 1, Currency: "BTC", Rounding: pricing.RoundNone, Direction: pricing.DirectionNearest, Decimals: 8},
+			wantErr: true,
+		},
+		{
+			name:    "increment without step",
+			policy:  &domain.PricingPolicy{StoreID: 1, Currency: "CHF", Rounding: pricing.RoundIncrement, Direction: pricing.DirectionNearest, Decimals: 2},
+			wantErr: true,
+		},
+		{
+			name:    "invalid store",
+			policy:  &domain.PricingPolicy{Currency: "CHF", Rounding: pricing.RoundNone, Direction: pricing.DirectionNearest, Decimals: 2},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockPricingRepository{}
+			if !tt.wantErr {
+				repo.On("SavePolicy", mock.Anything, tt.policy).Return(tt.policy, nil)
+			}
+
+			uc := NewPricingUseCase(repo, &MockProductRepository{})
+			_, err := uc.SavePolicy(ctx, tt.policy)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidPricingPolicy)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPricingUseCase_QuotePrice(t *testing.T) {
+	ctx := context.Background()
+	product := &domain.Product{ID: 1, StoreID: 7, Name: "Lamp", Price: 49.90}
+
+	tests := []struct {
+		name          string
+		adjustment    domain.PriceAdjustment
+		policy        *domain.PricingPolicy
+		wantUnrounded int64
+		wantPrice     int64
+	}{
+		{
+			name:          "no policy rounds to the cent",
+			adjustment:    domain.PriceAdjustment{Currency: "EUR", Rate: 1, DiscountPercent: 15},
+			wantUnrounded: 4242,
+			wantPrice:     4242,
+		},
+		{
+			name:          "five centime increment",
+			adjustment:    domain.PriceAdjustment{Currency: "CHF", Rate: 0.93, DiscountPercent: 0},
+			policy:        &domain.PricingPolicy{StoreID: 7, Currency: "CHF", Rounding: pricing.RoundIncrement, Step: 5, Direction: pricing.DirectionNearest, Decimals: 2},
+			wantUnrounded: 4641,
+			wantPrice:     4640,
+		},
+		{
+			name:          "charm ending rounds down",
+			adjustment:    domain.PriceAdjustment{Currency: "USD", Rate: 1.1, DiscountPercent: 20},
+			policy:        &domain.PricingPolicy{StoreID: 7, Currency: "USD", Rounding: pricing.RoundEnding, Ending: 99, Direction: pricing.DirectionDown, Decimals: 2},
+			wantUnrounded: 4391,
+			wantPrice:     4299,
+		},
+		{
+			name:          "zero decimal currency",
+			adjustment:    domain.PriceAdjustment{Currency: "JPY", Rate: 161.37, DiscountPercent: 0},
+			policy:        &domain.PricingPolicy{StoreID: 7, Currency: "JPY", Rounding: pricing.RoundIncrement, Step: 10, Direction: pricing.DirectionUp, Decimals: 0},
+			wantUnrounded: 8052,
+			wantPrice:     8060,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products := &MockProductRepository{}
+			products.On("GetByID", mock.Anything, int64(1)).Return(product, nil)
+			repo := &MockPricingRepository{}
+			if tt.policy != nil {
+				repo.On("GetPolicy", mock.Anything, int64(7), tt.adjustment.Currency).Return(tt.policy, nil)
+			} else {
+				repo.On("GetPolicy", mock.Anything, int64(7), tt.adjustment.Currency).Return(nil, domain.ErrPricingPolicyNotFound)
+			}
+
+			uc := NewPricingUseCase(repo, products)
+			quote, err := uc.QuotePrice(ctx, 1, tt.adjustment)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUnrounded, quote.Unrounded)
+			assert.Equal(t, tt.wantPrice, quote.Price)
+			assert.Equal(t, tt.adjustment.Currency, quote.Policy.Currency)
+			assert.Equal(t, 49.90, quote.BasePrice)
+		})
+	}
+}
+
+func TestPricingUseCase_QuotePrice_Invalid(t *testing.T) {
+	uc := NewPricingUseCase(&MockPricingRepository{}, &MockProductRepository{})
+
+	for _, adjustment := range []domain.PriceAdjustment{
+		{Currency: "usd", Rate: 1},
+		{Currency: "USD", Rate: 0},
+		{Currency: "USD", Rate: 1, DiscountPercent: -5},
+		{Currency: "USD", Rate: 1, DiscountPercent: 100},
+	} {
+		_, err := uc.QuotePrice(context.Background(), 1, adjustment)
+		assert.ErrorIs(t, err, domain.ErrInvalidPriceQuote)
+	}
+}
+
+func TestPricingUseCase_QuotePrice_OutOfRange(t *testing.T) {
+	productRepo := &MockProductRepository{}
+	productRepo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, StoreID: 7, Price: 49.90}, nil)
+	pricingRepo := &MockPricingRepository{}
+	pricingRepo.On("GetPolicy", mock.Anything, int64(7), "USD").Return(nil, domain.ErrPricingPolicyNotFound)
+
+	uc := NewPricingUseCase(pricingRepo, productRepo)
+
+	for _, rate := range []float64{math.NaN(), math.Inf(1), 1e300} {
+		quote, err := uc.QuotePrice(context.Background(), 1, domain.PriceAdjustment{Currency: "USD", Rate: rate})
+		assert.ErrorIs(t, err, domain.ErrInvalidPriceQuote, "rate %v", rate)
+		assert.Nil(t, quote, "a price that cannot be computed must not be quoted")
+	}
+}
+
+func TestPricingUseCase_DeletePolicy(t *testing.T) {
+	repo := &MockPricingRepository{}
+	repo.On("DeletePolicy", mock.Anything, int64(2), "CHF").Return(domain.ErrPricingPolicyNotFound)
+
+	uc := NewPricingUseCase(repo, &MockProductRepository{})
+
+	assert.ErrorIs(t, uc.DeletePolicy(context.Background(), 2, "CHF"), domain.ErrPricingPolicyNotFound)
+	assert.ErrorIs(t, uc.DeletePolicy(context.Background(), 2, "chf"), domain.ErrInvalidPricingPolicy)
+	repo.AssertExpectations(t)
+}
@@ -2,43 +2,83 @@ package usecase
 
 import (
 	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 
 	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/idgen"
+	"backend-context-engineering-template/pkg/logger"
+	"backend-context-engineering-template/pkg/richtext"
 	"github.com/sirupsen/logrus"
 )
 
+const streamBatchSize = 500
+
 type ProductUseCase struct {
+	tx          database.TxManager
 	productRepo ProductRepository
-	logger      *logrus.Logger
+	moderator   ProductModerator
+	monitor     MutationMonitor
+	events      EventPublisher
+	clock       clock.Clock
 }
 
-func NewProductUseCase(productRepo ProductRepository, logger *logrus.Logger) *ProductUseCase {
+// NewProductUseCase builds the product use case. tx runs each write in one
+// transaction with the events it publishes. moderator may be nil, in which case product content is not
+// moderated, and monitor may be nil to skip mutation rate tracking. events
+// may be nil when nothing subscribes to product changes.
+func NewProductUseCase(tx database.TxManager, productRepo ProductRepository, moderator ProductModerator, monitor MutationMonitor, events EventPublisher, clk clock.Clock) *ProductUseCase {
 	return &ProductUseCase{
+		tx:          tx,
 		productRepo: productRepo,
-		logger:      logger,
+		moderator:   moderator,
+		monitor:     monitor,
+		events:      events,
+		clock:       clk,
 	}
 }
 
 func (uc *ProductUseCase) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	uc.logger.WithFields(logrus.Fields{
+	logger.FromContext(ctx).WithFields(logrus.Fields{
 		"action":   "create_product",
 		"store_id": product.StoreID,
 		"name":     product.Name,
 	}).Info("Creating new product")
 
 	if err := product.Validate(); err != nil {
-		uc.logger.WithError(err).Error("Product validation failed")
-		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidProduct, err.Error())
+		logger.FromContext(ctx).WithError(err).Error("Product validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidProduct, err)
 	}
+	normalizeDescription(product)
 
-	createdProduct, err := uc.productRepo.Create(ctx, product)
+	if err := uc.screen(ctx, product); err != nil {
+		return nil, err
+	}
+
+	var createdProduct *domain.Product
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if createdProduct, err = uc.productRepo.Create(ctx, product); err != nil {
+			return err
+		}
+		uc.publish(ctx, domain.ProductEventCreated, createdProduct)
+		return nil
+	})
 	if err != nil {
-		uc.logger.WithError(err).Error("Failed to create product in repository")
+		logger.FromContext(ctx).WithError(err).Error("Failed to create product in repository")
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
 
-	uc.logger.WithFields(logrus.Fields{
+	if uc.moderator != nil {
+		uc.moderator.Submit(createdProduct)
+	}
+	uc.recordMutation(ctx, createdProduct.StoreID, domain.MutationCreate)
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
 		"action":     "create_product",
 		"product_id": createdProduct.ID,
 	}).Info("Product created successfully")
@@ -47,7 +87,7 @@ func (uc *ProductUseCase) CreateProduct(ctx context.Context, product *domain.Pro
 }
 
 func (uc *ProductUseCase) GetProduct(ctx context.Context, id int64) (*domain.Product, error) {
-	uc.logger.WithFields(logrus.Fields{
+	logger.FromContext(ctx).WithFields(logrus.Fields{
 		"action":     "get_product",
 		"product_id": id,
 	}).Info("Retrieving product")
@@ -58,20 +98,61 @@ func (uc *ProductUseCase) GetProduct(ctx context.Context, id int64) (*domain.Pro
 
 	product, err := uc.productRepo.GetByID(ctx, id)
 	if err != nil {
-		uc.logger.WithError(err).Error("Failed to get product from repository")
+		logger.FromContext(ctx).WithError(err).Error("Failed to get product from repository")
 		return nil, err
 	}
 
 	return product, nil
 }
 
-func (uc *ProductUseCase) GetProducts(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
-	uc.logger.WithFields(logrus.Fields{
-		"action": "get_products",
-		"limit":  limit,
-		"offset": offset,
+// GetProducts lists the products published now, drafts aside, that pass
+// the filter, in the order the filter asks for.
+func (uc *ProductUseCase) GetProducts(ctx context.Context, filter domain.ProductFilter, limit, offset int) ([]*domain.Product, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "get_products",
+		"filtered": !filter.IsEmpty(),
+		"limit":    limit,
+		"offset":   offset,
 	}).Info("Retrieving products")
 
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidProduct, err)
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	products, err := uc.productRepo.GetAll(ctx, filter, uc.clock.Now(), limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get products from repository")
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	return products, nil
+}
+
+// GetProductsByAvailability lists the products published now, drafts
+// aside, with the given availability at the time of the call, newest
+// first.
+func (uc *ProductUseCase) GetProductsByAvailability(ctx context.Context, availability string, limit, offset int) ([]*domain.Product, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":       "get_products_by_availability",
+		"availability": availability,
+		"limit":        limit,
+		"offset":       offset,
+	}).Info("Retrieving products by availability")
+
+	if !domain.IsValidAvailability(availability) {
+		return nil, fmt.Errorf("%w: unknown availability %q", domain.ErrInvalidProduct, availability)
+	}
+
 	if limit <= 0 {
 		limit = 10
 	}
@@ -82,17 +163,50 @@ func (uc *ProductUseCase) GetProducts(ctx context.Context, limit, offset int) ([
 		offset = 0
 	}
 
-	products, err := uc.productRepo.GetAll(ctx, limit, offset)
+	products, err := uc.productRepo.GetByAvailability(ctx, availability, uc.clock.Now(), limit, offset)
 	if err != nil {
-		uc.logger.WithError(err).Error("Failed to get products from repository")
+		logger.FromContext(ctx).WithError(err).Error("Failed to get products by availability from repository")
 		return nil, fmt.Errorf("failed to get products: %w", err)
 	}
 
 	return products, nil
 }
 
+// StreamProducts walks every product listed when the stream starts in
+// id order, fetching in batches so memory use stays flat regardless of
+// catalog size. Iteration stops at the first error returned by fn.
+func (uc *ProductUseCase) StreamProducts(ctx context.Context, fn func(*domain.Product) error) error {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action": "stream_products",
+	}).Info("Streaming products")
+
+	now := uc.clock.Now()
+	var afterID int64
+	for {
+		products, err := uc.productRepo.GetAfterID(ctx, afterID, streamBatchSize)
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).Error("Failed to get products from repository")
+			return fmt.Errorf("failed to stream products: %w", err)
+		}
+
+		for _, product := range products {
+			afterID = product.ID
+			if !product.IsListed(now) {
+				continue
+			}
+			if err := fn(product); err != nil {
+				return err
+			}
+		}
+
+		if len(products) < streamBatchSize {
+			return nil
+		}
+	}
+}
+
 func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id int64, product *domain.Product) (*domain.Product, error) {
-	uc.logger.WithFields(logrus.Fields{
+	logger.FromContext(ctx).WithFields(logrus.Fields{
 		"action":     "update_product",
 		"product_id": id,
 	}).Info("Updating product")
@@ -102,17 +216,47 @@ func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id int64, product *
 	}
 
 	if err := product.Validate(); err != nil {
-		uc.logger.WithError(err).Error("Product validation failed")
-		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidProduct, err.Error())
+		logger.FromContext(ctx).WithError(err).Error("Product validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidProduct, err)
 	}
+	normalizeDescription(product)
 
-	updatedProduct, err := uc.productRepo.Update(ctx, id, product)
+	if err := uc.screen(ctx, product); err != nil {
+		return nil, err
+	}
+
+	// The previous state is only loaded to tell subscribers how stock
+	// changed.
+	var previous *domain.Product
+	if uc.events != nil {
+		var err error
+		previous, err = uc.productRepo.GetByID(ctx, id)
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).Error("Failed to get product from repository")
+			return nil, err
+		}
+	}
+
+	var updatedProduct *domain.Product
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if updatedProduct, err = uc.productRepo.Update(ctx, id, product); err != nil {
+			return err
+		}
+		uc.publishUpdate(ctx, previous, updatedProduct)
+		return nil
+	})
 	if err != nil {
-		uc.logger.WithError(err).Error("Failed to update product in repository")
+		logger.FromContext(ctx).WithError(err).Error("Failed to update product in repository")
 		return nil, err
 	}
 
-	uc.logger.WithFields(logrus.Fields{
+	if uc.moderator != nil {
+		uc.moderator.Submit(updatedProduct)
+	}
+	uc.recordMutation(ctx, updatedProduct.StoreID, domain.MutationUpdate)
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
 		"action":     "update_product",
 		"product_id": updatedProduct.ID,
 	}).Info("Product updated successfully")
@@ -120,8 +264,85 @@ func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id int64, product *
 	return updatedProduct, nil
 }
 
+// UpdateProductPartial writes only the fields the patch sets, so a client
+// can change the price or the amount without resending the product and
+// without overwriting concurrent changes to other fields. The product is
+// validated as it will be after the patch. Moderation screens it again
+// only when the patch changes its name or description.
+func (uc *ProductUseCase) UpdateProductPartial(ctx context.Context, id int64, patch *domain.ProductPatch) (*domain.Product, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":     "patch_product",
+		"product_id": id,
+	}).Info("Patching product")
+
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", domain.ErrInvalidProduct)
+	}
+	if patch.IsEmpty() {
+		return nil, fmt.Errorf("%w: the patch sets no fields", domain.ErrInvalidProduct)
+	}
+
+	previous, err := uc.productRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get product from repository")
+		return nil, err
+	}
+
+	patched := *previous
+	patch.Apply(&patched)
+	if err := patched.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Product validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidProduct, err)
+	}
+
+	write := *patch
+	if patch.ChangesContent() {
+		// The description is stored as normalized for its format, which
+		// either field of the patch may have changed.
+		normalizeDescription(&patched)
+		write.Description = &patched.Description
+		write.DescriptionFormat = &patched.DescriptionFormat
+
+		if err := uc.screen(ctx, &patched); err != nil {
+			return nil, err
+		}
+		if uc.moderator != nil {
+			write.Moderation = &domain.ModerationResult{
+				Status: patched.ModerationStatus,
+				Reason: patched.ModerationReason.String,
+			}
+		}
+	}
+
+	var updatedProduct *domain.Product
+	err = uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if updatedProduct, err = uc.productRepo.Patch(ctx, id, &write); err != nil {
+			return err
+		}
+		uc.publishUpdate(ctx, previous, updatedProduct)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to patch product in repository")
+		return nil, err
+	}
+
+	if uc.moderator != nil && write.Moderation != nil {
+		uc.moderator.Submit(updatedProduct)
+	}
+	uc.recordMutation(ctx, updatedProduct.StoreID, domain.MutationUpdate)
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":     "patch_product",
+		"product_id": updatedProduct.ID,
+	}).Info("Product patched successfully")
+
+	return updatedProduct, nil
+}
+
 func (uc *ProductUseCase) DeleteProduct(ctx context.Context, id int64) error {
-	uc.logger.WithFields(logrus.Fields{
+	logger.FromContext(ctx).WithFields(logrus.Fields{
 		"action":     "delete_product",
 		"product_id": id,
 	}).Info("Deleting product")
@@ -130,15 +351,373 @@ func (uc *ProductUseCase) DeleteProduct(ctx context.Context, id int64) error {
 		return fmt.Errorf("%w: invalid product ID", domain.ErrInvalidProduct)
 	}
 
-	if err := uc.productRepo.Delete(ctx, id); err != nil {
-		uc.logger.WithError(err).Error("Failed to delete product from repository")
+	// The product is only loaded when something needs its store or its
+	// last state.
+	var product *domain.Product
+	var storeID int64
+	if uc.monitor != nil || uc.events != nil {
+		var err error
+		product, err = uc.productRepo.GetByID(ctx, id)
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).Error("Failed to get product from repository")
+			return err
+		}
+		storeID = product.StoreID
+	}
+
+	if uc.monitor != nil && uc.monitor.RequiresConfirmation(storeID, domain.MutationDelete) && !MassOperationConfirmed(ctx) {
+		logger.FromContext(ctx).WithFields(logrus.Fields{
+			"action":   "delete_product",
+			"store_id": storeID,
+		}).Warn("Delete blocked pending mass operation confirmation")
+		return fmt.Errorf("%w: unusual delete rate for store %d", domain.ErrConfirmationRequired, storeID)
+	}
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.productRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if product != nil {
+			uc.publish(ctx, domain.ProductEventDeleted, product)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to delete product from repository")
 		return err
 	}
+	uc.recordMutation(ctx, storeID, domain.MutationDelete)
 
-	uc.logger.WithFields(logrus.Fields{
+	logger.FromContext(ctx).WithFields(logrus.Fields{
 		"action":     "delete_product",
 		"product_id": id,
 	}).Info("Product deleted successfully")
 
 	return nil
 }
+
+// WriteProducts creates the items without an ID and replaces the others,
+// all in one transaction: either every item is saved or none is. Each item
+// is validated and moderated like a single create or update first. If any
+// is refused, a *domain.BulkWriteError lists them all by index. An update
+// the database refuses, of a missing product for instance, stops the write
+// and is the only item listed; a missing store of a created product refuses
+// the write as a whole with ErrStoreNotFound. Results are in the order of
+// items.
+func (uc *ProductUseCase) WriteProducts(ctx context.Context, items []domain.BulkProductItem) ([]*domain.BulkProductResult, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action": "write_products",
+		"items":  len(items),
+	}).Info("Writing products in bulk")
+
+	if len(items) == 0 || len(items) > domain.MaxBulkProducts {
+		return nil, fmt.Errorf("%w: a bulk write takes 1 to %d products", domain.ErrInvalidProduct, domain.MaxBulkProducts)
+	}
+
+	var refused []domain.BulkItemError
+	updated := make(map[int64]int, len(items))
+	for i, item := range items {
+		err := uc.prepareBulkItem(ctx, item, updated)
+		if err == nil {
+			if item.ID != 0 {
+				updated[item.ID] = i
+			}
+			continue
+		}
+		if !isBulkItemError(err) {
+			return nil, err
+		}
+		refused = append(refused, domain.BulkItemError{Index: i, Err: err})
+	}
+	if len(refused) > 0 {
+		logger.FromContext(ctx).WithField("refused", len(refused)).Warn("Bulk product write refused")
+		return nil, &domain.BulkWriteError{Items: refused}
+	}
+
+	results := make([]*domain.BulkProductResult, len(items))
+	previous := make([]*domain.Product, len(items))
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var creates []*domain.Product
+		var createdAt []int
+		for i, item := range items {
+			if item.ID == 0 {
+				creates = append(creates, item.Product)
+				createdAt = append(createdAt, i)
+				continue
+			}
+
+			// As in UpdateProduct, the previous state is only loaded to
+			// tell subscribers how stock changed.
+			if uc.events != nil {
+				product, err := uc.productRepo.GetByID(ctx, item.ID)
+				if err != nil {
+					return bulkItemError(i, err)
+				}
+				previous[i] = product
+			}
+			product, err := uc.productRepo.Update(ctx, item.ID, item.Product)
+			if err != nil {
+				return bulkItemError(i, err)
+			}
+			results[i] = &domain.BulkProductResult{Product: product}
+		}
+
+		if len(creates) > 0 {
+			created, err := uc.productRepo.CreateMany(ctx, creates)
+			if err != nil {
+				return fmt.Errorf("failed to create products: %w", err)
+			}
+			for j, product := range created {
+				results[createdAt[j]] = &domain.BulkProductResult{Product: product, Created: true}
+			}
+		}
+
+		for i, result := range results {
+			if result.Created {
+				uc.publish(ctx, domain.ProductEventCreated, result.Product)
+			} else {
+				uc.publishUpdate(ctx, previous[i], result.Product)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to write products in bulk")
+		return nil, err
+	}
+
+	database.AfterCommit(ctx, func() {
+		for _, result := range results {
+			if uc.moderator != nil {
+				uc.moderator.Submit(result.Product)
+			}
+			if result.Created {
+				uc.recordMutation(ctx, result.Product.StoreID, domain.MutationCreate)
+			} else {
+				uc.recordMutation(ctx, result.Product.StoreID, domain.MutationUpdate)
+			}
+		}
+	})
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action": "write_products",
+		"items":  len(items),
+	}).Info("Products written in bulk")
+
+	return results, nil
+}
+
+// prepareBulkItem validates, normalizes and moderates one item of a bulk
+// write. updated maps the products updated by earlier items to their
+// index.
+func (uc *ProductUseCase) prepareBulkItem(ctx context.Context, item domain.BulkProductItem, updated map[int64]int) error {
+	if item.ID < 0 {
+		return fmt.Errorf("%w: invalid product ID", domain.ErrInvalidProduct)
+	}
+	if first, ok := updated[item.ID]; ok {
+		return fmt.Errorf("%w: product %d is also written by item %d", domain.ErrInvalidProduct, item.ID, first)
+	}
+	if item.Product == nil {
+		return fmt.Errorf("%w: product is missing", domain.ErrInvalidProduct)
+	}
+	if err := item.Product.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrInvalidProduct, err)
+	}
+	normalizeDescription(item.Product)
+	return uc.screen(ctx, item.Product)
+}
+
+// isBulkItemError reports whether err refuses one item of a bulk write
+// rather than the whole write.
+func isBulkItemError(err error) bool {
+	for _, target := range []error{
+		domain.ErrInvalidProduct,
+		domain.ErrContentRejected,
+		domain.ErrProductNotFound,
+		domain.ErrDuplicateProduct,
+		domain.ErrStoreNotFound,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// bulkItemError attributes err to the item at index when it refuses only
+// that item.
+func bulkItemError(index int, err error) error {
+	if !isBulkItemError(err) {
+		return err
+	}
+	return &domain.BulkWriteError{Items: []domain.BulkItemError{{Index: index, Err: err}}}
+}
+
+// ImportProducts reads a CSV of domain.ProductCSVColumns row by row, so
+// files of any size are read in constant memory. Rows with an id replace
+// that product and the others are created, each like a single create or
+// update. A row that cannot be read or is refused is reported by line
+// and the import goes on. A header without the columns a product needs
+// fails the import with ErrInvalidProductImport. Any other error stops
+// the import; the rows saved before it stay saved.
+func (uc *ProductUseCase) ImportProducts(ctx context.Context, file io.Reader) (*domain.ProductImportReport, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action": "import_products",
+	}).Info("Importing products")
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	// Rows may be ragged; missing cells read as empty.
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	record, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: the file is empty", domain.ErrInvalidProductImport)
+		}
+		return nil, fmt.Errorf("%w: failed to read csv header: %s", domain.ErrInvalidProductImport, err.Error())
+	}
+	header, err := domain.ParseProductCSVHeader(record)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidProductImport, err.Error())
+	}
+
+	report := &domain.ProductImportReport{Errors: []domain.ProductImportError{}}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report.Failed++
+			report.Errors = append(report.Errors, domain.ProductImportError{Line: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).WithField("created", report.Created).WithField("updated", report.Updated).Error("Product import aborted")
+			return nil, fmt.Errorf("failed to read product import: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		created, err := uc.importRow(ctx, header, record)
+		if err != nil {
+			if !isBulkItemError(err) {
+				logger.FromContext(ctx).WithError(err).WithField("created", report.Created).WithField("updated", report.Updated).Error("Product import aborted")
+				return nil, err
+			}
+			report.Failed++
+			report.Errors = append(report.Errors, domain.ProductImportError{Line: line, Error: err.Error()})
+			continue
+		}
+		if created {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":  "import_products",
+		"created": report.Created,
+		"updated": report.Updated,
+		"failed":  report.Failed,
+	}).Info("Products imported")
+
+	return report, nil
+}
+
+// importRow saves the product of one import row and reports whether it
+// was created.
+func (uc *ProductUseCase) importRow(ctx context.Context, header *domain.ProductCSVHeader, record []string) (bool, error) {
+	id, product, err := header.Product(record)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", domain.ErrInvalidProduct, err.Error())
+	}
+	if id == 0 {
+		_, err = uc.CreateProduct(ctx, product)
+		return true, err
+	}
+	_, err = uc.UpdateProduct(ctx, id, product)
+	return false, err
+}
+
+func (uc *ProductUseCase) screen(ctx context.Context, product *domain.Product) error {
+	if uc.moderator == nil {
+		return nil
+	}
+
+	if err := uc.moderator.Screen(ctx, product); err != nil {
+		logger.FromContext(ctx).WithError(err).Warn("Product content did not pass moderation")
+		return err
+	}
+	return nil
+}
+
+func (uc *ProductUseCase) recordMutation(ctx context.Context, storeID int64, kind string) {
+	if uc.monitor != nil {
+		uc.monitor.Record(ctx, storeID, kind)
+	}
+}
+
+func (uc *ProductUseCase) publish(ctx context.Context, eventType string, product *domain.Product) {
+	uc.emit(ctx, domain.ProductEvent{
+		Type:      eventType,
+		StoreID:   product.StoreID,
+		ProductID: product.ID,
+		Product:   domain.NewProductEventData(product),
+	})
+}
+
+// publishUpdate publishes the updated event, and a stock event when the
+// amount or unit changed. previous may be nil when it was not loaded.
+func (uc *ProductUseCase) publishUpdate(ctx context.Context, previous, updated *domain.Product) {
+	uc.publish(ctx, domain.ProductEventUpdated, updated)
+	if previous != nil && (previous.Amount != updated.Amount || previous.Unit != updated.Unit) {
+		uc.emit(ctx, domain.ProductEvent{
+			Type:      domain.StockEventChanged,
+			StoreID:   updated.StoreID,
+			ProductID: updated.ID,
+			Stock: &domain.StockChange{
+				PreviousAmount: previous.Amount,
+				Amount:         updated.Amount,
+				Unit:           updated.Unit,
+			},
+		})
+	}
+}
+
+func (uc *ProductUseCase) emit(ctx context.Context, event domain.ProductEvent) {
+	emitEvent(ctx, uc.events, uc.clock, event)
+}
+
+// emitEvent assigns the event its ID and time and publishes it. events may
+// be nil.
+func emitEvent(ctx context.Context, events EventPublisher, clk clock.Clock, event domain.ProductEvent) {
+	if events == nil {
+		return
+	}
+
+	now := clk.Now()
+	id, err := idgen.UUIDv7At(now)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithField("event_type", event.Type).Error("Failed to generate event ID, event not published")
+		return
+	}
+	event.ID = id.String()
+	event.OccurredAt = now
+	events.Publish(ctx, event)
+}
+
+// normalizeDescription defaults the description format and strips HTML
+// descriptions down to the allowed subset before they are stored, so stored
+// content is safe even for clients that skip ?render=html.
+func normalizeDescription(product *domain.Product) {
+	if product.DescriptionFormat == "" {
+		product.DescriptionFormat = domain.DescriptionFormatPlain
+	}
+	if product.DescriptionFormat == domain.DescriptionFormatHTML && product.Description.Valid {
+		product.Description.String = richtext.Sanitize(product.Description.String)
+	}
+}
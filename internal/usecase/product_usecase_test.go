@@ -4,13 +4,18 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/quantity"
 
-	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockProductRepository struct {
@@ -22,6 +27,14 @@ func (m *MockProductRepository) Create(ctx context.Context, product *domain.Prod
 	return args.Get(0).(*domain.Product), args.Error(1)
 }
 
+func (m *MockProductRepository) CreateMany(ctx context.Context, products []*domain.Product) ([]*domain.Product, error) {
+	args := m.Called(ctx, products)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
 func (m *MockProductRepository) GetByID(ctx context.Context, id int64) (*domain.Product, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -30,8 +43,26 @@ func (m *MockProductRepository) GetByID(ctx context.Context, id int64) (*domain.
 	return args.Get(0).(*domain.Product), args.Error(1)
 }
 
-func (m *MockProductRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockProductRepository) GetAll(ctx context.Context, filter domain.ProductFilter, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	args := m.Called(ctx, filter, now, limit, offset)
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetByAvailability(ctx context.Context, availability string, now time.Time, limit, offset int) ([]*domain.Product, error) {
+	args := m.Called(ctx, availability, now, limit, offset)
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetAfterID(ctx context.Context, afterID int64, limit int) ([]*domain.Product, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetAllByStore(ctx context.Context, storeID int64) ([]*domain.Product, error) {
+	args := m.Called(ctx, storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]*domain.Product), args.Error(1)
 }
 
@@ -43,13 +74,33 @@ func (m *MockProductRepository) Update(ctx context.Context, id int64, product *d
 	return args.Get(0).(*domain.Product), args.Error(1)
 }
 
+func (m *MockProductRepository) Patch(ctx context.Context, id int64, patch *domain.ProductPatch) (*domain.Product, error) {
+	args := m.Called(ctx, id, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetByModerationStatus(ctx context.Context, status string, limit, offset int) ([]*domain.Product, error) {
+	args := m.Called(ctx, status, limit, offset)
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) UpdateModeration(ctx context.Context, id int64, result domain.ModerationResult) (*domain.Product, error) {
+	args := m.Called(ctx, id, result)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
 func (m *MockProductRepository) Delete(ctx context.Context, id int64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
 func TestProductUseCase_CreateProduct(t *testing.T) {
-	logger := logrus.New()
 	ctx := context.Background()
 
 	tests := []struct {
@@ -66,7 +117,7 @@ func TestProductUseCase_CreateProduct(t *testing.T) {
 				StoreID:     1,
 				Name:        "Test Product",
 				Description: sql.NullString{String: "Test Description", Valid: true},
-				Amount:      10,
+				Amount:      quantity.New(10),
 				Price:       29.99,
 			},
 			mockFn: func(m *MockProductRepository) {
@@ -76,7 +127,7 @@ func TestProductUseCase_CreateProduct(t *testing.T) {
 						StoreID:     1,
 						Name:        "Test Product",
 						Description: sql.NullString{String: "Test Description", Valid: true},
-						Amount:      10,
+						Amount:      quantity.New(10),
 						Price:       29.99,
 					}, nil)
 			},
@@ -85,17 +136,49 @@ func TestProductUseCase_CreateProduct(t *testing.T) {
 				StoreID:     1,
 				Name:        "Test Product",
 				Description: sql.NullString{String: "Test Description", Valid: true},
-				Amount:      10,
+				Amount:      quantity.New(10),
 				Price:       29.99,
 			},
 			wantErr: false,
 		},
+		{
+			name: "html description is sanitized",
+			product: &domain.Product{
+				StoreID:           1,
+				Name:              "Test Product",
+				Description:       sql.NullString{String: `<p onclick="x()">Soft</p><script>alert(1)</script>`, Valid: true},
+				DescriptionFormat: domain.DescriptionFormatHTML,
+				Amount:            quantity.New(10),
+				Price:             29.99,
+			},
+			mockFn: func(m *MockProductRepository) {
+				m.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+					return p.Description.String == "<p>Soft</p>"
+				})).Return(&domain.Product{ID: 2, DescriptionFormat: domain.DescriptionFormatHTML}, nil)
+			},
+			want:    &domain.Product{ID: 2, DescriptionFormat: domain.DescriptionFormatHTML},
+			wantErr: false,
+		},
+		{
+			name: "validation error - unknown description format",
+			product: &domain.Product{
+				StoreID:           1,
+				Name:              "Test Product",
+				DescriptionFormat: "rtf",
+				Amount:            quantity.New(10),
+				Price:             29.99,
+			},
+			mockFn:  func(m *MockProductRepository) {},
+			want:    nil,
+			wantErr: true,
+			errType: domain.ErrInvalidProduct,
+		},
 		{
 			name: "validation error - empty name",
 			product: &domain.Product{
 				StoreID: 1,
 				Name:    "",
-				Amount:  10,
+				Amount:  quantity.New(10),
 				Price:   29.99,
 			},
 			mockFn:  func(m *MockProductRepository) {},
@@ -108,7 +191,7 @@ func TestProductUseCase_CreateProduct(t *testing.T) {
 			product: &domain.Product{
 				StoreID: 1,
 				Name:    "Test Product",
-				Amount:  10,
+				Amount:  quantity.New(10),
 				Price:   -5.0,
 			},
 			mockFn:  func(m *MockProductRepository) {},
@@ -116,12 +199,112 @@ func TestProductUseCase_CreateProduct(t *testing.T) {
 			wantErr: true,
 			errType: domain.ErrInvalidProduct,
 		},
+		{
+			name: "weighed product",
+			product: &domain.Product{
+				StoreID: 1,
+				Name:    "Rye Flour",
+				Amount:  quantity.MustParse("2.375"),
+				Unit:    domain.UnitKilogram,
+				Price:   3.2,
+			},
+			mockFn: func(m *MockProductRepository) {
+				m.On("Create", mock.Anything, mock.Anything).Return(&domain.Product{ID: 3, Unit: domain.UnitKilogram}, nil)
+			},
+			want:    &domain.Product{ID: 3, Unit: domain.UnitKilogram},
+			wantErr: false,
+		},
+		{
+			name: "validation error - fractional pieces",
+			product: &domain.Product{
+				StoreID: 1,
+				Name:    "Test Product",
+				Amount:  quantity.MustParse("1.5"),
+				Price:   29.99,
+			},
+			mockFn:  func(m *MockProductRepository) {},
+			want:    nil,
+			wantErr: true,
+			errType: domain.ErrInvalidProduct,
+		},
+		{
+			name: "validation error - unknown unit",
+			product: &domain.Product{
+				StoreID: 1,
+				Name:    "Test Product",
+				Amount:  quantity.New(1),
+				Unit:    "gallon",
+				Price:   29.99,
+			},
+			mockFn:  func(m *MockProductRepository) {},
+			want:    nil,
+			wantErr: true,
+			errType: domain.ErrInvalidProduct,
+		},
+		{
+			name: "backordered product",
+			product: &domain.Product{
+				StoreID:        1,
+				Name:           "Grinder",
+				Amount:         quantity.New(-2),
+				Price:          89,
+				AllowBackorder: true,
+				BackorderLimit: quantity.New(10),
+			},
+			mockFn: func(m *MockProductRepository) {
+				m.On("Create", mock.Anything, mock.Anything).Return(&domain.Product{ID: 4, AllowBackorder: true}, nil)
+			},
+			want:    &domain.Product{ID: 4, AllowBackorder: true},
+			wantErr: false,
+		},
+		{
+			name: "validation error - amount below backorder limit",
+			product: &domain.Product{
+				StoreID:        1,
+				Name:           "Grinder",
+				Amount:         quantity.New(-11),
+				Price:          89,
+				AllowBackorder: true,
+				BackorderLimit: quantity.New(10),
+			},
+			mockFn:  func(m *MockProductRepository) {},
+			want:    nil,
+			wantErr: true,
+			errType: domain.ErrInvalidProduct,
+		},
+		{
+			name: "validation error - negative amount without backorders",
+			product: &domain.Product{
+				StoreID: 1,
+				Name:    "Test Product",
+				Amount:  quantity.New(-1),
+				Price:   29.99,
+			},
+			mockFn:  func(m *MockProductRepository) {},
+			want:    nil,
+			wantErr: true,
+			errType: domain.ErrInvalidProduct,
+		},
+		{
+			name: "validation error - preorder without backorders",
+			product: &domain.Product{
+				StoreID:             1,
+				Name:                "Kettle",
+				Amount:              quantity.New(0),
+				Price:               45,
+				PreorderReleaseDate: &time.Time{},
+			},
+			mockFn:  func(m *MockProductRepository) {},
+			want:    nil,
+			wantErr: true,
+			errType: domain.ErrInvalidProduct,
+		},
 		{
 			name: "repository error",
 			product: &domain.Product{
 				StoreID: 1,
 				Name:    "Test Product",
-				Amount:  10,
+				Amount:  quantity.New(10),
 				Price:   29.99,
 			},
 			mockFn: func(m *MockProductRepository) {
@@ -138,7 +321,7 @@ func TestProductUseCase_CreateProduct(t *testing.T) {
 			repo := &MockProductRepository{}
 			tt.mockFn(repo)
 
-			uc := NewProductUseCase(repo, logger)
+			uc := NewProductUseCase(database.NopTxManager{}, repo, nil, nil, nil, clock.Real())
 			got, err := uc.CreateProduct(ctx, tt.product)
 
 			if tt.wantErr {
@@ -157,7 +340,6 @@ func TestProductUseCase_CreateProduct(t *testing.T) {
 }
 
 func TestProductUseCase_GetProduct(t *testing.T) {
-	logger := logrus.New()
 	ctx := context.Background()
 
 	tests := []struct {
@@ -177,7 +359,7 @@ func TestProductUseCase_GetProduct(t *testing.T) {
 						ID:      1,
 						StoreID: 1,
 						Name:    "Test Product",
-						Amount:  10,
+						Amount:  quantity.New(10),
 						Price:   29.99,
 					}, nil)
 			},
@@ -185,7 +367,7 @@ func TestProductUseCase_GetProduct(t *testing.T) {
 				ID:      1,
 				StoreID: 1,
 				Name:    "Test Product",
-				Amount:  10,
+				Amount:  quantity.New(10),
 				Price:   29.99,
 			},
 			wantErr: false,
@@ -216,7 +398,7 @@ func TestProductUseCase_GetProduct(t *testing.T) {
 			repo := &MockProductRepository{}
 			tt.mockFn(repo)
 
-			uc := NewProductUseCase(repo, logger)
+			uc := NewProductUseCase(database.NopTxManager{}, repo, nil, nil, nil, clock.Real())
 			got, err := uc.GetProduct(ctx, tt.id)
 
 			if tt.wantErr {
@@ -235,11 +417,15 @@ func TestProductUseCase_GetProduct(t *testing.T) {
 }
 
 func TestProductUseCase_GetProducts(t *testing.T) {
-	logger := logrus.New()
 	ctx := context.Background()
 
+	storeID := int64(3)
+	minPrice, maxPrice := 20.0, 10.0
+	inStock := true
+
 	tests := []struct {
 		name    string
+		filter  domain.ProductFilter
 		limit   int
 		offset  int
 		mockFn  func(*MockProductRepository)
@@ -251,15 +437,15 @@ func TestProductUseCase_GetProducts(t *testing.T) {
 			limit:  10,
 			offset: 0,
 			mockFn: func(m *MockProductRepository) {
-				m.On("GetAll", mock.Anything, 10, 0).Return(
+				m.On("GetAll", mock.Anything, domain.ProductFilter{}, mock.Anything, 10, 0).Return(
 					[]*domain.Product{
-						{ID: 1, Name: "Product 1", StoreID: 1, Amount: 5, Price: 19.99},
-						{ID: 2, Name: "Product 2", StoreID: 1, Amount: 10, Price: 29.99},
+						{ID: 1, Name: "Product 1", StoreID: 1, Amount: quantity.New(5), Price: 19.99},
+						{ID: 2, Name: "Product 2", StoreID: 1, Amount: quantity.New(10), Price: 29.99},
 					}, nil)
 			},
 			want: []*domain.Product{
-				{ID: 1, Name: "Product 1", StoreID: 1, Amount: 5, Price: 19.99},
-				{ID: 2, Name: "Product 2", StoreID: 1, Amount: 10, Price: 29.99},
+				{ID: 1, Name: "Product 1", StoreID: 1, Amount: quantity.New(5), Price: 19.99},
+				{ID: 2, Name: "Product 2", StoreID: 1, Amount: quantity.New(10), Price: 29.99},
 			},
 			wantErr: false,
 		},
@@ -268,7 +454,7 @@ func TestProductUseCase_GetProducts(t *testing.T) {
 			limit:  0,
 			offset: 0,
 			mockFn: func(m *MockProductRepository) {
-				m.On("GetAll", mock.Anything, 10, 0).Return([]*domain.Product{}, nil)
+				m.On("GetAll", mock.Anything, domain.ProductFilter{}, mock.Anything, 10, 0).Return([]*domain.Product{}, nil)
 			},
 			want:    []*domain.Product{},
 			wantErr: false,
@@ -278,11 +464,39 @@ func TestProductUseCase_GetProducts(t *testing.T) {
 			limit:  150,
 			offset: 0,
 			mockFn: func(m *MockProductRepository) {
-				m.On("GetAll", mock.Anything, 100, 0).Return([]*domain.Product{}, nil)
+				m.On("GetAll", mock.Anything, domain.ProductFilter{}, mock.Anything, 100, 0).Return([]*domain.Product{}, nil)
 			},
 			want:    []*domain.Product{},
 			wantErr: false,
 		},
+		{
+			name:   "filter is passed to the repository",
+			filter: domain.ProductFilter{Name: "shirt", StoreID: &storeID, InStock: &inStock},
+			limit:  10,
+			offset: 0,
+			mockFn: func(m *MockProductRepository) {
+				m.On("GetAll", mock.Anything, domain.ProductFilter{Name: "shirt", StoreID: &storeID, InStock: &inStock}, mock.Anything, 10, 0).Return(
+					[]*domain.Product{{ID: 1, Name: "T-Shirt", StoreID: 3, Amount: quantity.New(5), Price: 19.99}}, nil)
+			},
+			want:    []*domain.Product{{ID: 1, Name: "T-Shirt", StoreID: 3, Amount: quantity.New(5), Price: 19.99}},
+			wantErr: false,
+		},
+		{
+			name:    "min price above max price",
+			filter:  domain.ProductFilter{MinPrice: &minPrice, MaxPrice: &maxPrice},
+			limit:   10,
+			offset:  0,
+			mockFn:  func(m *MockProductRepository) {},
+			wantErr: true,
+		},
+		{
+			name:    "unknown sort",
+			filter:  domain.ProductFilter{Sort: "price"},
+			limit:   10,
+			offset:  0,
+			mockFn:  func(m *MockProductRepository) {},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -290,8 +504,8 @@ func TestProductUseCase_GetProducts(t *testing.T) {
 			repo := &MockProductRepository{}
 			tt.mockFn(repo)
 
-			uc := NewProductUseCase(repo, logger)
-			got, err := uc.GetProducts(ctx, tt.limit, tt.offset)
+			uc := NewProductUseCase(database.NopTxManager{}, repo, nil, nil, nil, clock.Real())
+			got, err := uc.GetProducts(ctx, tt.filter, tt.limit, tt.offset)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -304,3 +518,538 @@ func TestProductUseCase_GetProducts(t *testing.T) {
 		})
 	}
 }
+
+func TestProductUseCase_GetProductsByAvailability(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := &MockProductRepository{}
+	repo.On("GetByAvailability", mock.Anything, domain.AvailabilityPreorder, now, 100, 0).Return(
+		[]*domain.Product{{ID: 1}}, nil)
+	uc := NewProductUseCase(database.NopTxManager{}, repo, nil, nil, nil, clock.NewFake(now))
+
+	products, err := uc.GetProductsByAvailability(ctx, domain.AvailabilityPreorder, 500, -1)
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+
+	_, err = uc.GetProductsByAvailability(ctx, "sold", 10, 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+
+	repo.AssertExpectations(t)
+}
+
+func TestProductUseCase_StreamProducts(t *testing.T) {
+	ctx := context.Background()
+
+	firstBatch := make([]*domain.Product, streamBatchSize)
+	for i := range firstBatch {
+		firstBatch[i] = &domain.Product{ID: int64(i + 1)}
+	}
+	tomorrow, yesterday := time.Now().Add(24*time.Hour), time.Now().Add(-24*time.Hour)
+
+	tests := []struct {
+		name     string
+		mockFn   func(*MockProductRepository)
+		fnErr    error
+		wantSeen int
+		wantErr  bool
+	}{
+		{
+			name: "follows batches until a short one",
+			mockFn: func(m *MockProductRepository) {
+				m.On("GetAfterID", mock.Anything, int64(0), streamBatchSize).Return(firstBatch, nil)
+				m.On("GetAfterID", mock.Anything, int64(streamBatchSize), streamBatchSize).Return(
+					[]*domain.Product{{ID: streamBatchSize + 1}}, nil)
+			},
+			wantSeen: streamBatchSize + 1,
+		},
+		{
+			name: "skips products outside their publishing window",
+			mockFn: func(m *MockProductRepository) {
+				m.On("GetAfterID", mock.Anything, int64(0), streamBatchSize).Return([]*domain.Product{
+					{ID: 1, PublishAt: &tomorrow},
+					{ID: 2, PublishAt: &yesterday, UnpublishAt: &tomorrow},
+					{ID: 3, UnpublishAt: &yesterday},
+				}, nil)
+			},
+			wantSeen: 1,
+		},
+		{
+			name: "callback error stops iteration",
+			mockFn: func(m *MockProductRepository) {
+				m.On("GetAfterID", mock.Anything, int64(0), streamBatchSize).Return(firstBatch, nil)
+			},
+			fnErr:    errors.New("client gone"),
+			wantSeen: 1,
+			wantErr:  true,
+		},
+		{
+			name: "repository error",
+			mockFn: func(m *MockProductRepository) {
+				m.On("GetAfterID", mock.Anything, int64(0), streamBatchSize).Return(
+					[]*domain.Product(nil), errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockProductRepository{}
+			tt.mockFn(repo)
+
+			uc := NewProductUseCase(database.NopTxManager{}, repo, nil, nil, nil, clock.Real())
+
+			seen := 0
+			err := uc.StreamProducts(ctx, func(p *domain.Product) error {
+				seen++
+				return tt.fnErr
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantSeen, seen)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+type MockMutationMonitor struct {
+	mock.Mock
+}
+
+func (m *MockMutationMonitor) Record(ctx context.Context, storeID int64, kind string) {
+	m.Called(ctx, storeID, kind)
+}
+
+func (m *MockMutationMonitor) RequiresConfirmation(storeID int64, kind string) bool {
+	args := m.Called(storeID, kind)
+	return args.Bool(0)
+}
+
+func TestProductUseCase_DeleteProduct(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		id        int64
+		confirmed bool
+		mockFn    func(*MockProductRepository, *MockMutationMonitor)
+		wantErr   bool
+		errType   error
+	}{
+		{
+			name: "successful deletion is recorded",
+			id:   1,
+			mockFn: func(r *MockProductRepository, m *MockMutationMonitor) {
+				r.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, StoreID: 7}, nil)
+				m.On("RequiresConfirmation", int64(7), domain.MutationDelete).Return(false)
+				r.On("Delete", mock.Anything, int64(1)).Return(nil)
+				m.On("Record", mock.Anything, int64(7), domain.MutationDelete).Return()
+			},
+		},
+		{
+			name: "anomalous store without confirmation",
+			id:   1,
+			mockFn: func(r *MockProductRepository, m *MockMutationMonitor) {
+				r.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, StoreID: 7}, nil)
+				m.On("RequiresConfirmation", int64(7), domain.MutationDelete).Return(true)
+			},
+			wantErr: true,
+			errType: domain.ErrConfirmationRequired,
+		},
+		{
+			name:      "anomalous store with confirmation",
+			id:        1,
+			confirmed: true,
+			mockFn: func(r *MockProductRepository, m *MockMutationMonitor) {
+				r.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, StoreID: 7}, nil)
+				m.On("RequiresConfirmation", int64(7), domain.MutationDelete).Return(true)
+				r.On("Delete", mock.Anything, int64(1)).Return(nil)
+				m.On("Record", mock.Anything, int64(7), domain.MutationDelete).Return()
+			},
+		},
+		{
+			name: "product not found",
+			id:   999,
+			mockFn: func(r *MockProductRepository, m *MockMutationMonitor) {
+				r.On("GetByID", mock.Anything, int64(999)).Return(nil, domain.ErrProductNotFound)
+			},
+			wantErr: true,
+			errType: domain.ErrProductNotFound,
+		},
+		{
+			name:    "invalid ID",
+			id:      0,
+			mockFn:  func(r *MockProductRepository, m *MockMutationMonitor) {},
+			wantErr: true,
+			errType: domain.ErrInvalidProduct,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockProductRepository{}
+			monitor := &MockMutationMonitor{}
+			tt.mockFn(repo, monitor)
+
+			ctx := context.Background()
+			if tt.confirmed {
+				ctx = WithMassOperationConfirmed(ctx)
+			}
+
+			uc := NewProductUseCase(database.NopTxManager{}, repo, nil, monitor, nil, clock.Real())
+			err := uc.DeleteProduct(ctx, tt.id)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.errType)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+			monitor.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProductUseCase_UpdateProductPartial(t *testing.T) {
+	existing := func() *domain.Product {
+		return &domain.Product{
+			ID:          1,
+			StoreID:     7,
+			Name:        "Widget",
+			Description: sql.NullString{String: "A widget", Valid: true},
+			Amount:      quantity.New(5),
+			Unit:        domain.UnitPiece,
+			Price:       10,
+			Status:      domain.ProductStatusActive,
+		}
+	}
+	price := 12.5
+	name := "Blocked"
+	negative := -1.0
+
+	tests := []struct {
+		name      string
+		id        int64
+		patch     *domain.ProductPatch
+		moderator ProductModerator
+		mockFn    func(*MockProductRepository)
+		wantErr   error
+	}{
+		{
+			name:  "writes only the price",
+			id:    1,
+			patch: &domain.ProductPatch{Price: &price},
+			mockFn: func(r *MockProductRepository) {
+				r.On("GetByID", mock.Anything, int64(1)).Return(existing(), nil)
+				r.On("Patch", mock.Anything, int64(1), &domain.ProductPatch{Price: &price}).Return(existing(), nil)
+			},
+		},
+		{
+			name:      "price change skips moderation",
+			id:        1,
+			patch:     &domain.ProductPatch{Price: &price},
+			moderator: rejectingModerator{name: "Widget"},
+			mockFn: func(r *MockProductRepository) {
+				r.On("GetByID", mock.Anything, int64(1)).Return(existing(), nil)
+				r.On("Patch", mock.Anything, int64(1), &domain.ProductPatch{Price: &price}).Return(existing(), nil)
+			},
+		},
+		{
+			name:      "new name is screened",
+			id:        1,
+			patch:     &domain.ProductPatch{Name: &name},
+			moderator: rejectingModerator{name: "Blocked"},
+			mockFn: func(r *MockProductRepository) {
+				r.On("GetByID", mock.Anything, int64(1)).Return(existing(), nil)
+			},
+			wantErr: domain.ErrContentRejected,
+		},
+		{
+			name:  "patched product is invalid",
+			id:    1,
+			patch: &domain.ProductPatch{Price: &negative},
+			mockFn: func(r *MockProductRepository) {
+				r.On("GetByID", mock.Anything, int64(1)).Return(existing(), nil)
+			},
+			wantErr: domain.ErrInvalidProduct,
+		},
+		{
+			name:    "empty patch",
+			id:      1,
+			patch:   &domain.ProductPatch{},
+			mockFn:  func(r *MockProductRepository) {},
+			wantErr: domain.ErrInvalidProduct,
+		},
+		{
+			name:  "product not found",
+			id:    999,
+			patch: &domain.ProductPatch{Price: &price},
+			mockFn: func(r *MockProductRepository) {
+				r.On("GetByID", mock.Anything, int64(999)).Return(nil, domain.ErrProductNotFound)
+			},
+			wantErr: domain.ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockProductRepository{}
+			tt.mockFn(repo)
+
+			uc := NewProductUseCase(database.NopTxManager{}, repo, tt.moderator, nil, nil, clock.Real())
+			_, err := uc.UpdateProductPartial(context.Background(), tt.id, tt.patch)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+type recordingPublisher struct {
+	events []domain.ProductEvent
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event domain.ProductEvent) {
+	p.events = append(p.events, event)
+}
+
+func TestProductUseCase_PublishesEvents(t *testing.T) {
+	repo := &MockProductRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).Return(&domain.Product{ID: 1, StoreID: 7, Name: "Widget"}, nil)
+	repo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, StoreID: 7, Name: "Widget", Amount: quantity.New(5), Unit: domain.UnitPiece}, nil)
+	repo.On("Update", mock.Anything, int64(1), mock.Anything).Return(&domain.Product{ID: 1, StoreID: 7, Name: "Widget", Amount: quantity.New(2), Unit: domain.UnitPiece}, nil).Once()
+	repo.On("Update", mock.Anything, int64(1), mock.Anything).Return(&domain.Product{ID: 1, StoreID: 7, Name: "Widget 2", Amount: quantity.New(5), Unit: domain.UnitPiece}, nil).Once()
+	repo.On("Delete", mock.Anything, int64(1)).Return(nil)
+
+	events := &recordingPublisher{}
+	uc := NewProductUseCase(database.NopTxManager{}, repo, nil, nil, events, clock.Real())
+
+	_, err := uc.CreateProduct(context.Background(), &domain.Product{StoreID: 7, Name: "Widget", Amount: quantity.New(5), Price: 1, Status: domain.ProductStatusActive})
+	require.NoError(t, err)
+	_, err = uc.UpdateProduct(context.Background(), 1, &domain.Product{StoreID: 7, Name: "Widget", Amount: quantity.New(2), Price: 1, Status: domain.ProductStatusActive})
+	require.NoError(t, err)
+	_, err = uc.UpdateProduct(context.Background(), 1, &domain.Product{StoreID: 7, Name: "Widget 2", Amount: quantity.New(5), Price: 1, Status: domain.ProductStatusActive})
+	require.NoError(t, err)
+	require.NoError(t, uc.DeleteProduct(context.Background(), 1))
+
+	var types []string
+	for _, event := range events.events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{
+		domain.ProductEventCreated,
+		domain.ProductEventUpdated,
+		domain.StockEventChanged,
+		domain.ProductEventUpdated,
+		domain.ProductEventDeleted,
+	}, types, "only the update that changed the amount is a stock change")
+
+	assert.Equal(t, &domain.StockChange{PreviousAmount: quantity.New(5), Amount: quantity.New(2), Unit: domain.UnitPiece}, events.events[2].Stock)
+	assert.Nil(t, events.events[2].Product)
+	assert.Equal(t, int64(7), events.events[4].StoreID)
+	assert.Equal(t, "Widget", events.events[4].Product.Name)
+	assert.NotEqual(t, events.events[0].ID, events.events[4].ID)
+	repo.AssertExpectations(t)
+}
+
+type txMarker struct{}
+
+// markingTxManager runs units of work with a context that says so, for
+// checking what happens inside them.
+type markingTxManager struct{}
+
+func (markingTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, txMarker{}, true))
+}
+
+// txPublisher records the events published inside a transaction.
+type txPublisher struct {
+	inTx, outsideTx []string
+}
+
+func (p *txPublisher) Publish(ctx context.Context, event domain.ProductEvent) {
+	if ctx.Value(txMarker{}) != nil {
+		p.inTx = append(p.inTx, event.Type)
+	} else {
+		p.outsideTx = append(p.outsideTx, event.Type)
+	}
+}
+
+func TestProductUseCase_PublishesEventsInTheWriteTransaction(t *testing.T) {
+	repo := &MockProductRepository{}
+	repo.On("Create", mock.Anything, mock.Anything).Return(&domain.Product{ID: 1, StoreID: 7, Name: "Widget"}, nil)
+	repo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, StoreID: 7, Name: "Widget"}, nil)
+	repo.On("Delete", mock.Anything, int64(1)).Return(nil)
+	repo.On("GetByID", mock.Anything, int64(2)).Return(&domain.Product{ID: 2, StoreID: 7, Name: "Gadget"}, nil)
+	repo.On("Update", mock.Anything, int64(2), mock.Anything).Return(nil, domain.ErrProductNotFound)
+
+	events := &txPublisher{}
+	uc := NewProductUseCase(markingTxManager{}, repo, nil, nil, events, clock.Real())
+
+	_, err := uc.CreateProduct(context.Background(), &domain.Product{StoreID: 7, Name: "Widget", Amount: quantity.New(5), Price: 1, Status: domain.ProductStatusActive})
+	require.NoError(t, err)
+	require.NoError(t, uc.DeleteProduct(context.Background(), 1))
+	_, err = uc.UpdateProduct(context.Background(), 2, &domain.Product{StoreID: 7, Name: "Gadget", Amount: quantity.New(5), Price: 1, Status: domain.ProductStatusActive})
+	require.ErrorIs(t, err, domain.ErrProductNotFound)
+
+	assert.Equal(t, []string{domain.ProductEventCreated, domain.ProductEventDeleted}, events.inTx, "a failed write publishes nothing")
+	assert.Empty(t, events.outsideTx)
+}
+
+func TestProductUseCase_WriteProducts(t *testing.T) {
+	ctx := context.Background()
+	valid := func(name string) *domain.Product {
+		return &domain.Product{StoreID: 7, Name: name, Amount: quantity.New(5), Price: 1, Status: domain.ProductStatusActive}
+	}
+	refusedIndexes := func(t *testing.T, err error) []int {
+		var refused *domain.BulkWriteError
+		require.ErrorAs(t, err, &refused)
+		assert.ErrorIs(t, err, domain.ErrBulkWriteRejected)
+		indexes := make([]int, len(refused.Items))
+		for i, item := range refused.Items {
+			indexes[i] = item.Index
+		}
+		return indexes
+	}
+
+	t.Run("creates and updates in order", func(t *testing.T) {
+		repo := &MockProductRepository{}
+		repo.On("GetByID", mock.Anything, int64(1)).Return(&domain.Product{ID: 1, StoreID: 7, Name: "Kettle", Amount: quantity.New(5), Unit: domain.UnitPiece}, nil)
+		repo.On("Update", mock.Anything, int64(1), mock.Anything).Return(&domain.Product{ID: 1, StoreID: 7, Name: "Kettle", Amount: quantity.New(2), Unit: domain.UnitPiece}, nil)
+		repo.On("CreateMany", mock.Anything, mock.MatchedBy(func(products []*domain.Product) bool {
+			return len(products) == 2 && products[0].Name == "Widget" && products[1].Name == "Gadget"
+		})).Return([]*domain.Product{{ID: 2, StoreID: 7, Name: "Widget"}, {ID: 3, StoreID: 7, Name: "Gadget"}}, nil)
+
+		events := &recordingPublisher{}
+		uc := NewProductUseCase(database.NopTxManager{}, repo, nil, nil, events, clock.Real())
+
+		results, err := uc.WriteProducts(ctx, []domain.BulkProductItem{
+			{Product: valid("Widget")},
+			{ID: 1, Product: valid("Kettle")},
+			{Product: valid("Gadget")},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, int64(2), results[0].Product.ID)
+		assert.True(t, results[0].Created)
+		assert.Equal(t, int64(1), results[1].Product.ID)
+		assert.False(t, results[1].Created)
+		assert.Equal(t, int64(3), results[2].Product.ID)
+
+		var types []string
+		for _, event := range events.events {
+			types = append(types, event.Type)
+		}
+		assert.Equal(t, []string{
+			domain.ProductEventCreated,
+			domain.ProductEventUpdated,
+			domain.StockEventChanged,
+			domain.ProductEventCreated,
+		}, types)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("refuses every invalid item and writes nothing", func(t *testing.T) {
+		repo := &MockProductRepository{}
+		uc := NewProductUseCase(database.NopTxManager{}, repo, nil, nil, nil, clock.Real())
+
+		_, err := uc.WriteProducts(ctx, []domain.BulkProductItem{
+			{Product: valid("Widget")},
+			{Product: &domain.Product{StoreID: 7, Name: "", Amount: quantity.New(1), Price: 1}},
+			{ID: 4, Product: valid("Kettle")},
+			{ID: 4, Product: valid("Kettle")},
+			{ID: -1, Product: valid("Gadget")},
+		})
+		assert.Equal(t, []int{1, 3, 4}, refusedIndexes(t, err))
+		repo.AssertNotCalled(t, "CreateMany", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("names the update the database refuses", func(t *testing.T) {
+		repo := &MockProductRepository{}
+		repo.On("Update", mock.Anything, int64(9), mock.Anything).Return(nil, domain.ErrProductNotFound)
+		uc := NewProductUseCase(database.NopTxManager{}, repo, nil, nil, nil, clock.Real())
+
+		_, err := uc.WriteProducts(ctx, []domain.BulkProductItem{
+			{Product: valid("Widget")},
+			{ID: 9, Product: valid("Kettle")},
+		})
+		assert.Equal(t, []int{1}, refusedIndexes(t, err))
+		assert.ErrorIs(t, err, domain.ErrBulkWriteRejected)
+		repo.AssertNotCalled(t, "CreateMany", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses empty and oversized writes", func(t *testing.T) {
+		uc := NewProductUseCase(database.NopTxManager{}, &MockProductRepository{}, nil, nil, nil, clock.Real())
+
+		_, err := uc.WriteProducts(ctx, nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+		_, err = uc.WriteProducts(ctx, make([]domain.BulkProductItem, domain.MaxBulkProducts+1))
+		assert.ErrorIs(t, err, domain.ErrInvalidProduct)
+	})
+}
+
+func TestProductUseCase_ImportProducts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("saves rows and reports the others by line", func(t *testing.T) {
+		repo := &MockProductRepository{}
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Name == "Widget" && p.StoreID == 7 && p.Amount.Cmp(quantity.MustParse("1.5")) == 0 && p.Unit == domain.UnitKilogram
+		})).Return(&domain.Product{ID: 2, StoreID: 7, Name: "Widget"}, nil)
+		repo.On("Update", mock.Anything, int64(1), mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Name == "Kettle" && p.PublishAt != nil && !p.Description.Valid
+		})).Return(&domain.Product{ID: 1, StoreID: 7, Name: "Kettle"}, nil)
+		repo.On("Update", mock.Anything, int64(9), mock.Anything).Return(nil, domain.ErrProductNotFound)
+		uc := NewProductUseCase(database.NopTxManager{}, repo, nil, nil, nil, clock.Real())
+
+		file := "\ufeffName,Store_ID,amount,unit,price,id,publish_at,notes\n" +
+			"Widget,7,1.5,kg,9.99,,,ignored\n" +
+			"Kettle,7,3,,30,1,2026-06-01T09:00:00Z\n" +
+			"Gadget,7,2,,abc\n" +
+			"\"Multi\nline\",7,1,,0\n" +
+			"Lamp,7,1,,5,9\n"
+		report, err := uc.ImportProducts(ctx, strings.NewReader(file))
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created)
+		assert.Equal(t, 1, report.Updated)
+		assert.Equal(t, 3, report.Failed)
+		require.Len(t, report.Errors, 3)
+		assert.Equal(t, 4, report.Errors[0].Line)
+		assert.Contains(t, report.Errors[0].Error, `invalid price "abc"`)
+		assert.Equal(t, 5, report.Errors[1].Line)
+		assert.Contains(t, report.Errors[1].Error, "price must be positive")
+		assert.Equal(t, 7, report.Errors[2].Line)
+		assert.Contains(t, report.Errors[2].Error, "product not found")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("refuses a header without the required columns", func(t *testing.T) {
+		uc := NewProductUseCase(database.NopTxManager{}, &MockProductRepository{}, nil, nil, nil, clock.Real())
+
+		_, err := uc.ImportProducts(ctx, strings.NewReader("name,amount,price\nWidget,1,2\n"))
+		assert.ErrorIs(t, err, domain.ErrInvalidProductImport)
+		_, err = uc.ImportProducts(ctx, strings.NewReader(""))
+		assert.ErrorIs(t, err, domain.ErrInvalidProductImport)
+	})
+
+	t.Run("stops on errors that are not the row's", func(t *testing.T) {
+		repo := &MockProductRepository{}
+		repo.On("Create", mock.Anything, mock.Anything).Return((*domain.Product)(nil), errors.New("database error")).Once()
+		uc := NewProductUseCase(database.NopTxManager{}, repo, nil, nil, nil, clock.Real())
+
+		_, err := uc.ImportProducts(ctx, strings.NewReader("store_id,name,amount,price\n7,Widget,1,2\n7,Gadget,1,2\n"))
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrInvalidProductImport)
+		repo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+)
+
+// PublishingScheduler announces products entering and leaving their
+// publishing windows. Listings filter on the windows themselves, so
+// products appear and disappear on time either way; every interval the
+// scheduler looks for windows that opened or closed since its last run,
+// drops those products from the cache and publishes product.published or
+// product.unpublished for them. Transitions while no instance runs it are
+// not announced.
+type PublishingScheduler struct {
+	repo     PublishScheduleRepository
+	cache    ProductCache
+	events   EventPublisher
+	interval time.Duration
+	clock    clock.Clock
+
+	last time.Time
+}
+
+// NewPublishingScheduler announces transitions from the time it is
+// created. cache and events may be nil.
+func NewPublishingScheduler(repo PublishScheduleRepository, cache ProductCache, events EventPublisher, interval time.Duration, clk clock.Clock) *PublishingScheduler {
+	return &PublishingScheduler{
+		repo:     repo,
+		cache:    cache,
+		events:   events,
+		interval: interval,
+		clock:    clk,
+		last:     clk.Now(),
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (s *PublishingScheduler) Run(ctx context.Context) {
+	logger.FromContext(ctx).WithField("interval", s.interval).Info("Publishing scheduler started")
+
+	ticker := s.clock.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.FromContext(ctx).Info("Publishing scheduler stopped")
+			return
+		case <-ticker.C():
+			if err := s.AnnounceTransitions(ctx); err != nil && ctx.Err() == nil {
+				logger.FromContext(ctx).WithError(err).Error("Failed to announce publishing transitions")
+			}
+		}
+	}
+}
+
+// AnnounceTransitions announces the windows that opened or closed since the
+// last run. When the lookup fails, the next run covers the same time again.
+func (s *PublishingScheduler) AnnounceTransitions(ctx context.Context) error {
+	from, until := s.last, s.clock.Now()
+	products, err := s.repo.GetPublishTransitions(ctx, from, until)
+	if err != nil {
+		return fmt.Errorf("failed to get publishing transitions: %w", err)
+	}
+	s.last = until
+
+	for _, product := range products {
+		if s.cache != nil {
+			s.cache.Invalidate(product.ID)
+		}
+		for _, eventType := range publishingTransitions(product, from, until) {
+			emitEvent(ctx, s.events, s.clock, domain.ProductEvent{
+				Type:      eventType,
+				StoreID:   product.StoreID,
+				ProductID: product.ID,
+				Product:   domain.NewProductEventData(product),
+			})
+		}
+	}
+
+	if len(products) > 0 {
+		logger.FromContext(ctx).WithField("products", len(products)).Info("Publishing transitions announced")
+	}
+	return nil
+}
+
+// publishingTransitions lists the event types for the ends of the
+// product's window that fall after from and up to until, in order.
+func publishingTransitions(product *domain.Product, from, until time.Time) []string {
+	within := func(t *time.Time) bool { return t != nil && t.After(from) && !t.After(until) }
+
+	var types []string
+	if within(product.PublishAt) {
+		types = append(types, domain.ProductEventPublished)
+	}
+	if within(product.UnpublishAt) {
+		types = append(types, domain.ProductEventUnpublished)
+	}
+	return types
+}
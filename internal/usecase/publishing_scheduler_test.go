@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPublishScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockPublishScheduleRepository) GetPublishTransitions(ctx context.Context, from, until time.Time) ([]*domain.Product, error) {
+	args := m.Called(ctx, from, until)
+	return args.Get(0).([]*domain.Product), args.Error(1)
+}
+
+type recordingCache struct {
+	invalidated []int64
+}
+
+func (c *recordingCache) Invalidate(id int64) {
+	c.invalidated = append(c.invalidated, id)
+}
+
+func TestPublishingScheduler_AnnounceTransitions(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	repo := &MockPublishScheduleRepository{}
+	cache := &recordingCache{}
+	events := &recordingPublisher{}
+	scheduler := NewPublishingScheduler(repo, cache, events, time.Minute, fake)
+
+	launch, sunset := start.Add(30*time.Second), start.Add(45*time.Second)
+	afterwards := start.Add(time.Hour)
+	repo.On("GetPublishTransitions", mock.Anything, start, start.Add(time.Minute)).Return([]*domain.Product{
+		{ID: 1, StoreID: 3, Name: "Launch", PublishAt: &launch, UnpublishAt: &afterwards},
+		{ID: 2, StoreID: 3, Name: "Flash sale", PublishAt: &launch, UnpublishAt: &sunset},
+		{ID: 3, StoreID: 5, Name: "Retired", UnpublishAt: &sunset},
+	}, nil).Once()
+
+	fake.Advance(time.Minute)
+	require.NoError(t, scheduler.AnnounceTransitions(ctx))
+
+	assert.Equal(t, []int64{1, 2, 3}, cache.invalidated)
+	var announced []string
+	for _, event := range events.events {
+		announced = append(announced, event.Type)
+		assert.Equal(t, event.ProductID, event.Product.ID)
+	}
+	assert.Equal(t, []string{
+		domain.ProductEventPublished,
+		domain.ProductEventPublished, domain.ProductEventUnpublished,
+		domain.ProductEventUnpublished,
+	}, announced)
+
+	// A failed lookup is covered again by the next run.
+	repo.On("GetPublishTransitions", mock.Anything, start.Add(time.Minute), start.Add(2*time.Minute)).
+		Return([]*domain.Product(nil), errors.New("database error")).Once()
+	fake.Advance(time.Minute)
+	assert.Error(t, scheduler.AnnounceTransitions(ctx))
+
+	repo.On("GetPublishTransitions", mock.Anything, start.Add(time.Minute), start.Add(3*time.Minute)).
+		Return([]*domain.Product{}, nil).Once()
+	fake.Advance(time.Minute)
+	require.NoError(t, scheduler.AnnounceTransitions(ctx))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ReconciliationScheduler periodically reconciles the catalog against every
+// enabled feed and connector under one policy.
+type ReconciliationScheduler struct {
+	reconciliationUseCase ReconciliationUseCaseInterface
+	interval              time.Duration
+	policy                string
+	clock                 clock.Clock
+}
+
+func NewReconciliationScheduler(reconciliationUseCase ReconciliationUseCaseInterface, interval time.Duration, policy string, clk clock.Clock) *ReconciliationScheduler {
+	return &ReconciliationScheduler{
+		reconciliationUseCase: reconciliationUseCase,
+		interval:              interval,
+		policy:                policy,
+		clock:                 clk,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (s *ReconciliationScheduler) Run(ctx context.Context) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"interval": s.interval,
+		"policy":   s.policy,
+	}).Info("Reconciliation scheduler started")
+
+	ticker := s.clock.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.FromContext(ctx).Info("Reconciliation scheduler stopped")
+			return
+		case <-ticker.C():
+			if err := s.reconciliationUseCase.ReconcileAll(ctx, s.policy); err != nil && ctx.Err() == nil {
+				logger.FromContext(ctx).WithError(err).Error("Failed to run scheduled reconciliation")
+			}
+		}
+	}
+}
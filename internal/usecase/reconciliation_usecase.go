@@ -0,0 +1,410 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// maxKeptDiscrepancies caps the discrepancies stored per run. A source that
+// disagrees with the whole catalog still has every discrepancy counted.
+const maxKeptDiscrepancies = 1000
+
+type ReconciliationUseCase struct {
+	reconciliationRepo ReconciliationRepository
+	productRepo        ProductRepository
+	products           ProductWriter
+	sources            map[string]ReferenceSource
+	clock              clock.Clock
+
+	mu      sync.Mutex
+	running map[string]struct{}
+}
+
+// NewReconciliationUseCase builds the reconciliation use case over the
+// given sources, keyed by domain.ReconciliationSourceFeed and
+// domain.ReconciliationSourceConnector. The catalog is read from
+// productRepo and healed through products.
+func NewReconciliationUseCase(reconciliationRepo ReconciliationRepository, productRepo ProductRepository, products ProductWriter, sources map[string]ReferenceSource, clk clock.Clock) *ReconciliationUseCase {
+	return &ReconciliationUseCase{
+		reconciliationRepo: reconciliationRepo,
+		productRepo:        productRepo,
+		products:           products,
+		sources:            sources,
+		clock:              clk,
+		running:            make(map[string]struct{}),
+	}
+}
+
+// Reconcile compares the source's snapshot with its store's catalog field by
+// field and heals what the policy allows. The returned run is the persisted
+// report, also when the snapshot could not be taken.
+func (uc *ReconciliationUseCase) Reconcile(ctx context.Context, source string, sourceID int64, policy string) (*domain.ReconciliationRun, error) {
+	run := &domain.ReconciliationRun{
+		Source:   source,
+		SourceID: sourceID,
+		Policy:   policy,
+		Status:   domain.ReconciliationRunStatusRunning,
+	}
+	if err := run.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidReconciliation, err)
+	}
+
+	reference, ok := uc.sources[source]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s sources are not configured", domain.ErrInvalidReconciliation, source)
+	}
+
+	storeID, err := reference.StoreID(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	run.StoreID = storeID
+
+	key := source + "/" + strconv.FormatInt(sourceID, 10)
+	if !uc.acquire(key) {
+		return nil, domain.ErrReconciliationInProgress
+	}
+	defer uc.release(key)
+
+	logger := logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":    "reconcile",
+		"source":    source,
+		"source_id": sourceID,
+		"store_id":  storeID,
+		"policy":    policy,
+	})
+	logger.Info("Starting reconciliation")
+
+	run.StartedAt = uc.clock.Now()
+	run, err = uc.reconciliationRepo.CreateRun(ctx, run)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create reconciliation run")
+		return nil, fmt.Errorf("failed to start reconciliation run: %w", err)
+	}
+
+	var discrepancies []*domain.Discrepancy
+	run.Status = domain.ReconciliationRunStatusSucceeded
+	snapshot, err := reference.Snapshot(ctx, sourceID)
+	if err == nil {
+		discrepancies, err = uc.reconcile(ctx, snapshot, run)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Reconciliation failed")
+		run.Status = domain.ReconciliationRunStatusFailed
+		run.Error = sql.NullString{String: err.Error(), Valid: true}
+	}
+	run.FinishedAt = sql.NullTime{Time: uc.clock.Now(), Valid: true}
+
+	if err := uc.reconciliationRepo.FinishRun(ctx, run, discrepancies); err != nil {
+		logger.WithError(err).Error("Failed to finish reconciliation run")
+		return nil, fmt.Errorf("failed to finish reconciliation run: %w", err)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"run_id":        run.ID,
+		"status":        run.Status,
+		"checked":       run.Checked,
+		"discrepancies": run.Discrepancies,
+		"healed":        run.Healed,
+		"failed":        run.Failed,
+	}).Info("Reconciliation finished")
+
+	return run, nil
+}
+
+// ReconcileAll reconciles every enabled feed and connector in turn.
+func (uc *ReconciliationUseCase) ReconcileAll(ctx context.Context, policy string) error {
+	for _, source := range []string{domain.ReconciliationSourceFeed, domain.ReconciliationSourceConnector} {
+		reference, ok := uc.sources[source]
+		if !ok {
+			continue
+		}
+
+		ids, err := reference.Enabled(ctx)
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).WithField("source", source).Error("Failed to list reconciliation sources")
+			return err
+		}
+
+		for _, id := range ids {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if _, err := uc.Reconcile(ctx, source, id, policy); err != nil && !errors.Is(err, domain.ErrReconciliationInProgress) {
+				logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{
+					"source":    source,
+					"source_id": id,
+				}).Error("Scheduled reconciliation failed")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (uc *ReconciliationUseCase) GetRun(ctx context.Context, id int64) (*domain.ReconciliationRun, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid reconciliation run ID", domain.ErrInvalidReconciliation)
+	}
+
+	run, err := uc.reconciliationRepo.GetRun(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get reconciliation run from repository")
+		return nil, err
+	}
+
+	return run, nil
+}
+
+func (uc *ReconciliationUseCase) GetRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	runs, err := uc.reconciliationRepo.GetRuns(ctx, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get reconciliation runs from repository")
+		return nil, fmt.Errorf("failed to get reconciliation runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+func (uc *ReconciliationUseCase) GetDiscrepancies(ctx context.Context, runID int64, limit, offset int) ([]*domain.Discrepancy, error) {
+	if _, err := uc.GetRun(ctx, runID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	list, err := uc.reconciliationRepo.GetDiscrepancies(ctx, runID, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get discrepancies from repository")
+		return nil, fmt.Errorf("failed to get discrepancies: %w", err)
+	}
+
+	return list, nil
+}
+
+func (uc *ReconciliationUseCase) reconcile(ctx context.Context, snapshot *domain.ReferenceSnapshot, run *domain.ReconciliationRun) ([]*domain.Discrepancy, error) {
+	catalog, err := uc.productRepo.GetAllByStore(ctx, snapshot.StoreID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load store catalog: %w", err)
+	}
+
+	byID := make(map[int64]*domain.Product, len(catalog))
+	byName := make(map[string]*domain.Product, len(catalog))
+	for _, product := range catalog {
+		byID[product.ID] = product
+		byName[product.Name] = product
+	}
+
+	var kept []*domain.Discrepancy
+	record := func(d *domain.Discrepancy) {
+		run.Discrepancies++
+		if d.Healed {
+			run.Healed++
+		}
+		if len(kept) < maxKeptDiscrepancies {
+			kept = append(kept, d)
+		}
+	}
+
+	matched := make(map[int64]bool, len(snapshot.Products))
+	seen := make(map[string]bool, len(snapshot.Products))
+	for _, ref := range snapshot.Products {
+		run.Checked++
+
+		var local *domain.Product
+		if ref.ProductID != 0 {
+			local = byID[ref.ProductID]
+			if local == nil {
+				// The linked product is in the trash and is left alone
+				// until it is restored or purged.
+				continue
+			}
+		} else {
+			if seen[ref.Name] {
+				run.Failed++
+				continue
+			}
+			seen[ref.Name] = true
+			local = byName[ref.Name]
+		}
+
+		if local == nil {
+			record(uc.create(ctx, snapshot, ref, run))
+			continue
+		}
+		matched[local.ID] = true
+
+		mismatches := compareReference(local, ref)
+		if len(mismatches) == 0 {
+			continue
+		}
+
+		healed := false
+		if uc.shouldHeal(run.Policy, local, ref, snapshot.TakenAt) {
+			if err := uc.heal(ctx, local, ref); err != nil {
+				run.Failed++
+				logger.FromContext(ctx).WithError(err).WithField("product_id", local.ID).Warn("Failed to heal product")
+			} else {
+				healed = true
+			}
+		}
+		for _, d := range mismatches {
+			d.Healed = healed
+			record(d)
+		}
+	}
+
+	for _, product := range catalog {
+		if matched[product.ID] || product.Status == domain.ProductStatusInactive {
+			continue
+		}
+		if snapshot.Scope != nil && !snapshot.Scope[product.ID] {
+			continue
+		}
+		record(&domain.Discrepancy{
+			ProductID: sql.NullInt64{Int64: product.ID, Valid: true},
+			Name:      product.Name,
+			Kind:      domain.DiscrepancyMissingInSource,
+		})
+	}
+
+	return kept, nil
+}
+
+// create reports a product the catalog lacks, creating it unless the
+// policy only reports.
+func (uc *ReconciliationUseCase) create(ctx context.Context, snapshot *domain.ReferenceSnapshot, ref domain.ReferenceProduct, run *domain.ReconciliationRun) *domain.Discrepancy {
+	d := &domain.Discrepancy{
+		Name: ref.Name,
+		Kind: domain.DiscrepancyMissingLocally,
+	}
+	if run.Policy == domain.ReconciliationPolicyReport {
+		return d
+	}
+
+	created, err := uc.products.CreateProduct(ctx, itemToProduct(snapshot.StoreID, domain.FeedItem{
+		Name:        ref.Name,
+		Description: ref.Description,
+		Amount:      ref.Amount,
+		Unit:        ref.Unit,
+		Price:       ref.Price,
+	}))
+	if err != nil {
+		run.Failed++
+		logger.FromContext(ctx).WithError(err).WithField("name", ref.Name).Warn("Failed to create missing product")
+		return d
+	}
+
+	d.ProductID = sql.NullInt64{Int64: created.ID, Valid: true}
+	d.Healed = true
+	return d
+}
+
+// shouldHeal applies the conflict-resolution policy. Under newest_wins a
+// reference without its own change time counts as changed when the
+// snapshot was taken.
+func (uc *ReconciliationUseCase) shouldHeal(policy string, local *domain.Product, ref domain.ReferenceProduct, takenAt time.Time) bool {
+	switch policy {
+	case domain.ReconciliationPolicySourceWins:
+		return true
+	case domain.ReconciliationPolicyNewestWins:
+		changedAt := ref.UpdatedAt
+		if changedAt.IsZero() {
+			changedAt = takenAt
+		}
+		return changedAt.After(local.UpdatedAt)
+	}
+	return false
+}
+
+// heal writes the source's fields over the product in one update, keeping
+// everything the source does not carry.
+func (uc *ReconciliationUseCase) heal(ctx context.Context, local *domain.Product, ref domain.ReferenceProduct) error {
+	desired := *local
+	desired.Name = ref.Name
+	desired.Description = sql.NullString{String: ref.Description, Valid: ref.Description != ""}
+	desired.Amount = ref.Amount
+	if ref.Unit != "" {
+		desired.Unit = ref.Unit
+	}
+	desired.Price = ref.Price
+
+	_, err := uc.products.UpdateProduct(ctx, local.ID, &desired)
+	return err
+}
+
+func (uc *ReconciliationUseCase) acquire(key string) bool {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if _, ok := uc.running[key]; ok {
+		return false
+	}
+	uc.running[key] = struct{}{}
+	return true
+}
+
+func (uc *ReconciliationUseCase) release(key string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	delete(uc.running, key)
+}
+
+// compareReference lists the fields in which the product differs from the
+// source. Names only differ for linked products, since the rest are matched
+// by name.
+func compareReference(local *domain.Product, ref domain.ReferenceProduct) []*domain.Discrepancy {
+	var mismatches []*domain.Discrepancy
+	add := func(field, localValue, sourceValue string) {
+		if localValue == sourceValue {
+			return
+		}
+		mismatches = append(mismatches, &domain.Discrepancy{
+			ProductID:   sql.NullInt64{Int64: local.ID, Valid: true},
+			Name:        local.Name,
+			Kind:        domain.DiscrepancyFieldMismatch,
+			Field:       field,
+			LocalValue:  localValue,
+			SourceValue: sourceValue,
+		})
+	}
+
+	add("name", local.Name, ref.Name)
+	add("description", local.Description.String, ref.Description)
+	add("amount", local.Amount.String(), ref.Amount.String())
+	if ref.Unit != "" {
+		add("unit", local.Unit, ref.Unit)
+	}
+	add("price", strconv.FormatFloat(local.Price, 'f', -1, 64), strconv.FormatFloat(ref.Price, 'f', -1, 64))
+
+	return mismatches
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/connectors"
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/database"
+	"backend-context-engineering-template/pkg/quantity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReconciliationRepository struct {
+	mock.Mock
+}
+
+func (m *MockReconciliationRepository) CreateRun(ctx context.Context, run *domain.ReconciliationRun) (*domain.ReconciliationRun, error) {
+	args := m.Called(ctx, run)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReconciliationRun), args.Error(1)
+}
+
+func (m *MockReconciliationRepository) FinishRun(ctx context.Context, run *domain.ReconciliationRun, discrepancies []*domain.Discrepancy) error {
+	args := m.Called(ctx, run, discrepancies)
+	return args.Error(0)
+}
+
+func (m *MockReconciliationRepository) GetRun(ctx context.Context, id int64) (*domain.ReconciliationRun, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReconciliationRun), args.Error(1)
+}
+
+func (m *MockReconciliationRepository) GetRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*domain.ReconciliationRun), args.Error(1)
+}
+
+func (m *MockReconciliationRepository) GetDiscrepancies(ctx context.Context, runID int64, limit, offset int) ([]*domain.Discrepancy, error) {
+	args := m.Called(ctx, runID, limit, offset)
+	return args.Get(0).([]*domain.Discrepancy), args.Error(1)
+}
+
+type fakeReference struct {
+	storeID  int64
+	snapshot *domain.ReferenceSnapshot
+	err      error
+}
+
+func (f *fakeReference) StoreID(ctx context.Context, id int64) (int64, error) {
+	return f.storeID, nil
+}
+
+func (f *fakeReference) Snapshot(ctx context.Context, id int64) (*domain.ReferenceSnapshot, error) {
+	return f.snapshot, f.err
+}
+
+func (f *fakeReference) Enabled(ctx context.Context) ([]int64, error) {
+	return []int64{1}, nil
+}
+
+func TestReconciliationUseCase_Reconcile(t *testing.T) {
+	ctx := context.Background()
+
+	localEdit := time.Now()
+	snapshot := &domain.ReferenceSnapshot{
+		StoreID: 3,
+		TakenAt: localEdit.Add(-time.Hour),
+		Products: []domain.ReferenceProduct{
+			{Name: "New", Amount: quantity.New(1), Unit: domain.UnitPiece, Price: 5},
+			{Name: "Changed", Amount: quantity.New(9), Unit: domain.UnitPiece, Price: 12},
+			{Name: "Same", Amount: quantity.New(2), Unit: domain.UnitPiece, Price: 3},
+		},
+	}
+	catalog := func() []*domain.Product {
+		return []*domain.Product{
+			{ID: 1, StoreID: 3, Name: "Changed", Amount: quantity.New(1), Unit: domain.UnitPiece, Price: 10, Status: domain.ProductStatusActive, UpdatedAt: localEdit},
+			{ID: 2, StoreID: 3, Name: "Same", Amount: quantity.New(2), Unit: domain.UnitPiece, Price: 3, Status: domain.ProductStatusActive},
+			{ID: 3, StoreID: 3, Name: "Local only", Amount: quantity.New(4), Unit: domain.UnitPiece, Price: 8, Status: domain.ProductStatusActive},
+			{ID: 4, StoreID: 3, Name: "Retired", Amount: quantity.New(0), Unit: domain.UnitPiece, Price: 8, Status: domain.ProductStatusInactive},
+		}
+	}
+
+	setup := func(policy string) (*MockReconciliationRepository, *MockProductRepository, *ReconciliationUseCase) {
+		repo := &MockReconciliationRepository{}
+		productRepo := &MockProductRepository{}
+		repo.On("CreateRun", mock.Anything, mock.MatchedBy(func(run *domain.ReconciliationRun) bool {
+			return run.StoreID == 3 && run.Policy == policy
+		})).Return(&domain.ReconciliationRun{ID: 9, StoreID: 3, Source: domain.ReconciliationSourceFeed, SourceID: 7, Policy: policy}, nil)
+		productRepo.On("GetAllByStore", mock.Anything, int64(3)).Return(catalog(), nil)
+
+		sources := map[string]ReferenceSource{domain.ReconciliationSourceFeed: &fakeReference{storeID: 3, snapshot: snapshot}}
+		uc := NewReconciliationUseCase(repo, productRepo, NewProductUseCase(database.NopTxManager{}, productRepo, nil, nil, nil, clock.Real()), sources, clock.Real())
+		return repo, productRepo, uc
+	}
+
+	t.Run("report only reports", func(t *testing.T) {
+		repo, productRepo, uc := setup(domain.ReconciliationPolicyReport)
+		var kept []*domain.Discrepancy
+		repo.On("FinishRun", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			kept = args.Get(2).([]*domain.Discrepancy)
+		}).Return(nil)
+
+		run, err := uc.Reconcile(ctx, domain.ReconciliationSourceFeed, 7, domain.ReconciliationPolicyReport)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReconciliationRunStatusSucceeded, run.Status)
+		assert.Equal(t, 3, run.Checked)
+		assert.Equal(t, 4, run.Discrepancies, "missing locally, two fields and missing in source")
+		assert.Equal(t, 0, run.Healed)
+		require.Len(t, kept, 4)
+		assert.Equal(t, domain.DiscrepancyMissingLocally, kept[0].Kind)
+		assert.Equal(t, "amount", kept[1].Field)
+		assert.Equal(t, "1", kept[1].LocalValue)
+		assert.Equal(t, "9", kept[1].SourceValue)
+		assert.Equal(t, "price", kept[2].Field)
+		assert.Equal(t, domain.DiscrepancyMissingInSource, kept[3].Kind)
+		assert.Equal(t, int64(3), kept[3].ProductID.Int64)
+
+		productRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		productRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("source wins heals all but missing in source", func(t *testing.T) {
+		repo, productRepo, uc := setup(domain.ReconciliationPolicySourceWins)
+		repo.On("FinishRun", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		productRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Name == "New" && p.StoreID == 3
+		})).Return(&domain.Product{ID: 5, StoreID: 3}, nil)
+		productRepo.On("Update", mock.Anything, int64(1), mock.MatchedBy(func(p *domain.Product) bool {
+			return p.Amount == quantity.New(9) && p.Price == 12 && p.Status == domain.ProductStatusActive
+		})).Return(&domain.Product{ID: 1, StoreID: 3}, nil).Once()
+
+		run, err := uc.Reconcile(ctx, domain.ReconciliationSourceFeed, 7, domain.ReconciliationPolicySourceWins)
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, run.Discrepancies)
+		assert.Equal(t, 3, run.Healed)
+		assert.Equal(t, 0, run.Failed)
+		productRepo.AssertExpectations(t)
+	})
+
+	t.Run("newest wins keeps newer local edits", func(t *testing.T) {
+		repo, productRepo, uc := setup(domain.ReconciliationPolicyNewestWins)
+		repo.On("FinishRun", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		productRepo.On("Create", mock.Anything, mock.Anything).Return(&domain.Product{ID: 5, StoreID: 3}, nil)
+
+		run, err := uc.Reconcile(ctx, domain.ReconciliationSourceFeed, 7, domain.ReconciliationPolicyNewestWins)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, run.Healed, "only the missing product is created")
+		productRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestReconciliationUseCase_Reconcile_SnapshotFails(t *testing.T) {
+
+	repo := &MockReconciliationRepository{}
+	repo.On("CreateRun", mock.Anything, mock.Anything).Return(&domain.ReconciliationRun{ID: 9, StoreID: 3}, nil)
+	repo.On("FinishRun", mock.Anything, mock.Anything, []*domain.Discrepancy(nil)).Return(nil)
+
+	sources := map[string]ReferenceSource{domain.ReconciliationSourceFeed: &fakeReference{storeID: 3, err: errors.New("connection refused")}}
+	uc := NewReconciliationUseCase(repo, &MockProductRepository{}, nil, sources, clock.Real())
+	run, err := uc.Reconcile(context.Background(), domain.ReconciliationSourceFeed, 7, domain.ReconciliationPolicyReport)
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReconciliationRunStatusFailed, run.Status)
+	assert.Equal(t, "connection refused", run.Error.String)
+	assert.True(t, run.FinishedAt.Valid)
+	repo.AssertExpectations(t)
+}
+
+func TestReconciliationUseCase_Reconcile_Invalid(t *testing.T) {
+	uc := NewReconciliationUseCase(&MockReconciliationRepository{}, &MockProductRepository{}, nil, map[string]ReferenceSource{}, clock.Real())
+
+	_, err := uc.Reconcile(context.Background(), domain.ReconciliationSourceFeed, 7, "last_writer")
+	assert.ErrorIs(t, err, domain.ErrInvalidReconciliation)
+
+	_, err = uc.Reconcile(context.Background(), domain.ReconciliationSourceFeed, 7, domain.ReconciliationPolicyReport)
+	assert.ErrorIs(t, err, domain.ErrInvalidReconciliation, "no feed source configured")
+}
+
+func TestConnectorReference_Snapshot(t *testing.T) {
+	repo := &MockConnectorRepository{}
+	secretStore := &MockSecretStore{}
+	adapter := &fakeConnector{pages: []*connectors.Page{
+		{Products: []connectors.ExternalProduct{{ExternalID: "a", Name: "Linked", Amount: 3, Price: 9}}, Next: "p2"},
+		{Products: []connectors.ExternalProduct{{ExternalID: "b", Name: "Unlinked", Amount: 1, Price: 4}}},
+	}}
+
+	repo.On("GetByID", mock.Anything, int64(2)).Return(&domain.Connector{ID: 2, StoreID: 5, Kind: "fake"}, nil)
+	secretStore.On("Get", mock.Anything, "connector/fake/2").Return([]byte("token"), nil)
+	repo.On("GetLinks", mock.Anything, int64(2)).Return([]*domain.ProductLink{
+		{ConnectorID: 2, ProductID: 30, ExternalID: "a"},
+		{ConnectorID: 2, ProductID: 31, ExternalID: "gone"},
+	}, nil)
+
+	snapshot, err := NewConnectorReference(repo, secretStore, &fakeFactory{connector: adapter}, clock.Real()).Snapshot(context.Background(), 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), snapshot.StoreID)
+	require.Len(t, snapshot.Products, 2)
+	assert.Equal(t, int64(30), snapshot.Products[0].ProductID)
+	assert.Equal(t, quantity.New(3), snapshot.Products[0].Amount)
+	assert.Equal(t, int64(0), snapshot.Products[1].ProductID)
+	assert.Equal(t, map[int64]bool{30: true, 31: true}, snapshot.Scope)
+	repo.AssertNotCalled(t, "SaveCheckpoint", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+// FeedReference takes snapshots of registered feeds. A feed publishes a
+// store's whole catalog, so its items are matched to products by name.
+type FeedReference struct {
+	feedRepo FeedRepository
+	fetcher  FeedFetcher
+	clock    clock.Clock
+}
+
+func NewFeedReference(feedRepo FeedRepository, fetcher FeedFetcher, clk clock.Clock) *FeedReference {
+	return &FeedReference{
+		feedRepo: feedRepo,
+		fetcher:  fetcher,
+		clock:    clk,
+	}
+}
+
+func (r *FeedReference) StoreID(ctx context.Context, id int64) (int64, error) {
+	feed, err := r.feedRepo.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return feed.StoreID, nil
+}
+
+func (r *FeedReference) Snapshot(ctx context.Context, id int64) (*domain.ReferenceSnapshot, error) {
+	feed, err := r.feedRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	takenAt := r.clock.Now()
+	items, err := r.fetcher.Fetch(ctx, feed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", domain.ErrFeedFetchFailed, err.Error())
+	}
+
+	snapshot := &domain.ReferenceSnapshot{
+		StoreID:  feed.StoreID,
+		TakenAt:  takenAt,
+		Products: make([]domain.ReferenceProduct, 0, len(items)),
+	}
+	for _, item := range items {
+		unit := item.Unit
+		if unit == "" {
+			unit = domain.UnitPiece
+		}
+		snapshot.Products = append(snapshot.Products, domain.ReferenceProduct{
+			Name:        item.Name,
+			Description: item.Description,
+			Amount:      item.Amount,
+			Unit:        unit,
+			Price:       item.Price,
+		})
+	}
+
+	return snapshot, nil
+}
+
+func (r *FeedReference) Enabled(ctx context.Context) ([]int64, error) {
+	feeds, err := r.feedRepo.GetEnabled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enabled feeds: %w", err)
+	}
+
+	ids := make([]int64, 0, len(feeds))
+	for _, feed := range feeds {
+		ids = append(ids, feed.ID)
+	}
+	return ids, nil
+}
+
+// ConnectorReference takes snapshots of connectors by pulling their full
+// catalog. The pull leaves the connector's checkpoint alone, so it does not
+// disturb incremental syncs. Pulled products are matched through the
+// connector's product links, and only linked products can be missing from
+// it.
+type ConnectorReference struct {
+	connectorRepo ConnectorRepository
+	secrets       SecretStore
+	factory       ConnectorFactory
+	clock         clock.Clock
+}
+
+func NewConnectorReference(connectorRepo ConnectorRepository, secrets SecretStore, factory ConnectorFactory, clk clock.Clock) *ConnectorReference {
+	return &ConnectorReference{
+		connectorRepo: connectorRepo,
+		secrets:       secrets,
+		factory:       factory,
+		clock:         clk,
+	}
+}
+
+func (r *ConnectorReference) StoreID(ctx context.Context, id int64) (int64, error) {
+	connector, err := r.connectorRepo.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return connector.StoreID, nil
+}
+
+func (r *ConnectorReference) Snapshot(ctx context.Context, id int64) (*domain.ReferenceSnapshot, error) {
+	connector, err := r.connectorRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := r.secrets.Get(ctx, connector.SecretKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load connector credentials: %w", err)
+	}
+
+	adapter, err := r.factory.New(connector.Kind, connector.Settings, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := r.connectorRepo.GetLinks(ctx, connector.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load product links: %w", err)
+	}
+
+	snapshot := &domain.ReferenceSnapshot{
+		StoreID: connector.StoreID,
+		TakenAt: r.clock.Now(),
+		Scope:   make(map[int64]bool, len(links)),
+	}
+
+	linked := make(map[string]int64, len(links))
+	for _, link := range links {
+		linked[link.ExternalID] = link.ProductID
+		snapshot.Scope[link.ProductID] = true
+	}
+
+	cursor := ""
+	for {
+		page, err := adapter.PullProducts(ctx, cursor, time.Time{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull products: %w", err)
+		}
+
+		for _, external := range page.Products {
+			amount, err := quantity.FromInt(external.Amount)
+			if err != nil {
+				return nil, fmt.Errorf("product %s: %w", external.ExternalID, err)
+			}
+			snapshot.Products = append(snapshot.Products, domain.ReferenceProduct{
+				ProductID:   linked[external.ExternalID],
+				Name:        external.Name,
+				Description: external.Description,
+				Amount:      amount,
+				Unit:        external.Unit,
+				Price:       external.Price,
+				UpdatedAt:   external.UpdatedAt,
+			})
+		}
+
+		if page.Next == "" {
+			break
+		}
+		cursor = page.Next
+	}
+
+	return snapshot, nil
+}
+
+// Enabled pages through every connector, since connectors are few.
+func (r *ConnectorReference) Enabled(ctx context.Context) ([]int64, error) {
+	const pageSize = 100
+
+	var ids []int64
+	for offset := 0; ; offset += pageSize {
+		list, err := r.connectorRepo.GetAll(ctx, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get connectors: %w", err)
+		}
+		for _, connector := range list {
+			if connector.Enabled {
+				ids = append(ids, connector.ID)
+			}
+		}
+		if len(list) < pageSize {
+			return ids, nil
+		}
+	}
+}
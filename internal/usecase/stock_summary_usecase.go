@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StockSummaryUseCase keeps the stores' stock summaries. They are
+// recalculated by a background job after a store's products change.
+type StockSummaryUseCase struct {
+	repo  StockSummaryRepository
+	clock clock.Clock
+}
+
+func NewStockSummaryUseCase(repo StockSummaryRepository, clk clock.Clock) *StockSummaryUseCase {
+	return &StockSummaryUseCase{
+		repo:  repo,
+		clock: clk,
+	}
+}
+
+// RecalculateStockSummary counts the store's products as of now.
+func (uc *StockSummaryUseCase) RecalculateStockSummary(ctx context.Context, storeID int64) (*domain.StockSummary, error) {
+	if storeID <= 0 {
+		return nil, fmt.Errorf("%w: invalid store ID", domain.ErrInvalidStore)
+	}
+
+	summary, err := uc.repo.Recalculate(ctx, storeID, uc.clock.Now())
+	if err != nil {
+		if !errors.Is(err, domain.ErrStoreNotFound) {
+			logger.FromContext(ctx).WithError(err).WithField("store_id", storeID).Error("Failed to recalculate stock summary")
+		}
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "recalculate_stock_summary",
+		"store_id": storeID,
+		"products": summary.Products,
+	}).Debug("Stock summary recalculated")
+	return summary, nil
+}
+
+// GetStockSummary returns the store's stock summary. A store whose products
+// have not changed since summaries were introduced has none yet; its
+// summary is calculated on the first read.
+func (uc *StockSummaryUseCase) GetStockSummary(ctx context.Context, storeID int64) (*domain.StockSummary, error) {
+	if storeID <= 0 {
+		return nil, fmt.Errorf("%w: invalid store ID", domain.ErrInvalidStore)
+	}
+
+	summary, err := uc.repo.GetByStoreID(ctx, storeID)
+	if errors.Is(err, domain.ErrStockSummaryNotFound) {
+		return uc.RecalculateStockSummary(ctx, storeID)
+	}
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get stock summary from repository")
+		return nil, err
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStockSummaryRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockSummaryRepository) Recalculate(ctx context.Context, storeID int64, calculatedAt time.Time) (*domain.StockSummary, error) {
+	args := m.Called(ctx, storeID, calculatedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockSummary), args.Error(1)
+}
+
+func (m *MockStockSummaryRepository) GetByStoreID(ctx context.Context, storeID int64) (*domain.StockSummary, error) {
+	args := m.Called(ctx, storeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.StockSummary), args.Error(1)
+}
+
+func TestStockSummaryUseCase_GetStockSummary(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	saved := &domain.StockSummary{StoreID: 1, Products: 3, InStock: 2, OutOfStock: 1, CalculatedAt: now.Add(-time.Hour)}
+	calculated := &domain.StockSummary{StoreID: 2, Products: 1, LowStock: 1, CalculatedAt: now}
+
+	tests := []struct {
+		name          string
+		storeID       int64
+		setupMock     func(*MockStockSummaryRepository)
+		expected      *domain.StockSummary
+		expectedError error
+	}{
+		{
+			name:    "saved summary",
+			storeID: 1,
+			setupMock: func(m *MockStockSummaryRepository) {
+				m.On("GetByStoreID", mock.Anything, int64(1)).Return(saved, nil)
+			},
+			expected: saved,
+		},
+		{
+			name:    "calculated on first read",
+			storeID: 2,
+			setupMock: func(m *MockStockSummaryRepository) {
+				m.On("GetByStoreID", mock.Anything, int64(2)).Return(nil, domain.ErrStockSummaryNotFound)
+				m.On("Recalculate", mock.Anything, int64(2), now).Return(calculated, nil)
+			},
+			expected: calculated,
+		},
+		{
+			name:    "unknown store",
+			storeID: 3,
+			setupMock: func(m *MockStockSummaryRepository) {
+				m.On("GetByStoreID", mock.Anything, int64(3)).Return(nil, domain.ErrStockSummaryNotFound)
+				m.On("Recalculate", mock.Anything, int64(3), now).Return(nil, domain.ErrStoreNotFound)
+			},
+			expectedError: domain.ErrStoreNotFound,
+		},
+		{
+			name:          "invalid store ID",
+			storeID:       0,
+			setupMock:     func(m *MockStockSummaryRepository) {},
+			expectedError: domain.ErrInvalidStore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockStockSummaryRepository)
+			tt.setupMock(repo)
+			uc := NewStockSummaryUseCase(repo, clock.NewFake(now))
+
+			summary, err := uc.GetStockSummary(context.Background(), tt.storeID)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, summary)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, summary)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
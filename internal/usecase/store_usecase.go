@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// StoreUseCase manages the stores that products belong to.
+type StoreUseCase struct {
+	storeRepo StoreRepository
+}
+
+func NewStoreUseCase(storeRepo StoreRepository) *StoreUseCase {
+	return &StoreUseCase{
+		storeRepo: storeRepo,
+	}
+}
+
+func (uc *StoreUseCase) CreateStore(ctx context.Context, store *domain.Store) (*domain.Store, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action": "create_store",
+		"name":   store.Name,
+	}).Info("Creating store")
+
+	if err := store.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Store validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidStore, err)
+	}
+
+	created, err := uc.storeRepo.Create(ctx, store)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to create store in repository")
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+
+	return created, nil
+}
+
+func (uc *StoreUseCase) GetStore(ctx context.Context, id int64) (*domain.Store, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid store ID", domain.ErrInvalidStore)
+	}
+
+	store, err := uc.storeRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get store from repository")
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func (uc *StoreUseCase) GetStores(ctx context.Context, limit, offset int) ([]*domain.Store, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	stores, err := uc.storeRepo.GetAll(ctx, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get stores from repository")
+		return nil, fmt.Errorf("failed to get stores: %w", err)
+	}
+
+	return stores, nil
+}
+
+func (uc *StoreUseCase) UpdateStore(ctx context.Context, id int64, store *domain.Store) (*domain.Store, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid store ID", domain.ErrInvalidStore)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "update_store",
+		"store_id": id,
+	}).Info("Updating store")
+
+	if err := store.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Store validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidStore, err)
+	}
+
+	updated, err := uc.storeRepo.Update(ctx, id, store)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to update store in repository")
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// DeleteStore removes a store that no longer has products.
+func (uc *StoreUseCase) DeleteStore(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return fmt.Errorf("%w: invalid store ID", domain.ErrInvalidStore)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "delete_store",
+		"store_id": id,
+	}).Info("Deleting store")
+
+	if err := uc.storeRepo.Delete(ctx, id); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to delete store from repository")
+		return err
+	}
+
+	return nil
+}
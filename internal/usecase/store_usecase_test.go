@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStoreRepository struct {
+	mock.Mock
+}
+
+func (m *MockStoreRepository) Create(ctx context.Context, store *domain.Store) (*domain.Store, error) {
+	args := m.Called(ctx, store)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreRepository) GetByID(ctx context.Context, id int64) (*domain.Store, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.Store, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreRepository) Update(ctx context.Context, id int64, store *domain.Store) (*domain.Store, error) {
+	args := m.Called(ctx, id, store)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Store), args.Error(1)
+}
+
+func (m *MockStoreRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestStoreUseCase_CreateStore(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		store   *domain.Store
+		mockFn  func(*MockStoreRepository)
+		wantErr error
+	}{
+		{
+			name:  "valid store",
+			store: &domain.Store{Name: "Corner Shop"},
+			mockFn: func(m *MockStoreRepository) {
+				m.On("Create", mock.Anything, mock.Anything).Return(&domain.Store{ID: 1, Name: "Corner Shop"}, nil)
+			},
+		},
+		{
+			name:    "blank name",
+			store:   &domain.Store{Name: "  "},
+			mockFn:  func(m *MockStoreRepository) {},
+			wantErr: domain.ErrInvalidStore,
+		},
+		{
+			name:    "name too long",
+			store:   &domain.Store{Name: strings.Repeat("a", 101)},
+			mockFn:  func(m *MockStoreRepository) {},
+			wantErr: domain.ErrInvalidStore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockStoreRepository)
+			tt.mockFn(repo)
+			uc := NewStoreUseCase(repo)
+
+			created, err := uc.CreateStore(ctx, tt.store)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, created)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), created.ID)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStoreUseCase_GetStores(t *testing.T) {
+	repo := new(MockStoreRepository)
+	repo.On("GetAll", mock.Anything, 100, 0).Return([]*domain.Store{}, nil)
+	uc := NewStoreUseCase(repo)
+
+	_, err := uc.GetStores(context.Background(), 1000, -5)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestStoreUseCase_DeleteStore(t *testing.T) {
+	repo := new(MockStoreRepository)
+	repo.On("Delete", mock.Anything, int64(1)).Return(domain.ErrStoreHasProducts)
+	uc := NewStoreUseCase(repo)
+
+	assert.ErrorIs(t, uc.DeleteStore(context.Background(), 1), domain.ErrStoreHasProducts)
+	assert.ErrorIs(t, uc.DeleteStore(context.Background(), 0), domain.ErrInvalidStore)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+)
+
+// TrashPurger periodically removes products that have been in the trash
+// longer than the retention period.
+type TrashPurger struct {
+	trashUseCase TrashUseCaseInterface
+	interval     time.Duration
+	clock        clock.Clock
+}
+
+func NewTrashPurger(trashUseCase TrashUseCaseInterface, interval time.Duration, clk clock.Clock) *TrashPurger {
+	return &TrashPurger{
+		trashUseCase: trashUseCase,
+		interval:     interval,
+		clock:        clk,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (p *TrashPurger) Run(ctx context.Context) {
+	logger.FromContext(ctx).WithField("interval", p.interval).Info("Trash purger started")
+
+	ticker := p.clock.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.FromContext(ctx).Info("Trash purger stopped")
+			return
+		case <-ticker.C():
+			if _, err := p.trashUseCase.PurgeExpired(ctx); err != nil && ctx.Err() == nil {
+				logger.FromContext(ctx).WithError(err).Error("Failed to purge trash")
+			}
+		}
+	}
+}
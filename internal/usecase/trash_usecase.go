@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// TrashUseCase manages the recycle bin deleted products are moved into.
+// Trashed products can be restored until they are older than the retention
+// period, after which PurgeExpired removes them for good.
+type TrashUseCase struct {
+	trashRepo TrashRepository
+	guard     *BulkGuard
+	retention time.Duration
+	clock     clock.Clock
+}
+
+// NewTrashUseCase holds back emptying trashes larger than guard allows;
+// guard may be nil.
+func NewTrashUseCase(trashRepo TrashRepository, guard *BulkGuard, retention time.Duration, clk clock.Clock) *TrashUseCase {
+	return &TrashUseCase{
+		trashRepo: trashRepo,
+		guard:     guard,
+		retention: retention,
+		clock:     clk,
+	}
+}
+
+func (uc *TrashUseCase) GetTrash(ctx context.Context, storeID int64, limit, offset int) ([]*domain.TrashedProduct, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "get_trash",
+		"store_id": storeID,
+		"limit":    limit,
+		"offset":   offset,
+	}).Info("Retrieving trashed products")
+
+	if storeID < 0 {
+		return nil, fmt.Errorf("%w: invalid store ID", domain.ErrInvalidProduct)
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	products, err := uc.trashRepo.GetAll(ctx, storeID, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get trashed products from repository")
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+
+	for _, product := range products {
+		product.PurgeAt = product.TrashedAt.Add(uc.retention)
+	}
+
+	return products, nil
+}
+
+func (uc *TrashUseCase) RestoreProduct(ctx context.Context, id int64) (*domain.Product, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":     "restore_product",
+		"product_id": id,
+	}).Info("Restoring product from trash")
+
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid product ID", domain.ErrInvalidProduct)
+	}
+
+	product, err := uc.trashRepo.Restore(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to restore product")
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":     "restore_product",
+		"product_id": product.ID,
+	}).Info("Product restored successfully")
+
+	return product, nil
+}
+
+// EmptyTrash permanently deletes everything in the store's trash. The
+// caller must have confirmed the mass operation on ctx, or, for trashes
+// past the bulk threshold, through the bulk guard. Products trashed between
+// the confirmation and the delete are deleted as well.
+func (uc *TrashUseCase) EmptyTrash(ctx context.Context, storeID int64) (int64, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "empty_trash",
+		"store_id": storeID,
+	}).Info("Emptying trash")
+
+	if storeID <= 0 {
+		return 0, fmt.Errorf("%w: store_id is required", domain.ErrInvalidProduct)
+	}
+
+	count, err := uc.trashRepo.Count(ctx, storeID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to count trashed products")
+		return 0, fmt.Errorf("failed to empty trash: %w", err)
+	}
+	confirmed, err := uc.guard.Check(ctx, domain.BulkOperation{
+		Kind:     domain.BulkOperationEmptyTrash,
+		StoreID:  storeID,
+		Affected: count,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if !confirmed && !MassOperationConfirmed(ctx) {
+		return 0, fmt.Errorf("%w: emptying the trash of store %d deletes its products permanently", domain.ErrConfirmationRequired, storeID)
+	}
+
+	deleted, err := uc.trashRepo.Empty(ctx, storeID)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to empty trash")
+		return 0, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":  "empty_trash",
+		"deleted": deleted,
+	}).Info("Trash emptied")
+
+	return deleted, nil
+}
+
+// PurgeExpired permanently deletes products trashed longer ago than the
+// retention period.
+func (uc *TrashUseCase) PurgeExpired(ctx context.Context) (int64, error) {
+	purged, err := uc.trashRepo.PurgeBefore(ctx, uc.clock.Now().Add(-uc.retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	if purged > 0 {
+		logger.FromContext(ctx).WithFields(logrus.Fields{
+			"action": "purge_trash",
+			"purged": purged,
+		}).Info("Purged expired products from trash")
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTrashRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrashRepository) GetAll(ctx context.Context, storeID int64, limit, offset int) ([]*domain.TrashedProduct, error) {
+	args := m.Called(ctx, storeID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.TrashedProduct), args.Error(1)
+}
+
+func (m *MockTrashRepository) Restore(ctx context.Context, id int64) (*domain.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Product), args.Error(1)
+}
+
+func (m *MockTrashRepository) Count(ctx context.Context, storeID int64) (int64, error) {
+	args := m.Called(ctx, storeID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTrashRepository) Empty(ctx context.Context, storeID int64) (int64, error) {
+	args := m.Called(ctx, storeID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTrashRepository) PurgeBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestTrashUseCase_GetTrash(t *testing.T) {
+	ctx := context.Background()
+	trashedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		storeID  int64
+		limit    int
+		mockFn   func(*MockTrashRepository)
+		wantErr  bool
+		errType  error
+		wantSize int
+	}{
+		{
+			name:    "lists trash with purge date",
+			storeID: 1,
+			limit:   10,
+			mockFn: func(m *MockTrashRepository) {
+				m.On("GetAll", ctx, int64(1), 10, 0).Return([]*domain.TrashedProduct{
+					{Product: domain.Product{ID: 1, StoreID: 1}, TrashedAt: trashedAt},
+				}, nil)
+			},
+			wantSize: 1,
+		},
+		{
+			name:  "limit is capped",
+			limit: 500,
+			mockFn: func(m *MockTrashRepository) {
+				m.On("GetAll", ctx, int64(0), 100, 0).Return([]*domain.TrashedProduct{}, nil)
+			},
+		},
+		{
+			name:    "invalid store ID",
+			storeID: -1,
+			mockFn:  func(m *MockTrashRepository) {},
+			wantErr: true,
+			errType: domain.ErrInvalidProduct,
+		},
+		{
+			name: "repository error",
+			mockFn: func(m *MockTrashRepository) {
+				m.On("GetAll", ctx, int64(0), 10, 0).Return(nil, errors.New("database error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockTrashRepository{}
+			tt.mockFn(repo)
+
+			uc := NewTrashUseCase(repo, nil, 30*24*time.Hour, clock.Real())
+			products, err := uc.GetTrash(ctx, tt.storeID, tt.limit, 0)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.errType != nil {
+					assert.ErrorIs(t, err, tt.errType)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, products, tt.wantSize)
+				for _, product := range products {
+					assert.Equal(t, trashedAt.Add(30*24*time.Hour), product.PurgeAt)
+				}
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrashUseCase_RestoreProduct(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		id      int64
+		mockFn  func(*MockTrashRepository)
+		wantErr bool
+		errType error
+	}{
+		{
+			name: "successful restore",
+			id:   1,
+			mockFn: func(m *MockTrashRepository) {
+				m.On("Restore", ctx, int64(1)).Return(&domain.Product{ID: 1}, nil)
+			},
+		},
+		{
+			name: "not in trash",
+			id:   2,
+			mockFn: func(m *MockTrashRepository) {
+				m.On("Restore", ctx, int64(2)).Return(nil, domain.ErrTrashedProductNotFound)
+			},
+			wantErr: true,
+			errType: domain.ErrTrashedProductNotFound,
+		},
+		{
+			name:    "invalid ID",
+			id:      0,
+			mockFn:  func(m *MockTrashRepository) {},
+			wantErr: true,
+			errType: domain.ErrInvalidProduct,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockTrashRepository{}
+			tt.mockFn(repo)
+
+			uc := NewTrashUseCase(repo, nil, time.Hour, clock.Real())
+			product, err := uc.RestoreProduct(ctx, tt.id)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.errType)
+				assert.Nil(t, product)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.id, product.ID)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrashUseCase_EmptyTrash(t *testing.T) {
+	confirmed := WithMassOperationConfirmed(context.Background())
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		storeID     int64
+		mockFn      func(*MockTrashRepository)
+		expectedErr error
+	}{
+		{
+			name:    "confirmed",
+			ctx:     confirmed,
+			storeID: 4,
+			mockFn: func(m *MockTrashRepository) {
+				m.On("Count", confirmed, int64(4)).Return(int64(7), nil)
+				m.On("Empty", confirmed, int64(4)).Return(int64(7), nil)
+			},
+		},
+		{
+			name:    "unconfirmed",
+			ctx:     context.Background(),
+			storeID: 4,
+			mockFn: func(m *MockTrashRepository) {
+				m.On("Count", mock.Anything, int64(4)).Return(int64(7), nil)
+			},
+			expectedErr: domain.ErrConfirmationRequired,
+		},
+		{
+			name:        "every store",
+			ctx:         confirmed,
+			storeID:     0,
+			mockFn:      func(m *MockTrashRepository) {},
+			expectedErr: domain.ErrInvalidProduct,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockTrashRepository{}
+			tt.mockFn(repo)
+
+			uc := NewTrashUseCase(repo, nil, 7*24*time.Hour, clock.Real())
+			deleted, err := uc.EmptyTrash(tt.ctx, tt.storeID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(7), deleted)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTrashUseCase_PurgeExpired(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := &MockTrashRepository{}
+	repo.On("PurgeBefore", ctx, now.Add(-7*24*time.Hour)).Return(int64(3), nil)
+
+	uc := NewTrashUseCase(repo, nil, 7*24*time.Hour, clock.NewFake(now))
+
+	purged, err := uc.PurgeExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	repo.AssertExpectations(t)
+}
+
+func TestTrashPurger_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fake := clock.NewFake(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	purged := make(chan time.Time, 1)
+	repo := &MockTrashRepository{}
+	repo.On("PurgeBefore", mock.Anything, mock.Anything).Return(int64(1), nil).
+		Run(func(args mock.Arguments) { purged <- args.Get(1).(time.Time) })
+
+	uc := NewTrashUseCase(repo, nil, 24*time.Hour, fake)
+	purger := NewTrashPurger(uc, time.Hour, fake)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		purger.Run(ctx)
+	}()
+
+	fake.BlockUntilTickers(1)
+	fake.Advance(59 * time.Minute)
+	select {
+	case <-purged:
+		t.Fatal("purged before the interval elapsed")
+	default:
+	}
+
+	fake.Advance(time.Minute)
+	assert.Equal(t, fake.Now().Add(-24*time.Hour), <-purged)
+
+	cancel()
+	<-done
+	repo.AssertNumberOfCalls(t, "PurgeBefore", 1)
+}
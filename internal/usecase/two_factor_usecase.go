@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"backend-context-engineering-template/pkg/totp"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	recoveryCodeCount = 10
+	// totpSkew accepts codes one step either side of now to absorb clock
+	// drift on the user's device.
+	totpSkew = 1
+)
+
+// TwoFactorUseCase manages TOTP enrollment and verifies second factors at
+// login. Enrollment is two-step: Setup issues a secret, and only Confirm
+// with a valid code turns enforcement on and hands out recovery codes.
+type TwoFactorUseCase struct {
+	repo     TwoFactorRepository
+	secrets  SecretStore
+	issuer   string
+	policies domain.TwoFactorPolicies
+	clock    clock.Clock
+}
+
+func NewTwoFactorUseCase(repo TwoFactorRepository, secrets SecretStore, issuer string, policies domain.TwoFactorPolicies, clk clock.Clock) *TwoFactorUseCase {
+	return &TwoFactorUseCase{
+		repo:     repo,
+		secrets:  secrets,
+		issuer:   issuer,
+		policies: policies,
+		clock:    clk,
+	}
+}
+
+func (uc *TwoFactorUseCase) Setup(ctx context.Context, identity string) (*domain.TwoFactorSetup, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "setup_two_factor",
+		"identity": identity,
+	}).Info("Starting two-factor enrollment")
+
+	existing, err := uc.repo.Get(ctx, identity)
+	if err != nil && !errors.Is(err, domain.ErrTwoFactorNotEnrolled) {
+		return nil, err
+	}
+	if existing != nil && existing.Confirmed() {
+		return nil, domain.ErrTwoFactorAlreadyEnrolled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.secrets.Put(ctx, domain.TwoFactorSecretKey(identity), []byte(secret)); err != nil {
+		return nil, fmt.Errorf("failed to store two-factor secret: %w", err)
+	}
+
+	enrollment := &domain.TwoFactorEnrollment{
+		Identity:  identity,
+		CreatedAt: uc.clock.Now(),
+	}
+	if err := uc.repo.Save(ctx, enrollment); err != nil {
+		return nil, err
+	}
+
+	return &domain.TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(uc.issuer, identity, secret),
+	}, nil
+}
+
+// Confirm completes enrollment with a code from the authenticator app and
+// returns single-use recovery codes. They are only ever shown here.
+func (uc *TwoFactorUseCase) Confirm(ctx context.Context, identity, code string) ([]string, error) {
+	enrollment, err := uc.repo.Get(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment.Confirmed() {
+		return nil, domain.ErrTwoFactorAlreadyEnrolled
+	}
+
+	valid, err := uc.useTOTP(ctx, identity, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		if codes[i], err = generateRecoveryCode(); err != nil {
+			return nil, err
+		}
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	confirmedAt := uc.clock.Now()
+	enrollment.RecoveryCodeHashes = hashes
+	enrollment.ConfirmedAt = &confirmedAt
+	if err := uc.repo.Save(ctx, enrollment); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "confirm_two_factor",
+		"identity": identity,
+	}).Info("Two-factor authentication enabled")
+
+	return codes, nil
+}
+
+// Verify checks the second factor for a login. code may be a TOTP code or
+// an unused recovery code. Identities without a confirmed enrollment pass
+// unless the policy for role requires two-factor authentication.
+func (uc *TwoFactorUseCase) Verify(ctx context.Context, identity, role, code string) error {
+	enrollment, err := uc.repo.Get(ctx, identity)
+	if err != nil && !errors.Is(err, domain.ErrTwoFactorNotEnrolled) {
+		return err
+	}
+	if enrollment == nil || !enrollment.Confirmed() {
+		if uc.policies.For(role) == domain.TwoFactorPolicyRequired {
+			return domain.ErrTwoFactorEnrollment
+		}
+		return nil
+	}
+
+	return uc.verifyCode(ctx, identity, code)
+}
+
+// Disable removes the enrollment. A confirmed enrollment can only be
+// removed with a valid code; a pending one is simply discarded.
+func (uc *TwoFactorUseCase) Disable(ctx context.Context, identity, code string) error {
+	enrollment, err := uc.repo.Get(ctx, identity)
+	if err != nil {
+		return err
+	}
+	if enrollment.Confirmed() {
+		if err := uc.verifyCode(ctx, identity, code); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.repo.Delete(ctx, identity); err != nil {
+		return err
+	}
+	if err := uc.secrets.Delete(ctx, enrollment.SecretKey()); err != nil {
+		logger.FromContext(ctx).WithError(err).Warn("Failed to delete two-factor secret")
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "disable_two_factor",
+		"identity": identity,
+	}).Info("Two-factor authentication disabled")
+
+	return nil
+}
+
+func (uc *TwoFactorUseCase) verifyCode(ctx context.Context, identity, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return domain.ErrTwoFactorRequired
+	}
+
+	valid, err := uc.useTOTP(ctx, identity, code)
+	if err != nil {
+		return err
+	}
+	if valid {
+		return nil
+	}
+
+	used, err := uc.repo.UseRecoveryCode(ctx, identity, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "verify_two_factor",
+		"identity": identity,
+	}).Warn("Recovery code used")
+
+	return nil
+}
+
+// useTOTP reports whether code is a valid TOTP code that has not been used
+// yet. Each accepted code moves the identity's last used step forward, and
+// codes from that step or earlier are refused.
+func (uc *TwoFactorUseCase) useTOTP(ctx context.Context, identity, code string) (bool, error) {
+	secret, err := uc.secrets.Get(ctx, domain.TwoFactorSecretKey(identity))
+	if err != nil {
+		return false, fmt.Errorf("failed to load two-factor secret: %w", err)
+	}
+	step, ok := totp.Match(string(secret), code, uc.clock.Now(), totpSkew)
+	if !ok {
+		return false, nil
+	}
+	return uc.repo.UseTOTPStep(ctx, identity, int64(step))
+}
+
+// generateRecoveryCode returns a code like "abcde-fghij".
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// hashRecoveryCode ignores case and separators so codes can be typed
+// loosely.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
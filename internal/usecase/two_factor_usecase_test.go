@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/totp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTwoFactorRepository struct {
+	mock.Mock
+}
+
+func (m *MockTwoFactorRepository) Get(ctx context.Context, identity string) (*domain.TwoFactorEnrollment, error) {
+	args := m.Called(ctx, identity)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.TwoFactorEnrollment), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) Save(ctx context.Context, enrollment *domain.TwoFactorEnrollment) error {
+	args := m.Called(ctx, enrollment)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) Delete(ctx context.Context, identity string) error {
+	args := m.Called(ctx, identity)
+	return args.Error(0)
+}
+
+func (m *MockTwoFactorRepository) UseRecoveryCode(ctx context.Context, identity, codeHash string) (bool, error) {
+	args := m.Called(ctx, identity, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTwoFactorRepository) UseTOTPStep(ctx context.Context, identity string, step int64) (bool, error) {
+	args := m.Called(ctx, identity, step)
+	return args.Bool(0), args.Error(1)
+}
+
+const testIdentity = "key:abc"
+
+var optionalPolicy = domain.TwoFactorPolicies{Default: domain.TwoFactorPolicyOptional}
+
+func TestTwoFactorUseCase_SetupAndConfirm(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockTwoFactorRepository)
+	mockSecrets := new(MockSecretStore)
+	uc := NewTwoFactorUseCase(mockRepo, mockSecrets, "product-service", optionalPolicy, clock.Real())
+
+	var secret []byte
+	mockRepo.On("Get", ctx, testIdentity).Return(nil, domain.ErrTwoFactorNotEnrolled).Once()
+	mockSecrets.On("Put", ctx, domain.TwoFactorSecretKey(testIdentity), mock.Anything).
+		Run(func(args mock.Arguments) { secret = args.Get(2).([]byte) }).Return(nil)
+	mockRepo.On("Save", ctx, mock.MatchedBy(func(e *domain.TwoFactorEnrollment) bool { return !e.Confirmed() })).Return(nil).Once()
+
+	setup, err := uc.Setup(ctx, testIdentity)
+	require.NoError(t, err)
+	assert.Equal(t, string(secret), setup.Secret)
+	assert.Contains(t, setup.ProvisioningURI, "otpauth://totp/")
+
+	pending := &domain.TwoFactorEnrollment{Identity: testIdentity}
+	mockRepo.On("Get", ctx, testIdentity).Return(pending, nil)
+	mockSecrets.On("Get", ctx, domain.TwoFactorSecretKey(testIdentity)).Return(secret, nil)
+
+	code, err := totp.Code(setup.Secret, time.Now())
+	require.NoError(t, err)
+
+	_, err = uc.Confirm(ctx, testIdentity, "12345")
+	assert.ErrorIs(t, err, domain.ErrInvalidTwoFactorCode)
+
+	mockRepo.On("UseTOTPStep", ctx, testIdentity, mock.Anything).Return(true, nil).Once()
+	mockRepo.On("Save", ctx, mock.MatchedBy(func(e *domain.TwoFactorEnrollment) bool {
+		return e.Confirmed() && len(e.RecoveryCodeHashes) == recoveryCodeCount
+	})).Return(nil).Once()
+
+	codes, err := uc.Confirm(ctx, testIdentity, code)
+	require.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCount)
+	assert.Equal(t, hashRecoveryCode(codes[0]), pending.RecoveryCodeHashes[0])
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTwoFactorUseCase_Setup_AlreadyEnrolled(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockTwoFactorRepository)
+	uc := NewTwoFactorUseCase(mockRepo, new(MockSecretStore), "product-service", optionalPolicy, clock.Real())
+
+	confirmedAt := time.Now()
+	mockRepo.On("Get", ctx, testIdentity).Return(&domain.TwoFactorEnrollment{Identity: testIdentity, ConfirmedAt: &confirmedAt}, nil)
+
+	_, err := uc.Setup(ctx, testIdentity)
+	assert.ErrorIs(t, err, domain.ErrTwoFactorAlreadyEnrolled)
+}
+
+func TestTwoFactorUseCase_Verify(t *testing.T) {
+	ctx := context.Background()
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	validCode, err := totp.Code(secret, time.Now())
+	require.NoError(t, err)
+	confirmedAt := time.Now()
+	confirmed := &domain.TwoFactorEnrollment{Identity: testIdentity, ConfirmedAt: &confirmedAt}
+
+	tests := []struct {
+		name    string
+		role    string
+		code    string
+		mockFn  func(*MockTwoFactorRepository, *MockSecretStore)
+		wantErr error
+	}{
+		{
+			name: "not enrolled with optional policy",
+			role: domain.TwoFactorRoleAPIKey,
+			mockFn: func(repo *MockTwoFactorRepository, _ *MockSecretStore) {
+				repo.On("Get", ctx, testIdentity).Return(nil, domain.ErrTwoFactorNotEnrolled)
+			},
+		},
+		{
+			name: "not enrolled with required policy",
+			role: domain.TwoFactorRoleBulk,
+			mockFn: func(repo *MockTwoFactorRepository, _ *MockSecretStore) {
+				repo.On("Get", ctx, testIdentity).Return(nil, domain.ErrTwoFactorNotEnrolled)
+			},
+			wantErr: domain.ErrTwoFactorEnrollment,
+		},
+		{
+			name: "pending enrollment with required policy",
+			role: domain.TwoFactorRoleBulk,
+			mockFn: func(repo *MockTwoFactorRepository, _ *MockSecretStore) {
+				repo.On("Get", ctx, testIdentity).Return(&domain.TwoFactorEnrollment{Identity: testIdentity}, nil)
+			},
+			wantErr: domain.ErrTwoFactorEnrollment,
+		},
+		{
+			name: "enrolled without code",
+			role: domain.TwoFactorRoleAPIKey,
+			mockFn: func(repo *MockTwoFactorRepository, _ *MockSecretStore) {
+				repo.On("Get", ctx, testIdentity).Return(confirmed, nil)
+			},
+			wantErr: domain.ErrTwoFactorRequired,
+		},
+		{
+			name: "enrolled with valid totp code",
+			role: domain.TwoFactorRoleAPIKey,
+			code: validCode,
+			mockFn: func(repo *MockTwoFactorRepository, secrets *MockSecretStore) {
+				repo.On("Get", ctx, testIdentity).Return(confirmed, nil)
+				secrets.On("Get", ctx, domain.TwoFactorSecretKey(testIdentity)).Return([]byte(secret), nil)
+				repo.On("UseTOTPStep", ctx, testIdentity, mock.Anything).Return(true, nil)
+			},
+		},
+		{
+			name: "enrolled with already used totp code",
+			role: domain.TwoFactorRoleAPIKey,
+			code: validCode,
+			mockFn: func(repo *MockTwoFactorRepository, secrets *MockSecretStore) {
+				repo.On("Get", ctx, testIdentity).Return(confirmed, nil)
+				secrets.On("Get", ctx, domain.TwoFactorSecretKey(testIdentity)).Return([]byte(secret), nil)
+				repo.On("UseTOTPStep", ctx, testIdentity, mock.Anything).Return(false, nil)
+				repo.On("UseRecoveryCode", ctx, testIdentity, hashRecoveryCode(validCode)).Return(false, nil)
+			},
+			wantErr: domain.ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "enrolled with unused recovery code",
+			role: domain.TwoFactorRoleAPIKey,
+			code: "ABCDE-FGHIJ",
+			mockFn: func(repo *MockTwoFactorRepository, secrets *MockSecretStore) {
+				repo.On("Get", ctx, testIdentity).Return(confirmed, nil)
+				secrets.On("Get", ctx, domain.TwoFactorSecretKey(testIdentity)).Return([]byte(secret), nil)
+				repo.On("UseRecoveryCode", ctx, testIdentity, hashRecoveryCode("abcdefghij")).Return(true, nil)
+			},
+		},
+		{
+			name: "enrolled with invalid code",
+			role: domain.TwoFactorRoleAPIKey,
+			code: "abcde-fghij",
+			mockFn: func(repo *MockTwoFactorRepository, secrets *MockSecretStore) {
+				repo.On("Get", ctx, testIdentity).Return(confirmed, nil)
+				secrets.On("Get", ctx, domain.TwoFactorSecretKey(testIdentity)).Return([]byte(secret), nil)
+				repo.On("UseRecoveryCode", ctx, testIdentity, mock.Anything).Return(false, nil)
+			},
+			wantErr: domain.ErrInvalidTwoFactorCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTwoFactorRepository)
+			mockSecrets := new(MockSecretStore)
+			tt.mockFn(mockRepo, mockSecrets)
+			policies := domain.TwoFactorPolicies{
+				Default: domain.TwoFactorPolicyOptional,
+				Roles:   map[string]string{domain.TwoFactorRoleBulk: domain.TwoFactorPolicyRequired},
+			}
+			uc := NewTwoFactorUseCase(mockRepo, mockSecrets, "product-service", policies, clock.Real())
+
+			err := uc.Verify(ctx, testIdentity, tt.role, tt.code)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+			mockSecrets.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTwoFactorUseCase_Disable(t *testing.T) {
+	ctx := context.Background()
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	code, err := totp.Code(secret, time.Now())
+	require.NoError(t, err)
+	confirmedAt := time.Now()
+
+	mockRepo := new(MockTwoFactorRepository)
+	mockSecrets := new(MockSecretStore)
+	mockRepo.On("Get", ctx, testIdentity).Return(&domain.TwoFactorEnrollment{Identity: testIdentity, ConfirmedAt: &confirmedAt}, nil)
+	mockSecrets.On("Get", ctx, domain.TwoFactorSecretKey(testIdentity)).Return([]byte(secret), nil)
+	mockRepo.On("UseTOTPStep", ctx, testIdentity, mock.Anything).Return(true, nil)
+	mockRepo.On("Delete", ctx, testIdentity).Return(nil)
+	mockSecrets.On("Delete", ctx, domain.TwoFactorSecretKey(testIdentity)).Return(nil)
+	uc := NewTwoFactorUseCase(mockRepo, mockSecrets, "product-service", optionalPolicy, clock.Real())
+
+	assert.ErrorIs(t, uc.Disable(ctx, testIdentity, ""), domain.ErrTwoFactorRequired)
+	mockRepo.AssertNotCalled(t, "Delete", ctx, testIdentity)
+
+	require.NoError(t, uc.Disable(ctx, testIdentity, code))
+	mockRepo.AssertExpectations(t)
+	mockSecrets.AssertExpectations(t)
+}
+
+func TestTwoFactorUseCase_Verify_CodeExpires(t *testing.T) {
+	ctx := context.Background()
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	issuedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	code, err := totp.Code(secret, issuedAt)
+	require.NoError(t, err)
+
+	confirmedAt := issuedAt.Add(-time.Hour)
+	mockRepo := new(MockTwoFactorRepository)
+	mockRepo.On("Get", ctx, testIdentity).Return(&domain.TwoFactorEnrollment{Identity: testIdentity, ConfirmedAt: &confirmedAt}, nil)
+	mockSecrets := new(MockSecretStore)
+	mockSecrets.On("Get", ctx, domain.TwoFactorSecretKey(testIdentity)).Return([]byte(secret), nil)
+
+	fake := clock.NewFake(issuedAt)
+	uc := NewTwoFactorUseCase(mockRepo, mockSecrets, "product-service", optionalPolicy, fake)
+
+	// The skew window accepts the code one step after it was issued.
+	fake.Advance(30 * time.Second)
+	mockRepo.On("UseTOTPStep", ctx, testIdentity, int64(totp.Step(issuedAt))).Return(true, nil)
+	assert.NoError(t, uc.Verify(ctx, testIdentity, domain.TwoFactorRoleAPIKey, code))
+
+	fake.Advance(2 * time.Minute)
+	mockRepo.On("UseRecoveryCode", ctx, testIdentity, hashRecoveryCode(code)).Return(false, nil)
+	assert.ErrorIs(t, uc.Verify(ctx, testIdentity, domain.TwoFactorRoleAPIKey, code), domain.ErrInvalidTwoFactorCode)
+}
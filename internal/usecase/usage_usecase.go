@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultUsagePeriod is the period reconciled when from is left out.
+const defaultUsagePeriod = 24 * time.Hour
+
+// UsageUseCase reconciles the usage emitted to billing with the usage the
+// instances recorded, so operators can find hours billed wrongly or not
+// at all.
+type UsageUseCase struct {
+	repo  UsageRepository
+	grace time.Duration
+	clock clock.Clock
+}
+
+// NewUsageUseCase takes the meter's grace period, within which an ended
+// hour is not yet expected to be closed.
+func NewUsageUseCase(repo UsageRepository, grace time.Duration, clk clock.Clock) *UsageUseCase {
+	return &UsageUseCase{
+		repo:  repo,
+		grace: grace,
+		clock: clk,
+	}
+}
+
+// Reconcile compares the hours starting from from up to to. A zero to
+// means now and a zero from the day before to; from is rounded down to
+// the hour.
+func (uc *UsageUseCase) Reconcile(ctx context.Context, from, to time.Time) (*domain.UsageReconciliation, error) {
+	now := uc.clock.Now().UTC()
+	if to.IsZero() {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultUsagePeriod)
+	}
+	from, to = from.UTC().Truncate(time.Hour), to.UTC()
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", domain.ErrInvalidUsagePeriod)
+	}
+	if to.Sub(from) > domain.MaxUsageReconciliationPeriod {
+		return nil, fmt.Errorf("%w: the period must not exceed %d days", domain.ErrInvalidUsagePeriod, domain.MaxUsageReconciliationPeriod/(24*time.Hour))
+	}
+
+	comparisons, err := uc.repo.GetUsage(ctx, from, to)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get usage from repository")
+		return nil, err
+	}
+
+	reconciliation := &domain.UsageReconciliation{From: from, To: to, Discrepancies: []domain.UsageDiscrepancy{}}
+	for _, comparison := range comparisons {
+		reconciliation.RecordedCalls += comparison.RecordedCalls
+
+		discrepancy := domain.UsageDiscrepancy{
+			StoreID:       comparison.StoreID,
+			Hour:          comparison.Hour,
+			RecordedCalls: comparison.RecordedCalls,
+		}
+		record := comparison.Record
+		switch {
+		case record == nil:
+			if comparison.Hour.Add(time.Hour + uc.grace).After(now) {
+				continue
+			}
+			discrepancy.Kind = domain.UsageNotClosed
+		case record.EmittedAt == nil:
+			reconciliation.Records++
+			discrepancy.Kind = domain.UsageNotEmitted
+		default:
+			reconciliation.Records++
+			reconciliation.Emitted++
+			reconciliation.EmittedCalls += record.APICalls
+			discrepancy.EmittedCalls = record.APICalls
+			if record.APICalls == comparison.RecordedCalls {
+				continue
+			}
+			discrepancy.Kind = domain.UsageCallsMismatch
+		}
+		reconciliation.Discrepancies = append(reconciliation.Discrepancies, discrepancy)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":        "usage_reconciliation",
+		"from":          from,
+		"to":            to,
+		"records":       reconciliation.Records,
+		"discrepancies": len(reconciliation.Discrepancies),
+	}).Info("Usage reconciled")
+
+	return reconciliation, nil
+}
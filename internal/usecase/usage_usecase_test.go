@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockUsageRepository struct {
+	mock.Mock
+}
+
+func (m *MockUsageRepository) GetUsage(ctx context.Context, from, to time.Time) ([]*domain.UsageComparison, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.UsageComparison), args.Error(1)
+}
+
+func TestUsageUseCase_Reconcile(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 3, 0, 0, time.UTC)
+	from := now.Add(-24 * time.Hour).Truncate(time.Hour)
+	hour := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	emitted := hour.Add(70 * time.Minute)
+
+	repo := new(MockUsageRepository)
+	repo.On("GetUsage", mock.Anything, from, now).Return([]*domain.UsageComparison{
+		{StoreID: 1, Hour: hour, RecordedCalls: 10, Record: &domain.UsageRecord{APICalls: 10, EmittedAt: &emitted}},
+		{StoreID: 2, Hour: hour, RecordedCalls: 12, Record: &domain.UsageRecord{APICalls: 9, EmittedAt: &emitted}},
+		{StoreID: 3, Hour: hour, RecordedCalls: 4, Record: &domain.UsageRecord{APICalls: 4}},
+		{StoreID: 4, Hour: hour, RecordedCalls: 6},
+		{StoreID: 5, Hour: hour, Record: &domain.UsageRecord{EmittedAt: &emitted}},
+		// Still within the grace period, so not expected to be closed.
+		{StoreID: 1, Hour: hour.Add(2 * time.Hour), RecordedCalls: 3},
+	}, nil)
+	uc := NewUsageUseCase(repo, 5*time.Minute, clock.NewFake(now))
+
+	reconciliation, err := uc.Reconcile(context.Background(), time.Time{}, time.Time{})
+	require.NoError(t, err)
+
+	assert.Equal(t, from, reconciliation.From)
+	assert.Equal(t, now, reconciliation.To)
+	assert.Equal(t, 4, reconciliation.Records)
+	assert.Equal(t, 3, reconciliation.Emitted)
+	assert.Equal(t, int64(35), reconciliation.RecordedCalls)
+	assert.Equal(t, int64(19), reconciliation.EmittedCalls)
+	assert.Equal(t, []domain.UsageDiscrepancy{
+		{StoreID: 2, Hour: hour, Kind: domain.UsageCallsMismatch, RecordedCalls: 12, EmittedCalls: 9},
+		{StoreID: 3, Hour: hour, Kind: domain.UsageNotEmitted, RecordedCalls: 4},
+		{StoreID: 4, Hour: hour, Kind: domain.UsageNotClosed, RecordedCalls: 6},
+	}, reconciliation.Discrepancies)
+	repo.AssertExpectations(t)
+}
+
+func TestUsageUseCase_Reconcile_InvalidPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	uc := NewUsageUseCase(new(MockUsageRepository), 5*time.Minute, clock.NewFake(now))
+
+	_, err := uc.Reconcile(context.Background(), now, now.Add(-time.Hour))
+	assert.ErrorIs(t, err, domain.ErrInvalidUsagePeriod)
+
+	_, err = uc.Reconcile(context.Background(), now.Add(-40*24*time.Hour), now)
+	assert.ErrorIs(t, err, domain.ErrInvalidUsagePeriod)
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"backend-context-engineering-template/pkg/secrets"
+	"github.com/sirupsen/logrus"
+)
+
+const webhookSecretBytes = 32
+
+// WebhookSecretUseCase rotates the secrets webhook deliveries are signed
+// with. Each store has its own, so one tenant's secret cannot verify, or
+// forge, deliveries meant for another; store 0 holds the secret for
+// subscriptions that span stores.
+type WebhookSecretUseCase struct {
+	secrets SecretStore
+	grace   time.Duration
+	clock   clock.Clock
+}
+
+// NewWebhookSecretUseCase keeps a rotated-out secret signing for grace, so
+// subscribers can deploy the new secret before the old one stops working.
+func NewWebhookSecretUseCase(secrets SecretStore, grace time.Duration, clk clock.Clock) *WebhookSecretUseCase {
+	return &WebhookSecretUseCase{
+		secrets: secrets,
+		grace:   grace,
+		clock:   clk,
+	}
+}
+
+// RotateSecret issues a new signing secret for the store. The first
+// rotation turns signing on for the store's subscriptions.
+func (uc *WebhookSecretUseCase) RotateSecret(ctx context.Context, storeID int64) (*domain.WebhookSigningSecret, error) {
+	if storeID < 0 {
+		return nil, fmt.Errorf("%w: invalid store ID", domain.ErrInvalidWebhook)
+	}
+
+	existing, err := uc.load(ctx, storeID)
+	if err != nil && !errors.Is(err, domain.ErrWebhookSecretNotFound) {
+		return nil, err
+	}
+
+	raw := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	now := uc.clock.Now()
+	secret := &domain.WebhookSigningSecret{
+		StoreID:   storeID,
+		Current:   "whsec_" + base64.RawURLEncoding.EncodeToString(raw),
+		RotatedAt: now,
+	}
+	if existing != nil && uc.grace > 0 {
+		secret.Previous = existing.Current
+		secret.PreviousExpiresAt = now.Add(uc.grace)
+	}
+	if err := uc.save(ctx, secret); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "rotate_webhook_secret",
+		"store_id": storeID,
+	}).Info("Webhook signing secret rotated")
+
+	return secret, nil
+}
+
+// RevokePreviousSecret stops signing with the rotated-out secret before its
+// grace period ends, for when it may have leaked.
+func (uc *WebhookSecretUseCase) RevokePreviousSecret(ctx context.Context, storeID int64) error {
+	if storeID < 0 {
+		return fmt.Errorf("%w: invalid store ID", domain.ErrInvalidWebhook)
+	}
+
+	secret, err := uc.load(ctx, storeID)
+	if err != nil {
+		return err
+	}
+	if secret.Previous == "" {
+		return nil
+	}
+
+	secret.Previous = ""
+	secret.PreviousExpiresAt = time.Time{}
+	if err := uc.save(ctx, secret); err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":   "revoke_webhook_secret",
+		"store_id": storeID,
+	}).Info("Previous webhook signing secret revoked")
+
+	return nil
+}
+
+func (uc *WebhookSecretUseCase) load(ctx context.Context, storeID int64) (*domain.WebhookSigningSecret, error) {
+	data, err := uc.secrets.Get(ctx, domain.WebhookSecretKey(storeID))
+	if errors.Is(err, secrets.ErrNotFound) {
+		return nil, domain.ErrWebhookSecretNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook secret: %w", err)
+	}
+
+	var secret domain.WebhookSigningSecret
+	if err := json.Unmarshal(data, &secret); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook secret: %w", err)
+	}
+	return &secret, nil
+}
+
+func (uc *WebhookSecretUseCase) save(ctx context.Context, secret *domain.WebhookSigningSecret) error {
+	data, err := json.Marshal(secret)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook secret: %w", err)
+	}
+	if err := uc.secrets.Put(ctx, domain.WebhookSecretKey(secret.StoreID), data); err != nil {
+		return fmt.Errorf("failed to store webhook secret: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/secrets"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySecretStore keeps secrets in a map, like secrets.PostgresStore
+// without the encryption.
+type memorySecretStore map[string][]byte
+
+func (s memorySecretStore) Put(ctx context.Context, key string, value []byte) error {
+	s[key] = value
+	return nil
+}
+
+func (s memorySecretStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, ok := s[key]
+	if !ok {
+		return nil, secrets.ErrNotFound
+	}
+	return value, nil
+}
+
+func (s memorySecretStore) Delete(ctx context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func (s memorySecretStore) signingSecret(t *testing.T, storeID int64) domain.WebhookSigningSecret {
+	var secret domain.WebhookSigningSecret
+	require.NoError(t, json.Unmarshal(s[domain.WebhookSecretKey(storeID)], &secret))
+	return secret
+}
+
+func newTestWebhookSecretUseCase(grace time.Duration) (*WebhookSecretUseCase, memorySecretStore, *clock.Fake) {
+	store := memorySecretStore{}
+	fake := clock.NewFake(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	uc := NewWebhookSecretUseCase(store, grace, fake)
+	return uc, store, fake
+}
+
+func TestWebhookSecretUseCase_RotateSecret(t *testing.T) {
+	uc, store, fake := newTestWebhookSecretUseCase(24 * time.Hour)
+
+	first, err := uc.RotateSecret(context.Background(), 7)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first.Current, "whsec_"))
+	assert.Empty(t, first.Previous, "nothing to keep on the first rotation")
+
+	fake.Advance(time.Hour)
+	second, err := uc.RotateSecret(context.Background(), 7)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Current, second.Current)
+	assert.Equal(t, first.Current, second.Previous)
+	assert.Equal(t, fake.Now().Add(24*time.Hour), second.PreviousExpiresAt)
+
+	stored := store.signingSecret(t, 7)
+	assert.Equal(t, []string{second.Current, first.Current}, stored.Active(fake.Now()))
+	assert.Equal(t, []string{second.Current}, stored.Active(fake.Now().Add(25*time.Hour)))
+
+	_, err = uc.RotateSecret(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Contains(t, store, "webhook_signing:global", "store 0 is the secret for unscoped subscriptions")
+
+	_, err = uc.RotateSecret(context.Background(), -1)
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhook)
+}
+
+func TestWebhookSecretUseCase_RotateSecretWithoutGrace(t *testing.T) {
+	uc, _, _ := newTestWebhookSecretUseCase(0)
+
+	_, err := uc.RotateSecret(context.Background(), 7)
+	require.NoError(t, err)
+	secret, err := uc.RotateSecret(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Empty(t, secret.Previous)
+}
+
+func TestWebhookSecretUseCase_RevokePreviousSecret(t *testing.T) {
+	uc, store, fake := newTestWebhookSecretUseCase(24 * time.Hour)
+
+	assert.ErrorIs(t, uc.RevokePreviousSecret(context.Background(), 7), domain.ErrWebhookSecretNotFound)
+
+	_, err := uc.RotateSecret(context.Background(), 7)
+	require.NoError(t, err)
+	current, err := uc.RotateSecret(context.Background(), 7)
+	require.NoError(t, err)
+
+	require.NoError(t, uc.RevokePreviousSecret(context.Background(), 7))
+	stored := store.signingSecret(t, 7)
+	assert.Equal(t, []string{current.Current}, stored.Active(fake.Now()))
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"github.com/sirupsen/logrus"
+)
+
+type WebhookUseCase struct {
+	webhookRepo WebhookRepository
+	monitor     WebhookMonitor
+	clock       clock.Clock
+}
+
+func NewWebhookUseCase(webhookRepo WebhookRepository, monitor WebhookMonitor, clk clock.Clock) *WebhookUseCase {
+	return &WebhookUseCase{
+		webhookRepo: webhookRepo,
+		monitor:     monitor,
+		clock:       clk,
+	}
+}
+
+func (uc *WebhookUseCase) CreateWebhook(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action": "create_webhook",
+		"url":    subscription.URL,
+	}).Info("Creating webhook subscription")
+
+	if err := subscription.Validate(); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Webhook subscription validation failed")
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidWebhook, err)
+	}
+
+	created, err := uc.webhookRepo.Create(ctx, subscription)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to create webhook subscription in repository")
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return created, nil
+}
+
+func (uc *WebhookUseCase) GetWebhook(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid webhook subscription ID", domain.ErrInvalidWebhook)
+	}
+
+	subscription, err := uc.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get webhook subscription from repository")
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// GetWebhooks lists the store's subscriptions, or every subscription when
+// storeID is 0.
+func (uc *WebhookUseCase) GetWebhooks(ctx context.Context, storeID int64, limit, offset int) ([]*domain.WebhookSubscription, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	subscriptions, err := uc.webhookRepo.GetAll(ctx, storeID, limit, offset)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to get webhook subscriptions from repository")
+		return nil, fmt.Errorf("failed to get webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// GetWebhookHealth reports how deliveries from this instance to the
+// subscription have fared. A subscription nothing was sent to yet has a
+// zero health report.
+func (uc *WebhookUseCase) GetWebhookHealth(ctx context.Context, id int64) (*domain.WebhookHealth, error) {
+	if _, err := uc.GetWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+
+	health, _ := uc.monitor.Health(id)
+	return &health, nil
+}
+
+// ResumeWebhook lifts a pause. Events published while the subscription was
+// paused are not replayed.
+func (uc *WebhookUseCase) ResumeWebhook(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("%w: invalid webhook subscription ID", domain.ErrInvalidWebhook)
+	}
+
+	subscription, err := uc.webhookRepo.Resume(ctx, id, uc.clock.Now())
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to resume webhook subscription in repository")
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"action":          "resume_webhook",
+		"subscription_id": id,
+	}).Info("Webhook subscription resumed")
+
+	return subscription, nil
+}
+
+func (uc *WebhookUseCase) DeleteWebhook(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return fmt.Errorf("%w: invalid webhook subscription ID", domain.ErrInvalidWebhook)
+	}
+
+	if err := uc.webhookRepo.Delete(ctx, id); err != nil {
+		logger.FromContext(ctx).WithError(err).Error("Failed to delete webhook subscription from repository")
+		return err
+	}
+
+	return nil
+}
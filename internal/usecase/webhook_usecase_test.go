@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, subscription *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	args := m.Called(ctx, subscription)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetByID(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetAll(ctx context.Context, storeID int64, limit, offset int) ([]*domain.WebhookSubscription, error) {
+	args := m.Called(ctx, storeID, limit, offset)
+	return args.Get(0).([]*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Resume(ctx context.Context, id int64, at time.Time) (*domain.WebhookSubscription, error) {
+	args := m.Called(ctx, id, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type fakeWebhookMonitor map[int64]domain.WebhookHealth
+
+func (m fakeWebhookMonitor) Health(id int64) (domain.WebhookHealth, bool) {
+	health, ok := m[id]
+	if !ok {
+		return domain.WebhookHealth{SubscriptionID: id}, false
+	}
+	return health, true
+}
+
+func TestWebhookUseCase_CreateWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		subscription *domain.WebhookSubscription
+		mockFn       func(*MockWebhookRepository)
+		wantErr      bool
+		errType      error
+	}{
+		{
+			name:         "successful creation",
+			subscription: &domain.WebhookSubscription{URL: "https://hooks.example.com/products"},
+			mockFn: func(m *MockWebhookRepository) {
+				m.On("Create", mock.Anything, mock.Anything).Return(&domain.WebhookSubscription{ID: 1}, nil)
+			},
+		},
+		{
+			name:         "invalid url",
+			subscription: &domain.WebhookSubscription{URL: "hooks.example.com"},
+			mockFn:       func(m *MockWebhookRepository) {},
+			wantErr:      true,
+			errType:      domain.ErrInvalidWebhook,
+		},
+		{
+			name: "scoped to a store and event types",
+			subscription: &domain.WebhookSubscription{
+				URL:        "https://hooks.example.com/products",
+				StoreID:    sql.NullInt64{Int64: 7, Valid: true},
+				EventTypes: []string{domain.ProductEventCreated, domain.StockEventChanged},
+			},
+			mockFn: func(m *MockWebhookRepository) {
+				m.On("Create", mock.Anything, mock.Anything).Return(&domain.WebhookSubscription{ID: 1}, nil)
+			},
+		},
+		{
+			name:         "unknown event type",
+			subscription: &domain.WebhookSubscription{URL: "https://hooks.example.com/products", EventTypes: []string{"order.created"}},
+			mockFn:       func(m *MockWebhookRepository) {},
+			wantErr:      true,
+			errType:      domain.ErrInvalidWebhook,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockWebhookRepository{}
+			tt.mockFn(repo)
+
+			uc := NewWebhookUseCase(repo, fakeWebhookMonitor{}, clock.Real())
+			_, err := uc.CreateWebhook(ctx, tt.subscription)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.errType)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookUseCase_GetWebhooks_ClampsLimit(t *testing.T) {
+	repo := &MockWebhookRepository{}
+	repo.On("GetAll", mock.Anything, int64(3), 100, 0).Return([]*domain.WebhookSubscription{}, nil)
+
+	uc := NewWebhookUseCase(repo, fakeWebhookMonitor{}, clock.Real())
+	_, err := uc.GetWebhooks(context.Background(), 3, 1000, -5)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookUseCase_DeleteWebhook(t *testing.T) {
+	repo := &MockWebhookRepository{}
+	repo.On("Delete", mock.Anything, int64(3)).Return(domain.ErrWebhookNotFound)
+
+	uc := NewWebhookUseCase(repo, fakeWebhookMonitor{}, clock.Real())
+
+	assert.ErrorIs(t, uc.DeleteWebhook(context.Background(), 3), domain.ErrWebhookNotFound)
+	assert.ErrorIs(t, uc.DeleteWebhook(context.Background(), 0), domain.ErrInvalidWebhook)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookUseCase_GetWebhookHealth(t *testing.T) {
+	repo := &MockWebhookRepository{}
+	repo.On("GetByID", mock.Anything, int64(1)).Return(&domain.WebhookSubscription{ID: 1}, nil)
+	repo.On("GetByID", mock.Anything, int64(2)).Return(&domain.WebhookSubscription{ID: 2}, nil)
+	repo.On("GetByID", mock.Anything, int64(3)).Return(nil, domain.ErrWebhookNotFound)
+
+	monitor := fakeWebhookMonitor{1: {SubscriptionID: 1, Deliveries: 10, SuccessRate: 0.9}}
+	uc := NewWebhookUseCase(repo, monitor, clock.Real())
+
+	health, err := uc.GetWebhookHealth(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0.9, health.SuccessRate)
+
+	health, err = uc.GetWebhookHealth(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, domain.WebhookHealth{SubscriptionID: 2}, *health)
+
+	_, err = uc.GetWebhookHealth(context.Background(), 3)
+	assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookUseCase_ResumeWebhook(t *testing.T) {
+	repo := &MockWebhookRepository{}
+	repo.On("Resume", mock.Anything, int64(1), mock.Anything).Return(&domain.WebhookSubscription{ID: 1}, nil)
+	repo.On("Resume", mock.Anything, int64(9), mock.Anything).Return(nil, domain.ErrWebhookNotFound)
+
+	uc := NewWebhookUseCase(repo, fakeWebhookMonitor{}, clock.Real())
+
+	subscription, err := uc.ResumeWebhook(context.Background(), 1)
+	require.NoError(t, err)
+	assert.False(t, subscription.PausedAt.Valid)
+
+	_, err = uc.ResumeWebhook(context.Background(), 9)
+	assert.ErrorIs(t, err, domain.ErrWebhookNotFound)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,394 @@
+// Package webhooks delivers product events to subscribed endpoints. Events
+// published close together are coalesced per subscriber, so a bulk import
+// reaches each endpoint as a few large deliveries instead of one request
+// per product. Each subscriber only receives the events within its scope,
+// signed with its store's secret.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/events"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Source lists the subscriptions events are delivered to and records
+// subscriptions paused for failing.
+type Source interface {
+	GetActive(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	Pause(ctx context.Context, id int64, reason string, at time.Time) error
+}
+
+type Config struct {
+	// Window is how long events are collected before they are delivered.
+	Window time.Duration
+	// MaxBatchSize caps the events in one delivery. Once that many are
+	// queued they are sent without waiting for the window. Zero uses
+	// DefaultMaxBatchSize.
+	MaxBatchSize int
+	// MaxPending caps the events held in memory; further events are
+	// dropped until the queue drains. Zero uses DefaultMaxPending.
+	MaxPending int
+	// MaxAttempts is how often a delivery is tried before it is dropped,
+	// waiting RetryBackoff, then twice that, between attempts.
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	// PauseAfter pauses a subscription once this many deliveries in a row
+	// have failed, so a dead endpoint stops tying up retries. Zero never
+	// pauses.
+	PauseAfter int
+	// Encoding frames deliveries. The zero value sends the native
+	// payload. CloudEvents binary mode carries one event per request, so
+	// it sends no batches.
+	Encoding events.Encoding
+}
+
+const (
+	DefaultMaxBatchSize = 100
+	DefaultMaxPending   = 10000
+)
+
+const notifyTimeout = 10 * time.Second
+
+// Dispatcher queues published events in memory and delivers them to every
+// active subscription each window. Delivery is at most once: events still
+// queued when the process dies are lost.
+type Dispatcher struct {
+	source    Source
+	client    *http.Client
+	secrets   SecretStore
+	notifiers []Notifier
+	cfg       Config
+	logger    *logrus.Logger
+	clock     clock.Clock
+	health    *healthTracker
+
+	mu       sync.Mutex
+	pending  []domain.ProductEvent
+	dropping bool
+	full     chan struct{}
+
+	events     *telemetry.Counter
+	deliveries *telemetry.Counter
+	batchSize  *telemetry.Histogram
+	duration   *telemetry.Histogram
+	paused     *telemetry.Counter
+}
+
+// NewDispatcher builds a dispatcher. secrets may be nil, in which case
+// deliveries are not signed.
+func NewDispatcher(source Source, client *http.Client, secrets SecretStore, notifiers []Notifier, registry *telemetry.Registry, cfg Config, clk clock.Clock, logger *logrus.Logger) *Dispatcher {
+	if cfg.MaxBatchSize <= 0 {
+		cfg.MaxBatchSize = DefaultMaxBatchSize
+	}
+	if cfg.MaxPending <= 0 {
+		cfg.MaxPending = DefaultMaxPending
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.Encoding.Format == "" {
+		cfg.Encoding.Format = events.FormatNative
+	}
+	return &Dispatcher{
+		source:     source,
+		client:     client,
+		secrets:    secrets,
+		notifiers:  notifiers,
+		cfg:        cfg,
+		logger:     logger,
+		clock:      clk,
+		health:     newHealthTracker(),
+		full:       make(chan struct{}, 1),
+		events:     registry.NewCounter("webhook_events_total", "Product events offered to the webhook dispatcher by result.", "result"),
+		deliveries: registry.NewCounter("webhook_deliveries_total", "Webhook deliveries by result.", "result"),
+		batchSize:  registry.NewHistogram("webhook_delivery_events", "Events per webhook delivery.", []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000}),
+		duration:   registry.NewHistogram("webhook_delivery_duration_seconds", "Time for a subscriber to answer one delivery attempt.", telemetry.DefaultDurationBuckets),
+		paused:     registry.NewCounter("webhook_subscriptions_paused_total", "Subscriptions paused after repeated delivery failures."),
+	}
+}
+
+// Health reports how deliveries from this instance to a subscription have
+// fared. ok is false if none have been attempted.
+func (d *Dispatcher) Health(id int64) (domain.WebhookHealth, bool) {
+	return d.health.get(id)
+}
+
+// Publish queues an event for the next delivery. It never blocks; when the
+// queue is full the event is dropped.
+func (d *Dispatcher) Publish(ctx context.Context, event domain.ProductEvent) {
+	d.mu.Lock()
+	if len(d.pending) >= d.cfg.MaxPending {
+		warn := !d.dropping
+		d.dropping = true
+		d.mu.Unlock()
+
+		d.events.Inc("dropped")
+		if warn {
+			d.logger.WithField("max_pending", d.cfg.MaxPending).Warn("Webhook queue is full, dropping events")
+		}
+		return
+	}
+	d.pending = append(d.pending, event)
+	full := len(d.pending) >= d.cfg.MaxBatchSize
+	d.mu.Unlock()
+
+	d.events.Inc("queued")
+	if full {
+		select {
+		case d.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run delivers queued events every window, or as soon as a full batch is
+// queued, until ctx is cancelled. Events still queued then are delivered
+// before Run returns.
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.logger.WithFields(logrus.Fields{"window": d.cfg.Window, "max_batch_size": d.cfg.MaxBatchSize}).Info("Webhook dispatcher started")
+
+	ticker := d.clock.NewTicker(d.cfg.Window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			d.Flush(context.WithoutCancel(ctx))
+			d.logger.Info("Webhook dispatcher stopped")
+			return
+		case <-ticker.C():
+		case <-d.full:
+		}
+		d.Flush(ctx)
+	}
+}
+
+// Flush delivers the queued events to every active subscription whose
+// scope they fall in, in batches of at most MaxBatchSize, and returns once
+// all deliveries have finished or been given up on.
+func (d *Dispatcher) Flush(ctx context.Context) {
+	d.mu.Lock()
+	queued := d.pending
+	d.pending = nil
+	d.dropping = false
+	d.mu.Unlock()
+
+	if len(queued) == 0 {
+		return
+	}
+
+	subscriptions, err := d.source.GetActive(ctx)
+	if err != nil {
+		d.logger.WithError(err).WithField("events", len(queued)).Error("Failed to load webhook subscriptions, requeueing events")
+		d.requeue(queued)
+		return
+	}
+
+	batchSize := d.cfg.MaxBatchSize
+	if d.cfg.Encoding.Format == events.FormatCloudEvents && d.cfg.Encoding.Mode == events.ModeBinary {
+		batchSize = 1
+	}
+
+	var wg sync.WaitGroup
+	for _, subscription := range subscriptions {
+		wg.Add(1)
+		go func(subscription *domain.WebhookSubscription) {
+			defer wg.Done()
+			d.health.observe(subscription)
+
+			var matching []domain.ProductEvent
+			for _, event := range queued {
+				if subscription.Matches(event) {
+					matching = append(matching, event)
+				}
+			}
+			if len(matching) == 0 {
+				return
+			}
+
+			// Sending unsigned when the secret cannot be read would let
+			// subscribers that verify signatures drop the events anyway.
+			keys, err := d.signingSecrets(ctx, subscription)
+			if err != nil {
+				d.deliveries.Inc("failed")
+				d.logger.WithError(err).WithFields(logrus.Fields{"subscription_id": subscription.ID, "events": len(matching)}).Error("Failed to load webhook signing secret, dropping events")
+				return
+			}
+
+			for start := 0; start < len(matching); start += batchSize {
+				end := min(start+batchSize, len(matching))
+				if paused := d.deliver(ctx, subscription, matching[start:end], keys); paused {
+					return
+				}
+			}
+		}(subscription)
+	}
+	wg.Wait()
+}
+
+// requeue puts events back in front of those published since, dropping
+// the newest ones if that overflows the queue.
+func (d *Dispatcher) requeue(events []domain.ProductEvent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.pending = append(events, d.pending...)
+	if overflow := len(d.pending) - d.cfg.MaxPending; overflow > 0 {
+		d.pending = d.pending[:d.cfg.MaxPending]
+		d.events.Add(float64(overflow), "dropped")
+	}
+}
+
+// deliver sends one batch, signed with keys, retrying failed attempts, and
+// reports whether the subscription was paused because of the failure.
+func (d *Dispatcher) deliver(ctx context.Context, subscription *domain.WebhookSubscription, batch []domain.ProductEvent, keys []string) bool {
+	logger := d.logger.WithFields(logrus.Fields{"subscription_id": subscription.ID, "events": len(batch)})
+
+	body, header, err := d.encode(batch)
+	if err != nil {
+		d.deliveries.Inc("failed")
+		logger.WithError(err).Error("Failed to encode webhook payload")
+		return false
+	}
+	d.batchSize.Observe(float64(len(batch)))
+
+	backoff := d.cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		start := d.clock.Now()
+		if len(keys) > 0 {
+			header.Set(SignatureHeader, Sign(keys, start, body))
+		}
+		err := d.post(ctx, subscription.URL, header, body)
+		latency := d.clock.Now().Sub(start)
+		d.duration.Observe(latency.Seconds())
+
+		if err == nil {
+			d.deliveries.Inc("delivered")
+			d.health.record(subscription.ID, nil, latency, d.clock.Now())
+			return false
+		}
+		if attempt >= d.cfg.MaxAttempts || ctx.Err() != nil || !d.sleep(ctx, backoff) {
+			d.deliveries.Inc("failed")
+			logger.WithError(err).WithField("attempts", attempt).Warn("Webhook delivery failed, dropping events")
+			// Deliveries cut short by shutdown say nothing about the
+			// subscriber.
+			if ctx.Err() != nil {
+				return false
+			}
+			return d.pauseIfFailing(ctx, subscription, d.health.record(subscription.ID, err, latency, d.clock.Now()))
+		}
+		backoff *= 2
+	}
+}
+
+func (d *Dispatcher) pauseIfFailing(ctx context.Context, subscription *domain.WebhookSubscription, health domain.WebhookHealth) bool {
+	if d.cfg.PauseAfter <= 0 || health.ConsecutiveFailures < d.cfg.PauseAfter {
+		return false
+	}
+
+	pause := domain.WebhookPause{
+		SubscriptionID:      subscription.ID,
+		URL:                 subscription.URL,
+		Reason:              fmt.Sprintf("%d consecutive deliveries failed", health.ConsecutiveFailures),
+		ConsecutiveFailures: health.ConsecutiveFailures,
+		SuccessRate:         health.SuccessRate,
+		LastError:           health.LastError,
+		PausedAt:            d.clock.Now(),
+	}
+	logger := d.logger.WithFields(logrus.Fields{"subscription_id": subscription.ID, "reason": pause.Reason})
+	if err := d.source.Pause(ctx, subscription.ID, pause.Reason, pause.PausedAt); err != nil {
+		logger.WithError(err).Error("Failed to pause failing webhook subscription")
+		return false
+	}
+	d.paused.Inc()
+	logger.Warn("Webhook subscription paused after repeated failures")
+
+	for _, notifier := range d.notifiers {
+		notifyCtx, cancel := context.WithTimeout(ctx, notifyTimeout)
+		if err := notifier.Notify(notifyCtx, pause); err != nil {
+			logger.WithError(err).Warn("Failed to send webhook pause notification")
+		}
+		cancel()
+	}
+	return true
+}
+
+// encode frames a batch as configured. In CloudEvents structured mode a
+// single event is sent as is and several as a CloudEvents batch; binary
+// mode is only given single events.
+func (d *Dispatcher) encode(batch []domain.ProductEvent) ([]byte, http.Header, error) {
+	header := make(http.Header)
+	if d.cfg.Encoding.Format != events.FormatCloudEvents {
+		header.Set("Content-Type", "application/json")
+		body, err := json.Marshal(domain.WebhookPayload{Bulk: len(batch) > 1, Events: batch})
+		return body, header, err
+	}
+
+	cloudEvents := make([]events.CloudEvent, len(batch))
+	for i, event := range batch {
+		cloudEvents[i] = events.NewCloudEvent(event, d.cfg.Encoding.Source)
+	}
+
+	var body []byte
+	var err error
+	switch {
+	case d.cfg.Encoding.Mode == events.ModeBinary:
+		for name, value := range cloudEvents[0].Attributes() {
+			header.Set("ce-"+name, value)
+		}
+		header.Set("Content-Type", cloudEvents[0].DataContentType)
+		body, err = json.Marshal(cloudEvents[0].Data)
+	case len(cloudEvents) == 1:
+		header.Set("Content-Type", "application/cloudevents+json")
+		body, err = json.Marshal(cloudEvents[0])
+	default:
+		header.Set("Content-Type", "application/cloudevents-batch+json")
+		body, err = json.Marshal(cloudEvents)
+	}
+	return body, header, err
+}
+
+func (d *Dispatcher) post(ctx context.Context, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = header.Clone()
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *Dispatcher) sleep(ctx context.Context, duration time.Duration) bool {
+	if duration <= 0 {
+		return true
+	}
+	ticker := d.clock.NewTicker(duration)
+	defer ticker.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-ticker.C():
+		return true
+	}
+}
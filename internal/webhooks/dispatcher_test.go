@@ -0,0 +1,511 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/internal/events"
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/secrets"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// subscriber records the payloads it receives and fails the first
+// failures requests.
+type subscriber struct {
+	mu       sync.Mutex
+	payloads []domain.WebhookPayload
+	failures int
+}
+
+func (s *subscriber) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var payload domain.WebhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.payloads = append(s.payloads, payload)
+}
+
+func (s *subscriber) received() []domain.WebhookPayload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]domain.WebhookPayload(nil), s.payloads...)
+}
+
+type fakeSource struct {
+	mu            sync.Mutex
+	subscriptions []*domain.WebhookSubscription
+	err           error
+}
+
+func (s *fakeSource) GetActive(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var active []*domain.WebhookSubscription
+	for _, subscription := range s.subscriptions {
+		if !subscription.PausedAt.Valid {
+			active = append(active, subscription)
+		}
+	}
+	return active, s.err
+}
+
+func (s *fakeSource) Pause(ctx context.Context, id int64, reason string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, subscription := range s.subscriptions {
+		if subscription.ID == id {
+			subscription.PausedAt = sql.NullTime{Time: at, Valid: true}
+			subscription.PauseReason = reason
+		}
+	}
+	return nil
+}
+
+// resume clears a pause the way WebhookRepository.Resume does.
+func (s *fakeSource) resume(id int64, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, subscription := range s.subscriptions {
+		if subscription.ID == id {
+			subscription.PausedAt = sql.NullTime{}
+			subscription.UpdatedAt = at
+		}
+	}
+}
+
+type recordingNotifier struct {
+	mu     sync.Mutex
+	pauses []domain.WebhookPause
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, pause domain.WebhookPause) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.pauses = append(n.pauses, pause)
+	return nil
+}
+
+func newSubscriber(t *testing.T, source *fakeSource, failures int) *subscriber {
+	sub := &subscriber{failures: failures}
+	server := httptest.NewServer(sub)
+	t.Cleanup(server.Close)
+
+	source.subscriptions = append(source.subscriptions, &domain.WebhookSubscription{ID: int64(len(source.subscriptions) + 1), URL: server.URL})
+	return sub
+}
+
+func newTestDispatcher(source Source, cfg Config, clk clock.Clock, notifiers ...Notifier) (*Dispatcher, *telemetry.Registry) {
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	return NewDispatcher(source, &http.Client{Timeout: 5 * time.Second}, nil, notifiers, registry, cfg, clk, logrus.New()), registry
+}
+
+func publish(d *Dispatcher, n int) {
+	for i := 1; i <= n; i++ {
+		d.Publish(context.Background(), domain.ProductEvent{
+			ID:        strconv.Itoa(i),
+			Type:      domain.ProductEventUpdated,
+			StoreID:   1,
+			ProductID: int64(i),
+		})
+	}
+}
+
+func batchSizes(payloads []domain.WebhookPayload) []int {
+	sizes := make([]int, len(payloads))
+	for i, p := range payloads {
+		sizes[i] = len(p.Events)
+	}
+	return sizes
+}
+
+func TestDispatcher_Flush_CoalescesPerSubscriber(t *testing.T) {
+	source := &fakeSource{}
+	first := newSubscriber(t, source, 0)
+	second := newSubscriber(t, source, 0)
+	d, _ := newTestDispatcher(source, Config{Window: time.Second, MaxBatchSize: 100}, clock.Real())
+
+	publish(d, 250)
+	d.Flush(context.Background())
+
+	for _, sub := range []*subscriber{first, second} {
+		payloads := sub.received()
+		assert.Equal(t, []int{100, 100, 50}, batchSizes(payloads))
+		for _, p := range payloads {
+			assert.True(t, p.Bulk)
+		}
+		assert.Equal(t, "1", payloads[0].Events[0].ID)
+		assert.Equal(t, "250", payloads[2].Events[49].ID)
+	}
+}
+
+func TestDispatcher_Flush_SingleEventIsNotBulk(t *testing.T) {
+	source := &fakeSource{}
+	sub := newSubscriber(t, source, 0)
+	d, _ := newTestDispatcher(source, Config{Window: time.Second}, clock.Real())
+
+	publish(d, 1)
+	d.Flush(context.Background())
+
+	payloads := sub.received()
+	require.Len(t, payloads, 1)
+	assert.False(t, payloads[0].Bulk)
+	assert.Len(t, payloads[0].Events, 1)
+}
+
+// rawRequest is a delivery as it arrived, for checking its framing.
+type rawRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newRawSubscriber(t *testing.T, source *fakeSource) func() []rawRequest {
+	var mu sync.Mutex
+	var requests []rawRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, rawRequest{header: r.Header, body: body})
+	}))
+	t.Cleanup(server.Close)
+
+	source.subscriptions = append(source.subscriptions, &domain.WebhookSubscription{ID: int64(len(source.subscriptions) + 1), URL: server.URL})
+	return func() []rawRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]rawRequest(nil), requests...)
+	}
+}
+
+func TestDispatcher_Flush_CloudEventsStructured(t *testing.T) {
+	source := &fakeSource{}
+	received := newRawSubscriber(t, source)
+	d, _ := newTestDispatcher(source, Config{Window: time.Second, MaxBatchSize: 2, Encoding: events.Encoding{
+		Format: events.FormatCloudEvents, Mode: events.ModeStructured, Source: "/product-service",
+	}}, clock.Real())
+
+	publish(d, 3)
+	d.Flush(context.Background())
+
+	requests := received()
+	require.Len(t, requests, 2)
+
+	assert.Equal(t, "application/cloudevents-batch+json", requests[0].header.Get("Content-Type"))
+	var batch []events.CloudEvent
+	require.NoError(t, json.Unmarshal(requests[0].body, &batch))
+	require.Len(t, batch, 2)
+	assert.Equal(t, "1.0", batch[0].SpecVersion)
+	assert.Equal(t, "/product-service", batch[0].Source)
+	assert.Equal(t, domain.ProductEventUpdated, batch[0].Type)
+	assert.Equal(t, "products/2", batch[1].Subject)
+	assert.Equal(t, "2", batch[1].Data.ID)
+
+	assert.Equal(t, "application/cloudevents+json", requests[1].header.Get("Content-Type"), "a lone event is not a batch")
+	var single events.CloudEvent
+	require.NoError(t, json.Unmarshal(requests[1].body, &single))
+	assert.Equal(t, "3", single.ID)
+}
+
+func TestDispatcher_Flush_CloudEventsBinary(t *testing.T) {
+	source := &fakeSource{}
+	received := newRawSubscriber(t, source)
+	d, _ := newTestDispatcher(source, Config{Window: time.Second, MaxBatchSize: 100, Encoding: events.Encoding{
+		Format: events.FormatCloudEvents, Mode: events.ModeBinary, Source: "/product-service",
+	}}, clock.Real())
+
+	publish(d, 2)
+	d.Flush(context.Background())
+
+	requests := received()
+	require.Len(t, requests, 2, "binary mode sends one event per request")
+	for i, request := range requests {
+		assert.Equal(t, "application/json", request.header.Get("Content-Type"))
+		assert.Equal(t, "1.0", request.header.Get("ce-specversion"))
+		assert.Equal(t, strconv.Itoa(i+1), request.header.Get("ce-id"))
+		assert.Equal(t, "/product-service", request.header.Get("ce-source"))
+		assert.Equal(t, domain.ProductEventUpdated, request.header.Get("ce-type"))
+
+		var event domain.ProductEvent
+		require.NoError(t, json.Unmarshal(request.body, &event))
+		assert.Equal(t, int64(i+1), event.ProductID)
+	}
+}
+
+func TestDispatcher_Flush_RetriesFailedDeliveries(t *testing.T) {
+	source := &fakeSource{}
+	flaky := newSubscriber(t, source, 1)
+	dead := newSubscriber(t, source, 100)
+	d, registry := newTestDispatcher(source, Config{Window: time.Second, MaxAttempts: 2}, clock.Real())
+
+	publish(d, 3)
+	d.Flush(context.Background())
+
+	assert.Equal(t, []int{3}, batchSizes(flaky.received()))
+	assert.Empty(t, dead.received())
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `webhook_deliveries_total{result="delivered"} 1`)
+	assert.Contains(t, buf.String(), `webhook_deliveries_total{result="failed"} 1`)
+}
+
+func TestDispatcher_Publish_DropsWhenQueueIsFull(t *testing.T) {
+	source := &fakeSource{}
+	sub := newSubscriber(t, source, 0)
+	d, registry := newTestDispatcher(source, Config{Window: time.Second, MaxPending: 2}, clock.Real())
+
+	publish(d, 3)
+	d.Flush(context.Background())
+
+	assert.Equal(t, []int{2}, batchSizes(sub.received()))
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `webhook_events_total{result="dropped"} 1`)
+	assert.Contains(t, buf.String(), `webhook_events_total{result="queued"} 2`)
+}
+
+func TestDispatcher_Flush_RequeuesWhenSubscriptionsFail(t *testing.T) {
+	source := &fakeSource{}
+	sub := newSubscriber(t, source, 0)
+	d, _ := newTestDispatcher(source, Config{Window: time.Second}, clock.Real())
+
+	publish(d, 2)
+	source.err = errors.New("connection refused")
+	d.Flush(context.Background())
+	assert.Empty(t, sub.received())
+
+	source.err = nil
+	d.Flush(context.Background())
+	assert.Equal(t, []int{2}, batchSizes(sub.received()))
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	source := &fakeSource{}
+	sub := newSubscriber(t, source, 0)
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	d, _ := newTestDispatcher(source, Config{Window: time.Minute, MaxBatchSize: 2}, fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.Run(ctx)
+	}()
+	fake.BlockUntilTickers(1)
+
+	// A full batch goes out without waiting for the window.
+	publish(d, 2)
+	require.Eventually(t, func() bool { return len(sub.received()) == 1 }, time.Second, 5*time.Millisecond)
+
+	// A partial one waits for the window to close.
+	publish(d, 1)
+	fake.Advance(time.Minute)
+	require.Eventually(t, func() bool { return len(sub.received()) == 2 }, time.Second, 5*time.Millisecond)
+
+	// Events still queued at shutdown are delivered before Run returns.
+	publish(d, 1)
+	cancel()
+	<-done
+	assert.Equal(t, []int{2, 1, 1}, batchSizes(sub.received()))
+}
+
+func TestDispatcher_Health(t *testing.T) {
+	source := &fakeSource{}
+	newSubscriber(t, source, 1)
+	d, _ := newTestDispatcher(source, Config{Window: time.Second, MaxBatchSize: 1}, clock.Real())
+
+	_, ok := d.Health(1)
+	assert.False(t, ok)
+
+	publish(d, 4)
+	d.Flush(context.Background())
+
+	health, ok := d.Health(1)
+	require.True(t, ok)
+	assert.Equal(t, int64(4), health.Deliveries)
+	assert.Equal(t, int64(1), health.Failures)
+	assert.Equal(t, 0, health.ConsecutiveFailures)
+	assert.Equal(t, 0.75, health.SuccessRate)
+	assert.True(t, health.LastSuccessAt.Valid)
+	assert.Contains(t, health.LastError, "status 503")
+}
+
+func TestDispatcher_PausesFailingSubscriber(t *testing.T) {
+	source := &fakeSource{}
+	dead := newSubscriber(t, source, 1000)
+	healthy := newSubscriber(t, source, 0)
+	notifier := &recordingNotifier{}
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	d, registry := newTestDispatcher(source, Config{Window: time.Second, MaxBatchSize: 1, PauseAfter: 3}, fake, notifier)
+
+	publish(d, 5)
+	d.Flush(context.Background())
+
+	// The dead endpoint stops being tried once it is paused; the healthy
+	// one gets every event.
+	assert.Empty(t, dead.received())
+	assert.Len(t, healthy.received(), 5)
+	health, _ := d.Health(1)
+	assert.Equal(t, int64(3), health.Deliveries)
+
+	require.Len(t, notifier.pauses, 1)
+	assert.Equal(t, int64(1), notifier.pauses[0].SubscriptionID)
+	assert.Equal(t, 3, notifier.pauses[0].ConsecutiveFailures)
+	assert.Equal(t, "3 consecutive deliveries failed", source.subscriptions[0].PauseReason)
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), "webhook_subscriptions_paused_total 1")
+
+	// Paused subscriptions get nothing until they are resumed, and a resume
+	// gives them a clean slate rather than pausing on the next failure.
+	publish(d, 1)
+	d.Flush(context.Background())
+	health, _ = d.Health(1)
+	assert.Equal(t, int64(3), health.Deliveries)
+
+	fake.Advance(time.Minute)
+	source.resume(1, fake.Now())
+	publish(d, 1)
+	d.Flush(context.Background())
+
+	health, _ = d.Health(1)
+	assert.Equal(t, 1, health.ConsecutiveFailures)
+	assert.False(t, source.subscriptions[0].PausedAt.Valid)
+	assert.Len(t, notifier.pauses, 1)
+}
+
+func TestDispatcher_Flush_DeliversOnlyMatchingEvents(t *testing.T) {
+	source := &fakeSource{}
+	all := newSubscriber(t, source, 0)
+	store := newSubscriber(t, source, 0)
+	deletes := newSubscriber(t, source, 0)
+	source.subscriptions[1].StoreID = sql.NullInt64{Int64: 2, Valid: true}
+	source.subscriptions[2].EventTypes = []string{domain.ProductEventDeleted}
+	d, _ := newTestDispatcher(source, Config{Window: time.Second}, clock.Real())
+
+	d.Publish(context.Background(), domain.ProductEvent{ID: "1", Type: domain.ProductEventUpdated, StoreID: 1, ProductID: 1})
+	d.Publish(context.Background(), domain.ProductEvent{ID: "2", Type: domain.ProductEventUpdated, StoreID: 2, ProductID: 2})
+	d.Publish(context.Background(), domain.ProductEvent{ID: "3", Type: domain.ProductEventDeleted, StoreID: 1, ProductID: 3})
+	d.Flush(context.Background())
+
+	eventIDs := func(sub *subscriber) []string {
+		var ids []string
+		for _, p := range sub.received() {
+			for _, event := range p.Events {
+				ids = append(ids, event.ID)
+			}
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, eventIDs(all))
+	assert.Equal(t, []string{"2"}, eventIDs(store))
+	assert.Equal(t, []string{"3"}, eventIDs(deletes))
+}
+
+type fakeSecrets struct {
+	values map[string][]byte
+	err    error
+}
+
+func (s *fakeSecrets) Get(ctx context.Context, key string) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	value, ok := s.values[key]
+	if !ok {
+		return nil, secrets.ErrNotFound
+	}
+	return value, nil
+}
+
+func signatureFor(key, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestDispatcher_Flush_SignsWithStoreSecret(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	secret, err := json.Marshal(domain.WebhookSigningSecret{
+		StoreID:           2,
+		Current:           "whsec_new",
+		Previous:          "whsec_old",
+		RotatedAt:         now.Add(-time.Hour),
+		PreviousExpiresAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	source := &fakeSource{}
+	storeRequests := newRawSubscriber(t, source)
+	globalRequests := newRawSubscriber(t, source)
+	source.subscriptions[0].StoreID = sql.NullInt64{Int64: 2, Valid: true}
+
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	store := &fakeSecrets{values: map[string][]byte{domain.WebhookSecretKey(2): secret}}
+	d := NewDispatcher(source, &http.Client{Timeout: 5 * time.Second}, store, nil, registry, Config{Window: time.Second}, clock.NewFake(now), logrus.New())
+
+	d.Publish(context.Background(), domain.ProductEvent{ID: "1", Type: domain.ProductEventUpdated, StoreID: 2, ProductID: 1})
+	d.Flush(context.Background())
+
+	requests := storeRequests()
+	require.Len(t, requests, 1)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	assert.Equal(t,
+		"t="+timestamp+",v1="+signatureFor("whsec_new", timestamp, requests[0].body)+",v1="+signatureFor("whsec_old", timestamp, requests[0].body),
+		requests[0].header.Get(SignatureHeader))
+
+	// No secret was issued for unscoped subscriptions yet.
+	requests = globalRequests()
+	require.Len(t, requests, 1)
+	assert.Empty(t, requests[0].header.Get(SignatureHeader))
+}
+
+func TestDispatcher_Flush_SkipsWhenSecretCannotBeLoaded(t *testing.T) {
+	source := &fakeSource{}
+	requests := newRawSubscriber(t, source)
+
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	store := &fakeSecrets{err: errors.New("db down")}
+	d := NewDispatcher(source, &http.Client{Timeout: 5 * time.Second}, store, nil, registry, Config{Window: time.Second}, clock.Real(), logrus.New())
+
+	publish(d, 1)
+	d.Flush(context.Background())
+
+	assert.Empty(t, requests())
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `webhook_deliveries_total{result="failed"} 1`)
+}
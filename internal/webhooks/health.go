@@ -0,0 +1,113 @@
+package webhooks
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+)
+
+// healthWindow is how many recent deliveries the success rate and average
+// latency are computed over.
+const healthWindow = 50
+
+type outcome struct {
+	ok      bool
+	latency time.Duration
+}
+
+type subscriberHealth struct {
+	recent      [healthWindow]outcome
+	size, next  int
+	deliveries  int64
+	failures    int64
+	consecutive int
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastError   string
+}
+
+// healthTracker scores subscribers by their deliveries from this instance.
+type healthTracker struct {
+	mu          sync.Mutex
+	subscribers map[int64]*subscriberHealth
+}
+
+func newHealthTracker() *healthTracker {
+	return &healthTracker{subscribers: make(map[int64]*subscriberHealth)}
+}
+
+// observe forgets a subscriber's failure streak if the subscription was
+// changed (i.e. resumed) since its last failure.
+func (t *healthTracker) observe(subscription *domain.WebhookSubscription) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if h, ok := t.subscribers[subscription.ID]; ok && subscription.UpdatedAt.After(h.lastFailure) {
+		h.consecutive = 0
+	}
+}
+
+func (t *healthTracker) record(id int64, err error, latency time.Duration, at time.Time) domain.WebhookHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.subscribers[id]
+	if !ok {
+		h = &subscriberHealth{}
+		t.subscribers[id] = h
+	}
+
+	h.recent[h.next] = outcome{ok: err == nil, latency: latency}
+	h.next = (h.next + 1) % healthWindow
+	h.size = min(h.size+1, healthWindow)
+	h.deliveries++
+	if err == nil {
+		h.consecutive = 0
+		h.lastSuccess = at
+	} else {
+		h.failures++
+		h.consecutive++
+		h.lastFailure = at
+		h.lastError = err.Error()
+	}
+	return h.snapshot(id)
+}
+
+func (t *healthTracker) get(id int64) (domain.WebhookHealth, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.subscribers[id]
+	if !ok {
+		return domain.WebhookHealth{SubscriptionID: id}, false
+	}
+	return h.snapshot(id), true
+}
+
+func (h *subscriberHealth) snapshot(id int64) domain.WebhookHealth {
+	health := domain.WebhookHealth{
+		SubscriptionID:      id,
+		Deliveries:          h.deliveries,
+		Failures:            h.failures,
+		ConsecutiveFailures: h.consecutive,
+		LastSuccessAt:       sql.NullTime{Time: h.lastSuccess, Valid: !h.lastSuccess.IsZero()},
+		LastFailureAt:       sql.NullTime{Time: h.lastFailure, Valid: !h.lastFailure.IsZero()},
+		LastError:           h.lastError,
+	}
+
+	var succeeded int
+	var latency time.Duration
+	for _, o := range h.recent[:h.size] {
+		if o.ok {
+			succeeded++
+		}
+		latency += o.latency
+	}
+	if h.size > 0 {
+		health.SuccessRate = float64(succeeded) / float64(h.size)
+		health.AvgLatency = latency / time.Duration(h.size)
+	}
+	return health
+}
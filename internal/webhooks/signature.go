@@ -0,0 +1,69 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/secrets"
+)
+
+// SignatureHeader carries "t=<unix seconds>,v1=<signature>", with one v1
+// per active secret. A signature is the hex HMAC-SHA256 of "<t>.<body>",
+// keyed with the secret of the subscription's store.
+const SignatureHeader = "Webhook-Signature"
+
+// SecretStore holds the signing secrets managed by WebhookSecretUseCase.
+type SecretStore interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// signingSecrets returns the secrets to sign a subscription's deliveries
+// with, or none if its store has no secret yet.
+func (d *Dispatcher) signingSecrets(ctx context.Context, subscription *domain.WebhookSubscription) ([]string, error) {
+	if d.secrets == nil {
+		return nil, nil
+	}
+	return SigningSecrets(ctx, d.secrets, subscription.StoreID.Int64, d.clock.Now())
+}
+
+// SigningSecrets returns the store's secrets active at at, or none if the
+// store has no secret yet. Other senders of store payloads use it to sign
+// like the dispatcher does.
+func SigningSecrets(ctx context.Context, store SecretStore, storeID int64, at time.Time) ([]string, error) {
+	data, err := store.Get(ctx, domain.WebhookSecretKey(storeID))
+	if errors.Is(err, secrets.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load webhook secret: %w", err)
+	}
+
+	var secret domain.WebhookSigningSecret
+	if err := json.Unmarshal(data, &secret); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook secret: %w", err)
+	}
+	return secret.Active(at), nil
+}
+
+// Sign returns the SignatureHeader value for body sent at at.
+func Sign(keys []string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+
+	parts := []string{"t=" + timestamp}
+	for _, key := range keys {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(timestamp + "."))
+		mac.Write(body)
+		parts = append(parts, "v1="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	return strings.Join(parts, ",")
+}
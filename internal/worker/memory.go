@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+)
+
+// MemoryQueue keeps jobs in this process, so they are only run by its own
+// pool and are lost when it exits. Claimed jobs are not leased: they can
+// only be lost with the process holding them.
+type MemoryQueue struct {
+	maxPending int
+	clock      clock.Clock
+
+	mu        sync.Mutex
+	ready     []Job
+	scheduled []scheduledJob
+	waiting   map[string]bool
+}
+
+type scheduledJob struct {
+	job Job
+	at  time.Time
+}
+
+// NewMemoryQueue builds a queue that holds up to maxPending jobs, retries
+// included.
+func NewMemoryQueue(maxPending int, clk clock.Clock) *MemoryQueue {
+	return &MemoryQueue{
+		maxPending: maxPending,
+		clock:      clk,
+		waiting:    make(map[string]bool),
+	}
+}
+
+func (q *MemoryQueue) Enqueue(ctx context.Context, job Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.waiting[job.ID] {
+		return nil
+	}
+	if len(q.ready)+len(q.scheduled) >= q.maxPending {
+		return ErrQueueFull
+	}
+	q.waiting[job.ID] = true
+	q.ready = append(q.ready, job)
+	return nil
+}
+
+func (q *MemoryQueue) Claim(ctx context.Context, lease time.Duration) (Job, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.clock.Now()
+	later := q.scheduled[:0]
+	for _, s := range q.scheduled {
+		if s.at.After(now) {
+			later = append(later, s)
+			continue
+		}
+		q.ready = append(q.ready, s.job)
+	}
+	q.scheduled = later
+
+	if len(q.ready) == 0 {
+		return Job{}, false, nil
+	}
+	job := q.ready[0]
+	q.ready = q.ready[1:]
+	delete(q.waiting, job.ID)
+	return job, true, nil
+}
+
+func (q *MemoryQueue) Complete(ctx context.Context, job Job) error {
+	return nil
+}
+
+// Retry requeues job at at. Retries are not refused when the queue is
+// full, so a failed job is not dropped for want of room.
+func (q *MemoryQueue) Retry(ctx context.Context, job Job, at time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.scheduled = append(q.scheduled, scheduledJob{job: job, at: at})
+	return nil
+}
+
+// Len returns how many jobs are queued, retries included.
+func (q *MemoryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.ready) + len(q.scheduled)
+}
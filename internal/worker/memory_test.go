@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryQueue_SharesWaitingJobs(t *testing.T) {
+	ctx := context.Background()
+	queue := NewMemoryQueue(10, clock.NewFake(start))
+	enqueue(t, queue, "test", "1")
+	enqueue(t, queue, "test", "1")
+	enqueue(t, queue, "test", "2")
+	assert.Equal(t, 2, queue.Len())
+
+	job, ok, err := queue.Claim(ctx, time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "test:1", job.ID)
+
+	// Once claimed, the job may be queued again for changes made since.
+	enqueue(t, queue, "test", "1")
+	assert.Equal(t, 2, queue.Len())
+}
+
+func TestMemoryQueue_Full(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake(start)
+	queue := NewMemoryQueue(1, clk)
+	enqueue(t, queue, "test", "1")
+
+	job, err := NewJob("test", "2", nil)
+	require.NoError(t, err)
+	assert.ErrorIs(t, queue.Enqueue(ctx, job), ErrQueueFull)
+
+	// Retries are taken even when the queue is full.
+	claimed, ok, err := queue.Claim(ctx, time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	enqueue(t, queue, "test", "3")
+	require.NoError(t, queue.Retry(ctx, claimed, clk.Now()))
+	assert.Equal(t, 2, queue.Len())
+}
@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// enqueueScript queues a job unless one with its ID is waiting.
+var enqueueScript = goredis.NewScript(`
+if redis.call('SADD', KEYS[2], ARGV[1]) == 1 then
+	redis.call('LPUSH', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// claimScript moves due retries and claims whose lease ran out back to the
+// ready list, then claims the oldest ready job until ARGV[2].
+var claimScript = goredis.NewScript(`
+local now = tonumber(ARGV[1])
+for _, key in ipairs({KEYS[2], KEYS[3]}) do
+	for _, job in ipairs(redis.call('ZRANGEBYSCORE', key, '-inf', now, 'LIMIT', 0, 100)) do
+		redis.call('ZREM', key, job)
+		redis.call('LPUSH', KEYS[1], job)
+	end
+end
+local job = redis.call('RPOP', KEYS[1])
+if not job then
+	return false
+end
+redis.call('SREM', KEYS[4], cjson.decode(job).id)
+redis.call('ZADD', KEYS[3], ARGV[2], job)
+return job
+`)
+
+// RedisQueue keeps jobs in Redis, so every instance sharing it takes jobs
+// from the same queue and jobs survive restarts. Jobs wait in a list,
+// retries in a sorted set by when they are due, and claimed jobs in one by
+// when their lease runs out.
+type RedisQueue struct {
+	client goredis.UniversalClient
+	keys   []string
+	clock  clock.Clock
+}
+
+func NewRedisQueue(client goredis.UniversalClient, prefix string, clk clock.Clock) *RedisQueue {
+	// The hash tag keeps the keys in one cluster slot for the scripts.
+	return &RedisQueue{
+		client: client,
+		keys: []string{
+			prefix + "{jobs}:ready",
+			prefix + "{jobs}:scheduled",
+			prefix + "{jobs}:claimed",
+			prefix + "{jobs}:waiting",
+		},
+		clock: clk,
+	}
+}
+
+func (q *RedisQueue) ready() string     { return q.keys[0] }
+func (q *RedisQueue) scheduled() string { return q.keys[1] }
+func (q *RedisQueue) claimed() string   { return q.keys[2] }
+func (q *RedisQueue) waiting() string   { return q.keys[3] }
+
+func (q *RedisQueue) Enqueue(ctx context.Context, job Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s: %w", job.ID, err)
+	}
+	if err := enqueueScript.Run(ctx, q.client, []string{q.ready(), q.waiting()}, job.ID, data).Err(); err != nil {
+		return fmt.Errorf("failed to enqueue job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (q *RedisQueue) Claim(ctx context.Context, lease time.Duration) (Job, bool, error) {
+	now := q.clock.Now()
+	data, err := claimScript.Run(ctx, q.client, q.keys, now.UnixMilli(), now.Add(lease).UnixMilli()).Text()
+	if err == goredis.Nil {
+		return Job{}, false, nil
+	}
+	if err != nil {
+		return Job{}, false, fmt.Errorf("failed to claim job: %w", err)
+	}
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		// A job that cannot be decoded would be claimed again forever.
+		q.client.ZRem(ctx, q.claimed(), data)
+		return Job{}, false, fmt.Errorf("failed to decode job: %w", err)
+	}
+	job.claim = data
+	return job, true, nil
+}
+
+func (q *RedisQueue) Complete(ctx context.Context, job Job) error {
+	if err := q.client.ZRem(ctx, q.claimed(), job.claim).Err(); err != nil {
+		return fmt.Errorf("failed to complete job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (q *RedisQueue) Retry(ctx context.Context, job Job, at time.Time) error {
+	claim := job.claim
+	job.claim = ""
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s: %w", job.ID, err)
+	}
+	_, err = q.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, q.claimed(), claim)
+		pipe.ZAdd(ctx, q.scheduled(), goredis.Z{Score: float64(at.UnixMilli()), Member: data})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to retry job %s: %w", job.ID, err)
+	}
+	return nil
+}
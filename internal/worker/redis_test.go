@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisQueue(t *testing.T, clk clock.Clock) *RedisQueue {
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisQueue(client, "test:", clk)
+}
+
+func claim(t *testing.T, queue Queue) (Job, bool) {
+	job, ok, err := queue.Claim(context.Background(), time.Minute)
+	require.NoError(t, err)
+	return job, ok
+}
+
+func TestRedisQueue_ClaimsInOrder(t *testing.T) {
+	ctx := context.Background()
+	queue := newTestRedisQueue(t, clock.NewFake(start))
+	enqueue(t, queue, "test", "1")
+	enqueue(t, queue, "test", "1")
+	enqueue(t, queue, "test", "2")
+
+	first, ok := claim(t, queue)
+	require.True(t, ok)
+	assert.Equal(t, "test:1", first.ID)
+	assert.JSONEq(t, `{"key":"1"}`, string(first.Payload))
+	second, ok := claim(t, queue)
+	require.True(t, ok)
+	assert.Equal(t, "test:2", second.ID)
+	_, ok = claim(t, queue)
+	assert.False(t, ok)
+
+	require.NoError(t, queue.Complete(ctx, first))
+	require.NoError(t, queue.Complete(ctx, second))
+	assert.Zero(t, queue.client.ZCard(ctx, queue.claimed()).Val())
+}
+
+func TestRedisQueue_RetryIsDueAtItsTime(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake(start)
+	queue := newTestRedisQueue(t, clk)
+	enqueue(t, queue, "test", "1")
+
+	job, ok := claim(t, queue)
+	require.True(t, ok)
+	job.Attempt++
+	require.NoError(t, queue.Retry(ctx, job, clk.Now().Add(10*time.Second)))
+	_, ok = claim(t, queue)
+	assert.False(t, ok)
+
+	clk.Advance(10 * time.Second)
+	retried, ok := claim(t, queue)
+	require.True(t, ok)
+	assert.Equal(t, "test:1", retried.ID)
+	assert.Equal(t, 1, retried.Attempt)
+}
+
+func TestRedisQueue_ReclaimsExpiredLeases(t *testing.T) {
+	clk := clock.NewFake(start)
+	queue := newTestRedisQueue(t, clk)
+	enqueue(t, queue, "test", "1")
+
+	_, ok := claim(t, queue)
+	require.True(t, ok)
+	clk.Advance(59 * time.Second)
+	_, ok = claim(t, queue)
+	assert.False(t, ok)
+
+	// The worker holding the job died; another instance takes it over.
+	clk.Advance(time.Second)
+	job, ok := claim(t, queue)
+	require.True(t, ok)
+	assert.Equal(t, "test:1", job.ID)
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"backend-context-engineering-template/internal/domain"
+
+	"github.com/sirupsen/logrus"
+)
+
+// JobRecalculateStockSummary recalculates a store's stock summary.
+const JobRecalculateStockSummary = "stock_summary.recalculate"
+
+type stockSummaryPayload struct {
+	StoreID int64 `json:"store_id"`
+}
+
+// StockSummaryRecalculator counts a store's products into its summary.
+type StockSummaryRecalculator interface {
+	RecalculateStockSummary(ctx context.Context, storeID int64) (*domain.StockSummary, error)
+}
+
+// StockSummaries keeps the stores' stock summaries up to date. Every
+// product event queues a recalculation of its store; changes made while
+// one is waiting share it, so a bulk import recalculates once or twice
+// rather than once per product.
+type StockSummaries struct {
+	queue        Queue
+	recalculator StockSummaryRecalculator
+	logger       *logrus.Logger
+}
+
+func NewStockSummaries(queue Queue, recalculator StockSummaryRecalculator, logger *logrus.Logger) *StockSummaries {
+	return &StockSummaries{
+		queue:        queue,
+		recalculator: recalculator,
+		logger:       logger,
+	}
+}
+
+// Publish queues a recalculation of the event's store. It is an
+// events.Sink.
+func (s *StockSummaries) Publish(ctx context.Context, event domain.ProductEvent) {
+	job, err := NewJob(JobRecalculateStockSummary, strconv.FormatInt(event.StoreID, 10), stockSummaryPayload{StoreID: event.StoreID})
+	if err == nil {
+		err = s.queue.Enqueue(ctx, job)
+	}
+	if err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("store_id", event.StoreID).Warn("Failed to queue stock summary recalculation")
+	}
+}
+
+// Handle runs a JobRecalculateStockSummary job. Stores deleted since it
+// was queued have nothing left to count.
+func (s *StockSummaries) Handle(ctx context.Context, job Job) error {
+	var payload stockSummaryPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("failed to decode stock summary job: %w", err)
+	}
+	_, err := s.recalculator.RecalculateStockSummary(ctx, payload.StoreID)
+	if errors.Is(err, domain.ErrStoreNotFound) {
+		return nil
+	}
+	return err
+}
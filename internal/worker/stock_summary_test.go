@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/internal/domain"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRecalculator struct {
+	stores []int64
+	err    error
+}
+
+func (r *fakeRecalculator) RecalculateStockSummary(ctx context.Context, storeID int64) (*domain.StockSummary, error) {
+	r.stores = append(r.stores, storeID)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &domain.StockSummary{StoreID: storeID}, nil
+}
+
+func TestStockSummaries_RecalculatesOncePerWaitingStore(t *testing.T) {
+	ctx := context.Background()
+	queue := NewMemoryQueue(10, clock.NewFake(start))
+	recalculator := &fakeRecalculator{}
+	summaries := NewStockSummaries(queue, recalculator, logrus.New())
+
+	summaries.Publish(ctx, domain.ProductEvent{StoreID: 7})
+	summaries.Publish(ctx, domain.ProductEvent{StoreID: 7})
+	summaries.Publish(ctx, domain.ProductEvent{StoreID: 8})
+	for {
+		job, ok, err := queue.Claim(ctx, time.Minute)
+		require.NoError(t, err)
+		if !ok {
+			break
+		}
+		assert.Equal(t, JobRecalculateStockSummary, job.Type)
+		require.NoError(t, summaries.Handle(ctx, job))
+	}
+
+	assert.Equal(t, []int64{7, 8}, recalculator.stores)
+}
+
+func TestStockSummaries_Handle(t *testing.T) {
+	ctx := context.Background()
+	job, err := NewJob(JobRecalculateStockSummary, "7", stockSummaryPayload{StoreID: 7})
+	require.NoError(t, err)
+
+	// A store deleted since the job was queued has nothing to count.
+	summaries := NewStockSummaries(nil, &fakeRecalculator{err: domain.ErrStoreNotFound}, logrus.New())
+	assert.NoError(t, summaries.Handle(ctx, job))
+
+	failure := errors.New("connection refused")
+	summaries = NewStockSummaries(nil, &fakeRecalculator{err: failure}, logrus.New())
+	assert.ErrorIs(t, summaries.Handle(ctx, job), failure)
+}
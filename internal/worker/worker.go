@@ -0,0 +1,216 @@
+// Package worker runs jobs in the background. Work that should not hold up
+// a request or an event sink is enqueued as a Job and run by a Pool, which
+// retries it with backoff when it fails. The pool's goroutines start and
+// stop with it, so queued work never outlives the service in a goroutine
+// nobody waits for.
+//
+// Jobs run at least once: a job whose worker dies is claimed again once
+// its lease runs out, so handlers must be safe to run twice.
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/redis"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrQueueFull is returned by Enqueue when the queue holds as many jobs as
+// it may.
+var ErrQueueFull = errors.New("job queue is full")
+
+// Job is a unit of background work.
+type Job struct {
+	// ID identifies the work the job does. A job is not enqueued while
+	// another with its ID is waiting to run, so changes that each need the
+	// same work done share one run.
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// Attempt counts the failed runs so far.
+	Attempt int `json:"attempt,omitempty"`
+
+	// claim is the job as the queue holds it while it is claimed.
+	claim string
+}
+
+// NewJob builds a job of jobType for the work key names, such as the ID of
+// the store it recalculates.
+func NewJob(jobType, key string, payload any) (Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Job{}, fmt.Errorf("failed to encode %s job: %w", jobType, err)
+	}
+	return Job{ID: jobType + ":" + key, Type: jobType, Payload: data}, nil
+}
+
+// Queue holds jobs until a pool runs them. MemoryQueue keeps them in this
+// process; RedisQueue shares them between instances and keeps them across
+// restarts.
+type Queue interface {
+	// Enqueue adds job, unless a job with its ID is already waiting.
+	Enqueue(ctx context.Context, job Job) error
+	// Claim takes the next job that is due and leases it for lease. It
+	// returns false when no job is due. A job neither completed nor
+	// retried before its lease runs out is claimed again.
+	Claim(ctx context.Context, lease time.Duration) (Job, bool, error)
+	// Complete removes a claimed job.
+	Complete(ctx context.Context, job Job) error
+	// Retry returns a claimed job to the queue, due at at.
+	Retry(ctx context.Context, job Job, at time.Time) error
+}
+
+// Handler runs a job. A job whose handler returns an error is retried.
+type Handler func(ctx context.Context, job Job) error
+
+type Config struct {
+	// Concurrency is how many jobs run at once.
+	Concurrency int
+	// PollInterval is how often an idle pool looks for due jobs.
+	PollInterval time.Duration
+	// Lease is how long a job may run. It is claimed again after that.
+	Lease time.Duration
+	// MaxAttempts is how many times a failing job runs before it is
+	// dropped.
+	MaxAttempts int
+	// RetryBackoff is the wait before the first retry; it doubles with
+	// each one after.
+	RetryBackoff time.Duration
+}
+
+// maxBackoffDoublings caps how many times RetryBackoff doubles.
+const maxBackoffDoublings = 10
+
+// Pool runs queued jobs with the handler registered for their type.
+type Pool struct {
+	queue    Queue
+	handlers map[string]Handler
+	cfg      Config
+	logger   *logrus.Logger
+	clock    clock.Clock
+
+	jobs     *telemetry.Counter
+	duration *telemetry.Histogram
+}
+
+func NewPool(queue Queue, handlers map[string]Handler, registry *telemetry.Registry, cfg Config, clk clock.Clock, logger *logrus.Logger) *Pool {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	return &Pool{
+		queue:    queue,
+		handlers: handlers,
+		cfg:      cfg,
+		logger:   logger,
+		clock:    clk,
+		jobs:     registry.NewCounter("jobs_total", "Background jobs run by type and result.", "type", "result"),
+		duration: registry.NewHistogram("job_duration_seconds", "Time background jobs took to run by type.", telemetry.DefaultDurationBuckets, "type"),
+	}
+}
+
+// Run runs jobs on Concurrency goroutines until ctx is cancelled, and
+// returns once they have stopped. Jobs that have started run to the end
+// of their lease; jobs that have not stay queued.
+func (p *Pool) Run(ctx context.Context) {
+	p.logger.WithFields(logrus.Fields{"concurrency": p.cfg.Concurrency, "interval": p.cfg.PollInterval}).Info("Job pool started")
+
+	var wg sync.WaitGroup
+	for range p.cfg.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx)
+		}()
+	}
+	wg.Wait()
+
+	p.logger.Info("Job pool stopped")
+}
+
+// work runs due jobs until there are none, then waits for the next poll.
+func (p *Pool) work(ctx context.Context) {
+	ticker := p.clock.NewTicker(p.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil && p.RunNext(ctx) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// RunNext claims the next due job and runs it. It reports whether there
+// was one.
+func (p *Pool) RunNext(ctx context.Context) bool {
+	job, ok, err := p.queue.Claim(ctx, p.cfg.Lease)
+	if err != nil {
+		// The tracker already reports Redis being down.
+		if ctx.Err() == nil && !errors.Is(err, redis.ErrUnavailable) {
+			p.logger.WithError(err).Warn("Failed to claim a job")
+		}
+		return false
+	}
+	if !ok {
+		return false
+	}
+	p.run(ctx, job)
+	return true
+}
+
+func (p *Pool) run(ctx context.Context, job Job) {
+	// A job the pool stops during still runs to the end of its lease, so
+	// it is not cut off halfway.
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.cfg.Lease)
+	defer cancel()
+
+	logger := p.logger.WithFields(logrus.Fields{"job_id": job.ID, "job_type": job.Type, "attempt": job.Attempt + 1})
+	start := p.clock.Now()
+	handler, ok := p.handlers[job.Type]
+	var err error
+	if ok {
+		err = handler(runCtx, job)
+	} else {
+		err = fmt.Errorf("no handler for job type %q", job.Type)
+	}
+	p.duration.Observe(p.clock.Now().Sub(start).Seconds(), job.Type)
+
+	if err == nil {
+		p.jobs.Inc(job.Type, "succeeded")
+		if err := p.queue.Complete(runCtx, job); err != nil {
+			logger.WithError(err).Warn("Failed to complete job, it runs again once its lease runs out")
+		}
+		return
+	}
+
+	job.Attempt++
+	if !ok || job.Attempt >= p.cfg.MaxAttempts {
+		p.jobs.Inc(job.Type, "failed")
+		logger.WithError(err).Error("Job failed, giving up")
+		if err := p.queue.Complete(runCtx, job); err != nil {
+			logger.WithError(err).Warn("Failed to drop job, it runs again once its lease runs out")
+		}
+		return
+	}
+
+	p.jobs.Inc(job.Type, "retried")
+	backoff := p.cfg.RetryBackoff << min(job.Attempt-1, maxBackoffDoublings)
+	logger.WithError(err).WithField("backoff", backoff).Warn("Job failed, retrying")
+	if err := p.queue.Retry(runCtx, job, p.clock.Now().Add(backoff)); err != nil {
+		logger.WithError(err).Warn("Failed to retry job, it runs again once its lease runs out")
+	}
+}
@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func newTestPool(queue Queue, handlers map[string]Handler, clk clock.Clock) (*Pool, *telemetry.Registry) {
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	cfg := Config{Concurrency: 2, PollInterval: time.Second, Lease: time.Minute, MaxAttempts: 3, RetryBackoff: 10 * time.Second}
+	return NewPool(queue, handlers, registry, cfg, clk, logrus.New()), registry
+}
+
+func metrics(t *testing.T, registry *telemetry.Registry) string {
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	return buf.String()
+}
+
+func enqueue(t *testing.T, queue Queue, jobType, key string) {
+	job, err := NewJob(jobType, key, map[string]string{"key": key})
+	require.NoError(t, err)
+	require.NoError(t, queue.Enqueue(context.Background(), job))
+}
+
+func TestPool_RunsQueuedJobs(t *testing.T) {
+	clk := clock.NewFake(start)
+	queue := NewMemoryQueue(10, clk)
+	ran := make(chan string, 2)
+	pool, registry := newTestPool(queue, map[string]Handler{
+		"test": func(ctx context.Context, job Job) error {
+			ran <- job.ID
+			return nil
+		},
+	}, clk)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pool.Run(ctx)
+	}()
+	clk.BlockUntilTickers(2)
+
+	enqueue(t, queue, "test", "1")
+	enqueue(t, queue, "test", "2")
+	clk.Advance(time.Second)
+	got := []string{<-ran, <-ran}
+	cancel()
+	wg.Wait()
+
+	assert.ElementsMatch(t, []string{"test:1", "test:2"}, got)
+	assert.Equal(t, 0, queue.Len())
+	assert.Contains(t, metrics(t, registry), `jobs_total{result="succeeded",type="test"} 2`)
+}
+
+func TestPool_RetriesWithBackoff(t *testing.T) {
+	clk := clock.NewFake(start)
+	queue := NewMemoryQueue(10, clk)
+	var attempts []int
+	pool, registry := newTestPool(queue, map[string]Handler{
+		"test": func(ctx context.Context, job Job) error {
+			attempts = append(attempts, job.Attempt)
+			if job.Attempt < 1 {
+				return errors.New("boom")
+			}
+			return nil
+		},
+	}, clk)
+	ctx := context.Background()
+	enqueue(t, queue, "test", "1")
+
+	assert.True(t, pool.RunNext(ctx))
+	assert.Equal(t, 1, queue.Len())
+	// The retry is not due until the backoff has passed.
+	clk.Advance(9 * time.Second)
+	assert.False(t, pool.RunNext(ctx))
+	clk.Advance(time.Second)
+	assert.True(t, pool.RunNext(ctx))
+
+	assert.Equal(t, []int{0, 1}, attempts)
+	assert.Equal(t, 0, queue.Len())
+	output := metrics(t, registry)
+	assert.Contains(t, output, `jobs_total{result="retried",type="test"} 1`)
+	assert.Contains(t, output, `jobs_total{result="succeeded",type="test"} 1`)
+}
+
+func TestPool_GivesUpAfterMaxAttempts(t *testing.T) {
+	clk := clock.NewFake(start)
+	queue := NewMemoryQueue(10, clk)
+	runs := 0
+	pool, registry := newTestPool(queue, map[string]Handler{
+		"test": func(ctx context.Context, job Job) error {
+			runs++
+			return errors.New("boom")
+		},
+	}, clk)
+	ctx := context.Background()
+	enqueue(t, queue, "test", "1")
+
+	for pool.RunNext(ctx) || queue.Len() > 0 {
+		clk.Advance(time.Minute)
+	}
+
+	assert.Equal(t, 3, runs)
+	assert.Contains(t, metrics(t, registry), `jobs_total{result="failed",type="test"} 1`)
+}
+
+func TestPool_DropsJobsWithoutHandler(t *testing.T) {
+	clk := clock.NewFake(start)
+	queue := NewMemoryQueue(10, clk)
+	pool, registry := newTestPool(queue, map[string]Handler{}, clk)
+	enqueue(t, queue, "unknown", "1")
+
+	assert.True(t, pool.RunNext(context.Background()))
+	assert.Equal(t, 0, queue.Len())
+	assert.Contains(t, metrics(t, registry), `jobs_total{result="failed",type="unknown"} 1`)
+}
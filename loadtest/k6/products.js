@@ -0,0 +1,67 @@
+// Product CRUD baseline. Run against `make loadtest-server`:
+//   k6 run loadtest/k6/products.js
+//   BASE_URL=http://host:8080 VUS=50 DURATION=2m k6 run loadtest/k6/products.js
+import http from 'k6/http';
+import { check } from 'k6';
+
+const BASE_URL = __ENV.BASE_URL || 'http://localhost:8080';
+const headers = { 'Content-Type': 'application/json' };
+
+export const options = {
+  scenarios: {
+    crud: {
+      executor: 'constant-vus',
+      vus: Number(__ENV.VUS || 20),
+      duration: __ENV.DURATION || '1m',
+    },
+  },
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{name:get}': ['p(95)<50'],
+  },
+};
+
+// Seed enough products that list and read requests hit real data.
+export function setup() {
+  for (let i = 0; i < 200; i++) {
+    http.post(`${BASE_URL}/api/v1/products`, JSON.stringify({
+      store_id: (i % 10) + 1,
+      name: `seed-${i}`,
+      amount: 10,
+      price: 9.99,
+    }), { headers });
+  }
+}
+
+export default function () {
+  const storeID = (__VU % 10) + 1;
+  const created = http.post(`${BASE_URL}/api/v1/products`, JSON.stringify({
+    store_id: storeID,
+    name: `vu-${__VU}-${__ITER}`,
+    amount: 1,
+    price: 1.5,
+  }), { headers, tags: { name: 'create' } });
+  check(created, { 'created': (r) => r.status === 201 });
+  if (created.status !== 201) {
+    return;
+  }
+  const id = created.json('id');
+
+  const got = http.get(`${BASE_URL}/api/v1/products/${id}`, { tags: { name: 'get' } });
+  check(got, { 'read': (r) => r.status === 200 });
+
+  const listed = http.get(`${BASE_URL}/api/v1/products?limit=20`, { tags: { name: 'list' } });
+  check(listed, { 'listed': (r) => r.status === 200 });
+
+  const updated = http.put(`${BASE_URL}/api/v1/products/${id}`, JSON.stringify({
+    store_id: storeID,
+    name: `vu-${__VU}-${__ITER}-updated`,
+    amount: 2,
+    price: 2.5,
+    version: created.json('version'),
+  }), { headers, tags: { name: 'update' } });
+  check(updated, { 'updated': (r) => r.status === 200 });
+
+  const deleted = http.del(`${BASE_URL}/api/v1/products/${id}`, null, { tags: { name: 'delete' } });
+  check(deleted, { 'deleted': (r) => r.status === 204 });
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS product_feed_runs;
+DROP TABLE IF EXISTS product_feeds;
+ALTER TABLE products DROP COLUMN IF EXISTS status;
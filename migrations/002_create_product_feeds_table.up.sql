@@ -0,0 +1,30 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active';
+
+CREATE TABLE IF NOT EXISTS product_feeds (
+    id SERIAL PRIMARY KEY,
+    store_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    interval_seconds INTEGER NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS product_feed_runs (
+    id SERIAL PRIMARY KEY,
+    feed_id INTEGER NOT NULL REFERENCES product_feeds(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    created INTEGER NOT NULL DEFAULT 0,
+    updated INTEGER NOT NULL DEFAULT 0,
+    deactivated INTEGER NOT NULL DEFAULT 0,
+    unchanged INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]',
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX idx_product_feeds_store_id ON product_feeds(store_id);
+CREATE INDEX idx_product_feed_runs_feed_id ON product_feed_runs(feed_id, started_at DESC);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS connector_syncs;
+DROP TABLE IF EXISTS connector_product_links;
+DROP TABLE IF EXISTS connector_checkpoints;
+DROP TABLE IF EXISTS connectors;
+DROP TABLE IF EXISTS secrets;
@@ -0,0 +1 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS description_format VARCHAR(20) NOT NULL DEFAULT 'plain';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_products_moderation_status;
+
+ALTER TABLE products DROP COLUMN IF EXISTS moderation_reason;
+ALTER TABLE products DROP COLUMN IF EXISTS moderation_status;
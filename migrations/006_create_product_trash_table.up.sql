@@ -0,0 +1,20 @@
+-- Deleted products are moved here so they can be restored until purged.
+-- Columns mirror products; keep them in sync when products changes.
+CREATE TABLE IF NOT EXISTS product_trash (
+    id INTEGER PRIMARY KEY,
+    store_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    description_format VARCHAR(20) NOT NULL DEFAULT 'plain',
+    amount INTEGER NOT NULL DEFAULT 0,
+    price NUMERIC(12,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    moderation_status VARCHAR(20) NOT NULL DEFAULT 'approved',
+    moderation_reason TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    trashed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_trash_store_id ON product_trash(store_id);
+CREATE INDEX IF NOT EXISTS idx_product_trash_trashed_at ON product_trash(trashed_at);
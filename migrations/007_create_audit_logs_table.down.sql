@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS audit_export_cursors;
+DROP TABLE IF EXISTS audit_logs;
@@ -0,0 +1,7 @@
+-- TOTP secrets live in the secrets table under "two_factor:<identity>".
+CREATE TABLE IF NOT EXISTS two_factor_enrollments (
+    identity VARCHAR(100) PRIMARY KEY,
+    recovery_code_hashes TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    confirmed_at TIMESTAMP
+);
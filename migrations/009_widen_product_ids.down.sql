@@ -0,0 +1,5 @@
+-- Fails if any product ID no longer fits in 32 bits.
+ALTER TABLE connector_product_links ALTER COLUMN product_id TYPE INTEGER;
+ALTER TABLE product_trash ALTER COLUMN id TYPE INTEGER;
+ALTER TABLE products ALTER COLUMN id TYPE INTEGER;
+ALTER SEQUENCE products_id_seq AS INTEGER;
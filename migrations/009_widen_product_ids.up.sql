@@ -0,0 +1,6 @@
+-- Snowflake product IDs need 64 bits. The sequence is widened too so
+-- serial IDs keep working alongside them.
+ALTER SEQUENCE products_id_seq AS BIGINT;
+ALTER TABLE products ALTER COLUMN id TYPE BIGINT;
+ALTER TABLE product_trash ALTER COLUMN id TYPE BIGINT;
+ALTER TABLE connector_product_links ALTER COLUMN product_id TYPE BIGINT;
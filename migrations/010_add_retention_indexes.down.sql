@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_connector_syncs_started_at;
+DROP INDEX IF EXISTS idx_product_feed_runs_started_at;
@@ -0,0 +1,4 @@
+-- Retention purges scan these tables by age; audit_logs(occurred_at) and
+-- product_trash(trashed_at) are already indexed.
+CREATE INDEX IF NOT EXISTS idx_product_feed_runs_started_at ON product_feed_runs(started_at);
+CREATE INDEX IF NOT EXISTS idx_connector_syncs_started_at ON connector_syncs(started_at);
@@ -0,0 +1,2 @@
+ALTER TABLE webhook_subscriptions DROP COLUMN IF EXISTS pause_reason;
+ALTER TABLE webhook_subscriptions DROP COLUMN IF EXISTS paused_at;
@@ -0,0 +1,2 @@
+ALTER TABLE webhook_subscriptions ADD COLUMN IF NOT EXISTS paused_at TIMESTAMP;
+ALTER TABLE webhook_subscriptions ADD COLUMN IF NOT EXISTS pause_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+-- Inbound messages already applied, recorded in the transaction that
+-- applied them so redeliveries are recognised.
+CREATE TABLE IF NOT EXISTS inbox_messages (
+    id BIGSERIAL PRIMARY KEY,
+    source VARCHAR(100) NOT NULL,
+    message_id VARCHAR(255) NOT NULL,
+    processed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (source, message_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inbox_messages_processed_at ON inbox_messages(processed_at);
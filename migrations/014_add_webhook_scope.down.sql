@@ -0,0 +1,2 @@
+ALTER TABLE webhook_subscriptions DROP COLUMN IF EXISTS event_types;
+ALTER TABLE webhook_subscriptions DROP COLUMN IF EXISTS store_id;
@@ -0,0 +1,4 @@
+-- A NULL store_id subscribes to every store; an empty event_types array
+-- to every event type.
+ALTER TABLE webhook_subscriptions ADD COLUMN IF NOT EXISTS store_id BIGINT;
+ALTER TABLE webhook_subscriptions ADD COLUMN IF NOT EXISTS event_types TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS catalog_changes;
+DROP TABLE IF EXISTS digest_settings;
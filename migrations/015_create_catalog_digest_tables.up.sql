@@ -0,0 +1,24 @@
+-- Stores that receive a daily digest of their catalog changes.
+CREATE TABLE IF NOT EXISTS digest_settings (
+    store_id BIGINT PRIMARY KEY,
+    channel VARCHAR(20) NOT NULL,
+    target TEXT NOT NULL,
+    last_sent_day DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Products changed per store, day and event type, for building digests.
+CREATE TABLE IF NOT EXISTS catalog_changes (
+    id BIGSERIAL PRIMARY KEY,
+    store_id BIGINT NOT NULL,
+    day DATE NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    product_id BIGINT NOT NULL,
+    product_name VARCHAR(255) NOT NULL DEFAULT '',
+    changes INTEGER NOT NULL DEFAULT 0,
+    last_at TIMESTAMP NOT NULL,
+    UNIQUE (store_id, day, event_type, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_changes_last_at ON catalog_changes(last_at);
@@ -0,0 +1,14 @@
+-- Rounding and display of computed prices, per store and currency. step
+-- and ending are in minor units.
+CREATE TABLE IF NOT EXISTS pricing_policies (
+    store_id BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    rounding VARCHAR(20) NOT NULL,
+    step BIGINT NOT NULL DEFAULT 0,
+    ending BIGINT NOT NULL DEFAULT 0,
+    direction VARCHAR(10) NOT NULL,
+    decimals SMALLINT NOT NULL DEFAULT 2,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (store_id, currency)
+);
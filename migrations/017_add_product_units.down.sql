@@ -0,0 +1,5 @@
+-- Fractional amounts are truncated to whole units.
+ALTER TABLE product_trash DROP COLUMN IF EXISTS unit;
+ALTER TABLE product_trash ALTER COLUMN amount TYPE INTEGER USING TRUNC(amount);
+ALTER TABLE products DROP COLUMN IF EXISTS unit;
+ALTER TABLE products ALTER COLUMN amount TYPE INTEGER USING TRUNC(amount);
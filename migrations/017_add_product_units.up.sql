@@ -0,0 +1,6 @@
+-- Products are counted in a unit, and weights and volumes in fractions of
+-- it down to a thousandth. Existing products are whole pieces.
+ALTER TABLE products ALTER COLUMN amount TYPE NUMERIC(15,3);
+ALTER TABLE products ADD COLUMN IF NOT EXISTS unit VARCHAR(10) NOT NULL DEFAULT 'piece';
+ALTER TABLE product_trash ALTER COLUMN amount TYPE NUMERIC(15,3);
+ALTER TABLE product_trash ADD COLUMN IF NOT EXISTS unit VARCHAR(10) NOT NULL DEFAULT 'piece';
@@ -0,0 +1,13 @@
+-- Products a bundle is made of, and how much of each goes into one bundle.
+-- A component cannot be deleted while a bundle uses it; deleting the bundle
+-- drops its components.
+CREATE TABLE IF NOT EXISTS bundle_components (
+    bundle_id BIGINT NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL REFERENCES products(id),
+    quantity NUMERIC(15,3) NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (bundle_id, product_id),
+    CHECK (bundle_id <> product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bundle_components_product_id ON bundle_components(product_id);
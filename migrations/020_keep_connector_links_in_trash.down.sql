@@ -0,0 +1,6 @@
+DELETE FROM connector_product_links l
+WHERE NOT EXISTS (SELECT 1 FROM products p WHERE p.id = l.product_id);
+
+ALTER TABLE connector_product_links
+    ADD CONSTRAINT connector_product_links_product_id_fkey
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE;
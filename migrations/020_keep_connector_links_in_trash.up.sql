@@ -0,0 +1,5 @@
+-- Moving a product to the trash deletes it from products, which cascaded to
+-- its connector links. Links now outlive the product row so a restored
+-- product stays linked; links to purged products are ignored and replaced
+-- on the next sync.
+ALTER TABLE connector_product_links DROP CONSTRAINT IF EXISTS connector_product_links_product_id_fkey;
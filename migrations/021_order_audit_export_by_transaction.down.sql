@@ -0,0 +1,3 @@
+ALTER TABLE audit_export_cursors DROP COLUMN IF EXISTS last_tx_id;
+DROP INDEX IF EXISTS idx_audit_logs_tx_id;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS tx_id;
@@ -0,0 +1,13 @@
+-- Audit exports follow commit order: entries are read by the transaction
+-- that wrote them, and only once every older transaction has finished, so a
+-- late commit can no longer fall behind an exported cursor. The statements
+-- share one transaction so existing rows and cursors get the same tx_id.
+BEGIN;
+
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS tx_id BIGINT NOT NULL DEFAULT (pg_current_xact_id()::text::bigint);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_tx_id ON audit_logs(tx_id, id);
+
+ALTER TABLE audit_export_cursors ADD COLUMN IF NOT EXISTS last_tx_id BIGINT NOT NULL DEFAULT 0;
+UPDATE audit_export_cursors SET last_tx_id = pg_current_xact_id()::text::bigint;
+
+COMMIT;
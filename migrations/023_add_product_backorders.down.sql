@@ -0,0 +1,7 @@
+-- Backordered products keep their negative amount.
+ALTER TABLE product_trash DROP COLUMN IF EXISTS preorder_release_date;
+ALTER TABLE product_trash DROP COLUMN IF EXISTS backorder_limit;
+ALTER TABLE product_trash DROP COLUMN IF EXISTS allow_backorder;
+ALTER TABLE products DROP COLUMN IF EXISTS preorder_release_date;
+ALTER TABLE products DROP COLUMN IF EXISTS backorder_limit;
+ALTER TABLE products DROP COLUMN IF EXISTS allow_backorder;
@@ -0,0 +1,9 @@
+-- Products that allow backorders can be sold past zero stock, down to
+-- -backorder_limit. A pre-order release date marks products sold ahead of
+-- their release; they are sold as backorders until then.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS allow_backorder BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS backorder_limit NUMERIC(15,3) NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS preorder_release_date TIMESTAMP NULL;
+ALTER TABLE product_trash ADD COLUMN IF NOT EXISTS allow_backorder BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE product_trash ADD COLUMN IF NOT EXISTS backorder_limit NUMERIC(15,3) NOT NULL DEFAULT 0;
+ALTER TABLE product_trash ADD COLUMN IF NOT EXISTS preorder_release_date TIMESTAMP NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_products_stock_availability;
+ALTER TABLE products DROP COLUMN IF EXISTS stock_availability;
+ALTER TABLE product_trash DROP COLUMN IF EXISTS low_stock_threshold;
+ALTER TABLE products DROP COLUMN IF EXISTS low_stock_threshold;
@@ -0,0 +1,20 @@
+-- A product in stock at or below its low stock threshold is low_stock; a
+-- threshold of 0 turns that off.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS low_stock_threshold NUMERIC(15,3) NOT NULL DEFAULT 0;
+ALTER TABLE product_trash ADD COLUMN IF NOT EXISTS low_stock_threshold NUMERIC(15,3) NOT NULL DEFAULT 0;
+
+-- stock_availability mirrors Product.StockAvailability and is kept up to
+-- date on every write, so the list endpoint can filter on it through an
+-- index. Pre-orders depend on the time of the query and are worked out
+-- there from preorder_release_date.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS stock_availability VARCHAR(20) GENERATED ALWAYS AS (
+    CASE
+        WHEN status = 'inactive' THEN 'discontinued'
+        WHEN amount > low_stock_threshold THEN 'in_stock'
+        WHEN amount > 0 THEN 'low_stock'
+        WHEN allow_backorder AND amount + backorder_limit > 0 THEN 'backorder'
+        ELSE 'out_of_stock'
+    END
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_stock_availability ON products(stock_availability, created_at DESC);
@@ -0,0 +1,51 @@
+-- Every write to products also saves the row as written here, and deletes
+-- save it once more as a tombstone, so the catalog can be compared between
+-- any two points in time. Columns mirror products; keep them in sync when
+-- products changes.
+CREATE TABLE IF NOT EXISTS product_revisions (
+    revision BIGSERIAL PRIMARY KEY,
+    id BIGINT NOT NULL,
+    store_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    description_format VARCHAR(20) NOT NULL DEFAULT 'plain',
+    amount NUMERIC(15,3) NOT NULL DEFAULT 0,
+    unit VARCHAR(10) NOT NULL DEFAULT 'piece',
+    price NUMERIC(12,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    moderation_status VARCHAR(20) NOT NULL DEFAULT 'approved',
+    moderation_reason TEXT,
+    allow_backorder BOOLEAN NOT NULL DEFAULT FALSE,
+    backorder_limit NUMERIC(15,3) NOT NULL DEFAULT 0,
+    preorder_release_date TIMESTAMP NULL,
+    low_stock_threshold NUMERIC(15,3) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    deleted BOOLEAN NOT NULL DEFAULT FALSE,
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_revisions_recorded_at ON product_revisions(recorded_at);
+CREATE INDEX IF NOT EXISTS idx_product_revisions_id ON product_revisions(id, recorded_at DESC, revision DESC);
+
+-- Existing products start with their current row, and trashed products
+-- with their last row and a tombstone from when they were trashed.
+INSERT INTO product_revisions (id, store_id, name, description, description_format, amount, unit, price, status,
+    moderation_status, moderation_reason, allow_backorder, backorder_limit, preorder_release_date, low_stock_threshold,
+    created_at, updated_at, deleted, recorded_at)
+SELECT id, store_id, name, description, description_format, amount, unit, price, status,
+    moderation_status, moderation_reason, allow_backorder, backorder_limit, preorder_release_date, low_stock_threshold,
+    created_at, updated_at, FALSE, COALESCE(updated_at, created_at, CURRENT_TIMESTAMP)
+FROM products;
+
+INSERT INTO product_revisions (id, store_id, name, description, description_format, amount, unit, price, status,
+    moderation_status, moderation_reason, allow_backorder, backorder_limit, preorder_release_date, low_stock_threshold,
+    created_at, updated_at, deleted, recorded_at)
+SELECT id, store_id, name, description, description_format, amount, unit, price, status,
+    moderation_status, moderation_reason, allow_backorder, backorder_limit, preorder_release_date, low_stock_threshold,
+    created_at, updated_at, deleted, recorded_at
+FROM product_trash
+CROSS JOIN LATERAL (VALUES
+    (FALSE, LEAST(COALESCE(updated_at, created_at, trashed_at), trashed_at)),
+    (TRUE, trashed_at)
+) AS tombstone(deleted, recorded_at);
@@ -0,0 +1,2 @@
+ALTER TABLE products DROP CONSTRAINT IF EXISTS fk_products_store_id;
+DROP TABLE IF EXISTS stores;
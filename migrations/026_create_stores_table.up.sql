@@ -0,0 +1,23 @@
+-- Stores own products. Every store a product, trashed product or revision
+-- already refers to is created with a placeholder name, and products can
+-- then only refer to stores that exist. A store cannot be deleted while it
+-- has products.
+CREATE TABLE IF NOT EXISTS stores (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO stores (id, name)
+SELECT store_id, 'Store ' || store_id
+FROM (
+    SELECT store_id FROM products
+    UNION SELECT store_id FROM product_trash
+    UNION SELECT store_id FROM product_revisions
+) AS referenced
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval('stores_id_seq', COALESCE(MAX(id), 1), MAX(id) IS NOT NULL) FROM stores;
+
+ALTER TABLE products ADD CONSTRAINT fk_products_store_id FOREIGN KEY (store_id) REFERENCES stores(id);
@@ -0,0 +1,34 @@
+-- Reconciliation runs compare a store's catalog with a feed or connector,
+-- the external source of truth, and may heal what differs.
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id BIGSERIAL PRIMARY KEY,
+    store_id BIGINT NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    source_id BIGINT NOT NULL,
+    policy VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    checked INTEGER NOT NULL DEFAULT 0,
+    discrepancies INTEGER NOT NULL DEFAULT 0,
+    healed INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reconciliation_runs_started_at ON reconciliation_runs(started_at DESC, id DESC);
+
+-- The discrepancies found by a run, up to the number a run keeps.
+CREATE TABLE IF NOT EXISTS reconciliation_discrepancies (
+    id BIGSERIAL PRIMARY KEY,
+    run_id BIGINT NOT NULL REFERENCES reconciliation_runs(id) ON DELETE CASCADE,
+    product_id BIGINT,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    field VARCHAR(50) NOT NULL DEFAULT '',
+    local_value TEXT NOT NULL DEFAULT '',
+    source_value TEXT NOT NULL DEFAULT '',
+    healed BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_reconciliation_discrepancies_run_id ON reconciliation_discrepancies(run_id, id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS catalog_restores;
+DROP TABLE IF EXISTS catalog_snapshots;
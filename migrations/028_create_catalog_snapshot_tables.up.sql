@@ -0,0 +1,33 @@
+-- Catalog snapshots are a store's products at one point in time. The
+-- products are kept in object storage; this table indexes them.
+CREATE TABLE IF NOT EXISTS catalog_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    store_id BIGINT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    object_key TEXT NOT NULL UNIQUE,
+    products INTEGER NOT NULL DEFAULT 0,
+    taken_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_snapshots_store_id ON catalog_snapshots(store_id, taken_at DESC, id DESC);
+
+-- Restores roll a store's catalog back to a snapshot in the background.
+CREATE TABLE IF NOT EXISTS catalog_restores (
+    id BIGSERIAL PRIMARY KEY,
+    store_id BIGINT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    snapshot_id BIGINT NOT NULL REFERENCES catalog_snapshots(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    created INTEGER NOT NULL DEFAULT 0,
+    updated INTEGER NOT NULL DEFAULT 0,
+    deleted INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_restores_created_at ON catalog_restores(created_at);
+
+-- One restore at a time per store.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_catalog_restores_in_progress ON catalog_restores(store_id)
+    WHERE status IN ('pending', 'running');
@@ -0,0 +1,3 @@
+ALTER TABLE product_feeds DROP CONSTRAINT IF EXISTS fk_product_feeds_mapping;
+ALTER TABLE product_feeds DROP COLUMN IF EXISTS mapping_id;
+DROP TABLE IF EXISTS import_mappings;
@@ -0,0 +1,17 @@
+-- Import mappings tell the feed pipeline how to read a supplier's CSV. A
+-- feed may be read with one of its own store's mappings, and a mapping
+-- cannot be deleted while a feed uses it.
+CREATE TABLE IF NOT EXISTS import_mappings (
+    id BIGSERIAL PRIMARY KEY,
+    store_id BIGINT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    columns JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (store_id, name),
+    UNIQUE (store_id, id)
+);
+
+ALTER TABLE product_feeds ADD COLUMN IF NOT EXISTS mapping_id BIGINT;
+ALTER TABLE product_feeds ADD CONSTRAINT fk_product_feeds_mapping
+    FOREIGN KEY (store_id, mapping_id) REFERENCES import_mappings(store_id, id);
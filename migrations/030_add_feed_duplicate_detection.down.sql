@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS feed_product_links;
+ALTER TABLE product_feed_runs DROP COLUMN IF EXISTS duplicates;
+ALTER TABLE product_feed_runs DROP COLUMN IF EXISTS skipped;
+ALTER TABLE product_feeds DROP COLUMN IF EXISTS on_duplicate;
+ALTER TABLE product_feeds DROP COLUMN IF EXISTS match_by;
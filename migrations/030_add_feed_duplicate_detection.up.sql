@@ -0,0 +1,34 @@
+-- Feeds match their rows to products already in the catalog with the
+-- rules in match_by, tried in order, and resolve a match with on_duplicate
+-- unless the row names its own resolution. Runs report every duplicate
+-- they detected.
+ALTER TABLE product_feeds ADD COLUMN IF NOT EXISTS match_by JSONB NOT NULL DEFAULT '["name"]';
+ALTER TABLE product_feeds ADD COLUMN IF NOT EXISTS on_duplicate VARCHAR(10) NOT NULL DEFAULT 'update';
+ALTER TABLE product_feed_runs ADD COLUMN IF NOT EXISTS skipped INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE product_feed_runs ADD COLUMN IF NOT EXISTS duplicates JSONB NOT NULL DEFAULT '[]';
+
+-- A link ties a feed row, by its SKU, barcode or name, to the product it
+-- imported, so later runs update that product without detecting it as a
+-- duplicate again. Like connector links, links outlive the product row so
+-- a restored product stays linked; links to missing products are ignored
+-- and replaced.
+CREATE TABLE IF NOT EXISTS feed_product_links (
+    feed_id INTEGER NOT NULL REFERENCES product_feeds(id) ON DELETE CASCADE,
+    item_key VARCHAR(255) NOT NULL,
+    product_id BIGINT NOT NULL,
+    sku VARCHAR(100),
+    barcode VARCHAR(50),
+    linked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (feed_id, item_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_feed_product_links_product_id ON feed_product_links(product_id);
+
+-- Feeds matched rows to products by name until now; existing feeds keep
+-- those matches without reporting them as duplicates.
+INSERT INTO feed_product_links (feed_id, item_key, product_id)
+SELECT DISTINCT ON (f.id, p.name) f.id, 'name:' || p.name, p.id
+FROM product_feeds f
+JOIN products p ON p.store_id = f.store_id
+ORDER BY f.id, p.name, p.id
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS feed_image_imports;
+ALTER TABLE product_feed_runs DROP COLUMN IF EXISTS images_queued;
+DROP TABLE IF EXISTS product_images;
@@ -0,0 +1,35 @@
+-- Product images are stored once per content hash in object storage under
+-- object_key; a product lists each image content once. Like feed links,
+-- images outlive the product row so a restored product keeps them.
+CREATE TABLE IF NOT EXISTS product_images (
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    content_hash CHAR(64) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    object_key TEXT NOT NULL,
+    source_url TEXT,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, content_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_images_content_hash ON product_images(content_hash);
+
+-- Feed runs queue the image URLs of their rows and download them in the
+-- background; each URL's outcome is kept with the run.
+ALTER TABLE product_feed_runs ADD COLUMN IF NOT EXISTS images_queued INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS feed_image_imports (
+    id BIGSERIAL PRIMARY KEY,
+    run_id INTEGER NOT NULL REFERENCES product_feed_runs(id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL,
+    url TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    image_id BIGINT,
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_feed_image_imports_run_id ON feed_image_imports(run_id, id);
@@ -0,0 +1,9 @@
+-- Storefront events per product, day and type, written by the analytics
+-- recorder.
+CREATE TABLE IF NOT EXISTS product_analytics_daily (
+    product_id BIGINT NOT NULL,
+    day DATE NOT NULL,
+    event_type VARCHAR(20) NOT NULL,
+    events BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (product_id, day, event_type)
+);
@@ -0,0 +1,23 @@
+-- Orders for a store's products. Items keep the product's name, unit and
+-- price at the time of the order, and do not refer to products so that
+-- products can still be trashed.
+CREATE TABLE IF NOT EXISTS orders (
+    id BIGSERIAL PRIMARY KEY,
+    store_id BIGINT NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'placed',
+    total NUMERIC(14,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_store_id ON orders(store_id, id);
+
+CREATE TABLE IF NOT EXISTS order_items (
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    quantity NUMERIC(15,3) NOT NULL CHECK (quantity > 0),
+    unit VARCHAR(10) NOT NULL,
+    unit_price NUMERIC(12,2) NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (order_id, product_id)
+);
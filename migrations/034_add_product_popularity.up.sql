@@ -0,0 +1,6 @@
+-- A product's popularity score, derived from its analytics counts and
+-- refreshed periodically, for sort=popularity. It is not mirrored in
+-- product_trash: a restored product scores again on the next refresh.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS popularity_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_products_popularity ON products(popularity_score DESC, id DESC);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_products_unpublish_at;
+DROP INDEX IF EXISTS idx_products_publish_at;
+ALTER TABLE product_revisions DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE product_revisions DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE product_trash DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE product_trash DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE products DROP COLUMN IF EXISTS unpublish_at;
+ALTER TABLE products DROP COLUMN IF EXISTS publish_at;
@@ -0,0 +1,12 @@
+-- A product is listed from publish_at until unpublish_at; NULL leaves that
+-- end of the window open. The partial indexes serve the publishing
+-- scheduler, which looks for windows opening or closing since its last run.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP NULL;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP NULL;
+ALTER TABLE product_trash ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP NULL;
+ALTER TABLE product_trash ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP NULL;
+ALTER TABLE product_revisions ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP NULL;
+ALTER TABLE product_revisions ADD COLUMN IF NOT EXISTS unpublish_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_publish_at ON products(publish_at) WHERE publish_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_products_unpublish_at ON products(unpublish_at) WHERE unpublish_at IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS detail;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS event;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS scopes;
@@ -0,0 +1,8 @@
+-- Scopes grant an API key's holder more than managing its stores; the
+-- "bulk" scope lets it confirm mutations that touch many rows at once.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{}';
+
+-- Use cases record audit events that are not tied to a single request,
+-- such as the request and confirmation of a bulk operation.
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS event VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS detail TEXT NOT NULL DEFAULT '';
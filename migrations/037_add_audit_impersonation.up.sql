@@ -0,0 +1,3 @@
+-- Requests an admin makes while impersonating a user are recorded as the
+-- user, with the admin named here.
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS impersonated_by VARCHAR(100) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE product_revisions DROP COLUMN IF EXISTS version;
+ALTER TABLE product_trash DROP COLUMN IF EXISTS version;
+ALTER TABLE products DROP COLUMN IF EXISTS version;
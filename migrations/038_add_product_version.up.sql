@@ -0,0 +1,6 @@
+-- version goes up with every write to a product, so an update made against
+-- an older version can be refused instead of overwriting the newer one.
+-- Trashed products and revisions keep the version they had.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE product_trash ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE product_revisions ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,10 @@
+-- Settings operators change while the service runs: feature flags, the rate
+-- limit, maintenance mode and each store's settings. version goes up with
+-- every change, so a change made against an older version is refused.
+CREATE TABLE IF NOT EXISTS live_config (
+    key VARCHAR(100) PRIMARY KEY,
+    value JSONB NOT NULL,
+    version BIGINT NOT NULL DEFAULT 1,
+    updated_by VARCHAR(255) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
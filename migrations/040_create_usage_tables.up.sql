@@ -0,0 +1,24 @@
+-- Billable API calls per store and hour, added to by every instance.
+CREATE TABLE IF NOT EXISTS usage_api_calls (
+    store_id BIGINT NOT NULL,
+    hour TIMESTAMP NOT NULL,
+    calls BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (store_id, hour)
+);
+
+-- Closed hours of metered usage per store, emitted to billing. A record is
+-- claimed by the instance sending it until claimed_until, and emitted once
+-- billing accepted it.
+CREATE TABLE IF NOT EXISTS usage_records (
+    store_id BIGINT NOT NULL,
+    hour TIMESTAMP NOT NULL,
+    api_calls BIGINT NOT NULL DEFAULT 0,
+    storage_bytes BIGINT NOT NULL DEFAULT 0,
+    product_count BIGINT NOT NULL DEFAULT 0,
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    claimed_until TIMESTAMP NULL,
+    emitted_at TIMESTAMP NULL,
+    PRIMARY KEY (store_id, hour)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_records_pending ON usage_records(hour) WHERE emitted_at IS NULL;
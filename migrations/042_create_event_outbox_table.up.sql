@@ -0,0 +1,13 @@
+-- Product events written in the transaction of the change that raised
+-- them. The relay publishes them in id order; sent_at is NULL until then.
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id VARCHAR(36) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    sent_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_outbox_pending ON event_outbox(id) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_event_outbox_sent_at ON event_outbox(sent_at);
@@ -0,0 +1,13 @@
+-- Each store's products counted by stock_availability, recalculated by a
+-- background job after its products change. Pre-orders are counted by
+-- their stock, as in stock_availability.
+CREATE TABLE IF NOT EXISTS store_stock_summaries (
+    store_id BIGINT PRIMARY KEY REFERENCES stores(id) ON DELETE CASCADE,
+    products BIGINT NOT NULL DEFAULT 0,
+    in_stock BIGINT NOT NULL DEFAULT 0,
+    low_stock BIGINT NOT NULL DEFAULT 0,
+    backorder BIGINT NOT NULL DEFAULT 0,
+    out_of_stock BIGINT NOT NULL DEFAULT 0,
+    discontinued BIGINT NOT NULL DEFAULT 0,
+    calculated_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,25 @@
+-- Categories form a tree: a category without a parent is a root, and one
+-- cannot be deleted while it has subcategories. Sibling names are unique,
+-- ignoring case.
+CREATE TABLE IF NOT EXISTS categories (
+    id BIGSERIAL PRIMARY KEY,
+    parent_id BIGINT REFERENCES categories(id),
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_parent_name ON categories (COALESCE(parent_id, 0), LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+-- Links outlive the product row, as connector links do, so a product
+-- restored from the trash keeps its categories; links to purged products
+-- are never listed. Deleting a category unlinks its products.
+CREATE TABLE IF NOT EXISTS product_categories (
+    product_id BIGINT NOT NULL,
+    category_id BIGINT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (product_id, category_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_categories_category_id ON product_categories (category_id, product_id);
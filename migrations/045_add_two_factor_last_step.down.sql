@@ -0,0 +1 @@
+ALTER TABLE two_factor_enrollments DROP COLUMN IF EXISTS last_totp_step;
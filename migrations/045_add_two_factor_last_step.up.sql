@@ -0,0 +1,2 @@
+-- The last TOTP time step accepted, so a code cannot be used twice.
+ALTER TABLE two_factor_enrollments ADD COLUMN IF NOT EXISTS last_totp_step BIGINT;
@@ -0,0 +1,2 @@
+-- Response headers replayed to retries along with the body, such as Location.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS headers JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,8 @@
+// Package migrations embeds the SQL migrations in this directory, so the
+// service and cmd/migrate can apply them without the files on disk.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var Files embed.FS
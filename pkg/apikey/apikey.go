@@ -0,0 +1,109 @@
+package apikey
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lib/pq"
+)
+
+var ErrNotFound = errors.New("api key not found")
+
+// ScopeBulk lets a key's holder confirm mutations that touch many rows at
+// once.
+const ScopeBulk = "bulk"
+
+// Key is an issued API key. Only the SHA-256 hash of the key is stored.
+// StoreIDs are the stores the key's holder owns; Scopes grant it more than
+// managing them.
+type Key struct {
+	ID       int64
+	Name     string
+	Hash     string
+	StoreIDs []int64
+	Scopes   []string
+}
+
+// Owns reports whether the key's holder owns the store.
+func (k *Key) Owns(storeID int64) bool {
+	for _, id := range k.StoreIDs {
+		if id == storeID {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the key was granted scope.
+func (k *Key) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Store looks up issued keys by hash. Revoked keys are not found.
+type Store interface {
+	Lookup(ctx context.Context, hash string) (*Key, error)
+}
+
+// Hash returns the hex SHA-256 of a raw key, the form keys are stored in.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// PostgresStore reads keys from the api_keys table.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Lookup(ctx context.Context, hash string) (*Key, error) {
+	query := `SELECT id, name, key_hash, store_ids, scopes FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL`
+
+	var key Key
+	if err := s.db.QueryRowContext(ctx, query, hash).Scan(&key.ID, &key.Name, &key.Hash, pq.Array(&key.StoreIDs), pq.Array(&key.Scopes)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to look up api key: %w", err)
+	}
+	return &key, nil
+}
+
+// MemoryStore holds a fixed set of keys in process memory.
+type MemoryStore struct {
+	mu   sync.RWMutex
+	keys map[string]*Key
+}
+
+func NewMemoryStore(keys ...*Key) *MemoryStore {
+	s := &MemoryStore{keys: make(map[string]*Key, len(keys))}
+	for _, key := range keys {
+		s.keys[key.Hash] = key
+	}
+	return s
+}
+
+func (s *MemoryStore) Lookup(_ context.Context, hash string) (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[hash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *key
+	return &copied, nil
+}
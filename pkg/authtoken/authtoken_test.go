@@ -0,0 +1,112 @@
+package authtoken
+
+import (
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestIssuer_IssueVerify(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	issuer := NewIssuer(testSecret, "product-service", 15*time.Minute, 24*time.Hour, fake)
+
+	pair, err := issuer.Issue(42, "ada@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, fake.Now().Add(15*time.Minute), pair.AccessExpiresAt)
+	assert.Equal(t, fake.Now().Add(24*time.Hour), pair.RefreshExpiresAt)
+	assert.True(t, issuer.Issued(pair.AccessToken))
+
+	claims, err := issuer.Verify(pair.AccessToken, KindAccess)
+	require.NoError(t, err)
+	id, err := claims.UserID()
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+	assert.Equal(t, "ada@example.com", claims.Email)
+	assert.Empty(t, claims.ImpersonatedBy())
+
+	_, err = issuer.Verify(pair.RefreshToken, KindRefresh)
+	assert.NoError(t, err)
+
+	t.Run("wrong kind", func(t *testing.T) {
+		_, err := issuer.Verify(pair.RefreshToken, KindAccess)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		_, err = issuer.Verify(pair.AccessToken, KindRefresh)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("other secret", func(t *testing.T) {
+		other := NewIssuer([]byte("fedcba9876543210fedcba9876543210"), "product-service", time.Minute, time.Hour, fake)
+		_, err := other.Verify(pair.AccessToken, KindAccess)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("other issuer", func(t *testing.T) {
+		other := NewIssuer(testSecret, "billing-service", time.Minute, time.Hour, fake)
+		assert.False(t, other.Issued(pair.AccessToken))
+		_, err := other.Verify(pair.AccessToken, KindAccess)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired := clock.NewFake(fake.Now().Add(16 * time.Minute))
+		later := NewIssuer(testSecret, "product-service", 15*time.Minute, 24*time.Hour, expired)
+		_, err := later.Verify(pair.AccessToken, KindAccess)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		_, err = later.Verify(pair.RefreshToken, KindRefresh)
+		assert.NoError(t, err)
+	})
+}
+
+func TestIssuer_IssueImpersonation(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	issuer := NewIssuer(testSecret, "product-service", 15*time.Minute, 24*time.Hour, fake)
+
+	token, expiresAt, err := issuer.IssueImpersonation(42, "ada@example.com", "workload:support-console", 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, fake.Now().Add(5*time.Minute), expiresAt)
+
+	claims, err := issuer.Verify(token, KindAccess)
+	require.NoError(t, err)
+	id, err := claims.UserID()
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), id)
+	assert.Equal(t, "workload:support-console", claims.ImpersonatedBy())
+
+	fake.Advance(6 * time.Minute)
+	_, err = issuer.Verify(token, KindAccess)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, _, err = issuer.IssueImpersonation(42, "ada@example.com", "", 5*time.Minute)
+	assert.Error(t, err)
+}
+
+func TestIssuer_RejectsUnsignedTokens(t *testing.T) {
+	issuer := NewIssuer(testSecret, "product-service", time.Minute, time.Hour, clock.Real())
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, Claims{
+		Kind: KindAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "product-service",
+			Subject:   "1",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	assert.True(t, issuer.Issued(token))
+	_, err = issuer.Verify(token, KindAccess)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestIssuer_Issued(t *testing.T) {
+	issuer := NewIssuer(testSecret, "product-service", time.Minute, time.Hour, clock.Real())
+	assert.False(t, issuer.Issued("not-a-token"))
+	assert.False(t, issuer.Issued(""))
+}
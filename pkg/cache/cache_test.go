@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := New[int64, string](2, 0)
+	c.Set(1, "a")
+	c.Set(2, "b")
+
+	_, ok := c.Get(1)
+	require.True(t, ok)
+
+	c.Set(3, "c")
+
+	_, ok = c.Get(2)
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, ok = c.Get(1)
+	assert.True(t, ok)
+	_, ok = c.Get(3)
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_ExpiresEntries(t *testing.T) {
+	c := New[int64, string](10, time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Set(1, "a")
+	_, ok := c.Get(1)
+	require.True(t, ok)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = c.Get(1)
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRU_Hottest(t *testing.T) {
+	c := New[int64, string](10, 0)
+	for id := int64(1); id <= 3; id++ {
+		c.Set(id, "v")
+	}
+	for i := 0; i < 5; i++ {
+		c.Get(2)
+	}
+	for i := 0; i < 3; i++ {
+		c.Get(3)
+	}
+	c.Get(1)
+
+	assert.Equal(t, []int64{2, 3}, c.Hottest(2))
+	assert.Equal(t, []int64{2, 3, 1}, c.Hottest(10))
+}
+
+func TestHotKeyFile(t *testing.T) {
+	file := NewHotKeyFile(filepath.Join(t.TempDir(), "hot_keys.json"))
+
+	keys, err := file.Load()
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	require.NoError(t, file.Save([]int64{42, 7, 9}))
+
+	keys, err = file.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []int64{42, 7, 9}, keys)
+}
+
+func TestLRU_PinnedEntriesSurviveEviction(t *testing.T) {
+	c := New[int64, string](2, 0)
+	c.SetWithTTL(1, "hot", 0, true)
+	c.Set(2, "b")
+	c.Set(3, "c")
+
+	_, ok := c.Get(1)
+	assert.True(t, ok, "pinned entry should not be evicted")
+	_, ok = c.Get(2)
+	assert.False(t, ok)
+}
+
+func TestLRU_Promote(t *testing.T) {
+	c := New[int64, string](2, time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Set(1, "a")
+	c.Promote(1, time.Hour)
+	c.Set(2, "b")
+	c.Set(3, "c")
+
+	now = now.Add(30 * time.Minute)
+	_, ok := c.Get(1)
+	assert.True(t, ok, "promoted entry should be pinned with the extended ttl")
+
+	now = now.Add(time.Hour)
+	_, ok = c.Get(1)
+	assert.False(t, ok)
+}
+
+func TestLRU_LookupReportsExpiry(t *testing.T) {
+	c := New[int64, string](2, time.Minute)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	c.Set(1, "a")
+
+	_, ok, expired := c.Lookup(2)
+	assert.False(t, ok)
+	assert.False(t, expired)
+
+	now = now.Add(2 * time.Minute)
+	_, ok, expired = c.Lookup(1)
+	assert.False(t, ok)
+	assert.True(t, expired)
+
+	_, _, expired = c.Lookup(1)
+	assert.False(t, expired, "the expired entry is removed")
+}
+
+func TestStats(t *testing.T) {
+	stats := NewStats()
+	ctx := WithEndpoint(context.Background(), "GET /api/v1/products/:id")
+
+	stats.Hit(ctx, TierMemory, 100)
+	stats.Hit(ctx, TierMemory, 50)
+	stats.Miss(ctx, TierMemory, true)
+	stats.Miss(context.Background(), TierMemory, false)
+	stats.Invalidated(TierMemory)
+
+	assert.Equal(t, []EndpointStats{
+		{Endpoint: "GET /api/v1/products/:id", Tier: TierMemory, Hits: 2, Misses: 1, Expired: 1, BytesSaved: 150},
+		{Endpoint: EndpointOther, Tier: TierMemory, Misses: 1},
+	}, stats.Endpoints())
+	assert.InDelta(t, 2.0/3, stats.Endpoints()[0].HitRatio(), 1e-9)
+	assert.Zero(t, EndpointStats{}.HitRatio())
+	assert.Equal(t, map[string]int64{TierMemory: 1}, stats.Invalidations())
+
+	var disabled *Stats
+	disabled.Hit(ctx, TierMemory, 1)
+	disabled.Miss(ctx, TierMemory, false)
+	disabled.Invalidated(TierMemory)
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// HotKeyFile persists a list of hot keys between restarts so a fresh process
+// can prime its cache with the entries the previous one served most.
+type HotKeyFile struct {
+	path string
+}
+
+func NewHotKeyFile(path string) *HotKeyFile {
+	return &HotKeyFile{path: path}
+}
+
+// Load returns the saved keys. A missing file is not an error: it just means
+// there is nothing to prime yet.
+func (f *HotKeyFile) Load() ([]int64, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read hot keys: %w", err)
+	}
+
+	var keys []int64
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("decode hot keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Save writes the keys atomically so a crash mid-write never leaves a
+// truncated file behind.
+func (f *HotKeyFile) Save(keys []int64) error {
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf("encode hot keys: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".hot-keys-*")
+	if err != nil {
+		return fmt.Errorf("create hot keys file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write hot keys: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write hot keys: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("replace hot keys file: %w", err)
+	}
+	return nil
+}
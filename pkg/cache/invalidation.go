@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisInvalidator broadcasts evicted keys over a Redis channel, so a write
+// on one instance drops the entry from every other instance's LRU instead
+// of leaving it stale until its TTL runs out. Each message carries the
+// sender's origin so instances skip their own invalidations.
+type RedisInvalidator struct {
+	client       redis.UniversalClient
+	channel      string
+	origin       string
+	availability Availability
+}
+
+// Availability reports whether Redis can be reached.
+type Availability interface {
+	Available() bool
+}
+
+// NewRedisInvalidator takes the availability of client's Redis, or nil to
+// assume it is always available.
+func NewRedisInvalidator(client redis.UniversalClient, channel string, availability Availability) *RedisInvalidator {
+	origin := make([]byte, 8)
+	rand.Read(origin)
+	return &RedisInvalidator{client: client, channel: channel, origin: hex.EncodeToString(origin), availability: availability}
+}
+
+// Available reports whether invalidations can currently be exchanged with
+// the other instances.
+func (i *RedisInvalidator) Available() bool {
+	return i.availability == nil || i.availability.Available()
+}
+
+func (i *RedisInvalidator) Publish(ctx context.Context, key int64) error {
+	if err := i.client.Publish(ctx, i.channel, i.origin+":"+strconv.FormatInt(key, 10)).Err(); err != nil {
+		return fmt.Errorf("publish invalidation: %w", err)
+	}
+	return nil
+}
+
+// Subscribe calls evict for every key another instance invalidates until ctx
+// is done. Invalidations published while the subscription is down are lost,
+// which the LRU's TTL bounds.
+func (i *RedisInvalidator) Subscribe(ctx context.Context, evict func(key int64)) error {
+	sub := i.client.Subscribe(ctx, i.channel)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		return fmt.Errorf("subscribe to invalidations: %w", err)
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			origin, raw, found := strings.Cut(msg.Payload, ":")
+			if !found || origin == i.origin {
+				continue
+			}
+			if key, err := strconv.ParseInt(raw, 10, 64); err == nil {
+				evict(key)
+			}
+		}
+	}
+}
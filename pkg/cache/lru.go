@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LRU is a size-bounded, concurrency-safe cache with a per-entry TTL. It also
+// counts hits per key so callers can find out which entries are hottest.
+type LRU[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	items    map[K]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+	hits      int64
+	pinned    bool
+}
+
+// New returns an LRU holding at most capacity entries. A ttl of zero keeps
+// entries until they are evicted.
+func New[K comparable, V any](capacity int, ttl time.Duration) *LRU[K, V] {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &LRU[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		items:    make(map[K]*list.Element, capacity),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	value, ok, _ := c.Lookup(key)
+	return value, ok
+}
+
+// Lookup is Get that also reports whether a miss was an entry past its
+// ttl, which it removes.
+func (c *LRU[K, V]) Lookup(key K) (value V, ok bool, expired bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return value, false, false
+	}
+
+	e := elem.Value.(*entry[K, V])
+	if !e.expiresAt.IsZero() && c.now().After(e.expiresAt) {
+		c.removeElement(elem)
+		return value, false, true
+	}
+
+	e.hits++
+	c.order.MoveToFront(elem)
+	return e.value, true, false
+}
+
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl, false)
+}
+
+// SetWithTTL stores value with its own ttl. Pinned entries are skipped by
+// eviction while unpinned ones remain, which keeps hot items resident under
+// memory pressure; they still expire when their ttl runs out.
+func (c *LRU[K, V]) SetWithTTL(key K, value V, ttl time.Duration, pinned bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.value = value
+		e.expiresAt = expiresAt
+		e.pinned = pinned
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt, pinned: pinned})
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.evictionCandidate())
+	}
+}
+
+// Promote pins an existing entry and extends its expiry to ttl from now. It
+// is a no-op for missing or already pinned entries, so a hot entry's expiry
+// is extended once rather than on every read.
+func (c *LRU[K, V]) Promote(key K, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return
+	}
+
+	e := elem.Value.(*entry[K, V])
+	if e.pinned {
+		return
+	}
+	e.pinned = true
+	if ttl > 0 {
+		e.expiresAt = c.now().Add(ttl)
+	} else {
+		e.expiresAt = time.Time{}
+	}
+}
+
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// Purge drops every entry.
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[K]*list.Element, c.capacity)
+	c.order.Init()
+}
+
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// Hottest returns up to n keys ordered by hit count, most hit first.
+func (c *LRU[K, V]) Hottest(n int) []K {
+	c.mu.Lock()
+	entries := make([]*entry[K, V], 0, c.order.Len())
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, elem.Value.(*entry[K, V]))
+	}
+	c.mu.Unlock()
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].hits > entries[j].hits
+	})
+	if n > len(entries) {
+		n = len(entries)
+	}
+
+	keys := make([]K, 0, n)
+	for _, e := range entries[:n] {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+func (c *LRU[K, V]) evictionCandidate() *list.Element {
+	for elem := c.order.Back(); elem != nil; elem = elem.Prev() {
+		if !elem.Value.(*entry[K, V]).pinned {
+			return elem
+		}
+	}
+	return c.order.Back()
+}
+
+func (c *LRU[K, V]) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*entry[K, V]).key)
+}
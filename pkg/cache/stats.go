@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// TierMemory is the in-process LRU tier.
+const TierMemory = "memory"
+
+// EndpointStats are the lookups one endpoint made against one cache tier.
+type EndpointStats struct {
+	Endpoint string
+	Tier     string
+	Hits     int64
+	Misses   int64
+	// Expired counts misses that found an entry past its ttl.
+	Expired int64
+	// BytesSaved estimates the bytes hits kept from being read from the
+	// tier below.
+	BytesSaved int64
+}
+
+// HitRatio is hits over lookups, or zero before the first lookup.
+func (s EndpointStats) HitRatio() float64 {
+	if lookups := s.Hits + s.Misses; lookups > 0 {
+		return float64(s.Hits) / float64(lookups)
+	}
+	return 0
+}
+
+// Stats counts cache lookups per endpoint and tier, and invalidations per
+// tier. A nil Stats records nothing.
+type Stats struct {
+	mu            sync.Mutex
+	endpoints     map[[2]string]*EndpointStats
+	invalidations map[string]int64
+}
+
+func NewStats() *Stats {
+	return &Stats{
+		endpoints:     make(map[[2]string]*EndpointStats),
+		invalidations: make(map[string]int64),
+	}
+}
+
+func (s *Stats) Hit(ctx context.Context, tier string, bytes int64) {
+	s.record(ctx, tier, func(e *EndpointStats) {
+		e.Hits++
+		e.BytesSaved += bytes
+	})
+}
+
+func (s *Stats) Miss(ctx context.Context, tier string, expired bool) {
+	s.record(ctx, tier, func(e *EndpointStats) {
+		e.Misses++
+		if expired {
+			e.Expired++
+		}
+	})
+}
+
+func (s *Stats) Invalidated(tier string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.invalidations[tier]++
+}
+
+func (s *Stats) record(ctx context.Context, tier string, update func(*EndpointStats)) {
+	if s == nil {
+		return
+	}
+	endpoint := EndpointFromContext(ctx)
+	key := [2]string{endpoint, tier}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.endpoints[key]
+	if !ok {
+		e = &EndpointStats{Endpoint: endpoint, Tier: tier}
+		s.endpoints[key] = e
+	}
+	update(e)
+}
+
+// Endpoints returns the per-endpoint counts ordered by endpoint and tier.
+func (s *Stats) Endpoints() []EndpointStats {
+	s.mu.Lock()
+	stats := make([]EndpointStats, 0, len(s.endpoints))
+	for _, e := range s.endpoints {
+		stats = append(stats, *e)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Endpoint != stats[j].Endpoint {
+			return stats[i].Endpoint < stats[j].Endpoint
+		}
+		return stats[i].Tier < stats[j].Tier
+	})
+	return stats
+}
+
+// Invalidations returns the invalidation count per tier.
+func (s *Stats) Invalidations() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invalidations := make(map[string]int64, len(s.invalidations))
+	for tier, n := range s.invalidations {
+		invalidations[tier] = n
+	}
+	return invalidations
+}
+
+type endpointKey struct{}
+
+// EndpointOther is the endpoint lookups outside a request are counted
+// under, such as scheduled jobs.
+const EndpointOther = "other"
+
+// WithEndpoint tags ctx with the endpoint cache lookups made with it are
+// counted under.
+func WithEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+func EndpointFromContext(ctx context.Context) string {
+	if endpoint, ok := ctx.Value(endpointKey{}).(string); ok {
+		return endpoint
+	}
+	return EndpointOther
+}
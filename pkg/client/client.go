@@ -0,0 +1,229 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-context-engineering-template/pkg/quantity"
+)
+
+const defaultMaxRetries = 5
+
+type Product struct {
+	ID                int64             `json:"id"`
+	StoreID           int64             `json:"store_id"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	DescriptionFormat string            `json:"description_format,omitempty"`
+	Amount            quantity.Quantity `json:"amount"`
+	Unit              string            `json:"unit"`
+	Price             float64           `json:"price"`
+	Status            string            `json:"status"`
+	Version           int64             `json:"version"`
+	CreatedAt         string            `json:"created_at"`
+	UpdatedAt         string            `json:"updated_at"`
+}
+
+type ProductPage struct {
+	Products []Product `json:"products"`
+	Total    int       `json:"total"`
+	Limit    int       `json:"limit"`
+	Offset   int       `json:"offset"`
+	// NextCursor is sent as cursor for the next page. It is empty on the
+	// last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type APIError struct {
+	StatusCode int
+	Code       string `json:"error"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("api error %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("api error %d %s", e.StatusCode, e.Code)
+}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+}
+
+func New(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+		maxRetries: defaultMaxRetries,
+	}
+}
+
+func (c *Client) ListProducts(ctx context.Context, limit, offset int) (*ProductPage, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	return c.listProducts(ctx, query)
+}
+
+func (c *Client) listProducts(ctx context.Context, query url.Values) (*ProductPage, error) {
+	var page ProductPage
+	if err := c.do(ctx, http.MethodGet, "/api/v1/products?"+query.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// do sends a request and decodes the JSON response into out. 429 responses
+// are retried after the server's Retry-After delay, up to maxRetries times.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	return c.doWithHeader(ctx, method, path, nil, body, out)
+}
+
+func (c *Client) doWithHeader(ctx context.Context, method, path string, header http.Header, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.maxRetries {
+			wait := retryAfter(resp.Header.Get("Retry-After"), attempt)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+			continue
+		}
+
+		err = decodeResponse(resp, out)
+		resp.Body.Close()
+		return err
+	}
+}
+
+func decodeResponse(resp *http.Response, out interface{}) error {
+	if resp.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil {
+			apiErr.Code = http.StatusText(resp.StatusCode)
+		}
+		return apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func retryAfter(value string, attempt int) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+		return 0
+	}
+	return time.Duration(1<<attempt) * time.Second
+}
+
+type ProductInput struct {
+	StoreID           int64             `json:"store_id"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	DescriptionFormat string            `json:"description_format,omitempty"`
+	Amount            quantity.Quantity `json:"amount"`
+	Unit              string            `json:"unit,omitempty"`
+	Price             float64           `json:"price"`
+	Status            string            `json:"status,omitempty"`
+	Version           int64             `json:"version,omitempty"`
+}
+
+func (c *Client) CreateProduct(ctx context.Context, input ProductInput) (*Product, error) {
+	var product Product
+	if err := c.do(ctx, http.MethodPost, "/api/v1/products", input, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// UpdateProduct replaces the product. input.Version must be the version the
+// change was based on; the server refuses the update with 409 when the
+// product has changed since.
+func (c *Client) UpdateProduct(ctx context.Context, id int64, input ProductInput) (*Product, error) {
+	var product Product
+	if err := c.do(ctx, http.MethodPut, "/api/v1/products/"+strconv.FormatInt(id, 10), input, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (c *Client) DeleteProduct(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/products/"+strconv.FormatInt(id, 10), nil, nil)
+}
+
+func (c *Client) GetProduct(ctx context.Context, id int64) (*Product, error) {
+	var product Product
+	if err := c.do(ctx, http.MethodGet, "/api/v1/products/"+strconv.FormatInt(id, 10), nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// EmptyTrash permanently deletes the trashed products of a store and
+// returns how many were removed. Calling it confirms the mass operation;
+// trashes past the server's bulk threshold are refused with a 428
+// bulk_confirmation_required APIError, since they need a two-step
+// confirmation.
+func (c *Client) EmptyTrash(ctx context.Context, storeID int64) (int64, error) {
+	var result struct {
+		Deleted int64 `json:"deleted"`
+	}
+	header := http.Header{"X-Confirm-Mass-Operation": {"true"}}
+	if err := c.doWithHeader(ctx, http.MethodDelete, "/api/v1/trash?store_id="+strconv.FormatInt(storeID, 10), header, nil, &result); err != nil {
+		return 0, err
+	}
+	return result.Deleted, nil
+}
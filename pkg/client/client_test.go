@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func productServer(t *testing.T, total int, throttleFirst bool) (*httptest.Server, *int32) {
+	var throttled int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if throttleFirst && atomic.CompareAndSwapInt32(&throttled, 0, 1) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		query := r.URL.Query()
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		if cursor := query.Get("cursor"); cursor != "" {
+			offset, _ = strconv.Atoi(strings.TrimPrefix(cursor, "c"))
+		}
+
+		page := ProductPage{Products: []Product{}, Limit: limit, Offset: offset}
+		for id := offset + 1; id <= total && id <= offset+limit; id++ {
+			page.Products = append(page.Products, Product{ID: int64(id)})
+		}
+		page.Total = len(page.Products)
+		if page.Total == limit {
+			page.NextCursor = "c" + strconv.Itoa(offset+limit)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(page))
+	}))
+	return server, &throttled
+}
+
+func TestClient_ListAll(t *testing.T) {
+	tests := []struct {
+		name   string
+		total  int
+		filter ListFilter
+	}{
+		{name: "several pages", total: 25, filter: ListFilter{PageSize: 10}},
+		{name: "small pages", total: 47, filter: ListFilter{PageSize: 5}},
+		{name: "exact page boundary", total: 20, filter: ListFilter{PageSize: 10}},
+		{name: "empty", total: 0, filter: ListFilter{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := productServer(t, tt.total, false)
+			defer server.Close()
+
+			var ids []int64
+			for product, err := range New(server.URL, server.Client()).ListAll(context.Background(), tt.filter) {
+				require.NoError(t, err)
+				ids = append(ids, product.ID)
+			}
+
+			require.Len(t, ids, tt.total)
+			for i, id := range ids {
+				assert.Equal(t, int64(i+1), id)
+			}
+		})
+	}
+}
+
+func TestClient_ListAll_Filter(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		page := ProductPage{Products: []Product{{ID: 1}}, Limit: 1}
+		if r.URL.Query().Get("cursor") == "" {
+			page.NextCursor = "next"
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(page))
+	}))
+	defer server.Close()
+
+	filter := ListFilter{PageSize: 1, StoreID: 3, Name: "tea", CategoryID: 7}
+	for _, err := range New(server.URL, server.Client()).ListAll(context.Background(), filter) {
+		require.NoError(t, err)
+	}
+
+	require.Len(t, queries, 2)
+	for _, query := range queries {
+		assert.Equal(t, "3", query.Get("store_id"))
+		assert.Equal(t, "tea", query.Get("name"))
+		assert.Equal(t, "7", query.Get("category_id"))
+		assert.Empty(t, query.Get("offset"))
+	}
+	assert.Equal(t, "next", queries[1].Get("cursor"))
+}
+
+func TestClient_RetriesAfterTooManyRequests(t *testing.T) {
+	server, throttled := productServer(t, 3, true)
+	defer server.Close()
+
+	page, err := New(server.URL, server.Client()).ListProducts(context.Background(), 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, page.Products, 3)
+	assert.Equal(t, int32(1), atomic.LoadInt32(throttled))
+}
+
+func TestClient_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error":"internal_error","message":"boom"}`))
+	}))
+	defer server.Close()
+
+	var calls int
+	for _, err := range New(server.URL, server.Client()).ListAll(context.Background(), ListFilter{}) {
+		calls++
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "internal_error", apiErr.Code)
+	}
+	assert.Equal(t, 1, calls)
+}
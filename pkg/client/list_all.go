@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 100
+)
+
+// ListFilter narrows ListAll to the products GET /api/v1/products would
+// list with the same filters. Zero fields are not filtered on.
+type ListFilter struct {
+	PageSize int
+	StoreID  int64
+	// Name matches products whose name contains it, ignoring case.
+	Name string
+	// CategoryID matches products in the category or its subcategories.
+	CategoryID int64
+}
+
+func (f ListFilter) query() url.Values {
+	query := url.Values{}
+	if f.StoreID > 0 {
+		query.Set("store_id", strconv.FormatInt(f.StoreID, 10))
+	}
+	if f.Name != "" {
+		query.Set("name", f.Name)
+	}
+	if f.CategoryID > 0 {
+		query.Set("category_id", strconv.FormatInt(f.CategoryID, 10))
+	}
+	return query
+}
+
+// ListAll iterates over every product the filter matches, following each
+// page's next_cursor until a page has none. Pages are fetched one after
+// another, since each cursor comes from the page before it. Iteration stops
+// at the first error.
+func (c *Client) ListAll(ctx context.Context, filter ListFilter) iter.Seq2[Product, error] {
+	pageSize := filter.PageSize
+	if pageSize <= 0 || pageSize > maxPageSize {
+		pageSize = defaultPageSize
+	}
+
+	return func(yield func(Product, error) bool) {
+		query := filter.query()
+		query.Set("limit", strconv.Itoa(pageSize))
+		for {
+			page, err := c.listProducts(ctx, query)
+			if err != nil {
+				yield(Product{}, err)
+				return
+			}
+			for _, product := range page.Products {
+				if !yield(product, nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			query.Set("cursor", page.NextCursor)
+		}
+	}
+}
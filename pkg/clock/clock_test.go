@@ -0,0 +1,51 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake_Advance(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+
+	ticker := c.NewTicker(time.Minute)
+	c.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(30*time.Second), c.Now())
+	assert.Empty(t, ticker.C())
+
+	c.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(time.Minute), <-ticker.C())
+
+	// The reader is behind, so only the first of two due ticks is kept.
+	c.Advance(2 * time.Minute)
+	assert.Equal(t, start.Add(2*time.Minute), <-ticker.C())
+	assert.Empty(t, ticker.C())
+
+	ticker.Stop()
+	c.Advance(time.Hour)
+	assert.Empty(t, ticker.C())
+}
+
+func TestFake_BlockUntilTickers(t *testing.T) {
+	c := NewFake(time.Now())
+	started := make(chan Ticker)
+	go func() { started <- c.NewTicker(time.Second) }()
+
+	c.BlockUntilTickers(1)
+	ticker := <-started
+	c.Advance(time.Second)
+	<-ticker.C()
+	ticker.Stop()
+}
+
+func TestReal(t *testing.T) {
+	c := Real()
+	assert.WithinDuration(t, time.Now(), c.Now(), time.Second)
+
+	ticker := c.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	<-ticker.C()
+}
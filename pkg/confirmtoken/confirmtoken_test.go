@@ -0,0 +1,70 @@
+package confirmtoken
+
+import (
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestSigner_IssueVerify(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	signer := NewSigner(testSecret, fake)
+
+	token, id, expiresAt, err := signer.Issue("key:abc", "empty_trash store=3 rows=120", 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, fake.Now().Add(10*time.Minute), expiresAt)
+
+	verified, err := signer.Verify(token, "key:abc", "empty_trash store=3 rows=120")
+	require.NoError(t, err)
+	assert.Equal(t, id, verified)
+
+	t.Run("unique IDs", func(t *testing.T) {
+		_, other, _, err := signer.Issue("key:abc", "empty_trash store=3 rows=120", 10*time.Minute)
+		require.NoError(t, err)
+		assert.NotEqual(t, id, other)
+	})
+
+	t.Run("other caller", func(t *testing.T) {
+		_, err := signer.Verify(token, "key:def", "empty_trash store=3 rows=120")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("other operation", func(t *testing.T) {
+		_, err := signer.Verify(token, "key:abc", "empty_trash store=3 rows=121")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("other secret", func(t *testing.T) {
+		other := NewSigner([]byte("fedcba9876543210fedcba9876543210"), fake)
+		_, err := other.Verify(token, "key:abc", "empty_trash store=3 rows=120")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		later := NewSigner(testSecret, clock.NewFake(fake.Now().Add(11*time.Minute)))
+		_, err := later.Verify(token, "key:abc", "empty_trash store=3 rows=120")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("other kind", func(t *testing.T) {
+		preview, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+			Kind:      "preview",
+			Operation: "empty_trash store=3 rows=120",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        "1",
+				Subject:   "key:abc",
+				ExpiresAt: jwt.NewNumericDate(fake.Now().Add(time.Hour)),
+			},
+		}).SignedString(testSecret)
+		require.NoError(t, err)
+		_, err = signer.Verify(preview, "key:abc", "empty_trash store=3 rows=120")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
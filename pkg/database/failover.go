@@ -0,0 +1,211 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+// Values of Config.TargetSessionAttrs, as in libpq: which of the listed
+// hosts a connection may be made to.
+const (
+	TargetAny       = "any"
+	TargetReadWrite = "read-write"
+	TargetReadOnly  = "read-only"
+)
+
+// ErrNoHost is returned when none of the listed hosts accepted a
+// connection of the target kind, as while a primary is failing over.
+var ErrNoHost = errors.New("no database host is available")
+
+// FailoverConnector connects to the first of several Postgres hosts that
+// accepts a connection of the target kind, trying the one it last reached
+// first. With TargetReadWrite, connections follow the primary to whichever
+// host is promoted when it fails, while the pool discards the connections
+// the old primary dropped.
+type FailoverConnector struct {
+	hosts      []string
+	connectors []driver.Connector
+	target     string
+	clock      clock.Clock
+	logger     *logrus.Logger
+
+	mu           sync.Mutex
+	current      int
+	failingSince time.Time
+}
+
+func newFailoverConnector(hosts []string, connectors []driver.Connector, target string, clk clock.Clock, logger *logrus.Logger) *FailoverConnector {
+	return &FailoverConnector{
+		hosts:      hosts,
+		connectors: connectors,
+		target:     target,
+		clock:      clk,
+		logger:     logger,
+	}
+}
+
+func (c *FailoverConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.mu.Lock()
+	start := c.current
+	c.mu.Unlock()
+
+	var errs []error
+	for i := range c.connectors {
+		index := (start + i) % len(c.connectors)
+		conn, err := c.connect(ctx, index)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.hosts[index], err))
+			continue
+		}
+
+		c.mu.Lock()
+		moved := c.current != index
+		c.current = index
+		c.failingSince = time.Time{}
+		c.mu.Unlock()
+		if moved {
+			c.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"from": c.hosts[start],
+				"to":   c.hosts[index],
+			}).Warn("Database connections moved to another host")
+		}
+		return conn, nil
+	}
+
+	if ctx.Err() == nil {
+		c.mu.Lock()
+		if c.failingSince.IsZero() {
+			c.failingSince = c.clock.Now()
+		}
+		c.mu.Unlock()
+	}
+	return nil, fmt.Errorf("%w: %w", ErrNoHost, errors.Join(errs...))
+}
+
+func (c *FailoverConnector) Driver() driver.Driver {
+	return c.connectors[0].Driver()
+}
+
+// connect opens a connection to the host at index and checks it is of the
+// target kind the way libpq does, by whether it takes writes.
+func (c *FailoverConnector) connect(ctx context.Context, index int) (driver.Conn, error) {
+	conn, err := c.connectors[index].Connect(ctx)
+	if err != nil || c.target == TargetAny {
+		return conn, err
+	}
+
+	readOnly, err := transactionReadOnly(ctx, conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	switch {
+	case c.target == TargetReadWrite && readOnly:
+		conn.Close()
+		return nil, errors.New("host is read-only")
+	case c.target == TargetReadOnly && !readOnly:
+		conn.Close()
+		return nil, errors.New("host takes writes")
+	}
+	return conn, nil
+}
+
+func transactionReadOnly(ctx context.Context, conn driver.Conn) (bool, error) {
+	queryer, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return false, errors.New("driver cannot query the session's read-only state")
+	}
+	rows, err := queryer.QueryContext(ctx, "SHOW transaction_read_only", nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if host is read-only: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, 1)
+	if err := rows.Next(values); err != nil {
+		return false, fmt.Errorf("failed to check if host is read-only: %w", err)
+	}
+	switch v := values[0].(type) {
+	case []byte:
+		return string(v) == "on", nil
+	case string:
+		return v == "on", nil
+	}
+	return false, fmt.Errorf("unexpected transaction_read_only value %v", values[0])
+}
+
+// Host returns the host connections were last made to.
+func (c *FailoverConnector) Host() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hosts[c.current]
+}
+
+// FailingOver reports whether no host has accepted a connection for less
+// than window: long enough ago to be a failover under way, not so long
+// that the database should be counted as down.
+func (c *FailoverConnector) FailingOver(window time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.failingSince.IsZero() && c.clock.Now().Sub(c.failingSince) < window
+}
+
+// parseHosts pairs a libpq-style comma-separated host list with its ports:
+// one port for every host, or one per host.
+func parseHosts(hostList, portList string) ([]string, []string, error) {
+	hosts := strings.Split(hostList, ",")
+	ports := strings.Split(portList, ",")
+	if len(ports) != 1 && len(ports) != len(hosts) {
+		return nil, nil, fmt.Errorf("%d ports given for %d hosts; give one port, or one per host", len(ports), len(hosts))
+	}
+	for i := range hosts {
+		hosts[i] = strings.TrimSpace(hosts[i])
+		if hosts[i] == "" {
+			return nil, nil, fmt.Errorf("empty host in %q", hostList)
+		}
+	}
+	if len(ports) == 1 {
+		for len(ports) < len(hosts) {
+			ports = append(ports, ports[0])
+		}
+	}
+	for i := range ports {
+		ports[i] = strings.TrimSpace(ports[i])
+	}
+	return hosts, ports, nil
+}
+
+// IsConnectionError reports whether err means the connection to Postgres
+// was lost or could not be made, so the same statement may succeed on a
+// new connection, as after a failover.
+func IsConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrNoHost) || errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "57P01", "57P02", "57P03": // admin_shutdown, crash_shutdown, cannot_connect_now
+			return true
+		}
+		return pqErr.Code.Class() == "08"
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hostConnector stands in for one Postgres host: down refuses
+// connections, and readOnly is what SHOW transaction_read_only answers.
+type hostConnector struct {
+	down     bool
+	readOnly bool
+}
+
+func (c *hostConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	if c.down {
+		return nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	}
+	return &hostConn{readOnly: c.readOnly}, nil
+}
+
+func (c *hostConnector) Driver() driver.Driver {
+	return nil
+}
+
+type hostConn struct {
+	fakeConn
+	readOnly bool
+}
+
+func (c *hostConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	value := "off"
+	if c.readOnly {
+		value = "on"
+	}
+	return &settingRows{value: value}, nil
+}
+
+type settingRows struct {
+	value string
+	read  bool
+}
+
+func (r *settingRows) Columns() []string { return []string{"transaction_read_only"} }
+func (r *settingRows) Close() error      { return nil }
+
+func (r *settingRows) Next(dest []driver.Value) error {
+	if r.read {
+		return io.EOF
+	}
+	r.read = true
+	dest[0] = []byte(r.value)
+	return nil
+}
+
+func TestFailoverConnector_FollowsPrimary(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	first, second := &hostConnector{}, &hostConnector{readOnly: true}
+	connector := newFailoverConnector([]string{"pg1:5432", "pg2:5432"}, []driver.Connector{first, second}, TargetReadWrite, fake, logrus.New())
+
+	conn, err := connector.Connect(ctx)
+	require.NoError(t, err)
+	conn.Close()
+	assert.Equal(t, "pg1:5432", connector.Host())
+
+	// The primary fails and the standby is not promoted yet.
+	first.down = true
+	_, err = connector.Connect(ctx)
+	assert.ErrorIs(t, err, ErrNoHost)
+	assert.ErrorContains(t, err, "pg2:5432: host is read-only")
+	fake.Advance(10 * time.Second)
+	assert.True(t, connector.FailingOver(30*time.Second))
+	assert.False(t, connector.FailingOver(5*time.Second), "failing for longer than the window is down")
+
+	second.readOnly = false
+	conn, err = connector.Connect(ctx)
+	require.NoError(t, err)
+	conn.Close()
+	assert.Equal(t, "pg2:5432", connector.Host())
+	assert.False(t, connector.FailingOver(30*time.Second))
+
+	// The old primary comes back as a standby; connections stay put.
+	first.down, first.readOnly = false, true
+	conn, err = connector.Connect(ctx)
+	require.NoError(t, err)
+	conn.Close()
+	assert.Equal(t, "pg2:5432", connector.Host())
+}
+
+func TestFailoverConnector_TargetAny(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	connector := newFailoverConnector([]string{"pg1:5432", "pg2:5432"},
+		[]driver.Connector{&hostConnector{down: true}, &hostConnector{readOnly: true}}, TargetAny, fake, logrus.New())
+
+	conn, err := connector.Connect(context.Background())
+	require.NoError(t, err)
+	conn.Close()
+	assert.Equal(t, "pg2:5432", connector.Host())
+}
+
+func TestParseHosts(t *testing.T) {
+	hosts, ports, err := parseHosts("pg1, pg2", "5432")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pg1", "pg2"}, hosts)
+	assert.Equal(t, []string{"5432", "5432"}, ports)
+
+	_, ports, err = parseHosts("pg1,pg2", "5432,5433")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5432", "5433"}, ports)
+
+	_, _, err = parseHosts("pg1,pg2,pg3", "5432,5433")
+	assert.Error(t, err)
+	_, _, err = parseHosts("pg1,", "5432")
+	assert.Error(t, err)
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: nil, want: false},
+		{err: fmt.Errorf("failed to get product: %w", driver.ErrBadConn), want: true},
+		{err: fmt.Errorf("failed to get product: %w", ErrNoHost), want: true},
+		{err: io.ErrUnexpectedEOF, want: true},
+		{err: &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}, want: true},
+		{err: &pq.Error{Code: "57P01"}, want: true},
+		{err: &pq.Error{Code: "08006"}, want: true},
+		{err: &pq.Error{Code: "23505"}, want: false},
+		{err: context.Canceled, want: false},
+		{err: context.DeadlineExceeded, want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsConnectionError(tt.err), "%v", tt.err)
+	}
+}
@@ -1,49 +1,151 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"net"
 	"time"
 
-	_ "github.com/lib/pq"
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 )
 
+const maxConns = 25
+
 type Config struct {
+	// Host is one host or, as in libpq, a comma-separated list of hosts
+	// tried in turn. Port is one port for every host or one per host.
 	Host     string
 	Port     string
 	User     string
 	Password string
 	Name     string
 	SSLMode  string
+	// TargetSessionAttrs is TargetAny, the default, TargetReadWrite or
+	// TargetReadOnly.
+	TargetSessionAttrs string
 }
 
+// connectTimeout bounds each connection attempt in seconds, so an
+// unreachable host does not hold up trying the next one.
+const connectTimeout = 5
+
 func NewPostgresConnection(cfg Config, logger *logrus.Logger) (*sql.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode)
+	db, _, err := NewPostgresFailoverConnection(cfg, clock.Real(), logger)
+	return db, err
+}
 
-	db, err := sql.Open("postgres", dsn)
+// NewPostgresFailoverConnection is NewPostgresConnection that also returns
+// the connector choosing between cfg's hosts, to tell whether it is failing
+// over.
+func NewPostgresFailoverConnection(cfg Config, clk clock.Clock, logger *logrus.Logger) (*sql.DB, *FailoverConnector, error) {
+	target := cfg.TargetSessionAttrs
+	switch target {
+	case "":
+		target = TargetAny
+	case TargetAny, TargetReadWrite, TargetReadOnly:
+	default:
+		return nil, nil, fmt.Errorf("unsupported target_session_attrs %q", target)
+	}
+
+	hosts, ports, err := parseHosts(cfg.Host, cfg.Port)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database connection: %w", err)
+		return nil, nil, err
 	}
+	addrs := make([]string, len(hosts))
+	connectors := make([]driver.Connector, len(hosts))
+	for i, host := range hosts {
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s connect_timeout=%d",
+			host, ports[i], cfg.User, cfg.Password, cfg.Name, cfg.SSLMode, connectTimeout)
+		connector, err := pq.NewConnector(dsn)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open database connection: %w", err)
+		}
+		addrs[i] = net.JoinHostPort(host, ports[i])
+		connectors[i] = connector
+	}
+	failover := newFailoverConnector(addrs, connectors, target, clk, logger)
+	db := sql.OpenDB(CountingConnector(failover))
 
 	// Set connection pool settings
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(25)
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(maxConns)
 	db.SetConnMaxLifetime(5 * time.Minute)
 	db.SetConnMaxIdleTime(5 * time.Minute)
 
 	// Test the connection
 	if err := db.Ping(); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	logger.WithFields(logrus.Fields{
-		"host":     cfg.Host,
-		"port":     cfg.Port,
+		"host":     failover.Host(),
 		"database": cfg.Name,
+		"target":   target,
 	}).Info("Successfully connected to PostgreSQL database")
 
-	return db, nil
+	return db, failover, nil
+}
+
+// OpenPostgres opens a pool on dsn whose statements count towards the
+// contexts made by WithQueryCount. It does not connect yet.
+func OpenPostgres(dsn string) (*sql.DB, error) {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+	return sql.OpenDB(CountingConnector(connector)), nil
+}
+
+// Warm opens up to n pool connections, holding each until all are open, and
+// then returns them to the idle pool so early requests don't wait on TCP and
+// auth handshakes.
+func Warm(ctx context.Context, db *sql.DB, n int) error {
+	if n <= 0 || n > maxConns {
+		n = maxConns
+	}
+
+	conns := make([]*sql.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	for i := 0; i < n; i++ {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to open pool connection: %w", err)
+		}
+		conns = append(conns, conn)
+
+		if err := conn.PingContext(ctx); err != nil {
+			return fmt.Errorf("failed to ping pool connection: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ReplicationLag returns how far the replica db trails its primary. A
+// replica that has replayed everything it received reports zero, so an
+// idle primary does not look like lag.
+func ReplicationLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	var seconds sql.NullFloat64
+	err := db.QueryRowContext(ctx, `
+		SELECT CASE
+			WHEN NOT pg_is_in_recovery() THEN 0
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+		END
+	`).Scan(&seconds)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query replication lag: %w", err)
+	}
+	return time.Duration(seconds.Float64 * float64(time.Second)), nil
 }
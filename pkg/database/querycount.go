@@ -0,0 +1,208 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+)
+
+type queryCountKey struct{}
+
+// WithQueryCount returns a context that counts the statements run with it
+// on connections opened through CountingConnector, including those of
+// goroutines given a context derived from it.
+func WithQueryCount(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryCountKey{}, new(atomic.Int64))
+}
+
+// QueryCount returns how many statements were run with ctx, or 0 when it
+// does not count them.
+func QueryCount(ctx context.Context) int64 {
+	if counter, ok := ctx.Value(queryCountKey{}).(*atomic.Int64); ok {
+		return counter.Load()
+	}
+	return 0
+}
+
+func countQuery(ctx context.Context) {
+	if counter, ok := ctx.Value(queryCountKey{}).(*atomic.Int64); ok {
+		counter.Add(1)
+	}
+}
+
+// CountingConnector wraps connector so that every query and exec, direct
+// or through a prepared statement, counts towards the context it is run
+// with. Transaction control and pings are not counted.
+func CountingConnector(connector driver.Connector) driver.Connector {
+	return &countingConnector{connector: connector}
+}
+
+type countingConnector struct {
+	connector driver.Connector
+}
+
+func (c *countingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &countingConn{conn: conn}, nil
+}
+
+func (c *countingConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// countingConn forwards the optional driver interfaces to conn, answering
+// as database/sql expects of a driver without them when conn lacks one.
+type countingConn struct {
+	conn driver.Conn
+}
+
+func (c *countingConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *countingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &countingStmt{stmt: stmt}, nil
+}
+
+func (c *countingConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *countingConn) Begin() (driver.Tx, error) {
+	return c.conn.Begin()
+}
+
+func (c *countingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.conn.Begin()
+}
+
+func (c *countingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		countQuery(ctx)
+	}
+	return rows, err
+}
+
+func (c *countingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	result, err := execer.ExecContext(ctx, query, args)
+	if err != driver.ErrSkip {
+		countQuery(ctx)
+	}
+	return result, err
+}
+
+func (c *countingConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *countingConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *countingConn) IsValid() bool {
+	if validator, ok := c.conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *countingConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+type countingStmt struct {
+	stmt driver.Stmt
+}
+
+func (s *countingStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *countingStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *countingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.stmt.Exec(args)
+}
+
+func (s *countingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.stmt.Query(args)
+}
+
+func (s *countingStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	countQuery(ctx)
+	if execer, ok := s.stmt.(driver.StmtExecContext); ok {
+		return execer.ExecContext(ctx, args)
+	}
+	values, err := namedValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.stmt.Exec(values)
+}
+
+func (s *countingStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	countQuery(ctx)
+	if queryer, ok := s.stmt.(driver.StmtQueryContext); ok {
+		return queryer.QueryContext(ctx, args)
+	}
+	values, err := namedValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.stmt.Query(values)
+}
+
+func (s *countingStmt) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+func namedValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("database: driver does not support named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConnector opens connections that answer every query with no rows.
+// Without direct, they lack QueryerContext and ExecerContext, so
+// database/sql prepares each statement first.
+type fakeConnector struct {
+	direct bool
+}
+
+func (c fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	if c.direct {
+		return &directConn{}, nil
+	}
+	return &fakeConn{}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct{}
+
+func (*fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (*fakeConn) Close() error                              { return nil }
+func (*fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type directConn struct {
+	fakeConn
+}
+
+func (*directConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return fakeRows{}, nil
+}
+
+func (*directConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                                    { return nil }
+func (fakeStmt) NumInput() int                                   { return -1 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error)  { return fakeRows{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string              { return []string{"id"} }
+func (fakeRows) Close() error                   { return nil }
+func (fakeRows) Next(dest []driver.Value) error { return io.EOF }
+
+func TestCountingConnector(t *testing.T) {
+	for _, direct := range []bool{true, false} {
+		db := sql.OpenDB(CountingConnector(fakeConnector{direct: direct}))
+
+		ctx := WithQueryCount(context.Background())
+		rows, err := db.QueryContext(ctx, "SELECT id FROM products WHERE store_id = $1", 7)
+		require.NoError(t, err)
+		rows.Close()
+		_, err = db.ExecContext(ctx, "UPDATE products SET amount = 0")
+		require.NoError(t, err)
+
+		stmt, err := db.PrepareContext(ctx, "SELECT id FROM products WHERE id = $1")
+		require.NoError(t, err)
+		for id := 1; id <= 3; id++ {
+			rows, err := stmt.QueryContext(ctx, id)
+			require.NoError(t, err)
+			rows.Close()
+		}
+		stmt.Close()
+
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		_, err = tx.ExecContext(ctx, "DELETE FROM products")
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		assert.Equal(t, int64(6), QueryCount(ctx), "direct=%v", direct)
+
+		// Statements run without a counting context are not counted anywhere.
+		_, err = db.ExecContext(context.Background(), "UPDATE products SET amount = 0")
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), QueryCount(ctx))
+		assert.Zero(t, QueryCount(context.Background()))
+
+		db.Close()
+	}
+}
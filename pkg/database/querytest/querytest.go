@@ -0,0 +1,26 @@
+// Package querytest fails tests that run more database statements than
+// they are expected to, so an accidental N+1 shows up in the test that
+// introduced it rather than in production latency.
+package querytest
+
+import (
+	"context"
+	"testing"
+
+	"backend-context-engineering-template/pkg/database"
+)
+
+// Budget returns a context derived from ctx that counts the statements run
+// with it, on a pool opened with database.OpenPostgres or
+// database.CountingConnector, and fails t at cleanup when they exceed max.
+func Budget(t testing.TB, ctx context.Context, max int64) context.Context {
+	t.Helper()
+
+	ctx = database.WithQueryCount(ctx)
+	t.Cleanup(func() {
+		if count := database.QueryCount(ctx); count > max {
+			t.Errorf("ran %d database queries, more than the budget of %d", count, max)
+		}
+	})
+	return ctx
+}
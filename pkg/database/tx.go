@@ -0,0 +1,117 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// TxManager runs a unit of work in one transaction, so a use case can make
+// several repository calls that are written together or not at all without
+// handling *sql.Tx itself.
+type TxManager interface {
+	// WithinTransaction calls fn with a context that carries the
+	// transaction. Repositories given that context run their statements in
+	// it. The transaction commits when fn returns nil and rolls back when
+	// it returns an error or panics. Called within a transaction, it joins
+	// the outer one, which alone commits.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Querier is what *sql.DB and *sql.Tx have in common.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+type txKey struct{}
+
+type txState struct {
+	tx          *sql.Tx
+	afterCommit []func()
+}
+
+// SQLTxManager runs units of work in transactions on db.
+type SQLTxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *SQLTxManager {
+	return &SQLTxManager{db: db}
+}
+
+func (m *SQLTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if InTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	state := &txState{tx: tx}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, state)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for _, hook := range state.afterCommit {
+		hook()
+	}
+	return nil
+}
+
+// Conn returns the transaction ctx carries, or db outside one.
+func Conn(ctx context.Context, db *sql.DB) Querier {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return db
+}
+
+// Stmt returns stmt, bound to the transaction ctx carries if there is one.
+func Stmt(ctx context.Context, stmt *sql.Stmt) *sql.Stmt {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx.StmtContext(ctx, stmt)
+	}
+	return stmt
+}
+
+// InTransaction reports whether ctx carries a transaction. Caches should
+// neither serve nor fill from reads inside one: they may see writes that
+// are rolled back.
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txState)
+	return ok
+}
+
+// AfterCommit calls fn once the transaction ctx carries has committed, and
+// never if it rolls back. Outside a transaction, fn is called straight away.
+func AfterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+// NopTxManager runs units of work without a transaction, for repositories
+// that have no database to roll back, such as the in-memory ones.
+type NopTxManager struct{}
+
+func (NopTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,143 @@
+package explain
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"backend-context-engineering-template/pkg/ratelimit"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Config struct {
+	// Threshold is the request latency from which a plan is captured.
+	Threshold time.Duration
+	// SampleRate is the share of slow requests considered, from 0 to 1.
+	SampleRate float64
+	// MaxPerMinute caps captures across the instance.
+	MaxPerMinute int64
+	// Timeout bounds one EXPLAIN ANALYZE, which takes at least as long as
+	// the query itself.
+	Timeout time.Duration
+}
+
+// Capturer re-runs slow requests' queries with EXPLAIN ANALYZE in the
+// background, one at a time, and logs their plans.
+type Capturer struct {
+	cfg     Config
+	limiter ratelimit.Store
+	logger  *logrus.Logger
+	plan    func(ctx context.Context, q Query) (string, error)
+	sample  func() float64
+
+	busy     atomic.Bool
+	wg       sync.WaitGroup
+	captures *telemetry.Counter
+}
+
+func NewCapturer(db *sql.DB, limiter ratelimit.Store, registry *telemetry.Registry, cfg Config, logger *logrus.Logger) *Capturer {
+	return &Capturer{
+		cfg:     cfg,
+		limiter: limiter,
+		logger:  logger,
+		plan: func(ctx context.Context, q Query) (string, error) {
+			return Plan(ctx, db, q)
+		},
+		sample:   rand.Float64,
+		captures: registry.NewCounter("explain_captures_total", "Slow request query plan captures by result.", "result"),
+	}
+}
+
+func (c *Capturer) Threshold() time.Duration {
+	return c.cfg.Threshold
+}
+
+// Capture logs the plan of q with fields unless the request is sampled out,
+// a capture is already running or the per-minute budget is spent. It
+// returns without waiting for the plan.
+func (c *Capturer) Capture(fields logrus.Fields, q Query) {
+	if !readOnly(q.SQL) || c.sample() >= c.cfg.SampleRate {
+		return
+	}
+	if !c.busy.CompareAndSwap(false, true) {
+		c.captures.Inc("skipped")
+		return
+	}
+
+	used, _, err := c.limiter.Consume(context.Background(), "explain", 1, time.Minute)
+	if err != nil || used > c.cfg.MaxPerMinute {
+		c.busy.Store(false)
+		c.captures.Inc("skipped")
+		return
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer c.busy.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Timeout)
+		defer cancel()
+
+		logger := c.logger.WithFields(fields).WithFields(logrus.Fields{"query": q.SQL, "query_duration": q.Duration})
+		plan, err := c.plan(ctx, q)
+		if err != nil {
+			c.captures.Inc("failed")
+			logger.WithError(err).Warn("Failed to capture slow request query plan")
+			return
+		}
+		c.captures.Inc("captured")
+		logger.WithField("plan", plan).Warn("Slow request query plan")
+	}()
+}
+
+// Wait blocks until running captures finish.
+func (c *Capturer) Wait() {
+	c.wg.Wait()
+}
+
+// Plan runs q with EXPLAIN (ANALYZE, BUFFERS) in a read-only transaction
+// that is rolled back, and returns the plan text.
+func Plan(ctx context.Context, db *sql.DB, q Query) (string, error) {
+	if !readOnly(q.SQL) {
+		return "", fmt.Errorf("refusing to explain a statement that is not a SELECT")
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to begin explain transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "EXPLAIN (ANALYZE, BUFFERS) "+q.SQL, q.Args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to explain query: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", fmt.Errorf("failed to scan query plan: %w", err)
+		}
+		lines = append(lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to read query plan: %w", err)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+func readOnly(query string) bool {
+	fields := strings.Fields(query)
+	return len(fields) > 0 && strings.EqualFold(fields[0], "SELECT")
+}
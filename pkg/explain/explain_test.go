@@ -0,0 +1,132 @@
+package explain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/ratelimit"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrack_KeepsSlowestQuery(t *testing.T) {
+	ctx := WithRecorder(context.Background())
+
+	Track(ctx, "SELECT 1")()
+	done := Track(ctx, "SELECT * FROM products WHERE store_id = $1", int64(7))
+	time.Sleep(5 * time.Millisecond)
+	done()
+	Track(ctx, "SELECT 2")()
+
+	q, ok := Slowest(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "SELECT * FROM products WHERE store_id = $1", q.SQL)
+	assert.Equal(t, []any{int64(7)}, q.Args)
+	assert.GreaterOrEqual(t, q.Duration, 5*time.Millisecond)
+}
+
+func TestTrack_WithoutRecorder(t *testing.T) {
+	ctx := context.Background()
+	Track(ctx, "SELECT 1")()
+
+	_, ok := Slowest(ctx)
+	assert.False(t, ok)
+}
+
+func newTestCapturer(cfg Config, plan func(ctx context.Context, q Query) (string, error)) (*Capturer, *test.Hook, *telemetry.Registry) {
+	logger, hook := test.NewNullLogger()
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	c := NewCapturer(nil, ratelimit.NewMemoryStore(clock.Real()), registry, cfg, logger)
+	c.plan = plan
+	c.sample = func() float64 { return 0.5 }
+	return c, hook, registry
+}
+
+func TestCapturer_LogsPlan(t *testing.T) {
+	c, hook, registry := newTestCapturer(Config{SampleRate: 1, MaxPerMinute: 10, Timeout: time.Second},
+		func(ctx context.Context, q Query) (string, error) {
+			return "Seq Scan on products  (actual time=0.01..120.5 rows=1 loops=1)", nil
+		})
+
+	c.Capture(logrus.Fields{"route": "/api/v1/products"}, Query{SQL: "SELECT * FROM products", Duration: time.Second})
+	c.Wait()
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, "Slow request query plan", entry.Message)
+	assert.Equal(t, "/api/v1/products", entry.Data["route"])
+	assert.Equal(t, "SELECT * FROM products", entry.Data["query"])
+	assert.Contains(t, entry.Data["plan"], "Seq Scan on products")
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `explain_captures_total{result="captured"} 1`)
+}
+
+func TestCapturer_Skips(t *testing.T) {
+	var calls int
+	var mu sync.Mutex
+	plan := func(ctx context.Context, q Query) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return "", errors.New("canceling statement due to statement timeout")
+	}
+
+	t.Run("sampled out", func(t *testing.T) {
+		c, _, _ := newTestCapturer(Config{SampleRate: 0.1, MaxPerMinute: 10, Timeout: time.Second}, plan)
+		c.Capture(nil, Query{SQL: "SELECT 1"})
+		c.Wait()
+	})
+	t.Run("writes are never re-run", func(t *testing.T) {
+		c, _, _ := newTestCapturer(Config{SampleRate: 1, MaxPerMinute: 10, Timeout: time.Second}, plan)
+		c.Capture(nil, Query{SQL: "DELETE FROM products"})
+		c.Capture(nil, Query{SQL: " update products SET name = $1"})
+		c.Wait()
+	})
+	assert.Zero(t, calls)
+
+	t.Run("per-minute budget", func(t *testing.T) {
+		c, hook, registry := newTestCapturer(Config{SampleRate: 1, MaxPerMinute: 2, Timeout: time.Second}, plan)
+		for i := 0; i < 4; i++ {
+			c.Capture(nil, Query{SQL: "SELECT 1"})
+			c.Wait()
+		}
+
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, "Failed to capture slow request query plan", hook.LastEntry().Message)
+
+		var buf bytes.Buffer
+		require.NoError(t, registry.WritePrometheus(&buf))
+		assert.Contains(t, buf.String(), `explain_captures_total{result="failed"} 2`)
+		assert.Contains(t, buf.String(), `explain_captures_total{result="skipped"} 2`)
+	})
+}
+
+func TestCapturer_OneAtATime(t *testing.T) {
+	release := make(chan struct{})
+	c, _, registry := newTestCapturer(Config{SampleRate: 1, MaxPerMinute: 10, Timeout: time.Second},
+		func(ctx context.Context, q Query) (string, error) {
+			<-release
+			return "Result", nil
+		})
+
+	c.Capture(nil, Query{SQL: "SELECT 1"})
+	c.Capture(nil, Query{SQL: "SELECT 2"})
+	close(release)
+	c.Wait()
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `explain_captures_total{result="captured"} 1`)
+	assert.Contains(t, buf.String(), `explain_captures_total{result="skipped"} 1`)
+}
@@ -0,0 +1,66 @@
+// Package explain captures the query plan of slow requests. Repositories
+// record the read queries they run in the request context; when the request
+// turns out slow, the slowest of them is re-run with EXPLAIN ANALYZE and
+// its plan is logged with the request.
+package explain
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Query is a statement a request ran, with its arguments and how long it
+// took.
+type Query struct {
+	SQL      string
+	Args     []any
+	Duration time.Duration
+}
+
+type recorderKey struct{}
+
+type recorder struct {
+	mu      sync.Mutex
+	slowest Query
+	ok      bool
+}
+
+// WithRecorder returns a context that records the queries run with it.
+func WithRecorder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, recorderKey{}, &recorder{})
+}
+
+// Track records query once the returned func is called, typically with
+// defer. Only read-only statements may be tracked: capturing a plan runs
+// the statement again. Without a recorder in ctx it does nothing.
+func Track(ctx context.Context, sql string, args ...any) func() {
+	rec, ok := ctx.Value(recorderKey{}).(*recorder)
+	if !ok {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		if !rec.ok || elapsed > rec.slowest.Duration {
+			rec.slowest = Query{SQL: sql, Args: args, Duration: elapsed}
+			rec.ok = true
+		}
+	}
+}
+
+// Slowest returns the slowest query recorded in ctx.
+func Slowest(ctx context.Context) (Query, bool) {
+	rec, ok := ctx.Value(recorderKey{}).(*recorder)
+	if !ok {
+		return Query{}, false
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.slowest, rec.ok
+}
@@ -0,0 +1,132 @@
+// Package health pings the dependencies a service needs to serve traffic,
+// so readiness probes fail when a connection is broken rather than only
+// when the process is.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+)
+
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded"
+)
+
+// Check pings one dependency. An optional dependency, such as a read
+// replica the service can fall back from, only degrades the report when
+// it is down.
+type Check struct {
+	Name     string
+	Optional bool
+	Ping     func(ctx context.Context) error
+}
+
+// Degraded marks a ping's error as degrading the report rather than
+// failing it, for a required dependency that is impaired but still
+// expected to serve, such as a database failing over.
+func Degraded(err error) error {
+	return &degradedError{err: err}
+}
+
+type degradedError struct {
+	err error
+}
+
+func (e *degradedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *degradedError) Unwrap() error {
+	return e.err
+}
+
+// Result is one check's outcome. Latency is how long the ping took, up to
+// the checker's timeout.
+type Result struct {
+	Name     string
+	Optional bool
+	Status   string
+	Latency  time.Duration
+	Err      error
+}
+
+// Report is the outcome of every check, in the order they were given.
+// Status is down when a required check failed, degraded when only
+// optional ones did, and up otherwise.
+type Report struct {
+	Status string
+	Checks []Result
+}
+
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+	clock   clock.Clock
+}
+
+// NewChecker gives each ping up to timeout.
+func NewChecker(timeout time.Duration, clk clock.Clock, checks ...Check) *Checker {
+	return &Checker{
+		checks:  checks,
+		timeout: timeout,
+		clock:   clk,
+	}
+}
+
+// Check pings every dependency at once and waits for all of them.
+func (c *Checker) Check(ctx context.Context) Report {
+	results := make([]Result, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, result := range results {
+		switch {
+		case result.Status == StatusUp:
+		case result.Optional, result.Status == StatusDegraded:
+			if report.Status == StatusUp {
+				report.Status = StatusDegraded
+			}
+		default:
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := c.clock.Now()
+	err := check.Ping(ctx)
+	result := Result{
+		Name:     check.Name,
+		Optional: check.Optional,
+		Status:   StatusUp,
+		Latency:  c.clock.Now().Sub(start),
+		Err:      err,
+	}
+	var degraded *degradedError
+	switch {
+	case errors.As(err, &degraded):
+		result.Status = StatusDegraded
+	case err != nil:
+		result.Status = StatusDown
+	}
+	return result
+}
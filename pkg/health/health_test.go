@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func up(ctx context.Context) error { return nil }
+
+func down(ctx context.Context) error { return errors.New("connection refused") }
+
+func degraded(ctx context.Context) error { return Degraded(errors.New("failing over")) }
+
+func TestChecker_Check(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []Check
+		want   string
+	}{
+		{name: "no checks", want: StatusUp},
+		{name: "all up", checks: []Check{{Name: "postgres", Ping: up}, {Name: "redis", Ping: up}}, want: StatusUp},
+		{name: "optional down", checks: []Check{{Name: "postgres", Ping: up}, {Name: "replica", Optional: true, Ping: down}}, want: StatusDegraded},
+		{name: "required down", checks: []Check{{Name: "postgres", Ping: down}, {Name: "replica", Optional: true, Ping: down}}, want: StatusDown},
+		{name: "required degraded", checks: []Check{{Name: "postgres", Ping: degraded}, {Name: "redis", Ping: up}}, want: StatusDegraded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := NewChecker(time.Second, clock.Real(), tt.checks...).Check(context.Background())
+
+			assert.Equal(t, tt.want, report.Status)
+			require.Len(t, report.Checks, len(tt.checks))
+			for i, check := range tt.checks {
+				assert.Equal(t, check.Name, report.Checks[i].Name, "results keep the checks' order")
+			}
+		})
+	}
+}
+
+func TestChecker_TimesOutSlowPings(t *testing.T) {
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	report := NewChecker(20*time.Millisecond, clock.Real(), Check{Name: "postgres", Ping: hanging}).Check(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.ErrorIs(t, report.Checks[0].Err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, report.Checks[0].Latency, 20*time.Millisecond)
+}
@@ -0,0 +1,118 @@
+package hotkeys
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSketch_NeverUnderCounts(t *testing.T) {
+	s := NewSketch(64, 4)
+	for id := int64(1); id <= 500; id++ {
+		s.Add(id, uint64(id%7)+1)
+	}
+
+	for id := int64(1); id <= 500; id++ {
+		assert.GreaterOrEqual(t, s.Estimate(id), uint64(id%7)+1)
+	}
+}
+
+func TestSketch_Decay(t *testing.T) {
+	s := NewSketch(64, 4)
+	s.Add(1, 10)
+	s.Decay()
+
+	assert.Equal(t, uint64(5), s.Estimate(1))
+}
+
+func TestTracker_Record(t *testing.T) {
+	tracker := NewTracker(10, 3, nil, clock.Real())
+
+	assert.False(t, tracker.Record(1))
+	assert.False(t, tracker.Record(1))
+	assert.True(t, tracker.Record(1))
+	assert.True(t, tracker.IsHot(1))
+	assert.False(t, tracker.IsHot(2))
+
+	assert.Equal(t, []HotKey{{ID: 1, Hits: 3}}, tracker.Hot())
+}
+
+func TestTracker_KeepsTopK(t *testing.T) {
+	tracker := NewTracker(2, 1, nil, clock.Real())
+	tracker.Record(1)
+	tracker.Record(2)
+	tracker.Record(2)
+	tracker.Record(3)
+	tracker.Record(3)
+	tracker.Record(3)
+
+	assert.Equal(t, []HotKey{{ID: 3, Hits: 3}, {ID: 2, Hits: 2}}, tracker.Hot())
+}
+
+func TestTracker_SyncSharesCounts(t *testing.T) {
+	store := NewMemoryStore(0, clock.Real())
+	a := NewTracker(10, 4, store, clock.Real())
+	b := NewTracker(10, 4, store, clock.Real())
+
+	for i := 0; i < 3; i++ {
+		a.Record(42)
+	}
+	require.NoError(t, a.Sync(context.Background()))
+	require.NoError(t, b.Sync(context.Background()))
+
+	assert.False(t, b.IsHot(42))
+	assert.True(t, b.Record(42), "instance b should see reads recorded on instance a")
+}
+
+func TestMemoryStore_DecaysPerHalfLife(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	store := NewMemoryStore(time.Minute, fake)
+
+	delta := NewSketch(sketchWidth, sketchDepth)
+	delta.Add(1, 8)
+	_, err := store.Merge(context.Background(), delta)
+	require.NoError(t, err)
+
+	merged, err := store.Merge(context.Background(), NewSketch(sketchWidth, sketchDepth))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(8), merged.Estimate(1), "merges within a half-life must not decay")
+
+	fake.Advance(2 * time.Minute)
+	merged, err = store.Merge(context.Background(), NewSketch(sketchWidth, sketchDepth))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), merged.Estimate(1))
+}
+
+func TestRedisStore_SharesAndDecays(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server.SetTime(now)
+
+	a := NewTracker(10, 4, NewRedisStore(client, "test:", time.Minute), clock.Real())
+	b := NewTracker(10, 4, NewRedisStore(client, "test:", time.Minute), clock.Real())
+
+	for i := 0; i < 3; i++ {
+		a.Record(42)
+	}
+	require.NoError(t, a.Sync(ctx))
+	require.NoError(t, b.Sync(ctx))
+
+	assert.False(t, b.IsHot(42))
+	assert.True(t, b.Record(42), "instance b should see reads recorded on instance a")
+	require.NoError(t, b.Sync(ctx))
+
+	server.SetTime(now.Add(2 * time.Minute))
+	require.NoError(t, a.Sync(ctx))
+	assert.False(t, a.IsHot(42), "two half-lives should quarter the count")
+}
@@ -0,0 +1,91 @@
+package hotkeys
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// mergeScript decays the shared counters by Redis server time, so instances
+// with skewed clocks agree on when a half-life has passed, then adds the
+// delta cells and returns every nonzero cell.
+var mergeScript = redis.NewScript(`
+local halfLife = tonumber(ARGV[1])
+if halfLife > 0 then
+	local time = redis.call('TIME')
+	local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+	local last = tonumber(redis.call('GET', KEYS[2]))
+	if not last then
+		redis.call('SET', KEYS[2], now)
+	elseif now - last >= halfLife then
+		local steps = math.floor((now - last) / halfLife)
+		local counts = redis.call('HGETALL', KEYS[1])
+		for i = 1, #counts, 2 do
+			local count = math.floor(tonumber(counts[i + 1]) / 2 ^ steps)
+			if count > 0 then
+				redis.call('HSET', KEYS[1], counts[i], count)
+			else
+				redis.call('HDEL', KEYS[1], counts[i])
+			end
+		end
+		redis.call('SET', KEYS[2], last + steps * halfLife)
+	end
+end
+for i = 2, #ARGV, 2 do
+	redis.call('HINCRBY', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+return redis.call('HGETALL', KEYS[1])
+`)
+
+// RedisStore keeps the shared sketch in Redis as a hash of its nonzero
+// cells, so every instance merged into the same Redis sees cluster-wide
+// hotness. Counts are halved once per halfLife like MemoryStore.
+type RedisStore struct {
+	client    redis.UniversalClient
+	countsKey string
+	decayKey  string
+	halfLife  time.Duration
+}
+
+func NewRedisStore(client redis.UniversalClient, prefix string, halfLife time.Duration) *RedisStore {
+	// The hash tag keeps both keys in one cluster slot for the script.
+	return &RedisStore{
+		client:    client,
+		countsKey: prefix + "{hotkeys}:counts",
+		decayKey:  prefix + "{hotkeys}:decayed_at",
+		halfLife:  halfLife,
+	}
+}
+
+func (s *RedisStore) Merge(ctx context.Context, delta *Sketch) (*Sketch, error) {
+	args := []interface{}{s.halfLife.Milliseconds()}
+	for row := range delta.counts {
+		for col, count := range delta.counts[row] {
+			if count > 0 {
+				args = append(args, row*delta.width+col, count)
+			}
+		}
+	}
+
+	cells, err := mergeScript.Run(ctx, s.client, []string{s.countsKey, s.decayKey}, args...).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("merge hot keys: %w", err)
+	}
+
+	merged := NewSketch(delta.width, delta.depth)
+	for i := 0; i+1 < len(cells); i += 2 {
+		cell, err := strconv.Atoi(cells[i])
+		if err != nil || cell < 0 || cell >= delta.width*delta.depth {
+			continue
+		}
+		count, err := strconv.ParseUint(cells[i+1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("merge hot keys: cell %d: %w", cell, err)
+		}
+		merged.counts[cell/delta.width][cell%delta.width] = count
+	}
+	return merged, nil
+}
@@ -0,0 +1,81 @@
+package hotkeys
+
+// Sketch is a count-min sketch: a fixed-size frequency table that never
+// under-counts and over-counts by a bounded amount, regardless of how many
+// distinct keys it sees.
+type Sketch struct {
+	width  int
+	depth  int
+	counts [][]uint64
+}
+
+func NewSketch(width, depth int) *Sketch {
+	counts := make([][]uint64, depth)
+	for i := range counts {
+		counts[i] = make([]uint64, width)
+	}
+	return &Sketch{width: width, depth: depth, counts: counts}
+}
+
+func (s *Sketch) Add(key int64, n uint64) {
+	for row := range s.counts {
+		s.counts[row][s.index(key, row)] += n
+	}
+}
+
+// Estimate returns the smallest counter for key, which is its best upper
+// bound.
+func (s *Sketch) Estimate(key int64) uint64 {
+	var estimate uint64
+	for row := range s.counts {
+		count := s.counts[row][s.index(key, row)]
+		if row == 0 || count < estimate {
+			estimate = count
+		}
+	}
+	return estimate
+}
+
+// Merge adds other's counts into s. Both sketches must share dimensions.
+func (s *Sketch) Merge(other *Sketch) {
+	for row := range s.counts {
+		for col := range s.counts[row] {
+			s.counts[row][col] += other.counts[row][col]
+		}
+	}
+}
+
+// Decay halves every counter so old traffic fades out.
+func (s *Sketch) Decay() {
+	for row := range s.counts {
+		for col := range s.counts[row] {
+			s.counts[row][col] /= 2
+		}
+	}
+}
+
+func (s *Sketch) Reset() {
+	for row := range s.counts {
+		clear(s.counts[row])
+	}
+}
+
+func (s *Sketch) Clone() *Sketch {
+	clone := NewSketch(s.width, s.depth)
+	clone.Merge(s)
+	return clone
+}
+
+func (s *Sketch) index(key int64, row int) int {
+	return int(mix(uint64(key)+uint64(row)*0x9e3779b97f4a7c15) % uint64(s.width))
+}
+
+// mix is the splitmix64 finalizer.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,192 @@
+package hotkeys
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+)
+
+const (
+	sketchWidth = 2048
+	sketchDepth = 4
+)
+
+type HotKey struct {
+	ID   int64  `json:"id"`
+	Hits uint64 `json:"hits"`
+}
+
+// Store shares sketches between instances. Merge adds the local delta to the
+// shared sketch and returns the combined view.
+type Store interface {
+	Merge(ctx context.Context, delta *Sketch) (*Sketch, error)
+}
+
+// Tracker estimates per-key read frequency and keeps the top K candidates.
+// Reads since the last sync are counted locally and added to the shared view
+// from the store, so every instance sees cluster-wide hotness.
+type Tracker struct {
+	mu        sync.Mutex
+	local     *Sketch
+	global    *Sketch
+	top       map[int64]uint64
+	topK      int
+	threshold uint64
+	store     Store
+	clock     clock.Clock
+}
+
+// NewTracker returns a tracker that reports a key as hot once its estimated
+// hits reach threshold. store may be nil to track this instance only.
+func NewTracker(topK int, threshold uint64, store Store, clk clock.Clock) *Tracker {
+	if store == nil {
+		store = NewMemoryStore(0, clk)
+	}
+	return &Tracker{
+		local:     NewSketch(sketchWidth, sketchDepth),
+		global:    NewSketch(sketchWidth, sketchDepth),
+		top:       make(map[int64]uint64, topK),
+		topK:      topK,
+		threshold: threshold,
+		store:     store,
+		clock:     clk,
+	}
+}
+
+// Record counts one read of key and reports whether the key is now hot.
+func (t *Tracker) Record(key int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.local.Add(key, 1)
+	hits := t.local.Estimate(key) + t.global.Estimate(key)
+	t.offer(key, hits)
+	return hits >= t.threshold
+}
+
+func (t *Tracker) IsHot(key int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.local.Estimate(key)+t.global.Estimate(key) >= t.threshold
+}
+
+// Hot returns the tracked keys at or above the threshold, hottest first.
+func (t *Tracker) Hot() []HotKey {
+	t.mu.Lock()
+	keys := make([]HotKey, 0, len(t.top))
+	for id, hits := range t.top {
+		if hits >= t.threshold {
+			keys = append(keys, HotKey{ID: id, Hits: hits})
+		}
+	}
+	t.mu.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Hits != keys[j].Hits {
+			return keys[i].Hits > keys[j].Hits
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys
+}
+
+// Sync pushes local counts to the store and adopts the merged view.
+func (t *Tracker) Sync(ctx context.Context) error {
+	t.mu.Lock()
+	delta := t.local.Clone()
+	t.local.Reset()
+	t.mu.Unlock()
+
+	merged, err := t.store.Merge(ctx, delta)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err != nil {
+		t.local.Merge(delta)
+		return err
+	}
+
+	t.global = merged
+	for id := range t.top {
+		t.top[id] = t.local.Estimate(id) + t.global.Estimate(id)
+	}
+	return nil
+}
+
+// Run syncs every interval until ctx is done.
+func (t *Tracker) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := t.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			if err := t.Sync(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func (t *Tracker) offer(key int64, hits uint64) {
+	if t.topK <= 0 {
+		return
+	}
+	if _, ok := t.top[key]; ok || len(t.top) < t.topK {
+		t.top[key] = hits
+		return
+	}
+
+	var coldest int64
+	var coldestHits uint64
+	first := true
+	for id, h := range t.top {
+		if first || h < coldestHits {
+			coldest, coldestHits, first = id, h, false
+		}
+	}
+	if hits > coldestHits {
+		delete(t.top, coldest)
+		t.top[key] = hits
+	}
+}
+
+// MemoryStore is a process-local Store. Counts are halved once per halfLife
+// so hotness follows recent traffic rather than all-time totals; a zero
+// halfLife never decays.
+type MemoryStore struct {
+	mu        sync.Mutex
+	sketch    *Sketch
+	halfLife  time.Duration
+	lastDecay time.Time
+	clock     clock.Clock
+}
+
+func NewMemoryStore(halfLife time.Duration, clk clock.Clock) *MemoryStore {
+	return &MemoryStore{
+		sketch:    NewSketch(sketchWidth, sketchDepth),
+		halfLife:  halfLife,
+		lastDecay: clk.Now(),
+		clock:     clk,
+	}
+}
+
+func (s *MemoryStore) Merge(_ context.Context, delta *Sketch) (*Sketch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.halfLife > 0 {
+		for now := s.clock.Now(); now.Sub(s.lastDecay) >= s.halfLife; s.lastDecay = s.lastDecay.Add(s.halfLife) {
+			s.sketch.Decay()
+		}
+	}
+	s.sketch.Merge(delta)
+	return s.sketch.Clone(), nil
+}
@@ -0,0 +1,67 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+// breaker opens after BreakerThreshold consecutive failures, rejects calls
+// for BreakerCooldown, then lets a single probe through. A successful probe
+// closes it again; a failed one re-opens it.
+type breaker struct {
+	mu          sync.Mutex
+	state       breakerState
+	failures    int
+	openedUntil time.Time
+	probing     bool
+}
+
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if now.Before(b.openedUntil) {
+			return false
+		}
+		b.state = stateHalfOpen
+		b.probing = true
+		return true
+	case stateHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *breaker) report(success bool, now time.Time, cfg Config) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.state = stateClosed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.state == stateHalfOpen || (cfg.BreakerThreshold > 0 && b.failures >= cfg.BreakerThreshold) {
+		b.state = stateOpen
+		b.openedUntil = now.Add(cfg.BreakerCooldown)
+		b.probing = false
+	}
+}
@@ -0,0 +1,54 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateDestination is returned when a request would connect to a
+// loopback, private, link-local or otherwise non-public address.
+var ErrPrivateDestination = errors.New("destination address is not public")
+
+// sharedAddressSpace is carrier-grade NAT space, which reaches internal
+// hosts on some networks.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// PublicTransport returns a transport that only connects to public
+// addresses, for URLs that users register. The address is checked when it
+// is dialed, after DNS resolution, so a host name that resolves to a
+// private address, or a redirect to one, is refused too. Proxies from the
+// environment are not used, since the check would then see only the proxy.
+func PublicTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   denyPrivate,
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.DialContext = dialer.DialContext
+	return t
+}
+
+func denyPrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || sharedAddressSpace.Contains(addr) {
+		return fmt.Errorf("%w: %s", ErrPrivateDestination, addr)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type Config struct {
+	Timeout          time.Duration
+	MaxRetries       int
+	BaseBackoff      time.Duration
+	MaxBackoff       time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          30 * time.Second,
+		MaxRetries:       3,
+		BaseBackoff:      200 * time.Millisecond,
+		MaxBackoff:       5 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// Propagator injects request-scoped context (trace headers, correlation IDs)
+// into outgoing requests. An OpenTelemetry TextMapPropagator fits here via
+// propagation.HeaderCarrier.
+type Propagator func(ctx context.Context, header http.Header)
+
+type Option func(*transport)
+
+func WithPropagator(p Propagator) Option {
+	return func(t *transport) {
+		t.propagators = append(t.propagators, p)
+	}
+}
+
+func WithMetrics(m *Metrics) Option {
+	return func(t *transport) {
+		t.metrics = m
+	}
+}
+
+func WithBaseTransport(rt http.RoundTripper) Option {
+	return func(t *transport) {
+		t.base = rt
+	}
+}
+
+func WithLogger(logger *logrus.Logger) Option {
+	return func(t *transport) {
+		t.logger = logger
+	}
+}
+
+// New returns an *http.Client whose transport retries idempotent requests
+// with jittered exponential backoff, trips a circuit breaker per host after
+// consecutive failures, propagates context headers and records
+// per-destination metrics.
+func New(cfg Config, clk clock.Clock, opts ...Option) *http.Client {
+	t := &transport{
+		base:     http.DefaultTransport,
+		cfg:      cfg,
+		clock:    clk,
+		breakers: make(map[string]*breaker),
+		metrics:  NewMetrics(),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: t,
+	}
+}
+
+type transport struct {
+	base        http.RoundTripper
+	cfg         Config
+	clock       clock.Clock
+	propagators []Propagator
+	metrics     *Metrics
+	logger      *logrus.Logger
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	b := t.breaker(host)
+
+	req = req.Clone(req.Context())
+	for _, propagate := range t.propagators {
+		propagate(req.Context(), req.Header)
+	}
+
+	retryable := isIdempotent(req.Method) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	for attempt := 0; ; attempt++ {
+		if !b.allow(t.clock.Now()) {
+			t.metrics.record(host, func(s *DestinationStats) { s.Rejected++ })
+			return nil, fmt.Errorf("%w for %s", ErrCircuitOpen, host)
+		}
+
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		start := t.clock.Now()
+		resp, err := t.base.RoundTrip(req)
+		end := t.clock.Now()
+		latency := end.Sub(start)
+
+		failed := err != nil || resp.StatusCode >= 500
+		b.report(!failed, end, t.cfg)
+		t.metrics.record(host, func(s *DestinationStats) {
+			s.Requests++
+			s.TotalLatency += latency
+			if failed {
+				s.Failures++
+			}
+			if attempt > 0 {
+				s.Retries++
+			}
+		})
+
+		if !retryable || attempt >= t.cfg.MaxRetries || !shouldRetry(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		if t.logger != nil {
+			t.logger.WithContext(req.Context()).WithFields(logrus.Fields{
+				"host":    host,
+				"method":  req.Method,
+				"attempt": attempt + 1,
+				"wait":    wait,
+			}).Debug("Retrying outbound request")
+		}
+
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func (t *transport) sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	ticker := t.clock.NewTicker(d)
+	defer ticker.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-ticker.C():
+		return nil
+	}
+}
+
+func (t *transport) breaker(host string) *breaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.breakers[host]
+	if !ok {
+		b = &breaker{}
+		t.breakers[host] = b
+	}
+	return b
+}
+
+func (t *transport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if after := parseRetryAfter(resp.Header.Get("Retry-After"), t.clock.Now()); after > 0 {
+			if after > t.cfg.MaxBackoff {
+				return t.cfg.MaxBackoff
+			}
+			return after
+		}
+	}
+
+	max := t.cfg.BaseBackoff << attempt
+	if max <= 0 || max > t.cfg.MaxBackoff {
+		max = t.cfg.MaxBackoff
+	}
+	// Full jitter keeps many clients from retrying in lockstep.
+	return time.Duration(rand.Int63n(int64(max) + 1))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrPrivateDestination)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return at.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,144 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	return Config{
+		Timeout:          5 * time.Second,
+		MaxRetries:       2,
+		BaseBackoff:      time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		BreakerThreshold: 3,
+		BreakerCooldown:  time.Hour,
+	}
+}
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "payload", string(body))
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	metrics := NewMetrics()
+	client := New(testConfig(), clock.Real(), WithMetrics(metrics))
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, bytes.NewReader([]byte("payload")))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	stats := metrics.Snapshot()[req.URL.Host]
+	assert.Equal(t, int64(3), stats.Requests)
+	assert.Equal(t, int64(2), stats.Retries)
+	assert.Equal(t, int64(2), stats.Failures)
+}
+
+func TestClient_DoesNotRetryPost(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := New(testConfig(), clock.Real())
+	resp, err := client.Post(server.URL, "application/json", bytes.NewReader([]byte(`{}`)))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestClient_CircuitBreakerOpens(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cfg := testConfig()
+	cfg.MaxRetries = 0
+	client := New(cfg, clock.Real())
+
+	for i := 0; i < cfg.BreakerThreshold; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	_, err := client.Get(server.URL)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, int32(cfg.BreakerThreshold), atomic.LoadInt32(&calls))
+}
+
+func TestClient_Propagator(t *testing.T) {
+	type key struct{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "00-trace-span-01", r.Header.Get("traceparent"))
+	}))
+	defer server.Close()
+
+	client := New(testConfig(), clock.Real(), WithPropagator(func(ctx context.Context, header http.Header) {
+		if v, ok := ctx.Value(key{}).(string); ok {
+			header.Set("traceparent", v)
+		}
+	}))
+
+	ctx := context.WithValue(context.Background(), key{}, "00-trace-span-01")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestPublicTransport_RefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	base := PublicTransport()
+	defer base.CloseIdleConnections()
+	metrics := NewMetrics()
+	client := New(testConfig(), clock.Real(), WithBaseTransport(base), WithMetrics(metrics))
+
+	_, err := client.Get(server.URL)
+	assert.ErrorIs(t, err, ErrPrivateDestination)
+	assert.Zero(t, metrics.Snapshot()[server.Listener.Addr().String()].Retries, "refused destinations are not retried")
+
+	for _, address := range []string{"10.0.0.1:80", "169.254.169.254:80", "[::1]:443", "[fd00::1]:80", "100.64.0.1:80", "0.0.0.0:80", "[::ffff:127.0.0.1]:80"} {
+		assert.ErrorIs(t, denyPrivate("tcp", address, nil), ErrPrivateDestination, address)
+	}
+	for _, address := range []string{"93.184.216.34:443", "[2606:4700::1111]:443"} {
+		assert.NoError(t, denyPrivate("tcp", address, nil), address)
+	}
+}
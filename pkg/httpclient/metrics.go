@@ -0,0 +1,48 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+type DestinationStats struct {
+	Requests     int64         `json:"requests"`
+	Failures     int64         `json:"failures"`
+	Retries      int64         `json:"retries"`
+	Rejected     int64         `json:"rejected"`
+	TotalLatency time.Duration `json:"total_latency"`
+}
+
+// Metrics aggregates outbound call statistics per destination host. A single
+// instance can be shared by several clients via WithMetrics.
+type Metrics struct {
+	mu    sync.Mutex
+	hosts map[string]*DestinationStats
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{hosts: make(map[string]*DestinationStats)}
+}
+
+func (m *Metrics) Snapshot() map[string]DestinationStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[string]DestinationStats, len(m.hosts))
+	for host, stats := range m.hosts {
+		snapshot[host] = *stats
+	}
+	return snapshot
+}
+
+func (m *Metrics) record(host string, update func(*DestinationStats)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.hosts[host]
+	if !ok {
+		stats = &DestinationStats{}
+		m.hosts[host] = stats
+	}
+	update(stats)
+}
@@ -0,0 +1,54 @@
+// Package idgen assigns IDs in the application instead of a central
+// database sequence, so instances in several regions can insert rows
+// without coordinating.
+package idgen
+
+import (
+	"errors"
+	"fmt"
+
+	"backend-context-engineering-template/pkg/clock"
+)
+
+const (
+	// StrategySerial leaves ID assignment to the database sequence.
+	StrategySerial = "serial"
+	// StrategySnowflake generates time-ordered 63-bit IDs per node.
+	StrategySnowflake = "snowflake"
+	// StrategyUUIDv7 generates time-ordered 128-bit UUIDs.
+	StrategyUUIDv7 = "uuidv7"
+)
+
+// NodeIDUnset marks a node ID that was not configured. Snowflake refuses it
+// rather than defaulting, since every instance left on the same default
+// would generate the same IDs.
+const NodeIDUnset = -1
+
+// ErrNodeIDRequired is returned when snowflake is chosen without a node ID.
+var ErrNodeIDRequired = errors.New("snowflake IDs need a node ID unique to this instance")
+
+// ErrUUIDKeys is returned when UUIDv7 is chosen for 64-bit integer keys.
+var ErrUUIDKeys = errors.New("uuidv7 IDs need UUID key columns; use snowflake for integer keys")
+
+// Generator returns a new unique ID on every call.
+type Generator interface {
+	NextID() (int64, error)
+}
+
+// New returns the generator for strategy. Serial returns a nil Generator,
+// which repositories take to mean the database assigns IDs.
+func New(strategy string, nodeID int64, clk clock.Clock) (Generator, error) {
+	switch strategy {
+	case StrategySerial, "":
+		return nil, nil
+	case StrategySnowflake:
+		if nodeID == NodeIDUnset {
+			return nil, ErrNodeIDRequired
+		}
+		return NewSnowflake(nodeID, clk)
+	case StrategyUUIDv7:
+		return nil, ErrUUIDKeys
+	default:
+		return nil, fmt.Errorf("unsupported ID strategy %q", strategy)
+	}
+}
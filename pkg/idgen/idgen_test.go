@@ -0,0 +1,123 @@
+package idgen
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	gen, err := New(StrategySerial, 0, clock.Real())
+	require.NoError(t, err)
+	assert.Nil(t, gen)
+
+	gen, err = New(StrategySnowflake, 3, clock.Real())
+	require.NoError(t, err)
+	assert.IsType(t, &Snowflake{}, gen)
+
+	_, err = New(StrategyUUIDv7, 0, clock.Real())
+	assert.ErrorIs(t, err, ErrUUIDKeys)
+
+	_, err = New("ulid", 0, clock.Real())
+	assert.Error(t, err)
+
+	_, err = New(StrategySnowflake, MaxNodeID+1, clock.Real())
+	assert.Error(t, err)
+
+	_, err = New(StrategySnowflake, NodeIDUnset, clock.Real())
+	assert.ErrorIs(t, err, ErrNodeIDRequired)
+
+	gen, err = New(StrategySerial, NodeIDUnset, clock.Real())
+	require.NoError(t, err)
+	assert.Nil(t, gen, "serial IDs need no node ID")
+}
+
+func TestSnowflake_Layout(t *testing.T) {
+	start := Epoch.Add(1500 * time.Millisecond)
+	s, err := NewSnowflake(5, clock.NewFake(start))
+	require.NoError(t, err)
+
+	first, err := s.NextID()
+	require.NoError(t, err)
+	second, err := s.NextID()
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1500)<<22|5<<12, first)
+	assert.Equal(t, first+1, second)
+	assert.Equal(t, start, Time(first))
+}
+
+func TestSnowflake_SequenceOverflowAndClockSkew(t *testing.T) {
+	fake := clock.NewFake(Epoch.Add(time.Second))
+	s, err := NewSnowflake(1, fake)
+	require.NoError(t, err)
+
+	var last int64
+	for i := 0; i <= maxSequence+1; i++ {
+		id, err := s.NextID()
+		require.NoError(t, err)
+		require.Greater(t, id, last)
+		last = id
+	}
+	assert.Equal(t, Epoch.Add(time.Second+time.Millisecond), Time(last), "exhausted sequence moves to the next millisecond")
+
+	fake.Advance(-time.Minute)
+	id, err := s.NextID()
+	require.NoError(t, err)
+	assert.Greater(t, id, last, "IDs keep increasing when the clock steps back")
+}
+
+func TestSnowflake_ConcurrentUnique(t *testing.T) {
+	const workers, perWorker = 8, 2000
+
+	a, err := NewSnowflake(1, clock.Real())
+	require.NoError(t, err)
+	b, err := NewSnowflake(2, clock.Real())
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	seen := make(map[int64]bool, 2*workers*perWorker)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		for _, gen := range []*Snowflake{a, b} {
+			wg.Add(1)
+			go func(gen *Snowflake) {
+				defer wg.Done()
+				for i := 0; i < perWorker; i++ {
+					id, err := gen.NextID()
+					assert.NoError(t, err)
+					mu.Lock()
+					assert.False(t, seen[id], "duplicate id %d", id)
+					seen[id] = true
+					mu.Unlock()
+				}
+			}(gen)
+		}
+	}
+	wg.Wait()
+	assert.Len(t, seen, 2*workers*perWorker)
+}
+
+func TestUUIDv7(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	u, err := UUIDv7At(at)
+	require.NoError(t, err)
+
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), u.String())
+
+	var ms int64
+	for _, b := range u[:6] {
+		ms = ms<<8 | int64(b)
+	}
+	assert.Equal(t, at.UnixMilli(), ms)
+
+	later, err := UUIDv7At(at.Add(time.Millisecond))
+	require.NoError(t, err)
+	assert.Less(t, u.String(), later.String(), "UUIDs sort by creation time")
+}
@@ -0,0 +1,75 @@
+package idgen
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+)
+
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+
+	// MaxNodeID is the largest node ID a Snowflake accepts.
+	MaxNodeID   = 1<<nodeBits - 1
+	maxSequence = 1<<sequenceBits - 1
+)
+
+// Epoch is the zero time of Snowflake timestamps. The 41 timestamp bits
+// last about 69 years from it.
+var Epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Snowflake generates IDs laid out as 41 bits of milliseconds since Epoch,
+// 10 bits of node ID and a 12-bit per-millisecond sequence. IDs from one
+// node are strictly increasing, and IDs across nodes sort by creation
+// time, so keyset pagination over them keeps working.
+type Snowflake struct {
+	nodeID int64
+	clock  clock.Clock
+
+	mu       sync.Mutex
+	lastMS   int64
+	sequence int64
+}
+
+// NewSnowflake returns a generator for nodeID, which must be unique among
+// all running instances.
+func NewSnowflake(nodeID int64, clk clock.Clock) (*Snowflake, error) {
+	if nodeID < 0 || nodeID > MaxNodeID {
+		return nil, fmt.Errorf("snowflake node ID %d out of range [0, %d]", nodeID, MaxNodeID)
+	}
+	return &Snowflake{nodeID: nodeID, clock: clk}, nil
+}
+
+// NextID never blocks. When the sequence for a millisecond runs out, or
+// the wall clock steps backwards, it carries on in the next logical
+// millisecond, so timestamps may briefly run ahead of the clock.
+func (s *Snowflake) NextID() (int64, error) {
+	now := s.clock.Now().Sub(Epoch).Milliseconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case now > s.lastMS:
+		s.lastMS = now
+		s.sequence = 0
+	case s.sequence < maxSequence:
+		s.sequence++
+	default:
+		s.lastMS++
+		s.sequence = 0
+	}
+
+	if s.lastMS < 0 || s.lastMS >= 1<<41 {
+		return 0, fmt.Errorf("snowflake timestamp %d outside the epoch range", s.lastMS)
+	}
+	return s.lastMS<<(nodeBits+sequenceBits) | s.nodeID<<sequenceBits | s.sequence, nil
+}
+
+// Time returns when id was generated, to millisecond precision.
+func Time(id int64) time.Time {
+	return Epoch.Add(time.Duration(id>>(nodeBits+sequenceBits)) * time.Millisecond)
+}
@@ -0,0 +1,114 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const productSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "product",
+	"type": "object",
+	"required": ["id", "name", "tags", "created_at"],
+	"additionalProperties": false,
+	"properties": {
+		"id": {"type": "integer", "minimum": 1},
+		"name": {"type": "string", "minLength": 1},
+		"price": {"type": "number", "minimum": 0},
+		"status": {"type": "string", "enum": ["active", "inactive"]},
+		"note": {"type": ["string", "null"]},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"created_at": {"type": "string", "format": "date-time"}
+	}
+}`
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := Parse([]byte(productSchema))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		document   string
+		violations []string
+	}{
+		{
+			name:     "valid",
+			document: `{"id": 1, "name": "Größe", "price": 1.5, "status": "active", "note": null, "tags": ["a"], "created_at": "2024-03-01T09:30:00Z"}`,
+		},
+		{
+			name:       "missing required",
+			document:   `{"id": 1, "tags": []}`,
+			violations: []string{"$.name: is required", "$.created_at: is required"},
+		},
+		{
+			name:     "wrong types",
+			document: `{"id": 1.5, "name": 3, "tags": ["a", 2], "created_at": "2024-03-01T09:30:00Z"}`,
+			violations: []string{
+				"$.id: expected integer, got number",
+				"$.name: expected string, got integer",
+				"$.tags[1]: expected string, got integer",
+			},
+		},
+		{
+			name:     "constraints",
+			document: `{"id": 0, "name": "", "price": -1, "status": "archived", "tags": [], "created_at": "yesterday"}`,
+			violations: []string{
+				"$.created_at: must be an RFC 3339 date-time",
+				"$.id: must be at least 1",
+				"$.name: must be at least 1 characters",
+				"$.price: must be at least 0",
+				"$.status: must be one of [active inactive]",
+			},
+		},
+		{
+			name:       "additional property",
+			document:   `{"id": 1, "name": "a", "tags": [], "created_at": "2024-03-01T09:30:00Z", "extra": true}`,
+			violations: []string{"$.extra: is not allowed"},
+		},
+		{
+			name:       "not an object",
+			document:   `[1]`,
+			violations: []string{"$: expected object, got array"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.document))
+			if tt.violations == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.violations, validationErr.Violations)
+		})
+	}
+}
+
+func TestParse_RejectsUnsupportedKeywords(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "unknown keyword", schema: `{"type": "object", "oneOf": []}`},
+		{name: "unknown nested keyword", schema: `{"properties": {"a": {"pattern": "^x"}}}`},
+		{name: "unknown type", schema: `{"type": "decimal"}`},
+		{name: "unsupported format", schema: `{"type": "string", "format": "email"}`},
+		{name: "non-scalar enum", schema: `{"enum": [[1]]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.schema))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidationError_Error(t *testing.T) {
+	assert.Equal(t, "$.a: is required", (&ValidationError{Violations: []string{"$.a: is required"}}).Error())
+	assert.Equal(t, "$.a: is required (and 2 more)", (&ValidationError{Violations: []string{"$.a: is required", "b", "c"}}).Error())
+}
@@ -0,0 +1,163 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const drainPollInterval = 50 * time.Millisecond
+
+// Manager tracks readiness and in-flight work for graceful rollouts. Drain
+// flips readiness off, waits for in-flight requests (including long-lived
+// streams) to finish, and then closes the Drained channel so the process can
+// shut down.
+type Manager struct {
+	ready    atomic.Bool
+	inFlight atomic.Int64
+
+	// readyMu orders Drain against the end of WarmUp, so a warm-up that
+	// finishes after a drain began cannot mark the instance ready again.
+	readyMu  sync.Mutex
+	draining bool
+	onDrain  []func()
+
+	drainOnce sync.Once
+	drained   chan struct{}
+
+	// notifyMu orders readiness changes, so listeners see them in the
+	// order they were made.
+	notifyMu  sync.Mutex
+	listeners []func(ready bool)
+}
+
+func New() *Manager {
+	return &Manager{drained: make(chan struct{})}
+}
+
+func (m *Manager) SetReady(ready bool) {
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+	if m.ready.Swap(ready) == ready {
+		return
+	}
+	for _, fn := range m.listeners {
+		fn(ready)
+	}
+}
+
+// OnReadyChange calls fn with the current readiness and again whenever it
+// changes, so another transport's health service can follow it.
+func (m *Manager) OnReadyChange(fn func(ready bool)) {
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+	m.listeners = append(m.listeners, fn)
+	fn(m.Ready())
+}
+
+func (m *Manager) Ready() bool {
+	return m.ready.Load()
+}
+
+func (m *Manager) Begin() {
+	m.inFlight.Add(1)
+}
+
+func (m *Manager) End() {
+	m.inFlight.Add(-1)
+}
+
+func (m *Manager) InFlight() int64 {
+	return m.inFlight.Load()
+}
+
+// Drain marks the manager not ready and waits until nothing is in flight or
+// ctx is done, whichever comes first. The Drained channel is closed either
+// way; the returned count is the work still in flight at that point.
+func (m *Manager) Drain(ctx context.Context) int64 {
+	m.readyMu.Lock()
+	var onDrain []func()
+	if !m.draining {
+		onDrain = m.onDrain
+	}
+	m.draining = true
+	m.SetReady(false)
+	m.readyMu.Unlock()
+	defer m.drainOnce.Do(func() { close(m.drained) })
+
+	for _, fn := range onDrain {
+		fn()
+	}
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if remaining := m.InFlight(); remaining <= 0 {
+			return 0
+		}
+		select {
+		case <-ctx.Done():
+			return m.InFlight()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) Drained() <-chan struct{} {
+	return m.drained
+}
+
+// OnDrain calls fn when the first Drain begins, before it waits for
+// in-flight work, so streams that stay open until their client leaves can
+// end instead of holding the drain up until its deadline. fn is called
+// right away if a drain already began.
+func (m *Manager) OnDrain(fn func()) {
+	m.readyMu.Lock()
+	if !m.draining {
+		m.onDrain = append(m.onDrain, fn)
+		m.readyMu.Unlock()
+		return
+	}
+	m.readyMu.Unlock()
+	fn()
+}
+
+// Draining reports whether Drain has been called.
+func (m *Manager) Draining() bool {
+	m.readyMu.Lock()
+	defer m.readyMu.Unlock()
+	return m.draining
+}
+
+// Step is one unit of warm-up work run before the service reports ready.
+type Step struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// WarmUp runs the steps in order and marks the manager ready once every
+// step has succeeded. A failing step is reported in the returned error but
+// does not stop the remaining steps; the manager then stays not ready so the
+// caller can retry. A drain that began meanwhile is never undone.
+func (m *Manager) WarmUp(ctx context.Context, steps ...Step) error {
+	var errs []error
+	for _, step := range steps {
+		if err := step.Run(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	m.readyMu.Lock()
+	defer m.readyMu.Unlock()
+	if !m.draining {
+		m.SetReady(true)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
+)
+
+func TestManager_WarmUp(t *testing.T) {
+	m := New()
+
+	var ran []string
+	err := m.WarmUp(context.Background(),
+		Step{Name: "first", Run: func(ctx context.Context) error {
+			ran = append(ran, "first")
+			return errors.New("boom")
+		}},
+		Step{Name: "second", Run: func(ctx context.Context) error {
+			ran = append(ran, "second")
+			return nil
+		}},
+	)
+
+	assert.EqualError(t, err, "first: boom")
+	assert.Equal(t, []string{"first", "second"}, ran, "a failing step must not stop the rest")
+	assert.False(t, m.Ready(), "a failed warm-up must not mark the instance ready")
+
+	assert.NoError(t, m.WarmUp(context.Background()))
+	assert.True(t, m.Ready())
+}
+
+func TestManager_WarmUpAfterDrain(t *testing.T) {
+	m := New()
+
+	err := m.WarmUp(context.Background(), Step{Name: "slow", Run: func(ctx context.Context) error {
+		m.Drain(ctx)
+		return nil
+	}})
+
+	assert.NoError(t, err)
+	assert.True(t, m.Draining())
+	assert.False(t, m.Ready(), "warm-up must not undo a drain that began while it ran")
+}
+
+func TestManager_OnReadyChange(t *testing.T) {
+	m := New()
+
+	var seen []bool
+	m.OnReadyChange(func(ready bool) { seen = append(seen, ready) })
+	m.SetReady(true)
+	m.SetReady(true)
+	m.Drain(context.Background())
+
+	assert.Equal(t, []bool{false, true, false}, seen)
+}
+
+func TestManager_Drain(t *testing.T) {
+	m := New()
+	m.SetReady(true)
+	m.Begin()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, int64(1), m.Drain(ctx))
+	assert.False(t, m.Ready())
+
+	m.End()
+	assert.Equal(t, int64(0), m.Drain(context.Background()))
+
+	select {
+	case <-m.Drained():
+	default:
+		t.Fatal("drained channel should be closed")
+	}
+}
+
+func TestManager_DrainLeavesNoGoroutines(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	m := New()
+	m.SetReady(true)
+	m.Begin()
+
+	// Concurrent drains, e.g. the admin endpoint racing a SIGTERM, must all
+	// return once in-flight work ends.
+	done := make(chan int64, 3)
+	for i := 0; i < 3; i++ {
+		go func() { done <- m.Drain(context.Background()) }()
+	}
+
+	time.Sleep(2 * drainPollInterval)
+	m.End()
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, int64(0), <-done)
+	}
+	<-m.Drained()
+}
+
+func TestManager_OnDrain(t *testing.T) {
+	m := New()
+
+	// A stream stays in flight until its client leaves or it is ended.
+	ended := make(chan struct{})
+	m.Begin()
+	go func() {
+		<-ended
+		m.End()
+	}()
+	calls := 0
+	m.OnDrain(func() {
+		calls++
+		close(ended)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	assert.Equal(t, int64(0), m.Drain(ctx))
+	assert.Less(t, time.Since(start), time.Second, "the drain must not wait out its deadline")
+
+	m.Drain(ctx)
+	assert.Equal(t, 1, calls, "only the first drain ends the streams")
+
+	late := false
+	m.OnDrain(func() { late = true })
+	assert.True(t, late, "listeners added during a drain are called right away")
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey struct{}
+
+// fieldSet holds the fields a context's log lines carry. Contexts derived
+// from the one it was added to share it, so fields added while serving a
+// request reach every layer.
+type fieldSet struct {
+	mu     sync.RWMutex
+	fields logrus.Fields
+}
+
+var defaultLogger atomic.Pointer[logrus.Logger]
+
+func init() {
+	defaultLogger.Store(logrus.StandardLogger())
+}
+
+// SetDefault makes l the logger FromContext writes to. New sets it.
+func SetDefault(l *logrus.Logger) {
+	defaultLogger.Store(l)
+}
+
+// Default returns the logger set by SetDefault.
+func Default() *logrus.Logger {
+	return defaultLogger.Load()
+}
+
+// NewContext returns a copy of ctx whose log lines carry fields, on top of
+// those ctx already carries. Middleware starts a request's fields this way.
+func NewContext(ctx context.Context, fields logrus.Fields) context.Context {
+	set := &fieldSet{fields: make(logrus.Fields, len(fields))}
+	if parent, ok := ctx.Value(contextKey{}).(*fieldSet); ok {
+		parent.mu.RLock()
+		for key, value := range parent.fields {
+			set.fields[key] = value
+		}
+		parent.mu.RUnlock()
+	}
+	for key, value := range fields {
+		set.fields[key] = value
+	}
+	return context.WithValue(ctx, contextKey{}, set)
+}
+
+// AddFields adds fields to those ctx's log lines carry. Unlike NewContext,
+// it changes them for every context sharing ctx's fields, including those
+// derived before, so a field learned while serving a request, such as its
+// store, is logged from then on. It does nothing when ctx carries no
+// fields.
+func AddFields(ctx context.Context, fields logrus.Fields) {
+	set, ok := ctx.Value(contextKey{}).(*fieldSet)
+	if !ok {
+		return
+	}
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	for key, value := range fields {
+		set.fields[key] = value
+	}
+}
+
+// FromContext returns the entry to log with while serving ctx: the default
+// logger with the fields ctx carries. The entry carries ctx itself too, so
+// hooks such as the request ID's see it. Background work without request
+// fields logs through it all the same.
+func FromContext(ctx context.Context) *logrus.Entry {
+	entry := Default().WithContext(ctx)
+	if set, ok := ctx.Value(contextKey{}).(*fieldSet); ok {
+		set.mu.RLock()
+		entry = entry.WithFields(set.fields)
+		set.mu.RUnlock()
+	}
+	return entry
+}
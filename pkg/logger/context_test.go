@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"backend-context-engineering-template/pkg/requestid"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func captureDefault(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	l := New("info")
+	l.SetOutput(&buf)
+	t.Cleanup(func() { SetDefault(logrus.StandardLogger()) })
+	return &buf
+}
+
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestFromContext(t *testing.T) {
+	buf := captureDefault(t)
+
+	ctx := requestid.NewContext(context.Background(), "req-1")
+	ctx = NewContext(ctx, logrus.Fields{"caller": "user:7"})
+	// A context derived before the store is known still logs it.
+	derived, cancel := context.WithCancel(ctx)
+	defer cancel()
+	AddFields(ctx, logrus.Fields{"store_id": int64(3)})
+	FromContext(derived).WithField("product_id", 9).Info("Product created")
+
+	// Fields added to a child do not leak into its parent.
+	child := NewContext(ctx, logrus.Fields{"job": "import"})
+	FromContext(child).Info("Child")
+	FromContext(ctx).Info("Parent")
+
+	FromContext(context.Background()).Info("Background work")
+	AddFields(context.Background(), logrus.Fields{"ignored": true})
+
+	entries := lines(t, buf)
+	require.Len(t, entries, 4)
+	assert.Equal(t, "req-1", entries[0][requestid.Field])
+	assert.Equal(t, "user:7", entries[0]["caller"])
+	assert.Equal(t, float64(3), entries[0]["store_id"])
+	assert.Equal(t, float64(9), entries[0]["product_id"])
+	assert.Equal(t, "import", entries[1]["job"])
+	assert.Equal(t, float64(3), entries[1]["store_id"])
+	assert.NotContains(t, entries[2], "job")
+	assert.Equal(t, "Background work", entries[3]["message"])
+	assert.NotContains(t, entries[3], "caller")
+}
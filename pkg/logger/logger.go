@@ -4,9 +4,13 @@ import (
 	"os"
 	"strings"
 
+	"backend-context-engineering-template/pkg/requestid"
+
 	"github.com/sirupsen/logrus"
 )
 
+// New builds the process's logger and makes it the default FromContext
+// writes to.
 func New(level string) *logrus.Logger {
 	logger := logrus.New()
 
@@ -30,5 +34,9 @@ func New(level string) *logrus.Logger {
 	// Set output
 	logger.SetOutput(os.Stdout)
 
+	// Entries logged with a request's context name the request
+	logger.AddHook(requestid.Hook{})
+
+	SetDefault(logger)
 	return logger
 }
@@ -0,0 +1,87 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sent struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	msg  string
+}
+
+func newTestMailer(t *testing.T, cfg Config) (*SMTPMailer, *[]sent) {
+	m, err := New(cfg)
+	require.NoError(t, err)
+
+	var messages []sent
+	m.send = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		messages = append(messages, sent{addr: addr, auth: auth, from: from, to: to, msg: string(msg)})
+		return nil
+	}
+	m.now = func() time.Time { return time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC) }
+	return m, &messages
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	m, messages := newTestMailer(t, Config{Addr: "smtp.example.com:587", From: "Catalog <catalog@example.com>"})
+
+	require.NoError(t, m.Send(context.Background(), "owner@example.com", "Änderungen am 1. März", "Created: 2\nUpdated: 1\n"))
+
+	require.Len(t, *messages, 1)
+	msg := (*messages)[0]
+	assert.Equal(t, "smtp.example.com:587", msg.addr)
+	assert.Nil(t, msg.auth)
+	assert.Equal(t, "catalog@example.com", msg.from)
+	assert.Equal(t, []string{"owner@example.com"}, msg.to)
+	assert.Equal(t, "From: Catalog <catalog@example.com>\r\n"+
+		"To: owner@example.com\r\n"+
+		"Subject: =?utf-8?q?=C3=84nderungen_am_1._M=C3=A4rz?=\r\n"+
+		"Date: Fri, 01 Mar 2024 09:30:00 +0000\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=utf-8\r\n"+
+		"Content-Transfer-Encoding: quoted-printable\r\n"+
+		"\r\n"+
+		"Created: 2\r\nUpdated: 1\r\n", msg.msg)
+}
+
+func TestSMTPMailer_SendAuthenticates(t *testing.T) {
+	m, messages := newTestMailer(t, Config{Addr: "smtp.example.com:587", Username: "user", Password: "pass", From: "catalog@example.com"})
+
+	require.NoError(t, m.Send(context.Background(), "owner@example.com", "Digest", "body"))
+
+	require.Len(t, *messages, 1)
+	assert.NotNil(t, (*messages)[0].auth)
+}
+
+func TestSMTPMailer_SendRejectsHeaderInjection(t *testing.T) {
+	m, messages := newTestMailer(t, Config{Addr: "smtp.example.com:587", From: "catalog@example.com"})
+
+	assert.Error(t, m.Send(context.Background(), "owner@example.com\r\nBcc: victim@example.com", "Digest", "body"))
+	assert.Error(t, m.Send(context.Background(), "owner@example.com", "Digest\r\nBcc: victim@example.com", "body"))
+	assert.Empty(t, *messages)
+}
+
+func TestSMTPMailer_SendReportsRelayErrors(t *testing.T) {
+	m, _ := newTestMailer(t, Config{Addr: "smtp.example.com:587", From: "catalog@example.com"})
+	m.send = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("550 mailbox unavailable") }
+
+	assert.ErrorContains(t, m.Send(context.Background(), "owner@example.com", "Digest", "body"), "550 mailbox unavailable")
+}
+
+func TestNew_RejectsInvalidConfig(t *testing.T) {
+	_, err := New(Config{Addr: "smtp.example.com", From: "catalog@example.com"})
+	assert.Error(t, err)
+
+	_, err = New(Config{Addr: "smtp.example.com:587", From: "not an address"})
+	assert.Error(t, err)
+}
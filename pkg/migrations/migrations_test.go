@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	sqlmigrations "backend-context-engineering-template/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_add_unit.up.sql":             {Data: []byte("ALTER TABLE products ADD COLUMN unit TEXT;")},
+		"002_add_unit.down.sql":           {Data: []byte("ALTER TABLE products DROP COLUMN unit;")},
+		"010_create_stores.up.sql":        {Data: []byte("CREATE TABLE stores (id BIGINT);")},
+		"001_create_products.up.sql":      {Data: []byte("CREATE TABLE products (id BIGINT);")},
+		"001_create_products.down.sql":    {Data: []byte("DROP TABLE products;")},
+		"README.md":                       {Data: []byte("not a migration")},
+		"migrations.go":                   {Data: []byte("package migrations")},
+		"archive/000_old_schema.up.sql":   {Data: []byte("SELECT 1;")},
+		"archive/000_old_schema.down.sql": {Data: []byte("SELECT 1;")},
+	}
+
+	migrations, err := Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, migrations, 3)
+
+	assert.Equal(t, Migration{Version: 1, Name: "create_products", Up: "CREATE TABLE products (id BIGINT);", Down: "DROP TABLE products;"}, migrations[0])
+	assert.Equal(t, int64(2), migrations[1].Version)
+	assert.Equal(t, Migration{Version: 10, Name: "create_stores", Up: "CREATE TABLE stores (id BIGINT);"}, migrations[2])
+}
+
+func TestLoad_Rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "down without up",
+			fsys: fstest.MapFS{"001_create_products.down.sql": {Data: []byte("DROP TABLE products;")}},
+		},
+		{
+			name: "one version with two names",
+			fsys: fstest.MapFS{
+				"001_create_products.up.sql": {Data: []byte("CREATE TABLE products (id BIGINT);")},
+				"001_create_stores.up.sql":   {Data: []byte("CREATE TABLE stores (id BIGINT);")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// The shipped migrations must load, and each must be reversible so that
+// cmd/migrate down can step through all of them.
+func TestLoad_EmbeddedMigrations(t *testing.T) {
+	migrations, err := Load(sqlmigrations.Files)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		assert.Equal(t, int64(i+1), m.Version, "migration versions must be contiguous")
+		assert.NotEmpty(t, m.Down, "migration %d_%s has no down file", m.Version, m.Name)
+	}
+}
@@ -0,0 +1,46 @@
+package pagination
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bind reads the page a list request asks for from its query string:
+// limit, sort among sorts, and where to start, as offset or cursor. A
+// cursor keeps the sort it was handed out for, so sort may be left out
+// next to it. A limit or offset that is not a usable number falls back to
+// its default, as list endpoints always have, and a limit over MaxLimit is
+// lowered to it, so the page's next cursor matches what was listed. An
+// unknown sort, a cursor that was not handed out, or one sent with a
+// different sort is an *Error.
+func Bind(c *gin.Context, sorts SortSpec) (Page, error) {
+	page := Page{Limit: DefaultLimit}
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		page.Limit = min(l, MaxLimit)
+	}
+
+	sort := c.Query("sort")
+	if cursor := c.Query("cursor"); cursor != "" {
+		if c.Query("offset") != "" {
+			return Page{}, &Error{Param: "cursor", Rule: "excluded_with", Message: "cursor and offset cannot be combined"}
+		}
+		decoded, err := DecodeCursor(cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		if sort != "" && !sorts.Same(sort, decoded.Sort) {
+			return Page{}, &Error{Param: "cursor", Rule: "sort", Message: "cursor was handed out for another sort"}
+		}
+		sort = decoded.Sort
+		page.Offset = decoded.Offset
+	} else if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		page.Offset = o
+	}
+
+	var err error
+	if page.Sort, err = sorts.Resolve(sort); err != nil {
+		return Page{}, err
+	}
+	return page, nil
+}
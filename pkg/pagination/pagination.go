@@ -0,0 +1,115 @@
+// Package pagination describes pages of listings: how many items a page
+// holds and where it starts, given either as an offset or as an opaque
+// cursor handed out with the page before. Cursors remember the order they
+// were handed out in, so one cannot be used to page through a listing
+// sorted another way.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultLimit is how many items a page holds unless asked otherwise.
+	DefaultLimit = 10
+	// MaxLimit is the most items a page holds; larger limits are lowered
+	// to it.
+	MaxLimit = 100
+)
+
+// Page is a window of a listing.
+type Page struct {
+	Limit  int
+	Offset int
+	// Sort is the order the listing is in, one of its SortSpec; empty for
+	// its default order.
+	Sort string
+}
+
+// Next returns the cursor of the page after p, given how many items p
+// held. There is none when p was not full.
+func (p Page) Next(n int) (Cursor, bool) {
+	if p.Limit <= 0 || n < p.Limit {
+		return Cursor{}, false
+	}
+	return Cursor{Offset: p.Offset + n, Sort: p.Sort}, true
+}
+
+// Cursor is where a page starts.
+type Cursor struct {
+	Offset int    `json:"o"`
+	Sort   string `json:"s,omitempty"`
+}
+
+// Encode returns the cursor as clients see it.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads a cursor Encode returned.
+func DecodeCursor(s string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, invalidCursor()
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < 0 {
+		return Cursor{}, invalidCursor()
+	}
+	return c, nil
+}
+
+// SortSpec lists the orders a listing can be sorted in. The first is its
+// default.
+type SortSpec []string
+
+// Resolve checks that key names one of the orders. An empty key is the
+// default order, as is a key for listings with a single order.
+func (s SortSpec) Resolve(key string) (string, error) {
+	if key == "" || len(s) == 0 {
+		return "", nil
+	}
+	if !slices.Contains(s, key) {
+		return "", &Error{
+			Param:   "sort",
+			Rule:    "oneof",
+			Message: fmt.Sprintf("sort must be one of %s", strings.Join(s, ", ")),
+		}
+	}
+	return key, nil
+}
+
+// Same reports whether two keys name the same order, an empty key naming
+// the default.
+func (s SortSpec) Same(a, b string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	if a == "" {
+		a = s[0]
+	}
+	if b == "" {
+		b = s[0]
+	}
+	return a == b
+}
+
+// Error is a pagination parameter that was refused.
+type Error struct {
+	Param   string
+	Rule    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func invalidCursor() error {
+	return &Error{Param: "cursor", Rule: "cursor", Message: "cursor must be one handed out with a previous page"}
+}
@@ -0,0 +1,101 @@
+package pagination
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSorts = SortSpec{"newest", "popularity"}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	for _, c := range []Cursor{{}, {Offset: 40}, {Offset: 7, Sort: "popularity"}} {
+		got, err := DecodeCursor(c.Encode())
+		require.NoError(t, err)
+		assert.Equal(t, c, got)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, s := range []string{"not base64!", "bm9wZQ", Cursor{Offset: -1}.Encode()} {
+		_, err := DecodeCursor(s)
+		var perr *Error
+		require.True(t, errors.As(err, &perr), s)
+		assert.Equal(t, "cursor", perr.Param)
+	}
+}
+
+func TestPage_Next(t *testing.T) {
+	p := Page{Limit: 10, Offset: 20, Sort: "popularity"}
+
+	next, ok := p.Next(10)
+	require.True(t, ok)
+	assert.Equal(t, Cursor{Offset: 30, Sort: "popularity"}, next)
+
+	_, ok = p.Next(9)
+	assert.False(t, ok, "a page that was not full is the last")
+}
+
+func TestSortSpec(t *testing.T) {
+	sort, err := testSorts.Resolve("")
+	require.NoError(t, err)
+	assert.Empty(t, sort)
+
+	sort, err = testSorts.Resolve("popularity")
+	require.NoError(t, err)
+	assert.Equal(t, "popularity", sort)
+
+	_, err = testSorts.Resolve("price")
+	var perr *Error
+	require.True(t, errors.As(err, &perr))
+	assert.Equal(t, "sort", perr.Param)
+
+	assert.True(t, testSorts.Same("", "newest"))
+	assert.False(t, testSorts.Same("", "popularity"))
+	assert.True(t, SortSpec(nil).Same("", "anything"))
+}
+
+func TestBind(t *testing.T) {
+	popular := Cursor{Offset: 20, Sort: "popularity"}.Encode()
+
+	tests := []struct {
+		name      string
+		query     string
+		want      Page
+		wantParam string
+	}{
+		{name: "defaults", query: "", want: Page{Limit: DefaultLimit}},
+		{name: "limit and offset", query: "limit=5&offset=15&sort=popularity", want: Page{Limit: 5, Offset: 15, Sort: "popularity"}},
+		{name: "unusable numbers fall back", query: "limit=-1&offset=abc", want: Page{Limit: DefaultLimit}},
+		{name: "oversized limit is lowered", query: "limit=500", want: Page{Limit: MaxLimit}},
+		{name: "cursor keeps its sort", query: "limit=5&cursor=" + popular, want: Page{Limit: 5, Offset: 20, Sort: "popularity"}},
+		{name: "cursor with its sort", query: "cursor=" + popular + "&sort=popularity", want: Page{Limit: DefaultLimit, Offset: 20, Sort: "popularity"}},
+		{name: "default sort named", query: "cursor=" + Cursor{Offset: 10}.Encode() + "&sort=newest", want: Page{Limit: DefaultLimit, Offset: 10}},
+		{name: "unknown sort", query: "sort=price", wantParam: "sort"},
+		{name: "cursor with another sort", query: "cursor=" + popular + "&sort=newest", wantParam: "cursor"},
+		{name: "cursor with offset", query: "cursor=" + popular + "&offset=5", wantParam: "cursor"},
+		{name: "invalid cursor", query: "cursor=nope", wantParam: "cursor"},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/items?"+tt.query, nil)
+
+			page, err := Bind(c, testSorts)
+			if tt.wantParam != "" {
+				var perr *Error
+				require.True(t, errors.As(err, &perr))
+				assert.Equal(t, tt.wantParam, perr.Param)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, page)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package previewtoken
+
+import (
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func TestSigner_IssueVerify(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	signer := NewSigner(testSecret, fake)
+
+	token, expiresAt, err := signer.Issue(42, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, fake.Now().Add(time.Hour), expiresAt)
+	assert.NoError(t, signer.Verify(token, 42))
+
+	t.Run("other product", func(t *testing.T) {
+		assert.ErrorIs(t, signer.Verify(token, 43), ErrInvalidToken)
+	})
+
+	t.Run("other secret", func(t *testing.T) {
+		other := NewSigner([]byte("fedcba9876543210fedcba9876543210"), fake)
+		assert.ErrorIs(t, other.Verify(token, 42), ErrInvalidToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		later := NewSigner(testSecret, clock.NewFake(fake.Now().Add(61*time.Minute)))
+		assert.ErrorIs(t, later.Verify(token, 42), ErrInvalidToken)
+	})
+
+	t.Run("other kind", func(t *testing.T) {
+		access, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+			Kind: "access",
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "42",
+				ExpiresAt: jwt.NewNumericDate(fake.Now().Add(time.Hour)),
+			},
+		}).SignedString(testSecret)
+		require.NoError(t, err)
+		assert.ErrorIs(t, signer.Verify(access, 42), ErrInvalidToken)
+	})
+}
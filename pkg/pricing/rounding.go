@@ -0,0 +1,168 @@
+// Package pricing rounds computed prices to the price points a store shows,
+// such as prices ending in .99 or, for CHF, multiples of 0.05. Amounts are
+// handled in minor units (cents), so rounding is exact.
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RoundNone keeps the amount at minor-unit precision.
+	RoundNone = "none"
+	// RoundIncrement rounds to a multiple of Step.
+	RoundIncrement = "increment"
+	// RoundEnding rounds to an amount ending in Ending, such as x.99.
+	RoundEnding = "ending"
+
+	DirectionNearest = "nearest"
+	DirectionUp      = "up"
+	DirectionDown    = "down"
+)
+
+// MaxDecimals is the most minor-unit digits a currency may have.
+const MaxDecimals = 4
+
+// Rule is a rounding rule. Step and Ending are in minor units.
+//
+// An ending rule picks amounts that end in Ending within blocks of the
+// smallest power of ten above it: Ending 99 gives 0.99, 1.99, 2.99 and so
+// on, Ending 950 gives 9.50, 19.50, 29.50.
+type Rule struct {
+	Strategy  string
+	Step      int64
+	Ending    int64
+	Direction string
+}
+
+func (r Rule) Validate() error {
+	switch r.Direction {
+	case DirectionNearest, DirectionUp, DirectionDown:
+	default:
+		return errors.New("direction must be nearest, up or down")
+	}
+
+	switch r.Strategy {
+	case RoundNone:
+	case RoundIncrement:
+		if r.Step <= 0 {
+			return errors.New("step must be positive")
+		}
+	case RoundEnding:
+		if r.Ending <= 0 {
+			return errors.New("ending must be positive")
+		}
+	default:
+		return errors.New("rounding must be none, increment or ending")
+	}
+	return nil
+}
+
+// Apply rounds a non-negative amount. The result is never negative: a
+// rounding down that would go below zero rounds up instead.
+func (r Rule) Apply(amount int64) int64 {
+	switch r.Strategy {
+	case RoundIncrement:
+		return r.pick(amount, amount/r.Step*r.Step, r.Step)
+	case RoundEnding:
+		block := blockSize(r.Ending)
+		below := amount/block*block + r.Ending
+		if below > amount {
+			below -= block
+		}
+		return r.pick(amount, below, block)
+	default:
+		return amount
+	}
+}
+
+// pick chooses between below, the largest candidate not above amount, and
+// the next candidate spacing above it. Ties round up.
+func (r Rule) pick(amount, below, spacing int64) int64 {
+	if below == amount {
+		return amount
+	}
+	above := below + spacing
+	switch {
+	case below < 0:
+		return above
+	case r.Direction == DirectionUp:
+		return above
+	case r.Direction == DirectionDown:
+		return below
+	case amount-below < above-amount:
+		return below
+	default:
+		return above
+	}
+}
+
+func blockSize(ending int64) int64 {
+	block := int64(10)
+	for block <= ending {
+		block *= 10
+	}
+	return block
+}
+
+// ErrAmountOutOfRange is returned for amounts that are not finite or do not
+// fit in minor units.
+var ErrAmountOutOfRange = errors.New("amount is not a finite number within range")
+
+// ToMinor converts an amount to minor units, rounding half away from zero.
+// It rounds the amount's shortest decimal form rather than its binary
+// value, so 1.005 becomes 101 cents and not 100. NaN, infinities and amounts
+// too large for int64 minor units return ErrAmountOutOfRange rather than a
+// price of zero.
+func ToMinor(amount float64, decimals int) (int64, error) {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, ErrAmountOutOfRange
+	}
+
+	digits := strconv.FormatFloat(math.Abs(amount), 'f', -1, 64)
+	whole, fraction, _ := strings.Cut(digits, ".")
+	fraction += strings.Repeat("0", decimals+1)
+
+	minor, err := strconv.ParseInt(whole+fraction[:decimals], 10, 64)
+	if err != nil {
+		return 0, ErrAmountOutOfRange
+	}
+	if fraction[decimals] >= '5' {
+		if minor == math.MaxInt64 {
+			return 0, ErrAmountOutOfRange
+		}
+		minor++
+	}
+	if amount < 0 {
+		return -minor, nil
+	}
+	return minor, nil
+}
+
+// FromMinor converts minor units back to an amount.
+func FromMinor(minor int64, decimals int) float64 {
+	return float64(minor) / math.Pow10(decimals)
+}
+
+// ExactMinor converts an amount given in major units, such as a step of
+// 0.05, to minor units, and fails if it has more digits than decimals.
+func ExactMinor(amount float64, decimals int) (int64, error) {
+	minor, err := ToMinor(amount, decimals)
+	if err != nil {
+		return 0, err
+	}
+	if FromMinor(minor, decimals) != amount {
+		return 0, fmt.Errorf("%s has more than %d decimals", strconv.FormatFloat(amount, 'f', -1, 64), decimals)
+	}
+	return minor, nil
+}
+
+// Format renders minor units with the currency's number of decimals, such
+// as "12.35" or, for zero decimals, "1235".
+func Format(minor int64, decimals int) string {
+	return strconv.FormatFloat(FromMinor(minor, decimals), 'f', decimals, 64)
+}
@@ -0,0 +1,181 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule_ApplyIncrement(t *testing.T) {
+	nickel := func(direction string) Rule { return Rule{Strategy: RoundIncrement, Step: 5, Direction: direction} }
+
+	tests := []struct {
+		name   string
+		rule   Rule
+		amount int64
+		want   int64
+	}{
+		{"zero", nickel(DirectionNearest), 0, 0},
+		{"exact multiple", nickel(DirectionNearest), 1235, 1235},
+		{"one below midpoint", nickel(DirectionNearest), 1232, 1230},
+		{"one above multiple", nickel(DirectionNearest), 1231, 1230},
+		{"one below multiple", nickel(DirectionNearest), 1234, 1235},
+		{"midpoint of even step rounds up", Rule{Strategy: RoundIncrement, Step: 10, Direction: DirectionNearest}, 1235, 1240},
+		{"just above midpoint", nickel(DirectionNearest), 1233, 1235},
+		{"smallest amount", nickel(DirectionNearest), 1, 0},
+		{"smallest amount at midpoint of step 2", Rule{Strategy: RoundIncrement, Step: 2, Direction: DirectionNearest}, 1, 2},
+		{"up from one above", nickel(DirectionUp), 1231, 1235},
+		{"up keeps exact multiple", nickel(DirectionUp), 1235, 1235},
+		{"up from zero", nickel(DirectionUp), 0, 0},
+		{"up from smallest amount", nickel(DirectionUp), 1, 5},
+		{"down from one below", nickel(DirectionDown), 1234, 1230},
+		{"down keeps exact multiple", nickel(DirectionDown), 1230, 1230},
+		{"down from smallest amount", nickel(DirectionDown), 4, 0},
+		{"step of one is a no-op", Rule{Strategy: RoundIncrement, Step: 1, Direction: DirectionNearest}, 1237, 1237},
+		{"whole units", Rule{Strategy: RoundIncrement, Step: 100, Direction: DirectionNearest}, 1250, 1300},
+		{"whole units below midpoint", Rule{Strategy: RoundIncrement, Step: 100, Direction: DirectionNearest}, 1249, 1200},
+		{"step larger than amount", Rule{Strategy: RoundIncrement, Step: 500, Direction: DirectionNearest}, 249, 0},
+		{"step larger than amount at midpoint", Rule{Strategy: RoundIncrement, Step: 500, Direction: DirectionNearest}, 250, 500},
+		{"large amount", nickel(DirectionNearest), 9_999_999_998, 10_000_000_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.Apply(tt.amount))
+		})
+	}
+}
+
+func TestRule_ApplyEnding(t *testing.T) {
+	ninetyNine := func(direction string) Rule { return Rule{Strategy: RoundEnding, Ending: 99, Direction: direction} }
+
+	tests := []struct {
+		name   string
+		rule   Rule
+		amount int64
+		want   int64
+	}{
+		{"already ends in .99", ninetyNine(DirectionNearest), 1299, 1299},
+		{"whole amount rounds down a cent", ninetyNine(DirectionNearest), 1300, 1299},
+		{"just above .99", ninetyNine(DirectionNearest), 1301, 1299},
+		{"midpoint rounds up", ninetyNine(DirectionNearest), 1349, 1399},
+		{"just below midpoint", ninetyNine(DirectionNearest), 1348, 1299},
+		{"just above midpoint", ninetyNine(DirectionNearest), 1350, 1399},
+		{"just below .99", ninetyNine(DirectionNearest), 1298, 1299},
+		{"below first ending", ninetyNine(DirectionNearest), 50, 99},
+		{"zero", ninetyNine(DirectionNearest), 0, 99},
+		{"up from whole amount", ninetyNine(DirectionUp), 1300, 1399},
+		{"up from .98", ninetyNine(DirectionUp), 1298, 1299},
+		{"up keeps .99", ninetyNine(DirectionUp), 1299, 1299},
+		{"down from .98", ninetyNine(DirectionDown), 1298, 1199},
+		{"down from whole amount", ninetyNine(DirectionDown), 1300, 1299},
+		{"down keeps .99", ninetyNine(DirectionDown), 1299, 1299},
+		{"down below first ending goes up", ninetyNine(DirectionDown), 98, 99},
+		{"down from zero goes up", ninetyNine(DirectionDown), 0, 99},
+		{".95 ending", Rule{Strategy: RoundEnding, Ending: 95, Direction: DirectionNearest}, 1210, 1195},
+		{".50 ending", Rule{Strategy: RoundEnding, Ending: 50, Direction: DirectionNearest}, 1274, 1250},
+		{".50 ending just past midpoint", Rule{Strategy: RoundEnding, Ending: 50, Direction: DirectionNearest}, 1200, 1250},
+		{"9.99 ending", Rule{Strategy: RoundEnding, Ending: 999, Direction: DirectionNearest}, 2450, 1999},
+		{"9.99 ending at midpoint", Rule{Strategy: RoundEnding, Ending: 999, Direction: DirectionNearest}, 2499, 2999},
+		{"ending 9 with no decimals", Rule{Strategy: RoundEnding, Ending: 9, Direction: DirectionNearest}, 1234, 1239},
+		{"ending 9 with no decimals, down", Rule{Strategy: RoundEnding, Ending: 9, Direction: DirectionDown}, 1234, 1229},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.Apply(tt.amount))
+		})
+	}
+}
+
+func TestRule_ApplyNone(t *testing.T) {
+	for _, amount := range []int64{0, 1, 1299, 1_000_000} {
+		assert.Equal(t, amount, Rule{Strategy: RoundNone, Direction: DirectionNearest}.Apply(amount))
+	}
+}
+
+func TestRule_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{name: "none", rule: Rule{Strategy: RoundNone, Direction: DirectionNearest}},
+		{name: "increment", rule: Rule{Strategy: RoundIncrement, Step: 5, Direction: DirectionUp}},
+		{name: "ending", rule: Rule{Strategy: RoundEnding, Ending: 99, Direction: DirectionDown}},
+		{name: "zero step", rule: Rule{Strategy: RoundIncrement, Direction: DirectionNearest}, wantErr: true},
+		{name: "negative step", rule: Rule{Strategy: RoundIncrement, Step: -5, Direction: DirectionNearest}, wantErr: true},
+		{name: "zero ending", rule: Rule{Strategy: RoundEnding, Direction: DirectionNearest}, wantErr: true},
+		{name: "unknown strategy", rule: Rule{Strategy: "bankers", Direction: DirectionNearest}, wantErr: true},
+		{name: "unknown direction", rule: Rule{Strategy: RoundNone, Direction: "sideways"}, wantErr: true},
+		{name: "missing direction", rule: Rule{Strategy: RoundNone}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestToMinor(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		decimals int
+		want     int64
+	}{
+		{19.99, 2, 1999},
+		{0.1 + 0.2, 2, 30},
+		{1.005, 2, 101},
+		{1.015, 2, 102},
+		{1.0049999, 2, 100},
+		{-1.005, 2, -101},
+		{0.125, 2, 13},
+		{1234.5, 0, 1235},
+		{1.2345, 4, 12345},
+		{0, 2, 0},
+		{1e-7, 2, 0},
+		{12345678.9, 2, 1234567890},
+	}
+
+	for _, tt := range tests {
+		minor, err := ToMinor(tt.amount, tt.decimals)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, minor, "%v with %d decimals", tt.amount, tt.decimals)
+	}
+
+	for _, amount := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1e17, -1e17, math.MaxFloat64} {
+		_, err := ToMinor(amount, 2)
+		assert.ErrorIs(t, err, ErrAmountOutOfRange, "%v", amount)
+	}
+}
+
+func TestExactMinor(t *testing.T) {
+	minor, err := ExactMinor(0.05, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), minor)
+
+	minor, err = ExactMinor(0.99, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(99), minor)
+
+	_, err = ExactMinor(0.005, 2)
+	assert.ErrorContains(t, err, "0.005 has more than 2 decimals")
+
+	_, err = ExactMinor(0.5, 0)
+	assert.Error(t, err)
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "12.35", Format(1235, 2))
+	assert.Equal(t, "0.05", Format(5, 2))
+	assert.Equal(t, "0.00", Format(0, 2))
+	assert.Equal(t, "1235", Format(1235, 0))
+	assert.Equal(t, "1.235", Format(1235, 3))
+}
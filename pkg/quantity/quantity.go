@@ -0,0 +1,278 @@
+// Package quantity is an exact decimal amount of stock, such as 12 pieces
+// or 1.25 kg. Quantities are counted in thousandths, so adding and
+// comparing them never drifts the way float64 would.
+package quantity
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxDecimals is the finest precision a quantity can have: a thousandth,
+// such as a gram of a kilogram.
+const MaxDecimals = 3
+
+const scale = 1000
+
+// maxMilli is the largest amount a NUMERIC(15,3) column holds:
+// 999999999999.999.
+const maxMilli = 999_999_999_999_999
+
+// Max is the largest quantity; -Max is the smallest.
+var Max = Quantity{milli: maxMilli}
+
+// ErrOutOfRange is returned when a quantity would exceed Max or fall
+// below -Max.
+var ErrOutOfRange = errors.New("quantity out of range")
+
+// Quantity is an amount with up to MaxDecimals decimals. The zero value
+// is 0.
+type Quantity struct {
+	milli int64
+}
+
+// New returns a whole quantity. It is for constants; it panics outside
+// the range FromInt accepts.
+func New(whole int64) Quantity {
+	q, err := FromInt(whole)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// FromInt returns a whole quantity, or ErrOutOfRange when whole does not
+// fit between -Max and Max.
+func FromInt(whole int64) (Quantity, error) {
+	if whole > maxMilli/scale || whole < -maxMilli/scale {
+		return Quantity{}, fmt.Errorf("%w: %d", ErrOutOfRange, whole)
+	}
+	return Quantity{milli: whole * scale}, nil
+}
+
+func checked(milli int64) (Quantity, error) {
+	if milli > maxMilli || milli < -maxMilli {
+		return Quantity{}, ErrOutOfRange
+	}
+	return Quantity{milli: milli}, nil
+}
+
+// Parse reads a decimal such as "12", "1.25" or "-0.5". Exponents and
+// more than MaxDecimals decimals are rejected rather than rounded.
+func Parse(s string) (Quantity, error) {
+	text := s
+	negative := strings.HasPrefix(text, "-")
+	if negative {
+		text = text[1:]
+	}
+
+	whole, fraction, hasPoint := strings.Cut(text, ".")
+	if whole == "" || (hasPoint && fraction == "") || !digits(whole) || !digits(fraction) {
+		return Quantity{}, fmt.Errorf("invalid quantity %q", s)
+	}
+	if len(fraction) > MaxDecimals {
+		return Quantity{}, fmt.Errorf("quantity %q has more than %d decimals", s, MaxDecimals)
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > maxMilli/scale {
+		return Quantity{}, fmt.Errorf("%w: %q", ErrOutOfRange, s)
+	}
+	milli := units * scale
+	if fraction != "" {
+		part, _ := strconv.ParseInt(fraction+strings.Repeat("0", MaxDecimals-len(fraction)), 10, 64)
+		milli += part
+	}
+	if negative {
+		milli = -milli
+	}
+	return Quantity{milli: milli}, nil
+}
+
+// MustParse is Parse for constants; it panics on invalid input.
+func MustParse(s string) Quantity {
+	q, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the quantity with as few decimals as it needs: "12",
+// "1.25", "-0.5".
+func (q Quantity) String() string {
+	milli := q.milli
+	sign := ""
+	if milli < 0 {
+		sign = "-"
+		milli = -milli
+	}
+
+	s := sign + strconv.FormatInt(milli/scale, 10)
+	if fraction := milli % scale; fraction != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%03d", fraction), "0")
+	}
+	return s
+}
+
+// Sign returns -1, 0 or 1.
+func (q Quantity) Sign() int {
+	switch {
+	case q.milli < 0:
+		return -1
+	case q.milli > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Cmp returns -1, 0 or 1 as q is less than, equal to or greater than
+// other.
+func (q Quantity) Cmp(other Quantity) int {
+	switch {
+	case q.milli < other.milli:
+		return -1
+	case q.milli > other.milli:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Decimals is the number of decimals the quantity needs, from 0 for a
+// whole quantity to MaxDecimals.
+func (q Quantity) Decimals() int {
+	n := 0
+	for rest := q.milli % scale; rest != 0; rest = (rest * 10) % scale {
+		n++
+	}
+	return n
+}
+
+// Whole returns the quantity truncated to whole units.
+func (q Quantity) Whole() int64 {
+	return q.milli / scale
+}
+
+func (q Quantity) Float64() float64 {
+	return float64(q.milli) / scale
+}
+
+// Add returns q + other, or ErrOutOfRange when the sum leaves the range.
+// Both operands are within Max, so the sum itself cannot overflow int64.
+func (q Quantity) Add(other Quantity) (Quantity, error) {
+	return checked(q.milli + other.milli)
+}
+
+// Sub returns q - other, or ErrOutOfRange when the difference leaves the
+// range.
+func (q Quantity) Sub(other Quantity) (Quantity, error) {
+	return checked(q.milli - other.milli)
+}
+
+// Mul returns the quantity n times over, or ErrOutOfRange when the
+// product leaves the range.
+func (q Quantity) Mul(n int64) (Quantity, error) {
+	if q.milli != 0 && (n > maxMilli/abs(q.milli) || n < -maxMilli/abs(q.milli)) {
+		return Quantity{}, ErrOutOfRange
+	}
+	return checked(q.milli * n)
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Fits returns how many whole times per fits into q, such as 3 for 2 kg
+// in 6.5 kg. It is 0 when either quantity is not positive.
+func (q Quantity) Fits(per Quantity) int64 {
+	if q.milli <= 0 || per.milli <= 0 {
+		return 0
+	}
+	return q.milli / per.milli
+}
+
+// MarshalJSON writes the quantity as a JSON number, so whole quantities
+// look exactly like the integers they used to be.
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number.
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	parsed, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+func (q Quantity) MarshalText() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+func (q *Quantity) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+// Value stores the quantity as a decimal string for a NUMERIC column.
+func (q Quantity) Value() (driver.Value, error) {
+	return q.String(), nil
+}
+
+// Scan reads a NUMERIC or integer column. NUMERIC columns may carry
+// trailing zeros beyond MaxDecimals, such as "1.2500"; they are accepted.
+func (q *Quantity) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		parsed, err := FromInt(v)
+		if err != nil {
+			return err
+		}
+		*q = parsed
+		return nil
+	case []byte:
+		return q.scanText(string(v))
+	case string:
+		return q.scanText(v)
+	case nil:
+		return errors.New("cannot scan NULL into a quantity")
+	default:
+		return fmt.Errorf("cannot scan %T into a quantity", src)
+	}
+}
+
+func (q *Quantity) scanText(s string) error {
+	if whole, fraction, ok := strings.Cut(s, "."); ok && len(fraction) > MaxDecimals {
+		s = whole + "." + strings.TrimRight(fraction, "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
@@ -0,0 +1,137 @@
+package quantity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in       string
+		want     string
+		decimals int
+	}{
+		{in: "12", want: "12", decimals: 0},
+		{in: "0", want: "0", decimals: 0},
+		{in: "-0", want: "0", decimals: 0},
+		{in: "1.25", want: "1.25", decimals: 2},
+		{in: "1.250", want: "1.25", decimals: 2},
+		{in: "0.001", want: "0.001", decimals: 3},
+		{in: "-0.5", want: "-0.5", decimals: 1},
+		{in: "007.10", want: "7.1", decimals: 1},
+		{in: "999999999999.999", want: "999999999999.999", decimals: 3},
+		{in: "-999999999999.999", want: "-999999999999.999", decimals: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			q, err := Parse(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, q.String())
+			assert.Equal(t, tt.decimals, q.Decimals())
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, in := range []string{"", "-", ".5", "1.", "1.2345", "1e3", "+1", "1,5", " 1", "abc", "1000000000000", "9223372036854775807"} {
+		_, err := Parse(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestQuantity_Arithmetic(t *testing.T) {
+	a := MustParse("0.1")
+	b := MustParse("0.2")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, MustParse("0.3"), sum)
+	difference, err := a.Sub(b)
+	require.NoError(t, err)
+	assert.Equal(t, MustParse("-0.1"), difference)
+	assert.Equal(t, -1, difference.Sign())
+	assert.Equal(t, 0, Quantity{}.Sign())
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.Equal(t, 1, b.Cmp(a))
+	assert.Equal(t, 0, New(3).Cmp(MustParse("3.000")))
+	assert.Equal(t, int64(2), MustParse("2.999").Whole())
+	assert.Equal(t, 1.25, MustParse("1.25").Float64())
+	assert.Equal(t, New(3), MustParse("3.000"))
+	product, err := MustParse("2.5").Mul(3)
+	require.NoError(t, err)
+	assert.Equal(t, MustParse("7.5"), product)
+	assert.Equal(t, int64(3), MustParse("6.5").Fits(New(2)))
+	assert.Equal(t, int64(4), MustParse("1").Fits(MustParse("0.25")))
+	assert.Equal(t, int64(0), MustParse("-1").Fits(New(1)))
+	assert.Equal(t, int64(0), New(5).Fits(Quantity{}))
+}
+
+func TestQuantity_OutOfRange(t *testing.T) {
+	_, err := Max.Add(MustParse("0.001"))
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	minimum, err := Quantity{}.Sub(Max)
+	require.NoError(t, err)
+	_, err = minimum.Sub(MustParse("0.001"))
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = New(2).Mul(1 << 62)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = MustParse("500000000000").Mul(2)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = FromInt(1_000_000_000_000)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+
+	var q Quantity
+	assert.ErrorIs(t, q.Scan(int64(1)<<62), ErrOutOfRange)
+	assert.Panics(t, func() { New(-1_000_000_000_000) })
+}
+
+func TestQuantity_JSON(t *testing.T) {
+	var payload struct {
+		Amount Quantity `json:"amount"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": 1.5}`), &payload))
+	assert.Equal(t, MustParse("1.5"), payload.Amount)
+
+	out, err := json.Marshal(map[string]Quantity{"whole": New(12), "fraction": MustParse("0.125")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"whole": 12, "fraction": 0.125}`, string(out))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": 0.30000000000000004}`), &payload))
+	assert.Error(t, json.Unmarshal([]byte(`{"amount": "1.5"}`), &payload))
+}
+
+func TestQuantity_YAML(t *testing.T) {
+	var spec struct {
+		Amount Quantity `yaml:"amount"`
+	}
+	require.NoError(t, yaml.Unmarshal([]byte("amount: 2.5\n"), &spec))
+	assert.Equal(t, MustParse("2.5"), spec.Amount)
+
+	require.NoError(t, yaml.Unmarshal([]byte("amount: 7\n"), &spec))
+	assert.Equal(t, New(7), spec.Amount)
+}
+
+func TestQuantity_Scan(t *testing.T) {
+	var q Quantity
+	require.NoError(t, q.Scan(int64(4)))
+	assert.Equal(t, New(4), q)
+
+	require.NoError(t, q.Scan([]byte("1.250")))
+	assert.Equal(t, MustParse("1.25"), q)
+
+	require.NoError(t, q.Scan("3.0000"))
+	assert.Equal(t, New(3), q)
+
+	assert.Error(t, q.Scan("1.23450"))
+	assert.Error(t, q.Scan(nil))
+	assert.Error(t, q.Scan(1.5))
+
+	value, err := MustParse("0.75").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "0.75", value)
+}
@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// FallbackLimiter limits with primary, and with fallback whenever primary
+// fails, so a shared limiter that cannot be reached leaves each instance
+// limiting on its own rather than not at all.
+type FallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+}
+
+func NewFallbackLimiter(primary, fallback Limiter) *FallbackLimiter {
+	return &FallbackLimiter{primary: primary, fallback: fallback}
+}
+
+func (l *FallbackLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	allowed, retryAfter, err := l.primary.Allow(ctx, key)
+	if err == nil {
+		return allowed, retryAfter, nil
+	}
+	return l.fallback.Allow(ctx, key)
+}
+
+// SetLimit changes the limit of both limiters that can change theirs.
+func (l *FallbackLimiter) SetLimit(rate float64, burst int64) {
+	for _, limiter := range []Limiter{l.primary, l.fallback} {
+		if adjustable, ok := limiter.(interface{ SetLimit(float64, int64) }); ok {
+			adjustable.SetLimit(rate, burst)
+		}
+	}
+}
+
+// ResettableStore is a Store whose windows can be discarded.
+type ResettableStore interface {
+	Store
+	Reset(ctx context.Context, key string) error
+}
+
+// FallbackStore counts in primary, and in fallback whenever primary fails.
+// Units counted in fallback are not carried over to primary when it is
+// back, so a key's windows restart there.
+type FallbackStore struct {
+	primary  ResettableStore
+	fallback ResettableStore
+}
+
+func NewFallbackStore(primary, fallback ResettableStore) *FallbackStore {
+	return &FallbackStore{primary: primary, fallback: fallback}
+}
+
+func (s *FallbackStore) Consume(ctx context.Context, key string, units int64, window time.Duration) (int64, time.Time, error) {
+	used, resetAt, err := s.primary.Consume(ctx, key, units, window)
+	if err == nil {
+		return used, resetAt, nil
+	}
+	return s.fallback.Consume(ctx, key, units, window)
+}
+
+// Reset discards key's current window in both stores, as either may hold
+// it. It fails if primary's window could not be discarded, since primary's
+// count applies again once it is back.
+func (s *FallbackStore) Reset(ctx context.Context, key string) error {
+	if err := s.fallback.Reset(ctx, key); err != nil {
+		return err
+	}
+	return s.primary.Reset(ctx, key)
+}
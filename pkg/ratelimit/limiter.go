@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+)
+
+// Limiter admits requests per key at a steady rate with room for bursts.
+// When a request is refused, retryAfter is how long until one would be
+// admitted. TokenBucket limits per instance; RedisSlidingWindow shares the
+// limit between instances.
+type Limiter interface {
+	Allow(ctx context.Context, key string) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// TokenBucket gives each key a bucket of burst tokens that refills at rate
+// tokens per second. Every request takes a token.
+type TokenBucket struct {
+	clock clock.Clock
+
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+	calls   int
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func NewTokenBucket(rate float64, burst int64, clk clock.Clock) *TokenBucket {
+	return &TokenBucket{
+		rate:    rate,
+		burst:   float64(burst),
+		clock:   clk,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// SetLimit changes the rate and burst of every bucket, keeping the tokens
+// each has left up to the new burst.
+func (l *TokenBucket) SetLimit(rate float64, burst int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = rate
+	l.burst = float64(burst)
+}
+
+func (l *TokenBucket) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	now := l.clock.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%1000 == 0 {
+		l.prune(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.updated = now
+
+	if b.tokens < 1 {
+		wait := (1 - b.tokens) / l.rate
+		return false, time.Duration(math.Ceil(wait * float64(time.Second))), nil
+	}
+	b.tokens--
+	return true, 0, nil
+}
+
+func (l *TokenBucket) refill(b *bucket, now time.Time) float64 {
+	return math.Min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+}
+
+// prune drops the buckets that have refilled, which behave like new ones.
+func (l *TokenBucket) prune(now time.Time) {
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
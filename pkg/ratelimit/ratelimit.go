@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+)
+
+// Store counts units consumed per key in fixed windows. Consume adds units
+// to the key's current window and returns the window total and reset time.
+// MemoryStore counts per instance; RedisStore shares the counts between
+// instances.
+type Store interface {
+	Consume(ctx context.Context, key string, units int64, window time.Duration) (used int64, resetAt time.Time, err error)
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	windows map[string]*memoryWindow
+	calls   int
+	clock   clock.Clock
+}
+
+type memoryWindow struct {
+	used    int64
+	resetAt time.Time
+}
+
+func NewMemoryStore(clk clock.Clock) *MemoryStore {
+	return &MemoryStore{windows: make(map[string]*memoryWindow), clock: clk}
+}
+
+func (s *MemoryStore) Consume(ctx context.Context, key string, units int64, window time.Duration) (int64, time.Time, error) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.calls%1000 == 0 {
+		s.prune(now)
+	}
+
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &memoryWindow{resetAt: now.Add(window)}
+		s.windows[key] = w
+	}
+	w.used += units
+
+	return w.used, w.resetAt, nil
+}
+
+func (s *MemoryStore) prune(now time.Time) {
+	for key, w := range s.windows {
+		if !now.Before(w.resetAt) {
+			delete(s.windows, key)
+		}
+	}
+}
+
+// Reset discards key's current window.
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.windows, key)
+	return nil
+}
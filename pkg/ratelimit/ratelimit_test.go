@@ -0,0 +1,234 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Consume(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	store := NewMemoryStore(fake)
+
+	used, resetAt, err := store.Consume(ctx, "key", 3, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), used)
+	assert.Equal(t, start.Add(time.Minute), resetAt)
+
+	fake.Advance(59 * time.Second)
+	used, resetAt, err = store.Consume(ctx, "key", 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), used, "units add up within the window")
+	assert.Equal(t, start.Add(time.Minute), resetAt)
+
+	fake.Advance(time.Second)
+	used, resetAt, err = store.Consume(ctx, "key", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), used, "a new window starts at the reset time")
+	assert.Equal(t, fake.Now().Add(time.Minute), resetAt)
+
+	require.NoError(t, store.Reset(ctx, "key"))
+	used, _, err = store.Consume(ctx, "key", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), used)
+}
+
+func TestRedisStore_Consume(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	store := NewRedisStore(client, "test:", fake)
+	other := NewRedisStore(client, "test:", fake)
+
+	used, resetAt, err := store.Consume(ctx, "key", 3, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), used)
+	assert.Equal(t, start.Add(time.Minute), resetAt)
+
+	fake.Advance(59 * time.Second)
+	server.FastForward(59 * time.Second)
+	used, resetAt, err = other.Consume(ctx, "key", 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), used, "instances sharing the Redis add up")
+	assert.Equal(t, start.Add(time.Minute), resetAt)
+
+	fake.Advance(time.Second)
+	server.FastForward(time.Second)
+	used, resetAt, err = store.Consume(ctx, "key", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), used, "a new window starts at the reset time")
+	assert.Equal(t, fake.Now().Add(time.Minute), resetAt)
+
+	require.NoError(t, other.Reset(ctx, "key"))
+	used, _, err = store.Consume(ctx, "key", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), used)
+}
+
+func TestTokenBucket_Allow(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := NewTokenBucket(2, 3, fake)
+
+	for i := 0; i < 3; i++ {
+		allowed, _, err := limiter.Allow(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, allowed, "the burst is admitted at once")
+	}
+	allowed, retryAfter, err := limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	allowed, _, err = limiter.Allow(ctx, "other")
+	require.NoError(t, err)
+	assert.True(t, allowed, "keys have buckets of their own")
+
+	fake.Advance(500 * time.Millisecond)
+	allowed, _, err = limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, allowed, "the bucket refills at the rate")
+	allowed, _, err = limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	fake.Advance(time.Hour)
+	for i := 0; i < 3; i++ {
+		allowed, _, err = limiter.Allow(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, allowed, "an idle bucket refills up to the burst")
+	}
+	allowed, _, err = limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestTokenBucket_SetLimit(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := NewTokenBucket(1, 1, fake)
+
+	allowed, _, err := limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	limiter.SetLimit(10, 5)
+	allowed, retryAfter, err := limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, allowed, "tokens already taken stay taken")
+	assert.Equal(t, 100*time.Millisecond, retryAfter)
+
+	fake.Advance(time.Second)
+	for i := 0; i < 5; i++ {
+		allowed, _, err = limiter.Allow(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, allowed, "the bucket refills up to the new burst")
+	}
+}
+
+func TestRedisSlidingWindow_Allow(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := NewRedisSlidingWindow(client, "test:", 2, 4, fake)
+	other := NewRedisSlidingWindow(client, "test:", 2, 4, fake)
+
+	for i := 0; i < 2; i++ {
+		allowed, _, err := limiter.Allow(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+	fake.Advance(time.Second)
+	for i := 0; i < 2; i++ {
+		allowed, _, err := other.Allow(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	allowed, retryAfter, err := limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, allowed, "instances sharing the Redis share the window")
+	assert.Equal(t, time.Second, retryAfter, "the first requests leave the 2s window a second later")
+
+	allowed, _, err = limiter.Allow(ctx, "other")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	fake.Advance(time.Second)
+	allowed, _, err = limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+type unavailableStore struct{}
+
+func (unavailableStore) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	return false, 0, errors.New("unavailable")
+}
+
+func (unavailableStore) Consume(ctx context.Context, key string, units int64, window time.Duration) (int64, time.Time, error) {
+	return 0, time.Time{}, errors.New("unavailable")
+}
+
+func (unavailableStore) Reset(ctx context.Context, key string) error {
+	return errors.New("unavailable")
+}
+
+func TestFallbackLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := NewFallbackLimiter(unavailableStore{}, NewTokenBucket(1, 1, fake))
+
+	allowed, _, err := limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, retryAfter, err := limiter.Allow(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, allowed, "the fallback limits while the primary is down")
+	assert.Equal(t, time.Second, retryAfter)
+
+	limiter.SetLimit(1, 2)
+	fake.Advance(2 * time.Second)
+	for i := 0; i < 2; i++ {
+		allowed, _, err = limiter.Allow(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, allowed, "limit changes reach the fallback")
+	}
+}
+
+func TestFallbackStore(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	primary, fallback := NewMemoryStore(fake), NewMemoryStore(fake)
+
+	used, _, err := NewFallbackStore(primary, fallback).Consume(ctx, "key", 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), used)
+
+	down := NewFallbackStore(unavailableStore{}, fallback)
+	used, _, err = down.Consume(ctx, "key", 3, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), used, "counted in the fallback while the primary is down")
+
+	assert.Error(t, down.Reset(ctx, "key"), "the primary's window is still there")
+	used, _, err = fallback.Consume(ctx, "key", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), used, "the fallback's window is reset anyway")
+}
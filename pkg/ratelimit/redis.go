@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// consumeScript adds units to the window and starts the window's expiry when
+// the counter is new, returning the total and the milliseconds left.
+var consumeScript = redis.NewScript(`
+local used = redis.call('INCRBY', KEYS[1], ARGV[1])
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	ttl = tonumber(ARGV[2])
+end
+return {used, ttl}
+`)
+
+// RedisStore keeps the windows in Redis so every instance counts against
+// the same budget. A window is a counter that Redis expires when it ends.
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+	clock  clock.Clock
+}
+
+func NewRedisStore(client redis.UniversalClient, prefix string, clk clock.Clock) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix, clock: clk}
+}
+
+func (s *RedisStore) Consume(ctx context.Context, key string, units int64, window time.Duration) (int64, time.Time, error) {
+	result, err := consumeScript.Run(ctx, s.client, []string{s.prefix + key}, units, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("consume %s: %w", key, err)
+	}
+	return result[0], s.clock.Now().Add(time.Duration(result[1]) * time.Millisecond), nil
+}
+
+// Reset discards key's current window.
+func (s *RedisStore) Reset(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.prefix+key).Err(); err != nil {
+		return fmt.Errorf("reset %s: %w", key, err)
+	}
+	return nil
+}
+
+// slidingWindowScript drops the requests that left the window, then admits
+// this one if fewer than the limit remain. A refused request gets the
+// milliseconds until the oldest one leaves.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+if redis.call('ZCARD', KEYS[1]) < tonumber(ARGV[3]) then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], window)
+	return {1, 0}
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {0, tonumber(oldest[2]) + window - now}
+`)
+
+// RedisSlidingWindow admits burst requests per key in any window of
+// burst/rate seconds, so keys average rate requests per second across
+// every instance sharing the Redis. Each key is a sorted set of the times
+// of its admitted requests.
+type RedisSlidingWindow struct {
+	client redis.UniversalClient
+	prefix string
+	clock  clock.Clock
+
+	limit atomic.Pointer[slidingWindowLimit]
+}
+
+type slidingWindowLimit struct {
+	requests int64
+	window   time.Duration
+}
+
+func NewRedisSlidingWindow(client redis.UniversalClient, prefix string, rate float64, burst int64, clk clock.Clock) *RedisSlidingWindow {
+	l := &RedisSlidingWindow{
+		client: client,
+		prefix: prefix,
+		clock:  clk,
+	}
+	l.SetLimit(rate, burst)
+	return l
+}
+
+// SetLimit changes the rate and burst for every key. Requests already
+// admitted count against the new limit.
+func (l *RedisSlidingWindow) SetLimit(rate float64, burst int64) {
+	l.limit.Store(&slidingWindowLimit{
+		requests: burst,
+		window:   time.Duration(float64(burst) / rate * float64(time.Second)),
+	})
+}
+
+func (l *RedisSlidingWindow) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	now := l.clock.Now()
+	limit := l.limit.Load()
+	member := strconv.FormatInt(now.UnixNano(), 36) + "-" + strconv.FormatUint(rand.Uint64(), 36)
+	result, err := slidingWindowScript.Run(ctx, l.client, []string{l.prefix + key},
+		now.UnixMilli(), limit.window.Milliseconds(), limit.requests, member).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("allow %s: %w", key, err)
+	}
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
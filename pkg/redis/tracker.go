@@ -0,0 +1,192 @@
+// Package redis tracks whether Redis can be reached, so the features kept
+// in it degrade to this process while it cannot instead of each one waiting
+// out timeouts and handling the outage its own way.
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrUnavailable is returned by every command, except pings, sent while
+// Redis is down.
+var ErrUnavailable = errors.New("redis is unavailable")
+
+type Config struct {
+	// FailureThreshold is how many commands in a row must fail to mark
+	// Redis down.
+	FailureThreshold int
+	// ProbeInterval is how often Redis is pinged, to notice it went down
+	// while idle and that it came back.
+	ProbeInterval time.Duration
+}
+
+// Tracker watches the commands sent through a client and marks Redis down
+// after FailureThreshold of them fail in a row, or when a ping fails. While
+// it is down, commands fail at once with ErrUnavailable rather than each
+// waiting for its connection to time out, and only a successful ping marks
+// it up again. Replies from Redis, errors included, count as successes:
+// they show it can be reached.
+type Tracker struct {
+	client goredis.UniversalClient
+	cfg    Config
+	clock  clock.Clock
+	logger *logrus.Logger
+
+	available atomic.Bool
+
+	mu        sync.Mutex
+	failures  int
+	downSince time.Time
+}
+
+// NewTracker hooks into client, which starts out available until a command
+// or ping fails.
+func NewTracker(client goredis.UniversalClient, registry *telemetry.Registry, cfg Config, clk clock.Clock, logger *logrus.Logger) *Tracker {
+	t := &Tracker{
+		client: client,
+		cfg:    cfg,
+		clock:  clk,
+		logger: logger,
+	}
+	t.available.Store(true)
+	client.AddHook(t)
+
+	registry.RegisterGaugeFunc("redis_available", "Whether Redis can be reached: 1 when it can, 0 while Redis-backed features run on process-local state.", func() []telemetry.Sample {
+		value := 0.0
+		if t.Available() {
+			value = 1
+		}
+		return []telemetry.Sample{{Value: value}}
+	})
+	return t
+}
+
+// Available reports whether Redis is up.
+func (t *Tracker) Available() bool {
+	return t.available.Load()
+}
+
+// Probe pings Redis and marks it up or down by the result.
+func (t *Tracker) Probe(ctx context.Context) error {
+	err := t.client.Ping(ctx).Err()
+	if err != nil && ctx.Err() != nil {
+		// Giving up on the ping says nothing about Redis.
+		return err
+	}
+	t.set(ctx, err == nil, err)
+	return err
+}
+
+// Run probes Redis every ProbeInterval until ctx is cancelled.
+func (t *Tracker) Run(ctx context.Context) {
+	t.logger.WithContext(ctx).WithField("interval", t.cfg.ProbeInterval).Info("Redis availability tracker started")
+
+	ticker := t.clock.NewTicker(t.cfg.ProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.logger.WithContext(ctx).Info("Redis availability tracker stopped")
+			return
+		case <-ticker.C():
+			probeCtx, cancel := context.WithTimeout(ctx, t.cfg.ProbeInterval)
+			t.Probe(probeCtx)
+			cancel()
+		}
+	}
+}
+
+func (t *Tracker) DialHook(next goredis.DialHook) goredis.DialHook {
+	return next
+}
+
+func (t *Tracker) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		// Pings go through so Probe can tell when Redis is back, and are
+		// judged by Probe alone.
+		if cmd.Name() == "ping" {
+			return next(ctx, cmd)
+		}
+		if !t.Available() {
+			cmd.SetErr(ErrUnavailable)
+			return ErrUnavailable
+		}
+		err := next(ctx, cmd)
+		t.observe(ctx, err)
+		return err
+	}
+}
+
+func (t *Tracker) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []goredis.Cmder) error {
+		if !t.Available() {
+			for _, cmd := range cmds {
+				cmd.SetErr(ErrUnavailable)
+			}
+			return ErrUnavailable
+		}
+		err := next(ctx, cmds)
+		t.observe(ctx, err)
+		return err
+	}
+}
+
+// observe counts the outcome of a command toward the failure threshold.
+func (t *Tracker) observe(ctx context.Context, err error) {
+	if !unreachable(ctx, err) {
+		t.mu.Lock()
+		t.failures = 0
+		t.mu.Unlock()
+		return
+	}
+
+	t.mu.Lock()
+	t.failures++
+	down := t.failures >= t.cfg.FailureThreshold
+	t.mu.Unlock()
+	if down {
+		t.set(ctx, false, err)
+	}
+}
+
+func (t *Tracker) set(ctx context.Context, available bool, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.failures = 0
+	if t.available.Load() == available {
+		return
+	}
+	t.available.Store(available)
+
+	if available {
+		t.logger.WithContext(ctx).WithField("down_for", t.clock.Now().Sub(t.downSince)).Info("Redis is available again")
+		return
+	}
+	t.downSince = t.clock.Now()
+	t.logger.WithContext(ctx).WithError(err).Warn("Redis is unavailable; sessions, rate limits and caching fall back to this instance")
+}
+
+// unreachable reports whether err shows Redis could not be reached, as
+// opposed to a reply from it or the caller giving up.
+func unreachable(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, goredis.Nil) || errors.Is(err, ErrUnavailable) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || ctx.Err() == context.Canceled {
+		return false
+	}
+	var reply goredis.Error
+	return !errors.As(err, &reply)
+}
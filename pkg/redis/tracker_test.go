@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTracker(t *testing.T) (*miniredis.Miniredis, *goredis.Client, *Tracker) {
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	tracker := NewTracker(client, registry, Config{FailureThreshold: 3, ProbeInterval: time.Second},
+		clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), logrus.New())
+	return server, client, tracker
+}
+
+func TestTracker_MarksDownAfterFailuresAndUpOnPing(t *testing.T) {
+	ctx := context.Background()
+	server, client, tracker := newTestTracker(t)
+
+	require.NoError(t, client.Set(ctx, "key", "value", 0).Err())
+	assert.True(t, tracker.Available())
+
+	server.Close()
+	for i := 0; i < 2; i++ {
+		err := client.Get(ctx, "key").Err()
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrUnavailable)
+		assert.True(t, tracker.Available(), "failures below the threshold")
+	}
+	require.Error(t, client.Get(ctx, "key").Err())
+	assert.False(t, tracker.Available())
+
+	assert.ErrorIs(t, client.Get(ctx, "key").Err(), ErrUnavailable, "commands fail at once while down")
+	_, err := client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Incr(ctx, "counter")
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Error(t, tracker.Probe(ctx))
+	assert.False(t, tracker.Available())
+
+	require.NoError(t, server.Restart())
+	require.NoError(t, tracker.Probe(ctx))
+	assert.True(t, tracker.Available())
+	assert.NoError(t, client.Incr(ctx, "counter").Err())
+}
+
+func TestTracker_ProbeMarksDown(t *testing.T) {
+	server, _, tracker := newTestTracker(t)
+
+	server.Close()
+	assert.Error(t, tracker.Probe(context.Background()))
+	assert.False(t, tracker.Available())
+}
+
+func TestTracker_RepliesCountAsAvailable(t *testing.T) {
+	ctx := context.Background()
+	server, client, tracker := newTestTracker(t)
+
+	for i := 0; i < 5; i++ {
+		assert.ErrorIs(t, client.Get(ctx, "missing").Err(), goredis.Nil)
+	}
+	server.SetError("ERR busy")
+	for i := 0; i < 5; i++ {
+		assert.Error(t, client.Get(ctx, "key").Err())
+	}
+	assert.True(t, tracker.Available())
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	server.SetError("")
+	for i := 0; i < 5; i++ {
+		assert.Error(t, client.Get(canceled, "key").Err())
+	}
+	assert.True(t, tracker.Available(), "callers giving up do not count")
+}
@@ -0,0 +1,114 @@
+// Package replication tracks how far a read replica trails the primary so
+// reads can be routed away from it when it falls behind.
+package replication
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Probe measures the replica's current lag behind the primary.
+type Probe func(ctx context.Context) (time.Duration, error)
+
+type Config struct {
+	Interval time.Duration
+	// MaxLag is the most lag reads tolerate before the replica is
+	// considered unhealthy.
+	MaxLag time.Duration
+}
+
+// Status is the latest measurement. Healthy is false until the first
+// successful probe, after a failed one, once the lag exceeds MaxLag, or
+// when no probe has succeeded for three intervals.
+type Status struct {
+	Lag        time.Duration
+	MeasuredAt time.Time
+	Err        error
+	Healthy    bool
+}
+
+type Monitor struct {
+	probe  Probe
+	cfg    Config
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	mu         sync.RWMutex
+	lag        time.Duration
+	measuredAt time.Time
+	err        error
+}
+
+func NewMonitor(probe Probe, cfg Config, clk clock.Clock, logger *logrus.Logger) *Monitor {
+	return &Monitor{
+		probe:  probe,
+		cfg:    cfg,
+		logger: logger,
+		clock:  clk,
+	}
+}
+
+// Run measures lag immediately and then every interval until ctx is
+// cancelled.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := m.clock.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	m.Measure(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			m.Measure(ctx)
+		}
+	}
+}
+
+// Measure runs the probe once and records the result.
+func (m *Monitor) Measure(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.Interval)
+	defer cancel()
+
+	lag, err := m.probe(ctx)
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.err = err
+	if err != nil {
+		if ctx.Err() == nil {
+			m.logger.WithError(err).Warn("Failed to measure replica lag")
+		}
+		return
+	}
+	if lag > m.cfg.MaxLag && m.lag <= m.cfg.MaxLag {
+		m.logger.WithFields(logrus.Fields{"lag": lag, "max_lag": m.cfg.MaxLag}).Warn("Replica lag above limit, reading from primary")
+	}
+	m.lag = lag
+	m.measuredAt = now
+}
+
+func (m *Monitor) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	fresh := !m.measuredAt.IsZero() && m.clock.Now().Sub(m.measuredAt) <= 3*m.cfg.Interval
+	return Status{
+		Lag:        m.lag,
+		MeasuredAt: m.measuredAt,
+		Err:        m.err,
+		Healthy:    fresh && m.err == nil && m.lag <= m.cfg.MaxLag,
+	}
+}
+
+// Healthy reports whether reads may be served by the replica.
+func (m *Monitor) Healthy() bool {
+	return m.Status().Healthy
+}
@@ -0,0 +1,78 @@
+package replication
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMonitor(probe Probe) (*Monitor, *clock.Fake) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := NewMonitor(probe, Config{Interval: time.Second, MaxLag: 5 * time.Second}, fake, logrus.New())
+	return m, fake
+}
+
+func TestMonitor_Status(t *testing.T) {
+	lag := 2 * time.Second
+	var probeErr error
+	m, fake := newTestMonitor(func(ctx context.Context) (time.Duration, error) {
+		return lag, probeErr
+	})
+
+	assert.False(t, m.Healthy(), "unmeasured replica is not trusted")
+
+	m.Measure(context.Background())
+	status := m.Status()
+	assert.True(t, status.Healthy)
+	assert.Equal(t, 2*time.Second, status.Lag)
+	assert.Equal(t, fake.Now(), status.MeasuredAt)
+
+	lag = 10 * time.Second
+	m.Measure(context.Background())
+	assert.False(t, m.Healthy(), "lag above MaxLag")
+
+	lag = 0
+	m.Measure(context.Background())
+	assert.True(t, m.Healthy())
+
+	probeErr = errors.New("connection refused")
+	m.Measure(context.Background())
+	status = m.Status()
+	assert.False(t, status.Healthy)
+	assert.Equal(t, probeErr, status.Err)
+
+	probeErr = nil
+	m.Measure(context.Background())
+	fake.Advance(4 * time.Second)
+	assert.False(t, m.Healthy(), "stale measurement")
+}
+
+func TestMonitor_Run(t *testing.T) {
+	probes := make(chan struct{}, 10)
+	m, fake := newTestMonitor(func(ctx context.Context) (time.Duration, error) {
+		probes <- struct{}{}
+		return 0, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		m.Run(ctx)
+	}()
+
+	<-probes
+	fake.BlockUntilTickers(1)
+	fake.Advance(time.Second)
+	<-probes
+
+	cancel()
+	<-done
+	assert.True(t, m.Healthy())
+}
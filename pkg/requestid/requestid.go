@@ -0,0 +1,79 @@
+// Package requestid carries the ID of the request being served through
+// its context, so every log line written while serving it, in whichever
+// layer, can be matched to the request and to the calls it made to other
+// services.
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Header is the HTTP header the ID is read from, echoed in and passed on
+// in.
+const Header = "X-Request-ID"
+
+// Field is the log field the ID is written to.
+const Field = "request_id"
+
+// maxLength caps the IDs accepted from callers.
+const maxLength = 128
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the ID ctx carries, or "" when it carries none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Valid reports whether a caller's ID may be used as is: at most 128
+// letters, digits and '-', '_', '.' or ':'. Anything else could forge log
+// fields or headers.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Hook adds the request ID to log entries whose context carries one, as
+// those of logger.WithContext(ctx) do.
+type Hook struct{}
+
+func (Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (Hook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	if id := FromContext(entry.Context); id != "" {
+		entry.Data[Field] = id
+	}
+	return nil
+}
+
+// Propagate sets the header on outgoing requests made for a request, so
+// the services called can log the same ID. It is an httpclient.Propagator.
+func Propagate(ctx context.Context, header http.Header) {
+	if id := FromContext(ctx); id != "" && header.Get(Header) == "" {
+		header.Set(Header, id)
+	}
+}
@@ -0,0 +1,53 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValid(t *testing.T) {
+	for _, id := range []string{"0190a2b4-7c1d-7e3f-8a9b-0c1d2e3f4a5b", "trace:abc.1_2"} {
+		assert.True(t, Valid(id), id)
+	}
+	for _, id := range []string{"", "has space", "line\nbreak", `quote"`, strings.Repeat("a", 129)} {
+		assert.False(t, Valid(id), id)
+	}
+}
+
+func TestHook(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(Hook{})
+
+	ctx := NewContext(context.Background(), "req-1")
+	logger.WithContext(ctx).WithField("product_id", 7).Info("Product created")
+	logger.WithContext(context.Background()).Info("Background work")
+	logger.Info("No context")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	var first map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "req-1", first[Field])
+	assert.NotContains(t, lines[1], Field)
+	assert.NotContains(t, lines[2], Field)
+}
+
+func TestPropagate(t *testing.T) {
+	header := http.Header{}
+	Propagate(context.Background(), header)
+	assert.Empty(t, header.Get(Header))
+
+	Propagate(NewContext(context.Background(), "req-1"), header)
+	assert.Equal(t, "req-1", header.Get(Header))
+}
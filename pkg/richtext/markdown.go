@@ -0,0 +1,206 @@
+package richtext
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,4})\s+(.*)$`)
+	orderedPattern  = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	codePattern     = regexp.MustCompile("`([^`]+)`")
+	strongPattern   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	emPattern       = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+	codeSpanPattern = regexp.MustCompile(`<code>.*?</code>`)
+)
+
+// ToHTML renders a description stored in the given format as safe HTML.
+func ToHTML(format, text string) string {
+	switch format {
+	case FormatMarkdown:
+		return RenderMarkdown(text)
+	case FormatHTML:
+		return Sanitize(text)
+	default:
+		escaped := html.EscapeString(text)
+		return strings.ReplaceAll(escaped, "\n", "<br>")
+	}
+}
+
+// RenderMarkdown converts the Markdown subset used in product descriptions
+// (headings, paragraphs, lists, quotes, fenced code, emphasis, code spans and
+// links) to HTML. The result is passed through Sanitize.
+func RenderMarkdown(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	var paragraph []string
+	listTag := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + renderInline(strings.Join(paragraph, " ")) + "</p>")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			out.WriteString("</" + listTag + ">")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			out.WriteString("<" + tag + ">")
+			listTag = tag
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+
+		case strings.HasPrefix(trimmed, "```"):
+			flushParagraph()
+			closeList()
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>")
+
+		case headingPattern.MatchString(trimmed):
+			flushParagraph()
+			closeList()
+			// Description headings start at h3 so they never compete with
+			// the storefront's own page headings.
+			match := headingPattern.FindStringSubmatch(trimmed)
+			level := string(rune('2' + len(match[1])))
+			out.WriteString("<h" + level + ">" + renderInline(match[2]) + "</h" + level + ">")
+
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flushParagraph()
+			openList("ul")
+			out.WriteString("<li>" + renderInline(trimmed[2:]) + "</li>")
+
+		case orderedPattern.MatchString(trimmed):
+			flushParagraph()
+			openList("ol")
+			out.WriteString("<li>" + renderInline(orderedPattern.FindStringSubmatch(trimmed)[1]) + "</li>")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushParagraph()
+			closeList()
+			out.WriteString("<blockquote>" + renderInline(strings.TrimSpace(trimmed[1:])) + "</blockquote>")
+
+		case trimmed == "---" || trimmed == "***":
+			flushParagraph()
+			closeList()
+			out.WriteString("<hr>")
+
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flushParagraph()
+	closeList()
+
+	return Sanitize(out.String())
+}
+
+func renderInline(text string) string {
+	text = html.EscapeString(text)
+
+	text = codePattern.ReplaceAllString(text, "<code>$1</code>")
+
+	// Emphasis and links must not apply inside code spans, so split around
+	// them and only transform the text in between.
+	spans := codeSpanPattern.FindAllStringIndex(text, -1)
+	var out strings.Builder
+	last := 0
+	for _, span := range spans {
+		out.WriteString(renderEmphasis(text[last:span[0]]))
+		out.WriteString(text[span[0]:span[1]])
+		last = span[1]
+	}
+	out.WriteString(renderEmphasis(text[last:]))
+
+	return out.String()
+}
+
+func renderEmphasis(text string) string {
+	text = renderLinks(text)
+	text = strongPattern.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = emPattern.ReplaceAllString(text, "<em>$1$2</em>")
+	return text
+}
+
+// renderLinks turns [label](destination) into links. The destination ends
+// at the parenthesis that balances its opening one, so URLs such as
+// https://en.wikipedia.org/wiki/Go_(language) stay whole; a destination
+// with whitespace or unbalanced parentheses is left as text.
+func renderLinks(text string) string {
+	var out strings.Builder
+	for {
+		open := strings.Index(text, "[")
+		if open < 0 {
+			break
+		}
+		closeLabel := strings.Index(text[open:], "](")
+		if closeLabel <= 1 || strings.ContainsAny(text[open+1:open+closeLabel], "[]") {
+			out.WriteString(text[:open+1])
+			text = text[open+1:]
+			continue
+		}
+		label := text[open+1 : open+closeLabel]
+		rest := text[open+closeLabel+2:]
+
+		end := linkDestinationEnd(rest)
+		if end <= 0 {
+			out.WriteString(text[:open+1])
+			text = text[open+1:]
+			continue
+		}
+
+		out.WriteString(text[:open])
+		out.WriteString(`<a href="` + rest[:end] + `">` + label + `</a>`)
+		text = rest[end+1:]
+	}
+	out.WriteString(text)
+	return out.String()
+}
+
+// linkDestinationEnd returns the index of the parenthesis closing a link
+// destination, or -1 if there is none.
+func linkDestinationEnd(dest string) int {
+	depth := 0
+	for i, r := range dest {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case unicode.IsSpace(r):
+			return -1
+		}
+	}
+	return -1
+}
@@ -0,0 +1,58 @@
+package richtext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "allowed markup", input: "<p>Soft <strong>cotton</strong></p>", expected: "<p>Soft <strong>cotton</strong></p>"},
+		{name: "script removed with content", input: "Hi<script>alert(1)</script>!", expected: "Hi!"},
+		{name: "event handler attributes dropped", input: `<p onclick="x()">a</p><img src=x onerror=alert(1)>`, expected: "<p>a</p>"},
+		{name: "javascript link unwrapped", input: `<a href="javascript:alert(1)">x</a>`, expected: "x"},
+		{name: "protocol-relative link unwrapped", input: `<a href="//evil.example">x</a>`, expected: "x"},
+		{name: "safe link", input: `<a href="https://example.com/?a=1&b=2" target="_blank">x</a>`, expected: `<a href="https://example.com/?a=1&amp;b=2" rel="nofollow noreferrer">x</a>`},
+		{name: "unknown tags unwrapped", input: "<div><span>text</span></div>", expected: "text"},
+		{name: "unclosed tags balanced", input: "<ul><li><em>one", expected: "<ul><li><em>one</em></li></ul>"},
+		{name: "stray end tag ignored", input: "a</strong>b", expected: "ab"},
+		{name: "unicode preserved", input: "<p>Größe & 尺寸 — ü</p>", expected: "<p>Größe &amp; 尺寸 — ü</p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Sanitize(tt.input))
+		})
+	}
+}
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		input    string
+		expected string
+	}{
+		{name: "plain text escaped", format: FormatPlain, input: "a < b\nnext", expected: "a &lt; b<br>next"},
+		{name: "markdown paragraph", format: FormatMarkdown, input: "Soft **cotton** and *wool*", expected: "<p>Soft <strong>cotton</strong> and <em>wool</em></p>"},
+		{name: "markdown list", format: FormatMarkdown, input: "- one\n- two", expected: "<ul><li>one</li><li>two</li></ul>"},
+		{name: "markdown heading", format: FormatMarkdown, input: "# Care", expected: "<h3>Care</h3>"},
+		{name: "markdown raw html escaped", format: FormatMarkdown, input: "<script>x</script>", expected: "<p>&lt;script&gt;x&lt;/script&gt;</p>"},
+		{name: "markdown unsafe link", format: FormatMarkdown, input: "[x](javascript:alert(1))", expected: "<p>x</p>"},
+		{name: "markdown link with parentheses", format: FormatMarkdown, input: "See [Go](https://en.wikipedia.org/wiki/Go_(language)).", expected: `<p>See <a href="https://en.wikipedia.org/wiki/Go_(language)" rel="nofollow noreferrer">Go</a>.</p>`},
+		{name: "markdown unbalanced link left as text", format: FormatMarkdown, input: "[x](https://example.com/(a b)", expected: "<p>[x](https://example.com/(a b)</p>"},
+		{name: "markdown code span", format: FormatMarkdown, input: "use `**raw**`", expected: "<p>use <code>**raw**</code></p>"},
+		{name: "html sanitized", format: FormatHTML, input: "<b onmouseover=x>b</b>", expected: "<b>b</b>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ToHTML(tt.format, tt.input))
+		})
+	}
+}
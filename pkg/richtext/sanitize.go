@@ -0,0 +1,56 @@
+package richtext
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// localHref rejects protocol-relative hrefs ("//host", "/\host"), which
+// bluemonday counts as relative but browsers resolve off-site.
+var localHref = regexp.MustCompile(`^\s*(?:/(?:[^/\\]|$)|[^/\\\s])`)
+
+// policy allows the small subset of HTML product descriptions need. Links
+// keep only an http, https, mailto or relative href and are marked nofollow.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements(
+		"p", "br", "hr",
+		"strong", "b", "em", "i", "u", "s",
+		"ul", "ol", "li",
+		"blockquote", "code", "pre",
+		"h3", "h4", "h5", "h6",
+	)
+	p.AllowAttrs("href").Matching(localHref).OnElements("a")
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.AllowRelativeURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.RequireNoReferrerOnLinks(true)
+	return p
+}()
+
+// Sanitize reduces untrusted HTML to a small allowlisted subset suitable for
+// product descriptions. The input is parsed first, so unclosed tags are
+// balanced the way a browser would, and then filtered through policy.
+func Sanitize(input string) string {
+	return policy.Sanitize(balance(input))
+}
+
+func balance(input string) string {
+	context := &xhtml.Node{Type: xhtml.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := xhtml.ParseFragment(strings.NewReader(input), context)
+	if err != nil {
+		return input
+	}
+
+	var out strings.Builder
+	for _, node := range nodes {
+		if err := xhtml.Render(&out, node); err != nil {
+			return input
+		}
+	}
+	return out.String()
+}
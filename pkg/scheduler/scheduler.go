@@ -0,0 +1,124 @@
+// Package scheduler runs jobs on cron schedules, such as cleanups that are
+// best left to the night. Each run is logged and measured under the job's
+// name, and a run still going when the next is due is skipped rather than
+// overlapped.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/logger"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// Job is work run on a schedule.
+type Job interface {
+	// Name identifies the job in logs and metrics.
+	Name() string
+	// Run does the work once. It should return soon after ctx is
+	// cancelled.
+	Run(ctx context.Context) error
+}
+
+// parser reads standard five-field specs as well as descriptors such as
+// "@daily" and "@every 1h". A spec may start with CRON_TZ=<zone> to be
+// read in that time zone rather than UTC.
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Scheduler runs the jobs added to it on their schedules.
+type Scheduler struct {
+	cron   *cron.Cron
+	names  map[string]bool
+	logger *logrus.Logger
+	clock  clock.Clock
+
+	// ctx is what Run was given; runs get it so they stop with the
+	// scheduler.
+	ctx context.Context
+
+	runs     *telemetry.Counter
+	duration *telemetry.Histogram
+}
+
+func New(registry *telemetry.Registry, clk clock.Clock, logger *logrus.Logger) *Scheduler {
+	return &Scheduler{
+		cron:     cron.New(cron.WithLocation(time.UTC), cron.WithParser(parser)),
+		names:    make(map[string]bool),
+		logger:   logger,
+		clock:    clk,
+		runs:     registry.NewCounter("scheduled_job_runs_total", "Scheduled job runs by job and result.", "job", "result"),
+		duration: registry.NewHistogram("scheduled_job_duration_seconds", "Time scheduled jobs took to run by job.", telemetry.DefaultDurationBuckets, "job"),
+	}
+}
+
+// Add runs job on the schedule spec describes, once Run is called. Job
+// names must be unique.
+func (s *Scheduler) Add(spec string, job Job) error {
+	schedule, err := parser.Parse(spec)
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for %s: %w", spec, job.Name(), err)
+	}
+	return s.add(schedule, job)
+}
+
+func (s *Scheduler) add(schedule cron.Schedule, job Job) error {
+	if s.names[job.Name()] {
+		return fmt.Errorf("scheduled job %s is added twice", job.Name())
+	}
+	s.names[job.Name()] = true
+
+	var running atomic.Bool
+	s.cron.Schedule(schedule, cron.FuncJob(func() {
+		if !running.CompareAndSwap(false, true) {
+			s.runs.Inc(job.Name(), "skipped")
+			s.logger.WithField("job", job.Name()).Warn("Scheduled job is still running, skipping this run")
+			return
+		}
+		defer running.Store(false)
+		s.run(job)
+	}))
+	return nil
+}
+
+// Run runs the jobs on their schedules until ctx is cancelled, and
+// returns once the runs in progress have returned.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.logger.WithField("jobs", len(s.names)).Info("Scheduler started")
+
+	s.ctx = ctx
+	s.cron.Start()
+	<-ctx.Done()
+	<-s.cron.Stop().Done()
+
+	s.logger.Info("Scheduler stopped")
+}
+
+func (s *Scheduler) run(job Job) {
+	ctx := logger.NewContext(s.ctx, logrus.Fields{"job": job.Name()})
+	if ctx.Err() != nil {
+		return
+	}
+
+	start := s.clock.Now()
+	err := job.Run(ctx)
+	elapsed := s.clock.Now().Sub(start)
+	s.duration.Observe(elapsed.Seconds(), job.Name())
+
+	log := s.logger.WithFields(logrus.Fields{"job": job.Name(), "duration": elapsed})
+	if err != nil {
+		s.runs.Inc(job.Name(), "failure")
+		if ctx.Err() == nil {
+			log.WithError(err).Error("Scheduled job failed")
+		}
+		return
+	}
+	s.runs.Inc(job.Name(), "success")
+	log.Info("Scheduled job finished")
+}
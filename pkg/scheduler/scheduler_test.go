@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+	"backend-context-engineering-template/pkg/telemetry"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testJob struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+func (j testJob) Name() string                  { return j.name }
+func (j testJob) Run(ctx context.Context) error { return j.run(ctx) }
+
+// every is due each d, far more often than a cron spec can say.
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time { return t.Add(time.Duration(e)) }
+
+func newTestScheduler() (*Scheduler, *telemetry.Registry) {
+	registry := telemetry.NewRegistry(telemetry.NewResource("product-service", "test", nil))
+	return New(registry, clock.Real(), logrus.New()), registry
+}
+
+func metrics(t *testing.T, registry *telemetry.Registry) string {
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	return buf.String()
+}
+
+// start runs s until the returned function is called, which waits for Run
+// to return.
+func start(s *Scheduler) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+func TestScheduler_Add(t *testing.T) {
+	s, _ := newTestScheduler()
+	noop := func(context.Context) error { return nil }
+
+	require.NoError(t, s.Add("0 3 * * *", testJob{name: "nightly", run: noop}))
+	require.NoError(t, s.Add("@every 1h", testJob{name: "hourly", run: noop}))
+	require.NoError(t, s.Add("CRON_TZ=UTC @daily", testJob{name: "daily", run: noop}))
+
+	err := s.Add("0 3 * *", testJob{name: "broken", run: noop})
+	assert.ErrorContains(t, err, "invalid schedule")
+	err = s.Add("@daily", testJob{name: "nightly", run: noop})
+	assert.ErrorContains(t, err, "added twice")
+}
+
+func TestScheduler_RunsJobsOnSchedule(t *testing.T) {
+	s, registry := newTestScheduler()
+	ran := make(chan struct{}, 10)
+	require.NoError(t, s.add(every(10*time.Millisecond), testJob{name: "cleanup", run: func(ctx context.Context) error {
+		ran <- struct{}{}
+		return nil
+	}}))
+	failing := errors.New("database is down")
+	require.NoError(t, s.add(every(10*time.Millisecond), testJob{name: "broken", run: func(ctx context.Context) error {
+		return failing
+	}}))
+
+	stop := start(s)
+	<-ran
+	<-ran
+	stop()
+
+	out := metrics(t, registry)
+	assert.Contains(t, out, `scheduled_job_runs_total{job="cleanup",result="success"`)
+	assert.Contains(t, out, `scheduled_job_runs_total{job="broken",result="failure"`)
+	assert.Contains(t, out, `scheduled_job_duration_seconds_count{job="cleanup"`)
+}
+
+func TestScheduler_SkipsRunsWhileOneIsInProgress(t *testing.T) {
+	s, registry := newTestScheduler()
+	started := make(chan struct{})
+	var returned bool
+	require.NoError(t, s.add(every(10*time.Millisecond), testJob{name: "slow", run: func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		returned = true
+		return ctx.Err()
+	}}))
+
+	stop := start(s)
+	<-started
+	assert.Eventually(t, func() bool {
+		return bytes.Contains([]byte(metrics(t, registry)), []byte(`scheduled_job_runs_total{job="slow",result="skipped"`))
+	}, time.Second, 5*time.Millisecond)
+	stop()
+
+	assert.True(t, returned, "Run should wait for the run in progress")
+}
@@ -0,0 +1,101 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrNotFound = errors.New("secret not found")
+
+// Store keeps small credentials (API tokens, passwords) encrypted at rest.
+type Store interface {
+	Put(ctx context.Context, key string, value []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// PostgresStore seals values with AES-256-GCM before writing them to the
+// secrets table; the database never sees plaintext.
+type PostgresStore struct {
+	db   *sql.DB
+	aead cipher.AEAD
+}
+
+// NewPostgresStore expects a base64-encoded 32-byte key.
+func NewPostgresStore(db *sql.DB, encodedKey string) (*PostgresStore, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secrets key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("secrets key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %w", err)
+	}
+
+	return &PostgresStore{db: db, aead: aead}, nil
+}
+
+func (s *PostgresStore) Put(ctx context.Context, key string, value []byte) error {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// The key is bound as additional data so a ciphertext cannot be moved
+	// to another key and still decrypt.
+	ciphertext := s.aead.Seal(nil, nonce, value, []byte(key))
+
+	query := `
+		INSERT INTO secrets (key, nonce, ciphertext, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (key) DO UPDATE SET nonce = EXCLUDED.nonce, ciphertext = EXCLUDED.ciphertext, updated_at = NOW()
+	`
+
+	if _, err := s.db.ExecContext(ctx, query, key, nonce, ciphertext); err != nil {
+		return fmt.Errorf("failed to store secret: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string) ([]byte, error) {
+	query := `SELECT nonce, ciphertext FROM secrets WHERE key = $1`
+
+	var nonce, ciphertext []byte
+	if err := s.db.QueryRowContext(ctx, query, key).Scan(&nonce, &ciphertext); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to load secret: %w", err)
+	}
+
+	value, err := s.aead.Open(nil, nonce, ciphertext, []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+
+	return value, nil
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM secrets WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package session
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+type CookieConfig struct {
+	Name     string
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// ParseSameSite maps "strict", "lax" or "none" to the cookie attribute,
+// defaulting to lax.
+func ParseSameSite(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// Write sets the session cookie. It is always httponly so scripts can never
+// read the bearer token.
+func (c CookieConfig) Write(w http.ResponseWriter, token string, expiresAt time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.Name,
+		Value:    token,
+		Path:     "/",
+		Domain:   c.Domain,
+		Expires:  expiresAt,
+		Secure:   c.Secure,
+		HttpOnly: true,
+		SameSite: c.SameSite,
+	})
+}
+
+func (c CookieConfig) Clear(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.Name,
+		Value:    "",
+		Path:     "/",
+		Domain:   c.Domain,
+		MaxAge:   -1,
+		Secure:   c.Secure,
+		HttpOnly: true,
+		SameSite: c.SameSite,
+	})
+}
@@ -0,0 +1,100 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// FallbackStore keeps sessions in primary, and in fallback while primary
+// fails, so sessions can still start while a shared store is down. Sessions
+// started meanwhile are only seen by this instance, and move to primary
+// the next time they are used once it is back. Sessions held only by
+// primary cannot be looked up while it is down.
+type FallbackStore struct {
+	primary  Store
+	fallback Store
+}
+
+func NewFallbackStore(primary, fallback Store) *FallbackStore {
+	return &FallbackStore{primary: primary, fallback: fallback}
+}
+
+func (s *FallbackStore) Save(ctx context.Context, session *Session) error {
+	if err := s.primary.Save(ctx, session); err != nil {
+		return s.fallback.Save(ctx, session)
+	}
+	return nil
+}
+
+// Touch saves the session in the store that holds it. A session only
+// fallback holds moves to primary once primary is back, and is taken out
+// of primary again if it was revoked while it moved.
+func (s *FallbackStore) Touch(ctx context.Context, session *Session) error {
+	err := s.primary.Touch(ctx, session)
+	if err == nil {
+		return nil
+	}
+	if fallbackErr := s.fallback.Touch(ctx, session); fallbackErr != nil {
+		return err
+	}
+	if errors.Is(err, ErrNotFound) && s.primary.Save(ctx, session) == nil {
+		if _, getErr := s.fallback.GetByTokenHash(ctx, session.TokenHash); errors.Is(getErr, ErrNotFound) {
+			s.primary.Delete(ctx, session.Identity, session.ID)
+			return ErrNotFound
+		}
+	}
+	return nil
+}
+
+// GetByTokenHash looks in primary first. When neither store has the
+// session, primary's error is returned, so a session that is unknown only
+// because primary is down is not reported as not found.
+func (s *FallbackStore) GetByTokenHash(ctx context.Context, tokenHash string) (*Session, error) {
+	session, err := s.primary.GetByTokenHash(ctx, tokenHash)
+	if err == nil {
+		return session, nil
+	}
+	if session, fallbackErr := s.fallback.GetByTokenHash(ctx, tokenHash); fallbackErr == nil {
+		return session, nil
+	}
+	return nil, err
+}
+
+// List returns the sessions in both stores, or only fallback's while
+// primary is down.
+func (s *FallbackStore) List(ctx context.Context, identity string) ([]*Session, error) {
+	fallback, err := s.fallback.List(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.primary.List(ctx, identity)
+	if err != nil {
+		return fallback, nil
+	}
+
+	seen := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		seen[session.ID] = true
+	}
+	for _, session := range fallback {
+		if !seen[session.ID] {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+// Delete removes the session from both stores. It is not found only when
+// neither store has it.
+func (s *FallbackStore) Delete(ctx context.Context, identity, id string) error {
+	fallbackErr := s.fallback.Delete(ctx, identity, id)
+	err := s.primary.Delete(ctx, identity, id)
+	if errors.Is(err, ErrNotFound) && fallbackErr == nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// toggleStore is a MemoryStore that fails every call while down.
+type toggleStore struct {
+	*MemoryStore
+	down bool
+}
+
+var errDown = errors.New("store is down")
+
+func (s *toggleStore) Save(ctx context.Context, session *Session) error {
+	if s.down {
+		return errDown
+	}
+	return s.MemoryStore.Save(ctx, session)
+}
+
+func (s *toggleStore) Touch(ctx context.Context, session *Session) error {
+	if s.down {
+		return errDown
+	}
+	return s.MemoryStore.Touch(ctx, session)
+}
+
+func (s *toggleStore) GetByTokenHash(ctx context.Context, tokenHash string) (*Session, error) {
+	if s.down {
+		return nil, errDown
+	}
+	return s.MemoryStore.GetByTokenHash(ctx, tokenHash)
+}
+
+func (s *toggleStore) List(ctx context.Context, identity string) ([]*Session, error) {
+	if s.down {
+		return nil, errDown
+	}
+	return s.MemoryStore.List(ctx, identity)
+}
+
+func (s *toggleStore) Delete(ctx context.Context, identity, id string) error {
+	if s.down {
+		return errDown
+	}
+	return s.MemoryStore.Delete(ctx, identity, id)
+}
+
+func TestFallbackStore(t *testing.T) {
+	ctx := context.Background()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	primary := &toggleStore{MemoryStore: NewMemoryStore(fake)}
+	manager := NewManager(NewFallbackStore(primary, NewMemoryStore(fake)), Config{TTL: time.Hour}, fake)
+
+	before, beforeToken, err := manager.Create(ctx, "key:abc", nil, "", "")
+	require.NoError(t, err)
+
+	primary.down = true
+	fake.Advance(time.Minute)
+	during, duringToken, err := manager.Create(ctx, "key:abc", nil, "", "")
+	require.NoError(t, err, "sessions start while the primary is down")
+	_, err = manager.Lookup(ctx, duringToken)
+	require.NoError(t, err)
+	_, err = manager.Lookup(ctx, beforeToken)
+	assert.ErrorIs(t, err, errDown, "sessions only the primary holds are not reported as not found")
+	sessions, err := manager.List(ctx, "key:abc")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, during.ID, sessions[0].ID)
+
+	primary.down = false
+	_, err = manager.Lookup(ctx, duringToken)
+	require.NoError(t, err)
+	_, err = primary.GetByTokenHash(ctx, during.TokenHash)
+	assert.NoError(t, err, "sessions move to the primary once it is back")
+	sessions, err = manager.List(ctx, "key:abc")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, []string{during.ID, before.ID}, []string{sessions[0].ID, sessions[1].ID})
+
+	require.NoError(t, manager.Revoke(ctx, "key:abc", during.ID))
+	_, err = manager.Lookup(ctx, duringToken)
+	assert.ErrorIs(t, err, ErrNotFound, "revoked in both stores")
+	assert.ErrorIs(t, manager.Revoke(ctx, "key:abc", during.ID), ErrNotFound)
+}
@@ -0,0 +1,179 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore keeps sessions in Redis so every instance sees the same
+// sessions and they survive restarts. Each session is a JSON value keyed by
+// its token hash that Redis expires at ExpiresAt; a set per identity indexes
+// the token hashes for listing and revocation.
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+	clock  clock.Clock
+}
+
+func NewRedisStore(client redis.UniversalClient, prefix string, clk clock.Clock) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix, clock: clk}
+}
+
+func (s *RedisStore) Save(ctx context.Context, session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("encode session: %w", err)
+	}
+
+	ttl := session.ExpiresAt.Sub(s.clock.Now())
+	if ttl <= 0 {
+		return nil
+	}
+
+	indexKey := s.identityKey(session.Identity)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.sessionKey(session.TokenHash), data, ttl)
+		pipe.SAdd(ctx, indexKey, session.TokenHash)
+		// The index lives as long as the identity's longest-lived session.
+		pipe.ExpireNX(ctx, indexKey, ttl)
+		pipe.ExpireGT(ctx, indexKey, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("save session: %w", err)
+	}
+	return nil
+}
+
+// touchScript saves a session only while its key exists, and then indexes
+// it as Save does, so a session deleted since it was read stays deleted.
+var touchScript = redis.NewScript(`
+if not redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2], "XX") then
+	return 0
+end
+redis.call("SADD", KEYS[2], ARGV[3])
+if redis.call("PTTL", KEYS[2]) < tonumber(ARGV[2]) then
+	redis.call("PEXPIRE", KEYS[2], ARGV[2])
+end
+return 1
+`)
+
+func (s *RedisStore) Touch(ctx context.Context, session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("encode session: %w", err)
+	}
+
+	ttl := session.ExpiresAt.Sub(s.clock.Now())
+	if ttl <= 0 {
+		return ErrNotFound
+	}
+
+	keys := []string{s.sessionKey(session.TokenHash), s.identityKey(session.Identity)}
+	saved, err := touchScript.Run(ctx, s.client, keys, data, ttl.Milliseconds(), session.TokenHash).Int()
+	if err != nil {
+		return fmt.Errorf("touch session: %w", err)
+	}
+	if saved == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *RedisStore) GetByTokenHash(ctx context.Context, tokenHash string) (*Session, error) {
+	session, err := s.get(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	if !s.clock.Now().Before(session.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+	return session, nil
+}
+
+func (s *RedisStore) List(ctx context.Context, identity string) ([]*Session, error) {
+	hashes, err := s.client.SMembers(ctx, s.identityKey(identity)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	now := s.clock.Now()
+	var sessions []*Session
+	var expired []interface{}
+	for _, hash := range hashes {
+		session, err := s.get(ctx, hash)
+		if errors.Is(err, ErrNotFound) {
+			expired = append(expired, hash)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !now.Before(session.ExpiresAt) {
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+	if len(expired) > 0 {
+		s.client.SRem(ctx, s.identityKey(identity), expired...)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+func (s *RedisStore) Delete(ctx context.Context, identity, id string) error {
+	sessions, err := s.List(ctx, identity)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if session.ID != id {
+			continue
+		}
+		_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, s.sessionKey(session.TokenHash))
+			pipe.SRem(ctx, s.identityKey(identity), session.TokenHash)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("delete session: %w", err)
+		}
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (s *RedisStore) get(ctx context.Context, tokenHash string) (*Session, error) {
+	data, err := s.client.Get(ctx, s.sessionKey(tokenHash)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, fmt.Errorf("decode session: %w", err)
+	}
+	return &session, nil
+}
+
+func (s *RedisStore) sessionKey(tokenHash string) string {
+	return s.prefix + "session:" + tokenHash
+}
+
+func (s *RedisStore) identityKey(identity string) string {
+	return s.prefix + "sessions:" + identity
+}
@@ -0,0 +1,101 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	store := NewRedisStore(client, "test:", fake)
+	manager := NewManager(store, Config{TTL: time.Hour}, fake)
+
+	first, token, err := manager.Create(ctx, "key:abc", nil, "test-agent", "10.0.0.1")
+	require.NoError(t, err)
+	_, _, err = manager.Create(ctx, "key:abc", nil, "", "")
+	require.NoError(t, err)
+	_, _, err = manager.Create(ctx, "key:other", nil, "", "")
+	require.NoError(t, err)
+
+	// A second instance sharing the Redis sees the same sessions.
+	otherStore := NewRedisStore(client, "test:", fake)
+	other := NewManager(otherStore, Config{TTL: time.Hour}, fake)
+	found, err := other.Lookup(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+	assert.Equal(t, "test-agent", found.UserAgent)
+
+	sessions, err := manager.List(ctx, "key:abc")
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	assert.ErrorIs(t, manager.Revoke(ctx, "key:other", first.ID), ErrNotFound, "other identities cannot revoke")
+	require.NoError(t, other.Revoke(ctx, "key:abc", first.ID))
+	_, err = manager.Lookup(ctx, token)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Redis expires sessions at ExpiresAt.
+	server.FastForward(time.Hour)
+	sessions, err = manager.List(ctx, "key:abc")
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+// revokingStore revokes each session right after it is read, as a revoke
+// racing a request that uses the session would.
+type revokingStore struct {
+	Store
+}
+
+func (s *revokingStore) GetByTokenHash(ctx context.Context, tokenHash string) (*Session, error) {
+	session, err := s.Store.GetByTokenHash(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	return session, s.Store.Delete(ctx, session.Identity, session.ID)
+}
+
+func TestManager_LookupDoesNotRestoreRevokedSession(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	stores := map[string]Store{
+		"memory":   NewMemoryStore(fake),
+		"redis":    NewRedisStore(client, "test:", fake),
+		"fallback": NewFallbackStore(NewRedisStore(client, "fallback:", fake), NewMemoryStore(fake)),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			_, token, err := NewManager(store, Config{TTL: time.Hour}, fake).Create(ctx, "key:abc", nil, "", "")
+			require.NoError(t, err)
+
+			racing := NewManager(&revokingStore{Store: store}, Config{TTL: time.Hour}, fake)
+			_, err = racing.Lookup(ctx, token)
+			assert.ErrorIs(t, err, ErrNotFound)
+
+			manager := NewManager(store, Config{TTL: time.Hour}, fake)
+			_, err = manager.Lookup(ctx, token)
+			assert.ErrorIs(t, err, ErrNotFound, "the revoked session stays revoked")
+			sessions, err := manager.List(ctx, "key:abc")
+			require.NoError(t, err)
+			assert.Empty(t, sessions)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+)
+
+var ErrNotFound = errors.New("session not found")
+
+// Session is a server-side browser session. ID is a public handle safe to
+// list and revoke by; the bearer token lives only in the cookie and is
+// stored hashed.
+type Session struct {
+	ID       string
+	Identity string
+	// StoreIDs are the stores the identity owned when the session started.
+	StoreIDs   []int64
+	TokenHash  string
+	CSRFToken  string
+	UserAgent  string
+	ClientIP   string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+}
+
+// Store persists sessions. Implementations must expire sessions at
+// ExpiresAt.
+type Store interface {
+	Save(ctx context.Context, s *Session) error
+	// Touch saves a session that is still stored, and returns ErrNotFound
+	// without saving it once it was deleted, so a session revoked while in
+	// use is not brought back.
+	Touch(ctx context.Context, s *Session) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Session, error)
+	List(ctx context.Context, identity string) ([]*Session, error)
+	Delete(ctx context.Context, identity, id string) error
+}
+
+type Config struct {
+	// TTL is the absolute session lifetime.
+	TTL time.Duration
+	// IdleTimeout ends sessions that go unused for this long.
+	IdleTimeout time.Duration
+}
+
+type Manager struct {
+	store Store
+	cfg   Config
+	clock clock.Clock
+}
+
+func NewManager(store Store, cfg Config, clk clock.Clock) *Manager {
+	return &Manager{store: store, cfg: cfg, clock: clk}
+}
+
+// Create starts a session for identity, which owns storeIDs, and returns it
+// with the bearer token to put in the cookie.
+func (m *Manager) Create(ctx context.Context, identity string, storeIDs []int64, userAgent, clientIP string) (*Session, string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	id, err := randomToken(12)
+	if err != nil {
+		return nil, "", err
+	}
+	csrf, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := m.clock.Now()
+	s := &Session{
+		ID:         id,
+		Identity:   identity,
+		StoreIDs:   storeIDs,
+		TokenHash:  hashToken(token),
+		CSRFToken:  csrf,
+		UserAgent:  userAgent,
+		ClientIP:   clientIP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  m.expiry(now, now),
+	}
+	if err := m.store.Save(ctx, s); err != nil {
+		return nil, "", fmt.Errorf("save session: %w", err)
+	}
+	return s, token, nil
+}
+
+// Lookup resolves a cookie token to its session and slides the idle
+// expiry forward.
+func (m *Manager) Lookup(ctx context.Context, token string) (*Session, error) {
+	s, err := m.store.GetByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := m.clock.Now()
+	if !now.Before(s.ExpiresAt) {
+		m.store.Delete(ctx, s.Identity, s.ID)
+		return nil, ErrNotFound
+	}
+
+	s.LastSeenAt = now
+	s.ExpiresAt = m.expiry(s.CreatedAt, now)
+	if err := m.store.Touch(ctx, s); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("save session: %w", err)
+	}
+	return s, nil
+}
+
+func (m *Manager) List(ctx context.Context, identity string) ([]*Session, error) {
+	return m.store.List(ctx, identity)
+}
+
+// Revoke ends one of identity's sessions. Sessions of other identities are
+// reported as not found.
+func (m *Manager) Revoke(ctx context.Context, identity, id string) error {
+	return m.store.Delete(ctx, identity, id)
+}
+
+// ValidCSRF compares the submitted token in constant time.
+func (s *Session) ValidCSRF(token string) bool {
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.CSRFToken)) == 1
+}
+
+func (m *Manager) expiry(createdAt, lastSeen time.Time) time.Time {
+	expiresAt := createdAt.Add(m.cfg.TTL)
+	if m.cfg.IdleTimeout > 0 {
+		if idle := lastSeen.Add(m.cfg.IdleTimeout); idle.Before(expiresAt) {
+			expiresAt = idle
+		}
+	}
+	return expiresAt
+}
+
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryStore keeps sessions in process memory. Sessions do not survive a
+// restart and are not shared between instances.
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+	clock    clock.Clock
+}
+
+func NewMemoryStore(clk clock.Clock) *MemoryStore {
+	return &MemoryStore{sessions: make(map[string]*Session), clock: clk}
+}
+
+func (s *MemoryStore) Save(_ context.Context, session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *session
+	s.sessions[session.TokenHash] = &copied
+	return nil
+}
+
+func (s *MemoryStore) Touch(_ context.Context, session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sessions[session.TokenHash]; !ok {
+		return ErrNotFound
+	}
+	copied := *session
+	s.sessions[session.TokenHash] = &copied
+	return nil
+}
+
+func (s *MemoryStore) GetByTokenHash(_ context.Context, tokenHash string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[tokenHash]
+	if !ok || !s.clock.Now().Before(session.ExpiresAt) {
+		delete(s.sessions, tokenHash)
+		return nil, ErrNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (s *MemoryStore) List(_ context.Context, identity string) ([]*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	var sessions []*Session
+	for hash, session := range s.sessions {
+		if !now.Before(session.ExpiresAt) {
+			delete(s.sessions, hash)
+			continue
+		}
+		if session.Identity == identity {
+			copied := *session
+			sessions = append(sessions, &copied)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, identity, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for hash, session := range s.sessions {
+		if session.ID == id && session.Identity == identity {
+			delete(s.sessions, hash)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
@@ -0,0 +1,81 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_CreateAndLookup(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+
+	store := NewMemoryStore(fake)
+	manager := NewManager(store, Config{TTL: 2 * time.Hour, IdleTimeout: 30 * time.Minute}, fake)
+
+	s, token, err := manager.Create(ctx, "key:abc", nil, "test-agent", "10.0.0.1")
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEqual(t, token, s.TokenHash, "token is stored hashed")
+	assert.Equal(t, start.Add(30*time.Minute), s.ExpiresAt)
+
+	fake.Advance(20 * time.Minute)
+	found, err := manager.Lookup(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, s.ID, found.ID)
+	assert.Equal(t, fake.Now().Add(30*time.Minute), found.ExpiresAt, "idle expiry slides")
+
+	_, err = manager.Lookup(ctx, "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Activity never extends a session past its absolute TTL.
+	for i := 0; i < 4; i++ {
+		fake.Advance(20 * time.Minute)
+		found, err = manager.Lookup(ctx, token)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, s.CreatedAt.Add(2*time.Hour), found.ExpiresAt)
+
+	fake.Advance(found.ExpiresAt.Sub(fake.Now()))
+	_, err = manager.Lookup(ctx, token)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestManager_ListAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	manager := NewManager(NewMemoryStore(clock.Real()), Config{TTL: time.Hour}, clock.Real())
+
+	first, token, err := manager.Create(ctx, "key:abc", nil, "", "")
+	require.NoError(t, err)
+	_, _, err = manager.Create(ctx, "key:abc", nil, "", "")
+	require.NoError(t, err)
+	_, _, err = manager.Create(ctx, "key:other", nil, "", "")
+	require.NoError(t, err)
+
+	sessions, err := manager.List(ctx, "key:abc")
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	assert.ErrorIs(t, manager.Revoke(ctx, "key:other", first.ID), ErrNotFound, "other identities cannot revoke")
+	require.NoError(t, manager.Revoke(ctx, "key:abc", first.ID))
+
+	_, err = manager.Lookup(ctx, token)
+	assert.ErrorIs(t, err, ErrNotFound)
+	sessions, err = manager.List(ctx, "key:abc")
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+}
+
+func TestSession_ValidCSRF(t *testing.T) {
+	s := &Session{CSRFToken: "token"}
+
+	assert.True(t, s.ValidCSRF("token"))
+	assert.False(t, s.ValidCSRF("other"))
+	assert.False(t, s.ValidCSRF(""))
+}
@@ -0,0 +1,45 @@
+package sqlutil
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// SQLSTATE codes of the constraint violations repositories map to domain
+// errors.
+const (
+	ForeignKeyViolation = "23503"
+	UniqueViolation     = "23505"
+)
+
+// Errors maps the errors of running statements on an entity to the errors
+// its repository returns.
+type Errors struct {
+	// NoRows is returned for sql.ErrNoRows; nil leaves it to be wrapped.
+	NoRows error
+	// Codes maps the SQLSTATE codes of PostgreSQL errors, such as
+	// UniqueViolation, to errors.
+	Codes map[string]error
+}
+
+// Map returns the error err maps to. Errors that map to nothing are
+// wrapped as the failure to do action, such as "create product". A nil
+// err maps to nil.
+func (e Errors) Map(err error, action string) error {
+	if err == nil {
+		return nil
+	}
+	if e.NoRows != nil && errors.Is(err, sql.ErrNoRows) {
+		return e.NoRows
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		if mapped, ok := e.Codes[string(pqErr.Code)]; ok {
+			return mapped
+		}
+	}
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
@@ -0,0 +1,81 @@
+// Package sqlutil runs queries with database/sql and scans their rows, so
+// a repository describes how to scan its entity once, in a ScanFunc, and
+// every query reading that entity goes through it.
+package sqlutil
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Querier runs statements; *sql.DB, *sql.Tx and *sql.Conn are Queriers.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Row is a row to scan; *sql.Row and *sql.Rows are Rows.
+type Row interface {
+	Scan(dest ...any) error
+}
+
+// Rows are the rows a query returned, as *sql.Rows are.
+type Rows interface {
+	Row
+	Next() bool
+	Err() error
+	Close() error
+}
+
+// ScanFunc reads a T from the columns of row.
+type ScanFunc[T any] func(row Row) (T, error)
+
+// QueryOne runs query and scans the row it returns. Like the Scan of
+// *sql.Row, it returns sql.ErrNoRows when there is none.
+func QueryOne[T any](ctx context.Context, q Querier, scan ScanFunc[T], query string, args ...any) (T, error) {
+	return scan(q.QueryRowContext(ctx, query, args...))
+}
+
+// QueryMany runs query and scans every row it returns.
+func QueryMany[T any](ctx context.Context, q Querier, scan ScanFunc[T], query string, args ...any) ([]T, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return ScanAll(rows, scan)
+}
+
+// ScanAll scans every row of rows and closes them, for queries run on a
+// prepared statement. It returns an empty slice, not nil, when there are
+// no rows.
+func ScanAll[T any](rows Rows, scan ScanFunc[T]) ([]T, error) {
+	defer rows.Close()
+
+	values := []T{}
+	for rows.Next() {
+		value, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Exec runs query and returns how many rows it affected.
+func Exec(ctx context.Context, q Querier, query string, args ...any) (int64, error) {
+	result, err := q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return affected, nil
+}
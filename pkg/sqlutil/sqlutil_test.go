@@ -0,0 +1,78 @@
+package sqlutil
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRows returns values one column at a time, then err.
+type fakeRows struct {
+	values []int64
+	next   int
+	err    error
+	closed bool
+}
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.values)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if r.values[r.next-1] < 0 {
+		return errors.New("negative value")
+	}
+	*dest[0].(*int64) = r.values[r.next-1]
+	return nil
+}
+
+func (r *fakeRows) Err() error   { return r.err }
+func (r *fakeRows) Close() error { r.closed = true; return nil }
+
+func scanInt(row Row) (int64, error) {
+	var v int64
+	err := row.Scan(&v)
+	return v, err
+}
+
+func TestScanAll(t *testing.T) {
+	rows := &fakeRows{values: []int64{3, 1, 2}}
+	values, err := ScanAll(rows, scanInt)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 1, 2}, values)
+	assert.True(t, rows.closed)
+
+	values, err = ScanAll(&fakeRows{}, scanInt)
+	require.NoError(t, err)
+	assert.NotNil(t, values, "no rows is an empty list")
+	assert.Empty(t, values)
+
+	_, err = ScanAll(&fakeRows{values: []int64{1, -1}}, scanInt)
+	assert.ErrorContains(t, err, "failed to scan row: negative value")
+
+	connReset := errors.New("connection reset")
+	_, err = ScanAll(&fakeRows{values: []int64{1}, err: connReset}, scanInt)
+	assert.ErrorIs(t, err, connReset)
+}
+
+func TestErrors_Map(t *testing.T) {
+	errNotFound := errors.New("not found")
+	errDuplicate := errors.New("duplicate")
+	e := Errors{NoRows: errNotFound, Codes: map[string]error{UniqueViolation: errDuplicate}}
+
+	assert.NoError(t, e.Map(nil, "get thing"))
+	assert.Equal(t, errNotFound, e.Map(sql.ErrNoRows, "get thing"))
+	assert.Equal(t, errDuplicate, e.Map(&pq.Error{Code: UniqueViolation}, "create thing"))
+
+	err := e.Map(&pq.Error{Code: ForeignKeyViolation, Message: "violates foreign key"}, "create thing")
+	assert.EqualError(t, err, "failed to create thing: pq: violates foreign key")
+
+	err = Errors{}.Map(sql.ErrNoRows, "get thing")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.EqualError(t, err, "failed to get thing: sql: no rows in result set")
+}
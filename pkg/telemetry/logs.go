@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+var severities = map[logrus.Level]log.Severity{
+	logrus.TraceLevel: log.SeverityTrace,
+	logrus.DebugLevel: log.SeverityDebug,
+	logrus.InfoLevel:  log.SeverityInfo,
+	logrus.WarnLevel:  log.SeverityWarn,
+	logrus.ErrorLevel: log.SeverityError,
+	logrus.FatalLevel: log.SeverityFatal,
+	logrus.PanicLevel: log.SeverityFatal3,
+}
+
+// LogHook bridges logrus into an OpenTelemetry logger provider. Fields
+// become attributes, and the error field becomes exception.message.
+type LogHook struct {
+	provider *sdklog.LoggerProvider
+	logger   log.Logger
+}
+
+func NewLogHook(provider *sdklog.LoggerProvider) *LogHook {
+	return &LogHook{provider: provider, logger: provider.Logger(scopeName)}
+}
+
+func (h *LogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *LogHook) Fire(entry *logrus.Entry) error {
+	var record log.Record
+	record.SetTimestamp(entry.Time)
+	record.SetSeverity(severities[entry.Level])
+	record.SetSeverityText(entry.Level.String())
+	record.SetBody(log.StringValue(entry.Message))
+
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := key
+		if key == logrus.ErrorKey {
+			name = "exception.message"
+		}
+		record.AddAttributes(log.String(name, fmt.Sprint(entry.Data[key])))
+	}
+
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	h.logger.Emit(ctx, record)
+
+	// Fatal and panic entries end the process before the next batch is
+	// exported, so ship them right away.
+	if entry.Level <= logrus.FatalLevel {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return h.provider.ForceFlush(flushCtx)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/otlptranslator"
+	"go.opentelemetry.io/otel/attribute"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a value reported by a metric callback.
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// Registry creates the service's metrics on an OpenTelemetry meter
+// provider. Counters and histograms are updated by instrumented code;
+// callback metrics read existing stats objects at collection time. A
+// Prometheus reader always backs WritePrometheus and PrometheusHandler;
+// other readers, such as the OTLP one, export the same metrics.
+type Registry struct {
+	provider *sdkmetric.MeterProvider
+	meter    metric.Meter
+	gatherer *prometheus.Registry
+
+	mu    sync.Mutex
+	names map[string]bool
+}
+
+func NewRegistry(resource Resource, readers ...sdkmetric.Reader) *Registry {
+	gatherer := prometheus.NewRegistry()
+	exporter, err := otelprometheus.New(
+		otelprometheus.WithRegisterer(gatherer),
+		// Metric names are already in Prometheus form, so they are kept
+		// as registered rather than given unit and _total suffixes.
+		otelprometheus.WithTranslationStrategy(otlptranslator.UnderscoreEscapingWithoutSuffixes),
+		otelprometheus.WithoutScopeInfo(),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("telemetry: failed to create Prometheus exporter: %v", err))
+	}
+
+	opts := []sdkmetric.Option{sdkmetric.WithResource(resource.otel()), sdkmetric.WithReader(exporter)}
+	for _, reader := range readers {
+		opts = append(opts, sdkmetric.WithReader(reader))
+	}
+	provider := sdkmetric.NewMeterProvider(opts...)
+
+	return &Registry{
+		provider: provider,
+		meter:    provider.Meter(scopeName),
+		gatherer: gatherer,
+		names:    make(map[string]bool),
+	}
+}
+
+// Shutdown exports what the readers still hold and stops them.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	return r.provider.Shutdown(ctx)
+}
+
+func (r *Registry) register(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.names[name] {
+		panic(fmt.Sprintf("telemetry: metric %q registered twice", name))
+	}
+	r.names[name] = true
+}
+
+func must[T any](instrument T, err error) T {
+	if err != nil {
+		panic(fmt.Sprintf("telemetry: %v", err))
+	}
+	return instrument
+}
+
+// attributes pairs label names with values.
+func attributes(names, values []string) metric.MeasurementOption {
+	if len(values) != len(names) {
+		panic(fmt.Sprintf("telemetry: got %d label values for %d labels", len(values), len(names)))
+	}
+	kvs := make([]attribute.KeyValue, len(names))
+	for i, name := range names {
+		kvs[i] = attribute.String(name, values[i])
+	}
+	return metric.WithAttributeSet(attribute.NewSet(kvs...))
+}
+
+type Counter struct {
+	counter metric.Float64Counter
+	labels  []string
+}
+
+func (r *Registry) NewCounter(name, help string, labelNames ...string) *Counter {
+	r.register(name)
+	return &Counter{
+		counter: must(r.meter.Float64Counter(name, metric.WithDescription(help))),
+		labels:  labelNames,
+	}
+}
+
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	c.counter.Add(context.Background(), delta, attributes(c.labels, labelValues))
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+type Histogram struct {
+	histogram metric.Float64Histogram
+	labels    []string
+}
+
+// DefaultDurationBuckets suit request latencies in seconds.
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+func (r *Registry) NewHistogram(name, help string, bounds []float64, labelNames ...string) *Histogram {
+	r.register(name)
+	return &Histogram{
+		histogram: must(r.meter.Float64Histogram(name, metric.WithDescription(help), metric.WithExplicitBucketBoundaries(bounds...))),
+		labels:    labelNames,
+	}
+}
+
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.histogram.Record(context.Background(), value, attributes(h.labels, labelValues))
+}
+
+func observe(fn func() []Sample) metric.Float64Callback {
+	return func(_ context.Context, o metric.Float64Observer) error {
+		for _, sample := range fn() {
+			kvs := make([]attribute.KeyValue, len(sample.Labels))
+			for i, label := range sample.Labels {
+				kvs[i] = attribute.String(label.Name, label.Value)
+			}
+			o.Observe(sample.Value, metric.WithAttributes(kvs...))
+		}
+		return nil
+	}
+}
+
+// RegisterGaugeFunc adds a gauge whose samples fn reports at collection.
+func (r *Registry) RegisterGaugeFunc(name, help string, fn func() []Sample) {
+	r.register(name)
+	must(r.meter.Float64ObservableGauge(name, metric.WithDescription(help), metric.WithFloat64Callback(observe(fn))))
+}
+
+// RegisterCounterFunc adds a counter whose running totals fn reports at
+// collection, for stats that are already counted elsewhere.
+func (r *Registry) RegisterCounterFunc(name, help string, fn func() []Sample) {
+	r.register(name)
+	must(r.meter.Float64ObservableCounter(name, metric.WithDescription(help), metric.WithFloat64Callback(observe(fn))))
+}
+
+// RegisterRuntimeMetrics adds Go runtime gauges and counters, and the
+// process uptime measured on clk from now.
+func (r *Registry) RegisterRuntimeMetrics(clk clock.Clock) {
+	var (
+		start    = clk.Now()
+		mu       sync.Mutex
+		stats    runtime.MemStats
+		readAt   time.Time
+		memStats = func() runtime.MemStats {
+			mu.Lock()
+			defer mu.Unlock()
+			// One collection reads several fields; avoid stopping the
+			// world for each of them.
+			if now := clk.Now(); now.Sub(readAt) > time.Second {
+				runtime.ReadMemStats(&stats)
+				readAt = now
+			}
+			return stats
+		}
+		single = func(v float64) []Sample { return []Sample{{Value: v}} }
+	)
+
+	r.RegisterGaugeFunc("process_runtime_go_goroutines", "Number of live goroutines.", func() []Sample {
+		return single(float64(runtime.NumGoroutine()))
+	})
+	r.RegisterGaugeFunc("process_runtime_go_mem_heap_alloc_bytes", "Bytes of allocated heap objects.", func() []Sample {
+		return single(float64(memStats().HeapAlloc))
+	})
+	r.RegisterGaugeFunc("process_runtime_go_mem_heap_objects", "Number of allocated heap objects.", func() []Sample {
+		return single(float64(memStats().HeapObjects))
+	})
+	r.RegisterCounterFunc("process_runtime_go_gc_count_total", "Completed GC cycles.", func() []Sample {
+		return single(float64(memStats().NumGC))
+	})
+	r.RegisterCounterFunc("process_runtime_go_gc_pause_seconds_total", "Total GC stop-the-world pause time.", func() []Sample {
+		return single(time.Duration(memStats().PauseTotalNs).Seconds())
+	})
+	r.RegisterGaugeFunc("process_uptime_seconds", "Seconds since the process started.", func() []Sample {
+		return single(clk.Now().Sub(start).Seconds())
+	})
+}
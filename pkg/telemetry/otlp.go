@@ -0,0 +1,72 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// OTLPConfig points the OTLP/HTTP exporters at a collector.
+type OTLPConfig struct {
+	// Endpoint is the collector's base URL, such as http://localhost:4318.
+	// Signals are sent to /v1/metrics and /v1/logs under it.
+	Endpoint string
+	// Headers are "key=value" entries, as given in
+	// OTEL_EXPORTER_OTLP_HEADERS.
+	Headers []string
+}
+
+func (c OTLPConfig) url(path string) string {
+	return strings.TrimRight(c.Endpoint, "/") + path
+}
+
+func (c OTLPConfig) headers() map[string]string {
+	parsed := make(map[string]string, len(c.Headers))
+	for _, header := range c.Headers {
+		if key, value, ok := strings.Cut(header, "="); ok {
+			parsed[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return parsed
+}
+
+// NewOTLPMetricReader pushes metrics to the collector every interval and
+// once more on Registry.Shutdown.
+func NewOTLPMetricReader(ctx context.Context, cfg OTLPConfig, interval time.Duration) (sdkmetric.Reader, error) {
+	exporter, err := otlpmetrichttp.New(ctx,
+		otlpmetrichttp.WithEndpointURL(cfg.url("/v1/metrics")),
+		otlpmetrichttp.WithHeaders(cfg.headers()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval)), nil
+}
+
+// NewLoggerProvider ships log records to the collector in batches of up
+// to batchSize, at least every interval. When its queue is full, records
+// are dropped rather than slowing the service down. Export failures are
+// reported through the OpenTelemetry error handler.
+func NewLoggerProvider(ctx context.Context, cfg OTLPConfig, resource Resource, batchSize int, interval time.Duration) (*sdklog.LoggerProvider, error) {
+	exporter, err := otlploghttp.New(ctx,
+		otlploghttp.WithEndpointURL(cfg.url("/v1/logs")),
+		otlploghttp.WithHeaders(cfg.headers()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []sdklog.BatchProcessorOption{sdklog.WithExportInterval(interval)}
+	if batchSize > 0 {
+		opts = append(opts, sdklog.WithExportMaxBatchSize(batchSize))
+	}
+	return sdklog.NewLoggerProvider(
+		sdklog.WithResource(resource.otel()),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter, opts...)),
+	), nil
+}
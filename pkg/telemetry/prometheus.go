@@ -0,0 +1,31 @@
+package telemetry
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
+)
+
+// PrometheusHandler serves the registry in the Prometheus exposition
+// format. Resource attributes are exposed on a target_info series.
+func (r *Registry) PrometheusHandler() http.Handler {
+	return promhttp.HandlerFor(r.gatherer, promhttp.HandlerOpts{})
+}
+
+// WritePrometheus writes the registry in the Prometheus text format.
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	families, err := r.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	enc := expfmt.NewEncoder(w, expfmt.NewFormat(expfmt.TypeTextPlain))
+	for _, family := range families {
+		if err := enc.Encode(family); err != nil {
+			return err
+		}
+	}
+	return nil
+}
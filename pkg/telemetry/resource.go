@@ -0,0 +1,65 @@
+// Package telemetry sets up the OpenTelemetry SDK for logs and metrics
+// with one set of resource attributes. Metrics can be pulled in the
+// Prometheus text format or pushed, like logs, to an OpenTelemetry
+// collector over OTLP/HTTP.
+package telemetry
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// scopeName is the instrumentation scope reported with every signal.
+const scopeName = "backend-context-engineering-template"
+
+// Resource describes the service emitting telemetry. Its attributes are
+// attached to every exported log record and metric.
+type Resource struct {
+	resource *resource.Resource
+}
+
+// NewResource builds the resource from the service name and environment,
+// the host, the process and the SDK, plus extra "key=value" attributes as
+// given in OTEL_RESOURCE_ATTRIBUTES. Extra attributes override the
+// defaults.
+func NewResource(serviceName, environment string, extra []string) Resource {
+	attributes := []attribute.KeyValue{
+		attribute.String("service.name", serviceName),
+		attribute.String("deployment.environment", environment),
+	}
+	if host, err := os.Hostname(); err == nil {
+		attributes = append(attributes, attribute.String("service.instance.id", host+"-"+strconv.Itoa(os.Getpid())))
+	}
+	for _, attr := range extra {
+		if key, value, ok := strings.Cut(attr, "="); ok && key != "" {
+			attributes = append(attributes, attribute.String(strings.TrimSpace(key), strings.TrimSpace(value)))
+		}
+	}
+
+	// The detectors only fail partially, for example without a hostname;
+	// the attributes they did find are kept.
+	res, _ := resource.New(context.Background(),
+		resource.WithHost(),
+		resource.WithProcessPID(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(attributes...),
+	)
+	return Resource{resource: res}
+}
+
+func (r Resource) Attribute(key string) string {
+	value, _ := r.otel().Set().Value(attribute.Key(key))
+	return value.Emit()
+}
+
+func (r Resource) otel() *resource.Resource {
+	if r.resource == nil {
+		return resource.Empty()
+	}
+	return r.resource
+}
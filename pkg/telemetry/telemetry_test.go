@@ -0,0 +1,168 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"backend-context-engineering-template/pkg/clock"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	collogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestRegistry(t *testing.T, readers ...sdkmetric.Reader) *Registry {
+	registry := NewRegistry(NewResource("product-service", "test", []string{"service.version=1.2.3"}), readers...)
+	t.Cleanup(func() { registry.Shutdown(context.Background()) })
+
+	requests := registry.NewCounter("requests_total", "Requests served.", "route")
+	requests.Inc("/a")
+	requests.Add(2, "/b")
+
+	latency := registry.NewHistogram("latency_seconds", "Latency.", []float64{0.1, 1}, "route")
+	latency.Observe(0.05, "/a")
+	latency.Observe(0.5, "/a")
+	latency.Observe(3, "/a")
+
+	registry.RegisterGaugeFunc("queue_depth", "Queued items.", func() []Sample {
+		return []Sample{{Value: 7}}
+	})
+	return registry
+}
+
+func TestRegistry_WritePrometheus(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestRegistry(t).WritePrometheus(&buf))
+	out := buf.String()
+
+	assert.Contains(t, out, `target_info{deployment_environment="test",`)
+	assert.Contains(t, out, `service_version="1.2.3"`)
+	assert.Contains(t, out, "# TYPE requests_total counter\n")
+	assert.Contains(t, out, `requests_total{route="/a"} 1`+"\n")
+	assert.Contains(t, out, `requests_total{route="/b"} 2`+"\n")
+	assert.Contains(t, out, "# TYPE latency_seconds histogram\n")
+	assert.Contains(t, out, `latency_seconds_bucket{route="/a",le="0.1"} 1`+"\n")
+	assert.Contains(t, out, `latency_seconds_bucket{route="/a",le="1"} 2`+"\n")
+	assert.Contains(t, out, `latency_seconds_bucket{route="/a",le="+Inf"} 3`+"\n")
+	assert.Contains(t, out, `latency_seconds_sum{route="/a"} 3.55`+"\n")
+	assert.Contains(t, out, `latency_seconds_count{route="/a"} 3`+"\n")
+	assert.Contains(t, out, "queue_depth 7\n")
+}
+
+func TestRegistry_RuntimeMetrics(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	registry := NewRegistry(NewResource("product-service", "test", nil))
+	registry.RegisterRuntimeMetrics(fake)
+	fake.Advance(90 * time.Second)
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), "process_uptime_seconds 90\n")
+	assert.Contains(t, buf.String(), "# TYPE process_runtime_go_gc_count_total counter\n")
+}
+
+// collector records the OTLP/HTTP requests it receives.
+type collector struct {
+	mu     sync.Mutex
+	bodies map[string][][]byte
+}
+
+func newCollector(t *testing.T) (*collector, OTLPConfig) {
+	c := &collector{bodies: make(map[string][][]byte)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+
+		c.mu.Lock()
+		c.bodies[r.URL.Path] = append(c.bodies[r.URL.Path], body)
+		c.mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return c, OTLPConfig{Endpoint: server.URL + "/", Headers: []string{"Authorization=secret"}}
+}
+
+func (c *collector) get(path string) [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bodies[path]
+}
+
+func TestOTLPMetricReader(t *testing.T) {
+	c, cfg := newCollector(t)
+	reader, err := NewOTLPMetricReader(context.Background(), cfg, time.Hour)
+	require.NoError(t, err)
+	registry := newTestRegistry(t, reader)
+
+	require.NoError(t, registry.Shutdown(context.Background()))
+
+	bodies := c.get("/v1/metrics")
+	require.Len(t, bodies, 1)
+	var req colmetrics.ExportMetricsServiceRequest
+	require.NoError(t, proto.Unmarshal(bodies[0], &req))
+
+	resourceMetrics := req.ResourceMetrics[0]
+	attributes := make(map[string]string)
+	for _, kv := range resourceMetrics.Resource.Attributes {
+		attributes[kv.Key] = kv.Value.GetStringValue()
+	}
+	assert.Equal(t, "product-service", attributes["service.name"])
+	assert.Equal(t, "1.2.3", attributes["service.version"])
+
+	scope := resourceMetrics.ScopeMetrics[0]
+	assert.Equal(t, scopeName, scope.Scope.Name)
+	byName := make(map[string]int)
+	for i, metric := range scope.Metrics {
+		byName[metric.Name] = i
+	}
+	sum := scope.Metrics[byName["requests_total"]].GetSum()
+	assert.True(t, sum.IsMonotonic)
+	assert.Len(t, sum.DataPoints, 2)
+	assert.NotNil(t, scope.Metrics[byName["queue_depth"]].GetGauge())
+	point := scope.Metrics[byName["latency_seconds"]].GetHistogram().DataPoints[0]
+	assert.Equal(t, uint64(3), point.Count)
+	assert.Equal(t, []uint64{1, 1, 1}, point.BucketCounts)
+}
+
+func TestLogHook(t *testing.T) {
+	c, cfg := newCollector(t)
+	provider, err := NewLoggerProvider(context.Background(), cfg, NewResource("product-service", "test", nil), 10, time.Hour)
+	require.NoError(t, err)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(NewLogHook(provider))
+
+	logger.WithField("product_id", 42).Warn("Product updated")
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	bodies := c.get("/v1/logs")
+	require.Len(t, bodies, 1)
+	var req collogs.ExportLogsServiceRequest
+	require.NoError(t, proto.Unmarshal(bodies[0], &req))
+	record := req.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+
+	assert.Equal(t, int32(13), int32(record.SeverityNumber))
+	assert.Equal(t, "warning", record.SeverityText)
+	assert.Equal(t, "Product updated", record.Body.GetStringValue())
+	require.Len(t, record.Attributes, 1)
+	assert.Equal(t, "product_id", record.Attributes[0].Key)
+	assert.Equal(t, "42", record.Attributes[0].Value.GetStringValue())
+}
+
+func TestRegistry_DuplicateNamePanics(t *testing.T) {
+	registry := NewRegistry(NewResource("product-service", "test", nil))
+	registry.NewCounter("requests_total", "Requests served.")
+
+	assert.Panics(t, func() { registry.NewCounter("requests_total", "Requests served.") })
+}
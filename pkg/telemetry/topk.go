@@ -0,0 +1,86 @@
+package telemetry
+
+import "sync"
+
+// OtherLabel is reported for values outside the top K.
+const OtherLabel = "other"
+
+// TopK bounds the cardinality of a label such as a store ID. It tracks the
+// most frequent values with the space-saving algorithm, which needs a
+// fixed number of counters however many distinct values are seen, and
+// passes through only the K heaviest; everything else becomes "other".
+//
+// Values can move in and out of the top K as traffic shifts, so a series
+// may stop being updated while its traffic is folded into "other".
+type TopK struct {
+	k int
+
+	mu       sync.Mutex
+	counts   map[string]*topKCounter
+	capacity int
+}
+
+// topKCounter holds a value's count and the count it inherited on
+// admission, so count-err is how often the value was definitely seen.
+type topKCounter struct {
+	count uint64
+	err   uint64
+}
+
+func (c *topKCounter) guaranteed() uint64 {
+	return c.count - c.err
+}
+
+// NewTopK keeps k values. It monitors twice as many candidates so a value
+// climbing into the top K is not evicted before it gets there.
+func NewTopK(k int) *TopK {
+	if k <= 0 {
+		k = 1
+	}
+	return &TopK{
+		k:        k,
+		counts:   make(map[string]*topKCounter, 2*k),
+		capacity: 2 * k,
+	}
+}
+
+// Label counts one occurrence of value and returns value if it is among
+// the top K, or OtherLabel.
+func (t *TopK) Label(value string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counter, ok := t.counts[value]
+	if !ok {
+		counter = &topKCounter{}
+		if len(t.counts) >= t.capacity {
+			// Space-saving: the new value takes over the smallest counter,
+			// inheriting its count as the bound on how often it was missed.
+			var minValue string
+			var minCount uint64
+			first := true
+			for v, c := range t.counts {
+				if first || c.count < minCount {
+					minValue, minCount, first = v, c.count, false
+				}
+			}
+			delete(t.counts, minValue)
+			counter.count, counter.err = minCount, minCount
+		}
+		t.counts[value] = counter
+	}
+	counter.count++
+
+	// Rank by guaranteed counts so a newcomer's inherited count cannot
+	// push it past values that were really seen more often.
+	larger := 0
+	for _, c := range t.counts {
+		if c.guaranteed() > counter.guaranteed() {
+			larger++
+		}
+	}
+	if larger < t.k {
+		return value
+	}
+	return OtherLabel
+}
@@ -0,0 +1,23 @@
+package telemetry
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopK(t *testing.T) {
+	topK := NewTopK(2)
+
+	for i := 0; i < 50; i++ {
+		topK.Label("a")
+		topK.Label("b")
+	}
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, OtherLabel, topK.Label("tail-"+strconv.Itoa(i)))
+	}
+
+	assert.Equal(t, "a", topK.Label("a"))
+	assert.Equal(t, "b", topK.Label("b"))
+}
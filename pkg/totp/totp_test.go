@@ -0,0 +1,76 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA1 seed from RFC 6238 appendix B.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, time.Unix(tt.unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "t=%d", tt.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	current, err := Code(secret, now)
+	require.NoError(t, err)
+	previous, err := Code(secret, now.Add(-period))
+	require.NoError(t, err)
+	stale, err := Code(secret, now.Add(-3*period))
+	require.NoError(t, err)
+
+	assert.True(t, Validate(secret, current, now, 1))
+	assert.True(t, Validate(secret, previous, now, 1))
+	assert.False(t, Validate(secret, previous, now, 0))
+	assert.False(t, Validate(secret, stale, now, 1))
+	assert.False(t, Validate(secret, "12345", now, 1))
+}
+
+func TestMatch(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	previous, err := Code(secret, now.Add(-period))
+	require.NoError(t, err)
+
+	step, ok := Match(secret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, Step(now)-1, step)
+
+	_, ok = Match(secret, previous, now, 0)
+	assert.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("product-service", "key:abc", "SECRET")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/product-service:key:abc?"))
+	assert.Contains(t, uri, "secret=SECRET")
+	assert.Contains(t, uri, "issuer=product-service")
+}
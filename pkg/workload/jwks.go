@@ -0,0 +1,175 @@
+package workload
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// minRefetch throttles refetching the key set for unknown key ids, so
+// tokens with made-up kids cannot hammer the issuer.
+const minRefetch = 30 * time.Second
+
+// KeySet is an issuer's JSON Web Key Set, fetched over HTTP and cached for
+// refresh. SPIRE's OIDC discovery provider, Google and EKS all publish
+// their signing keys this way.
+type KeySet struct {
+	client  *http.Client
+	url     string
+	refresh time.Duration
+	now     func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+func NewKeySet(client *http.Client, url string, refresh time.Duration) *KeySet {
+	return &KeySet{
+		client:  client,
+		url:     url,
+		refresh: refresh,
+		now:     time.Now,
+	}
+}
+
+// Key returns the public key with the given id, refetching the set when it
+// is stale or does not contain kid.
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	age := s.now().Sub(s.fetchedAt)
+	key, ok := s.keys[kid]
+	if s.keys == nil || age > s.refresh || (!ok && age > minRefetch) {
+		if err := s.fetch(ctx); err != nil {
+			if ok {
+				// Keep serving a known key when the issuer is briefly down.
+				return key, nil
+			}
+			return nil, err
+		}
+		key, ok = s.keys[kid]
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key id %q", ErrInvalidToken, kid)
+	}
+	return key, nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (s *KeySet) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch key set: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch key set: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("decode key set: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Skip key types we do not support rather than failing the set.
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	s.keys = keys
+	s.fetchedAt = s.now()
+	return nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// ParseIssuers reads "issuer=jwks_url" entries into key sets, such as
+// "https://accounts.google.com=https://www.googleapis.com/oauth2/v3/certs".
+func ParseIssuers(entries []string, client *http.Client, refresh time.Duration) (map[string]*KeySet, error) {
+	issuers := make(map[string]*KeySet, len(entries))
+	for _, entry := range entries {
+		issuer, url, ok := strings.Cut(entry, "=")
+		if !ok || issuer == "" || url == "" {
+			return nil, fmt.Errorf("invalid workload issuer %q, want issuer=jwks_url", entry)
+		}
+		issuers[issuer] = NewKeySet(client, url, refresh)
+	}
+	return issuers, nil
+}
@@ -0,0 +1,247 @@
+// Package workload authenticates internal services by workload identity
+// instead of long-lived API keys: SPIFFE JWT-SVIDs and cloud OIDC identity
+// tokens (GCP service accounts, EKS/IRSA service account tokens) verified
+// against their issuer's published keys, and X.509-SVIDs presented as
+// mTLS client certificates.
+package workload
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken   = errors.New("invalid workload token")
+	ErrUnknownIssuer  = errors.New("untrusted workload token issuer")
+	ErrNoSPIFFEID     = errors.New("certificate has no SPIFFE ID")
+	ErrUnmappedRole   = errors.New("workload has no service role")
+	errUnsupportedAlg = errors.New("unsupported signing algorithm")
+)
+
+// clockSkew tolerates small clock differences between issuer and server.
+const clockSkew = 30 * time.Second
+
+// Identity kinds.
+const (
+	KindJWT  = "jwt"
+	KindX509 = "x509-svid"
+)
+
+// Identity is an authenticated workload. ID is the SPIFFE ID for SVIDs and
+// the service account email (or subject) for cloud tokens.
+type Identity struct {
+	ID     string
+	Issuer string
+	Kind   string
+}
+
+// Verifier checks workload JWTs issued by one of the trusted issuers for
+// the configured audience.
+type Verifier struct {
+	issuers  map[string]*KeySet
+	audience string
+	now      func() time.Time
+}
+
+// NewVerifier trusts tokens from the given issuers, keyed by the token's
+// iss claim. Tokens must name audience in their aud claim.
+func NewVerifier(issuers map[string]*KeySet, audience string) *Verifier {
+	return &Verifier{
+		issuers:  issuers,
+		audience: audience,
+		now:      time.Now,
+	}
+}
+
+type claims struct {
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	Email     string   `json:"email"`
+}
+
+// audience accepts the aud claim as a string or a list of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+func (v *Verifier) Verify(ctx context.Context, token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Identity{}, err
+	}
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return Identity{}, err
+	}
+
+	keys, ok := v.issuers[c.Issuer]
+	if !ok {
+		return Identity{}, fmt.Errorf("%w: %q", ErrUnknownIssuer, c.Issuer)
+	}
+	key, err := keys.Key(ctx, header.Kid)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	now := v.now()
+	if c.ExpiresAt == 0 || now.After(time.Unix(c.ExpiresAt, 0).Add(clockSkew)) {
+		return Identity{}, fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if c.NotBefore != 0 && now.Add(clockSkew).Before(time.Unix(c.NotBefore, 0)) {
+		return Identity{}, fmt.Errorf("%w: token not yet valid", ErrInvalidToken)
+	}
+	if !c.Audience.contains(v.audience) {
+		return Identity{}, fmt.Errorf("%w: wrong audience", ErrInvalidToken)
+	}
+
+	id := c.Subject
+	if c.Email != "" {
+		id = c.Email
+	}
+	if id == "" {
+		return Identity{}, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return Identity{ID: id, Issuer: c.Issuer, Kind: KindJWT}, nil
+}
+
+// FromCertificate returns the SPIFFE ID of an X.509-SVID, issued by its
+// trust domain such as spiffe://prod.example. The certificate chain must
+// already have been verified against the trust bundle, which the TLS server
+// does when it is configured with client CAs.
+func FromCertificate(cert *x509.Certificate) (Identity, error) {
+	for _, uri := range cert.URIs {
+		if uri.Scheme == "spiffe" && uri.Host != "" {
+			return Identity{ID: uri.String(), Issuer: "spiffe://" + uri.Host, Kind: KindX509}, nil
+		}
+	}
+	return Identity{}, ErrNoSPIFFEID
+}
+
+func (a audience) contains(want string) bool {
+	for _, aud := range a {
+		if aud == want {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+	return nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+
+	switch alg {
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match algorithm")
+		}
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature)
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match algorithm")
+		}
+		if len(signature) != 64 {
+			return errors.New("malformed signature")
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return errors.New("signature mismatch")
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w %q", errUnsupportedAlg, alg)
+	}
+}
+
+// Roles maps workloads to service roles. A workload is named by its issuer
+// and ID together, so the same subject from another trusted issuer does not
+// inherit its role.
+type Roles map[string]string
+
+// ParseRoles reads "issuer|id=role" entries such as
+// "spiffe://prod.example|spiffe://prod.example/ns/search/sa/indexer=catalog-reader"
+// or "https://accounts.google.com|indexer@proj.iam.gserviceaccount.com=catalog-reader".
+func ParseRoles(entries []string) (Roles, error) {
+	roles := make(Roles, len(entries))
+	for _, entry := range entries {
+		i := strings.LastIndex(entry, "=")
+		if i <= 0 || i == len(entry)-1 {
+			return nil, fmt.Errorf("invalid workload role %q, want issuer|id=role", entry)
+		}
+		issuer, id, ok := strings.Cut(entry[:i], "|")
+		if !ok || issuer == "" || id == "" {
+			return nil, fmt.Errorf("invalid workload role %q, want issuer|id=role", entry)
+		}
+		roles[roleKey(issuer, id)] = entry[i+1:]
+	}
+	return roles, nil
+}
+
+// Role returns the service role of the workload, or ErrUnmappedRole.
+func (r Roles) Role(identity Identity) (string, error) {
+	role, ok := r[roleKey(identity.Issuer, identity.ID)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s from %s", ErrUnmappedRole, identity.ID, identity.Issuer)
+	}
+	return role, nil
+}
+
+func roleKey(issuer, id string) string {
+	return issuer + "|" + id
+}
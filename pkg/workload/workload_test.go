@@ -0,0 +1,168 @@
+package workload
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIssuer = "https://spire.example"
+
+type testKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return testKeys{rsa: rsaKey, ec: ecKey}
+}
+
+func (k testKeys) jwksServer(t *testing.T) *httptest.Server {
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	set := map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(k.rsa.N.Bytes()), "e": b64(big.NewInt(int64(k.rsa.E)).Bytes())},
+			{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(k.ec.X.FillBytes(make([]byte, 32))), "y": b64(k.ec.Y.FillBytes(make([]byte, 32)))},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (k testKeys) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	encode := func(v interface{}) string {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := encode(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch alg {
+	case "RS256":
+		sig, err := rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+		signature = sig
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		require.NoError(t, err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	keys := newTestKeys(t)
+	server := keys.jwksServer(t)
+	verifier := NewVerifier(map[string]*KeySet{
+		testIssuer: NewKeySet(server.Client(), server.URL, time.Hour),
+	}, "product-service")
+
+	now := time.Now()
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss": testIssuer,
+			"sub": "spiffe://prod.example/ns/search/sa/indexer",
+			"aud": []string{"product-service"},
+			"exp": now.Add(5 * time.Minute).Unix(),
+		}
+	}
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := valid()
+		claims[key] = value
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantID  string
+		wantErr error
+	}{
+		{name: "RS256 JWT-SVID", token: keys.sign(t, "RS256", "rsa-1", valid()), wantID: "spiffe://prod.example/ns/search/sa/indexer"},
+		{name: "ES256 JWT-SVID", token: keys.sign(t, "ES256", "ec-1", valid()), wantID: "spiffe://prod.example/ns/search/sa/indexer"},
+		{name: "cloud token uses email", token: keys.sign(t, "RS256", "rsa-1", with("email", "indexer@project.iam.gserviceaccount.com")), wantID: "indexer@project.iam.gserviceaccount.com"},
+		{name: "string audience", token: keys.sign(t, "RS256", "rsa-1", with("aud", "product-service")), wantID: "spiffe://prod.example/ns/search/sa/indexer"},
+		{name: "wrong audience", token: keys.sign(t, "RS256", "rsa-1", with("aud", "other-service")), wantErr: ErrInvalidToken},
+		{name: "expired", token: keys.sign(t, "RS256", "rsa-1", with("exp", now.Add(-time.Hour).Unix())), wantErr: ErrInvalidToken},
+		{name: "not yet valid", token: keys.sign(t, "RS256", "rsa-1", with("nbf", now.Add(time.Hour).Unix())), wantErr: ErrInvalidToken},
+		{name: "untrusted issuer", token: keys.sign(t, "RS256", "rsa-1", with("iss", "https://evil.example")), wantErr: ErrUnknownIssuer},
+		{name: "unknown key id", token: keys.sign(t, "RS256", "rsa-2", valid()), wantErr: ErrInvalidToken},
+		{name: "algorithm mismatch", token: keys.sign(t, "ES256", "rsa-1", valid()), wantErr: ErrInvalidToken},
+		{name: "unsigned", token: keys.sign(t, "none", "rsa-1", valid()), wantErr: ErrInvalidToken},
+		{name: "malformed", token: "not-a-token", wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := verifier.Verify(context.Background(), tt.token)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantID, identity.ID)
+			assert.Equal(t, testIssuer, identity.Issuer)
+			assert.Equal(t, KindJWT, identity.Kind)
+		})
+	}
+}
+
+func TestFromCertificate(t *testing.T) {
+	id, err := url.Parse("spiffe://prod.example/ns/search/sa/indexer")
+	require.NoError(t, err)
+
+	identity, err := FromCertificate(&x509.Certificate{URIs: []*url.URL{id}})
+	require.NoError(t, err)
+	assert.Equal(t, "spiffe://prod.example/ns/search/sa/indexer", identity.ID)
+	assert.Equal(t, "spiffe://prod.example", identity.Issuer)
+	assert.Equal(t, KindX509, identity.Kind)
+
+	_, err = FromCertificate(&x509.Certificate{})
+	assert.ErrorIs(t, err, ErrNoSPIFFEID)
+}
+
+func TestParseRoles(t *testing.T) {
+	roles, err := ParseRoles([]string{"spiffe://prod.example|spiffe://prod.example/ns/search/sa/indexer=catalog-reader"})
+	require.NoError(t, err)
+
+	role, err := roles.Role(Identity{ID: "spiffe://prod.example/ns/search/sa/indexer", Issuer: "spiffe://prod.example"})
+	require.NoError(t, err)
+	assert.Equal(t, "catalog-reader", role)
+
+	_, err = roles.Role(Identity{ID: "spiffe://prod.example/ns/other", Issuer: "spiffe://prod.example"})
+	assert.ErrorIs(t, err, ErrUnmappedRole)
+
+	_, err = roles.Role(Identity{ID: "spiffe://prod.example/ns/search/sa/indexer", Issuer: "https://evil.example"})
+	assert.ErrorIs(t, err, ErrUnmappedRole, "the same subject from another issuer has no role")
+
+	_, err = ParseRoles([]string{"missing-role"})
+	assert.Error(t, err)
+	_, err = ParseRoles([]string{"spiffe://prod.example/ns/search/sa/indexer=catalog-reader"})
+	assert.Error(t, err, "roles must name the issuer")
+}